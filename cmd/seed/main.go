@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/infrastructure/auth"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	infraRepo "github.com/condotrack/api/internal/infrastructure/repository"
+	"github.com/condotrack/api/internal/seed"
+)
+
+func main() {
+	opts := seed.DefaultOptions
+	flag.Int64Var(&opts.Seed, "seed", opts.Seed, "random seed; the same seed produces the same dataset")
+	flag.IntVar(&opts.Gestores, "gestores", opts.Gestores, "number of gestores")
+	flag.IntVar(&opts.ContratosPerGestor, "contratos", opts.ContratosPerGestor, "contratos per gestor")
+	flag.IntVar(&opts.AuditsPerContrato, "audits", opts.AuditsPerContrato, "monthly audits per contrato")
+	flag.IntVar(&opts.Courses, "courses", opts.Courses, "number of courses, one instructor each")
+	flag.IntVar(&opts.Enrollments, "enrollments", opts.Enrollments, "number of enrollments, one student each")
+	password := flag.String("password", "demo1234", "password of the demo instructors and students")
+	force := flag.Bool("force", false, "allow seeding when APP_ENV is production")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.IsProduction() && !*force {
+		log.Fatalf("Refusing to load demo data with APP_ENV=%s; pass -force to override", cfg.AppEnv)
+	}
+
+	log.Printf("Connecting to database at %s:%s...", cfg.DBHost, cfg.DBPort)
+	db, err := database.NewMySQL(cfg.GetDSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	seeder := seed.NewSeeder(
+		infraRepo.NewUserMySQLRepository(db.DB),
+		infraRepo.NewGestorMySQLRepository(db.DB),
+		infraRepo.NewContratoMySQLRepository(db.DB),
+		infraRepo.NewAuditCategoryMySQLRepository(db.DB),
+		infraRepo.NewAuditMySQLRepository(db.DB),
+		infraRepo.NewAuditItemMySQLRepository(db.DB),
+		infraRepo.NewCourseMySQLRepository(db.DB),
+		infraRepo.NewMatriculaMySQLRepository(db.DB),
+		infraRepo.NewPaymentMySQLRepository(db.DB),
+		auth.HashPassword,
+	)
+
+	ds := seed.Generate(opts, time.Now())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if err := seeder.Run(ctx, ds, *password); err != nil {
+		if errors.Is(err, seed.ErrAlreadySeeded) {
+			log.Printf("Demo dataset already loaded, nothing to do")
+			return
+		}
+		log.Fatalf("Failed to load demo dataset: %v", err)
+	}
+
+	log.Printf("Loaded %d gestores, %d contratos, %d audits (%d items), %d courses, %d enrollments and %d payments",
+		len(ds.Gestores), len(ds.Contratos), len(ds.Audits), len(ds.AuditItems), len(ds.Courses), len(ds.Matriculas), len(ds.Payments))
+	log.Printf("Demo users sign in as instrutor<n>@%s or aluno<n>@%s", seed.EmailDomain, seed.EmailDomain)
+}
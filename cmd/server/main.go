@@ -12,6 +12,8 @@ import (
 	"github.com/condotrack/api/internal/config"
 	delivery "github.com/condotrack/api/internal/delivery/http"
 	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/infrastructure/tracing"
+	"github.com/condotrack/api/pkg/lifecycle"
 )
 
 func main() {
@@ -24,6 +26,15 @@ func main() {
 	log.Printf("Starting CondoTrack API server...")
 	log.Printf("Environment: %s", cfg.AppEnv)
 
+	// Tracing is set up first so the database connections are traced
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	if cfg.OTelEndpoint != "" {
+		log.Printf("Exporting traces to %s", cfg.OTelEndpoint)
+	}
+
 	// Connect to database
 	log.Printf("Connecting to database at %s:%s...", cfg.DBHost, cfg.DBPort)
 	db, err := database.NewMySQL(cfg.GetDSN())
@@ -33,8 +44,21 @@ func main() {
 	defer db.Close()
 	log.Printf("Database connected successfully")
 
+	// Stats and report queries go to the read replica when one is configured
+	if dsn := cfg.GetReplicaDSN(); dsn != "" {
+		log.Printf("Connecting to read replica at %s:%s...", cfg.DBReplicaHost, cfg.DBReplicaPort)
+		if err := db.ConnectReplica(dsn); err != nil {
+			log.Printf("Warning: %v; reports will use the primary", err)
+		} else {
+			log.Printf("Read replica connected successfully")
+		}
+	}
+
+	// Background workers and in-flight webhooks are drained before the database closes
+	lc := lifecycle.New()
+
 	// Create router
-	router := delivery.NewRouter(cfg, db)
+	router := delivery.NewRouter(cfg, db, lc)
 	engine := router.Setup()
 
 	// Create HTTP server
@@ -65,7 +89,22 @@ func main() {
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	// The drain gets its own timeout so a slow HTTP shutdown does not cut it short
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer drainCancel()
+
+	log.Println("Draining background work...")
+	if err := lc.Shutdown(drainCtx); err != nil {
+		log.Printf("Background work not fully drained: %v", err)
+		return
+	}
+
+	// Spans of the drained work are flushed last
+	if err := shutdownTracing(drainCtx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
 	}
 
 	log.Println("Server exited gracefully")
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -21,9 +24,17 @@ type Config struct {
 	DBUser     string
 	DBPassword string
 
+	// Read replica for stats and reports; disabled while DBReplicaHost is empty. Port, user
+	// and password default to the primary's.
+	DBReplicaHost     string
+	DBReplicaPort     string
+	DBReplicaUser     string
+	DBReplicaPassword string
+
 	// JWT Authentication
-	JWTSecret     string
-	JWTExpiration int // hours
+	JWTSecret              string
+	JWTExpiration          int // hours
+	RefreshTokenExpiration int // days
 
 	// Asaas
 	AsaasAPIKey       string
@@ -39,15 +50,117 @@ type Config struct {
 
 	// Gateway padrao
 	DefaultPaymentGateway string
+	// Gateway que recebe as cobranças recusadas pelo padrão; vazio desativa o failover
+	FallbackPaymentGateway string
+
+	// HTTP calls to Asaas and Mercado Pago: timeout per attempt, retries of throttled and
+	// failed requests with backoff between the delays, and the consecutive failures that open
+	// the circuit breaker of a gateway and how long it stays open
+	GatewayHTTPTimeout       int // seconds
+	GatewayMaxRetries        int
+	GatewayRetryBaseDelay    int // milliseconds
+	GatewayRetryMaxDelay     int // milliseconds
+	GatewayBreakerThreshold  int
+	GatewayBreakerOpenPeriod int // seconds
+
+	// Mock gateway, registered outside production: seconds until PIX and boleto payments are
+	// confirmed (0 keeps them pending), share of payments failing at random (0-1) and where
+	// its webhooks are sent; an empty URL posts to this server
+	MockGatewayConfirmAfter int
+	MockGatewayFailureRate  float64
+	MockGatewayWebhookURL   string
+	MockGatewayWebhookToken string
 
 	// Revenue Split
 	RevenueInstructorPercent float64
 	RevenuePlatformPercent   float64
+	// Transfer instructor amounts through the gateway when payments settle
+	InstructorAutoTransfer bool
+
+	// Checkout: payment methods offered (comma separated pix, boleto, card) and the card or
+	// boleto installments, at most CheckoutMaxInstallments of at least CheckoutMinInstallment each.
+	// CheckoutFeePassThrough adds the gateway fee of the method to what the buyer pays; the
+	// checkout_fee_pass_through setting overrides it at runtime.
+	CheckoutPaymentMethods  string
+	CheckoutMaxInstallments int
+	CheckoutMinInstallment  float64
+	CheckoutFeePassThrough  bool
+
+	// Embedded checkout: sessions opened by landing pages carry a token signed with
+	// CheckoutSessionSecret, valid for CheckoutSessionTTL minutes
+	CheckoutSessionSecret string
+	CheckoutSessionTTL    int
+
+	// Expired PIX charges are cancelled every PixExpirationCheckMinutes; the student is sent
+	// to CheckoutRetryURL, with the enrollment ID appended as the last path segment
+	PixExpirationCheckMinutes int
+	CheckoutRetryURL          string
+
+	// Enrollment renewal
+	RenewalDiscountPercent float64
+	RenewalExtensionDays   int
+
+	// Enrollment cancellation
+	RefundWithdrawalDays int
+
+	// Purchase orders (approval limits by role; admin has no limit)
+	PurchaseOrderLimitSupervisor float64
+	PurchaseOrderLimitGestor     float64
+
+	// Contract renewal alerts
+	ContractRenewalAlertDays  []int
+	ContractRenewalCheckHours int // interval between alert sweeps
+
+	// Contract billing: monthly fee charges issued LeadDays before their due day
+	ContractBillingLeadDays   int
+	ContractBillingCheckHours int // interval between billing runs
+
+	// Contract KPI snapshots: interval between runs; each run stores the snapshot of the day
+	ContractKPISnapshotHours int
+
+	// Overdue task escalation: hours overdue before the contract gestor and then the admins
+	// are notified (0 disables the level), and the interval between sweeps
+	TaskEscalationGestorHours int
+	TaskEscalationAdminHours  int
+	TaskEscalationCheckHours  int
+
+	// Accounting period close: tax provisioned on revenue, % (0 skips the tax postings)
+	AccountingTaxPercent float64
 
 	// Upload
-	UploadDir     string
 	MaxUploadSize int64
 
+	// Images are served through links signed with ImageURLSecret, valid for ImageURLTTL
+	// minutes (rounded up to the next window so links stay cacheable)
+	ImageURLSecret string
+	ImageURLTTL    int
+
+	// Responses of at least this many bytes are gzipped for clients accepting it
+	CompressionMinSize int
+
+	// Rate limits (requests per minute): per user by role, per IP without a token
+	RateLimitAdmin     int
+	RateLimitGestor    int
+	RateLimitStudent   int
+	RateLimitAnonymous int
+
+	// Rate limit buckets: "memory" keeps them per instance, "redis" shares them between the
+	// replicas through the Redis at RedisURL (redis://host:6379/0)
+	RateLimitStore string
+	RedisURL       string
+
+	// Cached stats and settings: "memory" keeps them per instance, "redis" shares them, and
+	// their invalidations, between the replicas through the Redis at RedisURL. TTLs in seconds;
+	// 0 disables the cache.
+	CacheStore       string
+	StatsCacheTTL    int
+	SettingsCacheTTL int
+
+	// Public course catalog: requests per minute per IP, in place of the anonymous limit, and
+	// how long, in seconds, the catalog is served from memory and may be cached by browsers
+	RateLimitCatalog int
+	CatalogCacheTTL  int
+
 	// MinIO
 	MinioEndpoint        string
 	MinioAccessKey       string
@@ -58,12 +171,65 @@ type Config struct {
 	MinioBucketPortal    string
 	MinioBucketEvidence  string
 	MinioBucketCerts     string
-
-	// AI (Gemini)
-	GeminiAPIKey string
-
-	// CORS
+	MinioBucketContracts string
+	MinioBucketPayouts   string
+	MinioBucketPaymentProofs string
+	MinioBucketAccounting string
+
+	// Certificates: base URL of the validation page encoded in the QR code of the PDF; the
+	// validation code is appended as the last path segment
+	CertificateValidationURL string
+
+	// AI providers, tried in AIProviders order (comma separated) with fallback
+	GeminiAPIKey    string
+	OpenAIAPIKey    string
+	AnthropicAPIKey string
+	AIProviders     string
+
+	// Settings: AES-256 key (base64 or hex) encrypting secret settings at rest
+	SettingsMasterKey string
+
+	// CORS: default origins, origins of the public endpoints (certificate and coupon
+	// validation, checkout, course catalog) and of the admin routes; empty admin origins use the default
 	CORSAllowedOrigins string
+	CORSPublicOrigins  string
+	CORSAdminOrigins   string
+
+	// Proxies (comma separated IPs or CIDRs) allowed to set X-Forwarded-For; empty trusts none
+	TrustedProxies string
+
+	// Legacy PHP router deprecation dates (YYYY-MM-DD) sent in the Deprecation and Sunset
+	// headers; an empty sunset omits the header
+	LegacyDeprecatedAt string
+	LegacySunset       string
+
+	// Outbound notification channels; a channel is enabled when its credentials are set
+	SMTPHost           string
+	SMTPPort           int
+	SMTPUser           string
+	SMTPPassword       string
+	SMTPFrom           string
+	TwilioAccountSID   string
+	TwilioAuthToken    string
+	TwilioWhatsAppFrom string
+	VAPIDPrivateKey    string // base64url P-256 private key
+	VAPIDSubject       string
+
+	// Notification dispatch: worker interval (seconds), attempts per delivery and the first
+	// retry delay (seconds), doubled up to an hour
+	NotificationDispatchInterval int
+	NotificationMaxAttempts      int
+	NotificationRetryBaseDelay   int
+
+	// Public URL of this API (https://api.condotrack.com.br) e-mails load their open tracking
+	// pixel from, signed with ImageURLSecret; empty sends e-mails without the pixel
+	NotificationTrackingURL string
+
+	// Tracing: OTLP/HTTP endpoint of the collector (http://jaeger:4318); empty disables it.
+	// The sample ratio applies to traces started here, callers' sampling decisions are kept
+	OTelEndpoint    string
+	OTelServiceName string
+	OTelSampleRatio float64
 }
 
 // Load reads configuration from environment variables
@@ -83,9 +249,15 @@ func Load() (*Config, error) {
 		DBUser:     getEnv("DB_USER", "root"),
 		DBPassword: getEnv("DB_PASS", ""),
 
+		DBReplicaHost:     getEnv("DB_REPLICA_HOST", ""),
+		DBReplicaPort:     getEnv("DB_REPLICA_PORT", ""),
+		DBReplicaUser:     getEnv("DB_REPLICA_USER", ""),
+		DBReplicaPassword: getEnv("DB_REPLICA_PASS", ""),
+
 		// JWT Authentication
-		JWTSecret:     getEnv("JWT_SECRET", "your-super-secret-key-change-in-production"),
-		JWTExpiration: getEnvInt("JWT_EXPIRATION_HOURS", 24),
+		JWTSecret:              getEnv("JWT_SECRET", "your-super-secret-key-change-in-production"),
+		JWTExpiration:          getEnvInt("JWT_EXPIRATION_HOURS", 24),
+		RefreshTokenExpiration: getEnvInt("REFRESH_TOKEN_EXPIRATION_DAYS", 30),
 
 		// Asaas
 		AsaasAPIKey:       getEnv("ASAAS_API_KEY", ""),
@@ -100,16 +272,96 @@ func Load() (*Config, error) {
 		MercadoPagoEnv:           getEnv("MERCADOPAGO_ENV", "sandbox"),
 
 		// Gateway padrao
-		DefaultPaymentGateway: getEnv("DEFAULT_PAYMENT_GATEWAY", "asaas"),
+		DefaultPaymentGateway:  getEnv("DEFAULT_PAYMENT_GATEWAY", "asaas"),
+		FallbackPaymentGateway: getEnv("FALLBACK_PAYMENT_GATEWAY", ""),
+
+		// Gateway HTTP resilience
+		GatewayHTTPTimeout:       getEnvInt("GATEWAY_HTTP_TIMEOUT_SECONDS", 30),
+		GatewayMaxRetries:        getEnvInt("GATEWAY_MAX_RETRIES", 2),
+		GatewayRetryBaseDelay:    getEnvInt("GATEWAY_RETRY_BASE_DELAY_MS", 200),
+		GatewayRetryMaxDelay:     getEnvInt("GATEWAY_RETRY_MAX_DELAY_MS", 2000),
+		GatewayBreakerThreshold:  getEnvInt("GATEWAY_BREAKER_THRESHOLD", 5),
+		GatewayBreakerOpenPeriod: getEnvInt("GATEWAY_BREAKER_OPEN_SECONDS", 30),
+
+		// Mock gateway
+		MockGatewayConfirmAfter: getEnvInt("MOCK_GATEWAY_CONFIRM_AFTER_SECONDS", 5),
+		MockGatewayFailureRate:  getEnvFloat("MOCK_GATEWAY_FAILURE_RATE", 0),
+		MockGatewayWebhookURL:   getEnv("MOCK_GATEWAY_WEBHOOK_URL", ""),
+		MockGatewayWebhookToken: getEnv("MOCK_GATEWAY_WEBHOOK_TOKEN", ""),
 
 		// Revenue Split
 		RevenueInstructorPercent: getEnvFloat("REVENUE_INSTRUCTOR_PERCENT", 70.0),
 		RevenuePlatformPercent:   getEnvFloat("REVENUE_PLATFORM_PERCENT", 30.0),
+		InstructorAutoTransfer:   getEnvBool("INSTRUCTOR_AUTO_TRANSFER", false),
+
+		// Checkout
+		CheckoutPaymentMethods:  getEnv("CHECKOUT_PAYMENT_METHODS", "pix,boleto,card"),
+		CheckoutMaxInstallments: getEnvInt("CHECKOUT_MAX_INSTALLMENTS", 12),
+		CheckoutMinInstallment:  getEnvFloat("CHECKOUT_MIN_INSTALLMENT_AMOUNT", 5.0),
+		CheckoutFeePassThrough:  getEnvBool("CHECKOUT_FEE_PASS_THROUGH", false),
+		CheckoutSessionSecret:   getEnv("CHECKOUT_SESSION_SECRET", ""),
+		CheckoutSessionTTL:      getEnvInt("CHECKOUT_SESSION_TTL_MINUTES", 30),
+
+		PixExpirationCheckMinutes: getEnvInt("PIX_EXPIRATION_CHECK_INTERVAL_MINUTES", 15),
+		CheckoutRetryURL:          getEnv("CHECKOUT_RETRY_URL", ""),
+
+		// Enrollment renewal
+		RenewalDiscountPercent: getEnvFloat("RENEWAL_DISCOUNT_PERCENT", 20.0),
+		RenewalExtensionDays:   getEnvInt("RENEWAL_EXTENSION_DAYS", 365),
+
+		// Enrollment cancellation
+		RefundWithdrawalDays: getEnvInt("REFUND_WITHDRAWAL_DAYS", 7),
+
+		// Purchase orders
+		PurchaseOrderLimitSupervisor: getEnvFloat("PO_APPROVAL_LIMIT_SUPERVISOR", 2000.0),
+		PurchaseOrderLimitGestor:     getEnvFloat("PO_APPROVAL_LIMIT_GESTOR", 10000.0),
+
+		// Contract renewal alerts
+		ContractRenewalAlertDays:     getEnvIntList("CONTRACT_RENEWAL_ALERT_DAYS", []int{90, 60, 30}),
+		ContractRenewalCheckHours:    getEnvInt("CONTRACT_RENEWAL_CHECK_INTERVAL_HOURS", 24),
+
+		// Contract billing
+		ContractBillingLeadDays:   getEnvInt("CONTRACT_BILLING_LEAD_DAYS", 10),
+		ContractBillingCheckHours: getEnvInt("CONTRACT_BILLING_CHECK_INTERVAL_HOURS", 6),
+
+		// Contract KPI snapshots
+		ContractKPISnapshotHours: getEnvInt("CONTRACT_KPI_SNAPSHOT_INTERVAL_HOURS", 24),
+
+		// Overdue task escalation
+		TaskEscalationGestorHours: getEnvInt("TASK_ESCALATION_GESTOR_HOURS", 24),
+		TaskEscalationAdminHours:  getEnvInt("TASK_ESCALATION_ADMIN_HOURS", 72),
+		TaskEscalationCheckHours:  getEnvInt("TASK_ESCALATION_CHECK_INTERVAL_HOURS", 1),
+
+		// Accounting period close
+		AccountingTaxPercent: getEnvFloat("ACCOUNTING_TAX_PERCENT", 0),
 
 		// Upload
-		UploadDir:     getEnv("UPLOAD_DIR", "./uploads"),
 		MaxUploadSize: getEnvInt64("MAX_UPLOAD_SIZE", 50*1024*1024), // 50MB default
 
+		// Images
+		ImageURLSecret: getEnv("IMAGE_URL_SECRET", ""),
+		ImageURLTTL:    getEnvInt("IMAGE_URL_TTL_MINUTES", 15),
+
+		// Compression
+		CompressionMinSize: getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+
+		// Rate limits
+		RateLimitAdmin:     getEnvInt("RATE_LIMIT_ADMIN", 600),
+		RateLimitGestor:    getEnvInt("RATE_LIMIT_GESTOR", 300),
+		RateLimitStudent:   getEnvInt("RATE_LIMIT_STUDENT", 120),
+		RateLimitAnonymous: getEnvInt("RATE_LIMIT_ANONYMOUS", 100),
+		RateLimitStore:     getEnv("RATE_LIMIT_STORE", "memory"),
+		RedisURL:           getEnv("REDIS_URL", "redis://localhost:6379/0"),
+
+		// Cache
+		CacheStore:       getEnv("CACHE_STORE", "memory"),
+		StatsCacheTTL:    getEnvInt("STATS_CACHE_TTL_SECONDS", 60),
+		SettingsCacheTTL: getEnvInt("SETTINGS_CACHE_TTL_SECONDS", 300),
+
+		// Public course catalog
+		RateLimitCatalog: getEnvInt("RATE_LIMIT_CATALOG", 60),
+		CatalogCacheTTL:  getEnvInt("CATALOG_CACHE_TTL_SECONDS", 300),
+
 		// MinIO
 		MinioEndpoint:       getEnv("MINIO_ENDPOINT", "localhost:9000"),
 		MinioAccessKey:      getEnv("MINIO_ACCESS_KEY", "condotrack"),
@@ -120,12 +372,57 @@ func Load() (*Config, error) {
 		MinioBucketPortal:   getEnv("MINIO_BUCKET_PORTAL", "portal-images"),
 		MinioBucketEvidence: getEnv("MINIO_BUCKET_EVIDENCE", "evidence"),
 		MinioBucketCerts:    getEnv("MINIO_BUCKET_CERTIFICATES", "certificates"),
+		MinioBucketContracts: getEnv("MINIO_BUCKET_CONTRACTS", "contract-documents"),
+		MinioBucketPayouts:   getEnv("MINIO_BUCKET_PAYOUTS", "payout-receipts"),
+		MinioBucketPaymentProofs: getEnv("MINIO_BUCKET_PAYMENT_PROOFS", "payment-proofs"),
+		MinioBucketAccounting: getEnv("MINIO_BUCKET_ACCOUNTING", "accounting-exports"),
+
+		// Certificates
+		CertificateValidationURL: getEnv("CERTIFICATE_VALIDATION_URL", ""),
 
-		// AI (Gemini)
-		GeminiAPIKey: getEnv("GEMINI_API_KEY", ""),
+		// AI providers
+		GeminiAPIKey:    getEnv("GEMINI_API_KEY", ""),
+		OpenAIAPIKey:    getEnv("OPENAI_API_KEY", ""),
+		AnthropicAPIKey: getEnv("ANTHROPIC_API_KEY", ""),
+		AIProviders:     getEnv("AI_PROVIDERS", "gemini,openai,anthropic"),
+
+		// Settings
+		SettingsMasterKey: getEnv("SETTINGS_MASTER_KEY", ""),
 
 		// CORS
 		CORSAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", ""),
+		CORSPublicOrigins:  getEnv("CORS_PUBLIC_ORIGINS", "*"),
+		CORSAdminOrigins:   getEnv("CORS_ADMIN_ORIGINS", ""),
+
+		// Proxies
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+
+		// Legacy router
+		LegacyDeprecatedAt: getEnv("LEGACY_ROUTER_DEPRECATED_AT", "2026-10-14"),
+		LegacySunset:       getEnv("LEGACY_ROUTER_SUNSET", ""),
+
+		// Notification channels
+		SMTPHost:           getEnv("SMTP_HOST", ""),
+		SMTPPort:           getEnvInt("SMTP_PORT", 587),
+		SMTPUser:           getEnv("SMTP_USER", ""),
+		SMTPPassword:       getEnv("SMTP_PASS", ""),
+		SMTPFrom:           getEnv("SMTP_FROM", "CondoTrack <noreply@condotrack.com>"),
+		TwilioAccountSID:   getEnv("TWILIO_ACCOUNT_SID", ""),
+		TwilioAuthToken:    getEnv("TWILIO_AUTH_TOKEN", ""),
+		TwilioWhatsAppFrom: getEnv("TWILIO_WHATSAPP_FROM", ""),
+		VAPIDPrivateKey:    getEnv("VAPID_PRIVATE_KEY", ""),
+		VAPIDSubject:       getEnv("VAPID_SUBJECT", "mailto:suporte@condotrack.com"),
+
+		// Notification dispatch
+		NotificationDispatchInterval: getEnvInt("NOTIFICATION_DISPATCH_INTERVAL_SECONDS", 10),
+		NotificationMaxAttempts:      getEnvInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+		NotificationRetryBaseDelay:   getEnvInt("NOTIFICATION_RETRY_BASE_DELAY_SECONDS", 60),
+		NotificationTrackingURL:      getEnv("NOTIFICATION_TRACKING_URL", ""),
+
+		// Tracing
+		OTelEndpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		OTelServiceName: getEnv("OTEL_SERVICE_NAME", "condotrack-api"),
+		OTelSampleRatio: getEnvFloat("OTEL_TRACES_SAMPLE_RATIO", 1),
 	}
 
 	// Warn about insecure JWT secret in production
@@ -135,6 +432,31 @@ func Load() (*Config, error) {
 	if cfg.JWTSecret == "your-super-secret-key-change-in-production" {
 		log.Println("[WARN] Using default JWT secret. Set JWT_SECRET environment variable for security.")
 	}
+	if cfg.ImageURLSecret == "" {
+		cfg.ImageURLSecret = cfg.JWTSecret
+	}
+	if cfg.CheckoutSessionSecret == "" {
+		cfg.CheckoutSessionSecret = cfg.JWTSecret
+	}
+	if cfg.MockGatewayWebhookURL == "" {
+		cfg.MockGatewayWebhookURL = "http://localhost:" + cfg.ServerPort + "/api/v1/webhooks/mock"
+	}
+	if cfg.CertificateValidationURL == "" {
+		cfg.CertificateValidationURL = "http://localhost:" + cfg.ServerPort + "/api/v1/certificados/validate"
+	}
+	if cfg.DBReplicaPort == "" {
+		cfg.DBReplicaPort = cfg.DBPort
+	}
+	if cfg.DBReplicaUser == "" {
+		cfg.DBReplicaUser = cfg.DBUser
+		if cfg.DBReplicaPassword == "" {
+			cfg.DBReplicaPassword = cfg.DBPassword
+		}
+	}
+	// A proxy list that cannot be parsed must not leave X-Forwarded-For open to any client
+	if _, err := cfg.TrustedProxyList(); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
@@ -177,6 +499,26 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvIntList returns a comma-separated environment variable as []int or default
+func getEnvIntList(key string, defaultValue []int) []int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	var list []int
+	for _, part := range strings.Split(value, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return defaultValue
+		}
+		list = append(list, i)
+	}
+	if len(list) == 0 {
+		return defaultValue
+	}
+	return list
+}
+
 // getEnvBool returns environment variable as bool or default
 func getEnvBool(key string, defaultValue bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
@@ -187,9 +529,40 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// TrustedProxyList returns the proxies of TRUSTED_PROXIES, or an error naming the first
+// entry that is neither an IP nor a CIDR
+func (c *Config) TrustedProxyList() ([]string, error) {
+	var proxies []string
+	for _, proxy := range strings.Split(c.TrustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: not an IP or CIDR", proxy)
+			}
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
 // GetDSN returns the MySQL connection string
 func (c *Config) GetDSN() string {
-	return c.DBUser + ":" + c.DBPassword + "@tcp(" + c.DBHost + ":" + c.DBPort + ")/" + c.DBName + "?parseTime=true&charset=utf8mb4&collation=utf8mb4_unicode_ci"
+	return mysqlDSN(c.DBUser, c.DBPassword, c.DBHost, c.DBPort, c.DBName)
+}
+
+// GetReplicaDSN returns the connection string of the read replica, or "" when there is none
+func (c *Config) GetReplicaDSN() string {
+	if c.DBReplicaHost == "" {
+		return ""
+	}
+	return mysqlDSN(c.DBReplicaUser, c.DBReplicaPassword, c.DBReplicaHost, c.DBReplicaPort, c.DBName)
+}
+
+func mysqlDSN(user, password, host, port, name string) string {
+	return user + ":" + password + "@tcp(" + host + ":" + port + ")/" + name + "?parseTime=true&charset=utf8mb4&collation=utf8mb4_unicode_ci"
 }
 
 // IsDevelopment returns true if running in development mode
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/infrastructure/external"
+	"github.com/condotrack/api/internal/infrastructure/external/asaas"
+	"github.com/condotrack/api/internal/infrastructure/external/mercadopago"
+	"github.com/condotrack/api/internal/infrastructure/external/resilience"
+	"github.com/condotrack/api/internal/usecase/setting"
+)
+
+var (
+	asaasFees = gateway.GatewayFees{
+		PixPercent:  0.0099,
+		BoletoFixed: 2.99,
+		CardPercent: 0.0299,
+		CardFixed:   0.49,
+	}
+	mercadoPagoFees = gateway.GatewayFees{
+		PixPercent:  0.0099, // 0.99%
+		BoletoFixed: 3.49,
+		CardPercent: 0.0499, // 4.99%
+		CardFixed:   0.39,
+	}
+	// The mock gateway charges the Asaas fees, so simulated splits match the real ones
+	mockFees = asaasFees
+)
+
+// gatewaySettingKeys are the settings that change which gateways are available. The Asaas API
+// key is not among them: it is rotated in place on the client shared with payouts.
+var gatewaySettingKeys = []string{
+	"payment_default_gateway",
+	"payment_fallback_gateway",
+	"asaas_enabled",
+	"asaas_webhook_token",
+	"mercadopago_enabled",
+	"mercadopago_access_token",
+	"mercadopago_env",
+	"mercadopago_webhook_secret",
+}
+
+// gatewayReloader keeps the gateway factory in line with the payment settings: it rebuilds
+// adapters whose credentials changed, registers Mercado Pago once it has a token, activates or
+// deactivates gateways and switches the default. Empty settings fall back to the environment.
+type gatewayReloader struct {
+	cfg         *config.Config
+	settings    *setting.UseCase
+	factory     *external.GatewayFactory
+	asaasClient *asaas.Client
+	httpClients *resilience.Registry
+
+	mu                sync.Mutex
+	asaasWebhookToken string
+	mpClient          *mercadopago.Client
+	mpEnv             string
+	mpWebhookSecret   string
+}
+
+func newGatewayReloader(cfg *config.Config, settings *setting.UseCase, factory *external.GatewayFactory,
+	asaasClient *asaas.Client, mpClient *mercadopago.Client, httpClients *resilience.Registry) *gatewayReloader {
+	return &gatewayReloader{
+		cfg:               cfg,
+		settings:          settings,
+		factory:           factory,
+		asaasClient:       asaasClient,
+		httpClients:       httpClients,
+		asaasWebhookToken: cfg.AsaasWebhookToken,
+		mpClient:          mpClient,
+		mpEnv:             cfg.MercadoPagoEnv,
+		mpWebhookSecret:   cfg.MercadoPagoWebhookSecret,
+	}
+}
+
+// watch reloads the gateways whenever one of their settings changes
+func (g *gatewayReloader) watch() {
+	for _, key := range gatewaySettingKeys {
+		g.settings.Subscribe(key, func(string) { g.reload(context.Background()) })
+	}
+}
+
+func (g *gatewayReloader) value(ctx context.Context, key, fallback string) string {
+	v, err := g.settings.GetSettingValue(ctx, key)
+	if err != nil {
+		log.Printf("[GATEWAYS] Failed to read %s: %v", key, err)
+		return fallback
+	}
+	if v == "" {
+		return fallback
+	}
+	return v
+}
+
+func (g *gatewayReloader) reload(ctx context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if token := g.value(ctx, "asaas_webhook_token", g.cfg.AsaasWebhookToken); token != g.asaasWebhookToken {
+		g.factory.Register(asaas.NewAsaasAdapter(g.asaasClient, asaasFees, token))
+		g.asaasWebhookToken = token
+		log.Printf("[GATEWAYS] Asaas adapter reloaded")
+	}
+
+	mpToken := g.value(ctx, "mercadopago_access_token", g.cfg.MercadoPagoAccessToken)
+	mpEnv := g.value(ctx, "mercadopago_env", g.cfg.MercadoPagoEnv)
+	mpSecret := g.value(ctx, "mercadopago_webhook_secret", g.cfg.MercadoPagoWebhookSecret)
+	if mpToken != "" {
+		rebuild := g.mpClient == nil || mpEnv != g.mpEnv
+		if rebuild {
+			g.mpClient = mercadopago.NewClient(mpToken, mpEnv, g.httpClients.Client("mercadopago"))
+			g.mpEnv = mpEnv
+		} else {
+			g.mpClient.SetAccessToken(mpToken)
+		}
+		if rebuild || mpSecret != g.mpWebhookSecret {
+			g.factory.Register(mercadopago.NewMercadoPagoAdapter(g.mpClient, mercadoPagoFees, mpSecret))
+			g.mpWebhookSecret = mpSecret
+			log.Printf("[GATEWAYS] Mercado Pago gateway registered (env: %s)", mpEnv)
+		}
+	}
+
+	// Enable first and disable last, so moving the default off a gateway and deactivating it
+	// in the same update works
+	var disable []string
+	for _, name := range g.factory.ListRegistered() {
+		if g.value(ctx, name+"_enabled", "true") == "false" {
+			disable = append(disable, name)
+			continue
+		}
+		_ = g.factory.SetEnabled(name, true)
+	}
+
+	defaultName := g.value(ctx, "payment_default_gateway", g.cfg.DefaultPaymentGateway)
+	if defaultName == "" {
+		defaultName = "asaas"
+	}
+	if defaultName != g.factory.ActiveName() {
+		if err := g.factory.SetActive(defaultName); err != nil {
+			log.Printf("[GATEWAYS] Keeping default gateway %q: %v", g.factory.ActiveName(), err)
+		} else {
+			log.Printf("[GATEWAYS] Default gateway set to %q", defaultName)
+		}
+	}
+
+	for _, name := range disable {
+		if err := g.factory.SetEnabled(name, false); err != nil {
+			log.Printf("[GATEWAYS] Failed to deactivate %q: %v", name, err)
+		}
+	}
+
+	// "none" turns the fallback off even when the environment sets one
+	fallbackName := g.value(ctx, "payment_fallback_gateway", g.cfg.FallbackPaymentGateway)
+	if fallbackName == "none" {
+		fallbackName = ""
+	}
+	if fallbackName != g.factory.FallbackName() {
+		if err := g.factory.SetFallback(fallbackName); err != nil {
+			log.Printf("[GATEWAYS] Keeping fallback gateway %q: %v", g.factory.FallbackName(), err)
+		} else {
+			log.Printf("[GATEWAYS] Fallback gateway set to %q", fallbackName)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/accounting"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AccountingHandler handles accounting system export HTTP requests
+type AccountingHandler struct {
+	usecase accounting.UseCase
+}
+
+// NewAccountingHandler creates a new accounting handler
+func NewAccountingHandler(uc accounting.UseCase) *AccountingHandler {
+	return &AccountingHandler{usecase: uc}
+}
+
+// ListEntries handles GET /api/v1/accounting/entries
+// Query params: from, to (YYYY-MM-DD, defaults to the current month)
+func (h *AccountingHandler) ListEntries(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	entries, err := h.usecase.ListEntries(ctx, c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch accounting entries")
+		return
+	}
+
+	response.Success(c, entries)
+}
+
+// Export handles GET /api/v1/accounting/export/:layout
+// Query params: from, to (YYYY-MM-DD, defaults to the current month)
+func (h *AccountingHandler) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	export, err := h.usecase.Export(ctx, c.Param("layout"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to export accounting entries")
+		return
+	}
+
+	h.sendFile(c, export)
+}
+
+// Sync handles POST /api/v1/accounting/sync/:layout
+func (h *AccountingHandler) Sync(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	run, err := h.usecase.Sync(ctx, c.Param("layout"), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to sync accounting entries")
+		return
+	}
+	if run == nil {
+		response.SuccessWithMessage(c, "No new accounting entries to export", nil)
+		return
+	}
+
+	response.Created(c, run)
+}
+
+// ListRuns handles GET /api/v1/accounting/sync/runs
+// Query params: layout
+func (h *AccountingHandler) ListRuns(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	runs, err := h.usecase.ListRuns(ctx, c.Query("layout"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch accounting sync runs")
+		return
+	}
+
+	response.Success(c, runs)
+}
+
+// DownloadRun handles GET /api/v1/accounting/sync/runs/:id/download
+func (h *AccountingHandler) DownloadRun(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	export, err := h.usecase.DownloadRun(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to download accounting sync run")
+		return
+	}
+
+	h.sendFile(c, export)
+}
+
+// ListCursors handles GET /api/v1/accounting/sync/cursors
+func (h *AccountingHandler) ListCursors(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	cursors, err := h.usecase.ListCursors(ctx)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch accounting sync cursors", err)
+		return
+	}
+
+	response.Success(c, cursors)
+}
+
+// RequestClose handles POST /api/v1/accounting/period-close
+// The export runs in the background; poll GetCloseJob for its download URL.
+func (h *AccountingHandler) RequestClose(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.CreateAccountingCloseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	job, err := h.usecase.RequestClose(ctx, req.Period, req.Format, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to request accounting period close")
+		return
+	}
+
+	response.Created(c, job)
+}
+
+// ListCloseJobs handles GET /api/v1/accounting/period-close/jobs
+func (h *AccountingHandler) ListCloseJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	jobs, err := h.usecase.ListCloseJobs(ctx)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch accounting period close jobs", err)
+		return
+	}
+
+	response.Success(c, jobs)
+}
+
+// GetCloseJob handles GET /api/v1/accounting/period-close/jobs/:id
+func (h *AccountingHandler) GetCloseJob(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	job, err := h.usecase.GetCloseJob(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch accounting period close job")
+		return
+	}
+
+	response.Success(c, job)
+}
+
+func (h *AccountingHandler) sendFile(c *gin.Context, export *entity.AccountingExport) {
+	c.Header("Content-Disposition", `attachment; filename="`+export.FileName+`"`)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", export.Content)
+}
+
+func (h *AccountingHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/activitylog"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityLogHandler handles the activity log HTTP requests
+type ActivityLogHandler struct {
+	usecase activitylog.UseCase
+}
+
+// NewActivityLogHandler creates a new activity log handler
+func NewActivityLogHandler(uc activitylog.UseCase) *ActivityLogHandler {
+	return &ActivityLogHandler{usecase: uc}
+}
+
+// ListLogs handles GET /api/v1/activity-logs
+// Query params: user_id, entity (contratos, suppliers...), entity_id, action (create, update
+// or delete), date_from, date_to (YYYY-MM-DD, both inclusive), page, per_page
+func (h *ActivityLogHandler) ListLogs(c *gin.Context) {
+	filters := entity.ActivityLogFilters{
+		UserID:   c.Query("user_id"),
+		Entity:   c.Query("entity"),
+		EntityID: c.Query("entity_id"),
+		Action:   strings.ToLower(c.Query("action")),
+		Page:     1,
+		PerPage:  50,
+	}
+
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		t, err := time.Parse("2006-01-02", dateFrom)
+		if err != nil {
+			response.BadRequest(c, "Invalid date_from format, expected YYYY-MM-DD")
+			return
+		}
+		filters.DateFrom = &t
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		t, err := time.Parse("2006-01-02", dateTo)
+		if err != nil {
+			response.BadRequest(c, "Invalid date_to format, expected YYYY-MM-DD")
+			return
+		}
+		t = t.AddDate(0, 0, 1)
+		filters.DateTo = &t
+	}
+
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+		filters.Page = p
+	}
+	if pp, err := strconv.Atoi(c.Query("per_page")); err == nil && pp > 0 && pp <= 200 {
+		filters.PerPage = pp
+	}
+
+	logs, total, err := h.usecase.List(c.Request.Context(), filters)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch activity logs", err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"logs":     logs,
+		"total":    total,
+		"page":     filters.Page,
+		"per_page": filters.PerPage,
+	})
+}
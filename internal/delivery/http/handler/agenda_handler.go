@@ -120,7 +120,7 @@ func (h *AgendaHandler) CreateEvent(c *gin.Context) {
 
 	var req entity.CreateEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -156,7 +156,7 @@ func (h *AgendaHandler) UpdateEvent(c *gin.Context) {
 
 	var req entity.UpdateEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/assistant"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AIHandler handles AI provider administration HTTP requests
+type AIHandler struct {
+	usecase assistant.UseCase
+}
+
+// NewAIHandler creates a new AI handler
+func NewAIHandler(uc assistant.UseCase) *AIHandler {
+	return &AIHandler{usecase: uc}
+}
+
+// GetUsage handles GET /api/v1/ai/usage
+// Query params: from, to (YYYY-MM-DD, both inclusive), feature. Returns calls, failures and
+// tokens per provider and model.
+func (h *AIHandler) GetUsage(c *gin.Context) {
+	filters := entity.AIUsageFilters{Feature: c.Query("feature")}
+
+	if d := c.Query("from"); d != "" {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			response.BadRequest(c, "Invalid from date format, expected YYYY-MM-DD")
+			return
+		}
+		filters.From = &t
+	}
+	if d := c.Query("to"); d != "" {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			response.BadRequest(c, "Invalid to date format, expected YYYY-MM-DD")
+			return
+		}
+		t = t.AddDate(0, 0, 1)
+		filters.To = &t
+	}
+
+	summary, err := h.usecase.Usage(c.Request.Context(), filters)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch AI usage", err)
+		return
+	}
+
+	response.Success(c, summary)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// APIRequestHandler handles the API request audit log HTTP requests
+type APIRequestHandler struct {
+	repo repository.APIRequestRepository
+}
+
+// NewAPIRequestHandler creates a new API request audit log handler
+func NewAPIRequestHandler(repo repository.APIRequestRepository) *APIRequestHandler {
+	return &APIRequestHandler{repo: repo}
+}
+
+// ListRequests handles GET /api/v1/api-requests
+// Query params: actor_id, method, path (prefix), status, date_from, date_to (YYYY-MM-DD,
+// both inclusive), page, per_page
+func (h *APIRequestHandler) ListRequests(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := repository.APIRequestFilters{
+		ActorID:    c.Query("actor_id"),
+		Method:     strings.ToUpper(c.Query("method")),
+		PathPrefix: c.Query("path"),
+		Page:       1,
+		PerPage:    50,
+	}
+
+	if s := c.Query("status"); s != "" {
+		status, err := strconv.Atoi(s)
+		if err != nil {
+			response.BadRequest(c, "Invalid status, expected an HTTP status code")
+			return
+		}
+		filters.Status = status
+	}
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		t, err := time.Parse("2006-01-02", dateFrom)
+		if err != nil {
+			response.BadRequest(c, "Invalid date_from format, expected YYYY-MM-DD")
+			return
+		}
+		filters.DateFrom = &t
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		t, err := time.Parse("2006-01-02", dateTo)
+		if err != nil {
+			response.BadRequest(c, "Invalid date_to format, expected YYYY-MM-DD")
+			return
+		}
+		t = t.AddDate(0, 0, 1)
+		filters.DateTo = &t
+	}
+
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			filters.Page = parsed
+		}
+	}
+	if pp := c.Query("per_page"); pp != "" {
+		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 && parsed <= 200 {
+			filters.PerPage = parsed
+		}
+	}
+
+	requests, total, err := h.repo.FindAll(ctx, filters)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch API requests", err)
+		return
+	}
+	if requests == nil {
+		requests = []entity.APIRequest{}
+	}
+
+	response.Success(c, gin.H{
+		"requests": requests,
+		"total":    total,
+		"page":     filters.Page,
+		"per_page": filters.PerPage,
+	})
+}
@@ -55,7 +55,7 @@ func (h *AuditCategoryHandler) CreateCategory(c *gin.Context) {
 
 	var req entity.CreateAuditCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -79,7 +79,7 @@ func (h *AuditCategoryHandler) UpdateCategory(c *gin.Context) {
 
 	var req entity.UpdateAuditCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -1,8 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/assistant"
 	"github.com/condotrack/api/internal/usecase/audit"
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -18,41 +26,75 @@ func NewAuditHandler(uc audit.UseCase) *AuditHandler {
 }
 
 // ListAudits handles GET /api/v1/audits
+// Query params: page, per_page, contract_id, status, auditor, date_from, date_to (YYYY-MM-DD),
+// include_contract, filter[...] and sort, fields (sparse fieldset, e.g. id,status,score)
 func (h *AuditHandler) ListAudits(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	// Check for contract_id filter
-	contractID := c.Query("contract_id")
-	if contractID != "" {
-		audits, err := h.usecase.ListAuditsByContract(ctx, contractID)
-		if err != nil {
-			response.SafeInternalError(c, "Failed to fetch audits", err)
-			return
-		}
-		response.Success(c, audits)
-		return
+	filters := repository.AuditFilters{
+		ContractID: c.Query("contract_id"),
+		Status:     c.Query("status"),
+		Auditor:    c.Query("auditor"),
+		Page:       1,
+		PerPage:    20,
 	}
 
-	// Check if we should include contract info
-	includeContract := c.Query("include_contract") == "true"
+	// Parse date range
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		if t, err := time.Parse("2006-01-02", dateFrom); err == nil {
+			filters.DateFrom = &t
+		}
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		if t, err := time.Parse("2006-01-02", dateTo); err == nil {
+			filters.DateTo = &t
+		}
+	}
 
-	if includeContract {
-		audits, err := h.usecase.ListAuditsWithContract(ctx)
-		if err != nil {
-			response.SafeInternalError(c, "Failed to fetch audits", err)
-			return
+	// Parse pagination
+	if p := c.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			filters.Page = parsed
 		}
-		response.Success(c, audits)
+	}
+	if pp := c.Query("per_page"); pp != "" {
+		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 && parsed <= 100 {
+			filters.PerPage = parsed
+		}
+	}
+
+	q, ok := parseListQuery(c)
+	if !ok {
 		return
 	}
+	filters.Query = q
 
-	audits, err := h.usecase.ListAudits(ctx)
+	audits, total, err := h.usecase.ListAudits(ctx, filters)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to fetch audits", err)
+		respondListError(c, err, "Failed to fetch audits")
 		return
 	}
 
-	response.Success(c, audits)
+	// Contract and manager names are only sent when asked for
+	var list interface{} = audits
+	if c.Query("include_contract") != "true" {
+		plain := make([]entity.Audit, len(audits))
+		for i := range audits {
+			plain[i] = audits[i].Audit
+		}
+		list = plain
+	}
+
+	selected, ok := selectFields(c, list)
+	if !ok {
+		return
+	}
+	response.Success(c, gin.H{
+		"audits":   selected,
+		"total":    total,
+		"page":     filters.Page,
+		"per_page": filters.PerPage,
+	})
 }
 
 // GetAuditByID handles GET /api/v1/audits/:id
@@ -99,12 +141,15 @@ func (h *AuditHandler) CreateAudit(c *gin.Context) {
 
 	var req entity.CreateAuditRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
 	audit, err := h.usecase.CreateAudit(ctx, &req)
 	if err != nil {
+		if respondDataJSONError(c, err) {
+			return
+		}
 		response.SafeInternalError(c, "Failed to create audit", err)
 		return
 	}
@@ -126,7 +171,7 @@ func (h *AuditHandler) UpdateAudit(c *gin.Context) {
 
 	var req entity.UpdateAuditRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -136,6 +181,9 @@ func (h *AuditHandler) UpdateAudit(c *gin.Context) {
 			response.NotFound(c, "Audit not found")
 			return
 		}
+		if respondDataJSONError(c, err) {
+			return
+		}
 		response.SafeInternalError(c, "Failed to update audit", err)
 		return
 	}
@@ -160,3 +208,72 @@ func (h *AuditHandler) DeleteAudit(c *gin.Context) {
 
 	response.Success(c, map[string]string{"message": "Audit deleted successfully"})
 }
+
+// SummarizeAudit handles POST /api/v1/audits/:id/ai-summary
+// Returns an AI executive summary of the audit and suggested corrective actions. The summary
+// is cached on the audit until its data changes; ?refresh=true generates a new one.
+func (h *AuditHandler) SummarizeAudit(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	userID, _ := middleware.GetUserID(c)
+
+	summary, err := h.usecase.SummarizeAudit(ctx, id, userID, c.Query("refresh") == "true")
+	if err != nil {
+		switch {
+		case err.Error() == "audit not found":
+			response.NotFound(c, "Audit not found")
+		case errors.Is(err, assistant.ErrUnavailable):
+			response.ErrorCode(c, apperror.CodeAIUnavailable, "AI service not available")
+		default:
+			response.SafeInternalError(c, "Failed to summarize audit", err)
+		}
+		return
+	}
+
+	response.Success(c, summary)
+}
+
+// ListDataTemplates handles GET /api/v1/audits/data-templates
+// Returns the data_json templates with the JSON schema of their current version.
+func (h *AuditHandler) ListDataTemplates(c *gin.Context) {
+	response.Success(c, h.usecase.ListDataTemplates())
+}
+
+// MigrateDataJSON handles POST /api/v1/audits/data-templates/migrate
+// Upgrades stored data_json payloads to the current schema of their template.
+func (h *AuditHandler) MigrateDataJSON(c *gin.Context) {
+	var req entity.MigrateAuditDataRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	result, err := h.usecase.MigrateDataJSON(c.Request.Context(), &req)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to migrate audit data", err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// respondDataJSONError answers a data_json that does not match its template schema with
+// one field error per violation. It reports whether err was such an error.
+func respondDataJSONError(c *gin.Context, err error) bool {
+	var invalid *audit.DataJSONError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	fields := make([]response.FieldError, len(invalid.Errors))
+	for i, e := range invalid.Errors {
+		field := "data_json"
+		if e.Path != "" {
+			field += "." + e.Path
+		}
+		fields[i] = response.FieldError{Field: field, Rule: "schema", Message: e.Message}
+	}
+	response.FieldValidationError(c, "Invalid data_json", fields)
+	return true
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/usecase/auth"
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -29,7 +30,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	var req entity.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -38,7 +39,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		switch err {
 		case auth.ErrInvalidCredentials, auth.ErrUserInactive:
 			// Return same message for both to prevent user enumeration
-			response.Unauthorized(c, "Invalid email or password")
+			response.ErrorCode(c, apperror.CodeInvalidCredentials, "Invalid email or password")
 		default:
 			response.SafeInternalError(c, "Login failed", err)
 		}
@@ -48,23 +49,48 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	response.Success(c, result)
 }
 
+// Refresh handles POST /api/v1/auth/refresh
+// Exchanges a refresh token for a new access token; the refresh token is rotated, so the
+// client must store the one returned.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.usecase.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		if err == auth.ErrInvalidRefreshToken {
+			response.ErrorCode(c, apperror.CodeRefreshTokenInvalid, "Invalid or expired refresh token")
+			return
+		}
+		response.SafeInternalError(c, "Token refresh failed", err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
 // Register handles POST /api/v1/auth/register
 func (h *AuthHandler) Register(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	var req entity.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
 	user, err := h.usecase.Register(ctx, req)
 	if err != nil {
 		if err == auth.ErrEmailAlreadyExists {
-			response.BadRequest(c, "Email already registered")
+			response.ErrorCode(c, apperror.CodeEmailTaken, "Email already registered")
 			return
 		}
-		response.SafeInternalError(c, "Registration failed", err)
+		response.FromError(c, "Registration failed", err)
 		return
 	}
 
@@ -106,7 +132,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 
 	var req entity.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -129,6 +155,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 }
 
 // Logout handles POST /api/v1/auth/logout
+// The refresh token, when sent in the body, is revoked with the access token.
 func (h *AuthHandler) Logout(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
 	if strings.HasPrefix(authHeader, "Bearer ") {
@@ -137,6 +164,14 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 			h.jwtManager.BlacklistToken(tokenString, claims.ExpiresAt.Time)
 		}
 	}
+
+	var req entity.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err == nil {
+		if err := h.usecase.RevokeRefreshToken(c.Request.Context(), req.RefreshToken); err != nil {
+			response.SafeInternalError(c, "Logout failed", err)
+			return
+		}
+	}
 	response.SuccessWithMessage(c, "Logged out successfully", nil)
 }
 
@@ -184,7 +219,7 @@ func (h *AuthHandler) UpdateUser(c *gin.Context) {
 
 	var req entity.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -194,7 +229,7 @@ func (h *AuthHandler) UpdateUser(c *gin.Context) {
 			response.NotFound(c, "User not found")
 			return
 		}
-		response.SafeInternalError(c, "Failed to update user", err)
+		response.FromError(c, "Failed to update user", err)
 		return
 	}
 
@@ -208,7 +243,7 @@ func (h *AuthHandler) AdminUpdateUser(c *gin.Context) {
 
 	var req entity.AdminUpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -222,7 +257,7 @@ func (h *AuthHandler) AdminUpdateUser(c *gin.Context) {
 			response.BadRequest(c, "Email already in use")
 			return
 		}
-		response.SafeInternalError(c, "Failed to update user", err)
+		response.FromError(c, "Failed to update user", err)
 		return
 	}
 
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"github.com/condotrack/api/pkg/response"
+	"github.com/condotrack/api/pkg/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// respondBindError answers a request whose body could not be bound. Invalid fields get 422
+// with one entry per field, in the language of Accept-Language; a malformed body gets 400.
+func respondBindError(c *gin.Context, err error) {
+	fields, ok := validation.Translate(err, validation.Locale(c.GetHeader("Accept-Language")))
+	if !ok {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	response.FieldValidationError(c, "Invalid request", fields)
+}
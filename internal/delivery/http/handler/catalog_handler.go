@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/condotrack/api/internal/usecase/catalog"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogHandler handles the public course catalog requests of the marketing site
+type CatalogHandler struct {
+	usecase catalog.UseCase
+	maxAge  time.Duration
+}
+
+// NewCatalogHandler creates a new catalog handler. Responses may be cached by browsers and
+// CDNs for maxAge, the time the use case keeps the catalog in memory.
+func NewCatalogHandler(uc catalog.UseCase, maxAge time.Duration) *CatalogHandler {
+	return &CatalogHandler{usecase: uc, maxAge: maxAge}
+}
+
+// ListCourses handles GET /api/v1/catalog/courses
+func (h *CatalogHandler) ListCourses(c *gin.Context) {
+	courses, err := h.usecase.ListCourses(c.Request.Context())
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch courses", err)
+		return
+	}
+	h.cacheable(c)
+	response.Success(c, courses)
+}
+
+// GetCourse handles GET /api/v1/catalog/courses/:id
+func (h *CatalogHandler) GetCourse(c *gin.Context) {
+	course, err := h.usecase.GetCourse(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, catalog.ErrCourseNotFound) {
+			response.NotFound(c, "Course not found")
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch course", err)
+		return
+	}
+	h.cacheable(c)
+	response.Success(c, course)
+}
+
+func (h *CatalogHandler) cacheable(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(h.maxAge.Seconds())))
+}
@@ -71,13 +71,20 @@ func (h *CertificadoHandler) GenerateCertificate(c *gin.Context) {
 		EnrollmentID string `json:"enrollment_id" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
 	certificate, err := h.usecase.GenerateCertificate(ctx, req.EnrollmentID)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to generate certificate", err)
+		switch err.Error() {
+		case "enrollment not found":
+			response.NotFound(c, "Enrollment not found")
+		case "enrollment is not completed", "payment is not confirmed", "invalid student CPF on the enrollment":
+			response.BadRequest(c, err.Error())
+		default:
+			response.SafeInternalError(c, "Failed to generate certificate", err)
+		}
 		return
 	}
 
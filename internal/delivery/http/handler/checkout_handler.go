@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/usecase/checkout"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -22,19 +24,33 @@ func (h *CheckoutHandler) CreateCheckout(c *gin.Context) {
 
 	var req checkout.CheckoutRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
 	result, err := h.usecase.CreateCheckout(ctx, &req)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to create checkout", err)
+		response.FromError(c, "Failed to create checkout", err)
 		return
 	}
 
 	response.Created(c, result)
 }
 
+// GetPaymentMethods handles GET /api/v1/checkout/methods
+// Query params: course_id (required), discount_code
+func (h *CheckoutHandler) GetPaymentMethods(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	methods, err := h.usecase.GetPaymentMethods(ctx, c.Query("course_id"), c.Query("discount_code"))
+	if err != nil {
+		response.FromError(c, "Failed to fetch payment methods", err)
+		return
+	}
+
+	response.Success(c, methods)
+}
+
 // GetCheckoutStatus handles GET /api/v1/checkout/:id/status
 func (h *CheckoutHandler) GetCheckoutStatus(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -48,3 +64,143 @@ func (h *CheckoutHandler) GetCheckoutStatus(c *gin.Context) {
 
 	response.Success(c, result)
 }
+
+// RetryCheckout handles POST /api/v1/checkout/:id/retry
+// Charges the enrollment again after its payment failed or expired, keeping its price and coupon.
+func (h *CheckoutHandler) RetryCheckout(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req checkout.RetryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+	result, err := h.usecase.RetryCheckout(ctx, c.Param("id"), &req, userID, role)
+	if err != nil {
+		response.FromError(c, "Failed to retry checkout", err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// CreateSession handles POST /api/v1/checkout/sessions
+// Opens a checkout session for a landing page; its token authorizes the embedded checkout
+// to pay for the course and poll the session, without a user token.
+func (h *CheckoutHandler) CreateSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.CreateCheckoutSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	session, err := h.usecase.CreateSession(ctx, &req)
+	if err != nil {
+		response.FromError(c, "Failed to create checkout session", err)
+		return
+	}
+
+	response.Created(c, session)
+}
+
+// CheckoutSession handles POST /api/v1/checkout/sessions/:token/pay
+func (h *CheckoutHandler) CheckoutSession(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req checkout.SessionCheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.usecase.CheckoutSession(ctx, c.Param("token"), &req)
+	if err != nil {
+		response.FromError(c, "Failed to create checkout", err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// GetSessionStatus handles GET /api/v1/checkout/sessions/:token
+func (h *CheckoutHandler) GetSessionStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	status, err := h.usecase.GetSessionStatus(ctx, c.Param("token"))
+	if err != nil {
+		response.FromError(c, "Failed to get checkout session", err)
+		return
+	}
+
+	response.Success(c, status)
+}
+
+// RenewEnrollment handles POST /api/v1/enrollments/:id/renew
+func (h *CheckoutHandler) RenewEnrollment(c *gin.Context) {
+	ctx := c.Request.Context()
+	enrollmentID := c.Param("id")
+
+	var req checkout.RenewalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+	result, err := h.usecase.RenewEnrollment(ctx, enrollmentID, &req, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "enrollment not found":
+			response.NotFound(c, "Enrollment not found")
+		case "only active, completed or expired enrollments can be renewed",
+			"enrollment payment is not settled",
+			"enrollment already has a pending renewal",
+			"course has no price to renew",
+			"student CPF is required to create a renewal charge":
+			response.BadRequest(c, err.Error())
+		default:
+			response.FromError(c, "Failed to renew enrollment", err)
+		}
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// CancelEnrollment handles POST /api/v1/enrollments/:id/cancel
+func (h *CheckoutHandler) CancelEnrollment(c *gin.Context) {
+	ctx := c.Request.Context()
+	enrollmentID := c.Param("id")
+
+	// The body is optional; it only carries the cancellation reason
+	var req entity.CancelEnrollmentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	result, err := h.usecase.CancelEnrollment(ctx, enrollmentID, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "enrollment not found":
+			response.NotFound(c, "Enrollment not found")
+		case "enrollment already cancelled or being cancelled",
+			"payment has no gateway reference to refund":
+			response.BadRequest(c, err.Error())
+		default:
+			response.SafeInternalError(c, "Failed to cancel enrollment", err)
+		}
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/condotrack/api/internal/usecase/compliance"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// maxComplianceDays bounds the window of the compliance dashboard
+const maxComplianceDays = 365
+
+// ComplianceHandler handles the compliance dashboard
+type ComplianceHandler struct {
+	usecase compliance.UseCase
+}
+
+// NewComplianceHandler creates a new compliance handler
+func NewComplianceHandler(uc compliance.UseCase) *ComplianceHandler {
+	return &ComplianceHandler{usecase: uc}
+}
+
+// GetExpirations handles GET /api/v1/compliance/expirations
+// Query params: days (1-365, default 60)
+func (h *ComplianceHandler) GetExpirations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	days := compliance.DefaultDays
+	if d := c.Query("days"); d != "" {
+		n, err := strconv.Atoi(d)
+		if err != nil || n < 1 || n > maxComplianceDays {
+			response.BadRequest(c, "Invalid days: use 1 to 365")
+			return
+		}
+		days = n
+	}
+
+	expirations, err := h.usecase.GetExpirations(ctx, days)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch compliance expirations", err)
+		return
+	}
+
+	response.Success(c, expirations)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/contractbilling"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ContractBillingHandler handles contract monthly fee billing HTTP requests
+type ContractBillingHandler struct {
+	usecase contractbilling.UseCase
+}
+
+// NewContractBillingHandler creates a new contract billing handler
+func NewContractBillingHandler(uc contractbilling.UseCase) *ContractBillingHandler {
+	return &ContractBillingHandler{usecase: uc}
+}
+
+// GetPlan handles GET /api/v1/contratos/:id/billing
+func (h *ContractBillingHandler) GetPlan(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	plan, err := h.usecase.GetPlan(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch billing plan")
+		return
+	}
+
+	response.Success(c, plan)
+}
+
+// SavePlan handles PUT /api/v1/contratos/:id/billing
+func (h *ContractBillingHandler) SavePlan(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.SaveContractBillingPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	plan, err := h.usecase.SavePlan(ctx, c.Param("id"), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to save billing plan")
+		return
+	}
+
+	response.Success(c, plan)
+}
+
+// ListContractCharges handles GET /api/v1/contratos/:id/billing/charges
+// Query params: status, from, to (YYYY-MM)
+func (h *ContractBillingHandler) ListContractCharges(c *gin.Context) {
+	filters := chargeFilters(c)
+	filters.ContratoID = c.Param("id")
+	h.listCharges(c, filters)
+}
+
+// ListCharges handles GET /api/v1/contract-billing/charges
+// Query params: contrato_id, status, from, to (YYYY-MM)
+func (h *ContractBillingHandler) ListCharges(c *gin.Context) {
+	filters := chargeFilters(c)
+	filters.ContratoID = c.Query("contrato_id")
+	h.listCharges(c, filters)
+}
+
+// CreateCharge handles POST /api/v1/contratos/:id/billing/charges
+func (h *ContractBillingHandler) CreateCharge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.CreateContractChargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	charge, err := h.usecase.CreateCharge(ctx, c.Param("id"), req.Period)
+	if err != nil {
+		h.handleError(c, err, "Failed to create contract charge")
+		return
+	}
+
+	response.Created(c, charge)
+}
+
+// CancelCharge handles POST /api/v1/contratos/:id/billing/charges/:chargeId/cancel
+func (h *ContractBillingHandler) CancelCharge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	charge, err := h.usecase.CancelCharge(ctx, c.Param("id"), c.Param("chargeId"))
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel contract charge")
+		return
+	}
+
+	response.Success(c, charge)
+}
+
+// GetReceivables handles GET /api/v1/contract-billing/receivables
+func (h *ContractBillingHandler) GetReceivables(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	report, err := h.usecase.GetReceivables(ctx)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch contract receivables", err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// RunBilling handles POST /api/v1/contract-billing/run
+func (h *ContractBillingHandler) RunBilling(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := h.usecase.RunBilling(ctx, time.Now())
+	if err != nil {
+		response.SafeInternalError(c, "Failed to run contract billing", err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+func (h *ContractBillingHandler) listCharges(c *gin.Context, filters entity.ContractChargeFilters) {
+	ctx := c.Request.Context()
+
+	charges, err := h.usecase.ListCharges(ctx, filters)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch contract charges")
+		return
+	}
+
+	response.Success(c, charges)
+}
+
+func chargeFilters(c *gin.Context) entity.ContractChargeFilters {
+	return entity.ContractChargeFilters{
+		Status: c.Query("status"),
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+	}
+}
+
+func (h *ContractBillingHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.FromError(c, message, err)
+	}
+}
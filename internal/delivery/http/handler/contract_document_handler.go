@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/internal/usecase/contractdocument"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ContractDocumentHandler handles contract document HTTP requests
+type ContractDocumentHandler struct {
+	usecase contractdocument.UseCase
+	uploads *storage.UploadPolicies
+	cfg     *config.Config
+}
+
+// NewContractDocumentHandler creates a new contract document handler
+func NewContractDocumentHandler(uc contractdocument.UseCase, uploads *storage.UploadPolicies, cfg *config.Config) *ContractDocumentHandler {
+	return &ContractDocumentHandler{usecase: uc, uploads: uploads, cfg: cfg}
+}
+
+// ListDocuments handles GET /api/v1/contratos/:id/documents
+// Query params: document_type, include_inactive
+func (h *ContractDocumentHandler) ListDocuments(c *gin.Context) {
+	ctx := c.Request.Context()
+	contratoID := c.Param("id")
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	filters := entity.ContractDocumentFilters{
+		DocumentType:    c.Query("document_type"),
+		IncludeInactive: c.Query("include_inactive") == "true",
+	}
+
+	docs, err := h.usecase.ListDocuments(ctx, contratoID, filters, userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch contract documents")
+		return
+	}
+
+	response.Success(c, docs)
+}
+
+// GetDocument handles GET /api/v1/contratos/:id/documents/:docId
+func (h *ContractDocumentHandler) GetDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	doc, err := h.usecase.GetDocument(ctx, c.Param("id"), c.Param("docId"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch contract document")
+		return
+	}
+
+	response.Success(c, doc)
+}
+
+// CreateDocument handles POST /api/v1/contratos/:id/documents
+// Multipart form: file, document_type, title, description, effective_from, effective_until, notes
+func (h *ContractDocumentHandler) CreateDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	title := strings.TrimSpace(c.PostForm("title"))
+	if title == "" {
+		response.BadRequest(c, "title is required")
+		return
+	}
+
+	file, upload, ok := h.readUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	req := &entity.CreateContractDocumentRequest{
+		DocumentType: c.PostForm("document_type"),
+		Title:        title,
+		Description:  optionalForm(c, "description"),
+		Upload:       *upload,
+	}
+
+	doc, err := h.usecase.CreateDocument(ctx, c.Param("id"), req, file, userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to upload contract document")
+		return
+	}
+
+	response.Created(c, doc)
+}
+
+// AddVersion handles POST /api/v1/contratos/:id/documents/:docId/versions
+// Multipart form: file, effective_from, effective_until, notes
+func (h *ContractDocumentHandler) AddVersion(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	file, upload, ok := h.readUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	version, err := h.usecase.AddVersion(ctx, c.Param("id"), c.Param("docId"), upload, file, userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to upload document version")
+		return
+	}
+
+	response.Created(c, version)
+}
+
+// DownloadDocument handles GET /api/v1/contratos/:id/documents/:docId/download
+// Query params: version (specific version) or date (YYYY-MM-DD, version in effect on that day)
+func (h *ContractDocumentHandler) DownloadDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	var version int
+	if v := c.Query("version"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			response.BadRequest(c, "Invalid version")
+			return
+		}
+		version = n
+	}
+
+	var on *time.Time
+	if d := c.Query("date"); d != "" {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			response.BadRequest(c, "Invalid date format, expected YYYY-MM-DD")
+			return
+		}
+		on = &t
+	}
+
+	v, reader, err := h.usecase.OpenVersion(ctx, c.Param("id"), c.Param("docId"), version, on, userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to download contract document")
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, v.Size, v.ContentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, strings.ReplaceAll(v.FileName, `"`, "")),
+	})
+}
+
+// ArchiveDocument handles DELETE /api/v1/contratos/:id/documents/:docId
+func (h *ContractDocumentHandler) ArchiveDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	if err := h.usecase.ArchiveDocument(ctx, c.Param("id"), c.Param("docId"), userID, role); err != nil {
+		h.handleError(c, err, "Failed to archive contract document")
+		return
+	}
+
+	response.Success(c, map[string]string{"message": "Document archived successfully"})
+}
+
+// readUpload validates the multipart file and collects the version metadata
+func (h *ContractDocumentHandler) readUpload(c *gin.Context) (multipart.File, *entity.ContractDocumentUpload, bool) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "No file provided")
+		return nil, nil, false
+	}
+
+	contentType := storage.GetContentTypeFromExtension(header.Filename)
+	if !checkUpload(c, h.uploads.Get(storage.UploadContractDocument), contentType, header.Size) {
+		file.Close()
+		return nil, nil, false
+	}
+
+	return file, &entity.ContractDocumentUpload{
+		FileName:       header.Filename,
+		ContentType:    contentType,
+		Size:           header.Size,
+		EffectiveFrom:  optionalForm(c, "effective_from"),
+		EffectiveUntil: optionalForm(c, "effective_until"),
+		Notes:          optionalForm(c, "notes"),
+	}, true
+}
+
+func (h *ContractDocumentHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, contractdocument.ErrAccessDenied):
+		response.Forbidden(c, err.Error())
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"),
+		err.Error() == "document is archived":
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
+
+func optionalForm(c *gin.Context, key string) *string {
+	v := strings.TrimSpace(c.PostForm(key))
+	if v == "" {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/contractfinance"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ContractFinanceHandler handles contract budget and cost HTTP requests
+type ContractFinanceHandler struct {
+	usecase contractfinance.UseCase
+}
+
+// NewContractFinanceHandler creates a new contract finance handler
+func NewContractFinanceHandler(uc contractfinance.UseCase) *ContractFinanceHandler {
+	return &ContractFinanceHandler{usecase: uc}
+}
+
+// ListBudgetLines handles GET /api/v1/contratos/:id/budget-lines
+func (h *ContractFinanceHandler) ListBudgetLines(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	lines, err := h.usecase.ListBudgetLines(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch budget lines")
+		return
+	}
+
+	response.Success(c, lines)
+}
+
+// CreateBudgetLine handles POST /api/v1/contratos/:id/budget-lines
+func (h *ContractFinanceHandler) CreateBudgetLine(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.CreateContractBudgetLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	line, err := h.usecase.CreateBudgetLine(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create budget line")
+		return
+	}
+
+	response.Created(c, line)
+}
+
+// UpdateBudgetLine handles PUT /api/v1/contratos/:id/budget-lines/:lineId
+func (h *ContractFinanceHandler) UpdateBudgetLine(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.UpdateContractBudgetLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	line, err := h.usecase.UpdateBudgetLine(ctx, c.Param("id"), c.Param("lineId"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update budget line")
+		return
+	}
+
+	response.Success(c, line)
+}
+
+// DeleteBudgetLine handles DELETE /api/v1/contratos/:id/budget-lines/:lineId
+func (h *ContractFinanceHandler) DeleteBudgetLine(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.usecase.DeleteBudgetLine(ctx, c.Param("id"), c.Param("lineId")); err != nil {
+		h.handleError(c, err, "Failed to delete budget line")
+		return
+	}
+
+	response.Success(c, map[string]string{"message": "Budget line deleted successfully"})
+}
+
+// ListCosts handles GET /api/v1/contratos/:id/costs
+// Query params: from, to (YYYY-MM), source (manual, task_time)
+func (h *ContractFinanceHandler) ListCosts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := entity.ContractCostFilters{
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+		Source: c.Query("source"),
+	}
+
+	costs, err := h.usecase.ListCosts(ctx, c.Param("id"), filters)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch contract costs")
+		return
+	}
+
+	response.Success(c, costs)
+}
+
+// RecordCost handles POST /api/v1/contratos/:id/costs
+func (h *ContractFinanceHandler) RecordCost(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.CreateContractCostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	cost, err := h.usecase.RecordCost(ctx, c.Param("id"), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to record contract cost")
+		return
+	}
+
+	response.Created(c, cost)
+}
+
+// DeleteCost handles DELETE /api/v1/contratos/:id/costs/:costId
+func (h *ContractFinanceHandler) DeleteCost(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.usecase.DeleteCost(ctx, c.Param("id"), c.Param("costId")); err != nil {
+		h.handleError(c, err, "Failed to delete contract cost")
+		return
+	}
+
+	response.Success(c, map[string]string{"message": "Cost deleted successfully"})
+}
+
+// GetFinancialReport handles GET /api/v1/contratos/:id/financials
+// Query params: from, to (YYYY-MM; defaults to the last 12 months)
+func (h *ContractFinanceHandler) GetFinancialReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	report, err := h.usecase.GetFinancialReport(ctx, c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleError(c, err, "Failed to build financial report")
+		return
+	}
+
+	response.Success(c, report)
+}
+
+func (h *ContractFinanceHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
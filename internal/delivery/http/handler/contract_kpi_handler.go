@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/usecase/contractkpi"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ContractKPIHandler handles contract KPI snapshot HTTP requests
+type ContractKPIHandler struct {
+	usecase contractkpi.UseCase
+}
+
+// NewContractKPIHandler creates a new contract KPI handler
+func NewContractKPIHandler(uc contractkpi.UseCase) *ContractKPIHandler {
+	return &ContractKPIHandler{usecase: uc}
+}
+
+// GetHistory handles GET /api/v1/contratos/:id/kpis/history
+// Query params: from, to (YYYY-MM-DD; defaults to the last 90 days)
+func (h *ContractKPIHandler) GetHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	history, err := h.usecase.GetHistory(ctx, c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			response.NotFound(c, "Contrato not found")
+		case strings.HasPrefix(err.Error(), "invalid"):
+			response.BadRequest(c, err.Error())
+		default:
+			response.SafeInternalError(c, "Failed to fetch contract KPI history", err)
+		}
+		return
+	}
+
+	response.Success(c, history)
+}
+
+// RunSnapshot handles POST /api/v1/contratos/kpis/snapshot
+func (h *ContractKPIHandler) RunSnapshot(c *gin.Context) {
+	result, err := h.usecase.RunSnapshot(c.Request.Context(), time.Now())
+	if err != nil {
+		response.SafeInternalError(c, "Failed to take contract KPI snapshot", err)
+		return
+	}
+
+	response.Success(c, result)
+}
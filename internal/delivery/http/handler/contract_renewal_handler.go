@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/contractrenewal"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ContractRenewalHandler handles contract renewal HTTP requests
+type ContractRenewalHandler struct {
+	usecase contractrenewal.UseCase
+}
+
+// NewContractRenewalHandler creates a new contract renewal handler
+func NewContractRenewalHandler(uc contractrenewal.UseCase) *ContractRenewalHandler {
+	return &ContractRenewalHandler{usecase: uc}
+}
+
+// ListRenewals handles GET /api/v1/contratos/renewals
+// Query params: status (pending, renegotiating, renewed, lost)
+func (h *ContractRenewalHandler) ListRenewals(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	renewals, err := h.usecase.ListRenewals(ctx, entity.ContractRenewalFilters{Status: c.Query("status")})
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch contract renewals", err)
+		return
+	}
+
+	response.Success(c, renewals)
+}
+
+// ListUpcoming handles GET /api/v1/contratos/renewals/upcoming
+// Query params: days (defaults to the widest alert threshold)
+func (h *ContractRenewalHandler) ListUpcoming(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	days := 0
+	if d := c.Query("days"); d != "" {
+		n, err := strconv.Atoi(d)
+		if err != nil || n < 1 {
+			response.BadRequest(c, "Invalid days")
+			return
+		}
+		days = n
+	}
+
+	upcoming, err := h.usecase.ListUpcoming(ctx, days)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch upcoming renewals", err)
+		return
+	}
+
+	response.Success(c, upcoming)
+}
+
+// RunAlerts handles POST /api/v1/contratos/renewals/alerts/run
+func (h *ContractRenewalHandler) RunAlerts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	result, err := h.usecase.RunAlerts(ctx, time.Now())
+	if err != nil {
+		response.SafeInternalError(c, "Failed to run renewal alerts", err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ListContractRenewals handles GET /api/v1/contratos/:id/renewals
+func (h *ContractRenewalHandler) ListContractRenewals(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	renewals, err := h.usecase.ListContractRenewals(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch contract renewals")
+		return
+	}
+
+	response.Success(c, renewals)
+}
+
+// CreateRenewal handles POST /api/v1/contratos/:id/renewals
+func (h *ContractRenewalHandler) CreateRenewal(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.CreateContractRenewalRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	renewal, err := h.usecase.CreateRenewal(ctx, c.Param("id"), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create contract renewal")
+		return
+	}
+
+	response.Created(c, renewal)
+}
+
+// UpdateRenewal handles PUT /api/v1/contratos/:id/renewals/:renewalId
+func (h *ContractRenewalHandler) UpdateRenewal(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.UpdateContractRenewalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	renewal, err := h.usecase.UpdateRenewal(ctx, c.Param("id"), c.Param("renewalId"), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update contract renewal")
+		return
+	}
+
+	response.Success(c, renewal)
+}
+
+func (h *ContractRenewalHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"),
+		strings.HasPrefix(err.Error(), "renewal is already"),
+		err.Error() == "contract already has an open renewal":
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
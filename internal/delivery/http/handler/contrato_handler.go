@@ -80,7 +80,7 @@ func (h *ContratoHandler) CreateContrato(c *gin.Context) {
 
 	var req entity.CreateContratoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -100,7 +100,7 @@ func (h *ContratoHandler) UpdateContrato(c *gin.Context) {
 
 	var req entity.UpdateContratoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -138,3 +138,32 @@ func (h *ContratoHandler) DeleteContrato(c *gin.Context) {
 
 	response.Success(c, map[string]string{"message": "Contrato deleted successfully"})
 }
+
+// ListDeletedContratos handles GET /api/v1/contratos/trash
+func (h *ContratoHandler) ListDeletedContratos(c *gin.Context) {
+	contratos, err := h.usecase.ListDeletedContratos(c.Request.Context())
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch deleted contratos", err)
+		return
+	}
+
+	response.Success(c, contratos)
+}
+
+// RestoreContrato handles POST /api/v1/contratos/:id/restore
+func (h *ContratoHandler) RestoreContrato(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	contrato, err := h.usecase.RestoreContrato(ctx, id)
+	if err != nil {
+		if err.Error() == "contrato not found" {
+			response.NotFound(c, "Contrato not found in trash")
+			return
+		}
+		response.SafeInternalError(c, "Failed to restore contrato", err)
+		return
+	}
+
+	response.Success(c, contrato)
+}
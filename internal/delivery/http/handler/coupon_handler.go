@@ -75,7 +75,7 @@ func (h *CouponHandler) CreateCoupon(c *gin.Context) {
 
 	var req coupon.CreateCouponRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -102,7 +102,7 @@ func (h *CouponHandler) UpdateCoupon(c *gin.Context) {
 
 	var req coupon.UpdateCouponRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -134,7 +134,7 @@ func (h *CouponHandler) ValidateCoupon(c *gin.Context) {
 
 	var req coupon.ValidateCouponRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -99,7 +99,7 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 
 	var req entity.CreateCourseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -119,7 +119,7 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 
 	var req entity.UpdateCourseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
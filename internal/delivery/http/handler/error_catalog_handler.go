@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCatalogHandler publishes the error codes the API can return
+type ErrorCatalogHandler struct{}
+
+// NewErrorCatalogHandler creates a new error catalog handler
+func NewErrorCatalogHandler() *ErrorCatalogHandler {
+	return &ErrorCatalogHandler{}
+}
+
+// ListErrorCodes handles GET /api/v1/errors
+func (h *ErrorCatalogHandler) ListErrorCodes(c *gin.Context) {
+	response.Success(c, apperror.Catalog())
+}
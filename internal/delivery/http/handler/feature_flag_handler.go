@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/featureflag"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureFlagHandler handles feature flag HTTP requests
+type FeatureFlagHandler struct {
+	usecase featureflag.UseCase
+}
+
+// NewFeatureFlagHandler creates a new feature flag handler
+func NewFeatureFlagHandler(uc featureflag.UseCase) *FeatureFlagHandler {
+	return &FeatureFlagHandler{usecase: uc}
+}
+
+// ListFlags handles GET /api/v1/feature-flags
+func (h *FeatureFlagHandler) ListFlags(c *gin.Context) {
+	flags, err := h.usecase.ListFlags(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch feature flags")
+		return
+	}
+
+	response.Success(c, flags)
+}
+
+// GetFlag handles GET /api/v1/feature-flags/:key
+func (h *FeatureFlagHandler) GetFlag(c *gin.Context) {
+	flag, err := h.usecase.GetFlag(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch feature flag")
+		return
+	}
+
+	response.Success(c, flag)
+}
+
+// CreateFlag handles POST /api/v1/feature-flags
+func (h *FeatureFlagHandler) CreateFlag(c *gin.Context) {
+	var req entity.CreateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	flag, err := h.usecase.CreateFlag(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create feature flag")
+		return
+	}
+
+	response.Created(c, flag)
+}
+
+// UpdateFlag handles PUT /api/v1/feature-flags/:key
+func (h *FeatureFlagHandler) UpdateFlag(c *gin.Context) {
+	var req entity.UpdateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	flag, err := h.usecase.UpdateFlag(c.Request.Context(), c.Param("key"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update feature flag")
+		return
+	}
+
+	response.Success(c, flag)
+}
+
+// DeleteFlag handles DELETE /api/v1/feature-flags/:key
+func (h *FeatureFlagHandler) DeleteFlag(c *gin.Context) {
+	if err := h.usecase.DeleteFlag(c.Request.Context(), c.Param("key")); err != nil {
+		h.handleError(c, err, "Failed to delete feature flag")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Feature flag deleted", nil)
+}
+
+// Evaluate handles GET /api/v1/feature-flags/evaluate
+// Query params: contract_id (optional org the flags are evaluated for)
+func (h *FeatureFlagHandler) Evaluate(c *gin.Context) {
+	fc := middleware.FlagContext(c, c.Query("contract_id"))
+
+	flags, err := h.usecase.Evaluate(c.Request.Context(), fc)
+	if err != nil {
+		h.handleError(c, err, "Failed to evaluate feature flags")
+		return
+	}
+
+	response.Success(c, flags)
+}
+
+func (h *FeatureFlagHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"github.com/condotrack/api/pkg/fieldset"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// selectFields trims a list to the ?fields= sparse fieldset of the request. It responds
+// 400 and returns false when a requested field does not exist.
+func selectFields(c *gin.Context, list interface{}) (interface{}, bool) {
+	selected, err := fieldset.Select(list, fieldset.Parse(c.Query("fields")))
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return nil, false
+	}
+	return selected, true
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"github.com/condotrack/api/internal/infrastructure/external"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// GatewayHandler exposes the state of the payment gateways
+type GatewayHandler struct {
+	factory *external.GatewayFactory
+}
+
+// NewGatewayHandler creates a new gateway handler
+func NewGatewayHandler(factory *external.GatewayFactory) *GatewayHandler {
+	return &GatewayHandler{factory: factory}
+}
+
+// ListGateways handles GET /api/v1/settings/gateways
+// Returns the registered gateways, whether they accept new payments and which is the default.
+func (h *GatewayHandler) ListGateways(c *gin.Context) {
+	response.Success(c, h.factory.Status())
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"strings"
 
+	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/usecase/gestor"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -69,7 +70,7 @@ func (h *GestorHandler) CreateGestor(c *gin.Context) {
 
 	var req gestor.CreateGestorRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -79,7 +80,7 @@ func (h *GestorHandler) CreateGestor(c *gin.Context) {
 			response.BadRequest(c, err.Error())
 			return
 		}
-		response.SafeInternalError(c, "Failed to create gestor", err)
+		response.FromError(c, "Failed to create gestor", err)
 		return
 	}
 
@@ -93,7 +94,12 @@ func (h *GestorHandler) UpdateGestor(c *gin.Context) {
 
 	var req gestor.UpdateGestorRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
+		return
+	}
+	// The account link decides who owns the record, so only admins change it
+	if role, _ := middleware.GetUserRole(c); req.UserID != nil && role != "admin" {
+		response.Forbidden(c, "Only admins can change the user of a gestor")
 		return
 	}
 
@@ -107,7 +113,7 @@ func (h *GestorHandler) UpdateGestor(c *gin.Context) {
 			response.BadRequest(c, err.Error())
 			return
 		}
-		response.SafeInternalError(c, "Failed to update gestor", err)
+		response.FromError(c, "Failed to update gestor", err)
 		return
 	}
 
@@ -131,3 +137,84 @@ func (h *GestorHandler) DeleteGestor(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "Gestor deleted successfully", nil)
 }
+
+// ListDeletedGestores handles GET /api/v1/gestores/trash
+func (h *GestorHandler) ListDeletedGestores(c *gin.Context) {
+	gestores, err := h.usecase.ListDeletedGestores(c.Request.Context())
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch deleted gestores", err)
+		return
+	}
+
+	response.Success(c, gestores)
+}
+
+// RestoreGestor handles POST /api/v1/gestores/:id/restore
+func (h *GestorHandler) RestoreGestor(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	gestor, err := h.usecase.RestoreGestor(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, "Gestor not found in trash")
+			return
+		}
+		response.SafeInternalError(c, "Failed to restore gestor", err)
+		return
+	}
+
+	response.Success(c, gestor)
+}
+
+// GetGestorMetrics handles GET /api/v1/gestores/:id/metrics
+// Query params: from, to (YYYY-MM-DD; defaults to the last 12 months)
+func (h *GestorHandler) GetGestorMetrics(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	metrics, err := h.usecase.GetGestorMetrics(ctx, id, c.Query("from"), c.Query("to"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch gestor metrics", err)
+		return
+	}
+
+	response.Success(c, metrics)
+}
+
+// ReassignGestor handles POST /api/v1/gestores/:id/reassign
+// Moves the contracts, open tasks and scheduled inspections of the gestor to to_gestor_id.
+func (h *GestorHandler) ReassignGestor(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req gestor.ReassignGestorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	summary, err := h.usecase.ReassignGestor(ctx, id, &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to reassign gestor", err)
+		return
+	}
+
+	response.Success(c, summary)
+}
@@ -5,18 +5,20 @@ import (
 	"time"
 
 	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/infrastructure/external/resilience"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 )
 
 // HealthHandler handles health check requests
 type HealthHandler struct {
-	db *database.MySQL
+	db       *database.MySQL
+	gateways *resilience.Registry
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(db *database.MySQL) *HealthHandler {
-	return &HealthHandler{db: db}
+func NewHealthHandler(db *database.MySQL, gateways *resilience.Registry) *HealthHandler {
+	return &HealthHandler{db: db, gateways: gateways}
 }
 
 // HealthCheck handles GET /api/v1/health
@@ -30,13 +32,24 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 		dbStatus = "unhealthy: " + err.Error()
 	}
 
+	services := gin.H{
+		"database": dbStatus,
+	}
+	if h.db.Replica != nil {
+		replicaStatus := "healthy"
+		if err := h.db.ReplicaHealth(ctx); err != nil {
+			replicaStatus = "unhealthy: " + err.Error()
+		}
+		services["database_replica"] = replicaStatus
+	}
+	// An open breaker degrades payments but not the application, so it does not fail the check
+	services["gateways"] = h.gateways.Status()
+
 	c.JSON(200, gin.H{
 		"success":   true,
 		"message":   "OK",
 		"timestamp": time.Now().Format(time.RFC3339),
-		"services": gin.H{
-			"database": dbStatus,
-		},
+		"services":  services,
 	})
 }
 
@@ -1,67 +1,91 @@
 package handler
 
 import (
+	"bytes"
 	"fmt"
 	"io"
-	"os"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/pkg/imaging"
 	"github.com/condotrack/api/pkg/response"
+	"github.com/condotrack/api/pkg/signedurl"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-// ImageHandler handles image upload and management
+// imageFilePath is the public route serving images through signed links
+const imageFilePath = "/api/v1/images/file/"
+
+// ImageHandler handles image upload and management. Originals are stored in the uploads
+// bucket under their filename and each variant under "<variant>/<filename>".
 type ImageHandler struct {
-	cfg *config.Config
+	storage *storage.StorageService
+	signer  *signedurl.Signer
+	uploads *storage.UploadPolicies
+	cfg     *config.Config
 }
 
 // NewImageHandler creates a new image handler
-func NewImageHandler(cfg *config.Config) *ImageHandler {
-	return &ImageHandler{cfg: cfg}
+func NewImageHandler(storage *storage.StorageService, uploads *storage.UploadPolicies, cfg *config.Config) *ImageHandler {
+	return &ImageHandler{
+		storage: storage,
+		signer:  signedurl.New(cfg.ImageURLSecret),
+		uploads: uploads,
+		cfg:     cfg,
+	}
+}
+
+func (h *ImageHandler) checkStorage(c *gin.Context) bool {
+	if h.storage == nil {
+		response.InternalError(c, "Storage service is not available")
+		return false
+	}
+	return true
 }
 
 // ListImages handles GET /api/v1/images
 func (h *ImageHandler) ListImages(c *gin.Context) {
-	uploadDir := h.cfg.UploadDir
-
-	// Ensure directory exists
-	if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-		response.Success(c, []string{})
+	if !h.checkStorage(c) {
 		return
 	}
 
-	files, err := os.ReadDir(uploadDir)
+	files, err := h.storage.ListFiles(c.Request.Context(), h.cfg.MinioBucketUploads, "")
 	if err != nil {
 		response.SafeInternalError(c, "Failed to list images", err)
 		return
 	}
 
-	var images []gin.H
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-
-		// Filter only image files
-		ext := strings.ToLower(filepath.Ext(file.Name()))
-		if !isImageExtension(ext) {
-			continue
+	// Variants are listed under the original they belong to
+	variants := make(map[string][]string)
+	for _, f := range files {
+		if dir, name, ok := strings.Cut(f.Name, "/"); ok {
+			variants[name] = append(variants[name], dir)
 		}
+	}
 
-		info, err := file.Info()
-		if err != nil {
+	expires := h.expiry()
+	images := []gin.H{}
+	for _, f := range files {
+		if strings.Contains(f.Name, "/") || !isImageFile(f.Name) {
 			continue
 		}
 
 		images = append(images, gin.H{
-			"filename":   file.Name(),
-			"url":        fmt.Sprintf("/uploads/%s", file.Name()),
-			"size":       info.Size(),
-			"created_at": info.ModTime().Format(time.RFC3339),
+			"filename":   f.Name,
+			"url":        h.signedURL(f.Name, "", expires),
+			"variants":   h.variantURLs(f.Name, variants[f.Name], expires),
+			"size":       f.Size,
+			"created_at": f.LastModified.Format(time.RFC3339),
+			"expires_at": expires.Format(time.RFC3339),
 		})
 	}
 
@@ -70,13 +94,10 @@ func (h *ImageHandler) ListImages(c *gin.Context) {
 
 // UploadImage handles POST /api/v1/images
 func (h *ImageHandler) UploadImage(c *gin.Context) {
-	uploadDir := h.cfg.UploadDir
-
-	// Ensure directory exists
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		response.SafeInternalError(c, "Failed to create upload directory", err)
+	if !h.checkStorage(c) {
 		return
 	}
+	ctx := c.Request.Context()
 
 	// Get file from request
 	file, header, err := c.Request.FormFile("file")
@@ -86,81 +107,195 @@ func (h *ImageHandler) UploadImage(c *gin.Context) {
 	}
 	defer file.Close()
 
-	// Check file size
-	if header.Size > h.cfg.MaxUploadSize {
-		response.BadRequest(c, fmt.Sprintf("File size exceeds maximum allowed size of %d bytes", h.cfg.MaxUploadSize))
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if !checkUpload(c, h.uploads.Get(storage.UploadImage), storage.GetContentTypeFromExtension(header.Filename), header.Size) {
 		return
 	}
 
-	// Check file extension
-	ext := strings.ToLower(filepath.Ext(header.Filename))
-	if !isImageExtension(ext) {
-		response.BadRequest(c, "Invalid file type. Allowed types: jpg, jpeg, png, gif, webp")
+	data, err := io.ReadAll(file)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to read file", err)
 		return
 	}
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
 
 	// Generate unique filename
 	newFilename := fmt.Sprintf("%s_%s%s", time.Now().Format("20060102150405"), uuid.New().String()[:8], ext)
-	filePath := filepath.Join(uploadDir, newFilename)
 
-	// Create destination file
-	dst, err := os.Create(filePath)
-	if err != nil {
-		response.SafeInternalError(c, "Failed to create file", err)
+	if _, err := h.storage.UploadFile(ctx, h.cfg.MinioBucketUploads, newFilename, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		response.SafeInternalError(c, "Failed to save file", err)
 		return
 	}
-	defer dst.Close()
 
-	// Copy file content
-	if _, err := io.Copy(dst, file); err != nil {
-		response.SafeInternalError(c, "Failed to save file", err)
-		return
+	// A failed variant is not fatal: the original is served in its place
+	var created []string
+	if imaging.Supported(contentType) {
+		resized, err := imaging.GenerateVariants(data)
+		if err != nil {
+			log.Printf("[IMAGES] No variants for %s: %v", newFilename, err)
+		}
+		for _, v := range resized {
+			key := v.Variant.Name + "/" + newFilename
+			if _, err := h.storage.UploadFile(ctx, h.cfg.MinioBucketUploads, key, bytes.NewReader(v.Data), int64(len(v.Data)), v.ContentType); err != nil {
+				log.Printf("[IMAGES] Failed to store %s: %v", key, err)
+				continue
+			}
+			created = append(created, v.Variant.Name)
+		}
 	}
 
+	expires := h.expiry()
 	response.Created(c, gin.H{
-		"filename":     newFilename,
-		"url":          fmt.Sprintf("/uploads/%s", newFilename),
+		"filename":      newFilename,
+		"url":           h.signedURL(newFilename, "", expires),
+		"variants":      h.variantURLs(newFilename, created, expires),
 		"original_name": header.Filename,
-		"size":         header.Size,
+		"size":          header.Size,
+		"expires_at":    expires.Format(time.RFC3339),
 	})
 }
 
 // DeleteImage handles DELETE /api/v1/images/:filename
 func (h *ImageHandler) DeleteImage(c *gin.Context) {
+	if !h.checkStorage(c) {
+		return
+	}
+	ctx := c.Request.Context()
 	filename := c.Param("filename")
 
-	// Validate filename (prevent path traversal)
-	if strings.Contains(filename, "..") || strings.Contains(filename, "/") || strings.Contains(filename, "\\") {
+	if !validImageFilename(filename) {
 		response.BadRequest(c, "Invalid filename")
 		return
 	}
 
-	filePath := filepath.Join(h.cfg.UploadDir, filename)
-
-	// Check if file exists
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	if !h.storage.FileExists(ctx, h.cfg.MinioBucketUploads, filename) {
 		response.NotFound(c, "Image not found")
 		return
 	}
 
-	// Delete file
-	if err := os.Remove(filePath); err != nil {
+	if err := h.storage.DeleteFile(ctx, h.cfg.MinioBucketUploads, filename); err != nil {
 		response.SafeInternalError(c, "Failed to delete image", err)
 		return
 	}
+	for _, v := range imaging.Variants {
+		if err := h.storage.DeleteFile(ctx, h.cfg.MinioBucketUploads, v.Name+"/"+filename); err != nil {
+			log.Printf("[IMAGES] Failed to delete %s variant of %s: %v", v.Name, filename, err)
+		}
+	}
 
 	response.Success(c, gin.H{
 		"message": "Image deleted successfully",
 	})
 }
 
-// isImageExtension checks if the extension is a valid image extension
-func isImageExtension(ext string) bool {
-	validExtensions := []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
-	for _, valid := range validExtensions {
-		if ext == valid {
+// ServeImage handles GET /api/v1/images/file/:filename?variant=&expires=&signature=.
+// The route is public; access is granted by the signature of a link from ListImages or
+// UploadImage. A variant that was not generated falls back to the original.
+func (h *ImageHandler) ServeImage(c *gin.Context) {
+	if !h.checkStorage(c) {
+		return
+	}
+	ctx := c.Request.Context()
+	filename := c.Param("filename")
+	variant := c.Query("variant")
+
+	if !validImageFilename(filename) || (variant != "" && !isVariant(variant)) {
+		response.BadRequest(c, "Invalid filename")
+		return
+	}
+
+	expires, err := h.signer.Verify(objectKey(filename, variant), c.Query("expires"), c.Query("signature"), time.Now())
+	if err != nil {
+		response.Forbidden(c, "Invalid or expired image link")
+		return
+	}
+
+	key := objectKey(filename, variant)
+	if variant != "" && !h.storage.FileExists(ctx, h.cfg.MinioBucketUploads, key) {
+		key = filename
+	}
+
+	reader, info, err := h.storage.GetFile(ctx, h.cfg.MinioBucketUploads, key)
+	if err != nil {
+		response.NotFound(c, "Image not found")
+		return
+	}
+	defer reader.Close()
+
+	seeker, ok := reader.(io.ReadSeeker)
+	if !ok {
+		response.InternalError(c, "Failed to read image")
+		return
+	}
+
+	// The link stops working at expires, so caches must not keep the image beyond it
+	maxAge := int(time.Until(expires).Seconds())
+	c.Header("Cache-Control", "private, max-age="+strconv.Itoa(maxAge))
+	if info.ETag != "" {
+		c.Header("ETag", `"`+info.ETag+`"`)
+	}
+	if info.ContentType != "" {
+		c.Header("Content-Type", info.ContentType)
+	}
+	http.ServeContent(c.Writer, c.Request, filename, info.LastModified, seeker)
+}
+
+// expiry returns the expiry of the links generated now
+func (h *ImageHandler) expiry() time.Time {
+	return signedurl.Expiry(time.Now(), time.Duration(h.cfg.ImageURLTTL)*time.Minute)
+}
+
+// signedURL returns the link to an image or one of its variants
+func (h *ImageHandler) signedURL(filename, variant string, expires time.Time) string {
+	q := h.signer.Sign(objectKey(filename, variant), expires)
+	if variant != "" {
+		q.Set("variant", variant)
+	}
+	return imageFilePath + url.PathEscape(filename) + "?" + q.Encode()
+}
+
+// variantURLs returns the links of every variant by name. Variants missing for an image
+// (too small, or a format that cannot be resized) link to the original.
+func (h *ImageHandler) variantURLs(filename string, available []string, expires time.Time) gin.H {
+	urls := gin.H{}
+	for _, v := range imaging.Variants {
+		name := ""
+		for _, a := range available {
+			if a == v.Name {
+				name = v.Name
+			}
+		}
+		urls[v.Name] = h.signedURL(filename, name, expires)
+	}
+	return urls
+}
+
+// objectKey returns the key of an image or one of its variants in the uploads bucket
+func objectKey(filename, variant string) string {
+	if variant == "" {
+		return filename
+	}
+	return variant + "/" + filename
+}
+
+func isVariant(name string) bool {
+	for _, v := range imaging.Variants {
+		if v.Name == name {
 			return true
 		}
 	}
 	return false
 }
+
+// validImageFilename rejects path traversal and keys of other folders
+func validImageFilename(filename string) bool {
+	return filename != "" && !strings.Contains(filename, "..") && !strings.Contains(filename, "/") && !strings.Contains(filename, "\\")
+}
+
+// isImageFile checks if the file name has an image extension
+func isImageFile(filename string) bool {
+	return strings.HasPrefix(storage.GetContentTypeFromExtension(filename), "image/")
+}
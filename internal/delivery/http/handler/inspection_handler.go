@@ -68,6 +68,15 @@ func (h *InspectionHandler) ListInspections(c *gin.Context) {
 		hasFilter = true
 	}
 
+	q, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+	if !q.IsEmpty() {
+		filter.Query = q
+		hasFilter = true
+	}
+
 	var filterPtr *entity.InspectionFilter
 	if hasFilter {
 		filterPtr = filter
@@ -75,7 +84,7 @@ func (h *InspectionHandler) ListInspections(c *gin.Context) {
 
 	inspections, err := h.usecase.ListInspections(ctx, filterPtr)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to fetch inspections", err)
+		respondListError(c, err, "Failed to fetch inspections")
 		return
 	}
 
@@ -107,7 +116,7 @@ func (h *InspectionHandler) CreateInspection(c *gin.Context) {
 
 	var req entity.CreateInspectionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -143,7 +152,7 @@ func (h *InspectionHandler) UpdateInspection(c *gin.Context) {
 
 	var req entity.UpdateInspectionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/instructorportal"
+	"github.com/condotrack/api/internal/usecase/payout"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// InstructorPortalHandler handles the self-service requests of the logged-in instructor
+// under /api/v1/me/instructor. The instructor is always the subject of the token.
+type InstructorPortalHandler struct {
+	usecase instructorportal.UseCase
+}
+
+// NewInstructorPortalHandler creates a new instructor portal handler
+func NewInstructorPortalHandler(uc instructorportal.UseCase) *InstructorPortalHandler {
+	return &InstructorPortalHandler{usecase: uc}
+}
+
+// ListCourses handles GET /api/v1/me/instructor/courses
+func (h *InstructorPortalHandler) ListCourses(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	courses, err := h.usecase.ListCourses(ctx, userID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch courses", err)
+		return
+	}
+
+	response.Success(c, courses)
+}
+
+// ListEnrollments handles GET /api/v1/me/instructor/enrollments
+// Query params: page, per_page (max 100), filter[...], sort
+func (h *InstructorPortalHandler) ListEnrollments(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	page, perPage := 1, 20
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(c.Query("per_page")); err == nil && pp > 0 && pp <= 100 {
+		perPage = pp
+	}
+
+	q, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	enrollments, total, err := h.usecase.ListEnrollments(ctx, userID, page, perPage, q)
+	if err != nil {
+		respondListError(c, err, "Failed to fetch enrollments")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"enrollments": enrollments,
+		"total":       total,
+		"page":        page,
+		"per_page":    perPage,
+	})
+}
+
+// GetEarnings handles GET /api/v1/me/instructor/earnings
+func (h *InstructorPortalHandler) GetEarnings(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	earnings, err := h.usecase.GetEarnings(ctx, userID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch earnings", err)
+		return
+	}
+
+	response.Success(c, earnings)
+}
+
+// ListPayouts handles GET /api/v1/me/instructor/payouts
+// Query params: status
+func (h *InstructorPortalHandler) ListPayouts(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	batches, err := h.usecase.ListPayouts(ctx, userID, c.Query("status"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch payouts")
+		return
+	}
+
+	response.Success(c, batches)
+}
+
+// GetPayoutAccount handles GET /api/v1/me/instructor/payout-account
+func (h *InstructorPortalHandler) GetPayoutAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	account, err := h.usecase.GetPayoutAccount(ctx, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch payout account")
+		return
+	}
+
+	response.Success(c, account)
+}
+
+// SavePayoutAccount handles PUT /api/v1/me/instructor/payout-account
+func (h *InstructorPortalHandler) SavePayoutAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.SavePayoutAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	account, err := h.usecase.SavePayoutAccount(ctx, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to save payout account")
+		return
+	}
+
+	response.Success(c, account)
+}
+
+// VerifyPayoutAccount handles POST /api/v1/me/instructor/payout-account/verify
+// Checks the account again with the gateway or sends a new micro-deposit.
+func (h *InstructorPortalHandler) VerifyPayoutAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	account, err := h.usecase.VerifyPayoutAccount(ctx, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to verify payout account")
+		return
+	}
+
+	response.Success(c, account)
+}
+
+// ConfirmPayoutAccount handles POST /api/v1/me/instructor/payout-account/confirm
+func (h *InstructorPortalHandler) ConfirmPayoutAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.ConfirmMicroDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	account, err := h.usecase.ConfirmPayoutAccount(ctx, userID, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to confirm payout account")
+		return
+	}
+
+	response.Success(c, account)
+}
+
+func (h *InstructorPortalHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, payout.ErrAccessDenied):
+		response.Forbidden(c, err.Error())
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
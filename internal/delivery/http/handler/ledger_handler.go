@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/usecase/ledger"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// LedgerHandler handles instructor earnings ledger HTTP requests
+type LedgerHandler struct {
+	usecase ledger.UseCase
+}
+
+// NewLedgerHandler creates a new instructor ledger handler
+func NewLedgerHandler(uc ledger.UseCase) *LedgerHandler {
+	return &LedgerHandler{usecase: uc}
+}
+
+// GetLedger handles GET /api/v1/revenue-splits/instructor/:id/ledger
+// Query params: from, to (YYYY-MM-DD, optional)
+func (h *LedgerHandler) GetLedger(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	statement, err := h.usecase.GetLedger(ctx, c.Param("id"), c.Query("from"), c.Query("to"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch instructor ledger")
+		return
+	}
+
+	response.Success(c, statement)
+}
+
+// GetStatement handles GET /api/v1/revenue-splits/instructor/:id/statement
+// Query params: month (YYYY-MM, defaults to the current month)
+func (h *LedgerHandler) GetStatement(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	statement, err := h.usecase.GetStatement(ctx, c.Param("id"), c.Query("month"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch instructor statement")
+		return
+	}
+
+	response.Success(c, statement)
+}
+
+// ExportStatementPDF handles GET /api/v1/revenue-splits/instructor/:id/statement/pdf
+// Query params: month (YYYY-MM, defaults to the current month)
+func (h *LedgerHandler) ExportStatementPDF(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	statement, content, err := h.usecase.ExportStatementPDF(ctx, c.Param("id"), c.Query("month"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to export instructor statement")
+		return
+	}
+
+	fileName := fmt.Sprintf("extrato-%s-%s.pdf", statement.InstructorID, statement.PeriodStart.Format("2006-01"))
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+func (h *LedgerHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, ledger.ErrAccessDenied):
+		response.Forbidden(c, err.Error())
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
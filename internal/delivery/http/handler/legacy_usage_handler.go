@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// LegacyUsageHandler reports how much the deprecated legacy router is still used
+type LegacyUsageHandler struct {
+	repo       repository.LegacyUsageRepository
+	successors map[string]string
+}
+
+// NewLegacyUsageHandler creates a new legacy usage handler; successors maps each legacy
+// endpoint to its /api/v1 replacement
+func NewLegacyUsageHandler(repo repository.LegacyUsageRepository, successors map[string]string) *LegacyUsageHandler {
+	return &LegacyUsageHandler{repo: repo, successors: successors}
+}
+
+// GetUsage handles GET /api/v1/legacy-usage
+// Query params: days (period ending today, default 30, max 365)
+func (h *LegacyUsageHandler) GetUsage(c *gin.Context) {
+	days := 30
+	if d := c.Query("days"); d != "" {
+		parsed, err := strconv.Atoi(d)
+		if err != nil || parsed < 1 || parsed > 365 {
+			response.BadRequest(c, "Invalid days, expected 1 to 365")
+			return
+		}
+		days = parsed
+	}
+
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1-days)
+	summary, err := h.repo.Summary(c.Request.Context(), since)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch legacy router usage", err)
+		return
+	}
+	if summary == nil {
+		summary = []entity.LegacyUsageSummary{}
+	}
+	for i := range summary {
+		summary[i].Successor = h.successors[summary[i].Endpoint]
+	}
+
+	response.Success(c, gin.H{
+		"since":     since.Format("2006-01-02"),
+		"endpoints": summary,
+	})
+}
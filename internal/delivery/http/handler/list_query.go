@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/listquery"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// parseListQuery reads the filter[...] and sort parameters of a list request. It responds
+// 400 and returns false when they are malformed.
+func parseListQuery(c *gin.Context) (listquery.Query, bool) {
+	q, err := listquery.Parse(c.Request.URL.Query())
+	if err != nil {
+		response.BadRequest(c, err.Error())
+		return listquery.Query{}, false
+	}
+	return q, true
+}
+
+// respondListError answers a failed list request: filters or sort fields the repository does
+// not support are the client's fault, anything else is an internal error
+func respondListError(c *gin.Context, err error, message string) {
+	if errors.Is(err, listquery.ErrInvalid) {
+		response.BadRequest(c, err.Error())
+		return
+	}
+	response.SafeInternalError(c, message, err)
+}
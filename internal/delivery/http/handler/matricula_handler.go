@@ -2,7 +2,9 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 
+	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/usecase/matricula"
 	"github.com/condotrack/api/pkg/response"
@@ -20,6 +22,7 @@ func NewMatriculaHandler(uc matricula.UseCase) *MatriculaHandler {
 }
 
 // ListEnrollments handles GET /api/v1/enrollments
+// Query params: student_id, page, per_page, fields (sparse fieldset, e.g. id,student_name,status)
 func (h *MatriculaHandler) ListEnrollments(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -31,7 +34,11 @@ func (h *MatriculaHandler) ListEnrollments(c *gin.Context) {
 			response.SafeInternalError(c, "Failed to fetch enrollments", err)
 			return
 		}
-		response.Success(c, enrollments)
+		selected, ok := selectFields(c, enrollments)
+		if !ok {
+			return
+		}
+		response.Success(c, selected)
 		return
 	}
 
@@ -54,13 +61,83 @@ func (h *MatriculaHandler) ListEnrollments(c *gin.Context) {
 		}
 	}
 
-	result, err := h.usecase.ListEnrollments(ctx, page, perPage)
+	q, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.usecase.ListEnrollments(ctx, page, perPage, q)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to fetch enrollments", err)
+		respondListError(c, err, "Failed to fetch enrollments")
 		return
 	}
 
-	response.Success(c, result)
+	selected, ok := selectFields(c, result.Enrollments)
+	if !ok {
+		return
+	}
+	response.Success(c, gin.H{
+		"enrollments": selected,
+		"total":       result.Total,
+		"page":        result.Page,
+		"per_page":    result.PerPage,
+	})
+}
+
+// SearchEnrollments handles GET /api/v1/enrollments/search
+// Query params: q (student name, email or CPF, or course name), status and payment_status
+// (repeated or comma separated), course_id, page, per_page, fields
+func (h *MatriculaHandler) SearchEnrollments(c *gin.Context) {
+	filter := &entity.EnrollmentSearchFilter{
+		Terms:           c.Query("q"),
+		Statuses:        queryList(c, "status"),
+		PaymentStatuses: queryList(c, "payment_status"),
+		Page:            1,
+		PerPage:         10,
+	}
+	if courseID := c.Query("course_id"); courseID != "" {
+		filter.CourseID = &courseID
+	}
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+		filter.Page = p
+	}
+	if pp, err := strconv.Atoi(c.Query("per_page")); err == nil && pp > 0 {
+		filter.PerPage = pp
+	}
+
+	result, err := h.usecase.SearchEnrollments(c.Request.Context(), filter)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to search enrollments", err)
+		return
+	}
+
+	selected, ok := selectFields(c, result.Enrollments)
+	if !ok {
+		return
+	}
+	response.Success(c, gin.H{
+		"enrollments": selected,
+		"total":       result.Total,
+		"page":        result.Page,
+		"per_page":    result.PerPage,
+	})
+}
+
+// queryList returns the values of a query parameter given repeated or comma separated
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
 }
 
 // GetEnrollmentByID handles GET /api/v1/enrollments/:id
@@ -88,12 +165,16 @@ func (h *MatriculaHandler) CreateEnrollment(c *gin.Context) {
 
 	var req entity.CreateMatriculaRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
 	enrollment, err := h.usecase.CreateEnrollment(ctx, &req)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
 		response.SafeInternalError(c, "Failed to create enrollment", err)
 		return
 	}
@@ -110,7 +191,7 @@ func (h *MatriculaHandler) UpdatePaymentStatus(c *gin.Context) {
 		Status string `json:"status" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -133,7 +214,7 @@ func (h *MatriculaHandler) UpdateProgress(c *gin.Context) {
 		Progress float64 `json:"progress" binding:"required,min=0,max=100"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -146,3 +227,106 @@ func (h *MatriculaHandler) UpdateProgress(c *gin.Context) {
 		"message": "Progress updated successfully",
 	})
 }
+
+// BulkCreateEnrollments handles POST /api/v1/enrollments/bulk
+// Accepts either a JSON body ({"course_id": "...", "students": [...]}) or a
+// multipart form with a course_id field and a CSV file in the "file" field.
+func (h *MatriculaHandler) BulkCreateEnrollments(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.BulkEnrollmentRequest
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		req.CourseID = c.PostForm("course_id")
+		if req.CourseID == "" {
+			response.BadRequest(c, "course_id is required")
+			return
+		}
+
+		file, _, err := c.Request.FormFile("file")
+		if err != nil {
+			response.BadRequest(c, "CSV file is required in the 'file' field")
+			return
+		}
+		defer file.Close()
+
+		students, err := matricula.ParseBulkEnrollmentCSV(file)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		req.Students = students
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.usecase.BulkCreateEnrollments(ctx, &req)
+	if err != nil {
+		if err.Error() == "course not found" {
+			response.NotFound(c, "Course not found")
+			return
+		}
+		if err.Error() == "no students provided" || strings.HasPrefix(err.Error(), "too many students") || strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to create bulk enrollments", err)
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// TransferEnrollment handles POST /api/v1/enrollments/:id/transfer
+func (h *MatriculaHandler) TransferEnrollment(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req entity.TransferEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	result, err := h.usecase.TransferEnrollment(ctx, id, &req, userID)
+	if err != nil {
+		switch {
+		case err.Error() == "enrollment not found":
+			response.NotFound(c, "Enrollment not found")
+		case err.Error() == "course not found":
+			response.NotFound(c, "Course not found")
+		case strings.HasPrefix(err.Error(), "transfer requires"),
+			strings.HasPrefix(err.Error(), "to_student_name"),
+			strings.HasPrefix(err.Error(), "invalid"),
+			strings.HasPrefix(err.Error(), "enrollment cannot be transferred"),
+			strings.HasPrefix(err.Error(), "target course"),
+			strings.HasPrefix(err.Error(), "student is already enrolled"),
+			strings.HasPrefix(err.Error(), "revenue split already processed"):
+			response.BadRequest(c, err.Error())
+		default:
+			response.SafeInternalError(c, "Failed to transfer enrollment", err)
+		}
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ListTransfers handles GET /api/v1/enrollments/:id/transfers
+func (h *MatriculaHandler) ListTransfers(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	transfers, err := h.usecase.ListTransfers(ctx, id)
+	if err != nil {
+		if err.Error() == "enrollment not found" {
+			response.NotFound(c, "Enrollment not found")
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch enrollment transfers", err)
+		return
+	}
+
+	response.Success(c, transfers)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/broadcast"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationBroadcastHandler handles notification broadcast HTTP requests
+type NotificationBroadcastHandler struct {
+	usecase broadcast.UseCase
+}
+
+// NewNotificationBroadcastHandler creates a new notification broadcast handler
+func NewNotificationBroadcastHandler(uc broadcast.UseCase) *NotificationBroadcastHandler {
+	return &NotificationBroadcastHandler{usecase: uc}
+}
+
+// CreateBroadcast handles POST /api/v1/notifications/broadcasts
+// Notifies every active user, or those of role, on their channels
+func (h *NotificationBroadcastHandler) CreateBroadcast(c *gin.Context) {
+	var req entity.CreateNotificationBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	b, err := h.usecase.Send(c.Request.Context(), userID, &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to send broadcast", err)
+		return
+	}
+	response.Created(c, b)
+}
+
+// ListBroadcasts handles GET /api/v1/notifications/broadcasts
+func (h *NotificationBroadcastHandler) ListBroadcasts(c *gin.Context) {
+	broadcasts, err := h.usecase.List(c.Request.Context())
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch broadcasts", err)
+		return
+	}
+	response.Success(c, broadcasts)
+}
+
+// GetBroadcastStats handles GET /api/v1/notifications/broadcasts/:id/stats
+func (h *NotificationBroadcastHandler) GetBroadcastStats(c *gin.Context) {
+	stats, err := h.usecase.GetStats(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, broadcast.ErrBroadcastNotFound) {
+			response.NotFound(c, "Broadcast not found")
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch broadcast stats", err)
+		return
+	}
+	response.Success(c, stats)
+}
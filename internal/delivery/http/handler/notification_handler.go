@@ -1,14 +1,26 @@
 package handler
 
 import (
+	"errors"
+	"log"
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
-	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/condotrack/api/pkg/realtime"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// wsPingEvery keeps idle WebSocket connections open through proxies
+	wsPingEvery    = 30 * time.Second
+	wsWriteTimeout = 10 * time.Second
 )
 
 // getUserIDFromContext gets user_id from JWT context (required auth)
@@ -19,14 +31,19 @@ func getUserIDFromContext(c *gin.Context) string {
 	return ""
 }
 
+// transparentGIF is the 1x1 tracking pixel of the e-mails
+var transparentGIF = []byte("GIF89a\x01\x00\x01\x00\x80\x00\x00\x00\x00\x00\xff\xff\xff!\xf9\x04\x01\x00\x00\x00\x00,\x00\x00\x00\x00\x01\x00\x01\x00\x00\x02\x02D\x01\x00;")
+
 // NotificationHandler handles notification-related HTTP requests
 type NotificationHandler struct {
-	repo repository.NotificacaoRepository
+	usecase notification.UseCase
+	tracker *notification.OpenTracker
 }
 
-// NewNotificationHandler creates a new notification handler
-func NewNotificationHandler(repo repository.NotificacaoRepository) *NotificationHandler {
-	return &NotificationHandler{repo: repo}
+// NewNotificationHandler creates a new notification handler; tracker verifies the links of
+// the e-mail tracking pixel and may be nil when e-mails carry none
+func NewNotificationHandler(uc notification.UseCase, tracker *notification.OpenTracker) *NotificationHandler {
+	return &NotificationHandler{usecase: uc, tracker: tracker}
 }
 
 // ListNotifications handles GET /api/v1/notifications
@@ -51,7 +68,7 @@ func (h *NotificationHandler) ListNotifications(c *gin.Context) {
 
 		if !isRead {
 			// Return only unread notifications
-			notifications, err := h.repo.FindUnreadByUserID(ctx, userID)
+			notifications, err := h.usecase.ListUnread(ctx, userID)
 			if err != nil {
 				response.SafeInternalError(c, "Failed to fetch notifications", err)
 				return
@@ -62,7 +79,7 @@ func (h *NotificationHandler) ListNotifications(c *gin.Context) {
 	}
 
 	// Return all notifications for the user
-	notifications, err := h.repo.FindByUserID(ctx, userID)
+	notifications, err := h.usecase.List(ctx, userID)
 	if err != nil {
 		response.SafeInternalError(c, "Failed to fetch notifications", err)
 		return
@@ -81,7 +98,7 @@ func (h *NotificationHandler) GetUnreadNotifications(c *gin.Context) {
 		return
 	}
 
-	notifications, err := h.repo.FindUnreadByUserID(ctx, userID)
+	notifications, err := h.usecase.ListUnread(ctx, userID)
 	if err != nil {
 		response.SafeInternalError(c, "Failed to fetch unread notifications", err)
 		return
@@ -100,7 +117,7 @@ func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
 		return
 	}
 
-	count, err := h.repo.CountUnread(ctx, userID)
+	count, err := h.usecase.CountUnread(ctx, userID)
 	if err != nil {
 		response.SafeInternalError(c, "Failed to count unread notifications", err)
 		return
@@ -118,7 +135,7 @@ func (h *NotificationHandler) CreateNotification(c *gin.Context) {
 
 	var req entity.CreateNotificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -147,7 +164,7 @@ func (h *NotificationHandler) CreateNotification(c *gin.Context) {
 		Read:    false,
 	}
 
-	if err := h.repo.Create(ctx, notification); err != nil {
+	if err := h.usecase.Create(ctx, notification); err != nil {
 		response.SafeInternalError(c, "Failed to create notification", err)
 		return
 	}
@@ -165,7 +182,11 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.MarkAsRead(ctx, id); err != nil {
+	if err := h.usecase.MarkAsRead(ctx, getUserIDFromContext(c), id); err != nil {
+		if errors.Is(err, notification.ErrNotificationNotFound) {
+			response.NotFound(c, "Notification not found")
+			return
+		}
 		response.SafeInternalError(c, "Failed to mark notification as read", err)
 		return
 	}
@@ -186,7 +207,7 @@ func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.MarkAllAsRead(ctx, userID); err != nil {
+	if err := h.usecase.MarkAllAsRead(ctx, userID); err != nil {
 		response.SafeInternalError(c, "Failed to mark all notifications as read", err)
 		return
 	}
@@ -207,7 +228,11 @@ func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
 		return
 	}
 
-	if err := h.repo.Delete(ctx, id); err != nil {
+	if err := h.usecase.Delete(ctx, getUserIDFromContext(c), id); err != nil {
+		if errors.Is(err, notification.ErrNotificationNotFound) {
+			response.NotFound(c, "Notification not found")
+			return
+		}
 		response.SafeInternalError(c, "Failed to delete notification", err)
 		return
 	}
@@ -217,3 +242,184 @@ func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
 		"id":      id,
 	})
 }
+
+// GetReceipts handles GET /api/v1/notifications/:id/receipts
+// Returns the delivery and read state of the notification on each channel
+func (h *NotificationHandler) GetReceipts(c *gin.Context) {
+	receipts, err := h.usecase.GetReceipts(c.Request.Context(), getUserIDFromContext(c), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, notification.ErrNotificationNotFound) {
+			response.NotFound(c, "Notification not found")
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch notification receipts", err)
+		return
+	}
+	response.Success(c, receipts)
+}
+
+// ConfirmDelivery handles POST /api/v1/notifications/:id/delivered
+// Called by the service worker once a push woke it up and it fetched the notification
+func (h *NotificationHandler) ConfirmDelivery(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.usecase.ConfirmDelivery(c.Request.Context(), getUserIDFromContext(c), id); err != nil {
+		if errors.Is(err, notification.ErrNotificationNotFound) {
+			response.NotFound(c, "Notification not found")
+			return
+		}
+		response.SafeInternalError(c, "Failed to confirm notification delivery", err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"message": "Notification delivery confirmed",
+		"id":      id,
+	})
+}
+
+// TrackOpen handles GET /api/v1/notifications/:id/open.gif, the tracking pixel of the e-mail
+// of a notification (public, authorized by the expires and signature of its link). The pixel
+// is served whatever the outcome, so mail clients never show a broken image.
+func (h *NotificationHandler) TrackOpen(c *gin.Context) {
+	id := c.Param("id")
+	if h.tracker != nil && h.tracker.Verify(id, c.Query("expires"), c.Query("signature")) {
+		if err := h.usecase.RecordOpen(c.Request.Context(), id); err != nil {
+			log.Printf("[NOTIFICATION] Failed to record the open of notification %s: %v", id, err)
+		}
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/gif", transparentGIF)
+}
+
+// StreamUnreadCount handles GET /api/v1/notifications/ws, a WebSocket pushing the unread
+// count of the user as {"type":"unread_count","data":{"unread_count":n}}: once on connect and
+// again whenever it changes. New notifications are pushed as {"type":"notification","data":
+// {...}} and {"type":"ping"} events keep idle connections open.
+func (h *NotificationHandler) StreamUnreadCount(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	count, err := h.usecase.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to count unread notifications", err)
+		return
+	}
+
+	sub := h.usecase.Subscribe(userID)
+	defer sub.Close()
+
+	server := websocket.Server{
+		Handshake: acceptBearerProtocol,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			// The server's read and write timeouts would end the connection
+			ws.SetDeadline(time.Time{})
+
+			gone := make(chan struct{})
+			go func() {
+				var msg string
+				for websocket.Message.Receive(ws, &msg) == nil {
+				}
+				close(gone)
+			}()
+
+			send := func(e realtime.Event) bool {
+				ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+				return websocket.JSON.Send(ws, e) == nil
+			}
+			if !send(notification.UnreadCountEvent(count)) {
+				return
+			}
+
+			ping := time.NewTicker(wsPingEvery)
+			defer ping.Stop()
+			for {
+				select {
+				case <-gone:
+					return
+				case e, ok := <-sub.C:
+					// Closed at shutdown
+					if !ok || !send(e) {
+						return
+					}
+				case <-ping.C:
+					if !send(realtime.Event{Type: "ping"}) {
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// StreamNotifications handles GET /api/v1/notifications/stream, Server-Sent Events for
+// clients that cannot open a WebSocket. An "unread_count" event carries {"unread_count":n}
+// on connect and whenever the count changes; a "notification" event carries each new
+// notification of the user, such as a confirmed payment or an assigned task. "ping" events
+// keep idle connections open.
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		response.Unauthorized(c, "Authentication required")
+		return
+	}
+
+	count, err := h.usecase.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to count unread notifications", err)
+		return
+	}
+
+	sub := h.usecase.Subscribe(userID)
+	defer sub.Close()
+
+	// The server's write timeout would end the stream
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // keep proxies from buffering the stream
+	c.Status(http.StatusOK)
+
+	send := func(e realtime.Event) {
+		c.SSEvent(e.Type, e.Data)
+		c.Writer.Flush()
+	}
+	send(notification.UnreadCountEvent(count))
+
+	ping := time.NewTicker(wsPingEvery)
+	defer ping.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e, ok := <-sub.C:
+			// Closed at shutdown
+			if !ok {
+				return
+			}
+			send(e)
+		case <-ping.C:
+			send(realtime.Event{Type: "ping", Data: gin.H{}})
+		}
+	}
+}
+
+// acceptBearerProtocol selects the "bearer" subprotocol when the client authenticated with
+// it, as browsers drop connections that do not echo one of the offered subprotocols
+func acceptBearerProtocol(config *websocket.Config, _ *http.Request) error {
+	offered := config.Protocol
+	config.Protocol = nil
+	for _, p := range offered {
+		if p == middleware.WebSocketBearerProtocol {
+			config.Protocol = []string{p}
+			break
+		}
+	}
+	return nil
+}
@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/storage"
 	"github.com/condotrack/api/internal/usecase/payment"
+	"github.com/condotrack/api/internal/usecase/revenue"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -15,13 +22,17 @@ import (
 type PaymentHandler struct {
 	usecase       payment.UseCase
 	matriculaRepo repository.MatriculaRepository
+	splitRules    revenue.SplitRuleUseCase
+	uploads       *storage.UploadPolicies
 }
 
 // NewPaymentHandler creates a new payment handler
-func NewPaymentHandler(uc payment.UseCase, matriculaRepo repository.MatriculaRepository) *PaymentHandler {
+func NewPaymentHandler(uc payment.UseCase, matriculaRepo repository.MatriculaRepository, splitRules revenue.SplitRuleUseCase, uploads *storage.UploadPolicies) *PaymentHandler {
 	return &PaymentHandler{
 		usecase:       uc,
 		matriculaRepo: matriculaRepo,
+		splitRules:    splitRules,
+		uploads:       uploads,
 	}
 }
 
@@ -31,13 +42,13 @@ func (h *PaymentHandler) CreateCustomer(c *gin.Context) {
 
 	var req payment.CreateCustomerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
 	customer, err := h.usecase.CreateCustomer(ctx, &req)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to create customer", err)
+		response.FromError(c, "Failed to create customer", err)
 		return
 	}
 
@@ -50,7 +61,7 @@ func (h *PaymentHandler) CreatePixPayment(c *gin.Context) {
 
 	var req payment.CreatePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -82,7 +93,7 @@ func (h *PaymentHandler) CreateBoletoPayment(c *gin.Context) {
 
 	var req payment.CreatePaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -109,13 +120,13 @@ func (h *PaymentHandler) CreateCardPayment(c *gin.Context) {
 
 	var req payment.CreateCardPaymentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
 	gwPayment, err := h.usecase.CreateCardPayment(ctx, &req)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to create card payment", err)
+		response.FromError(c, "Failed to create card payment", err)
 		return
 	}
 
@@ -141,8 +152,188 @@ func (h *PaymentHandler) GetPaymentStatus(c *gin.Context) {
 	response.Success(c, result)
 }
 
+// GetInstallments handles GET /api/v1/payments/:id/installments
+func (h *PaymentHandler) GetInstallments(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	plan, err := h.usecase.GetInstallments(ctx, c.Param("id"), userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, payment.ErrPaymentNotFound):
+			response.NotFound(c, "Payment not found")
+		case errors.Is(err, payment.ErrAccessDenied):
+			response.Forbidden(c, err.Error())
+		case errors.Is(err, payment.ErrNoInstallments):
+			response.BadRequest(c, err.Error())
+		default:
+			response.SafeInternalError(c, "Failed to get payment installments", err)
+		}
+		return
+	}
+
+	response.Success(c, plan)
+}
+
+// GetPaymentTimeline handles GET /api/v1/payments/:id/timeline
+func (h *PaymentHandler) GetPaymentTimeline(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	timeline, err := h.usecase.GetPaymentTimeline(ctx, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, payment.ErrPaymentNotFound) {
+			response.NotFound(c, "Payment not found")
+			return
+		}
+		response.SafeInternalError(c, "Failed to get payment timeline", err)
+		return
+	}
+
+	response.Success(c, timeline)
+}
+
+// ReissueBoleto handles POST /api/v1/payments/:id/boleto/reissue
+func (h *PaymentHandler) ReissueBoleto(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// The body is optional; it only carries the new due date
+	var req payment.ReissueBoletoRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	result, err := h.usecase.ReissueBoleto(ctx, c.Param("id"), &req, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, payment.ErrPaymentNotFound):
+			response.NotFound(c, "Payment not found")
+		case strings.HasPrefix(err.Error(), "invalid"),
+			err.Error() == "payment has no gateway charge to reissue":
+			response.BadRequest(c, err.Error())
+		default:
+			response.FromError(c, "Failed to reissue boleto", err)
+		}
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// RegeneratePix handles POST /api/v1/payments/:id/pix/regenerate
+func (h *PaymentHandler) RegeneratePix(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	result, err := h.usecase.RegeneratePix(ctx, c.Param("id"), userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, payment.ErrPaymentNotFound):
+			response.NotFound(c, "Payment not found")
+		case errors.Is(err, payment.ErrAccessDenied):
+			response.Forbidden(c, err.Error())
+		case err.Error() == "payment has no gateway charge to regenerate":
+			response.BadRequest(c, err.Error())
+		default:
+			response.FromError(c, "Failed to regenerate PIX payment", err)
+		}
+		return
+	}
+
+	response.Created(c, result)
+}
+
+// Reconcile handles POST /api/v1/payments/reconcile
+func (h *PaymentHandler) Reconcile(c *gin.Context) {
+	// The body is optional; without it the run applies the fixes to the default batch
+	var req payment.ReconcileRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	report, err := h.usecase.Reconcile(c.Request.Context(), &req)
+	if err != nil {
+		response.FromError(c, "Failed to reconcile payments", err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// MarkPaid handles POST /api/v1/payments/:id/mark-paid
+// Multipart form: file (proof of payment), reason, payment_reference, paid_at
+func (h *PaymentHandler) MarkPaid(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "No proof file provided")
+		return
+	}
+	defer file.Close()
+
+	contentType := storage.GetContentTypeFromExtension(header.Filename)
+	if !checkUpload(c, h.uploads.Get(storage.UploadPaymentProof), contentType, header.Size) {
+		return
+	}
+
+	proof := &entity.PaymentProof{
+		FileName:         header.Filename,
+		ContentType:      contentType,
+		Size:             header.Size,
+		Reason:           c.PostForm("reason"),
+		PaymentReference: optionalForm(c, "payment_reference"),
+		PaidAt:           optionalForm(c, "paid_at"),
+	}
+
+	result, err := h.usecase.MarkPaid(ctx, c.Param("id"), proof, file, userID, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, payment.ErrPaymentNotFound):
+			response.NotFound(c, "Payment not found")
+		case strings.HasPrefix(err.Error(), "invalid"):
+			response.BadRequest(c, err.Error())
+		default:
+			response.FromError(c, "Failed to mark payment as paid", err)
+		}
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// DownloadProof handles GET /api/v1/payments/:id/proof
+func (h *PaymentHandler) DownloadProof(c *gin.Context) {
+	confirmation, reader, err := h.usecase.OpenProof(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, payment.ErrNoPaymentProof) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to download payment proof", err)
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, confirmation.ProofSize, confirmation.ProofContentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, strings.ReplaceAll(confirmation.ProofFileName, `"`, "")),
+	})
+}
+
 // SimulateRevenueSplit handles GET /api/v1/payments/simulate-split
+// Query parameters: value, method, course_id, instructor_id
 func (h *PaymentHandler) SimulateRevenueSplit(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var req entity.CalculateSplitRequest
 
 	if value := c.Query("value"); value != "" {
@@ -155,6 +346,9 @@ func (h *PaymentHandler) SimulateRevenueSplit(c *gin.Context) {
 		req.PaymentMethod = method
 	}
 
+	req.CourseID = c.Query("course_id")
+	req.InstructorID = c.Query("instructor_id")
+
 	if req.GrossAmount <= 0 {
 		response.BadRequest(c, "value parameter is required and must be greater than 0")
 		return
@@ -165,10 +359,19 @@ func (h *PaymentHandler) SimulateRevenueSplit(c *gin.Context) {
 	}
 
 	result := h.usecase.SimulateRevenueSplit(&req)
+
+	// Use the same split configuration the webhook would apply to this course/instructor
+	if err := h.splitRules.Simulate(ctx, result, req.CourseID, req.InstructorID); err != nil {
+		response.SafeInternalError(c, "Failed to simulate revenue split", err)
+		return
+	}
+
 	response.Success(c, result)
 }
 
 // ListPayments handles GET /api/v1/payments
+// Query params: enrollment_id, gateway, status, payment_method, date_from, date_to, page,
+// per_page, fields (sparse fieldset)
 func (h *PaymentHandler) ListPayments(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -205,14 +408,24 @@ func (h *PaymentHandler) ListPayments(c *gin.Context) {
 		}
 	}
 
+	q, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+	filters.Query = q
+
 	payments, total, err := h.usecase.ListPayments(ctx, filters)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to fetch payments", err)
+		respondListError(c, err, "Failed to fetch payments")
 		return
 	}
 
+	selected, ok := selectFields(c, payments)
+	if !ok {
+		return
+	}
 	response.Success(c, gin.H{
-		"payments": payments,
+		"payments": selected,
 		"total":    total,
 		"page":     filters.Page,
 		"per_page": filters.PerPage,
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/internal/usecase/payout"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// PayoutHandler handles instructor payout batch and transfer HTTP requests
+type PayoutHandler struct {
+	usecase   payout.UseCase
+	transfers payout.TransferUseCase
+	uploads   *storage.UploadPolicies
+	cfg       *config.Config
+}
+
+// NewPayoutHandler creates a new payout handler
+func NewPayoutHandler(uc payout.UseCase, transfers payout.TransferUseCase, uploads *storage.UploadPolicies, cfg *config.Config) *PayoutHandler {
+	return &PayoutHandler{usecase: uc, transfers: transfers, uploads: uploads, cfg: cfg}
+}
+
+// ListBatches handles GET /api/v1/payout-batches
+// Query params: instructor_id, status
+func (h *PayoutHandler) ListBatches(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := entity.PayoutBatchFilters{
+		InstructorID: c.Query("instructor_id"),
+		Status:       c.Query("status"),
+	}
+
+	batches, err := h.usecase.ListBatches(ctx, filters)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch payout batches", err)
+		return
+	}
+
+	response.Success(c, batches)
+}
+
+// ListPayableBalances handles GET /api/v1/payout-batches/pending
+func (h *PayoutHandler) ListPayableBalances(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	balances, err := h.usecase.ListPayableBalances(ctx)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch payable balances", err)
+		return
+	}
+
+	response.Success(c, balances)
+}
+
+// ListInstructorBatches handles GET /api/v1/payout-batches/instructor/:id
+// Query params: status
+func (h *PayoutHandler) ListInstructorBatches(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	batches, err := h.usecase.ListInstructorBatches(ctx, c.Param("id"), c.Query("status"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch instructor payouts")
+		return
+	}
+
+	response.Success(c, batches)
+}
+
+// GetBatch handles GET /api/v1/payout-batches/:id
+func (h *PayoutHandler) GetBatch(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	batch, err := h.usecase.GetBatch(ctx, c.Param("id"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch payout batch")
+		return
+	}
+
+	response.Success(c, batch)
+}
+
+// CreateBatch handles POST /api/v1/payout-batches
+func (h *PayoutHandler) CreateBatch(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.CreatePayoutBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	batch, err := h.usecase.CreateBatch(ctx, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create payout batch")
+		return
+	}
+
+	response.Created(c, batch)
+}
+
+// MarkPaid handles POST /api/v1/payout-batches/:id/pay
+// Multipart form: file (bank receipt), payment_reference, paid_at
+func (h *PayoutHandler) MarkPaid(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "No receipt file provided")
+		return
+	}
+	defer file.Close()
+
+	contentType := storage.GetContentTypeFromExtension(header.Filename)
+	if !checkUpload(c, h.uploads.Get(storage.UploadPayoutReceipt), contentType, header.Size) {
+		return
+	}
+
+	receipt := &entity.PayoutReceipt{
+		FileName:         header.Filename,
+		ContentType:      contentType,
+		Size:             header.Size,
+		PaymentReference: optionalForm(c, "payment_reference"),
+		PaidAt:           optionalForm(c, "paid_at"),
+	}
+
+	batch, err := h.usecase.MarkPaid(ctx, c.Param("id"), receipt, file, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to mark payout batch as paid")
+		return
+	}
+
+	response.Success(c, batch)
+}
+
+// CancelBatch handles POST /api/v1/payout-batches/:id/cancel
+func (h *PayoutHandler) CancelBatch(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	batch, err := h.usecase.CancelBatch(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel payout batch")
+		return
+	}
+
+	response.Success(c, batch)
+}
+
+// DownloadReceipt handles GET /api/v1/payout-batches/:id/receipt
+func (h *PayoutHandler) DownloadReceipt(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	batch, reader, err := h.usecase.OpenReceipt(ctx, c.Param("id"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to download payout receipt")
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, *batch.ReceiptSize, *batch.ReceiptContentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, strings.ReplaceAll(*batch.ReceiptFileName, `"`, "")),
+	})
+}
+
+// GetAccount handles GET /api/v1/payout-accounts/:id
+func (h *PayoutHandler) GetAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	account, err := h.transfers.GetAccount(ctx, c.Param("id"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch payout account")
+		return
+	}
+
+	response.Success(c, account)
+}
+
+// SaveAccount handles PUT /api/v1/payout-accounts/:id
+func (h *PayoutHandler) SaveAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.SavePayoutAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	account, err := h.transfers.SaveAccount(ctx, c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to save payout account")
+		return
+	}
+
+	response.Success(c, account)
+}
+
+// VerifyAccount handles POST /api/v1/payout-accounts/:id/verify
+func (h *PayoutHandler) VerifyAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	account, err := h.transfers.StartVerification(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to verify payout account")
+		return
+	}
+
+	response.Success(c, account)
+}
+
+// TransferSplit handles POST /api/v1/revenue-splits/:id/transfer
+// Sends the instructor amount through the gateway now; also used to retry failed transfers.
+func (h *PayoutHandler) TransferSplit(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	split, err := h.transfers.TransferSplit(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to transfer revenue split")
+		return
+	}
+
+	response.Success(c, split)
+}
+
+func (h *PayoutHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, payout.ErrAccessDenied):
+		response.Forbidden(c, err.Error())
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
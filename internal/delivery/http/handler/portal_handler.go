@@ -1,18 +1,25 @@
 package handler
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/ai"
+	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/internal/usecase/assistant"
+	"github.com/condotrack/api/internal/usecase/evidence"
+	"github.com/condotrack/api/internal/usecase/systemimage"
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -20,35 +27,26 @@ import (
 
 // PortalHandler handles portal-specific HTTP requests
 type PortalHandler struct {
-	storage *storage.StorageService
-	cfg     *config.Config
+	storage    *storage.StorageService
+	aiUC       assistant.UseCase
+	evidenceUC evidence.UseCase
+	systemUC   systemimage.UseCase
+	uploads    *storage.UploadPolicies
+	cfg        *config.Config
 }
 
 // NewPortalHandler creates a new portal handler
-func NewPortalHandler(storage *storage.StorageService, cfg *config.Config) *PortalHandler {
+func NewPortalHandler(storage *storage.StorageService, aiUC assistant.UseCase, evidenceUC evidence.UseCase, systemUC systemimage.UseCase, uploads *storage.UploadPolicies, cfg *config.Config) *PortalHandler {
 	return &PortalHandler{
-		storage: storage,
-		cfg:     cfg,
+		storage:    storage,
+		aiUC:       aiUC,
+		evidenceUC: evidenceUC,
+		systemUC:   systemUC,
+		uploads:    uploads,
+		cfg:        cfg,
 	}
 }
 
-// System image IDs that map to specific filenames
-var systemImageMap = map[string]string{
-	"sys_logo":      "logo-condotrack.png",
-	"sys_prof":      "professor-sergio.png",
-	"sys_prof_mini": "prof-sergio.png",
-	"pos_1":         "step1.png",
-	"pos_2":         "step2.png",
-	"pos_3":         "step3.png",
-	"pos_4":         "step4.png",
-	"pos_5":         "step5.png",
-	"pos_6":         "step6.png",
-	"pos_7":         "step7.png",
-	"padlet_bg":     "padlet_bg.png",
-	"padlet_cover":  "padlet-cover-v2.png",
-	"padlet_comm":   "padlet-comunidade.png",
-}
-
 // PortalImageResponse represents the response for portal images
 type PortalImageResponse struct {
 	Name             string   `json:"name"`
@@ -87,6 +85,11 @@ func (h *PortalHandler) ListPortalImages(c *gin.Context) {
 		response.SafeInternalError(c, "Failed to list images", err)
 		return
 	}
+	systemImages, err := h.systemUC.Filenames(ctx)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to load system images", err)
+		return
+	}
 
 	// Build response with portal-specific metadata
 	var images []PortalImageResponse
@@ -95,7 +98,7 @@ func (h *PortalHandler) ListPortalImages(c *gin.Context) {
 		isSystem := false
 
 		// Check if this is a system image
-		for id, filename := range systemImageMap {
+		for id, filename := range systemImages {
 			if f.Name == filename {
 				tags = append(tags, "system", id)
 				isSystem = true
@@ -125,7 +128,7 @@ func (h *PortalHandler) UploadPortalImage(c *gin.Context) {
 
 	var req UploadPortalImageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -135,10 +138,9 @@ func (h *PortalHandler) UploadPortalImage(c *gin.Context) {
 		return
 	}
 
-	// Validate base64 image size (rough estimate: base64 is ~4/3 of original)
-	// Limit to ~10MB decoded (which is ~13.3MB base64)
-	if len(req.Image) > 14*1024*1024 {
-		response.BadRequest(c, "Image too large. Maximum size is approximately 10MB.")
+	// The data URI carries the content type; base64 is ~4/3 of the decoded size
+	contentType, _, _ := strings.Cut(strings.TrimPrefix(req.Image, "data:"), ";")
+	if !checkUpload(c, h.uploads.Get(storage.UploadPortalImage), contentType, int64(len(req.Image))*3/4) {
 		return
 	}
 
@@ -147,8 +149,13 @@ func (h *PortalHandler) UploadPortalImage(c *gin.Context) {
 	isSystemOverwrite := false
 
 	if req.ID != "" {
+		systemImages, err := h.systemUC.Filenames(ctx)
+		if err != nil {
+			response.SafeInternalError(c, "Failed to load system images", err)
+			return
+		}
 		// Check if it's a system ID
-		if sysFilename, ok := systemImageMap[req.ID]; ok {
+		if sysFilename, ok := systemImages[req.ID]; ok {
 			targetFilename = sysFilename
 			isSystemOverwrite = true
 		}
@@ -202,7 +209,12 @@ func (h *PortalHandler) DeletePortalImage(c *gin.Context) {
 	}
 
 	// Check if it's a system image (cannot delete system images)
-	for _, sysFilename := range systemImageMap {
+	systemImages, err := h.systemUC.Filenames(ctx)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to load system images", err)
+		return
+	}
+	for _, sysFilename := range systemImages {
 		if filename == sysFilename {
 			response.BadRequest(c, "Cannot delete system images. You can only overwrite them.")
 			return
@@ -216,8 +228,7 @@ func (h *PortalHandler) DeletePortalImage(c *gin.Context) {
 	}
 
 	// Delete from MinIO
-	err := h.storage.DeleteFile(ctx, h.cfg.MinioBucketPortal, filename)
-	if err != nil {
+	if err := h.storage.DeleteFile(ctx, h.cfg.MinioBucketPortal, filename); err != nil {
 		response.SafeInternalError(c, "Failed to delete image", err)
 		return
 	}
@@ -228,149 +239,144 @@ func (h *PortalHandler) DeletePortalImage(c *gin.Context) {
 	})
 }
 
-// AIProxyRequest represents the request to proxy to Gemini AI
+// AIProxyRequest represents the request to the portal AI assistant
 type AIProxyRequest struct {
 	Message string `json:"message" binding:"required"`
 	Context string `json:"context,omitempty"`
 }
 
-// AIProxyResponse represents the response from Gemini AI
+// AIProxyResponse represents the answer of the portal AI assistant
 type AIProxyResponse struct {
 	Success  bool   `json:"success"`
 	Response string `json:"response"`
+	Provider string `json:"provider,omitempty"`
 	Error    string `json:"error,omitempty"`
 }
 
-// ProxyGeminiAI handles POST /api/v1/portal/ai
-func (h *PortalHandler) ProxyGeminiAI(c *gin.Context) {
-	var req AIProxyRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
-		return
-	}
+const (
+	portalAISystemPrompt = "CRÍTICO: TODAS as respostas DEVEM ser em PORTUGUÊS DO BRASIL, independente do idioma da pergunta. Você é um assistente especializado em gestão de condomínios, contratos de facilities, auditorias e ISO 9001."
 
-	// Limit input length to prevent payload amplification
-	if len(req.Message) > 5000 {
-		response.BadRequest(c, "Message too long. Maximum 5000 characters.")
-		return
-	}
-	if len(req.Context) > 10000 {
-		response.BadRequest(c, "Context too long. Maximum 10000 characters.")
-		return
-	}
+	// aiStreamTimeout bounds a streamed answer; the stream also ends when the client disconnects
+	aiStreamTimeout = 2 * time.Minute
+)
 
-	if h.cfg.GeminiAPIKey == "" {
-		response.InternalError(c, "AI service not available")
+// ProxyAI handles POST /api/v1/portal/ai
+func (h *PortalHandler) ProxyAI(c *gin.Context) {
+	req, call, ok := h.prepareAIRequest(c)
+	if !ok {
 		return
 	}
 
-	// Build Gemini request
-	geminiReq := map[string]interface{}{
-		"systemInstruction": map[string]interface{}{
-			"parts": []map[string]string{
-				{"text": "CRÍTICO: TODAS as respostas DEVEM ser em PORTUGUÊS DO BRASIL, independente do idioma da pergunta. Você é um assistente especializado em gestão de condomínios, contratos de facilities, auditorias e ISO 9001."},
-			},
-		},
-		"contents": []map[string]interface{}{
-			{
-				"role": "user",
-				"parts": []map[string]string{
-					{"text": req.Message},
-				},
-			},
-		},
-		"generationConfig": map[string]interface{}{
-			"temperature":     0.7,
-			"maxOutputTokens": 1024,
-		},
-	}
-
-	// Add context if provided
-	if req.Context != "" {
-		contents := geminiReq["contents"].([]map[string]interface{})
-		contents[0]["parts"] = []map[string]string{
-			{"text": fmt.Sprintf("Contexto: %s\n\nPergunta: %s", req.Context, req.Message)},
-		}
-		geminiReq["contents"] = contents
-	}
-
-	jsonData, err := json.Marshal(geminiReq)
+	resp, err := h.aiUC.Generate(c.Request.Context(), call, req)
 	if err != nil {
-		response.InternalError(c, "Failed to build request")
+		h.aiError(c, err)
 		return
 	}
 
-	// Call Gemini API (API key sent via header, not URL, to avoid logging exposure)
-	apiURL := "https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash:generateContent"
+	response.Success(c, AIProxyResponse{
+		Success:  true,
+		Response: resp.Text,
+		Provider: resp.Provider,
+	})
+}
+
+// StreamAI handles POST /api/v1/portal/ai/stream
+// Relays the answer as Server-Sent Events while the provider generates it: "message" events
+// carry {"text": chunk}, followed by a final "done" event, or an "error" event if the stream
+// breaks. Until the first chunk arrives, failures fall back to the next provider and are
+// reported as a regular JSON error. Closing the connection cancels the provider request.
+func (h *PortalHandler) StreamAI(c *gin.Context) {
+	req, call, ok := h.prepareAIRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), aiStreamTimeout)
+	defer cancel()
+
+	started := false
+	_, err := h.aiUC.Stream(ctx, call, req, func(text string) error {
+		if !started {
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+			c.Header("X-Accel-Buffering", "no") // keep proxies from buffering the stream
+			c.Status(http.StatusOK)
+			started = true
+		}
+		c.SSEvent("message", gin.H{"text": text})
+		c.Writer.Flush()
+		return nil
+	})
 
-	httpReq, err := http.NewRequestWithContext(c.Request.Context(), "POST", apiURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		response.InternalError(c, "Failed to create request")
+	if c.Request.Context().Err() != nil {
+		// Client went away; nothing left to write to
 		return
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-goog-api-key", h.cfg.GeminiAPIKey)
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		response.SafeInternalError(c, "Failed to call AI service", err)
+	if !started {
+		h.aiError(c, err)
 		return
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		response.InternalError(c, "Failed to read response")
-		return
+		log.Printf("[ERROR] AI stream interrupted: %v", err)
+		c.SSEvent("error", gin.H{"error": "AI service stream interrupted"})
+	} else {
+		c.SSEvent("done", gin.H{})
 	}
+	c.Writer.Flush()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("[ERROR] Gemini API error (status %d): %s", resp.StatusCode, string(body))
-		response.InternalError(c, "AI service temporarily unavailable")
-		return
+// prepareAIRequest validates the AI request and builds the provider request. It writes the
+// error response and returns false when the request cannot be sent.
+func (h *PortalHandler) prepareAIRequest(c *gin.Context) (ai.Request, assistant.Call, bool) {
+	var req AIProxyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return ai.Request{}, assistant.Call{}, false
 	}
 
-	// Parse Gemini response
-	var geminiResp map[string]interface{}
-	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		response.InternalError(c, "Failed to parse Gemini response")
-		return
+	// Limit input length to prevent payload amplification
+	if len(req.Message) > 5000 {
+		response.BadRequest(c, "Message too long. Maximum 5000 characters.")
+		return ai.Request{}, assistant.Call{}, false
+	}
+	if len(req.Context) > 10000 {
+		response.BadRequest(c, "Context too long. Maximum 10000 characters.")
+		return ai.Request{}, assistant.Call{}, false
 	}
 
-	// Extract text from response
-	text := ""
-	if candidates, ok := geminiResp["candidates"].([]interface{}); ok && len(candidates) > 0 {
-		if candidate, ok := candidates[0].(map[string]interface{}); ok {
-			if content, ok := candidate["content"].(map[string]interface{}); ok {
-				if parts, ok := content["parts"].([]interface{}); ok && len(parts) > 0 {
-					if part, ok := parts[0].(map[string]interface{}); ok {
-						if t, ok := part["text"].(string); ok {
-							text = t
-						}
-					}
-				}
-			}
-		}
+	prompt := req.Message
+	if req.Context != "" {
+		prompt = fmt.Sprintf("Contexto: %s\n\nPergunta: %s", req.Context, req.Message)
 	}
 
-	if text == "" {
-		response.InternalError(c, "Empty response from Gemini")
+	userID, _ := middleware.GetUserID(c)
+	return ai.Request{
+		System:      portalAISystemPrompt,
+		Prompt:      prompt,
+		Temperature: 0.7,
+		MaxTokens:   ai.DefaultMaxTokens,
+	}, assistant.Call{Feature: "portal_assistant", UserID: userID}, true
+}
+
+// aiError writes the response for a request no provider could answer
+func (h *PortalHandler) aiError(c *gin.Context, err error) {
+	if errors.Is(err, assistant.ErrUnavailable) {
+		response.ErrorCode(c, apperror.CodeAIUnavailable, "AI service not available")
 		return
 	}
-
-	response.Success(c, AIProxyResponse{
-		Success:  true,
-		Response: text,
-	})
+	response.SafeInternalError(c, "AI service temporarily unavailable", err)
 }
 
 // UploadEvidence handles POST /api/v1/portal/evidence - Upload de arquivos de evidência para auditorias
+// Multipart form: file, description, tags (repeated or comma separated), captured_at,
+// entity_type (audit, inspection or task), entity_id
 func (h *PortalHandler) UploadEvidence(c *gin.Context) {
 	if !h.checkStorage(c) {
 		return
 	}
 	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
 
 	// Parse multipart form
 	file, header, err := c.Request.FormFile("file")
@@ -380,51 +386,78 @@ func (h *PortalHandler) UploadEvidence(c *gin.Context) {
 	}
 	defer file.Close()
 
-	// Validate file type
+	// Inspection photos and task attachments have policies of their own
 	contentType := storage.GetContentTypeFromExtension(header.Filename)
-	if !storage.IsAllowedEvidenceType(contentType) {
-		response.BadRequest(c, "File type not allowed. Allowed: jpg, jpeg, png, gif, pdf, doc, docx")
+	if !checkUpload(c, h.uploads.Get(evidenceUploadContext(c.PostForm("entity_type"))), contentType, header.Size) {
 		return
 	}
 
-	// Validate file size (max 10MB for evidence)
-	if header.Size > 10*1024*1024 {
-		response.BadRequest(c, "File too large. Maximum size is 10MB")
-		return
+	req := &entity.UploadEvidenceRequest{
+		FileName:    header.Filename,
+		ContentType: contentType,
+		Size:        header.Size,
+		Description: optionalForm(c, "description"),
+		Tags:        c.PostFormArray("tags"),
+		CapturedAt:  optionalForm(c, "captured_at"),
+		EntityType:  optionalForm(c, "entity_type"),
+		EntityID:    optionalForm(c, "entity_id"),
 	}
 
-	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	filename := fmt.Sprintf("ev_%d_%s%s", time.Now().Unix(), uuid.New().String()[:8], ext)
-
-	// Upload to MinIO
-	result, err := h.storage.UploadFile(ctx, h.cfg.MinioBucketEvidence, filename, file, header.Size, contentType)
+	ev, err := h.evidenceUC.Upload(ctx, req, file, userID)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to upload evidence", err)
+		h.handleEvidenceError(c, err, "Failed to upload evidence")
 		return
 	}
 
-	response.Created(c, gin.H{
-		"success":  true,
-		"url":      result.URL,
-		"filename": result.Filename,
-	})
+	response.Created(c, ev)
 }
 
 // ListEvidence handles GET /api/v1/portal/evidence
+// Query params: entity_type, entity_id, tag (repeated; files must have every tag), q, page,
+// per_page, plus filter[...] and sort
 func (h *PortalHandler) ListEvidence(c *gin.Context) {
-	if !h.checkStorage(c) {
+	ctx := c.Request.Context()
+
+	filter := &entity.EvidenceFilter{
+		Tags:    c.QueryArray("tag"),
+		Page:    1,
+		PerPage: 50,
+	}
+	if entityType := c.Query("entity_type"); entityType != "" {
+		filter.EntityType = &entityType
+	}
+	if entityID := c.Query("entity_id"); entityID != "" {
+		filter.EntityID = &entityID
+	}
+	if search := strings.TrimSpace(c.Query("q")); search != "" {
+		filter.Search = &search
+	}
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+		filter.Page = p
+	}
+	if pp, err := strconv.Atoi(c.Query("per_page")); err == nil && pp > 0 {
+		if pp > 200 {
+			pp = 200
+		}
+		filter.PerPage = pp
+	}
+	q, ok := parseListQuery(c)
+	if !ok {
 		return
 	}
-	ctx := c.Request.Context()
+	filter.Query = q
 
-	files, err := h.storage.ListFiles(ctx, h.cfg.MinioBucketEvidence, "")
+	result, err := h.evidenceUC.List(ctx, filter)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to list evidence files", err)
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		respondListError(c, err, "Failed to list evidence files")
 		return
 	}
 
-	response.Success(c, files)
+	response.Success(c, result)
 }
 
 // DeleteEvidence handles DELETE /api/v1/portal/evidence/:filename
@@ -446,9 +479,7 @@ func (h *PortalHandler) DeleteEvidence(c *gin.Context) {
 		return
 	}
 
-	// Delete from MinIO
-	err := h.storage.DeleteFile(ctx, h.cfg.MinioBucketEvidence, filename)
-	if err != nil {
+	if err := h.evidenceUC.Delete(ctx, filename); err != nil {
 		response.SafeInternalError(c, "Failed to delete evidence", err)
 		return
 	}
@@ -458,3 +489,25 @@ func (h *PortalHandler) DeleteEvidence(c *gin.Context) {
 		"message": "Evidence file removed",
 	})
 }
+
+// evidenceUploadContext returns the upload context of evidence linked to an entity type
+func evidenceUploadContext(entityType string) string {
+	switch entityType {
+	case entity.EvidenceEntityInspection:
+		return storage.UploadInspectionPhoto
+	case entity.EvidenceEntityTask:
+		return storage.UploadTaskAttachment
+	}
+	return storage.UploadEvidence
+}
+
+func (h *PortalHandler) handleEvidenceError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/purchaseorder"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// PurchaseOrderHandler handles purchase order HTTP requests
+type PurchaseOrderHandler struct {
+	usecase purchaseorder.UseCase
+}
+
+// NewPurchaseOrderHandler creates a new purchase order handler
+func NewPurchaseOrderHandler(uc purchaseorder.UseCase) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{usecase: uc}
+}
+
+// ListPurchaseOrders handles GET /api/v1/purchase-orders
+// Query params: status, supplier_id, contrato_id
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := entity.PurchaseOrderFilters{
+		Status:     c.Query("status"),
+		SupplierID: c.Query("supplier_id"),
+		ContratoID: c.Query("contrato_id"),
+	}
+
+	orders, err := h.usecase.ListPurchaseOrders(ctx, filters)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch purchase orders", err)
+		return
+	}
+
+	response.Success(c, orders)
+}
+
+// GetPurchaseOrder handles GET /api/v1/purchase-orders/:id
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	order, err := h.usecase.GetPurchaseOrder(ctx, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch purchase order")
+		return
+	}
+
+	response.Success(c, order)
+}
+
+// CreatePurchaseOrder handles POST /api/v1/purchase-orders
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.CreatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	order, err := h.usecase.CreatePurchaseOrder(ctx, &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create purchase order")
+		return
+	}
+
+	response.Created(c, order)
+}
+
+// UpdatePurchaseOrder handles PUT /api/v1/purchase-orders/:id
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req entity.UpdatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	order, err := h.usecase.UpdatePurchaseOrder(ctx, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update purchase order")
+		return
+	}
+
+	response.Success(c, order)
+}
+
+// ApprovePurchaseOrder handles POST /api/v1/purchase-orders/:id/approve
+func (h *PurchaseOrderHandler) ApprovePurchaseOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+	order, err := h.usecase.ApprovePurchaseOrder(ctx, id, userID, role)
+	if err != nil {
+		if strings.Contains(err.Error(), "approval limit") {
+			response.Forbidden(c, err.Error())
+			return
+		}
+		h.handleError(c, err, "Failed to approve purchase order")
+		return
+	}
+
+	response.Success(c, order)
+}
+
+// DeliverPurchaseOrder handles POST /api/v1/purchase-orders/:id/deliver
+func (h *PurchaseOrderHandler) DeliverPurchaseOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	order, err := h.usecase.DeliverPurchaseOrder(ctx, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to mark purchase order as delivered")
+		return
+	}
+
+	response.Success(c, order)
+}
+
+// InvoicePurchaseOrder handles POST /api/v1/purchase-orders/:id/invoice
+func (h *PurchaseOrderHandler) InvoicePurchaseOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req entity.InvoicePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	order, err := h.usecase.InvoicePurchaseOrder(ctx, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to invoice purchase order")
+		return
+	}
+
+	response.Success(c, order)
+}
+
+// CancelPurchaseOrder handles POST /api/v1/purchase-orders/:id/cancel
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req entity.CancelPurchaseOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	order, err := h.usecase.CancelPurchaseOrder(ctx, id, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to cancel purchase order")
+		return
+	}
+
+	response.Success(c, order)
+}
+
+// ExportPDF handles GET /api/v1/purchase-orders/:id/pdf
+func (h *PurchaseOrderHandler) ExportPDF(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	order, content, err := h.usecase.ExportPDF(ctx, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to export purchase order")
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+order.Number+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+func (h *PurchaseOrderHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"),
+		strings.HasPrefix(err.Error(), "only draft"),
+		strings.HasSuffix(err.Error(), "is inactive"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.FromError(c, message, err)
+	}
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/usecase/revenue"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -8,12 +11,13 @@ import (
 
 // RevenueHandler handles revenue split-related HTTP requests
 type RevenueHandler struct {
-	usecase revenue.UseCase
+	usecase    revenue.UseCase
+	splitRules revenue.SplitRuleUseCase
 }
 
 // NewRevenueHandler creates a new revenue handler
-func NewRevenueHandler(uc revenue.UseCase) *RevenueHandler {
-	return &RevenueHandler{usecase: uc}
+func NewRevenueHandler(uc revenue.UseCase, splitRules revenue.SplitRuleUseCase) *RevenueHandler {
+	return &RevenueHandler{usecase: uc, splitRules: splitRules}
 }
 
 // ListRevenueSplits handles GET /api/v1/revenue-splits
@@ -111,7 +115,7 @@ func (h *RevenueHandler) UpdateStatus(c *gin.Context) {
 		Status string `json:"status" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -132,3 +136,136 @@ func (h *RevenueHandler) UpdateStatus(c *gin.Context) {
 		"message": "Revenue split status updated successfully",
 	})
 }
+
+// GetSplitRules handles GET /api/v1/revenue-splits/rules
+func (h *RevenueHandler) GetSplitRules(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	rules, err := h.splitRules.GetRules(ctx)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch split configuration", err)
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// SaveSplitRules handles PUT /api/v1/revenue-splits/rules
+func (h *RevenueHandler) SaveSplitRules(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.SaveSplitRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rules, err := h.splitRules.SaveRules(ctx, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to save split configuration")
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// PreviewSplit handles POST /api/v1/revenue-splits/rules/preview
+func (h *RevenueHandler) PreviewSplit(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req revenue.PreviewSplitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	parties, err := h.splitRules.Preview(ctx, &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to preview revenue split")
+		return
+	}
+
+	response.Success(c, parties)
+}
+
+// ListSplitOverrides handles GET /api/v1/revenue-splits/rules/overrides
+func (h *RevenueHandler) ListSplitOverrides(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	overrides, err := h.splitRules.ListOverrides(ctx)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch split overrides", err)
+		return
+	}
+
+	response.Success(c, overrides)
+}
+
+// GetSplitOverride handles GET /api/v1/revenue-splits/rules/overrides/:scope/:id
+func (h *RevenueHandler) GetSplitOverride(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	override, err := h.splitRules.GetOverride(ctx, c.Param("scope"), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch split override")
+		return
+	}
+
+	response.Success(c, override)
+}
+
+// SaveSplitOverride handles PUT /api/v1/revenue-splits/rules/overrides/:scope/:id
+func (h *RevenueHandler) SaveSplitOverride(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.SaveSplitRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	override, err := h.splitRules.SaveOverride(ctx, c.Param("scope"), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to save split override")
+		return
+	}
+
+	response.Success(c, override)
+}
+
+// DeleteSplitOverride handles DELETE /api/v1/revenue-splits/rules/overrides/:scope/:id
+func (h *RevenueHandler) DeleteSplitOverride(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.splitRules.DeleteOverride(ctx, c.Param("scope"), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to delete split override")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Split override deleted", nil)
+}
+
+// GetPartyLedger handles GET /api/v1/revenue-splits/ledger/:party_type
+// Query parameters: party_id (required for instructor and affiliate)
+func (h *RevenueHandler) GetPartyLedger(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	ledger, err := h.usecase.GetPartyLedger(ctx, c.Param("party_type"), c.Query("party_id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch party ledger")
+		return
+	}
+
+	response.Success(c, ledger)
+}
+
+func (h *RevenueHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/search"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// searchResources maps each search type to the resource whose read permission it needs.
+// Suppliers are not in the permission matrix and follow the contracts they serve.
+var searchResources = map[string]string{
+	entity.SearchTypeContrato: entity.ResourceContratos,
+	entity.SearchTypeSupplier: entity.ResourceContratos,
+	entity.SearchTypeTask:     entity.ResourceTasks,
+	entity.SearchTypeGestor:   entity.ResourceGestores,
+}
+
+// SearchHandler handles global search HTTP requests
+type SearchHandler struct {
+	usecase search.UseCase
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(uc search.UseCase) *SearchHandler {
+	return &SearchHandler{usecase: uc}
+}
+
+// Search handles GET /api/v1/search
+// Query params: q, type (contrato, supplier, task or gestor; repeated or comma separated),
+// limit (results per type). Only the types the user may read are searched.
+func (h *SearchHandler) Search(c *gin.Context) {
+	role, _ := middleware.GetUserRole(c)
+	readable := func(t string) bool {
+		resource, ok := searchResources[t]
+		return !ok || entity.RolePermission(entity.UserRole(role), resource, entity.ActionRead) != entity.ScopeNone
+	}
+
+	filter := &entity.SearchFilter{Terms: c.Query("q")}
+	if types := queryList(c, "type"); len(types) > 0 {
+		for _, t := range types {
+			if !readable(t) {
+				response.Forbidden(c, "You don't have permission to search "+t+" records")
+				return
+			}
+		}
+		filter.Types = types
+	} else {
+		for _, t := range entity.SearchTypes {
+			if readable(t) {
+				filter.Types = append(filter.Types, t)
+			}
+		}
+		if len(filter.Types) == 0 {
+			response.Forbidden(c, "You don't have permission to perform this action")
+			return
+		}
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		filter.Limit = limit
+	}
+
+	result, err := h.usecase.Search(c.Request.Context(), filter)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to search", err)
+		return
+	}
+
+	response.Success(c, result)
+}
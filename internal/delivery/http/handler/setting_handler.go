@@ -2,7 +2,10 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/usecase/setting"
 	"github.com/gin-gonic/gin"
@@ -95,14 +98,12 @@ func (h *SettingHandler) UpdateSetting(c *gin.Context) {
 
 	var req entity.UpdateSettingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 
-	if err := h.usecase.UpdateSetting(c.Request.Context(), key, req.Value); err != nil {
+	userID, _ := middleware.GetUserID(c)
+	if err := h.usecase.UpdateSetting(c.Request.Context(), key, req.Value, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -121,10 +122,7 @@ func (h *SettingHandler) UpdateSetting(c *gin.Context) {
 func (h *SettingHandler) BulkUpdateSettings(c *gin.Context) {
 	var req entity.BulkUpdateSettingsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 
@@ -136,7 +134,8 @@ func (h *SettingHandler) BulkUpdateSettings(c *gin.Context) {
 		return
 	}
 
-	if err := h.usecase.BulkUpdateSettings(c.Request.Context(), req.Settings); err != nil {
+	userID, _ := middleware.GetUserID(c)
+	if err := h.usecase.BulkUpdateSettings(c.Request.Context(), req.Settings, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"error":   err.Error(),
@@ -151,6 +150,148 @@ func (h *SettingHandler) BulkUpdateSettings(c *gin.Context) {
 	})
 }
 
+// GetSettingHistory returns the recorded changes of a setting, newest first
+// GET /api/v1/settings/:key/history
+// Query params: limit (default 50, max 200)
+func (h *SettingHandler) GetSettingHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	changes, err := h.usecase.GetSettingHistory(c.Request.Context(), c.Param("key"), limit)
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    changes,
+	})
+}
+
+// RollbackSetting restores the value a setting had before a recorded change
+// POST /api/v1/settings/:key/rollback
+func (h *SettingHandler) RollbackSetting(c *gin.Context) {
+	var req entity.RollbackSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	setting, err := h.usecase.RollbackSetting(c.Request.Context(), c.Param("key"), req.ChangeID, userID)
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Setting rolled back successfully",
+		"data":    setting,
+	})
+}
+
+// ResolveSetting returns the value of a setting for a contract or organization and the scope
+// it came from
+// GET /api/v1/settings/:key/resolve
+// Query params: contract_id, organization_id
+func (h *SettingHandler) ResolveSetting(c *gin.Context) {
+	resolved, err := h.usecase.ResolveSetting(c.Request.Context(), c.Param("key"), c.Query("contract_id"), c.Query("organization_id"))
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    resolved,
+	})
+}
+
+// ListOverrides returns the organization and contract overrides of a setting
+// GET /api/v1/settings/:key/overrides
+func (h *SettingHandler) ListOverrides(c *gin.Context) {
+	overrides, err := h.usecase.ListOverrides(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    overrides,
+	})
+}
+
+// SetOverride sets the value of a setting for an organization or contract
+// PUT /api/v1/settings/:key/overrides/:scope/:scope_id
+func (h *SettingHandler) SetOverride(c *gin.Context) {
+	var req entity.SetSettingOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	override, err := h.usecase.SetOverride(c.Request.Context(), c.Param("key"),
+		entity.SettingScope(c.Param("scope")), c.Param("scope_id"), req.Value, userID)
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Setting override saved successfully",
+		"data":    override,
+	})
+}
+
+// DeleteOverride removes the value of a setting for an organization or contract
+// DELETE /api/v1/settings/:key/overrides/:scope/:scope_id
+func (h *SettingHandler) DeleteOverride(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	err := h.usecase.DeleteOverride(c.Request.Context(), c.Param("key"),
+		entity.SettingScope(c.Param("scope")), c.Param("scope_id"), userID)
+	if err != nil {
+		c.JSON(settingErrorStatus(err), gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Setting override removed successfully",
+	})
+}
+
+func settingErrorStatus(err error) int {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		return http.StatusNotFound
+	case strings.HasPrefix(err.Error(), "invalid scope"):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 // GetCategories returns all available setting categories
 // GET /api/v1/settings/categories
 func (h *SettingHandler) GetCategories(c *gin.Context) {
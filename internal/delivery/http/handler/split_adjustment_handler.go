@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/splitadjustment"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// SplitAdjustmentHandler handles revenue split adjustment and dispute HTTP requests
+type SplitAdjustmentHandler struct {
+	usecase splitadjustment.UseCase
+}
+
+// NewSplitAdjustmentHandler creates a new split adjustment handler
+func NewSplitAdjustmentHandler(uc splitadjustment.UseCase) *SplitAdjustmentHandler {
+	return &SplitAdjustmentHandler{usecase: uc}
+}
+
+// ListAdjustments handles GET /api/v1/revenue-splits/adjustments
+// Query params: split_id, status
+func (h *SplitAdjustmentHandler) ListAdjustments(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := entity.SplitAdjustmentFilters{
+		SplitID: c.Query("split_id"),
+		Status:  c.Query("status"),
+	}
+
+	adjustments, err := h.usecase.ListAdjustments(ctx, filters)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch split adjustments")
+		return
+	}
+
+	response.Success(c, adjustments)
+}
+
+// CreateAdjustment handles POST /api/v1/revenue-splits/:id/adjustments
+func (h *SplitAdjustmentHandler) CreateAdjustment(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.CreateSplitAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	adjustment, err := h.usecase.CreateAdjustment(ctx, c.Param("id"), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create split adjustment")
+		return
+	}
+
+	response.Created(c, adjustment)
+}
+
+// ApproveAdjustment handles POST /api/v1/revenue-splits/adjustments/:id/approve
+func (h *SplitAdjustmentHandler) ApproveAdjustment(c *gin.Context) {
+	h.decide(c, h.usecase.ApproveAdjustment, "Failed to approve split adjustment")
+}
+
+// RejectAdjustment handles POST /api/v1/revenue-splits/adjustments/:id/reject
+func (h *SplitAdjustmentHandler) RejectAdjustment(c *gin.Context) {
+	h.decide(c, h.usecase.RejectAdjustment, "Failed to reject split adjustment")
+}
+
+type decideAdjustmentFunc func(ctx context.Context, id string, req *entity.DecideSplitAdjustmentRequest, userID string) (*entity.SplitAdjustment, error)
+
+func (h *SplitAdjustmentHandler) decide(c *gin.Context, fn decideAdjustmentFunc, message string) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.DecideSplitAdjustmentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	adjustment, err := fn(ctx, c.Param("id"), &req, userID)
+	if err != nil {
+		h.handleError(c, err, message)
+		return
+	}
+
+	response.Success(c, adjustment)
+}
+
+// ListDisputes handles GET /api/v1/revenue-splits/disputes
+// Query params: split_id, instructor_id, status. Instructors only see their own disputes.
+func (h *SplitAdjustmentHandler) ListDisputes(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	filters := entity.SplitDisputeFilters{
+		SplitID:      c.Query("split_id"),
+		InstructorID: c.Query("instructor_id"),
+		Status:       c.Query("status"),
+	}
+
+	disputes, err := h.usecase.ListDisputes(ctx, filters, userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch split disputes")
+		return
+	}
+
+	response.Success(c, disputes)
+}
+
+// GetDispute handles GET /api/v1/revenue-splits/disputes/:id
+func (h *SplitAdjustmentHandler) GetDispute(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	dispute, err := h.usecase.GetDispute(ctx, c.Param("id"), userID, role)
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch split dispute")
+		return
+	}
+
+	response.Success(c, dispute)
+}
+
+// OpenDispute handles POST /api/v1/revenue-splits/:id/disputes
+func (h *SplitAdjustmentHandler) OpenDispute(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.OpenSplitDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	dispute, err := h.usecase.OpenDispute(ctx, c.Param("id"), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to open split dispute")
+		return
+	}
+
+	response.Created(c, dispute)
+}
+
+// ReviewDispute handles POST /api/v1/revenue-splits/disputes/:id/review
+func (h *SplitAdjustmentHandler) ReviewDispute(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	dispute, err := h.usecase.ReviewDispute(ctx, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to review split dispute")
+		return
+	}
+
+	response.Success(c, dispute)
+}
+
+// ResolveDispute handles POST /api/v1/revenue-splits/disputes/:id/resolve
+func (h *SplitAdjustmentHandler) ResolveDispute(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.ResolveSplitDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	dispute, err := h.usecase.ResolveDispute(ctx, c.Param("id"), &req, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to resolve split dispute")
+		return
+	}
+
+	response.Success(c, dispute)
+}
+
+func (h *SplitAdjustmentHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, splitadjustment.ErrAccessDenied):
+		response.Forbidden(c, err.Error())
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
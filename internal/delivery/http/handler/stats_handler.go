@@ -2,9 +2,12 @@ package handler
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/cache"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
@@ -17,15 +20,21 @@ type StatsHandler struct {
 	auditRepo     repository.AuditRepository
 	contratoRepo  repository.ContratoRepository
 	gestorRepo    repository.GestorRepository
+	cache         cache.Cache
+	cacheTTL      time.Duration
 }
 
-// NewStatsHandler creates a new stats handler
+// NewStatsHandler creates a new stats handler. The aggregate queries run on db, which may
+// be a read replica so dashboards do not load the primary, and their results are cached for
+// cacheTTL; ?refresh=true recomputes them.
 func NewStatsHandler(
 	db *sqlx.DB,
 	matriculaRepo repository.MatriculaRepository,
 	auditRepo repository.AuditRepository,
 	contratoRepo repository.ContratoRepository,
 	gestorRepo repository.GestorRepository,
+	c cache.Cache,
+	cacheTTL time.Duration,
 ) *StatsHandler {
 	return &StatsHandler{
 		db:            db,
@@ -33,14 +42,14 @@ func NewStatsHandler(
 		auditRepo:     auditRepo,
 		contratoRepo:  contratoRepo,
 		gestorRepo:    gestorRepo,
+		cache:         c,
+		cacheTTL:      cacheTTL,
 	}
 }
 
 // GetOverview handles GET /api/v1/stats/overview
 func (h *StatsHandler) GetOverview(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	overview, err := h.buildOverview(ctx)
+	overview, err := fetchStats(c, h, "stats:overview", h.buildOverview)
 	if err != nil {
 		response.SafeInternalError(c, "Failed to fetch system overview", err)
 		return
@@ -51,9 +60,7 @@ func (h *StatsHandler) GetOverview(c *gin.Context) {
 
 // GetEnrollmentStats handles GET /api/v1/stats/enrollments
 func (h *StatsHandler) GetEnrollmentStats(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	stats, err := h.buildEnrollmentStats(ctx)
+	stats, err := fetchStats(c, h, "stats:enrollments", h.buildEnrollmentStats)
 	if err != nil {
 		response.SafeInternalError(c, "Failed to fetch enrollment statistics", err)
 		return
@@ -64,9 +71,7 @@ func (h *StatsHandler) GetEnrollmentStats(c *gin.Context) {
 
 // GetPaymentStats handles GET /api/v1/stats/payments
 func (h *StatsHandler) GetPaymentStats(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	stats, err := h.buildPaymentStats(ctx)
+	stats, err := fetchStats(c, h, "stats:payments", h.buildPaymentStats)
 	if err != nil {
 		response.SafeInternalError(c, "Failed to fetch payment statistics", err)
 		return
@@ -77,9 +82,7 @@ func (h *StatsHandler) GetPaymentStats(c *gin.Context) {
 
 // GetAuditStats handles GET /api/v1/stats/audits
 func (h *StatsHandler) GetAuditStats(c *gin.Context) {
-	ctx := c.Request.Context()
-
-	stats, err := h.buildAuditStats(ctx)
+	stats, err := fetchStats(c, h, "stats:audits", h.buildAuditStats)
 	if err != nil {
 		response.SafeInternalError(c, "Failed to fetch audit statistics", err)
 		return
@@ -88,6 +91,24 @@ func (h *StatsHandler) GetAuditStats(c *gin.Context) {
 	response.Success(c, stats)
 }
 
+// fetchStats serves the stats under key from the cache, building them on a miss. With
+// ?refresh=true they are built again and replace the cached ones.
+func fetchStats[T any](c *gin.Context, h *StatsHandler, key string, build func(context.Context) (T, error)) (T, error) {
+	ctx := c.Request.Context()
+	if c.Query("refresh") != "true" || h.cache == nil || h.cacheTTL <= 0 {
+		return cache.Fetch(ctx, h.cache, key, h.cacheTTL, build)
+	}
+
+	stats, err := build(ctx)
+	if err != nil {
+		return stats, err
+	}
+	if err := h.cache.Set(ctx, key, stats, h.cacheTTL); err != nil {
+		log.Printf("[CACHE] Failed to store %s: %v", key, err)
+	}
+	return stats, nil
+}
+
 // buildOverview builds the system overview
 func (h *StatsHandler) buildOverview(ctx context.Context) (*entity.SystemOverview, error) {
 	overview := &entity.SystemOverview{}
@@ -145,7 +166,7 @@ func (h *StatsHandler) buildOverview(ctx context.Context) (*entity.SystemOvervie
 
 	// Get contract count
 	var contractCount int
-	contractQuery := `SELECT COUNT(*) FROM contratos WHERE ativo = 1`
+	contractQuery := `SELECT COUNT(*) FROM contratos WHERE ativo = 1 AND deleted_at IS NULL`
 	if err := h.db.GetContext(ctx, &contractCount, contractQuery); err != nil {
 		return nil, err
 	}
@@ -153,7 +174,7 @@ func (h *StatsHandler) buildOverview(ctx context.Context) (*entity.SystemOvervie
 
 	// Get gestor count
 	var gestorCount int
-	gestorQuery := `SELECT COUNT(*) FROM gestores WHERE ativo = 1`
+	gestorQuery := `SELECT COUNT(*) FROM gestores WHERE ativo = 1 AND deleted_at IS NULL`
 	if err := h.db.GetContext(ctx, &gestorCount, gestorQuery); err != nil {
 		return nil, err
 	}
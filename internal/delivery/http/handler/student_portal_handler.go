@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/condotrack/api/internal/usecase/studentportal"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// StudentPortalHandler handles the self-service requests of the logged-in student under
+// /api/v1/me. The student is always the subject of the token, never an ID in the path.
+type StudentPortalHandler struct {
+	usecase       studentportal.UseCase
+	notifications notification.UseCase
+}
+
+// NewStudentPortalHandler creates a new student portal handler
+func NewStudentPortalHandler(uc studentportal.UseCase, notifications notification.UseCase) *StudentPortalHandler {
+	return &StudentPortalHandler{usecase: uc, notifications: notifications}
+}
+
+// ListEnrollments handles GET /api/v1/me/enrollments
+func (h *StudentPortalHandler) ListEnrollments(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	enrollments, err := h.usecase.ListEnrollments(ctx, userID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch enrollments", err)
+		return
+	}
+
+	response.Success(c, enrollments)
+}
+
+// ListPayments handles GET /api/v1/me/payments
+// Query params: page, per_page (max 100)
+func (h *StudentPortalHandler) ListPayments(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	page, perPage := 1, 20
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+		page = p
+	}
+	if pp, err := strconv.Atoi(c.Query("per_page")); err == nil && pp > 0 && pp <= 100 {
+		perPage = pp
+	}
+
+	payments, total, err := h.usecase.ListPayments(ctx, userID, page, perPage)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch payments", err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"payments": payments,
+		"total":    total,
+		"page":     page,
+		"per_page": perPage,
+	})
+}
+
+// GetPaymentCharge handles GET /api/v1/me/payments/:id/charge
+func (h *StudentPortalHandler) GetPaymentCharge(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	charge, err := h.usecase.GetPaymentCharge(ctx, userID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, studentportal.ErrPaymentNotFound) {
+			response.NotFound(c, "Payment not found")
+			return
+		}
+		response.FromError(c, "Failed to fetch payment charge", err)
+		return
+	}
+
+	response.Success(c, charge)
+}
+
+// ListCertificates handles GET /api/v1/me/certificates
+func (h *StudentPortalHandler) ListCertificates(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	certs, err := h.usecase.ListCertificates(ctx, userID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch certificates", err)
+		return
+	}
+
+	response.Success(c, certs)
+}
+
+// RateCourse handles PUT /api/v1/me/enrollments/:id/rating
+// Rates the course of an active or completed enrollment from 1 to 5, replacing an earlier rating
+func (h *StudentPortalHandler) RateCourse(c *gin.Context) {
+	var req entity.RateCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	rating, err := h.usecase.RateCourse(ctx, userID, c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, studentportal.ErrEnrollmentNotFound) {
+			response.NotFound(c, "Enrollment not found")
+			return
+		}
+		response.FromError(c, "Failed to rate course", err)
+		return
+	}
+
+	response.Success(c, rating)
+}
+
+// GetNotificationPreferences handles GET /api/v1/me/notification-preferences
+func (h *StudentPortalHandler) GetNotificationPreferences(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	prefs, err := h.notifications.GetPreferences(ctx, userID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch notification preferences", err)
+		return
+	}
+
+	response.Success(c, prefs)
+}
+
+// UpdateNotificationPreferences handles PUT /api/v1/me/notification-preferences
+func (h *StudentPortalHandler) UpdateNotificationPreferences(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	prefs, err := h.notifications.UpdatePreferences(ctx, userID, &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to update notification preferences", err)
+		return
+	}
+
+	response.Success(c, prefs)
+}
+
+// GetNotificationChannels handles GET /api/v1/me/notification-channels
+func (h *StudentPortalHandler) GetNotificationChannels(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	prefs, err := h.notifications.GetChannelPreferences(ctx, userID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch notification channels", err)
+		return
+	}
+
+	response.Success(c, prefs)
+}
+
+// UpdateNotificationChannels handles PUT /api/v1/me/notification-channels
+func (h *StudentPortalHandler) UpdateNotificationChannels(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.UpdateNotificationChannelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	prefs, err := h.notifications.UpdateChannelPreferences(ctx, userID, &req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to update notification channels", err)
+		return
+	}
+
+	response.Success(c, prefs)
+}
+
+// GetPushPublicKey handles GET /api/v1/me/push-subscriptions/public-key
+func (h *StudentPortalHandler) GetPushPublicKey(c *gin.Context) {
+	key := h.notifications.PushPublicKey()
+	if key == "" {
+		response.NotFound(c, "Web push is not configured")
+		return
+	}
+
+	response.Success(c, gin.H{"public_key": key})
+}
+
+// SubscribePush handles POST /api/v1/me/push-subscriptions
+// Body: the PushSubscription JSON of the browser (endpoint, keys.p256dh, keys.auth)
+func (h *StudentPortalHandler) SubscribePush(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	var req entity.CreatePushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	sub, err := h.notifications.SubscribePush(ctx, userID, &req, c.Request.UserAgent())
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to save push subscription", err)
+		return
+	}
+
+	response.Created(c, sub)
+}
+
+// UnsubscribePush handles DELETE /api/v1/me/push-subscriptions/:id
+func (h *StudentPortalHandler) UnsubscribePush(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+
+	if err := h.notifications.UnsubscribePush(ctx, userID, c.Param("id")); err != nil {
+		if errors.Is(err, notification.ErrPushSubscriptionNotFound) {
+			response.NotFound(c, "Push subscription not found")
+			return
+		}
+		response.SafeInternalError(c, "Failed to delete push subscription", err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Push subscription deleted"})
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
+	"github.com/condotrack/api/internal/usecase/statement"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// StudentStatementHandler handles the financial statement of a student
+type StudentStatementHandler struct {
+	usecase statement.UseCase
+}
+
+// NewStudentStatementHandler creates a new student statement handler
+func NewStudentStatementHandler(uc statement.UseCase) *StudentStatementHandler {
+	return &StudentStatementHandler{usecase: uc}
+}
+
+// GetStatement handles GET /api/v1/students/:id/statement
+func (h *StudentStatementHandler) GetStatement(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserID(c)
+	role, _ := middleware.GetUserRole(c)
+
+	result, err := h.usecase.GetStatement(ctx, c.Param("id"), userID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, statement.ErrStudentNotFound):
+			response.NotFound(c, "Student not found")
+		case errors.Is(err, statement.ErrAccessDenied):
+			response.Forbidden(c, err.Error())
+		default:
+			response.SafeInternalError(c, "Failed to get student statement", err)
+		}
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"strconv"
 	"strings"
 
+	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/usecase/supplier"
 	"github.com/condotrack/api/pkg/response"
@@ -71,7 +73,7 @@ func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
 
 	var req entity.CreateSupplierRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -81,7 +83,7 @@ func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
 			response.BadRequest(c, err.Error())
 			return
 		}
-		response.SafeInternalError(c, "Failed to create supplier", err)
+		response.FromError(c, "Failed to create supplier", err)
 		return
 	}
 
@@ -95,7 +97,7 @@ func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
 
 	var req entity.UpdateSupplierRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -109,7 +111,7 @@ func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
 			response.BadRequest(c, err.Error())
 			return
 		}
-		response.SafeInternalError(c, "Failed to update supplier", err)
+		response.FromError(c, "Failed to update supplier", err)
 		return
 	}
 
@@ -133,3 +135,371 @@ func (h *SupplierHandler) DeleteSupplier(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "Supplier deleted successfully", nil)
 }
+
+// EvaluateSupplier handles POST /api/v1/suppliers/:id/evaluations
+func (h *SupplierHandler) EvaluateSupplier(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req entity.CreateSupplierEvaluationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	evaluation, err := h.usecase.EvaluateSupplier(ctx, id, &req, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "already evaluated") || strings.HasPrefix(err.Error(), "invalid period") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to evaluate supplier", err)
+		return
+	}
+
+	response.Created(c, evaluation)
+}
+
+// ListEvaluations handles GET /api/v1/suppliers/:id/evaluations
+func (h *SupplierHandler) ListEvaluations(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	evaluations, err := h.usecase.ListEvaluations(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch supplier evaluations", err)
+		return
+	}
+
+	response.Success(c, evaluations)
+}
+
+// GetRanking handles GET /api/v1/suppliers/ranking
+// Query params: category, contrato_id, from (YYYY-MM), to (YYYY-MM), min_evaluations, limit
+func (h *SupplierHandler) GetRanking(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filters := entity.SupplierRankingFilters{
+		Category:   c.Query("category"),
+		ContratoID: c.Query("contrato_id"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+	}
+	if v, err := strconv.Atoi(c.Query("min_evaluations")); err == nil && v > 0 {
+		filters.MinEvaluations = v
+	}
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 {
+		filters.Limit = v
+	}
+
+	ranking, err := h.usecase.GetRanking(ctx, filters)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid period") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch supplier ranking", err)
+		return
+	}
+
+	response.Success(c, ranking)
+}
+
+// LinkContract handles POST /api/v1/suppliers/:id/contracts
+func (h *SupplierHandler) LinkContract(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req entity.CreateSupplierContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	link, err := h.usecase.LinkContract(ctx, id, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "already linked") || strings.HasSuffix(err.Error(), "is required") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to link supplier to contract", err)
+		return
+	}
+
+	response.Created(c, link)
+}
+
+// ListSupplierContracts handles GET /api/v1/suppliers/:id/contracts
+func (h *SupplierHandler) ListSupplierContracts(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	links, err := h.usecase.ListSupplierContracts(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch supplier contracts", err)
+		return
+	}
+
+	response.Success(c, links)
+}
+
+// ListContractSuppliers handles GET /api/v1/contratos/:id/suppliers
+func (h *SupplierHandler) ListContractSuppliers(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	links, err := h.usecase.ListContractSuppliers(ctx, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch contract suppliers", err)
+		return
+	}
+
+	response.Success(c, links)
+}
+
+// UpdateContractLink handles PUT /api/v1/suppliers/:id/contracts/:linkId
+func (h *SupplierHandler) UpdateContractLink(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	linkID := c.Param("linkId")
+
+	var req entity.UpdateSupplierContractRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	link, err := h.usecase.UpdateContractLink(ctx, id, linkID, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "already linked") || strings.HasSuffix(err.Error(), "is required") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to update supplier contract", err)
+		return
+	}
+
+	response.Success(c, link)
+}
+
+// UnlinkContract handles DELETE /api/v1/suppliers/:id/contracts/:linkId
+func (h *SupplierHandler) UnlinkContract(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	linkID := c.Param("linkId")
+
+	if err := h.usecase.UnlinkContract(ctx, id, linkID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to unlink supplier contract", err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Supplier contract removed successfully", nil)
+}
+
+// RecordSpend handles POST /api/v1/suppliers/:id/contracts/:linkId/spend
+func (h *SupplierHandler) RecordSpend(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	linkID := c.Param("linkId")
+
+	var req entity.CreateSupplierSpendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	spend, err := h.usecase.RecordSpend(ctx, id, linkID, &req, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid period") || strings.HasSuffix(err.Error(), "is inactive") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to record supplier spend", err)
+		return
+	}
+
+	response.Created(c, spend)
+}
+
+// ListSpend handles GET /api/v1/suppliers/:id/contracts/:linkId/spend
+func (h *SupplierHandler) ListSpend(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	linkID := c.Param("linkId")
+
+	entries, err := h.usecase.ListSpend(ctx, id, linkID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch supplier spend", err)
+		return
+	}
+
+	response.Success(c, entries)
+}
+
+// ListDocuments handles GET /api/v1/suppliers/:id/documents
+// Query params: include_inactive
+func (h *SupplierHandler) ListDocuments(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	docs, err := h.usecase.ListDocuments(ctx, id, c.Query("include_inactive") == "true")
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to fetch supplier documents", err)
+		return
+	}
+
+	response.Success(c, docs)
+}
+
+// AddDocument handles POST /api/v1/suppliers/:id/documents
+func (h *SupplierHandler) AddDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req entity.CreateSupplierDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	doc, err := h.usecase.AddDocument(ctx, id, &req, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") || strings.HasSuffix(err.Error(), "is required") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to add supplier document", err)
+		return
+	}
+
+	response.Created(c, doc)
+}
+
+// UpdateDocument handles PUT /api/v1/suppliers/:id/documents/:documentId
+func (h *SupplierHandler) UpdateDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	documentID := c.Param("documentId")
+
+	var req entity.UpdateSupplierDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	doc, err := h.usecase.UpdateDocument(ctx, id, documentID, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "invalid") || strings.HasSuffix(err.Error(), "is required") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to update supplier document", err)
+		return
+	}
+
+	response.Success(c, doc)
+}
+
+// DeleteDocument handles DELETE /api/v1/suppliers/:id/documents/:documentId
+func (h *SupplierHandler) DeleteDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+	documentID := c.Param("documentId")
+
+	if err := h.usecase.DeleteDocument(ctx, id, documentID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to delete supplier document", err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "Supplier document removed successfully", nil)
+}
+
+// GetSpendByContract handles GET /api/v1/suppliers/reports/spend-by-contract
+// Query params: from (YYYY-MM), to (YYYY-MM), supplier_id, contrato_id
+func (h *SupplierHandler) GetSpendByContract(c *gin.Context) {
+	h.spendReport(c, entity.SpendGroupByContract)
+}
+
+// GetSpendBySupplier handles GET /api/v1/suppliers/reports/spend-by-supplier
+// Query params: from (YYYY-MM), to (YYYY-MM), supplier_id, contrato_id
+func (h *SupplierHandler) GetSpendBySupplier(c *gin.Context) {
+	h.spendReport(c, entity.SpendGroupBySupplier)
+}
+
+func (h *SupplierHandler) spendReport(c *gin.Context, groupBy string) {
+	ctx := c.Request.Context()
+
+	filters := entity.SpendReportFilters{
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		SupplierID: c.Query("supplier_id"),
+		ContratoID: c.Query("contrato_id"),
+	}
+
+	report, err := h.usecase.GetSpendReport(ctx, groupBy, filters)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid") {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.SafeInternalError(c, "Failed to build spend report", err)
+		return
+	}
+
+	response.Success(c, report)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/systemimage"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// SystemImageHandler handles the admin registry of portal system image slots
+type SystemImageHandler struct {
+	usecase systemimage.UseCase
+}
+
+// NewSystemImageHandler creates a new system image handler
+func NewSystemImageHandler(uc systemimage.UseCase) *SystemImageHandler {
+	return &SystemImageHandler{usecase: uc}
+}
+
+// ListImages handles GET /api/v1/portal/system-images
+func (h *SystemImageHandler) ListImages(c *gin.Context) {
+	images, err := h.usecase.ListImages(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch system images")
+		return
+	}
+
+	response.Success(c, images)
+}
+
+// GetImage handles GET /api/v1/portal/system-images/:id
+func (h *SystemImageHandler) GetImage(c *gin.Context) {
+	image, err := h.usecase.GetImage(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to fetch system image")
+		return
+	}
+
+	response.Success(c, image)
+}
+
+// CreateImage handles POST /api/v1/portal/system-images
+func (h *SystemImageHandler) CreateImage(c *gin.Context) {
+	var req entity.CreateSystemImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	image, err := h.usecase.CreateImage(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to create system image")
+		return
+	}
+
+	response.Created(c, image)
+}
+
+// UpdateImage handles PUT /api/v1/portal/system-images/:id
+func (h *SystemImageHandler) UpdateImage(c *gin.Context) {
+	var req entity.UpdateSystemImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	image, err := h.usecase.UpdateImage(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "Failed to update system image")
+		return
+	}
+
+	response.Success(c, image)
+}
+
+// DeleteImage handles DELETE /api/v1/portal/system-images/:id
+func (h *SystemImageHandler) DeleteImage(c *gin.Context) {
+	if err := h.usecase.DeleteImage(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "Failed to delete system image")
+		return
+	}
+
+	response.SuccessWithMessage(c, "System image deleted", nil)
+}
+
+func (h *SystemImageHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/taskescalation"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// TaskEscalationHandler handles overdue task escalation HTTP requests
+type TaskEscalationHandler struct {
+	usecase taskescalation.UseCase
+}
+
+// NewTaskEscalationHandler creates a new task escalation handler
+func NewTaskEscalationHandler(uc taskescalation.UseCase) *TaskEscalationHandler {
+	return &TaskEscalationHandler{usecase: uc}
+}
+
+// GetReport handles GET /api/v1/tasks/escalations
+// Query params: contract_id, from, to (YYYY-MM-DD, escalation date, inclusive)
+func (h *TaskEscalationHandler) GetReport(c *gin.Context) {
+	filters := entity.TaskEscalationReportFilters{ContractID: c.Query("contract_id")}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			response.BadRequest(c, "invalid from: use YYYY-MM-DD")
+			return
+		}
+		filters.From = &t
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			response.BadRequest(c, "invalid to: use YYYY-MM-DD")
+			return
+		}
+		end := t.AddDate(0, 0, 1)
+		filters.To = &end
+	}
+
+	report, err := h.usecase.Report(c.Request.Context(), filters)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch task escalation report", err)
+		return
+	}
+
+	response.Success(c, report)
+}
+
+// RunEscalations handles POST /api/v1/tasks/escalations/run
+func (h *TaskEscalationHandler) RunEscalations(c *gin.Context) {
+	result, err := h.usecase.RunEscalations(c.Request.Context(), time.Now())
+	if err != nil {
+		response.SafeInternalError(c, "Failed to run task escalations", err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -1,20 +1,27 @@
 package handler
 
 import (
+	"errors"
+	"strings"
+
+	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/assistant"
 	"github.com/condotrack/api/internal/usecase/task"
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 )
 
 // TaskHandler handles task-related HTTP requests
 type TaskHandler struct {
-	usecase task.UseCase
+	usecase      task.UseCase
+	suggestionUC task.SuggestionUseCase
 }
 
 // NewTaskHandler creates a new task handler
-func NewTaskHandler(uc task.UseCase) *TaskHandler {
-	return &TaskHandler{usecase: uc}
+func NewTaskHandler(uc task.UseCase, suggestionUC task.SuggestionUseCase) *TaskHandler {
+	return &TaskHandler{usecase: uc, suggestionUC: suggestionUC}
 }
 
 // ListTasks handles GET /api/v1/tasks
@@ -39,10 +46,15 @@ func (h *TaskHandler) ListTasks(c *gin.Context) {
 	if createdBy := c.Query("created_by"); createdBy != "" {
 		filter.CreatedBy = &createdBy
 	}
+	q, ok := parseListQuery(c)
+	if !ok {
+		return
+	}
+	filter.Query = q
 
 	tasks, err := h.usecase.ListTasks(ctx, filter)
 	if err != nil {
-		response.SafeInternalError(c, "Failed to fetch tasks", err)
+		respondListError(c, err, "Failed to fetch tasks")
 		return
 	}
 
@@ -74,7 +86,7 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 
 	var req entity.CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -95,6 +107,68 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	response.Created(c, task)
 }
 
+// CreateTasks handles POST /api/v1/tasks/bulk
+// Creates up to 50 tasks of a contract at once, e.g. the accepted AI suggestions. Either all
+// tasks are created or none.
+func (h *TaskHandler) CreateTasks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.BulkCreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	tasks, err := h.usecase.CreateTasks(ctx, &req)
+	if err != nil {
+		msg := err.Error()
+		if msg == "contract not found" || msg == "creator not found" ||
+			strings.HasPrefix(msg, "assignee not found") || strings.HasPrefix(msg, "invalid priority") {
+			response.BadRequest(c, msg)
+			return
+		}
+		response.SafeInternalError(c, "Failed to create tasks", err)
+		return
+	}
+
+	response.Created(c, tasks)
+}
+
+// SuggestFromAudit handles POST /api/v1/audits/:id/task-suggestions
+// Asks the AI for tasks fixing the audit non-conformities. Nothing is created: accepted
+// suggestions are sent to POST /api/v1/tasks/bulk.
+func (h *TaskHandler) SuggestFromAudit(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	suggestions, err := h.suggestionUC.SuggestFromAudit(c.Request.Context(), c.Param("id"), userID)
+	h.respondSuggestions(c, suggestions, err)
+}
+
+// SuggestFromInspection handles POST /api/v1/inspections/:id/task-suggestions
+// Asks the AI for tasks addressing the inspection findings, like SuggestFromAudit.
+func (h *TaskHandler) SuggestFromInspection(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	suggestions, err := h.suggestionUC.SuggestFromInspection(c.Request.Context(), c.Param("id"), userID)
+	h.respondSuggestions(c, suggestions, err)
+}
+
+func (h *TaskHandler) respondSuggestions(c *gin.Context, suggestions *entity.TaskSuggestions, err error) {
+	if err != nil {
+		switch {
+		case strings.HasSuffix(err.Error(), "not found"):
+			response.NotFound(c, err.Error())
+		case strings.HasPrefix(err.Error(), "invalid source"):
+			response.BadRequest(c, err.Error())
+		case errors.Is(err, assistant.ErrUnavailable):
+			response.ErrorCode(c, apperror.CodeAIUnavailable, "AI service not available")
+		default:
+			response.SafeInternalError(c, "Failed to suggest tasks", err)
+		}
+		return
+	}
+
+	response.Success(c, suggestions)
+}
+
 // UpdateTask handles PUT /api/v1/tasks/:id
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -102,7 +176,7 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 
 	var req entity.UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -134,7 +208,7 @@ func (h *TaskHandler) UpdateTaskStatus(c *gin.Context) {
 
 	var req entity.UpdateTaskStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -73,7 +73,7 @@ func (h *TeamHandler) CreateTeamMember(c *gin.Context) {
 
 	var req entity.CreateTeamMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -101,7 +101,7 @@ func (h *TeamHandler) UpdateTeamMember(c *gin.Context) {
 
 	var req entity.UpdateTeamMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, "Invalid request: "+err.Error())
+		respondBindError(c, err)
 		return
 	}
 
@@ -171,3 +171,157 @@ func (h *TeamHandler) GetContractsByUser(c *gin.Context) {
 
 	response.Success(c, members)
 }
+
+// ListShifts handles GET /api/v1/team/shifts
+// Query params: contract_id, user_id, is_active
+func (h *TeamHandler) ListShifts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filter := &entity.TeamShiftFilter{}
+
+	if contractID := c.Query("contract_id"); contractID != "" {
+		filter.ContractID = &contractID
+	}
+
+	if userID := c.Query("user_id"); userID != "" {
+		filter.UserID = &userID
+	}
+
+	if isActiveStr := c.Query("is_active"); isActiveStr != "" {
+		isActive := isActiveStr == "true" || isActiveStr == "1"
+		filter.IsActive = &isActive
+	}
+
+	shifts, err := h.usecase.ListShifts(ctx, filter)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch shifts", err)
+		return
+	}
+
+	response.Success(c, shifts)
+}
+
+// GetShiftByID handles GET /api/v1/team/shifts/:id
+func (h *TeamHandler) GetShiftByID(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	shift, err := h.usecase.GetShiftByID(ctx, id)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch shift", err)
+		return
+	}
+
+	if shift == nil {
+		response.NotFound(c, "Shift not found")
+		return
+	}
+
+	response.Success(c, shift)
+}
+
+// CreateShift handles POST /api/v1/team/shifts
+func (h *TeamHandler) CreateShift(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req entity.CreateTeamShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	shift, err := h.usecase.CreateShift(ctx, &req)
+	if err != nil {
+		h.handleShiftError(c, err, "Failed to create shift")
+		return
+	}
+
+	response.Created(c, shift)
+}
+
+// UpdateShift handles PUT /api/v1/team/shifts/:id
+func (h *TeamHandler) UpdateShift(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var req entity.UpdateTeamShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	shift, err := h.usecase.UpdateShift(ctx, id, &req)
+	if err != nil {
+		h.handleShiftError(c, err, "Failed to update shift")
+		return
+	}
+
+	response.Success(c, shift)
+}
+
+// DeleteShift handles DELETE /api/v1/team/shifts/:id
+func (h *TeamHandler) DeleteShift(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	if err := h.usecase.DeleteShift(ctx, id); err != nil {
+		h.handleShiftError(c, err, "Failed to delete shift")
+		return
+	}
+
+	response.SuccessWithMessage(c, "Shift removed successfully", nil)
+}
+
+// GetShiftConflicts handles GET /api/v1/team/contract/:id/conflicts
+// Query params: from, to (YYYY-MM-DD; defaults to the next 7 days)
+func (h *TeamHandler) GetShiftConflicts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	conflicts, err := h.usecase.GetShiftConflicts(ctx, c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleShiftError(c, err, "Failed to fetch shift conflicts")
+		return
+	}
+
+	response.Success(c, conflicts)
+}
+
+// GetCoverageReport handles GET /api/v1/team/contract/:id/coverage
+// Query params: from, to (YYYY-MM-DD; defaults to the next 7 days)
+func (h *TeamHandler) GetCoverageReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	report, err := h.usecase.GetCoverageReport(ctx, c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleShiftError(c, err, "Failed to build coverage report")
+		return
+	}
+
+	response.Success(c, report)
+}
+
+func (h *TeamHandler) handleShiftError(c *gin.Context, err error, message string) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		response.NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "invalid"),
+		strings.Contains(err.Error(), "overlaps"):
+		response.BadRequest(c, err.Error())
+	default:
+		response.SafeInternalError(c, message, err)
+	}
+}
+
+// GetWorkload handles GET /api/v1/team/workload
+// Query params: contract_id, from, to (YYYY-MM-DD; defaults to the next 7 days)
+func (h *TeamHandler) GetWorkload(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	workload, err := h.usecase.GetWorkload(ctx, c.Query("contract_id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.handleShiftError(c, err, "Failed to fetch team workload")
+		return
+	}
+
+	response.Success(c, workload)
+}
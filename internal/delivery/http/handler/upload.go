@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// checkUpload validates a file against the upload policy of its context. It responds 400
+// and returns false when the type is not allowed or the file is too large.
+func checkUpload(c *gin.Context, policy storage.UploadPolicy, contentType string, size int64) bool {
+	if !policy.Allows(contentType) {
+		response.BadRequest(c, "File type not allowed. Allowed: "+policy.AllowedExtensions())
+		return false
+	}
+	if size > policy.MaxSize {
+		response.BadRequest(c, "File too large. Maximum size is "+policy.MaxSizeLabel())
+		return false
+	}
+	return true
+}
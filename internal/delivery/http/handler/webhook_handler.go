@@ -2,17 +2,25 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
-	"math"
+	"time"
 
 	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/delivery/http/middleware"
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/gateway"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/condotrack/api/internal/infrastructure/database"
 	"github.com/condotrack/api/internal/infrastructure/external"
+	"github.com/condotrack/api/internal/infrastructure/external/mock"
+	"github.com/condotrack/api/internal/usecase/contractbilling"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/condotrack/api/internal/usecase/payout"
+	"github.com/condotrack/api/internal/usecase/revenue"
+	"github.com/condotrack/api/pkg/money"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,7 +34,14 @@ type WebhookHandler struct {
 	paymentRepo      repository.PaymentRepository
 	paymentTxnRepo   repository.PaymentTransactionRepository
 	revenueSplitRepo repository.RevenueSplitRepository
+	renewalRepo      repository.EnrollmentRenewalRepository
+	ledgerRepo       repository.InstructorLedgerRepository
+	webhookEventRepo repository.WebhookEventRepository
 	gatewayFactory   *external.GatewayFactory
+	transfers        payout.TransferUseCase
+	splitRules       revenue.SplitRuleUseCase
+	billing          contractbilling.UseCase
+	notifier         notification.UseCase
 }
 
 // NewWebhookHandler creates a new webhook handler.
@@ -37,7 +52,14 @@ func NewWebhookHandler(
 	paymentRepo repository.PaymentRepository,
 	paymentTxnRepo repository.PaymentTransactionRepository,
 	revenueSplitRepo repository.RevenueSplitRepository,
+	renewalRepo repository.EnrollmentRenewalRepository,
+	ledgerRepo repository.InstructorLedgerRepository,
+	webhookEventRepo repository.WebhookEventRepository,
 	gatewayFactory *external.GatewayFactory,
+	transfers payout.TransferUseCase,
+	splitRules revenue.SplitRuleUseCase,
+	billing contractbilling.UseCase,
+	notifier notification.UseCase,
 ) *WebhookHandler {
 	return &WebhookHandler{
 		cfg:              cfg,
@@ -46,7 +68,14 @@ func NewWebhookHandler(
 		paymentRepo:      paymentRepo,
 		paymentTxnRepo:   paymentTxnRepo,
 		revenueSplitRepo: revenueSplitRepo,
+		renewalRepo:      renewalRepo,
+		ledgerRepo:       ledgerRepo,
+		webhookEventRepo: webhookEventRepo,
 		gatewayFactory:   gatewayFactory,
+		transfers:        transfers,
+		splitRules:       splitRules,
+		billing:          billing,
+		notifier:         notifier,
 	}
 }
 
@@ -82,22 +111,56 @@ func (h *WebhookHandler) HandleAsaasWebhook(c *gin.Context) {
 		return
 	}
 
+	// Transfer events (instructor payouts) share the webhook URL with payment events
+	if tg, ok := gw.(gateway.TransferGateway); ok {
+		transferEvent, err := tg.ParseTransferEvent(ctx, body)
+		if err != nil {
+			log.Printf("Failed to parse webhook: %v", err)
+			middleware.SetWebhookError(c, err)
+			response.BadRequest(c, "Invalid payload")
+			return
+		}
+		if transferEvent != nil {
+			middleware.SetWebhookEvent(c, transferEvent.GatewayEvent, "", transferEvent.Transfer.GatewayTransferID)
+			log.Printf("Received transfer webhook: event=%s transfer_id=%s status=%s",
+				transferEvent.GatewayEvent, transferEvent.Transfer.GatewayTransferID, transferEvent.Transfer.Status)
+			if err := h.transfers.HandleTransferEvent(ctx, transferEvent); err != nil {
+				log.Printf("Failed to handle transfer event: %v", err)
+				middleware.SetWebhookError(c, err)
+				response.InternalError(c, "Failed to process webhook")
+				return
+			}
+			c.JSON(200, gin.H{
+				"success": true,
+				"message": "Webhook processed",
+			})
+			return
+		}
+	}
+
 	// Parse webhook event into canonical format
 	event, err := gw.ParseWebhookEvent(ctx, headers, body)
 	if err != nil {
 		log.Printf("Failed to parse webhook: %v", err)
+		middleware.SetWebhookError(c, err)
 		response.BadRequest(c, "Invalid payload: "+err.Error())
 		return
 	}
 
+	middleware.SetWebhookEvent(c, event.EventType, event.EventID, event.PaymentID)
 	log.Printf("Received webhook: gateway=%s event=%s payment_id=%s status=%s",
 		event.GatewayName, event.EventType, event.PaymentID, event.Status)
 
+	if h.handleContractCharge(c, event) {
+		return
+	}
+
 	// Handle by canonical event type
 	switch event.EventType {
 	case gateway.EventPaymentConfirmed:
 		if err := h.handlePaymentConfirmed(ctx, event); err != nil {
 			log.Printf("Failed to handle payment confirmation: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
@@ -105,6 +168,7 @@ func (h *WebhookHandler) HandleAsaasWebhook(c *gin.Context) {
 	case gateway.EventPaymentOverdue:
 		if err := h.handlePaymentOverdue(ctx, event); err != nil {
 			log.Printf("Failed to handle payment overdue: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
@@ -112,6 +176,7 @@ func (h *WebhookHandler) HandleAsaasWebhook(c *gin.Context) {
 	case gateway.EventPaymentRefunded:
 		if err := h.handlePaymentRefunded(ctx, event); err != nil {
 			log.Printf("Failed to handle payment refund: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
@@ -119,12 +184,14 @@ func (h *WebhookHandler) HandleAsaasWebhook(c *gin.Context) {
 	case gateway.EventPaymentDeleted:
 		if err := h.handlePaymentDeleted(ctx, event); err != nil {
 			log.Printf("Failed to handle payment deletion: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
 
 	default:
 		log.Printf("Unhandled webhook event: %s", event.EventType)
+		middleware.SetWebhookIgnored(c)
 	}
 
 	c.JSON(200, gin.H{
@@ -133,6 +200,26 @@ func (h *WebhookHandler) HandleAsaasWebhook(c *gin.Context) {
 	})
 }
 
+// handleContractCharge settles the monthly fee charges of contracts, which are not course
+// payments. It reports whether the event was one of them and the response was sent.
+func (h *WebhookHandler) handleContractCharge(c *gin.Context, event *gateway.WebhookEvent) bool {
+	handled, err := h.billing.HandleChargeEvent(c.Request.Context(), event)
+	if err != nil {
+		log.Printf("Failed to handle contract charge %s: %v", event.PaymentID, err)
+		middleware.SetWebhookError(c, err)
+		response.InternalError(c, "Failed to process webhook")
+		return true
+	}
+	if !handled {
+		return false
+	}
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "Webhook processed",
+	})
+	return true
+}
+
 // handlePaymentConfirmed processes payment confirmation and creates revenue split.
 func (h *WebhookHandler) handlePaymentConfirmed(ctx context.Context, event *gateway.WebhookEvent) error {
 	// 1. Try to find payment in payments table by gateway payment ID
@@ -140,6 +227,37 @@ func (h *WebhookHandler) handlePaymentConfirmed(ctx context.Context, event *gate
 	if err != nil {
 		return err
 	}
+	// Later installments of a card charge are gateway payments of their own, found through
+	// the installment plan of the first one
+	if payment == nil && event.InstallmentID != "" {
+		payment, err = h.paymentRepo.FindByGatewayInstallmentID(ctx, event.GatewayName, event.InstallmentID)
+		if err != nil {
+			return err
+		}
+	}
+
+	// A boleto of a carnê is a payment of its own under the payment of the plan, which is the
+	// one confirmed and keeps the splits, one per boleto
+	var carnetPayment *entity.Payment
+	if payment != nil && payment.InstallmentOf != nil {
+		carnetPayment = payment
+		payment, err = h.paymentRepo.FindByID(ctx, *carnetPayment.InstallmentOf)
+		if err != nil {
+			return err
+		}
+		if payment == nil {
+			return fmt.Errorf("payment %s of installment %s not found", *carnetPayment.InstallmentOf, carnetPayment.ID)
+		}
+	}
+
+	// installment is the card installment or the boleto the event settles, 0 when one split
+	// covers the whole payment
+	installment := 0
+	if carnetPayment != nil {
+		installment = *carnetPayment.InstallmentNumber
+	} else if payment != nil && payment.InstallmentCount > 1 {
+		installment = max(event.InstallmentNumber, 1)
+	}
 
 	// 2. Also find enrollment (for backwards compatibility and revenue split)
 	enrollment, err := h.matriculaRepo.FindByAsaasPaymentID(ctx, event.PaymentID)
@@ -147,15 +265,70 @@ func (h *WebhookHandler) handlePaymentConfirmed(ctx context.Context, event *gate
 		return err
 	}
 
+	// Renewal payments are not stored on the enrollment row; resolve them through enrollment_renewals
+	var renewal *entity.EnrollmentRenewal
+	if enrollment == nil && payment != nil {
+		renewal, err = h.renewalRepo.FindByPaymentID(ctx, payment.ID)
+		if err != nil {
+			return err
+		}
+		if renewal != nil {
+			enrollment, err = h.matriculaRepo.FindByID(ctx, renewal.EnrollmentID)
+			if err != nil {
+				return err
+			}
+		} else if installment > 1 {
+			enrollment, err = h.matriculaRepo.FindByID(ctx, payment.EnrollmentID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if enrollment == nil && payment == nil {
 		log.Printf("No enrollment or payment found for gateway payment ID: %s", event.PaymentID)
 		return nil
 	}
 
-	// 3. Log webhook receipt
-	rawPayload := string(event.RawPayload)
-	h.logPaymentTransaction(ctx, payment, entity.TxEventWebhookReceived, event.GatewayEvent,
-		nil, &event.Status, &event.Amount, &rawPayload)
+	// 3. Log webhook receipt; manual confirmations are logged by the payment use case
+	if event.GatewayEvent != gateway.GatewayEventManual {
+		rawPayload := string(event.RawPayload)
+		received := payment
+		if carnetPayment != nil {
+			received = carnetPayment
+		}
+		h.logPaymentTransaction(ctx, received, entity.TxEventWebhookReceived, event.GatewayEvent,
+			nil, &event.Status, &event.Amount, &rawPayload)
+	}
+
+	// A card payment is confirmed first and received when it settles; both events reach
+	// this handler, so reuse the split created by the first one
+	paymentID := event.PaymentID
+	if payment != nil {
+		paymentID = payment.ID
+	}
+	split, err := h.revenueSplitRepo.FindByPaymentID(ctx, paymentID)
+	if err != nil {
+		return err
+	}
+	// Installment payments get one split per settled installment. A split covering the whole
+	// payment, created before installments were split, is reused by all of them.
+	if installment > 0 && split != nil && split.InstallmentNumber != nil {
+		split, err = h.revenueSplitRepo.FindByPaymentInstallment(ctx, paymentID, installment)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Installments after the first only add their split, the first one confirmed the payment
+	confirmsPayment := installment <= 1 || payment.Status != entity.FinPaymentStatusConfirmed
+
+	// The student hears of the payment once: card payments are confirmed and then received,
+	// and each boleto of a carnê is paid on its own
+	notifyPayer := confirmsPayment && !alreadyPaid(payment, enrollment)
+	if carnetPayment != nil {
+		notifyPayer = !carnetPayment.IsPaid()
+	}
 
 	// 4. Start transaction
 	tx, err := h.db.BeginTx(ctx)
@@ -164,13 +337,34 @@ func (h *WebhookHandler) handlePaymentConfirmed(ctx context.Context, event *gate
 	}
 	defer tx.Rollback()
 
+	// Gateways resend events they consider unacknowledged; the claim commits with the split,
+	// so a redelivery is skipped while a failed attempt can still be retried
+	if event.EventID != "" {
+		claimed, err := h.webhookEventRepo.ClaimWithTx(ctx, tx, &entity.ProcessedWebhookEvent{
+			Gateway:          event.GatewayName,
+			GatewayEventID:   event.EventID,
+			EventType:        event.EventType,
+			GatewayPaymentID: event.PaymentID,
+			ProcessedAt:      time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record webhook event: %w", err)
+		}
+		if !claimed {
+			log.Printf("Duplicate webhook event skipped: gateway=%s event_id=%s payment=%s",
+				event.GatewayName, event.EventID, event.PaymentID)
+			return nil
+		}
+	}
+
 	// 5. Update payment record if it exists
-	if payment != nil {
+	if payment != nil && confirmsPayment {
 		prevStatus := payment.Status
 		payment.Status = entity.FinPaymentStatusConfirmed
 		payment.PaidAt = event.PaidAt
-		netAmount := event.NetAmount
-		if netAmount > 0 {
+		// The net of an installment event is the one of the installment only
+		netAmount := money.FromFloat(event.NetAmount)
+		if netAmount > 0 && installment == 0 {
 			payment.NetAmount = netAmount
 		}
 		if err := h.paymentRepo.UpdateWithTx(ctx, tx, payment); err != nil {
@@ -182,60 +376,106 @@ func (h *WebhookHandler) handlePaymentConfirmed(ctx context.Context, event *gate
 			&prevStatus, &payment.Status, &event.Amount, nil)
 	}
 
+	// The boleto itself is confirmed whatever its number
+	if carnetPayment != nil {
+		prevStatus := carnetPayment.Status
+		carnetPayment.Status = entity.FinPaymentStatusConfirmed
+		carnetPayment.PaidAt = event.PaidAt
+		if err := h.paymentRepo.UpdateWithTx(ctx, tx, carnetPayment); err != nil {
+			return err
+		}
+		h.logPaymentTransaction(ctx, carnetPayment, entity.TxEventStatusChanged, event.GatewayEvent,
+			&prevStatus, &carnetPayment.Status, &event.Amount, nil)
+	}
+
 	// 6. Update enrollment status
-	if enrollment != nil {
+	if enrollment != nil && confirmsPayment {
 		if err := h.matriculaRepo.UpdatePaymentStatusWithTx(ctx, tx, enrollment.ID, entity.PaymentStatusConfirmed); err != nil {
 			return err
 		}
 
 		enrollment.Status = entity.EnrollmentStatusActive
 		enrollment.PaymentStatus = entity.PaymentStatusConfirmed
+
+		// Extend the expiration date once per renewal
+		if renewal != nil && renewal.Status == entity.RenewalStatusPending {
+			now := time.Now()
+			newExpiration := entity.RenewalExpiration(enrollment.ExpirationDate, now, renewal.ExtensionDays)
+			renewal.PreviousExpiration = enrollment.ExpirationDate
+			renewal.NewExpiration = &newExpiration
+			renewal.ConfirmedAt = &now
+			renewal.Status = entity.RenewalStatusConfirmed
+			enrollment.ExpirationDate = &newExpiration
+			if err := h.renewalRepo.ConfirmWithTx(ctx, tx, renewal); err != nil {
+				return err
+			}
+		}
+
 		if err := h.matriculaRepo.UpdateWithTx(ctx, tx, enrollment); err != nil {
 			return err
 		}
+	}
 
-		// 7. CREATE REVENUE SPLIT (CRITICAL FIX)
-		gw := h.gatewayFactory.GetActive()
-		fees := gw.GetFees()
+	// 7. CREATE REVENUE SPLIT (CRITICAL FIX)
+	if enrollment != nil && split == nil {
+		// The fee is the one recorded when the charge was created on its gateway; the
+		// active gateway's fee config is only an estimate for charges with no payment record
 		billingType := event.BillingType
+		grossAmount := money.FromFloat(event.Amount)
+		var gatewayFee money.Cents
 		if payment != nil {
 			billingType = payment.PaymentMethod
-		}
-
-		grossAmount := event.Amount
-		if payment != nil {
 			grossAmount = payment.GrossAmount
+			gatewayFee = payment.GatewayFee
+		} else if gw := h.gatewayFactory.GetActive(); gw != nil {
+			gatewayFee = calculateGatewayFee(grossAmount, billingType, gw.GetFees())
 		}
 
-		gatewayFee := roundCents(calculateGatewayFee(grossAmount, billingType, fees))
-		netAmount := roundCents(grossAmount - gatewayFee)
-		instructorAmount := roundCents(netAmount * (h.cfg.RevenueInstructorPercent / 100))
-		platformAmount := roundCents(netAmount * (h.cfg.RevenuePlatformPercent / 100))
-
-		paymentID := event.PaymentID
-		if payment != nil {
-			paymentID = payment.ID
+		if carnetPayment != nil {
+			// Each boleto carries its share of the charge and a boleto fee of its own
+			grossAmount = carnetPayment.GrossAmount
+			gatewayFee = carnetPayment.GatewayFee
+		} else if installment > 0 {
+			// Each installment carries its share of the charge and of its fee
+			grossAmount = entity.InstallmentShare(grossAmount, payment.InstallmentCount, installment)
+			gatewayFee = entity.InstallmentShare(gatewayFee, payment.InstallmentCount, installment)
+		}
+		netAmount := grossAmount - gatewayFee
+
+		split = &entity.RevenueSplit{
+			ID:            uuid.New().String(),
+			EnrollmentID:  enrollment.ID,
+			PaymentID:     paymentID,
+			GrossAmount:   grossAmount,
+			NetAmount:     netAmount,
+			PaymentFee:    gatewayFee,
+			InstructorID:  enrollment.InstructorID,
+			PaymentMethod: billingType,
+			Status:        entity.RevenueSplitStatusPending,
+		}
+		if installment > 0 {
+			split.InstallmentNumber = &installment
 		}
 
-		split := &entity.RevenueSplit{
-			ID:               uuid.New().String(),
-			EnrollmentID:     enrollment.ID,
-			PaymentID:        paymentID,
-			GrossAmount:      grossAmount,
-			NetAmount:        netAmount,
-			PlatformFee:      platformAmount,
-			PaymentFee:       gatewayFee,
-			InstructorAmount: instructorAmount,
-			PlatformAmount:   platformAmount,
-			InstructorID:     enrollment.InstructorID,
-			PaymentMethod:    billingType,
-			Status:           entity.RevenueSplitStatusPending,
+		// Share the net between instructor, affiliates and platform per the configuration
+		// of the course (or its instructor) when overridden, the global one otherwise
+		if err := h.splitRules.Allocate(ctx, split, enrollment.CourseID); err != nil {
+			return fmt.Errorf("failed to allocate revenue split: %w", err)
 		}
 
 		if err := h.revenueSplitRepo.CreateWithTx(ctx, tx, split); err != nil {
 			log.Printf("Failed to create revenue split: %v", err)
 			return fmt.Errorf("failed to create revenue split: %w", err)
 		}
+		if err := h.revenueSplitRepo.ReplacePartiesWithTx(ctx, tx, split); err != nil {
+			return fmt.Errorf("failed to create revenue split parties: %w", err)
+		}
+		if entry := entity.NewSplitCreditEntry(split, time.Now()); entry != nil {
+			entry.ID = uuid.New().String()
+			if err := h.ledgerRepo.CreateWithTx(ctx, tx, entry); err != nil {
+				return fmt.Errorf("failed to record instructor ledger credit: %w", err)
+			}
+		}
 	}
 
 	// 8. Commit
@@ -244,6 +484,18 @@ func (h *WebhookHandler) handlePaymentConfirmed(ctx context.Context, event *gate
 	}
 
 	log.Printf("Payment confirmed: gateway_id=%s enrollment=%s", event.PaymentID, getEnrollmentID(enrollment))
+
+	if notifyPayer {
+		h.notifyPaymentConfirmed(ctx, payment, carnetPayment, enrollment)
+	}
+
+	// 9. Pay the instructor out once the funds are available. A failed transfer stays on the
+	// split for retry and must not fail the webhook, which would make the gateway resend it.
+	if event.Settled && split != nil {
+		if err := h.transfers.AutoTransfer(ctx, split.ID); err != nil {
+			log.Printf("Failed to transfer revenue split %s: %v", split.ID, err)
+		}
+	}
 	return nil
 }
 
@@ -285,13 +537,17 @@ func (h *WebhookHandler) handlePaymentRefunded(ctx context.Context, event *gatew
 	if err != nil {
 		log.Printf("Failed to find payment for refund event: %v", err)
 	}
-	if payment != nil {
+	// Partial refunds issued by an enrollment cancellation are already recorded locally
+	if payment != nil && payment.Status != entity.FinPaymentStatusPartiallyRefunded {
 		payment.Status = entity.FinPaymentStatusRefunded
-		payment.RefundedAmount = event.Amount
+		payment.RefundedAmount = money.FromFloat(event.Amount)
 		if err := h.paymentRepo.Update(ctx, payment); err != nil {
 			log.Printf("Failed to update payment for refund: %v", err)
 		}
 	}
+	if payment != nil {
+		h.debitInstructor(ctx, payment, entity.LedgerEntryRefund, "Estorno")
+	}
 
 	enrollment, err := h.matriculaRepo.FindByAsaasPaymentID(ctx, event.PaymentID)
 	if err != nil {
@@ -326,6 +582,51 @@ func (h *WebhookHandler) handlePaymentRefunded(ctx context.Context, event *gatew
 	return nil
 }
 
+// debitInstructor takes back from the instructor ledger whatever is still credited for the
+// splits of a refunded or charged back payment. Shares already given back by an enrollment
+// cancellation are not debited twice. The split of a boleto of a carnê is the one of its
+// number on the payment of the plan.
+func (h *WebhookHandler) debitInstructor(ctx context.Context, payment *entity.Payment, entryType, label string) {
+	splitPaymentID := payment.ID
+	if payment.InstallmentOf != nil {
+		splitPaymentID = *payment.InstallmentOf
+	}
+	splits, err := h.revenueSplitRepo.FindAllByPaymentID(ctx, splitPaymentID)
+	if err != nil {
+		log.Printf("Failed to find revenue splits of payment %s: %v", splitPaymentID, err)
+		return
+	}
+	for i := range splits {
+		if payment.InstallmentOf != nil && (splits[i].InstallmentNumber == nil || *splits[i].InstallmentNumber != *payment.InstallmentNumber) {
+			continue
+		}
+		h.debitSplit(ctx, payment, &splits[i], entryType, label)
+	}
+}
+
+// debitSplit debits what is still credited for one split of a payment
+func (h *WebhookHandler) debitSplit(ctx context.Context, payment *entity.Payment, split *entity.RevenueSplit, entryType, label string) {
+	earnings, err := h.ledgerRepo.GetSplitEarnings(ctx, split.ID)
+	if err != nil {
+		log.Printf("Failed to fetch ledger earnings of split %s: %v", split.ID, err)
+		return
+	}
+	if earnings <= 0 {
+		return
+	}
+
+	// Keyed by payment, and by installment when the payment has one split per installment
+	entry := entity.NewSplitLedgerEntry(split, entryType, split.EventID(payment.ID), -money.FromFloat(earnings),
+		label+" - matrícula "+split.EnrollmentID, time.Now())
+	if entry == nil {
+		return
+	}
+	entry.ID = uuid.New().String()
+	if err := h.ledgerRepo.Create(ctx, entry); err != nil {
+		log.Printf("Failed to record instructor ledger %s of split %s: %v", entryType, split.ID, err)
+	}
+}
+
 // handlePaymentDeleted processes payment deletion events.
 func (h *WebhookHandler) handlePaymentDeleted(ctx context.Context, event *gateway.WebhookEvent) error {
 	// Update payment record
@@ -373,11 +674,15 @@ func (h *WebhookHandler) logPaymentTransaction(
 		EventType:   eventType,
 		NewStatus:   derefStr(newStatus),
 	}
+	if gatewayEvent == gateway.GatewayEventManual {
+		txLog.EventSource = entity.EventSourceManual
+	}
 	if prevStatus != nil {
 		txLog.PreviousStatus = prevStatus
 	}
 	if amount != nil {
-		txLog.Amount = amount
+		cents := money.FromFloat(*amount)
+		txLog.Amount = &cents
 	}
 	if rawPayload != nil {
 		txLog.RawPayload = rawPayload
@@ -391,24 +696,19 @@ func (h *WebhookHandler) logPaymentTransaction(
 }
 
 // calculateGatewayFee calculates the fee for a given payment method.
-func calculateGatewayFee(amount float64, billingType string, fees gateway.GatewayFees) float64 {
+func calculateGatewayFee(amount money.Cents, billingType string, fees gateway.GatewayFees) money.Cents {
 	switch billingType {
 	case "pix", "PIX":
-		return amount * fees.PixPercent
+		return amount.Mul(fees.PixPercent)
 	case "boleto", "BOLETO":
-		return fees.BoletoFixed
+		return money.FromFloat(fees.BoletoFixed)
 	case "credit_card", "CREDIT_CARD", "card":
-		return (amount * fees.CardPercent) + fees.CardFixed
+		return amount.Mul(fees.CardPercent) + money.FromFloat(fees.CardFixed)
 	default:
 		return 0
 	}
 }
 
-// roundCents rounds a float64 to 2 decimal places for financial precision.
-func roundCents(v float64) float64 {
-	return math.Round(v*100) / 100
-}
-
 func derefStr(s *string) string {
 	if s == nil {
 		return ""
@@ -416,6 +716,60 @@ func derefStr(s *string) string {
 	return *s
 }
 
+// alreadyPaid reports whether the payment, or the enrollment when there is no payment
+// record, was confirmed before
+func alreadyPaid(payment *entity.Payment, enrollment *entity.Matricula) bool {
+	if payment != nil {
+		return payment.IsPaid()
+	}
+	return enrollment != nil && enrollment.PaymentStatus == entity.PaymentStatusConfirmed
+}
+
+// notifyPaymentConfirmed tells the student their payment was confirmed (non-critical). The
+// payment is committed, so a failure is only logged.
+func (h *WebhookHandler) notifyPaymentConfirmed(ctx context.Context, payment, carnetPayment *entity.Payment, enrollment *entity.Matricula) {
+	userID := ""
+	if payment != nil && payment.PayerUserID != nil {
+		userID = *payment.PayerUserID
+	} else if enrollment != nil {
+		userID = enrollment.StudentID
+	}
+	if userID == "" {
+		return
+	}
+
+	payload := map[string]interface{}{}
+	message := "Seu pagamento foi confirmado."
+	if enrollment != nil {
+		payload["enrollment_id"] = enrollment.ID
+		if enrollment.CourseName != "" {
+			message = fmt.Sprintf("Seu pagamento do curso %s foi confirmado.", enrollment.CourseName)
+		}
+	}
+	if payment != nil {
+		payload["payment_id"] = payment.ID
+	}
+	if carnetPayment != nil && carnetPayment.InstallmentNumber != nil {
+		payload["installment_number"] = *carnetPayment.InstallmentNumber
+		message = fmt.Sprintf("O boleto %d/%d do seu carnê foi confirmado.", *carnetPayment.InstallmentNumber, payment.InstallmentCount)
+	}
+	raw, _ := json.Marshal(payload)
+	data := string(raw)
+
+	notif := &entity.Notificacao{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Type:      entity.NotificationTypePayment,
+		Title:     "Pagamento confirmado",
+		Message:   message,
+		Data:      &data,
+		CreatedAt: time.Now(),
+	}
+	if err := h.notifier.Create(ctx, notif); err != nil {
+		log.Printf("Failed to notify user %s of confirmed payment of enrollment %s: %v", userID, getEnrollmentID(enrollment), err)
+	}
+}
+
 func getEnrollmentID(enrollment *entity.Matricula) string {
 	if enrollment == nil {
 		return "unknown"
@@ -461,18 +815,25 @@ func (h *WebhookHandler) HandleMercadoPagoWebhook(c *gin.Context) {
 	if err != nil {
 		log.Printf("Failed to parse MP webhook: %v", err)
 		// Return 200 for unsupported event types (MP expects 200)
+		middleware.SetWebhookIgnored(c)
 		c.JSON(200, gin.H{"success": true, "message": "Event type not handled"})
 		return
 	}
 
+	middleware.SetWebhookEvent(c, event.EventType, event.EventID, event.PaymentID)
 	log.Printf("Received MP webhook: event=%s payment_id=%s status=%s",
 		event.EventType, event.PaymentID, event.Status)
 
+	if h.handleContractCharge(c, event) {
+		return
+	}
+
 	// Reuse the same canonical event handlers
 	switch event.EventType {
 	case gateway.EventPaymentConfirmed:
 		if err := h.handlePaymentConfirmed(ctx, event); err != nil {
 			log.Printf("Failed to handle MP payment confirmation: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
@@ -480,6 +841,7 @@ func (h *WebhookHandler) HandleMercadoPagoWebhook(c *gin.Context) {
 	case gateway.EventPaymentOverdue:
 		if err := h.handlePaymentOverdue(ctx, event); err != nil {
 			log.Printf("Failed to handle MP payment overdue: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
@@ -487,6 +849,7 @@ func (h *WebhookHandler) HandleMercadoPagoWebhook(c *gin.Context) {
 	case gateway.EventPaymentRefunded:
 		if err := h.handlePaymentRefunded(ctx, event); err != nil {
 			log.Printf("Failed to handle MP payment refund: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
@@ -494,6 +857,7 @@ func (h *WebhookHandler) HandleMercadoPagoWebhook(c *gin.Context) {
 	case gateway.EventPaymentDeleted:
 		if err := h.handlePaymentDeleted(ctx, event); err != nil {
 			log.Printf("Failed to handle MP payment deletion: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
@@ -501,12 +865,14 @@ func (h *WebhookHandler) HandleMercadoPagoWebhook(c *gin.Context) {
 	case gateway.EventPaymentChargeback:
 		if err := h.handlePaymentChargeback(ctx, event); err != nil {
 			log.Printf("Failed to handle MP chargeback: %v", err)
+			middleware.SetWebhookError(c, err)
 			response.InternalError(c, "Failed to process webhook")
 			return
 		}
 
 	default:
 		log.Printf("Unhandled MP webhook event: %s", event.EventType)
+		middleware.SetWebhookIgnored(c)
 	}
 
 	c.JSON(200, gin.H{
@@ -515,6 +881,82 @@ func (h *WebhookHandler) HandleMercadoPagoWebhook(c *gin.Context) {
 	})
 }
 
+// HandleMockWebhook handles POST /api/v1/webhooks/mock, the events of the mock gateway
+// registered outside production
+func (h *WebhookHandler) HandleMockWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		log.Printf("Failed to read mock webhook body: %v", err)
+		response.BadRequest(c, "Failed to read body")
+		return
+	}
+
+	gw, err := h.gatewayFactory.Get("mock")
+	if err != nil {
+		log.Printf("Mock gateway not registered: %v", err)
+		response.NotFound(c, "Gateway not configured")
+		return
+	}
+
+	headers := map[string]string{
+		mock.TokenHeader: c.GetHeader(mock.TokenHeader),
+	}
+	if !gw.ValidateWebhookSignature(ctx, headers, body) {
+		log.Printf("Invalid mock webhook token")
+		response.Unauthorized(c, "Invalid webhook token")
+		return
+	}
+
+	event, err := gw.ParseWebhookEvent(ctx, headers, body)
+	if err != nil {
+		log.Printf("Failed to parse mock webhook: %v", err)
+		middleware.SetWebhookError(c, err)
+		response.BadRequest(c, "Invalid payload: "+err.Error())
+		return
+	}
+
+	middleware.SetWebhookEvent(c, event.EventType, event.EventID, event.PaymentID)
+	log.Printf("Received mock webhook: event=%s payment_id=%s status=%s",
+		event.EventType, event.PaymentID, event.Status)
+
+	if h.handleContractCharge(c, event) {
+		return
+	}
+
+	if err := h.ApplyEvent(ctx, event); err != nil {
+		log.Printf("Failed to handle mock %s: %v", event.EventType, err)
+		middleware.SetWebhookError(c, err)
+		response.InternalError(c, "Failed to process webhook")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": true,
+		"message": "Webhook processed",
+	})
+}
+
+// ApplyEvent runs the handler of a canonical payment event. Payment reconciliation replays
+// missed webhooks through it.
+func (h *WebhookHandler) ApplyEvent(ctx context.Context, event *gateway.WebhookEvent) error {
+	switch event.EventType {
+	case gateway.EventPaymentConfirmed:
+		return h.handlePaymentConfirmed(ctx, event)
+	case gateway.EventPaymentOverdue:
+		return h.handlePaymentOverdue(ctx, event)
+	case gateway.EventPaymentRefunded:
+		return h.handlePaymentRefunded(ctx, event)
+	case gateway.EventPaymentDeleted:
+		return h.handlePaymentDeleted(ctx, event)
+	case gateway.EventPaymentChargeback:
+		return h.handlePaymentChargeback(ctx, event)
+	}
+	log.Printf("Unhandled %s webhook event: %s", event.GatewayName, event.EventType)
+	return nil
+}
+
 // handlePaymentChargeback processes chargeback events.
 func (h *WebhookHandler) handlePaymentChargeback(ctx context.Context, event *gateway.WebhookEvent) error {
 	// Update payment record
@@ -527,6 +969,7 @@ func (h *WebhookHandler) handlePaymentChargeback(ctx context.Context, event *gat
 		if err := h.paymentRepo.Update(ctx, payment); err != nil {
 			log.Printf("Failed to update payment for chargeback: %v", err)
 		}
+		h.debitInstructor(ctx, payment, entity.LedgerEntryChargeback, "Chargeback")
 	}
 
 	// Update enrollment
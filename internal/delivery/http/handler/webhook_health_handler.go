@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/webhookhealth"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookHealthHours bounds the window of the webhook health dashboard (30 days)
+const maxWebhookHealthHours = 720
+
+var webhookOutcomes = map[string]bool{
+	entity.WebhookOutcomeProcessed: true, entity.WebhookOutcomeIgnored: true, entity.WebhookOutcomeRejected: true,
+	entity.WebhookOutcomeInvalid: true, entity.WebhookOutcomeFailed: true,
+}
+
+// WebhookHealthHandler handles the webhook health dashboard HTTP requests
+type WebhookHealthHandler struct {
+	usecase webhookhealth.UseCase
+}
+
+// NewWebhookHealthHandler creates a new webhook health handler
+func NewWebhookHealthHandler(uc webhookhealth.UseCase) *WebhookHealthHandler {
+	return &WebhookHealthHandler{usecase: uc}
+}
+
+// GetHealth handles GET /api/v1/webhooks/health
+// Query params: hours (window, default 24, at most 720)
+func (h *WebhookHealthHandler) GetHealth(c *gin.Context) {
+	since, ok := webhookWindow(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.usecase.Health(c.Request.Context(), since)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch webhook health", err)
+		return
+	}
+	response.Success(c, report)
+}
+
+// ListDeliveries handles GET /api/v1/webhooks/deliveries
+// Query params: gateway, outcome (processed, ignored, rejected, invalid or failed), hours
+// (window, default 24, at most 720), page, per_page
+func (h *WebhookHealthHandler) ListDeliveries(c *gin.Context) {
+	since, ok := webhookWindow(c)
+	if !ok {
+		return
+	}
+	filters := entity.WebhookDeliveryFilters{
+		Gateway: c.Query("gateway"),
+		Outcome: c.Query("outcome"),
+		Since:   &since,
+		Page:    1,
+		PerPage: 50,
+	}
+	if filters.Outcome != "" && !webhookOutcomes[filters.Outcome] {
+		response.BadRequest(c, "Invalid outcome, expected processed, ignored, rejected, invalid or failed")
+		return
+	}
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 0 {
+		filters.Page = p
+	}
+	if pp, err := strconv.Atoi(c.Query("per_page")); err == nil && pp > 0 && pp <= 200 {
+		filters.PerPage = pp
+	}
+
+	deliveries, total, err := h.usecase.ListDeliveries(c.Request.Context(), filters)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to fetch webhook deliveries", err)
+		return
+	}
+	response.Success(c, gin.H{
+		"deliveries": deliveries,
+		"total":      total,
+		"page":       filters.Page,
+		"per_page":   filters.PerPage,
+	})
+}
+
+// webhookWindow returns the start of the window given by the hours query param, sending a
+// 400 when it is not valid
+func webhookWindow(c *gin.Context) (time.Time, bool) {
+	hours := 24
+	if s := c.Query("hours"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > maxWebhookHealthHours {
+			response.BadRequest(c, "Invalid hours, expected a number between 1 and 720")
+			return time.Time{}, false
+		}
+		hours = parsed
+	}
+	return time.Now().Add(-time.Duration(hours) * time.Hour), true
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/condotrack/api/internal/usecase/activitylog"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityLog returns a middleware that records who changed what on every mutating request
+// (see activitylog.UseCase.Record). The body is captured and redacted as in RequestAudit;
+// the response of a POST is read for the ID of the record it created. Entries are written
+// after the response, and pending writes are tracked by lc.
+func ActivityLog(uc activitylog.UseCase, lc *lifecycle.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+
+		body := captureBody(c.Request)
+		var writer *createdIDWriter
+		if c.Request.Method == http.MethodPost {
+			writer = &createdIDWriter{ResponseWriter: c.Writer}
+			c.Writer = writer
+		}
+
+		c.Next()
+
+		m := activitylog.Mutation{
+			Method:    c.Request.Method,
+			Route:     c.FullPath(),
+			Path:      c.Request.URL.Path,
+			IP:        c.ClientIP(),
+			RequestID: c.GetString("request_id"),
+			Status:    c.Writer.Status(),
+		}
+		if body != nil {
+			m.Body = *body
+		}
+		if writer != nil {
+			m.CreatedID = writer.createdID()
+		}
+		if userID, ok := GetUserID(c); ok {
+			m.UserID = userID
+		}
+		if role, ok := GetUserRole(c); ok {
+			m.UserRole = role
+		}
+
+		write := func() {
+			ctx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+			defer cancel()
+			if err := uc.Record(ctx, m); err != nil {
+				log.Printf("[ACTIVITY_LOG] Failed to record %s %s: %v", m.Method, m.Path, err)
+			}
+		}
+		done, err := lc.Track("activity_log")
+		if err != nil {
+			write()
+			return
+		}
+		go func() {
+			defer done()
+			write()
+		}()
+	}
+}
+
+// createdIDWriter keeps the start of the response body, up to auditBodyLimit, to read the
+// ID of the record a POST created
+type createdIDWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *createdIDWriter) Write(b []byte) (int, error) {
+	w.keep(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *createdIDWriter) WriteString(s string) (int, error) {
+	w.keep([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *createdIDWriter) keep(b []byte) {
+	if room := auditBodyLimit + 1 - w.body.Len(); room > 0 {
+		if len(b) > room {
+			b = b[:room]
+		}
+		w.body.Write(b)
+	}
+}
+
+// createdID returns data.id of a JSON response ({"success": true, "data": {"id": ...}}),
+// or "" when the response has none or was too large to keep
+func (w *createdIDWriter) createdID() string {
+	if w.body.Len() > auditBodyLimit {
+		return ""
+	}
+	var resp struct {
+		Data struct {
+			ID json.RawMessage `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.body.Bytes(), &resp); err != nil || len(resp.Data.ID) == 0 {
+		return ""
+	}
+	var id string
+	if err := json.Unmarshal(resp.Data.ID, &id); err == nil {
+		return id
+	}
+	// Numeric IDs
+	var n json.Number
+	if err := json.Unmarshal(resp.Data.ID, &n); err == nil {
+		return n.String()
+	}
+	return ""
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/condotrack/api/internal/infrastructure/auth"
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -21,34 +22,54 @@ const (
 	UserRoleKey = "user_role"
 	// ClaimsKey is the context key for JWT claims
 	ClaimsKey = "claims"
+	// WebSocketBearerProtocol is the WebSocket subprotocol announcing a token as the next one
+	WebSocketBearerProtocol = "bearer"
 )
 
+// WebSocketProtocolToken lets browsers, which cannot set headers on WebSocket connections,
+// authenticate by offering the subprotocols "bearer" and the token, as in
+// new WebSocket(url, ["bearer", token]). It must run before AuthMiddleware.
+func WebSocketProtocolToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(AuthorizationHeader) == "" {
+			protocols := strings.Split(c.GetHeader("Sec-WebSocket-Protocol"), ",")
+			for i := 0; i+1 < len(protocols); i++ {
+				if strings.TrimSpace(protocols[i]) == WebSocketBearerProtocol {
+					c.Request.Header.Set(AuthorizationHeader, BearerPrefix+strings.TrimSpace(protocols[i+1]))
+					break
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
 // AuthMiddleware creates a middleware that validates JWT tokens
 func AuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader(AuthorizationHeader)
 		if authHeader == "" {
-			response.Unauthorized(c, "Authorization header is required")
+			response.ErrorCode(c, apperror.CodeTokenMissing, "Authorization header is required")
 			c.Abort()
 			return
 		}
 
 		if !strings.HasPrefix(authHeader, BearerPrefix) {
-			response.Unauthorized(c, "Invalid authorization header format. Use: Bearer <token>")
+			response.ErrorCode(c, apperror.CodeTokenInvalid, "Invalid authorization header format. Use: Bearer <token>")
 			c.Abort()
 			return
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, BearerPrefix)
 		if tokenString == "" {
-			response.Unauthorized(c, "Token is required")
+			response.ErrorCode(c, apperror.CodeTokenMissing, "Token is required")
 			c.Abort()
 			return
 		}
 
 		// Check if token has been blacklisted (logout)
 		if jwtManager.IsBlacklisted(tokenString) {
-			response.Unauthorized(c, "Token has been revoked")
+			response.ErrorCode(c, apperror.CodeTokenRevoked, "Token has been revoked")
 			c.Abort()
 			return
 		}
@@ -57,11 +78,11 @@ func AuthMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
 		if err != nil {
 			switch err {
 			case auth.ErrExpiredToken:
-				response.Unauthorized(c, "Token has expired")
+				response.ErrorCode(c, apperror.CodeTokenExpired, "Token has expired")
 			case auth.ErrInvalidToken:
-				response.Unauthorized(c, "Invalid token")
+				response.ErrorCode(c, apperror.CodeTokenInvalid, "Invalid token")
 			case auth.ErrInvalidClaims:
-				response.Unauthorized(c, "Invalid token claims")
+				response.ErrorCode(c, apperror.CodeTokenInvalid, "Invalid token claims")
 			default:
 				response.Unauthorized(c, "Authentication failed")
 			}
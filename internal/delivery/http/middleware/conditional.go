@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalGET returns a middleware that tags GET responses with a weak ETag derived from
+// the versions of the tables the endpoint reads, and answers 304 Not Modified when the
+// client's If-None-Match still matches, before the handler runs. The ETag also covers the
+// URL and the user, since filters and access rules change the representation. Register it
+// after authentication and access checks.
+func ConditionalGET(versions repository.ResourceVersionRepository, tables ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		current := make([]entity.ResourceVersion, 0, len(tables))
+		for _, table := range tables {
+			v, err := versions.TableVersion(c.Request.Context(), table)
+			if err != nil {
+				log.Printf("[ETAG] Failed to read version of %s: %v", table, err)
+				c.Next()
+				return
+			}
+			if !v.Settled() {
+				c.Next()
+				return
+			}
+			current = append(current, *v)
+		}
+
+		userID, _ := GetUserID(c)
+		role, _ := GetUserRole(c)
+		etag := entity.WeakETag(c.Request.URL.RequestURI()+"|"+userID+"|"+role, current...)
+
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", "private, no-cache")
+		if entity.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,253 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ContractResolver extracts the contract a request acts on.
+// An empty ID means there is no contract to check (e.g. the resource does not exist).
+type ContractResolver func(c *gin.Context) (string, error)
+
+// ContractAccess enforces per-contract team roles on audit, inspection, task, purchase
+// order and supplier link endpoints
+type ContractAccess struct {
+	teamRepo             repository.TeamRepository
+	gestorRepo           repository.GestorRepository
+	contratoRepo         repository.ContratoRepository
+	auditRepo            repository.AuditRepository
+	inspectionRepo       repository.InspectionRepository
+	taskRepo             repository.TaskRepository
+	purchaseOrderRepo    repository.PurchaseOrderRepository
+	supplierContractRepo repository.SupplierContractRepository
+}
+
+// NewContractAccess creates a new contract access checker
+func NewContractAccess(
+	teamRepo repository.TeamRepository,
+	gestorRepo repository.GestorRepository,
+	contratoRepo repository.ContratoRepository,
+	auditRepo repository.AuditRepository,
+	inspectionRepo repository.InspectionRepository,
+	taskRepo repository.TaskRepository,
+	purchaseOrderRepo repository.PurchaseOrderRepository,
+	supplierContractRepo repository.SupplierContractRepository,
+) *ContractAccess {
+	return &ContractAccess{
+		teamRepo:             teamRepo,
+		gestorRepo:           gestorRepo,
+		contratoRepo:         contratoRepo,
+		auditRepo:            auditRepo,
+		inspectionRepo:       inspectionRepo,
+		taskRepo:             taskRepo,
+		purchaseOrderRepo:    purchaseOrderRepo,
+		supplierContractRepo: supplierContractRepo,
+	}
+}
+
+// Require creates a middleware that lets admins through and otherwise requires an active
+// team assignment on the resolved contract whose role allows the action
+func (a *ContractAccess) Require(action string, resolve ContractResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.Check(c, action, resolve) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Check runs the same verification as Require for handlers that dispatch requests themselves.
+// It writes the error response and returns false when access is denied.
+func (a *ContractAccess) Check(c *gin.Context, action string, resolve ContractResolver) bool {
+	role, _ := GetUserRole(c)
+	if role == "admin" {
+		return true
+	}
+
+	userID, ok := GetUserID(c)
+	if !ok {
+		response.Unauthorized(c, "User not found in context")
+		return false
+	}
+
+	contractID, err := resolve(c)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to check contract access", err)
+		return false
+	}
+	if contractID == "" {
+		return true
+	}
+
+	member, err := a.teamRepo.FindByUserAndContract(c.Request.Context(), userID, contractID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to check contract access", err)
+		return false
+	}
+	if member == nil || !member.IsActiveOn(time.Now()) || !entity.TeamRoleAllows(member.Role, action) {
+		response.Forbidden(c, "You don't have permission to perform this action on this contract")
+		return false
+	}
+
+	return true
+}
+
+// GestorUser resolves the user of the gestor in the given route parameter, for
+// RequirePermission on a gestor's own record
+func (a *ContractAccess) GestorUser(param string) OwnerResolver {
+	return func(c *gin.Context) (string, error) {
+		return a.gestorUser(c, c.Param(param))
+	}
+}
+
+// ContractGestor resolves the user of the gestor managing the contract in the given route
+// parameter
+func (a *ContractAccess) ContractGestor(param string) OwnerResolver {
+	return func(c *gin.Context) (string, error) {
+		contrato, err := a.contratoRepo.FindByID(c.Request.Context(), c.Param(param))
+		if err != nil || contrato == nil {
+			return "", err
+		}
+		return a.gestorUser(c, contrato.GestorID)
+	}
+}
+
+// GestorFromBody resolves the user of the gestor in a JSON body field, leaving the body
+// readable for the handler. A missing field or malformed body resolves to no owner.
+func (a *ContractAccess) GestorFromBody(field string) OwnerResolver {
+	return func(c *gin.Context) (string, error) {
+		gestorID, err := bodyField(c, field)
+		if err != nil {
+			return "", err
+		}
+		return a.gestorUser(c, gestorID)
+	}
+}
+
+// ContractNewGestor resolves the user of the gestor a contract has after an update: the one
+// in the JSON body field, or the current one of the contract in the route parameter when the
+// body leaves the field out
+func (a *ContractAccess) ContractNewGestor(param, field string) OwnerResolver {
+	current := a.ContractGestor(param)
+	return func(c *gin.Context) (string, error) {
+		gestorID, err := bodyField(c, field)
+		if err != nil {
+			return "", err
+		}
+		if gestorID == "" {
+			return current(c)
+		}
+		return a.gestorUser(c, gestorID)
+	}
+}
+
+// gestorUser returns the user linked to a gestor, empty when there is none
+func (a *ContractAccess) gestorUser(c *gin.Context, gestorID string) (string, error) {
+	if gestorID == "" {
+		return "", nil
+	}
+	gestor, err := a.gestorRepo.FindByID(c.Request.Context(), gestorID)
+	if err != nil || gestor == nil || gestor.UserID == nil {
+		return "", err
+	}
+	return *gestor.UserID, nil
+}
+
+// AuditContract resolves the contract of the audit in the given route parameter
+func (a *ContractAccess) AuditContract(param string) ContractResolver {
+	return func(c *gin.Context) (string, error) {
+		audit, err := a.auditRepo.FindByID(c.Request.Context(), c.Param(param))
+		if err != nil || audit == nil {
+			return "", err
+		}
+		return audit.ContractID, nil
+	}
+}
+
+// InspectionContract resolves the contract of the inspection in the given route parameter
+func (a *ContractAccess) InspectionContract(param string) ContractResolver {
+	return func(c *gin.Context) (string, error) {
+		inspection, err := a.inspectionRepo.FindByID(c.Request.Context(), c.Param(param))
+		if err != nil || inspection == nil {
+			return "", err
+		}
+		return inspection.ContractID, nil
+	}
+}
+
+// TaskContract resolves the contract of the task in the given route parameter.
+// Tasks without a contract resolve to no contract.
+func (a *ContractAccess) TaskContract(param string) ContractResolver {
+	return func(c *gin.Context) (string, error) {
+		task, err := a.taskRepo.FindByID(c.Request.Context(), c.Param(param))
+		if err != nil || task == nil || task.ContractID == nil {
+			return "", err
+		}
+		return *task.ContractID, nil
+	}
+}
+
+// PurchaseOrderContract resolves the contract of the purchase order in the given route parameter
+func (a *ContractAccess) PurchaseOrderContract(param string) ContractResolver {
+	return func(c *gin.Context) (string, error) {
+		order, err := a.purchaseOrderRepo.FindByID(c.Request.Context(), c.Param(param))
+		if err != nil || order == nil {
+			return "", err
+		}
+		return order.ContratoID, nil
+	}
+}
+
+// SupplierContract resolves the contract of the supplier link in the given route parameter
+func (a *ContractAccess) SupplierContract(param string) ContractResolver {
+	return func(c *gin.Context) (string, error) {
+		link, err := a.supplierContractRepo.FindByID(c.Request.Context(), c.Param(param))
+		if err != nil || link == nil {
+			return "", err
+		}
+		return link.ContratoID, nil
+	}
+}
+
+// ContractFromParam resolves the contract ID from a route parameter
+func ContractFromParam(name string) ContractResolver {
+	return func(c *gin.Context) (string, error) {
+		return c.Param(name), nil
+	}
+}
+
+// ContractFromBody resolves the contract ID from a JSON body field, leaving the body
+// readable for the handler. A missing field or malformed body resolves to no contract,
+// so the handler's own validation reports it.
+func ContractFromBody(field string) ContractResolver {
+	return func(c *gin.Context) (string, error) {
+		return bodyField(c, field)
+	}
+}
+
+// bodyField reads a string field of the JSON body and restores the body for the handler
+func bodyField(c *gin.Context, field string) (string, error) {
+	if c.Request.Body == nil {
+		return "", nil
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", nil
+	}
+	value, _ := payload[field].(string)
+	return value, nil
+}
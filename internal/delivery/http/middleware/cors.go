@@ -6,42 +6,82 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// CORS returns a middleware that handles Cross-Origin Resource Sharing.
-// If allowedOrigins is non-empty, only those origins are allowed (with credentials).
-// If empty, all origins are allowed without credentials (no wildcard + credentials).
-func CORS(allowedOrigins string) gin.HandlerFunc {
-	allowed := make(map[string]bool)
-	if allowedOrigins != "" {
-		for _, o := range strings.Split(allowedOrigins, ",") {
-			origin := strings.TrimSpace(o)
-			if origin != "" {
-				allowed[origin] = true
-			}
+// CORSPolicy is the set of origins allowed to call a group of routes
+type CORSPolicy struct {
+	origins map[string]bool
+	// public allows any origin with "*" and no credentials, for endpoints called from
+	// third-party pages
+	public bool
+}
+
+// NewCORSPolicy parses a comma separated list of origins.
+// If the list is "*", any origin is allowed, without credentials.
+// If it has origins, only those are allowed (with credentials).
+// If empty, the request origin is echoed back (development mode).
+func NewCORSPolicy(origins string) CORSPolicy {
+	policy := CORSPolicy{origins: make(map[string]bool)}
+	for _, o := range strings.Split(origins, ",") {
+		origin := strings.TrimSpace(o)
+		switch origin {
+		case "":
+		case "*":
+			policy.public = true
+		default:
+			policy.origins[origin] = true
 		}
 	}
+	return policy
+}
 
-	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-
-		if len(allowed) > 0 {
-			// Whitelist mode: only allow configured origins
-			if allowed[origin] {
-				c.Header("Access-Control-Allow-Origin", origin)
-				c.Header("Access-Control-Allow-Credentials", "true")
-			}
-			// If origin not in whitelist, don't set CORS headers (browser will block)
+func (p CORSPolicy) apply(c *gin.Context, origin string) {
+	switch {
+	case p.public:
+		c.Header("Access-Control-Allow-Origin", "*")
+	case len(p.origins) > 0:
+		// Whitelist mode: only allow configured origins
+		if p.origins[origin] {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+			c.Writer.Header().Add("Vary", "Origin")
+		}
+		// If origin not in whitelist, don't set CORS headers (browser will block)
+	default:
+		// Development mode: allow all origins
+		if origin != "" {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Credentials", "true")
+			c.Writer.Header().Add("Vary", "Origin")
 		} else {
-			// Development mode: allow all origins but without credentials for safety
-			if origin != "" {
-				c.Header("Access-Control-Allow-Origin", origin)
-				c.Header("Access-Control-Allow-Credentials", "true")
-			} else {
-				c.Header("Access-Control-Allow-Origin", "*")
+			c.Header("Access-Control-Allow-Origin", "*")
+		}
+	}
+}
+
+// CORSRoute applies a policy to the routes under a path prefix
+type CORSRoute struct {
+	Prefix string
+	Policy CORSPolicy
+}
+
+// CORS returns a middleware that handles Cross-Origin Resource Sharing. Requests use the
+// policy of the longest matching route prefix, or defaultPolicy when none matches. It runs
+// globally rather than per group so preflight requests, which match no route, get the same
+// policy as the request they announce.
+func CORS(defaultPolicy CORSPolicy, routes ...CORSRoute) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := defaultPolicy
+		longest := -1
+		path := c.Request.URL.Path
+		for _, route := range routes {
+			if len(route.Prefix) > longest && matchesPrefix(path, route.Prefix) {
+				policy, longest = route.Policy, len(route.Prefix)
 			}
 		}
+		policy.apply(c, c.Request.Header.Get("Origin"))
 
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Accept, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Accept, X-Requested-With, If-None-Match, Idempotency-Key")
+		c.Header("Access-Control-Expose-Headers", "ETag, X-Request-ID, Idempotent-Replayed, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After")
 		c.Header("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == "OPTIONS" {
@@ -52,3 +92,11 @@ func CORS(allowedOrigins string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// matchesPrefix reports whether path is prefix or a path below it
+func matchesPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
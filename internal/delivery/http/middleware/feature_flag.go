@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureChecker evaluates feature flags
+type FeatureChecker interface {
+	IsEnabled(ctx context.Context, key string, fc entity.FlagContext) bool
+}
+
+// FlagContext builds the flag evaluation context of the authenticated user
+func FlagContext(c *gin.Context, orgID string) entity.FlagContext {
+	userID, _ := GetUserID(c)
+	role, _ := GetUserRole(c)
+	return entity.FlagContext{UserID: userID, Role: role, OrgID: orgID}
+}
+
+// RequireFeature creates a middleware that answers 404 while the flag is off for the caller,
+// so gated endpoints look like they do not exist yet. resolve picks the org (contract) the
+// request acts on and may be nil.
+func RequireFeature(flags FeatureChecker, key string, resolve ContractResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var orgID string
+		if resolve != nil {
+			id, err := resolve(c)
+			if err != nil {
+				response.SafeInternalError(c, "Failed to check feature availability", err)
+				c.Abort()
+				return
+			}
+			orgID = id
+		}
+
+		if !flags.IsEnabled(c.Request.Context(), key, FlagContext(c, orgID)) {
+			response.NotFound(c, "Resource not found")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
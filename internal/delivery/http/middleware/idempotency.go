@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from a previous request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyMaxLen = 255
+	idempotencyTTL       = 24 * time.Hour
+	// idempotencyStaleAfter frees keys of requests that never finished, e.g. on a crash
+	idempotencyStaleAfter = 2 * time.Minute
+	idempotencyPurgeEvery = time.Hour
+	idempotencyTimeout    = 5 * time.Second
+)
+
+// Idempotency returns an opt-in middleware for create endpoints: a request sent with an
+// Idempotency-Key header runs once per key, route and user, and retries within 24 hours get
+// the stored response back. Retrying with the same key but a different body is refused, as
+// is a retry while the first request is still running. Only successful responses are
+// stored; failed requests release the key so they can be retried. It must run after the
+// authentication middleware. Expired keys are purged hourly by a worker of lc.
+func Idempotency(repo repository.IdempotencyRepository, lc *lifecycle.Manager) gin.HandlerFunc {
+	lc.Every("idempotency purge", idempotencyPurgeEvery, false, func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, idempotencyTimeout)
+		defer cancel()
+		if _, err := repo.DeleteExpired(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("[IDEMPOTENCY] Failed to purge expired keys: %v", err)
+		}
+	})
+
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > idempotencyKeyMaxLen {
+			response.ErrorCode(c, apperror.CodeIdempotencyKeyInvalid, "Idempotency-Key must be at most 255 characters")
+			c.Abort()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				response.BadRequest(c, "Failed to read request body")
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		userID, _ := GetUserID(c)
+		route := c.Request.Method + " " + c.FullPath()
+		record := &entity.IdempotencyKey{
+			ID:          sha256Hex([]byte(userID + "\n" + route + "\n" + key)),
+			Key:         key,
+			Route:       route,
+			RequestHash: sha256Hex(body),
+		}
+		if userID != "" {
+			record.UserID = &userID
+		}
+
+		reserved, err := repo.Reserve(c.Request.Context(), record, idempotencyTTL, idempotencyStaleAfter)
+		if err != nil {
+			// Without the store the request still runs, just without the retry protection
+			log.Printf("[IDEMPOTENCY] Failed to reserve key for %s: %v", route, err)
+			c.Next()
+			return
+		}
+		if !reserved {
+			replayIdempotent(c, repo, record)
+			return
+		}
+
+		w := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		ctx, cancel := context.WithTimeout(context.Background(), idempotencyTimeout)
+		defer cancel()
+
+		status := w.Status()
+		if status < 200 || status >= 300 {
+			if err := repo.Delete(ctx, record.ID); err != nil {
+				log.Printf("[IDEMPOTENCY] Failed to release key for %s: %v", route, err)
+			}
+			return
+		}
+
+		contentType := w.Header().Get("Content-Type")
+		responseBody := w.body.String()
+		record.StatusCode = &status
+		record.ContentType = &contentType
+		record.ResponseBody = &responseBody
+		if err := repo.Complete(ctx, record); err != nil {
+			log.Printf("[IDEMPOTENCY] Failed to store response for %s: %v", route, err)
+		}
+	}
+}
+
+// replayIdempotent answers a request whose key is already taken
+func replayIdempotent(c *gin.Context, repo repository.IdempotencyRepository, record *entity.IdempotencyKey) {
+	existing, err := repo.FindByID(c.Request.Context(), record.ID)
+	if err != nil {
+		response.SafeInternalError(c, "Failed to load idempotency key", err)
+		c.Abort()
+		return
+	}
+	if existing == nil {
+		// Released between the reservation and the lookup: the first request failed
+		response.ErrorCode(c, apperror.CodeIdempotencyInProgress, "A request with this Idempotency-Key is being processed")
+		c.Abort()
+		return
+	}
+
+	switch {
+	case existing.RequestHash != record.RequestHash:
+		response.ErrorCode(c, apperror.CodeIdempotencyKeyReused, "Idempotency-Key was already used with a different request")
+	case !existing.Completed():
+		response.ErrorCode(c, apperror.CodeIdempotencyInProgress, "A request with this Idempotency-Key is being processed")
+	default:
+		contentType := "application/json; charset=utf-8"
+		if existing.ContentType != nil && *existing.ContentType != "" {
+			contentType = *existing.ContentType
+		}
+		var body []byte
+		if existing.ResponseBody != nil {
+			body = []byte(*existing.ResponseBody)
+		}
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(*existing.StatusCode, contentType, body)
+	}
+	c.Abort()
+}
+
+// idempotencyWriter keeps a copy of the response body for storage
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// IPAllowlist restricts routes to client IPs within a list of CIDR ranges. An empty list
+// allows every client. The list can be replaced at runtime, e.g. when its setting changes.
+type IPAllowlist struct {
+	name string
+
+	mu   sync.RWMutex
+	nets []*net.IPNet
+}
+
+// NewIPAllowlist creates an empty (allow all) allowlist; name identifies it in logs
+func NewIPAllowlist(name string) *IPAllowlist {
+	return &IPAllowlist{name: name}
+}
+
+// ParseIPAllowlist parses a comma or newline separated list of CIDR ranges. Bare addresses
+// are accepted as single-host ranges.
+func ParseIPAllowlist(value string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, entry := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ' ' || r == '\t' || r == '\r'
+	}) {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an IP address or CIDR range", entry)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an IP address or CIDR range", entry)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// ValidateIPAllowlist reports whether value is a valid allowlist
+func ValidateIPAllowlist(value string) error {
+	_, err := ParseIPAllowlist(value)
+	return err
+}
+
+// Set replaces the allowed ranges. An invalid list is logged and the current one is kept,
+// so a bad value never opens or locks out the routes.
+func (a *IPAllowlist) Set(value string) {
+	nets, err := ParseIPAllowlist(value)
+	if err != nil {
+		log.Printf("[IP_ALLOWLIST] Ignoring invalid %s allowlist: %v", a.name, err)
+		return
+	}
+	a.mu.Lock()
+	a.nets = nets
+	a.mu.Unlock()
+}
+
+// Allows reports whether the client IP is within the allowlist
+func (a *IPAllowlist) Allows(clientIP string) bool {
+	a.mu.RLock()
+	nets := a.nets
+	a.mu.RUnlock()
+	if len(nets) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Require returns a middleware rejecting clients outside the allowlist with 403
+func (a *IPAllowlist) Require() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !a.Allows(c.ClientIP()) {
+			log.Printf("[IP_ALLOWLIST] Blocked %s from %s %s", c.ClientIP(), a.name, c.Request.URL.Path)
+			response.ErrorCode(c, apperror.CodeIPNotAllowed, "Access from this IP address is not allowed")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
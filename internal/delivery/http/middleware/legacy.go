@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	legacyFlushEvery   = time.Minute
+	legacyFlushTimeout = 5 * time.Second
+)
+
+// LegacyPolicy describes the deprecation of the legacy PHP router
+type LegacyPolicy struct {
+	// DeprecatedAt is sent in the Deprecation header (RFC 9745)
+	DeprecatedAt time.Time
+	// Sunset, when set, is the date the router stops answering (RFC 8594)
+	Sunset time.Time
+	// Successors maps every legacy endpoint to the /api/v1 route replacing it, sent as a
+	// successor-version link. Endpoints missing from it are counted as "(unknown)".
+	Successors map[string]string
+}
+
+// LegacyDeprecation returns a middleware for the legacy router that announces its deprecation
+// in every response and counts the requests per endpoint, method and day. Counts are kept in
+// memory and added to the stored totals every minute, so tracking costs no query per request;
+// the counts left at shutdown are flushed by a hook of lc.
+func LegacyDeprecation(policy LegacyPolicy, repo repository.LegacyUsageRepository, lc *lifecycle.Manager) gin.HandlerFunc {
+	counter := &legacyCounter{repo: repo, counts: make(map[legacyUsageKey]*entity.LegacyUsage)}
+	// Periodic flushes get a context of their own so one in progress at shutdown completes
+	lc.Every("legacy usage flush", legacyFlushEvery, false, func(context.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), legacyFlushTimeout)
+		defer cancel()
+		counter.flush(ctx)
+	})
+	lc.OnShutdown("legacy usage flush", func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, legacyFlushTimeout)
+		defer cancel()
+		return counter.flush(ctx)
+	})
+
+	return func(c *gin.Context) {
+		endpoint := strings.ToLower(c.Query("endpoint"))
+
+		if !policy.DeprecatedAt.IsZero() {
+			c.Header("Deprecation", "@"+strconv.FormatInt(policy.DeprecatedAt.Unix(), 10))
+		}
+		if !policy.Sunset.IsZero() {
+			c.Header("Sunset", policy.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if successor, ok := policy.Successors[endpoint]; ok {
+			c.Writer.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+		}
+
+		c.Next()
+
+		// Only known endpoints get a counter of their own, so random values cannot grow the table
+		tracked := endpoint
+		if _, ok := policy.Successors[endpoint]; !ok && endpoint != "" {
+			tracked = "(unknown)"
+		}
+		userID, _ := GetUserID(c)
+		counter.add(tracked, c.Request.Method, userID, c.Writer.Status() >= 400)
+	}
+}
+
+type legacyUsageKey struct {
+	day      string
+	endpoint string
+	method   string
+}
+
+// legacyCounter aggregates legacy router usage between flushes
+type legacyCounter struct {
+	repo repository.LegacyUsageRepository
+
+	mu     sync.Mutex
+	counts map[legacyUsageKey]*entity.LegacyUsage
+}
+
+func (lc *legacyCounter) add(endpoint, method, userID string, failed bool) {
+	now := time.Now()
+	if endpoint == "" {
+		endpoint = "(index)"
+	}
+	key := legacyUsageKey{day: now.Format("2006-01-02"), endpoint: endpoint, method: method}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	u, ok := lc.counts[key]
+	if !ok {
+		day, _ := time.ParseInLocation("2006-01-02", key.day, now.Location())
+		u = &entity.LegacyUsage{Day: day, Endpoint: endpoint, Method: method}
+		lc.counts[key] = u
+	}
+	u.Requests++
+	if failed {
+		u.Errors++
+	}
+	if userID != "" {
+		u.LastUserID = &userID
+	}
+	u.LastSeenAt = now
+}
+
+// flush stores the counts gathered since the last flush. Counts that fail to be stored are
+// kept for the next attempt.
+func (lc *legacyCounter) flush(ctx context.Context) error {
+	lc.mu.Lock()
+	pending := lc.counts
+	lc.counts = make(map[legacyUsageKey]*entity.LegacyUsage)
+	lc.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	usage := make([]entity.LegacyUsage, 0, len(pending))
+	for _, u := range pending {
+		usage = append(usage, *u)
+	}
+
+	if err := lc.repo.Add(ctx, usage); err != nil {
+		log.Printf("[LEGACY] Failed to store usage of %d endpoints: %v", len(usage), err)
+		lc.restore(pending)
+		return err
+	}
+	return nil
+}
+
+func (lc *legacyCounter) restore(pending map[legacyUsageKey]*entity.LegacyUsage) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	for key, old := range pending {
+		u, ok := lc.counts[key]
+		if !ok {
+			lc.counts[key] = old
+			continue
+		}
+		u.Requests += old.Requests
+		u.Errors += old.Errors
+		if u.LastUserID == nil {
+			u.LastUserID = old.LastUserID
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// InFlight creates a middleware that tracks each request as an operation of kind, so shutdown
+// waits for it, e.g. a webhook whose transaction must not be cut off when the database closes.
+// Requests arriving once shutdown has started get a 503 with Retry-After and are redelivered
+// by the sender.
+func InFlight(lc *lifecycle.Manager, kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		done, err := lc.Track(kind)
+		if err != nil {
+			c.Header("Retry-After", "30")
+			response.ErrorCode(c, apperror.CodeUnavailable, "Server is shutting down, retry later")
+			c.Abort()
+			return
+		}
+		defer done()
+		c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// OwnerResolver extracts the user who owns the record a request acts on. An empty ID means
+// the record has no owner (it does not exist or is not linked to a user) and is denied.
+type OwnerResolver func(c *gin.Context) (string, error)
+
+// RequirePermission creates a middleware that checks the role of the user against the
+// permission matrix (entity.RolePermission). When the role may only act on its own records,
+// every owner resolver must resolve to the user; without resolvers such a role is denied.
+func RequirePermission(resource, action string, owners ...OwnerResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !CheckPermission(c, resource, action, owners...) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// CheckPermission runs the same verification as RequirePermission for handlers that dispatch
+// requests themselves. It writes the error response and returns false when access is denied.
+func CheckPermission(c *gin.Context, resource, action string, owners ...OwnerResolver) bool {
+	role, _ := GetUserRole(c)
+	switch entity.RolePermission(entity.UserRole(role), resource, action) {
+	case entity.ScopeAll:
+		return true
+	case entity.ScopeOwn:
+		if len(owners) > 0 && ownsAll(c, owners) {
+			return true
+		}
+		if c.IsAborted() {
+			return false
+		}
+	}
+
+	response.Forbidden(c, "You don't have permission to perform this action")
+	return false
+}
+
+// ownsAll reports whether the user owns what every resolver points at. It aborts with an
+// internal error when a resolver fails.
+func ownsAll(c *gin.Context, owners []OwnerResolver) bool {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return false
+	}
+	for _, resolve := range owners {
+		owner, err := resolve(c)
+		if err != nil {
+			response.SafeInternalError(c, "Failed to check permission", err)
+			c.Abort()
+			return false
+		}
+		if owner == "" || owner != userID {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/gin-gonic/gin"
+)
+
+// newPermissionRouter serves the gestor and contract routes as router.go guards them. Gestor
+// and user IDs differ, as they do in the database.
+func newPermissionRouter(userID, role string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ana, bia := "user-ana", "user-bia"
+	gestores := testutil.NewMockGestorRepository(
+		&entity.Gestor{ID: "gst-1", UserID: &ana, Ativo: true},
+		&entity.Gestor{ID: "gst-2", UserID: &bia, Ativo: true},
+		&entity.Gestor{ID: "gst-3", Ativo: true}, // no account linked
+	)
+	contratos := testutil.NewMockContratoRepository(
+		&entity.Contrato{ID: "ctr-1", GestorID: "gst-1"},
+		&entity.Contrato{ID: "ctr-2", GestorID: "gst-2"},
+	)
+	access := NewContractAccess(nil, gestores, contratos, nil, nil, nil, nil, nil)
+
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Set(UserIDKey, userID)
+		c.Set(UserRoleKey, role)
+	})
+	// Echoes the body, to show the resolvers left it readable
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+	engine.PUT("/gestores/:id", RequirePermission(entity.ResourceGestores, entity.ActionUpdate, access.GestorUser("id")), echo)
+	engine.POST("/contratos", RequirePermission(entity.ResourceContratos, entity.ActionCreate, access.GestorFromBody("gestor_id")), echo)
+	engine.PUT("/contratos/:id",
+		RequirePermission(entity.ResourceContratos, entity.ActionUpdate, access.ContractGestor("id"), access.ContractNewGestor("id", "gestor_id")),
+		echo)
+	engine.DELETE("/contratos/:id", RequirePermission(entity.ResourceContratos, entity.ActionDelete, access.ContractGestor("id")), echo)
+	return engine
+}
+
+func TestRequirePermission_GestorOwnership(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"own gestor record", "gestor", http.MethodPut, "/gestores/gst-1", `{"nome":"Ana"}`, http.StatusOK},
+		{"gestor record of another user", "gestor", http.MethodPut, "/gestores/gst-2", `{"nome":"Ana"}`, http.StatusForbidden},
+		{"gestor record without a user", "gestor", http.MethodPut, "/gestores/gst-3", `{}`, http.StatusForbidden},
+		{"gestor ID taken for the user ID", "gestor", http.MethodPut, "/gestores/user-ana", `{}`, http.StatusForbidden},
+
+		{"create own contract", "gestor", http.MethodPost, "/contratos", `{"gestor_id":"gst-1"}`, http.StatusOK},
+		{"create contract of another gestor", "gestor", http.MethodPost, "/contratos", `{"gestor_id":"gst-2"}`, http.StatusForbidden},
+		{"create contract without gestor_id", "gestor", http.MethodPost, "/contratos", `{"nome":"X"}`, http.StatusForbidden},
+		{"create contract with a malformed body", "gestor", http.MethodPost, "/contratos", `{"gestor_id":`, http.StatusForbidden},
+		{"create contract with a numeric gestor_id", "gestor", http.MethodPost, "/contratos", `{"gestor_id":1}`, http.StatusForbidden},
+		{"create contract with the user ID", "gestor", http.MethodPost, "/contratos", `{"gestor_id":"user-ana"}`, http.StatusForbidden},
+
+		{"update own contract", "gestor", http.MethodPut, "/contratos/ctr-1", `{"nome":"X"}`, http.StatusOK},
+		{"update own contract keeping the gestor", "gestor", http.MethodPut, "/contratos/ctr-1", `{"gestor_id":"gst-1"}`, http.StatusOK},
+		{"move own contract to another gestor", "gestor", http.MethodPut, "/contratos/ctr-1", `{"gestor_id":"gst-2"}`, http.StatusForbidden},
+		{"update contract of another gestor", "gestor", http.MethodPut, "/contratos/ctr-2", `{"gestor_id":"gst-1"}`, http.StatusForbidden},
+		{"update missing contract", "gestor", http.MethodPut, "/contratos/ctr-9", `{}`, http.StatusForbidden},
+		{"delete own contract", "manager", http.MethodDelete, "/contratos/ctr-1", ``, http.StatusOK},
+		{"delete contract of another gestor", "gestor", http.MethodDelete, "/contratos/ctr-2", ``, http.StatusForbidden},
+
+		{"admin creates any contract", "admin", http.MethodPost, "/contratos", `{"gestor_id":"gst-2"}`, http.StatusOK},
+		{"supervisor cannot create contracts", "supervisor", http.MethodPost, "/contratos", `{"gestor_id":"gst-1"}`, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newPermissionRouter("user-ana", tt.role)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Fatalf("%s %s = %d, want %d (%s)", tt.method, tt.path, w.Code, tt.want, w.Body.String())
+			}
+			if tt.want == http.StatusOK && w.Body.String() != tt.body {
+				t.Errorf("expected the handler to read the body, got %q", w.Body.String())
+			}
+		})
+	}
+}
@@ -1,19 +1,25 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/condotrack/api/internal/infrastructure/auth"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/ratelimit"
+	"github.com/condotrack/api/pkg/response"
 	"github.com/gin-gonic/gin"
 )
 
-// visitor holds rate limiting state for a single IP address
-type visitor struct {
-	tokens    int
-	lastSeen  time.Time
-	mu        sync.Mutex
-}
+// Rate limit headers sent on every limited request
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
 
 // MaxBodySize returns a middleware that limits the request body size.
 // Requests exceeding maxBytes will get a 413 error.
@@ -24,69 +30,89 @@ func MaxBodySize(maxBytes int64) gin.HandlerFunc {
 	}
 }
 
-// RateLimiter returns a middleware that limits requests per IP address
-// using an in-memory token bucket algorithm.
-// limit is the maximum number of requests allowed within the given window.
-func RateLimiter(limit int, window time.Duration) gin.HandlerFunc {
-	var visitors sync.Map
-
-	// Background goroutine to clean up expired entries
-	go func() {
-		for {
-			time.Sleep(window * 2)
-			visitors.Range(func(key, value interface{}) bool {
-				v := value.(*visitor)
-				v.mu.Lock()
-				if time.Since(v.lastSeen) > window*2 {
-					v.mu.Unlock()
-					visitors.Delete(key)
-					return true
-				}
-				v.mu.Unlock()
-				return true
-			})
+// RateLimiter returns a middleware that limits requests per IP address using a token bucket
+// algorithm. limit is the maximum number of requests allowed within the given window. The
+// buckets are kept in store under name, so limiters sharing a store have separate budgets.
+func RateLimiter(store ratelimit.Store, name string, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limitRequest(c, store.Allow(c.Request.Context(), name+":ip:"+c.ClientIP(), limit, window)) {
+			return
 		}
-	}()
+		c.Next()
+	}
+}
 
-	return func(c *gin.Context) {
-		ip := c.ClientIP()
+// RateLimitTiers are the request budgets per window of each kind of caller
+type RateLimitTiers struct {
+	Admin     int // admin
+	Gestor    int // gestor, manager, auditor and instructor
+	Student   int // student and user
+	Anonymous int // requests without a valid token, per IP
+}
 
-		val, _ := visitors.LoadOrStore(ip, &visitor{
-			tokens:   limit,
-			lastSeen: time.Now(),
-		})
-		v := val.(*visitor)
+func (t RateLimitTiers) forRole(role string) int {
+	switch role {
+	case "admin":
+		return t.Admin
+	case "gestor", "manager", "auditor", "instructor":
+		return t.Gestor
+	}
+	return t.Student
+}
 
-		v.mu.Lock()
-		defer v.mu.Unlock()
+// UserRateLimiter limits authenticated requests per user (JWT subject), with a budget that
+// depends on the role, so users sharing an office NAT no longer share one budget. Requests
+// without a valid token fall back to a per IP budget. Paths starting with one of the exempt
+// prefixes (health checks, gateway webhooks) are never limited.
+func UserRateLimiter(store ratelimit.Store, jwtManager *auth.JWTManager, tiers RateLimitTiers, window time.Duration, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, prefix := range exempt {
+			if strings.HasPrefix(path, prefix) {
+				c.Next()
+				return
+			}
+		}
 
-		// Replenish tokens based on elapsed time
-		elapsed := time.Since(v.lastSeen)
-		if elapsed > window {
-			// Full window has passed, reset tokens
-			v.tokens = limit
-		} else {
-			// Proportional replenishment
-			replenish := int(float64(limit) * (float64(elapsed) / float64(window)))
-			v.tokens += replenish
-			if v.tokens > limit {
-				v.tokens = limit
+		key, limit := "ip:"+c.ClientIP(), tiers.Anonymous
+		if header := c.GetHeader(AuthorizationHeader); strings.HasPrefix(header, BearerPrefix) {
+			token := strings.TrimPrefix(header, BearerPrefix)
+			if claims, err := jwtManager.ValidateToken(token); err == nil && !jwtManager.IsBlacklisted(token) {
+				key, limit = "user:"+claims.UserID, tiers.forRole(claims.Role)
 			}
 		}
-		v.lastSeen = time.Now()
 
-		// Check if request is allowed
-		if v.tokens <= 0 {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"error":   "Too many requests",
-			})
+		if !limitRequest(c, store.Allow(c.Request.Context(), "global:"+key, limit, window)) {
 			return
 		}
-
-		// Consume a token
-		v.tokens--
-
 		c.Next()
 	}
 }
+
+// limitRequest sets the rate limit headers and, when the request was refused, aborts it with
+// a 429 telling when to retry. Behind several limiters (the global one and a route one) the
+// headers describe the one with the fewest requests left.
+func limitRequest(c *gin.Context, q ratelimit.Quota) bool {
+	header := c.Writer.Header()
+	if current, err := strconv.Atoi(header.Get(RateLimitRemainingHeader)); err != nil || q.Remaining <= current {
+		header.Set(RateLimitLimitHeader, strconv.Itoa(q.Limit))
+		header.Set(RateLimitRemainingHeader, strconv.Itoa(q.Remaining))
+		header.Set(RateLimitResetHeader, strconv.FormatInt(time.Now().Unix()+seconds(q.Reset), 10))
+	}
+	if q.Allowed {
+		return true
+	}
+
+	retryAfter := seconds(q.Retry)
+	header.Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+	response.AppError(c, apperror.New(apperror.CodeRateLimited, "Too many requests").
+		WithDetails("limit", q.Limit).
+		WithDetails("retry_after", retryAfter))
+	c.Abort()
+	return false
+}
+
+// seconds rounds a duration up to whole seconds
+func seconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"runtime/debug"
 
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,6 +18,7 @@ func Recovery() gin.HandlerFunc {
 				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 					"success": false,
 					"error":   "Internal server error",
+					"code":    apperror.CodeInternal,
 				})
 			}
 		}()
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// auditBodyLimit is the largest body recorded; larger bodies are noted but not stored
+	auditBodyLimit = 16 << 10
+	// auditStringLimit truncates long values such as base64 images inside recorded bodies
+	auditStringLimit  = 1024
+	auditWriteTimeout = 5 * time.Second
+)
+
+// sensitiveKeyParts mark the JSON keys whose values are never recorded. CPFs and CNPJs also
+// come as holder_doc and as *_document (owner_document, payer_document); document_type and
+// document_id are kept.
+var sensitiveKeyParts = []string{
+	"password", "senha", "token", "secret", "api_key", "apikey", "authorization",
+	"card", "cvv", "security_code", "cpf", "holder_doc", "_document", "private_key",
+}
+
+// RequestAudit returns a middleware that records every mutating request (method, path,
+// actor, status, latency and body) so admins can trace who changed what. Only JSON bodies
+// are stored, with credentials, card data and CPF/CNPJ documents redacted; records are
+// written after the response so a slow or failing database never affects the request.
+// Pending writes are tracked by lc, so shutdown waits for them.
+func RequestAudit(repo repository.APIRequestRepository, lc *lifecycle.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		body := captureBody(c.Request)
+
+		c.Next()
+
+		record := &entity.APIRequest{
+			ID:        uuid.New().String(),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Status:    c.Writer.Status(),
+			LatencyMs: time.Since(start).Milliseconds(),
+			ClientIP:  c.ClientIP(),
+			Body:      body,
+		}
+		if route := c.FullPath(); route != "" {
+			record.Route = &route
+		}
+		if userID, ok := GetUserID(c); ok && userID != "" {
+			record.ActorID = &userID
+		}
+		if role, ok := GetUserRole(c); ok && role != "" {
+			record.ActorRole = &role
+		}
+		if id := c.GetString("request_id"); id != "" {
+			record.RequestID = &id
+		}
+
+		write := func() {
+			ctx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+			defer cancel()
+			if err := repo.Create(ctx, record); err != nil {
+				log.Printf("[REQUEST_AUDIT] Failed to record %s %s: %v", record.Method, record.Path, err)
+			}
+		}
+		done, err := lc.Track("request_audit")
+		if err != nil {
+			// Shutting down: nobody would wait for a goroutine
+			write()
+			return
+		}
+		go func() {
+			defer done()
+			write()
+		}()
+	}
+}
+
+// captureBody reads up to auditBodyLimit bytes of the body for the record and puts them
+// back in front of the rest, so handlers still see the full body.
+func captureBody(r *http.Request) *string {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	contentType := strings.ToLower(r.Header.Get("Content-Type"))
+	if strings.HasPrefix(contentType, "multipart/") {
+		return strPtr("[multipart body omitted]")
+	}
+
+	captured, err := io.ReadAll(io.LimitReader(r.Body, auditBodyLimit+1))
+	r.Body = readCloser{io.MultiReader(bytes.NewReader(captured), r.Body), r.Body}
+	if err != nil || len(captured) == 0 {
+		return nil
+	}
+	if len(captured) > auditBodyLimit {
+		return strPtr(fmt.Sprintf("[body larger than %d KB omitted]", auditBodyLimit>>10))
+	}
+	return strPtr(redactBody(captured))
+}
+
+// redactBody returns the JSON body with the values of sensitive keys replaced and long
+// strings truncated. Bodies that are not JSON cannot be redacted and are not recorded.
+func redactBody(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Sprintf("[non-JSON body of %d bytes omitted]", len(body))
+	}
+
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "[body omitted]"
+	}
+	return string(out)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if isSensitiveKey(k) {
+				val[k] = "[REDACTED]"
+				continue
+			}
+			val[k] = redactValue(item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactValue(item)
+		}
+		return val
+	case string:
+		if len(val) > auditStringLimit {
+			return fmt.Sprintf("[%d chars omitted]", len(val))
+		}
+		return val
+	}
+	return v
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// readCloser joins the replayed body reader with the original body's Close
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRedactBody_Checkout(t *testing.T) {
+	body := `{
+		"student_id": "stu-1",
+		"student_name": "Ana Souza",
+		"student_email": "ana@example.com",
+		"student_cpf": "529.982.247-25",
+		"student_phone": "11987654321",
+		"course_id": "c1",
+		"course_name": "NR-35",
+		"amount": 199.9,
+		"discount_code": "DEZ",
+		"payment_method": "card",
+		"card_number": "4111111111111111",
+		"card_exp_month": "12",
+		"card_exp_year": "2030",
+		"card_cvv": "123",
+		"holder_name": "Ana Souza",
+		"holder_email": "ana@example.com",
+		"holder_doc": "52998224725",
+		"holder_zip": "01001000",
+		"holder_phone": "11987654321",
+		"installments": 3
+	}`
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(redactBody([]byte(body))), &got); err != nil {
+		t.Fatalf("expected a JSON record, got %v", err)
+	}
+
+	for _, key := range []string{"student_cpf", "card_number", "card_exp_month", "card_exp_year", "card_cvv", "holder_doc"} {
+		if got[key] != "[REDACTED]" {
+			t.Errorf("expected %s to be redacted, got %v", key, got[key])
+		}
+	}
+	kept := map[string]interface{}{
+		"student_id": "stu-1", "course_id": "c1", "payment_method": "card", "holder_name": "Ana Souza",
+		"amount": 199.9, "installments": 3.0,
+	}
+	for key, want := range kept {
+		if got[key] != want {
+			t.Errorf("expected %s to be kept as %v, got %v", key, want, got[key])
+		}
+	}
+}
+
+func TestRedactBody_Documents(t *testing.T) {
+	body := `{"owner_document":"52998224725","payer_document":"11222333000181","document_type":"alvara","document_id":"doc-1"}`
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(redactBody([]byte(body))), &got); err != nil {
+		t.Fatalf("expected a JSON record, got %v", err)
+	}
+	if got["owner_document"] != "[REDACTED]" || got["payer_document"] != "[REDACTED]" {
+		t.Errorf("expected the CPF/CNPJ documents to be redacted, got %v", got)
+	}
+	if got["document_type"] != "alvara" || got["document_id"] != "doc-1" {
+		t.Errorf("expected the document references to be kept, got %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Context keys the webhook handlers use to describe a delivery to WebhookDeliveries
+const (
+	webhookEventTypeKey = "webhook_event_type"
+	webhookEventIDKey   = "webhook_event_id"
+	webhookPaymentIDKey = "webhook_payment_id"
+	webhookOutcomeKey   = "webhook_outcome"
+	webhookErrorKey     = "webhook_error"
+)
+
+// webhookErrorLimit is the size of the error column
+const webhookErrorLimit = 500
+
+// SetWebhookEvent records the event a webhook delivery carried
+func SetWebhookEvent(c *gin.Context, eventType, eventID, paymentID string) {
+	c.Set(webhookEventTypeKey, eventType)
+	c.Set(webhookEventIDKey, eventID)
+	c.Set(webhookPaymentIDKey, paymentID)
+}
+
+// SetWebhookIgnored marks a webhook delivery whose event type is not handled
+func SetWebhookIgnored(c *gin.Context) {
+	c.Set(webhookOutcomeKey, entity.WebhookOutcomeIgnored)
+}
+
+// SetWebhookError records why a webhook delivery failed
+func SetWebhookError(c *gin.Context, err error) {
+	c.Set(webhookErrorKey, err.Error())
+}
+
+// WebhookDeliveries returns a middleware that records every webhook delivery of a gateway,
+// named after the last segment of the route (/webhooks/asaas), for the webhook health
+// dashboard. The outcome follows the response status — 2xx processed, 401 rejected, other
+// 4xx invalid, 5xx failed — unless the handler marked the event as ignored. Records are
+// written after the response, and pending writes are tracked by lc.
+func WebhookDeliveries(repo repository.WebhookDeliveryRepository, lc *lifecycle.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		delivery := &entity.WebhookDelivery{
+			ID:               uuid.New().String(),
+			Gateway:          path.Base(c.FullPath()),
+			EventType:        contextString(c, webhookEventTypeKey),
+			GatewayEventID:   contextString(c, webhookEventIDKey),
+			GatewayPaymentID: contextString(c, webhookPaymentIDKey),
+			Outcome:          webhookOutcome(c),
+			Status:           c.Writer.Status(),
+			LatencyMs:        time.Since(start).Milliseconds(),
+			Error:            contextString(c, webhookErrorKey),
+			ReceivedAt:       start,
+		}
+		if delivery.Error != nil && len(*delivery.Error) > webhookErrorLimit {
+			truncated := (*delivery.Error)[:webhookErrorLimit]
+			delivery.Error = &truncated
+		}
+
+		write := func() {
+			ctx, cancel := context.WithTimeout(context.Background(), auditWriteTimeout)
+			defer cancel()
+			if err := repo.Create(ctx, delivery); err != nil {
+				log.Printf("[WEBHOOKS] Failed to record %s delivery: %v", delivery.Gateway, err)
+			}
+		}
+		done, err := lc.Track("webhook_delivery")
+		if err != nil {
+			write()
+			return
+		}
+		go func() {
+			defer done()
+			write()
+		}()
+	}
+}
+
+func webhookOutcome(c *gin.Context) string {
+	if outcome := c.GetString(webhookOutcomeKey); outcome != "" {
+		return outcome
+	}
+	switch status := c.Writer.Status(); {
+	case status < http.StatusBadRequest:
+		return entity.WebhookOutcomeProcessed
+	case status == http.StatusUnauthorized:
+		return entity.WebhookOutcomeRejected
+	case status < http.StatusInternalServerError:
+		return entity.WebhookOutcomeInvalid
+	}
+	return entity.WebhookOutcomeFailed
+}
+
+// contextString returns a string set on the context, nil when unset or empty
+func contextString(c *gin.Context, key string) *string {
+	if s := c.GetString(key); s != "" {
+		return &s
+	}
+	return nil
+}
@@ -1,49 +1,93 @@
 package http
 
 import (
+	"context"
 	"log"
 	"time"
 
 	"github.com/condotrack/api/internal/config"
 	"github.com/condotrack/api/internal/delivery/http/handler"
 	"github.com/condotrack/api/internal/delivery/http/middleware"
-	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/condotrack/api/internal/infrastructure/auth"
 	"github.com/condotrack/api/internal/infrastructure/database"
 	"github.com/condotrack/api/internal/infrastructure/external"
+	"github.com/condotrack/api/internal/infrastructure/external/anthropic"
 	"github.com/condotrack/api/internal/infrastructure/external/asaas"
+	"github.com/condotrack/api/internal/infrastructure/external/gemini"
 	"github.com/condotrack/api/internal/infrastructure/external/mercadopago"
+	"github.com/condotrack/api/internal/infrastructure/external/mock"
+	"github.com/condotrack/api/internal/infrastructure/external/openai"
+	"github.com/condotrack/api/internal/infrastructure/external/resilience"
+	"github.com/condotrack/api/internal/infrastructure/external/smtp"
+	"github.com/condotrack/api/internal/infrastructure/external/twilio"
+	"github.com/condotrack/api/internal/infrastructure/external/webpush"
 	infraRepo "github.com/condotrack/api/internal/infrastructure/repository"
 	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/internal/usecase/accounting"
+	"github.com/condotrack/api/internal/usecase/activitylog"
 	"github.com/condotrack/api/internal/usecase/agenda"
+	"github.com/condotrack/api/internal/usecase/assistant"
 	authUseCase "github.com/condotrack/api/internal/usecase/auth"
 	"github.com/condotrack/api/internal/usecase/audit"
+	"github.com/condotrack/api/internal/usecase/broadcast"
 	"github.com/condotrack/api/internal/usecase/certificado"
+	"github.com/condotrack/api/internal/usecase/catalog"
 	"github.com/condotrack/api/internal/usecase/checkout"
+	"github.com/condotrack/api/internal/usecase/compliance"
+	"github.com/condotrack/api/internal/usecase/contractdocument"
+	"github.com/condotrack/api/internal/usecase/contractbilling"
+	"github.com/condotrack/api/internal/usecase/contractfinance"
+	"github.com/condotrack/api/internal/usecase/contractkpi"
+	"github.com/condotrack/api/internal/usecase/contractrenewal"
 	"github.com/condotrack/api/internal/usecase/contrato"
 	"github.com/condotrack/api/internal/usecase/coupon"
 	"github.com/condotrack/api/internal/usecase/course"
+	"github.com/condotrack/api/internal/usecase/evidence"
+	"github.com/condotrack/api/internal/usecase/featureflag"
 	"github.com/condotrack/api/internal/usecase/gestor"
 	"github.com/condotrack/api/internal/usecase/inspection"
+	"github.com/condotrack/api/internal/usecase/instructorportal"
+	"github.com/condotrack/api/internal/usecase/ledger"
 	"github.com/condotrack/api/internal/usecase/matricula"
+	"github.com/condotrack/api/internal/usecase/notification"
 	"github.com/condotrack/api/internal/usecase/payment"
+	"github.com/condotrack/api/internal/usecase/payout"
 	"github.com/condotrack/api/internal/usecase/revenue"
+	"github.com/condotrack/api/internal/usecase/search"
 	"github.com/condotrack/api/internal/usecase/setting"
+	"github.com/condotrack/api/internal/usecase/purchaseorder"
+	"github.com/condotrack/api/internal/usecase/splitadjustment"
+	"github.com/condotrack/api/internal/usecase/statement"
+	"github.com/condotrack/api/internal/usecase/studentportal"
 	"github.com/condotrack/api/internal/usecase/supplier"
+	"github.com/condotrack/api/internal/usecase/systemimage"
 	"github.com/condotrack/api/internal/usecase/task"
+	"github.com/condotrack/api/internal/usecase/taskescalation"
 	"github.com/condotrack/api/internal/usecase/team"
+	"github.com/condotrack/api/internal/usecase/webhookhealth"
+	"github.com/condotrack/api/pkg/cache"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/condotrack/api/pkg/ratelimit"
+	"github.com/condotrack/api/pkg/realtime"
+	"github.com/condotrack/api/pkg/secretbox"
+	"github.com/condotrack/api/pkg/validation"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
 // Router holds all the handlers and configuration
 type Router struct {
-	cfg     *config.Config
-	db      *database.MySQL
-	storage *storage.StorageService
+	cfg       *config.Config
+	db        *database.MySQL
+	storage   *storage.StorageService
+	lifecycle *lifecycle.Manager
 
 	// Handlers
 	healthHandler         *handler.HealthHandler
+	errorCatalogHandler   *handler.ErrorCatalogHandler
 	gestorHandler         *handler.GestorHandler
 	contratoHandler       *handler.ContratoHandler
 	auditHandler          *handler.AuditHandler
@@ -52,28 +96,77 @@ type Router struct {
 	paymentHandler        *handler.PaymentHandler
 	checkoutHandler       *handler.CheckoutHandler
 	webhookHandler        *handler.WebhookHandler
+	webhookHealthHandler  *handler.WebhookHealthHandler
+	webhookDeliveryRepo   repository.WebhookDeliveryRepository
 	certificadoHandler    *handler.CertificadoHandler
 	imageHandler          *handler.ImageHandler
 	portalHandler         *handler.PortalHandler
+	studentPortalHandler  *handler.StudentPortalHandler
+	instructorPortalHandler *handler.InstructorPortalHandler
+	studentStatementHandler *handler.StudentStatementHandler
 	notificationHandler   *handler.NotificationHandler
+	notificationBroadcastHandler *handler.NotificationBroadcastHandler
+	catalogHandler          *handler.CatalogHandler
 	statsHandler          *handler.StatsHandler
 	revenueHandler        *handler.RevenueHandler
+	payoutHandler         *handler.PayoutHandler
+	accountingHandler     *handler.AccountingHandler
+	ledgerHandler         *handler.LedgerHandler
+	splitAdjustmentHandler *handler.SplitAdjustmentHandler
 	supplierHandler       *handler.SupplierHandler
+	searchHandler         *handler.SearchHandler
+	complianceHandler     *handler.ComplianceHandler
+	purchaseOrderHandler  *handler.PurchaseOrderHandler
+	contractDocumentHandler *handler.ContractDocumentHandler
+	contractRenewalHandler  *handler.ContractRenewalHandler
+	contractFinanceHandler  *handler.ContractFinanceHandler
+	contractBillingHandler  *handler.ContractBillingHandler
+	contractKPIHandler      *handler.ContractKPIHandler
 	courseHandler         *handler.CourseHandler
 	taskHandler           *handler.TaskHandler
+	taskEscalationHandler *handler.TaskEscalationHandler
 	teamHandler       *handler.TeamHandler
 	agendaHandler     *handler.AgendaHandler
 	inspectionHandler *handler.InspectionHandler
 	couponHandler     *handler.CouponHandler
 	authHandler       *handler.AuthHandler
 	settingHandler    *handler.SettingHandler
+	featureFlagHandler *handler.FeatureFlagHandler
+	systemImageHandler *handler.SystemImageHandler
+	gatewayHandler    *handler.GatewayHandler
+	aiHandler         *handler.AIHandler
+	apiRequestHandler *handler.APIRequestHandler
+	apiRequestRepo    repository.APIRequestRepository
+	activityLogHandler *handler.ActivityLogHandler
+	activityLogUC     activitylog.UseCase
+	legacyUsageHandler *handler.LegacyUsageHandler
+	legacyUsageRepo   repository.LegacyUsageRepository
+	usersAllowlist    *middleware.IPAllowlist
+	resourceVersions  repository.ResourceVersionRepository
+	idempotencyRepo   repository.IdempotencyRepository
+	settingsAllowlist *middleware.IPAllowlist
+	reconcileAllowlist *middleware.IPAllowlist
 	jwtManager        *auth.JWTManager
+	rateLimits        ratelimit.Store
+	contractAccess    *middleware.ContractAccess
+	featureFlags      featureflag.UseCase
 }
 
-// NewRouter creates a new router with all dependencies
-func NewRouter(cfg *config.Config, db *database.MySQL) *Router {
+// NewRouter creates a new router with all dependencies. Background workers are started on lc,
+// which the caller shuts down after the HTTP server.
+func NewRouter(cfg *config.Config, db *database.MySQL, lc *lifecycle.Manager) *Router {
+	// Gateway HTTP clients share one retry policy and keep a circuit breaker per gateway
+	gatewayHTTP := resilience.NewRegistry(resilience.Config{
+		Timeout:          time.Duration(cfg.GatewayHTTPTimeout) * time.Second,
+		MaxRetries:       cfg.GatewayMaxRetries,
+		BaseDelay:        time.Duration(cfg.GatewayRetryBaseDelay) * time.Millisecond,
+		MaxDelay:         time.Duration(cfg.GatewayRetryMaxDelay) * time.Millisecond,
+		FailureThreshold: cfg.GatewayBreakerThreshold,
+		OpenDuration:     time.Duration(cfg.GatewayBreakerOpenPeriod) * time.Second,
+	})
+
 	// Initialize Asaas client
-	asaasClient := asaas.NewClient(cfg.AsaasAPIKey, cfg.AsaasAPIURL)
+	asaasClient := asaas.NewClient(cfg.AsaasAPIKey, cfg.AsaasAPIURL, gatewayHTTP.Client("asaas"))
 
 	// Initialize storage service (MinIO)
 	storageService, err := storage.NewStorageService(cfg)
@@ -83,29 +176,31 @@ func NewRouter(cfg *config.Config, db *database.MySQL) *Router {
 	}
 
 	// Initialize Asaas adapter and gateway factory
-	asaasAdapter := asaas.NewAsaasAdapter(asaasClient, gateway.GatewayFees{
-		PixPercent:  0.0099,
-		BoletoFixed: 2.99,
-		CardPercent: 0.0299,
-		CardFixed:   0.49,
-	}, cfg.AsaasWebhookToken)
+	asaasAdapter := asaas.NewAsaasAdapter(asaasClient, asaasFees, cfg.AsaasWebhookToken)
 
 	gatewayFactory := external.NewGatewayFactory()
 	gatewayFactory.Register(asaasAdapter)
 
 	// Register Mercado Pago adapter if configured
+	var mpClient *mercadopago.Client
 	if cfg.MercadoPagoAccessToken != "" {
-		mpClient := mercadopago.NewClient(cfg.MercadoPagoAccessToken, cfg.MercadoPagoEnv)
-		mpAdapter := mercadopago.NewMercadoPagoAdapter(mpClient, gateway.GatewayFees{
-			PixPercent:  0.0099,  // 0.99%
-			BoletoFixed: 3.49,
-			CardPercent: 0.0499,  // 4.99%
-			CardFixed:   0.39,
-		}, cfg.MercadoPagoWebhookSecret)
+		mpClient = mercadopago.NewClient(cfg.MercadoPagoAccessToken, cfg.MercadoPagoEnv, gatewayHTTP.Client("mercadopago"))
+		mpAdapter := mercadopago.NewMercadoPagoAdapter(mpClient, mercadoPagoFees, cfg.MercadoPagoWebhookSecret)
 		gatewayFactory.Register(mpAdapter)
 		log.Printf("Mercado Pago gateway registered (env: %s)", cfg.MercadoPagoEnv)
 	}
 
+	// The mock gateway simulates payments for staging and E2E tests; never in production
+	if !cfg.IsProduction() {
+		gatewayFactory.Register(mock.NewMockAdapter(mock.Options{
+			ConfirmAfter: time.Duration(cfg.MockGatewayConfirmAfter) * time.Second,
+			FailureRate:  cfg.MockGatewayFailureRate,
+			WebhookURL:   cfg.MockGatewayWebhookURL,
+			WebhookToken: cfg.MockGatewayWebhookToken,
+		}, mockFees))
+		log.Printf("Mock gateway registered (webhooks to %s)", cfg.MockGatewayWebhookURL)
+	}
+
 	// Set the active/default gateway
 	activeGatewayName := cfg.DefaultPaymentGateway
 	if activeGatewayName == "" {
@@ -115,6 +210,11 @@ func NewRouter(cfg *config.Config, db *database.MySQL) *Router {
 		log.Printf("Warning: Failed to set active gateway %q, falling back to asaas: %v", activeGatewayName, err)
 		_ = gatewayFactory.SetActive("asaas")
 	}
+	if cfg.FallbackPaymentGateway != "" {
+		if err := gatewayFactory.SetFallback(cfg.FallbackPaymentGateway); err != nil {
+			log.Printf("Warning: Failed to set fallback gateway %q: %v", cfg.FallbackPaymentGateway, err)
+		}
+	}
 
 	// Initialize repositories
 	gestorRepo := infraRepo.NewGestorMySQLRepository(db.DB)
@@ -125,77 +225,364 @@ func NewRouter(cfg *config.Config, db *database.MySQL) *Router {
 	matriculaRepo := infraRepo.NewMatriculaMySQLRepository(db.DB)
 	certificadoRepo := infraRepo.NewCertificadoMySQLRepository(db.DB)
 	notificacaoRepo := infraRepo.NewNotificacaoMySQLRepository(db.DB)
+	notificationDeliveryRepo := infraRepo.NewNotificationDeliveryMySQLRepository(db.DB)
+	notificationBroadcastRepo := infraRepo.NewNotificationBroadcastMySQLRepository(db.DB, db.Reader())
+	courseRatingRepo := infraRepo.NewCourseRatingMySQLRepository(db.DB, db.Reader())
+	pushSubscriptionRepo := infraRepo.NewPushSubscriptionMySQLRepository(db.DB)
 	revenueSplitRepo := infraRepo.NewRevenueSplitMySQLRepository(db.DB)
+	payoutBatchRepo := infraRepo.NewPayoutBatchMySQLRepository(db.DB)
+	payoutAccountRepo := infraRepo.NewInstructorPayoutAccountMySQLRepository(db.DB)
+	accountingRepo := infraRepo.NewAccountingMySQLRepository(db.DB)
+	splitRuleRepo := infraRepo.NewSplitRuleMySQLRepository(db.DB)
+	ledgerRepo := infraRepo.NewInstructorLedgerMySQLRepository(db.DB)
+	webhookEventRepo := infraRepo.NewWebhookEventMySQLRepository(db.DB)
+	splitAdjustmentRepo := infraRepo.NewSplitAdjustmentMySQLRepository(db.DB)
+	splitDisputeRepo := infraRepo.NewSplitDisputeMySQLRepository(db.DB)
 	supplierRepo := infraRepo.NewSupplierMySQLRepository(db.DB)
+	supplierEvaluationRepo := infraRepo.NewSupplierEvaluationMySQLRepository(db.DB)
+	supplierContractRepo := infraRepo.NewSupplierContractMySQLRepository(db.DB, db.Reader())
+	supplierDocumentRepo := infraRepo.NewSupplierDocumentMySQLRepository(db.DB)
+	complianceRepo := infraRepo.NewComplianceMySQLRepository(db.Reader())
+	searchRepo := infraRepo.NewSearchMySQLRepository(db.Reader())
+	purchaseOrderRepo := infraRepo.NewPurchaseOrderMySQLRepository(db.DB)
+	contractDocumentRepo := infraRepo.NewContractDocumentMySQLRepository(db.DB)
+	contractRenewalRepo := infraRepo.NewContractRenewalMySQLRepository(db.DB)
+	contractFinancialRepo := infraRepo.NewContractFinancialMySQLRepository(db.DB, db.Reader())
+	contractBillingRepo := infraRepo.NewContractBillingMySQLRepository(db.DB)
+	contractKPIRepo := infraRepo.NewContractKPIMySQLRepository(db.DB)
 	courseRepo := infraRepo.NewCourseMySQLRepository(db.DB)
 	taskRepo := infraRepo.NewTaskMySQLRepository(db.DB)
+	taskEscalationRepo := infraRepo.NewTaskEscalationMySQLRepository(db.DB)
 	teamRepo := infraRepo.NewTeamMySQLRepository(db.DB)
+	teamShiftRepo := infraRepo.NewTeamShiftMySQLRepository(db.DB)
 	agendaRepo := infraRepo.NewAgendaMySQLRepository(db.DB)
 	inspectionRepo := infraRepo.NewInspectionMySQLRepository(db.DB)
 	userRepo := infraRepo.NewUserMySQLRepository(db.DB)
+	refreshTokenRepo := infraRepo.NewRefreshTokenMySQLRepository(db.DB)
 	settingRepo := infraRepo.NewSettingMySQLRepository(db.DB)
+	featureFlagRepo := infraRepo.NewFeatureFlagMySQLRepository(db.DB)
+	systemImageRepo := infraRepo.NewSystemImageMySQLRepository(db.DB)
+	aiUsageRepo := infraRepo.NewAIUsageMySQLRepository(db.DB, db.Reader())
+	apiRequestRepo := infraRepo.NewAPIRequestMySQLRepository(db.DB, db.Reader())
+	webhookDeliveryRepo := infraRepo.NewWebhookDeliveryMySQLRepository(db.DB, db.Reader())
+	activityLogRepo := infraRepo.NewActivityLogMySQLRepository(db.DB, db.Reader())
+	legacyUsageRepo := infraRepo.NewLegacyUsageMySQLRepository(db.DB, db.Reader())
 	paymentRepo := infraRepo.NewPaymentMySQLRepository(db.DB)
+	paymentConfirmationRepo := infraRepo.NewPaymentConfirmationMySQLRepository(db.DB)
+	evidenceRepo := infraRepo.NewEvidenceMySQLRepository(db.DB)
 	paymentTxnRepo := infraRepo.NewPaymentTransactionMySQLRepository(db.DB)
 	couponRepo := infraRepo.NewCouponMySQLRepository(db.DB)
+	enrollmentTransferRepo := infraRepo.NewEnrollmentTransferMySQLRepository(db.DB)
+	enrollmentRenewalRepo := infraRepo.NewEnrollmentRenewalMySQLRepository(db.DB)
+	enrollmentCancellationRepo := infraRepo.NewEnrollmentCancellationMySQLRepository(db.DB)
+	gatewayCustomerRepo := infraRepo.NewGatewayCustomerMySQLRepository(db.DB)
+	checkoutSessionRepo := infraRepo.NewCheckoutSessionMySQLRepository(db.DB)
 
-	// Get active gateway for use cases
-	activeGw := gatewayFactory.GetActive()
+	// Use cases follow the default gateway as it is switched through the settings
+	activeGw := gatewayFactory.Default()
+
+	// AI providers, tried in the configured order with fallback
+	geminiProvider := gemini.NewProvider(cfg.GeminiAPIKey, "")
+	openaiProvider := openai.NewProvider(cfg.OpenAIAPIKey, "")
+	anthropicProvider := anthropic.NewProvider(cfg.AnthropicAPIKey, "")
+	aiUC := assistant.NewUseCase(aiUsageRepo, cfg.AIProviders, geminiProvider, openaiProvider, anthropicProvider)
 
 	// Initialize use cases
 	gestorUC := gestor.NewUseCase(gestorRepo)
 	contratoUC := contrato.NewUseCase(contratoRepo, gestorRepo)
-	auditUC := audit.NewUseCase(auditRepo, auditItemRepo, contratoRepo, db)
+	auditUC := audit.NewUseCase(auditRepo, auditItemRepo, contratoRepo, aiUC, db)
 	auditCategoryUC := audit.NewCategoryUseCase(auditCategoryRepo)
-	matriculaUC := matricula.NewUseCase(matriculaRepo)
-	paymentUC := payment.NewUseCase(activeGw, paymentRepo, cfg)
-	checkoutUC := checkout.NewUseCase(activeGw, matriculaRepo, paymentRepo, couponRepo, paymentTxnRepo, db, cfg)
+	splitRuleUC := revenue.NewSplitRuleUseCase(splitRuleRepo, courseRepo, userRepo, db, cfg)
+	matriculaUC := matricula.NewUseCase(matriculaRepo, courseRepo, revenueSplitRepo, splitRuleUC, enrollmentTransferRepo, ledgerRepo, db)
+	// Unread counts are pushed to connected clients; the hub lets them go at shutdown
+	notificationHub := realtime.NewHub()
+	lc.OnShutdown("notification hub", func(context.Context) error {
+		notificationHub.Close()
+		return nil
+	})
+	// E-mails carry a signed tracking pixel when the public URL of the API is set
+	var openTracker *notification.OpenTracker
+	if cfg.NotificationTrackingURL != "" {
+		openTracker = notification.NewOpenTracker(cfg.ImageURLSecret, cfg.NotificationTrackingURL)
+	}
+	// Notifications also go out by e-mail, WhatsApp and web push, on the channels configured
+	notificationDispatcher := notification.NewDispatcher(notificationDeliveryRepo, pushSubscriptionRepo, notificacaoRepo, userRepo,
+		notification.DispatcherConfig{
+			MaxAttempts: cfg.NotificationMaxAttempts,
+			BaseDelay:   time.Duration(cfg.NotificationRetryBaseDelay) * time.Second,
+			MaxDelay:    time.Hour,
+		}, notificationChannels(cfg, pushSubscriptionRepo, openTracker)...)
+	notificationDispatcher.Start(lc, time.Duration(cfg.NotificationDispatchInterval)*time.Second)
+	notificationUC := notification.NewUseCase(notificacaoRepo, notificationHub, notificationDispatcher)
+	broadcastUC := broadcast.NewUseCase(notificationBroadcastRepo, userRepo, notificationUC)
+	paymentUC := payment.NewUseCase(activeGw, paymentRepo, paymentTxnRepo, matriculaRepo, enrollmentRenewalRepo, notificationUC, paymentConfirmationRepo, storageService, db, cfg)
+	paymentUC.StartPixExpirationWorker(lc, time.Duration(cfg.PixExpirationCheckMinutes)*time.Minute)
+	// New checkout charges move to the fallback gateway when the default one fails them
+	checkoutUC := checkout.NewUseCase(gatewayFactory.Fallback(), matriculaRepo, paymentRepo, couponRepo, paymentTxnRepo, courseRepo, enrollmentRenewalRepo, enrollmentCancellationRepo, revenueSplitRepo, ledgerRepo, gatewayCustomerRepo, checkoutSessionRepo, db, cfg)
+	checkoutUC.StartCustomerBackfill(lc)
+	checkoutUC.StartSessionCleanup(lc)
+	catalogUC := catalog.NewUseCase(courseRepo, userRepo, courseRatingRepo, checkoutUC, time.Duration(cfg.CatalogCacheTTL)*time.Second)
 	couponUC := coupon.NewUseCase(couponRepo)
-	certificadoUC := certificado.NewUseCase(certificadoRepo, matriculaRepo)
-	revenueUC := revenue.NewUseCase(revenueSplitRepo)
-	supplierUC := supplier.NewUseCase(supplierRepo)
+	certificadoUC := certificado.NewUseCase(certificadoRepo, matriculaRepo, courseRepo, storageService, cfg)
+	studentPortalUC := studentportal.NewUseCase(matriculaRepo, paymentRepo, certificadoRepo, courseRatingRepo, activeGw)
+	statementUC := statement.NewUseCase(matriculaRepo, paymentRepo, enrollmentTransferRepo)
+	revenueUC := revenue.NewUseCase(revenueSplitRepo, ledgerRepo)
+	payoutUC := payout.NewUseCase(payoutBatchRepo, userRepo, payoutAccountRepo, ledgerRepo, storageService, db, cfg)
+	transferUC := payout.NewTransferUseCase(revenueSplitRepo, payoutAccountRepo, userRepo, ledgerRepo, asaasAdapter, cfg)
+	instructorPortalUC := instructorportal.NewUseCase(courseRepo, matriculaRepo, revenueUC, payoutUC, transferUC)
+	accountingUC := accounting.NewUseCase(accountingRepo, storageService, db, cfg)
+	ledgerUC := ledger.NewUseCase(ledgerRepo, userRepo)
+	splitAdjustmentUC := splitadjustment.NewUseCase(splitAdjustmentRepo, splitDisputeRepo, revenueSplitRepo, ledgerRepo, db)
+	supplierUC := supplier.NewUseCase(supplierRepo, supplierEvaluationRepo, supplierContractRepo, contratoRepo, supplierDocumentRepo)
+	searchUC := search.NewUseCase(searchRepo)
+	complianceUC := compliance.NewUseCase(complianceRepo)
+	webhookHealthUC := webhookhealth.NewUseCase(webhookDeliveryRepo, gatewayFactory.ListRegistered)
+	activityLogUC := activitylog.NewUseCase(activityLogRepo)
+	purchaseOrderUC := purchaseorder.NewUseCase(purchaseOrderRepo, supplierRepo, contratoRepo, db, cfg)
+	contractDocumentUC := contractdocument.NewUseCase(contractDocumentRepo, contratoRepo, teamRepo, storageService, db, cfg)
+	evidenceUC := evidence.NewUseCase(evidenceRepo, auditRepo, inspectionRepo, taskRepo, storageService, cfg)
+	contractRenewalUC := contractrenewal.NewUseCase(contractRenewalRepo, contratoRepo, teamRepo, userRepo, notificationUC, db, cfg)
+	contractRenewalUC.StartAlertScheduler(lc, time.Duration(cfg.ContractRenewalCheckHours)*time.Hour)
+	accountingUC.StartCloseWorker(lc, 15*time.Second)
+	contractFinanceUC := contractfinance.NewUseCase(contractFinancialRepo, contratoRepo, taskRepo)
+	contractBillingUC := contractbilling.NewUseCase(contractBillingRepo, contratoRepo, activeGw, cfg)
+	contractBillingUC.StartBillingScheduler(lc, time.Duration(cfg.ContractBillingCheckHours)*time.Hour)
+	contractKPIUC := contractkpi.NewUseCase(contractKPIRepo, contratoRepo)
+	contractKPIUC.StartSnapshotScheduler(lc, time.Duration(cfg.ContractKPISnapshotHours)*time.Hour)
 	courseUC := course.NewUseCase(courseRepo)
-	taskUC := task.NewUseCase(taskRepo, contratoRepo, gestorRepo)
-	teamUC := team.NewUseCase(teamRepo, gestorRepo, contratoRepo)
+	taskUC := task.NewUseCase(taskRepo, contratoRepo, gestorRepo, notificationUC)
+	taskEscalationUC := taskescalation.NewUseCase(taskEscalationRepo, userRepo, notificationUC, cfg)
+	taskEscalationUC.StartEscalationScheduler(lc, time.Duration(cfg.TaskEscalationCheckHours)*time.Hour)
+	taskSuggestionUC := task.NewSuggestionUseCase(auditRepo, auditItemRepo, inspectionRepo, aiUC)
+	teamUC := team.NewUseCase(teamRepo, gestorRepo, contratoRepo, teamShiftRepo, agendaRepo)
 	agendaUC := agenda.NewUseCase(agendaRepo, contratoRepo, gestorRepo)
 	inspectionUC := inspection.NewUseCase(inspectionRepo, contratoRepo, gestorRepo)
 
 	// Initialize JWT manager
 	jwtManager := auth.NewJWTManager(cfg.JWTSecret, cfg.JWTExpiration)
-	jwtManager.StartBlacklistCleanup(10 * time.Minute) // Clean expired tokens every 10 min
-	authUC := authUseCase.NewUseCase(userRepo, jwtManager)
-	settingUC := setting.NewUseCase(settingRepo)
+	lc.Every("jwt blacklist cleanup", 10*time.Minute, false, func(context.Context) { // Clean expired tokens every 10 min
+		jwtManager.PurgeBlacklist()
+	})
+	lc.Every("refresh token cleanup", time.Hour, false, func(ctx context.Context) {
+		if _, err := refreshTokenRepo.DeleteExpired(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to delete expired refresh tokens: %v", err)
+		}
+	})
+	authUC := authUseCase.NewUseCase(userRepo, refreshTokenRepo, jwtManager, time.Duration(cfg.RefreshTokenExpiration)*24*time.Hour)
+	redisClient := newRedisClient(cfg, lc)
+	appCache := cacheStore(cfg, redisClient)
+	settingUC := setting.NewUseCase(settingRepo, contratoRepo, settingsSecretBox(cfg), appCache, time.Duration(cfg.SettingsCacheTTL)*time.Second)
+	featureFlagUC := featureflag.NewUseCase(featureFlagRepo)
+	systemImageUC := systemimage.NewUseCase(systemImageRepo)
+
+	// Credentials stored in settings override the environment and are swapped in on change
+	settingUC.Subscribe("asaas_api_key", asaasClient.SetAPIKey)
+	settingUC.Subscribe("gemini_api_key", geminiProvider.SetAPIKey)
+	settingUC.Subscribe("openai_api_key", openaiProvider.SetAPIKey)
+	settingUC.Subscribe("anthropic_api_key", anthropicProvider.SetAPIKey)
+	settingUC.Subscribe("ai_providers", aiUC.SetOrder)
+	settingUC.Subscribe("checkout_fee_pass_through", checkoutUC.SetFeePassThrough)
+	newGatewayReloader(cfg, settingUC, gatewayFactory, asaasClient, mpClient, gatewayHTTP).watch()
+
+	// IP allowlists for the admin-sensitive routes, edited through the settings
+	usersAllowlist := middleware.NewIPAllowlist("users")
+	settingsAllowlist := middleware.NewIPAllowlist("settings")
+	reconcileAllowlist := middleware.NewIPAllowlist("reconcile")
+	settingUC.AddValidator("ip_allowlist_users", middleware.ValidateIPAllowlist)
+	settingUC.AddValidator("ip_allowlist_settings", middleware.ValidateIPAllowlist)
+	settingUC.AddValidator("ip_allowlist_reconcile", middleware.ValidateIPAllowlist)
+	settingUC.Subscribe("ip_allowlist_users", usersAllowlist.Set)
+	settingUC.Subscribe("ip_allowlist_settings", settingsAllowlist.Set)
+	settingUC.Subscribe("ip_allowlist_reconcile", reconcileAllowlist.Set)
+
+	// Accepted file types and sizes per upload context, edited through the settings
+	uploadPolicies := storage.NewUploadPolicies(cfg.MaxUploadSize)
+	for _, uploadContext := range storage.UploadContexts {
+		key := storage.UploadPolicySettingKey(uploadContext)
+		settingUC.AddValidator(key, uploadPolicies.Validator(uploadContext))
+		settingUC.Subscribe(key, uploadPolicies.Setter(uploadContext))
+	}
+	if n, err := settingUC.EncryptStoredSecrets(context.Background()); err == nil && n > 0 {
+		log.Printf("Encrypted %d secret settings stored in plaintext", n)
+	}
+	settingUC.ApplyStoredValues(context.Background())
+
+	// Reconciliation replays missed webhooks through the webhook handlers
+	webhookHandler := handler.NewWebhookHandler(cfg, db, matriculaRepo, paymentRepo, paymentTxnRepo, revenueSplitRepo, enrollmentRenewalRepo, ledgerRepo, webhookEventRepo, gatewayFactory, transferUC, splitRuleUC, contractBillingUC, notificationUC)
+	paymentUC.SetEventApplier(webhookHandler)
 
 	// Initialize handlers
 	return &Router{
 		cfg:                  cfg,
 		db:                   db,
 		storage:              storageService,
-		healthHandler:        handler.NewHealthHandler(db),
+		lifecycle:            lc,
+		rateLimits:           rateLimitStore(cfg, redisClient),
+		healthHandler:        handler.NewHealthHandler(db, gatewayHTTP),
+		errorCatalogHandler:  handler.NewErrorCatalogHandler(),
 		gestorHandler:        handler.NewGestorHandler(gestorUC),
 		contratoHandler:      handler.NewContratoHandler(contratoUC),
 		auditHandler:         handler.NewAuditHandler(auditUC),
 		auditCategoryHandler: handler.NewAuditCategoryHandler(auditCategoryUC),
 		matriculaHandler:     handler.NewMatriculaHandler(matriculaUC),
-		paymentHandler:       handler.NewPaymentHandler(paymentUC, matriculaRepo),
+		paymentHandler:       handler.NewPaymentHandler(paymentUC, matriculaRepo, splitRuleUC, uploadPolicies),
 		checkoutHandler:      handler.NewCheckoutHandler(checkoutUC),
-		webhookHandler:       handler.NewWebhookHandler(cfg, db, matriculaRepo, paymentRepo, paymentTxnRepo, revenueSplitRepo, gatewayFactory),
+		webhookHandler:       webhookHandler,
+		webhookHealthHandler: handler.NewWebhookHealthHandler(webhookHealthUC),
+		webhookDeliveryRepo:  webhookDeliveryRepo,
 		certificadoHandler:   handler.NewCertificadoHandler(certificadoUC),
-		imageHandler:         handler.NewImageHandler(cfg),
-		portalHandler:        handler.NewPortalHandler(storageService, cfg),
-		notificationHandler:  handler.NewNotificationHandler(notificacaoRepo),
-		statsHandler:         handler.NewStatsHandler(db.DB, matriculaRepo, auditRepo, contratoRepo, gestorRepo),
-		revenueHandler:       handler.NewRevenueHandler(revenueUC),
+		imageHandler:         handler.NewImageHandler(storageService, uploadPolicies, cfg),
+		portalHandler:        handler.NewPortalHandler(storageService, aiUC, evidenceUC, systemImageUC, uploadPolicies, cfg),
+		studentPortalHandler: handler.NewStudentPortalHandler(studentPortalUC, notificationUC),
+		instructorPortalHandler: handler.NewInstructorPortalHandler(instructorPortalUC),
+		studentStatementHandler: handler.NewStudentStatementHandler(statementUC),
+		notificationHandler:  handler.NewNotificationHandler(notificationUC, openTracker),
+		notificationBroadcastHandler: handler.NewNotificationBroadcastHandler(broadcastUC),
+		catalogHandler:       handler.NewCatalogHandler(catalogUC, time.Duration(cfg.CatalogCacheTTL)*time.Second),
+		statsHandler:         handler.NewStatsHandler(db.Reader(), matriculaRepo, auditRepo, contratoRepo, gestorRepo, appCache, time.Duration(cfg.StatsCacheTTL)*time.Second),
+		revenueHandler:       handler.NewRevenueHandler(revenueUC, splitRuleUC),
+		payoutHandler:        handler.NewPayoutHandler(payoutUC, transferUC, uploadPolicies, cfg),
+		accountingHandler:    handler.NewAccountingHandler(accountingUC),
+		ledgerHandler:        handler.NewLedgerHandler(ledgerUC),
+		splitAdjustmentHandler: handler.NewSplitAdjustmentHandler(splitAdjustmentUC),
 		supplierHandler:      handler.NewSupplierHandler(supplierUC),
+		searchHandler:        handler.NewSearchHandler(searchUC),
+		complianceHandler:    handler.NewComplianceHandler(complianceUC),
+		purchaseOrderHandler: handler.NewPurchaseOrderHandler(purchaseOrderUC),
+		contractDocumentHandler: handler.NewContractDocumentHandler(contractDocumentUC, uploadPolicies, cfg),
+		contractRenewalHandler:  handler.NewContractRenewalHandler(contractRenewalUC),
+		contractFinanceHandler:  handler.NewContractFinanceHandler(contractFinanceUC),
+		contractBillingHandler:  handler.NewContractBillingHandler(contractBillingUC),
+		contractKPIHandler:      handler.NewContractKPIHandler(contractKPIUC),
 		courseHandler:        handler.NewCourseHandler(courseUC),
-		taskHandler:          handler.NewTaskHandler(taskUC),
+		taskHandler:          handler.NewTaskHandler(taskUC, taskSuggestionUC),
+		taskEscalationHandler: handler.NewTaskEscalationHandler(taskEscalationUC),
 		teamHandler:       handler.NewTeamHandler(teamUC),
 		agendaHandler:     handler.NewAgendaHandler(agendaUC),
 		inspectionHandler: handler.NewInspectionHandler(inspectionUC),
 		couponHandler:     handler.NewCouponHandler(couponUC),
 		authHandler:       handler.NewAuthHandler(authUC, jwtManager),
 		settingHandler:    handler.NewSettingHandler(settingUC),
+		featureFlagHandler: handler.NewFeatureFlagHandler(featureFlagUC),
+		systemImageHandler: handler.NewSystemImageHandler(systemImageUC),
+		gatewayHandler:    handler.NewGatewayHandler(gatewayFactory),
+		aiHandler:         handler.NewAIHandler(aiUC),
+		apiRequestHandler: handler.NewAPIRequestHandler(apiRequestRepo),
+		apiRequestRepo:    apiRequestRepo,
+		activityLogHandler: handler.NewActivityLogHandler(activityLogUC),
+		activityLogUC:     activityLogUC,
+		legacyUsageHandler: handler.NewLegacyUsageHandler(legacyUsageRepo, legacySuccessors),
+		legacyUsageRepo:   legacyUsageRepo,
+		usersAllowlist:    usersAllowlist,
+		resourceVersions:  infraRepo.NewResourceVersionMySQLRepository(db.DB),
+		idempotencyRepo:   infraRepo.NewIdempotencyMySQLRepository(db.DB),
+		settingsAllowlist: settingsAllowlist,
+		reconcileAllowlist: reconcileAllowlist,
 		jwtManager:        jwtManager,
+		contractAccess:    middleware.NewContractAccess(teamRepo, gestorRepo, contratoRepo, auditRepo, inspectionRepo, taskRepo, purchaseOrderRepo, supplierContractRepo),
+		featureFlags:      featureFlagUC,
+	}
+}
+
+// conditional answers GET requests with 304 Not Modified while the tables are unchanged
+func (r *Router) conditional(tables ...string) gin.HandlerFunc {
+	return middleware.ConditionalGET(r.resourceVersions, tables...)
+}
+
+// notificationChannels returns the outbound notification channels whose credentials are set;
+// e-mails carry the tracking pixel of tracker unless it is nil
+func notificationChannels(cfg *config.Config, pushSubs repository.PushSubscriptionRepository, tracker *notification.OpenTracker) []notify.Channel {
+	var channels []notify.Channel
+	if cfg.SMTPHost != "" {
+		smtpCfg := smtp.Config{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUser,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		}
+		if tracker != nil {
+			smtpCfg.PixelURL = tracker.URL
+		}
+		channels = append(channels, smtp.NewChannel(smtpCfg))
+	}
+	if cfg.TwilioAccountSID != "" && cfg.TwilioWhatsAppFrom != "" {
+		channels = append(channels, twilio.NewChannel(twilio.Config{
+			AccountSID: cfg.TwilioAccountSID,
+			AuthToken:  cfg.TwilioAuthToken,
+			From:       cfg.TwilioWhatsAppFrom,
+		}))
 	}
+	if cfg.VAPIDPrivateKey != "" {
+		push, err := webpush.NewChannel(cfg.VAPIDPrivateKey, cfg.VAPIDSubject, pushSubs)
+		if err != nil {
+			log.Printf("Warning: Invalid VAPID_PRIVATE_KEY (%v); web push notifications are disabled", err)
+		} else {
+			channels = append(channels, push)
+		}
+	}
+	return channels
+}
+
+// newRedisClient connects to the Redis at REDIS_URL when the rate limits or the cache are
+// kept there, and returns nil otherwise. Redis calls get short timeouts unless REDIS_URL sets
+// them, so a slow Redis does not slow every request.
+func newRedisClient(cfg *config.Config, lc *lifecycle.Manager) redis.UniversalClient {
+	if cfg.RateLimitStore != "redis" && cfg.CacheStore != "redis" {
+		return nil
+	}
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		log.Printf("Warning: Invalid REDIS_URL (%v); rate limits and cache are kept per instance", err)
+		return nil
+	}
+	if opts.DialTimeout == 0 {
+		opts.DialTimeout = time.Second
+	}
+	if opts.ReadTimeout == 0 {
+		opts.ReadTimeout = 200 * time.Millisecond
+	}
+	if opts.WriteTimeout == 0 {
+		opts.WriteTimeout = 200 * time.Millisecond
+	}
+	client := redis.NewClient(opts)
+	lc.OnShutdown("redis", func(context.Context) error {
+		return client.Close()
+	})
+	return client
+}
+
+// rateLimitStore returns where the rate limit buckets are kept: in Redis, shared by the
+// replicas, with RATE_LIMIT_STORE=redis, in the memory of the instance otherwise
+func rateLimitStore(cfg *config.Config, client redis.UniversalClient) ratelimit.Store {
+	if cfg.RateLimitStore != "redis" || client == nil {
+		return ratelimit.NewMemoryStore()
+	}
+	return ratelimit.NewRedisStore(client, "ratelimit:")
+}
+
+// cacheStore returns where the cached stats and settings are kept: in Redis, shared by the
+// replicas, with CACHE_STORE=redis, in the memory of the instance otherwise
+func cacheStore(cfg *config.Config, client redis.UniversalClient) cache.Cache {
+	if cfg.CacheStore != "redis" || client == nil {
+		return cache.NewMemory()
+	}
+	return cache.NewRedis(client, "cache:")
+}
+
+// settingsSecretBox builds the cipher for secret settings from SETTINGS_MASTER_KEY.
+// Without a valid key, secret settings can still be listed (masked) but not written.
+func settingsSecretBox(cfg *config.Config) *secretbox.Box {
+	if cfg.SettingsMasterKey == "" {
+		log.Printf("Warning: SETTINGS_MASTER_KEY not set; secret settings cannot be stored")
+		return nil
+	}
+	box, err := secretbox.New(cfg.SettingsMasterKey)
+	if err != nil {
+		log.Printf("Warning: Invalid SETTINGS_MASTER_KEY (%v); secret settings cannot be stored", err)
+		return nil
+	}
+	return box
 }
 
 // Setup configures the Gin router with all routes
@@ -205,61 +592,144 @@ func (r *Router) Setup() *gin.Engine {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Report binding errors by the JSON field names
+	validation.Setup()
+
 	// Create Gin engine
 	engine := gin.New()
+	// Client IPs feed the rate limits, the IP allowlists and the request log, so only proxies
+	// we run may set X-Forwarded-For; with none configured the client IP is the remote address
+	proxies, err := r.cfg.TrustedProxyList()
+	if err == nil {
+		err = engine.SetTrustedProxies(proxies)
+	}
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// Apply global middlewares
 	engine.Use(middleware.Recovery())
 	engine.Use(middleware.Logger())
-	engine.Use(middleware.CORS(r.cfg.CORSAllowedOrigins))
+	engine.Use(r.cors())
 	engine.Use(middleware.RequestID())
-	engine.Use(middleware.RateLimiter(100, time.Minute))     // 100 req/min per IP
+	engine.Use(middleware.Tracing())
+	engine.Use(middleware.Compress(r.cfg.CompressionMinSize))
+	// Per user by role, per IP without a token; health checks, gateway webhooks, signed image
+	// links and the course catalog, limited on its own, are exempt
+	engine.Use(middleware.UserRateLimiter(r.rateLimits, r.jwtManager, middleware.RateLimitTiers{
+		Admin:     r.cfg.RateLimitAdmin,
+		Gestor:    r.cfg.RateLimitGestor,
+		Student:   r.cfg.RateLimitStudent,
+		Anonymous: r.cfg.RateLimitAnonymous,
+	}, time.Minute, "/ping", "/api/v1/health", "/api/v1/webhooks/", "/api/v1/images/file/", "/api/v1/catalog/"))
 	engine.Use(middleware.MaxBodySize(r.cfg.MaxUploadSize)) // Default 50MB max body
-
-	// Serve static files (uploads)
-	engine.Static("/uploads", r.cfg.UploadDir)
+	engine.Use(middleware.RequestAudit(r.apiRequestRepo, r.lifecycle))
+	engine.Use(middleware.ActivityLog(r.activityLogUC, r.lifecycle))
 
 	// Health check routes
 	engine.GET("/ping", r.healthHandler.Ping)
 
 	// API v1 routes
 	v1 := engine.Group("/api/v1")
+	// AI endpoints share one budget: 20 req/min
+	aiLimiter := middleware.RateLimiter(r.rateLimits, "ai", 20, time.Minute)
+	// Create endpoints retried by mobile clients replay the first response per Idempotency-Key
+	idempotent := middleware.Idempotency(r.idempotencyRepo, r.lifecycle)
+	// Permission checks shared by several routes (see entity.RolePermission)
+	auditsRead := middleware.RequirePermission(entity.ResourceAudits, entity.ActionRead)
+	tasksRead := middleware.RequirePermission(entity.ResourceTasks, entity.ActionRead)
+	settingsRead := middleware.RequirePermission(entity.ResourceSettings, entity.ActionRead)
+	settingsUpdate := middleware.RequirePermission(entity.ResourceSettings, entity.ActionUpdate)
 	{
 		// Health
 		v1.GET("/health", r.healthHandler.HealthCheck)
 
+		// Error code catalog
+		v1.GET("/errors", r.errorCatalogHandler.ListErrorCodes)
+
 		// Gestores (protected)
 		gestores := v1.Group("/gestores")
 		gestores.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
-			gestores.GET("", r.gestorHandler.ListGestores)
-			gestores.GET("/:id", r.gestorHandler.GetGestorByID)
-			gestores.POST("", r.gestorHandler.CreateGestor)
-			gestores.PUT("/:id", r.gestorHandler.UpdateGestor)
-			gestores.DELETE("/:id", r.gestorHandler.DeleteGestor)
+			gestores.GET("", middleware.RequirePermission(entity.ResourceGestores, entity.ActionRead), r.gestorHandler.ListGestores)
+			gestores.GET("/trash", middleware.RequireRole("admin"), r.gestorHandler.ListDeletedGestores)
+			gestores.GET("/:id", middleware.RequirePermission(entity.ResourceGestores, entity.ActionRead), r.gestorHandler.GetGestorByID)
+			gestores.GET("/:id/metrics", middleware.RequirePermission(entity.ResourceGestores, entity.ActionRead), r.gestorHandler.GetGestorMetrics)
+			gestores.POST("", middleware.RequirePermission(entity.ResourceGestores, entity.ActionCreate), r.gestorHandler.CreateGestor)
+			gestores.PUT("/:id", middleware.RequirePermission(entity.ResourceGestores, entity.ActionUpdate, r.contractAccess.GestorUser("id")), r.gestorHandler.UpdateGestor)
+			gestores.DELETE("/:id", middleware.RequirePermission(entity.ResourceGestores, entity.ActionDelete), r.gestorHandler.DeleteGestor)
+			gestores.POST("/:id/restore", middleware.RequireRole("admin"), r.gestorHandler.RestoreGestor)
+			gestores.POST("/:id/reassign", middleware.RequireRole("admin"), r.gestorHandler.ReassignGestor)
 		}
 
 		// Contratos (protected)
 		contratos := v1.Group("/contratos")
 		contratos.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
-			contratos.GET("", r.contratoHandler.ListContratos)
-			contratos.GET("/:id", r.contratoHandler.GetContratoByID)
-			contratos.POST("", r.contratoHandler.CreateContrato)
-			contratos.PUT("/:id", r.contratoHandler.UpdateContrato)
-			contratos.DELETE("/:id", r.contratoHandler.DeleteContrato)
+			contratos.GET("", middleware.RequirePermission(entity.ResourceContratos, entity.ActionRead), r.contratoHandler.ListContratos)
+			contratos.GET("/renewals", middleware.RequirePermission(entity.ResourceContratos, entity.ActionRead), r.contractRenewalHandler.ListRenewals)
+			contratos.GET("/renewals/upcoming", middleware.RequirePermission(entity.ResourceContratos, entity.ActionRead), r.contractRenewalHandler.ListUpcoming)
+			contratos.POST("/renewals/alerts/run", middleware.RequireRole("admin"), r.contractRenewalHandler.RunAlerts)
+			contratos.GET("/trash", middleware.RequireRole("admin"), r.contratoHandler.ListDeletedContratos)
+			contratos.POST("/kpis/snapshot", middleware.RequireRole("admin"), r.contractKPIHandler.RunSnapshot)
+			contratos.GET("/:id", middleware.RequirePermission(entity.ResourceContratos, entity.ActionRead), r.contratoHandler.GetContratoByID)
+			contratos.POST("", middleware.RequirePermission(entity.ResourceContratos, entity.ActionCreate, r.contractAccess.GestorFromBody("gestor_id")), r.contratoHandler.CreateContrato)
+			contratos.PUT("/:id",
+				middleware.RequirePermission(entity.ResourceContratos, entity.ActionUpdate, r.contractAccess.ContractGestor("id"), r.contractAccess.ContractNewGestor("id", "gestor_id")),
+				r.contratoHandler.UpdateContrato)
+			contratos.DELETE("/:id", middleware.RequirePermission(entity.ResourceContratos, entity.ActionDelete, r.contractAccess.ContractGestor("id")), r.contratoHandler.DeleteContrato)
+			contratos.POST("/:id/restore", middleware.RequireRole("admin"), r.contratoHandler.RestoreContrato)
+			contratos.GET("/:id/suppliers", r.supplierHandler.ListContractSuppliers)
+			contratos.GET("/:id/documents", r.contractDocumentHandler.ListDocuments)
+			contratos.POST("/:id/documents", r.contractDocumentHandler.CreateDocument)
+			contratos.GET("/:id/documents/:docId", r.contractDocumentHandler.GetDocument)
+			contratos.DELETE("/:id/documents/:docId", r.contractDocumentHandler.ArchiveDocument)
+			contratos.POST("/:id/documents/:docId/versions", r.contractDocumentHandler.AddVersion)
+			contratos.GET("/:id/documents/:docId/download", r.contractDocumentHandler.DownloadDocument)
+			contratos.GET("/:id/renewals", middleware.RequirePermission(entity.ResourceContratos, entity.ActionRead), r.contractAccess.Require(entity.TeamActionView, middleware.ContractFromParam("id")), r.contractRenewalHandler.ListContractRenewals)
+			// Renewing rewrites the end date of the contract, so it is guarded like PUT /contratos/:id
+			contratos.POST("/:id/renewals", middleware.RequirePermission(entity.ResourceContratos, entity.ActionUpdate, r.contractAccess.ContractGestor("id")), r.contractRenewalHandler.CreateRenewal)
+			contratos.PUT("/:id/renewals/:renewalId", middleware.RequirePermission(entity.ResourceContratos, entity.ActionUpdate, r.contractAccess.ContractGestor("id")), r.contractRenewalHandler.UpdateRenewal)
+			contratos.GET("/:id/budget-lines", middleware.RequireRole("admin", "gestor"), r.contractFinanceHandler.ListBudgetLines)
+			contratos.POST("/:id/budget-lines", middleware.RequireRole("admin", "gestor"), r.contractFinanceHandler.CreateBudgetLine)
+			contratos.PUT("/:id/budget-lines/:lineId", middleware.RequireRole("admin", "gestor"), r.contractFinanceHandler.UpdateBudgetLine)
+			contratos.DELETE("/:id/budget-lines/:lineId", middleware.RequireRole("admin", "gestor"), r.contractFinanceHandler.DeleteBudgetLine)
+			contratos.GET("/:id/costs", middleware.RequireRole("admin", "gestor", "supervisor"), r.contractFinanceHandler.ListCosts)
+			contratos.POST("/:id/costs", middleware.RequireRole("admin", "gestor", "supervisor"), r.contractFinanceHandler.RecordCost)
+			contratos.DELETE("/:id/costs/:costId", middleware.RequireRole("admin", "gestor"), r.contractFinanceHandler.DeleteCost)
+			contratos.GET("/:id/financials", middleware.RequireRole("admin", "gestor"), r.contractFinanceHandler.GetFinancialReport)
+			contratos.GET("/:id/kpis/history", middleware.RequirePermission(entity.ResourceContratos, entity.ActionRead), r.contractAccess.Require(entity.TeamActionView, middleware.ContractFromParam("id")), r.contractKPIHandler.GetHistory)
+			contratos.GET("/:id/billing", middleware.RequireRole("admin", "gestor"), r.contractBillingHandler.GetPlan)
+			contratos.PUT("/:id/billing", middleware.RequireRole("admin"), r.contractBillingHandler.SavePlan)
+			contratos.GET("/:id/billing/charges", middleware.RequireRole("admin", "gestor"), r.contractBillingHandler.ListContractCharges)
+			contratos.POST("/:id/billing/charges", middleware.RequireRole("admin"), idempotent, r.contractBillingHandler.CreateCharge)
+			contratos.POST("/:id/billing/charges/:chargeId/cancel", middleware.RequireRole("admin"), r.contractBillingHandler.CancelCharge)
+		}
+
+		// Contract billing across contracts (admin)
+		contractBilling := v1.Group("/contract-billing")
+		contractBilling.Use(middleware.AuthMiddleware(r.jwtManager))
+		contractBilling.Use(middleware.RequireRole("admin"))
+		{
+			contractBilling.GET("/charges", r.contractBillingHandler.ListCharges)
+			contractBilling.GET("/receivables", r.contractBillingHandler.GetReceivables)
+			contractBilling.POST("/run", r.contractBillingHandler.RunBilling)
 		}
 
 		// Audits (protected)
 		audits := v1.Group("/audits")
 		audits.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
-			audits.GET("", r.auditHandler.ListAudits)
-			audits.GET("/meta", r.auditHandler.GetAuditMeta)
-			audits.GET("/:id", r.auditHandler.GetAuditByID)
-			audits.POST("", r.auditHandler.CreateAudit)
-			audits.PUT("/:id", r.auditHandler.UpdateAudit)
-			audits.DELETE("/:id", r.auditHandler.DeleteAudit)
+			audits.GET("", auditsRead, r.conditional("audits"), r.auditHandler.ListAudits)
+			audits.GET("/meta", auditsRead, r.auditHandler.GetAuditMeta)
+			audits.GET("/data-templates", auditsRead, r.auditHandler.ListDataTemplates)
+			audits.POST("/data-templates/migrate", middleware.RequireRole("admin"), r.auditHandler.MigrateDataJSON)
+			audits.GET("/:id", auditsRead, r.contractAccess.Require(entity.TeamActionView, r.contractAccess.AuditContract("id")), r.conditional("audits", "audit_items"), r.auditHandler.GetAuditByID)
+			audits.POST("", middleware.RequirePermission(entity.ResourceAudits, entity.ActionCreate), r.contractAccess.Require(entity.TeamActionManageAudits, middleware.ContractFromBody("contract_id")), idempotent, r.auditHandler.CreateAudit)
+			audits.PUT("/:id", middleware.RequirePermission(entity.ResourceAudits, entity.ActionUpdate), r.contractAccess.Require(entity.TeamActionManageAudits, r.contractAccess.AuditContract("id")), r.auditHandler.UpdateAudit)
+			audits.DELETE("/:id", middleware.RequirePermission(entity.ResourceAudits, entity.ActionDelete), r.contractAccess.Require(entity.TeamActionManageAudits, r.contractAccess.AuditContract("id")), r.auditHandler.DeleteAudit)
+			audits.POST("/:id/ai-summary", aiLimiter, auditsRead, r.contractAccess.Require(entity.TeamActionView, r.contractAccess.AuditContract("id")), r.auditHandler.SummarizeAudit)
+			audits.POST("/:id/task-suggestions", aiLimiter, middleware.RequirePermission(entity.ResourceTasks, entity.ActionCreate), r.contractAccess.Require(entity.TeamActionManageTasks, r.contractAccess.AuditContract("id")), r.taskHandler.SuggestFromAudit)
 		}
 
 		// Audit Categories (protected)
@@ -277,39 +747,79 @@ func (r *Router) Setup() *gin.Engine {
 		enrollments := v1.Group("/enrollments")
 		enrollments.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
-			enrollments.GET("", r.matriculaHandler.ListEnrollments)
-			enrollments.GET("/:id", r.matriculaHandler.GetEnrollmentByID)
+			enrollments.GET("", r.conditional("enrollments"), r.matriculaHandler.ListEnrollments)
+			enrollments.GET("/search", middleware.RequireRole("admin"), r.matriculaHandler.SearchEnrollments)
+			enrollments.GET("/:id", r.conditional("enrollments"), r.matriculaHandler.GetEnrollmentByID)
 			enrollments.POST("", r.matriculaHandler.CreateEnrollment)
+			enrollments.POST("/bulk", middleware.RequireRole("admin"), r.matriculaHandler.BulkCreateEnrollments)
 			enrollments.PATCH("/:id/payment-status", r.matriculaHandler.UpdatePaymentStatus)
 			enrollments.PATCH("/:id/progress", r.matriculaHandler.UpdateProgress)
+			enrollments.POST("/:id/transfer", middleware.RequireRole("admin"), r.matriculaHandler.TransferEnrollment)
+			enrollments.GET("/:id/transfers", r.matriculaHandler.ListTransfers)
+			enrollments.POST("/:id/renew", idempotent, r.checkoutHandler.RenewEnrollment)
+			enrollments.POST("/:id/cancel", middleware.RequireRole("admin"), r.checkoutHandler.CancelEnrollment)
+		}
+
+		// Students (protected)
+		students := v1.Group("/students")
+		students.Use(middleware.AuthMiddleware(r.jwtManager))
+		{
+			students.GET("/:id/statement", r.studentStatementHandler.GetStatement)
 		}
 
 		// Payments (protected)
 		payments := v1.Group("/payments")
 		payments.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
-			payments.GET("", r.paymentHandler.ListPayments)
+			payments.GET("", r.conditional("payments"), r.paymentHandler.ListPayments)
 			payments.GET("/enrollment/:id", r.paymentHandler.GetPaymentsByEnrollment)
 			payments.POST("/customer", r.paymentHandler.CreateCustomer)
-			payments.POST("/pix", r.paymentHandler.CreatePixPayment)
-			payments.POST("/boleto", r.paymentHandler.CreateBoletoPayment)
-			payments.POST("/card", r.paymentHandler.CreateCardPayment)
+			payments.POST("/pix", idempotent, r.paymentHandler.CreatePixPayment)
+			payments.POST("/boleto", idempotent, r.paymentHandler.CreateBoletoPayment)
+			payments.POST("/card", idempotent, r.paymentHandler.CreateCardPayment)
 			payments.GET("/:id/status", r.paymentHandler.GetPaymentStatus)
+			payments.GET("/:id/installments", r.paymentHandler.GetInstallments)
+			payments.GET("/:id/timeline", middleware.RequireRole("admin"), r.paymentHandler.GetPaymentTimeline)
+			payments.POST("/:id/boleto/reissue", middleware.RequireRole("admin"), idempotent, r.paymentHandler.ReissueBoleto)
+			payments.POST("/:id/pix/regenerate", idempotent, r.paymentHandler.RegeneratePix)
 			payments.GET("/simulate-split", r.paymentHandler.SimulateRevenueSplit)
+			payments.POST("/reconcile", middleware.RequireRole("admin"), r.reconcileAllowlist.Require(), r.paymentHandler.Reconcile)
+			payments.POST("/:id/mark-paid", middleware.RequireRole("admin"), r.paymentHandler.MarkPaid)
+			payments.GET("/:id/proof", middleware.RequireRole("admin"), r.paymentHandler.DownloadProof)
 		}
 
 		// Checkout
 		checkout := v1.Group("/checkout")
 		{
 			checkout.POST("", r.checkoutHandler.CreateCheckout)
+			checkout.GET("/methods", r.checkoutHandler.GetPaymentMethods)
 			checkout.GET("/:id/status", r.checkoutHandler.GetCheckoutStatus)
+			checkout.POST("/:id/retry", middleware.AuthMiddleware(r.jwtManager), idempotent, r.checkoutHandler.RetryCheckout)
+
+			// Embedded checkouts of third-party landing pages, authorized by the session token
+			checkout.POST("/sessions", r.checkoutHandler.CreateSession)
+			checkout.GET("/sessions/:token", r.checkoutHandler.GetSessionStatus)
+			checkout.POST("/sessions/:token/pay", r.checkoutHandler.CheckoutSession)
+		}
+
+		// Public course catalog of the marketing site, with its own per IP limit
+		catalog := v1.Group("/catalog", middleware.RateLimiter(r.rateLimits, "catalog", r.cfg.RateLimitCatalog, time.Minute))
+		{
+			catalog.GET("/courses", r.catalogHandler.ListCourses)
+			catalog.GET("/courses/:id", r.catalogHandler.GetCourse)
 		}
 
-		// Webhooks
-		webhooks := v1.Group("/webhooks")
+		// Webhooks; shutdown waits for the ones being processed
+		webhooks := v1.Group("/webhooks", middleware.InFlight(r.lifecycle, "webhook"))
 		{
-			webhooks.POST("/asaas", r.webhookHandler.HandleAsaasWebhook)
-			webhooks.POST("/mercadopago", r.webhookHandler.HandleMercadoPagoWebhook)
+			deliveries := middleware.WebhookDeliveries(r.webhookDeliveryRepo, r.lifecycle)
+			webhooks.POST("/asaas", deliveries, r.webhookHandler.HandleAsaasWebhook)
+			webhooks.POST("/mercadopago", deliveries, r.webhookHandler.HandleMercadoPagoWebhook)
+			webhooks.POST("/mock", deliveries, r.webhookHandler.HandleMockWebhook)
+
+			// Health dashboard (admin only)
+			webhooks.GET("/health", middleware.AuthMiddleware(r.jwtManager), middleware.RequireRole("admin"), r.webhookHealthHandler.GetHealth)
+			webhooks.GET("/deliveries", middleware.AuthMiddleware(r.jwtManager), middleware.RequireRole("admin"), r.webhookHealthHandler.ListDeliveries)
 		}
 
 		// Certificados
@@ -325,14 +835,57 @@ func (r *Router) Setup() *gin.Engine {
 		notifications := v1.Group("/notifications")
 		notifications.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
-			notifications.GET("", r.notificationHandler.ListNotifications)
+			notifications.GET("", r.conditional("notifications"), r.notificationHandler.ListNotifications)
 			notifications.GET("/unread", r.notificationHandler.GetUnreadNotifications)
 			notifications.GET("/count", r.notificationHandler.GetUnreadCount)
+			notifications.GET("/stream", r.notificationHandler.StreamNotifications)
 			notifications.POST("", r.notificationHandler.CreateNotification)
+			notifications.GET("/broadcasts", middleware.RequireRole("admin"), r.notificationBroadcastHandler.ListBroadcasts)
+			notifications.POST("/broadcasts", middleware.RequireRole("admin"), r.notificationBroadcastHandler.CreateBroadcast)
+			notifications.GET("/broadcasts/:id/stats", middleware.RequireRole("admin"), r.notificationBroadcastHandler.GetBroadcastStats)
+			notifications.GET("/:id/receipts", r.notificationHandler.GetReceipts)
+			notifications.POST("/:id/delivered", r.notificationHandler.ConfirmDelivery)
 			notifications.PATCH("/:id/read", r.notificationHandler.MarkAsRead)
 			notifications.PATCH("/mark-all-read", r.notificationHandler.MarkAllAsRead)
 			notifications.DELETE("/:id", r.notificationHandler.DeleteNotification)
 		}
+		// WebSocket clients send the token as a subprotocol, so it is read before authenticating
+		v1.GET("/notifications/ws", middleware.WebSocketProtocolToken(), middleware.AuthMiddleware(r.jwtManager),
+			r.notificationHandler.StreamUnreadCount)
+		// E-mail open tracking pixel (public - authorized by the signature of its link)
+		v1.GET("/notifications/:id/open.gif", r.notificationHandler.TrackOpen)
+
+		// Self-service for the logged-in student, scoped by the token subject
+		me := v1.Group("/me")
+		me.Use(middleware.AuthMiddleware(r.jwtManager))
+		{
+			me.GET("/enrollments", r.studentPortalHandler.ListEnrollments)
+			me.PUT("/enrollments/:id/rating", r.studentPortalHandler.RateCourse)
+			me.GET("/payments", r.studentPortalHandler.ListPayments)
+			me.GET("/payments/:id/charge", r.studentPortalHandler.GetPaymentCharge)
+			me.GET("/certificates", r.studentPortalHandler.ListCertificates)
+			me.GET("/notification-preferences", r.studentPortalHandler.GetNotificationPreferences)
+			me.PUT("/notification-preferences", r.studentPortalHandler.UpdateNotificationPreferences)
+			me.GET("/notification-channels", r.studentPortalHandler.GetNotificationChannels)
+			me.PUT("/notification-channels", r.studentPortalHandler.UpdateNotificationChannels)
+			me.GET("/push-subscriptions/public-key", r.studentPortalHandler.GetPushPublicKey)
+			me.POST("/push-subscriptions", r.studentPortalHandler.SubscribePush)
+			me.DELETE("/push-subscriptions/:id", r.studentPortalHandler.UnsubscribePush)
+
+			// Courses, students and earnings of the logged-in instructor
+			instructor := me.Group("/instructor")
+			instructor.Use(middleware.RequireRole("instructor"))
+			{
+				instructor.GET("/courses", r.instructorPortalHandler.ListCourses)
+				instructor.GET("/enrollments", r.instructorPortalHandler.ListEnrollments)
+				instructor.GET("/earnings", r.instructorPortalHandler.GetEarnings)
+				instructor.GET("/payouts", r.instructorPortalHandler.ListPayouts)
+				instructor.GET("/payout-account", r.instructorPortalHandler.GetPayoutAccount)
+				instructor.PUT("/payout-account", r.instructorPortalHandler.SavePayoutAccount)
+				instructor.POST("/payout-account/verify", r.instructorPortalHandler.VerifyPayoutAccount)
+				instructor.POST("/payout-account/confirm", r.instructorPortalHandler.ConfirmPayoutAccount)
+			}
+		}
 
 		// Images (protected)
 		images := v1.Group("/images")
@@ -342,6 +895,8 @@ func (r *Router) Setup() *gin.Engine {
 			images.POST("", r.imageHandler.UploadImage)
 			images.DELETE("/:filename", r.imageHandler.DeleteImage)
 		}
+		// Image files (public, authorized by the signature of the link)
+		v1.GET("/images/file/:filename", r.imageHandler.ServeImage)
 
 		// Stats/Dashboard (protected)
 		stats := v1.Group("/stats")
@@ -358,22 +913,123 @@ func (r *Router) Setup() *gin.Engine {
 		revenueSplits.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
 			revenueSplits.GET("", r.revenueHandler.ListRevenueSplits)
+			revenueSplits.GET("/rules", middleware.RequireRole("admin"), r.revenueHandler.GetSplitRules)
+			revenueSplits.PUT("/rules", middleware.RequireRole("admin"), r.revenueHandler.SaveSplitRules)
+			revenueSplits.POST("/rules/preview", middleware.RequireRole("admin"), r.revenueHandler.PreviewSplit)
+			revenueSplits.GET("/rules/overrides", middleware.RequireRole("admin"), r.revenueHandler.ListSplitOverrides)
+			revenueSplits.GET("/rules/overrides/:scope/:id", middleware.RequireRole("admin"), r.revenueHandler.GetSplitOverride)
+			revenueSplits.PUT("/rules/overrides/:scope/:id", middleware.RequireRole("admin"), r.revenueHandler.SaveSplitOverride)
+			revenueSplits.DELETE("/rules/overrides/:scope/:id", middleware.RequireRole("admin"), r.revenueHandler.DeleteSplitOverride)
+			revenueSplits.GET("/ledger/:party_type", middleware.RequireRole("admin"), r.revenueHandler.GetPartyLedger)
+			revenueSplits.GET("/adjustments", middleware.RequireRole("admin"), r.splitAdjustmentHandler.ListAdjustments)
+			revenueSplits.POST("/adjustments/:id/approve", middleware.RequireRole("admin"), r.splitAdjustmentHandler.ApproveAdjustment)
+			revenueSplits.POST("/adjustments/:id/reject", middleware.RequireRole("admin"), r.splitAdjustmentHandler.RejectAdjustment)
+			revenueSplits.GET("/disputes", r.splitAdjustmentHandler.ListDisputes)
+			revenueSplits.GET("/disputes/:id", r.splitAdjustmentHandler.GetDispute)
+			revenueSplits.POST("/disputes/:id/review", middleware.RequireRole("admin"), r.splitAdjustmentHandler.ReviewDispute)
+			revenueSplits.POST("/disputes/:id/resolve", middleware.RequireRole("admin"), r.splitAdjustmentHandler.ResolveDispute)
 			revenueSplits.GET("/:id", r.revenueHandler.GetRevenueSplitByID)
 			revenueSplits.GET("/enrollment/:id", r.revenueHandler.GetRevenueSplitByEnrollment)
 			revenueSplits.GET("/instructor/:id", r.revenueHandler.GetInstructorEarnings)
 			revenueSplits.GET("/instructor/:id/total", r.revenueHandler.GetInstructorTotalEarnings)
+			revenueSplits.GET("/instructor/:id/ledger", r.ledgerHandler.GetLedger)
+			revenueSplits.GET("/instructor/:id/statement", r.ledgerHandler.GetStatement)
+			revenueSplits.GET("/instructor/:id/statement/pdf", r.ledgerHandler.ExportStatementPDF)
 			revenueSplits.PATCH("/:id/status", r.revenueHandler.UpdateStatus)
+			revenueSplits.POST("/:id/transfer", middleware.RequireRole("admin"), r.payoutHandler.TransferSplit)
+			revenueSplits.POST("/:id/adjustments", middleware.RequireRole("admin"), r.splitAdjustmentHandler.CreateAdjustment)
+			revenueSplits.POST("/:id/disputes", r.splitAdjustmentHandler.OpenDispute)
+		}
+
+		// Instructor payout batches (protected)
+		payoutBatches := v1.Group("/payout-batches")
+		payoutBatches.Use(middleware.AuthMiddleware(r.jwtManager))
+		{
+			payoutBatches.GET("", middleware.RequireRole("admin"), r.payoutHandler.ListBatches)
+			payoutBatches.GET("/pending", middleware.RequireRole("admin"), r.payoutHandler.ListPayableBalances)
+			payoutBatches.GET("/instructor/:id", r.payoutHandler.ListInstructorBatches)
+			payoutBatches.GET("/:id", r.payoutHandler.GetBatch)
+			payoutBatches.GET("/:id/receipt", r.payoutHandler.DownloadReceipt)
+			payoutBatches.POST("", middleware.RequireRole("admin"), r.payoutHandler.CreateBatch)
+			payoutBatches.POST("/:id/pay", middleware.RequireRole("admin"), r.payoutHandler.MarkPaid)
+			payoutBatches.POST("/:id/cancel", middleware.RequireRole("admin"), r.payoutHandler.CancelBatch)
+		}
+
+		// Instructor payout accounts for automatic transfers (protected)
+		payoutAccounts := v1.Group("/payout-accounts")
+		payoutAccounts.Use(middleware.AuthMiddleware(r.jwtManager))
+		{
+			payoutAccounts.GET("/:id", r.payoutHandler.GetAccount)
+			payoutAccounts.PUT("/:id", middleware.RequireRole("admin"), r.payoutHandler.SaveAccount)
+			payoutAccounts.POST("/:id/verify", middleware.RequireRole("admin"), r.payoutHandler.VerifyAccount)
 		}
 
+		// Accounting system export for Conta Azul / Omie (Admin only)
+		accountingRoutes := v1.Group("/accounting")
+		accountingRoutes.Use(middleware.AuthMiddleware(r.jwtManager))
+		accountingRoutes.Use(middleware.RequireRole("admin"))
+		{
+			accountingRoutes.GET("/entries", r.accountingHandler.ListEntries)
+			accountingRoutes.GET("/export/:layout", r.accountingHandler.Export)
+			accountingRoutes.POST("/sync/:layout", r.accountingHandler.Sync)
+			accountingRoutes.GET("/sync/runs", r.accountingHandler.ListRuns)
+			accountingRoutes.GET("/sync/runs/:id/download", r.accountingHandler.DownloadRun)
+			accountingRoutes.GET("/sync/cursors", r.accountingHandler.ListCursors)
+			accountingRoutes.POST("/period-close", r.accountingHandler.RequestClose)
+			accountingRoutes.GET("/period-close/jobs", r.accountingHandler.ListCloseJobs)
+			accountingRoutes.GET("/period-close/jobs/:id", r.accountingHandler.GetCloseJob)
+		}
+
+		// Global search over contratos, suppliers, tasks and gestores (protected)
+		v1.GET("/search", middleware.AuthMiddleware(r.jwtManager), r.searchHandler.Search)
+
+		// Documents of contracts and their suppliers expired or expiring soon (protected)
+		v1.GET("/compliance/expirations", middleware.AuthMiddleware(r.jwtManager), middleware.RequirePermission(entity.ResourceContratos, entity.ActionRead), r.complianceHandler.GetExpirations)
+
 		// Suppliers (protected)
 		suppliers := v1.Group("/suppliers")
 		suppliers.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
+			supplierUpdate := middleware.RequirePermission(entity.ResourceSuppliers, entity.ActionUpdate)
+			linkManage := r.contractAccess.Require(entity.TeamActionManagePurchases, r.contractAccess.SupplierContract("linkId"))
 			suppliers.GET("", r.supplierHandler.ListSuppliers)
+			suppliers.GET("/ranking", r.supplierHandler.GetRanking)
+			suppliers.GET("/reports/spend-by-contract", r.supplierHandler.GetSpendByContract)
+			suppliers.GET("/reports/spend-by-supplier", r.supplierHandler.GetSpendBySupplier)
 			suppliers.GET("/:id", r.supplierHandler.GetSupplierByID)
-			suppliers.POST("", r.supplierHandler.CreateSupplier)
-			suppliers.PUT("/:id", r.supplierHandler.UpdateSupplier)
-			suppliers.DELETE("/:id", r.supplierHandler.DeleteSupplier)
+			suppliers.POST("", middleware.RequirePermission(entity.ResourceSuppliers, entity.ActionCreate), r.supplierHandler.CreateSupplier)
+			suppliers.PUT("/:id", supplierUpdate, r.supplierHandler.UpdateSupplier)
+			suppliers.DELETE("/:id", middleware.RequirePermission(entity.ResourceSuppliers, entity.ActionDelete), r.supplierHandler.DeleteSupplier)
+			suppliers.GET("/:id/evaluations", r.supplierHandler.ListEvaluations)
+			suppliers.POST("/:id/evaluations", supplierUpdate, r.contractAccess.Require(entity.TeamActionManagePurchases, middleware.ContractFromBody("contrato_id")), r.supplierHandler.EvaluateSupplier)
+			suppliers.GET("/:id/contracts", r.supplierHandler.ListSupplierContracts)
+			suppliers.POST("/:id/contracts", supplierUpdate, r.contractAccess.Require(entity.TeamActionManagePurchases, middleware.ContractFromBody("contrato_id")), r.supplierHandler.LinkContract)
+			suppliers.PUT("/:id/contracts/:linkId", supplierUpdate, linkManage, r.supplierHandler.UpdateContractLink)
+			suppliers.DELETE("/:id/contracts/:linkId", supplierUpdate, linkManage, r.supplierHandler.UnlinkContract)
+			suppliers.GET("/:id/contracts/:linkId/spend", r.supplierHandler.ListSpend)
+			suppliers.POST("/:id/contracts/:linkId/spend", supplierUpdate, linkManage, r.supplierHandler.RecordSpend)
+			suppliers.GET("/:id/documents", r.supplierHandler.ListDocuments)
+			suppliers.POST("/:id/documents", supplierUpdate, r.supplierHandler.AddDocument)
+			suppliers.PUT("/:id/documents/:documentId", supplierUpdate, r.supplierHandler.UpdateDocument)
+			suppliers.DELETE("/:id/documents/:documentId", supplierUpdate, r.supplierHandler.DeleteDocument)
+		}
+
+		// Purchase orders (protected)
+		purchaseOrders := v1.Group("/purchase-orders")
+		// Staff only; orders of a contract are seen by its team and managed by its leaders
+		purchaseOrders.Use(middleware.AuthMiddleware(r.jwtManager), middleware.RequireRole("admin", "gestor", "supervisor"))
+		{
+			orderView := r.contractAccess.Require(entity.TeamActionView, r.contractAccess.PurchaseOrderContract("id"))
+			orderManage := r.contractAccess.Require(entity.TeamActionManagePurchases, r.contractAccess.PurchaseOrderContract("id"))
+			purchaseOrders.GET("", r.purchaseOrderHandler.ListPurchaseOrders)
+			purchaseOrders.GET("/:id", orderView, r.purchaseOrderHandler.GetPurchaseOrder)
+			purchaseOrders.POST("", r.contractAccess.Require(entity.TeamActionManagePurchases, middleware.ContractFromBody("contrato_id")), r.purchaseOrderHandler.CreatePurchaseOrder)
+			purchaseOrders.PUT("/:id", orderManage, r.purchaseOrderHandler.UpdatePurchaseOrder)
+			purchaseOrders.POST("/:id/approve", orderManage, r.purchaseOrderHandler.ApprovePurchaseOrder)
+			purchaseOrders.POST("/:id/deliver", orderManage, r.purchaseOrderHandler.DeliverPurchaseOrder)
+			purchaseOrders.POST("/:id/invoice", orderManage, r.purchaseOrderHandler.InvoicePurchaseOrder)
+			purchaseOrders.POST("/:id/cancel", orderManage, r.purchaseOrderHandler.CancelPurchaseOrder)
+			purchaseOrders.GET("/:id/pdf", orderView, r.purchaseOrderHandler.ExportPDF)
 		}
 
 		// Courses (protected)
@@ -391,15 +1047,22 @@ func (r *Router) Setup() *gin.Engine {
 		tasks := v1.Group("/tasks")
 		tasks.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
-			tasks.GET("", r.taskHandler.ListTasks)
-			tasks.GET("/overdue", r.taskHandler.GetOverdueTasks)
-			tasks.GET("/contract/:id", r.taskHandler.GetTasksByContract)
-			tasks.GET("/assignee/:id", r.taskHandler.GetTasksByAssignee)
-			tasks.GET("/:id", r.taskHandler.GetTaskByID)
-			tasks.POST("", r.taskHandler.CreateTask)
-			tasks.PUT("/:id", r.taskHandler.UpdateTask)
-			tasks.PATCH("/:id/status", r.taskHandler.UpdateTaskStatus)
-			tasks.DELETE("/:id", r.taskHandler.DeleteTask)
+			tasks.GET("", tasksRead, r.conditional("tasks"), r.taskHandler.ListTasks)
+			tasks.GET("/overdue", tasksRead, r.taskHandler.GetOverdueTasks)
+			tasks.GET("/escalations", middleware.RequireRole("admin"), r.taskEscalationHandler.GetReport)
+			tasks.POST("/escalations/run", middleware.RequireRole("admin"), r.taskEscalationHandler.RunEscalations)
+			tasks.GET("/contract/:id", tasksRead, r.contractAccess.Require(entity.TeamActionView, middleware.ContractFromParam("id")), r.taskHandler.GetTasksByContract)
+			tasks.GET("/assignee/:id", tasksRead, r.taskHandler.GetTasksByAssignee)
+			tasks.GET("/:id", tasksRead, r.contractAccess.Require(entity.TeamActionView, r.contractAccess.TaskContract("id")), r.conditional("tasks"), r.taskHandler.GetTaskByID)
+			tasks.POST("", middleware.RequirePermission(entity.ResourceTasks, entity.ActionCreate), r.contractAccess.Require(entity.TeamActionManageTasks, middleware.ContractFromBody("contract_id")), idempotent, r.taskHandler.CreateTask)
+			tasks.POST("/bulk", middleware.RequirePermission(entity.ResourceTasks, entity.ActionCreate), r.contractAccess.Require(entity.TeamActionManageTasks, middleware.ContractFromBody("contract_id")), r.taskHandler.CreateTasks)
+			tasks.PUT("/:id",
+				middleware.RequirePermission(entity.ResourceTasks, entity.ActionUpdate),
+				r.contractAccess.Require(entity.TeamActionManageTasks, r.contractAccess.TaskContract("id")),
+				r.contractAccess.Require(entity.TeamActionManageTasks, middleware.ContractFromBody("contract_id")),
+				r.taskHandler.UpdateTask)
+			tasks.PATCH("/:id/status", middleware.RequirePermission(entity.ResourceTasks, entity.ActionUpdate), r.contractAccess.Require(entity.TeamActionUpdateTaskStatus, r.contractAccess.TaskContract("id")), r.taskHandler.UpdateTaskStatus)
+			tasks.DELETE("/:id", middleware.RequirePermission(entity.ResourceTasks, entity.ActionDelete), r.contractAccess.Require(entity.TeamActionManageTasks, r.contractAccess.TaskContract("id")), r.taskHandler.DeleteTask)
 		}
 
 		// Team Management (protected)
@@ -407,11 +1070,19 @@ func (r *Router) Setup() *gin.Engine {
 		teamGroup.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
 			teamGroup.GET("", r.teamHandler.ListTeamMembers)
+			teamGroup.GET("/workload", r.teamHandler.GetWorkload)
+			teamGroup.GET("/shifts", r.teamHandler.ListShifts)
+			teamGroup.GET("/shifts/:id", r.teamHandler.GetShiftByID)
+			teamGroup.POST("/shifts", middleware.RequireRole("admin", "gestor", "supervisor"), r.teamHandler.CreateShift)
+			teamGroup.PUT("/shifts/:id", middleware.RequireRole("admin", "gestor", "supervisor"), r.teamHandler.UpdateShift)
+			teamGroup.DELETE("/shifts/:id", middleware.RequireRole("admin", "gestor", "supervisor"), r.teamHandler.DeleteShift)
 			teamGroup.GET("/:id", r.teamHandler.GetTeamMemberByID)
 			teamGroup.POST("", r.teamHandler.CreateTeamMember)
 			teamGroup.PUT("/:id", r.teamHandler.UpdateTeamMember)
 			teamGroup.DELETE("/:id", r.teamHandler.DeleteTeamMember)
 			teamGroup.GET("/contract/:id", r.teamHandler.GetTeamByContract)
+			teamGroup.GET("/contract/:id/coverage", r.teamHandler.GetCoverageReport)
+			teamGroup.GET("/contract/:id/conflicts", r.teamHandler.GetShiftConflicts)
 			teamGroup.GET("/user/:id", r.teamHandler.GetContractsByUser)
 		}
 
@@ -430,12 +1101,16 @@ func (r *Router) Setup() *gin.Engine {
 		inspections := v1.Group("/inspections")
 		inspections.Use(middleware.AuthMiddleware(r.jwtManager))
 		{
-			inspections.GET("", r.inspectionHandler.ListInspections)
+			inspections.GET("", r.conditional("inspections"), r.inspectionHandler.ListInspections)
 			inspections.GET("/scheduled", r.inspectionHandler.GetScheduledInspections)
-			inspections.GET("/:id", r.inspectionHandler.GetInspectionByID)
-			inspections.POST("", r.inspectionHandler.CreateInspection)
-			inspections.PUT("/:id", r.inspectionHandler.UpdateInspection)
-			inspections.DELETE("/:id", r.inspectionHandler.DeleteInspection)
+			inspections.GET("/:id", r.contractAccess.Require(entity.TeamActionView, r.contractAccess.InspectionContract("id")), r.conditional("inspections"), r.inspectionHandler.GetInspectionByID)
+			inspections.POST("", r.contractAccess.Require(entity.TeamActionManageInspections, middleware.ContractFromBody("contract_id")), r.inspectionHandler.CreateInspection)
+			inspections.PUT("/:id",
+				r.contractAccess.Require(entity.TeamActionManageInspections, r.contractAccess.InspectionContract("id")),
+				r.contractAccess.Require(entity.TeamActionManageInspections, middleware.ContractFromBody("contract_id")),
+				r.inspectionHandler.UpdateInspection)
+			inspections.DELETE("/:id", r.contractAccess.Require(entity.TeamActionManageInspections, r.contractAccess.InspectionContract("id")), r.inspectionHandler.DeleteInspection)
+			inspections.POST("/:id/task-suggestions", aiLimiter, middleware.RequirePermission(entity.ResourceTasks, entity.ActionCreate), r.contractAccess.Require(entity.TeamActionManageTasks, r.contractAccess.InspectionContract("id")), r.taskHandler.SuggestFromInspection)
 		}
 
 		// Coupons - public validate endpoint
@@ -456,10 +1131,12 @@ func (r *Router) Setup() *gin.Engine {
 		authRoutes := v1.Group("/auth")
 		{
 			// Login and register have stricter rate limits to prevent brute-force
-			loginLimiter := middleware.RateLimiter(10, time.Minute)  // 10 req/min per IP
-			registerLimiter := middleware.RateLimiter(5, time.Minute) // 5 req/min per IP
+			loginLimiter := middleware.RateLimiter(r.rateLimits, "login", 10, time.Minute)      // 10 req/min per IP
+			registerLimiter := middleware.RateLimiter(r.rateLimits, "register", 5, time.Minute) // 5 req/min per IP
+			refreshLimiter := middleware.RateLimiter(r.rateLimits, "refresh", 30, time.Minute)  // 30 req/min per IP
 			authRoutes.POST("/login", loginLimiter, r.authHandler.Login)
 			authRoutes.POST("/register", registerLimiter, r.authHandler.Register)
+			authRoutes.POST("/refresh", refreshLimiter, r.authHandler.Refresh)
 			authRoutes.POST("/logout", r.authHandler.Logout)
 
 			// Protected routes
@@ -473,6 +1150,7 @@ func (r *Router) Setup() *gin.Engine {
 
 			// Admin routes
 			adminRoutes := authRoutes.Group("/users")
+			adminRoutes.Use(r.usersAllowlist.Require())
 			adminRoutes.Use(middleware.AuthMiddleware(r.jwtManager))
 			adminRoutes.Use(middleware.RequireRole("admin"))
 			{
@@ -485,15 +1163,35 @@ func (r *Router) Setup() *gin.Engine {
 
 		// Settings (Admin only)
 		settingsRoutes := v1.Group("/settings")
+		settingsRoutes.Use(r.settingsAllowlist.Require())
 		settingsRoutes.Use(middleware.AuthMiddleware(r.jwtManager))
-		settingsRoutes.Use(middleware.RequireRole("admin"))
 		{
-			settingsRoutes.GET("", r.settingHandler.ListSettings)
-			settingsRoutes.GET("/all", r.settingHandler.GetAllSettings)
-			settingsRoutes.GET("/categories", r.settingHandler.GetCategories)
-			settingsRoutes.GET("/:key", r.settingHandler.GetSettingByKey)
-			settingsRoutes.PUT("", r.settingHandler.BulkUpdateSettings)
-			settingsRoutes.PUT("/:key", r.settingHandler.UpdateSetting)
+			settingsRoutes.GET("", settingsRead, r.settingHandler.ListSettings)
+			settingsRoutes.GET("/all", settingsRead, r.settingHandler.GetAllSettings)
+			settingsRoutes.GET("/categories", settingsRead, r.settingHandler.GetCategories)
+			settingsRoutes.GET("/gateways", settingsRead, r.gatewayHandler.ListGateways)
+			settingsRoutes.GET("/:key", settingsRead, r.settingHandler.GetSettingByKey)
+			settingsRoutes.GET("/:key/history", settingsRead, r.settingHandler.GetSettingHistory)
+			settingsRoutes.POST("/:key/rollback", settingsUpdate, r.settingHandler.RollbackSetting)
+			settingsRoutes.GET("/:key/resolve", settingsRead, r.settingHandler.ResolveSetting)
+			settingsRoutes.GET("/:key/overrides", settingsRead, r.settingHandler.ListOverrides)
+			settingsRoutes.PUT("/:key/overrides/:scope/:scope_id", settingsUpdate, r.settingHandler.SetOverride)
+			settingsRoutes.DELETE("/:key/overrides/:scope/:scope_id", settingsUpdate, r.settingHandler.DeleteOverride)
+			settingsRoutes.PUT("", settingsUpdate, r.settingHandler.BulkUpdateSettings)
+			settingsRoutes.PUT("/:key", settingsUpdate, r.settingHandler.UpdateSetting)
+		}
+
+		// Feature flags: evaluation for the current user, management for admins.
+		// Gate routes with middleware.RequireFeature(r.featureFlags, "<key>", resolver).
+		featureFlags := v1.Group("/feature-flags")
+		featureFlags.Use(middleware.AuthMiddleware(r.jwtManager))
+		{
+			featureFlags.GET("/evaluate", r.featureFlagHandler.Evaluate)
+			featureFlags.GET("", middleware.RequireRole("admin"), r.featureFlagHandler.ListFlags)
+			featureFlags.POST("", middleware.RequireRole("admin"), r.featureFlagHandler.CreateFlag)
+			featureFlags.GET("/:key", middleware.RequireRole("admin"), r.featureFlagHandler.GetFlag)
+			featureFlags.PUT("/:key", middleware.RequireRole("admin"), r.featureFlagHandler.UpdateFlag)
+			featureFlags.DELETE("/:key", middleware.RequireRole("admin"), r.featureFlagHandler.DeleteFlag)
 		}
 
 		// Portal-specific endpoints
@@ -511,16 +1209,50 @@ func (r *Router) Setup() *gin.Engine {
 				portalProtected.DELETE("/images/:filename", r.portalHandler.DeletePortalImage)
 				portalProtected.POST("/evidence", r.portalHandler.UploadEvidence)
 				portalProtected.DELETE("/evidence/:filename", r.portalHandler.DeleteEvidence)
-				aiLimiter := middleware.RateLimiter(20, time.Minute) // 20 req/min for AI proxy
-			portalProtected.POST("/ai", aiLimiter, r.portalHandler.ProxyGeminiAI)
+				portalProtected.GET("/system-images", middleware.RequireRole("admin"), r.systemImageHandler.ListImages)
+				portalProtected.POST("/system-images", middleware.RequireRole("admin"), r.systemImageHandler.CreateImage)
+				portalProtected.GET("/system-images/:id", middleware.RequireRole("admin"), r.systemImageHandler.GetImage)
+				portalProtected.PUT("/system-images/:id", middleware.RequireRole("admin"), r.systemImageHandler.UpdateImage)
+				portalProtected.DELETE("/system-images/:id", middleware.RequireRole("admin"), r.systemImageHandler.DeleteImage)
+			portalProtected.POST("/ai", aiLimiter, r.portalHandler.ProxyAI)
+			portalProtected.POST("/ai/stream", aiLimiter, r.portalHandler.StreamAI)
 			}
 		}
 
+		// AI provider metering (admin only)
+		aiRoutes := v1.Group("/ai")
+		aiRoutes.Use(middleware.AuthMiddleware(r.jwtManager), middleware.RequireRole("admin"))
+		{
+			aiRoutes.GET("/usage", r.aiHandler.GetUsage)
+		}
+
+		// Audit log of mutating requests (admin only)
+		apiRequests := v1.Group("/api-requests")
+		apiRequests.Use(middleware.AuthMiddleware(r.jwtManager), middleware.RequireRole("admin"))
+		{
+			apiRequests.GET("", r.apiRequestHandler.ListRequests)
+		}
+
+		// Activity log: who changed what (admin only)
+		activityLogs := v1.Group("/activity-logs")
+		activityLogs.Use(middleware.AuthMiddleware(r.jwtManager), middleware.RequireRole("admin"))
+		{
+			activityLogs.GET("", r.activityLogHandler.ListLogs)
+		}
+
+		// Legacy router usage (admin only)
+		legacyUsage := v1.Group("/legacy-usage")
+		legacyUsage.Use(middleware.AuthMiddleware(r.jwtManager), middleware.RequireRole("admin"))
+		{
+			legacyUsage.GET("", r.legacyUsageHandler.GetUsage)
+		}
+
 		// Backend integration compatibility routes (for legacy PHP API compatibility)
 		// Protected with OptionalAuth - public endpoints work without token,
-		// protected endpoints require token
+		// protected endpoints require token. Deprecated: responses carry Deprecation, Sunset
+		// and successor-version Link headers, and usage is counted per endpoint.
 		backendIntegration := engine.Group("/backend_integration")
-		backendIntegration.Use(middleware.OptionalAuth(r.jwtManager))
+		backendIntegration.Use(middleware.OptionalAuth(r.jwtManager), r.legacyDeprecation())
 		{
 			backendIntegration.GET("/api_router.php", r.handleLegacyAPIRouter)
 			backendIntegration.POST("/api_router.php", r.handleLegacyAPIRouter)
@@ -531,6 +1263,91 @@ func (r *Router) Setup() *gin.Engine {
 	return engine
 }
 
+// cors builds the CORS middleware: public endpoints embeddable by any site, admin routes
+// limited to the admin origins and everything else under CORS_ALLOWED_ORIGINS
+func (r *Router) cors() gin.HandlerFunc {
+	defaultPolicy := middleware.NewCORSPolicy(r.cfg.CORSAllowedOrigins)
+	publicPolicy := middleware.NewCORSPolicy(r.cfg.CORSPublicOrigins)
+	adminPolicy := defaultPolicy
+	if r.cfg.CORSAdminOrigins != "" {
+		adminPolicy = middleware.NewCORSPolicy(r.cfg.CORSAdminOrigins)
+	}
+
+	routes := []middleware.CORSRoute{
+		{Prefix: "/api/v1/certificados/validate", Policy: publicPolicy},
+		{Prefix: "/api/v1/coupons/validate", Policy: publicPolicy},
+		{Prefix: "/api/v1/checkout", Policy: publicPolicy},
+		{Prefix: "/api/v1/catalog", Policy: publicPolicy},
+	}
+	for _, prefix := range []string{"/api/v1/auth/users", "/api/v1/settings", "/api/v1/feature-flags", "/api/v1/api-requests", "/api/v1/legacy-usage", "/api/v1/portal/system-images"} {
+		routes = append(routes, middleware.CORSRoute{Prefix: prefix, Policy: adminPolicy})
+	}
+	return middleware.CORS(defaultPolicy, routes...)
+}
+
+// legacySuccessors maps every legacy router endpoint to the /api/v1 route replacing it
+var legacySuccessors = map[string]string{
+	"login":         "/api/v1/auth/login",
+	"register":      "/api/v1/auth/register",
+	"logout":        "/api/v1/auth/logout",
+	"health":        "/api/v1/health",
+	"ping":          "/api/v1/health",
+	"images":        "/api/v1/portal/images",
+	"upload":        "/api/v1/portal/images",
+	"me":            "/api/v1/auth/me",
+	"current_user":  "/api/v1/auth/me",
+	"gestores":      "/api/v1/gestores",
+	"managers":      "/api/v1/gestores",
+	"contratos":     "/api/v1/contratos",
+	"contracts":     "/api/v1/contratos",
+	"audits":        "/api/v1/audits",
+	"auditorias":    "/api/v1/audits",
+	"enrollments":   "/api/v1/enrollments",
+	"matriculas":    "/api/v1/enrollments",
+	"courses":       "/api/v1/courses",
+	"tasks":         "/api/v1/tasks",
+	"suppliers":     "/api/v1/suppliers",
+	"team":          "/api/v1/team",
+	"agenda":        "/api/v1/agenda",
+	"inspections":   "/api/v1/inspections",
+	"stats":         "/api/v1/stats/overview",
+	"notifications": "/api/v1/notifications",
+	"settings":      "/api/v1/settings",
+	"certificates":  "/api/v1/certificados",
+	"certificados":  "/api/v1/certificados",
+	"payments":      "/api/v1/payments",
+	"pagamentos":    "/api/v1/payments",
+	"revenue":       "/api/v1/revenue-splits",
+	"receitas":      "/api/v1/revenue-splits",
+}
+
+// legacyDeprecation builds the deprecation middleware of the legacy router from the
+// configured dates
+func (r *Router) legacyDeprecation() gin.HandlerFunc {
+	policy := middleware.LegacyPolicy{Successors: legacySuccessors}
+	if r.cfg.LegacyDeprecatedAt != "" {
+		if t, err := time.Parse("2006-01-02", r.cfg.LegacyDeprecatedAt); err == nil {
+			policy.DeprecatedAt = t
+		} else {
+			log.Printf("Warning: invalid LEGACY_ROUTER_DEPRECATED_AT %q, expected YYYY-MM-DD", r.cfg.LegacyDeprecatedAt)
+		}
+	}
+	if r.cfg.LegacySunset != "" {
+		if t, err := time.Parse("2006-01-02", r.cfg.LegacySunset); err == nil {
+			policy.Sunset = t
+		} else {
+			log.Printf("Warning: invalid LEGACY_ROUTER_SUNSET %q, expected YYYY-MM-DD", r.cfg.LegacySunset)
+		}
+	}
+	return middleware.LegacyDeprecation(policy, r.legacyUsageRepo, r.lifecycle)
+}
+
+// withParam sets a path parameter so /api/v1 handlers can serve legacy requests, which pass
+// their IDs in the query string
+func withParam(c *gin.Context, key, value string) {
+	c.Params = append(c.Params, gin.Param{Key: key, Value: value})
+}
+
 // handleLegacyAPIRouter handles legacy PHP API compatibility.
 // Public endpoints: login, register, logout, health, images (GET).
 // All other endpoints require JWT authentication via OptionalAuth.
@@ -556,10 +1373,17 @@ func (r *Router) handleLegacyAPIRouter(c *gin.Context) {
 			r.portalHandler.ListPortalImages(c)
 			return
 		}
+	case "certificates", "certificados":
+		// Certificate validation is public, as in /api/v1/certificados/validate/:code
+		if code := c.Query("code"); c.Request.Method == "GET" && code != "" {
+			withParam(c, "code", code)
+			r.certificadoHandler.ValidateCertificate(c)
+			return
+		}
 	case "":
 		c.JSON(200, gin.H{
 			"status":  "CondoTrack API Online (Go)",
-			"actions": []string{"login", "register", "health", "images"},
+			"actions": []string{"login", "register", "health", "images", "certificates"},
 		})
 		return
 	}
@@ -585,18 +1409,28 @@ func (r *Router) handleLegacyAPIRouter(c *gin.Context) {
 	case "upload":
 		r.portalHandler.UploadPortalImage(c)
 	case "gestores", "managers":
-		r.gestorHandler.ListGestores(c)
+		if middleware.CheckPermission(c, entity.ResourceGestores, entity.ActionRead) {
+			r.gestorHandler.ListGestores(c)
+		}
 	case "contratos", "contracts":
 		if c.Request.Method == "GET" {
-			r.contratoHandler.ListContratos(c)
+			if middleware.CheckPermission(c, entity.ResourceContratos, entity.ActionRead) {
+				r.contratoHandler.ListContratos(c)
+			}
 		} else if c.Request.Method == "POST" {
-			r.contratoHandler.CreateContrato(c)
+			if middleware.CheckPermission(c, entity.ResourceContratos, entity.ActionCreate, r.contractAccess.GestorFromBody("gestor_id")) {
+				r.contratoHandler.CreateContrato(c)
+			}
 		}
 	case "audits", "auditorias":
 		if c.Request.Method == "GET" {
-			r.auditHandler.ListAudits(c)
+			if middleware.CheckPermission(c, entity.ResourceAudits, entity.ActionRead) {
+				r.auditHandler.ListAudits(c)
+			}
 		} else if c.Request.Method == "POST" {
-			r.auditHandler.CreateAudit(c)
+			if r.contractAccess.Check(c, entity.TeamActionManageAudits, middleware.ContractFromBody("contract_id")) {
+				r.auditHandler.CreateAudit(c)
+			}
 		}
 	case "enrollments", "matriculas":
 		if c.Request.Method == "GET" {
@@ -612,15 +1446,21 @@ func (r *Router) handleLegacyAPIRouter(c *gin.Context) {
 		}
 	case "tasks":
 		if c.Request.Method == "GET" {
-			r.taskHandler.ListTasks(c)
+			if middleware.CheckPermission(c, entity.ResourceTasks, entity.ActionRead) {
+				r.taskHandler.ListTasks(c)
+			}
 		} else if c.Request.Method == "POST" {
-			r.taskHandler.CreateTask(c)
+			if r.contractAccess.Check(c, entity.TeamActionManageTasks, middleware.ContractFromBody("contract_id")) {
+				r.taskHandler.CreateTask(c)
+			}
 		}
 	case "suppliers":
 		if c.Request.Method == "GET" {
 			r.supplierHandler.ListSuppliers(c)
 		} else if c.Request.Method == "POST" {
-			r.supplierHandler.CreateSupplier(c)
+			if middleware.CheckPermission(c, entity.ResourceSuppliers, entity.ActionCreate) {
+				r.supplierHandler.CreateSupplier(c)
+			}
 		}
 	case "team":
 		if c.Request.Method == "GET" {
@@ -638,7 +1478,9 @@ func (r *Router) handleLegacyAPIRouter(c *gin.Context) {
 		if c.Request.Method == "GET" {
 			r.inspectionHandler.ListInspections(c)
 		} else if c.Request.Method == "POST" {
-			r.inspectionHandler.CreateInspection(c)
+			if r.contractAccess.Check(c, entity.TeamActionManageInspections, middleware.ContractFromBody("contract_id")) {
+				r.inspectionHandler.CreateInspection(c)
+			}
 		}
 	case "stats":
 		r.statsHandler.GetOverview(c)
@@ -660,6 +1502,71 @@ func (r *Router) handleLegacyAPIRouter(c *gin.Context) {
 		} else if c.Request.Method == "PUT" {
 			r.settingHandler.BulkUpdateSettings(c)
 		}
+	case "certificates", "certificados":
+		// GET ?id= one certificate, GET ?aluno_id= a student's certificates, POST generates
+		if c.Request.Method == "GET" {
+			if id := c.Query("id"); id != "" {
+				withParam(c, "id", id)
+				r.certificadoHandler.GetCertificateByID(c)
+			} else {
+				withParam(c, "aluno_id", c.Query("aluno_id"))
+				r.certificadoHandler.GetCertificatesByStudent(c)
+			}
+		} else if c.Request.Method == "POST" {
+			r.certificadoHandler.GenerateCertificate(c)
+		}
+	case "payments", "pagamentos":
+		// GET ?id= payment status, GET ?enrollment_id= payments of an enrollment, GET
+		// ?simulate=1 simulates the split, GET lists with the /api/v1/payments filters;
+		// POST ?method=pix|boleto|card creates a charge and ?method=customer a customer
+		if c.Request.Method == "GET" {
+			if c.Query("simulate") == "1" {
+				r.paymentHandler.SimulateRevenueSplit(c)
+			} else if id := c.Query("id"); id != "" {
+				withParam(c, "id", id)
+				r.paymentHandler.GetPaymentStatus(c)
+			} else if enrollmentID := c.Query("enrollment_id"); enrollmentID != "" {
+				withParam(c, "id", enrollmentID)
+				r.paymentHandler.GetPaymentsByEnrollment(c)
+			} else {
+				r.paymentHandler.ListPayments(c)
+			}
+		} else if c.Request.Method == "POST" {
+			switch c.Query("method") {
+			case "pix":
+				r.paymentHandler.CreatePixPayment(c)
+			case "boleto":
+				r.paymentHandler.CreateBoletoPayment(c)
+			case "card", "credit_card":
+				r.paymentHandler.CreateCardPayment(c)
+			case "customer":
+				r.paymentHandler.CreateCustomer(c)
+			default:
+				c.JSON(400, gin.H{"success": false, "error": "method must be pix, boleto, card or customer"})
+			}
+		}
+	case "revenue", "receitas":
+		// GET ?id= one split, ?enrollment_id= the split of an enrollment, ?instructor_id= the
+		// earnings of an instructor (with total=1, their totals); otherwise the split list
+		if c.Request.Method != "GET" {
+			break
+		}
+		if id := c.Query("id"); id != "" {
+			withParam(c, "id", id)
+			r.revenueHandler.GetRevenueSplitByID(c)
+		} else if enrollmentID := c.Query("enrollment_id"); enrollmentID != "" {
+			withParam(c, "id", enrollmentID)
+			r.revenueHandler.GetRevenueSplitByEnrollment(c)
+		} else if instructorID := c.Query("instructor_id"); instructorID != "" {
+			withParam(c, "id", instructorID)
+			if c.Query("total") == "1" {
+				r.revenueHandler.GetInstructorTotalEarnings(c)
+			} else {
+				r.revenueHandler.GetInstructorEarnings(c)
+			}
+		} else {
+			r.revenueHandler.ListRevenueSplits(c)
+		}
 	default:
 		c.JSON(404, gin.H{"success": false, "error": "Unknown endpoint: " + endpoint})
 	}
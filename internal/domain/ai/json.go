@@ -0,0 +1,21 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// DecodeJSON parses a JSON object out of a model answer. Models often wrap the object in a
+// markdown code fence or add a sentence around it, so only the outermost braces are decoded.
+func DecodeJSON(text string, v interface{}) error {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return errors.New("invalid AI response: no JSON object found")
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), v); err != nil {
+		return errors.New("invalid AI response: " + err.Error())
+	}
+	return nil
+}
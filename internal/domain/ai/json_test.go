@@ -0,0 +1,25 @@
+package ai
+
+import "testing"
+
+func TestDecodeJSON_StripsFence(t *testing.T) {
+	var out struct {
+		Summary string `json:"summary"`
+	}
+	text := "Aqui está:\n```json\n{\"summary\": \"ok {1}\"}\n```"
+	if err := DecodeJSON(text, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Summary != "ok {1}" {
+		t.Errorf("unexpected summary %q", out.Summary)
+	}
+}
+
+func TestDecodeJSON_Invalid(t *testing.T) {
+	var out map[string]interface{}
+	for _, text := range []string{"sem json", "{\"summary\": }"} {
+		if err := DecodeJSON(text, &out); err == nil {
+			t.Errorf("expected an error for %q", text)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package ai
+
+import "context"
+
+// AIProvider defines the interface every text generation provider must implement
+// (Gemini, OpenAI, Anthropic). Providers are tried in the configured order, so failures
+// must be returned as errors for the next provider to take over.
+type AIProvider interface {
+	// Name returns the provider identifier (e.g. "gemini", "openai", "anthropic")
+	Name() string
+
+	// Model returns the model requests are sent to
+	Model() string
+
+	// Available reports whether the provider has credentials configured
+	Available() bool
+
+	// Generate returns the complete answer
+	Generate(ctx context.Context, req Request) (*Response, error)
+
+	// Stream generates the answer calling onChunk with each piece of text as it arrives. An
+	// error returned by onChunk aborts the stream.
+	Stream(ctx context.Context, req Request, onChunk func(text string) error) (*Response, error)
+}
+
+// Request is a single-turn prompt
+type Request struct {
+	System      string
+	Prompt      string
+	Temperature float64
+	MaxTokens   int
+}
+
+// Usage is the token count reported by the provider
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// Response is a generated answer
+type Response struct {
+	Text     string
+	Provider string
+	Model    string
+	Usage    Usage
+}
+
+// DefaultMaxTokens is used when a request does not set MaxTokens
+const DefaultMaxTokens = 1024
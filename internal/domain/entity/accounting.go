@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+// Accounting entry type constants
+const (
+	AccountingEntryRevenue          = "revenue"           // confirmed payment, amount charged
+	AccountingEntryGatewayFee       = "gateway_fee"       // gateway fee of a confirmed payment
+	AccountingEntryRefund           = "refund"            // amount returned to the payer
+	AccountingEntryInstructorPayout = "instructor_payout" // paid payout batch or completed gateway transfer
+	AccountingEntryTax              = "tax"               // tax provision on revenue, only in the period close journal
+)
+
+// Accounting export layout constants
+const (
+	AccountingLayoutContaAzul = "contaazul"
+	AccountingLayoutOmie      = "omie"
+)
+
+// IsValidAccountingLayout checks if the layout is supported
+func IsValidAccountingLayout(layout string) bool {
+	return layout == AccountingLayoutContaAzul || layout == AccountingLayoutOmie
+}
+
+// AccountingEntry is a financial movement derived from payments, payout batches and transfers.
+// Key is stable across exports (e.g. "payment:<id>") and identifies the entry in the target system.
+type AccountingEntry struct {
+	Key                  string    `db:"entry_key" json:"key"`
+	Type                 string    `db:"entry_type" json:"type"`
+	SourceID             string    `db:"source_id" json:"source_id"`
+	OccurredAt           time.Time `db:"occurred_at" json:"occurred_at"`
+	Amount               float64   `db:"amount" json:"amount"`
+	Description          string    `db:"description" json:"description"`
+	Counterparty         *string   `db:"counterparty" json:"counterparty,omitempty"`
+	CounterpartyDocument *string   `db:"counterparty_document" json:"counterparty_document,omitempty"`
+	PaymentMethod        *string   `db:"payment_method" json:"payment_method,omitempty"`
+}
+
+// IsExpense reports whether the entry takes money out of the company
+func (e *AccountingEntry) IsExpense() bool {
+	return e.Type != AccountingEntryRevenue
+}
+
+// AccountingSyncCursor tracks how far the entries were exported to a layout
+type AccountingSyncCursor struct {
+	Layout         string     `db:"layout" json:"layout"`
+	LastOccurredAt *time.Time `db:"last_occurred_at" json:"last_occurred_at,omitempty"`
+	LastRunID      *string    `db:"last_run_id" json:"last_run_id,omitempty"`
+	UpdatedAt      *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// AccountingSyncRun is one incremental export; its entries are never exported again to the same layout
+type AccountingSyncRun struct {
+	ID          string    `db:"id" json:"id"`
+	Layout      string    `db:"layout" json:"layout"`
+	EntryCount  int       `db:"entry_count" json:"entry_count"`
+	TotalAmount float64   `db:"total_amount" json:"total_amount"`
+	CreatedBy   *string   `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// AccountingExport is a rendered export file
+type AccountingExport struct {
+	FileName   string
+	Content    []byte
+	EntryCount int
+}
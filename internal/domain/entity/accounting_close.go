@@ -0,0 +1,67 @@
+package entity
+
+import "time"
+
+// Period close export formats
+const (
+	AccountingCloseFormatCSV  = "csv"  // double-entry journal, one debit/credit pair per line
+	AccountingCloseFormatSPED = "sped" // I050/I200/I250 records of the SPED ECD layout
+)
+
+// IsValidAccountingCloseFormat checks if the period close format is supported
+func IsValidAccountingCloseFormat(format string) bool {
+	return format == AccountingCloseFormatCSV || format == AccountingCloseFormatSPED
+}
+
+// Period close job status constants
+const (
+	AccountingCloseStatusPending = "pending"
+	AccountingCloseStatusRunning = "running"
+	AccountingCloseStatusDone    = "done"
+	AccountingCloseStatusFailed  = "failed"
+)
+
+// AccountingCloseJob is a period close export generated in the background. The file is kept
+// in storage under FileKey and downloaded through a presigned URL.
+type AccountingCloseJob struct {
+	ID          string     `db:"id" json:"id"`
+	Period      string     `db:"period" json:"period"` // YYYY-MM
+	Format      string     `db:"format" json:"format"`
+	Status      string     `db:"status" json:"status"`
+	FileKey     *string    `db:"file_key" json:"-"`
+	LineCount   int        `db:"line_count" json:"line_count"`
+	TotalAmount float64    `db:"total_amount" json:"total_amount"` // sum of the debits, equal to the credits
+	Error       *string    `db:"error" json:"error,omitempty"`
+	CreatedBy   *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	StartedAt   *time.Time `db:"started_at" json:"started_at,omitempty"`
+	FinishedAt  *time.Time `db:"finished_at" json:"finished_at,omitempty"`
+
+	DownloadURL string `db:"-" json:"download_url,omitempty"`
+}
+
+// CreateAccountingCloseRequest represents the request to export a closed month
+type CreateAccountingCloseRequest struct {
+	Period string `json:"period" binding:"required"` // YYYY-MM
+	Format string `json:"format"`                    // csv (default) or sped
+}
+
+// LedgerAccount is an account of the chart used by the period close
+type LedgerAccount struct {
+	Code   string
+	Name   string
+	Nature string // SPED COD_NAT: 01 asset, 02 liability, 04 income statement
+}
+
+// JournalLine is a double-entry posting: Amount is debited to Debit and credited to Credit
+type JournalLine struct {
+	Key          string
+	Type         string
+	Date         time.Time
+	Debit        LedgerAccount
+	Credit       LedgerAccount
+	Amount       float64
+	History      string
+	Counterparty string
+	Document     string
+}
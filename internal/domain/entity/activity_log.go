@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Activity log actions, after the request method
+const (
+	ActivityActionCreate = "create" // POST
+	ActivityActionUpdate = "update" // PUT and PATCH
+	ActivityActionDelete = "delete" // DELETE
+)
+
+// ActivityLog records a change made through the API: who (user, role and IP) changed which
+// entity and what. Entity is the resource of the route (contratos for
+// /contratos/:id/documents) and EntityID the first ID in the route, or the ID of the record
+// a POST created. Diff holds the fields submitted, with credentials, card data and documents redacted.
+type ActivityLog struct {
+	ID        string          `db:"id" json:"id"`
+	UserID    *string         `db:"user_id" json:"user_id,omitempty"`
+	UserRole  *string         `db:"user_role" json:"user_role,omitempty"`
+	Action    string          `db:"action" json:"action"`
+	Entity    string          `db:"entity" json:"entity"`
+	EntityID  *string         `db:"entity_id" json:"entity_id,omitempty"`
+	Route     string          `db:"route" json:"route"`
+	Diff      json.RawMessage `db:"diff" json:"diff,omitempty"`
+	IPAddress string          `db:"ip_address" json:"ip_address"`
+	RequestID *string         `db:"request_id" json:"request_id,omitempty"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+// ActivityLogFilters holds filter parameters for listing activity logs
+type ActivityLogFilters struct {
+	UserID   string
+	Entity   string
+	EntityID string
+	Action   string
+	DateFrom *time.Time
+	DateTo   *time.Time
+	Page     int
+	PerPage  int
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// AIUsage meters one call to an AI provider. Failed attempts are recorded too, so fallbacks
+// show up per provider.
+type AIUsage struct {
+	ID           string    `db:"id" json:"id"`
+	Provider     string    `db:"provider" json:"provider"`
+	Model        string    `db:"model" json:"model"`
+	Feature      string    `db:"feature" json:"feature"`
+	UserID       *string   `db:"user_id" json:"user_id,omitempty"`
+	InputTokens  int       `db:"input_tokens" json:"input_tokens"`
+	OutputTokens int       `db:"output_tokens" json:"output_tokens"`
+	LatencyMs    int64     `db:"latency_ms" json:"latency_ms"`
+	Success      bool      `db:"success" json:"success"`
+	Error        *string   `db:"error_message" json:"error,omitempty"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// AIUsageSummary aggregates the usage of a provider and model
+type AIUsageSummary struct {
+	Provider     string `db:"provider" json:"provider"`
+	Model        string `db:"model" json:"model"`
+	Calls        int    `db:"calls" json:"calls"`
+	Failures     int    `db:"failures" json:"failures"`
+	InputTokens  int64  `db:"input_tokens" json:"input_tokens"`
+	OutputTokens int64  `db:"output_tokens" json:"output_tokens"`
+}
+
+// AIUsageFilters holds the filter parameters for summarizing AI usage
+type AIUsageFilters struct {
+	From    *time.Time
+	To      *time.Time
+	Feature string
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// APIRequest is the audit record of a mutating API request, kept for troubleshooting and
+// compliance. Body holds the JSON body with credentials, card data and documents redacted.
+type APIRequest struct {
+	ID        string    `db:"id" json:"id"`
+	Method    string    `db:"method" json:"method"`
+	Path      string    `db:"path" json:"path"`
+	Route     *string   `db:"route" json:"route,omitempty"`
+	ActorID   *string   `db:"actor_id" json:"actor_id,omitempty"`
+	ActorRole *string   `db:"actor_role" json:"actor_role,omitempty"`
+	Status    int       `db:"status" json:"status"`
+	LatencyMs int64     `db:"latency_ms" json:"latency_ms"`
+	ClientIP  string    `db:"client_ip" json:"client_ip"`
+	RequestID *string   `db:"request_id" json:"request_id,omitempty"`
+	Body      *string   `db:"body" json:"body,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "encoding/json"
+
+// AuditDataTemplate describes a template of the audit data_json and its schema versions
+type AuditDataTemplate struct {
+	Name           string          `json:"name"`
+	CurrentVersion int             `json:"current_version"`
+	Versions       []int           `json:"versions"`
+	Schema         json.RawMessage `json:"schema"` // JSON schema of the current version
+}
+
+// AuditDataRecord is the data_json stored on an audit
+type AuditDataRecord struct {
+	AuditID  string          `db:"id"`
+	DataJSON json.RawMessage `db:"data_json"`
+}
+
+// MigrateAuditDataRequest represents the request to upgrade stored data_json payloads to
+// the current schema of their template
+type MigrateAuditDataRequest struct {
+	DryRun bool `json:"dry_run"`
+	Limit  int  `json:"limit" binding:"omitempty,min=1,max=100000"` // audits to scan; all when 0
+}
+
+// AuditDataMigrationResult reports a data_json migration run
+type AuditDataMigrationResult struct {
+	DryRun   bool                        `json:"dry_run"`
+	Scanned  int                         `json:"scanned"`
+	Upgraded int                         `json:"upgraded"`
+	Current  int                         `json:"current"` // already in the current version
+	Failed   []AuditDataMigrationFailure `json:"failed"`
+}
+
+// AuditDataMigrationFailure is an audit whose data_json could not be upgraded
+type AuditDataMigrationFailure struct {
+	AuditID string   `json:"audit_id"`
+	Errors  []string `json:"errors"`
+}
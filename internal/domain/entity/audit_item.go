@@ -37,6 +37,14 @@ func (ai *AuditItem) CalculateItemPercentage() float64 {
 	return (ai.Score / ai.MaxScore) * 100
 }
 
+// NonConformityPercent is the item score percentage below which an item is a non-conformity
+const NonConformityPercent = 70.0
+
+// IsNonConformity reports whether the item scored below NonConformityPercent
+func (ai *AuditItem) IsNonConformity() bool {
+	return ai.MaxScore > 0 && ai.CalculateItemPercentage() < NonConformityPercent
+}
+
 // CreateAuditCategoryRequest represents the request to create an audit category
 type CreateAuditCategoryRequest struct {
 	Name        string  `json:"name" binding:"required"`
@@ -45,3 +45,19 @@ func TestCalculateItemPercentage_FractionalResult(t *testing.T) {
 		t.Errorf("CalculateItemPercentage() = %f, want %f", got, expected)
 	}
 }
+
+func TestIsNonConformity(t *testing.T) {
+	cases := []struct {
+		item AuditItem
+		want bool
+	}{
+		{AuditItem{Score: 6, MaxScore: 10}, true},
+		{AuditItem{Score: 7, MaxScore: 10}, false},
+		{AuditItem{Score: 0, MaxScore: 0}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.item.IsNonConformity(); got != tc.want {
+			t.Errorf("IsNonConformity(%v/%v) = %v, want %v", tc.item.Score, tc.item.MaxScore, got, tc.want)
+		}
+	}
+}
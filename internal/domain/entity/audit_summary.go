@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// AuditAISummary is the AI generated executive summary of an audit. It is cached on the audit
+// with a hash of the data it was generated from, so edits to the audit or its items produce
+// a new summary on the next request.
+type AuditAISummary struct {
+	Summary           string                  `json:"summary"`
+	CorrectiveActions []AuditCorrectiveAction `json:"corrective_actions"`
+	Provider          string                  `json:"provider"`
+	Model             string                  `json:"model"`
+	GeneratedAt       time.Time               `json:"generated_at"`
+	Cached            bool                    `json:"cached"`
+	InputHash         string                  `json:"-"`
+}
+
+// AuditCorrectiveAction is a corrective action suggested for an audit finding. Priority uses
+// the task priorities so actions can be turned into tasks.
+type AuditCorrectiveAction struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Priority    string `json:"priority"`
+	Category    string `json:"category,omitempty"`
+}
@@ -18,6 +18,7 @@ type Certificado struct {
 	ValidationCode string     `db:"validation_code" json:"validation_code"`
 	Status         string     `db:"status" json:"status"`
 	DownloadURL    *string    `db:"download_url" json:"download_url,omitempty"`
+	PDFKey         *string    `db:"pdf_key" json:"-"` // object key of the rendered PDF in storage
 	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
 }
 
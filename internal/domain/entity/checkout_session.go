@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// Checkout session status constants
+const (
+	CheckoutSessionStatusOpen       = "open"
+	CheckoutSessionStatusProcessing = "processing"
+	CheckoutSessionStatusCompleted  = "completed"
+	CheckoutSessionStatusExpired    = "expired"
+)
+
+// CheckoutSession is a checkout opened by a third-party landing page for a course. The price
+// is fixed when the session is opened, and the session is paid at most once.
+type CheckoutSession struct {
+	ID           string      `db:"id" json:"id"`
+	CourseID     string      `db:"course_id" json:"course_id"`
+	Amount       money.Cents `db:"amount" json:"amount"`
+	DiscountCode *string     `db:"discount_code" json:"discount_code,omitempty"`
+	Status       string      `db:"status" json:"status"`
+	EnrollmentID *string     `db:"enrollment_id" json:"enrollment_id,omitempty"`
+	ExpiresAt    time.Time   `db:"expires_at" json:"expires_at"`
+	CreatedAt    time.Time   `db:"created_at" json:"created_at"`
+	CompletedAt  *time.Time  `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// CurrentStatus returns the status of the session at now; open sessions past their expiry are
+// expired
+func (s *CheckoutSession) CurrentStatus(now time.Time) string {
+	if s.Status == CheckoutSessionStatusOpen && !now.Before(s.ExpiresAt) {
+		return CheckoutSessionStatusExpired
+	}
+	return s.Status
+}
+
+// CreateCheckoutSessionRequest represents the request to open a checkout session
+type CreateCheckoutSessionRequest struct {
+	CourseID     string `json:"course_id" binding:"required"`
+	DiscountCode string `json:"discount_code,omitempty" binding:"omitempty,max=50"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// Compliance expiration sources
+const (
+	ComplianceSourceContractDocument = "contract_document"
+	ComplianceSourceSupplierDocument = "supplier_document"
+	ComplianceSourceCertification    = "certification"
+)
+
+// ComplianceExpiration is a document of a contract, or of a supplier serving it, that expired
+// or expires soon. Supplier documents appear once for every contract the supplier serves.
+type ComplianceExpiration struct {
+	Source       string    `db:"source" json:"source"`
+	ID           string    `db:"id" json:"id"`
+	ContratoID   string    `db:"contrato_id" json:"contrato_id"`
+	ContratoNome string    `db:"contrato_nome" json:"-"`
+	Title        string    `db:"title" json:"title"`
+	DocumentType string    `db:"document_type" json:"document_type"`
+	SupplierID   *string   `db:"supplier_id" json:"supplier_id,omitempty"`
+	SupplierName *string   `db:"supplier_name" json:"supplier_name,omitempty"`
+	ExpiresAt    time.Time `db:"expires_at" json:"expires_at"`
+	DaysLeft     int       `db:"-" json:"days_left"`
+	Expired      bool      `db:"-" json:"expired"`
+}
+
+// ContractComplianceExpirations groups the expirations of a contract, soonest first
+type ContractComplianceExpirations struct {
+	ContratoID     string                 `json:"contrato_id"`
+	ContratoNome   string                 `json:"contrato_nome"`
+	Expired        int                    `json:"expired"`
+	Expiring       int                    `json:"expiring"`
+	NextExpiration time.Time              `json:"next_expiration"`
+	Items          []ComplianceExpiration `json:"items"`
+}
+
+// ComplianceExpirations is the "what expires soon" view across contracts: documents already
+// expired and those expiring within Days, for the contracts with the most urgent first
+type ComplianceExpirations struct {
+	Days      int                             `json:"days"`
+	Until     time.Time                       `json:"until"`
+	Expired   int                             `json:"expired"`
+	Expiring  int                             `json:"expiring"`
+	Contracts []ContractComplianceExpirations `json:"contracts"`
+}
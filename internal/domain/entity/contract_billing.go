@@ -0,0 +1,171 @@
+package entity
+
+import "time"
+
+// Contract charge status constants
+const (
+	ContractChargeStatusPending   = "pending" // queued, the gateway charge is being created
+	ContractChargeStatusOpen      = "open"    // issued and awaiting payment
+	ContractChargeStatusOverdue   = "overdue" // past its due date and still unpaid
+	ContractChargeStatusPaid      = "paid"
+	ContractChargeStatusCancelled = "cancelled"
+	ContractChargeStatusRefunded  = "refunded"
+	ContractChargeStatusFailed    = "failed" // the gateway refused it; the next run tries again
+)
+
+// ContractBillingPlan is the monthly fee (taxa de administração) billed to the condominium of
+// a contract. Every month in range gets one charge, issued LeadDays before its due day.
+type ContractBillingPlan struct {
+	ID                string     `db:"id" json:"id"`
+	ContratoID        string     `db:"contrato_id" json:"contrato_id"`
+	Description       string     `db:"description" json:"description"`
+	MonthlyAmount     float64    `db:"monthly_amount" json:"monthly_amount"`
+	DueDay            int        `db:"due_day" json:"due_day"`
+	BillingType       string     `db:"billing_type" json:"billing_type"` // boleto or pix
+	PayerName         string     `db:"payer_name" json:"payer_name"`
+	PayerDocument     string     `db:"payer_document" json:"payer_document"` // CNPJ of the condominium
+	PayerEmail        string     `db:"payer_email" json:"payer_email"`
+	PayerPhone        *string    `db:"payer_phone" json:"payer_phone,omitempty"`
+	Gateway           *string    `db:"gateway" json:"gateway,omitempty"`
+	GatewayCustomerID *string    `db:"gateway_customer_id" json:"-"`
+	StartPeriod       string     `db:"start_period" json:"start_period"`       // YYYY-MM
+	EndPeriod         *string    `db:"end_period" json:"end_period,omitempty"` // YYYY-MM, open-ended when nil
+	Active            bool       `db:"active" json:"active"`
+	CreatedBy         *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt         *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	// Joined fields
+	ContratoNome string `db:"contrato_nome" json:"contrato_nome,omitempty"`
+}
+
+// BillsPeriod reports whether the plan charges the given YYYY-MM period
+func (p *ContractBillingPlan) BillsPeriod(period string) bool {
+	if !p.Active || period < p.StartPeriod {
+		return false
+	}
+	return p.EndPeriod == nil || period <= *p.EndPeriod
+}
+
+// DueDate returns the due date of the plan in a YYYY-MM period
+func (p *ContractBillingPlan) DueDate(period string) (time.Time, error) {
+	month, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return month.AddDate(0, 0, p.DueDay-1), nil
+}
+
+// SaveContractBillingPlanRequest represents the request to set up the billing of a contract
+type SaveContractBillingPlanRequest struct {
+	Description   string  `json:"description"`
+	MonthlyAmount float64 `json:"monthly_amount" binding:"required,gt=0"`
+	DueDay        int     `json:"due_day" binding:"required,min=1,max=28"`
+	BillingType   string  `json:"billing_type" binding:"omitempty,oneof=boleto pix"`
+	PayerName     string  `json:"payer_name" binding:"required"`
+	PayerDocument string  `json:"payer_document" binding:"required"`
+	PayerEmail    string  `json:"payer_email" binding:"required,email"`
+	PayerPhone    *string `json:"payer_phone"`
+	StartPeriod   string  `json:"start_period" binding:"required"`
+	EndPeriod     *string `json:"end_period"`
+	Active        *bool   `json:"active"`
+}
+
+// ContractCharge is a monthly receivable of a contract. A contract is charged at most once
+// per period.
+type ContractCharge struct {
+	ID               string     `db:"id" json:"id"`
+	PlanID           string     `db:"plan_id" json:"plan_id"`
+	ContratoID       string     `db:"contrato_id" json:"contrato_id"`
+	Period           string     `db:"period" json:"period"` // YYYY-MM
+	Description      string     `db:"description" json:"description"`
+	Amount           float64    `db:"amount" json:"amount"`
+	DueDate          time.Time  `db:"due_date" json:"due_date"`
+	BillingType      string     `db:"billing_type" json:"billing_type"`
+	Status           string     `db:"status" json:"status"`
+	Gateway          *string    `db:"gateway" json:"gateway,omitempty"`
+	GatewayPaymentID *string    `db:"gateway_payment_id" json:"gateway_payment_id,omitempty"`
+	InvoiceURL       *string    `db:"invoice_url" json:"invoice_url,omitempty"`
+	BoletoURL        *string    `db:"boleto_url" json:"boleto_url,omitempty"`
+	PixCopyPaste     *string    `db:"pix_copy_paste" json:"pix_copy_paste,omitempty"`
+	PaidAmount       *float64   `db:"paid_amount" json:"paid_amount,omitempty"`
+	GatewayFee       float64    `db:"gateway_fee" json:"gateway_fee"`
+	PaidAt           *time.Time `db:"paid_at" json:"paid_at,omitempty"`
+	Error            *string    `db:"error" json:"error,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	// Joined fields
+	ContratoNome string `db:"contrato_nome" json:"contrato_nome,omitempty"`
+
+	// Computed
+	DaysOverdue int `db:"-" json:"days_overdue,omitempty"`
+}
+
+// IsReceivable reports whether the charge is still expected to be paid
+func (c *ContractCharge) IsReceivable() bool {
+	return c.Status == ContractChargeStatusOpen || c.Status == ContractChargeStatusOverdue
+}
+
+// OverdueDays is how many days past its due date an unpaid charge is, 0 when it is not late
+func (c *ContractCharge) OverdueDays(now time.Time) int {
+	if !c.IsReceivable() {
+		return 0
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due := time.Date(c.DueDate.Year(), c.DueDate.Month(), c.DueDate.Day(), 0, 0, 0, 0, time.UTC)
+	if !today.After(due) {
+		return 0
+	}
+	return int(today.Sub(due).Hours() / 24)
+}
+
+// ContractChargeFilters holds the filters for listing contract charges
+type ContractChargeFilters struct {
+	ContratoID string
+	Status     string
+	From       string // YYYY-MM
+	To         string // YYYY-MM
+}
+
+// CreateContractChargeRequest represents the request to bill a contract for a period out of schedule
+type CreateContractChargeRequest struct {
+	Period string `json:"period" binding:"required"` // YYYY-MM
+}
+
+// ContractBillingRunResult summarizes a billing run
+type ContractBillingRunResult struct {
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+	Overdue int `json:"overdue"` // charges that went overdue in this run
+}
+
+// ContractReceivable is the open balance of a contract, with its late amount split by age
+type ContractReceivable struct {
+	ContratoID    string  `db:"contrato_id" json:"contrato_id"`
+	ContratoNome  string  `db:"contrato_nome" json:"contrato_nome"`
+	OpenCount     int     `db:"open_count" json:"open_count"`
+	OpenAmount    float64 `db:"open_amount" json:"open_amount"`
+	OverdueCount  int     `db:"overdue_count" json:"overdue_count"`
+	OverdueAmount float64 `db:"overdue_amount" json:"overdue_amount"`
+	Overdue1To30  float64 `db:"overdue_1_30" json:"overdue_1_30"`
+	Overdue31To60 float64 `db:"overdue_31_60" json:"overdue_31_60"`
+	Overdue61To90 float64 `db:"overdue_61_90" json:"overdue_61_90"`
+	OverdueOver90 float64 `db:"overdue_over_90" json:"overdue_over_90"`
+	MaxDaysLate   int     `db:"max_days_late" json:"max_days_late"`
+}
+
+// ContractReceivablesReport is the receivables and delinquency position across contracts
+type ContractReceivablesReport struct {
+	Contracts      []ContractReceivable `json:"contracts"`
+	OpenAmount     float64              `json:"open_amount"`
+	OverdueAmount  float64              `json:"overdue_amount"`
+	DelinquencyPct *float64             `json:"delinquency_pct,omitempty"` // overdue relative to the open balance
+}
+
+// ContractChargeAggregate is what a contract billed and received in a period
+type ContractChargeAggregate struct {
+	Period   string  `db:"period"`
+	Billed   float64 `db:"billed"`
+	Received float64 `db:"received"`
+}
@@ -0,0 +1,104 @@
+package entity
+
+import "time"
+
+// Contract document type constants
+const (
+	ContractDocumentTypeContract   = "contract"
+	ContractDocumentTypeAddendum   = "addendum"
+	ContractDocumentTypePriceTable = "price_table"
+	ContractDocumentTypeOther      = "other"
+)
+
+// ContractDocument represents a document attached to a contract (signed contract, addendum, price table)
+type ContractDocument struct {
+	ID             string     `db:"id" json:"id"`
+	ContratoID     string     `db:"contrato_id" json:"contrato_id"`
+	DocumentType   string     `db:"document_type" json:"document_type"`
+	Title          string     `db:"title" json:"title"`
+	Description    *string    `db:"description" json:"description,omitempty"`
+	CurrentVersion int        `db:"current_version" json:"current_version"`
+	IsActive       bool       `db:"is_active" json:"is_active"`
+	CreatedBy      *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	Versions []ContractDocumentVersion `db:"-" json:"versions,omitempty"`
+}
+
+// ContractDocumentVersion represents an uploaded revision of a contract document
+type ContractDocumentVersion struct {
+	ID             string     `db:"id" json:"id"`
+	DocumentID     string     `db:"document_id" json:"document_id"`
+	Version        int        `db:"version" json:"version"`
+	ObjectKey      string     `db:"object_key" json:"-"`
+	FileName       string     `db:"file_name" json:"file_name"`
+	ContentType    string     `db:"content_type" json:"content_type"`
+	Size           int64      `db:"size" json:"size"`
+	EffectiveFrom  time.Time  `db:"effective_from" json:"effective_from"`
+	EffectiveUntil *time.Time `db:"effective_until" json:"effective_until,omitempty"`
+	Notes          *string    `db:"notes" json:"notes,omitempty"`
+	UploadedBy     *string    `db:"uploaded_by" json:"uploaded_by,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ContractDocumentUpload carries the metadata of an uploaded file
+type ContractDocumentUpload struct {
+	FileName       string
+	ContentType    string
+	Size           int64
+	EffectiveFrom  *string // YYYY-MM-DD, defaults to today
+	EffectiveUntil *string // YYYY-MM-DD
+	Notes          *string
+}
+
+// CreateContractDocumentRequest represents the request to attach a new document to a contract
+type CreateContractDocumentRequest struct {
+	DocumentType string
+	Title        string
+	Description  *string
+	Upload       ContractDocumentUpload
+}
+
+// ContractDocumentFilters holds the filters for listing contract documents
+type ContractDocumentFilters struct {
+	DocumentType    string
+	IncludeInactive bool
+}
+
+// IsValidContractDocumentType checks if the document type is supported
+func IsValidContractDocumentType(t string) bool {
+	switch t {
+	case ContractDocumentTypeContract, ContractDocumentTypeAddendum, ContractDocumentTypePriceTable, ContractDocumentTypeOther:
+		return true
+	}
+	return false
+}
+
+// IsEffectiveOn reports whether the version is in effect on the given date
+func (v *ContractDocumentVersion) IsEffectiveOn(date time.Time) bool {
+	day := truncateDay(date)
+	if day.Before(truncateDay(v.EffectiveFrom)) {
+		return false
+	}
+	return v.EffectiveUntil == nil || !day.After(truncateDay(*v.EffectiveUntil))
+}
+
+// EffectiveContractDocumentVersion returns the most recent version in effect on the given date
+func EffectiveContractDocumentVersion(versions []ContractDocumentVersion, date time.Time) *ContractDocumentVersion {
+	var found *ContractDocumentVersion
+	for i := range versions {
+		v := &versions[i]
+		if !v.IsEffectiveOn(date) {
+			continue
+		}
+		if found == nil || v.Version > found.Version {
+			found = v
+		}
+	}
+	return found
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
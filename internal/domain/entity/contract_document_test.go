@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func docDate(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestContractDocumentVersion_IsEffectiveOn(t *testing.T) {
+	until := docDate("2024-12-31")
+	v := ContractDocumentVersion{EffectiveFrom: docDate("2024-01-01"), EffectiveUntil: &until}
+
+	tests := map[string]bool{
+		"2023-12-31": false,
+		"2024-01-01": true,
+		"2024-06-15": true,
+		"2024-12-31": true,
+		"2025-01-01": false,
+	}
+	for day, want := range tests {
+		if got := v.IsEffectiveOn(docDate(day)); got != want {
+			t.Errorf("IsEffectiveOn(%s) = %v, want %v", day, got, want)
+		}
+	}
+
+	open := ContractDocumentVersion{EffectiveFrom: docDate("2024-01-01")}
+	if !open.IsEffectiveOn(docDate("2030-01-01")) {
+		t.Error("expected open-ended version to stay effective")
+	}
+}
+
+func TestEffectiveContractDocumentVersion(t *testing.T) {
+	v1Until := docDate("2024-06-30")
+	versions := []ContractDocumentVersion{
+		{Version: 1, EffectiveFrom: docDate("2024-01-01"), EffectiveUntil: &v1Until},
+		{Version: 2, EffectiveFrom: docDate("2024-07-01")},
+		// Uploaded later but only effective next year
+		{Version: 3, EffectiveFrom: docDate("2025-01-01")},
+	}
+
+	tests := map[string]int{
+		"2023-06-01": 0,
+		"2024-03-10": 1,
+		"2024-07-01": 2,
+		"2024-12-31": 2,
+		"2025-02-01": 3,
+	}
+	for day, want := range tests {
+		got := EffectiveContractDocumentVersion(versions, docDate(day))
+		if want == 0 {
+			if got != nil {
+				t.Errorf("%s: expected no version, got %d", day, got.Version)
+			}
+			continue
+		}
+		if got == nil || got.Version != want {
+			t.Errorf("%s: expected version %d, got %+v", day, want, got)
+		}
+	}
+}
+
+func TestIsValidContractDocumentType(t *testing.T) {
+	for _, v := range []string{"contract", "addendum", "price_table", "other"} {
+		if !IsValidContractDocumentType(v) {
+			t.Errorf("expected %s to be valid", v)
+		}
+	}
+	if IsValidContractDocumentType("invoice") {
+		t.Error("expected invoice to be invalid")
+	}
+}
@@ -0,0 +1,156 @@
+package entity
+
+import "time"
+
+// Budget line kind constants
+const (
+	BudgetKindRevenue = "revenue"
+	BudgetKindCost    = "cost"
+)
+
+// Contract cost source constants
+const (
+	ContractCostSourceManual   = "manual"
+	ContractCostSourceTaskTime = "task_time"
+	ContractCostSourceSupplier = "supplier" // derived from supplier spend, never stored in contract_costs
+)
+
+// ContractBudgetLine is a planned monthly revenue or cost of a contract for a range of months
+type ContractBudgetLine struct {
+	ID            string     `db:"id" json:"id"`
+	ContratoID    string     `db:"contrato_id" json:"contrato_id"`
+	Kind          string     `db:"kind" json:"kind"`
+	Category      string     `db:"category" json:"category"`
+	MonthlyAmount float64    `db:"monthly_amount" json:"monthly_amount"`
+	StartPeriod   string     `db:"start_period" json:"start_period"`       // YYYY-MM
+	EndPeriod     *string    `db:"end_period" json:"end_period,omitempty"` // YYYY-MM, open-ended when nil
+	Notes         *string    `db:"notes" json:"notes,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// AppliesTo reports whether the line is budgeted for the given YYYY-MM period
+func (l *ContractBudgetLine) AppliesTo(period string) bool {
+	if period < l.StartPeriod {
+		return false
+	}
+	return l.EndPeriod == nil || period <= *l.EndPeriod
+}
+
+// CreateContractBudgetLineRequest represents the request to add a budget line
+type CreateContractBudgetLineRequest struct {
+	Kind          string  `json:"kind" binding:"required,oneof=revenue cost"`
+	Category      string  `json:"category" binding:"required"`
+	MonthlyAmount float64 `json:"monthly_amount" binding:"gte=0"`
+	StartPeriod   string  `json:"start_period" binding:"required"`
+	EndPeriod     *string `json:"end_period"`
+	Notes         *string `json:"notes"`
+}
+
+// UpdateContractBudgetLineRequest represents the request to update a budget line
+type UpdateContractBudgetLineRequest struct {
+	Category      *string  `json:"category"`
+	MonthlyAmount *float64 `json:"monthly_amount" binding:"omitempty,gte=0"`
+	StartPeriod   *string  `json:"start_period"`
+	EndPeriod     *string  `json:"end_period"`
+	Notes         *string  `json:"notes"`
+}
+
+// ContractCost is an actual cost recorded against a contract
+type ContractCost struct {
+	ID          string    `db:"id" json:"id"`
+	ContratoID  string    `db:"contrato_id" json:"contrato_id"`
+	Source      string    `db:"source" json:"source"`
+	Category    string    `db:"category" json:"category"`
+	Period      string    `db:"period" json:"period"` // YYYY-MM
+	Amount      float64   `db:"amount" json:"amount"`
+	Description *string   `db:"description" json:"description,omitempty"`
+	TaskID      *string   `db:"task_id" json:"task_id,omitempty"`
+	Hours       *float64  `db:"hours" json:"hours,omitempty"`
+	HourlyRate  *float64  `db:"hourly_rate" json:"hourly_rate,omitempty"`
+	CreatedBy   *string   `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// CreateContractCostRequest represents the request to record an actual cost.
+// Sending task_id with hours and hourly_rate records task time; otherwise amount is required.
+type CreateContractCostRequest struct {
+	Period      string   `json:"period" binding:"required"`
+	Category    string   `json:"category"`
+	Amount      *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Description *string  `json:"description"`
+	TaskID      *string  `json:"task_id"`
+	Hours       *float64 `json:"hours" binding:"omitempty,gt=0"`
+	HourlyRate  *float64 `json:"hourly_rate" binding:"omitempty,gt=0"`
+}
+
+// ContractCostFilters holds the filters for listing contract costs
+type ContractCostFilters struct {
+	From   string
+	To     string
+	Source string
+}
+
+// ContractCostAggregate is the total cost of a contract for a period, source and category
+type ContractCostAggregate struct {
+	Period   string  `db:"period"`
+	Source   string  `db:"source"`
+	Category string  `db:"category"`
+	Total    float64 `db:"total"`
+}
+
+// ContractFinancialCategory compares budget and actual cost of a category in a month
+type ContractFinancialCategory struct {
+	Category string  `json:"category"`
+	Budget   float64 `json:"budget"`
+	Actual   float64 `json:"actual"`
+	Variance float64 `json:"variance"`
+}
+
+// ContractFinancialMonth is the budget vs actual summary of a contract for one month
+type ContractFinancialMonth struct {
+	Period          string                      `json:"period"`
+	Revenue         float64                     `json:"revenue"`
+	Billed          float64                     `json:"billed"`      // monthly fee charged through contract billing
+	Received        float64                     `json:"received"`    // part of billed already paid
+	Outstanding     float64                     `json:"outstanding"` // billed - received
+	BudgetCost      float64                     `json:"budget_cost"`
+	ActualCost      float64                     `json:"actual_cost"`
+	SupplierCost    float64                     `json:"supplier_cost"`
+	TaskTimeCost    float64                     `json:"task_time_cost"`
+	OtherCost       float64                     `json:"other_cost"`
+	CostVariance    float64                     `json:"cost_variance"`               // budget_cost - actual_cost; negative means over budget
+	CostVariancePct *float64                    `json:"cost_variance_pct,omitempty"` // relative to budget_cost
+	BudgetMargin    float64                     `json:"budget_margin"`
+	ActualMargin    float64                     `json:"actual_margin"`
+	MarginPct       *float64                    `json:"margin_pct,omitempty"` // actual margin relative to revenue
+	Categories      []ContractFinancialCategory `json:"categories"`
+}
+
+// ContractFinancialReport is the monthly budget vs actual report of a contract
+type ContractFinancialReport struct {
+	ContratoID   string                   `json:"contrato_id"`
+	ContratoNome string                   `json:"contrato_nome"`
+	From         string                   `json:"from"`
+	To           string                   `json:"to"`
+	Months       []ContractFinancialMonth `json:"months"`
+	Totals       ContractFinancialMonth   `json:"totals"`
+}
+
+// PeriodsBetween lists the YYYY-MM periods from from to to, inclusive
+func PeriodsBetween(from, to string) []string {
+	start, err := time.Parse("2006-01", from)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse("2006-01", to)
+	if err != nil {
+		return nil
+	}
+
+	var periods []string
+	for m := start; !m.After(end); m = m.AddDate(0, 1, 0) {
+		periods = append(periods, m.Format("2006-01"))
+	}
+	return periods
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// ContractKPIWindowDays is the trailing window, in days, the audit and inspection KPIs of a
+// snapshot are measured over
+const ContractKPIWindowDays = 30
+
+// ContractKPISnapshot holds the KPIs of a contract as measured on one day. Audits and
+// inspections are counted over the trailing window; tasks as they stood on the day.
+type ContractKPISnapshot struct {
+	ID                       string     `db:"id" json:"id"`
+	ContratoID               string     `db:"contrato_id" json:"contrato_id"`
+	SnapshotDate             time.Time  `db:"snapshot_date" json:"snapshot_date"`
+	AuditCount               int        `db:"audit_count" json:"audit_count"`
+	AverageAuditScore        *float64   `db:"average_audit_score" json:"average_audit_score,omitempty"`
+	LastAuditScore           *float64   `db:"last_audit_score" json:"last_audit_score,omitempty"`
+	OpenTasks                int        `db:"open_tasks" json:"open_tasks"`
+	OverdueTasks             int        `db:"overdue_tasks" json:"overdue_tasks"`
+	InspectionsDue           int        `db:"inspections_due" json:"inspections_due"`
+	InspectionsCompleted     int        `db:"inspections_completed" json:"inspections_completed"`
+	InspectionComplianceRate *float64   `db:"inspection_compliance_rate" json:"inspection_compliance_rate,omitempty"` // percentage of due inspections
+	CreatedAt                time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt                *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// ContractKPIHistory is the snapshot history of a contract within a date range, oldest first
+type ContractKPIHistory struct {
+	ContratoID string                `json:"contrato_id"`
+	From       string                `json:"from"`
+	To         string                `json:"to"`
+	Snapshots  []ContractKPISnapshot `json:"snapshots"`
+}
+
+// ContractKPISnapshotResult summarizes a snapshot run
+type ContractKPISnapshotResult struct {
+	SnapshotDate string `json:"snapshot_date"`
+	Contracts    int    `json:"contracts"`
+}
@@ -0,0 +1,145 @@
+package entity
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Contract renewal status constants
+const (
+	ContractRenewalStatusPending       = "pending"
+	ContractRenewalStatusRenegotiating = "renegotiating"
+	ContractRenewalStatusRenewed       = "renewed"
+	ContractRenewalStatusLost          = "lost"
+)
+
+// contractRenewalTransitions lists the allowed status changes
+var contractRenewalTransitions = map[string][]string{
+	ContractRenewalStatusPending:       {ContractRenewalStatusRenegotiating, ContractRenewalStatusRenewed, ContractRenewalStatusLost},
+	ContractRenewalStatusRenegotiating: {ContractRenewalStatusRenewed, ContractRenewalStatusLost},
+}
+
+// ContractRenewal tracks the renewal negotiation of a contract reaching its end date
+type ContractRenewal struct {
+	ID                string     `db:"id" json:"id"`
+	ContratoID        string     `db:"contrato_id" json:"contrato_id"`
+	Status            string     `db:"status" json:"status"`
+	CurrentEndDate    *time.Time `db:"current_end_date" json:"current_end_date,omitempty"`
+	NewStartDate      *time.Time `db:"new_start_date" json:"new_start_date,omitempty"`
+	NewEndDate        *time.Time `db:"new_end_date" json:"new_end_date,omitempty"`
+	NewMonthlyValue   *float64   `db:"new_monthly_value" json:"new_monthly_value,omitempty"`
+	AdjustmentPercent *float64   `db:"adjustment_percent" json:"adjustment_percent,omitempty"`
+	Terms             *string    `db:"terms" json:"terms,omitempty"`
+	Notes             *string    `db:"notes" json:"notes,omitempty"`
+	CreatedBy         *string    `db:"created_by" json:"created_by,omitempty"`
+	DecidedBy         *string    `db:"decided_by" json:"decided_by,omitempty"`
+	DecidedAt         *time.Time `db:"decided_at" json:"decided_at,omitempty"`
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt         *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	// Joined fields
+	ContratoNome string `db:"contrato_nome" json:"contrato_nome,omitempty"`
+}
+
+// IsOpen reports whether the renewal is still being negotiated
+func (r *ContractRenewal) IsOpen() bool {
+	return r.Status == ContractRenewalStatusPending || r.Status == ContractRenewalStatusRenegotiating
+}
+
+// ContractRenewalAlert records an expiration alert already sent for a contract end date
+type ContractRenewalAlert struct {
+	ID            string    `db:"id" json:"id"`
+	ContratoID    string    `db:"contrato_id" json:"contrato_id"`
+	EndDate       time.Time `db:"end_date" json:"end_date"`
+	ThresholdDays int       `db:"threshold_days" json:"threshold_days"`
+	Recipients    int       `db:"recipients" json:"recipients"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+// UpcomingContractRenewal is a contract approaching its end date with its open renewal, if any
+type UpcomingContractRenewal struct {
+	ContratoID    string     `db:"contrato_id" json:"contrato_id"`
+	ContratoNome  string     `db:"contrato_nome" json:"contrato_nome"`
+	GestorID      string     `db:"gestor_id" json:"gestor_id"`
+	DataFim       time.Time  `db:"data_fim" json:"data_fim"`
+	DaysLeft      int        `db:"-" json:"days_left"`
+	RenewalID     *string    `db:"renewal_id" json:"renewal_id,omitempty"`
+	RenewalStatus *string    `db:"renewal_status" json:"renewal_status,omitempty"`
+	LastAlertDays *int       `db:"last_alert_days" json:"last_alert_days,omitempty"`
+	LastAlertAt   *time.Time `db:"last_alert_at" json:"last_alert_at,omitempty"`
+}
+
+// ContractRenewalAlertResult summarizes an alert sweep
+type ContractRenewalAlertResult struct {
+	Checked        int `json:"checked"`
+	AlertsSent     int `json:"alerts_sent"`
+	Notifications  int `json:"notifications"`
+	RenewalsOpened int `json:"renewals_opened"`
+}
+
+// CreateContractRenewalRequest represents the request to open a renewal for a contract
+type CreateContractRenewalRequest struct {
+	NewStartDate      *string  `json:"new_start_date"` // YYYY-MM-DD
+	NewEndDate        *string  `json:"new_end_date"`   // YYYY-MM-DD
+	NewMonthlyValue   *float64 `json:"new_monthly_value" binding:"omitempty,gte=0"`
+	AdjustmentPercent *float64 `json:"adjustment_percent"`
+	Terms             *string  `json:"terms"`
+	Notes             *string  `json:"notes"`
+}
+
+// UpdateContractRenewalRequest represents the request to update terms or move a renewal forward
+type UpdateContractRenewalRequest struct {
+	Status            *string  `json:"status" binding:"omitempty,oneof=renegotiating renewed lost"`
+	NewStartDate      *string  `json:"new_start_date"`
+	NewEndDate        *string  `json:"new_end_date"`
+	NewMonthlyValue   *float64 `json:"new_monthly_value" binding:"omitempty,gte=0"`
+	AdjustmentPercent *float64 `json:"adjustment_percent"`
+	Terms             *string  `json:"terms"`
+	Notes             *string  `json:"notes"`
+}
+
+// ContractRenewalFilters holds the filters for listing renewals
+type ContractRenewalFilters struct {
+	Status string
+}
+
+// CanTransitionContractRenewal reports whether a renewal may move from one status to another
+func CanTransitionContractRenewal(from, to string) bool {
+	for _, s := range contractRenewalTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// DaysUntil returns the number of whole days from now until the given date
+func DaysUntil(end, now time.Time) int {
+	return int(math.Round(truncateDay(end).Sub(truncateDay(now)).Hours() / 24))
+}
+
+// DueRenewalAlertThreshold returns the alert threshold to send for a contract
+// ending in daysLeft days. Only the tightest reached threshold is considered,
+// so a missed sweep does not flood the team with stale alerts.
+func DueRenewalAlertThreshold(daysLeft int, thresholds []int, sent []int) (int, bool) {
+	if daysLeft < 0 {
+		return 0, false
+	}
+
+	sorted := append([]int(nil), thresholds...)
+	sort.Ints(sorted)
+
+	for _, t := range sorted {
+		if daysLeft > t {
+			continue
+		}
+		for _, s := range sent {
+			if s <= t {
+				return 0, false
+			}
+		}
+		return t, true
+	}
+	return 0, false
+}
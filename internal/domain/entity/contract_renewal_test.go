@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDueRenewalAlertThreshold(t *testing.T) {
+	thresholds := []int{90, 60, 30}
+
+	tests := []struct {
+		name     string
+		daysLeft int
+		sent     []int
+		want     int
+		wantOK   bool
+	}{
+		{"too far", 120, nil, 0, false},
+		{"first alert", 90, nil, 90, true},
+		{"90 already sent", 75, []int{90}, 0, false},
+		{"second alert", 60, []int{90}, 60, true},
+		{"missed sweeps only send tightest", 25, nil, 30, true},
+		{"30 already sent", 10, []int{90, 60, 30}, 0, false},
+		{"expired", -1, nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := DueRenewalAlertThreshold(tt.daysLeft, thresholds, tt.sent)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: got (%d, %v), want (%d, %v)", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDaysUntil(t *testing.T) {
+	now := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC)
+	end := time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC)
+	if got := DaysUntil(end, now); got != 90 {
+		t.Errorf("expected 90 days, got %d", got)
+	}
+	if got := DaysUntil(now, now); got != 0 {
+		t.Errorf("expected 0 days, got %d", got)
+	}
+}
+
+func TestCanTransitionContractRenewal(t *testing.T) {
+	if !CanTransitionContractRenewal(ContractRenewalStatusPending, ContractRenewalStatusRenegotiating) {
+		t.Error("expected pending -> renegotiating")
+	}
+	if !CanTransitionContractRenewal(ContractRenewalStatusRenegotiating, ContractRenewalStatusLost) {
+		t.Error("expected renegotiating -> lost")
+	}
+	if CanTransitionContractRenewal(ContractRenewalStatusRenewed, ContractRenewalStatusRenegotiating) {
+		t.Error("renewed must be final")
+	}
+	if CanTransitionContractRenewal(ContractRenewalStatusLost, ContractRenewalStatusRenewed) {
+		t.Error("lost must be final")
+	}
+}
@@ -19,6 +19,7 @@ type Contrato struct {
 	Ativo           bool       `db:"ativo" json:"ativo"`
 	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt       *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+	DeletedAt       *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 }
 
 // ContratoWithGestor represents a contract with its gestor information
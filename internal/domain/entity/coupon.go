@@ -1,6 +1,10 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/condotrack/api/pkg/apperror"
+)
 
 // Coupon represents a discount coupon.
 type Coupon struct {
@@ -52,28 +56,42 @@ const (
 	CouponAppliesToSpecific = "specific_courses"
 )
 
-// CalculateDiscount calculates the actual discount amount for a given order amount.
-func (c *Coupon) CalculateDiscount(orderAmount float64) float64 {
+// Coupon eligibility errors
+var (
+	ErrCouponNotFound      = apperror.New(apperror.CodeCouponNotFound, "invalid coupon code")
+	ErrCouponUserLimit     = apperror.New(apperror.CodeCouponUserLimit, "coupon usage limit exceeded for this user")
+	ErrCouponNotApplicable = apperror.New(apperror.CodeCouponNotApplicable, "coupon is not applicable to this order")
+	ErrCouponInactive      = apperror.New(apperror.CodeCouponInactive, "coupon is inactive")
+	ErrCouponMinimumAmount = apperror.New(apperror.CodeCouponMinimumAmount, "order amount is below the coupon minimum")
+	ErrCouponUsageLimit    = apperror.New(apperror.CodeCouponUsageLimit, "coupon usage limit reached")
+	ErrCouponNotStarted    = apperror.New(apperror.CodeCouponNotStarted, "coupon is not valid yet")
+	ErrCouponExpired       = apperror.New(apperror.CodeCouponExpired, "coupon has expired")
+)
+
+// CheckEligibility returns why the coupon cannot be applied to an order, or nil when it can.
+// Per-user limits need the usage history and are checked by the callers.
+func (c *Coupon) CheckEligibility(orderAmount float64, now time.Time) error {
 	if !c.IsActive {
-		return 0
+		return ErrCouponInactive
 	}
-
-	// Check minimum order amount
 	if c.MinimumOrderAmount != nil && orderAmount < *c.MinimumOrderAmount {
-		return 0
+		return ErrCouponMinimumAmount.WithDetails("minimum_order_amount", *c.MinimumOrderAmount)
 	}
-
-	// Check usage limits
 	if c.MaxUses != nil && c.CurrentUses >= *c.MaxUses {
-		return 0
+		return ErrCouponUsageLimit
 	}
-
-	// Check validity period
-	now := time.Now()
 	if c.StartsAt != nil && now.Before(*c.StartsAt) {
-		return 0
+		return ErrCouponNotStarted.WithDetails("starts_at", *c.StartsAt)
 	}
 	if c.ExpiresAt != nil && now.After(*c.ExpiresAt) {
+		return ErrCouponExpired.WithDetails("expires_at", *c.ExpiresAt)
+	}
+	return nil
+}
+
+// CalculateDiscount calculates the actual discount amount for a given order amount.
+func (c *Coupon) CalculateDiscount(orderAmount float64) float64 {
+	if c.CheckEligibility(orderAmount, time.Now()) != nil {
 		return 0
 	}
 
@@ -1,8 +1,11 @@
 package entity
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/condotrack/api/pkg/apperror"
 )
 
 func TestCoupon_CalculateDiscount_Percentage(t *testing.T) {
@@ -205,3 +208,56 @@ func TestCoupon_CalculateDiscount_ZeroOrder(t *testing.T) {
 		t.Errorf("expected 0 for zero order amount, got %f", discount)
 	}
 }
+
+func TestCoupon_CheckEligibility_Reasons(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	minimum := 100.0
+	maxUses := 5
+
+	tests := []struct {
+		name   string
+		coupon Coupon
+		amount float64
+		want   error
+	}{
+		{"eligible", Coupon{IsActive: true}, 50, nil},
+		{"inactive", Coupon{IsActive: false}, 50, ErrCouponInactive},
+		{"below minimum", Coupon{IsActive: true, MinimumOrderAmount: &minimum}, 50, ErrCouponMinimumAmount},
+		{"usage limit", Coupon{IsActive: true, MaxUses: &maxUses, CurrentUses: 5}, 50, ErrCouponUsageLimit},
+		{"not started", Coupon{IsActive: true, StartsAt: &future}, 50, ErrCouponNotStarted},
+		{"expired", Coupon{IsActive: true, ExpiresAt: &past}, 50, ErrCouponExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.coupon.CheckEligibility(tt.amount, now)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("expected eligible, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCoupon_CheckEligibility_MinimumDetails(t *testing.T) {
+	minimum := 100.0
+	coupon := &Coupon{IsActive: true, MinimumOrderAmount: &minimum}
+
+	appErr, ok := apperror.As(coupon.CheckEligibility(50, time.Now()))
+	if !ok {
+		t.Fatal("expected a coded error")
+	}
+	if appErr.Code != apperror.CodeCouponMinimumAmount {
+		t.Errorf("expected %s, got %s", apperror.CodeCouponMinimumAmount, appErr.Code)
+	}
+	if appErr.Details["minimum_order_amount"] != 100.0 {
+		t.Errorf("expected the minimum in the details, got %v", appErr.Details)
+	}
+}
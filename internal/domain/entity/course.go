@@ -49,3 +49,12 @@ type CourseListResponse struct {
 	Page    int      `json:"page"`
 	PerPage int      `json:"per_page"`
 }
+
+// EffectivePrice returns the price a student pays for the course, honoring a
+// discount price when one is set below the list price
+func (c *Course) EffectivePrice() float64 {
+	if c.DiscountPrice != nil && *c.DiscountPrice > 0 && *c.DiscountPrice < c.Price {
+		return *c.DiscountPrice
+	}
+	return c.Price
+}
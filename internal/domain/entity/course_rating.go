@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// CourseRating is the rating, from 1 to 5, a student gave a course they are enrolled in.
+// A student has one rating per course; rating again replaces it.
+type CourseRating struct {
+	ID           string     `db:"id" json:"id"`
+	CourseID     string     `db:"course_id" json:"course_id"`
+	StudentID    string     `db:"student_id" json:"student_id"`
+	EnrollmentID string     `db:"enrollment_id" json:"enrollment_id"`
+	Rating       int        `db:"rating" json:"rating"`
+	Comment      *string    `db:"comment" json:"comment,omitempty"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// RateCourseRequest represents the request of a student to rate the course of an enrollment
+type RateCourseRequest struct {
+	Rating  int     `json:"rating" binding:"required,min=1,max=5"`
+	Comment *string `json:"comment,omitempty" binding:"omitempty,max=2000"`
+}
+
+// CourseRatingSummary is the average rating of a course and how many students rated it
+type CourseRatingSummary struct {
+	CourseID string  `db:"course_id" json:"-"`
+	Average  float64 `db:"average" json:"average"`
+	Count    int     `db:"count" json:"count"`
+}
+
+// CanRateEnrollment reports whether the student of an enrollment may rate its course: the
+// enrollment must be active or completed, not pending payment, cancelled or expired
+func CanRateEnrollment(status string) bool {
+	return status == EnrollmentStatusActive || status == EnrollmentStatusCompleted
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "testing"
+
+func TestCourseEffectivePrice(t *testing.T) {
+	discount := 80.0
+	zero := 0.0
+	higher := 150.0
+
+	tests := []struct {
+		name     string
+		course   Course
+		expected float64
+	}{
+		{"no discount", Course{Price: 100}, 100},
+		{"discount applied", Course{Price: 100, DiscountPrice: &discount}, 80},
+		{"zero discount ignored", Course{Price: 100, DiscountPrice: &zero}, 100},
+		{"discount above price ignored", Course{Price: 100, DiscountPrice: &higher}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.course.EffectivePrice(); got != tt.expected {
+				t.Errorf("EffectivePrice() = %f, want %f", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Refund policy constants
+const (
+	RefundPolicyFull     = "full"     // within the CDC withdrawal window (art. 49)
+	RefundPolicyProrated = "prorated" // after the window, proportional to unused access
+	RefundPolicyNone     = "none"     // nothing paid or nothing left to refund
+)
+
+// Refund basis constants describe how a prorated refund was measured
+const (
+	RefundBasisWindow   = "withdrawal_window"
+	RefundBasisTime     = "remaining_time"
+	RefundBasisProgress = "remaining_progress"
+	RefundBasisNoCharge = "no_charge"
+)
+
+// Cancellation refund status constants
+const (
+	CancellationRefundNotApplicable = "not_applicable"
+	CancellationRefundRequested     = "requested"
+	CancellationRefundFailed        = "failed"
+)
+
+// DefaultWithdrawalDays is the CDC withdrawal period for purchases made online
+const DefaultWithdrawalDays = 7
+
+// EnrollmentCancellation records a cancellation and the refund policy decision applied to it
+type EnrollmentCancellation struct {
+	ID                  string    `db:"id" json:"id"`
+	EnrollmentID        string    `db:"enrollment_id" json:"enrollment_id"`
+	PaymentID           *string   `db:"payment_id" json:"payment_id,omitempty"`
+	Policy              string    `db:"policy" json:"policy"`
+	Basis               string    `db:"basis" json:"basis"`
+	DaysSincePurchase   int       `db:"days_since_purchase" json:"days_since_purchase"`
+	PaidAmount          float64   `db:"paid_amount" json:"paid_amount"`
+	RefundPercent       float64   `db:"refund_percent" json:"refund_percent"`
+	RefundAmount        float64   `db:"refund_amount" json:"refund_amount"`
+	RefundStatus        string    `db:"refund_status" json:"refund_status"`
+	GatewayRefundStatus *string   `db:"gateway_refund_status" json:"gateway_refund_status,omitempty"`
+	RevenueSplitID      *string   `db:"revenue_split_id" json:"revenue_split_id,omitempty"`
+	InstructorClawback  float64   `db:"instructor_clawback" json:"instructor_clawback"`
+	Reason              *string   `db:"reason" json:"reason,omitempty"`
+	CancelledBy         *string   `db:"cancelled_by" json:"cancelled_by,omitempty"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
+// CancelEnrollmentRequest represents the request to cancel an enrollment
+type CancelEnrollmentRequest struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
+// CancelEnrollmentResponse represents the result of a cancellation
+type CancelEnrollmentResponse struct {
+	Enrollment   *Matricula              `json:"enrollment"`
+	Cancellation *EnrollmentCancellation `json:"cancellation"`
+	RevenueSplit *RevenueSplit           `json:"revenue_split,omitempty"`
+	// RevenueSplits are every split the refund was taken from, the first one being RevenueSplit
+	RevenueSplits []RevenueSplit `json:"revenue_splits,omitempty"`
+}
+
+// RefundDecision is the outcome of applying the refund policy to a purchase
+type RefundDecision struct {
+	Policy            string
+	Basis             string
+	DaysSincePurchase int
+	RefundPercent     float64
+	RefundAmount      float64
+}
+
+// DecideRefund applies the refund policy to a paid enrollment.
+// Inside the withdrawal window the full amount is returned. After it, the refund
+// is proportional to the access time left until expiration or, for enrollments
+// without an expiration date, to the course progress not yet completed.
+func DecideRefund(paid float64, purchasedAt time.Time, expiration *time.Time, progress float64, now time.Time, windowDays int) RefundDecision {
+	days := int(now.Sub(purchasedAt).Hours() / 24)
+	if days < 0 {
+		days = 0
+	}
+	decision := RefundDecision{DaysSincePurchase: days}
+
+	if paid <= 0 {
+		decision.Policy = RefundPolicyNone
+		decision.Basis = RefundBasisNoCharge
+		return decision
+	}
+
+	if now.Before(purchasedAt.AddDate(0, 0, windowDays)) {
+		decision.Policy = RefundPolicyFull
+		decision.Basis = RefundBasisWindow
+		decision.RefundPercent = 100
+		decision.RefundAmount = paid
+		return decision
+	}
+
+	var remaining float64
+	if expiration != nil && expiration.After(purchasedAt) {
+		decision.Basis = RefundBasisTime
+		total := expiration.Sub(purchasedAt).Hours()
+		left := expiration.Sub(now).Hours()
+		remaining = left / total
+	} else {
+		decision.Basis = RefundBasisProgress
+		remaining = (100 - progress) / 100
+	}
+	remaining = math.Max(0, math.Min(1, remaining))
+
+	decision.RefundPercent = math.Round(remaining*10000) / 100
+	decision.RefundAmount = math.Round(paid*remaining*100) / 100
+	if decision.RefundAmount <= 0 {
+		decision.Policy = RefundPolicyNone
+		decision.RefundPercent = 0
+		decision.RefundAmount = 0
+		return decision
+	}
+	decision.Policy = RefundPolicyProrated
+	return decision
+}
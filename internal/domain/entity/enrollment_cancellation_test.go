@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecideRefund_WithinWithdrawalWindow(t *testing.T) {
+	purchased := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := purchased.AddDate(0, 0, 6)
+
+	d := DecideRefund(497, purchased, nil, 80, now, DefaultWithdrawalDays)
+	if d.Policy != RefundPolicyFull {
+		t.Errorf("expected full policy, got %s", d.Policy)
+	}
+	if d.RefundAmount != 497 {
+		t.Errorf("expected full refund 497, got %.2f", d.RefundAmount)
+	}
+	if d.DaysSincePurchase != 6 {
+		t.Errorf("expected 6 days since purchase, got %d", d.DaysSincePurchase)
+	}
+}
+
+func TestDecideRefund_ProratedByRemainingTime(t *testing.T) {
+	purchased := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiration := purchased.AddDate(0, 0, 100)
+	now := purchased.AddDate(0, 0, 25)
+
+	d := DecideRefund(200, purchased, &expiration, 0, now, DefaultWithdrawalDays)
+	if d.Policy != RefundPolicyProrated || d.Basis != RefundBasisTime {
+		t.Fatalf("expected prorated by time, got %s/%s", d.Policy, d.Basis)
+	}
+	if d.RefundAmount != 150 {
+		t.Errorf("expected refund 150, got %.2f", d.RefundAmount)
+	}
+	if d.RefundPercent != 75 {
+		t.Errorf("expected 75%%, got %.2f", d.RefundPercent)
+	}
+}
+
+func TestDecideRefund_ProratedByProgress(t *testing.T) {
+	purchased := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := purchased.AddDate(0, 1, 0)
+
+	d := DecideRefund(300, purchased, nil, 40, now, DefaultWithdrawalDays)
+	if d.Basis != RefundBasisProgress {
+		t.Fatalf("expected progress basis, got %s", d.Basis)
+	}
+	if d.RefundAmount != 180 {
+		t.Errorf("expected refund 180, got %.2f", d.RefundAmount)
+	}
+}
+
+func TestDecideRefund_NothingLeft(t *testing.T) {
+	purchased := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiration := purchased.AddDate(0, 1, 0)
+	now := purchased.AddDate(0, 2, 0)
+
+	d := DecideRefund(300, purchased, &expiration, 0, now, DefaultWithdrawalDays)
+	if d.Policy != RefundPolicyNone || d.RefundAmount != 0 {
+		t.Errorf("expected no refund after expiration, got %s %.2f", d.Policy, d.RefundAmount)
+	}
+}
+
+func TestDecideRefund_NoCharge(t *testing.T) {
+	now := time.Now()
+	d := DecideRefund(0, now, nil, 0, now, DefaultWithdrawalDays)
+	if d.Policy != RefundPolicyNone || d.Basis != RefundBasisNoCharge {
+		t.Errorf("expected no-charge decision, got %s/%s", d.Policy, d.Basis)
+	}
+}
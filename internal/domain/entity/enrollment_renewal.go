@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+// Enrollment renewal status constants
+const (
+	RenewalStatusPending   = "pending"
+	RenewalStatusConfirmed = "confirmed"
+	RenewalStatusCancelled = "cancelled"
+)
+
+// EnrollmentRenewal links a renewal payment to the original enrollment.
+// The expiration date is only extended once the payment is confirmed.
+type EnrollmentRenewal struct {
+	ID                 string     `db:"id" json:"id"`
+	EnrollmentID       string     `db:"enrollment_id" json:"enrollment_id"`
+	PaymentID          string     `db:"payment_id" json:"payment_id"`
+	BaseAmount         float64    `db:"base_amount" json:"base_amount"`
+	DiscountPercent    float64    `db:"discount_percent" json:"discount_percent"`
+	FinalAmount        float64    `db:"final_amount" json:"final_amount"`
+	ExtensionDays      int        `db:"extension_days" json:"extension_days"`
+	PreviousExpiration *time.Time `db:"previous_expiration" json:"previous_expiration,omitempty"`
+	NewExpiration      *time.Time `db:"new_expiration" json:"new_expiration,omitempty"`
+	Status             string     `db:"status" json:"status"`
+	ConfirmedAt        *time.Time `db:"confirmed_at" json:"confirmed_at,omitempty"`
+	CreatedAt          time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt          *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// RenewalExpiration calculates the new expiration date for a renewal.
+// Time left on a still-valid enrollment is preserved; expired (or never
+// expiring) enrollments are extended from now.
+func RenewalExpiration(current *time.Time, now time.Time, days int) time.Time {
+	base := now
+	if current != nil && current.After(now) {
+		base = *current
+	}
+	return base.AddDate(0, 0, days)
+}
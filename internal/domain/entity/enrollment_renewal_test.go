@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenewalExpiration_ActiveEnrollmentKeepsRemainingTime(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	current := now.AddDate(0, 0, 30)
+
+	got := RenewalExpiration(&current, now, 365)
+	want := current.AddDate(0, 0, 365)
+	if !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRenewalExpiration_ExpiredEnrollmentStartsFromNow(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, -2, 0)
+
+	got := RenewalExpiration(&past, now, 90)
+	want := now.AddDate(0, 0, 90)
+	if !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRenewalExpiration_NoExpiration(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	got := RenewalExpiration(nil, now, 30)
+	want := now.AddDate(0, 0, 30)
+	if !got.Equal(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
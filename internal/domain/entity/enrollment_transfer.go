@@ -0,0 +1,55 @@
+package entity
+
+import "time"
+
+// Enrollment transfer type constants
+const (
+	TransferTypeCourse  = "course"
+	TransferTypeStudent = "student"
+	TransferTypeBoth    = "course_and_student"
+)
+
+// EnrollmentTransfer records a change of course and/or student on an enrollment
+type EnrollmentTransfer struct {
+	ID                   string    `db:"id" json:"id"`
+	EnrollmentID         string    `db:"enrollment_id" json:"enrollment_id"`
+	TransferType         string    `db:"transfer_type" json:"transfer_type"`
+	FromStudentID        string    `db:"from_student_id" json:"from_student_id"`
+	FromStudentName      string    `db:"from_student_name" json:"from_student_name"`
+	ToStudentID          string    `db:"to_student_id" json:"to_student_id"`
+	ToStudentName        string    `db:"to_student_name" json:"to_student_name"`
+	FromCourseID         string    `db:"from_course_id" json:"from_course_id"`
+	FromCourseName       string    `db:"from_course_name" json:"from_course_name"`
+	ToCourseID           string    `db:"to_course_id" json:"to_course_id"`
+	ToCourseName         string    `db:"to_course_name" json:"to_course_name"`
+	FromAmount           float64   `db:"from_amount" json:"from_amount"`
+	ToAmount             float64   `db:"to_amount" json:"to_amount"`
+	PriceDifference      float64   `db:"price_difference" json:"price_difference"`
+	RevenueSplitID       *string   `db:"revenue_split_id" json:"revenue_split_id,omitempty"`
+	FromInstructorAmount *float64  `db:"from_instructor_amount" json:"from_instructor_amount,omitempty"`
+	ToInstructorAmount   *float64  `db:"to_instructor_amount" json:"to_instructor_amount,omitempty"`
+	Reason               *string   `db:"reason" json:"reason,omitempty"`
+	TransferredBy        *string   `db:"transferred_by" json:"transferred_by,omitempty"`
+	CreatedAt            time.Time `db:"created_at" json:"created_at"`
+}
+
+// TransferEnrollmentRequest represents the request to move an enrollment to another
+// course, another student, or both. At least one target must be provided.
+type TransferEnrollmentRequest struct {
+	ToCourseID     *string `json:"to_course_id,omitempty"`
+	ToStudentID    *string `json:"to_student_id,omitempty"`
+	ToStudentName  *string `json:"to_student_name,omitempty"`
+	ToStudentEmail *string `json:"to_student_email,omitempty" binding:"omitempty,email"`
+	ToStudentCPF   *string `json:"to_student_cpf,omitempty"`
+	ToStudentPhone *string `json:"to_student_phone,omitempty"`
+	Reason         *string `json:"reason,omitempty"`
+}
+
+// TransferEnrollmentResponse represents the result of an enrollment transfer
+type TransferEnrollmentResponse struct {
+	Enrollment   *Matricula          `json:"enrollment"`
+	Transfer     *EnrollmentTransfer `json:"transfer"`
+	RevenueSplit *RevenueSplit       `json:"revenue_split,omitempty"`
+	// RevenueSplits are every split moved to the target course, the first one being RevenueSplit
+	RevenueSplits []RevenueSplit `json:"revenue_splits,omitempty"`
+}
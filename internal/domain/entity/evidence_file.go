@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/listquery"
+)
+
+// Evidence entity type constants: what an evidence file documents
+const (
+	EvidenceEntityAudit      = "audit"
+	EvidenceEntityInspection = "inspection"
+	EvidenceEntityTask       = "task"
+)
+
+// Evidence tag limits
+const (
+	MaxEvidenceTags      = 20
+	MaxEvidenceTagLength = 50
+)
+
+// EvidenceFile is the metadata of a file in the evidence bucket
+type EvidenceFile struct {
+	ID           string     `db:"id" json:"id"`
+	Filename     string     `db:"filename" json:"filename"`
+	OriginalName string     `db:"original_name" json:"original_name"`
+	ContentType  string     `db:"content_type" json:"content_type"`
+	Size         int64      `db:"size" json:"size"`
+	Description  *string    `db:"description" json:"description,omitempty"`
+	Tags         StringList `db:"tags" json:"tags"`
+	CapturedAt   *time.Time `db:"captured_at" json:"captured_at,omitempty"`
+	EntityType   *string    `db:"entity_type" json:"entity_type,omitempty"`
+	EntityID     *string    `db:"entity_id" json:"entity_id,omitempty"`
+	UploadedBy   *string    `db:"uploaded_by" json:"uploaded_by,omitempty"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+
+	URL string `db:"-" json:"url"`
+}
+
+// UploadEvidenceRequest carries the file and metadata of an evidence upload
+type UploadEvidenceRequest struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Description *string
+	Tags        []string
+	CapturedAt  *string // RFC 3339 or YYYY-MM-DD
+	EntityType  *string
+	EntityID    *string
+}
+
+// EvidenceFilter holds the filters for listing evidence files
+type EvidenceFilter struct {
+	EntityType *string
+	EntityID   *string
+	Tags       []string // files must have every tag
+	Search     *string  // substring of the original name or description
+	Page       int
+	PerPage    int
+	Query      listquery.Query // filter[...] and sort parameters
+}
+
+// EvidenceListResponse represents a page of evidence files
+type EvidenceListResponse struct {
+	Evidence []EvidenceFile `json:"evidence"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	PerPage  int            `json:"per_page"`
+}
+
+// ValidEvidenceEntityType checks if evidence can be linked to the entity type
+func ValidEvidenceEntityType(t string) bool {
+	switch t {
+	case EvidenceEntityAudit, EvidenceEntityInspection, EvidenceEntityTask:
+		return true
+	}
+	return false
+}
+
+// NormalizeEvidenceTags lowercases and trims the tags, splitting comma separated values and
+// dropping blanks and duplicates. ok is false when a tag is too long or there are too many.
+func NormalizeEvidenceTags(tags []string) (StringList, bool) {
+	list := StringList{}
+	for _, raw := range tags {
+		for _, tag := range strings.Split(raw, ",") {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || list.Contains(tag) {
+				continue
+			}
+			if len([]rune(tag)) > MaxEvidenceTagLength {
+				return nil, false
+			}
+			list = append(list, tag)
+		}
+	}
+	return list, len(list) <= MaxEvidenceTags
+}
+
+// ParseCaptureDate reads a capture date given as RFC 3339 or as a day (YYYY-MM-DD)
+func ParseCaptureDate(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEvidenceTags(t *testing.T) {
+	tags, ok := NormalizeEvidenceTags([]string{" Fachada ", "infiltração,fachada", "", "Bloco A"})
+	if !ok {
+		t.Fatal("expected tags to be valid")
+	}
+	want := []string{"fachada", "infiltração", "bloco a"}
+	if len(tags) != len(want) {
+		t.Fatalf("tags = %v, want %v", tags, want)
+	}
+	for i := range want {
+		if tags[i] != want[i] {
+			t.Errorf("tags[%d] = %q, want %q", i, tags[i], want[i])
+		}
+	}
+}
+
+func TestNormalizeEvidenceTags_Limits(t *testing.T) {
+	if _, ok := NormalizeEvidenceTags([]string{strings.Repeat("a", MaxEvidenceTagLength+1)}); ok {
+		t.Error("expected a tag over the length limit to be rejected")
+	}
+
+	many := make([]string, MaxEvidenceTags+1)
+	for i := range many {
+		many[i] = strings.Repeat("t", i+1)
+	}
+	if _, ok := NormalizeEvidenceTags(many); ok {
+		t.Error("expected more than MaxEvidenceTags tags to be rejected")
+	}
+
+	tags, ok := NormalizeEvidenceTags(nil)
+	if !ok || tags == nil || len(tags) != 0 {
+		t.Errorf("expected an empty list for no tags, got %v", tags)
+	}
+}
+
+func TestParseCaptureDate(t *testing.T) {
+	if d, ok := ParseCaptureDate("2024-03-05"); !ok || d.Day() != 5 {
+		t.Errorf("day format: got %v, %v", d, ok)
+	}
+	if d, ok := ParseCaptureDate("2024-03-05T14:30:00-03:00"); !ok || d.Hour() != 14 {
+		t.Errorf("RFC 3339 format: got %v, %v", d, ok)
+	}
+	if _, ok := ParseCaptureDate("05/03/2024"); ok {
+		t.Error("expected other formats to be rejected")
+	}
+}
+
+func TestValidEvidenceEntityType(t *testing.T) {
+	for _, v := range []string{EvidenceEntityAudit, EvidenceEntityInspection, EvidenceEntityTask} {
+		if !ValidEvidenceEntityType(v) {
+			t.Errorf("%q should be valid", v)
+		}
+	}
+	if ValidEvidenceEntityType("contract") {
+		t.Error("contract should not be valid")
+	}
+}
@@ -0,0 +1,90 @@
+package entity
+
+import "testing"
+
+func TestFeatureFlagIsEnabledFor(t *testing.T) {
+	flag := FeatureFlag{
+		Key:            "gateway.mercadopago",
+		Enabled:        true,
+		RolloutPercent: 100,
+		UserIDs:        StringList{"beta-user"},
+		Roles:          StringList{"admin", "instructor"},
+		OrgIDs:         StringList{"contract-1"},
+	}
+
+	tests := []struct {
+		name string
+		fc   FlagContext
+		want bool
+	}{
+		{"matching role and org", FlagContext{UserID: "u1", Role: "instructor", OrgID: "contract-1"}, true},
+		{"role not targeted", FlagContext{UserID: "u1", Role: "student", OrgID: "contract-1"}, false},
+		{"org not targeted", FlagContext{UserID: "u1", Role: "instructor", OrgID: "contract-2"}, false},
+		{"listed user bypasses restrictions", FlagContext{UserID: "beta-user", Role: "student"}, true},
+	}
+	for _, tt := range tests {
+		if got := flag.IsEnabledFor(tt.fc); got != tt.want {
+			t.Errorf("%s: IsEnabledFor = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	flag.Enabled = false
+	if flag.IsEnabledFor(FlagContext{UserID: "beta-user"}) {
+		t.Error("disabled flag: expected off for listed users too")
+	}
+}
+
+func TestFeatureFlagRollout(t *testing.T) {
+	flag := FeatureFlag{Key: "new-checkout", Enabled: true, RolloutPercent: 30}
+
+	on := 0
+	for i := 0; i < 1000; i++ {
+		userID := "user-" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+		enabled := flag.IsEnabledFor(FlagContext{UserID: userID})
+		if enabled != (RolloutBucket(flag.Key, userID) < 30) {
+			t.Fatalf("user %s: evaluation does not follow its bucket", userID)
+		}
+		if enabled {
+			on++
+		}
+	}
+	if on < 200 || on > 400 {
+		t.Errorf("30%% rollout enabled %d of 1000 users", on)
+	}
+
+	if flag.IsEnabledFor(FlagContext{}) {
+		t.Error("partial rollout: anonymous callers have no bucket and should be off")
+	}
+	flag.RolloutPercent = 0
+	if flag.IsEnabledFor(FlagContext{UserID: "user-1"}) {
+		t.Error("0% rollout: expected off")
+	}
+}
+
+func TestStringListScanValue(t *testing.T) {
+	v, err := StringList(nil).Value()
+	if err != nil || v != "[]" {
+		t.Errorf("nil Value() = %v, %v; want []", v, err)
+	}
+
+	var l StringList
+	if err := l.Scan([]byte(`["a","b"]`)); err != nil || len(l) != 2 || !l.Contains("b") {
+		t.Errorf("Scan = %v, %v", l, err)
+	}
+	if err := l.Scan(42); err == nil {
+		t.Error("Scan(int): expected error")
+	}
+}
+
+func TestValidFeatureFlagKey(t *testing.T) {
+	for _, key := range []string{"gateway.mercadopago", "ai_streaming", "checkout-v2"} {
+		if !ValidFeatureFlagKey(key) {
+			t.Errorf("ValidFeatureFlagKey(%q) = false", key)
+		}
+	}
+	for _, key := range []string{"", "x", "Gateway", "with space", ".hidden"} {
+		if ValidFeatureFlagKey(key) {
+			t.Errorf("ValidFeatureFlagKey(%q) = true", key)
+		}
+	}
+}
@@ -1,39 +1,54 @@
 package entity
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
 
 // Payment represents a dedicated payment record in the payments table.
 // This is the new entity decoupled from enrollments.
 type Payment struct {
-	ID                string     `db:"id" json:"id"`
-	EnrollmentID      string     `db:"enrollment_id" json:"enrollment_id"`
-	PayerUserID       *string    `db:"payer_user_id" json:"payer_user_id,omitempty"`
-	PayerName         string     `db:"payer_name" json:"payer_name"`
-	PayerEmail        string     `db:"payer_email" json:"payer_email"`
-	PayerCPF          *string    `db:"payer_cpf" json:"payer_cpf,omitempty"`
-	GrossAmount       float64    `db:"gross_amount" json:"gross_amount"`
-	DiscountAmount    float64    `db:"discount_amount" json:"discount_amount"`
-	NetAmount         float64    `db:"net_amount" json:"net_amount"`
-	GatewayFee        float64    `db:"gateway_fee" json:"gateway_fee"`
-	RefundedAmount    float64    `db:"refunded_amount" json:"refunded_amount"`
-	PaymentMethod     string     `db:"payment_method" json:"payment_method"`
-	Gateway           string     `db:"gateway" json:"gateway"`
-	GatewayPaymentID  *string    `db:"gateway_payment_id" json:"gateway_payment_id,omitempty"`
-	GatewayCustomerID *string    `db:"gateway_customer_id" json:"gateway_customer_id,omitempty"`
-	GatewayInvoiceURL *string    `db:"gateway_invoice_url" json:"gateway_invoice_url,omitempty"`
-	GatewayMetadata   *string    `db:"gateway_metadata" json:"gateway_metadata,omitempty"`
-	InstallmentCount  int        `db:"installment_count" json:"installment_count"`
-	InstallmentOf     *string    `db:"installment_of" json:"installment_of,omitempty"`
-	InstallmentNumber *int       `db:"installment_number" json:"installment_number,omitempty"`
-	Status            string     `db:"status" json:"status"`
-	CouponID          *string    `db:"coupon_id" json:"coupon_id,omitempty"`
-	DueDate           *time.Time `db:"due_date" json:"due_date,omitempty"`
-	PaidAt            *time.Time `db:"paid_at" json:"paid_at,omitempty"`
-	RefundedAt        *time.Time `db:"refunded_at" json:"refunded_at,omitempty"`
-	CancelledAt       *time.Time `db:"cancelled_at" json:"cancelled_at,omitempty"`
-	ExpiresAt         *time.Time `db:"expires_at" json:"expires_at,omitempty"`
-	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt         *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+	ID                string      `db:"id" json:"id"`
+	EnrollmentID      string      `db:"enrollment_id" json:"enrollment_id"`
+	PayerUserID       *string     `db:"payer_user_id" json:"payer_user_id,omitempty"`
+	PayerName         string      `db:"payer_name" json:"payer_name"`
+	PayerEmail        string      `db:"payer_email" json:"payer_email"`
+	PayerCPF          *string     `db:"payer_cpf" json:"payer_cpf,omitempty"`
+	GrossAmount       money.Cents `db:"gross_amount" json:"gross_amount"`
+	DiscountAmount    money.Cents `db:"discount_amount" json:"discount_amount"`
+	NetAmount         money.Cents `db:"net_amount" json:"net_amount"`
+	GatewayFee        money.Cents `db:"gateway_fee" json:"gateway_fee"`
+	FeeSurcharge      money.Cents `db:"fee_surcharge" json:"fee_surcharge"`
+	RefundedAmount    money.Cents `db:"refunded_amount" json:"refunded_amount"`
+	PaymentMethod     string      `db:"payment_method" json:"payment_method"`
+	Gateway           string      `db:"gateway" json:"gateway"`
+	GatewayPaymentID  *string     `db:"gateway_payment_id" json:"gateway_payment_id,omitempty"`
+	GatewayCustomerID *string     `db:"gateway_customer_id" json:"gateway_customer_id,omitempty"`
+	GatewayInvoiceURL *string     `db:"gateway_invoice_url" json:"gateway_invoice_url,omitempty"`
+	GatewayMetadata   *string     `db:"gateway_metadata" json:"gateway_metadata,omitempty"`
+	InstallmentCount  int         `db:"installment_count" json:"installment_count"`
+	InstallmentOf     *string     `db:"installment_of" json:"installment_of,omitempty"`
+	InstallmentNumber *int        `db:"installment_number" json:"installment_number,omitempty"`
+	Status            string      `db:"status" json:"status"`
+	CouponID          *string     `db:"coupon_id" json:"coupon_id,omitempty"`
+	DueDate           *time.Time  `db:"due_date" json:"due_date,omitempty"`
+	PaidAt            *time.Time  `db:"paid_at" json:"paid_at,omitempty"`
+	RefundedAt        *time.Time  `db:"refunded_at" json:"refunded_at,omitempty"`
+	CancelledAt       *time.Time  `db:"cancelled_at" json:"cancelled_at,omitempty"`
+	ExpiresAt         *time.Time  `db:"expires_at" json:"expires_at,omitempty"`
+	CreatedAt         time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt         *time.Time  `db:"updated_at" json:"updated_at,omitempty"`
+
+	// LineItems is the breakdown of what the buyer was charged, set by checkout
+	LineItems PaymentLineItems `db:"line_items" json:"line_items,omitempty"`
+
+	// GatewayInstallmentID groups the installments of a card charge the gateway settles
+	// one by one (Asaas installment ID)
+	GatewayInstallmentID *string `db:"gateway_installment_id" json:"gateway_installment_id,omitempty"`
 }
 
 // Payment status constants matching the ENUM in 006_financial_module.sql
@@ -67,4 +82,74 @@ const (
 	GatewayMercadoPago = "mercadopago"
 	GatewayManual     = "manual"
 	GatewayFree       = "free"
+	GatewayMock       = "mock"
 )
+
+// Payment line item kinds
+const (
+	LineItemPrice    = "price"
+	LineItemDiscount = "discount"
+	LineItemFee      = "fee"
+)
+
+// PaymentLineItem is one line of what the buyer was charged: the price, a discount (negative)
+// or the gateway fee passed through to the buyer
+type PaymentLineItem struct {
+	Kind        string      `json:"kind"`
+	Description string      `json:"description"`
+	Amount      money.Cents `json:"amount"`
+}
+
+// PaymentLineItems is the breakdown of a payment, stored as a JSON array column
+type PaymentLineItems []PaymentLineItem
+
+// Value implements driver.Valuer
+func (l PaymentLineItems) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal([]PaymentLineItem(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner
+func (l *PaymentLineItems) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, (*[]PaymentLineItem)(l))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]PaymentLineItem)(l))
+	}
+	return errors.New("unsupported type for PaymentLineItems")
+}
+
+// PixExpired reports whether an unpaid PIX payment can no longer be paid with its QR code:
+// the gateway flagged it overdue, or its expiration or due date has passed
+func (p *Payment) PixExpired(now time.Time) bool {
+	if p.PaymentMethod != MethodPIX {
+		return false
+	}
+	switch p.Status {
+	case FinPaymentStatusOverdue:
+		return true
+	case FinPaymentStatusPending, FinPaymentStatusAwaitingPayment:
+	default:
+		return false
+	}
+	if p.ExpiresAt != nil && !now.Before(*p.ExpiresAt) {
+		return true
+	}
+	// A charge stays payable until the end of its due date
+	return p.DueDate != nil && now.Format("2006-01-02") > p.DueDate.Format("2006-01-02")
+}
+
+// IsPaid reports whether the payment was settled by the gateway
+func (p *Payment) IsPaid() bool {
+	return p.Status == FinPaymentStatusConfirmed || p.Status == FinPaymentStatusReceived
+}
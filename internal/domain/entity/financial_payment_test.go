@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPayment_PixExpired(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	yesterday := now.AddDate(0, 0, -1)
+	today := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		p    Payment
+		want bool
+	}{
+		{"overdue", Payment{PaymentMethod: MethodPIX, Status: FinPaymentStatusOverdue}, true},
+		{"due yesterday", Payment{PaymentMethod: MethodPIX, Status: FinPaymentStatusPending, DueDate: &yesterday}, true},
+		{"due today", Payment{PaymentMethod: MethodPIX, Status: FinPaymentStatusPending, DueDate: &today}, false},
+		{"qr code expired", Payment{PaymentMethod: MethodPIX, Status: FinPaymentStatusPending, ExpiresAt: &past}, true},
+		{"no dates", Payment{PaymentMethod: MethodPIX, Status: FinPaymentStatusPending}, false},
+		{"paid", Payment{PaymentMethod: MethodPIX, Status: FinPaymentStatusConfirmed, DueDate: &yesterday}, false},
+		{"boleto", Payment{PaymentMethod: MethodBoleto, Status: FinPaymentStatusOverdue}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.p.PixExpired(now); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
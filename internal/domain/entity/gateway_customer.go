@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// GatewayCustomer maps a payer document (CPF or CNPJ, digits only) to the customer created
+// for it on a gateway, so repeat purchases reuse the gateway customer
+type GatewayCustomer struct {
+	ID                string    `db:"id" json:"id"`
+	Gateway           string    `db:"gateway" json:"gateway"`
+	Document          string    `db:"document" json:"document"`
+	UserID            *string   `db:"user_id" json:"user_id,omitempty"`
+	GatewayCustomerID string    `db:"gateway_customer_id" json:"gateway_customer_id"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
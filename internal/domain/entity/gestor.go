@@ -2,9 +2,11 @@ package entity
 
 import "time"
 
-// Gestor represents a manager/administrator entity
+// Gestor represents a manager/administrator entity. UserID links it to the user account
+// that logs in as this gestor; ownership of gestor records and contracts goes through it.
 type Gestor struct {
 	ID        string     `db:"id" json:"id"`
+	UserID    *string    `db:"user_id" json:"user_id,omitempty"`
 	Nome      string     `db:"nome" json:"nome"`
 	Email     string     `db:"email" json:"email"`
 	Telefone  *string    `db:"telefone" json:"telefone,omitempty"`
@@ -12,6 +14,7 @@ type Gestor struct {
 	Ativo     bool       `db:"ativo" json:"ativo"`
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 }
 
 // GestorWithContracts represents a gestor with their contracts count
@@ -19,3 +22,57 @@ type GestorWithContracts struct {
 	Gestor
 	TotalContratos int `db:"total_contratos" json:"total_contratos"`
 }
+
+// GestorReassignment is the summary of a reassignment: the contracts, open tasks and
+// scheduled inspections moved from one gestor to another
+type GestorReassignment struct {
+	FromGestorID  string   `json:"from_gestor_id"`
+	ToGestorID    string   `json:"to_gestor_id"`
+	Contracts     int      `json:"contracts"`
+	Tasks         int      `json:"tasks"`
+	Inspections   int      `json:"inspections"`
+	ContractIDs   []string `json:"contract_ids"`
+	TaskIDs       []string `json:"task_ids"`
+	InspectionIDs []string `json:"inspection_ids"`
+}
+
+// GestorContractMetrics holds the raw audit, task and inspection counts of one contract
+// managed by a gestor within a date range
+type GestorContractMetrics struct {
+	ContratoID           string  `db:"contrato_id" json:"contrato_id"`
+	ContratoNome         string  `db:"contrato_nome" json:"contrato_nome"`
+	Ativo                bool    `db:"ativo" json:"ativo"`
+	MetaScore            float64 `db:"meta_score" json:"meta_score"`
+	AuditCount           int     `db:"audit_count" json:"audit_count"`
+	AuditScoreSum        float64 `db:"audit_score_sum" json:"-"`
+	AuditsOnTarget       int     `db:"audits_on_target" json:"audits_on_target"`
+	TasksCompletedOnTime int     `db:"tasks_completed_on_time" json:"tasks_completed_on_time"`
+	TasksCompletedLate   int     `db:"tasks_completed_late" json:"tasks_completed_late"`
+	TasksOverdue         int     `db:"tasks_overdue" json:"tasks_overdue"`
+	InspectionsDue       int     `db:"inspections_due" json:"inspections_due"`
+	InspectionsCompleted int     `db:"inspections_completed" json:"inspections_completed"`
+
+	AverageAuditScore        *float64 `db:"-" json:"average_audit_score,omitempty"`
+	TaskOnTimeRate           *float64 `db:"-" json:"task_on_time_rate,omitempty"`          // percentage of measured tasks
+	InspectionComplianceRate *float64 `db:"-" json:"inspection_compliance_rate,omitempty"` // percentage of due inspections
+}
+
+// GestorMetrics is the performance summary of a gestor across their contracts
+type GestorMetrics struct {
+	GestorID                 string                  `json:"gestor_id"`
+	GestorNome               string                  `json:"gestor_nome"`
+	From                     string                  `json:"from"`
+	To                       string                  `json:"to"`
+	ManagedContracts         int                     `json:"managed_contracts"`
+	ActiveContracts          int                     `json:"active_contracts"`
+	AuditCount               int                     `json:"audit_count"`
+	AuditsOnTarget           int                     `json:"audits_on_target"`
+	AverageAuditScore        *float64                `json:"average_audit_score,omitempty"`
+	TasksMeasured            int                     `json:"tasks_measured"` // completed plus overdue
+	TasksCompletedOnTime     int                     `json:"tasks_completed_on_time"`
+	TaskOnTimeRate           *float64                `json:"task_on_time_rate,omitempty"`
+	InspectionsDue           int                     `json:"inspections_due"`
+	InspectionsCompleted     int                     `json:"inspections_completed"`
+	InspectionComplianceRate *float64                `json:"inspection_compliance_rate,omitempty"`
+	Contracts                []GestorContractMetrics `json:"contracts"`
+}
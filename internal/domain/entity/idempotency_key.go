@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// IdempotencyKey is the stored outcome of a request sent with an Idempotency-Key header.
+// ID identifies the key within a route and user; a record without a status is a request
+// still in progress.
+type IdempotencyKey struct {
+	ID           string     `db:"id" json:"id"`
+	Key          string     `db:"idempotency_key" json:"key"`
+	UserID       *string    `db:"user_id" json:"user_id,omitempty"`
+	Route        string     `db:"route" json:"route"`
+	RequestHash  string     `db:"request_hash" json:"request_hash"`
+	StatusCode   *int       `db:"status_code" json:"status_code,omitempty"`
+	ContentType  *string    `db:"content_type" json:"content_type,omitempty"`
+	ResponseBody *string    `db:"response_body" json:"-"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	CompletedAt  *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+	ExpiresAt    time.Time  `db:"expires_at" json:"expires_at"`
+}
+
+// Completed reports whether the response of the request was stored
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != nil
+}
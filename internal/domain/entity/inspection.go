@@ -3,6 +3,8 @@ package entity
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/condotrack/api/internal/domain/listquery"
 )
 
 // InspectionType constants
@@ -75,6 +77,7 @@ type InspectionFilter struct {
 	Status      string
 	StartDate   *time.Time
 	EndDate     *time.Time
+	Query       listquery.Query // filter[...] and sort parameters
 }
 
 // ValidInspectionTypes returns all valid inspection types
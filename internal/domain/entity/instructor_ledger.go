@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// Instructor ledger entry type constants. Credits are positive, every other type is
+// recorded with the sign of its effect on the balance owed to the instructor.
+const (
+	LedgerEntryCredit     = "credit"     // instructor share of a revenue split
+	LedgerEntryRefund     = "refund"     // share given back on a refund or cancellation
+	LedgerEntryChargeback = "chargeback" // share lost to a chargeback
+	LedgerEntryPayout     = "payout"     // amount paid out (payout batch or gateway transfer)
+	LedgerEntryAdjustment = "adjustment" // correction, e.g. a course transfer re-pricing the split
+)
+
+// InstructorLedgerEntry is one movement of the balance the platform owes an instructor.
+// The entry key identifies the event it records, so recording an event twice is a no-op.
+type InstructorLedgerEntry struct {
+	ID             string    `db:"id" json:"id"`
+	InstructorID   string    `db:"instructor_id" json:"instructor_id"`
+	EntryKey       string    `db:"entry_key" json:"entry_key"`
+	EntryType      string    `db:"entry_type" json:"entry_type"`
+	Amount         float64   `db:"amount" json:"amount"`
+	RevenueSplitID *string   `db:"revenue_split_id" json:"revenue_split_id,omitempty"`
+	PayoutBatchID  *string   `db:"payout_batch_id" json:"payout_batch_id,omitempty"`
+	EnrollmentID   *string   `db:"enrollment_id" json:"enrollment_id,omitempty"`
+	Description    string    `db:"description" json:"description"`
+	OccurredAt     time.Time `db:"occurred_at" json:"occurred_at"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// InstructorLedgerTotals sums ledger entries per type
+type InstructorLedgerTotals struct {
+	Credits     float64 `json:"credits"`
+	Refunds     float64 `json:"refunds"`
+	Chargebacks float64 `json:"chargebacks"`
+	Adjustments float64 `json:"adjustments"`
+	Payouts     float64 `json:"payouts"`
+}
+
+// Earned is what the instructor is entitled to: credits net of refunds, chargebacks and adjustments
+func (t InstructorLedgerTotals) Earned() float64 {
+	return roundShare(t.Credits + t.Refunds + t.Chargebacks + t.Adjustments)
+}
+
+// InstructorStatement is the ledger of an instructor over a period, with the balance carried
+// from before the period
+type InstructorStatement struct {
+	InstructorID   string                  `json:"instructor_id"`
+	InstructorName string                  `json:"instructor_name,omitempty"`
+	PeriodStart    *time.Time              `json:"period_start,omitempty"`
+	PeriodEnd      *time.Time              `json:"period_end,omitempty"`
+	OpeningBalance float64                 `json:"opening_balance"`
+	Totals         InstructorLedgerTotals  `json:"totals"`
+	ClosingBalance float64                 `json:"closing_balance"`
+	Entries        []InstructorLedgerEntry `json:"entries"`
+}
+
+// SummarizeLedger adds up entries per type and returns the balance after them
+func SummarizeLedger(opening float64, entries []InstructorLedgerEntry) (InstructorLedgerTotals, float64) {
+	var totals InstructorLedgerTotals
+	balance := opening
+	for _, e := range entries {
+		switch e.EntryType {
+		case LedgerEntryCredit:
+			totals.Credits += e.Amount
+		case LedgerEntryRefund:
+			totals.Refunds += e.Amount
+		case LedgerEntryChargeback:
+			totals.Chargebacks += e.Amount
+		case LedgerEntryAdjustment:
+			totals.Adjustments += e.Amount
+		case LedgerEntryPayout:
+			totals.Payouts += e.Amount
+		}
+		balance += e.Amount
+	}
+	totals.Credits = roundShare(totals.Credits)
+	totals.Refunds = roundShare(totals.Refunds)
+	totals.Chargebacks = roundShare(totals.Chargebacks)
+	totals.Adjustments = roundShare(totals.Adjustments)
+	totals.Payouts = roundShare(totals.Payouts)
+	return totals, roundShare(balance)
+}
+
+// NewSplitLedgerEntry records a movement of the instructor share of a split. It returns nil
+// when the split has no instructor or the amount is zero. The key is prefixed with the entry
+// type, followed by the ID of the event being recorded.
+func NewSplitLedgerEntry(split *RevenueSplit, entryType, eventID string, amount money.Cents, description string, occurredAt time.Time) *InstructorLedgerEntry {
+	if split.InstructorID == nil || *split.InstructorID == "" || amount == 0 {
+		return nil
+	}
+	return &InstructorLedgerEntry{
+		InstructorID:   *split.InstructorID,
+		EntryKey:       fmt.Sprintf("%s:%s", entryType, eventID),
+		EntryType:      entryType,
+		Amount:         amount.Float(),
+		RevenueSplitID: &split.ID,
+		EnrollmentID:   &split.EnrollmentID,
+		Description:    description,
+		OccurredAt:     occurredAt,
+	}
+}
+
+// NewSplitCreditEntry records the instructor share of a new split
+func NewSplitCreditEntry(split *RevenueSplit, occurredAt time.Time) *InstructorLedgerEntry {
+	return NewSplitLedgerEntry(split, LedgerEntryCredit, split.ID, split.InstructorAmount,
+		"Receita - matrícula "+split.EnrollmentID, occurredAt)
+}
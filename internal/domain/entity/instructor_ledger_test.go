@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSplitLedgerEntry(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	split := &RevenueSplit{ID: "split-1", EnrollmentID: "enr-1", InstructorID: strPtr("inst-1"), InstructorAmount: 7000}
+
+	credit := NewSplitCreditEntry(split, now)
+	if credit == nil || credit.EntryKey != "credit:split-1" || credit.Amount != 70 || credit.InstructorID != "inst-1" {
+		t.Fatalf("credit entry = %+v", credit)
+	}
+
+	refund := NewSplitLedgerEntry(split, LedgerEntryRefund, "canc-1", -3500, "Estorno", now)
+	if refund == nil || refund.EntryKey != "refund:canc-1" || refund.Amount != -35 {
+		t.Errorf("refund entry = %+v", refund)
+	}
+
+	if NewSplitLedgerEntry(split, LedgerEntryRefund, "canc-2", 0, "Estorno", now) != nil {
+		t.Error("zero amount: expected no entry")
+	}
+	if NewSplitCreditEntry(&RevenueSplit{ID: "split-2", InstructorAmount: 7000}, now) != nil {
+		t.Error("split without instructor: expected no entry")
+	}
+}
+
+func TestSummarizeLedger(t *testing.T) {
+	entries := []InstructorLedgerEntry{
+		{EntryType: LedgerEntryCredit, Amount: 70},
+		{EntryType: LedgerEntryCredit, Amount: 35.1},
+		{EntryType: LedgerEntryRefund, Amount: -17.55},
+		{EntryType: LedgerEntryChargeback, Amount: -70},
+		{EntryType: LedgerEntryAdjustment, Amount: 5},
+		{EntryType: LedgerEntryPayout, Amount: -20},
+	}
+
+	totals, balance := SummarizeLedger(10, entries)
+	if totals.Credits != 105.1 || totals.Refunds != -17.55 || totals.Chargebacks != -70 ||
+		totals.Adjustments != 5 || totals.Payouts != -20 {
+		t.Errorf("totals = %+v", totals)
+	}
+	if totals.Earned() != 22.55 {
+		t.Errorf("earned = %v, want 22.55", totals.Earned())
+	}
+	if balance != 12.55 {
+		t.Errorf("balance = %v, want 12.55", balance)
+	}
+}
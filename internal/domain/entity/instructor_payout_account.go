@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// Payout account method constants
+const (
+	PayoutMethodWallet      = "wallet"       // Asaas account (wallet ID)
+	PayoutMethodPix         = "pix"          // PIX key
+	PayoutMethodBankAccount = "bank_account" // TED to a bank account
+)
+
+// Payout account verification status constants
+const (
+	PayoutAccountUnverified = "unverified" // verification not started or could not be started
+	PayoutAccountPending    = "pending"    // micro-deposit sent, waiting for the instructor to confirm it
+	PayoutAccountVerified   = "verified"
+	PayoutAccountFailed     = "failed"
+)
+
+// Payout account verification method constants
+const (
+	PayoutVerificationGateway      = "gateway"       // checked by the gateway without moving money
+	PayoutVerificationMicroDeposit = "micro_deposit" // a small amount sent and confirmed by the instructor
+)
+
+// InstructorPayoutAccount is where automatic transfers of an instructor's revenue are sent.
+// Payouts only go to verified accounts.
+type InstructorPayoutAccount struct {
+	InstructorID     string     `db:"instructor_id" json:"instructor_id"`
+	Method           string     `db:"method" json:"method"`
+	WalletID         *string    `db:"wallet_id" json:"wallet_id,omitempty"`
+	PixKey           *string    `db:"pix_key" json:"pix_key,omitempty"`
+	PixKeyType       *string    `db:"pix_key_type" json:"pix_key_type,omitempty"`
+	BankCode         *string    `db:"bank_code" json:"bank_code,omitempty"`
+	BankAgency       *string    `db:"bank_agency" json:"bank_agency,omitempty"`
+	BankAccount      *string    `db:"bank_account" json:"bank_account,omitempty"`
+	BankAccountDigit *string    `db:"bank_account_digit" json:"bank_account_digit,omitempty"`
+	BankAccountType  *string    `db:"bank_account_type" json:"bank_account_type,omitempty"`
+	OwnerName        *string    `db:"owner_name" json:"owner_name,omitempty"`
+	OwnerDocument    *string    `db:"owner_document" json:"owner_document,omitempty"`
+	AutoTransfer     bool       `db:"auto_transfer" json:"auto_transfer"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	// Verification; the micro-deposit amount is what the instructor proves they received
+	VerificationStatus     string       `db:"verification_status" json:"verification_status"`
+	VerificationMethod     *string      `db:"verification_method" json:"verification_method,omitempty"`
+	MicroDepositAmount     *money.Cents `db:"micro_deposit_amount" json:"-"`
+	MicroDepositTransferID *string      `db:"micro_deposit_transfer_id" json:"-"`
+	VerificationAttempts   int          `db:"verification_attempts" json:"verification_attempts"`
+	VerificationError      *string      `db:"verification_error" json:"verification_error,omitempty"`
+	VerifiedAt             *time.Time   `db:"verified_at" json:"verified_at,omitempty"`
+}
+
+// IsVerified reports whether payouts can be sent to the account
+func (a *InstructorPayoutAccount) IsVerified() bool {
+	return a.VerificationStatus == PayoutAccountVerified
+}
+
+// SameDestination reports whether both accounts send payouts to the same place, so a
+// verification of one holds for the other
+func (a *InstructorPayoutAccount) SameDestination(b *InstructorPayoutAccount) bool {
+	if a.Method != b.Method {
+		return false
+	}
+	switch a.Method {
+	case PayoutMethodWallet:
+		return sameValue(a.WalletID, b.WalletID)
+	case PayoutMethodPix:
+		return sameValue(a.PixKey, b.PixKey) && sameValue(a.PixKeyType, b.PixKeyType)
+	case PayoutMethodBankAccount:
+		return sameValue(a.BankCode, b.BankCode) && sameValue(a.BankAgency, b.BankAgency) && sameValue(a.BankAccount, b.BankAccount) &&
+			sameValue(a.BankAccountDigit, b.BankAccountDigit) && sameValue(a.BankAccountType, b.BankAccountType) &&
+			sameValue(a.OwnerName, b.OwnerName) && sameValue(a.OwnerDocument, b.OwnerDocument)
+	}
+	return false
+}
+
+// ConfirmMicroDepositRequest represents the request to confirm the micro-deposit received on a
+// payout account
+type ConfirmMicroDepositRequest struct {
+	Amount money.Cents `json:"amount" binding:"required,gt=0"`
+}
+
+// SavePayoutAccountRequest represents the request to register or replace an instructor payout account
+type SavePayoutAccountRequest struct {
+	Method           string  `json:"method" binding:"required,oneof=wallet pix bank_account"`
+	WalletID         *string `json:"wallet_id"`
+	PixKey           *string `json:"pix_key"`
+	PixKeyType       *string `json:"pix_key_type" binding:"omitempty,oneof=CPF CNPJ EMAIL PHONE EVP"`
+	BankCode         *string `json:"bank_code"`
+	BankAgency       *string `json:"bank_agency"`
+	BankAccount      *string `json:"bank_account"`
+	BankAccountDigit *string `json:"bank_account_digit"`
+	BankAccountType  *string `json:"bank_account_type" binding:"omitempty,oneof=CONTA_CORRENTE CONTA_POUPANCA"`
+	OwnerName        *string `json:"owner_name"`
+	OwnerDocument    *string `json:"owner_document"`
+	AutoTransfer     *bool   `json:"auto_transfer"`
+}
+
+// Validate checks that the fields required by the chosen method are present
+func (r *SavePayoutAccountRequest) Validate() error {
+	switch r.Method {
+	case PayoutMethodWallet:
+		if isBlank(r.WalletID) {
+			return errors.New("invalid payout account: wallet_id is required")
+		}
+	case PayoutMethodPix:
+		if isBlank(r.PixKey) || isBlank(r.PixKeyType) {
+			return errors.New("invalid payout account: pix_key and pix_key_type are required")
+		}
+	case PayoutMethodBankAccount:
+		if isBlank(r.BankCode) || isBlank(r.BankAgency) || isBlank(r.BankAccount) || isBlank(r.BankAccountDigit) ||
+			isBlank(r.OwnerName) || isBlank(r.OwnerDocument) {
+			return errors.New("invalid payout account: bank_code, bank_agency, bank_account, bank_account_digit, owner_name and owner_document are required")
+		}
+	default:
+		return errors.New("invalid payout account: method must be wallet, pix or bank_account")
+	}
+	return nil
+}
+
+func isBlank(s *string) bool {
+	return s == nil || *s == ""
+}
+
+// sameValue treats nil and empty as the same value
+func sameValue(x, y *string) bool {
+	if isBlank(x) || isBlank(y) {
+		return isBlank(x) && isBlank(y)
+	}
+	return *x == *y
+}
@@ -0,0 +1,60 @@
+package entity
+
+import "testing"
+
+func TestSavePayoutAccountRequestValidate(t *testing.T) {
+	s := func(v string) *string { return &v }
+
+	tests := []struct {
+		name    string
+		req     SavePayoutAccountRequest
+		wantErr bool
+	}{
+		{"wallet", SavePayoutAccountRequest{Method: PayoutMethodWallet, WalletID: s("wal-1")}, false},
+		{"wallet without id", SavePayoutAccountRequest{Method: PayoutMethodWallet}, true},
+		{"pix", SavePayoutAccountRequest{Method: PayoutMethodPix, PixKey: s("a@b.com"), PixKeyType: s("EMAIL")}, false},
+		{"pix without type", SavePayoutAccountRequest{Method: PayoutMethodPix, PixKey: s("a@b.com")}, true},
+		{"bank account", SavePayoutAccountRequest{
+			Method: PayoutMethodBankAccount, BankCode: s("341"), BankAgency: s("0001"), BankAccount: s("123"),
+			BankAccountDigit: s("4"), OwnerName: s("Maria"), OwnerDocument: s("12345678900"),
+		}, false},
+		{"bank account without owner", SavePayoutAccountRequest{
+			Method: PayoutMethodBankAccount, BankCode: s("341"), BankAgency: s("0001"), BankAccount: s("123"),
+			BankAccountDigit: s("4"),
+		}, true},
+		{"unknown method", SavePayoutAccountRequest{Method: "cash"}, true},
+	}
+
+	for _, tt := range tests {
+		err := tt.req.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestInstructorPayoutAccountSameDestination(t *testing.T) {
+	s := func(v string) *string { return &v }
+	pix := &InstructorPayoutAccount{Method: PayoutMethodPix, PixKey: s("a@b.com"), PixKeyType: s("EMAIL")}
+
+	tests := []struct {
+		name  string
+		other *InstructorPayoutAccount
+		want  bool
+	}{
+		{"same key", &InstructorPayoutAccount{Method: PayoutMethodPix, PixKey: s("a@b.com"), PixKeyType: s("EMAIL"), AutoTransfer: true}, true},
+		{"other key", &InstructorPayoutAccount{Method: PayoutMethodPix, PixKey: s("c@d.com"), PixKeyType: s("EMAIL")}, false},
+		{"other method", &InstructorPayoutAccount{Method: PayoutMethodWallet, WalletID: s("wal-1")}, false},
+		{"fields of another method", &InstructorPayoutAccount{Method: PayoutMethodPix, PixKey: s("a@b.com"), PixKeyType: s("EMAIL"), WalletID: s("wal-1")}, true},
+	}
+	for _, tt := range tests {
+		if got := pix.SameDestination(tt.other); got != tt.want {
+			t.Errorf("%s: SameDestination() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	bank := &InstructorPayoutAccount{Method: PayoutMethodBankAccount, BankCode: s("341"), BankAccount: s("123"), BankAccountType: s("")}
+	if !bank.SameDestination(&InstructorPayoutAccount{Method: PayoutMethodBankAccount, BankCode: s("341"), BankAccount: s("123")}) {
+		t.Error("expected an empty field to match a missing one")
+	}
+}
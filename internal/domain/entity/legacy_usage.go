@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// LegacyUsage counts the requests to one legacy router endpoint and method on a day
+type LegacyUsage struct {
+	Day        time.Time `db:"day" json:"day"`
+	Endpoint   string    `db:"endpoint" json:"endpoint"`
+	Method     string    `db:"method" json:"method"`
+	Requests   int64     `db:"requests" json:"requests"`
+	Errors     int64     `db:"errors" json:"errors"`
+	LastUserID *string   `db:"last_user_id" json:"last_user_id,omitempty"`
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+}
+
+// LegacyUsageSummary totals the usage of a legacy endpoint over a period
+type LegacyUsageSummary struct {
+	Endpoint    string    `db:"endpoint" json:"endpoint"`
+	Method      string    `db:"method" json:"method"`
+	Requests    int64     `db:"requests" json:"requests"`
+	Errors      int64     `db:"errors" json:"errors"`
+	ActiveDays  int       `db:"active_days" json:"active_days"`
+	FirstSeenAt time.Time `db:"first_seen_at" json:"first_seen_at"`
+	LastSeenAt  time.Time `db:"last_seen_at" json:"last_seen_at"`
+	Successor   string    `db:"-" json:"successor,omitempty"`
+}
@@ -34,11 +34,13 @@ type Matricula struct {
 
 // Enrollment status constants
 const (
-	EnrollmentStatusPending   = "pending"
-	EnrollmentStatusActive    = "active"
-	EnrollmentStatusCompleted = "completed"
-	EnrollmentStatusCancelled = "cancelled"
-	EnrollmentStatusExpired   = "expired"
+	EnrollmentStatusPending    = "pending"
+	EnrollmentStatusActive     = "active"
+	EnrollmentStatusCompleted  = "completed"
+	EnrollmentStatusCancelled  = "cancelled"
+	EnrollmentStatusCancelling = "cancelling" // claimed by a cancellation while its refund is issued
+	EnrollmentStatusExpired    = "expired"
+	EnrollmentStatusRetrying   = "retrying" // claimed by a checkout retry while its new charge is created
 )
 
 // Payment status constants
@@ -49,6 +51,8 @@ const (
 	PaymentStatusRefunded   = "refunded"
 	PaymentStatusOverdue    = "overdue"
 	PaymentStatusChargeback = "chargeback"
+	// PaymentStatusComped marks seats granted without charge (corporate/B2B bulk enrollments)
+	PaymentStatusComped = "comped"
 )
 
 // CreateMatriculaRequest represents the request to create an enrollment
@@ -84,3 +88,53 @@ type MatriculaListResponse struct {
 	Page        int         `json:"page"`
 	PerPage     int         `json:"per_page"`
 }
+
+// EnrollmentSearchFilter holds the parameters of an enrollment search
+type EnrollmentSearchFilter struct {
+	// Terms are matched against the student name, email and CPF and the course name; every term
+	// must match one of them
+	Terms           string
+	Statuses        []string // any of the enrollment statuses
+	PaymentStatuses []string // any of the payment statuses
+	CourseID        *string
+	Page            int
+	PerPage         int
+}
+
+// MinEnrollmentSearchLength is the shortest search accepted, so a single letter cannot page
+// through every enrollment
+const MinEnrollmentSearchLength = 2
+
+// MaxBulkEnrollmentStudents caps how many students a single bulk request may enroll
+const MaxBulkEnrollmentStudents = 500
+
+// BulkEnrollmentStudent represents one student entry in a bulk enrollment request
+type BulkEnrollmentStudent struct {
+	StudentID    string  `json:"student_id" binding:"required"`
+	StudentName  string  `json:"student_name" binding:"required"`
+	StudentEmail string  `json:"student_email" binding:"required,email"`
+	StudentCPF   *string `json:"student_cpf,omitempty"`
+	StudentPhone *string `json:"student_phone,omitempty"`
+}
+
+// BulkEnrollmentRequest represents the request to enroll many students in a course without payment
+type BulkEnrollmentRequest struct {
+	CourseID string                  `json:"course_id" binding:"required"`
+	Students []BulkEnrollmentStudent `json:"students" binding:"required,min=1,dive"`
+}
+
+// BulkEnrollmentSkipped describes a student that was not enrolled by a bulk request
+type BulkEnrollmentSkipped struct {
+	StudentID    string `json:"student_id"`
+	StudentEmail string `json:"student_email"`
+	Reason       string `json:"reason"`
+}
+
+// BulkEnrollmentResponse represents the result of a bulk enrollment
+type BulkEnrollmentResponse struct {
+	CourseID     string                  `json:"course_id"`
+	Enrollments  []Matricula             `json:"enrollments"`
+	Skipped      []BulkEnrollmentSkipped `json:"skipped"`
+	TotalCreated int                     `json:"total_created"`
+	TotalSkipped int                     `json:"total_skipped"`
+}
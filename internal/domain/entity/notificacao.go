@@ -10,6 +10,7 @@ type Notificacao struct {
 	Title       string     `db:"title" json:"title"`
 	Message     string     `db:"message" json:"message"`
 	Data        *string    `db:"data" json:"data,omitempty"`
+	BroadcastID *string    `db:"broadcast_id" json:"broadcast_id,omitempty"`
 	Read        bool       `db:"is_read" json:"is_read"`
 	ReadAt      *time.Time `db:"read_at" json:"read_at,omitempty"`
 	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
@@ -22,6 +23,8 @@ const (
 	NotificationTypeAudit      = "audit"
 	NotificationTypeCertificate = "certificate"
 	NotificationTypeSystem     = "system"
+	NotificationTypeContract   = "contract"
+	NotificationTypeTask       = "task"
 )
 
 // CreateNotificationRequest represents the request to create a notification
@@ -32,3 +35,38 @@ type CreateNotificationRequest struct {
 	Message string  `json:"message" binding:"required"`
 	Data    *string `json:"data,omitempty"`
 }
+
+// OptionalNotificationTypes are the notification types a user can turn off; system notices
+// are always delivered
+var OptionalNotificationTypes = []string{
+	NotificationTypePayment,
+	NotificationTypeEnrollment,
+	NotificationTypeCertificate,
+	NotificationTypeAudit,
+	NotificationTypeContract,
+	NotificationTypeTask,
+}
+
+// IsOptionalNotificationType reports whether users can turn off notifications of a type
+func IsOptionalNotificationType(t string) bool {
+	for _, optional := range OptionalNotificationTypes {
+		if t == optional {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationPreference is whether a user receives the notifications of a type. Types
+// without a stored preference are delivered.
+type NotificationPreference struct {
+	UserID    string     `db:"user_id" json:"-"`
+	Type      string     `db:"type" json:"type"`
+	Enabled   bool       `db:"enabled" json:"enabled"`
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// UpdateNotificationPreferencesRequest turns notification types on or off, keyed by type
+type UpdateNotificationPreferencesRequest struct {
+	Preferences map[string]bool `json:"preferences" binding:"required"`
+}
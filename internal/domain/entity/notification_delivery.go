@@ -0,0 +1,88 @@
+package entity
+
+import "time"
+
+// Notification channel constants: where a notification is delivered besides the app
+const (
+	NotificationChannelEmail    = "email"
+	NotificationChannelWhatsApp = "whatsapp"
+	NotificationChannelPush     = "push"
+)
+
+// NotificationChannels lists the channels in order, with whether each is on for users who
+// have not chosen. WhatsApp messages need the user to opt in.
+var NotificationChannels = []NotificationChannelPreference{
+	{Channel: NotificationChannelEmail, Enabled: true},
+	{Channel: NotificationChannelWhatsApp, Enabled: false},
+	{Channel: NotificationChannelPush, Enabled: true},
+}
+
+// IsNotificationChannel reports whether c is a known channel
+func IsNotificationChannel(c string) bool {
+	for _, known := range NotificationChannels {
+		if known.Channel == c {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationChannelPreference is whether a user receives notifications on a channel
+type NotificationChannelPreference struct {
+	UserID    string     `db:"user_id" json:"-"`
+	Channel   string     `db:"channel" json:"channel"`
+	Enabled   bool       `db:"enabled" json:"enabled"`
+	Available bool       `db:"-" json:"available"` // the channel is configured on this server
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// UpdateNotificationChannelsRequest turns channels on or off, keyed by channel
+type UpdateNotificationChannelsRequest struct {
+	Channels map[string]bool `json:"channels" binding:"required"`
+}
+
+// Notification delivery status constants
+const (
+	NotificationDeliveryPending = "pending" // waiting for its next attempt
+	NotificationDeliverySending = "sending" // claimed by a dispatcher
+	NotificationDeliverySent    = "sent"
+	NotificationDeliveryFailed  = "failed"  // gave up after the last attempt
+	NotificationDeliverySkipped = "skipped" // the user has no address on the channel
+)
+
+// NotificationDelivery is the delivery of one notification on one channel
+type NotificationDelivery struct {
+	ID             string     `db:"id" json:"id"`
+	NotificationID string     `db:"notification_id" json:"notification_id"`
+	UserID         string     `db:"user_id" json:"user_id"`
+	Channel        string     `db:"channel" json:"channel"`
+	Status         string     `db:"status" json:"status"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	LastError      *string    `db:"last_error" json:"last_error,omitempty"`
+	SentAt         *time.Time `db:"sent_at" json:"sent_at,omitempty"`
+	DeliveredAt    *time.Time `db:"delivered_at" json:"delivered_at,omitempty"` // confirmed by the recipient
+	OpenedAt       *time.Time `db:"opened_at" json:"opened_at,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// PushSubscription is a browser registered for web push notifications of a user
+type PushSubscription struct {
+	ID        string    `db:"id" json:"id"`
+	UserID    string    `db:"user_id" json:"-"`
+	Endpoint  string    `db:"endpoint" json:"endpoint"`
+	P256dh    string    `db:"p256dh" json:"-"`
+	Auth      string    `db:"auth" json:"-"`
+	UserAgent *string   `db:"user_agent" json:"user_agent,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// CreatePushSubscriptionRequest is the PushSubscription of the browser Push API
+type CreatePushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required,url"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// NotificationChannelInApp is the notification list of the app. It is not an outbound
+// channel: every notification is delivered there and read when the user marks it.
+const NotificationChannelInApp = "in_app"
+
+// NotificationReceipt is the delivery and read state of a notification on one channel. Read is
+// the in-app read or the e-mail open (tracking pixel); push deliveries are delivered once the
+// service worker of the browser confirms them.
+type NotificationReceipt struct {
+	Channel     string     `json:"channel"`
+	Status      string     `json:"status"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// NotificationBroadcast is a notification sent at once to every active user, or to the active
+// users of a role
+type NotificationBroadcast struct {
+	ID         string    `db:"id" json:"id"`
+	Type       string    `db:"type" json:"type"`
+	Title      string    `db:"title" json:"title"`
+	Message    string    `db:"message" json:"message"`
+	TargetRole *string   `db:"target_role" json:"target_role,omitempty"`
+	Recipients int       `db:"recipients" json:"recipients"`
+	CreatedBy  *string   `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+// CreateNotificationBroadcastRequest represents the request to broadcast a notification. The
+// type defaults to system, which users cannot turn off.
+type CreateNotificationBroadcastRequest struct {
+	Type    string  `json:"type"`
+	Title   string  `json:"title" binding:"required,max=255"`
+	Message string  `json:"message" binding:"required"`
+	Role    *string `json:"role,omitempty"`
+}
+
+// BroadcastChannelStats aggregates the receipts of the notifications of a broadcast on one
+// channel. Sent counts the deliveries handed to the provider, Delivered those the recipient
+// confirmed and Read the in-app reads and e-mail opens.
+type BroadcastChannelStats struct {
+	Channel       string  `db:"channel" json:"channel"`
+	Total         int     `db:"total" json:"total"`
+	Pending       int     `db:"pending" json:"pending"`
+	Sent          int     `db:"sent" json:"sent"`
+	Failed        int     `db:"failed" json:"failed"`
+	Skipped       int     `db:"skipped" json:"skipped"`
+	Delivered     int     `db:"delivered" json:"delivered"`
+	Read          int     `db:"opened" json:"read"`
+	SentRate      float64 `db:"-" json:"sent_rate"` // shares of the total, 0 to 1
+	DeliveredRate float64 `db:"-" json:"delivered_rate"`
+	ReadRate      float64 `db:"-" json:"read_rate"`
+}
+
+// BroadcastStats is the engagement of a broadcast on each channel
+type BroadcastStats struct {
+	Broadcast NotificationBroadcast   `json:"broadcast"`
+	Channels  []BroadcastChannelStats `json:"channels"`
+}
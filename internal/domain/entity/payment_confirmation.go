@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// PaymentConfirmation records a payment an admin confirmed by hand, paid outside the gateway
+// (e.g. a bank transfer), with the proof of payment
+type PaymentConfirmation struct {
+	ID               string    `db:"id" json:"id"`
+	PaymentID        string    `db:"payment_id" json:"payment_id"`
+	Reason           string    `db:"reason" json:"reason"`
+	PaymentReference *string   `db:"payment_reference" json:"payment_reference,omitempty"`
+	ProofKey         string    `db:"proof_key" json:"-"`
+	ProofFileName    string    `db:"proof_file_name" json:"proof_file_name"`
+	ProofContentType string    `db:"proof_content_type" json:"-"`
+	ProofSize        int64     `db:"proof_size" json:"-"`
+	PaidAt           time.Time `db:"paid_at" json:"paid_at"`
+	ConfirmedBy      *string   `db:"confirmed_by" json:"confirmed_by,omitempty"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+}
+
+// PaymentProof is the proof of payment uploaded when a payment is marked paid
+type PaymentProof struct {
+	FileName         string
+	ContentType      string
+	Size             int64
+	Reason           string
+	PaymentReference *string
+	PaidAt           *string // YYYY-MM-DD, defaults to now
+}
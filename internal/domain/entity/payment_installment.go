@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// PaymentInstallmentPlan is a payment charged in installments of their own, such as the
+// boletos of a carnê, with the status of each installment
+type PaymentInstallmentPlan struct {
+	PaymentID        string               `json:"payment_id"`
+	EnrollmentID     string               `json:"enrollment_id"`
+	PaymentMethod    string               `json:"payment_method"`
+	Status           string               `json:"status"`
+	InstallmentCount int                  `json:"installment_count"`
+	PaidCount        int                  `json:"paid_count"`
+	OverdueCount     int                  `json:"overdue_count"`
+	TotalAmount      money.Cents          `json:"total_amount"`
+	PaidAmount       money.Cents          `json:"paid_amount"`
+	Installments     []PaymentInstallment `json:"installments"`
+}
+
+// PaymentInstallment is one installment of a plan
+type PaymentInstallment struct {
+	PaymentID        string      `json:"payment_id"`
+	Number           int         `json:"number"`
+	Amount           money.Cents `json:"amount"`
+	Status           string      `json:"status"`
+	DueDate          *time.Time  `json:"due_date,omitempty"`
+	PaidAt           *time.Time  `json:"paid_at,omitempty"`
+	GatewayPaymentID *string     `json:"gateway_payment_id,omitempty"`
+	InvoiceURL       *string     `json:"invoice_url,omitempty"`
+}
+
+// NewPaymentInstallmentPlan builds the plan of a parent payment from its installments,
+// ordered by number
+func NewPaymentInstallmentPlan(parent *Payment, installments []Payment) *PaymentInstallmentPlan {
+	plan := &PaymentInstallmentPlan{
+		PaymentID:        parent.ID,
+		EnrollmentID:     parent.EnrollmentID,
+		PaymentMethod:    parent.PaymentMethod,
+		Status:           parent.Status,
+		InstallmentCount: parent.InstallmentCount,
+		TotalAmount:      parent.NetAmount,
+		Installments:     make([]PaymentInstallment, 0, len(installments)),
+	}
+	for i := range installments {
+		p := &installments[i]
+		number := i + 1
+		if p.InstallmentNumber != nil {
+			number = *p.InstallmentNumber
+		}
+		plan.Installments = append(plan.Installments, PaymentInstallment{
+			PaymentID:        p.ID,
+			Number:           number,
+			Amount:           p.NetAmount,
+			Status:           p.Status,
+			DueDate:          p.DueDate,
+			PaidAt:           p.PaidAt,
+			GatewayPaymentID: p.GatewayPaymentID,
+			InvoiceURL:       p.GatewayInvoiceURL,
+		})
+		switch {
+		case p.IsPaid():
+			plan.PaidCount++
+			plan.PaidAmount += p.NetAmount
+		case p.Status == FinPaymentStatusOverdue:
+			plan.OverdueCount++
+		}
+	}
+	return plan
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "testing"
+
+func TestNewPaymentInstallmentPlan(t *testing.T) {
+	parent := &Payment{ID: "p1", EnrollmentID: "e1", PaymentMethod: MethodBoleto, InstallmentCount: 3, NetAmount: 30000, Status: FinPaymentStatusConfirmed}
+	url := "https://pay/2"
+	installments := []Payment{
+		{ID: "p1-1", NetAmount: 10000, Status: FinPaymentStatusReceived},
+		{ID: "p1-2", NetAmount: 10000, Status: FinPaymentStatusConfirmed, GatewayInvoiceURL: &url},
+		{ID: "p1-3", NetAmount: 10000, Status: FinPaymentStatusOverdue},
+	}
+
+	plan := NewPaymentInstallmentPlan(parent, installments)
+	if plan.PaymentID != "p1" || plan.InstallmentCount != 3 || len(plan.Installments) != 3 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if plan.PaidCount != 2 || plan.PaidAmount != 20000 || plan.OverdueCount != 1 || plan.TotalAmount != 30000 {
+		t.Errorf("unexpected totals: %+v", plan)
+	}
+	if plan.Installments[1].Number != 2 || *plan.Installments[1].InvoiceURL != url {
+		t.Errorf("unexpected second installment: %+v", plan.Installments[1])
+	}
+}
@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// PaymentTimelineEvent is a payment transaction as shown to support, with a readable label.
+// Raw gateway payloads and client details are left out.
+type PaymentTimelineEvent struct {
+	ID             string       `json:"id"`
+	At             time.Time    `json:"at"`
+	Type           string       `json:"type"`
+	Source         string       `json:"source"`
+	Label          string       `json:"label"`
+	PreviousStatus *string      `json:"previous_status,omitempty"`
+	NewStatus      string       `json:"new_status"`
+	GatewayEvent   *string      `json:"gateway_event,omitempty"`
+	Amount         *money.Cents `json:"amount,omitempty"`
+	Description    *string      `json:"description,omitempty"`
+	TriggeredBy    *string      `json:"triggered_by,omitempty"`
+}
+
+// PaymentTimeline is the ordered history of a payment
+type PaymentTimeline struct {
+	PaymentID string                 `json:"payment_id"`
+	Status    string                 `json:"status"`
+	Events    []PaymentTimelineEvent `json:"events"`
+}
+
+// FinPaymentStatusLabels maps payment statuses to display labels
+var FinPaymentStatusLabels = map[string]string{
+	FinPaymentStatusPending:           "Pendente",
+	FinPaymentStatusAwaitingPayment:   "Aguardando pagamento",
+	FinPaymentStatusConfirmed:         "Confirmado",
+	FinPaymentStatusReceived:          "Recebido",
+	FinPaymentStatusOverdue:           "Vencido",
+	FinPaymentStatusRefundRequested:   "Estorno solicitado",
+	FinPaymentStatusRefunded:          "Estornado",
+	FinPaymentStatusPartiallyRefunded: "Estornado parcialmente",
+	FinPaymentStatusChargeback:        "Chargeback",
+	FinPaymentStatusFailed:            "Falhou",
+	FinPaymentStatusCancelled:         "Cancelado",
+}
+
+// PaymentStatusLabel returns the display label of a payment status, or the status itself
+// when it is unknown
+func PaymentStatusLabel(status string) string {
+	if label, ok := FinPaymentStatusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// NewPaymentTimelineEvent builds the timeline entry of a transaction
+func NewPaymentTimelineEvent(tx PaymentTransaction) PaymentTimelineEvent {
+	return PaymentTimelineEvent{
+		ID:             tx.ID,
+		At:             tx.CreatedAt,
+		Type:           tx.EventType,
+		Source:         tx.EventSource,
+		Label:          PaymentTimelineLabel(tx),
+		PreviousStatus: tx.PreviousStatus,
+		NewStatus:      tx.NewStatus,
+		GatewayEvent:   tx.GatewayEventID,
+		Amount:         tx.Amount,
+		Description:    tx.Description,
+		TriggeredBy:    tx.TriggeredBy,
+	}
+}
+
+// PaymentTimelineLabel describes a transaction in a sentence, e.g.
+// "Status alterado de Pendente para Confirmado"
+func PaymentTimelineLabel(tx PaymentTransaction) string {
+	switch tx.EventType {
+	case TxEventCreated:
+		return "Cobrança criada (" + PaymentStatusLabel(tx.NewStatus) + ")"
+	case TxEventWebhookReceived:
+		if tx.GatewayEventID != nil && *tx.GatewayEventID != "" {
+			return "Webhook recebido: " + *tx.GatewayEventID
+		}
+		return "Webhook recebido"
+	case TxEventStatusChanged:
+		if tx.PreviousStatus != nil && *tx.PreviousStatus != "" {
+			return fmt.Sprintf("Status alterado de %s para %s",
+				PaymentStatusLabel(*tx.PreviousStatus), PaymentStatusLabel(tx.NewStatus))
+		}
+		return "Status alterado para " + PaymentStatusLabel(tx.NewStatus)
+	case TxEventRefundRequested:
+		return "Estorno solicitado"
+	case TxEventManualConfirmation:
+		if tx.Description != nil && *tx.Description != "" {
+			return "Pagamento confirmado manualmente: " + *tx.Description
+		}
+		return "Pagamento confirmado manualmente"
+	case TxEventError:
+		if tx.Description != nil && *tx.Description != "" {
+			return "Erro: " + *tx.Description
+		}
+		return "Erro"
+	default:
+		return tx.EventType
+	}
+}
@@ -0,0 +1,38 @@
+package entity
+
+import "testing"
+
+func TestPaymentTimelineLabel(t *testing.T) {
+	pending := FinPaymentStatusPending
+	event := "PAYMENT_CONFIRMED"
+	empty := ""
+	reason := "gateway timeout"
+	transfer := "TED do aluno"
+
+	tests := []struct {
+		name string
+		tx   PaymentTransaction
+		want string
+	}{
+		{"created", PaymentTransaction{EventType: TxEventCreated, NewStatus: FinPaymentStatusPending}, "Cobrança criada (Pendente)"},
+		{"webhook", PaymentTransaction{EventType: TxEventWebhookReceived, GatewayEventID: &event}, "Webhook recebido: PAYMENT_CONFIRMED"},
+		{"webhook without event", PaymentTransaction{EventType: TxEventWebhookReceived, GatewayEventID: &empty}, "Webhook recebido"},
+		{"status changed", PaymentTransaction{EventType: TxEventStatusChanged, PreviousStatus: &pending, NewStatus: FinPaymentStatusConfirmed}, "Status alterado de Pendente para Confirmado"},
+		{"status without previous", PaymentTransaction{EventType: TxEventStatusChanged, NewStatus: FinPaymentStatusRefunded}, "Status alterado para Estornado"},
+		{"refund", PaymentTransaction{EventType: TxEventRefundRequested}, "Estorno solicitado"},
+		{"error", PaymentTransaction{EventType: TxEventError, Description: &reason}, "Erro: gateway timeout"},
+		{"manual confirmation", PaymentTransaction{EventType: TxEventManualConfirmation, Description: &transfer}, "Pagamento confirmado manualmente: TED do aluno"},
+		{"unknown type", PaymentTransaction{EventType: "manual_note"}, "manual_note"},
+	}
+	for _, tt := range tests {
+		if got := PaymentTimelineLabel(tt.tx); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestPaymentStatusLabel_Unknown(t *testing.T) {
+	if got := PaymentStatusLabel("on_hold"); got != "on_hold" {
+		t.Errorf("expected unknown statuses to pass through, got %q", got)
+	}
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// Payout batch status constants
+const (
+	PayoutBatchStatusScheduled = "scheduled"
+	PayoutBatchStatusPaid      = "paid"
+	PayoutBatchStatusCancelled = "cancelled"
+)
+
+// PayoutBatch groups pending revenue splits of one instructor into a single bank payout
+type PayoutBatch struct {
+	ID                 string         `db:"id" json:"id"`
+	InstructorID       string         `db:"instructor_id" json:"instructor_id"`
+	InstructorName     *string        `db:"instructor_name" json:"instructor_name,omitempty"`
+	Status             string         `db:"status" json:"status"`
+	PayoutDate         time.Time      `db:"payout_date" json:"payout_date"`
+	TotalAmount        money.Cents    `db:"total_amount" json:"total_amount"`
+	SplitCount         int            `db:"split_count" json:"split_count"`
+	Notes              *string        `db:"notes" json:"notes,omitempty"`
+	PaymentReference   *string        `db:"payment_reference" json:"payment_reference,omitempty"`
+	ReceiptKey         *string        `db:"receipt_key" json:"-"`
+	ReceiptFileName    *string        `db:"receipt_file_name" json:"receipt_file_name,omitempty"`
+	ReceiptContentType *string        `db:"receipt_content_type" json:"-"`
+	ReceiptSize        *int64         `db:"receipt_size" json:"-"`
+	CreatedBy          *string        `db:"created_by" json:"created_by,omitempty"`
+	PaidBy             *string        `db:"paid_by" json:"paid_by,omitempty"`
+	PaidAt             *time.Time     `db:"paid_at" json:"paid_at,omitempty"`
+	CancelledAt        *time.Time     `db:"cancelled_at" json:"cancelled_at,omitempty"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          *time.Time     `db:"updated_at" json:"updated_at,omitempty"`
+	Splits             []RevenueSplit `db:"-" json:"splits,omitempty"`
+}
+
+// HasReceipt reports whether a bank receipt was uploaded for the batch
+func (b *PayoutBatch) HasReceipt() bool {
+	return b.ReceiptKey != nil && *b.ReceiptKey != ""
+}
+
+// CreatePayoutBatchRequest represents the request to batch pending splits of an instructor.
+// Without split_ids every pending split not yet batched is included, optionally only those
+// created up to created_until (YYYY-MM-DD).
+type CreatePayoutBatchRequest struct {
+	InstructorID string   `json:"instructor_id" binding:"required"`
+	PayoutDate   string   `json:"payout_date" binding:"required"`
+	SplitIDs     []string `json:"split_ids"`
+	CreatedUntil *string  `json:"created_until"`
+	Notes        *string  `json:"notes"`
+}
+
+// PayoutReceipt is the bank receipt uploaded when a batch is marked paid
+type PayoutReceipt struct {
+	FileName         string
+	ContentType      string
+	Size             int64
+	PaymentReference *string
+	PaidAt           *string // YYYY-MM-DD, defaults to now
+}
+
+// PayoutBatchFilters holds the filters for listing payout batches
+type PayoutBatchFilters struct {
+	InstructorID string
+	Status       string
+}
+
+// InstructorPayableBalance is the total of pending, unbatched splits of an instructor.
+// Only instructors with a verified payout account can be batched.
+type InstructorPayableBalance struct {
+	InstructorID          string      `db:"instructor_id" json:"instructor_id"`
+	InstructorName        *string     `db:"instructor_name" json:"instructor_name,omitempty"`
+	SplitCount            int         `db:"split_count" json:"split_count"`
+	TotalAmount           money.Cents `db:"total_amount" json:"total_amount"`
+	OldestSplitAt         *time.Time  `db:"oldest_split_at" json:"oldest_split_at,omitempty"`
+	PayoutAccountVerified bool        `db:"payout_account_verified" json:"payout_account_verified"`
+}
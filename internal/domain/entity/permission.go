@@ -0,0 +1,101 @@
+package entity
+
+// Permission resource constants: the kinds of records the permission matrix covers
+const (
+	ResourceGestores  = "gestores"
+	ResourceContratos = "contratos"
+	ResourceAudits    = "audits"
+	ResourceTasks     = "tasks"
+	ResourceSettings  = "settings"
+	ResourceSuppliers = "suppliers"
+)
+
+// Permission action constants
+const (
+	ActionRead   = "read"
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// PermissionScope is how far a permission reaches
+type PermissionScope int
+
+const (
+	// ScopeNone denies the action
+	ScopeNone PermissionScope = iota
+	// ScopeOwn allows the action on the records the user owns: a gestor owns the gestor record
+	// linked to their user (gestores.user_id) and the contracts of that gestor
+	ScopeOwn
+	// ScopeAll allows the action on every record
+	ScopeAll
+)
+
+// gestorPermissions are shared by RoleGestor and the legacy RoleManager
+var gestorPermissions = map[string]PermissionScope{
+	ResourceGestores + ":" + ActionRead:    ScopeAll,
+	ResourceGestores + ":" + ActionUpdate:  ScopeOwn,
+	ResourceContratos + ":" + ActionRead:   ScopeAll,
+	ResourceContratos + ":" + ActionCreate: ScopeOwn,
+	ResourceContratos + ":" + ActionUpdate: ScopeOwn,
+	ResourceContratos + ":" + ActionDelete: ScopeOwn,
+	ResourceAudits + ":" + ActionRead:      ScopeAll,
+	ResourceAudits + ":" + ActionCreate:    ScopeAll,
+	ResourceAudits + ":" + ActionUpdate:    ScopeAll,
+	ResourceAudits + ":" + ActionDelete:    ScopeAll,
+	ResourceTasks + ":" + ActionRead:       ScopeAll,
+	ResourceTasks + ":" + ActionCreate:     ScopeAll,
+	ResourceTasks + ":" + ActionUpdate:     ScopeAll,
+	ResourceTasks + ":" + ActionDelete:     ScopeAll,
+	ResourceSuppliers + ":" + ActionRead:   ScopeAll,
+	ResourceSuppliers + ":" + ActionCreate: ScopeAll,
+	ResourceSuppliers + ":" + ActionUpdate: ScopeAll,
+	ResourceSuppliers + ":" + ActionDelete: ScopeAll,
+}
+
+// fieldStaffPermissions are shared by the field staff roles: they follow audits and work on
+// the tasks of their contracts
+var fieldStaffPermissions = map[string]PermissionScope{
+	ResourceAudits + ":" + ActionRead:  ScopeAll,
+	ResourceTasks + ":" + ActionRead:   ScopeAll,
+	ResourceTasks + ":" + ActionUpdate: ScopeAll,
+}
+
+// rolePermissions is the permission matrix: the scope of each resource:action per role.
+// Admins are allowed everything and are not listed. Audits and tasks are further limited
+// to the contracts the user is assigned to by their team role.
+var rolePermissions = map[UserRole]map[string]PermissionScope{
+	RoleGestor:  gestorPermissions,
+	RoleManager: gestorPermissions,
+	RoleAuditor: {
+		ResourceGestores + ":" + ActionRead:  ScopeAll,
+		ResourceContratos + ":" + ActionRead: ScopeAll,
+		ResourceAudits + ":" + ActionRead:    ScopeAll,
+		ResourceAudits + ":" + ActionCreate:  ScopeAll,
+		ResourceAudits + ":" + ActionUpdate:  ScopeAll,
+		ResourceTasks + ":" + ActionRead:     ScopeAll,
+		ResourceTasks + ":" + ActionUpdate:   ScopeAll,
+		ResourceSuppliers + ":" + ActionRead: ScopeAll,
+	},
+	// Supervisors run the audits and tasks of their field staff
+	RoleSupervisor: {
+		ResourceAudits + ":" + ActionRead:    ScopeAll,
+		ResourceAudits + ":" + ActionCreate:  ScopeAll,
+		ResourceAudits + ":" + ActionUpdate:  ScopeAll,
+		ResourceTasks + ":" + ActionRead:     ScopeAll,
+		ResourceTasks + ":" + ActionCreate:   ScopeAll,
+		ResourceTasks + ":" + ActionUpdate:   ScopeAll,
+		ResourceSuppliers + ":" + ActionRead: ScopeAll,
+	},
+	RoleZelador:    fieldStaffPermissions,
+	RoleManutencao: fieldStaffPermissions,
+	RoleASG:        fieldStaffPermissions,
+}
+
+// RolePermission returns the scope the role has for the action on the resource
+func RolePermission(role UserRole, resource, action string) PermissionScope {
+	if role == RoleAdmin {
+		return ScopeAll
+	}
+	return rolePermissions[role][resource+":"+action]
+}
@@ -0,0 +1,77 @@
+package entity
+
+import "testing"
+
+func TestRolePermission(t *testing.T) {
+	tests := []struct {
+		role     UserRole
+		resource string
+		action   string
+		want     PermissionScope
+	}{
+		{RoleAdmin, ResourceSettings, ActionUpdate, ScopeAll},
+		{RoleAdmin, ResourceContratos, ActionDelete, ScopeAll},
+		{RoleGestor, ResourceContratos, ActionRead, ScopeAll},
+		{RoleGestor, ResourceContratos, ActionDelete, ScopeOwn},
+		{RoleGestor, ResourceGestores, ActionUpdate, ScopeOwn},
+		{RoleGestor, ResourceGestores, ActionDelete, ScopeNone},
+		{RoleGestor, ResourceSettings, ActionRead, ScopeNone},
+		{RoleManager, ResourceContratos, ActionUpdate, ScopeOwn},
+		{RoleAuditor, ResourceAudits, ActionCreate, ScopeAll},
+		{RoleAuditor, ResourceAudits, ActionDelete, ScopeNone},
+		{RoleAuditor, ResourceContratos, ActionUpdate, ScopeNone},
+		{RoleZelador, ResourceTasks, ActionUpdate, ScopeAll},
+		{RoleZelador, ResourceTasks, ActionCreate, ScopeNone},
+		{RoleInstructor, ResourceAudits, ActionRead, ScopeNone},
+		{RoleStudent, ResourceContratos, ActionRead, ScopeNone},
+		{"", ResourceGestores, ActionRead, ScopeNone},
+	}
+	for _, tt := range tests {
+		if got := RolePermission(tt.role, tt.resource, tt.action); got != tt.want {
+			t.Errorf("RolePermission(%q, %q, %q) = %d, want %d", tt.role, tt.resource, tt.action, got, tt.want)
+		}
+	}
+}
+
+// usersRoleEnum is the users.role ENUM of the database (066_auditor_role.sql)
+var usersRoleEnum = []UserRole{"admin", "gestor", "supervisor", "zelador", "manutencao", "asg", "student", "instructor", "auditor"}
+
+// TestRolePermission_DBRoles covers every role a user can have in the database
+func TestRolePermission_DBRoles(t *testing.T) {
+	const (
+		n = ScopeNone
+		o = ScopeOwn
+		a = ScopeAll
+	)
+	resources := []string{ResourceGestores, ResourceContratos, ResourceAudits, ResourceTasks, ResourceSettings, ResourceSuppliers}
+	actions := []string{ActionRead, ActionCreate, ActionUpdate, ActionDelete}
+	// Scopes of read, create, update and delete, per resource in the order above
+	want := map[UserRole][6][4]PermissionScope{
+		"admin":      {{a, a, a, a}, {a, a, a, a}, {a, a, a, a}, {a, a, a, a}, {a, a, a, a}, {a, a, a, a}},
+		"gestor":     {{a, n, o, n}, {a, o, o, o}, {a, a, a, a}, {a, a, a, a}, {n, n, n, n}, {a, a, a, a}},
+		"auditor":    {{a, n, n, n}, {a, n, n, n}, {a, a, a, n}, {a, n, a, n}, {n, n, n, n}, {a, n, n, n}},
+		"supervisor": {{n, n, n, n}, {n, n, n, n}, {a, a, a, n}, {a, a, a, n}, {n, n, n, n}, {a, n, n, n}},
+		"zelador":    {{n, n, n, n}, {n, n, n, n}, {a, n, n, n}, {a, n, a, n}, {n, n, n, n}, {n, n, n, n}},
+		"manutencao": {{n, n, n, n}, {n, n, n, n}, {a, n, n, n}, {a, n, a, n}, {n, n, n, n}, {n, n, n, n}},
+		"asg":        {{n, n, n, n}, {n, n, n, n}, {a, n, n, n}, {a, n, a, n}, {n, n, n, n}, {n, n, n, n}},
+		"student":    {},
+		"instructor": {},
+	}
+	for _, role := range usersRoleEnum {
+		if !role.IsValid() {
+			t.Errorf("UserRole(%q).IsValid() = false, want true", role)
+		}
+		scopes, ok := want[role]
+		if !ok {
+			t.Errorf("no expected permissions for database role %q", role)
+			continue
+		}
+		for i, resource := range resources {
+			for j, action := range actions {
+				if got := RolePermission(role, resource, action); got != scopes[i][j] {
+					t.Errorf("RolePermission(%q, %q, %q) = %d, want %d", role, resource, action, got, scopes[i][j])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Purchase order status constants
+const (
+	PurchaseOrderStatusDraft     = "draft"
+	PurchaseOrderStatusApproved  = "approved"
+	PurchaseOrderStatusDelivered = "delivered"
+	PurchaseOrderStatusInvoiced  = "invoiced"
+	PurchaseOrderStatusCancelled = "cancelled"
+)
+
+// purchaseOrderTransitions lists the allowed status changes
+var purchaseOrderTransitions = map[string][]string{
+	PurchaseOrderStatusDraft:     {PurchaseOrderStatusApproved, PurchaseOrderStatusCancelled},
+	PurchaseOrderStatusApproved:  {PurchaseOrderStatusDelivered, PurchaseOrderStatusCancelled},
+	PurchaseOrderStatusDelivered: {PurchaseOrderStatusInvoiced},
+}
+
+// PurchaseOrder represents a purchase order issued to a supplier for a contract
+type PurchaseOrder struct {
+	ID                   string     `db:"id" json:"id"`
+	Number               string     `db:"number" json:"number"`
+	SupplierID           string     `db:"supplier_id" json:"supplier_id"`
+	ContratoID           string     `db:"contrato_id" json:"contrato_id"`
+	Status               string     `db:"status" json:"status"`
+	Description          *string    `db:"description" json:"description,omitempty"`
+	TotalAmount          float64    `db:"total_amount" json:"total_amount"`
+	ExpectedDeliveryDate *time.Time `db:"expected_delivery_date" json:"expected_delivery_date,omitempty"`
+	CreatedBy            *string    `db:"created_by" json:"created_by,omitempty"`
+	ApprovedBy           *string    `db:"approved_by" json:"approved_by,omitempty"`
+	ApprovedAt           *time.Time `db:"approved_at" json:"approved_at,omitempty"`
+	DeliveredAt          *time.Time `db:"delivered_at" json:"delivered_at,omitempty"`
+	InvoiceNumber        *string    `db:"invoice_number" json:"invoice_number,omitempty"`
+	InvoicedAt           *time.Time `db:"invoiced_at" json:"invoiced_at,omitempty"`
+	CancelledAt          *time.Time `db:"cancelled_at" json:"cancelled_at,omitempty"`
+	CancelReason         *string    `db:"cancel_reason" json:"cancel_reason,omitempty"`
+	CreatedAt            time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt            *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	// Joined fields
+	SupplierName string `db:"supplier_name" json:"supplier_name,omitempty"`
+	ContratoNome string `db:"contrato_nome" json:"contrato_nome,omitempty"`
+
+	Items []PurchaseOrderItem `db:"-" json:"items,omitempty"`
+}
+
+// PurchaseOrderItem represents a line of a purchase order
+type PurchaseOrderItem struct {
+	ID              string  `db:"id" json:"id"`
+	PurchaseOrderID string  `db:"purchase_order_id" json:"purchase_order_id"`
+	Description     string  `db:"description" json:"description"`
+	Quantity        float64 `db:"quantity" json:"quantity"`
+	Unit            *string `db:"unit" json:"unit,omitempty"`
+	UnitPrice       float64 `db:"unit_price" json:"unit_price"`
+	TotalPrice      float64 `db:"total_price" json:"total_price"`
+	Position        int     `db:"position" json:"position"`
+}
+
+// PurchaseOrderItemRequest represents a line in a create/update request
+type PurchaseOrderItemRequest struct {
+	Description string  `json:"description" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required,gt=0"`
+	Unit        *string `json:"unit"`
+	UnitPrice   float64 `json:"unit_price" binding:"gte=0"`
+}
+
+// CreatePurchaseOrderRequest represents the request to create a purchase order
+type CreatePurchaseOrderRequest struct {
+	SupplierID           string                     `json:"supplier_id" binding:"required"`
+	ContratoID           string                     `json:"contrato_id" binding:"required"`
+	Description          *string                    `json:"description"`
+	ExpectedDeliveryDate *string                    `json:"expected_delivery_date"` // YYYY-MM-DD
+	Items                []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// UpdatePurchaseOrderRequest represents the request to update a draft purchase order
+type UpdatePurchaseOrderRequest struct {
+	Description          *string                    `json:"description"`
+	ExpectedDeliveryDate *string                    `json:"expected_delivery_date"` // YYYY-MM-DD
+	Items                []PurchaseOrderItemRequest `json:"items" binding:"omitempty,min=1,dive"`
+}
+
+// InvoicePurchaseOrderRequest represents the request to register the supplier invoice
+type InvoicePurchaseOrderRequest struct {
+	InvoiceNumber string `json:"invoice_number" binding:"required"`
+}
+
+// CancelPurchaseOrderRequest represents the request to cancel a purchase order
+type CancelPurchaseOrderRequest struct {
+	Reason *string `json:"reason"`
+}
+
+// PurchaseOrderFilters holds the filters for listing purchase orders
+type PurchaseOrderFilters struct {
+	Status     string
+	SupplierID string
+	ContratoID string
+}
+
+// CanTransitionPurchaseOrder reports whether a purchase order may move from one status to another
+func CanTransitionPurchaseOrder(from, to string) bool {
+	for _, s := range purchaseOrderTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildPurchaseOrderItems converts request lines into items and returns the order total
+func BuildPurchaseOrderItems(orderID string, reqs []PurchaseOrderItemRequest, newID func() string) ([]PurchaseOrderItem, float64) {
+	items := make([]PurchaseOrderItem, len(reqs))
+	var total float64
+	for i, r := range reqs {
+		lineTotal := math.Round(r.Quantity*r.UnitPrice*100) / 100
+		items[i] = PurchaseOrderItem{
+			ID:              newID(),
+			PurchaseOrderID: orderID,
+			Description:     r.Description,
+			Quantity:        r.Quantity,
+			Unit:            r.Unit,
+			UnitPrice:       r.UnitPrice,
+			TotalPrice:      lineTotal,
+			Position:        i + 1,
+		}
+		total += lineTotal
+	}
+	return items, math.Round(total*100) / 100
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCanTransitionPurchaseOrder(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{PurchaseOrderStatusDraft, PurchaseOrderStatusApproved, true},
+		{PurchaseOrderStatusApproved, PurchaseOrderStatusDelivered, true},
+		{PurchaseOrderStatusDelivered, PurchaseOrderStatusInvoiced, true},
+		{PurchaseOrderStatusDraft, PurchaseOrderStatusCancelled, true},
+		{PurchaseOrderStatusApproved, PurchaseOrderStatusCancelled, true},
+		{PurchaseOrderStatusDraft, PurchaseOrderStatusDelivered, false},
+		{PurchaseOrderStatusDelivered, PurchaseOrderStatusCancelled, false},
+		{PurchaseOrderStatusInvoiced, PurchaseOrderStatusDraft, false},
+		{PurchaseOrderStatusCancelled, PurchaseOrderStatusApproved, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionPurchaseOrder(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionPurchaseOrder(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestBuildPurchaseOrderItems(t *testing.T) {
+	seq := 0
+	newID := func() string {
+		seq++
+		return fmt.Sprintf("item-%d", seq)
+	}
+
+	items, total := BuildPurchaseOrderItems("po-1", []PurchaseOrderItemRequest{
+		{Description: "Cloro 5L", Quantity: 3, UnitPrice: 45.9},
+		{Description: "Luvas", Quantity: 2.5, UnitPrice: 10.333},
+	}, newID)
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].TotalPrice != 137.7 {
+		t.Errorf("expected first line 137.70, got %.2f", items[0].TotalPrice)
+	}
+	if items[1].TotalPrice != 25.83 {
+		t.Errorf("expected second line 25.83, got %.2f", items[1].TotalPrice)
+	}
+	if total != 163.53 {
+		t.Errorf("expected total 163.53, got %.2f", total)
+	}
+	if items[1].Position != 2 || items[1].PurchaseOrderID != "po-1" || items[1].ID != "item-2" {
+		t.Errorf("unexpected item metadata: %+v", items[1])
+	}
+}
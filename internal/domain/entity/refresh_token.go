@@ -0,0 +1,32 @@
+package entity
+
+import "time"
+
+// RefreshToken is a long-lived token exchanged for a new access token. Only the SHA-256 hash
+// of the token is stored. Every use rotates it: the token is revoked and replaced by a new
+// one of the same family, which groups the tokens issued since one login.
+type RefreshToken struct {
+	ID         string     `db:"id" json:"id"`
+	UserID     string     `db:"user_id" json:"user_id"`
+	FamilyID   string     `db:"family_id" json:"family_id"`
+	TokenHash  string     `db:"token_hash" json:"-"`
+	ExpiresAt  time.Time  `db:"expires_at" json:"expires_at"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+	ReplacedBy *string    `db:"replaced_by" json:"replaced_by,omitempty"`
+}
+
+// Active reports whether the token can still be exchanged at the given time
+func (t *RefreshToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// Rotated reports whether the token was revoked because it was exchanged for a new one
+func (t *RefreshToken) Rotated() bool {
+	return t.RevokedAt != nil && t.ReplacedBy != nil
+}
+
+// RefreshTokenRequest represents a request carrying a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
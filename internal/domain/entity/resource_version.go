@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ResourceVersion summarizes the state of a table for conditional requests: any insert,
+// delete or update changes the row count or the latest modification time.
+type ResourceVersion struct {
+	Table        string     `db:"-" json:"table"`
+	Count        int64      `db:"row_count" json:"count"`
+	LastModified *time.Time `db:"last_modified" json:"last_modified,omitempty"`
+	CheckedAt    time.Time  `db:"checked_at" json:"-"`
+}
+
+// Settled reports whether the version can be cached. Timestamps have second precision, so
+// while the latest change is within the current second another change may still land on the
+// same timestamp without being noticed.
+func (v ResourceVersion) Settled() bool {
+	return v.LastModified == nil || v.LastModified.Before(v.CheckedAt.Truncate(time.Second))
+}
+
+// WeakETag builds a weak ETag for a representation derived from the versions; scope
+// distinguishes representations of the same data, e.g. the URL and the requesting user
+func WeakETag(scope string, versions ...ResourceVersion) string {
+	h := sha256.New()
+	io.WriteString(h, scope)
+	for _, v := range versions {
+		modified := int64(0)
+		if v.LastModified != nil {
+			modified = v.LastModified.UnixNano()
+		}
+		fmt.Fprintf(h, "|%s:%d:%d", v.Table, v.Count, modified)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// ETagMatches reports whether an If-None-Match header matches the ETag; weak comparison
+// ignores the W/ prefix
+func ETagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResourceVersion_Settled(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 30, 0, time.UTC)
+	same := now
+	earlier := now.Add(-time.Second)
+
+	if (ResourceVersion{LastModified: &same, CheckedAt: now.Add(400 * time.Millisecond)}).Settled() {
+		t.Error("a change within the current second should not be settled")
+	}
+	if !(ResourceVersion{LastModified: &earlier, CheckedAt: now}).Settled() {
+		t.Error("a change in a past second should be settled")
+	}
+	if !(ResourceVersion{CheckedAt: now}).Settled() {
+		t.Error("an empty table should be settled")
+	}
+}
+
+func TestWeakETag(t *testing.T) {
+	modified := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	v := ResourceVersion{Table: "tasks", Count: 3, LastModified: &modified}
+
+	etag := WeakETag("/api/v1/tasks|u-1", v)
+	if etag != WeakETag("/api/v1/tasks|u-1", v) {
+		t.Error("ETag should be stable for the same versions")
+	}
+	if etag == WeakETag("/api/v1/tasks|u-2", v) {
+		t.Error("ETag should differ between scopes")
+	}
+	v.Count = 4
+	if etag == WeakETag("/api/v1/tasks|u-1", v) {
+		t.Error("ETag should change with the row count")
+	}
+	if etag[:3] != `W/"` {
+		t.Errorf("expected a weak ETag, got %s", etag)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `W/"abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"xyz", W/"abc"`, true},
+		{`"xyz"`, false},
+		{"*", true},
+	}
+	for _, tt := range tests {
+		if got := ETagMatches(tt.header, etag); got != tt.want {
+			t.Errorf("ETagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -1,23 +1,40 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
 
 // RevenueSplit represents a revenue split calculation
 type RevenueSplit struct {
-	ID               string     `db:"id" json:"id"`
-	EnrollmentID     string     `db:"enrollment_id" json:"enrollment_id"`
-	PaymentID        string     `db:"payment_id" json:"payment_id"`
-	GrossAmount      float64    `db:"gross_amount" json:"gross_amount"`
-	NetAmount        float64    `db:"net_amount" json:"net_amount"`
-	PlatformFee      float64    `db:"platform_fee" json:"platform_fee"`
-	PaymentFee       float64    `db:"payment_fee" json:"payment_fee"`
-	InstructorAmount float64    `db:"instructor_amount" json:"instructor_amount"`
-	PlatformAmount   float64    `db:"platform_amount" json:"platform_amount"`
-	InstructorID     *string    `db:"instructor_id" json:"instructor_id,omitempty"`
-	PaymentMethod    string     `db:"payment_method" json:"payment_method"`
-	Status           string     `db:"status" json:"status"`
-	ProcessedAt      *time.Time `db:"processed_at" json:"processed_at,omitempty"`
-	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	ID               string      `db:"id" json:"id"`
+	EnrollmentID     string      `db:"enrollment_id" json:"enrollment_id"`
+	PaymentID        string      `db:"payment_id" json:"payment_id"`
+	GrossAmount      money.Cents `db:"gross_amount" json:"gross_amount"`
+	NetAmount        money.Cents `db:"net_amount" json:"net_amount"`
+	PlatformFee      money.Cents `db:"platform_fee" json:"platform_fee"`
+	PaymentFee       money.Cents `db:"payment_fee" json:"payment_fee"`
+	InstructorAmount money.Cents `db:"instructor_amount" json:"instructor_amount"`
+	PlatformAmount   money.Cents `db:"platform_amount" json:"platform_amount"`
+	InstructorID     *string     `db:"instructor_id" json:"instructor_id,omitempty"`
+	PaymentMethod    string      `db:"payment_method" json:"payment_method"`
+	Status           string      `db:"status" json:"status"`
+	PayoutBatchID    *string     `db:"payout_batch_id" json:"payout_batch_id,omitempty"`
+	TransferID       *string     `db:"transfer_id" json:"transfer_id,omitempty"`
+	TransferStatus   *string     `db:"transfer_status" json:"transfer_status,omitempty"`
+	TransferError    *string     `db:"transfer_error" json:"transfer_error,omitempty"`
+	TransferredAt    *time.Time  `db:"transferred_at" json:"transferred_at,omitempty"`
+	ProcessedAt      *time.Time  `db:"processed_at" json:"processed_at,omitempty"`
+	CreatedAt        time.Time   `db:"created_at" json:"created_at"`
+
+	// InstallmentNumber is the card installment the split covers, nil when it covers the
+	// whole payment
+	InstallmentNumber *int `db:"installment_number" json:"installment_number,omitempty"`
+
+	// Parties is the share of each party (instructor, affiliates, platform), when loaded
+	Parties []RevenueSplitParty `db:"-" json:"parties,omitempty"`
 }
 
 // Revenue split status constants
@@ -25,6 +42,16 @@ const (
 	RevenueSplitStatusPending   = "pending"
 	RevenueSplitStatusProcessed = "processed"
 	RevenueSplitStatusFailed    = "failed"
+	RevenueSplitStatusReversed  = "reversed"
+)
+
+// Revenue split transfer status constants (automatic payout through the gateway)
+const (
+	SplitTransferStatusPending    = "pending"
+	SplitTransferStatusProcessing = "processing"
+	SplitTransferStatusDone       = "done"
+	SplitTransferStatusFailed     = "failed"
+	SplitTransferStatusCancelled  = "cancelled"
 )
 
 // PaymentFees holds the fee configuration for different payment methods
@@ -51,6 +78,8 @@ type CalculateSplitRequest struct {
 	PaymentMethod         string  `json:"payment_method" binding:"required"`
 	InstructorPercent     float64 `json:"instructor_percent,omitempty"`
 	PlatformPercent       float64 `json:"platform_percent,omitempty"`
+	CourseID              string  `json:"course_id,omitempty"`
+	InstructorID          string  `json:"instructor_id,omitempty"`
 }
 
 // CalculateSplitResponse represents the response of revenue split calculation
@@ -63,6 +92,10 @@ type CalculateSplitResponse struct {
 	PlatformAmount   float64 `json:"platform_amount"`
 	InstructorPercent float64 `json:"instructor_percent"`
 	PlatformPercent   float64 `json:"platform_percent"`
+
+	// Set when the split configuration (global or a course/instructor override) was applied
+	RuleScope string              `json:"rule_scope,omitempty"`
+	Parties   []RevenueSplitParty `json:"parties,omitempty"`
 }
 
 // CalculatePaymentFee calculates the payment gateway fee based on method
@@ -111,3 +144,48 @@ func CalculateRevenueSplit(grossAmount float64, paymentMethod string, instructor
 		PlatformPercent:   platformPercent,
 	}
 }
+
+// InstallmentShare returns the part of total covered by installment number of count. Every
+// installment gets total/count rounded down to the cent and the last one the remainder, so
+// the installments add up to total.
+func InstallmentShare(total money.Cents, count, number int) money.Cents {
+	if count <= 1 {
+		return total
+	}
+	parts := total.Split(count)
+	return parts[min(max(number, 1), count)-1]
+}
+
+// ProrateRefund shares a refund of a payment among its splits by their gross amounts, the
+// last split taking the rounding remainder. The refund is capped at what the splits received.
+func ProrateRefund(splits []RevenueSplit, refund money.Cents) []money.Cents {
+	var gross money.Cents
+	for _, split := range splits {
+		gross += split.GrossAmount
+	}
+	shares := make([]money.Cents, len(splits))
+	if gross <= 0 || refund <= 0 {
+		return shares
+	}
+	refund = min(refund, gross)
+
+	remaining := refund
+	for i, split := range splits {
+		if i == len(splits)-1 {
+			shares[i] = remaining
+			break
+		}
+		shares[i] = min(refund.Mul(float64(split.GrossAmount)/float64(gross)), remaining)
+		remaining -= shares[i]
+	}
+	return shares
+}
+
+// EventID keys an event of the split, such as a refund or a transfer, in the instructor
+// ledger: eventID, plus the installment number when the payment has one split per installment
+func (s *RevenueSplit) EventID(eventID string) string {
+	if s.InstallmentNumber == nil {
+		return eventID
+	}
+	return fmt.Sprintf("%s:%d", eventID, *s.InstallmentNumber)
+}
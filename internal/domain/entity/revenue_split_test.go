@@ -3,6 +3,8 @@ package entity
 import (
 	"math"
 	"testing"
+
+	"github.com/condotrack/api/pkg/money"
 )
 
 func almostEqual(a, b, epsilon float64) bool {
@@ -140,3 +142,67 @@ func TestCalculateRevenueSplit_CustomPercents(t *testing.T) {
 		t.Errorf("PlatformPercent: expected 20, got %f", result.PlatformPercent)
 	}
 }
+
+func TestInstallmentShare(t *testing.T) {
+	tests := []struct {
+		total         money.Cents
+		count, number int
+		want          money.Cents
+	}{
+		{10000, 1, 1, 10000},
+		{10000, 3, 1, 3333},
+		{10000, 3, 2, 3333},
+		{10000, 3, 3, 3334},
+		{9999, 2, 2, 5000},
+		{12000, 12, 12, 1000},
+	}
+	for _, tt := range tests {
+		if got := InstallmentShare(tt.total, tt.count, tt.number); got != tt.want {
+			t.Errorf("InstallmentShare(%v, %d, %d) = %v, want %v", tt.total, tt.count, tt.number, got, tt.want)
+		}
+	}
+
+	var sum money.Cents
+	for n := 1; n <= 7; n++ {
+		sum += InstallmentShare(25001, 7, n)
+	}
+	if sum != 25001 {
+		t.Errorf("installments add up to %v, want 250.01", sum)
+	}
+}
+
+func TestProrateRefund(t *testing.T) {
+	splits := []RevenueSplit{{GrossAmount: 3333}, {GrossAmount: 3333}, {GrossAmount: 3334}}
+	tests := []struct {
+		refund money.Cents
+		want   []money.Cents
+	}{
+		{10000, []money.Cents{3333, 3333, 3334}},
+		{5000, []money.Cents{1667, 1667, 1666}},
+		{20000, []money.Cents{3333, 3333, 3334}},
+		{0, []money.Cents{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		got := ProrateRefund(splits, tt.refund)
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ProrateRefund(%v) = %v, want %v", tt.refund, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if got := ProrateRefund([]RevenueSplit{{GrossAmount: 45000}}, 12000); got[0] != 12000 {
+		t.Errorf("expected a single split to take the whole refund, got %v", got)
+	}
+}
+
+func TestRevenueSplitEventID(t *testing.T) {
+	installment := 2
+	if got := (&RevenueSplit{}).EventID("c1"); got != "c1" {
+		t.Errorf("EventID of a whole-payment split = %q, want c1", got)
+	}
+	if got := (&RevenueSplit{InstallmentNumber: &installment}).EventID("c1"); got != "c1:2" {
+		t.Errorf("EventID of an installment split = %q, want c1:2", got)
+	}
+}
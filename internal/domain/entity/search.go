@@ -0,0 +1,63 @@
+package entity
+
+// Search result types, one per entity covered by the global search
+const (
+	SearchTypeContrato = "contrato"
+	SearchTypeSupplier = "supplier"
+	SearchTypeTask     = "task"
+	SearchTypeGestor   = "gestor"
+)
+
+// SearchTypes lists the searchable types in the order their groups are returned
+var SearchTypes = []string{SearchTypeContrato, SearchTypeSupplier, SearchTypeTask, SearchTypeGestor}
+
+// IsValidSearchType reports whether t is a searchable type
+func IsValidSearchType(t string) bool {
+	for _, st := range SearchTypes {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// MinSearchLength is the shortest search accepted
+	MinSearchLength = 2
+	// DefaultSearchLimit is the number of results returned per type when no limit is given
+	DefaultSearchLimit = 5
+	// MaxSearchLimit caps the results returned per type
+	MaxSearchLimit = 20
+)
+
+// SearchFilter holds the parameters of a global search
+type SearchFilter struct {
+	// Terms are matched against the text columns of each type; every term must match
+	Terms string
+	Types []string // the types searched; every type when empty
+	Limit int      // results per type
+}
+
+// SearchResult is a record matching a search. Score ranks the results of a type: the
+// FULLTEXT relevance, or 3, 2 and 1 for a title equal to, starting with or only containing
+// the search when the type is searched with LIKE.
+type SearchResult struct {
+	Type     string  `db:"type" json:"type"`
+	ID       string  `db:"id" json:"id"`
+	Title    string  `db:"title" json:"title"`
+	Subtitle *string `db:"subtitle" json:"subtitle,omitempty"`
+	Score    float64 `db:"score" json:"score"`
+}
+
+// SearchGroup holds the results of a type, best ranked first
+type SearchGroup struct {
+	Type    string         `json:"type"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchResponse is the result of a global search, grouped by type
+type SearchResponse struct {
+	Query  string        `json:"query"`
+	Total  int           `json:"total"`
+	Groups []SearchGroup `json:"groups"`
+}
@@ -25,6 +25,29 @@ const (
 	CategoryEmail   SettingCategory = "email"
 )
 
+// SettingScope is the level a setting value applies to. Values resolve from the most specific
+// scope: the contract, then its organization (the gestor managing it), then the global value.
+type SettingScope string
+
+const (
+	SettingScopeGlobal       SettingScope = "global"
+	SettingScopeOrganization SettingScope = "organization"
+	SettingScopeContract     SettingScope = "contract"
+)
+
+// IsValid reports whether the scope is known
+func (s SettingScope) IsValid() bool {
+	switch s {
+	case SettingScopeGlobal, SettingScopeOrganization, SettingScopeContract:
+		return true
+	}
+	return false
+}
+
+// SecretMask replaces the value of secret settings in read responses. Sending it back in an
+// update keeps the stored secret.
+const SecretMask = "********"
+
 // Setting represents a system configuration setting
 type Setting struct {
 	ID              string          `db:"id" json:"id"`
@@ -67,7 +90,7 @@ func (s *Setting) ToPublic() *SettingPublic {
 		hasValue = true
 		if s.IsSecret {
 			// Mask secret values
-			value = "********"
+			value = SecretMask
 		} else {
 			value = *s.Value
 		}
@@ -110,6 +133,77 @@ type BulkUpdateSettingsRequest struct {
 	Settings map[string]string `json:"settings"` // key -> value
 }
 
+// Setting change sources
+const (
+	SettingChangeSourceUpdate   = "update"
+	SettingChangeSourceBulk     = "bulk"
+	SettingChangeSourceRollback = "rollback"
+)
+
+// SettingChange records a change of a setting value and who made it: the global value or the
+// override of an organization or contract. A nil NewValue means the override was removed.
+// Secret values are kept as stored (encrypted) and masked in responses.
+type SettingChange struct {
+	ID         string       `db:"id" json:"id"`
+	SettingKey string       `db:"setting_key" json:"key"`
+	Scope      SettingScope `db:"scope" json:"scope"`
+	ScopeID    *string      `db:"scope_id" json:"scope_id,omitempty"`
+	OldValue   *string      `db:"old_value" json:"old_value"`
+	NewValue   *string      `db:"new_value" json:"new_value"`
+	Source     string       `db:"source" json:"source"`
+	ChangedBy  *string      `db:"changed_by" json:"changed_by,omitempty"`
+	RollbackOf *string      `db:"rollback_of" json:"rollback_of,omitempty"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+}
+
+// Masked returns a copy of the change with its values replaced by SecretMask
+func (c SettingChange) Masked() SettingChange {
+	mask := SecretMask
+	if c.OldValue != nil && *c.OldValue != "" {
+		c.OldValue = &mask
+	}
+	if c.NewValue != nil && *c.NewValue != "" {
+		c.NewValue = &mask
+	}
+	return c
+}
+
+// RollbackSettingRequest restores the value a setting had before the given change
+type RollbackSettingRequest struct {
+	ChangeID string `json:"change_id" binding:"required"`
+}
+
+// SettingOverride is the value of a setting for one organization or contract
+type SettingOverride struct {
+	ID         string       `db:"id" json:"id"`
+	SettingKey string       `db:"setting_key" json:"key"`
+	Scope      SettingScope `db:"scope" json:"scope"`
+	ScopeID    string       `db:"scope_id" json:"scope_id"`
+	Value      string       `db:"setting_value" json:"value"`
+	CreatedAt  time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt  *time.Time   `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// SettingTarget is what a setting is resolved for; empty IDs skip their scope
+type SettingTarget struct {
+	OrganizationID string
+	ContractID     string
+}
+
+// ResolvedSetting is the value of a setting for a target and the scope it came from
+type ResolvedSetting struct {
+	Key     string       `json:"key"`
+	Value   string       `json:"value"`
+	Scope   SettingScope `json:"scope"`
+	ScopeID string       `json:"scope_id,omitempty"`
+}
+
+// SetSettingOverrideRequest represents a request to set the value of a setting for an
+// organization or contract
+type SetSettingOverrideRequest struct {
+	Value string `json:"value"`
+}
+
 // CategoryLabels maps category keys to display labels
 var CategoryLabels = map[SettingCategory]string{
 	CategoryGeneral: "Geral",
@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// Split adjustment status constants
+const (
+	SplitAdjustmentStatusPending  = "pending"
+	SplitAdjustmentStatusApproved = "approved"
+	SplitAdjustmentStatusRejected = "rejected"
+)
+
+// Split dispute status constants
+const (
+	SplitDisputeStatusOpen        = "open"
+	SplitDisputeStatusUnderReview = "under_review"
+	SplitDisputeStatusResolved    = "resolved"
+	SplitDisputeStatusRejected    = "rejected"
+)
+
+// splitDisputeTransitions lists the allowed dispute status changes
+var splitDisputeTransitions = map[string][]string{
+	SplitDisputeStatusOpen:        {SplitDisputeStatusUnderReview, SplitDisputeStatusResolved, SplitDisputeStatusRejected},
+	SplitDisputeStatusUnderReview: {SplitDisputeStatusResolved, SplitDisputeStatusRejected},
+}
+
+// SplitAdjustment is a change of the instructor share of a revenue split. It is requested
+// with a reason and only applied once approved by an admin other than the requester; the
+// difference is moved between the instructor and the platform.
+type SplitAdjustment struct {
+	ID                       string      `db:"id" json:"id"`
+	SplitID                  string      `db:"split_id" json:"split_id"`
+	DisputeID                *string     `db:"dispute_id" json:"dispute_id,omitempty"`
+	Status                   string      `db:"status" json:"status"`
+	Reason                   string      `db:"reason" json:"reason"`
+	PreviousInstructorAmount money.Cents `db:"previous_instructor_amount" json:"previous_instructor_amount"`
+	InstructorAmount         money.Cents `db:"instructor_amount" json:"instructor_amount"`
+	RequestedBy              string      `db:"requested_by" json:"requested_by"`
+	DecidedBy                *string     `db:"decided_by" json:"decided_by,omitempty"`
+	DecisionNotes            *string     `db:"decision_notes" json:"decision_notes,omitempty"`
+	DecidedAt                *time.Time  `db:"decided_at" json:"decided_at,omitempty"`
+	CreatedAt                time.Time   `db:"created_at" json:"created_at"`
+}
+
+// Delta is the change of the instructor share the adjustment applies
+func (a *SplitAdjustment) Delta() money.Cents {
+	return a.InstructorAmount - a.PreviousInstructorAmount
+}
+
+// SplitAdjustmentFilters holds the filter parameters for listing split adjustments
+type SplitAdjustmentFilters struct {
+	SplitID string
+	Status  string
+}
+
+// CreateSplitAdjustmentRequest represents the request to adjust the instructor share of a split
+type CreateSplitAdjustmentRequest struct {
+	InstructorAmount *money.Cents `json:"instructor_amount" binding:"required,gte=0"`
+	Reason           string       `json:"reason" binding:"required,max=500"`
+	DisputeID        *string      `json:"dispute_id"`
+}
+
+// DecideSplitAdjustmentRequest represents the request to approve or reject an adjustment
+type DecideSplitAdjustmentRequest struct {
+	Notes *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+// SplitDispute is a contestation of a revenue split opened by its instructor
+type SplitDispute struct {
+	ID              string       `db:"id" json:"id"`
+	SplitID         string       `db:"split_id" json:"split_id"`
+	InstructorID    string       `db:"instructor_id" json:"instructor_id"`
+	Status          string       `db:"status" json:"status"`
+	Reason          string       `db:"reason" json:"reason"`
+	ExpectedAmount  *money.Cents `db:"expected_amount" json:"expected_amount,omitempty"`
+	ResolutionNotes *string      `db:"resolution_notes" json:"resolution_notes,omitempty"`
+	ResolvedBy      *string      `db:"resolved_by" json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time   `db:"resolved_at" json:"resolved_at,omitempty"`
+	AdjustmentID    *string      `db:"adjustment_id" json:"adjustment_id,omitempty"`
+	CreatedAt       time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt       *time.Time   `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// IsActive reports whether the dispute still awaits a resolution
+func (d *SplitDispute) IsActive() bool {
+	return d.Status == SplitDisputeStatusOpen || d.Status == SplitDisputeStatusUnderReview
+}
+
+// SplitDisputeFilters holds the filter parameters for listing split disputes
+type SplitDisputeFilters struct {
+	SplitID      string
+	InstructorID string
+	Status       string
+}
+
+// OpenSplitDisputeRequest represents the request of an instructor to contest a split
+type OpenSplitDisputeRequest struct {
+	Reason         string       `json:"reason" binding:"required,max=1000"`
+	ExpectedAmount *money.Cents `json:"expected_amount" binding:"omitempty,gte=0"`
+}
+
+// ResolveSplitDisputeRequest closes a dispute. Resolving it with an instructor amount
+// requests the matching adjustment, which still needs approval.
+type ResolveSplitDisputeRequest struct {
+	Status           string       `json:"status" binding:"required,oneof=resolved rejected"`
+	ResolutionNotes  string       `json:"resolution_notes" binding:"required,max=1000"`
+	InstructorAmount *money.Cents `json:"instructor_amount" binding:"omitempty,gte=0"`
+}
+
+// CanTransitionSplitDispute reports whether a dispute may move from one status to another
+func CanTransitionSplitDispute(from, to string) bool {
+	for _, s := range splitDisputeTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// AdjustInstructorShare sets the instructor share of a split, taking the difference from
+// (or giving it back to) the platform share. Affiliate shares are left untouched.
+func AdjustInstructorShare(split *RevenueSplit, amount money.Cents) error {
+	delta := amount - split.InstructorAmount
+	if split.PlatformAmount-delta < 0 {
+		return errors.New("invalid adjustment: instructor amount exceeds the instructor and platform shares")
+	}
+
+	if len(split.Parties) > 0 {
+		instructor, platform := -1, -1
+		for i, p := range split.Parties {
+			switch p.PartyType {
+			case SplitPartyInstructor:
+				instructor = i
+			case SplitPartyPlatform:
+				platform = i
+			}
+		}
+		if instructor < 0 || platform < 0 {
+			return errors.New("invalid adjustment: split has no instructor share")
+		}
+		split.Parties[instructor].Amount = amount
+		split.Parties[platform].Amount -= delta
+		split.ApplyParties(split.Parties)
+		return nil
+	}
+
+	split.InstructorAmount = amount
+	split.PlatformAmount -= delta
+	split.PlatformFee = split.PlatformAmount
+	return nil
+}
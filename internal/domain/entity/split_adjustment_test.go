@@ -0,0 +1,71 @@
+package entity
+
+import "testing"
+
+func TestAdjustInstructorShare(t *testing.T) {
+	split := &RevenueSplit{NetAmount: 10000, InstructorAmount: 7000, PlatformAmount: 3000, PlatformFee: 3000}
+	if err := AdjustInstructorShare(split, 7550); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if split.InstructorAmount != 7550 || split.PlatformAmount != 2450 || split.PlatformFee != 2450 {
+		t.Errorf("split = %+v", split)
+	}
+
+	if err := AdjustInstructorShare(split, 10001); err == nil {
+		t.Error("amount above instructor and platform shares: expected error")
+	}
+	if split.InstructorAmount != 7550 {
+		t.Errorf("failed adjustment changed the split: %+v", split)
+	}
+}
+
+func TestAdjustInstructorShareWithParties(t *testing.T) {
+	split := &RevenueSplit{NetAmount: 10000}
+	split.ApplyParties([]RevenueSplitParty{
+		{PartyType: SplitPartyInstructor, Amount: 6000},
+		{PartyType: SplitPartyAffiliate, Amount: 1000},
+		{PartyType: SplitPartyPlatform, Amount: 3000},
+	})
+
+	if err := AdjustInstructorShare(split, 5000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if split.InstructorAmount != 5000 || split.PlatformAmount != 4000 {
+		t.Errorf("split amounts = %v / %v", split.InstructorAmount, split.PlatformAmount)
+	}
+	if split.Parties[1].Amount != 1000 || split.Parties[2].Amount != 4000 {
+		t.Errorf("parties = %+v", split.Parties)
+	}
+
+	noInstructor := &RevenueSplit{}
+	noInstructor.ApplyParties([]RevenueSplitParty{{PartyType: SplitPartyPlatform, Amount: 10000}})
+	if err := AdjustInstructorShare(noInstructor, 1000); err == nil {
+		t.Error("split without instructor party: expected error")
+	}
+}
+
+func TestSplitAdjustmentDelta(t *testing.T) {
+	a := SplitAdjustment{PreviousInstructorAmount: 7000, InstructorAmount: 6545}
+	if got := a.Delta(); got != -455 {
+		t.Errorf("Delta() = %v, want -4.55", got)
+	}
+}
+
+func TestCanTransitionSplitDispute(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{SplitDisputeStatusOpen, SplitDisputeStatusUnderReview, true},
+		{SplitDisputeStatusOpen, SplitDisputeStatusResolved, true},
+		{SplitDisputeStatusUnderReview, SplitDisputeStatusRejected, true},
+		{SplitDisputeStatusUnderReview, SplitDisputeStatusOpen, false},
+		{SplitDisputeStatusResolved, SplitDisputeStatusUnderReview, false},
+		{SplitDisputeStatusRejected, SplitDisputeStatusResolved, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransitionSplitDispute(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionSplitDispute(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// Split party type constants
+const (
+	SplitPartyInstructor = "instructor" // instructor of the enrolled course
+	SplitPartyAffiliate  = "affiliate"  // partner identified by party_id
+	SplitPartyPlatform   = "platform"   // the platform itself; receives rounding differences
+)
+
+// Split configuration scope constants, from the most to the least specific
+const (
+	SplitScopeCourse     = "course"     // override for one course
+	SplitScopeInstructor = "instructor" // override for every course of an instructor
+	SplitScopeGlobal     = "global"     // configuration for everything else
+)
+
+// SplitRule is one ordered entry of a revenue split configuration.
+// Fixed amounts are taken from the net first, in rule order; percentages then share what is left.
+type SplitRule struct {
+	ID          string      `db:"id" json:"id"`
+	Scope       string      `db:"scope" json:"scope"`
+	ScopeID     string      `db:"scope_id" json:"scope_id,omitempty"`
+	Position    int         `db:"position" json:"position"`
+	PartyType   string      `db:"party_type" json:"party_type"`
+	PartyID     *string     `db:"party_id" json:"party_id,omitempty"`
+	Label       *string     `db:"label" json:"label,omitempty"`
+	Percent     float64     `db:"percent" json:"percent"`
+	FixedAmount money.Cents `db:"fixed_amount" json:"fixed_amount"`
+	CreatedAt   time.Time   `db:"created_at" json:"created_at"`
+}
+
+// SplitRuleInput is one party of a split configuration request
+type SplitRuleInput struct {
+	PartyType   string      `json:"party_type" binding:"required,oneof=instructor affiliate platform"`
+	PartyID     *string     `json:"party_id"`
+	Label       *string     `json:"label"`
+	Percent     float64     `json:"percent" binding:"gte=0,lte=100"`
+	FixedAmount money.Cents `json:"fixed_amount" binding:"gte=0"`
+}
+
+// SaveSplitRulesRequest replaces the split configuration; parties are applied in the given order
+type SaveSplitRulesRequest struct {
+	Rules []SplitRuleInput `json:"rules" binding:"required,min=1,dive"`
+}
+
+// SplitRuleSet is the ordered configuration of one scope
+type SplitRuleSet struct {
+	Scope   string      `json:"scope"`
+	ScopeID string      `json:"scope_id,omitempty"`
+	Rules   []SplitRule `json:"rules"`
+}
+
+// RevenueSplitParty is the share of one party in a revenue split. The rule is copied onto the
+// row so later configuration changes do not alter splits already created; the status follows
+// the revenue split.
+type RevenueSplitParty struct {
+	ID           string      `db:"id" json:"id"`
+	SplitID      string      `db:"split_id" json:"split_id"`
+	PaymentID    string      `db:"payment_id" json:"payment_id"`
+	EnrollmentID string      `db:"enrollment_id" json:"enrollment_id"`
+	Position     int         `db:"position" json:"position"`
+	PartyType    string      `db:"party_type" json:"party_type"`
+	PartyID      *string     `db:"party_id" json:"party_id,omitempty"`
+	Label        *string     `db:"label" json:"label,omitempty"`
+	Percent      float64     `db:"percent" json:"percent"`
+	FixedAmount  money.Cents `db:"fixed_amount" json:"fixed_amount"`
+	Amount       money.Cents `db:"amount" json:"amount"`
+	Status       string      `db:"status" json:"status"`
+	CreatedAt    time.Time   `db:"created_at" json:"created_at"`
+}
+
+// SplitPartyLedger lists the shares of a party with totals per split status
+type SplitPartyLedger struct {
+	PartyType       string              `json:"party_type"`
+	PartyID         *string             `json:"party_id,omitempty"`
+	TotalAmount     money.Cents         `json:"total_amount"`
+	PendingAmount   money.Cents         `json:"pending_amount"`
+	ProcessedAmount money.Cents         `json:"processed_amount"`
+	ReversedAmount  money.Cents         `json:"reversed_amount"`
+	Entries         []RevenueSplitParty `json:"entries"`
+}
+
+// DefaultSplitRules builds the instructor/platform configuration from the global percentages
+func DefaultSplitRules(instructorPercent, platformPercent float64) []SplitRule {
+	return []SplitRule{
+		{Scope: SplitScopeGlobal, Position: 1, PartyType: SplitPartyInstructor, Percent: instructorPercent},
+		{Scope: SplitScopeGlobal, Position: 2, PartyType: SplitPartyPlatform, Percent: platformPercent},
+	}
+}
+
+// GroupSplitRules splits rules ordered by scope, scope ID and position into one set per scope
+func GroupSplitRules(rules []SplitRule) []SplitRuleSet {
+	sets := make([]SplitRuleSet, 0)
+	for _, r := range rules {
+		n := len(sets)
+		if n == 0 || sets[n-1].Scope != r.Scope || sets[n-1].ScopeID != r.ScopeID {
+			sets = append(sets, SplitRuleSet{Scope: r.Scope, ScopeID: r.ScopeID})
+			n++
+		}
+		sets[n-1].Rules = append(sets[n-1].Rules, r)
+	}
+	return sets
+}
+
+// ValidateSplitRules checks that a configuration allocates the whole net amount:
+// one platform party, at most one instructor, affiliates identified, and percentages
+// adding up to exactly 100 so nothing is left once the fixed amounts are taken.
+func ValidateSplitRules(rules []SplitRule) error {
+	if len(rules) == 0 {
+		return errors.New("invalid split configuration: at least one party is required")
+	}
+
+	var platforms, instructors int
+	var percent float64
+	for _, r := range rules {
+		switch r.PartyType {
+		case SplitPartyPlatform:
+			platforms++
+		case SplitPartyInstructor:
+			instructors++
+		case SplitPartyAffiliate:
+			if r.PartyID == nil || *r.PartyID == "" {
+				return fmt.Errorf("invalid split configuration: affiliate at position %d requires party_id", r.Position)
+			}
+		default:
+			return fmt.Errorf("invalid split configuration: unknown party type %q", r.PartyType)
+		}
+		if r.PartyType != SplitPartyAffiliate && r.PartyID != nil && *r.PartyID != "" {
+			return fmt.Errorf("invalid split configuration: %s at position %d must not set party_id", r.PartyType, r.Position)
+		}
+		if r.Percent < 0 || r.Percent > 100 || r.FixedAmount < 0 {
+			return fmt.Errorf("invalid split configuration: negative or out of range share at position %d", r.Position)
+		}
+		percent += r.Percent
+	}
+
+	if platforms != 1 {
+		return errors.New("invalid split configuration: exactly one platform party is required")
+	}
+	if instructors > 1 {
+		return errors.New("invalid split configuration: at most one instructor party is allowed")
+	}
+	if math.Abs(percent-100) > 0.0001 {
+		return fmt.Errorf("invalid split configuration: percentages add up to %.2f, must be 100", percent)
+	}
+	return nil
+}
+
+// AllocateSplit divides the net amount between the parties. Fixed amounts are paid in rule
+// order while the net lasts, so parties earlier in the list are covered first on small
+// payments; the remainder is shared by percentage. Rounding differences go to the platform,
+// so the shares always add up to the net amount.
+func AllocateSplit(netAmount money.Cents, rules []SplitRule, instructorID *string) ([]RevenueSplitParty, error) {
+	net := max(netAmount, 0)
+	parties := make([]RevenueSplitParty, len(rules))
+
+	left := net
+	for i, r := range rules {
+		parties[i] = RevenueSplitParty{
+			Position:    r.Position,
+			PartyType:   r.PartyType,
+			PartyID:     r.PartyID,
+			Label:       r.Label,
+			Percent:     r.Percent,
+			FixedAmount: r.FixedAmount,
+		}
+		if r.PartyType == SplitPartyInstructor {
+			parties[i].PartyID = instructorID
+		}
+		fixed := min(r.FixedAmount, left)
+		parties[i].Amount = fixed
+		left -= fixed
+	}
+
+	platform := -1
+	var allocated money.Cents
+	for i := range parties {
+		parties[i].Amount += left.Percent(parties[i].Percent)
+		allocated += parties[i].Amount
+		if parties[i].PartyType == SplitPartyPlatform {
+			platform = i
+		}
+	}
+	if platform < 0 {
+		return nil, errors.New("invalid split configuration: exactly one platform party is required")
+	}
+
+	parties[platform].Amount += net - allocated
+	if parties[platform].Amount < 0 {
+		return nil, errors.New("invalid split configuration: shares exceed the net amount")
+	}
+	return parties, nil
+}
+
+// RulesFromParties rebuilds the configuration a split was created with, for reallocation
+func RulesFromParties(parties []RevenueSplitParty) []SplitRule {
+	rules := make([]SplitRule, len(parties))
+	for i, p := range parties {
+		rules[i] = SplitRule{
+			Position:    p.Position,
+			PartyType:   p.PartyType,
+			PartyID:     p.PartyID,
+			Label:       p.Label,
+			Percent:     p.Percent,
+			FixedAmount: p.FixedAmount,
+		}
+	}
+	return rules
+}
+
+// ScaleParties reduces every share by the same ratio (partial refunds), keeping the total
+// equal to the new net amount
+func ScaleParties(parties []RevenueSplitParty, keep float64, netAmount money.Cents) {
+	platform := -1
+	var allocated money.Cents
+	for i := range parties {
+		parties[i].Amount = parties[i].Amount.Mul(keep)
+		allocated += parties[i].Amount
+		if parties[i].PartyType == SplitPartyPlatform {
+			platform = i
+		}
+	}
+	if platform >= 0 {
+		parties[platform].Amount += netAmount - allocated
+	}
+}
+
+// ApplyParties copies the party shares onto the instructor and platform totals of the split
+func (s *RevenueSplit) ApplyParties(parties []RevenueSplitParty) {
+	s.InstructorAmount = 0
+	s.PlatformAmount = 0
+	for _, p := range parties {
+		switch p.PartyType {
+		case SplitPartyInstructor:
+			s.InstructorAmount += p.Amount
+		case SplitPartyPlatform:
+			s.PlatformAmount += p.Amount
+		}
+	}
+	s.PlatformFee = s.PlatformAmount
+	s.Parties = parties
+}
+
+func roundShare(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+func strPtr(s string) *string { return &s }
+
+func threePartyRules() []SplitRule {
+	return []SplitRule{
+		{Position: 1, PartyType: SplitPartyAffiliate, PartyID: strPtr("aff-1"), FixedAmount: 1000},
+		{Position: 2, PartyType: SplitPartyInstructor, Percent: 60},
+		{Position: 3, PartyType: SplitPartyPlatform, Percent: 40},
+	}
+}
+
+func TestValidateSplitRules(t *testing.T) {
+	if err := ValidateSplitRules(threePartyRules()); err != nil {
+		t.Fatalf("ValidateSplitRules: unexpected error %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func([]SplitRule) []SplitRule
+	}{
+		{"percentages below 100", func(r []SplitRule) []SplitRule { r[1].Percent = 50; return r }},
+		{"affiliate without id", func(r []SplitRule) []SplitRule { r[0].PartyID = nil; return r }},
+		{"instructor with id", func(r []SplitRule) []SplitRule { r[1].PartyID = strPtr("inst-1"); return r }},
+		{"no platform", func(r []SplitRule) []SplitRule {
+			r[2].PartyType = SplitPartyAffiliate
+			r[2].PartyID = strPtr("aff-2")
+			return r
+		}},
+		{"two instructors", func(r []SplitRule) []SplitRule {
+			r[0] = SplitRule{Position: 1, PartyType: SplitPartyInstructor}
+			return r
+		}},
+		{"negative fixed amount", func(r []SplitRule) []SplitRule { r[0].FixedAmount = -1; return r }},
+		{"empty", func([]SplitRule) []SplitRule { return nil }},
+	}
+	for _, tt := range tests {
+		if err := ValidateSplitRules(tt.modify(threePartyRules())); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestAllocateSplit(t *testing.T) {
+	parties, err := AllocateSplit(10000, threePartyRules(), strPtr("inst-1"))
+	if err != nil {
+		t.Fatalf("AllocateSplit: unexpected error %v", err)
+	}
+
+	want := []money.Cents{1000, 5400, 3600}
+	for i, p := range parties {
+		if p.Amount != want[i] {
+			t.Errorf("party %d (%s) = %v, want %v", i, p.PartyType, p.Amount, want[i])
+		}
+	}
+	if parties[1].PartyID == nil || *parties[1].PartyID != "inst-1" {
+		t.Errorf("instructor party ID = %v, want inst-1", parties[1].PartyID)
+	}
+
+	// Rounding differences go to the platform so the shares add up to the net
+	parties, err = AllocateSplit(3333, []SplitRule{
+		{Position: 1, PartyType: SplitPartyInstructor, Percent: 33.3333},
+		{Position: 2, PartyType: SplitPartyAffiliate, PartyID: strPtr("aff-1"), Percent: 33.3333},
+		{Position: 3, PartyType: SplitPartyPlatform, Percent: 33.3334},
+	}, nil)
+	if err != nil {
+		t.Fatalf("AllocateSplit: unexpected error %v", err)
+	}
+	var sum money.Cents
+	for _, p := range parties {
+		sum += p.Amount
+	}
+	if sum != 3333 {
+		t.Errorf("shares add up to %v, want 33.33", sum)
+	}
+
+	// Fixed amounts are covered in order while the net lasts
+	parties, _ = AllocateSplit(600, threePartyRules(), nil)
+	if parties[0].Amount != 600 || parties[1].Amount != 0 || parties[2].Amount != 0 {
+		t.Errorf("small net = %v/%v/%v, want 6/0/0", parties[0].Amount, parties[1].Amount, parties[2].Amount)
+	}
+}
+
+func TestScaleAndApplyParties(t *testing.T) {
+	parties, _ := AllocateSplit(10000, threePartyRules(), strPtr("inst-1"))
+
+	ScaleParties(parties, 0.5, 5000)
+	split := &RevenueSplit{NetAmount: 5000}
+	split.ApplyParties(parties)
+
+	if parties[0].Amount != 500 || split.InstructorAmount != 2700 || split.PlatformAmount != 1800 {
+		t.Errorf("scaled = affiliate %v, instructor %v, platform %v; want 5/27/18",
+			parties[0].Amount, split.InstructorAmount, split.PlatformAmount)
+	}
+	if split.PlatformFee != split.PlatformAmount || len(split.Parties) != 3 {
+		t.Errorf("ApplyParties did not set platform fee and parties: %+v", split)
+	}
+
+	rules := RulesFromParties(parties)
+	if len(rules) != 3 || rules[0].FixedAmount != 1000 || rules[1].Percent != 60 {
+		t.Errorf("RulesFromParties = %+v, want the original configuration", rules)
+	}
+}
+
+func TestGroupSplitRules(t *testing.T) {
+	sets := GroupSplitRules([]SplitRule{
+		{Scope: SplitScopeCourse, ScopeID: "course-1", Position: 1, PartyType: SplitPartyInstructor, Percent: 80},
+		{Scope: SplitScopeCourse, ScopeID: "course-1", Position: 2, PartyType: SplitPartyPlatform, Percent: 20},
+		{Scope: SplitScopeCourse, ScopeID: "course-2", Position: 1, PartyType: SplitPartyPlatform, Percent: 100},
+		{Scope: SplitScopeInstructor, ScopeID: "inst-1", Position: 1, PartyType: SplitPartyPlatform, Percent: 100},
+	})
+
+	if len(sets) != 3 {
+		t.Fatalf("GroupSplitRules returned %d sets, want 3", len(sets))
+	}
+	if sets[0].ScopeID != "course-1" || len(sets[0].Rules) != 2 {
+		t.Errorf("first set = %+v, want course-1 with 2 rules", sets[0])
+	}
+	if sets[2].Scope != SplitScopeInstructor || sets[2].ScopeID != "inst-1" {
+		t.Errorf("last set = %s/%s, want instructor/inst-1", sets[2].Scope, sets[2].ScopeID)
+	}
+	if len(GroupSplitRules(nil)) != 0 {
+		t.Error("GroupSplitRules(nil) should be empty")
+	}
+}
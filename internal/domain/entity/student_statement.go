@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/condotrack/api/pkg/money"
+)
+
+// Student statement entry types
+const (
+	StatementEntryCharge     = "charge"     // a payment or boleto charged to the student
+	StatementEntryPayment    = "payment"    // a charge the student paid
+	StatementEntryRefund     = "refund"     // money returned to the student
+	StatementEntryChargeback = "chargeback" // a payment disputed with the card issuer
+	StatementEntryCredit     = "credit"     // price difference owed to the student by a transfer to a cheaper course
+)
+
+// StudentStatement is the financial history of a student across enrollments: what was
+// charged, paid, refunded and credited, the charges still open and the totals
+type StudentStatement struct {
+	StudentID    string                `json:"student_id"`
+	StudentName  string                `json:"student_name"`
+	StudentEmail string                `json:"student_email"`
+	Enrollments  []StatementEnrollment `json:"enrollments"`
+	Entries      []StatementEntry      `json:"entries"`
+	OpenCharges  []StatementCharge     `json:"open_charges"`
+	Totals       StatementTotals       `json:"totals"`
+}
+
+// StatementEnrollment is an enrollment of the student as shown on the statement
+type StatementEnrollment struct {
+	ID            string    `json:"id"`
+	CourseID      string    `json:"course_id"`
+	CourseName    string    `json:"course_name"`
+	Status        string    `json:"status"`
+	PaymentStatus string    `json:"payment_status"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// StatementEntry is a movement of the statement. Amount is always positive, Type tells
+// which way the money went.
+type StatementEntry struct {
+	Date         time.Time   `json:"date"`
+	Type         string      `json:"type"`
+	EnrollmentID string      `json:"enrollment_id"`
+	PaymentID    *string     `json:"payment_id,omitempty"`
+	Description  string      `json:"description"`
+	Amount       money.Cents `json:"amount"`
+	Status       *string     `json:"status,omitempty"`
+}
+
+// StatementCharge is a charge still awaiting payment, with what the student needs to pay it
+type StatementCharge struct {
+	PaymentID         string      `json:"payment_id"`
+	EnrollmentID      string      `json:"enrollment_id"`
+	CourseName        string      `json:"course_name"`
+	PaymentMethod     string      `json:"payment_method"`
+	InstallmentNumber *int        `json:"installment_number,omitempty"`
+	InstallmentCount  int         `json:"installment_count,omitempty"`
+	Amount            money.Cents `json:"amount"`
+	Status            string      `json:"status"`
+	DueDate           *time.Time  `json:"due_date,omitempty"`
+	Overdue           bool        `json:"overdue"`
+	InvoiceURL        *string     `json:"invoice_url,omitempty"`
+}
+
+// StatementTotals sums the statement. Balance is what the student still owes: the open
+// charges less the credits, negative when the school owes the student.
+type StatementTotals struct {
+	Charged  money.Cents `json:"charged"`
+	Paid     money.Cents `json:"paid"`
+	Refunded money.Cents `json:"refunded"`
+	Open     money.Cents `json:"open"`
+	Overdue  money.Cents `json:"overdue"`
+	Credits  money.Cents `json:"credits"`
+	Balance  money.Cents `json:"balance"`
+}
@@ -15,6 +15,9 @@ type Supplier struct {
 	Notes     *string    `db:"notes" json:"notes,omitempty"`
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	// Scores holds the averaged evaluation scores (not a column)
+	Scores *SupplierScores `db:"-" json:"scores,omitempty"`
 }
 
 // CreateSupplierRequest represents the request to create a supplier
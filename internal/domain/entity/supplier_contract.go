@@ -0,0 +1,108 @@
+package entity
+
+import "time"
+
+// SupplierContract links a supplier to a contract for a service category
+type SupplierContract struct {
+	ID              string     `db:"id" json:"id"`
+	SupplierID      string     `db:"supplier_id" json:"supplier_id"`
+	ContratoID      string     `db:"contrato_id" json:"contrato_id"`
+	ServiceCategory string     `db:"service_category" json:"service_category"`
+	MonthlyBudget   *float64   `db:"monthly_budget" json:"monthly_budget,omitempty"`
+	IsActive        bool       `db:"is_active" json:"is_active"`
+	Notes           *string    `db:"notes" json:"notes,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	// Joined fields
+	SupplierName string `db:"supplier_name" json:"supplier_name,omitempty"`
+	ContratoNome string `db:"contrato_nome" json:"contrato_nome,omitempty"`
+}
+
+// CreateSupplierContractRequest represents the request to link a supplier to a contract
+type CreateSupplierContractRequest struct {
+	ContratoID      string   `json:"contrato_id" binding:"required"`
+	ServiceCategory string   `json:"service_category" binding:"required"`
+	MonthlyBudget   *float64 `json:"monthly_budget" binding:"omitempty,gte=0"`
+	Notes           *string  `json:"notes"`
+}
+
+// UpdateSupplierContractRequest represents the request to update a supplier-contract link
+type UpdateSupplierContractRequest struct {
+	ServiceCategory *string  `json:"service_category"`
+	MonthlyBudget   *float64 `json:"monthly_budget" binding:"omitempty,gte=0"`
+	IsActive        *bool    `json:"is_active"`
+	Notes           *string  `json:"notes"`
+}
+
+// SupplierSpend represents a monthly spend entry on a supplier-contract link
+type SupplierSpend struct {
+	ID                 string    `db:"id" json:"id"`
+	SupplierContractID string    `db:"supplier_contract_id" json:"supplier_contract_id"`
+	SupplierID         string    `db:"supplier_id" json:"supplier_id"`
+	ContratoID         string    `db:"contrato_id" json:"contrato_id"`
+	Period             string    `db:"period" json:"period"` // YYYY-MM
+	Amount             float64   `db:"amount" json:"amount"`
+	Description        *string   `db:"description" json:"description,omitempty"`
+	InvoiceNumber      *string   `db:"invoice_number" json:"invoice_number,omitempty"`
+	CreatedBy          *string   `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+}
+
+// CreateSupplierSpendRequest represents the request to record a spend entry
+type CreateSupplierSpendRequest struct {
+	Period        string  `json:"period" binding:"required"`
+	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	Description   *string `json:"description"`
+	InvoiceNumber *string `json:"invoice_number"`
+}
+
+// SpendReportFilters holds the filters of the spend reports
+type SpendReportFilters struct {
+	From       string // YYYY-MM, inclusive
+	To         string // YYYY-MM, inclusive
+	SupplierID string
+	ContratoID string
+}
+
+// SpendAggregate is a spend total for a group (contract or supplier) in a period
+type SpendAggregate struct {
+	GroupID     string  `db:"group_id"`
+	GroupName   string  `db:"group_name"`
+	Period      string  `db:"period"`
+	TotalAmount float64 `db:"total_amount"`
+	EntryCount  int     `db:"entry_count"`
+}
+
+// SpendReportMonth is the spend of a group in one month compared with its budget
+type SpendReportMonth struct {
+	Period   string   `json:"period"`
+	Amount   float64  `json:"amount"`
+	Budget   *float64 `json:"budget,omitempty"`
+	Variance *float64 `json:"variance,omitempty"`
+}
+
+// SpendReportItem is the spend of a contract or supplier over the report range
+type SpendReportItem struct {
+	ID            string             `json:"id"`
+	Name          string             `json:"name"`
+	TotalAmount   float64            `json:"total_amount"`
+	EntryCount    int                `json:"entry_count"`
+	MonthlyBudget *float64           `json:"monthly_budget,omitempty"`
+	Months        []SpendReportMonth `json:"months"`
+}
+
+// SpendReport represents a spend-per-contract or spend-per-supplier report
+type SpendReport struct {
+	GroupBy     string            `json:"group_by"`
+	From        string            `json:"from,omitempty"`
+	To          string            `json:"to,omitempty"`
+	TotalAmount float64           `json:"total_amount"`
+	Items       []SpendReportItem `json:"items"`
+}
+
+// Spend report grouping constants
+const (
+	SpendGroupByContract = "contract"
+	SpendGroupBySupplier = "supplier"
+)
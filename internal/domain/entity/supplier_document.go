@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// Supplier document type constants
+const (
+	SupplierDocumentTypeLicense       = "license"
+	SupplierDocumentTypeInsurance     = "insurance"
+	SupplierDocumentTypeTaxClearance  = "tax_clearance"
+	SupplierDocumentTypeCertification = "certification"
+	SupplierDocumentTypeOther         = "other"
+)
+
+// SupplierDocument is a compliance document of a supplier, such as an operating license, an
+// insurance policy, a tax clearance certificate or a technical certification (NR-10, NR-35)
+type SupplierDocument struct {
+	ID           string     `db:"id" json:"id"`
+	SupplierID   string     `db:"supplier_id" json:"supplier_id"`
+	DocumentType string     `db:"document_type" json:"document_type"`
+	Title        string     `db:"title" json:"title"`
+	Number       *string    `db:"number" json:"number,omitempty"`
+	IssuedAt     *time.Time `db:"issued_at" json:"issued_at,omitempty"`
+	ExpiresAt    *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+	Notes        *string    `db:"notes" json:"notes,omitempty"`
+	IsActive     bool       `db:"is_active" json:"is_active"`
+	CreatedBy    *string    `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// CreateSupplierDocumentRequest represents the request to register a supplier document
+type CreateSupplierDocumentRequest struct {
+	DocumentType string  `json:"document_type" binding:"required"`
+	Title        string  `json:"title" binding:"required"`
+	Number       *string `json:"number"`
+	IssuedAt     *string `json:"issued_at"`  // YYYY-MM-DD
+	ExpiresAt    *string `json:"expires_at"` // YYYY-MM-DD
+	Notes        *string `json:"notes"`
+}
+
+// UpdateSupplierDocumentRequest represents the request to update a supplier document. An
+// empty expires_at clears the expiry.
+type UpdateSupplierDocumentRequest struct {
+	Title     *string `json:"title"`
+	Number    *string `json:"number"`
+	IssuedAt  *string `json:"issued_at"`
+	ExpiresAt *string `json:"expires_at"`
+	Notes     *string `json:"notes"`
+	IsActive  *bool   `json:"is_active"`
+}
+
+// IsValidSupplierDocumentType checks if the document type is supported
+func IsValidSupplierDocumentType(t string) bool {
+	switch t {
+	case SupplierDocumentTypeLicense, SupplierDocumentTypeInsurance, SupplierDocumentTypeTaxClearance,
+		SupplierDocumentTypeCertification, SupplierDocumentTypeOther:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Supplier evaluation score bounds
+const (
+	SupplierScoreMin = 1
+	SupplierScoreMax = 5
+)
+
+// SupplierEvaluation represents a periodic evaluation of a supplier on a contract
+type SupplierEvaluation struct {
+	ID               string     `db:"id" json:"id"`
+	SupplierID       string     `db:"supplier_id" json:"supplier_id"`
+	ContratoID       string     `db:"contrato_id" json:"contrato_id"`
+	Period           string     `db:"period" json:"period"` // YYYY-MM
+	QualityScore     int        `db:"quality_score" json:"quality_score"`
+	PunctualityScore int        `db:"punctuality_score" json:"punctuality_score"`
+	PriceScore       int        `db:"price_score" json:"price_score"`
+	OverallScore     float64    `db:"overall_score" json:"overall_score"`
+	Comments         *string    `db:"comments" json:"comments,omitempty"`
+	EvaluatedBy      *string    `db:"evaluated_by" json:"evaluated_by,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// CreateSupplierEvaluationRequest represents the request to evaluate a supplier
+type CreateSupplierEvaluationRequest struct {
+	ContratoID       string  `json:"contrato_id" binding:"required"`
+	Period           string  `json:"period" binding:"required"`
+	QualityScore     int     `json:"quality_score" binding:"required,min=1,max=5"`
+	PunctualityScore int     `json:"punctuality_score" binding:"required,min=1,max=5"`
+	PriceScore       int     `json:"price_score" binding:"required,min=1,max=5"`
+	Comments         *string `json:"comments"`
+}
+
+// SupplierScores holds averaged evaluation scores of a supplier
+type SupplierScores struct {
+	EvaluationCount  int     `db:"evaluation_count" json:"evaluation_count"`
+	QualityScore     float64 `db:"quality_score" json:"quality_score"`
+	PunctualityScore float64 `db:"punctuality_score" json:"punctuality_score"`
+	PriceScore       float64 `db:"price_score" json:"price_score"`
+	OverallScore     float64 `db:"overall_score" json:"overall_score"`
+}
+
+// SupplierRanking represents a supplier position in the ranking report
+type SupplierRanking struct {
+	Position     int     `db:"-" json:"position"`
+	SupplierID   string  `db:"supplier_id" json:"supplier_id"`
+	SupplierName string  `db:"supplier_name" json:"supplier_name"`
+	Category     *string `db:"category" json:"category,omitempty"`
+	SupplierScores
+}
+
+// SupplierRankingFilters holds the filters of the ranking report
+type SupplierRankingFilters struct {
+	Category       string
+	ContratoID     string
+	From           string // YYYY-MM, inclusive
+	To             string // YYYY-MM, inclusive
+	MinEvaluations int
+	Limit          int
+}
+
+// CalculateOverallScore returns the average of the three criteria rounded to two decimals
+func CalculateOverallScore(quality, punctuality, price int) float64 {
+	avg := float64(quality+punctuality+price) / 3
+	return math.Round(avg*100) / 100
+}
+
+// IsValidEvaluationPeriod reports whether period is a YYYY-MM month
+func IsValidEvaluationPeriod(period string) bool {
+	_, err := time.Parse("2006-01", period)
+	return err == nil
+}
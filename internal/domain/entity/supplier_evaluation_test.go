@@ -0,0 +1,39 @@
+package entity
+
+import "testing"
+
+func TestCalculateOverallScore(t *testing.T) {
+	tests := []struct {
+		quality, punctuality, price int
+		want                        float64
+	}{
+		{5, 5, 5, 5},
+		{4, 3, 5, 4},
+		{5, 4, 4, 4.33},
+		{1, 2, 2, 1.67},
+	}
+
+	for _, tt := range tests {
+		got := CalculateOverallScore(tt.quality, tt.punctuality, tt.price)
+		if got != tt.want {
+			t.Errorf("CalculateOverallScore(%d, %d, %d) = %.2f, want %.2f",
+				tt.quality, tt.punctuality, tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidEvaluationPeriod(t *testing.T) {
+	valid := []string{"2024-01", "2025-12"}
+	invalid := []string{"", "2024", "2024-13", "01-2024", "2024-1", "2024-01-15"}
+
+	for _, p := range valid {
+		if !IsValidEvaluationPeriod(p) {
+			t.Errorf("expected %q to be valid", p)
+		}
+	}
+	for _, p := range invalid {
+		if IsValidEvaluationPeriod(p) {
+			t.Errorf("expected %q to be invalid", p)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SystemImage is a slot for a branded portal asset (logo, banners, step illustrations). The
+// portal refers to the slot ID; uploads with that ID overwrite the file of the slot.
+type SystemImage struct {
+	ID          string     `db:"id" json:"id"`
+	Filename    string     `db:"filename" json:"filename"`
+	Description *string    `db:"description" json:"description,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+}
+
+// CreateSystemImageRequest represents the request to create a system image slot
+type CreateSystemImageRequest struct {
+	ID          string  `json:"id" binding:"required"`
+	Filename    string  `json:"filename" binding:"required"`
+	Description *string `json:"description" binding:"omitempty,max=255"`
+}
+
+// UpdateSystemImageRequest represents the request to update a system image slot; omitted
+// fields are left unchanged
+type UpdateSystemImageRequest struct {
+	Filename    *string `json:"filename"`
+	Description *string `json:"description" binding:"omitempty,max=255"`
+}
+
+var (
+	systemImageIDPattern       = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+	systemImageFilenamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,199}\.(png|jpe?g|gif|webp|svg)$`)
+)
+
+// ValidSystemImageID reports whether id is a lowercase slot ID, e.g. "sys_logo" or "pos_1"
+func ValidSystemImageID(id string) bool {
+	return systemImageIDPattern.MatchString(id)
+}
+
+// ValidSystemImageFilename reports whether filename can hold a system image: a plain image
+// name without directories, not in the "upload_" namespace of user uploads
+func ValidSystemImageFilename(filename string) bool {
+	return systemImageFilenamePattern.MatchString(filename) &&
+		!strings.Contains(filename, "..") &&
+		!strings.HasPrefix(filename, "upload_")
+}
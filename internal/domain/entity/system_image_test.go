@@ -0,0 +1,29 @@
+package entity
+
+import "testing"
+
+func TestValidSystemImageID(t *testing.T) {
+	for _, id := range []string{"sys_logo", "pos_1", "banner-natal-2024"} {
+		if !ValidSystemImageID(id) {
+			t.Errorf("%q should be valid", id)
+		}
+	}
+	for _, id := range []string{"", "Sys_Logo", "_logo", "logo principal", "a/b"} {
+		if ValidSystemImageID(id) {
+			t.Errorf("%q should be invalid", id)
+		}
+	}
+}
+
+func TestValidSystemImageFilename(t *testing.T) {
+	for _, name := range []string{"logo-condotrack.png", "padlet-cover-v2.png", "banner_natal.jpeg", "icon.svg"} {
+		if !ValidSystemImageFilename(name) {
+			t.Errorf("%q should be valid", name)
+		}
+	}
+	for _, name := range []string{"", "logo", "logo.pdf", "../logo.png", "img/logo.png", "logo..png", "upload_1_abc.png", ".png"} {
+		if ValidSystemImageFilename(name) {
+			t.Errorf("%q should be invalid", name)
+		}
+	}
+}
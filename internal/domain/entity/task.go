@@ -1,6 +1,11 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/listquery"
+)
 
 // TaskStatus constants define the possible states of a task
 const (
@@ -20,21 +25,23 @@ const (
 
 // Task represents a task entity
 type Task struct {
-	ID             string     `db:"id" json:"id"`
-	Title          string     `db:"title" json:"title"`
-	Description    *string    `db:"description" json:"description,omitempty"`
-	Status         string     `db:"status" json:"status"`
-	Priority       string     `db:"priority" json:"priority"`
-	DueDate        *time.Time `db:"due_date" json:"due_date,omitempty"`
-	ContractID     *string    `db:"contract_id" json:"contract_id,omitempty"`
-	ContractName   *string    `db:"contract_name" json:"contract_name,omitempty"`
-	AssignedTo     *string    `db:"assigned_to" json:"assigned_to,omitempty"`
-	AssignedToName *string    `db:"assigned_to_name" json:"assigned_to_name,omitempty"`
-	CreatedBy      string     `db:"created_by" json:"created_by"`
-	CreatedByName  *string    `db:"created_by_name" json:"created_by_name,omitempty"`
-	CompletedAt    *time.Time `db:"completed_at" json:"completed_at,omitempty"`
-	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
-	UpdatedAt      *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+	ID              string     `db:"id" json:"id"`
+	Title           string     `db:"title" json:"title"`
+	Description     *string    `db:"description" json:"description,omitempty"`
+	Status          string     `db:"status" json:"status"`
+	Priority        string     `db:"priority" json:"priority"`
+	DueDate         *time.Time `db:"due_date" json:"due_date,omitempty"`
+	ContractID      *string    `db:"contract_id" json:"contract_id,omitempty"`
+	ContractName    *string    `db:"contract_name" json:"contract_name,omitempty"`
+	AssignedTo      *string    `db:"assigned_to" json:"assigned_to,omitempty"`
+	AssignedToName  *string    `db:"assigned_to_name" json:"assigned_to_name,omitempty"`
+	CreatedBy       string     `db:"created_by" json:"created_by"`
+	CreatedByName   *string    `db:"created_by_name" json:"created_by_name,omitempty"`
+	CompletedAt     *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+	EscalationLevel int        `db:"escalation_level" json:"escalation_level"` // see TaskEscalationGestor and TaskEscalationAdmin
+	EscalatedAt     *time.Time `db:"escalated_at" json:"escalated_at,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       *time.Time `db:"updated_at" json:"updated_at,omitempty"`
 }
 
 // TaskWithDetails represents a task with related entity names
@@ -77,6 +84,7 @@ type TaskFilter struct {
 	Status     *string
 	Priority   *string
 	CreatedBy  *string
+	Query      listquery.Query // filter[...] and sort parameters
 }
 
 // ValidTaskStatus checks if the given status is valid
@@ -96,3 +104,74 @@ func ValidTaskPriority(priority string) bool {
 	}
 	return false
 }
+
+// NormalizeTaskPriority maps a priority to one of the task priorities, defaulting to medium
+func NormalizeTaskPriority(priority string) string {
+	priority = strings.ToLower(strings.TrimSpace(priority))
+	if ValidTaskPriority(priority) {
+		return priority
+	}
+	return TaskPriorityMedium
+}
+
+// Task suggestion sources
+const (
+	TaskSourceAudit      = "audit"
+	TaskSourceInspection = "inspection"
+)
+
+// TaskSuggestion is a task proposed by the AI for a finding. AssigneeRole is the contract team
+// role expected to carry it out.
+type TaskSuggestion struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Priority     string `json:"priority"`
+	AssigneeRole string `json:"assignee_role"`
+	DueInDays    int    `json:"due_in_days,omitempty"`
+}
+
+// Normalize trims the suggestion and maps the priority, role and deadline to accepted values.
+// It reports false when the suggestion has no title.
+func (s *TaskSuggestion) Normalize() bool {
+	s.Title = strings.TrimSpace(s.Title)
+	if len(s.Title) > 255 {
+		s.Title = s.Title[:255]
+	}
+	s.Description = strings.TrimSpace(s.Description)
+	s.Priority = NormalizeTaskPriority(s.Priority)
+	s.AssigneeRole = strings.ToLower(strings.TrimSpace(s.AssigneeRole))
+	if s.AssigneeRole != TeamRoleLeader && s.AssigneeRole != TeamRoleAuditor && s.AssigneeRole != TeamRoleInspector {
+		s.AssigneeRole = TeamRoleLeader
+	}
+	if s.DueInDays < 0 || s.DueInDays > 365 {
+		s.DueInDays = 0
+	}
+	return s.Title != ""
+}
+
+// TaskSuggestions are the tasks proposed for the findings of an audit or inspection. They are
+// not stored: the gestor picks the ones to keep and creates them with a bulk request.
+type TaskSuggestions struct {
+	Source     string           `json:"source"`
+	SourceID   string           `json:"source_id"`
+	ContractID string           `json:"contract_id"`
+	Tasks      []TaskSuggestion `json:"tasks"`
+	Provider   string           `json:"provider"`
+	Model      string           `json:"model"`
+}
+
+// BulkTaskInput is one task of a bulk creation request
+type BulkTaskInput struct {
+	Title       string     `json:"title" binding:"required,max=255"`
+	Description *string    `json:"description,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	AssignedTo  *string    `json:"assigned_to,omitempty"`
+}
+
+// BulkCreateTaskRequest creates several tasks of a contract at once, e.g. accepted suggestions
+type BulkCreateTaskRequest struct {
+	ContractID *string         `json:"contract_id,omitempty"`
+	CreatedBy  string          `json:"created_by" binding:"required"`
+	Tasks      []BulkTaskInput `json:"tasks" binding:"required,min=1,max=50,dive"`
+}
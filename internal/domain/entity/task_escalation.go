@@ -0,0 +1,88 @@
+package entity
+
+import "time"
+
+// Task escalation levels. A task records the highest level it reached.
+const (
+	TaskEscalationNone   = 0
+	TaskEscalationGestor = 1 // the contract gestor was notified
+	TaskEscalationAdmin  = 2 // the admins were notified
+)
+
+// TaskEscalationPolicy holds how long a task may stay overdue before each escalation level;
+// a zero threshold disables its level
+type TaskEscalationPolicy struct {
+	GestorAfter time.Duration
+	AdminAfter  time.Duration
+}
+
+// Enabled reports whether any escalation level is configured
+func (p TaskEscalationPolicy) Enabled() bool {
+	return p.GestorAfter > 0 || p.AdminAfter > 0
+}
+
+// DueLevel returns the level a task overdue for the given time should be escalated to, or
+// TaskEscalationNone when it has already reached it
+func (p TaskEscalationPolicy) DueLevel(overdue time.Duration, current int) int {
+	level := TaskEscalationNone
+	switch {
+	case p.AdminAfter > 0 && overdue >= p.AdminAfter:
+		level = TaskEscalationAdmin
+	case p.GestorAfter > 0 && overdue >= p.GestorAfter:
+		level = TaskEscalationGestor
+	}
+	if level <= current {
+		return TaskEscalationNone
+	}
+	return level
+}
+
+// OverdueTask is an open task past its due date that has not reached the last escalation
+// level, with the gestor of its contract
+type OverdueTask struct {
+	TaskID          string    `db:"task_id"`
+	Title           string    `db:"title"`
+	Priority        string    `db:"priority"`
+	DueDate         time.Time `db:"due_date"`
+	ContractID      *string   `db:"contract_id"`
+	ContractName    *string   `db:"contract_name"`
+	GestorID        *string   `db:"gestor_id"`
+	EscalationLevel int       `db:"escalation_level"`
+}
+
+// TaskEscalation is an escalation sent for an overdue task
+type TaskEscalation struct {
+	ID           string    `db:"id" json:"id"`
+	TaskID       string    `db:"task_id" json:"task_id"`
+	ContractID   *string   `db:"contract_id" json:"contract_id,omitempty"`
+	Level        int       `db:"level" json:"level"`
+	OverdueHours int       `db:"overdue_hours" json:"overdue_hours"`
+	Recipients   int       `db:"recipients" json:"recipients"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// TaskEscalationResult summarizes an escalation sweep
+type TaskEscalationResult struct {
+	Checked       int `json:"checked"`
+	Escalated     int `json:"escalated"`
+	Notifications int `json:"notifications"`
+}
+
+// TaskEscalationReport counts the escalations of the tasks of a contract; tasks without a
+// contract are grouped with a nil contract
+type TaskEscalationReport struct {
+	ContractID        *string    `db:"contract_id" json:"contract_id"`
+	ContractName      *string    `db:"contract_name" json:"contract_name,omitempty"`
+	Tasks             int        `db:"tasks" json:"tasks"`
+	GestorEscalations int        `db:"gestor_escalations" json:"gestor_escalations"`
+	AdminEscalations  int        `db:"admin_escalations" json:"admin_escalations"`
+	StillOpen         int        `db:"still_open" json:"still_open"` // escalated tasks not completed or cancelled yet
+	LastEscalatedAt   *time.Time `db:"last_escalated_at" json:"last_escalated_at,omitempty"`
+}
+
+// TaskEscalationReportFilters holds the filter parameters of the escalation report
+type TaskEscalationReportFilters struct {
+	ContractID string
+	From       *time.Time // escalation date, inclusive
+	To         *time.Time // escalation date, exclusive
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestValidTaskStatus_Valid(t *testing.T) {
 	validStatuses := []string{"pending", "in_progress", "completed", "cancelled"}
@@ -37,3 +40,56 @@ func TestValidTaskPriority_Invalid(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeTaskPriority(t *testing.T) {
+	cases := map[string]string{"high": "high", " URGENT ": "urgent", "alta": "medium", "": "medium"}
+	for in, want := range cases {
+		if got := NormalizeTaskPriority(in); got != want {
+			t.Errorf("NormalizeTaskPriority(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTaskSuggestionNormalize(t *testing.T) {
+	s := TaskSuggestion{Title: "  Trocar lâmpadas da garagem ", Priority: "HIGH", AssigneeRole: "zelador", DueInDays: -3}
+	if !s.Normalize() {
+		t.Fatal("Normalize() = false, want true")
+	}
+	if s.Title != "Trocar lâmpadas da garagem" || s.Priority != "high" || s.AssigneeRole != TeamRoleLeader || s.DueInDays != 0 {
+		t.Errorf("unexpected normalized suggestion: %+v", s)
+	}
+
+	empty := TaskSuggestion{Title: "  "}
+	if empty.Normalize() {
+		t.Error("Normalize() without title = true, want false")
+	}
+}
+
+func TestTaskEscalationPolicyDueLevel(t *testing.T) {
+	policy := TaskEscalationPolicy{GestorAfter: 24 * time.Hour, AdminAfter: 72 * time.Hour}
+	tests := []struct {
+		overdue time.Duration
+		current int
+		want    int
+	}{
+		{time.Hour, TaskEscalationNone, TaskEscalationNone},
+		{24 * time.Hour, TaskEscalationNone, TaskEscalationGestor},
+		{30 * time.Hour, TaskEscalationGestor, TaskEscalationNone},
+		{72 * time.Hour, TaskEscalationGestor, TaskEscalationAdmin},
+		{100 * time.Hour, TaskEscalationNone, TaskEscalationAdmin},
+		{100 * time.Hour, TaskEscalationAdmin, TaskEscalationNone},
+	}
+	for _, tt := range tests {
+		if got := policy.DueLevel(tt.overdue, tt.current); got != tt.want {
+			t.Errorf("DueLevel(%v, %d) = %d, want %d", tt.overdue, tt.current, got, tt.want)
+		}
+	}
+
+	adminOnly := TaskEscalationPolicy{AdminAfter: 48 * time.Hour}
+	if got := adminOnly.DueLevel(30*time.Hour, TaskEscalationNone); got != TaskEscalationNone {
+		t.Errorf("gestor level disabled: DueLevel = %d, want none", got)
+	}
+	if (TaskEscalationPolicy{}).Enabled() {
+		t.Error("zero policy: Enabled() = true")
+	}
+}
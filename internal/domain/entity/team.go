@@ -2,6 +2,45 @@ package entity
 
 import "time"
 
+// Team role constants: the role a member holds on one contract assignment
+const (
+	TeamRoleLeader    = "leader"
+	TeamRoleAuditor   = "auditor"
+	TeamRoleInspector = "inspector"
+	TeamRoleViewer    = "viewer"
+)
+
+// Team action constants checked against a member's contract role
+const (
+	TeamActionView              = "view"
+	TeamActionManageAudits      = "manage_audits"
+	TeamActionManageInspections = "manage_inspections"
+	TeamActionManageTasks       = "manage_tasks"
+	TeamActionUpdateTaskStatus  = "update_task_status"
+	TeamActionManagePurchases   = "manage_purchases"
+)
+
+// teamRolePermissions lists the actions each contract role may perform
+var teamRolePermissions = map[string][]string{
+	TeamRoleLeader: {
+		TeamActionView, TeamActionManageAudits, TeamActionManageInspections,
+		TeamActionManageTasks, TeamActionUpdateTaskStatus, TeamActionManagePurchases,
+	},
+	TeamRoleAuditor:   {TeamActionView, TeamActionManageAudits, TeamActionUpdateTaskStatus},
+	TeamRoleInspector: {TeamActionView, TeamActionManageInspections, TeamActionUpdateTaskStatus},
+	TeamRoleViewer:    {TeamActionView},
+}
+
+// TeamRoleAllows reports whether a contract team role may perform the action
+func TeamRoleAllows(role, action string) bool {
+	for _, allowed := range teamRolePermissions[role] {
+		if allowed == action {
+			return true
+		}
+	}
+	return false
+}
+
 // TeamMember represents a team member assignment to a contract
 type TeamMember struct {
 	ID           string     `db:"id" json:"id"`
@@ -19,6 +58,17 @@ type TeamMember struct {
 	UpdatedAt    *time.Time `db:"updated_at" json:"updated_at,omitempty"`
 }
 
+// IsActiveOn reports whether the assignment is active and within its start and end dates at t
+func (m *TeamMember) IsActiveOn(t time.Time) bool {
+	if !m.IsActive {
+		return false
+	}
+	if m.StartDate != nil && t.Before(*m.StartDate) {
+		return false
+	}
+	return m.EndDate == nil || !t.After(*m.EndDate)
+}
+
 // TeamMemberDB represents the database model for team_members table
 type TeamMemberDB struct {
 	ID         string     `db:"id"`
@@ -36,14 +86,14 @@ type TeamMemberDB struct {
 type CreateTeamMemberRequest struct {
 	UserID     string     `json:"user_id" binding:"required"`
 	ContractID string     `json:"contract_id" binding:"required"`
-	Role       string     `json:"role" binding:"required"`
+	Role       string     `json:"role" binding:"required,oneof=leader auditor inspector viewer"`
 	StartDate  *time.Time `json:"start_date,omitempty"`
 	EndDate    *time.Time `json:"end_date,omitempty"`
 }
 
 // UpdateTeamMemberRequest represents the request to update a team member assignment
 type UpdateTeamMemberRequest struct {
-	Role      *string    `json:"role,omitempty"`
+	Role      *string    `json:"role,omitempty" binding:"omitempty,oneof=leader auditor inspector viewer"`
 	StartDate *time.Time `json:"start_date,omitempty"`
 	EndDate   *time.Time `json:"end_date,omitempty"`
 	IsActive  *bool      `json:"is_active,omitempty"`
@@ -55,3 +105,27 @@ type TeamMemberFilter struct {
 	UserID     *string
 	IsActive   *bool
 }
+
+// TeamWorkload summarizes the open work and commitments of a team member over a date range
+type TeamWorkload struct {
+	UserID               string   `db:"user_id" json:"user_id"`
+	UserName             string   `db:"user_name" json:"user_name"`
+	ActiveContracts      int      `db:"active_contracts" json:"active_contracts"`
+	OpenTasks            int      `db:"open_tasks" json:"open_tasks"` // due by the end of the range or undated
+	OverdueTasks         int      `db:"overdue_tasks" json:"overdue_tasks"`
+	HighPriorityTasks    int      `db:"high_priority_tasks" json:"high_priority_tasks"`
+	ScheduledInspections int      `db:"scheduled_inspections" json:"scheduled_inspections"`
+	AgendaEvents         int      `db:"agenda_events" json:"agenda_events"`
+	AgendaMinutes        int      `db:"agenda_minutes" json:"agenda_minutes"`
+	ShiftMinutes         int      `db:"-" json:"shift_minutes"`
+	TotalItems           int      `db:"-" json:"total_items"`
+	AgendaUtilization    *float64 `db:"-" json:"agenda_utilization,omitempty"` // agenda minutes as a percentage of shift minutes
+}
+
+// TeamWorkloadFilter represents filter options for the workload report
+type TeamWorkloadFilter struct {
+	ContractID *string
+	From       time.Time
+	To         time.Time // exclusive
+	Now        time.Time
+}
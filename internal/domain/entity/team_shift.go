@@ -0,0 +1,232 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// TeamShift is a weekly recurring shift a team member covers on a contract
+type TeamShift struct {
+	ID             string     `db:"id" json:"id"`
+	TeamMemberID   string     `db:"team_member_id" json:"team_member_id"`
+	UserID         string     `db:"user_id" json:"user_id"`
+	UserName       string     `db:"user_name" json:"user_name"`
+	ContractID     string     `db:"contract_id" json:"contract_id"`
+	ContractName   string     `db:"contract_name" json:"contract_name"`
+	Weekday        int        `db:"weekday" json:"weekday"`       // 0 = Sunday ... 6 = Saturday
+	StartTime      string     `db:"start_time" json:"start_time"` // HH:MM
+	EndTime        string     `db:"end_time" json:"end_time"`     // HH:MM
+	EffectiveFrom  *time.Time `db:"effective_from" json:"effective_from,omitempty"`
+	EffectiveUntil *time.Time `db:"effective_until" json:"effective_until,omitempty"`
+	Notes          *string    `db:"notes" json:"notes,omitempty"`
+	IsActive       bool       `db:"is_active" json:"is_active"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      *time.Time `db:"updated_at" json:"updated_at,omitempty"`
+
+	Conflicts []TeamShiftConflict `db:"-" json:"conflicts,omitempty"`
+}
+
+// TeamShiftDB represents the database model for team_shifts table
+type TeamShiftDB struct {
+	ID             string     `db:"id"`
+	TeamMemberID   string     `db:"team_member_id"`
+	UserID         string     `db:"user_id"`
+	ContractID     string     `db:"contract_id"`
+	Weekday        int        `db:"weekday"`
+	StartTime      string     `db:"start_time"`
+	EndTime        string     `db:"end_time"`
+	EffectiveFrom  *time.Time `db:"effective_from"`
+	EffectiveUntil *time.Time `db:"effective_until"`
+	Notes          *string    `db:"notes"`
+	IsActive       bool       `db:"is_active"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// CreateTeamShiftRequest represents the request to schedule a shift for a team member
+type CreateTeamShiftRequest struct {
+	TeamMemberID   string     `json:"team_member_id" binding:"required"`
+	Weekday        *int       `json:"weekday" binding:"required,min=0,max=6"`
+	StartTime      string     `json:"start_time" binding:"required"`
+	EndTime        string     `json:"end_time" binding:"required"`
+	EffectiveFrom  *time.Time `json:"effective_from,omitempty"`
+	EffectiveUntil *time.Time `json:"effective_until,omitempty"`
+	Notes          *string    `json:"notes,omitempty"`
+}
+
+// UpdateTeamShiftRequest represents the request to update a shift
+type UpdateTeamShiftRequest struct {
+	Weekday        *int       `json:"weekday,omitempty" binding:"omitempty,min=0,max=6"`
+	StartTime      *string    `json:"start_time,omitempty"`
+	EndTime        *string    `json:"end_time,omitempty"`
+	EffectiveFrom  *time.Time `json:"effective_from,omitempty"`
+	EffectiveUntil *time.Time `json:"effective_until,omitempty"`
+	Notes          *string    `json:"notes,omitempty"`
+	IsActive       *bool      `json:"is_active,omitempty"`
+}
+
+// TeamShiftFilter represents filter options for listing shifts
+type TeamShiftFilter struct {
+	ContractID *string
+	UserID     *string
+	IsActive   *bool
+}
+
+// TeamShiftConflict is an agenda event of the shift's member that overlaps one occurrence of the shift
+// and belongs to another contract (or to no contract at all)
+type TeamShiftConflict struct {
+	ShiftID           string    `json:"shift_id"`
+	UserID            string    `json:"user_id"`
+	UserName          string    `json:"user_name"`
+	Date              string    `json:"date"` // YYYY-MM-DD
+	ShiftStart        string    `json:"shift_start"`
+	ShiftEnd          string    `json:"shift_end"`
+	EventID           string    `json:"event_id"`
+	EventTitle        string    `json:"event_title"`
+	EventType         EventType `json:"event_type"`
+	EventContractID   *string   `json:"event_contract_id,omitempty"`
+	EventContractName *string   `json:"event_contract_name,omitempty"`
+	EventStart        time.Time `json:"event_start"`
+	EventEnd          time.Time `json:"event_end"`
+}
+
+// TeamCoverageShift is one shift occurrence on a coverage report day
+type TeamCoverageShift struct {
+	ShiftID   string `json:"shift_id"`
+	UserID    string `json:"user_id"`
+	UserName  string `json:"user_name"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Conflicts int    `json:"conflicts"`
+}
+
+// TeamCoverageDay summarizes who covers a contract on a given day
+type TeamCoverageDay struct {
+	Date           string              `json:"date"`
+	Weekday        int                 `json:"weekday"`
+	CoveredMinutes int                 `json:"covered_minutes"` // union of the shift windows
+	FirstStart     *string             `json:"first_start,omitempty"`
+	LastEnd        *string             `json:"last_end,omitempty"`
+	Shifts         []TeamCoverageShift `json:"shifts"`
+}
+
+// TeamCoverageMember is the scheduled time of one member in a coverage report
+type TeamCoverageMember struct {
+	UserID           string  `json:"user_id"`
+	UserName         string  `json:"user_name"`
+	ScheduledMinutes int     `json:"scheduled_minutes"`
+	ScheduledHours   float64 `json:"scheduled_hours"`
+	Conflicts        int     `json:"conflicts"`
+}
+
+// TeamCoverageReport is the shift coverage of a contract over a date range
+type TeamCoverageReport struct {
+	ContractID    string               `json:"contract_id"`
+	ContractName  string               `json:"contract_name"`
+	From          string               `json:"from"`
+	To            string               `json:"to"`
+	CoveredHours  float64              `json:"covered_hours"`
+	UncoveredDays []string             `json:"uncovered_days"`
+	Days          []TeamCoverageDay    `json:"days"`
+	Members       []TeamCoverageMember `json:"members"`
+	Conflicts     []TeamShiftConflict  `json:"conflicts"`
+}
+
+// ParseShiftClock parses an HH:MM (or HH:MM:SS) time of day into minutes after midnight
+func ParseShiftClock(value string) (int, error) {
+	var h, m, s int
+	n, _ := fmt.Sscanf(value, "%d:%d:%d", &h, &m, &s)
+	if n < 2 || h < 0 || h > 23 || m < 0 || m > 59 || s < 0 || s > 59 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return h*60 + m, nil
+}
+
+// FormatShiftClock formats minutes after midnight as HH:MM
+func FormatShiftClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// OccursOn reports whether the shift is scheduled on the given date
+func (s *TeamShift) OccursOn(date time.Time) bool {
+	if !s.IsActive || int(date.Weekday()) != s.Weekday {
+		return false
+	}
+	day := truncateDay(date)
+	if s.EffectiveFrom != nil && day.Before(truncateDay(*s.EffectiveFrom)) {
+		return false
+	}
+	if s.EffectiveUntil != nil && day.After(truncateDay(*s.EffectiveUntil)) {
+		return false
+	}
+	return true
+}
+
+// Window returns the start and end of the shift on the given date, in the date's location
+func (s *TeamShift) Window(date time.Time) (time.Time, time.Time) {
+	start, _ := ParseShiftClock(s.StartTime)
+	end, _ := ParseShiftClock(s.EndTime)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return day.Add(time.Duration(start) * time.Minute), day.Add(time.Duration(end) * time.Minute)
+}
+
+// Overlaps reports whether two shifts share a weekday, overlapping hours and effective dates
+func (s *TeamShift) Overlaps(other *TeamShift) bool {
+	if s.Weekday != other.Weekday {
+		return false
+	}
+	aStart, _ := ParseShiftClock(s.StartTime)
+	aEnd, _ := ParseShiftClock(s.EndTime)
+	bStart, _ := ParseShiftClock(other.StartTime)
+	bEnd, _ := ParseShiftClock(other.EndTime)
+	if aStart >= bEnd || bStart >= aEnd {
+		return false
+	}
+	if s.EffectiveUntil != nil && other.EffectiveFrom != nil && truncateDay(*s.EffectiveUntil).Before(truncateDay(*other.EffectiveFrom)) {
+		return false
+	}
+	if other.EffectiveUntil != nil && s.EffectiveFrom != nil && truncateDay(*other.EffectiveUntil).Before(truncateDay(*s.EffectiveFrom)) {
+		return false
+	}
+	return true
+}
+
+// ShiftEventConflicts lists the events that overlap occurrences of the shift between from (inclusive)
+// and to (exclusive). Events of the shift's own contract are part of the job and never conflict.
+func ShiftEventConflicts(shift *TeamShift, events []AgendaEvent, from, to time.Time) []TeamShiftConflict {
+	var conflicts []TeamShiftConflict
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !shift.OccursOn(day) {
+			continue
+		}
+		start, end := shift.Window(day)
+		for _, e := range events {
+			if e.ContractID != nil && *e.ContractID == shift.ContractID {
+				continue
+			}
+			eventStart, eventEnd := e.StartDatetime, e.EndDatetime
+			if e.AllDay {
+				eventStart = time.Date(eventStart.Year(), eventStart.Month(), eventStart.Day(), 0, 0, 0, 0, start.Location())
+				eventEnd = time.Date(eventEnd.Year(), eventEnd.Month(), eventEnd.Day(), 0, 0, 0, 0, start.Location()).AddDate(0, 0, 1)
+			}
+			if !eventStart.Before(end) || !eventEnd.After(start) {
+				continue
+			}
+			conflicts = append(conflicts, TeamShiftConflict{
+				ShiftID:           shift.ID,
+				UserID:            shift.UserID,
+				UserName:          shift.UserName,
+				Date:              day.Format("2006-01-02"),
+				ShiftStart:        shift.StartTime,
+				ShiftEnd:          shift.EndTime,
+				EventID:           e.ID,
+				EventTitle:        e.Title,
+				EventType:         e.EventType,
+				EventContractID:   e.ContractID,
+				EventContractName: e.ContractName,
+				EventStart:        e.StartDatetime,
+				EventEnd:          e.EndDatetime,
+			})
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseShiftClock(t *testing.T) {
+	cases := map[string]int{"08:00": 480, "17:30": 1050, "23:59:00": 1439, "00:00": 0}
+	for value, want := range cases {
+		got, err := ParseShiftClock(value)
+		if err != nil || got != want {
+			t.Errorf("ParseShiftClock(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "8", "24:00", "12:60", "ab:cd"} {
+		if _, err := ParseShiftClock(value); err == nil {
+			t.Errorf("expected error for %q", value)
+		}
+	}
+}
+
+func TestTeamShiftOverlaps(t *testing.T) {
+	until := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	morning := &TeamShift{Weekday: 1, StartTime: "08:00", EndTime: "12:00"}
+	afternoon := &TeamShift{Weekday: 1, StartTime: "12:00", EndTime: "18:00"}
+	overlapping := &TeamShift{Weekday: 1, StartTime: "11:00", EndTime: "14:00"}
+	otherDay := &TeamShift{Weekday: 2, StartTime: "08:00", EndTime: "12:00"}
+
+	if morning.Overlaps(afternoon) {
+		t.Error("back-to-back shifts should not overlap")
+	}
+	if !morning.Overlaps(overlapping) || !overlapping.Overlaps(afternoon) {
+		t.Error("expected overlapping hours to be detected")
+	}
+	if morning.Overlaps(otherDay) {
+		t.Error("shifts on different weekdays should not overlap")
+	}
+
+	ended := &TeamShift{Weekday: 1, StartTime: "08:00", EndTime: "12:00", EffectiveUntil: &until}
+	later := &TeamShift{Weekday: 1, StartTime: "09:00", EndTime: "10:00", EffectiveFrom: &from}
+	if ended.Overlaps(later) || later.Overlaps(ended) {
+		t.Error("shifts with disjoint effective dates should not overlap")
+	}
+}
+
+func TestShiftEventConflicts(t *testing.T) {
+	contractID := "c1"
+	otherContract := "c2"
+	shift := &TeamShift{ID: "s1", UserID: "u1", ContractID: contractID, Weekday: int(time.Monday), StartTime: "08:00", EndTime: "12:00", IsActive: true}
+
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []AgendaEvent{
+		{ID: "own", ContractID: &contractID, StartDatetime: monday.Add(9 * time.Hour), EndDatetime: monday.Add(10 * time.Hour)},
+		{ID: "clash", ContractID: &otherContract, StartDatetime: monday.Add(11 * time.Hour), EndDatetime: monday.Add(13 * time.Hour)},
+		{ID: "after", StartDatetime: monday.Add(12 * time.Hour), EndDatetime: monday.Add(13 * time.Hour)},
+		{ID: "allday", AllDay: true, StartDatetime: monday.AddDate(0, 0, 7), EndDatetime: monday.AddDate(0, 0, 7)},
+		{ID: "tuesday", StartDatetime: monday.AddDate(0, 0, 1).Add(9 * time.Hour), EndDatetime: monday.AddDate(0, 0, 1).Add(10 * time.Hour)},
+	}
+
+	conflicts := ShiftEventConflicts(shift, events, monday, monday.AddDate(0, 0, 14))
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %d: %+v", len(conflicts), conflicts)
+	}
+	if conflicts[0].EventID != "clash" || conflicts[0].Date != "2024-01-01" {
+		t.Errorf("unexpected first conflict: %+v", conflicts[0])
+	}
+	if conflicts[1].EventID != "allday" || conflicts[1].Date != "2024-01-08" {
+		t.Errorf("unexpected second conflict: %+v", conflicts[1])
+	}
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTeamRoleAllows(t *testing.T) {
+	tests := []struct {
+		role   string
+		action string
+		want   bool
+	}{
+		{TeamRoleLeader, TeamActionManageTasks, true},
+		{TeamRoleLeader, TeamActionManageAudits, true},
+		{TeamRoleAuditor, TeamActionManageAudits, true},
+		{TeamRoleAuditor, TeamActionManageInspections, false},
+		{TeamRoleAuditor, TeamActionUpdateTaskStatus, true},
+		{TeamRoleInspector, TeamActionManageInspections, true},
+		{TeamRoleInspector, TeamActionManageTasks, false},
+		{TeamRoleViewer, TeamActionView, true},
+		{TeamRoleViewer, TeamActionUpdateTaskStatus, false},
+		{TeamRoleLeader, TeamActionManagePurchases, true},
+		{TeamRoleAuditor, TeamActionManagePurchases, false},
+		{TeamRoleViewer, TeamActionManagePurchases, false},
+		{"supervisor", TeamActionView, false},
+	}
+
+	for _, tt := range tests {
+		if got := TeamRoleAllows(tt.role, tt.action); got != tt.want {
+			t.Errorf("TeamRoleAllows(%q, %q) = %v, want %v", tt.role, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestTeamMemberIsActiveOn(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, -1, 0)
+	future := now.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name   string
+		member TeamMember
+		want   bool
+	}{
+		{"open-ended", TeamMember{IsActive: true}, true},
+		{"inactive", TeamMember{IsActive: false}, false},
+		{"not started", TeamMember{IsActive: true, StartDate: &future}, false},
+		{"ended", TeamMember{IsActive: true, EndDate: &past}, false},
+		{"within range", TeamMember{IsActive: true, StartDate: &past, EndDate: &future}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.member.IsActiveOn(now); got != tt.want {
+			t.Errorf("%s: IsActiveOn = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
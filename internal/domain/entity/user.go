@@ -8,8 +8,21 @@ type UserRole string
 const (
 	// RoleAdmin represents an administrator user
 	RoleAdmin UserRole = "admin"
-	// RoleManager represents a manager user
+	// RoleManager represents a manager user. Kept for existing accounts, it has the
+	// permissions of RoleGestor.
 	RoleManager UserRole = "manager"
+	// RoleGestor represents the gestor managing a set of contracts
+	RoleGestor UserRole = "gestor"
+	// RoleAuditor represents an auditor who runs audits on contracts
+	RoleAuditor UserRole = "auditor"
+	// RoleSupervisor represents the supervisor of the field staff of a contract
+	RoleSupervisor UserRole = "supervisor"
+	// RoleZelador represents a caretaker (field staff)
+	RoleZelador UserRole = "zelador"
+	// RoleManutencao represents a maintenance worker (field staff)
+	RoleManutencao UserRole = "manutencao"
+	// RoleASG represents a general services assistant (field staff)
+	RoleASG UserRole = "asg"
 	// RoleInstructor represents an instructor user
 	RoleInstructor UserRole = "instructor"
 	// RoleStudent represents a student user
@@ -26,7 +39,8 @@ func (r UserRole) String() string {
 // IsValid checks if the role is valid
 func (r UserRole) IsValid() bool {
 	switch r {
-	case RoleAdmin, RoleManager, RoleInstructor, RoleStudent, RoleUser:
+	case RoleAdmin, RoleManager, RoleGestor, RoleAuditor, RoleSupervisor, RoleZelador, RoleManutencao, RoleASG,
+		RoleInstructor, RoleStudent, RoleUser:
 		return true
 	}
 	return false
@@ -43,6 +57,7 @@ type User struct {
 	Phone        *string    `db:"phone" json:"phone,omitempty"`
 	CPF          *string    `db:"cpf" json:"cpf,omitempty"`
 	AvatarURL    *string    `db:"avatar_url" json:"avatar_url,omitempty"`
+	Bio          *string    `db:"bio" json:"bio,omitempty"`
 	LastLoginAt  *time.Time `db:"last_login" json:"last_login,omitempty"`
 	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt    *time.Time `db:"updated_at" json:"updated_at,omitempty"`
@@ -57,6 +72,7 @@ type UserPublic struct {
 	IsActive    bool       `json:"is_active"`
 	Phone       *string    `json:"phone,omitempty"`
 	AvatarURL   *string    `json:"avatar_url,omitempty"`
+	Bio         *string    `json:"bio,omitempty"`
 	LastLoginAt *time.Time `json:"last_login,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 }
@@ -71,6 +87,7 @@ func (u *User) ToPublic() *UserPublic {
 		IsActive:    u.IsActive,
 		Phone:       u.Phone,
 		AvatarURL:   u.AvatarURL,
+		Bio:         u.Bio,
 		LastLoginAt: u.LastLoginAt,
 		CreatedAt:   u.CreatedAt,
 	}
@@ -93,9 +110,11 @@ type RegisterRequest struct {
 
 // LoginResponse represents the response after successful login
 type LoginResponse struct {
-	Token     string      `json:"token"`
-	ExpiresIn int64       `json:"expires_in"`
-	User      *UserPublic `json:"user"`
+	Token            string      `json:"token"`
+	ExpiresIn        int64       `json:"expires_in"`
+	RefreshToken     string      `json:"refresh_token"`
+	RefreshExpiresIn int64       `json:"refresh_expires_in"`
+	User             *UserPublic `json:"user"`
 }
 
 // ChangePasswordRequest represents a password change request
@@ -104,11 +123,13 @@ type ChangePasswordRequest struct {
 	NewPassword string `json:"new_password" binding:"required,min=6"`
 }
 
-// UpdateUserRequest represents a user update request
+// UpdateUserRequest represents a user update request. Bio is shown on the public course
+// catalog for instructors.
 type UpdateUserRequest struct {
 	Nome      *string `json:"nome,omitempty"`
 	Phone     *string `json:"phone,omitempty"`
 	AvatarURL *string `json:"avatar_url,omitempty"`
+	Bio       *string `json:"bio,omitempty" binding:"omitempty,max=2000"`
 }
 
 // AdminUpdateUserRequest represents an admin user update request
@@ -119,4 +140,5 @@ type AdminUpdateUserRequest struct {
 	IsActive  *bool     `json:"is_active,omitempty"`
 	Phone     *string   `json:"phone,omitempty"`
 	AvatarURL *string   `json:"avatar_url,omitempty"`
+	Bio       *string   `json:"bio,omitempty" binding:"omitempty,max=2000"`
 }
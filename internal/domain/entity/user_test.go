@@ -12,6 +12,8 @@ func TestUserRole_String(t *testing.T) {
 	}{
 		{RoleAdmin, "admin"},
 		{RoleManager, "manager"},
+		{RoleGestor, "gestor"},
+		{RoleAuditor, "auditor"},
 		{RoleInstructor, "instructor"},
 		{RoleStudent, "student"},
 		{RoleUser, "user"},
@@ -25,7 +27,7 @@ func TestUserRole_String(t *testing.T) {
 }
 
 func TestUserRole_IsValid_ValidRoles(t *testing.T) {
-	validRoles := []UserRole{RoleAdmin, RoleManager, RoleInstructor, RoleStudent, RoleUser}
+	validRoles := []UserRole{RoleAdmin, RoleManager, RoleGestor, RoleAuditor, RoleSupervisor, RoleZelador, RoleManutencao, RoleASG, RoleInstructor, RoleStudent, RoleUser}
 	for _, role := range validRoles {
 		if !role.IsValid() {
 			t.Errorf("UserRole(%q).IsValid() = false, want true", role)
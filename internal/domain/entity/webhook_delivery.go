@@ -0,0 +1,77 @@
+package entity
+
+import "time"
+
+// Webhook delivery outcomes
+const (
+	WebhookOutcomeProcessed = "processed" // applied, or a redelivery of an event already applied
+	WebhookOutcomeIgnored   = "ignored"   // a valid event of a type that is not handled
+	WebhookOutcomeRejected  = "rejected"  // wrong signature or token
+	WebhookOutcomeInvalid   = "invalid"   // unreadable body or payload
+	WebhookOutcomeFailed    = "failed"    // processing failed; the gateway redelivers it
+)
+
+// Webhook health statuses of a gateway
+const (
+	WebhookHealthHealthy  = "healthy"
+	WebhookHealthDegraded = "degraded" // a share of the deliveries fails
+	WebhookHealthFailing  = "failing"  // nothing was processed since the last failure
+	WebhookHealthSilent   = "silent"   // no delivery in the window
+)
+
+// WebhookDelivery records a webhook request received from a payment gateway and its outcome
+type WebhookDelivery struct {
+	ID               string    `db:"id" json:"id"`
+	Gateway          string    `db:"gateway" json:"gateway"`
+	EventType        *string   `db:"event_type" json:"event_type,omitempty"`
+	GatewayEventID   *string   `db:"gateway_event_id" json:"gateway_event_id,omitempty"`
+	GatewayPaymentID *string   `db:"gateway_payment_id" json:"gateway_payment_id,omitempty"`
+	Outcome          string    `db:"outcome" json:"outcome"`
+	Status           int       `db:"status" json:"status"`
+	LatencyMs        int64     `db:"latency_ms" json:"latency_ms"`
+	Error            *string   `db:"error" json:"error,omitempty"`
+	ReceivedAt       time.Time `db:"received_at" json:"received_at"`
+}
+
+// WebhookDeliveryFilters holds filter parameters for listing webhook deliveries
+type WebhookDeliveryFilters struct {
+	Gateway string
+	Outcome string
+	Since   *time.Time
+	Page    int
+	PerPage int
+}
+
+// WebhookGatewayStats aggregates the webhook deliveries of a gateway since a point in time
+type WebhookGatewayStats struct {
+	Gateway         string     `db:"gateway" json:"gateway"`
+	Total           int        `db:"total" json:"total"`
+	Processed       int        `db:"processed" json:"processed"`
+	Ignored         int        `db:"ignored" json:"ignored"`
+	Rejected        int        `db:"rejected" json:"rejected"`
+	Invalid         int        `db:"invalid" json:"invalid"`
+	Failed          int        `db:"failed" json:"failed"`
+	AvgLatencyMs    float64    `db:"avg_latency_ms" json:"avg_latency_ms"`
+	MaxLatencyMs    int64      `db:"max_latency_ms" json:"max_latency_ms"`
+	LastReceivedAt  *time.Time `db:"last_received_at" json:"last_received_at,omitempty"`
+	LastProcessedAt *time.Time `db:"last_processed_at" json:"last_processed_at,omitempty"`
+	LastFailureAt   *time.Time `db:"last_failure_at" json:"last_failure_at,omitempty"`
+}
+
+// Failures counts the deliveries that were not accepted
+func (s WebhookGatewayStats) Failures() int {
+	return s.Rejected + s.Invalid + s.Failed
+}
+
+// WebhookHealth is the webhook health of a gateway over a window
+type WebhookHealth struct {
+	WebhookGatewayStats
+	FailureRate float64 `json:"failure_rate"` // share of the deliveries not accepted, 0 to 1
+	Status      string  `json:"status"`
+}
+
+// WebhookHealthReport is the webhook health of every gateway
+type WebhookHealthReport struct {
+	Since    time.Time       `json:"since"`
+	Gateways []WebhookHealth `json:"gateways"`
+}
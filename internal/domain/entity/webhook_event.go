@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// ProcessedWebhookEvent records a gateway webhook event that changed our data, so a
+// redelivery of the same event is recognised and skipped
+type ProcessedWebhookEvent struct {
+	Gateway          string    `db:"gateway" json:"gateway"`
+	GatewayEventID   string    `db:"gateway_event_id" json:"gateway_event_id"`
+	EventType        string    `db:"event_type" json:"event_type"`
+	GatewayPaymentID string    `db:"gateway_payment_id" json:"gateway_payment_id"`
+	ProcessedAt      time.Time `db:"processed_at" json:"processed_at"`
+}
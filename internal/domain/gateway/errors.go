@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/condotrack/api/pkg/apperror"
+)
+
+// ErrCircuitOpen is returned without calling the provider while its circuit breaker is open
+// after repeated failures
+var ErrCircuitOpen = errors.New("payment gateway circuit breaker is open")
+
+// IsTimeout reports whether a gateway call failed because the provider did not answer in
+// time, either by the HTTP client timeout or the request deadline
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// Retryable reports whether a failed charge may be tried again on another gateway: the
+// provider refused it or could not be reached. Timeouts are not retried, since the charge
+// may exist on the provider, nor errors of the request itself.
+func Retryable(err error) bool {
+	if err == nil || IsTimeout(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	_, coded := apperror.As(err)
+	return !coded
+}
+
+// Failure wraps an error returned by a gateway call with GATEWAY_TIMEOUT or GATEWAY_ERROR,
+// so clients can tell a slow provider (the charge may still exist) from a refused request,
+// or with SERVICE_UNAVAILABLE when the call was not attempted because the circuit is open.
+// Errors that already carry a code are returned unchanged.
+func Failure(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := apperror.As(err); ok {
+		return err
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return apperror.Wrap(apperror.CodeUnavailable, "Payment gateway is temporarily unavailable", err)
+	}
+	if IsTimeout(err) {
+		return apperror.Wrap(apperror.CodeGatewayTimeout, "Payment gateway timed out", err)
+	}
+	return apperror.Wrap(apperror.CodeGatewayError, "Payment gateway request failed", err)
+}
@@ -30,3 +30,43 @@ type PaymentGateway interface {
 	// Fees
 	GetFees() GatewayFees
 }
+
+// Selector is implemented by gateways that dispatch to several providers (the default
+// gateway of the factory), so operations on an existing payment can reach the provider it
+// was created with.
+type Selector interface {
+	For(name string) (PaymentGateway, error)
+}
+
+// ForPayment returns the gateway a payment was created with when gw can select it, or gw
+// itself otherwise.
+func ForPayment(gw PaymentGateway, name string) PaymentGateway {
+	if s, ok := gw.(Selector); ok && name != "" {
+		if selected, err := s.For(name); err == nil {
+			return selected
+		}
+	}
+	return gw
+}
+
+// TransferGateway is implemented by gateways that can pay out from the platform balance
+// (e.g. Asaas transfers). It is kept apart from PaymentGateway because not every gateway
+// supports outgoing transfers.
+type TransferGateway interface {
+	// Name returns the gateway identifier
+	Name() string
+
+	CreateTransfer(ctx context.Context, req TransferRequest) (*TransferResponse, error)
+	GetTransfer(ctx context.Context, gatewayTransferID string) (*TransferResponse, error)
+
+	// ParseTransferEvent parses a transfer webhook. It returns nil when the body is not a transfer event.
+	ParseTransferEvent(ctx context.Context, body []byte) (*TransferEvent, error)
+}
+
+// AccountVerifier is implemented by transfer gateways that can check a payout destination
+// (that the PIX key or bank account exists and belongs to its owner) without moving money.
+// Payout accounts are verified with a micro-deposit on gateways that cannot.
+type AccountVerifier interface {
+	// VerifyAccount checks the destination of req; its amount is ignored
+	VerifyAccount(ctx context.Context, req TransferRequest) (*AccountVerification, error)
+}
@@ -36,6 +36,10 @@ type CustomerResponse struct {
 	Name      string
 	Email     string
 	Document  string
+
+	// Gateway is the provider holding the customer, set by gateways that may fall back to
+	// another provider
+	Gateway string
 }
 
 // CreatePaymentRequest is the gateway-agnostic payment creation request (PIX/Boleto).
@@ -45,6 +49,12 @@ type CreatePaymentRequest struct {
 	Description       string
 	DueDate           time.Time
 	ExternalReference string
+
+	// CustomerGateway is the provider holding CustomerGatewayID, empty for the default one
+	CustomerGateway string
+	// Customer is the payer, which lets a fallback gateway find or create the customer on
+	// another provider when the charge fails; nil keeps the charge on one provider
+	Customer *CreateCustomerRequest
 }
 
 // CreateCardPaymentRequest extends CreatePaymentRequest with card data.
@@ -86,10 +96,17 @@ type PaymentResponse struct {
 
 	// Card
 	TransactionReceiptURL string
+	InstallmentID         string // Groups the installments of a card charge split by the gateway
+
+	// Set by gateways that may fall back to another provider: the provider that created the
+	// charge and, when it moved there, the customer it was created for on that provider
+	Gateway           string
+	CustomerGatewayID string
 }
 
 // WebhookEvent is the gateway-agnostic webhook event.
 type WebhookEvent struct {
+	EventID          string // Gateway event ID, the same on every redelivery of the event
 	EventType        string // Canonical event type
 	GatewayEvent     string // Original gateway event
 	GatewayName      string // Which gateway sent the event
@@ -103,6 +120,12 @@ type WebhookEvent struct {
 	ExternalRef      string
 	PaidAt           *time.Time
 	RawPayload       []byte
+	Settled          bool // Funds are available in the gateway balance (not only confirmed)
+
+	// Card installments the gateway settles one by one arrive as separate payments of the
+	// same installment plan
+	InstallmentID     string
+	InstallmentNumber int
 }
 
 // GatewayFees holds fee configuration for a gateway.
@@ -124,3 +147,66 @@ const (
 	EventPaymentFailed    = "payment_failed"
 	EventPaymentChargeback = "payment_chargeback"
 )
+
+// GatewayEventManual is the gateway event of payments an admin confirmed by hand, paid
+// outside the gateway; they go through the webhook pipeline like a gateway event
+const GatewayEventManual = "MANUAL"
+
+// Canonical transfer status constants
+const (
+	TransferStatusPending    = "pending"
+	TransferStatusProcessing = "processing"
+	TransferStatusDone       = "done"
+	TransferStatusFailed     = "failed"
+	TransferStatusCancelled  = "cancelled"
+)
+
+// TransferRequest is the gateway-agnostic request to send money out of the platform balance.
+// Exactly one destination is set: WalletID, PixKey or BankAccount.
+type TransferRequest struct {
+	Amount            float64
+	WalletID          string
+	PixKey            string
+	PixKeyType        string
+	BankAccount       *TransferBankAccount
+	Description       string
+	ExternalReference string
+}
+
+// TransferBankAccount is the destination bank account of a TED transfer.
+type TransferBankAccount struct {
+	BankCode      string
+	Agency        string
+	Account       string
+	AccountDigit  string
+	AccountType   string
+	OwnerName     string
+	OwnerDocument string
+}
+
+// TransferResponse is the gateway-agnostic transfer response.
+type TransferResponse struct {
+	GatewayTransferID string
+	Status            string // Canonical transfer status
+	GatewayRawStatus  string
+	Amount            float64
+	Fee               float64
+	FailReason        string
+	ExternalReference string
+	EffectiveDate     *time.Time
+}
+
+// AccountVerification is the gateway-agnostic result of a payout destination check.
+// Reason tells why an invalid destination was rejected.
+type AccountVerification struct {
+	Valid  bool
+	Reason string
+}
+
+// TransferEvent is the gateway-agnostic transfer status webhook event.
+type TransferEvent struct {
+	GatewayEvent string
+	GatewayName  string
+	Transfer     TransferResponse
+	RawPayload   []byte
+}
@@ -0,0 +1,154 @@
+// Package listquery parses the filter and sort language shared by list endpoints:
+//
+//	?filter[status]=active&filter[created_at][gte]=2024-01-01&sort=-created_at,title
+//
+// A filter without an operator is an equality; sort fields prefixed with "-" are descending.
+// Which fields can be filtered and sorted is decided by each repository.
+package listquery
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrInvalid is wrapped by every error caused by a malformed or unsupported list query
+var ErrInvalid = errors.New("invalid list query")
+
+// Filter operators
+const (
+	OpEq   = "eq"
+	OpNe   = "ne"
+	OpGt   = "gt"
+	OpGte  = "gte"
+	OpLt   = "lt"
+	OpLte  = "lte"
+	OpIn   = "in"   // comma separated values
+	OpLike = "like" // substring match
+)
+
+var validOps = map[string]bool{
+	OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true, OpIn: true, OpLike: true,
+}
+
+const (
+	maxFilters = 20
+	maxSorts   = 5
+)
+
+// Filter is one condition on a field
+type Filter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Values splits the value of an "in" filter
+func (f Filter) Values() []string {
+	var values []string
+	for _, v := range strings.Split(f.Value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Sort orders the results by a field
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query is a parsed list query; the zero value filters nothing and keeps the default order
+type Query struct {
+	Filters []Filter
+	Sort    []Sort
+}
+
+// IsEmpty reports whether the query has neither filters nor sorting
+func (q Query) IsEmpty() bool {
+	return len(q.Filters) == 0 && len(q.Sort) == 0
+}
+
+// Parse reads the filter[...] and sort parameters of a query string. Other parameters are
+// ignored, so endpoint specific parameters keep working alongside.
+func Parse(values url.Values) (Query, error) {
+	var q Query
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > maxFilters {
+		return Query{}, fmt.Errorf("%w: at most %d filters", ErrInvalid, maxFilters)
+	}
+
+	for _, key := range keys {
+		field, op, err := parseFilterKey(key)
+		if err != nil {
+			return Query{}, err
+		}
+		for _, value := range values[key] {
+			q.Filters = append(q.Filters, Filter{Field: field, Op: op, Value: strings.TrimSpace(value)})
+		}
+	}
+
+	if s := values.Get("sort"); s != "" {
+		for _, part := range strings.Split(s, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			desc := strings.HasPrefix(part, "-")
+			field := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+			if !validName(field) {
+				return Query{}, fmt.Errorf("%w: bad sort field %q", ErrInvalid, part)
+			}
+			q.Sort = append(q.Sort, Sort{Field: field, Desc: desc})
+		}
+		if len(q.Sort) > maxSorts {
+			return Query{}, fmt.Errorf("%w: at most %d sort fields", ErrInvalid, maxSorts)
+		}
+	}
+
+	return q, nil
+}
+
+// parseFilterKey splits "filter[field]" or "filter[field][op]"
+func parseFilterKey(key string) (field, op string, err error) {
+	rest := strings.TrimPrefix(key, "filter[")
+	field, rest, ok := strings.Cut(rest, "]")
+	if !ok || !validName(field) {
+		return "", "", fmt.Errorf("%w: bad filter %q", ErrInvalid, key)
+	}
+
+	op = OpEq
+	if rest != "" {
+		if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+			return "", "", fmt.Errorf("%w: bad filter %q", ErrInvalid, key)
+		}
+		op = rest[1 : len(rest)-1]
+		if !validOps[op] {
+			return "", "", fmt.Errorf("%w: unknown operator %q in %q", ErrInvalid, op, key)
+		}
+	}
+	return field, op, nil
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package listquery
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	values, _ := url.ParseQuery("filter[status]=active&filter[created_at][gte]=2024-01-01&sort=-created_at,title&page=2")
+
+	q, err := Parse(values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantFilters := []Filter{
+		{Field: "created_at", Op: OpGte, Value: "2024-01-01"},
+		{Field: "status", Op: OpEq, Value: "active"},
+	}
+	if !reflect.DeepEqual(q.Filters, wantFilters) {
+		t.Errorf("filters = %+v, want %+v", q.Filters, wantFilters)
+	}
+	wantSort := []Sort{{Field: "created_at", Desc: true}, {Field: "title"}}
+	if !reflect.DeepEqual(q.Sort, wantSort) {
+		t.Errorf("sort = %+v, want %+v", q.Sort, wantSort)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	values, _ := url.ParseQuery("status=active&page=1")
+	q, err := Parse(values)
+	if err != nil || !q.IsEmpty() {
+		t.Errorf("Parse = %+v, %v; want an empty query", q, err)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"filter[status][between]=1",
+		"filter[Status]=x",
+		"filter[status]x=1",
+		"filter[]=1",
+		"sort=-created at",
+	} {
+		values, _ := url.ParseQuery(raw)
+		if _, err := Parse(values); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalid", raw, err)
+		}
+	}
+}
+
+func TestFilter_Values(t *testing.T) {
+	f := Filter{Field: "status", Op: OpIn, Value: "active, pending,,"}
+	if got := f.Values(); !reflect.DeepEqual(got, []string{"active", "pending"}) {
+		t.Errorf("Values = %v", got)
+	}
+}
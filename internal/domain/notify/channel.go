@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// ErrNoAddress is returned by a channel when the recipient cannot be reached on it (no
+// e-mail, phone or push subscription). The delivery is skipped instead of retried.
+var ErrNoAddress = errors.New("recipient has no address on this channel")
+
+// Channel defines the interface every outbound notification channel must implement (SMTP
+// e-mail, WhatsApp, web push). Errors other than ErrNoAddress are retried with backoff.
+type Channel interface {
+	// Name returns the channel identifier (entity.NotificationChannelEmail, ...)
+	Name() string
+
+	// Send delivers the notification to the user
+	Send(ctx context.Context, n *entity.Notificacao, to *entity.User) error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// AccountingRepository defines the interface for accounting entries and export sync state
+type AccountingRepository interface {
+	// FindEntries returns the accounting entries that occurred in [from, to), oldest first
+	FindEntries(ctx context.Context, from, to time.Time) ([]entity.AccountingEntry, error)
+
+	// FindUnexportedEntries returns entries since the given time that were never exported to the layout, oldest first
+	FindUnexportedEntries(ctx context.Context, layout string, since time.Time, limit int) ([]entity.AccountingEntry, error)
+
+	// FindRunEntries returns the entries exported by a sync run
+	FindRunEntries(ctx context.Context, runID string) ([]entity.AccountingEntry, error)
+
+	// FindCursors returns the sync cursor of every layout that was synced at least once
+	FindCursors(ctx context.Context) ([]entity.AccountingSyncCursor, error)
+
+	// LockCursorWithTx returns the sync cursor of a layout, creating it when missing, and locks it within a transaction
+	LockCursorWithTx(ctx context.Context, tx *sqlx.Tx, layout string) (*entity.AccountingSyncCursor, error)
+
+	// UpdateCursorWithTx saves the sync cursor of a layout within a transaction
+	UpdateCursorWithTx(ctx context.Context, tx *sqlx.Tx, cursor *entity.AccountingSyncCursor) error
+
+	// CreateRunWithTx creates a sync run and records its entries as exported within a transaction
+	CreateRunWithTx(ctx context.Context, tx *sqlx.Tx, run *entity.AccountingSyncRun, entryKeys []string) error
+
+	// FindRuns returns the sync runs of a layout (all layouts when empty), newest first
+	FindRuns(ctx context.Context, layout string, limit int) ([]entity.AccountingSyncRun, error)
+
+	// FindRunByID returns a sync run by ID
+	FindRunByID(ctx context.Context, id string) (*entity.AccountingSyncRun, error)
+
+	// CreateCloseJob queues a period close export
+	CreateCloseJob(ctx context.Context, job *entity.AccountingCloseJob) error
+
+	// FindCloseJobs returns the period close exports, newest first
+	FindCloseJobs(ctx context.Context, limit int) ([]entity.AccountingCloseJob, error)
+
+	// FindCloseJobByID returns a period close export by ID
+	FindCloseJobByID(ctx context.Context, id string) (*entity.AccountingCloseJob, error)
+
+	// ClaimCloseJob marks the oldest pending export as running and returns it, nil when there is
+	// none. Exports left running since before staleBefore (e.g. by a crashed instance) are claimed again.
+	ClaimCloseJob(ctx context.Context, staleBefore time.Time) (*entity.AccountingCloseJob, error)
+
+	// FinishCloseJob saves the outcome of a claimed export
+	FinishCloseJob(ctx context.Context, job *entity.AccountingCloseJob) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// ActivityLogRepository defines the interface for activity log data access
+type ActivityLogRepository interface {
+	// Create records an activity log entry
+	Create(ctx context.Context, log *entity.ActivityLog) error
+
+	// FindAll returns the entries matching the filters, newest first, and the total count
+	FindAll(ctx context.Context, filters entity.ActivityLogFilters) ([]entity.ActivityLog, int, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// AIUsageRepository defines the interface for AI usage metering data access
+type AIUsageRepository interface {
+	// Create records a provider call
+	Create(ctx context.Context, usage *entity.AIUsage) error
+
+	// Summarize aggregates the recorded calls per provider and model
+	Summarize(ctx context.Context, filters entity.AIUsageFilters) ([]entity.AIUsageSummary, error)
+}
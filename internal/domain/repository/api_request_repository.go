@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// APIRequestFilters holds filter parameters for listing audited API requests.
+type APIRequestFilters struct {
+	ActorID    string
+	Method     string
+	PathPrefix string
+	Status     int
+	DateFrom   *time.Time
+	DateTo     *time.Time
+	Page       int
+	PerPage    int
+}
+
+// APIRequestRepository defines the interface for API request audit log data access
+type APIRequestRepository interface {
+	// Create records an API request
+	Create(ctx context.Context, req *entity.APIRequest) error
+
+	// FindAll returns the recorded requests matching the filters, newest first, and the total count
+	FindAll(ctx context.Context, filters APIRequestFilters) ([]entity.APIRequest, int, error)
+}
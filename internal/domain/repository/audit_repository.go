@@ -2,15 +2,19 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
 	"github.com/jmoiron/sqlx"
 )
 
 // AuditRepository defines the interface for audit data access
 type AuditRepository interface {
-	// FindAll returns all audits
-	FindAll(ctx context.Context) ([]entity.Audit, error)
+	// FindAll returns a page of the audits matching the filters, with their contract
+	// information, and the total number of matches
+	FindAll(ctx context.Context, filters AuditFilters) ([]entity.AuditWithContract, int, error)
 
 	// FindByID returns an audit by ID
 	FindByID(ctx context.Context, id string) (*entity.Audit, error)
@@ -18,9 +22,6 @@ type AuditRepository interface {
 	// FindByContractID returns all audits for a specific contract
 	FindByContractID(ctx context.Context, contractID string) ([]entity.Audit, error)
 
-	// FindAllWithContract returns all audits with contract information
-	FindAllWithContract(ctx context.Context) ([]entity.AuditWithContract, error)
-
 	// FindLastByContractID returns the most recent audit for a contract
 	FindLastByContractID(ctx context.Context, contractID string) (*entity.Audit, error)
 
@@ -44,6 +45,31 @@ type AuditRepository interface {
 
 	// GetAverageScoreByContractID returns the average score for a contract
 	GetAverageScoreByContractID(ctx context.Context, contractID string) (float64, error)
+
+	// FindAISummary returns the AI summary cached on an audit, or nil if there is none
+	FindAISummary(ctx context.Context, auditID string) (*entity.AuditAISummary, error)
+
+	// SaveAISummary caches an AI summary on an audit
+	SaveAISummary(ctx context.Context, auditID string, summary *entity.AuditAISummary) error
+
+	// FindDataJSON returns up to limit audits with a data_json, ordered by ID and starting
+	// after afterID
+	FindDataJSON(ctx context.Context, afterID string, limit int) ([]entity.AuditDataRecord, error)
+
+	// UpdateDataJSON replaces the data_json of an audit
+	UpdateDataJSON(ctx context.Context, id string, data json.RawMessage) error
+}
+
+// AuditFilters holds filter parameters for listing audits.
+type AuditFilters struct {
+	ContractID string
+	Status     string
+	Auditor    string     // substring of the auditor name
+	DateFrom   *time.Time // audit date, inclusive
+	DateTo     *time.Time // audit date, inclusive (the whole day)
+	Page       int
+	PerPage    int
+	Query      listquery.Query // filter[...] and sort parameters
 }
 
 // AuditItemRepository defines the interface for audit item data access
@@ -39,6 +39,9 @@ type CertificadoRepository interface {
 
 // NotificacaoRepository defines the interface for notification data access
 type NotificacaoRepository interface {
+	// FindByID returns a notification by ID, or nil if it does not exist
+	FindByID(ctx context.Context, id string) (*entity.Notificacao, error)
+
 	// FindByUserID returns all notifications for a user
 	FindByUserID(ctx context.Context, userID string) ([]entity.Notificacao, error)
 
@@ -59,6 +62,12 @@ type NotificacaoRepository interface {
 
 	// CountUnread returns the count of unread notifications for a user
 	CountUnread(ctx context.Context, userID string) (int, error)
+
+	// FindPreferences returns the stored notification preferences of a user
+	FindPreferences(ctx context.Context, userID string) ([]entity.NotificationPreference, error)
+
+	// SavePreferences creates or replaces notification preferences of a user
+	SavePreferences(ctx context.Context, userID string, prefs []entity.NotificationPreference) error
 }
 
 // RevenueSplitRepository defines the interface for revenue split data access
@@ -66,15 +75,29 @@ type RevenueSplitRepository interface {
 	// FindByID returns a revenue split by ID
 	FindByID(ctx context.Context, id string) (*entity.RevenueSplit, error)
 
+	// FindByIDForUpdateWithTx returns a revenue split by ID and locks it within a transaction
+	FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.RevenueSplit, error)
+
 	// FindAll returns all revenue splits with optional status filter
 	FindAll(ctx context.Context, status string) ([]entity.RevenueSplit, error)
 
 	// FindByEnrollmentID returns revenue split by enrollment ID
 	FindByEnrollmentID(ctx context.Context, enrollmentID string) (*entity.RevenueSplit, error)
 
-	// FindByPaymentID returns revenue split by payment ID
+	// FindByPaymentID returns revenue split by payment ID; the first installment one when the
+	// payment is split per installment
 	FindByPaymentID(ctx context.Context, paymentID string) (*entity.RevenueSplit, error)
 
+	// FindByPaymentInstallment returns the revenue split of one installment of a payment
+	FindByPaymentInstallment(ctx context.Context, paymentID string, installment int) (*entity.RevenueSplit, error)
+
+	// FindAllByPaymentID returns every revenue split of a payment, in installment order
+	FindAllByPaymentID(ctx context.Context, paymentID string) ([]entity.RevenueSplit, error)
+
+	// FindAllByEnrollmentID returns every revenue split of an enrollment (one per paid
+	// installment, and those of its renewals), oldest first
+	FindAllByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.RevenueSplit, error)
+
 	// FindByInstructorID returns all revenue splits for an instructor
 	FindByInstructorID(ctx context.Context, instructorID string) ([]entity.RevenueSplit, error)
 
@@ -87,6 +110,29 @@ type RevenueSplitRepository interface {
 	// UpdateStatus updates the status of a revenue split
 	UpdateStatus(ctx context.Context, id, status string) error
 
-	// GetTotalByInstructor returns total earnings for an instructor
-	GetTotalByInstructor(ctx context.Context, instructorID string) (float64, error)
+	// UpdateStatusWithTx updates the status of a revenue split within a transaction
+	UpdateStatusWithTx(ctx context.Context, tx *sqlx.Tx, id, status string) error
+
+	// UpdateAmountsWithTx rewrites the amounts and instructor of a revenue split within a transaction
+	UpdateAmountsWithTx(ctx context.Context, tx *sqlx.Tx, split *entity.RevenueSplit) error
+
+	// FindByTransferID returns the revenue split paid out by a gateway transfer
+	FindByTransferID(ctx context.Context, transferID string) (*entity.RevenueSplit, error)
+
+	// ClaimForTransfer marks a pending, unbatched split as being transferred.
+	// It returns false when the split is not eligible or another transfer already claimed it.
+	ClaimForTransfer(ctx context.Context, id string) (bool, error)
+
+	// UpdateTransfer saves the transfer tracking fields, status and processed date of a split
+	UpdateTransfer(ctx context.Context, split *entity.RevenueSplit) error
+
+	// FindParties returns the party shares of a split in configuration order
+	FindParties(ctx context.Context, splitID string) ([]entity.RevenueSplitParty, error)
+
+	// FindPartyEntries returns the shares of a party across all splits, newest first.
+	// An empty party ID matches parties without one (the platform).
+	FindPartyEntries(ctx context.Context, partyType, partyID string) ([]entity.RevenueSplitParty, error)
+
+	// ReplacePartiesWithTx replaces the party shares of a split with split.Parties within a transaction
+	ReplacePartiesWithTx(ctx context.Context, tx *sqlx.Tx, split *entity.RevenueSplit) error
 }
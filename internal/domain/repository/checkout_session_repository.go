@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// CheckoutSessionRepository defines the interface for the checkout sessions of embedded checkouts
+type CheckoutSessionRepository interface {
+	Create(ctx context.Context, session *entity.CheckoutSession) error
+
+	// FindByID returns the session, nil when it does not exist
+	FindByID(ctx context.Context, id string) (*entity.CheckoutSession, error)
+
+	// Claim moves an open session that has not expired at now to processing and reports
+	// whether it did, so only one checkout pays for a session
+	Claim(ctx context.Context, id string, now time.Time) (bool, error)
+
+	// Release reopens a claimed session whose checkout failed
+	Release(ctx context.Context, id string) error
+
+	// Complete marks a claimed session as paid with the enrollment
+	Complete(ctx context.Context, id, enrollmentID string) error
+
+	// DeleteExpired removes the sessions that expired unpaid before the given time and returns
+	// how many were removed
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// ComplianceRepository defines the queries of the compliance dashboard
+type ComplianceRepository interface {
+	// FindExpirations returns the active contract documents and the active documents of
+	// suppliers linked to active contracts that expire on or before until, already expired
+	// ones included, by expiry date
+	FindExpirations(ctx context.Context, until time.Time) ([]entity.ComplianceExpiration, error)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// ContractBillingRepository defines the interface for contract billing plan and charge data access
+type ContractBillingRepository interface {
+	// FindPlanByContratoID returns the billing plan of a contract
+	FindPlanByContratoID(ctx context.Context, contratoID string) (*entity.ContractBillingPlan, error)
+
+	// FindActivePlans returns the active plans of active contracts
+	FindActivePlans(ctx context.Context) ([]entity.ContractBillingPlan, error)
+
+	// SavePlan creates or replaces the billing plan of a contract
+	SavePlan(ctx context.Context, plan *entity.ContractBillingPlan) error
+
+	// UpdatePlanCustomer stores the gateway customer the plan is billed to
+	UpdatePlanCustomer(ctx context.Context, id, gateway, customerID string) error
+
+	// FindCharges returns charges matching the filters, newest period first
+	FindCharges(ctx context.Context, filters entity.ContractChargeFilters) ([]entity.ContractCharge, error)
+
+	// FindChargeByID returns a charge by ID
+	FindChargeByID(ctx context.Context, id string) (*entity.ContractCharge, error)
+
+	// FindChargeByPeriod returns the charge of a contract for a YYYY-MM period
+	FindChargeByPeriod(ctx context.Context, contratoID, period string) (*entity.ContractCharge, error)
+
+	// FindChargeByGatewayPaymentID returns the charge created with a gateway payment
+	FindChargeByGatewayPaymentID(ctx context.Context, gateway, gatewayPaymentID string) (*entity.ContractCharge, error)
+
+	// CreateCharge claims the period of a contract; returns false when it was already charged
+	CreateCharge(ctx context.Context, charge *entity.ContractCharge) (bool, error)
+
+	// ClaimFailedCharge moves a failed charge back to pending for another attempt; returns
+	// false when another run got to it first
+	ClaimFailedCharge(ctx context.Context, id string) (bool, error)
+
+	// UpdateCharge updates a charge
+	UpdateCharge(ctx context.Context, charge *entity.ContractCharge) error
+
+	// MarkOverdue flags the open charges due before the given date as overdue
+	MarkOverdue(ctx context.Context, before time.Time) (int64, error)
+
+	// FindReceivables returns the open balance of each contract with receivable charges,
+	// aged as of now
+	FindReceivables(ctx context.Context, now time.Time) ([]entity.ContractReceivable, error)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// ContractDocumentRepository defines the interface for contract document data access
+type ContractDocumentRepository interface {
+	// FindByContratoID returns the documents attached to a contract
+	FindByContratoID(ctx context.Context, contratoID string, filters entity.ContractDocumentFilters) ([]entity.ContractDocument, error)
+
+	// FindByID returns a document by ID (without versions)
+	FindByID(ctx context.Context, id string) (*entity.ContractDocument, error)
+
+	// FindByIDForUpdateWithTx returns a document by ID and locks the row within a transaction
+	FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.ContractDocument, error)
+
+	// FindVersions returns all versions of a document, newest first
+	FindVersions(ctx context.Context, documentID string) ([]entity.ContractDocumentVersion, error)
+
+	// FindVersion returns a specific version of a document
+	FindVersion(ctx context.Context, documentID string, version int) (*entity.ContractDocumentVersion, error)
+
+	// CreateWithTx creates a document within a transaction
+	CreateWithTx(ctx context.Context, tx *sqlx.Tx, doc *entity.ContractDocument) error
+
+	// UpdateCurrentVersionWithTx sets the current version of a document within a transaction
+	UpdateCurrentVersionWithTx(ctx context.Context, tx *sqlx.Tx, documentID string, version int) error
+
+	// CreateVersionWithTx creates a document version within a transaction
+	CreateVersionWithTx(ctx context.Context, tx *sqlx.Tx, version *entity.ContractDocumentVersion) error
+
+	// CloseOpenVersionsWithTx ends earlier open-ended versions of a document on the given date
+	CloseOpenVersionsWithTx(ctx context.Context, tx *sqlx.Tx, documentID string, before int, until time.Time) error
+
+	// Deactivate archives a document (versions and files are kept)
+	Deactivate(ctx context.Context, id string) error
+}
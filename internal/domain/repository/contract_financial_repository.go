@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// ContractFinancialRepository defines the interface for contract budget and cost data access
+type ContractFinancialRepository interface {
+	// FindBudgetLines returns the budget lines of a contract
+	FindBudgetLines(ctx context.Context, contratoID string) ([]entity.ContractBudgetLine, error)
+
+	// FindBudgetLineByID returns a budget line by ID
+	FindBudgetLineByID(ctx context.Context, id string) (*entity.ContractBudgetLine, error)
+
+	// CreateBudgetLine creates a budget line
+	CreateBudgetLine(ctx context.Context, line *entity.ContractBudgetLine) error
+
+	// UpdateBudgetLine updates a budget line
+	UpdateBudgetLine(ctx context.Context, line *entity.ContractBudgetLine) error
+
+	// DeleteBudgetLine removes a budget line by ID
+	DeleteBudgetLine(ctx context.Context, id string) error
+
+	// FindCosts returns the recorded costs of a contract, newest period first
+	FindCosts(ctx context.Context, contratoID string, filters entity.ContractCostFilters) ([]entity.ContractCost, error)
+
+	// FindCostByID returns a recorded cost by ID
+	FindCostByID(ctx context.Context, id string) (*entity.ContractCost, error)
+
+	// CreateCost records an actual cost
+	CreateCost(ctx context.Context, cost *entity.ContractCost) error
+
+	// DeleteCost removes a recorded cost by ID
+	DeleteCost(ctx context.Context, id string) error
+
+	// SumCosts returns recorded costs grouped by period, source and category
+	SumCosts(ctx context.Context, contratoID, from, to string) ([]entity.ContractCostAggregate, error)
+
+	// SumSupplierSpend returns supplier spend grouped by period and service category
+	SumSupplierSpend(ctx context.Context, contratoID, from, to string) ([]entity.ContractCostAggregate, error)
+
+	// SumCharges returns the monthly fee billed and received per period
+	SumCharges(ctx context.Context, contratoID, from, to string) ([]entity.ContractChargeAggregate, error)
+}
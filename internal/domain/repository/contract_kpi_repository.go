@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// ContractKPIRepository defines the interface for contract KPI snapshot data access
+type ContractKPIRepository interface {
+	// Measure computes the KPIs of every active contract as of now, counting audits and
+	// inspections since from. Rates are left for the caller to fill in.
+	Measure(ctx context.Context, from, now time.Time) ([]entity.ContractKPISnapshot, error)
+
+	// Save stores the snapshots, replacing those already taken for the same contract and day
+	Save(ctx context.Context, snapshots []entity.ContractKPISnapshot) error
+
+	// FindByContratoID returns the snapshots of a contract taken between from and to
+	// (inclusive), oldest first
+	FindByContratoID(ctx context.Context, contratoID string, from, to time.Time) ([]entity.ContractKPISnapshot, error)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// ContractRenewalRepository defines the interface for contract renewal data access
+type ContractRenewalRepository interface {
+	// FindAll returns renewals matching the filters, newest first
+	FindAll(ctx context.Context, filters entity.ContractRenewalFilters) ([]entity.ContractRenewal, error)
+
+	// FindByID returns a renewal by ID
+	FindByID(ctx context.Context, id string) (*entity.ContractRenewal, error)
+
+	// FindByContratoID returns the renewal history of a contract, newest first
+	FindByContratoID(ctx context.Context, contratoID string) ([]entity.ContractRenewal, error)
+
+	// FindOpenByContratoID returns the pending or renegotiating renewal of a contract
+	FindOpenByContratoID(ctx context.Context, contratoID string) (*entity.ContractRenewal, error)
+
+	// Create creates a renewal
+	Create(ctx context.Context, renewal *entity.ContractRenewal) error
+
+	// Update updates a renewal
+	Update(ctx context.Context, renewal *entity.ContractRenewal) error
+
+	// UpdateWithTx updates a renewal within a transaction
+	UpdateWithTx(ctx context.Context, tx *sqlx.Tx, renewal *entity.ContractRenewal) error
+
+	// FindUpcoming returns active contracts ending between from and until
+	FindUpcoming(ctx context.Context, from, until time.Time) ([]entity.UpcomingContractRenewal, error)
+
+	// FindSentThresholds returns the alert thresholds already sent for a contract end date
+	FindSentThresholds(ctx context.Context, contratoID string, endDate time.Time) ([]int, error)
+
+	// CreateAlert records a sent alert
+	CreateAlert(ctx context.Context, alert *entity.ContractRenewalAlert) error
+}
@@ -4,11 +4,12 @@ import (
 	"context"
 
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
 )
 
 // ContratoRepository defines the interface for contrato data access
 type ContratoRepository interface {
-	// FindAll returns all contratos
+	// FindAll returns all active contratos; deleted ones are excluded by every lookup below
 	FindAll(ctx context.Context) ([]entity.Contrato, error)
 
 	// FindByID returns a contrato by ID
@@ -26,9 +27,18 @@ type ContratoRepository interface {
 	// Update updates an existing contrato
 	Update(ctx context.Context, contrato *entity.Contrato) error
 
-	// Delete deletes a contrato by ID
+	// UpdateWithTx updates an existing contrato within a transaction
+	UpdateWithTx(ctx context.Context, tx *sqlx.Tx, contrato *entity.Contrato) error
+
+	// Delete soft deletes a contrato by ID, moving it to the trash
 	Delete(ctx context.Context, id string) error
 
+	// FindDeleted returns the soft deleted contratos, most recently deleted first
+	FindDeleted(ctx context.Context) ([]entity.Contrato, error)
+
+	// Restore brings a soft deleted contrato back; false when it is not in the trash
+	Restore(ctx context.Context, id string) (bool, error)
+
 	// CountByGestorID returns the number of contracts for a gestor
 	CountByGestorID(ctx context.Context, gestorID string) (int, error)
 }
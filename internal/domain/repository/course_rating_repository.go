@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// CourseRatingRepository defines the interface for the ratings students give courses
+type CourseRatingRepository interface {
+	// Save creates the rating of a student for a course, or replaces the one they gave before
+	Save(ctx context.Context, rating *entity.CourseRating) error
+
+	// FindByStudent returns the rating a student gave a course, nil when they did not rate it
+	FindByStudent(ctx context.Context, courseID, studentID string) (*entity.CourseRating, error)
+
+	// Summaries returns the average rating and rating count of every rated course
+	Summaries(ctx context.Context) ([]entity.CourseRatingSummary, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// EnrollmentCancellationRepository defines the interface for enrollment cancellation records
+type EnrollmentCancellationRepository interface {
+	// FindByEnrollmentID returns the cancellation record of an enrollment
+	FindByEnrollmentID(ctx context.Context, enrollmentID string) (*entity.EnrollmentCancellation, error)
+
+	// CreateWithTx records a cancellation within a transaction
+	CreateWithTx(ctx context.Context, tx *sqlx.Tx, cancellation *entity.EnrollmentCancellation) error
+
+	// UpdateRefundWithTx saves the refund outcome and split reversal of a cancellation within a transaction
+	UpdateRefundWithTx(ctx context.Context, tx *sqlx.Tx, cancellation *entity.EnrollmentCancellation) error
+}
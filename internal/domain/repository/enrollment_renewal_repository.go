@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// EnrollmentRenewalRepository defines the interface for enrollment renewal data access
+type EnrollmentRenewalRepository interface {
+	// FindByPaymentID returns the renewal linked to a payment
+	FindByPaymentID(ctx context.Context, paymentID string) (*entity.EnrollmentRenewal, error)
+
+	// FindByEnrollmentID returns all renewals of an enrollment, newest first
+	FindByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.EnrollmentRenewal, error)
+
+	// FindPendingByEnrollmentID returns the pending renewal of an enrollment, if any
+	FindPendingByEnrollmentID(ctx context.Context, enrollmentID string) (*entity.EnrollmentRenewal, error)
+
+	// FindPendingByEnrollmentIDWithTx returns the pending renewal of an enrollment within a transaction
+	FindPendingByEnrollmentIDWithTx(ctx context.Context, tx *sqlx.Tx, enrollmentID string) (*entity.EnrollmentRenewal, error)
+
+	// CreateWithTx creates a renewal within a transaction
+	CreateWithTx(ctx context.Context, tx *sqlx.Tx, renewal *entity.EnrollmentRenewal) error
+
+	// ConfirmWithTx marks a renewal as confirmed and stores the new expiration date
+	ConfirmWithTx(ctx context.Context, tx *sqlx.Tx, renewal *entity.EnrollmentRenewal) error
+
+	// UpdateStatus updates the status of a renewal
+	UpdateStatus(ctx context.Context, id, status string) error
+
+	// UpdatePaymentID points a renewal to the payment that replaced its previous one
+	UpdatePaymentID(ctx context.Context, id, paymentID string) error
+
+	// UpdatePaymentIDWithTx points a renewal to the payment that replaced its previous one within a transaction
+	UpdatePaymentIDWithTx(ctx context.Context, tx *sqlx.Tx, id, paymentID string) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// EnrollmentTransferRepository defines the interface for enrollment transfer history
+type EnrollmentTransferRepository interface {
+	// FindByEnrollmentID returns the transfer history of an enrollment, newest first
+	FindByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.EnrollmentTransfer, error)
+
+	// CreateWithTx records a transfer within a transaction
+	CreateWithTx(ctx context.Context, tx *sqlx.Tx, transfer *entity.EnrollmentTransfer) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// EvidenceRepository defines the interface for evidence file metadata access
+type EvidenceRepository interface {
+	// Create stores the metadata of an uploaded evidence file
+	Create(ctx context.Context, file *entity.EvidenceFile) error
+
+	// FindAll returns a page of evidence files matching the filter and the total count
+	FindAll(ctx context.Context, filter *entity.EvidenceFilter) ([]entity.EvidenceFile, int, error)
+
+	// FindByFilename returns an evidence file by its object name, or nil when it does not exist
+	FindByFilename(ctx context.Context, filename string) (*entity.EvidenceFile, error)
+
+	// DeleteByFilename removes the metadata of an evidence file
+	DeleteByFilename(ctx context.Context, filename string) error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// FeatureFlagRepository defines the interface for feature flag data access
+type FeatureFlagRepository interface {
+	// FindAll returns all feature flags ordered by key
+	FindAll(ctx context.Context) ([]entity.FeatureFlag, error)
+
+	// FindByKey returns a feature flag by key
+	FindByKey(ctx context.Context, key string) (*entity.FeatureFlag, error)
+
+	// Create creates a feature flag
+	Create(ctx context.Context, flag *entity.FeatureFlag) error
+
+	// Update saves the state and targeting of a feature flag
+	Update(ctx context.Context, flag *entity.FeatureFlag) error
+
+	// Delete removes a feature flag by key
+	Delete(ctx context.Context, key string) error
+}
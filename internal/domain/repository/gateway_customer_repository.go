@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// GatewayCustomerRepository defines the interface for gateway customer mapping data access
+type GatewayCustomerRepository interface {
+	// FindByDocument returns the customer of a document on a gateway, or nil when there is none
+	FindByDocument(ctx context.Context, gateway, document string) (*entity.GatewayCustomer, error)
+
+	// Create stores a mapping; a mapping already stored for the gateway and document is kept
+	Create(ctx context.Context, customer *entity.GatewayCustomer) error
+
+	// BackfillFromPayments maps the documents of past payments to the newest gateway customer
+	// they were charged as, and returns how many mappings were added
+	BackfillFromPayments(ctx context.Context) (int64, error)
+}
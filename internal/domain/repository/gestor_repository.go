@@ -2,21 +2,26 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
 )
 
 // GestorRepository defines the interface for gestor data access
 type GestorRepository interface {
-	// FindAll returns all gestores
+	// FindAll returns all active gestores; deleted ones are excluded by every lookup below
 	FindAll(ctx context.Context) ([]entity.Gestor, error)
 
 	// FindByID returns a gestor by ID
 	FindByID(ctx context.Context, id string) (*entity.Gestor, error)
 
-	// FindByEmail returns a gestor by email
+	// FindByEmail returns a gestor by email, including a deleted one so the email is not reused
 	FindByEmail(ctx context.Context, email string) (*entity.Gestor, error)
 
+	// FindByUserID returns the gestor linked to a user account, including a deleted one so the
+	// account is not linked twice
+	FindByUserID(ctx context.Context, userID string) (*entity.Gestor, error)
+
 	// FindAllWithContracts returns all gestores with their contract counts
 	FindAllWithContracts(ctx context.Context) ([]entity.GestorWithContracts, error)
 
@@ -26,6 +31,20 @@ type GestorRepository interface {
 	// Update updates an existing gestor
 	Update(ctx context.Context, gestor *entity.Gestor) error
 
-	// Delete deletes a gestor by ID
+	// Delete soft deletes a gestor by ID, moving it to the trash
 	Delete(ctx context.Context, id string) error
+
+	// FindDeleted returns the soft deleted gestores, most recently deleted first
+	FindDeleted(ctx context.Context) ([]entity.Gestor, error)
+
+	// Restore brings a soft deleted gestor back; false when it is not in the trash
+	Restore(ctx context.Context, id string) (bool, error)
+
+	// FindContractMetrics returns audit, task and inspection counts for each contract of a gestor
+	// within [from, to), measured against now
+	FindContractMetrics(ctx context.Context, gestorID string, from, to, now time.Time) ([]entity.GestorContractMetrics, error)
+
+	// Reassign moves the contracts, open tasks and scheduled inspections of a gestor to
+	// another one in a single transaction
+	Reassign(ctx context.Context, fromID, toID string) (*entity.GestorReassignment, error)
 }
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// IdempotencyRepository defines the interface for idempotency key data access
+type IdempotencyRepository interface {
+	// Reserve stores a new in-progress key valid for ttl. It reports false when the key
+	// already exists; expired keys and keys left in progress for longer than staleAfter are
+	// replaced.
+	Reserve(ctx context.Context, key *entity.IdempotencyKey, ttl, staleAfter time.Duration) (bool, error)
+
+	// FindByID returns a key, or nil when it does not exist
+	FindByID(ctx context.Context, id string) (*entity.IdempotencyKey, error)
+
+	// Complete stores the response of a reserved key
+	Complete(ctx context.Context, key *entity.IdempotencyKey) error
+
+	// Delete releases a key so the request can be retried
+	Delete(ctx context.Context, id string) error
+
+	// DeleteExpired removes the expired keys and returns how many were removed
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// InstructorLedgerRepository defines the interface for instructor ledger data access
+type InstructorLedgerRepository interface {
+	// Create records an entry; an entry whose key was already recorded is ignored
+	Create(ctx context.Context, entry *entity.InstructorLedgerEntry) error
+
+	// CreateWithTx records an entry within a transaction; an entry whose key was already recorded is ignored
+	CreateWithTx(ctx context.Context, tx *sqlx.Tx, entry *entity.InstructorLedgerEntry) error
+
+	// FindByInstructor returns the entries of an instructor in chronological order, optionally
+	// limited to [from, to)
+	FindByInstructor(ctx context.Context, instructorID string, from, to *time.Time) ([]entity.InstructorLedgerEntry, error)
+
+	// GetBalance returns the balance of an instructor from the entries that occurred before a date
+	GetBalance(ctx context.Context, instructorID string, before time.Time) (float64, error)
+
+	// GetTotals returns the totals per entry type of every entry of an instructor
+	GetTotals(ctx context.Context, instructorID string) (*entity.InstructorLedgerTotals, error)
+
+	// GetSplitEarnings returns what is still credited for a split: its credit net of refunds,
+	// chargebacks and adjustments, ignoring payouts
+	GetSplitEarnings(ctx context.Context, splitID string) (float64, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// InstructorPayoutAccountRepository defines the interface for instructor payout account data access
+type InstructorPayoutAccountRepository interface {
+	// FindByInstructorID returns the payout account of an instructor
+	FindByInstructorID(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error)
+
+	// Save creates or replaces the payout account of an instructor
+	Save(ctx context.Context, account *entity.InstructorPayoutAccount) error
+
+	// UpdateVerification stores the verification of an account read with the given number of
+	// attempts; it returns false, storing nothing, when another request changed them meanwhile
+	UpdateVerification(ctx context.Context, account *entity.InstructorPayoutAccount, attempts int) (bool, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// LegacyUsageRepository defines the interface for legacy router usage counters
+type LegacyUsageRepository interface {
+	// Add adds the counts to the stored daily totals
+	Add(ctx context.Context, usage []entity.LegacyUsage) error
+
+	// Summary totals the usage of every endpoint since the given day, most used first
+	Summary(ctx context.Context, since time.Time) ([]entity.LegacyUsageSummary, error)
+}
@@ -4,17 +4,24 @@ import (
 	"context"
 
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
 	"github.com/jmoiron/sqlx"
 )
 
 // MatriculaRepository defines the interface for matricula data access
 type MatriculaRepository interface {
-	// FindAll returns all matriculas with pagination
-	FindAll(ctx context.Context, page, perPage int) ([]entity.Matricula, int, error)
+	// FindAll returns the matriculas matching the list query, with pagination
+	FindAll(ctx context.Context, page, perPage int, query listquery.Query) ([]entity.Matricula, int, error)
+
+	// Search returns the matriculas matching a search, most recent first, with pagination
+	Search(ctx context.Context, filter *entity.EnrollmentSearchFilter) ([]entity.Matricula, int, error)
 
 	// FindByID returns a matricula by ID
 	FindByID(ctx context.Context, id string) (*entity.Matricula, error)
 
+	// FindByIDForUpdateWithTx returns a matricula by ID and locks its row until the transaction ends
+	FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.Matricula, error)
+
 	// FindByStudentID returns all matriculas for a specific student
 	FindByStudentID(ctx context.Context, studentID string) ([]entity.Matricula, error)
 
@@ -42,6 +49,17 @@ type MatriculaRepository interface {
 	// UpdatePaymentStatusWithTx updates payment status within a transaction
 	UpdatePaymentStatusWithTx(ctx context.Context, tx *sqlx.Tx, id, paymentStatus string) error
 
+	// ClaimCancellationWithTx moves an enrollment to cancelling within a transaction.
+	// It returns false when the enrollment is already cancelled or being cancelled.
+	ClaimCancellationWithTx(ctx context.Context, tx *sqlx.Tx, id string) (bool, error)
+
+	// ClaimRetry moves a pending or expired enrollment whose payment is still open to retrying.
+	// It returns false when another retry claimed it first or it is no longer retriable.
+	ClaimRetry(ctx context.Context, id string) (bool, error)
+
+	// ReleaseRetry gives an enrollment claimed by a retry back its previous status
+	ReleaseRetry(ctx context.Context, id, status string) error
+
 	// UpdateStatus updates the enrollment status
 	UpdateStatus(ctx context.Context, id, status string) error
 
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// NotificationDeliveryRepository defines the interface for the outbound deliveries of
+// notifications and the channel preferences of users
+type NotificationDeliveryRepository interface {
+	// CreateDeliveries queues deliveries
+	CreateDeliveries(ctx context.Context, deliveries []entity.NotificationDelivery) error
+
+	// ClaimDue marks up to limit pending deliveries whose next attempt is due, or sending
+	// deliveries claimed before staleBefore, as sending and returns them
+	ClaimDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.NotificationDelivery, error)
+
+	// UpdateDelivery saves the status, attempts, next attempt, error and sent time of a delivery
+	UpdateDelivery(ctx context.Context, d *entity.NotificationDelivery) error
+
+	// FindByNotificationID returns the deliveries of a notification
+	FindByNotificationID(ctx context.Context, notificationID string) ([]entity.NotificationDelivery, error)
+
+	// MarkDelivered records when the recipient confirmed the delivery of a notification on a
+	// channel, keeping the first confirmation
+	MarkDelivered(ctx context.Context, notificationID, channel string, at time.Time) error
+
+	// MarkOpened records when the recipient opened a notification on a channel, keeping the
+	// first open, which also confirms the delivery
+	MarkOpened(ctx context.Context, notificationID, channel string, at time.Time) error
+
+	// FindChannelPreferences returns the stored channel preferences of a user
+	FindChannelPreferences(ctx context.Context, userID string) ([]entity.NotificationChannelPreference, error)
+
+	// SaveChannelPreferences creates or replaces channel preferences of a user
+	SaveChannelPreferences(ctx context.Context, userID string, prefs []entity.NotificationChannelPreference) error
+}
+
+// NotificationBroadcastRepository defines the interface for notification broadcast data access
+type NotificationBroadcastRepository interface {
+	// Create creates a new broadcast
+	Create(ctx context.Context, b *entity.NotificationBroadcast) error
+
+	// SetRecipients saves the number of users a broadcast was sent to
+	SetRecipients(ctx context.Context, id string, recipients int) error
+
+	// FindByID returns a broadcast by ID, or nil if it does not exist
+	FindByID(ctx context.Context, id string) (*entity.NotificationBroadcast, error)
+
+	// FindRecent returns the latest broadcasts, newest first
+	FindRecent(ctx context.Context, limit int) ([]entity.NotificationBroadcast, error)
+
+	// ChannelStats aggregates the receipts of the notifications of a broadcast: in-app first,
+	// then each outbound channel it was queued on
+	ChannelStats(ctx context.Context, id string) ([]entity.BroadcastChannelStats, error)
+}
+
+// PushSubscriptionRepository defines the interface for web push subscription data access
+type PushSubscriptionRepository interface {
+	// Save stores a subscription, replacing the one with the same endpoint
+	Save(ctx context.Context, sub *entity.PushSubscription) error
+
+	// FindByUserID returns the subscriptions of a user
+	FindByUserID(ctx context.Context, userID string) ([]entity.PushSubscription, error)
+
+	// Delete removes a subscription of a user; returns false when there is none
+	Delete(ctx context.Context, userID, id string) (bool, error)
+
+	// DeleteByEndpoint removes the subscription of an endpoint the push service dropped
+	DeleteByEndpoint(ctx context.Context, endpoint string) error
+}
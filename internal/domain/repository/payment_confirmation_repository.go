@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// PaymentConfirmationRepository defines the interface for manual payment confirmation data access
+type PaymentConfirmationRepository interface {
+	// Create records a manual confirmation; a payment has at most one
+	Create(ctx context.Context, confirmation *entity.PaymentConfirmation) error
+
+	// Delete removes a manual confirmation whose payment could not be confirmed
+	Delete(ctx context.Context, id string) error
+
+	// FindByPaymentID returns the manual confirmation of a payment, nil when it has none
+	FindByPaymentID(ctx context.Context, paymentID string) (*entity.PaymentConfirmation, error)
+}
@@ -5,15 +5,23 @@ import (
 	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
 	"github.com/jmoiron/sqlx"
 )
 
 // PaymentRepository defines the interface for payment data access (payments table).
 type PaymentRepository interface {
 	FindByID(ctx context.Context, id string) (*entity.Payment, error)
+	// FindByIDForUpdateWithTx returns a payment by ID and locks its row until the transaction ends
+	FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.Payment, error)
 	FindByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.Payment, error)
+	// FindInstallments returns the installments charged for a parent payment, by number
+	FindInstallments(ctx context.Context, parentID string) ([]entity.Payment, error)
 	FindByGatewayPaymentID(ctx context.Context, gateway, gatewayPaymentID string) (*entity.Payment, error)
+	FindByGatewayInstallmentID(ctx context.Context, gateway, installmentID string) (*entity.Payment, error)
 	FindAll(ctx context.Context, filters PaymentFilters) ([]entity.Payment, int, error)
+	// FindExpiredPix returns up to limit unpaid PIX payments whose QR code expired by now, oldest first
+	FindExpiredPix(ctx context.Context, now time.Time, limit int) ([]entity.Payment, error)
 	Create(ctx context.Context, payment *entity.Payment) error
 	CreateWithTx(ctx context.Context, tx *sqlx.Tx, payment *entity.Payment) error
 	Update(ctx context.Context, payment *entity.Payment) error
@@ -25,6 +33,7 @@ type PaymentRepository interface {
 // PaymentFilters holds filter parameters for listing payments.
 type PaymentFilters struct {
 	EnrollmentID string
+	PayerUserID  string
 	Gateway      string
 	Status       string
 	PaymentMethod string
@@ -32,4 +41,5 @@ type PaymentFilters struct {
 	DateTo       *time.Time
 	Page         int
 	PerPage      int
+	Query        listquery.Query // filter[...] and sort parameters
 }
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// PayoutBatchRepository defines the interface for instructor payout batch data access
+type PayoutBatchRepository interface {
+	// FindAll returns payout batches matching the filters, newest payout date first
+	FindAll(ctx context.Context, filters entity.PayoutBatchFilters) ([]entity.PayoutBatch, error)
+
+	// FindByID returns a payout batch by ID
+	FindByID(ctx context.Context, id string) (*entity.PayoutBatch, error)
+
+	// FindByIDForUpdateWithTx returns a payout batch by ID, locking the row within a transaction
+	FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.PayoutBatch, error)
+
+	// FindSplits returns the revenue splits assigned to a batch
+	FindSplits(ctx context.Context, batchID string) ([]entity.RevenueSplit, error)
+
+	// FindSplitsForUpdateWithTx returns the revenue splits assigned to a batch, locking them
+	FindSplitsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, batchID string) ([]entity.RevenueSplit, error)
+
+	// FindPayableSplitsForUpdateWithTx returns pending, unbatched splits of an instructor created
+	// before the given time (when set), locking them within a transaction
+	FindPayableSplitsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, instructorID string, createdBefore *time.Time) ([]entity.RevenueSplit, error)
+
+	// FindPayableBalances returns the pending, unbatched totals per instructor
+	FindPayableBalances(ctx context.Context) ([]entity.InstructorPayableBalance, error)
+
+	// CreateWithTx creates a payout batch within a transaction
+	CreateWithTx(ctx context.Context, tx *sqlx.Tx, batch *entity.PayoutBatch) error
+
+	// AssignSplitsWithTx links revenue splits to a batch within a transaction
+	AssignSplitsWithTx(ctx context.Context, tx *sqlx.Tx, batchID string, splitIDs []string) error
+
+	// ReleaseSplitsWithTx unlinks revenue splits from their batch within a transaction
+	ReleaseSplitsWithTx(ctx context.Context, tx *sqlx.Tx, batchID string, splitIDs []string) error
+
+	// MarkSplitsProcessedWithTx marks the pending splits of a batch as processed within a transaction
+	MarkSplitsProcessedWithTx(ctx context.Context, tx *sqlx.Tx, batchID string, processedAt time.Time) error
+
+	// UpdateWithTx updates status, totals, receipt and payment fields of a batch within a transaction
+	UpdateWithTx(ctx context.Context, tx *sqlx.Tx, batch *entity.PayoutBatch) error
+}
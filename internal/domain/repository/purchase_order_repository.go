@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// PurchaseOrderRepository defines the interface for purchase order data access
+type PurchaseOrderRepository interface {
+	// FindAll returns purchase orders matching the filters, newest first
+	FindAll(ctx context.Context, filters entity.PurchaseOrderFilters) ([]entity.PurchaseOrder, error)
+
+	// FindByID returns a purchase order by ID (without items)
+	FindByID(ctx context.Context, id string) (*entity.PurchaseOrder, error)
+
+	// FindItems returns the items of a purchase order in position order
+	FindItems(ctx context.Context, orderID string) ([]entity.PurchaseOrderItem, error)
+
+	// NextNumberWithTx returns the next sequential order number for a year (PO-YYYY-NNNNN)
+	NextNumberWithTx(ctx context.Context, tx *sqlx.Tx, year int) (string, error)
+
+	// CreateWithTx creates a purchase order within a transaction
+	CreateWithTx(ctx context.Context, tx *sqlx.Tx, order *entity.PurchaseOrder) error
+
+	// UpdateWithTx updates the description, delivery date and total of a purchase order within a
+	// transaction, only while it is still a draft; it returns false when it no longer is
+	UpdateWithTx(ctx context.Context, tx *sqlx.Tx, order *entity.PurchaseOrder) (bool, error)
+
+	// UpdateStatus moves a purchase order to its new status, only while it is still in from with
+	// the same total; it returns false when another request changed it first
+	UpdateStatus(ctx context.Context, order *entity.PurchaseOrder, from string) (bool, error)
+
+	// ReplaceItemsWithTx replaces all items of a purchase order within a transaction
+	ReplaceItemsWithTx(ctx context.Context, tx *sqlx.Tx, orderID string, items []entity.PurchaseOrderItem) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// RefreshTokenRepository defines the interface for refresh token data access
+type RefreshTokenRepository interface {
+	// Create stores a new refresh token
+	Create(ctx context.Context, token *entity.RefreshToken) error
+
+	// FindByHash returns the token with the given hash, or nil when it does not exist
+	FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+
+	// Revoke revokes a token, recording the token that replaces it when it was rotated. It
+	// reports false when the token was already revoked.
+	Revoke(ctx context.Context, id string, replacedBy *string) (bool, error)
+
+	// RevokeFamily revokes every active token of a family
+	RevokeFamily(ctx context.Context, familyID string) error
+
+	// RevokeByUser revokes every active token of a user
+	RevokeByUser(ctx context.Context, userID string) error
+
+	// DeleteExpired removes the expired tokens and returns how many were removed
+	DeleteExpired(ctx context.Context) (int64, error)
+}
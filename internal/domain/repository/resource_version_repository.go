@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// ResourceVersionRepository defines the interface for reading table versions used by
+// conditional GET requests
+type ResourceVersionRepository interface {
+	// TableVersion returns the row count and latest modification time of a table
+	TableVersion(ctx context.Context, table string) (*entity.ResourceVersion, error)
+}
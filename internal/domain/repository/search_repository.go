@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// SearchRepository defines the interface for the global search
+type SearchRepository interface {
+	// Search returns up to limit records of the search type matching every term, best ranked
+	// first
+	Search(ctx context.Context, searchType string, terms []string, limit int) ([]entity.SearchResult, error)
+}
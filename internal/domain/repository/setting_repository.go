@@ -34,4 +34,20 @@ type SettingRepository interface {
 
 	// GetCategories returns all distinct categories
 	GetCategories(ctx context.Context) ([]string, error)
+
+	// ApplyChanges writes the new values (global or overrides) and records the changes in one
+	// transaction. The old value of each change is filled in from the stored value.
+	ApplyChanges(ctx context.Context, changes []*entity.SettingChange) error
+
+	// FindChanges returns the recorded changes of a setting, newest first
+	FindChanges(ctx context.Context, key string, limit int) ([]entity.SettingChange, error)
+
+	// FindChangeByID returns a recorded setting change
+	FindChangeByID(ctx context.Context, id string) (*entity.SettingChange, error)
+
+	// FindOverrides returns the organization and contract overrides of a setting
+	FindOverrides(ctx context.Context, key string) ([]entity.SettingOverride, error)
+
+	// FindOverride returns the override of a setting for a scope, or nil when there is none
+	FindOverride(ctx context.Context, key string, scope entity.SettingScope, scopeID string) (*entity.SettingOverride, error)
 }
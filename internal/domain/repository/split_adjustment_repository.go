@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// SplitAdjustmentRepository defines the interface for revenue split adjustment data access
+type SplitAdjustmentRepository interface {
+	// FindAll returns adjustments matching the filters, newest first
+	FindAll(ctx context.Context, filters entity.SplitAdjustmentFilters) ([]entity.SplitAdjustment, error)
+
+	// FindByID returns an adjustment by ID
+	FindByID(ctx context.Context, id string) (*entity.SplitAdjustment, error)
+
+	// FindByIDForUpdateWithTx returns an adjustment by ID and locks it within a transaction
+	FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.SplitAdjustment, error)
+
+	// FindPendingBySplitWithTx returns the adjustment of a split awaiting approval, if any
+	FindPendingBySplitWithTx(ctx context.Context, tx *sqlx.Tx, splitID string) (*entity.SplitAdjustment, error)
+
+	// CreateWithTx creates an adjustment within a transaction
+	CreateWithTx(ctx context.Context, tx *sqlx.Tx, adjustment *entity.SplitAdjustment) error
+
+	// UpdateWithTx saves the decision of an adjustment within a transaction
+	UpdateWithTx(ctx context.Context, tx *sqlx.Tx, adjustment *entity.SplitAdjustment) error
+}
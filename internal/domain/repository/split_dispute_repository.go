@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// SplitDisputeRepository defines the interface for revenue split dispute data access
+type SplitDisputeRepository interface {
+	// FindAll returns disputes matching the filters, newest first
+	FindAll(ctx context.Context, filters entity.SplitDisputeFilters) ([]entity.SplitDispute, error)
+
+	// FindByID returns a dispute by ID
+	FindByID(ctx context.Context, id string) (*entity.SplitDispute, error)
+
+	// FindByIDForUpdateWithTx returns a dispute by ID and locks it within a transaction
+	FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.SplitDispute, error)
+
+	// FindActiveBySplit returns the open or under review dispute of a split, if any
+	FindActiveBySplit(ctx context.Context, splitID string) (*entity.SplitDispute, error)
+
+	// Create creates a dispute
+	Create(ctx context.Context, dispute *entity.SplitDispute) error
+
+	// UpdateWithTx saves the status and resolution of a dispute within a transaction
+	UpdateWithTx(ctx context.Context, tx *sqlx.Tx, dispute *entity.SplitDispute) error
+}
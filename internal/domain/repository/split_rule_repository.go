@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// SplitRuleRepository defines the interface for revenue split configuration data access
+type SplitRuleRepository interface {
+	// FindByScope returns the rules of a scope in order; empty when the scope has no configuration.
+	// The global scope has an empty scope ID.
+	FindByScope(ctx context.Context, scope, scopeID string) ([]entity.SplitRule, error)
+
+	// FindOverrides returns the rules of every course and instructor override, grouped by scope
+	FindOverrides(ctx context.Context) ([]entity.SplitRule, error)
+
+	// ReplaceScopeWithTx replaces the configuration of a scope within a transaction; no rules removes it
+	ReplaceScopeWithTx(ctx context.Context, tx *sqlx.Tx, scope, scopeID string, rules []entity.SplitRule) error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// SupplierContractRepository defines the interface for supplier-contract links and spend entries
+type SupplierContractRepository interface {
+	// FindByID returns a supplier-contract link by ID
+	FindByID(ctx context.Context, id string) (*entity.SupplierContract, error)
+
+	// FindBySupplierID returns the contract links of a supplier
+	FindBySupplierID(ctx context.Context, supplierID string) ([]entity.SupplierContract, error)
+
+	// FindByContratoID returns the supplier links of a contract
+	FindByContratoID(ctx context.Context, contratoID string) ([]entity.SupplierContract, error)
+
+	// FindLink returns the link of a supplier to a contract for a service category
+	FindLink(ctx context.Context, supplierID, contratoID, serviceCategory string) (*entity.SupplierContract, error)
+
+	// Create creates a new supplier-contract link
+	Create(ctx context.Context, link *entity.SupplierContract) error
+
+	// Update updates a supplier-contract link
+	Update(ctx context.Context, link *entity.SupplierContract) error
+
+	// Delete removes a supplier-contract link and its spend entries
+	Delete(ctx context.Context, id string) error
+
+	// FindSpendByLinkID returns the spend entries of a link, newest period first
+	FindSpendByLinkID(ctx context.Context, linkID string) ([]entity.SupplierSpend, error)
+
+	// CreateSpend records a spend entry
+	CreateSpend(ctx context.Context, spend *entity.SupplierSpend) error
+
+	// SumSpend returns spend totals grouped by contract or supplier and period
+	SumSpend(ctx context.Context, groupBy string, filters entity.SpendReportFilters) ([]entity.SpendAggregate, error)
+
+	// SumMonthlyBudget returns the monthly budget of active links keyed by contract or supplier ID
+	SumMonthlyBudget(ctx context.Context, groupBy string, filters entity.SpendReportFilters) (map[string]float64, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// SupplierDocumentRepository defines the interface for supplier compliance documents
+type SupplierDocumentRepository interface {
+	// FindByID returns a supplier document by ID
+	FindByID(ctx context.Context, id string) (*entity.SupplierDocument, error)
+
+	// FindBySupplierID returns the documents of a supplier, the ones expiring first on top
+	FindBySupplierID(ctx context.Context, supplierID string, includeInactive bool) ([]entity.SupplierDocument, error)
+
+	// Create creates a new supplier document
+	Create(ctx context.Context, doc *entity.SupplierDocument) error
+
+	// Update updates a supplier document
+	Update(ctx context.Context, doc *entity.SupplierDocument) error
+
+	// Delete removes a supplier document
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// SupplierEvaluationRepository defines the interface for supplier evaluation data access
+type SupplierEvaluationRepository interface {
+	// FindBySupplierID returns all evaluations of a supplier, newest period first
+	FindBySupplierID(ctx context.Context, supplierID string) ([]entity.SupplierEvaluation, error)
+
+	// FindByPeriod returns the evaluation of a supplier on a contract for a period
+	FindByPeriod(ctx context.Context, supplierID, contratoID, period string) (*entity.SupplierEvaluation, error)
+
+	// GetScores returns the averaged scores of a supplier
+	GetScores(ctx context.Context, supplierID string) (*entity.SupplierScores, error)
+
+	// GetScoresBySupplierIDs returns the averaged scores keyed by supplier ID
+	GetScoresBySupplierIDs(ctx context.Context, supplierIDs []string) (map[string]entity.SupplierScores, error)
+
+	// GetRanking returns suppliers ordered by overall score
+	GetRanking(ctx context.Context, filters entity.SupplierRankingFilters) ([]entity.SupplierRanking, error)
+
+	// Create creates a new evaluation
+	Create(ctx context.Context, evaluation *entity.SupplierEvaluation) error
+}
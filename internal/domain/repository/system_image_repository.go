@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// SystemImageRepository defines the interface for system image slot data access
+type SystemImageRepository interface {
+	// FindAll returns all slots ordered by ID
+	FindAll(ctx context.Context) ([]entity.SystemImage, error)
+
+	// FindByID returns a slot by ID, or nil when it does not exist
+	FindByID(ctx context.Context, id string) (*entity.SystemImage, error)
+
+	// FindByFilename returns the slot using a file, or nil when there is none
+	FindByFilename(ctx context.Context, filename string) (*entity.SystemImage, error)
+
+	// Create creates a slot
+	Create(ctx context.Context, image *entity.SystemImage) error
+
+	// Update saves the filename and description of a slot
+	Update(ctx context.Context, image *entity.SystemImage) error
+
+	// Delete removes a slot by ID
+	Delete(ctx context.Context, id string) error
+}
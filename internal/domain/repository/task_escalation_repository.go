@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// TaskEscalationRepository defines the interface for overdue task escalation data access
+type TaskEscalationRepository interface {
+	// FindOverdue returns the open tasks due before now that are below the last escalation level
+	FindOverdue(ctx context.Context, now time.Time) ([]entity.OverdueTask, error)
+
+	// MarkEscalated raises the escalation level of a task. It reports false when the task
+	// already reached the level, e.g. in a concurrent sweep.
+	MarkEscalated(ctx context.Context, taskID string, level int, at time.Time) (bool, error)
+
+	// Create logs an escalation
+	Create(ctx context.Context, escalation *entity.TaskEscalation) error
+
+	// Report counts the escalations per contract
+	Report(ctx context.Context, filters entity.TaskEscalationReportFilters) ([]entity.TaskEscalationReport, error)
+}
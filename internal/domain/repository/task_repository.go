@@ -29,6 +29,9 @@ type TaskRepository interface {
 	// Create creates a new task
 	Create(ctx context.Context, task *entity.Task) error
 
+	// CreateBatch creates several tasks in a single transaction
+	CreateBatch(ctx context.Context, tasks []*entity.Task) error
+
 	// Update updates an existing task
 	Update(ctx context.Context, task *entity.Task) error
 
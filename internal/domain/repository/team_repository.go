@@ -34,4 +34,8 @@ type TeamRepository interface {
 
 	// Delete removes a team member assignment by ID
 	Delete(ctx context.Context, id string) error
+
+	// FindWorkload returns open tasks, scheduled inspections and agenda commitments of every
+	// member with an active assignment in the range (optionally on one contract)
+	FindWorkload(ctx context.Context, filter *entity.TeamWorkloadFilter) ([]entity.TeamWorkload, error)
 }
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// TeamShiftRepository defines the interface for team shift data access
+type TeamShiftRepository interface {
+	// FindAll returns all shifts with optional filters
+	FindAll(ctx context.Context, filter *entity.TeamShiftFilter) ([]entity.TeamShift, error)
+
+	// FindByID returns a shift by ID
+	FindByID(ctx context.Context, id string) (*entity.TeamShift, error)
+
+	// Create creates a new shift
+	Create(ctx context.Context, shift *entity.TeamShiftDB) error
+
+	// Update updates an existing shift
+	Update(ctx context.Context, shift *entity.TeamShiftDB) error
+
+	// Delete removes a shift by ID
+	Delete(ctx context.Context, id string) error
+}
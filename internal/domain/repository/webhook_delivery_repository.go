@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// WebhookDeliveryRepository defines the interface for webhook delivery data access
+type WebhookDeliveryRepository interface {
+	// Create records a webhook delivery
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// FindAll returns the deliveries matching the filters, newest first, and the total count
+	FindAll(ctx context.Context, filters entity.WebhookDeliveryFilters) ([]entity.WebhookDelivery, int, error)
+
+	// Stats aggregates the deliveries received since the given time, per gateway
+	Stats(ctx context.Context, since time.Time) ([]entity.WebhookGatewayStats, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// WebhookEventRepository defines the interface for processed webhook event data access
+type WebhookEventRepository interface {
+	// ClaimWithTx records an event within a transaction. It reports false when the event of
+	// the gateway was already recorded; the claim is undone if the transaction rolls back.
+	ClaimWithTx(ctx context.Context, tx *sqlx.Tx, event *entity.ProcessedWebhookEvent) (bool, error)
+}
@@ -126,19 +126,14 @@ func (m *JWTManager) IsBlacklisted(tokenString string) bool {
 	return true
 }
 
-// StartBlacklistCleanup starts a background goroutine that periodically
-// removes expired entries from the token blacklist.
-func (m *JWTManager) StartBlacklistCleanup(interval time.Duration) {
-	go func() {
-		for {
-			time.Sleep(interval)
-			now := time.Now()
-			m.blacklist.Range(func(key, value interface{}) bool {
-				if now.After(value.(time.Time)) {
-					m.blacklist.Delete(key)
-				}
-				return true
-			})
+// PurgeBlacklist removes the expired entries from the token blacklist. It is run
+// periodically by the lifecycle manager.
+func (m *JWTManager) PurgeBlacklist() {
+	now := time.Now()
+	m.blacklist.Range(func(key, value interface{}) bool {
+		if now.After(value.(time.Time)) {
+			m.blacklist.Delete(key)
 		}
-	}()
+		return true
+	})
 }
@@ -0,0 +1,24 @@
+package auth
+
+import "testing"
+
+func TestGenerateRefreshToken(t *testing.T) {
+	token, hash, err := GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken() error = %v", err)
+	}
+	if len(token) != 43 {
+		t.Errorf("len(token) = %d, want 43", len(token))
+	}
+	if hash != HashRefreshToken(token) {
+		t.Error("hash does not match HashRefreshToken(token)")
+	}
+	if len(hash) != 64 {
+		t.Errorf("len(hash) = %d, want 64", len(hash))
+	}
+
+	other, _, _ := GenerateRefreshToken()
+	if other == token {
+		t.Error("GenerateRefreshToken() returned the same token twice")
+	}
+}
@@ -5,19 +5,65 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 // MySQL holds the database connection
 type MySQL struct {
 	DB *sqlx.DB
+	// Replica is the optional read replica serving stats and report queries, nil when
+	// none is configured
+	Replica *sqlx.DB
 }
 
 // NewMySQL creates a new MySQL connection
 func NewMySQL(dsn string) (*MySQL, error) {
-	db, err := sqlx.Connect("mysql", dsn)
+	db, err := connect(dsn)
 	if err != nil {
+		return nil, err
+	}
+	return &MySQL{DB: db}, nil
+}
+
+// ConnectReplica opens the read replica. Until it succeeds, Reader returns the primary.
+func (m *MySQL) ConnectReplica(dsn string) error {
+	db, err := connect(dsn)
+	if err != nil {
+		return fmt.Errorf("replica: %w", err)
+	}
+	m.Replica = db
+	return nil
+}
+
+// Reader returns the connection for reads that tolerate replication lag: the replica when
+// there is one, the primary otherwise. Writes and reads inside transactions use DB.
+func (m *MySQL) Reader() *sqlx.DB {
+	if m.Replica != nil {
+		return m.Replica
+	}
+	return m.DB
+}
+
+// connect opens a connection pool traced with OpenTelemetry: every query and transaction is a
+// span of the request running it
+func connect(dsn string) (*sqlx.DB, error) {
+	sqlDB, err := otelsql.Open("mysql", dsn,
+		otelsql.WithAttributes(semconv.DBSystemMySQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitConnPrepare:      true,
+			OmitRows:             true,
+			DisableErrSkip:       true,
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MySQL: %w", err)
+	}
+	db := sqlx.NewDb(sqlDB, "mysql")
+	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to connect to MySQL: %w", err)
 	}
 
@@ -35,14 +81,18 @@ func NewMySQL(dsn string) (*MySQL, error) {
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping MySQL: %w", err)
 	}
 
-	return &MySQL{DB: db}, nil
+	return db, nil
 }
 
-// Close closes the database connection
+// Close closes the database connections
 func (m *MySQL) Close() error {
+	if m.Replica != nil {
+		m.Replica.Close()
+	}
 	if m.DB != nil {
 		return m.DB.Close()
 	}
@@ -54,6 +104,14 @@ func (m *MySQL) Health(ctx context.Context) error {
 	return m.DB.PingContext(ctx)
 }
 
+// ReplicaHealth checks the read replica; it is nil when there is no replica
+func (m *MySQL) ReplicaHealth(ctx context.Context) error {
+	if m.Replica == nil {
+		return nil
+	}
+	return m.Replica.PingContext(ctx)
+}
+
 // BeginTx starts a new transaction
 func (m *MySQL) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 	return m.DB.BeginTxx(ctx, nil)
@@ -0,0 +1,237 @@
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/ai"
+	"github.com/condotrack/api/pkg/sse"
+)
+
+const (
+	defaultModel   = "claude-3-5-haiku-latest"
+	defaultBaseURL = "https://api.anthropic.com/v1"
+	apiVersion     = "2023-06-01"
+)
+
+// Provider implements ai.AIProvider for the Anthropic Messages API.
+type Provider struct {
+	mu         sync.RWMutex
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewProvider creates an Anthropic provider; an empty model uses claude-3-5-haiku-latest.
+func NewProvider(apiKey, model string) *Provider {
+	if model == "" {
+		model = defaultModel
+	}
+	return &Provider{
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetAPIKey replaces the API key used by subsequent requests (rotation from settings)
+func (p *Provider) SetAPIKey(apiKey string) {
+	p.mu.Lock()
+	p.apiKey = apiKey
+	p.mu.Unlock()
+}
+
+func (p *Provider) currentAPIKey() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.apiKey
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() string { return "anthropic" }
+
+// Model returns the model requests are sent to.
+func (p *Provider) Model() string { return p.model }
+
+// Available reports whether an API key is configured.
+func (p *Provider) Available() bool { return p.currentAPIKey() != "" }
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type messagesRequest struct {
+	Model       string    `json:"model"`
+	MaxTokens   int       `json:"max_tokens"`
+	System      string    `json:"system,omitempty"`
+	Messages    []message `json:"messages"`
+	Temperature float64   `json:"temperature"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+type messagesUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type messagesResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage messagesUsage `json:"usage"`
+}
+
+// streamEvent covers the stream events used here: message_start, content_block_delta,
+// message_delta and error
+type streamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Usage messagesUsage `json:"usage"`
+	} `json:"message"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Usage messagesUsage `json:"usage"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *Provider) newRequest(ctx context.Context, req ai.Request, stream bool) (*http.Request, error) {
+	body := messagesRequest{
+		Model:       p.model,
+		MaxTokens:   req.MaxTokens,
+		System:      req.System,
+		Messages:    []message{{Role: "user", Content: req.Prompt}},
+		Temperature: req.Temperature,
+		Stream:      stream,
+	}
+	if body.MaxTokens <= 0 {
+		body.MaxTokens = ai.DefaultMaxTokens
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: failed to build request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/messages", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.currentAPIKey())
+	httpReq.Header.Set("anthropic-version", apiVersion)
+	return httpReq, nil
+}
+
+// Generate returns the complete answer.
+func (p *Provider) Generate(ctx context.Context, req ai.Request) (*ai.Response, error) {
+	httpReq, err := p.newRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("anthropic: API error (status %d): %s", resp.StatusCode, truncate(body))
+	}
+
+	var result messagesResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("anthropic: failed to parse response: %w", err)
+	}
+	text := ""
+	for _, c := range result.Content {
+		if c.Type == "text" {
+			text += c.Text
+		}
+	}
+	if text == "" {
+		return nil, fmt.Errorf("anthropic: empty response")
+	}
+
+	return &ai.Response{
+		Text:     text,
+		Provider: p.Name(),
+		Model:    p.model,
+		Usage:    ai.Usage{InputTokens: result.Usage.InputTokens, OutputTokens: result.Usage.OutputTokens},
+	}, nil
+}
+
+// Stream relays the answer as Anthropic generates it.
+func (p *Provider) Stream(ctx context.Context, req ai.Request, onChunk func(text string) error) (*ai.Response, error) {
+	httpReq, err := p.newRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// No client timeout: the caller's context bounds the whole stream
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("anthropic: API error (status %d): %s", resp.StatusCode, truncate(body))
+	}
+
+	out := &ai.Response{Provider: p.Name(), Model: p.model}
+	err = sse.Read(resp.Body, func(e sse.Event) error {
+		var ev streamEvent
+		if err := json.Unmarshal([]byte(e.Data), &ev); err != nil {
+			return nil
+		}
+		switch ev.Type {
+		case "message_start":
+			out.Usage.InputTokens = ev.Message.Usage.InputTokens
+		case "message_delta":
+			out.Usage.OutputTokens = ev.Usage.OutputTokens
+		case "error":
+			return fmt.Errorf("%s", ev.Error.Message)
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" && ev.Delta.Text != "" {
+				out.Text += ev.Delta.Text
+				return onChunk(ev.Delta.Text)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return out, fmt.Errorf("anthropic: stream interrupted: %w", err)
+	}
+	if out.Text == "" {
+		return out, fmt.Errorf("anthropic: empty response")
+	}
+	return out, nil
+}
+
+// truncate keeps provider error bodies short enough for logs
+func truncate(body []byte) string {
+	if len(body) > 500 {
+		return string(body[:500]) + "..."
+	}
+	return string(body)
+}
@@ -0,0 +1,59 @@
+package anthropic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/ai"
+)
+
+func TestStream_RelaysChunksAndUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "key" || r.Header.Get("anthropic-version") == "" || r.URL.Path != "/messages" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message_start\n" + `data: {"type":"message_start","message":{"usage":{"input_tokens":5,"output_tokens":1}}}` + "\n\n"))
+		w.Write([]byte("event: ping\n" + `data: {"type":"ping"}` + "\n\n"))
+		w.Write([]byte("event: content_block_delta\n" + `data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Olá"}}` + "\n\n"))
+		w.Write([]byte("event: content_block_delta\n" + `data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":", mundo"}}` + "\n\n"))
+		w.Write([]byte("event: message_delta\n" + `data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":3}}` + "\n\n"))
+		w.Write([]byte("event: message_stop\n" + `data: {"type":"message_stop"}` + "\n\n"))
+	}))
+	defer srv.Close()
+
+	p := NewProvider("key", "")
+	p.baseURL = srv.URL
+
+	var chunks []string
+	resp, err := p.Stream(context.Background(), ai.Request{Prompt: "oi"}, func(text string) error {
+		chunks = append(chunks, text)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "Olá, mundo" || len(chunks) != 2 {
+		t.Errorf("unexpected answer %q from chunks %v", resp.Text, chunks)
+	}
+	if resp.Usage.InputTokens != 5 || resp.Usage.OutputTokens != 3 {
+		t.Errorf("unexpected usage %+v", resp.Usage)
+	}
+}
+
+func TestStream_ErrorEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: error\n" + `data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}` + "\n\n"))
+	}))
+	defer srv.Close()
+
+	p := NewProvider("key", "")
+	p.baseURL = srv.URL
+	if _, err := p.Stream(context.Background(), ai.Request{Prompt: "oi"}, func(string) error { return nil }); err == nil {
+		t.Fatal("expected the error event to fail the stream")
+	}
+}
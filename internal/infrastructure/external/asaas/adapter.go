@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/phone"
 )
 
 // AsaasAdapter implements gateway.PaymentGateway using the existing Asaas client.
@@ -33,7 +34,7 @@ func (a *AsaasAdapter) CreateCustomer(ctx context.Context, req gateway.CreateCus
 		Name:    req.Name,
 		Email:   req.Email,
 		CPFCnpj: req.Document,
-		Phone:   req.Phone,
+		Phone:   nationalPhone(req.Phone),
 	})
 	if err != nil {
 		return nil, err
@@ -126,7 +127,7 @@ func (a *AsaasAdapter) CreateCardPayment(ctx context.Context, req gateway.Create
 			Email:      req.HolderEmail,
 			CPFCnpj:    req.HolderDoc,
 			PostalCode: req.HolderZip,
-			Phone:      req.HolderPhone,
+			Phone:      nationalPhone(req.HolderPhone),
 		},
 		Installments: req.Installments,
 	}
@@ -171,7 +172,15 @@ func (a *AsaasAdapter) ParseWebhookEvent(ctx context.Context, headers map[string
 		return nil, fmt.Errorf("webhook event has no payment data")
 	}
 
+	// Older webhook versions carry no event ID; the event name and payment status identify
+	// a redelivery just as well
+	eventID := event.ID
+	if eventID == "" {
+		eventID = event.Event + ":" + event.Payment.ID + ":" + event.Payment.Status
+	}
+
 	canonical := &gateway.WebhookEvent{
+		EventID:          eventID,
 		GatewayEvent:     event.Event,
 		GatewayName:      "asaas",
 		PaymentID:        event.Payment.ID,
@@ -183,6 +192,10 @@ func (a *AsaasAdapter) ParseWebhookEvent(ctx context.Context, headers map[string
 		BillingType:      a.normalizeBillingType(event.Payment.BillingType),
 		ExternalRef:      event.Payment.ExternalReference,
 		RawPayload:       body,
+		Settled:          isPaymentSettled(event.Payment.Status),
+
+		InstallmentID:     event.Payment.Installment,
+		InstallmentNumber: event.Payment.InstallmentNumber,
 	}
 
 	// Map event type
@@ -210,6 +223,104 @@ func (a *AsaasAdapter) ValidateWebhookSignature(ctx context.Context, headers map
 	return token == a.webhookToken
 }
 
+// CreateTransfer sends an amount from the Asaas balance to a wallet, PIX key or bank account.
+func (a *AsaasAdapter) CreateTransfer(ctx context.Context, req gateway.TransferRequest) (*gateway.TransferResponse, error) {
+	asaasReq := &CreateTransferRequest{
+		Value:             req.Amount,
+		Description:       req.Description,
+		ExternalReference: req.ExternalReference,
+	}
+	switch {
+	case req.WalletID != "":
+		asaasReq.WalletID = req.WalletID
+	case req.PixKey != "":
+		asaasReq.OperationType = "PIX"
+		asaasReq.PixAddressKey = req.PixKey
+		asaasReq.PixAddressKeyType = req.PixKeyType
+	case req.BankAccount != nil:
+		asaasReq.OperationType = "TED"
+		asaasReq.BankAccount = &TransferBankAccount{
+			Bank:            TransferBank{Code: req.BankAccount.BankCode},
+			OwnerName:       req.BankAccount.OwnerName,
+			CPFCnpj:         req.BankAccount.OwnerDocument,
+			Agency:          req.BankAccount.Agency,
+			Account:         req.BankAccount.Account,
+			AccountDigit:    req.BankAccount.AccountDigit,
+			BankAccountType: req.BankAccount.AccountType,
+		}
+	default:
+		return nil, fmt.Errorf("transfer has no destination")
+	}
+
+	resp, err := a.client.CreateTransfer(ctx, asaasReq)
+	if err != nil {
+		return nil, err
+	}
+	return a.toCanonicalTransfer(resp), nil
+}
+
+// GetTransfer retrieves a transfer from Asaas.
+func (a *AsaasAdapter) GetTransfer(ctx context.Context, gatewayTransferID string) (*gateway.TransferResponse, error) {
+	resp, err := a.client.GetTransfer(ctx, gatewayTransferID)
+	if err != nil {
+		return nil, err
+	}
+	return a.toCanonicalTransfer(resp), nil
+}
+
+// ParseTransferEvent parses an Asaas TRANSFER_* webhook. Payment events return nil.
+func (a *AsaasAdapter) ParseTransferEvent(ctx context.Context, body []byte) (*gateway.TransferEvent, error) {
+	var event TransferWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse Asaas webhook: %w", err)
+	}
+	if event.Transfer == nil {
+		return nil, nil
+	}
+
+	return &gateway.TransferEvent{
+		GatewayEvent: event.Event,
+		GatewayName:  "asaas",
+		Transfer:     *a.toCanonicalTransfer(event.Transfer),
+		RawPayload:   body,
+	}, nil
+}
+
+// NormalizeTransferStatus translates Asaas transfer status to canonical status.
+func (a *AsaasAdapter) NormalizeTransferStatus(asaasStatus string) string {
+	switch asaasStatus {
+	case TransferStatusPending:
+		return gateway.TransferStatusPending
+	case TransferStatusBankProcessing:
+		return gateway.TransferStatusProcessing
+	case TransferStatusDone:
+		return gateway.TransferStatusDone
+	case TransferStatusCancelled:
+		return gateway.TransferStatusCancelled
+	default:
+		return gateway.TransferStatusFailed
+	}
+}
+
+// toCanonicalTransfer converts Asaas TransferResponse to canonical format.
+func (a *AsaasAdapter) toCanonicalTransfer(resp *TransferResponse) *gateway.TransferResponse {
+	result := &gateway.TransferResponse{
+		GatewayTransferID: resp.ID,
+		Status:            a.NormalizeTransferStatus(resp.Status),
+		GatewayRawStatus:  resp.Status,
+		Amount:            resp.Value,
+		Fee:               resp.TransferFee,
+		FailReason:        resp.FailReason,
+		ExternalReference: resp.ExternalReference,
+	}
+	if resp.EffectiveDate != "" {
+		if t, err := ParseAsaasDate(resp.EffectiveDate); err == nil {
+			result.EffectiveDate = &t
+		}
+	}
+	return result
+}
+
 // GetFees returns the gateway fee configuration.
 func (a *AsaasAdapter) GetFees() gateway.GatewayFees {
 	return a.fees
@@ -282,6 +393,7 @@ func (a *AsaasAdapter) toCanonicalPayment(resp *PaymentResponse) *gateway.Paymen
 		InvoiceURL:            resp.InvoiceURL,
 		BoletoURL:             resp.BankSlipURL,
 		TransactionReceiptURL: resp.TransactionReceiptURL,
+		InstallmentID:         resp.Installment,
 	}
 
 	// Parse confirmed date
@@ -300,3 +412,10 @@ func (a *AsaasAdapter) toCanonicalPayment(resp *PaymentResponse) *gateway.Paymen
 
 	return result
 }
+
+// nationalPhone returns a phone as the digits Asaas expects, the area code followed by the
+// number, dropping the +55 of E.164 numbers
+func nationalPhone(number string) string {
+	area, local := phone.AreaCode(number)
+	return area + local
+}
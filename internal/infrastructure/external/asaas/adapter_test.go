@@ -1,6 +1,10 @@
 package asaas
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/condotrack/api/internal/domain/gateway"
@@ -481,3 +485,153 @@ func TestAPIError_NoErrors(t *testing.T) {
 		t.Errorf("APIError.Error() = %q, want %q", apiErr.Error(), "unknown Asaas API error")
 	}
 }
+
+// --- Transfer tests ---
+
+func TestNormalizeTransferStatus(t *testing.T) {
+	a := newTestAsaasAdapter()
+	tests := map[string]string{
+		"PENDING":         gateway.TransferStatusPending,
+		"BANK_PROCESSING": gateway.TransferStatusProcessing,
+		"DONE":            gateway.TransferStatusDone,
+		"CANCELLED":       gateway.TransferStatusCancelled,
+		"FAILED":          gateway.TransferStatusFailed,
+		"SOMETHING_NEW":   gateway.TransferStatusFailed,
+	}
+	for raw, want := range tests {
+		if got := a.NormalizeTransferStatus(raw); got != want {
+			t.Errorf("NormalizeTransferStatus(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestCreateTransfer_PixRequest(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/transfers" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{"id":"tra_1","value":70,"transferFee":0,"status":"PENDING","externalReference":"split-1"}`))
+	}))
+	defer server.Close()
+
+	a := NewAsaasAdapter(NewClient("key", server.URL, nil), gateway.GatewayFees{}, "")
+	resp, err := a.CreateTransfer(context.Background(), gateway.TransferRequest{
+		Amount:            70,
+		PixKey:            "instrutor@example.com",
+		PixKeyType:        "EMAIL",
+		ExternalReference: "split-1",
+	})
+	if err != nil {
+		t.Fatalf("CreateTransfer: unexpected error %v", err)
+	}
+
+	if received["operationType"] != "PIX" || received["pixAddressKey"] != "instrutor@example.com" || received["pixAddressKeyType"] != "EMAIL" {
+		t.Errorf("request body = %v, want a PIX transfer", received)
+	}
+	if _, ok := received["walletId"]; ok {
+		t.Error("walletId should be omitted for PIX transfers")
+	}
+	if resp.GatewayTransferID != "tra_1" || resp.Status != gateway.TransferStatusPending || resp.ExternalReference != "split-1" {
+		t.Errorf("CreateTransfer = %+v", resp)
+	}
+}
+
+func TestCreateTransfer_NoDestination(t *testing.T) {
+	a := newTestAsaasAdapter()
+	if _, err := a.CreateTransfer(context.Background(), gateway.TransferRequest{Amount: 10}); err == nil {
+		t.Error("expected error for transfer without destination")
+	}
+}
+
+func TestParseTransferEvent(t *testing.T) {
+	a := newTestAsaasAdapter()
+
+	body := []byte(`{"event":"TRANSFER_DONE","transfer":{"id":"tra_1","value":70,"status":"DONE","effectiveDate":"2026-03-02"}}`)
+	event, err := a.ParseTransferEvent(context.Background(), body)
+	if err != nil || event == nil {
+		t.Fatalf("ParseTransferEvent = %v, %v", event, err)
+	}
+	if event.GatewayEvent != "TRANSFER_DONE" || event.Transfer.Status != gateway.TransferStatusDone {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Transfer.EffectiveDate == nil || event.Transfer.EffectiveDate.Format("2006-01-02") != "2026-03-02" {
+		t.Errorf("EffectiveDate = %v, want 2026-03-02", event.Transfer.EffectiveDate)
+	}
+
+	payment := []byte(`{"event":"PAYMENT_RECEIVED","payment":{"id":"pay_1","status":"RECEIVED"}}`)
+	event, err = a.ParseTransferEvent(context.Background(), payment)
+	if err != nil || event != nil {
+		t.Errorf("ParseTransferEvent(payment) = %v, %v; want nil, nil", event, err)
+	}
+}
+
+func TestParseWebhookEvent_Settled(t *testing.T) {
+	a := newTestAsaasAdapter()
+	tests := map[string]bool{
+		"RECEIVED":         true,
+		"RECEIVED_IN_CASH": true,
+		"CONFIRMED":        false,
+	}
+	for status, want := range tests {
+		body := []byte(`{"event":"PAYMENT_CONFIRMED","payment":{"id":"pay_1","status":"` + status + `"}}`)
+		event, err := a.ParseWebhookEvent(context.Background(), nil, body)
+		if err != nil {
+			t.Fatalf("ParseWebhookEvent: unexpected error %v", err)
+		}
+		if event.Settled != want {
+			t.Errorf("status %s: Settled = %v, want %v", status, event.Settled, want)
+		}
+	}
+}
+
+func TestParseWebhookEvent_EventID(t *testing.T) {
+	a := newTestAsaasAdapter()
+
+	body := []byte(`{"id":"evt_05b708f961d739ea7eba7e4db318f621","event":"PAYMENT_RECEIVED","payment":{"id":"pay_1","status":"RECEIVED"}}`)
+	event, err := a.ParseWebhookEvent(context.Background(), nil, body)
+	if err != nil {
+		t.Fatalf("ParseWebhookEvent: unexpected error %v", err)
+	}
+	if event.EventID != "evt_05b708f961d739ea7eba7e4db318f621" {
+		t.Errorf("EventID = %q, want the webhook id", event.EventID)
+	}
+
+	body = []byte(`{"event":"PAYMENT_RECEIVED","payment":{"id":"pay_1","status":"RECEIVED"}}`)
+	event, err = a.ParseWebhookEvent(context.Background(), nil, body)
+	if err != nil {
+		t.Fatalf("ParseWebhookEvent: unexpected error %v", err)
+	}
+	if event.EventID != "PAYMENT_RECEIVED:pay_1:RECEIVED" {
+		t.Errorf("EventID = %q, want PAYMENT_RECEIVED:pay_1:RECEIVED", event.EventID)
+	}
+}
+
+func TestParseWebhookEvent_Installment(t *testing.T) {
+	a := newTestAsaasAdapter()
+
+	body := []byte(`{"event":"PAYMENT_RECEIVED","payment":{"id":"pay_3","status":"RECEIVED","billingType":"CREDIT_CARD","installment":"ins_1","installmentNumber":3}}`)
+	event, err := a.ParseWebhookEvent(context.Background(), nil, body)
+	if err != nil {
+		t.Fatalf("ParseWebhookEvent: unexpected error %v", err)
+	}
+	if event.InstallmentID != "ins_1" || event.InstallmentNumber != 3 {
+		t.Errorf("installment = %q #%d, want ins_1 #3", event.InstallmentID, event.InstallmentNumber)
+	}
+}
+
+func TestNationalPhone(t *testing.T) {
+	for in, want := range map[string]string{
+		"+5511987654321":  "11987654321",
+		"(11) 3333-4444":  "1133334444",
+		"":                "",
+		"+1 415 555 0100": "14155550100",
+	} {
+		if got := nationalPhone(in); got != want {
+			t.Errorf("nationalPhone(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
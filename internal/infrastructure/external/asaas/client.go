@@ -7,67 +7,84 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
+	"sync"
+
+	"github.com/condotrack/api/internal/infrastructure/external/resilience"
 )
 
 // Client represents the Asaas API client
 type Client struct {
+	mu         sync.RWMutex
 	apiKey     string
 	baseURL    string
-	httpClient *http.Client
+	httpClient *resilience.Client
 }
 
-// NewClient creates a new Asaas API client
-func NewClient(apiKey, baseURL string) *Client {
+// NewClient creates a new Asaas API client. A nil httpClient uses the default timeouts,
+// retries and circuit breaker.
+func NewClient(apiKey, baseURL string, httpClient *resilience.Client) *Client {
+	if httpClient == nil {
+		httpClient = resilience.NewClient("asaas", resilience.DefaultConfig())
+	}
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		httpClient: httpClient,
 	}
 }
 
-// doRequest performs an HTTP request to the Asaas API
+// SetAPIKey replaces the API key used by subsequent requests (key rotation from settings)
+func (c *Client) SetAPIKey(apiKey string) {
+	c.mu.Lock()
+	c.apiKey = apiKey
+	c.mu.Unlock()
+}
+
+func (c *Client) currentAPIKey() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.apiKey
+}
+
+// doRequest performs an HTTP request to the Asaas API. Asaas takes no idempotency key, so
+// only throttled POSTs are retried: a POST that failed with a 5xx may have created the charge.
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
-	var bodyReader io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewBuffer(jsonBody)
 	}
 
 	url := c.baseURL + path
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("access_token", c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	resp, err := c.httpClient.Do(ctx, method != http.MethodPost, func(ctx context.Context) (*http.Request, error) {
+		var bodyReader io.Reader
+		if jsonBody != nil {
+			bodyReader = bytes.NewReader(jsonBody)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("access_token", c.currentAPIKey())
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
 	if resp.StatusCode >= 400 {
 		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err == nil && len(apiErr.Errors) > 0 {
+		if err := json.Unmarshal(resp.Body, &apiErr); err == nil && len(apiErr.Errors) > 0 {
 			return nil, &apiErr
 		}
-		return nil, fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("API error: status %d, body: %s", resp.StatusCode, string(resp.Body))
 	}
 
-	return respBody, nil
+	return resp.Body, nil
 }
 
 // get performs a GET request
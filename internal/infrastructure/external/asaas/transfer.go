@@ -0,0 +1,37 @@
+package asaas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// CreateTransfer sends money from the Asaas balance to another Asaas wallet, a PIX key or a bank account
+func (c *Client) CreateTransfer(ctx context.Context, req *CreateTransferRequest) (*TransferResponse, error) {
+	respBody, err := c.post(ctx, "/transfers", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var transfer TransferResponse
+	if err := json.Unmarshal(respBody, &transfer); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transfer response: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+// GetTransfer retrieves a transfer by ID
+func (c *Client) GetTransfer(ctx context.Context, transferID string) (*TransferResponse, error) {
+	respBody, err := c.get(ctx, "/transfers/"+transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	var transfer TransferResponse
+	if err := json.Unmarshal(respBody, &transfer); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transfer response: %w", err)
+	}
+
+	return &transfer, nil
+}
@@ -126,6 +126,8 @@ type PaymentResponse struct {
 	InvoiceNumber     string      `json:"invoiceNumber,omitempty"`
 	PixQRCode         *PixQRCode  `json:"pix,omitempty"`
 	TransactionReceiptURL string  `json:"transactionReceiptUrl,omitempty"`
+	Installment       string      `json:"installment,omitempty"`
+	InstallmentNumber int         `json:"installmentNumber,omitempty"`
 }
 
 // PixQRCode represents PIX QR code information
@@ -154,6 +156,7 @@ const (
 
 // WebhookEvent represents an Asaas webhook event
 type WebhookEvent struct {
+	ID      string          `json:"id"`
 	Event   string          `json:"event"`
 	Payment *WebhookPayment `json:"payment,omitempty"`
 }
@@ -169,6 +172,8 @@ type WebhookPayment struct {
 	ExternalReference string  `json:"externalReference,omitempty"`
 	PaymentDate       string  `json:"paymentDate,omitempty"`
 	ConfirmedDate     string  `json:"confirmedDate,omitempty"`
+	Installment       string  `json:"installment,omitempty"`
+	InstallmentNumber int     `json:"installmentNumber,omitempty"`
 }
 
 // Webhook event types
@@ -191,6 +196,66 @@ const (
 	WebhookEventPaymentPixAddressKeyCreated = "PAYMENT_CHECKOUT_VIEWED"
 )
 
+// CreateTransferRequest represents the request to create a transfer.
+// Set WalletID for Asaas accounts, PixAddressKey for PIX or BankAccount for TED.
+type CreateTransferRequest struct {
+	Value             float64              `json:"value"`
+	WalletID          string               `json:"walletId,omitempty"`
+	OperationType     string               `json:"operationType,omitempty"` // PIX, TED
+	PixAddressKey     string               `json:"pixAddressKey,omitempty"`
+	PixAddressKeyType string               `json:"pixAddressKeyType,omitempty"` // CPF, CNPJ, EMAIL, PHONE, EVP
+	BankAccount       *TransferBankAccount `json:"bankAccount,omitempty"`
+	Description       string               `json:"description,omitempty"`
+	ExternalReference string               `json:"externalReference,omitempty"`
+}
+
+// TransferBankAccount represents the destination bank account of a transfer
+type TransferBankAccount struct {
+	Bank            TransferBank `json:"bank"`
+	OwnerName       string       `json:"ownerName"`
+	CPFCnpj         string       `json:"cpfCnpj"`
+	Agency          string       `json:"agency"`
+	Account         string       `json:"account"`
+	AccountDigit    string       `json:"accountDigit"`
+	BankAccountType string       `json:"bankAccountType,omitempty"` // CONTA_CORRENTE, CONTA_POUPANCA
+}
+
+// TransferBank identifies the destination bank by its COMPE code
+type TransferBank struct {
+	Code string `json:"code"`
+}
+
+// TransferResponse represents a transfer response from Asaas
+type TransferResponse struct {
+	ID                    string  `json:"id"`
+	DateCreated           string  `json:"dateCreated"`
+	Value                 float64 `json:"value"`
+	NetValue              float64 `json:"netValue"`
+	TransferFee           float64 `json:"transferFee"`
+	Status                string  `json:"status"`
+	EffectiveDate         string  `json:"effectiveDate,omitempty"`
+	FailReason            string  `json:"failReason,omitempty"`
+	WalletID              string  `json:"walletId,omitempty"`
+	OperationType         string  `json:"operationType,omitempty"`
+	ExternalReference     string  `json:"externalReference,omitempty"`
+	TransactionReceiptURL string  `json:"transactionReceiptUrl,omitempty"`
+}
+
+// TransferStatus constants
+const (
+	TransferStatusPending        = "PENDING"
+	TransferStatusBankProcessing = "BANK_PROCESSING"
+	TransferStatusDone           = "DONE"
+	TransferStatusCancelled      = "CANCELLED"
+	TransferStatusFailed         = "FAILED"
+)
+
+// TransferWebhookEvent represents an Asaas transfer webhook event
+type TransferWebhookEvent struct {
+	Event    string            `json:"event"`
+	Transfer *TransferResponse `json:"transfer,omitempty"`
+}
+
 // CustomerListResponse represents the response when listing customers
 type CustomerListResponse struct {
 	Object     string     `json:"object"`
@@ -224,6 +289,13 @@ func IsPaymentConfirmed(status string) bool {
 		   status == PaymentStatusReceivedInCash
 }
 
+// isPaymentSettled checks if the payment amount is available in the Asaas balance.
+// Card payments are CONFIRMED first and only become RECEIVED on the credit date.
+func isPaymentSettled(status string) bool {
+	return status == PaymentStatusReceived ||
+		status == PaymentStatusReceivedInCash
+}
+
 // IsPaymentPending checks if payment is still pending
 func IsPaymentPending(status string) bool {
 	return status == PaymentStatusPending ||
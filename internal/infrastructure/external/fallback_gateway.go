@@ -0,0 +1,127 @@
+package external
+
+import (
+	"context"
+	"log"
+
+	"github.com/condotrack/api/internal/domain/gateway"
+)
+
+// Fallback returns a gateway that delegates to the current default gateway like Default, but
+// retries a failed customer or charge creation on the fallback gateway (see SetFallback).
+func (f *GatewayFactory) Fallback() gateway.PaymentGateway {
+	return &FallbackGateway{defaultGateway: &defaultGateway{factory: f}}
+}
+
+// FallbackGateway creates customers and charges on the default gateway and, when the
+// provider fails them (gateway.Retryable), on the fallback gateway. Responses name the
+// gateway that handled them, so the payment is recorded and later queried there. A charge
+// only moves when the request carries its customer, which is then found or created on the
+// fallback gateway.
+type FallbackGateway struct {
+	*defaultGateway
+}
+
+// CreateCustomer creates the customer on the default gateway, or on the fallback one when it
+// fails
+func (g *FallbackGateway) CreateCustomer(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+	gw, err := g.active()
+	if err != nil {
+		return nil, err
+	}
+	customer, err := gw.CreateCustomer(ctx, req)
+	if err == nil {
+		customer.Gateway = gw.Name()
+		return customer, nil
+	}
+	fallback := g.factory.fallbackFor(gw.Name())
+	if fallback == nil || !gateway.Retryable(err) {
+		return nil, err
+	}
+
+	log.Printf("[GATEWAYS] Customer creation failed on %s, retrying on %s: %v", gw.Name(), fallback.Name(), err)
+	customer, fallbackErr := fallback.CreateCustomer(ctx, req)
+	if fallbackErr != nil {
+		log.Printf("[GATEWAYS] Customer creation failed on fallback %s: %v", fallback.Name(), fallbackErr)
+		return nil, err
+	}
+	customer.Gateway = fallback.Name()
+	return customer, nil
+}
+
+func (g *FallbackGateway) CreatePixPayment(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+	return g.charge(ctx, req, func(gw gateway.PaymentGateway, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		return gw.CreatePixPayment(ctx, req)
+	})
+}
+
+func (g *FallbackGateway) CreateBoletoPayment(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+	return g.charge(ctx, req, func(gw gateway.PaymentGateway, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		return gw.CreateBoletoPayment(ctx, req)
+	})
+}
+
+func (g *FallbackGateway) CreateCardPayment(ctx context.Context, req gateway.CreateCardPaymentRequest) (*gateway.PaymentResponse, error) {
+	return g.charge(ctx, req.CreatePaymentRequest, func(gw gateway.PaymentGateway, base gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		card := req
+		card.CreatePaymentRequest = base
+		return gw.CreateCardPayment(ctx, card)
+	})
+}
+
+// charge creates the charge on the gateway holding the customer and, when the provider
+// fails it, moves it with its customer to the fallback gateway. The error of the first
+// attempt is returned when the fallback fails as well.
+func (g *FallbackGateway) charge(ctx context.Context, req gateway.CreatePaymentRequest,
+	create func(gateway.PaymentGateway, gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error)) (*gateway.PaymentResponse, error) {
+	gw, err := g.holding(req.CustomerGateway)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := create(gw, req)
+	if err == nil {
+		resp.Gateway = gw.Name()
+		return resp, nil
+	}
+	fallback := g.factory.fallbackFor(gw.Name())
+	if fallback == nil || req.Customer == nil || !gateway.Retryable(err) {
+		return nil, err
+	}
+
+	log.Printf("[GATEWAYS] Charge %s failed on %s, retrying on %s: %v", req.ExternalReference, gw.Name(), fallback.Name(), err)
+	customer, fallbackErr := customerOn(ctx, fallback, *req.Customer)
+	if fallbackErr != nil {
+		log.Printf("[GATEWAYS] Customer of charge %s unavailable on fallback %s: %v", req.ExternalReference, fallback.Name(), fallbackErr)
+		return nil, err
+	}
+	moved := req
+	moved.CustomerGatewayID = customer.GatewayID
+	moved.CustomerGateway = fallback.Name()
+	resp, fallbackErr = create(fallback, moved)
+	if fallbackErr != nil {
+		log.Printf("[GATEWAYS] Charge %s failed on fallback %s: %v", req.ExternalReference, fallback.Name(), fallbackErr)
+		return nil, err
+	}
+	resp.Gateway = fallback.Name()
+	resp.CustomerGatewayID = customer.GatewayID
+	return resp, nil
+}
+
+// holding returns the gateway holding a customer, the default one when name is empty
+func (g *FallbackGateway) holding(name string) (gateway.PaymentGateway, error) {
+	if name == "" {
+		return g.active()
+	}
+	return g.factory.Get(name)
+}
+
+// customerOn returns the customer of the document on gw, creating it when missing
+func customerOn(ctx context.Context, gw gateway.PaymentGateway, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+	if req.Document != "" {
+		customer, err := gw.FindCustomerByDocument(ctx, req.Document)
+		if err == nil && customer != nil {
+			return customer, nil
+		}
+	}
+	return gw.CreateCustomer(ctx, req)
+}
@@ -1,52 +1,137 @@
 package external
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/condotrack/api/internal/domain/gateway"
 )
 
-// GatewayFactory manages payment gateway instances and selects the active one.
+// GatewayFactory manages payment gateway instances and selects the active one. Gateways can be
+// replaced, deactivated and switched at runtime; deactivated gateways stay reachable by name
+// so payments already created with them can still be queried and refunded.
 type GatewayFactory struct {
-	gateways      map[string]gateway.PaymentGateway
-	activeGateway string
+	mu              sync.RWMutex
+	gateways        map[string]gateway.PaymentGateway
+	disabled        map[string]bool
+	activeGateway   string
+	fallbackGateway string
+}
+
+// GatewayStatus describes a registered gateway
+type GatewayStatus struct {
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled"`
+	Default  bool   `json:"default"`
+	Fallback bool   `json:"fallback"`
 }
 
 // NewGatewayFactory creates a new gateway factory.
 func NewGatewayFactory() *GatewayFactory {
 	return &GatewayFactory{
 		gateways: make(map[string]gateway.PaymentGateway),
+		disabled: make(map[string]bool),
 	}
 }
 
-// Register adds a gateway implementation to the factory.
+// Register adds a gateway implementation to the factory, replacing the adapter previously
+// registered under the same name.
 func (f *GatewayFactory) Register(gw gateway.PaymentGateway) {
+	f.mu.Lock()
 	f.gateways[gw.Name()] = gw
+	f.mu.Unlock()
+}
+
+// SetEnabled activates or deactivates a gateway for new payments. The default gateway cannot
+// be deactivated.
+func (f *GatewayFactory) SetEnabled(name string, enabled bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.gateways[name]; !ok {
+		return fmt.Errorf("gateway %q not registered", name)
+	}
+	if !enabled && name == f.activeGateway {
+		return fmt.Errorf("gateway %q is the default and cannot be deactivated", name)
+	}
+	f.disabled[name] = !enabled
+	return nil
 }
 
 // SetActive sets which gateway is the default for new payments.
 func (f *GatewayFactory) SetActive(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	if _, ok := f.gateways[name]; !ok {
 		return fmt.Errorf("gateway %q not registered", name)
 	}
+	if f.disabled[name] {
+		return fmt.Errorf("gateway %q is deactivated", name)
+	}
 	f.activeGateway = name
 	return nil
 }
 
+// SetFallback sets the gateway new charges are retried on when the default one fails; an
+// empty name turns the fallback off. A deactivated fallback is skipped until reactivated.
+func (f *GatewayFactory) SetFallback(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if name != "" {
+		if _, ok := f.gateways[name]; !ok {
+			return fmt.Errorf("gateway %q not registered", name)
+		}
+	}
+	f.fallbackGateway = name
+	return nil
+}
+
+// FallbackName returns the name of the fallback gateway, empty when there is none.
+func (f *GatewayFactory) FallbackName() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.fallbackGateway
+}
+
+// fallbackFor returns the gateway to retry a charge failed on the named one, or nil when
+// there is no enabled fallback other than it.
+func (f *GatewayFactory) fallbackFor(name string) gateway.PaymentGateway {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.fallbackGateway == "" || f.fallbackGateway == name || f.disabled[f.fallbackGateway] {
+		return nil
+	}
+	return f.gateways[f.fallbackGateway]
+}
+
+// ActiveName returns the name of the default gateway.
+func (f *GatewayFactory) ActiveName() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.activeGateway
+}
+
 // GetActive returns the currently active gateway.
 func (f *GatewayFactory) GetActive() gateway.PaymentGateway {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 	if gw, ok := f.gateways[f.activeGateway]; ok {
 		return gw
 	}
-	// Fallback: return any registered gateway
-	for _, gw := range f.gateways {
-		return gw
+	// Fallback: return any enabled gateway
+	for _, name := range f.names() {
+		if !f.disabled[name] {
+			return f.gateways[name]
+		}
 	}
 	return nil
 }
 
 // Get returns a specific gateway by name.
 func (f *GatewayFactory) Get(name string) (gateway.PaymentGateway, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
 	gw, ok := f.gateways[name]
 	if !ok {
 		return nil, fmt.Errorf("gateway %q not registered", name)
@@ -56,9 +141,150 @@ func (f *GatewayFactory) Get(name string) (gateway.PaymentGateway, error) {
 
 // ListRegistered returns the names of all registered gateways.
 func (f *GatewayFactory) ListRegistered() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.names()
+}
+
+// Status returns the registered gateways with their state.
+func (f *GatewayFactory) Status() []GatewayStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	names := f.names()
+	status := make([]GatewayStatus, len(names))
+	for i, name := range names {
+		status[i] = GatewayStatus{
+			Name:     name,
+			Enabled:  !f.disabled[name],
+			Default:  name == f.activeGateway,
+			Fallback: name == f.fallbackGateway,
+		}
+	}
+	return status
+}
+
+func (f *GatewayFactory) names() []string {
 	names := make([]string, 0, len(f.gateways))
 	for name := range f.gateways {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
+
+// Default returns a gateway that always delegates to the current default gateway, for
+// services built once at startup. It implements gateway.Selector so operations on an existing
+// payment can reach the gateway that created it.
+func (f *GatewayFactory) Default() gateway.PaymentGateway {
+	return &defaultGateway{factory: f}
+}
+
+type defaultGateway struct {
+	factory *GatewayFactory
+}
+
+func (d *defaultGateway) active() (gateway.PaymentGateway, error) {
+	gw := d.factory.GetActive()
+	if gw == nil {
+		return nil, fmt.Errorf("no payment gateway available")
+	}
+	return gw, nil
+}
+
+func (d *defaultGateway) For(name string) (gateway.PaymentGateway, error) {
+	return d.factory.Get(name)
+}
+
+func (d *defaultGateway) Name() string {
+	if gw := d.factory.GetActive(); gw != nil {
+		return gw.Name()
+	}
+	return ""
+}
+
+func (d *defaultGateway) CreateCustomer(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+	gw, err := d.active()
+	if err != nil {
+		return nil, err
+	}
+	return gw.CreateCustomer(ctx, req)
+}
+
+func (d *defaultGateway) FindCustomerByDocument(ctx context.Context, document string) (*gateway.CustomerResponse, error) {
+	gw, err := d.active()
+	if err != nil {
+		return nil, err
+	}
+	return gw.FindCustomerByDocument(ctx, document)
+}
+
+func (d *defaultGateway) CreatePixPayment(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+	gw, err := d.active()
+	if err != nil {
+		return nil, err
+	}
+	return gw.CreatePixPayment(ctx, req)
+}
+
+func (d *defaultGateway) CreateBoletoPayment(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+	gw, err := d.active()
+	if err != nil {
+		return nil, err
+	}
+	return gw.CreateBoletoPayment(ctx, req)
+}
+
+func (d *defaultGateway) CreateCardPayment(ctx context.Context, req gateway.CreateCardPaymentRequest) (*gateway.PaymentResponse, error) {
+	gw, err := d.active()
+	if err != nil {
+		return nil, err
+	}
+	return gw.CreateCardPayment(ctx, req)
+}
+
+func (d *defaultGateway) GetPayment(ctx context.Context, gatewayPaymentID string) (*gateway.PaymentResponse, error) {
+	gw, err := d.active()
+	if err != nil {
+		return nil, err
+	}
+	return gw.GetPayment(ctx, gatewayPaymentID)
+}
+
+func (d *defaultGateway) RefundPayment(ctx context.Context, gatewayPaymentID string, amount float64) (*gateway.PaymentResponse, error) {
+	gw, err := d.active()
+	if err != nil {
+		return nil, err
+	}
+	return gw.RefundPayment(ctx, gatewayPaymentID, amount)
+}
+
+func (d *defaultGateway) CancelPayment(ctx context.Context, gatewayPaymentID string) error {
+	gw, err := d.active()
+	if err != nil {
+		return err
+	}
+	return gw.CancelPayment(ctx, gatewayPaymentID)
+}
+
+func (d *defaultGateway) ParseWebhookEvent(ctx context.Context, headers map[string]string, body []byte) (*gateway.WebhookEvent, error) {
+	gw, err := d.active()
+	if err != nil {
+		return nil, err
+	}
+	return gw.ParseWebhookEvent(ctx, headers, body)
+}
+
+func (d *defaultGateway) ValidateWebhookSignature(ctx context.Context, headers map[string]string, body []byte) bool {
+	gw, err := d.active()
+	if err != nil {
+		return false
+	}
+	return gw.ValidateWebhookSignature(ctx, headers, body)
+}
+
+func (d *defaultGateway) GetFees() gateway.GatewayFees {
+	if gw := d.factory.GetActive(); gw != nil {
+		return gw.GetFees()
+	}
+	return gateway.GatewayFees{}
+}
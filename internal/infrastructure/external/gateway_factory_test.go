@@ -0,0 +1,156 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func namedGateway(name string) *testutil.MockGateway {
+	return &testutil.MockGateway{
+		NameFunc: func() string { return name },
+		GetPaymentFunc: func(ctx context.Context, id string) (*gateway.PaymentResponse, error) {
+			return &gateway.PaymentResponse{GatewayPaymentID: name + ":" + id}, nil
+		},
+	}
+}
+
+func TestGatewayFactory_DefaultFollowsActive(t *testing.T) {
+	f := NewGatewayFactory()
+	f.Register(namedGateway("asaas"))
+	f.Register(namedGateway("mercadopago"))
+	_ = f.SetActive("asaas")
+
+	gw := f.Default()
+	if gw.Name() != "asaas" {
+		t.Fatalf("expected asaas as default, got %q", gw.Name())
+	}
+
+	if err := f.SetActive("mercadopago"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gw.Name() != "mercadopago" {
+		t.Errorf("default gateway did not follow the switch, got %q", gw.Name())
+	}
+
+	// Existing payments still reach the gateway that created them
+	resp, err := gateway.ForPayment(gw, "asaas").GetPayment(context.Background(), "pay_1")
+	if err != nil || resp.GatewayPaymentID != "asaas:pay_1" {
+		t.Errorf("ForPayment routed to %v, %v", resp, err)
+	}
+}
+
+func TestGatewayFactory_SetEnabled(t *testing.T) {
+	f := NewGatewayFactory()
+	f.Register(namedGateway("asaas"))
+	f.Register(namedGateway("mercadopago"))
+	_ = f.SetActive("asaas")
+
+	if err := f.SetEnabled("asaas", false); err == nil {
+		t.Error("expected the default gateway to refuse deactivation")
+	}
+	if err := f.SetEnabled("mercadopago", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := f.SetActive("mercadopago"); err == nil {
+		t.Error("expected a deactivated gateway to refuse becoming the default")
+	}
+	if _, err := f.Get("mercadopago"); err != nil {
+		t.Error("deactivated gateways must stay reachable by name")
+	}
+
+	status := f.Status()
+	if len(status) != 2 || !status[0].Default || status[1].Enabled {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestGatewayFactory_RegisterReplaces(t *testing.T) {
+	f := NewGatewayFactory()
+	f.Register(namedGateway("asaas"))
+	_ = f.SetActive("asaas")
+
+	replacement := namedGateway("asaas")
+	replacement.GetFeesFunc = func() gateway.GatewayFees { return gateway.GatewayFees{BoletoFixed: 1} }
+	f.Register(replacement)
+
+	if got := f.Default().GetFees().BoletoFixed; got != 1 {
+		t.Errorf("expected the replaced adapter to serve requests, got fees %v", got)
+	}
+}
+
+func TestFallbackGateway_MovesFailedCharge(t *testing.T) {
+	f := NewGatewayFactory()
+	primary := namedGateway("asaas")
+	primary.CreatePixPaymentFunc = func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		return nil, errors.New("API error: status 503")
+	}
+	secondary := namedGateway("mercadopago")
+	secondary.CreateCustomerFunc = func(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+		return &gateway.CustomerResponse{GatewayID: "mp-cust"}, nil
+	}
+	secondary.CreatePixPaymentFunc = func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		return &gateway.PaymentResponse{GatewayPaymentID: "mp-pay:" + req.CustomerGatewayID}, nil
+	}
+	f.Register(primary)
+	f.Register(secondary)
+	_ = f.SetActive("asaas")
+	gw := f.Fallback()
+	req := gateway.CreatePaymentRequest{
+		CustomerGatewayID: "asaas-cust",
+		Customer:          &gateway.CreateCustomerRequest{Name: "Ana", Document: "52998224725"},
+	}
+
+	// Without a fallback gateway the error is returned
+	if _, err := gw.CreatePixPayment(context.Background(), req); err == nil {
+		t.Fatal("expected the charge to fail without a fallback")
+	}
+
+	if err := f.SetFallback("mercadopago"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := gw.CreatePixPayment(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Gateway != "mercadopago" || resp.CustomerGatewayID != "mp-cust" || resp.GatewayPaymentID != "mp-pay:mp-cust" {
+		t.Errorf("moved charge = %+v", resp)
+	}
+
+	// Charges without their customer, timeouts and a deactivated fallback stay put
+	noCustomer := req
+	noCustomer.Customer = nil
+	if _, err := gw.CreatePixPayment(context.Background(), noCustomer); err == nil {
+		t.Error("expected a charge without its customer not to move")
+	}
+	primary.CreatePixPaymentFunc = func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		return nil, context.DeadlineExceeded
+	}
+	if _, err := gw.CreatePixPayment(context.Background(), req); err == nil {
+		t.Error("expected a timed out charge not to move")
+	}
+	primary.CreatePixPaymentFunc = nil
+	_ = f.SetEnabled("mercadopago", false)
+	if fb := f.fallbackFor("asaas"); fb != nil {
+		t.Errorf("expected no fallback while deactivated, got %s", fb.Name())
+	}
+}
+
+func TestFallbackGateway_ChargesWhereTheCustomerIs(t *testing.T) {
+	f := NewGatewayFactory()
+	f.Register(namedGateway("asaas"))
+	secondary := namedGateway("mercadopago")
+	secondary.CreateBoletoPaymentFunc = func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		return &gateway.PaymentResponse{GatewayPaymentID: "mp-boleto"}, nil
+	}
+	f.Register(secondary)
+	_ = f.SetActive("asaas")
+
+	resp, err := f.Fallback().CreateBoletoPayment(context.Background(), gateway.CreatePaymentRequest{CustomerGateway: "mercadopago"})
+	if err != nil || resp.Gateway != "mercadopago" || resp.GatewayPaymentID != "mp-boleto" {
+		t.Errorf("boleto = %+v, %v", resp, err)
+	}
+}
@@ -0,0 +1,219 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/ai"
+	"github.com/condotrack/api/pkg/sse"
+)
+
+const (
+	defaultModel   = "gemini-1.5-flash"
+	defaultBaseURL = "https://generativelanguage.googleapis.com/v1beta/models/"
+)
+
+// Provider implements ai.AIProvider for the Google Gemini API.
+type Provider struct {
+	mu         sync.RWMutex
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewProvider creates a Gemini provider; an empty model uses gemini-1.5-flash.
+func NewProvider(apiKey, model string) *Provider {
+	if model == "" {
+		model = defaultModel
+	}
+	return &Provider{
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetAPIKey replaces the API key used by subsequent requests (rotation from settings)
+func (p *Provider) SetAPIKey(apiKey string) {
+	p.mu.Lock()
+	p.apiKey = apiKey
+	p.mu.Unlock()
+}
+
+func (p *Provider) currentAPIKey() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.apiKey
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() string { return "gemini" }
+
+// Model returns the model requests are sent to.
+func (p *Provider) Model() string { return p.model }
+
+// Available reports whether an API key is configured.
+func (p *Provider) Available() bool { return p.currentAPIKey() != "" }
+
+type part struct {
+	Text string `json:"text"`
+}
+
+type content struct {
+	Role  string `json:"role,omitempty"`
+	Parts []part `json:"parts"`
+}
+
+type generateRequest struct {
+	SystemInstruction *content  `json:"systemInstruction,omitempty"`
+	Contents          []content `json:"contents"`
+	GenerationConfig  struct {
+		Temperature     float64 `json:"temperature"`
+		MaxOutputTokens int     `json:"maxOutputTokens"`
+	} `json:"generationConfig"`
+}
+
+type generateResponse struct {
+	Candidates []struct {
+		Content content `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+func (r *generateResponse) text() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+	text := ""
+	for _, p := range r.Candidates[0].Content.Parts {
+		text += p.Text
+	}
+	return text
+}
+
+func (r *generateResponse) usage() ai.Usage {
+	return ai.Usage{InputTokens: r.UsageMetadata.PromptTokenCount, OutputTokens: r.UsageMetadata.CandidatesTokenCount}
+}
+
+func (p *Provider) newRequest(ctx context.Context, method string, req ai.Request) (*http.Request, error) {
+	body := generateRequest{
+		Contents: []content{{Role: "user", Parts: []part{{Text: req.Prompt}}}},
+	}
+	if req.System != "" {
+		body.SystemInstruction = &content{Parts: []part{{Text: req.System}}}
+	}
+	body.GenerationConfig.Temperature = req.Temperature
+	body.GenerationConfig.MaxOutputTokens = req.MaxTokens
+	if body.GenerationConfig.MaxOutputTokens <= 0 {
+		body.GenerationConfig.MaxOutputTokens = ai.DefaultMaxTokens
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("gemini: failed to build request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+p.model+":"+method, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("gemini: failed to create request: %w", err)
+	}
+	// API key sent via header, not URL, to avoid logging exposure
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-goog-api-key", p.currentAPIKey())
+	return httpReq, nil
+}
+
+// Generate returns the complete answer.
+func (p *Provider) Generate(ctx context.Context, req ai.Request) (*ai.Response, error) {
+	httpReq, err := p.newRequest(ctx, "generateContent", req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gemini: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("gemini: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gemini: API error (status %d): %s", resp.StatusCode, truncate(body))
+	}
+
+	var result generateResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("gemini: failed to parse response: %w", err)
+	}
+	text := result.text()
+	if text == "" {
+		return nil, fmt.Errorf("gemini: empty response")
+	}
+
+	return &ai.Response{Text: text, Provider: p.Name(), Model: p.model, Usage: result.usage()}, nil
+}
+
+// Stream relays the answer as Gemini generates it.
+func (p *Provider) Stream(ctx context.Context, req ai.Request, onChunk func(text string) error) (*ai.Response, error) {
+	httpReq, err := p.newRequest(ctx, "streamGenerateContent?alt=sse", req)
+	if err != nil {
+		return nil, err
+	}
+
+	// No client timeout: the caller's context bounds the whole stream
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("gemini: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("gemini: API error (status %d): %s", resp.StatusCode, truncate(body))
+	}
+
+	out := &ai.Response{Provider: p.Name(), Model: p.model}
+	err = sse.Read(resp.Body, func(e sse.Event) error {
+		var chunk generateResponse
+		if err := json.Unmarshal([]byte(e.Data), &chunk); err != nil {
+			return nil
+		}
+		// Usage metadata is cumulative; the last chunk has the totals
+		out.Usage = chunk.usage()
+		text := chunk.text()
+		if text == "" {
+			return nil
+		}
+		out.Text += text
+		return onChunk(text)
+	})
+	if err != nil {
+		return out, fmt.Errorf("gemini: stream interrupted: %w", err)
+	}
+	if out.Text == "" {
+		return out, fmt.Errorf("gemini: empty response")
+	}
+	return out, nil
+}
+
+// truncate keeps provider error bodies short enough for logs
+func truncate(body []byte) string {
+	if len(body) > 500 {
+		return string(body[:500]) + "..."
+	}
+	return string(body)
+}
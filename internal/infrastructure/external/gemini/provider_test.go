@@ -0,0 +1,54 @@
+package gemini
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/ai"
+)
+
+func TestStream_RelaysChunksAndUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-goog-api-key") != "key" || r.URL.Path != "/gemini-1.5-flash:streamGenerateContent" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(`data: {"candidates":[{"content":{"parts":[{"text":"Olá"}]}}]}` + "\n\n"))
+		w.Write([]byte(`data: {"candidates":[{"content":{"parts":[{"text":", mundo"}]}}],"usageMetadata":{"promptTokenCount":5,"candidatesTokenCount":3}}` + "\n\n"))
+	}))
+	defer srv.Close()
+
+	p := NewProvider("key", "")
+	p.baseURL = srv.URL + "/"
+
+	var chunks []string
+	resp, err := p.Stream(context.Background(), ai.Request{Prompt: "oi"}, func(text string) error {
+		chunks = append(chunks, text)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "Olá, mundo" || len(chunks) != 2 {
+		t.Errorf("unexpected answer %q from chunks %v", resp.Text, chunks)
+	}
+	if resp.Usage.InputTokens != 5 || resp.Usage.OutputTokens != 3 {
+		t.Errorf("unexpected usage %+v", resp.Usage)
+	}
+}
+
+func TestGenerate_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"quota"}`, http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	p := NewProvider("key", "")
+	p.baseURL = srv.URL + "/"
+	if _, err := p.Generate(context.Background(), ai.Request{Prompt: "oi"}); err == nil {
+		t.Fatal("expected an error for a 429 response")
+	}
+}
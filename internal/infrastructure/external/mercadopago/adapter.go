@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/phone"
 )
 
 // MercadoPagoAdapter implements gateway.PaymentGateway using the Mercado Pago client.
@@ -307,18 +308,10 @@ func splitName(fullName string) (string, string) {
 	return parts[0], parts[1]
 }
 
-// splitPhone splits a phone number into area code and number.
-func splitPhone(phone string) (string, string) {
-	cleaned := strings.Map(func(r rune) rune {
-		if r >= '0' && r <= '9' {
-			return r
-		}
-		return -1
-	}, phone)
-	if len(cleaned) >= 10 {
-		return cleaned[:2], cleaned[2:]
-	}
-	return "", cleaned
+// splitPhone splits a phone number into area code and number. E.164 numbers ("+5511...")
+// lose the country code; numbers of other countries keep all their digits as the number.
+func splitPhone(number string) (string, string) {
+	return phone.AreaCode(number)
 }
 
 // getDocumentNumber extracts document number from customer.
@@ -255,6 +255,11 @@ func TestSplitPhone(t *testing.T) {
 	if area != "11" || num != "987654321" {
 		t.Errorf("splitPhone with formatting: got (%s, %s)", area, num)
 	}
+
+	area, num = splitPhone("+5511987654321")
+	if area != "11" || num != "987654321" {
+		t.Errorf("splitPhone with country code: got (%s, %s)", area, num)
+	}
 }
 
 func TestValidateWebhookSignature_EmptySignature(t *testing.T) {
@@ -7,7 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/condotrack/api/internal/infrastructure/external/resilience"
 )
 
 const (
@@ -17,68 +20,84 @@ const (
 
 // Client represents the Mercado Pago API client.
 type Client struct {
+	mu          sync.RWMutex
 	accessToken string
 	baseURL     string
-	httpClient  *http.Client
+	httpClient  *resilience.Client
 }
 
-// NewClient creates a new Mercado Pago API client.
-func NewClient(accessToken, env string) *Client {
+// NewClient creates a new Mercado Pago API client. A nil httpClient uses the default
+// timeouts, retries and circuit breaker.
+func NewClient(accessToken, env string, httpClient *resilience.Client) *Client {
 	baseURL := sandboxBaseURL
 	if env == "production" {
 		baseURL = prodBaseURL
 	}
+	if httpClient == nil {
+		httpClient = resilience.NewClient("mercadopago", resilience.DefaultConfig())
+	}
 
 	return &Client{
 		accessToken: accessToken,
 		baseURL:     baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		httpClient:  httpClient,
 	}
 }
 
-// doRequest performs an HTTP request to the Mercado Pago API.
+// SetAccessToken replaces the access token used by subsequent requests (rotation from settings)
+func (c *Client) SetAccessToken(accessToken string) {
+	c.mu.Lock()
+	c.accessToken = accessToken
+	c.mu.Unlock()
+}
+
+func (c *Client) currentAccessToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.accessToken
+}
+
+// doRequest performs an HTTP request to the Mercado Pago API. Every attempt carries the same
+// idempotency key, so a retried POST cannot create the payment twice.
 func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) ([]byte, error) {
-	var bodyReader io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewBuffer(jsonBody)
 	}
 
 	url := c.baseURL + path
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
-	req.Header.Set("X-Idempotency-Key", fmt.Sprintf("%d", time.Now().UnixNano()))
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	idempotencyKey := fmt.Sprintf("%d", time.Now().UnixNano())
+	resp, err := c.httpClient.Do(ctx, true, func(ctx context.Context) (*http.Request, error) {
+		var bodyReader io.Reader
+		if jsonBody != nil {
+			bodyReader = bytes.NewReader(jsonBody)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+c.currentAccessToken())
+		req.Header.Set("X-Idempotency-Key", idempotencyKey)
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
 	if resp.StatusCode >= 400 {
 		var apiErr MPAPIError
-		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Message != "" {
+		if err := json.Unmarshal(resp.Body, &apiErr); err == nil && apiErr.Message != "" {
 			return nil, &apiErr
 		}
-		return nil, fmt.Errorf("MP API error: status %d, body: %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("MP API error: status %d, body: %s", resp.StatusCode, string(resp.Body))
 	}
 
-	return respBody, nil
+	return resp.Body, nil
 }
 
 // get performs a GET request.
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/condotrack/api/internal/domain/gateway"
@@ -86,6 +87,7 @@ func ParseWebhookEvent(ctx context.Context, adapter *MercadoPagoAdapter, headers
 	}
 
 	canonical := &gateway.WebhookEvent{
+		EventID:          notificationID(notification, headers),
 		GatewayEvent:     notification.Action,
 		GatewayName:      "mercadopago",
 		PaymentID:        paymentID,
@@ -115,3 +117,15 @@ func ParseWebhookEvent(ctx context.Context, adapter *MercadoPagoAdapter, headers
 
 	return canonical, nil
 }
+
+// notificationID returns the ID of a notification, which MP keeps across redeliveries,
+// falling back to the request ID of the delivery
+func notificationID(notification MPWebhookNotification, headers map[string]string) string {
+	if notification.ID != 0 {
+		return strconv.FormatInt(notification.ID, 10)
+	}
+	if requestID := headers["x-request-id"]; requestID != "" {
+		return requestID
+	}
+	return notification.Action + ":" + notification.Data.ID
+}
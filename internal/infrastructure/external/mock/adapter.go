@@ -0,0 +1,496 @@
+// Package mock implements a payment gateway that keeps payments in memory and simulates
+// their lifecycle, including the webhooks, so checkout, webhook handling and revenue split
+// can be exercised end to end without a provider sandbox. It must not be registered in
+// production.
+package mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	mathrand "math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/gateway"
+)
+
+// TokenHeader carries the webhook token of the mock gateway
+const TokenHeader = "mock-webhook-token"
+
+// Event names of the webhooks sent by the mock gateway
+const (
+	EventConfirmed = "payment.confirmed"
+	EventOverdue   = "payment.overdue"
+	EventRefunded  = "payment.refunded"
+	EventDeleted   = "payment.deleted"
+	EventFailed    = "payment.failed"
+)
+
+// Statuses of the mock payments
+const (
+	StatusPending   = "PENDING"
+	StatusConfirmed = "CONFIRMED"
+	StatusOverdue   = "OVERDUE"
+	StatusRefunded  = "REFUNDED"
+	StatusCancelled = "CANCELLED"
+	StatusDeclined  = "DECLINED"
+)
+
+// Test cards: any other number is approved
+const (
+	CardDeclined = "4000000000000002"
+	CardError    = "4000000000000119"
+)
+
+// Email tags forcing an outcome for the payments of a customer, e.g. aluno+overdue@x.com
+const (
+	TagFail    = "+fail"    // the payment is refused when created
+	TagTimeout = "+timeout" // creating the payment times out
+	TagOverdue = "+overdue" // the payment becomes overdue instead of confirmed
+	TagDecline = "+decline" // card payments are declined
+)
+
+// ErrTimeout is returned for customers tagged with TagTimeout. It wraps
+// context.DeadlineExceeded so it is reported as a gateway timeout.
+var ErrTimeout = fmt.Errorf("mock gateway: simulated timeout: %w", context.DeadlineExceeded)
+
+// pixQRCode is a 1x1 PNG standing in for the QR code image
+const pixQRCode = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAQAAAC1HAwCAAAAC0lEQVR42mNkYAAAAAYAAjCB0C8AAAAASUVORK5CYII="
+
+// Options configures the simulated lifecycle
+type Options struct {
+	// ConfirmAfter is the delay until PIX and boleto payments are confirmed, or become
+	// overdue for TagOverdue customers. Zero keeps them pending.
+	ConfirmAfter time.Duration
+	// FailureRate is the share of payments (0-1) refused at random when created
+	FailureRate float64
+	// WebhookURL receives the events; empty sends none
+	WebhookURL string
+	// WebhookToken is sent in TokenHeader and required by ValidateWebhookSignature; empty
+	// generates a random one, which works as long as the events are sent to this process
+	WebhookToken string
+}
+
+// MockAdapter implements gateway.PaymentGateway in memory
+type MockAdapter struct {
+	opts   Options
+	fees   gateway.GatewayFees
+	client *http.Client
+	// after schedules lifecycle transitions, replaced in tests
+	after func(time.Duration, func())
+
+	mu        sync.Mutex
+	rng       *mathrand.Rand
+	customers map[string]*gateway.CustomerResponse
+	payments  map[string]*payment
+}
+
+type payment struct {
+	ID          string     `json:"id"`
+	Customer    string     `json:"customer"`
+	Amount      float64    `json:"amount"`
+	NetAmount   float64    `json:"net_amount"`
+	Status      string     `json:"status"`
+	BillingType string     `json:"billing_type"`
+	DueDate     string     `json:"due_date"`
+	ExternalRef string     `json:"external_reference,omitempty"`
+	PaidAt      *time.Time `json:"paid_at,omitempty"`
+}
+
+// webhookBody is the payload of the mock webhooks
+type webhookBody struct {
+	ID      string  `json:"id"`
+	Event   string  `json:"event"`
+	Payment payment `json:"payment"`
+}
+
+// NewMockAdapter creates a new mock gateway adapter
+func NewMockAdapter(opts Options, fees gateway.GatewayFees) *MockAdapter {
+	if opts.WebhookToken == "" {
+		buf := make([]byte, 16)
+		_, _ = rand.Read(buf)
+		opts.WebhookToken = hex.EncodeToString(buf)
+	}
+	return &MockAdapter{
+		opts:      opts,
+		fees:      fees,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		after:     func(d time.Duration, f func()) { time.AfterFunc(d, f) },
+		rng:       mathrand.New(mathrand.NewSource(time.Now().UnixNano())),
+		customers: make(map[string]*gateway.CustomerResponse),
+		payments:  make(map[string]*payment),
+	}
+}
+
+func (a *MockAdapter) Name() string { return "mock" }
+
+// CreateCustomer stores a customer, returning the existing one for a known document
+func (a *MockAdapter) CreateCustomer(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if req.Document != "" {
+		for _, c := range a.customers {
+			if c.Document == req.Document {
+				return copyCustomer(c), nil
+			}
+		}
+	}
+	c := &gateway.CustomerResponse{GatewayID: "cus_mock_" + newID(), Name: req.Name, Email: req.Email, Document: req.Document}
+	a.customers[c.GatewayID] = c
+	return copyCustomer(c), nil
+}
+
+// FindCustomerByDocument finds a customer by CPF/CNPJ
+func (a *MockAdapter) FindCustomerByDocument(ctx context.Context, document string) (*gateway.CustomerResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, c := range a.customers {
+		if c.Document == document {
+			return copyCustomer(c), nil
+		}
+	}
+	return nil, nil
+}
+
+// CreatePixPayment creates a pending PIX payment, confirmed after Options.ConfirmAfter
+func (a *MockAdapter) CreatePixPayment(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+	p, err := a.create(req, gateway.BillingPIX)
+	if err != nil {
+		return nil, err
+	}
+	resp := a.toCanonicalPayment(p)
+	resp.PixQRCodeBase64 = pixQRCode
+	resp.PixCopyPaste = "00020126580014br.gov.bcb.pix0136" + p.ID + "5204000053039865802BR5913CONDOTRACK MOCK6009SAO PAULO6304MOCK"
+	resp.PixExpiration = time.Now().Add(24 * time.Hour).Format("2006-01-02 15:04:05")
+	return resp, nil
+}
+
+// CreateBoletoPayment creates a pending boleto, confirmed after Options.ConfirmAfter
+func (a *MockAdapter) CreateBoletoPayment(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+	p, err := a.create(req, gateway.BillingBoleto)
+	if err != nil {
+		return nil, err
+	}
+	resp := a.toCanonicalPayment(p)
+	resp.BoletoBarCode = fmt.Sprintf("23793.38128 60000.000003 00000.000400 1 %014.0f", p.Amount*100)
+	return resp, nil
+}
+
+// CreateCardPayment approves the payment at once, confirming it with a webhook, unless the
+// card or customer forces a decline
+func (a *MockAdapter) CreateCardPayment(ctx context.Context, req gateway.CreateCardPaymentRequest) (*gateway.PaymentResponse, error) {
+	if req.CardNumber == CardError {
+		return nil, errors.New("mock gateway: card processing error")
+	}
+	a.mu.Lock()
+	declined := req.CardNumber == CardDeclined || a.customerTagged(req.CustomerGatewayID, TagDecline)
+	a.mu.Unlock()
+
+	p, err := a.newPayment(req.CreatePaymentRequest, gateway.BillingCreditCard)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	event := EventConfirmed
+	if declined {
+		p.Status = StatusDeclined
+		event = EventFailed
+	} else {
+		now := time.Now()
+		p.Status = StatusConfirmed
+		p.PaidAt = &now
+	}
+	snapshot := *p
+	a.mu.Unlock()
+
+	// Providers confirm card payments by webhook too, after answering the request
+	a.after(0, func() { a.send(event, snapshot) })
+	return a.toCanonicalPayment(&snapshot), nil
+}
+
+// GetPayment returns the current state of a payment
+func (a *MockAdapter) GetPayment(ctx context.Context, gatewayPaymentID string) (*gateway.PaymentResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p, ok := a.payments[gatewayPaymentID]
+	if !ok {
+		return nil, fmt.Errorf("mock gateway: payment %s not found", gatewayPaymentID)
+	}
+	return a.toCanonicalPayment(p), nil
+}
+
+// RefundPayment refunds a confirmed payment and sends the refund webhook
+func (a *MockAdapter) RefundPayment(ctx context.Context, gatewayPaymentID string, amount float64) (*gateway.PaymentResponse, error) {
+	a.mu.Lock()
+	p, ok := a.payments[gatewayPaymentID]
+	if !ok {
+		a.mu.Unlock()
+		return nil, fmt.Errorf("mock gateway: payment %s not found", gatewayPaymentID)
+	}
+	if p.Status != StatusConfirmed {
+		a.mu.Unlock()
+		return nil, fmt.Errorf("mock gateway: payment %s is %s and cannot be refunded", gatewayPaymentID, p.Status)
+	}
+	p.Status = StatusRefunded
+	snapshot := *p
+	a.mu.Unlock()
+
+	a.after(0, func() { a.send(EventRefunded, snapshot) })
+	return a.toCanonicalPayment(&snapshot), nil
+}
+
+// CancelPayment cancels a pending payment and sends the deletion webhook
+func (a *MockAdapter) CancelPayment(ctx context.Context, gatewayPaymentID string) error {
+	a.mu.Lock()
+	p, ok := a.payments[gatewayPaymentID]
+	if !ok {
+		a.mu.Unlock()
+		return fmt.Errorf("mock gateway: payment %s not found", gatewayPaymentID)
+	}
+	if p.Status != StatusPending && p.Status != StatusOverdue {
+		a.mu.Unlock()
+		return fmt.Errorf("mock gateway: payment %s is %s and cannot be cancelled", gatewayPaymentID, p.Status)
+	}
+	p.Status = StatusCancelled
+	snapshot := *p
+	a.mu.Unlock()
+
+	a.after(0, func() { a.send(EventDeleted, snapshot) })
+	return nil
+}
+
+// ParseWebhookEvent parses a mock webhook into the canonical format
+func (a *MockAdapter) ParseWebhookEvent(ctx context.Context, headers map[string]string, body []byte) (*gateway.WebhookEvent, error) {
+	var event webhookBody
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse mock webhook: %w", err)
+	}
+	if event.Payment.ID == "" {
+		return nil, fmt.Errorf("webhook event has no payment data")
+	}
+
+	eventType := ""
+	switch event.Event {
+	case EventConfirmed:
+		eventType = gateway.EventPaymentConfirmed
+	case EventOverdue:
+		eventType = gateway.EventPaymentOverdue
+	case EventRefunded:
+		eventType = gateway.EventPaymentRefunded
+	case EventDeleted:
+		eventType = gateway.EventPaymentDeleted
+	case EventFailed:
+		eventType = gateway.EventPaymentFailed
+	}
+
+	eventID := event.ID
+	if eventID == "" {
+		eventID = event.Event + ":" + event.Payment.ID
+	}
+
+	return &gateway.WebhookEvent{
+		EventID:          eventID,
+		EventType:        eventType,
+		GatewayEvent:     event.Event,
+		GatewayName:      "mock",
+		PaymentID:        event.Payment.ID,
+		CustomerID:       event.Payment.Customer,
+		Amount:           event.Payment.Amount,
+		NetAmount:        event.Payment.NetAmount,
+		Status:           a.NormalizeStatus(event.Payment.Status),
+		GatewayRawStatus: event.Payment.Status,
+		BillingType:      event.Payment.BillingType,
+		ExternalRef:      event.Payment.ExternalRef,
+		PaidAt:           event.Payment.PaidAt,
+		RawPayload:       body,
+		Settled:          event.Payment.Status == StatusConfirmed,
+	}, nil
+}
+
+// ValidateWebhookSignature checks the webhook token
+func (a *MockAdapter) ValidateWebhookSignature(ctx context.Context, headers map[string]string, body []byte) bool {
+	return headers[TokenHeader] == a.opts.WebhookToken
+}
+
+// GetFees returns the gateway fee configuration
+func (a *MockAdapter) GetFees() gateway.GatewayFees {
+	return a.fees
+}
+
+// NormalizeStatus translates a mock status to the canonical status
+func (a *MockAdapter) NormalizeStatus(status string) string {
+	switch status {
+	case StatusPending:
+		return gateway.StatusPending
+	case StatusConfirmed:
+		return gateway.StatusConfirmed
+	case StatusOverdue:
+		return gateway.StatusOverdue
+	case StatusRefunded:
+		return gateway.StatusRefunded
+	case StatusCancelled:
+		return gateway.StatusCancelled
+	default:
+		return gateway.StatusFailed
+	}
+}
+
+// create stores a pending PIX or boleto payment and schedules its confirmation, or its
+// expiry for TagOverdue customers
+func (a *MockAdapter) create(req gateway.CreatePaymentRequest, billingType string) (*payment, error) {
+	p, err := a.newPayment(req, billingType)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	overdue := a.customerTagged(req.CustomerGatewayID, TagOverdue)
+	snapshot := *p
+	a.mu.Unlock()
+
+	if a.opts.ConfirmAfter > 0 {
+		a.after(a.opts.ConfirmAfter, func() {
+			if overdue {
+				a.transition(snapshot.ID, StatusOverdue, EventOverdue)
+			} else {
+				a.transition(snapshot.ID, StatusConfirmed, EventConfirmed)
+			}
+		})
+	}
+	return &snapshot, nil
+}
+
+// newPayment applies the forced failures and stores a pending payment
+func (a *MockAdapter) newPayment(req gateway.CreatePaymentRequest, billingType string) (*payment, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.customers[req.CustomerGatewayID]; !ok {
+		return nil, fmt.Errorf("mock gateway: customer %s not found", req.CustomerGatewayID)
+	}
+	if a.customerTagged(req.CustomerGatewayID, TagTimeout) {
+		return nil, ErrTimeout
+	}
+	if a.customerTagged(req.CustomerGatewayID, TagFail) || (a.opts.FailureRate > 0 && a.rng.Float64() < a.opts.FailureRate) {
+		return nil, errors.New("mock gateway: payment refused (simulated failure)")
+	}
+
+	p := &payment{
+		ID:          "pay_mock_" + newID(),
+		Customer:    req.CustomerGatewayID,
+		Amount:      req.Amount,
+		NetAmount:   req.Amount - a.fee(req.Amount, billingType),
+		Status:      StatusPending,
+		BillingType: billingType,
+		DueDate:     req.DueDate.Format("2006-01-02"),
+		ExternalRef: req.ExternalReference,
+	}
+	a.payments[p.ID] = p
+	return p, nil
+}
+
+// transition moves a payment that is still pending to status and sends the event
+func (a *MockAdapter) transition(id, status, event string) {
+	a.mu.Lock()
+	p, ok := a.payments[id]
+	if !ok || p.Status != StatusPending {
+		a.mu.Unlock()
+		return
+	}
+	p.Status = status
+	if status == StatusConfirmed {
+		now := time.Now()
+		p.PaidAt = &now
+	}
+	snapshot := *p
+	a.mu.Unlock()
+
+	a.send(event, snapshot)
+}
+
+// send posts a webhook, retrying a few times while the server is not ready
+func (a *MockAdapter) send(event string, p payment) {
+	if a.opts.WebhookURL == "" {
+		return
+	}
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	body, err := json.Marshal(webhookBody{ID: "evt_mock_" + hex.EncodeToString(buf), Event: event, Payment: p})
+	if err != nil {
+		log.Printf("[MOCK GATEWAY] Failed to encode %s webhook: %v", event, err)
+		return
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		req, err := http.NewRequest(http.MethodPost, a.opts.WebhookURL, bytes.NewReader(body))
+		if err != nil {
+			log.Printf("[MOCK GATEWAY] Invalid webhook URL %q: %v", a.opts.WebhookURL, err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(TokenHeader, a.opts.WebhookToken)
+
+		resp, err := a.client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 500 {
+				log.Printf("[MOCK GATEWAY] Sent %s for %s: %d", event, p.ID, resp.StatusCode)
+				return
+			}
+			err = fmt.Errorf("status %d", resp.StatusCode)
+		}
+		log.Printf("[MOCK GATEWAY] Webhook %s for %s failed (attempt %d): %v", event, p.ID, attempt, err)
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+}
+
+// customerTagged reports whether the email of a customer has a tag; callers hold mu
+func (a *MockAdapter) customerTagged(customerID, tag string) bool {
+	c, ok := a.customers[customerID]
+	return ok && strings.Contains(strings.ToLower(c.Email), tag+"@")
+}
+
+func (a *MockAdapter) fee(amount float64, billingType string) float64 {
+	switch billingType {
+	case gateway.BillingPIX:
+		return amount * a.fees.PixPercent
+	case gateway.BillingBoleto:
+		return a.fees.BoletoFixed
+	default:
+		return amount*a.fees.CardPercent + a.fees.CardFixed
+	}
+}
+
+func (a *MockAdapter) toCanonicalPayment(p *payment) *gateway.PaymentResponse {
+	return &gateway.PaymentResponse{
+		GatewayPaymentID: p.ID,
+		Status:           a.NormalizeStatus(p.Status),
+		GatewayRawStatus: p.Status,
+		Amount:           p.Amount,
+		NetAmount:        p.NetAmount,
+		BillingType:      p.BillingType,
+		DueDate:          p.DueDate,
+		PaidAt:           p.PaidAt,
+		ConfirmedAt:      p.PaidAt,
+	}
+}
+
+func copyCustomer(c *gateway.CustomerResponse) *gateway.CustomerResponse {
+	cp := *c
+	return &cp
+}
+
+func newID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
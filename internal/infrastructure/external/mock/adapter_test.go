@@ -0,0 +1,255 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/gateway"
+)
+
+var testFees = gateway.GatewayFees{PixPercent: 0.01, BoletoFixed: 2, CardPercent: 0.03, CardFixed: 0.5}
+
+// webhookRecorder collects the webhooks the adapter sends
+type webhookRecorder struct {
+	mu     sync.Mutex
+	events []*gateway.WebhookEvent
+	server *httptest.Server
+}
+
+func newTestAdapter(t *testing.T, opts Options) (*MockAdapter, *webhookRecorder) {
+	rec := &webhookRecorder{}
+	var a *MockAdapter
+	rec.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		headers := map[string]string{TokenHeader: r.Header.Get(TokenHeader)}
+		if !a.ValidateWebhookSignature(r.Context(), headers, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		event, err := a.ParseWebhookEvent(r.Context(), headers, body)
+		if err != nil {
+			t.Errorf("failed to parse webhook: %v", err)
+		}
+		rec.mu.Lock()
+		rec.events = append(rec.events, event)
+		rec.mu.Unlock()
+	}))
+	t.Cleanup(rec.server.Close)
+
+	opts.WebhookURL = rec.server.URL
+	a = NewMockAdapter(opts, testFees)
+	// Run transitions at once, in the calling goroutine
+	a.after = func(_ time.Duration, f func()) { f() }
+	return a, rec
+}
+
+func (r *webhookRecorder) last(t *testing.T) *gateway.WebhookEvent {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.events) == 0 {
+		t.Fatal("expected a webhook")
+	}
+	return r.events[len(r.events)-1]
+}
+
+func newCustomer(t *testing.T, a *MockAdapter, email string) string {
+	t.Helper()
+	c, err := a.CreateCustomer(context.Background(), gateway.CreateCustomerRequest{Name: "Aluno", Email: email, Document: email})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c.GatewayID
+}
+
+func TestMockAdapter_PixConfirmed(t *testing.T) {
+	a, rec := newTestAdapter(t, Options{ConfirmAfter: time.Second})
+	ctx := context.Background()
+	customer := newCustomer(t, a, "aluno@example.com")
+
+	resp, err := a.CreatePixPayment(ctx, gateway.CreatePaymentRequest{CustomerGatewayID: customer, Amount: 100, DueDate: time.Now()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Status != gateway.StatusPending || resp.PixCopyPaste == "" {
+		t.Errorf("expected a pending PIX with copy-paste code, got %+v", resp)
+	}
+	if resp.NetAmount != 99 {
+		t.Errorf("expected net amount 99 after the PIX fee, got %v", resp.NetAmount)
+	}
+
+	event := rec.last(t)
+	if event.EventType != gateway.EventPaymentConfirmed || event.PaymentID != resp.GatewayPaymentID || event.GatewayName != "mock" {
+		t.Errorf("unexpected webhook: %+v", event)
+	}
+	if !event.Settled || event.PaidAt == nil {
+		t.Error("expected a settled confirmation with the payment date")
+	}
+	if event.EventID == "" {
+		t.Error("expected the webhook to carry an event ID")
+	}
+
+	current, _ := a.GetPayment(ctx, resp.GatewayPaymentID)
+	if current.Status != gateway.StatusConfirmed {
+		t.Errorf("expected the payment to be confirmed, got %s", current.Status)
+	}
+}
+
+func TestMockAdapter_NoAutoConfirm(t *testing.T) {
+	a, rec := newTestAdapter(t, Options{})
+	customer := newCustomer(t, a, "aluno@example.com")
+
+	resp, err := a.CreateBoletoPayment(context.Background(), gateway.CreatePaymentRequest{CustomerGatewayID: customer, Amount: 50, DueDate: time.Now()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.BoletoBarCode == "" {
+		t.Error("expected a boleto bar code")
+	}
+	if len(rec.events) != 0 {
+		t.Errorf("expected no webhook without ConfirmAfter, got %d", len(rec.events))
+	}
+}
+
+func TestMockAdapter_Overdue(t *testing.T) {
+	a, rec := newTestAdapter(t, Options{ConfirmAfter: time.Second})
+	customer := newCustomer(t, a, "aluno+overdue@example.com")
+
+	if _, err := a.CreateBoletoPayment(context.Background(), gateway.CreatePaymentRequest{CustomerGatewayID: customer, Amount: 50, DueDate: time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event := rec.last(t); event.EventType != gateway.EventPaymentOverdue {
+		t.Errorf("expected an overdue webhook, got %s", event.EventType)
+	}
+}
+
+func TestMockAdapter_ForcedFailures(t *testing.T) {
+	a, _ := newTestAdapter(t, Options{})
+	ctx := context.Background()
+
+	failing := newCustomer(t, a, "aluno+fail@example.com")
+	if _, err := a.CreatePixPayment(ctx, gateway.CreatePaymentRequest{CustomerGatewayID: failing, Amount: 10}); err == nil {
+		t.Error("expected +fail customers to be refused")
+	}
+
+	slow := newCustomer(t, a, "aluno+timeout@example.com")
+	_, err := a.CreatePixPayment(ctx, gateway.CreatePaymentRequest{CustomerGatewayID: slow, Amount: 10})
+	if !gateway.IsTimeout(err) {
+		t.Errorf("expected a timeout for +timeout customers, got %v", err)
+	}
+
+	if _, err := a.CreatePixPayment(ctx, gateway.CreatePaymentRequest{CustomerGatewayID: "cus_unknown", Amount: 10}); err == nil {
+		t.Error("expected unknown customers to be refused")
+	}
+}
+
+func TestMockAdapter_FailureRate(t *testing.T) {
+	a, _ := newTestAdapter(t, Options{FailureRate: 1})
+	customer := newCustomer(t, a, "aluno@example.com")
+
+	if _, err := a.CreatePixPayment(context.Background(), gateway.CreatePaymentRequest{CustomerGatewayID: customer, Amount: 10}); err == nil {
+		t.Error("expected every payment to fail with a failure rate of 1")
+	}
+}
+
+func TestMockAdapter_Card(t *testing.T) {
+	a, rec := newTestAdapter(t, Options{})
+	ctx := context.Background()
+	customer := newCustomer(t, a, "aluno@example.com")
+
+	card := func(number string) gateway.CreateCardPaymentRequest {
+		return gateway.CreateCardPaymentRequest{
+			CreatePaymentRequest: gateway.CreatePaymentRequest{CustomerGatewayID: customer, Amount: 200},
+			CardNumber:           number,
+		}
+	}
+
+	resp, err := a.CreateCardPayment(ctx, card("4111111111111111"))
+	if err != nil || resp.Status != gateway.StatusConfirmed {
+		t.Fatalf("expected an approved card payment, got %+v, %v", resp, err)
+	}
+	if event := rec.last(t); event.EventType != gateway.EventPaymentConfirmed {
+		t.Errorf("expected a confirmation webhook, got %s", event.EventType)
+	}
+
+	resp, err = a.CreateCardPayment(ctx, card(CardDeclined))
+	if err != nil || resp.Status != gateway.StatusFailed {
+		t.Errorf("expected a declined card payment, got %+v, %v", resp, err)
+	}
+	if event := rec.last(t); event.EventType != gateway.EventPaymentFailed {
+		t.Errorf("expected a failure webhook, got %s", event.EventType)
+	}
+
+	if _, err := a.CreateCardPayment(ctx, card(CardError)); err == nil {
+		t.Error("expected an error for the error test card")
+	}
+}
+
+func TestMockAdapter_RefundAndCancel(t *testing.T) {
+	a, rec := newTestAdapter(t, Options{})
+	ctx := context.Background()
+	customer := newCustomer(t, a, "aluno@example.com")
+
+	pending, _ := a.CreatePixPayment(ctx, gateway.CreatePaymentRequest{CustomerGatewayID: customer, Amount: 10})
+	if _, err := a.RefundPayment(ctx, pending.GatewayPaymentID, 10); err == nil {
+		t.Error("expected pending payments not to be refundable")
+	}
+	if err := a.CancelPayment(ctx, pending.GatewayPaymentID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event := rec.last(t); event.EventType != gateway.EventPaymentDeleted {
+		t.Errorf("expected a deletion webhook, got %s", event.EventType)
+	}
+
+	paid, _ := a.CreateCardPayment(ctx, gateway.CreateCardPaymentRequest{
+		CreatePaymentRequest: gateway.CreatePaymentRequest{CustomerGatewayID: customer, Amount: 10},
+		CardNumber:           "4111111111111111",
+	})
+	refund, err := a.RefundPayment(ctx, paid.GatewayPaymentID, 10)
+	if err != nil || refund.Status != gateway.StatusRefunded {
+		t.Fatalf("expected a refund, got %+v, %v", refund, err)
+	}
+	if event := rec.last(t); event.EventType != gateway.EventPaymentRefunded {
+		t.Errorf("expected a refund webhook, got %s", event.EventType)
+	}
+}
+
+func TestMockAdapter_CustomerByDocument(t *testing.T) {
+	a, _ := newTestAdapter(t, Options{})
+	ctx := context.Background()
+	id := newCustomer(t, a, "aluno@example.com")
+
+	again := newCustomer(t, a, "aluno@example.com")
+	if again != id {
+		t.Errorf("expected the existing customer for the same document, got %s and %s", id, again)
+	}
+	found, _ := a.FindCustomerByDocument(ctx, "aluno@example.com")
+	if found == nil || found.GatewayID != id {
+		t.Errorf("expected to find customer %s, got %+v", id, found)
+	}
+	if missing, _ := a.FindCustomerByDocument(ctx, "other"); missing != nil {
+		t.Errorf("expected no customer, got %+v", missing)
+	}
+}
+
+func TestMockAdapter_WebhookToken(t *testing.T) {
+	a := NewMockAdapter(Options{WebhookToken: "secret"}, testFees)
+	ctx := context.Background()
+	body, _ := json.Marshal(webhookBody{Event: EventConfirmed, Payment: payment{ID: "pay_1", Status: StatusConfirmed}})
+
+	if a.ValidateWebhookSignature(ctx, map[string]string{TokenHeader: "wrong"}, body) {
+		t.Error("expected a wrong token to be rejected")
+	}
+	if !a.ValidateWebhookSignature(ctx, map[string]string{TokenHeader: "secret"}, body) {
+		t.Error("expected the configured token to be accepted")
+	}
+	if generated := NewMockAdapter(Options{}, testFees); generated.ValidateWebhookSignature(ctx, map[string]string{}, body) {
+		t.Error("expected a generated token when none is configured")
+	}
+}
@@ -0,0 +1,219 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/ai"
+	"github.com/condotrack/api/pkg/sse"
+)
+
+const (
+	defaultModel   = "gpt-4o-mini"
+	defaultBaseURL = "https://api.openai.com/v1"
+)
+
+// Provider implements ai.AIProvider for the OpenAI chat completions API.
+type Provider struct {
+	mu         sync.RWMutex
+	apiKey     string
+	model      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewProvider creates an OpenAI provider; an empty model uses gpt-4o-mini.
+func NewProvider(apiKey, model string) *Provider {
+	if model == "" {
+		model = defaultModel
+	}
+	return &Provider{
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetAPIKey replaces the API key used by subsequent requests (rotation from settings)
+func (p *Provider) SetAPIKey(apiKey string) {
+	p.mu.Lock()
+	p.apiKey = apiKey
+	p.mu.Unlock()
+}
+
+func (p *Provider) currentAPIKey() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.apiKey
+}
+
+// Name returns the provider identifier.
+func (p *Provider) Name() string { return "openai" }
+
+// Model returns the model requests are sent to.
+func (p *Provider) Model() string { return p.model }
+
+// Available reports whether an API key is configured.
+func (p *Provider) Available() bool { return p.currentAPIKey() != "" }
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model         string    `json:"model"`
+	Messages      []message `json:"messages"`
+	Temperature   float64   `json:"temperature"`
+	MaxTokens     int       `json:"max_tokens"`
+	Stream        bool      `json:"stream,omitempty"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options,omitempty"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message message `json:"message"`
+		Delta   message `json:"delta"`
+	} `json:"choices"`
+	Usage *chatUsage `json:"usage"`
+}
+
+func (p *Provider) newRequest(ctx context.Context, req ai.Request, stream bool) (*http.Request, error) {
+	body := chatRequest{
+		Model:       p.model,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      stream,
+	}
+	if body.MaxTokens <= 0 {
+		body.MaxTokens = ai.DefaultMaxTokens
+	}
+	if req.System != "" {
+		body.Messages = append(body.Messages, message{Role: "system", Content: req.System})
+	}
+	body.Messages = append(body.Messages, message{Role: "user", Content: req.Prompt})
+	if stream {
+		body.StreamOptions = &struct {
+			IncludeUsage bool `json:"include_usage"`
+		}{IncludeUsage: true}
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to build request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.currentAPIKey())
+	return httpReq, nil
+}
+
+// Generate returns the complete answer.
+func (p *Provider) Generate(ctx context.Context, req ai.Request) (*ai.Response, error) {
+	httpReq, err := p.newRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("openai: failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai: API error (status %d): %s", resp.StatusCode, truncate(body))
+	}
+
+	var result chatResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("openai: failed to parse response: %w", err)
+	}
+	if len(result.Choices) == 0 || result.Choices[0].Message.Content == "" {
+		return nil, fmt.Errorf("openai: empty response")
+	}
+
+	out := &ai.Response{Text: result.Choices[0].Message.Content, Provider: p.Name(), Model: p.model}
+	if result.Usage != nil {
+		out.Usage = ai.Usage{InputTokens: result.Usage.PromptTokens, OutputTokens: result.Usage.CompletionTokens}
+	}
+	return out, nil
+}
+
+// Stream relays the answer as OpenAI generates it.
+func (p *Provider) Stream(ctx context.Context, req ai.Request, onChunk func(text string) error) (*ai.Response, error) {
+	httpReq, err := p.newRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// No client timeout: the caller's context bounds the whole stream
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("openai: API error (status %d): %s", resp.StatusCode, truncate(body))
+	}
+
+	out := &ai.Response{Provider: p.Name(), Model: p.model}
+	err = sse.Read(resp.Body, func(e sse.Event) error {
+		if e.Data == "[DONE]" {
+			return nil
+		}
+		var chunk chatResponse
+		if err := json.Unmarshal([]byte(e.Data), &chunk); err != nil {
+			return nil
+		}
+		// With include_usage, the last chunk carries the usage and no choices
+		if chunk.Usage != nil {
+			out.Usage = ai.Usage{InputTokens: chunk.Usage.PromptTokens, OutputTokens: chunk.Usage.CompletionTokens}
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			return nil
+		}
+		text := chunk.Choices[0].Delta.Content
+		out.Text += text
+		return onChunk(text)
+	})
+	if err != nil {
+		return out, fmt.Errorf("openai: stream interrupted: %w", err)
+	}
+	if out.Text == "" {
+		return out, fmt.Errorf("openai: empty response")
+	}
+	return out, nil
+}
+
+// truncate keeps provider error bodies short enough for logs
+func truncate(body []byte) string {
+	if len(body) > 500 {
+		return string(body[:500]) + "..."
+	}
+	return string(body)
+}
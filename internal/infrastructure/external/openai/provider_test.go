@@ -0,0 +1,44 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/ai"
+)
+
+func TestStream_RelaysChunksAndUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" || r.URL.Path != "/chat/completions" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(`data: {"choices":[{"delta":{"role":"assistant","content":""}}]}` + "\n\n"))
+		w.Write([]byte(`data: {"choices":[{"delta":{"content":"Olá"}}]}` + "\n\n"))
+		w.Write([]byte(`data: {"choices":[{"delta":{"content":", mundo"}}]}` + "\n\n"))
+		w.Write([]byte(`data: {"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":3}}` + "\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	p := NewProvider("key", "")
+	p.baseURL = srv.URL
+
+	var chunks []string
+	resp, err := p.Stream(context.Background(), ai.Request{Prompt: "oi"}, func(text string) error {
+		chunks = append(chunks, text)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Text != "Olá, mundo" || len(chunks) != 2 {
+		t.Errorf("unexpected answer %q from chunks %v", resp.Text, chunks)
+	}
+	if resp.Usage.InputTokens != 5 || resp.Usage.OutputTokens != 3 {
+		t.Errorf("unexpected usage %+v", resp.Usage)
+	}
+}
@@ -0,0 +1,61 @@
+package resilience
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	b := NewBreaker(2, 30*time.Second)
+	b.now = func() time.Time { return now }
+
+	b.Record(false)
+	if !b.Allow() {
+		t.Fatal("expected the breaker to stay closed below the threshold")
+	}
+	b.Record(false)
+	if b.Allow() {
+		t.Fatal("expected the breaker to open at the threshold")
+	}
+	if s := b.Status(); s.State != StateOpen || s.OpenedAt == nil || s.ConsecutiveFailures != 2 {
+		t.Errorf("unexpected status: %+v", s)
+	}
+
+	now = now.Add(30 * time.Second)
+	if !b.Allow() {
+		t.Fatal("expected a trial request after the cool-down")
+	}
+	if b.Allow() {
+		t.Error("expected a single trial request while half-open")
+	}
+	b.Record(false)
+	if b.Allow() || b.Status().State != StateOpen {
+		t.Fatal("expected a failed trial to reopen the breaker")
+	}
+
+	now = now.Add(30 * time.Second)
+	if !b.Allow() {
+		t.Fatal("expected a trial request after the cool-down")
+	}
+	b.Record(true)
+	if s := b.Status(); s.State != StateClosed || s.ConsecutiveFailures != 0 || s.OpenedAt != nil {
+		t.Errorf("expected a successful trial to close the breaker, got %+v", s)
+	}
+}
+
+func TestBreakerAbandonedTrial(t *testing.T) {
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	b := NewBreaker(1, time.Second)
+	b.now = func() time.Time { return now }
+
+	b.Record(false)
+	now = now.Add(time.Second)
+	if !b.Allow() {
+		t.Fatal("expected a trial request after the cool-down")
+	}
+	b.Abandon()
+	if !b.Allow() {
+		t.Error("expected the next request to be the trial after an abandoned one")
+	}
+}
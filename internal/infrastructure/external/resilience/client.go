@@ -0,0 +1,204 @@
+// Package resilience wraps the HTTP calls to the payment gateways with a timeout, retries
+// with exponential backoff and jitter, and a circuit breaker per gateway.
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/gateway"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Config holds the resilience settings of a gateway client
+type Config struct {
+	Timeout          time.Duration // per attempt
+	MaxRetries       int           // attempts after the first one
+	BaseDelay        time.Duration // backoff before the first retry, doubled on each retry
+	MaxDelay         time.Duration // cap of the backoff and of Retry-After
+	FailureThreshold int           // consecutive failures that open the breaker
+	OpenDuration     time.Duration // how long the breaker stays open before a trial request
+}
+
+// DefaultConfig returns the settings used when none are configured
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          30 * time.Second,
+		MaxRetries:       2,
+		BaseDelay:        200 * time.Millisecond,
+		MaxDelay:         2 * time.Second,
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+	}
+}
+
+// Response is the outcome of a request that reached the gateway
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Client sends requests to one gateway
+type Client struct {
+	name       string
+	cfg        Config
+	httpClient *http.Client
+	breaker    *Breaker
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient creates a client for the named gateway with its own breaker. Every attempt is
+// traced as a client span named after the gateway ("asaas POST"), propagating the trace.
+func NewClient(name string, cfg Config) *Client {
+	transport := otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return name + " " + r.Method
+		}))
+	return &Client{
+		name:       name,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		breaker:    NewBreaker(cfg.FailureThreshold, cfg.OpenDuration),
+		sleep:      sleep,
+	}
+}
+
+// Name returns the gateway name
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Status returns the state of the breaker of the gateway
+func (c *Client) Status() BreakerStatus {
+	return c.breaker.Status()
+}
+
+// Do sends the request built by newRequest, which is called again for each attempt so the
+// body can be re-read. 429 responses are always retried, since the gateway refused them
+// without processing; transport errors and 5xx responses are retried only when retry is
+// true, because the gateway may have acted on the request before failing. Any status is
+// returned as a Response: mapping it to an error is left to the caller. gateway.ErrCircuitOpen
+// is returned without sending anything while the breaker is open.
+func (c *Client) Do(ctx context.Context, retry bool, newRequest func(ctx context.Context) (*http.Request, error)) (*Response, error) {
+	for attempt := 0; ; attempt++ {
+		if !c.breaker.Allow() {
+			return nil, fmt.Errorf("%s: %w", c.name, gateway.ErrCircuitOpen)
+		}
+
+		resp, err := c.send(ctx, newRequest)
+		if err != nil && ctx.Err() != nil {
+			// The caller gave up, which says nothing about the gateway
+			c.breaker.Abandon()
+			return nil, err
+		}
+		failed := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		c.breaker.Record(!failed)
+		if !failed {
+			return resp, nil
+		}
+
+		throttled := err == nil && resp.StatusCode == http.StatusTooManyRequests
+		if attempt >= c.cfg.MaxRetries || (!retry && !throttled) {
+			return resp, err
+		}
+		if serr := c.sleep(ctx, c.backoff(attempt, resp)); serr != nil {
+			return nil, serr
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, newRequest func(ctx context.Context) (*http.Request, error)) (*Response, error) {
+	req, err := newRequest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// backoff returns the wait before the retry that follows attempt: the Retry-After of a
+// throttled response when given, otherwise an exponential delay with equal jitter, so
+// clients that failed together do not retry together
+func (c *Client) backoff(attempt int, resp *Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, c.cfg.MaxDelay)
+		}
+	}
+
+	delay := c.cfg.BaseDelay << attempt
+	if delay <= 0 || delay > c.cfg.MaxDelay {
+		delay = c.cfg.MaxDelay
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Registry hands out one client per gateway, so a client rebuilt after a settings change
+// keeps the breaker of the one it replaces
+type Registry struct {
+	cfg     Config
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewRegistry creates a registry whose clients use cfg
+func NewRegistry(cfg Config) *Registry {
+	return &Registry{cfg: cfg, clients: make(map[string]*Client)}
+}
+
+// Client returns the client of the named gateway, creating it on first use
+func (r *Registry) Client(name string) *Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.clients[name]; ok {
+		return c
+	}
+	c := NewClient(name, r.cfg)
+	r.clients[name] = c
+	return c
+}
+
+// Status returns the breaker state of every gateway client, by gateway name
+func (r *Registry) Status() map[string]BreakerStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := make(map[string]BreakerStatus, len(r.clients))
+	for name, c := range r.clients {
+		status[name] = c.Status()
+	}
+	return status
+}
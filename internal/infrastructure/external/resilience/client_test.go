@@ -0,0 +1,210 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/gateway"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func newTestClient(cfg Config) (*Client, *[]time.Duration) {
+	c := NewClient("test", cfg)
+	var waits []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return c, &waits
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Timeout = time.Second
+	cfg.FailureThreshold = 10
+	return cfg
+}
+
+// statusServer answers with the given statuses in turn, then 200
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *int) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= len(statuses) {
+			if statuses[calls-1] == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			w.WriteHeader(statuses[calls-1])
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func get(url string) func(ctx context.Context) (*http.Request, error) {
+	return func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	}
+}
+
+func TestDo_RetriesServerErrors(t *testing.T) {
+	server, calls := statusServer(t, http.StatusBadGateway, http.StatusServiceUnavailable)
+	c, waits := newTestClient(testConfig())
+
+	resp, err := c.Do(context.Background(), true, get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || string(resp.Body) != `{"ok":true}` {
+		t.Errorf("unexpected response: %d %s", resp.StatusCode, resp.Body)
+	}
+	if *calls != 3 || len(*waits) != 2 {
+		t.Fatalf("expected 3 attempts and 2 waits, got %d and %v", *calls, *waits)
+	}
+	for i, wait := range *waits {
+		full := testConfig().BaseDelay << i
+		if wait < full/2 || wait >= full {
+			t.Errorf("wait %d: expected a jittered delay in [%v, %v), got %v", i, full/2, full, wait)
+		}
+	}
+}
+
+func TestDo_StopsAfterMaxRetries(t *testing.T) {
+	server, calls := statusServer(t, 500, 500, 500, 500)
+	c, _ := newTestClient(testConfig())
+
+	resp, err := c.Do(context.Background(), true, get(server.URL))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 500 || *calls != 3 {
+		t.Errorf("expected the last 500 after 3 attempts, got %d after %d", resp.StatusCode, *calls)
+	}
+}
+
+func TestDo_NonRetryableRequest(t *testing.T) {
+	server, calls := statusServer(t, http.StatusInternalServerError)
+	c, _ := newTestClient(testConfig())
+
+	resp, _ := c.Do(context.Background(), false, get(server.URL))
+	if resp.StatusCode != http.StatusInternalServerError || *calls != 1 {
+		t.Errorf("expected a single attempt, got %d attempts", *calls)
+	}
+
+	server, calls = statusServer(t, http.StatusTooManyRequests)
+	resp, _ = c.Do(context.Background(), false, get(server.URL))
+	if resp.StatusCode != http.StatusOK || *calls != 2 {
+		t.Errorf("expected a throttled request to be retried, got %d after %d attempts", resp.StatusCode, *calls)
+	}
+}
+
+func TestDo_HonorsRetryAfter(t *testing.T) {
+	server, _ := statusServer(t, http.StatusTooManyRequests)
+	c, waits := newTestClient(testConfig())
+
+	if _, err := c.Do(context.Background(), true, get(server.URL)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != time.Second {
+		t.Errorf("expected to wait the Retry-After of 1s, got %v", *waits)
+	}
+}
+
+func TestDo_ClientErrorsAreNotRetried(t *testing.T) {
+	server, calls := statusServer(t, http.StatusBadRequest)
+	c, _ := newTestClient(testConfig())
+
+	resp, _ := c.Do(context.Background(), true, get(server.URL))
+	if resp.StatusCode != http.StatusBadRequest || *calls != 1 {
+		t.Errorf("expected the 400 without retries, got %d after %d attempts", resp.StatusCode, *calls)
+	}
+	if c.Status().ConsecutiveFailures != 0 {
+		t.Error("expected a 400 not to count as a gateway failure")
+	}
+}
+
+func TestDo_OpenBreaker(t *testing.T) {
+	server, calls := statusServer(t, 500, 500, 500, 500)
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+	cfg.FailureThreshold = 2
+	c, _ := newTestClient(cfg)
+
+	c.Do(context.Background(), true, get(server.URL))
+	c.Do(context.Background(), true, get(server.URL))
+	_, err := c.Do(context.Background(), true, get(server.URL))
+	if !errors.Is(err, gateway.ErrCircuitOpen) {
+		t.Fatalf("expected the breaker to be open, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("expected no request while the breaker is open, got %d", *calls)
+	}
+	if c.Status().State != StateOpen {
+		t.Errorf("expected an open breaker, got %+v", c.Status())
+	}
+}
+
+func TestRegistrySharesClients(t *testing.T) {
+	r := NewRegistry(testConfig())
+	if r.Client("asaas") != r.Client("asaas") {
+		t.Error("expected the same client for the same gateway")
+	}
+	r.Client("mercadopago")
+	if status := r.Status(); len(status) != 2 || status["asaas"].State != StateClosed {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestDo_TracesAttempts(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if len(traceparents) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	t.Cleanup(server.Close)
+	c, _ := newTestClient(testConfig())
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "checkout")
+	if _, err := c.Do(ctx, true, get(server.URL)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parent.End()
+
+	var attempts int
+	for _, span := range recorder.Ended() {
+		if span.Name() != "test GET" {
+			continue
+		}
+		attempts++
+		if span.Parent().TraceID() != parent.SpanContext().TraceID() {
+			t.Error("expected the attempt to belong to the caller's trace")
+		}
+	}
+	if attempts != 2 {
+		t.Errorf("expected a span per attempt, got %d", attempts)
+	}
+	if len(traceparents) != 2 || traceparents[0] == "" {
+		t.Errorf("expected the trace to be propagated to the gateway, got %v", traceparents)
+	}
+}
@@ -0,0 +1,123 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	netsmtp "net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
+)
+
+// Config holds the SMTP server settings
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+
+	// PixelURL returns the link of the open tracking pixel of a notification; when set,
+	// e-mails carry an HTML part with the pixel besides the plain text
+	PixelURL func(notificationID string) string
+}
+
+// Channel implements notify.Channel over SMTP with STARTTLS when the server offers it
+type Channel struct {
+	cfg  Config
+	send func(addr string, a netsmtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewChannel creates an e-mail channel
+func NewChannel(cfg Config) *Channel {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	return &Channel{cfg: cfg, send: netsmtp.SendMail}
+}
+
+// Name returns the channel identifier
+func (c *Channel) Name() string { return entity.NotificationChannelEmail }
+
+// Send e-mails the notification to the user
+func (c *Channel) Send(ctx context.Context, n *entity.Notificacao, to *entity.User) error {
+	if to.Email == "" {
+		return notify.ErrNoAddress
+	}
+	from, err := mail.ParseAddress(c.cfg.From)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid sender %q: %w", c.cfg.From, err)
+	}
+
+	var auth netsmtp.Auth
+	if c.cfg.Username != "" {
+		auth = netsmtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+	}
+	var pixel string
+	if c.cfg.PixelURL != nil {
+		pixel = c.cfg.PixelURL(n.ID)
+	}
+	msg := buildMessage(from, &mail.Address{Name: to.Nome, Address: to.Email}, n, pixel, time.Now())
+	addr := net.JoinHostPort(c.cfg.Host, strconv.Itoa(c.cfg.Port))
+
+	// net/smtp takes no context, so the send runs apart and is abandoned on cancellation
+	done := make(chan error, 1)
+	go func() { done <- c.send(addr, auth, from.Address, []string{to.Email}, msg) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("smtp: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildMessage renders a plain text UTF-8 message or, with a tracking pixel, a
+// multipart/alternative message whose HTML part loads the pixel
+func buildMessage(from, to *mail.Address, n *entity.Notificacao, pixel string, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if pixel == "" {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+		b.WriteString("\r\n")
+		b.WriteString(n.Message)
+		b.WriteString("\r\n")
+		return b.Bytes()
+	}
+
+	// Writes to a bytes.Buffer do not fail
+	mw := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	text, _ := mw.CreatePart(partHeader("text/plain"))
+	fmt.Fprintf(text, "%s\r\n", n.Message)
+	body := strings.ReplaceAll(html.EscapeString(n.Message), "\n", "<br>")
+	part, _ := mw.CreatePart(partHeader("text/html"))
+	fmt.Fprintf(part, `<!DOCTYPE html><html><body><p>%s</p><img src="%s" width="1" height="1" alt=""></body></html>`+"\r\n",
+		body, html.EscapeString(pixel))
+	mw.Close()
+	return b.Bytes()
+}
+
+func partHeader(contentType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	}
+}
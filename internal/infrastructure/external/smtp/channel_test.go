@@ -0,0 +1,82 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	netsmtp "net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
+)
+
+func TestBuildMessage(t *testing.T) {
+	n := &entity.Notificacao{Title: "Pagamento confirmado", Message: "Seu pagamento de R$ 199,90 foi confirmado."}
+	msg := string(buildMessage(
+		&mail.Address{Name: "CondoTrack", Address: "noreply@condotrack.com"},
+		&mail.Address{Name: "Ana", Address: "ana@exemplo.com"},
+		n, "", time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC),
+	))
+
+	for _, want := range []string{
+		"From: \"CondoTrack\" <noreply@condotrack.com>\r\n",
+		"To: \"Ana\" <ana@exemplo.com>\r\n",
+		"Subject: Pagamento confirmado\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nSeu pagamento de R$ 199,90 foi confirmado.\r\n",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in message:\n%s", want, msg)
+		}
+	}
+
+	n.Title = "Matrícula aprovada"
+	msg = string(buildMessage(&mail.Address{Address: "a@b.com"}, &mail.Address{Address: "c@d.com"}, n, "", time.Now()))
+	if !strings.Contains(msg, "Subject: =?utf-8?q?Matr=C3=ADcula_aprovada?=\r\n") {
+		t.Errorf("expected an encoded subject, got:\n%s", msg)
+	}
+}
+
+func TestBuildMessage_Pixel(t *testing.T) {
+	n := &entity.Notificacao{Title: "Aviso", Message: "Reunião <amanhã>\nàs 10h"}
+	msg := string(buildMessage(&mail.Address{Address: "a@b.com"}, &mail.Address{Address: "c@d.com"}, n,
+		"https://api.condotrack.com/api/v1/notifications/n-1/open.gif?expires=1&signature=ab", time.Now()))
+
+	for _, want := range []string{
+		"Content-Type: multipart/alternative; boundary=",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nReunião <amanhã>\nàs 10h\r\n",
+		"Content-Type: text/html; charset=utf-8\r\n",
+		"<p>Reunião &lt;amanhã&gt;<br>às 10h</p>",
+		`<img src="https://api.condotrack.com/api/v1/notifications/n-1/open.gif?expires=1&amp;signature=ab" width="1" height="1" alt="">`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in message:\n%s", want, msg)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	c := NewChannel(Config{Host: "smtp.exemplo.com", Username: "user", Password: "pass", From: "CondoTrack <noreply@condotrack.com>"})
+	var gotAddr, gotFrom string
+	var gotTo []string
+	c.send = func(addr string, a netsmtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo = addr, from, to
+		return nil
+	}
+	n := &entity.Notificacao{Title: "Oi", Message: "Olá"}
+
+	if err := c.Send(context.Background(), n, &entity.User{Email: "ana@exemplo.com"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotAddr != "smtp.exemplo.com:587" || gotFrom != "noreply@condotrack.com" || len(gotTo) != 1 || gotTo[0] != "ana@exemplo.com" {
+		t.Errorf("unexpected envelope: %s %s %v", gotAddr, gotFrom, gotTo)
+	}
+
+	if err := c.Send(context.Background(), n, &entity.User{}); !errors.Is(err, notify.ErrNoAddress) {
+		t.Errorf("expected ErrNoAddress without an e-mail, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package twilio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
+	"github.com/condotrack/api/pkg/phone"
+)
+
+const defaultBaseURL = "https://api.twilio.com/2010-04-01"
+
+// Config holds the Twilio credentials and the WhatsApp sender number
+type Config struct {
+	AccountSID string
+	AuthToken  string
+	From       string // E.164 number enabled for WhatsApp, e.g. +5511999990000
+}
+
+// Channel implements notify.Channel for WhatsApp over the Twilio Messages API
+type Channel struct {
+	cfg        Config
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewChannel creates a WhatsApp channel
+func NewChannel(cfg Config) *Channel {
+	return &Channel{
+		cfg:        cfg,
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name returns the channel identifier
+func (c *Channel) Name() string { return entity.NotificationChannelWhatsApp }
+
+// Send messages the notification to the WhatsApp of the user's phone
+func (c *Channel) Send(ctx context.Context, n *entity.Notificacao, to *entity.User) error {
+	if to.Phone == nil {
+		return notify.ErrNoAddress
+	}
+	number, ok := normalizePhone(*to.Phone)
+	if !ok {
+		return notify.ErrNoAddress
+	}
+
+	form := url.Values{}
+	form.Set("From", "whatsapp:"+c.cfg.From)
+	form.Set("To", "whatsapp:"+number)
+	form.Set("Body", "*"+n.Title+"*\n"+n.Message)
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", c.baseURL, url.PathEscape(c.cfg.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("twilio: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.cfg.AccountSID, c.cfg.AuthToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("twilio: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio: API error %d (status %d): %s", apiErr.Code, resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("twilio: API error (status %d)", resp.StatusCode)
+	}
+	return nil
+}
+
+// normalizePhone returns the phone in E.164, taking numbers without a country code as
+// Brazilian (+55)
+func normalizePhone(number string) (string, bool) {
+	n, err := phone.Normalize(number)
+	return n, err == nil
+}
@@ -0,0 +1,76 @@
+package twilio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
+)
+
+func TestSend(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if r.URL.Path != "/Accounts/AC123/Messages.json" || user != "AC123" || pass != "token" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		r.ParseForm()
+		if r.PostForm.Get("To") != "whatsapp:+5511987654321" || r.PostForm.Get("From") != "whatsapp:+5511900000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":21211,"message":"Invalid 'To' Phone Number"}`))
+			return
+		}
+		if r.PostForm.Get("Body") != "*Pagamento confirmado*\nObrigado!" {
+			http.Error(w, "unexpected body", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM1","status":"queued"}`))
+	}))
+	defer srv.Close()
+
+	c := NewChannel(Config{AccountSID: "AC123", AuthToken: "token", From: "+5511900000000"})
+	c.baseURL = srv.URL
+	n := &entity.Notificacao{Title: "Pagamento confirmado", Message: "Obrigado!"}
+	ctx := context.Background()
+
+	phone := "(11) 98765-4321"
+	if err := c.Send(ctx, n, &entity.User{Phone: &phone}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	other := "+1 415 555 0100"
+	if err := c.Send(ctx, n, &entity.User{Phone: &other}); err == nil || err.Error() != "twilio: API error 21211 (status 400): Invalid 'To' Phone Number" {
+		t.Errorf("expected the API error, got %v", err)
+	}
+
+	if err := c.Send(ctx, n, &entity.User{}); !errors.Is(err, notify.ErrNoAddress) {
+		t.Errorf("expected ErrNoAddress without a phone, got %v", err)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"(11) 98765-4321", "+5511987654321", true},
+		{"1133334444", "+551133334444", true},
+		{"+55 11 98765-4321", "+5511987654321", true},
+		{"+1 415 555 0100", "+14155550100", true},
+		{"5511987654321", "+5511987654321", true},
+		{"123", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := normalizePhone(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("normalizePhone(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
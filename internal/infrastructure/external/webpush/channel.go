@@ -0,0 +1,156 @@
+package webpush
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// ttl is how long the push service keeps a push for an offline browser
+	ttl = 24 * time.Hour
+	// vapidExpiry is the lifetime of the VAPID token, at most 24h by RFC 8292
+	vapidExpiry = 12 * time.Hour
+)
+
+// Channel implements notify.Channel for web push (RFC 8030) with VAPID (RFC 8292)
+// authentication. Pushes carry no payload: the service worker wakes up and fetches the
+// unread notifications of the user from the API, so nothing needs to be encrypted.
+type Channel struct {
+	key        *ecdsa.PrivateKey
+	publicKey  string
+	subject    string
+	subs       repository.PushSubscriptionRepository
+	httpClient *http.Client
+}
+
+// NewChannel creates a web push channel from the base64url encoded VAPID private key, the raw
+// 32 byte P-256 scalar. The subject is the mailto: or https: contact of the sender.
+func NewChannel(privateKey, subject string, subs repository.PushSubscriptionRepository) (*Channel, error) {
+	d, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(privateKey, "="))
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid VAPID private key: %w", err)
+	}
+	ecdhKey, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid VAPID private key: %w", err)
+	}
+	pub := ecdhKey.PublicKey().Bytes() // 0x04 || X || Y
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}
+
+	return &Channel{
+		key:        key,
+		publicKey:  base64.RawURLEncoding.EncodeToString(pub),
+		subject:    subject,
+		subs:       subs,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Name returns the channel identifier
+func (c *Channel) Name() string { return entity.NotificationChannelPush }
+
+// PublicKey returns the VAPID public key browsers subscribe with (applicationServerKey)
+func (c *Channel) PublicKey() string { return c.publicKey }
+
+// Send pushes to every browser the user subscribed. Subscriptions the push service reports
+// as gone are deleted; the delivery succeeds when at least one browser was reached.
+func (c *Channel) Send(ctx context.Context, n *entity.Notificacao, to *entity.User) error {
+	subs, err := c.subs.FindByUserID(ctx, to.ID)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	reached := 0
+	for _, sub := range subs {
+		gone, err := c.push(ctx, sub.Endpoint)
+		switch {
+		case gone:
+			if err := c.subs.DeleteByEndpoint(ctx, sub.Endpoint); err != nil {
+				lastErr = err
+			}
+		case err != nil:
+			lastErr = err
+		default:
+			reached++
+		}
+	}
+	if reached > 0 {
+		return nil
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return notify.ErrNoAddress
+}
+
+// push sends an empty push message; gone reports that the subscription expired
+func (c *Channel) push(ctx context.Context, endpoint string) (gone bool, err error) {
+	auth, err := c.authorization(endpoint)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("webpush: failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("TTL", fmt.Sprintf("%d", int(ttl.Seconds())))
+	req.Header.Set("Urgency", "normal")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("webpush: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return true, nil
+	case resp.StatusCode >= 300:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		return false, fmt.Errorf("webpush: push service error (status %d): %s", resp.StatusCode, body)
+	}
+	return false, nil
+}
+
+// authorization builds the VAPID header for the origin of the endpoint
+func (c *Channel) authorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("webpush: invalid endpoint: %w", err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(vapidExpiry).Unix(),
+		"sub": c.subject,
+	})
+	signed, err := token.SignedString(c.key)
+	if err != nil {
+		return "", fmt.Errorf("webpush: failed to sign VAPID token: %w", err)
+	}
+	return "vapid t=" + signed + ", k=" + c.publicKey, nil
+}
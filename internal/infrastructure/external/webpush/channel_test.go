@@ -0,0 +1,87 @@
+package webpush
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestChannel(t *testing.T, subs *testutil.MockPushSubscriptionRepository) *Channel {
+	t.Helper()
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewChannel(base64.RawURLEncoding.EncodeToString(key.Bytes()), "mailto:ti@condotrack.com", subs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.PublicKey() != base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()) {
+		t.Fatal("expected the public key to match the private key")
+	}
+	return c
+}
+
+func TestSend(t *testing.T) {
+	subs := testutil.NewMockPushSubscriptionRepository()
+	c := newTestChannel(t, subs)
+
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "vapid t=")
+		raw, k, _ := strings.Cut(auth, ", k=")
+		if k != c.PublicKey() || r.Header.Get("TTL") == "" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		token, err := jwt.Parse(raw, func(*jwt.Token) (interface{}, error) { return &c.key.PublicKey, nil },
+			jwt.WithValidMethods([]string{"ES256"}), jwt.WithAudience(srvURL))
+		if err != nil || !token.Valid {
+			http.Error(w, "invalid vapid token", http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	subs.Subscriptions["s1"] = &entity.PushSubscription{ID: "s1", UserID: "user-1", Endpoint: srv.URL + "/push/abc"}
+	subs.Subscriptions["s2"] = &entity.PushSubscription{ID: "s2", UserID: "user-1", Endpoint: srv.URL + "/gone"}
+	ctx := context.Background()
+	n := &entity.Notificacao{Title: "Oi"}
+
+	if err := c.Send(ctx, n, &entity.User{ID: "user-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := subs.Subscriptions["s2"]; ok {
+		t.Error("expected the expired subscription to be deleted")
+	}
+	if _, ok := subs.Subscriptions["s1"]; !ok {
+		t.Error("expected the live subscription to be kept")
+	}
+
+	if err := c.Send(ctx, n, &entity.User{ID: "user-2"}); !errors.Is(err, notify.ErrNoAddress) {
+		t.Errorf("expected ErrNoAddress without subscriptions, got %v", err)
+	}
+}
+
+func TestNewChannelRejectsInvalidKey(t *testing.T) {
+	if _, err := NewChannel("not-a-key", "mailto:ti@condotrack.com", nil); err == nil {
+		t.Error("expected an invalid key to be rejected")
+	}
+}
@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type accountingMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewAccountingMySQLRepository creates a new MySQL implementation of AccountingRepository
+func NewAccountingMySQLRepository(db *sqlx.DB) repository.AccountingRepository {
+	return &accountingMySQLRepository{db: db}
+}
+
+// accountingEntriesSelect derives the ledger from its sources: settled payments (revenue and
+// gateway fee), refunds, paid payout batches, completed transfers of unbatched splits and
+// the paid monthly fee charges of contracts.
+// The installments of a boleto plan are accounted for by their parent payment.
+// Entry keys are built from the source ID so they stay the same across exports.
+const accountingEntriesSelect = `SELECT * FROM (
+			  SELECT CONCAT('payment:', p.id) as entry_key, 'revenue' as entry_type, p.id as source_id,
+			  p.paid_at as occurred_at, p.gross_amount - p.discount_amount as amount,
+			  CONCAT('Matrícula ', p.enrollment_id) as description, p.payer_name as counterparty,
+			  p.payer_cpf as counterparty_document, p.payment_method
+			  FROM payments p
+			  WHERE p.installment_of IS NULL AND p.paid_at IS NOT NULL AND p.status IN ('confirmed', 'received', 'partially_refunded', 'refunded')
+			  AND p.gross_amount - p.discount_amount > 0
+			  UNION ALL
+			  SELECT CONCAT('fee:', p.id), 'gateway_fee', p.id, p.paid_at, p.gateway_fee,
+			  CONCAT('Tarifa ', p.gateway, ' - matrícula ', p.enrollment_id), p.gateway, NULL, p.payment_method
+			  FROM payments p
+			  WHERE p.installment_of IS NULL AND p.paid_at IS NOT NULL AND p.status IN ('confirmed', 'received', 'partially_refunded', 'refunded')
+			  AND p.gateway_fee > 0
+			  UNION ALL
+			  SELECT CONCAT('refund:', p.id), 'refund', p.id, p.refunded_at, p.refunded_amount,
+			  CONCAT('Estorno - matrícula ', p.enrollment_id), p.payer_name, p.payer_cpf, p.payment_method
+			  FROM payments p
+			  WHERE p.installment_of IS NULL AND p.refunded_at IS NOT NULL AND p.refunded_amount > 0
+			  UNION ALL
+			  SELECT CONCAT('payout:', b.id), 'instructor_payout', b.id, b.paid_at, b.total_amount,
+			  CONCAT('Repasse instrutor - lote ', b.id), u.name, a.owner_document, 'bank_transfer'
+			  FROM payout_batches b
+			  LEFT JOIN users u ON u.id = b.instructor_id
+			  LEFT JOIN instructor_payout_accounts a ON a.instructor_id = b.instructor_id
+			  WHERE b.status = 'paid' AND b.paid_at IS NOT NULL AND b.total_amount > 0
+			  UNION ALL
+			  SELECT CONCAT('transfer:', s.id), 'instructor_payout', s.id, s.transferred_at, s.instructor_amount,
+			  CONCAT('Repasse instrutor - matrícula ', s.enrollment_id), u.name, a.owner_document, 'bank_transfer'
+			  FROM revenue_splits s
+			  LEFT JOIN users u ON u.id = s.instructor_id
+			  LEFT JOIN instructor_payout_accounts a ON a.instructor_id = s.instructor_id
+			  WHERE s.transfer_status = 'done' AND s.transferred_at IS NOT NULL AND s.payout_batch_id IS NULL
+			  AND s.instructor_amount > 0
+			  UNION ALL
+			  SELECT CONCAT('contract_charge:', ch.id), 'revenue', ch.id, ch.paid_at, COALESCE(ch.paid_amount, ch.amount),
+			  ch.description, bp.payer_name, bp.payer_document, ch.billing_type
+			  FROM contract_charges ch
+			  INNER JOIN contract_billing_plans bp ON bp.id = ch.plan_id
+			  WHERE ch.paid_at IS NOT NULL AND ch.status IN ('paid', 'refunded')
+			  UNION ALL
+			  SELECT CONCAT('contract_fee:', ch.id), 'gateway_fee', ch.id, ch.paid_at, ch.gateway_fee,
+			  CONCAT('Tarifa ', ch.gateway, ' - ', ch.description), ch.gateway, NULL, ch.billing_type
+			  FROM contract_charges ch
+			  WHERE ch.paid_at IS NOT NULL AND ch.status IN ('paid', 'refunded') AND ch.gateway_fee > 0
+			  UNION ALL
+			  SELECT CONCAT('contract_refund:', ch.id), 'refund', ch.id, COALESCE(ch.updated_at, ch.paid_at),
+			  COALESCE(ch.paid_amount, ch.amount), CONCAT('Estorno - ', ch.description), bp.payer_name,
+			  bp.payer_document, ch.billing_type
+			  FROM contract_charges ch
+			  INNER JOIN contract_billing_plans bp ON bp.id = ch.plan_id
+			  WHERE ch.status = 'refunded' AND ch.paid_at IS NOT NULL
+			  ) e`
+
+const accountingCloseJobSelect = `SELECT id, period, format, status, file_key, line_count, total_amount, error,
+			  created_by, created_at, started_at, finished_at
+			  FROM accounting_close_jobs`
+
+const accountingRunSelect = `SELECT id, layout, entry_count, total_amount, created_by, created_at
+			  FROM accounting_sync_runs`
+
+func (r *accountingMySQLRepository) FindEntries(ctx context.Context, from, to time.Time) ([]entity.AccountingEntry, error) {
+	var entries []entity.AccountingEntry
+	query := accountingEntriesSelect + ` WHERE e.occurred_at >= ? AND e.occurred_at < ?
+			  ORDER BY e.occurred_at, e.entry_key`
+	err := r.db.SelectContext(ctx, &entries, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *accountingMySQLRepository) FindUnexportedEntries(ctx context.Context, layout string, since time.Time, limit int) ([]entity.AccountingEntry, error) {
+	var entries []entity.AccountingEntry
+	query := accountingEntriesSelect + ` WHERE e.occurred_at >= ?
+			  AND NOT EXISTS (SELECT 1 FROM accounting_exported_entries x WHERE x.layout = ? AND x.entry_key = e.entry_key)
+			  ORDER BY e.occurred_at, e.entry_key
+			  LIMIT ?`
+	err := r.db.SelectContext(ctx, &entries, query, since, layout, limit)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *accountingMySQLRepository) FindRunEntries(ctx context.Context, runID string) ([]entity.AccountingEntry, error) {
+	var entries []entity.AccountingEntry
+	query := accountingEntriesSelect + `
+			  INNER JOIN accounting_exported_entries x ON x.entry_key = e.entry_key
+			  WHERE x.run_id = ?
+			  ORDER BY e.occurred_at, e.entry_key`
+	err := r.db.SelectContext(ctx, &entries, query, runID)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *accountingMySQLRepository) FindCursors(ctx context.Context) ([]entity.AccountingSyncCursor, error) {
+	var cursors []entity.AccountingSyncCursor
+	query := `SELECT layout, last_occurred_at, last_run_id, updated_at FROM accounting_sync_cursors ORDER BY layout`
+	err := r.db.SelectContext(ctx, &cursors, query)
+	if err != nil {
+		return nil, err
+	}
+	return cursors, nil
+}
+
+func (r *accountingMySQLRepository) LockCursorWithTx(ctx context.Context, tx *sqlx.Tx, layout string) (*entity.AccountingSyncCursor, error) {
+	if _, err := tx.ExecContext(ctx, `INSERT IGNORE INTO accounting_sync_cursors (layout) VALUES (?)`, layout); err != nil {
+		return nil, err
+	}
+
+	var cursor entity.AccountingSyncCursor
+	query := `SELECT layout, last_occurred_at, last_run_id, updated_at FROM accounting_sync_cursors
+			  WHERE layout = ? FOR UPDATE`
+	err := tx.GetContext(ctx, &cursor, query, layout)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func (r *accountingMySQLRepository) UpdateCursorWithTx(ctx context.Context, tx *sqlx.Tx, cursor *entity.AccountingSyncCursor) error {
+	query := `UPDATE accounting_sync_cursors SET last_occurred_at = ?, last_run_id = ?, updated_at = NOW()
+			  WHERE layout = ?`
+	_, err := tx.ExecContext(ctx, query, cursor.LastOccurredAt, cursor.LastRunID, cursor.Layout)
+	return err
+}
+
+func (r *accountingMySQLRepository) CreateRunWithTx(ctx context.Context, tx *sqlx.Tx, run *entity.AccountingSyncRun, entryKeys []string) error {
+	query := `INSERT INTO accounting_sync_runs (id, layout, entry_count, total_amount, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, NOW())`
+	if _, err := tx.ExecContext(ctx, query, run.ID, run.Layout, run.EntryCount, run.TotalAmount, run.CreatedBy); err != nil {
+		return err
+	}
+
+	insert := `INSERT INTO accounting_exported_entries (layout, entry_key, run_id, exported_at)`
+	return insertBatch(ctx, tx, insert, "(?, ?, ?, NOW())", len(entryKeys), func(i int) []interface{} {
+		return []interface{}{run.Layout, entryKeys[i], run.ID}
+	})
+}
+
+func (r *accountingMySQLRepository) FindRuns(ctx context.Context, layout string, limit int) ([]entity.AccountingSyncRun, error) {
+	var runs []entity.AccountingSyncRun
+	query := accountingRunSelect + ` WHERE 1=1`
+	var args []interface{}
+
+	if layout != "" {
+		query += ` AND layout = ?`
+		args = append(args, layout)
+	}
+	query += ` ORDER BY created_at DESC LIMIT ?`
+	args = append(args, limit)
+
+	err := r.db.SelectContext(ctx, &runs, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *accountingMySQLRepository) FindRunByID(ctx context.Context, id string) (*entity.AccountingSyncRun, error) {
+	var run entity.AccountingSyncRun
+	err := r.db.GetContext(ctx, &run, accountingRunSelect+` WHERE id = ?`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *accountingMySQLRepository) CreateCloseJob(ctx context.Context, job *entity.AccountingCloseJob) error {
+	query := `INSERT INTO accounting_close_jobs (id, period, format, status, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, job.ID, job.Period, job.Format, job.Status, job.CreatedBy, job.CreatedAt)
+	return err
+}
+
+func (r *accountingMySQLRepository) FindCloseJobs(ctx context.Context, limit int) ([]entity.AccountingCloseJob, error) {
+	var jobs []entity.AccountingCloseJob
+	err := r.db.SelectContext(ctx, &jobs, accountingCloseJobSelect+` ORDER BY created_at DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *accountingMySQLRepository) FindCloseJobByID(ctx context.Context, id string) (*entity.AccountingCloseJob, error) {
+	var job entity.AccountingCloseJob
+	err := r.db.GetContext(ctx, &job, accountingCloseJobSelect+` WHERE id = ?`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *accountingMySQLRepository) ClaimCloseJob(ctx context.Context, staleBefore time.Time) (*entity.AccountingCloseJob, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED lets several instances claim different jobs at the same time
+	var job entity.AccountingCloseJob
+	query := accountingCloseJobSelect + ` WHERE status = 'pending' OR (status = 'running' AND started_at < ?)
+			  ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED`
+	if err := tx.GetContext(ctx, &job, query, staleBefore); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := tx.ExecContext(ctx, `UPDATE accounting_close_jobs SET status = 'running', started_at = ? WHERE id = ?`, now, job.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	job.Status = entity.AccountingCloseStatusRunning
+	job.StartedAt = &now
+	return &job, nil
+}
+
+func (r *accountingMySQLRepository) FinishCloseJob(ctx context.Context, job *entity.AccountingCloseJob) error {
+	query := `UPDATE accounting_close_jobs SET status = ?, file_key = ?, line_count = ?, total_amount = ?,
+			  error = ?, finished_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, job.Status, job.FileKey, job.LineCount, job.TotalAmount,
+		job.Error, job.FinishedAt, job.ID)
+	return err
+}
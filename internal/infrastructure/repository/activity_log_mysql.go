@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type activityLogMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewActivityLogMySQLRepository creates a new MySQL implementation of ActivityLogRepository.
+// reader serves the listing and may be a read replica; writes go to db.
+func NewActivityLogMySQLRepository(db, reader *sqlx.DB) repository.ActivityLogRepository {
+	return &activityLogMySQLRepository{db: db, reader: reader}
+}
+
+func (r *activityLogMySQLRepository) Create(ctx context.Context, l *entity.ActivityLog) error {
+	query := `INSERT INTO activity_logs (id, user_id, user_role, action, entity, entity_id, route, diff,
+			  ip_address, request_id, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		l.ID, l.UserID, l.UserRole, l.Action, l.Entity, l.EntityID, l.Route, l.Diff,
+		l.IPAddress, l.RequestID, l.CreatedAt)
+	return err
+}
+
+func (r *activityLogMySQLRepository) FindAll(ctx context.Context, filters entity.ActivityLogFilters) ([]entity.ActivityLog, int, error) {
+	where := []string{"1=1"}
+	args := []interface{}{}
+
+	if filters.UserID != "" {
+		where = append(where, "user_id = ?")
+		args = append(args, filters.UserID)
+	}
+	if filters.Entity != "" {
+		where = append(where, "entity = ?")
+		args = append(args, filters.Entity)
+	}
+	if filters.EntityID != "" {
+		where = append(where, "entity_id = ?")
+		args = append(args, filters.EntityID)
+	}
+	if filters.Action != "" {
+		where = append(where, "action = ?")
+		args = append(args, filters.Action)
+	}
+	if filters.DateFrom != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *filters.DateFrom)
+	}
+	if filters.DateTo != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *filters.DateTo)
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.reader.GetContext(ctx, &total, `SELECT COUNT(*) FROM activity_logs WHERE `+whereClause, args...); err != nil {
+		return nil, 0, err
+	}
+
+	page := filters.Page
+	if page < 1 {
+		page = 1
+	}
+	perPage := filters.PerPage
+	if perPage < 1 {
+		perPage = 50
+	}
+
+	query := fmt.Sprintf(`SELECT id, user_id, user_role, action, entity, entity_id, route, diff, ip_address,
+			  request_id, created_at
+			  FROM activity_logs WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?`, whereClause)
+	var logs []entity.ActivityLog
+	if err := r.reader.SelectContext(ctx, &logs, query, append(args, perPage, (page-1)*perPage)...); err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
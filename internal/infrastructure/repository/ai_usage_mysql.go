@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type aiUsageMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewAIUsageMySQLRepository creates a new MySQL implementation of AIUsageRepository. reader serves
+// the report queries and may be a read replica; writes go to db.
+func NewAIUsageMySQLRepository(db, reader *sqlx.DB) repository.AIUsageRepository {
+	return &aiUsageMySQLRepository{db: db, reader: reader}
+}
+
+func (r *aiUsageMySQLRepository) Create(ctx context.Context, u *entity.AIUsage) error {
+	query := `INSERT INTO ai_usage (id, provider, model, feature, user_id, input_tokens, output_tokens, latency_ms,
+			  success, error_message, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		u.ID, u.Provider, u.Model, u.Feature, u.UserID, u.InputTokens, u.OutputTokens, u.LatencyMs,
+		u.Success, u.Error)
+	return err
+}
+
+func (r *aiUsageMySQLRepository) Summarize(ctx context.Context, filters entity.AIUsageFilters) ([]entity.AIUsageSummary, error) {
+	query := `SELECT provider, model, COUNT(*) AS calls, SUM(CASE WHEN success THEN 0 ELSE 1 END) AS failures,
+			  COALESCE(SUM(input_tokens), 0) AS input_tokens, COALESCE(SUM(output_tokens), 0) AS output_tokens
+			  FROM ai_usage WHERE 1=1`
+	args := []interface{}{}
+
+	if filters.From != nil {
+		query += ` AND created_at >= ?`
+		args = append(args, *filters.From)
+	}
+	if filters.To != nil {
+		query += ` AND created_at < ?`
+		args = append(args, *filters.To)
+	}
+	if filters.Feature != "" {
+		query += ` AND feature = ?`
+		args = append(args, filters.Feature)
+	}
+	query += ` GROUP BY provider, model ORDER BY provider, model`
+
+	var summary []entity.AIUsageSummary
+	if err := r.reader.SelectContext(ctx, &summary, query, args...); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type apiRequestMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewAPIRequestMySQLRepository creates a new MySQL implementation of APIRequestRepository. reader serves
+// the report queries and may be a read replica; writes go to db.
+func NewAPIRequestMySQLRepository(db, reader *sqlx.DB) repository.APIRequestRepository {
+	return &apiRequestMySQLRepository{db: db, reader: reader}
+}
+
+const apiRequestColumns = `id, method, path, route, actor_id, actor_role, status, latency_ms, client_ip,
+			  request_id, body, created_at`
+
+func (r *apiRequestMySQLRepository) Create(ctx context.Context, req *entity.APIRequest) error {
+	query := `INSERT INTO api_requests (id, method, path, route, actor_id, actor_role, status, latency_ms,
+			  client_ip, request_id, body, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		req.ID, req.Method, req.Path, req.Route, req.ActorID, req.ActorRole, req.Status, req.LatencyMs,
+		req.ClientIP, req.RequestID, req.Body)
+	return err
+}
+
+func (r *apiRequestMySQLRepository) FindAll(ctx context.Context, filters repository.APIRequestFilters) ([]entity.APIRequest, int, error) {
+	where := []string{"1=1"}
+	args := []interface{}{}
+
+	if filters.ActorID != "" {
+		where = append(where, "actor_id = ?")
+		args = append(args, filters.ActorID)
+	}
+	if filters.Method != "" {
+		where = append(where, "method = ?")
+		args = append(args, filters.Method)
+	}
+	if filters.PathPrefix != "" {
+		where = append(where, "path LIKE ?")
+		args = append(args, strings.NewReplacer("%", `\%`, "_", `\_`).Replace(filters.PathPrefix)+"%")
+	}
+	if filters.Status != 0 {
+		where = append(where, "status = ?")
+		args = append(args, filters.Status)
+	}
+	if filters.DateFrom != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *filters.DateFrom)
+	}
+	if filters.DateTo != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *filters.DateTo)
+	}
+
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM api_requests WHERE %s`, whereClause)
+	if err := r.reader.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	page := filters.Page
+	if page < 1 {
+		page = 1
+	}
+	perPage := filters.PerPage
+	if perPage < 1 {
+		perPage = 50
+	}
+	offset := (page - 1) * perPage
+
+	query := fmt.Sprintf(`SELECT %s FROM api_requests WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?`,
+		apiRequestColumns, whereClause)
+	args = append(args, perPage, offset)
+
+	var requests []entity.APIRequest
+	if err := r.reader.SelectContext(ctx, &requests, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return requests, total, nil
+}
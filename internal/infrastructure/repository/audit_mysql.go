@@ -3,7 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
@@ -19,17 +22,93 @@ func NewAuditMySQLRepository(db *sqlx.DB) repository.AuditRepository {
 	return &auditMySQLRepository{db: db}
 }
 
-func (r *auditMySQLRepository) FindAll(ctx context.Context) ([]entity.Audit, error) {
-	var audits []entity.Audit
-	query := `SELECT id, contract_id, auditor_name, audit_date, score, target_score,
-			  previous_score, status, observations, COALESCE(data_json, '{}') as data_json, created_at, updated_at
-			  FROM audits
-			  ORDER BY audit_date DESC`
-	err := r.db.SelectContext(ctx, &audits, query)
+// auditListSchema lists the fields accepted in audit filter[...] and sort parameters
+var auditListSchema = listSchema{
+	"status":        {expr: "a.status", kind: kindString, sortable: true},
+	"contract_id":   {expr: "a.contract_id", kind: kindString},
+	"contract_name": {expr: "c.nome", kind: kindString, sortable: true},
+	"auditor_name":  {expr: "a.auditor_name", kind: kindString, sortable: true},
+	"score":         {expr: "a.score", kind: kindNumber, sortable: true},
+	"target_score":  {expr: "a.target_score", kind: kindNumber, sortable: true},
+	"audit_date":    {expr: "a.audit_date", kind: kindTime, sortable: true},
+	"created_at":    {expr: "a.created_at", kind: kindTime, sortable: true},
+	"updated_at":    {expr: "a.updated_at", kind: kindTime, sortable: true},
+}
+
+func (r *auditMySQLRepository) FindAll(ctx context.Context, filters repository.AuditFilters) ([]entity.AuditWithContract, int, error) {
+	where := []string{"1=1"}
+	args := []interface{}{}
+
+	if filters.ContractID != "" {
+		where = append(where, "a.contract_id = ?")
+		args = append(args, filters.ContractID)
+	}
+	if filters.Status != "" {
+		where = append(where, "a.status = ?")
+		args = append(args, filters.Status)
+	}
+	if filters.Auditor != "" {
+		where = append(where, "a.auditor_name LIKE ?")
+		args = append(args, "%"+filters.Auditor+"%")
+	}
+	if filters.DateFrom != nil {
+		where = append(where, "a.audit_date >= ?")
+		args = append(args, *filters.DateFrom)
+	}
+	if filters.DateTo != nil {
+		where = append(where, "a.audit_date < ?")
+		args = append(args, filters.DateTo.AddDate(0, 0, 1))
+	}
+
+	conditions, queryArgs, err := auditListSchema.where(filters.Query)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return audits, nil
+	where = append(where, conditions...)
+	args = append(args, queryArgs...)
+	orderBy, err := auditListSchema.orderBy(filters.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	if orderBy == "" {
+		orderBy = "a.audit_date DESC"
+	}
+
+	whereClause := strings.Join(where, " AND ")
+	from := `FROM audits a
+			  LEFT JOIN contratos c ON c.id = a.contract_id
+			  LEFT JOIN gestores g ON g.id = c.gestor_id`
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) %s WHERE %s`, from, whereClause)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, err
+	}
+
+	page := filters.Page
+	if page < 1 {
+		page = 1
+	}
+	perPage := filters.PerPage
+	if perPage < 1 {
+		perPage = 20
+	}
+	offset := (page - 1) * perPage
+
+	query := fmt.Sprintf(`SELECT a.id, a.contract_id, a.auditor_name, a.audit_date, a.score, a.target_score,
+			  a.previous_score, a.status, a.observations, COALESCE(a.data_json, '{}') as data_json, a.created_at, a.updated_at,
+			  COALESCE(c.nome, '') as contract_name, COALESCE(g.nome, '') as gestor_name
+			  %s
+			  WHERE %s
+			  ORDER BY %s
+			  LIMIT ? OFFSET ?`, from, whereClause, orderBy)
+	args = append(args, perPage, offset)
+
+	var audits []entity.AuditWithContract
+	if err := r.db.SelectContext(ctx, &audits, query, args...); err != nil {
+		return nil, 0, err
+	}
+	return audits, total, nil
 }
 
 func (r *auditMySQLRepository) FindByID(ctx context.Context, id string) (*entity.Audit, error) {
@@ -62,22 +141,6 @@ func (r *auditMySQLRepository) FindByContractID(ctx context.Context, contractID
 	return audits, nil
 }
 
-func (r *auditMySQLRepository) FindAllWithContract(ctx context.Context) ([]entity.AuditWithContract, error) {
-	var audits []entity.AuditWithContract
-	query := `SELECT a.id, a.contract_id, a.auditor_name, a.audit_date, a.score, a.target_score,
-			  a.previous_score, a.status, a.observations, COALESCE(a.data_json, '{}') as data_json, a.created_at, a.updated_at,
-			  c.nome as contract_name, g.nome as gestor_name
-			  FROM audits a
-			  INNER JOIN contratos c ON c.id = a.contract_id
-			  INNER JOIN gestores g ON g.id = c.gestor_id
-			  ORDER BY a.audit_date DESC`
-	err := r.db.SelectContext(ctx, &audits, query)
-	if err != nil {
-		return nil, err
-	}
-	return audits, nil
-}
-
 func (r *auditMySQLRepository) FindLastByContractID(ctx context.Context, contractID string) (*entity.Audit, error) {
 	var audit entity.Audit
 	query := `SELECT id, contract_id, auditor_name, audit_date, score, target_score,
@@ -205,6 +268,61 @@ func (r *auditMySQLRepository) GetAverageScoreByContractID(ctx context.Context,
 	return avg, err
 }
 
+func (r *auditMySQLRepository) FindAISummary(ctx context.Context, auditID string) (*entity.AuditAISummary, error) {
+	var row struct {
+		Summary sql.NullString `db:"ai_summary"`
+		Hash    sql.NullString `db:"ai_summary_hash"`
+	}
+	query := `SELECT ai_summary, ai_summary_hash FROM audits WHERE id = ?`
+	err := r.db.GetContext(ctx, &row, query, auditID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !row.Summary.Valid {
+		return nil, nil
+	}
+
+	var summary entity.AuditAISummary
+	if err := json.Unmarshal([]byte(row.Summary.String), &summary); err != nil {
+		return nil, err
+	}
+	summary.InputHash = row.Hash.String
+	return &summary, nil
+}
+
+func (r *auditMySQLRepository) SaveAISummary(ctx context.Context, auditID string, summary *entity.AuditAISummary) error {
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return err
+	}
+	// updated_at is left alone: caching a summary is not an edit of the audit
+	query := `UPDATE audits SET ai_summary = ?, ai_summary_hash = ? WHERE id = ?`
+	_, err = r.db.ExecContext(ctx, query, string(data), summary.InputHash, auditID)
+	return err
+}
+
+func (r *auditMySQLRepository) FindDataJSON(ctx context.Context, afterID string, limit int) ([]entity.AuditDataRecord, error) {
+	var records []entity.AuditDataRecord
+	query := `SELECT id, data_json FROM audits
+			  WHERE id > ? AND data_json IS NOT NULL
+			  ORDER BY id
+			  LIMIT ?`
+	if err := r.db.SelectContext(ctx, &records, query, afterID, limit); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (r *auditMySQLRepository) UpdateDataJSON(ctx context.Context, id string, data json.RawMessage) error {
+	// updated_at is left alone: upgrading the schema does not change the audit
+	query := `UPDATE audits SET data_json = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, data, id)
+	return err
+}
+
 // AuditItemMySQLRepository implementation
 type auditItemMySQLRepository struct {
 	db *sqlx.DB
@@ -250,31 +368,19 @@ func (r *auditItemMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx
 }
 
 func (r *auditItemMySQLRepository) CreateBatch(ctx context.Context, items []entity.AuditItem) error {
-	if len(items) == 0 {
-		return nil
-	}
-	query := `INSERT INTO audit_items (id, audit_id, category_id, item_name, score, max_score, observation, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, NOW())`
-	for _, item := range items {
-		if _, err := r.db.ExecContext(ctx, query, item.ID, item.AuditID, item.CategoryID, item.ItemName, item.Score, item.MaxScore, item.Observation); err != nil {
-			return err
-		}
-	}
-	return nil
+	return insertAuditItems(ctx, r.db, items)
 }
 
 func (r *auditItemMySQLRepository) CreateBatchWithTx(ctx context.Context, tx *sqlx.Tx, items []entity.AuditItem) error {
-	if len(items) == 0 {
-		return nil
-	}
-	query := `INSERT INTO audit_items (id, audit_id, category_id, item_name, score, max_score, observation, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, NOW())`
-	for _, item := range items {
-		if _, err := tx.ExecContext(ctx, query, item.ID, item.AuditID, item.CategoryID, item.ItemName, item.Score, item.MaxScore, item.Observation); err != nil {
-			return err
-		}
-	}
-	return nil
+	return insertAuditItems(ctx, tx, items)
+}
+
+func insertAuditItems(ctx context.Context, exec sqlx.ExecerContext, items []entity.AuditItem) error {
+	insert := `INSERT INTO audit_items (id, audit_id, category_id, item_name, score, max_score, observation, created_at)`
+	return insertBatch(ctx, exec, insert, "(?, ?, ?, ?, ?, ?, ?, NOW())", len(items), func(i int) []interface{} {
+		item := items[i]
+		return []interface{}{item.ID, item.AuditID, item.CategoryID, item.ItemName, item.Score, item.MaxScore, item.Observation}
+	})
 }
 
 func (r *auditItemMySQLRepository) Delete(ctx context.Context, id string) error {
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// batchInsertRows caps the rows of one multi-row INSERT, keeping statements far below MySQL's
+// 65535 placeholders and max_allowed_packet
+const batchInsertRows = 500
+
+// insertBatch writes n rows with as few INSERT statements as possible. insert is the statement
+// up to VALUES, row the placeholders of one row, e.g. "(?, ?, NOW())", and args the values of
+// row i. Rows are sent in chunks of batchInsertRows; run it on a transaction when the whole
+// batch must be stored or none of it.
+func insertBatch(ctx context.Context, exec sqlx.ExecerContext, insert, row string, n int, args func(i int) []interface{}) error {
+	perRow := strings.Count(row, "?")
+	for start := 0; start < n; start += batchInsertRows {
+		end := min(start+batchInsertRows, n)
+		rows := make([]string, 0, end-start)
+		values := make([]interface{}, 0, (end-start)*perRow)
+		for i := start; i < end; i++ {
+			rows = append(rows, row)
+			values = append(values, args(i)...)
+		}
+		if _, err := exec.ExecContext(ctx, insert+" VALUES "+strings.Join(rows, ", "), values...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// recordingExecer keeps the statements it is asked to run in place of a database
+type recordingExecer struct {
+	statements []string
+	args       int
+}
+
+func (e *recordingExecer) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.statements = append(e.statements, query)
+	e.args += len(args)
+	return nil, nil
+}
+
+func auditItems(n int) []entity.AuditItem {
+	items := make([]entity.AuditItem, n)
+	for i := range items {
+		items[i] = entity.AuditItem{ID: fmt.Sprintf("item-%d", i), AuditID: "audit-1", ItemName: "Extintores", Score: 8, MaxScore: 10}
+	}
+	return items
+}
+
+func TestInsertAuditItems_Chunked(t *testing.T) {
+	exec := &recordingExecer{}
+	if err := insertAuditItems(context.Background(), exec, auditItems(2*batchInsertRows+1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(exec.statements) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(exec.statements))
+	}
+	if rows := strings.Count(exec.statements[0], "NOW()"); rows != batchInsertRows {
+		t.Errorf("expected %d rows in a full chunk, got %d", batchInsertRows, rows)
+	}
+	if rows := strings.Count(exec.statements[2], "NOW()"); rows != 1 {
+		t.Errorf("expected the remaining row in the last chunk, got %d", rows)
+	}
+	if exec.args != 7*(2*batchInsertRows+1) {
+		t.Errorf("expected 7 values per item, got %d", exec.args)
+	}
+
+	exec = &recordingExecer{}
+	insertAuditItems(context.Background(), exec, nil)
+	if len(exec.statements) != 0 {
+		t.Errorf("expected no statement without items, got %d", len(exec.statements))
+	}
+}
+
+// BenchmarkInsertAuditItems measures building the statements of an audit; statements/op is
+// the number of round trips a database would take
+func BenchmarkInsertAuditItems(b *testing.B) {
+	for _, n := range []int{10, 150, 1200} {
+		items := auditItems(n)
+		b.Run(fmt.Sprintf("items=%d", n), func(b *testing.B) {
+			var exec *recordingExecer
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				exec = &recordingExecer{}
+				if err := insertAuditItems(context.Background(), exec, items); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(exec.statements)), "statements/op")
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
@@ -23,7 +24,7 @@ func (r *certificadoMySQLRepository) FindByID(ctx context.Context, id string) (*
 	var cert entity.Certificado
 	query := `SELECT id, enrollment_id, student_id, student_name, student_cpf, course_id, course_name,
 			  course_hours, instructor_name, completion_date, issue_date, validation_code, status,
-			  download_url, created_at
+			  download_url, pdf_key, created_at
 			  FROM certificates
 			  WHERE id = ?`
 	err := r.db.GetContext(ctx, &cert, query, id)
@@ -40,7 +41,7 @@ func (r *certificadoMySQLRepository) FindByValidationCode(ctx context.Context, c
 	var cert entity.Certificado
 	query := `SELECT id, enrollment_id, student_id, student_name, student_cpf, course_id, course_name,
 			  course_hours, instructor_name, completion_date, issue_date, validation_code, status,
-			  download_url, created_at
+			  download_url, pdf_key, created_at
 			  FROM certificates
 			  WHERE validation_code = ?`
 	err := r.db.GetContext(ctx, &cert, query, code)
@@ -57,7 +58,7 @@ func (r *certificadoMySQLRepository) FindByStudentID(ctx context.Context, studen
 	var certs []entity.Certificado
 	query := `SELECT id, enrollment_id, student_id, student_name, student_cpf, course_id, course_name,
 			  course_hours, instructor_name, completion_date, issue_date, validation_code, status,
-			  download_url, created_at
+			  download_url, pdf_key, created_at
 			  FROM certificates
 			  WHERE student_id = ?
 			  ORDER BY created_at DESC`
@@ -69,7 +70,7 @@ func (r *certificadoMySQLRepository) FindByEnrollmentID(ctx context.Context, enr
 	var cert entity.Certificado
 	query := `SELECT id, enrollment_id, student_id, student_name, student_cpf, course_id, course_name,
 			  course_hours, instructor_name, completion_date, issue_date, validation_code, status,
-			  download_url, created_at
+			  download_url, pdf_key, created_at
 			  FROM certificates
 			  WHERE enrollment_id = ?`
 	err := r.db.GetContext(ctx, &cert, query, enrollmentID)
@@ -85,23 +86,24 @@ func (r *certificadoMySQLRepository) FindByEnrollmentID(ctx context.Context, enr
 func (r *certificadoMySQLRepository) Create(ctx context.Context, cert *entity.Certificado) error {
 	query := `INSERT INTO certificates (id, enrollment_id, student_id, student_name, student_cpf,
 			  course_id, course_name, course_hours, instructor_name, completion_date, issue_date,
-			  validation_code, status, download_url, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+			  validation_code, status, download_url, pdf_key, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
 	_, err := r.db.ExecContext(ctx, query,
 		cert.ID, cert.EnrollmentID, cert.StudentID, cert.StudentName, cert.StudentCPF,
 		cert.CourseID, cert.CourseName, cert.CourseHours, cert.InstructorName,
-		cert.CompletionDate, cert.IssueDate, cert.ValidationCode, cert.Status, cert.DownloadURL)
+		cert.CompletionDate, cert.IssueDate, cert.ValidationCode, cert.Status, cert.DownloadURL, cert.PDFKey)
 	return err
 }
 
 func (r *certificadoMySQLRepository) Update(ctx context.Context, cert *entity.Certificado) error {
 	query := `UPDATE certificates SET
 			  student_name = ?, student_cpf = ?, course_name = ?, course_hours = ?,
-			  instructor_name = ?, completion_date = ?, issue_date = ?, status = ?, download_url = ?
+			  instructor_name = ?, completion_date = ?, issue_date = ?, status = ?, download_url = ?,
+			  pdf_key = ?
 			  WHERE id = ?`
 	_, err := r.db.ExecContext(ctx, query,
 		cert.StudentName, cert.StudentCPF, cert.CourseName, cert.CourseHours,
-		cert.InstructorName, cert.CompletionDate, cert.IssueDate, cert.Status, cert.DownloadURL, cert.ID)
+		cert.InstructorName, cert.CompletionDate, cert.IssueDate, cert.Status, cert.DownloadURL, cert.PDFKey, cert.ID)
 	return err
 }
 
@@ -134,9 +136,24 @@ func NewNotificacaoMySQLRepository(db *sqlx.DB) repository.NotificacaoRepository
 	return &notificacaoMySQLRepository{db: db}
 }
 
+func (r *notificacaoMySQLRepository) FindByID(ctx context.Context, id string) (*entity.Notificacao, error) {
+	var notif entity.Notificacao
+	query := `SELECT id, user_id, type, title, message, data, broadcast_id, is_read, read_at, created_at
+			  FROM notifications
+			  WHERE id = ?`
+	err := r.db.GetContext(ctx, &notif, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &notif, nil
+}
+
 func (r *notificacaoMySQLRepository) FindByUserID(ctx context.Context, userID string) ([]entity.Notificacao, error) {
 	var notifs []entity.Notificacao
-	query := `SELECT id, user_id, type, title, message, data, is_read, read_at, created_at
+	query := `SELECT id, user_id, type, title, message, data, broadcast_id, is_read, read_at, created_at
 			  FROM notifications
 			  WHERE user_id = ?
 			  ORDER BY created_at DESC`
@@ -146,7 +163,7 @@ func (r *notificacaoMySQLRepository) FindByUserID(ctx context.Context, userID st
 
 func (r *notificacaoMySQLRepository) FindUnreadByUserID(ctx context.Context, userID string) ([]entity.Notificacao, error) {
 	var notifs []entity.Notificacao
-	query := `SELECT id, user_id, type, title, message, data, is_read, read_at, created_at
+	query := `SELECT id, user_id, type, title, message, data, broadcast_id, is_read, read_at, created_at
 			  FROM notifications
 			  WHERE user_id = ? AND is_read = 0
 			  ORDER BY created_at DESC`
@@ -155,9 +172,9 @@ func (r *notificacaoMySQLRepository) FindUnreadByUserID(ctx context.Context, use
 }
 
 func (r *notificacaoMySQLRepository) Create(ctx context.Context, notif *entity.Notificacao) error {
-	query := `INSERT INTO notifications (id, user_id, type, title, message, data, is_read, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, 0, NOW())`
-	_, err := r.db.ExecContext(ctx, query, notif.ID, notif.UserID, notif.Type, notif.Title, notif.Message, notif.Data)
+	query := `INSERT INTO notifications (id, user_id, type, title, message, data, broadcast_id, is_read, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, 0, NOW())`
+	_, err := r.db.ExecContext(ctx, query, notif.ID, notif.UserID, notif.Type, notif.Title, notif.Message, notif.Data, notif.BroadcastID)
 	return err
 }
 
@@ -186,6 +203,32 @@ func (r *notificacaoMySQLRepository) CountUnread(ctx context.Context, userID str
 	return count, err
 }
 
+func (r *notificacaoMySQLRepository) FindPreferences(ctx context.Context, userID string) ([]entity.NotificationPreference, error) {
+	var prefs []entity.NotificationPreference
+	query := `SELECT user_id, type, enabled, updated_at
+			  FROM notification_preferences
+			  WHERE user_id = ?`
+	err := r.db.SelectContext(ctx, &prefs, query, userID)
+	return prefs, err
+}
+
+func (r *notificacaoMySQLRepository) SavePreferences(ctx context.Context, userID string, prefs []entity.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(prefs))
+	args := make([]interface{}, 0, len(prefs)*3)
+	for _, p := range prefs {
+		values = append(values, "(?, ?, ?, NOW())")
+		args = append(args, userID, p.Type, p.Enabled)
+	}
+	query := `INSERT INTO notification_preferences (user_id, type, enabled, updated_at)
+			  VALUES ` + strings.Join(values, ", ") + `
+			  ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
 // RevenueSplitMySQLRepository implementation
 type revenueSplitMySQLRepository struct {
 	db *sqlx.DB
@@ -200,7 +243,8 @@ func (r *revenueSplitMySQLRepository) FindByID(ctx context.Context, id string) (
 	var split entity.RevenueSplit
 	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
 			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
-			  status, processed_at, created_at
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
 			  FROM revenue_splits
 			  WHERE id = ?`
 	err := r.db.GetContext(ctx, &split, query, id)
@@ -213,6 +257,24 @@ func (r *revenueSplitMySQLRepository) FindByID(ctx context.Context, id string) (
 	return &split, nil
 }
 
+func (r *revenueSplitMySQLRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.RevenueSplit, error) {
+	var split entity.RevenueSplit
+	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
+			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
+			  FROM revenue_splits
+			  WHERE id = ? FOR UPDATE`
+	err := tx.GetContext(ctx, &split, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &split, nil
+}
+
 func (r *revenueSplitMySQLRepository) FindAll(ctx context.Context, status string) ([]entity.RevenueSplit, error) {
 	var splits []entity.RevenueSplit
 	var err error
@@ -220,7 +282,8 @@ func (r *revenueSplitMySQLRepository) FindAll(ctx context.Context, status string
 	if status != "" {
 		query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
 				  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
-				  status, processed_at, created_at
+				  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+				  processed_at, created_at, installment_number
 				  FROM revenue_splits
 				  WHERE status = ?
 				  ORDER BY created_at DESC`
@@ -228,7 +291,8 @@ func (r *revenueSplitMySQLRepository) FindAll(ctx context.Context, status string
 	} else {
 		query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
 				  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
-				  status, processed_at, created_at
+				  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+				  processed_at, created_at, installment_number
 				  FROM revenue_splits
 				  ORDER BY created_at DESC`
 		err = r.db.SelectContext(ctx, &splits, query)
@@ -244,7 +308,8 @@ func (r *revenueSplitMySQLRepository) FindByEnrollmentID(ctx context.Context, en
 	var split entity.RevenueSplit
 	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
 			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
-			  status, processed_at, created_at
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
 			  FROM revenue_splits
 			  WHERE enrollment_id = ?`
 	err := r.db.GetContext(ctx, &split, query, enrollmentID)
@@ -261,9 +326,12 @@ func (r *revenueSplitMySQLRepository) FindByPaymentID(ctx context.Context, payme
 	var split entity.RevenueSplit
 	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
 			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
-			  status, processed_at, created_at
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
 			  FROM revenue_splits
-			  WHERE payment_id = ?`
+			  WHERE payment_id = ?
+			  ORDER BY installment_number
+			  LIMIT 1`
 	err := r.db.GetContext(ctx, &split, query, paymentID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -274,11 +342,56 @@ func (r *revenueSplitMySQLRepository) FindByPaymentID(ctx context.Context, payme
 	return &split, nil
 }
 
+func (r *revenueSplitMySQLRepository) FindByPaymentInstallment(ctx context.Context, paymentID string, installment int) (*entity.RevenueSplit, error) {
+	var split entity.RevenueSplit
+	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
+			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
+			  FROM revenue_splits
+			  WHERE payment_id = ? AND installment_number = ?`
+	err := r.db.GetContext(ctx, &split, query, paymentID, installment)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &split, nil
+}
+
+func (r *revenueSplitMySQLRepository) FindAllByPaymentID(ctx context.Context, paymentID string) ([]entity.RevenueSplit, error) {
+	var splits []entity.RevenueSplit
+	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
+			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
+			  FROM revenue_splits
+			  WHERE payment_id = ?
+			  ORDER BY installment_number`
+	err := r.db.SelectContext(ctx, &splits, query, paymentID)
+	return splits, err
+}
+
+func (r *revenueSplitMySQLRepository) FindAllByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.RevenueSplit, error) {
+	var splits []entity.RevenueSplit
+	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
+			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
+			  FROM revenue_splits
+			  WHERE enrollment_id = ?
+			  ORDER BY created_at, installment_number`
+	err := r.db.SelectContext(ctx, &splits, query, enrollmentID)
+	return splits, err
+}
+
 func (r *revenueSplitMySQLRepository) FindByInstructorID(ctx context.Context, instructorID string) ([]entity.RevenueSplit, error) {
 	var splits []entity.RevenueSplit
 	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
 			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
-			  status, processed_at, created_at
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
 			  FROM revenue_splits
 			  WHERE instructor_id = ?
 			  ORDER BY created_at DESC`
@@ -287,24 +400,24 @@ func (r *revenueSplitMySQLRepository) FindByInstructorID(ctx context.Context, in
 }
 
 func (r *revenueSplitMySQLRepository) Create(ctx context.Context, split *entity.RevenueSplit) error {
-	query := `INSERT INTO revenue_splits (id, enrollment_id, payment_id, gross_amount, net_amount,
-			  platform_fee, payment_fee, instructor_amount, platform_amount, instructor_id,
+	query := `INSERT INTO revenue_splits (id, enrollment_id, payment_id, installment_number, gross_amount,
+			  net_amount, platform_fee, payment_fee, instructor_amount, platform_amount, instructor_id,
 			  payment_method, status, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
 	_, err := r.db.ExecContext(ctx, query,
-		split.ID, split.EnrollmentID, split.PaymentID, split.GrossAmount, split.NetAmount,
+		split.ID, split.EnrollmentID, split.PaymentID, split.InstallmentNumber, split.GrossAmount, split.NetAmount,
 		split.PlatformFee, split.PaymentFee, split.InstructorAmount, split.PlatformAmount,
 		split.InstructorID, split.PaymentMethod, split.Status)
 	return err
 }
 
 func (r *revenueSplitMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, split *entity.RevenueSplit) error {
-	query := `INSERT INTO revenue_splits (id, enrollment_id, payment_id, gross_amount, net_amount,
-			  platform_fee, payment_fee, instructor_amount, platform_amount, instructor_id,
+	query := `INSERT INTO revenue_splits (id, enrollment_id, payment_id, installment_number, gross_amount,
+			  net_amount, platform_fee, payment_fee, instructor_amount, platform_amount, instructor_id,
 			  payment_method, status, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
 	_, err := tx.ExecContext(ctx, query,
-		split.ID, split.EnrollmentID, split.PaymentID, split.GrossAmount, split.NetAmount,
+		split.ID, split.EnrollmentID, split.PaymentID, split.InstallmentNumber, split.GrossAmount, split.NetAmount,
 		split.PlatformFee, split.PaymentFee, split.InstructorAmount, split.PlatformAmount,
 		split.InstructorID, split.PaymentMethod, split.Status)
 	return err
@@ -316,10 +429,119 @@ func (r *revenueSplitMySQLRepository) UpdateStatus(ctx context.Context, id, stat
 	return err
 }
 
-func (r *revenueSplitMySQLRepository) GetTotalByInstructor(ctx context.Context, instructorID string) (float64, error) {
-	var total float64
-	query := `SELECT COALESCE(SUM(instructor_amount), 0) FROM revenue_splits
-			  WHERE instructor_id = ? AND status = 'processed'`
-	err := r.db.GetContext(ctx, &total, query, instructorID)
-	return total, err
+func (r *revenueSplitMySQLRepository) UpdateStatusWithTx(ctx context.Context, tx *sqlx.Tx, id, status string) error {
+	query := `UPDATE revenue_splits SET status = ?, processed_at = NOW() WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query, status, id)
+	return err
+}
+
+func (r *revenueSplitMySQLRepository) UpdateAmountsWithTx(ctx context.Context, tx *sqlx.Tx, split *entity.RevenueSplit) error {
+	query := `UPDATE revenue_splits SET gross_amount = ?, net_amount = ?, platform_fee = ?,
+			  payment_fee = ?, instructor_amount = ?, platform_amount = ?, instructor_id = ?
+			  WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query,
+		split.GrossAmount, split.NetAmount, split.PlatformFee,
+		split.PaymentFee, split.InstructorAmount, split.PlatformAmount, split.InstructorID,
+		split.ID)
+	return err
+}
+
+func (r *revenueSplitMySQLRepository) FindByTransferID(ctx context.Context, transferID string) (*entity.RevenueSplit, error) {
+	var split entity.RevenueSplit
+	query := `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
+			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at, installment_number
+			  FROM revenue_splits
+			  WHERE transfer_id = ?`
+	err := r.db.GetContext(ctx, &split, query, transferID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &split, nil
+}
+
+func (r *revenueSplitMySQLRepository) ClaimForTransfer(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE revenue_splits SET transfer_status = 'pending', transfer_id = NULL, transfer_error = NULL
+			  WHERE id = ? AND status = 'pending' AND payout_batch_id IS NULL
+			  AND (transfer_status IS NULL OR transfer_status IN ('failed', 'cancelled'))`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (r *revenueSplitMySQLRepository) UpdateTransfer(ctx context.Context, split *entity.RevenueSplit) error {
+	query := `UPDATE revenue_splits SET status = ?, transfer_id = ?, transfer_status = ?, transfer_error = ?,
+			  transferred_at = ?, processed_at = ?
+			  WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		split.Status, split.TransferID, split.TransferStatus, split.TransferError,
+		split.TransferredAt, split.ProcessedAt, split.ID)
+	return err
+}
+
+const splitPartySelect = `SELECT p.id, p.split_id, s.payment_id, s.enrollment_id, p.position, p.party_type,
+			  p.party_id, p.label, p.percent, p.fixed_amount, p.amount, s.status, p.created_at
+			  FROM revenue_split_parties p
+			  INNER JOIN revenue_splits s ON s.id = p.split_id`
+
+func (r *revenueSplitMySQLRepository) FindParties(ctx context.Context, splitID string) ([]entity.RevenueSplitParty, error) {
+	var parties []entity.RevenueSplitParty
+	query := splitPartySelect + ` WHERE p.split_id = ? ORDER BY p.position`
+	err := r.db.SelectContext(ctx, &parties, query, splitID)
+	if err != nil {
+		return nil, err
+	}
+	return parties, nil
+}
+
+func (r *revenueSplitMySQLRepository) FindPartyEntries(ctx context.Context, partyType, partyID string) ([]entity.RevenueSplitParty, error) {
+	var parties []entity.RevenueSplitParty
+	query := splitPartySelect + ` WHERE p.party_type = ?`
+	args := []interface{}{partyType}
+	if partyID != "" {
+		query += ` AND p.party_id = ?`
+		args = append(args, partyID)
+	} else {
+		query += ` AND p.party_id IS NULL`
+	}
+	query += ` ORDER BY p.created_at DESC, p.position`
+
+	err := r.db.SelectContext(ctx, &parties, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parties, nil
+}
+
+func (r *revenueSplitMySQLRepository) ReplacePartiesWithTx(ctx context.Context, tx *sqlx.Tx, split *entity.RevenueSplit) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM revenue_split_parties WHERE split_id = ?`, split.ID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO revenue_split_parties (id, split_id, position, party_type, party_id, label,
+			  percent, fixed_amount, amount, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	for i := range split.Parties {
+		p := &split.Parties[i]
+		p.SplitID = split.ID
+		p.PaymentID = split.PaymentID
+		p.EnrollmentID = split.EnrollmentID
+		p.Status = split.Status
+		if _, err := tx.ExecContext(ctx, query,
+			p.ID, p.SplitID, p.Position, p.PartyType, p.PartyID, p.Label,
+			p.Percent, p.FixedAmount, p.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type checkoutSessionMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewCheckoutSessionMySQLRepository creates a new MySQL implementation of CheckoutSessionRepository.
+// Sessions are polled right after they are paid, so they are always read from the primary.
+func NewCheckoutSessionMySQLRepository(db *sqlx.DB) repository.CheckoutSessionRepository {
+	return &checkoutSessionMySQLRepository{db: db}
+}
+
+func (r *checkoutSessionMySQLRepository) Create(ctx context.Context, session *entity.CheckoutSession) error {
+	query := `INSERT INTO checkout_sessions (id, course_id, amount, discount_code, status, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID, session.CourseID, session.Amount, session.DiscountCode, session.Status, session.ExpiresAt, session.CreatedAt)
+	return err
+}
+
+func (r *checkoutSessionMySQLRepository) FindByID(ctx context.Context, id string) (*entity.CheckoutSession, error) {
+	var session entity.CheckoutSession
+	query := `SELECT id, course_id, amount, discount_code, status, enrollment_id, expires_at, created_at, completed_at
+			  FROM checkout_sessions
+			  WHERE id = ?`
+	if err := r.db.GetContext(ctx, &session, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *checkoutSessionMySQLRepository) Claim(ctx context.Context, id string, now time.Time) (bool, error) {
+	query := `UPDATE checkout_sessions SET status = ?
+			  WHERE id = ? AND status = ? AND expires_at > ?`
+	result, err := r.db.ExecContext(ctx, query, entity.CheckoutSessionStatusProcessing, id, entity.CheckoutSessionStatusOpen, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+func (r *checkoutSessionMySQLRepository) Release(ctx context.Context, id string) error {
+	query := `UPDATE checkout_sessions SET status = ? WHERE id = ? AND status = ?`
+	_, err := r.db.ExecContext(ctx, query, entity.CheckoutSessionStatusOpen, id, entity.CheckoutSessionStatusProcessing)
+	return err
+}
+
+func (r *checkoutSessionMySQLRepository) Complete(ctx context.Context, id, enrollmentID string) error {
+	query := `UPDATE checkout_sessions SET status = ?, enrollment_id = ?, completed_at = NOW()
+			  WHERE id = ? AND status = ?`
+	_, err := r.db.ExecContext(ctx, query, entity.CheckoutSessionStatusCompleted, enrollmentID, id, entity.CheckoutSessionStatusProcessing)
+	return err
+}
+
+func (r *checkoutSessionMySQLRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	// A session left processing by an instance that stopped mid-checkout is dropped as well
+	query := `DELETE FROM checkout_sessions WHERE status IN (?, ?) AND expires_at < ?`
+	result, err := r.db.ExecContext(ctx, query, entity.CheckoutSessionStatusOpen, entity.CheckoutSessionStatusProcessing, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type complianceMySQLRepository struct {
+	reader *sqlx.DB
+}
+
+// NewComplianceMySQLRepository creates a new MySQL implementation of ComplianceRepository. The
+// dashboard only reads, so reader may be a read replica.
+func NewComplianceMySQLRepository(reader *sqlx.DB) repository.ComplianceRepository {
+	return &complianceMySQLRepository{reader: reader}
+}
+
+// FindExpirations takes the expiry of a contract document from the end of its current
+// version, and lists supplier documents under every active contract link of the supplier
+func (r *complianceMySQLRepository) FindExpirations(ctx context.Context, until time.Time) ([]entity.ComplianceExpiration, error) {
+	query := `SELECT ? AS source, d.id, d.contrato_id, c.nome AS contrato_nome, d.title, d.document_type,
+			  NULL AS supplier_id, NULL AS supplier_name, v.effective_until AS expires_at
+			  FROM contract_documents d
+			  INNER JOIN contract_document_versions v ON v.document_id = d.id AND v.version = d.current_version
+			  INNER JOIN contratos c ON c.id = d.contrato_id
+			  WHERE d.is_active = 1 AND c.ativo = 1 AND c.deleted_at IS NULL
+			  AND v.effective_until IS NOT NULL AND v.effective_until <= ?
+			  UNION ALL
+			  SELECT DISTINCT CASE WHEN sd.document_type = ? THEN ? ELSE ? END AS source, sd.id, sc.contrato_id,
+			  c.nome AS contrato_nome, sd.title, sd.document_type, s.id AS supplier_id, s.name AS supplier_name,
+			  sd.expires_at
+			  FROM supplier_documents sd
+			  INNER JOIN suppliers s ON s.id = sd.supplier_id
+			  INNER JOIN supplier_contracts sc ON sc.supplier_id = sd.supplier_id AND sc.is_active = 1
+			  INNER JOIN contratos c ON c.id = sc.contrato_id
+			  WHERE sd.is_active = 1 AND s.is_active = 1 AND c.ativo = 1 AND c.deleted_at IS NULL
+			  AND sd.expires_at IS NOT NULL AND sd.expires_at <= ?
+			  ORDER BY expires_at, contrato_nome, title`
+
+	var expirations []entity.ComplianceExpiration
+	err := r.reader.SelectContext(ctx, &expirations, query,
+		entity.ComplianceSourceContractDocument, until,
+		entity.SupplierDocumentTypeCertification, entity.ComplianceSourceCertification, entity.ComplianceSourceSupplierDocument, until)
+	if err != nil {
+		return nil, err
+	}
+	return expirations, nil
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type contractBillingMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewContractBillingMySQLRepository creates a new MySQL implementation of ContractBillingRepository
+func NewContractBillingMySQLRepository(db *sqlx.DB) repository.ContractBillingRepository {
+	return &contractBillingMySQLRepository{db: db}
+}
+
+const contractBillingPlanSelect = `SELECT p.id, p.contrato_id, p.description, p.monthly_amount, p.due_day, p.billing_type,
+			  p.payer_name, p.payer_document, p.payer_email, p.payer_phone, p.gateway, p.gateway_customer_id,
+			  p.start_period, p.end_period, p.active, p.created_by, p.created_at, p.updated_at,
+			  COALESCE(c.nome, '') as contrato_nome
+			  FROM contract_billing_plans p
+			  LEFT JOIN contratos c ON c.id = p.contrato_id`
+
+const contractChargeSelect = `SELECT ch.id, ch.plan_id, ch.contrato_id, ch.period, ch.description, ch.amount, ch.due_date,
+			  ch.billing_type, ch.status, ch.gateway, ch.gateway_payment_id, ch.invoice_url, ch.boleto_url,
+			  ch.pix_copy_paste, ch.paid_amount, ch.gateway_fee, ch.paid_at, ch.error, ch.created_at, ch.updated_at,
+			  COALESCE(c.nome, '') as contrato_nome
+			  FROM contract_charges ch
+			  LEFT JOIN contratos c ON c.id = ch.contrato_id`
+
+func (r *contractBillingMySQLRepository) FindPlanByContratoID(ctx context.Context, contratoID string) (*entity.ContractBillingPlan, error) {
+	var plan entity.ContractBillingPlan
+	err := r.db.GetContext(ctx, &plan, contractBillingPlanSelect+` WHERE p.contrato_id = ?`, contratoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *contractBillingMySQLRepository) FindActivePlans(ctx context.Context) ([]entity.ContractBillingPlan, error) {
+	var plans []entity.ContractBillingPlan
+	query := contractBillingPlanSelect + ` WHERE p.active = TRUE AND c.ativo = TRUE ORDER BY p.created_at`
+	err := r.db.SelectContext(ctx, &plans, query)
+	if err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+func (r *contractBillingMySQLRepository) SavePlan(ctx context.Context, plan *entity.ContractBillingPlan) error {
+	query := `INSERT INTO contract_billing_plans (id, contrato_id, description, monthly_amount, due_day, billing_type,
+			  payer_name, payer_document, payer_email, payer_phone, gateway, gateway_customer_id, start_period,
+			  end_period, active, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON DUPLICATE KEY UPDATE description = VALUES(description), monthly_amount = VALUES(monthly_amount),
+			  due_day = VALUES(due_day), billing_type = VALUES(billing_type), payer_name = VALUES(payer_name),
+			  payer_document = VALUES(payer_document), payer_email = VALUES(payer_email),
+			  payer_phone = VALUES(payer_phone), gateway = VALUES(gateway),
+			  gateway_customer_id = VALUES(gateway_customer_id), start_period = VALUES(start_period),
+			  end_period = VALUES(end_period), active = VALUES(active), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query,
+		plan.ID, plan.ContratoID, plan.Description, plan.MonthlyAmount, plan.DueDay, plan.BillingType,
+		plan.PayerName, plan.PayerDocument, plan.PayerEmail, plan.PayerPhone, plan.Gateway, plan.GatewayCustomerID,
+		plan.StartPeriod, plan.EndPeriod, plan.Active, plan.CreatedBy, plan.CreatedAt)
+	return err
+}
+
+func (r *contractBillingMySQLRepository) UpdatePlanCustomer(ctx context.Context, id, gateway, customerID string) error {
+	query := `UPDATE contract_billing_plans SET gateway = ?, gateway_customer_id = ?, updated_at = NOW() WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, gateway, customerID, id)
+	return err
+}
+
+func (r *contractBillingMySQLRepository) FindCharges(ctx context.Context, filters entity.ContractChargeFilters) ([]entity.ContractCharge, error) {
+	var charges []entity.ContractCharge
+	query := contractChargeSelect + ` WHERE 1=1`
+	args := []interface{}{}
+
+	if filters.ContratoID != "" {
+		query += ` AND ch.contrato_id = ?`
+		args = append(args, filters.ContratoID)
+	}
+	if filters.Status != "" {
+		query += ` AND ch.status = ?`
+		args = append(args, filters.Status)
+	}
+	if filters.From != "" {
+		query += ` AND ch.period >= ?`
+		args = append(args, filters.From)
+	}
+	if filters.To != "" {
+		query += ` AND ch.period <= ?`
+		args = append(args, filters.To)
+	}
+	query += ` ORDER BY ch.period DESC, c.nome`
+
+	err := r.db.SelectContext(ctx, &charges, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return charges, nil
+}
+
+func (r *contractBillingMySQLRepository) FindChargeByID(ctx context.Context, id string) (*entity.ContractCharge, error) {
+	return r.findCharge(ctx, ` WHERE ch.id = ?`, id)
+}
+
+func (r *contractBillingMySQLRepository) FindChargeByPeriod(ctx context.Context, contratoID, period string) (*entity.ContractCharge, error) {
+	return r.findCharge(ctx, ` WHERE ch.contrato_id = ? AND ch.period = ?`, contratoID, period)
+}
+
+func (r *contractBillingMySQLRepository) FindChargeByGatewayPaymentID(ctx context.Context, gateway, gatewayPaymentID string) (*entity.ContractCharge, error) {
+	return r.findCharge(ctx, ` WHERE ch.gateway = ? AND ch.gateway_payment_id = ?`, gateway, gatewayPaymentID)
+}
+
+func (r *contractBillingMySQLRepository) findCharge(ctx context.Context, where string, args ...interface{}) (*entity.ContractCharge, error) {
+	var charge entity.ContractCharge
+	err := r.db.GetContext(ctx, &charge, contractChargeSelect+where, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &charge, nil
+}
+
+func (r *contractBillingMySQLRepository) CreateCharge(ctx context.Context, charge *entity.ContractCharge) (bool, error) {
+	query := `INSERT IGNORE INTO contract_charges (id, plan_id, contrato_id, period, description, amount, due_date,
+			  billing_type, status, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := r.db.ExecContext(ctx, query,
+		charge.ID, charge.PlanID, charge.ContratoID, charge.Period, charge.Description, charge.Amount,
+		charge.DueDate, charge.BillingType, charge.Status, charge.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *contractBillingMySQLRepository) ClaimFailedCharge(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE contract_charges SET status = 'pending', updated_at = NOW() WHERE id = ? AND status = 'failed'`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *contractBillingMySQLRepository) UpdateCharge(ctx context.Context, charge *entity.ContractCharge) error {
+	query := `UPDATE contract_charges SET description = ?, amount = ?, due_date = ?, billing_type = ?, status = ?,
+			  gateway = ?, gateway_payment_id = ?, invoice_url = ?, boleto_url = ?, pix_copy_paste = ?,
+			  paid_amount = ?, gateway_fee = ?, paid_at = ?, error = ?, updated_at = NOW()
+			  WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		charge.Description, charge.Amount, charge.DueDate, charge.BillingType, charge.Status,
+		charge.Gateway, charge.GatewayPaymentID, charge.InvoiceURL, charge.BoletoURL, charge.PixCopyPaste,
+		charge.PaidAmount, charge.GatewayFee, charge.PaidAt, charge.Error, charge.ID)
+	return err
+}
+
+func (r *contractBillingMySQLRepository) MarkOverdue(ctx context.Context, before time.Time) (int64, error) {
+	query := `UPDATE contract_charges SET status = 'overdue', updated_at = NOW() WHERE status = 'open' AND due_date < ?`
+	result, err := r.db.ExecContext(ctx, query, before.Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *contractBillingMySQLRepository) FindReceivables(ctx context.Context, now time.Time) ([]entity.ContractReceivable, error) {
+	var rows []entity.ContractReceivable
+	query := `SELECT ch.contrato_id, COALESCE(c.nome, '') as contrato_nome,
+			  COUNT(*) as open_count, SUM(ch.amount) as open_amount,
+			  SUM(CASE WHEN ch.due_date < ? THEN 1 ELSE 0 END) as overdue_count,
+			  SUM(CASE WHEN ch.due_date < ? THEN ch.amount ELSE 0 END) as overdue_amount,
+			  SUM(CASE WHEN ch.due_date < ? AND DATEDIFF(?, ch.due_date) <= 30 THEN ch.amount ELSE 0 END) as overdue_1_30,
+			  SUM(CASE WHEN DATEDIFF(?, ch.due_date) BETWEEN 31 AND 60 THEN ch.amount ELSE 0 END) as overdue_31_60,
+			  SUM(CASE WHEN DATEDIFF(?, ch.due_date) BETWEEN 61 AND 90 THEN ch.amount ELSE 0 END) as overdue_61_90,
+			  SUM(CASE WHEN DATEDIFF(?, ch.due_date) > 90 THEN ch.amount ELSE 0 END) as overdue_over_90,
+			  GREATEST(COALESCE(MAX(DATEDIFF(?, ch.due_date)), 0), 0) as max_days_late
+			  FROM contract_charges ch
+			  LEFT JOIN contratos c ON c.id = ch.contrato_id
+			  WHERE ch.status IN ('open', 'overdue')
+			  GROUP BY ch.contrato_id, c.nome
+			  ORDER BY overdue_amount DESC, open_amount DESC`
+	today := now.Format("2006-01-02")
+	err := r.db.SelectContext(ctx, &rows, query, today, today, today, today, today, today, today, today)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type contractDocumentMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewContractDocumentMySQLRepository creates a new MySQL implementation of ContractDocumentRepository
+func NewContractDocumentMySQLRepository(db *sqlx.DB) repository.ContractDocumentRepository {
+	return &contractDocumentMySQLRepository{db: db}
+}
+
+const contractDocumentSelect = `SELECT id, contrato_id, document_type, title, description, current_version,
+			  is_active, created_by, created_at, updated_at
+			  FROM contract_documents`
+
+const contractDocumentVersionSelect = `SELECT id, document_id, version, object_key, file_name, content_type, size,
+			  effective_from, effective_until, notes, uploaded_by, created_at
+			  FROM contract_document_versions`
+
+func (r *contractDocumentMySQLRepository) FindByContratoID(ctx context.Context, contratoID string, filters entity.ContractDocumentFilters) ([]entity.ContractDocument, error) {
+	var docs []entity.ContractDocument
+	query := contractDocumentSelect + ` WHERE contrato_id = ?`
+	args := []interface{}{contratoID}
+
+	if filters.DocumentType != "" {
+		query += ` AND document_type = ?`
+		args = append(args, filters.DocumentType)
+	}
+	if !filters.IncludeInactive {
+		query += ` AND is_active = 1`
+	}
+	query += ` ORDER BY document_type, title`
+
+	err := r.db.SelectContext(ctx, &docs, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (r *contractDocumentMySQLRepository) FindByID(ctx context.Context, id string) (*entity.ContractDocument, error) {
+	var doc entity.ContractDocument
+	query := contractDocumentSelect + ` WHERE id = ?`
+	err := r.db.GetContext(ctx, &doc, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *contractDocumentMySQLRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.ContractDocument, error) {
+	var doc entity.ContractDocument
+	query := contractDocumentSelect + ` WHERE id = ? FOR UPDATE`
+	err := tx.GetContext(ctx, &doc, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *contractDocumentMySQLRepository) FindVersions(ctx context.Context, documentID string) ([]entity.ContractDocumentVersion, error) {
+	var versions []entity.ContractDocumentVersion
+	query := contractDocumentVersionSelect + ` WHERE document_id = ? ORDER BY version DESC`
+	err := r.db.SelectContext(ctx, &versions, query, documentID)
+	if err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (r *contractDocumentMySQLRepository) FindVersion(ctx context.Context, documentID string, version int) (*entity.ContractDocumentVersion, error) {
+	var v entity.ContractDocumentVersion
+	query := contractDocumentVersionSelect + ` WHERE document_id = ? AND version = ?`
+	err := r.db.GetContext(ctx, &v, query, documentID, version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *contractDocumentMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, doc *entity.ContractDocument) error {
+	query := `INSERT INTO contract_documents (id, contrato_id, document_type, title, description,
+			  current_version, is_active, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query,
+		doc.ID, doc.ContratoID, doc.DocumentType, doc.Title, doc.Description,
+		doc.CurrentVersion, doc.IsActive, doc.CreatedBy)
+	return err
+}
+
+func (r *contractDocumentMySQLRepository) UpdateCurrentVersionWithTx(ctx context.Context, tx *sqlx.Tx, documentID string, version int) error {
+	query := `UPDATE contract_documents SET current_version = ?, updated_at = NOW() WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query, version, documentID)
+	return err
+}
+
+func (r *contractDocumentMySQLRepository) CreateVersionWithTx(ctx context.Context, tx *sqlx.Tx, v *entity.ContractDocumentVersion) error {
+	query := `INSERT INTO contract_document_versions (id, document_id, version, object_key, file_name,
+			  content_type, size, effective_from, effective_until, notes, uploaded_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query,
+		v.ID, v.DocumentID, v.Version, v.ObjectKey, v.FileName,
+		v.ContentType, v.Size, v.EffectiveFrom, v.EffectiveUntil, v.Notes, v.UploadedBy)
+	return err
+}
+
+func (r *contractDocumentMySQLRepository) CloseOpenVersionsWithTx(ctx context.Context, tx *sqlx.Tx, documentID string, before int, until time.Time) error {
+	query := `UPDATE contract_document_versions SET effective_until = ?
+			  WHERE document_id = ? AND version < ? AND effective_until IS NULL AND effective_from <= ?`
+	_, err := tx.ExecContext(ctx, query, until, documentID, before, until)
+	return err
+}
+
+func (r *contractDocumentMySQLRepository) Deactivate(ctx context.Context, id string) error {
+	query := `UPDATE contract_documents SET is_active = 0, updated_at = NOW() WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type contractFinancialMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewContractFinancialMySQLRepository creates a new MySQL implementation of ContractFinancialRepository. reader serves
+// the report queries and may be a read replica; writes go to db.
+func NewContractFinancialMySQLRepository(db, reader *sqlx.DB) repository.ContractFinancialRepository {
+	return &contractFinancialMySQLRepository{db: db, reader: reader}
+}
+
+const budgetLineSelect = `SELECT id, contrato_id, kind, category, monthly_amount, start_period, end_period,
+			  notes, created_at, updated_at
+			  FROM contract_budget_lines`
+
+const contractCostSelect = `SELECT id, contrato_id, source, category, period, amount, description,
+			  task_id, hours, hourly_rate, created_by, created_at
+			  FROM contract_costs`
+
+func (r *contractFinancialMySQLRepository) FindBudgetLines(ctx context.Context, contratoID string) ([]entity.ContractBudgetLine, error) {
+	var lines []entity.ContractBudgetLine
+	query := budgetLineSelect + ` WHERE contrato_id = ? ORDER BY kind DESC, category, start_period`
+	err := r.db.SelectContext(ctx, &lines, query, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+func (r *contractFinancialMySQLRepository) FindBudgetLineByID(ctx context.Context, id string) (*entity.ContractBudgetLine, error) {
+	var line entity.ContractBudgetLine
+	query := budgetLineSelect + ` WHERE id = ?`
+	err := r.db.GetContext(ctx, &line, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &line, nil
+}
+
+func (r *contractFinancialMySQLRepository) CreateBudgetLine(ctx context.Context, line *entity.ContractBudgetLine) error {
+	query := `INSERT INTO contract_budget_lines (id, contrato_id, kind, category, monthly_amount,
+			  start_period, end_period, notes, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		line.ID, line.ContratoID, line.Kind, line.Category, line.MonthlyAmount,
+		line.StartPeriod, line.EndPeriod, line.Notes)
+	return err
+}
+
+func (r *contractFinancialMySQLRepository) UpdateBudgetLine(ctx context.Context, line *entity.ContractBudgetLine) error {
+	query := `UPDATE contract_budget_lines SET
+			  category = ?, monthly_amount = ?, start_period = ?, end_period = ?, notes = ?, updated_at = NOW()
+			  WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		line.Category, line.MonthlyAmount, line.StartPeriod, line.EndPeriod, line.Notes, line.ID)
+	return err
+}
+
+func (r *contractFinancialMySQLRepository) DeleteBudgetLine(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM contract_budget_lines WHERE id = ?`, id)
+	return err
+}
+
+func (r *contractFinancialMySQLRepository) FindCosts(ctx context.Context, contratoID string, filters entity.ContractCostFilters) ([]entity.ContractCost, error) {
+	var costs []entity.ContractCost
+	query := contractCostSelect + ` WHERE contrato_id = ?`
+	args := []interface{}{contratoID}
+
+	if filters.From != "" {
+		query += ` AND period >= ?`
+		args = append(args, filters.From)
+	}
+	if filters.To != "" {
+		query += ` AND period <= ?`
+		args = append(args, filters.To)
+	}
+	if filters.Source != "" {
+		query += ` AND source = ?`
+		args = append(args, filters.Source)
+	}
+	query += ` ORDER BY period DESC, created_at DESC`
+
+	err := r.db.SelectContext(ctx, &costs, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return costs, nil
+}
+
+func (r *contractFinancialMySQLRepository) FindCostByID(ctx context.Context, id string) (*entity.ContractCost, error) {
+	var cost entity.ContractCost
+	query := contractCostSelect + ` WHERE id = ?`
+	err := r.db.GetContext(ctx, &cost, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &cost, nil
+}
+
+func (r *contractFinancialMySQLRepository) CreateCost(ctx context.Context, cost *entity.ContractCost) error {
+	query := `INSERT INTO contract_costs (id, contrato_id, source, category, period, amount, description,
+			  task_id, hours, hourly_rate, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		cost.ID, cost.ContratoID, cost.Source, cost.Category, cost.Period, cost.Amount, cost.Description,
+		cost.TaskID, cost.Hours, cost.HourlyRate, cost.CreatedBy)
+	return err
+}
+
+func (r *contractFinancialMySQLRepository) DeleteCost(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM contract_costs WHERE id = ?`, id)
+	return err
+}
+
+func (r *contractFinancialMySQLRepository) SumCosts(ctx context.Context, contratoID, from, to string) ([]entity.ContractCostAggregate, error) {
+	var rows []entity.ContractCostAggregate
+	query := `SELECT period, source, category, SUM(amount) AS total
+			  FROM contract_costs
+			  WHERE contrato_id = ? AND period BETWEEN ? AND ?
+			  GROUP BY period, source, category
+			  ORDER BY period`
+	err := r.reader.SelectContext(ctx, &rows, query, contratoID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *contractFinancialMySQLRepository) SumSupplierSpend(ctx context.Context, contratoID, from, to string) ([]entity.ContractCostAggregate, error) {
+	var rows []entity.ContractCostAggregate
+	query := `SELECT sp.period, 'supplier' AS source, sc.service_category AS category, SUM(sp.amount) AS total
+			  FROM supplier_spend sp
+			  INNER JOIN supplier_contracts sc ON sc.id = sp.supplier_contract_id
+			  WHERE sp.contrato_id = ? AND sp.period BETWEEN ? AND ?
+			  GROUP BY sp.period, sc.service_category
+			  ORDER BY sp.period`
+	err := r.reader.SelectContext(ctx, &rows, query, contratoID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *contractFinancialMySQLRepository) SumCharges(ctx context.Context, contratoID, from, to string) ([]entity.ContractChargeAggregate, error) {
+	var rows []entity.ContractChargeAggregate
+	query := `SELECT period, SUM(amount) AS billed,
+			  COALESCE(SUM(CASE WHEN status = 'paid' THEN COALESCE(paid_amount, amount) ELSE 0 END), 0) AS received
+			  FROM contract_charges
+			  WHERE contrato_id = ? AND period BETWEEN ? AND ? AND status IN ('open', 'overdue', 'paid')
+			  GROUP BY period
+			  ORDER BY period`
+	err := r.reader.SelectContext(ctx, &rows, query, contratoID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type contractKPIMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewContractKPIMySQLRepository creates a new MySQL implementation of ContractKPIRepository
+func NewContractKPIMySQLRepository(db *sqlx.DB) repository.ContractKPIRepository {
+	return &contractKPIMySQLRepository{db: db}
+}
+
+func (r *contractKPIMySQLRepository) Measure(ctx context.Context, from, now time.Time) ([]entity.ContractKPISnapshot, error) {
+	var snapshots []entity.ContractKPISnapshot
+	query := `SELECT c.id as contrato_id,
+			  (SELECT COUNT(*) FROM audits a
+			   WHERE a.contract_id = c.id AND a.audit_date >= ? AND a.audit_date <= ?) as audit_count,
+			  (SELECT ROUND(AVG(a.score), 2) FROM audits a
+			   WHERE a.contract_id = c.id AND a.audit_date >= ? AND a.audit_date <= ?) as average_audit_score,
+			  (SELECT a.score FROM audits a
+			   WHERE a.contract_id = c.id AND a.audit_date <= ?
+			   ORDER BY a.audit_date DESC, a.created_at DESC LIMIT 1) as last_audit_score,
+			  (SELECT COUNT(*) FROM tasks t
+			   WHERE t.contract_id = c.id AND t.status IN ('pending', 'in_progress')) as open_tasks,
+			  (SELECT COUNT(*) FROM tasks t
+			   WHERE t.contract_id = c.id AND t.status IN ('pending', 'in_progress')
+			   AND DATE(t.due_date) < DATE(?)) as overdue_tasks,
+			  (SELECT COUNT(*) FROM inspections i
+			   WHERE i.contract_id = c.id AND i.inspection_date >= ? AND i.inspection_date <= ?
+			   AND i.status <> 'cancelled') as inspections_due,
+			  (SELECT COUNT(*) FROM inspections i
+			   WHERE i.contract_id = c.id AND i.inspection_date >= ? AND i.inspection_date <= ?
+			   AND i.status = 'completed') as inspections_completed
+			  FROM contratos c
+			  WHERE c.ativo = 1 AND c.deleted_at IS NULL
+			  ORDER BY c.id`
+	err := r.db.SelectContext(ctx, &snapshots, query,
+		from, now, from, now, now,
+		now,
+		from, now, from, now)
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (r *contractKPIMySQLRepository) Save(ctx context.Context, snapshots []entity.ContractKPISnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(snapshots))
+	args := make([]interface{}, 0, len(snapshots)*11)
+	for _, s := range snapshots {
+		values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())")
+		args = append(args, s.ID, s.ContratoID, s.SnapshotDate.Format("2006-01-02"), s.AuditCount,
+			s.AverageAuditScore, s.LastAuditScore, s.OpenTasks, s.OverdueTasks,
+			s.InspectionsDue, s.InspectionsCompleted, s.InspectionComplianceRate)
+	}
+	query := `INSERT INTO contract_kpi_snapshots (id, contrato_id, snapshot_date, audit_count,
+			  average_audit_score, last_audit_score, open_tasks, overdue_tasks,
+			  inspections_due, inspections_completed, inspection_compliance_rate, created_at)
+			  VALUES ` + strings.Join(values, ", ") + `
+			  ON DUPLICATE KEY UPDATE audit_count = VALUES(audit_count),
+			  average_audit_score = VALUES(average_audit_score), last_audit_score = VALUES(last_audit_score),
+			  open_tasks = VALUES(open_tasks), overdue_tasks = VALUES(overdue_tasks),
+			  inspections_due = VALUES(inspections_due), inspections_completed = VALUES(inspections_completed),
+			  inspection_compliance_rate = VALUES(inspection_compliance_rate), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *contractKPIMySQLRepository) FindByContratoID(ctx context.Context, contratoID string, from, to time.Time) ([]entity.ContractKPISnapshot, error) {
+	var snapshots []entity.ContractKPISnapshot
+	query := `SELECT id, contrato_id, snapshot_date, audit_count, average_audit_score, last_audit_score,
+			  open_tasks, overdue_tasks, inspections_due, inspections_completed, inspection_compliance_rate,
+			  created_at, updated_at
+			  FROM contract_kpi_snapshots
+			  WHERE contrato_id = ? AND snapshot_date >= ? AND snapshot_date <= ?
+			  ORDER BY snapshot_date ASC`
+	err := r.db.SelectContext(ctx, &snapshots, query, contratoID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type contractRenewalMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewContractRenewalMySQLRepository creates a new MySQL implementation of ContractRenewalRepository
+func NewContractRenewalMySQLRepository(db *sqlx.DB) repository.ContractRenewalRepository {
+	return &contractRenewalMySQLRepository{db: db}
+}
+
+const contractRenewalSelect = `SELECT r.id, r.contrato_id, r.status, r.current_end_date, r.new_start_date, r.new_end_date,
+			  r.new_monthly_value, r.adjustment_percent, r.terms, r.notes, r.created_by, r.decided_by, r.decided_at,
+			  r.created_at, r.updated_at, c.nome AS contrato_nome
+			  FROM contract_renewals r
+			  INNER JOIN contratos c ON c.id = r.contrato_id`
+
+func (r *contractRenewalMySQLRepository) FindAll(ctx context.Context, filters entity.ContractRenewalFilters) ([]entity.ContractRenewal, error) {
+	var renewals []entity.ContractRenewal
+	query := contractRenewalSelect + ` WHERE 1=1`
+	args := []interface{}{}
+
+	if filters.Status != "" {
+		query += ` AND r.status = ?`
+		args = append(args, filters.Status)
+	}
+	query += ` ORDER BY r.created_at DESC`
+
+	err := r.db.SelectContext(ctx, &renewals, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return renewals, nil
+}
+
+func (r *contractRenewalMySQLRepository) FindByID(ctx context.Context, id string) (*entity.ContractRenewal, error) {
+	var renewal entity.ContractRenewal
+	query := contractRenewalSelect + ` WHERE r.id = ?`
+	err := r.db.GetContext(ctx, &renewal, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &renewal, nil
+}
+
+func (r *contractRenewalMySQLRepository) FindByContratoID(ctx context.Context, contratoID string) ([]entity.ContractRenewal, error) {
+	var renewals []entity.ContractRenewal
+	query := contractRenewalSelect + ` WHERE r.contrato_id = ? ORDER BY r.created_at DESC`
+	err := r.db.SelectContext(ctx, &renewals, query, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	return renewals, nil
+}
+
+func (r *contractRenewalMySQLRepository) FindOpenByContratoID(ctx context.Context, contratoID string) (*entity.ContractRenewal, error) {
+	var renewal entity.ContractRenewal
+	query := contractRenewalSelect + ` WHERE r.contrato_id = ? AND r.status IN ('pending', 'renegotiating')
+			  ORDER BY r.created_at DESC LIMIT 1`
+	err := r.db.GetContext(ctx, &renewal, query, contratoID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &renewal, nil
+}
+
+func (r *contractRenewalMySQLRepository) Create(ctx context.Context, renewal *entity.ContractRenewal) error {
+	query := `INSERT INTO contract_renewals (id, contrato_id, status, current_end_date, new_start_date, new_end_date,
+			  new_monthly_value, adjustment_percent, terms, notes, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		renewal.ID, renewal.ContratoID, renewal.Status, renewal.CurrentEndDate, renewal.NewStartDate, renewal.NewEndDate,
+		renewal.NewMonthlyValue, renewal.AdjustmentPercent, renewal.Terms, renewal.Notes, renewal.CreatedBy)
+	return err
+}
+
+func (r *contractRenewalMySQLRepository) Update(ctx context.Context, renewal *entity.ContractRenewal) error {
+	query := `UPDATE contract_renewals SET
+			  status = ?, new_start_date = ?, new_end_date = ?, new_monthly_value = ?, adjustment_percent = ?,
+			  terms = ?, notes = ?, decided_by = ?, decided_at = ?, updated_at = NOW()
+			  WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		renewal.Status, renewal.NewStartDate, renewal.NewEndDate, renewal.NewMonthlyValue, renewal.AdjustmentPercent,
+		renewal.Terms, renewal.Notes, renewal.DecidedBy, renewal.DecidedAt, renewal.ID)
+	return err
+}
+
+func (r *contractRenewalMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, renewal *entity.ContractRenewal) error {
+	query := `UPDATE contract_renewals SET
+			  status = ?, new_start_date = ?, new_end_date = ?, new_monthly_value = ?, adjustment_percent = ?,
+			  terms = ?, notes = ?, decided_by = ?, decided_at = ?, updated_at = NOW()
+			  WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query,
+		renewal.Status, renewal.NewStartDate, renewal.NewEndDate, renewal.NewMonthlyValue, renewal.AdjustmentPercent,
+		renewal.Terms, renewal.Notes, renewal.DecidedBy, renewal.DecidedAt, renewal.ID)
+	return err
+}
+
+func (r *contractRenewalMySQLRepository) FindUpcoming(ctx context.Context, from, until time.Time) ([]entity.UpcomingContractRenewal, error) {
+	var upcoming []entity.UpcomingContractRenewal
+	query := `SELECT c.id AS contrato_id, c.nome AS contrato_nome, c.gestor_id, c.data_fim,
+			  r.id AS renewal_id, r.status AS renewal_status,
+			  a.threshold_days AS last_alert_days, a.created_at AS last_alert_at
+			  FROM contratos c
+			  LEFT JOIN contract_renewals r ON r.id = (
+			      SELECT r2.id FROM contract_renewals r2
+			      WHERE r2.contrato_id = c.id AND r2.status IN ('pending', 'renegotiating')
+			      ORDER BY r2.created_at DESC LIMIT 1)
+			  LEFT JOIN contract_renewal_alerts a ON a.id = (
+			      SELECT a2.id FROM contract_renewal_alerts a2
+			      WHERE a2.contrato_id = c.id AND a2.end_date = c.data_fim
+			      ORDER BY a2.threshold_days ASC LIMIT 1)
+			  WHERE c.ativo = 1 AND c.deleted_at IS NULL AND c.data_fim IS NOT NULL AND c.data_fim BETWEEN ? AND ?
+			  ORDER BY c.data_fim ASC`
+	err := r.db.SelectContext(ctx, &upcoming, query, from.Format("2006-01-02"), until.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return upcoming, nil
+}
+
+func (r *contractRenewalMySQLRepository) FindSentThresholds(ctx context.Context, contratoID string, endDate time.Time) ([]int, error) {
+	var thresholds []int
+	query := `SELECT threshold_days FROM contract_renewal_alerts WHERE contrato_id = ? AND end_date = ?`
+	err := r.db.SelectContext(ctx, &thresholds, query, contratoID, endDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	return thresholds, nil
+}
+
+func (r *contractRenewalMySQLRepository) CreateAlert(ctx context.Context, alert *entity.ContractRenewalAlert) error {
+	query := `INSERT INTO contract_renewal_alerts (id, contrato_id, end_date, threshold_days, recipients, created_at)
+			  VALUES (?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		alert.ID, alert.ContratoID, alert.EndDate.Format("2006-01-02"), alert.ThresholdDays, alert.Recipients)
+	return err
+}
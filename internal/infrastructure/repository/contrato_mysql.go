@@ -22,9 +22,9 @@ func NewContratoMySQLRepository(db *sqlx.DB) repository.ContratoRepository {
 func (r *contratoMySQLRepository) FindAll(ctx context.Context) ([]entity.Contrato, error) {
 	var contratos []entity.Contrato
 	query := `SELECT id, gestor_id, nome, descricao, endereco, cidade, estado, cep,
-			  total_unidades, meta_score, data_inicio, data_fim, ativo, created_at, updated_at
+			  total_unidades, meta_score, data_inicio, data_fim, ativo, created_at, updated_at, deleted_at
 			  FROM contratos
-			  WHERE ativo = 1
+			  WHERE ativo = 1 AND deleted_at IS NULL
 			  ORDER BY nome`
 	err := r.db.SelectContext(ctx, &contratos, query)
 	if err != nil {
@@ -36,9 +36,9 @@ func (r *contratoMySQLRepository) FindAll(ctx context.Context) ([]entity.Contrat
 func (r *contratoMySQLRepository) FindByID(ctx context.Context, id string) (*entity.Contrato, error) {
 	var contrato entity.Contrato
 	query := `SELECT id, gestor_id, nome, descricao, endereco, cidade, estado, cep,
-			  total_unidades, meta_score, data_inicio, data_fim, ativo, created_at, updated_at
+			  total_unidades, meta_score, data_inicio, data_fim, ativo, created_at, updated_at, deleted_at
 			  FROM contratos
-			  WHERE id = ?`
+			  WHERE id = ? AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &contrato, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -52,9 +52,9 @@ func (r *contratoMySQLRepository) FindByID(ctx context.Context, id string) (*ent
 func (r *contratoMySQLRepository) FindByGestorID(ctx context.Context, gestorID string) ([]entity.Contrato, error) {
 	var contratos []entity.Contrato
 	query := `SELECT id, gestor_id, nome, descricao, endereco, cidade, estado, cep,
-			  total_unidades, meta_score, data_inicio, data_fim, ativo, created_at, updated_at
+			  total_unidades, meta_score, data_inicio, data_fim, ativo, created_at, updated_at, deleted_at
 			  FROM contratos
-			  WHERE gestor_id = ? AND ativo = 1
+			  WHERE gestor_id = ? AND ativo = 1 AND deleted_at IS NULL
 			  ORDER BY nome`
 	err := r.db.SelectContext(ctx, &contratos, query, gestorID)
 	if err != nil {
@@ -66,11 +66,11 @@ func (r *contratoMySQLRepository) FindByGestorID(ctx context.Context, gestorID s
 func (r *contratoMySQLRepository) FindAllWithGestor(ctx context.Context) ([]entity.ContratoWithGestor, error) {
 	var contratos []entity.ContratoWithGestor
 	query := `SELECT c.id, c.gestor_id, c.nome, c.descricao, c.endereco, c.cidade, c.estado, c.cep,
-			  c.total_unidades, c.meta_score, c.data_inicio, c.data_fim, c.ativo, c.created_at, c.updated_at,
+			  c.total_unidades, c.meta_score, c.data_inicio, c.data_fim, c.ativo, c.created_at, c.updated_at, c.deleted_at,
 			  g.nome as gestor_nome, g.email as gestor_email
 			  FROM contratos c
 			  INNER JOIN gestores g ON g.id = c.gestor_id
-			  WHERE c.ativo = 1
+			  WHERE c.ativo = 1 AND c.deleted_at IS NULL
 			  ORDER BY c.nome`
 	err := r.db.SelectContext(ctx, &contratos, query)
 	if err != nil {
@@ -94,7 +94,7 @@ func (r *contratoMySQLRepository) Update(ctx context.Context, contrato *entity.C
 	query := `UPDATE contratos
 			  SET gestor_id = ?, nome = ?, descricao = ?, endereco = ?, cidade = ?, estado = ?, cep = ?,
 			  total_unidades = ?, meta_score = ?, data_inicio = ?, data_fim = ?, ativo = ?, updated_at = NOW()
-			  WHERE id = ?`
+			  WHERE id = ? AND deleted_at IS NULL`
 	_, err := r.db.ExecContext(ctx, query,
 		contrato.GestorID, contrato.Nome, contrato.Descricao,
 		contrato.Endereco, contrato.Cidade, contrato.Estado, contrato.CEP,
@@ -102,15 +102,54 @@ func (r *contratoMySQLRepository) Update(ctx context.Context, contrato *entity.C
 	return err
 }
 
+func (r *contratoMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, contrato *entity.Contrato) error {
+	query := `UPDATE contratos
+			  SET gestor_id = ?, nome = ?, descricao = ?, endereco = ?, cidade = ?, estado = ?, cep = ?,
+			  total_unidades = ?, meta_score = ?, data_inicio = ?, data_fim = ?, ativo = ?, updated_at = NOW()
+			  WHERE id = ? AND deleted_at IS NULL`
+	_, err := tx.ExecContext(ctx, query,
+		contrato.GestorID, contrato.Nome, contrato.Descricao,
+		contrato.Endereco, contrato.Cidade, contrato.Estado, contrato.CEP,
+		contrato.TotalUnidades, contrato.MetaScore, contrato.DataInicio, contrato.DataFim, contrato.Ativo, contrato.ID)
+	return err
+}
+
 func (r *contratoMySQLRepository) Delete(ctx context.Context, id string) error {
-	query := `UPDATE contratos SET ativo = 0, updated_at = NOW() WHERE id = ?`
+	query := `UPDATE contratos SET deleted_at = NOW(), updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
+func (r *contratoMySQLRepository) FindDeleted(ctx context.Context) ([]entity.Contrato, error) {
+	var contratos []entity.Contrato
+	query := `SELECT id, gestor_id, nome, descricao, endereco, cidade, estado, cep,
+			  total_unidades, meta_score, data_inicio, data_fim, ativo, created_at, updated_at, deleted_at
+			  FROM contratos
+			  WHERE deleted_at IS NOT NULL
+			  ORDER BY deleted_at DESC`
+	err := r.db.SelectContext(ctx, &contratos, query)
+	if err != nil {
+		return nil, err
+	}
+	return contratos, nil
+}
+
+func (r *contratoMySQLRepository) Restore(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE contratos SET deleted_at = NULL, updated_at = NOW() WHERE id = ? AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
 func (r *contratoMySQLRepository) CountByGestorID(ctx context.Context, gestorID string) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM contratos WHERE gestor_id = ? AND ativo = 1`
+	query := `SELECT COUNT(*) FROM contratos WHERE gestor_id = ? AND ativo = 1 AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &count, query, gestorID)
 	return count, err
 }
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type courseRatingMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewCourseRatingMySQLRepository creates a new MySQL implementation of CourseRatingRepository.
+// reader serves the catalog summaries and may be a read replica; writes go to db.
+func NewCourseRatingMySQLRepository(db, reader *sqlx.DB) repository.CourseRatingRepository {
+	return &courseRatingMySQLRepository{db: db, reader: reader}
+}
+
+func (r *courseRatingMySQLRepository) Save(ctx context.Context, rating *entity.CourseRating) error {
+	query := `INSERT INTO course_ratings (id, course_id, student_id, enrollment_id, rating, comment, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)
+			  ON DUPLICATE KEY UPDATE enrollment_id = VALUES(enrollment_id), rating = VALUES(rating),
+			  comment = VALUES(comment), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query,
+		rating.ID, rating.CourseID, rating.StudentID, rating.EnrollmentID, rating.Rating, rating.Comment, rating.CreatedAt)
+	return err
+}
+
+func (r *courseRatingMySQLRepository) FindByStudent(ctx context.Context, courseID, studentID string) (*entity.CourseRating, error) {
+	var rating entity.CourseRating
+	query := `SELECT id, course_id, student_id, enrollment_id, rating, comment, created_at, updated_at
+			  FROM course_ratings
+			  WHERE course_id = ? AND student_id = ?`
+	if err := r.db.GetContext(ctx, &rating, query, courseID, studentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rating, nil
+}
+
+func (r *courseRatingMySQLRepository) Summaries(ctx context.Context) ([]entity.CourseRatingSummary, error) {
+	query := `SELECT course_id, AVG(rating) AS average, COUNT(*) AS count
+			  FROM course_ratings
+			  GROUP BY course_id`
+	var summaries []entity.CourseRatingSummary
+	if err := r.reader.SelectContext(ctx, &summaries, query); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type enrollmentCancellationMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewEnrollmentCancellationMySQLRepository creates a new MySQL implementation of EnrollmentCancellationRepository
+func NewEnrollmentCancellationMySQLRepository(db *sqlx.DB) repository.EnrollmentCancellationRepository {
+	return &enrollmentCancellationMySQLRepository{db: db}
+}
+
+func (r *enrollmentCancellationMySQLRepository) FindByEnrollmentID(ctx context.Context, enrollmentID string) (*entity.EnrollmentCancellation, error) {
+	var c entity.EnrollmentCancellation
+	query := `SELECT id, enrollment_id, payment_id, policy, basis, days_since_purchase, paid_amount,
+			  refund_percent, refund_amount, refund_status, gateway_refund_status, revenue_split_id,
+			  instructor_clawback, reason, cancelled_by, created_at
+			  FROM enrollment_cancellations
+			  WHERE enrollment_id = ?
+			  ORDER BY created_at DESC
+			  LIMIT 1`
+	err := r.db.GetContext(ctx, &c, query, enrollmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *enrollmentCancellationMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, c *entity.EnrollmentCancellation) error {
+	query := `INSERT INTO enrollment_cancellations (id, enrollment_id, payment_id, policy, basis,
+			  days_since_purchase, paid_amount, refund_percent, refund_amount, refund_status,
+			  gateway_refund_status, revenue_split_id, instructor_clawback, reason, cancelled_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query,
+		c.ID, c.EnrollmentID, c.PaymentID, c.Policy, c.Basis,
+		c.DaysSincePurchase, c.PaidAmount, c.RefundPercent, c.RefundAmount, c.RefundStatus,
+		c.GatewayRefundStatus, c.RevenueSplitID, c.InstructorClawback, c.Reason, c.CancelledBy)
+	return err
+}
+
+func (r *enrollmentCancellationMySQLRepository) UpdateRefundWithTx(ctx context.Context, tx *sqlx.Tx, c *entity.EnrollmentCancellation) error {
+	query := `UPDATE enrollment_cancellations SET refund_status = ?, gateway_refund_status = ?,
+			  revenue_split_id = ?, instructor_clawback = ?
+			  WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query,
+		c.RefundStatus, c.GatewayRefundStatus, c.RevenueSplitID, c.InstructorClawback, c.ID)
+	return err
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type enrollmentRenewalMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewEnrollmentRenewalMySQLRepository creates a new MySQL implementation of EnrollmentRenewalRepository
+func NewEnrollmentRenewalMySQLRepository(db *sqlx.DB) repository.EnrollmentRenewalRepository {
+	return &enrollmentRenewalMySQLRepository{db: db}
+}
+
+const renewalColumns = `id, enrollment_id, payment_id, base_amount, discount_percent, final_amount,
+	extension_days, previous_expiration, new_expiration, status, confirmed_at, created_at, updated_at`
+
+func (r *enrollmentRenewalMySQLRepository) FindByPaymentID(ctx context.Context, paymentID string) (*entity.EnrollmentRenewal, error) {
+	var renewal entity.EnrollmentRenewal
+	query := fmt.Sprintf(`SELECT %s FROM enrollment_renewals WHERE payment_id = ?`, renewalColumns)
+	err := r.db.GetContext(ctx, &renewal, query, paymentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &renewal, nil
+}
+
+func (r *enrollmentRenewalMySQLRepository) FindByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.EnrollmentRenewal, error) {
+	var renewals []entity.EnrollmentRenewal
+	query := fmt.Sprintf(`SELECT %s FROM enrollment_renewals WHERE enrollment_id = ? ORDER BY created_at DESC`, renewalColumns)
+	err := r.db.SelectContext(ctx, &renewals, query, enrollmentID)
+	return renewals, err
+}
+
+func (r *enrollmentRenewalMySQLRepository) FindPendingByEnrollmentID(ctx context.Context, enrollmentID string) (*entity.EnrollmentRenewal, error) {
+	var renewal entity.EnrollmentRenewal
+	query := fmt.Sprintf(`SELECT %s FROM enrollment_renewals
+		WHERE enrollment_id = ? AND status = 'pending'
+		ORDER BY created_at DESC LIMIT 1`, renewalColumns)
+	err := r.db.GetContext(ctx, &renewal, query, enrollmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &renewal, nil
+}
+
+func (r *enrollmentRenewalMySQLRepository) FindPendingByEnrollmentIDWithTx(ctx context.Context, tx *sqlx.Tx, enrollmentID string) (*entity.EnrollmentRenewal, error) {
+	var renewal entity.EnrollmentRenewal
+	query := fmt.Sprintf(`SELECT %s FROM enrollment_renewals
+		WHERE enrollment_id = ? AND status = 'pending'
+		ORDER BY created_at DESC LIMIT 1`, renewalColumns)
+	err := tx.GetContext(ctx, &renewal, query, enrollmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &renewal, nil
+}
+
+func (r *enrollmentRenewalMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, rn *entity.EnrollmentRenewal) error {
+	query := `INSERT INTO enrollment_renewals (id, enrollment_id, payment_id, base_amount, discount_percent,
+		final_amount, extension_days, previous_expiration, new_expiration, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query,
+		rn.ID, rn.EnrollmentID, rn.PaymentID, rn.BaseAmount, rn.DiscountPercent,
+		rn.FinalAmount, rn.ExtensionDays, rn.PreviousExpiration, rn.NewExpiration, rn.Status)
+	return err
+}
+
+func (r *enrollmentRenewalMySQLRepository) ConfirmWithTx(ctx context.Context, tx *sqlx.Tx, rn *entity.EnrollmentRenewal) error {
+	query := `UPDATE enrollment_renewals
+		SET status = 'confirmed', previous_expiration = ?, new_expiration = ?, confirmed_at = NOW(), updated_at = NOW()
+		WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query, rn.PreviousExpiration, rn.NewExpiration, rn.ID)
+	return err
+}
+
+func (r *enrollmentRenewalMySQLRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	query := `UPDATE enrollment_renewals SET status = ?, updated_at = NOW() WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, status, id)
+	return err
+}
+
+func (r *enrollmentRenewalMySQLRepository) UpdatePaymentID(ctx context.Context, id, paymentID string) error {
+	query := `UPDATE enrollment_renewals SET payment_id = ?, updated_at = NOW() WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, paymentID, id)
+	return err
+}
+
+func (r *enrollmentRenewalMySQLRepository) UpdatePaymentIDWithTx(ctx context.Context, tx *sqlx.Tx, id, paymentID string) error {
+	query := `UPDATE enrollment_renewals SET payment_id = ?, updated_at = NOW() WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query, paymentID, id)
+	return err
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type enrollmentTransferMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewEnrollmentTransferMySQLRepository creates a new MySQL implementation of EnrollmentTransferRepository
+func NewEnrollmentTransferMySQLRepository(db *sqlx.DB) repository.EnrollmentTransferRepository {
+	return &enrollmentTransferMySQLRepository{db: db}
+}
+
+func (r *enrollmentTransferMySQLRepository) FindByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.EnrollmentTransfer, error) {
+	var transfers []entity.EnrollmentTransfer
+	query := `SELECT id, enrollment_id, transfer_type, from_student_id, from_student_name,
+			  to_student_id, to_student_name, from_course_id, from_course_name, to_course_id,
+			  to_course_name, from_amount, to_amount, price_difference, revenue_split_id,
+			  from_instructor_amount, to_instructor_amount, reason, transferred_by, created_at
+			  FROM enrollment_transfers
+			  WHERE enrollment_id = ?
+			  ORDER BY created_at DESC`
+	err := r.db.SelectContext(ctx, &transfers, query, enrollmentID)
+	return transfers, err
+}
+
+func (r *enrollmentTransferMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, t *entity.EnrollmentTransfer) error {
+	query := `INSERT INTO enrollment_transfers (id, enrollment_id, transfer_type, from_student_id,
+			  from_student_name, to_student_id, to_student_name, from_course_id, from_course_name,
+			  to_course_id, to_course_name, from_amount, to_amount, price_difference, revenue_split_id,
+			  from_instructor_amount, to_instructor_amount, reason, transferred_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query,
+		t.ID, t.EnrollmentID, t.TransferType, t.FromStudentID,
+		t.FromStudentName, t.ToStudentID, t.ToStudentName, t.FromCourseID, t.FromCourseName,
+		t.ToCourseID, t.ToCourseName, t.FromAmount, t.ToAmount, t.PriceDifference, t.RevenueSplitID,
+		t.FromInstructorAmount, t.ToInstructorAmount, t.Reason, t.TransferredBy)
+	return err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type evidenceMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewEvidenceMySQLRepository creates a new MySQL implementation of EvidenceRepository
+func NewEvidenceMySQLRepository(db *sqlx.DB) repository.EvidenceRepository {
+	return &evidenceMySQLRepository{db: db}
+}
+
+const evidenceSelect = `SELECT id, filename, original_name, content_type, size, description, tags,
+			  captured_at, entity_type, entity_id, uploaded_by, created_at
+			  FROM evidence_files`
+
+// evidenceListSchema lists the fields accepted in evidence filter[...] and sort parameters
+var evidenceListSchema = listSchema{
+	"entity_type":   {expr: "entity_type", kind: kindString},
+	"entity_id":     {expr: "entity_id", kind: kindString},
+	"content_type":  {expr: "content_type", kind: kindString},
+	"uploaded_by":   {expr: "uploaded_by", kind: kindString},
+	"original_name": {expr: "original_name", kind: kindString, sortable: true},
+	"size":          {expr: "size", kind: kindNumber, sortable: true},
+	"captured_at":   {expr: "captured_at", kind: kindTime, sortable: true},
+	"created_at":    {expr: "created_at", kind: kindTime, sortable: true},
+}
+
+func (r *evidenceMySQLRepository) Create(ctx context.Context, file *entity.EvidenceFile) error {
+	query := `INSERT INTO evidence_files (id, filename, original_name, content_type, size, description,
+			  tags, captured_at, entity_type, entity_id, uploaded_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		file.ID, file.Filename, file.OriginalName, file.ContentType, file.Size, file.Description,
+		file.Tags, file.CapturedAt, file.EntityType, file.EntityID, file.UploadedBy, file.CreatedAt,
+	)
+	return err
+}
+
+func (r *evidenceMySQLRepository) FindAll(ctx context.Context, filter *entity.EvidenceFilter) ([]entity.EvidenceFile, int, error) {
+	conditions, args, err := evidenceListSchema.where(filter.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy, err := evidenceListSchema.orderBy(filter.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	if orderBy == "" {
+		orderBy = "COALESCE(captured_at, created_at) DESC, created_at DESC"
+	}
+
+	if filter.EntityType != nil {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, *filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, *filter.EntityID)
+	}
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, "JSON_CONTAINS(tags, JSON_QUOTE(?))")
+		args = append(args, tag)
+	}
+	if filter.Search != nil {
+		pattern := "%" + strings.NewReplacer("%", `\%`, "_", `\_`).Replace(*filter.Search) + "%"
+		conditions = append(conditions, "(original_name LIKE ? OR description LIKE ?)")
+		args = append(args, pattern, pattern)
+	}
+	whereClause := strings.Join(append([]string{"1=1"}, conditions...), " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM evidence_files WHERE `+whereClause, args...); err != nil {
+		return nil, 0, err
+	}
+
+	var files []entity.EvidenceFile
+	query := evidenceSelect + ` WHERE ` + whereClause + ` ORDER BY ` + orderBy + ` LIMIT ? OFFSET ?`
+	offset := (filter.Page - 1) * filter.PerPage
+	if err := r.db.SelectContext(ctx, &files, query, append(args, filter.PerPage, offset)...); err != nil {
+		return nil, 0, err
+	}
+	return files, total, nil
+}
+
+func (r *evidenceMySQLRepository) FindByFilename(ctx context.Context, filename string) (*entity.EvidenceFile, error) {
+	var file entity.EvidenceFile
+	err := r.db.GetContext(ctx, &file, evidenceSelect+` WHERE filename = ?`, filename)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (r *evidenceMySQLRepository) DeleteByFilename(ctx context.Context, filename string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM evidence_files WHERE filename = ?`, filename)
+	return err
+}
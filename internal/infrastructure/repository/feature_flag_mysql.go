@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type featureFlagMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewFeatureFlagMySQLRepository creates a new MySQL implementation of FeatureFlagRepository
+func NewFeatureFlagMySQLRepository(db *sqlx.DB) repository.FeatureFlagRepository {
+	return &featureFlagMySQLRepository{db: db}
+}
+
+const featureFlagSelect = `SELECT id, flag_key, description, enabled, rollout_percent, user_ids, roles, org_ids,
+			  created_at, updated_at
+			  FROM feature_flags`
+
+func (r *featureFlagMySQLRepository) FindAll(ctx context.Context) ([]entity.FeatureFlag, error) {
+	var flags []entity.FeatureFlag
+	if err := r.db.SelectContext(ctx, &flags, featureFlagSelect+` ORDER BY flag_key`); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+func (r *featureFlagMySQLRepository) FindByKey(ctx context.Context, key string) (*entity.FeatureFlag, error) {
+	var flag entity.FeatureFlag
+	err := r.db.GetContext(ctx, &flag, featureFlagSelect+` WHERE flag_key = ?`, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *featureFlagMySQLRepository) Create(ctx context.Context, f *entity.FeatureFlag) error {
+	query := `INSERT INTO feature_flags (id, flag_key, description, enabled, rollout_percent, user_ids, roles, org_ids, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		f.ID, f.Key, f.Description, f.Enabled, f.RolloutPercent, f.UserIDs, f.Roles, f.OrgIDs)
+	return err
+}
+
+func (r *featureFlagMySQLRepository) Update(ctx context.Context, f *entity.FeatureFlag) error {
+	query := `UPDATE feature_flags SET description = ?, enabled = ?, rollout_percent = ?, user_ids = ?, roles = ?,
+			  org_ids = ?, updated_at = NOW()
+			  WHERE flag_key = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		f.Description, f.Enabled, f.RolloutPercent, f.UserIDs, f.Roles, f.OrgIDs, f.Key)
+	return err
+}
+
+func (r *featureFlagMySQLRepository) Delete(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM feature_flags WHERE flag_key = ?`, key)
+	return err
+}
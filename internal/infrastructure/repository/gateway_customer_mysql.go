@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type gatewayCustomerMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewGatewayCustomerMySQLRepository creates a new MySQL implementation of GatewayCustomerRepository
+func NewGatewayCustomerMySQLRepository(db *sqlx.DB) repository.GatewayCustomerRepository {
+	return &gatewayCustomerMySQLRepository{db: db}
+}
+
+func (r *gatewayCustomerMySQLRepository) FindByDocument(ctx context.Context, gateway, document string) (*entity.GatewayCustomer, error) {
+	var customer entity.GatewayCustomer
+	err := r.db.GetContext(ctx, &customer, `SELECT id, gateway, document, user_id, gateway_customer_id,
+			  created_at, updated_at
+			  FROM gateway_customers WHERE gateway = ? AND document = ?`, gateway, document)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (r *gatewayCustomerMySQLRepository) Create(ctx context.Context, customer *entity.GatewayCustomer) error {
+	_, err := r.db.ExecContext(ctx, `INSERT IGNORE INTO gateway_customers
+			  (id, gateway, document, user_id, gateway_customer_id, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		customer.ID, customer.Gateway, customer.Document, customer.UserID, customer.GatewayCustomerID,
+		customer.CreatedAt, customer.UpdatedAt)
+	return err
+}
+
+// BackfillFromPayments inserts the newest payment of each gateway and document first, so
+// INSERT IGNORE keeps the customer the payer was last charged as
+func (r *gatewayCustomerMySQLRepository) BackfillFromPayments(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `INSERT IGNORE INTO gateway_customers
+			  (id, gateway, document, user_id, gateway_customer_id, created_at, updated_at)
+			  SELECT UUID(), gateway, document, payer_user_id, gateway_customer_id, NOW(), NOW()
+			  FROM (
+				SELECT gateway, payer_user_id, gateway_customer_id, created_at,
+				       REPLACE(REPLACE(REPLACE(payer_cpf, '.', ''), '-', ''), '/', '') AS document
+				FROM payments
+				WHERE payer_cpf IS NOT NULL AND payer_cpf <> ''
+				  AND gateway_customer_id IS NOT NULL AND gateway_customer_id <> ''
+			  ) p
+			  ORDER BY created_at DESC`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
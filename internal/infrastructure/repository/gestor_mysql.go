@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
@@ -21,9 +23,9 @@ func NewGestorMySQLRepository(db *sqlx.DB) repository.GestorRepository {
 
 func (r *gestorMySQLRepository) FindAll(ctx context.Context) ([]entity.Gestor, error) {
 	var gestores []entity.Gestor
-	query := `SELECT id, nome, email, telefone, cpf, ativo, created_at, updated_at
+	query := `SELECT id, user_id, nome, email, telefone, cpf, ativo, created_at, updated_at, deleted_at
 			  FROM gestores
-			  WHERE ativo = 1
+			  WHERE ativo = 1 AND deleted_at IS NULL
 			  ORDER BY nome`
 	err := r.db.SelectContext(ctx, &gestores, query)
 	if err != nil {
@@ -34,9 +36,9 @@ func (r *gestorMySQLRepository) FindAll(ctx context.Context) ([]entity.Gestor, e
 
 func (r *gestorMySQLRepository) FindByID(ctx context.Context, id string) (*entity.Gestor, error) {
 	var gestor entity.Gestor
-	query := `SELECT id, nome, email, telefone, cpf, ativo, created_at, updated_at
+	query := `SELECT id, user_id, nome, email, telefone, cpf, ativo, created_at, updated_at, deleted_at
 			  FROM gestores
-			  WHERE id = ?`
+			  WHERE id = ? AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &gestor, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -49,7 +51,7 @@ func (r *gestorMySQLRepository) FindByID(ctx context.Context, id string) (*entit
 
 func (r *gestorMySQLRepository) FindByEmail(ctx context.Context, email string) (*entity.Gestor, error) {
 	var gestor entity.Gestor
-	query := `SELECT id, nome, email, telefone, cpf, ativo, created_at, updated_at
+	query := `SELECT id, user_id, nome, email, telefone, cpf, ativo, created_at, updated_at, deleted_at
 			  FROM gestores
 			  WHERE email = ?`
 	err := r.db.GetContext(ctx, &gestor, query, email)
@@ -62,13 +64,28 @@ func (r *gestorMySQLRepository) FindByEmail(ctx context.Context, email string) (
 	return &gestor, nil
 }
 
+func (r *gestorMySQLRepository) FindByUserID(ctx context.Context, userID string) (*entity.Gestor, error) {
+	var gestor entity.Gestor
+	query := `SELECT id, user_id, nome, email, telefone, cpf, ativo, created_at, updated_at, deleted_at
+			  FROM gestores
+			  WHERE user_id = ?`
+	err := r.db.GetContext(ctx, &gestor, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &gestor, nil
+}
+
 func (r *gestorMySQLRepository) FindAllWithContracts(ctx context.Context) ([]entity.GestorWithContracts, error) {
 	var gestores []entity.GestorWithContracts
-	query := `SELECT g.id, g.nome, g.email, g.telefone, g.cpf, g.ativo, g.created_at, g.updated_at,
+	query := `SELECT g.id, g.user_id, g.nome, g.email, g.telefone, g.cpf, g.ativo, g.created_at, g.updated_at, g.deleted_at,
 			  COALESCE(COUNT(c.id), 0) as total_contratos
 			  FROM gestores g
-			  LEFT JOIN contratos c ON c.gestor_id = g.id AND c.ativo = 1
-			  WHERE g.ativo = 1
+			  LEFT JOIN contratos c ON c.gestor_id = g.id AND c.ativo = 1 AND c.deleted_at IS NULL
+			  WHERE g.ativo = 1 AND g.deleted_at IS NULL
 			  GROUP BY g.id
 			  ORDER BY g.nome`
 	err := r.db.SelectContext(ctx, &gestores, query)
@@ -79,22 +96,130 @@ func (r *gestorMySQLRepository) FindAllWithContracts(ctx context.Context) ([]ent
 }
 
 func (r *gestorMySQLRepository) Create(ctx context.Context, gestor *entity.Gestor) error {
-	query := `INSERT INTO gestores (id, nome, email, telefone, cpf, ativo, created_at)
-			  VALUES (?, ?, ?, ?, ?, ?, NOW())`
-	_, err := r.db.ExecContext(ctx, query, gestor.ID, gestor.Nome, gestor.Email, gestor.Telefone, gestor.CPF, gestor.Ativo)
+	query := `INSERT INTO gestores (id, user_id, nome, email, telefone, cpf, ativo, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query, gestor.ID, gestor.UserID, gestor.Nome, gestor.Email, gestor.Telefone, gestor.CPF, gestor.Ativo)
 	return err
 }
 
 func (r *gestorMySQLRepository) Update(ctx context.Context, gestor *entity.Gestor) error {
 	query := `UPDATE gestores
-			  SET nome = ?, email = ?, telefone = ?, cpf = ?, ativo = ?, updated_at = NOW()
-			  WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, gestor.Nome, gestor.Email, gestor.Telefone, gestor.CPF, gestor.Ativo, gestor.ID)
+			  SET user_id = ?, nome = ?, email = ?, telefone = ?, cpf = ?, ativo = ?, updated_at = NOW()
+			  WHERE id = ? AND deleted_at IS NULL`
+	_, err := r.db.ExecContext(ctx, query, gestor.UserID, gestor.Nome, gestor.Email, gestor.Telefone, gestor.CPF, gestor.Ativo, gestor.ID)
 	return err
 }
 
 func (r *gestorMySQLRepository) Delete(ctx context.Context, id string) error {
-	query := `UPDATE gestores SET ativo = 0, updated_at = NOW() WHERE id = ?`
+	query := `UPDATE gestores SET deleted_at = NOW(), updated_at = NOW() WHERE id = ? AND deleted_at IS NULL`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+func (r *gestorMySQLRepository) FindDeleted(ctx context.Context) ([]entity.Gestor, error) {
+	var gestores []entity.Gestor
+	query := `SELECT id, user_id, nome, email, telefone, cpf, ativo, created_at, updated_at, deleted_at
+			  FROM gestores
+			  WHERE deleted_at IS NOT NULL
+			  ORDER BY deleted_at DESC`
+	err := r.db.SelectContext(ctx, &gestores, query)
+	if err != nil {
+		return nil, err
+	}
+	return gestores, nil
+}
+
+func (r *gestorMySQLRepository) Restore(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE gestores SET deleted_at = NULL, updated_at = NOW() WHERE id = ? AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *gestorMySQLRepository) FindContractMetrics(ctx context.Context, gestorID string, from, to, now time.Time) ([]entity.GestorContractMetrics, error) {
+	var metrics []entity.GestorContractMetrics
+	query := `SELECT c.id as contrato_id, c.nome as contrato_nome, c.ativo, c.meta_score,
+			  (SELECT COUNT(*) FROM audits a
+			   WHERE a.contract_id = c.id AND a.audit_date >= ? AND a.audit_date < ?) as audit_count,
+			  (SELECT COALESCE(SUM(a.score), 0) FROM audits a
+			   WHERE a.contract_id = c.id AND a.audit_date >= ? AND a.audit_date < ?) as audit_score_sum,
+			  (SELECT COUNT(*) FROM audits a
+			   WHERE a.contract_id = c.id AND a.audit_date >= ? AND a.audit_date < ?
+			   AND a.score >= a.target_score) as audits_on_target,
+			  (SELECT COUNT(*) FROM tasks t
+			   WHERE t.contract_id = c.id AND t.due_date >= ? AND t.due_date < ?
+			   AND t.status = 'completed' AND DATE(t.completed_at) <= DATE(t.due_date)) as tasks_completed_on_time,
+			  (SELECT COUNT(*) FROM tasks t
+			   WHERE t.contract_id = c.id AND t.due_date >= ? AND t.due_date < ?
+			   AND t.status = 'completed' AND (t.completed_at IS NULL OR DATE(t.completed_at) > DATE(t.due_date))) as tasks_completed_late,
+			  (SELECT COUNT(*) FROM tasks t
+			   WHERE t.contract_id = c.id AND t.due_date >= ? AND t.due_date < ?
+			   AND t.status IN ('pending', 'in_progress') AND DATE(t.due_date) < DATE(?)) as tasks_overdue,
+			  (SELECT COUNT(*) FROM inspections i
+			   WHERE i.contract_id = c.id AND i.inspection_date >= ? AND i.inspection_date < ?
+			   AND (i.status = 'completed' OR (i.status <> 'cancelled' AND i.inspection_date <= ?))) as inspections_due,
+			  (SELECT COUNT(*) FROM inspections i
+			   WHERE i.contract_id = c.id AND i.inspection_date >= ? AND i.inspection_date < ?
+			   AND i.status = 'completed') as inspections_completed
+			  FROM contratos c
+			  WHERE c.gestor_id = ? AND c.deleted_at IS NULL
+			  ORDER BY c.nome`
+	err := r.db.SelectContext(ctx, &metrics, query,
+		from, to, from, to, from, to,
+		from, to, from, to, from, to, now,
+		from, to, now, from, to,
+		gestorID)
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// Reassign locks the rows it moves, so the summary lists exactly what was updated
+func (r *gestorMySQLRepository) Reassign(ctx context.Context, fromID, toID string) (*entity.GestorReassignment, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &entity.GestorReassignment{FromGestorID: fromID, ToGestorID: toID}
+	moves := []struct {
+		ids    *[]string
+		table  string
+		column string
+		where  string
+	}{
+		{&result.ContractIDs, "contratos", "gestor_id", "deleted_at IS NULL"},
+		{&result.TaskIDs, "tasks", "assigned_to", "status IN ('pending', 'in_progress')"},
+		{&result.InspectionIDs, "inspections", "inspector_id", "status = 'scheduled'"},
+	}
+	for _, m := range moves {
+		*m.ids = []string{}
+		query := fmt.Sprintf(`SELECT id FROM %s WHERE %s = ? AND %s ORDER BY id FOR UPDATE`, m.table, m.column, m.where)
+		if err := tx.SelectContext(ctx, m.ids, query, fromID); err != nil {
+			return nil, err
+		}
+		if len(*m.ids) == 0 {
+			continue
+		}
+		update := fmt.Sprintf(`UPDATE %s SET %s = ?, updated_at = NOW() WHERE %s = ? AND %s`, m.table, m.column, m.column, m.where)
+		if _, err := tx.ExecContext(ctx, update, toID, fromID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	result.Contracts = len(result.ContractIDs)
+	result.Tasks = len(result.TaskIDs)
+	result.Inspections = len(result.InspectionIDs)
+	return result, nil
+}
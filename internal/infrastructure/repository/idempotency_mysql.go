@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type idempotencyMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewIdempotencyMySQLRepository creates a new MySQL implementation of IdempotencyRepository
+func NewIdempotencyMySQLRepository(db *sqlx.DB) repository.IdempotencyRepository {
+	return &idempotencyMySQLRepository{db: db}
+}
+
+func (r *idempotencyMySQLRepository) Reserve(ctx context.Context, key *entity.IdempotencyKey, ttl, staleAfter time.Duration) (bool, error) {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys
+			  WHERE id = ? AND (expires_at < NOW() OR (completed_at IS NULL AND created_at < NOW() - INTERVAL ? SECOND))`,
+		key.ID, int(staleAfter.Seconds()))
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.db.ExecContext(ctx, `INSERT IGNORE INTO idempotency_keys
+			  (id, idempotency_key, user_id, route, request_hash, created_at, expires_at)
+			  VALUES (?, ?, ?, ?, ?, NOW(), NOW() + INTERVAL ? SECOND)`,
+		key.ID, key.Key, key.UserID, key.Route, key.RequestHash, int(ttl.Seconds()))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *idempotencyMySQLRepository) FindByID(ctx context.Context, id string) (*entity.IdempotencyKey, error) {
+	var key entity.IdempotencyKey
+	err := r.db.GetContext(ctx, &key, `SELECT id, idempotency_key, user_id, route, request_hash, status_code,
+			  content_type, response_body, created_at, completed_at, expires_at
+			  FROM idempotency_keys WHERE id = ?`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *idempotencyMySQLRepository) Complete(ctx context.Context, key *entity.IdempotencyKey) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE idempotency_keys
+			  SET status_code = ?, content_type = ?, response_body = ?, completed_at = NOW()
+			  WHERE id = ?`,
+		key.StatusCode, key.ContentType, key.ResponseBody, key.ID)
+	return err
+}
+
+func (r *idempotencyMySQLRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE id = ?`, id)
+	return err
+}
+
+func (r *idempotencyMySQLRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
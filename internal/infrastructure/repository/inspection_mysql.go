@@ -38,6 +38,18 @@ func (r *inspectionMySQLRepository) FindAll(ctx context.Context) ([]entity.Inspe
 	return inspections, nil
 }
 
+// inspectionListSchema lists the fields accepted in inspection filter[...] and sort parameters
+var inspectionListSchema = listSchema{
+	"status":          {expr: "i.status", kind: kindString, sortable: true},
+	"inspection_type": {expr: "i.inspection_type", kind: kindString, sortable: true},
+	"contract_id":     {expr: "i.contract_id", kind: kindString},
+	"contract_name":   {expr: "c.nome", kind: kindString, sortable: true},
+	"inspector_id":    {expr: "i.inspector_id", kind: kindString},
+	"inspection_date": {expr: "i.inspection_date", kind: kindTime, sortable: true},
+	"created_at":      {expr: "i.created_at", kind: kindTime, sortable: true},
+	"updated_at":      {expr: "i.updated_at", kind: kindTime, sortable: true},
+}
+
 func (r *inspectionMySQLRepository) FindAllWithFilters(ctx context.Context, filter *entity.InspectionFilter) ([]entity.Inspection, error) {
 	var inspections []entity.Inspection
 	query := `SELECT i.id, i.contract_id, c.nome as contract_name,
@@ -73,9 +85,28 @@ func (r *inspectionMySQLRepository) FindAllWithFilters(ctx context.Context, filt
 			query += " AND i.inspection_date <= ?"
 			args = append(args, filter.EndDate)
 		}
+
+		conditions, queryArgs, err := inspectionListSchema.where(filter.Query)
+		if err != nil {
+			return nil, err
+		}
+		for _, cond := range conditions {
+			query += " AND " + cond
+		}
+		args = append(args, queryArgs...)
 	}
 
-	query += " ORDER BY i.inspection_date DESC"
+	orderBy := "i.inspection_date DESC"
+	if filter != nil {
+		sorted, err := inspectionListSchema.orderBy(filter.Query)
+		if err != nil {
+			return nil, err
+		}
+		if sorted != "" {
+			orderBy = sorted
+		}
+	}
+	query += " ORDER BY " + orderBy
 
 	err := r.db.SelectContext(ctx, &inspections, query, args...)
 	if err != nil {
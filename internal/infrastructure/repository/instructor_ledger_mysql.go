@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type instructorLedgerMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewInstructorLedgerMySQLRepository creates a new MySQL implementation of InstructorLedgerRepository
+func NewInstructorLedgerMySQLRepository(db *sqlx.DB) repository.InstructorLedgerRepository {
+	return &instructorLedgerMySQLRepository{db: db}
+}
+
+const instructorLedgerInsert = `INSERT IGNORE INTO instructor_ledger_entries (id, instructor_id, entry_key, entry_type,
+			  amount, revenue_split_id, payout_batch_id, enrollment_id, description, occurred_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+
+func (r *instructorLedgerMySQLRepository) Create(ctx context.Context, entry *entity.InstructorLedgerEntry) error {
+	_, err := r.db.ExecContext(ctx, instructorLedgerInsert, instructorLedgerArgs(entry)...)
+	return err
+}
+
+func (r *instructorLedgerMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, entry *entity.InstructorLedgerEntry) error {
+	_, err := tx.ExecContext(ctx, instructorLedgerInsert, instructorLedgerArgs(entry)...)
+	return err
+}
+
+func instructorLedgerArgs(e *entity.InstructorLedgerEntry) []interface{} {
+	return []interface{}{
+		e.ID, e.InstructorID, e.EntryKey, e.EntryType, e.Amount, e.RevenueSplitID, e.PayoutBatchID,
+		e.EnrollmentID, e.Description, e.OccurredAt,
+	}
+}
+
+func (r *instructorLedgerMySQLRepository) FindByInstructor(ctx context.Context, instructorID string, from, to *time.Time) ([]entity.InstructorLedgerEntry, error) {
+	query := `SELECT id, instructor_id, entry_key, entry_type, amount, revenue_split_id, payout_batch_id,
+			  enrollment_id, description, occurred_at, created_at
+			  FROM instructor_ledger_entries
+			  WHERE instructor_id = ?`
+	args := []interface{}{instructorID}
+
+	if from != nil {
+		query += " AND occurred_at >= ?"
+		args = append(args, *from)
+	}
+	if to != nil {
+		query += " AND occurred_at < ?"
+		args = append(args, *to)
+	}
+	query += " ORDER BY occurred_at, created_at, entry_key"
+
+	var entries []entity.InstructorLedgerEntry
+	err := r.db.SelectContext(ctx, &entries, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *instructorLedgerMySQLRepository) GetBalance(ctx context.Context, instructorID string, before time.Time) (float64, error) {
+	var balance float64
+	query := `SELECT COALESCE(SUM(amount), 0) FROM instructor_ledger_entries
+			  WHERE instructor_id = ? AND occurred_at < ?`
+	err := r.db.GetContext(ctx, &balance, query, instructorID, before)
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (r *instructorLedgerMySQLRepository) GetTotals(ctx context.Context, instructorID string) (*entity.InstructorLedgerTotals, error) {
+	var rows []struct {
+		EntryType string  `db:"entry_type"`
+		Total     float64 `db:"total"`
+	}
+	query := `SELECT entry_type, COALESCE(SUM(amount), 0) as total FROM instructor_ledger_entries
+			  WHERE instructor_id = ? GROUP BY entry_type`
+	err := r.db.SelectContext(ctx, &rows, query, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &entity.InstructorLedgerTotals{}
+	for _, row := range rows {
+		switch row.EntryType {
+		case entity.LedgerEntryCredit:
+			totals.Credits = row.Total
+		case entity.LedgerEntryRefund:
+			totals.Refunds = row.Total
+		case entity.LedgerEntryChargeback:
+			totals.Chargebacks = row.Total
+		case entity.LedgerEntryAdjustment:
+			totals.Adjustments = row.Total
+		case entity.LedgerEntryPayout:
+			totals.Payouts = row.Total
+		}
+	}
+	return totals, nil
+}
+
+func (r *instructorLedgerMySQLRepository) GetSplitEarnings(ctx context.Context, splitID string) (float64, error) {
+	var total float64
+	query := `SELECT COALESCE(SUM(amount), 0) FROM instructor_ledger_entries
+			  WHERE revenue_split_id = ? AND entry_type <> 'payout'`
+	err := r.db.GetContext(ctx, &total, query, splitID)
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type instructorPayoutAccountMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewInstructorPayoutAccountMySQLRepository creates a new MySQL implementation of InstructorPayoutAccountRepository
+func NewInstructorPayoutAccountMySQLRepository(db *sqlx.DB) repository.InstructorPayoutAccountRepository {
+	return &instructorPayoutAccountMySQLRepository{db: db}
+}
+
+func (r *instructorPayoutAccountMySQLRepository) FindByInstructorID(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error) {
+	var account entity.InstructorPayoutAccount
+	query := `SELECT instructor_id, method, wallet_id, pix_key, pix_key_type, bank_code, bank_agency,
+			  bank_account, bank_account_digit, bank_account_type, owner_name, owner_document,
+			  auto_transfer, created_at, updated_at, verification_status, verification_method,
+			  micro_deposit_amount, micro_deposit_transfer_id, verification_attempts, verification_error, verified_at
+			  FROM instructor_payout_accounts
+			  WHERE instructor_id = ?`
+	err := r.db.GetContext(ctx, &account, query, instructorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *instructorPayoutAccountMySQLRepository) Save(ctx context.Context, account *entity.InstructorPayoutAccount) error {
+	query := `INSERT INTO instructor_payout_accounts (instructor_id, method, wallet_id, pix_key, pix_key_type,
+			  bank_code, bank_agency, bank_account, bank_account_digit, bank_account_type, owner_name,
+			  owner_document, auto_transfer, verification_status, verification_method, micro_deposit_amount,
+			  micro_deposit_transfer_id, verification_attempts, verification_error, verified_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())
+			  ON DUPLICATE KEY UPDATE method = VALUES(method), wallet_id = VALUES(wallet_id),
+			  pix_key = VALUES(pix_key), pix_key_type = VALUES(pix_key_type), bank_code = VALUES(bank_code),
+			  bank_agency = VALUES(bank_agency), bank_account = VALUES(bank_account),
+			  bank_account_digit = VALUES(bank_account_digit), bank_account_type = VALUES(bank_account_type),
+			  owner_name = VALUES(owner_name), owner_document = VALUES(owner_document),
+			  auto_transfer = VALUES(auto_transfer), verification_status = VALUES(verification_status),
+			  verification_method = VALUES(verification_method), micro_deposit_amount = VALUES(micro_deposit_amount),
+			  micro_deposit_transfer_id = VALUES(micro_deposit_transfer_id),
+			  verification_attempts = VALUES(verification_attempts), verification_error = VALUES(verification_error),
+			  verified_at = VALUES(verified_at), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query,
+		account.InstructorID, account.Method, account.WalletID, account.PixKey, account.PixKeyType,
+		account.BankCode, account.BankAgency, account.BankAccount, account.BankAccountDigit,
+		account.BankAccountType, account.OwnerName, account.OwnerDocument, account.AutoTransfer,
+		account.VerificationStatus, account.VerificationMethod, account.MicroDepositAmount,
+		account.MicroDepositTransferID, account.VerificationAttempts, account.VerificationError, account.VerifiedAt)
+	return err
+}
+
+func (r *instructorPayoutAccountMySQLRepository) UpdateVerification(ctx context.Context, account *entity.InstructorPayoutAccount, attempts int) (bool, error) {
+	query := `UPDATE instructor_payout_accounts
+			  SET verification_status = ?, verification_method = ?, micro_deposit_amount = ?,
+			  micro_deposit_transfer_id = ?, verification_attempts = ?, verification_error = ?, verified_at = ?
+			  WHERE instructor_id = ? AND verification_attempts = ?`
+	result, err := r.db.ExecContext(ctx, query,
+		account.VerificationStatus, account.VerificationMethod, account.MicroDepositAmount,
+		account.MicroDepositTransferID, account.VerificationAttempts, account.VerificationError, account.VerifiedAt,
+		account.InstructorID, attempts)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type legacyUsageMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewLegacyUsageMySQLRepository creates a new MySQL implementation of LegacyUsageRepository. reader serves
+// the report queries and may be a read replica; writes go to db.
+func NewLegacyUsageMySQLRepository(db, reader *sqlx.DB) repository.LegacyUsageRepository {
+	return &legacyUsageMySQLRepository{db: db, reader: reader}
+}
+
+func (r *legacyUsageMySQLRepository) Add(ctx context.Context, usage []entity.LegacyUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+
+	rows := make([]string, len(usage))
+	args := make([]interface{}, 0, len(usage)*7)
+	for i, u := range usage {
+		rows[i] = "(?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, u.Day.Format("2006-01-02"), u.Endpoint, u.Method, u.Requests, u.Errors, u.LastUserID, u.LastSeenAt)
+	}
+	query := `INSERT INTO legacy_usage (day, endpoint, method, requests, errors, last_user_id, last_seen_at)
+			  VALUES ` + strings.Join(rows, ", ") + `
+			  ON DUPLICATE KEY UPDATE
+			  requests = requests + VALUES(requests),
+			  errors = errors + VALUES(errors),
+			  last_user_id = COALESCE(VALUES(last_user_id), last_user_id),
+			  last_seen_at = GREATEST(last_seen_at, VALUES(last_seen_at))`
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *legacyUsageMySQLRepository) Summary(ctx context.Context, since time.Time) ([]entity.LegacyUsageSummary, error) {
+	var summary []entity.LegacyUsageSummary
+	query := `SELECT endpoint, method, SUM(requests) AS requests, SUM(errors) AS errors,
+			  COUNT(*) AS active_days, MIN(day) AS first_seen_at, MAX(last_seen_at) AS last_seen_at
+			  FROM legacy_usage
+			  WHERE day >= ?
+			  GROUP BY endpoint, method
+			  ORDER BY requests DESC, endpoint, method`
+	if err := r.reader.SelectContext(ctx, &summary, query, since.Format("2006-01-02")); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
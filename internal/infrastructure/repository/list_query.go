@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/listquery"
+)
+
+// columnKind tells how list query values are parsed for a column
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindNumber
+	kindTime
+)
+
+// listColumn maps a list query field onto a SQL expression; sortExpr, when set, replaces
+// expr in ORDER BY
+type listColumn struct {
+	expr     string
+	kind     columnKind
+	sortable bool
+	sortExpr string
+}
+
+// listSchema holds the fields a repository accepts in filter[...] and sort
+type listSchema map[string]listColumn
+
+var kindOps = map[columnKind]map[string]bool{
+	kindString: {listquery.OpEq: true, listquery.OpNe: true, listquery.OpIn: true, listquery.OpLike: true},
+	kindNumber: {listquery.OpEq: true, listquery.OpNe: true, listquery.OpIn: true,
+		listquery.OpGt: true, listquery.OpGte: true, listquery.OpLt: true, listquery.OpLte: true},
+	kindTime: {listquery.OpEq: true, listquery.OpGt: true, listquery.OpGte: true, listquery.OpLt: true, listquery.OpLte: true},
+}
+
+var sqlOps = map[string]string{
+	listquery.OpEq: "=", listquery.OpNe: "<>",
+	listquery.OpGt: ">", listquery.OpGte: ">=", listquery.OpLt: "<", listquery.OpLte: "<=",
+}
+
+// where translates the filters into SQL conditions and their arguments
+func (s listSchema) where(q listquery.Query) ([]string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	for _, f := range q.Filters {
+		col, ok := s[f.Field]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: cannot filter by %q", listquery.ErrInvalid, f.Field)
+		}
+		if !kindOps[col.kind][f.Op] {
+			return nil, nil, fmt.Errorf("%w: operator %q not supported for %q", listquery.ErrInvalid, f.Op, f.Field)
+		}
+
+		switch f.Op {
+		case listquery.OpIn:
+			values := f.Values()
+			if len(values) == 0 {
+				return nil, nil, fmt.Errorf("%w: empty list for %q", listquery.ErrInvalid, f.Field)
+			}
+			placeholders := make([]string, len(values))
+			for i, v := range values {
+				arg, err := col.parse(f.Field, v)
+				if err != nil {
+					return nil, nil, err
+				}
+				placeholders[i] = "?"
+				args = append(args, arg)
+			}
+			conditions = append(conditions, fmt.Sprintf("%s IN (%s)", col.expr, strings.Join(placeholders, ", ")))
+
+		case listquery.OpLike:
+			pattern := strings.NewReplacer("%", `\%`, "_", `\_`).Replace(f.Value)
+			conditions = append(conditions, col.expr+" LIKE ?")
+			args = append(args, "%"+pattern+"%")
+
+		default:
+			if col.kind == kindTime && isDate(f.Value) {
+				cond, dayArgs, err := col.dateCondition(f)
+				if err != nil {
+					return nil, nil, err
+				}
+				conditions = append(conditions, cond)
+				args = append(args, dayArgs...)
+				continue
+			}
+			arg, err := col.parse(f.Field, f.Value)
+			if err != nil {
+				return nil, nil, err
+			}
+			conditions = append(conditions, fmt.Sprintf("%s %s ?", col.expr, sqlOps[f.Op]))
+			args = append(args, arg)
+		}
+	}
+	return conditions, args, nil
+}
+
+// orderBy translates the sort into an ORDER BY list; it is empty without sort fields
+func (s listSchema) orderBy(q listquery.Query) (string, error) {
+	parts := make([]string, 0, len(q.Sort))
+	for _, srt := range q.Sort {
+		col, ok := s[srt.Field]
+		if !ok || !col.sortable {
+			return "", fmt.Errorf("%w: cannot sort by %q", listquery.ErrInvalid, srt.Field)
+		}
+		dir := "ASC"
+		if srt.Desc {
+			dir = "DESC"
+		}
+		expr := col.expr
+		if col.sortExpr != "" {
+			expr = col.sortExpr
+		}
+		parts = append(parts, expr+" "+dir)
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+func (c listColumn) parse(field, value string) (interface{}, error) {
+	switch c.kind {
+	case kindNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q expects a number", listquery.ErrInvalid, field)
+		}
+		return n, nil
+	case kindTime:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q expects a date (YYYY-MM-DD) or RFC 3339 time", listquery.ErrInvalid, field)
+		}
+		return t, nil
+	}
+	return value, nil
+}
+
+// dateCondition compares a time column with a whole day: eq matches the day, lte includes
+// it and gt starts after it
+func (c listColumn) dateCondition(f listquery.Filter) (string, []interface{}, error) {
+	day, err := time.Parse("2006-01-02", f.Value)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %q expects a date (YYYY-MM-DD) or RFC 3339 time", listquery.ErrInvalid, f.Field)
+	}
+	next := day.AddDate(0, 0, 1)
+
+	switch f.Op {
+	case listquery.OpEq:
+		return fmt.Sprintf("(%s >= ? AND %s < ?)", c.expr, c.expr), []interface{}{day, next}, nil
+	case listquery.OpGt:
+		return c.expr + " >= ?", []interface{}{next}, nil
+	case listquery.OpLte:
+		return c.expr + " < ?", []interface{}{next}, nil
+	}
+	return fmt.Sprintf("%s %s ?", c.expr, sqlOps[f.Op]), []interface{}{day}, nil
+}
+
+func isDate(value string) bool {
+	return len(value) == len("2006-01-02") && !strings.Contains(value, "T")
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/jmoiron/sqlx"
 )
@@ -19,10 +21,42 @@ func NewMatriculaMySQLRepository(db *sqlx.DB) repository.MatriculaRepository {
 	return &matriculaMySQLRepository{db: db}
 }
 
-func (r *matriculaMySQLRepository) FindAll(ctx context.Context, page, perPage int) ([]entity.Matricula, int, error) {
+// enrollmentListSchema lists the fields accepted in enrollment filter[...] and sort parameters
+var enrollmentListSchema = listSchema{
+	"status":          {expr: "status", kind: kindString, sortable: true},
+	"payment_status":  {expr: "payment_status", kind: kindString, sortable: true},
+	"payment_method":  {expr: "payment_method", kind: kindString},
+	"student_id":      {expr: "student_id", kind: kindString},
+	"student_name":    {expr: "student_name", kind: kindString, sortable: true},
+	"student_email":   {expr: "student_email", kind: kindString},
+	"course_id":       {expr: "course_id", kind: kindString},
+	"course_name":     {expr: "course_name", kind: kindString, sortable: true},
+	"instructor_id":   {expr: "instructor_id", kind: kindString},
+	"final_amount":    {expr: "final_amount", kind: kindNumber, sortable: true},
+	"progress":        {expr: "progress", kind: kindNumber, sortable: true},
+	"enrollment_date": {expr: "enrollment_date", kind: kindTime, sortable: true},
+	"expiration_date": {expr: "expiration_date", kind: kindTime, sortable: true},
+	"created_at":      {expr: "created_at", kind: kindTime, sortable: true},
+	"updated_at":      {expr: "updated_at", kind: kindTime, sortable: true},
+}
+
+func (r *matriculaMySQLRepository) FindAll(ctx context.Context, page, perPage int, lq listquery.Query) ([]entity.Matricula, int, error) {
+	conditions, args, err := enrollmentListSchema.where(lq)
+	if err != nil {
+		return nil, 0, err
+	}
+	orderBy, err := enrollmentListSchema.orderBy(lq)
+	if err != nil {
+		return nil, 0, err
+	}
+	if orderBy == "" {
+		orderBy = "created_at DESC"
+	}
+	whereClause := strings.Join(append([]string{"1=1"}, conditions...), " AND ")
+
 	var total int
-	countQuery := `SELECT COUNT(*) FROM enrollments`
-	if err := r.db.GetContext(ctx, &total, countQuery); err != nil {
+	countQuery := `SELECT COUNT(*) FROM enrollments WHERE ` + whereClause
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
 		return nil, 0, err
 	}
 
@@ -34,15 +68,83 @@ func (r *matriculaMySQLRepository) FindAll(ctx context.Context, page, perPage in
 			  expiration_date, status, progress, certificate_id, asaas_customer_id, asaas_payment_id,
 			  created_at, updated_at
 			  FROM enrollments
-			  ORDER BY created_at DESC
+			  WHERE ` + whereClause + `
+			  ORDER BY ` + orderBy + `
 			  LIMIT ? OFFSET ?`
-	err := r.db.SelectContext(ctx, &matriculas, query, perPage, offset)
+	err = r.db.SelectContext(ctx, &matriculas, query, append(args, perPage, offset)...)
 	if err != nil {
 		return nil, 0, err
 	}
 	return matriculas, total, nil
 }
 
+func (r *matriculaMySQLRepository) Search(ctx context.Context, filter *entity.EnrollmentSearchFilter) ([]entity.Matricula, int, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+
+	escape := strings.NewReplacer("%", `\%`, "_", `\_`)
+	for _, term := range strings.Fields(filter.Terms) {
+		pattern := "%" + escape.Replace(term) + "%"
+		match := []string{"student_name LIKE ?", "student_email LIKE ?", "course_name LIKE ?"}
+		args = append(args, pattern, pattern, pattern)
+		// CPFs are stored with or without punctuation, so digits are compared on their own
+		if digits := onlyDigits(term); len(digits) >= 3 {
+			match = append(match, "REPLACE(REPLACE(student_cpf, '.', ''), '-', '') LIKE ?")
+			args = append(args, "%"+digits+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(match, " OR ")+")")
+	}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "status IN (?"+strings.Repeat(", ?", len(filter.Statuses)-1)+")")
+		for _, status := range filter.Statuses {
+			args = append(args, status)
+		}
+	}
+	if len(filter.PaymentStatuses) > 0 {
+		conditions = append(conditions, "payment_status IN (?"+strings.Repeat(", ?", len(filter.PaymentStatuses)-1)+")")
+		for _, status := range filter.PaymentStatuses {
+			args = append(args, status)
+		}
+	}
+	if filter.CourseID != nil {
+		conditions = append(conditions, "course_id = ?")
+		args = append(args, *filter.CourseID)
+	}
+	whereClause := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM enrollments WHERE `+whereClause, args...); err != nil {
+		return nil, 0, err
+	}
+
+	offset := (filter.Page - 1) * filter.PerPage
+	var matriculas []entity.Matricula
+	query := `SELECT id, student_id, student_name, student_email, student_cpf, student_phone,
+			  course_id, course_name, instructor_id, instructor_name, payment_id, payment_status,
+			  amount, discount_amount, final_amount, payment_method, enrollment_date, completion_date,
+			  expiration_date, status, progress, certificate_id, asaas_customer_id, asaas_payment_id,
+			  created_at, updated_at
+			  FROM enrollments
+			  WHERE ` + whereClause + `
+			  ORDER BY created_at DESC
+			  LIMIT ? OFFSET ?`
+	if err := r.db.SelectContext(ctx, &matriculas, query, append(args, filter.PerPage, offset)...); err != nil {
+		return nil, 0, err
+	}
+	return matriculas, total, nil
+}
+
+// onlyDigits returns the digits of s
+func onlyDigits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func (r *matriculaMySQLRepository) FindByID(ctx context.Context, id string) (*entity.Matricula, error) {
 	var matricula entity.Matricula
 	query := `SELECT id, student_id, student_name, student_email, student_cpf, student_phone,
@@ -62,6 +164,25 @@ func (r *matriculaMySQLRepository) FindByID(ctx context.Context, id string) (*en
 	return &matricula, nil
 }
 
+func (r *matriculaMySQLRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.Matricula, error) {
+	var matricula entity.Matricula
+	query := `SELECT id, student_id, student_name, student_email, student_cpf, student_phone,
+			  course_id, course_name, instructor_id, instructor_name, payment_id, payment_status,
+			  amount, discount_amount, final_amount, payment_method, enrollment_date, completion_date,
+			  expiration_date, status, progress, certificate_id, asaas_customer_id, asaas_payment_id,
+			  created_at, updated_at
+			  FROM enrollments
+			  WHERE id = ? FOR UPDATE`
+	err := tx.GetContext(ctx, &matricula, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &matricula, nil
+}
+
 func (r *matriculaMySQLRepository) FindByStudentID(ctx context.Context, studentID string) ([]entity.Matricula, error) {
 	var matriculas []entity.Matricula
 	query := `SELECT id, student_id, student_name, student_email, student_cpf, student_phone,
@@ -139,15 +260,15 @@ func (r *matriculaMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx
 
 func (r *matriculaMySQLRepository) Update(ctx context.Context, m *entity.Matricula) error {
 	query := `UPDATE enrollments SET
-			  student_name = ?, student_email = ?, student_cpf = ?, student_phone = ?,
-			  course_name = ?, instructor_id = ?, instructor_name = ?, payment_id = ?, payment_status = ?,
+			  student_id = ?, student_name = ?, student_email = ?, student_cpf = ?, student_phone = ?,
+			  course_id = ?, course_name = ?, instructor_id = ?, instructor_name = ?, payment_id = ?, payment_status = ?,
 			  amount = ?, discount_amount = ?, final_amount = ?, payment_method = ?, completion_date = ?,
 			  expiration_date = ?, status = ?, progress = ?, certificate_id = ?,
 			  asaas_customer_id = ?, asaas_payment_id = ?, updated_at = NOW()
 			  WHERE id = ?`
 	_, err := r.db.ExecContext(ctx, query,
-		m.StudentName, m.StudentEmail, m.StudentCPF, m.StudentPhone,
-		m.CourseName, m.InstructorID, m.InstructorName, m.PaymentID, m.PaymentStatus,
+		m.StudentID, m.StudentName, m.StudentEmail, m.StudentCPF, m.StudentPhone,
+		m.CourseID, m.CourseName, m.InstructorID, m.InstructorName, m.PaymentID, m.PaymentStatus,
 		m.Amount, m.DiscountAmount, m.FinalAmount, m.PaymentMethod, m.CompletionDate,
 		m.ExpirationDate, m.Status, m.Progress, m.CertificateID,
 		m.AsaasCustomerID, m.AsaasPaymentID, m.ID)
@@ -156,15 +277,15 @@ func (r *matriculaMySQLRepository) Update(ctx context.Context, m *entity.Matricu
 
 func (r *matriculaMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, m *entity.Matricula) error {
 	query := `UPDATE enrollments SET
-			  student_name = ?, student_email = ?, student_cpf = ?, student_phone = ?,
-			  course_name = ?, instructor_id = ?, instructor_name = ?, payment_id = ?, payment_status = ?,
+			  student_id = ?, student_name = ?, student_email = ?, student_cpf = ?, student_phone = ?,
+			  course_id = ?, course_name = ?, instructor_id = ?, instructor_name = ?, payment_id = ?, payment_status = ?,
 			  amount = ?, discount_amount = ?, final_amount = ?, payment_method = ?, completion_date = ?,
 			  expiration_date = ?, status = ?, progress = ?, certificate_id = ?,
 			  asaas_customer_id = ?, asaas_payment_id = ?, updated_at = NOW()
 			  WHERE id = ?`
 	_, err := tx.ExecContext(ctx, query,
-		m.StudentName, m.StudentEmail, m.StudentCPF, m.StudentPhone,
-		m.CourseName, m.InstructorID, m.InstructorName, m.PaymentID, m.PaymentStatus,
+		m.StudentID, m.StudentName, m.StudentEmail, m.StudentCPF, m.StudentPhone,
+		m.CourseID, m.CourseName, m.InstructorID, m.InstructorName, m.PaymentID, m.PaymentStatus,
 		m.Amount, m.DiscountAmount, m.FinalAmount, m.PaymentMethod, m.CompletionDate,
 		m.ExpirationDate, m.Status, m.Progress, m.CertificateID,
 		m.AsaasCustomerID, m.AsaasPaymentID, m.ID)
@@ -183,6 +304,40 @@ func (r *matriculaMySQLRepository) UpdatePaymentStatusWithTx(ctx context.Context
 	return err
 }
 
+func (r *matriculaMySQLRepository) ClaimCancellationWithTx(ctx context.Context, tx *sqlx.Tx, id string) (bool, error) {
+	query := `UPDATE enrollments SET status = 'cancelling', updated_at = NOW()
+			  WHERE id = ? AND status NOT IN ('cancelled', 'cancelling')`
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *matriculaMySQLRepository) ClaimRetry(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE enrollments SET status = 'retrying', updated_at = NOW()
+			  WHERE id = ? AND status IN ('pending', 'expired') AND payment_status IN ('pending', 'overdue', 'failed')`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *matriculaMySQLRepository) ReleaseRetry(ctx context.Context, id, status string) error {
+	query := `UPDATE enrollments SET status = ?, updated_at = NOW() WHERE id = ? AND status = 'retrying'`
+	_, err := r.db.ExecContext(ctx, query, status, id)
+	return err
+}
+
 func (r *matriculaMySQLRepository) UpdateStatus(ctx context.Context, id, status string) error {
 	query := `UPDATE enrollments SET status = ?, updated_at = NOW() WHERE id = ?`
 	_, err := r.db.ExecContext(ctx, query, status, id)
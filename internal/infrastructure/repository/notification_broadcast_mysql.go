@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type notificationBroadcastMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewNotificationBroadcastMySQLRepository creates a new MySQL implementation of NotificationBroadcastRepository.
+// reader serves the listing and the engagement stats and may be a read replica; writes go to db.
+func NewNotificationBroadcastMySQLRepository(db, reader *sqlx.DB) repository.NotificationBroadcastRepository {
+	return &notificationBroadcastMySQLRepository{db: db, reader: reader}
+}
+
+const notificationBroadcastSelect = `SELECT id, type, title, message, target_role, recipients, created_by, created_at
+			  FROM notification_broadcasts`
+
+func (r *notificationBroadcastMySQLRepository) Create(ctx context.Context, b *entity.NotificationBroadcast) error {
+	query := `INSERT INTO notification_broadcasts (id, type, title, message, target_role, recipients, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, b.ID, b.Type, b.Title, b.Message, b.TargetRole, b.Recipients, b.CreatedBy, b.CreatedAt)
+	return err
+}
+
+func (r *notificationBroadcastMySQLRepository) SetRecipients(ctx context.Context, id string, recipients int) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notification_broadcasts SET recipients = ? WHERE id = ?`, recipients, id)
+	return err
+}
+
+func (r *notificationBroadcastMySQLRepository) FindByID(ctx context.Context, id string) (*entity.NotificationBroadcast, error) {
+	var b entity.NotificationBroadcast
+	if err := r.db.GetContext(ctx, &b, notificationBroadcastSelect+` WHERE id = ?`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *notificationBroadcastMySQLRepository) FindRecent(ctx context.Context, limit int) ([]entity.NotificationBroadcast, error) {
+	var broadcasts []entity.NotificationBroadcast
+	err := r.reader.SelectContext(ctx, &broadcasts, notificationBroadcastSelect+` ORDER BY created_at DESC LIMIT ?`, limit)
+	return broadcasts, err
+}
+
+func (r *notificationBroadcastMySQLRepository) ChannelStats(ctx context.Context, id string) ([]entity.BroadcastChannelStats, error) {
+	// Every notification is in the app from its creation, so in-app is sent and delivered
+	query := `SELECT 'in_app' AS channel,
+			  COUNT(*) AS total,
+			  0 AS pending,
+			  COUNT(*) AS sent,
+			  0 AS failed,
+			  0 AS skipped,
+			  COUNT(*) AS delivered,
+			  COALESCE(SUM(is_read), 0) AS opened
+			  FROM notifications
+			  WHERE broadcast_id = ?
+			  UNION ALL
+			  SELECT d.channel,
+			  COUNT(*) AS total,
+			  COALESCE(SUM(d.status IN ('pending', 'sending')), 0) AS pending,
+			  COALESCE(SUM(d.status = 'sent'), 0) AS sent,
+			  COALESCE(SUM(d.status = 'failed'), 0) AS failed,
+			  COALESCE(SUM(d.status = 'skipped'), 0) AS skipped,
+			  COALESCE(SUM(d.delivered_at IS NOT NULL), 0) AS delivered,
+			  COALESCE(SUM(d.opened_at IS NOT NULL), 0) AS opened
+			  FROM notification_deliveries d
+			  JOIN notifications n ON n.id = d.notification_id
+			  WHERE n.broadcast_id = ?
+			  GROUP BY d.channel`
+	var stats []entity.BroadcastChannelStats
+	if err := r.reader.SelectContext(ctx, &stats, query, id, id); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
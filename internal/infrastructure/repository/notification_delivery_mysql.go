@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type notificationDeliveryMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationDeliveryMySQLRepository creates a new MySQL implementation of NotificationDeliveryRepository
+func NewNotificationDeliveryMySQLRepository(db *sqlx.DB) repository.NotificationDeliveryRepository {
+	return &notificationDeliveryMySQLRepository{db: db}
+}
+
+const notificationDeliverySelect = `SELECT id, notification_id, user_id, channel, status, attempts, next_attempt_at,
+			  last_error, sent_at, delivered_at, opened_at, created_at, updated_at
+			  FROM notification_deliveries`
+
+func (r *notificationDeliveryMySQLRepository) CreateDeliveries(ctx context.Context, deliveries []entity.NotificationDelivery) error {
+	// A notification queued twice on a channel keeps its first delivery
+	return insertBatch(ctx, r.db,
+		`INSERT IGNORE INTO notification_deliveries (id, notification_id, user_id, channel, status, attempts, next_attempt_at, created_at)`,
+		"(?, ?, ?, ?, ?, 0, ?, NOW())", len(deliveries), func(i int) []interface{} {
+			d := deliveries[i]
+			return []interface{}{d.ID, d.NotificationID, d.UserID, d.Channel, d.Status, d.NextAttemptAt}
+		})
+}
+
+func (r *notificationDeliveryMySQLRepository) ClaimDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.NotificationDelivery, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// SKIP LOCKED lets several instances dispatch different deliveries at the same time
+	var deliveries []entity.NotificationDelivery
+	query := notificationDeliverySelect + `
+			  WHERE (status = 'pending' AND next_attempt_at <= ?) OR (status = 'sending' AND updated_at < ?)
+			  ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED`
+	if err := tx.SelectContext(ctx, &deliveries, query, now, staleBefore, limit); err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(deliveries))
+	for i := range deliveries {
+		ids[i] = deliveries[i].ID
+	}
+	update, args, err := sqlx.In(`UPDATE notification_deliveries SET status = 'sending', updated_at = ? WHERE id IN (?)`, now, ids)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(update), args...); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for i := range deliveries {
+		deliveries[i].Status = entity.NotificationDeliverySending
+		deliveries[i].UpdatedAt = &now
+	}
+	return deliveries, nil
+}
+
+func (r *notificationDeliveryMySQLRepository) UpdateDelivery(ctx context.Context, d *entity.NotificationDelivery) error {
+	query := `UPDATE notification_deliveries SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?,
+			  sent_at = ?, updated_at = NOW() WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.SentAt, d.ID)
+	return err
+}
+
+func (r *notificationDeliveryMySQLRepository) FindByNotificationID(ctx context.Context, notificationID string) ([]entity.NotificationDelivery, error) {
+	var deliveries []entity.NotificationDelivery
+	err := r.db.SelectContext(ctx, &deliveries, notificationDeliverySelect+` WHERE notification_id = ? ORDER BY channel`, notificationID)
+	return deliveries, err
+}
+
+func (r *notificationDeliveryMySQLRepository) MarkDelivered(ctx context.Context, notificationID, channel string, at time.Time) error {
+	// updated_at is set to itself so it keeps the last dispatch instead of the ON UPDATE default
+	query := `UPDATE notification_deliveries SET delivered_at = COALESCE(delivered_at, ?), updated_at = updated_at
+			  WHERE notification_id = ? AND channel = ?`
+	_, err := r.db.ExecContext(ctx, query, at, notificationID, channel)
+	return err
+}
+
+func (r *notificationDeliveryMySQLRepository) MarkOpened(ctx context.Context, notificationID, channel string, at time.Time) error {
+	query := `UPDATE notification_deliveries SET opened_at = COALESCE(opened_at, ?), delivered_at = COALESCE(delivered_at, ?),
+			  updated_at = updated_at
+			  WHERE notification_id = ? AND channel = ?`
+	_, err := r.db.ExecContext(ctx, query, at, at, notificationID, channel)
+	return err
+}
+
+func (r *notificationDeliveryMySQLRepository) FindChannelPreferences(ctx context.Context, userID string) ([]entity.NotificationChannelPreference, error) {
+	var prefs []entity.NotificationChannelPreference
+	query := `SELECT user_id, channel, enabled, updated_at
+			  FROM notification_channel_preferences
+			  WHERE user_id = ?`
+	err := r.db.SelectContext(ctx, &prefs, query, userID)
+	return prefs, err
+}
+
+func (r *notificationDeliveryMySQLRepository) SaveChannelPreferences(ctx context.Context, userID string, prefs []entity.NotificationChannelPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(prefs))
+	args := make([]interface{}, 0, len(prefs)*3)
+	for _, p := range prefs {
+		values = append(values, "(?, ?, ?, NOW())")
+		args = append(args, userID, p.Channel, p.Enabled)
+	}
+	query := `INSERT INTO notification_channel_preferences (user_id, channel, enabled, updated_at)
+			  VALUES ` + strings.Join(values, ", ") + `
+			  ON DUPLICATE KEY UPDATE enabled = VALUES(enabled), updated_at = NOW()`
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type paymentConfirmationMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewPaymentConfirmationMySQLRepository creates a new MySQL implementation of PaymentConfirmationRepository
+func NewPaymentConfirmationMySQLRepository(db *sqlx.DB) repository.PaymentConfirmationRepository {
+	return &paymentConfirmationMySQLRepository{db: db}
+}
+
+func (r *paymentConfirmationMySQLRepository) Create(ctx context.Context, c *entity.PaymentConfirmation) error {
+	query := `INSERT INTO payment_confirmations (id, payment_id, reason, payment_reference, proof_key,
+			  proof_file_name, proof_content_type, proof_size, paid_at, confirmed_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		c.ID, c.PaymentID, c.Reason, c.PaymentReference, c.ProofKey,
+		c.ProofFileName, c.ProofContentType, c.ProofSize, c.PaidAt, c.ConfirmedBy, c.CreatedAt)
+	return err
+}
+
+func (r *paymentConfirmationMySQLRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM payment_confirmations WHERE id = ?`, id)
+	return err
+}
+
+func (r *paymentConfirmationMySQLRepository) FindByPaymentID(ctx context.Context, paymentID string) (*entity.PaymentConfirmation, error) {
+	query := `SELECT id, payment_id, reason, payment_reference, proof_key, proof_file_name, proof_content_type,
+			  proof_size, paid_at, confirmed_by, created_at
+			  FROM payment_confirmations WHERE payment_id = ?`
+	var confirmation entity.PaymentConfirmation
+	if err := r.db.GetContext(ctx, &confirmation, query, paymentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &confirmation, nil
+}
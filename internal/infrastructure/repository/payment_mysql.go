@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
@@ -22,9 +23,9 @@ func NewPaymentMySQLRepository(db *sqlx.DB) repository.PaymentRepository {
 }
 
 const paymentColumns = `id, enrollment_id, payer_user_id, payer_name, payer_email, payer_cpf,
-	gross_amount, discount_amount, net_amount, gateway_fee, refunded_amount,
-	payment_method, gateway, gateway_payment_id, gateway_customer_id,
-	gateway_invoice_url, gateway_metadata,
+	gross_amount, discount_amount, net_amount, gateway_fee, fee_surcharge, refunded_amount,
+	payment_method, gateway, gateway_payment_id, gateway_customer_id, gateway_installment_id,
+	gateway_invoice_url, gateway_metadata, line_items,
 	installment_count, installment_of, installment_number,
 	status, coupon_id, due_date, paid_at, refunded_at, cancelled_at, expires_at,
 	created_at, updated_at`
@@ -42,13 +43,35 @@ func (r *paymentMySQLRepository) FindByID(ctx context.Context, id string) (*enti
 	return &p, nil
 }
 
+func (r *paymentMySQLRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.Payment, error) {
+	var p entity.Payment
+	query := fmt.Sprintf(`SELECT %s FROM payments WHERE id = ? FOR UPDATE`, paymentColumns)
+	err := tx.GetContext(ctx, &p, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
 func (r *paymentMySQLRepository) FindByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.Payment, error) {
 	var payments []entity.Payment
-	query := fmt.Sprintf(`SELECT %s FROM payments WHERE enrollment_id = ? ORDER BY created_at DESC`, paymentColumns)
+	// Installments of a plan are listed through their parent payment
+	query := fmt.Sprintf(`SELECT %s FROM payments WHERE enrollment_id = ? AND installment_of IS NULL
+		ORDER BY created_at DESC`, paymentColumns)
 	err := r.db.SelectContext(ctx, &payments, query, enrollmentID)
 	return payments, err
 }
 
+func (r *paymentMySQLRepository) FindInstallments(ctx context.Context, parentID string) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	query := fmt.Sprintf(`SELECT %s FROM payments WHERE installment_of = ? ORDER BY installment_number`, paymentColumns)
+	err := r.db.SelectContext(ctx, &payments, query, parentID)
+	return payments, err
+}
+
 func (r *paymentMySQLRepository) FindByGatewayPaymentID(ctx context.Context, gw, gatewayPaymentID string) (*entity.Payment, error) {
 	var p entity.Payment
 	query := fmt.Sprintf(`SELECT %s FROM payments WHERE gateway = ? AND gateway_payment_id = ?`, paymentColumns)
@@ -62,14 +85,38 @@ func (r *paymentMySQLRepository) FindByGatewayPaymentID(ctx context.Context, gw,
 	return &p, nil
 }
 
+// paymentListSchema lists the fields accepted in payment filter[...] and sort parameters
+var paymentListSchema = listSchema{
+	"status":         {expr: "status", kind: kindString, sortable: true},
+	"gateway":        {expr: "gateway", kind: kindString, sortable: true},
+	"payment_method": {expr: "payment_method", kind: kindString, sortable: true},
+	"enrollment_id":  {expr: "enrollment_id", kind: kindString},
+	"payer_user_id":  {expr: "payer_user_id", kind: kindString},
+	"payer_name":     {expr: "payer_name", kind: kindString, sortable: true},
+	"payer_email":    {expr: "payer_email", kind: kindString},
+	"coupon_id":      {expr: "coupon_id", kind: kindString},
+	"gross_amount":   {expr: "gross_amount", kind: kindNumber, sortable: true},
+	"net_amount":     {expr: "net_amount", kind: kindNumber, sortable: true},
+	"due_date":       {expr: "due_date", kind: kindTime, sortable: true},
+	"paid_at":        {expr: "paid_at", kind: kindTime, sortable: true},
+	"expires_at":     {expr: "expires_at", kind: kindTime, sortable: true},
+	"created_at":     {expr: "created_at", kind: kindTime, sortable: true},
+	"updated_at":     {expr: "updated_at", kind: kindTime, sortable: true},
+}
+
 func (r *paymentMySQLRepository) FindAll(ctx context.Context, filters repository.PaymentFilters) ([]entity.Payment, int, error) {
-	where := []string{"1=1"}
+	// Installments of a plan are listed through their parent payment
+	where := []string{"installment_of IS NULL"}
 	args := []interface{}{}
 
 	if filters.EnrollmentID != "" {
 		where = append(where, "enrollment_id = ?")
 		args = append(args, filters.EnrollmentID)
 	}
+	if filters.PayerUserID != "" {
+		where = append(where, "payer_user_id = ?")
+		args = append(args, filters.PayerUserID)
+	}
 	if filters.Gateway != "" {
 		where = append(where, "gateway = ?")
 		args = append(args, filters.Gateway)
@@ -91,6 +138,20 @@ func (r *paymentMySQLRepository) FindAll(ctx context.Context, filters repository
 		args = append(args, *filters.DateTo)
 	}
 
+	conditions, queryArgs, err := paymentListSchema.where(filters.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	where = append(where, conditions...)
+	args = append(args, queryArgs...)
+	orderBy, err := paymentListSchema.orderBy(filters.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	if orderBy == "" {
+		orderBy = "created_at DESC"
+	}
+
 	whereClause := strings.Join(where, " AND ")
 
 	var total int
@@ -109,8 +170,8 @@ func (r *paymentMySQLRepository) FindAll(ctx context.Context, filters repository
 	}
 	offset := (page - 1) * perPage
 
-	query := fmt.Sprintf(`SELECT %s FROM payments WHERE %s ORDER BY created_at DESC LIMIT ? OFFSET ?`,
-		paymentColumns, whereClause)
+	query := fmt.Sprintf(`SELECT %s FROM payments WHERE %s ORDER BY %s LIMIT ? OFFSET ?`,
+		paymentColumns, whereClause, orderBy)
 	args = append(args, perPage, offset)
 
 	var payments []entity.Payment
@@ -120,29 +181,56 @@ func (r *paymentMySQLRepository) FindAll(ctx context.Context, filters repository
 	return payments, total, nil
 }
 
+func (r *paymentMySQLRepository) FindByGatewayInstallmentID(ctx context.Context, gw, installmentID string) (*entity.Payment, error) {
+	var p entity.Payment
+	query := fmt.Sprintf(`SELECT %s FROM payments WHERE gateway = ? AND gateway_installment_id = ?`, paymentColumns)
+	err := r.db.GetContext(ctx, &p, query, gw, installmentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// FindExpiredPix matches entity.Payment.PixExpired: overdue, past the expiration, or past
+// the due date for charges without one
+func (r *paymentMySQLRepository) FindExpiredPix(ctx context.Context, now time.Time, limit int) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	query := fmt.Sprintf(`SELECT %s FROM payments
+		WHERE payment_method = 'pix' AND status IN ('pending', 'awaiting_payment', 'overdue')
+		AND (status = 'overdue' OR expires_at <= ? OR due_date < DATE(?))
+		ORDER BY created_at LIMIT ?`, paymentColumns)
+	if err := r.db.SelectContext(ctx, &payments, query, now, now, limit); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
 func (r *paymentMySQLRepository) Create(ctx context.Context, p *entity.Payment) error {
 	query := `INSERT INTO payments (
 		id, enrollment_id, payer_user_id, payer_name, payer_email, payer_cpf,
-		gross_amount, discount_amount, net_amount, gateway_fee, refunded_amount,
-		payment_method, gateway, gateway_payment_id, gateway_customer_id,
-		gateway_invoice_url, gateway_metadata,
+		gross_amount, discount_amount, net_amount, gateway_fee, fee_surcharge, refunded_amount,
+		payment_method, gateway, gateway_payment_id, gateway_customer_id, gateway_installment_id,
+		gateway_invoice_url, gateway_metadata, line_items,
 		installment_count, installment_of, installment_number,
 		status, coupon_id, due_date, paid_at, refunded_at, cancelled_at, expires_at,
 		created_at
 	) VALUES (
+		?, ?, ?, ?, ?, ?,
 		?, ?, ?, ?, ?, ?,
 		?, ?, ?, ?, ?,
-		?, ?, ?, ?,
-		?, ?,
+		?, ?, ?,
 		?, ?, ?,
 		?, ?, ?, ?, ?, ?, ?,
 		NOW()
 	)`
 	_, err := r.db.ExecContext(ctx, query,
 		p.ID, p.EnrollmentID, p.PayerUserID, p.PayerName, p.PayerEmail, p.PayerCPF,
-		p.GrossAmount, p.DiscountAmount, p.NetAmount, p.GatewayFee, p.RefundedAmount,
-		p.PaymentMethod, p.Gateway, p.GatewayPaymentID, p.GatewayCustomerID,
-		p.GatewayInvoiceURL, p.GatewayMetadata,
+		p.GrossAmount, p.DiscountAmount, p.NetAmount, p.GatewayFee, p.FeeSurcharge, p.RefundedAmount,
+		p.PaymentMethod, p.Gateway, p.GatewayPaymentID, p.GatewayCustomerID, p.GatewayInstallmentID,
+		p.GatewayInvoiceURL, p.GatewayMetadata, p.LineItems,
 		p.InstallmentCount, p.InstallmentOf, p.InstallmentNumber,
 		p.Status, p.CouponID, p.DueDate, p.PaidAt, p.RefundedAt, p.CancelledAt, p.ExpiresAt,
 	)
@@ -152,26 +240,26 @@ func (r *paymentMySQLRepository) Create(ctx context.Context, p *entity.Payment)
 func (r *paymentMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, p *entity.Payment) error {
 	query := `INSERT INTO payments (
 		id, enrollment_id, payer_user_id, payer_name, payer_email, payer_cpf,
-		gross_amount, discount_amount, net_amount, gateway_fee, refunded_amount,
-		payment_method, gateway, gateway_payment_id, gateway_customer_id,
-		gateway_invoice_url, gateway_metadata,
+		gross_amount, discount_amount, net_amount, gateway_fee, fee_surcharge, refunded_amount,
+		payment_method, gateway, gateway_payment_id, gateway_customer_id, gateway_installment_id,
+		gateway_invoice_url, gateway_metadata, line_items,
 		installment_count, installment_of, installment_number,
 		status, coupon_id, due_date, paid_at, refunded_at, cancelled_at, expires_at,
 		created_at
 	) VALUES (
+		?, ?, ?, ?, ?, ?,
 		?, ?, ?, ?, ?, ?,
 		?, ?, ?, ?, ?,
-		?, ?, ?, ?,
-		?, ?,
+		?, ?, ?,
 		?, ?, ?,
 		?, ?, ?, ?, ?, ?, ?,
 		NOW()
 	)`
 	_, err := tx.ExecContext(ctx, query,
 		p.ID, p.EnrollmentID, p.PayerUserID, p.PayerName, p.PayerEmail, p.PayerCPF,
-		p.GrossAmount, p.DiscountAmount, p.NetAmount, p.GatewayFee, p.RefundedAmount,
-		p.PaymentMethod, p.Gateway, p.GatewayPaymentID, p.GatewayCustomerID,
-		p.GatewayInvoiceURL, p.GatewayMetadata,
+		p.GrossAmount, p.DiscountAmount, p.NetAmount, p.GatewayFee, p.FeeSurcharge, p.RefundedAmount,
+		p.PaymentMethod, p.Gateway, p.GatewayPaymentID, p.GatewayCustomerID, p.GatewayInstallmentID,
+		p.GatewayInvoiceURL, p.GatewayMetadata, p.LineItems,
 		p.InstallmentCount, p.InstallmentOf, p.InstallmentNumber,
 		p.Status, p.CouponID, p.DueDate, p.PaidAt, p.RefundedAt, p.CancelledAt, p.ExpiresAt,
 	)
@@ -184,7 +272,7 @@ func (r *paymentMySQLRepository) Update(ctx context.Context, p *entity.Payment)
 		gross_amount = ?, discount_amount = ?, net_amount = ?, gateway_fee = ?, refunded_amount = ?,
 		gateway_payment_id = ?, gateway_customer_id = ?,
 		gateway_invoice_url = ?, gateway_metadata = ?,
-		status = ?, paid_at = ?, refunded_at = ?, cancelled_at = ?,
+		status = ?, due_date = ?, paid_at = ?, refunded_at = ?, cancelled_at = ?,
 		updated_at = NOW()
 		WHERE id = ?`
 	_, err := r.db.ExecContext(ctx, query,
@@ -192,7 +280,7 @@ func (r *paymentMySQLRepository) Update(ctx context.Context, p *entity.Payment)
 		p.GrossAmount, p.DiscountAmount, p.NetAmount, p.GatewayFee, p.RefundedAmount,
 		p.GatewayPaymentID, p.GatewayCustomerID,
 		p.GatewayInvoiceURL, p.GatewayMetadata,
-		p.Status, p.PaidAt, p.RefundedAt, p.CancelledAt,
+		p.Status, p.DueDate, p.PaidAt, p.RefundedAt, p.CancelledAt,
 		p.ID,
 	)
 	return err
@@ -204,7 +292,7 @@ func (r *paymentMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx,
 		gross_amount = ?, discount_amount = ?, net_amount = ?, gateway_fee = ?, refunded_amount = ?,
 		gateway_payment_id = ?, gateway_customer_id = ?,
 		gateway_invoice_url = ?, gateway_metadata = ?,
-		status = ?, paid_at = ?, refunded_at = ?, cancelled_at = ?,
+		status = ?, due_date = ?, paid_at = ?, refunded_at = ?, cancelled_at = ?,
 		updated_at = NOW()
 		WHERE id = ?`
 	_, err := tx.ExecContext(ctx, query,
@@ -212,7 +300,7 @@ func (r *paymentMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx,
 		p.GrossAmount, p.DiscountAmount, p.NetAmount, p.GatewayFee, p.RefundedAmount,
 		p.GatewayPaymentID, p.GatewayCustomerID,
 		p.GatewayInvoiceURL, p.GatewayMetadata,
-		p.Status, p.PaidAt, p.RefundedAt, p.CancelledAt,
+		p.Status, p.DueDate, p.PaidAt, p.RefundedAt, p.CancelledAt,
 		p.ID,
 	)
 	return err
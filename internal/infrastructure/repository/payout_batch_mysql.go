@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type payoutBatchMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewPayoutBatchMySQLRepository creates a new MySQL implementation of PayoutBatchRepository
+func NewPayoutBatchMySQLRepository(db *sqlx.DB) repository.PayoutBatchRepository {
+	return &payoutBatchMySQLRepository{db: db}
+}
+
+const payoutBatchSelect = `SELECT b.id, b.instructor_id, u.name as instructor_name, b.status, b.payout_date,
+			  b.total_amount, b.split_count, b.notes, b.payment_reference, b.receipt_key, b.receipt_file_name,
+			  b.receipt_content_type, b.receipt_size, b.created_by, b.paid_by, b.paid_at, b.cancelled_at,
+			  b.created_at, b.updated_at
+			  FROM payout_batches b
+			  LEFT JOIN users u ON u.id = b.instructor_id`
+
+const payoutSplitSelect = `SELECT id, enrollment_id, payment_id, gross_amount, net_amount, platform_fee,
+			  payment_fee, instructor_amount, platform_amount, instructor_id, payment_method,
+			  status, payout_batch_id, transfer_id, transfer_status, transfer_error, transferred_at,
+			  processed_at, created_at
+			  FROM revenue_splits`
+
+func (r *payoutBatchMySQLRepository) FindAll(ctx context.Context, filters entity.PayoutBatchFilters) ([]entity.PayoutBatch, error) {
+	var batches []entity.PayoutBatch
+	query := payoutBatchSelect + ` WHERE 1=1`
+	var args []interface{}
+
+	if filters.InstructorID != "" {
+		query += ` AND b.instructor_id = ?`
+		args = append(args, filters.InstructorID)
+	}
+	if filters.Status != "" {
+		query += ` AND b.status = ?`
+		args = append(args, filters.Status)
+	}
+	query += ` ORDER BY b.payout_date DESC, b.created_at DESC`
+
+	err := r.db.SelectContext(ctx, &batches, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+func (r *payoutBatchMySQLRepository) FindByID(ctx context.Context, id string) (*entity.PayoutBatch, error) {
+	var batch entity.PayoutBatch
+	err := r.db.GetContext(ctx, &batch, payoutBatchSelect+` WHERE b.id = ?`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *payoutBatchMySQLRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.PayoutBatch, error) {
+	var batch entity.PayoutBatch
+	err := tx.GetContext(ctx, &batch, payoutBatchSelect+` WHERE b.id = ? FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *payoutBatchMySQLRepository) FindSplits(ctx context.Context, batchID string) ([]entity.RevenueSplit, error) {
+	var splits []entity.RevenueSplit
+	query := payoutSplitSelect + ` WHERE payout_batch_id = ? ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &splits, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return splits, nil
+}
+
+func (r *payoutBatchMySQLRepository) FindSplitsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, batchID string) ([]entity.RevenueSplit, error) {
+	var splits []entity.RevenueSplit
+	query := payoutSplitSelect + ` WHERE payout_batch_id = ? ORDER BY created_at FOR UPDATE`
+	err := tx.SelectContext(ctx, &splits, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return splits, nil
+}
+
+func (r *payoutBatchMySQLRepository) FindPayableSplitsForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, instructorID string, createdBefore *time.Time) ([]entity.RevenueSplit, error) {
+	var splits []entity.RevenueSplit
+	query := payoutSplitSelect + ` WHERE instructor_id = ? AND status = 'pending' AND payout_batch_id IS NULL
+			  AND (transfer_status IS NULL OR transfer_status IN ('failed', 'cancelled'))`
+	args := []interface{}{instructorID}
+	if createdBefore != nil {
+		query += ` AND created_at < ?`
+		args = append(args, *createdBefore)
+	}
+	query += ` ORDER BY created_at FOR UPDATE`
+
+	err := tx.SelectContext(ctx, &splits, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return splits, nil
+}
+
+func (r *payoutBatchMySQLRepository) FindPayableBalances(ctx context.Context) ([]entity.InstructorPayableBalance, error) {
+	var balances []entity.InstructorPayableBalance
+	query := `SELECT s.instructor_id, u.name as instructor_name, COUNT(*) as split_count,
+			  COALESCE(SUM(s.instructor_amount), 0) as total_amount, MIN(s.created_at) as oldest_split_at,
+			  COALESCE(a.verification_status = 'verified', FALSE) as payout_account_verified
+			  FROM revenue_splits s
+			  LEFT JOIN users u ON u.id = s.instructor_id
+			  LEFT JOIN instructor_payout_accounts a ON a.instructor_id = s.instructor_id
+			  WHERE s.status = 'pending' AND s.payout_batch_id IS NULL AND s.instructor_id IS NOT NULL
+			  AND s.instructor_amount > 0
+			  AND (s.transfer_status IS NULL OR s.transfer_status IN ('failed', 'cancelled'))
+			  GROUP BY s.instructor_id, u.name, a.verification_status
+			  ORDER BY total_amount DESC`
+	err := r.db.SelectContext(ctx, &balances, query)
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+func (r *payoutBatchMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, batch *entity.PayoutBatch) error {
+	query := `INSERT INTO payout_batches (id, instructor_id, status, payout_date, total_amount, split_count,
+			  notes, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query,
+		batch.ID, batch.InstructorID, batch.Status, batch.PayoutDate, batch.TotalAmount, batch.SplitCount,
+		batch.Notes, batch.CreatedBy)
+	return err
+}
+
+func (r *payoutBatchMySQLRepository) AssignSplitsWithTx(ctx context.Context, tx *sqlx.Tx, batchID string, splitIDs []string) error {
+	if len(splitIDs) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(`UPDATE revenue_splits SET payout_batch_id = ? WHERE id IN (?)`, batchID, splitIDs)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), args...)
+	return err
+}
+
+func (r *payoutBatchMySQLRepository) ReleaseSplitsWithTx(ctx context.Context, tx *sqlx.Tx, batchID string, splitIDs []string) error {
+	if len(splitIDs) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(`UPDATE revenue_splits SET payout_batch_id = NULL WHERE payout_batch_id = ? AND id IN (?)`, batchID, splitIDs)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), args...)
+	return err
+}
+
+func (r *payoutBatchMySQLRepository) MarkSplitsProcessedWithTx(ctx context.Context, tx *sqlx.Tx, batchID string, processedAt time.Time) error {
+	query := `UPDATE revenue_splits SET status = 'processed', processed_at = ?
+			  WHERE payout_batch_id = ? AND status = 'pending'`
+	_, err := tx.ExecContext(ctx, query, processedAt, batchID)
+	return err
+}
+
+func (r *payoutBatchMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, batch *entity.PayoutBatch) error {
+	query := `UPDATE payout_batches
+			  SET status = ?, total_amount = ?, split_count = ?, payment_reference = ?, receipt_key = ?,
+			  receipt_file_name = ?, receipt_content_type = ?, receipt_size = ?, paid_by = ?, paid_at = ?,
+			  cancelled_at = ?, updated_at = NOW()
+			  WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query,
+		batch.Status, batch.TotalAmount, batch.SplitCount, batch.PaymentReference, batch.ReceiptKey,
+		batch.ReceiptFileName, batch.ReceiptContentType, batch.ReceiptSize, batch.PaidBy, batch.PaidAt,
+		batch.CancelledAt, batch.ID)
+	return err
+}
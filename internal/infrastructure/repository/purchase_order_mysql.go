@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type purchaseOrderMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewPurchaseOrderMySQLRepository creates a new MySQL implementation of PurchaseOrderRepository
+func NewPurchaseOrderMySQLRepository(db *sqlx.DB) repository.PurchaseOrderRepository {
+	return &purchaseOrderMySQLRepository{db: db}
+}
+
+const purchaseOrderSelect = `SELECT po.id, po.number, po.supplier_id, po.contrato_id, po.status, po.description,
+			  po.total_amount, po.expected_delivery_date, po.created_by, po.approved_by, po.approved_at,
+			  po.delivered_at, po.invoice_number, po.invoiced_at, po.cancelled_at, po.cancel_reason,
+			  po.created_at, po.updated_at,
+			  s.name AS supplier_name, c.nome AS contrato_nome
+			  FROM purchase_orders po
+			  INNER JOIN suppliers s ON s.id = po.supplier_id
+			  INNER JOIN contratos c ON c.id = po.contrato_id`
+
+func (r *purchaseOrderMySQLRepository) FindAll(ctx context.Context, filters entity.PurchaseOrderFilters) ([]entity.PurchaseOrder, error) {
+	query := purchaseOrderSelect + ` WHERE 1=1`
+	args := []interface{}{}
+
+	if filters.Status != "" {
+		query += " AND po.status = ?"
+		args = append(args, filters.Status)
+	}
+	if filters.SupplierID != "" {
+		query += " AND po.supplier_id = ?"
+		args = append(args, filters.SupplierID)
+	}
+	if filters.ContratoID != "" {
+		query += " AND po.contrato_id = ?"
+		args = append(args, filters.ContratoID)
+	}
+	query += " ORDER BY po.created_at DESC"
+
+	var orders []entity.PurchaseOrder
+	if err := r.db.SelectContext(ctx, &orders, query, args...); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *purchaseOrderMySQLRepository) FindByID(ctx context.Context, id string) (*entity.PurchaseOrder, error) {
+	var order entity.PurchaseOrder
+	query := purchaseOrderSelect + ` WHERE po.id = ?`
+	err := r.db.GetContext(ctx, &order, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *purchaseOrderMySQLRepository) FindItems(ctx context.Context, orderID string) ([]entity.PurchaseOrderItem, error) {
+	var items []entity.PurchaseOrderItem
+	query := `SELECT id, purchase_order_id, description, quantity, unit, unit_price, total_price, position
+			  FROM purchase_order_items
+			  WHERE purchase_order_id = ?
+			  ORDER BY position`
+	if err := r.db.SelectContext(ctx, &items, query, orderID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *purchaseOrderMySQLRepository) NextNumberWithTx(ctx context.Context, tx *sqlx.Tx, year int) (string, error) {
+	prefix := fmt.Sprintf("PO-%d-", year)
+	var last sql.NullInt64
+	query := `SELECT MAX(CAST(SUBSTRING(number, ?) AS UNSIGNED)) FROM purchase_orders WHERE number LIKE ? FOR UPDATE`
+	if err := tx.GetContext(ctx, &last, query, len(prefix)+1, prefix+"%"); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%05d", prefix, last.Int64+1), nil
+}
+
+func (r *purchaseOrderMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, o *entity.PurchaseOrder) error {
+	query := `INSERT INTO purchase_orders (id, number, supplier_id, contrato_id, status, description,
+			  total_amount, expected_delivery_date, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query,
+		o.ID, o.Number, o.SupplierID, o.ContratoID, o.Status, o.Description,
+		o.TotalAmount, o.ExpectedDeliveryDate, o.CreatedBy)
+	return err
+}
+
+func (r *purchaseOrderMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, o *entity.PurchaseOrder) (bool, error) {
+	query := `UPDATE purchase_orders SET
+			  description = ?, total_amount = ?, expected_delivery_date = ?, updated_at = NOW()
+			  WHERE id = ? AND status = 'draft'`
+	result, err := tx.ExecContext(ctx, query, o.Description, o.TotalAmount, o.ExpectedDeliveryDate, o.ID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (r *purchaseOrderMySQLRepository) UpdateStatus(ctx context.Context, o *entity.PurchaseOrder, from string) (bool, error) {
+	query := `UPDATE purchase_orders SET
+			  status = ?, approved_by = ?, approved_at = ?, delivered_at = ?, invoice_number = ?, invoiced_at = ?,
+			  cancelled_at = ?, cancel_reason = ?, updated_at = NOW()
+			  WHERE id = ? AND status = ? AND total_amount = ?`
+	result, err := r.db.ExecContext(ctx, query,
+		o.Status, o.ApprovedBy, o.ApprovedAt, o.DeliveredAt, o.InvoiceNumber, o.InvoicedAt,
+		o.CancelledAt, o.CancelReason,
+		o.ID, from, o.TotalAmount)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (r *purchaseOrderMySQLRepository) ReplaceItemsWithTx(ctx context.Context, tx *sqlx.Tx, orderID string, items []entity.PurchaseOrderItem) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM purchase_order_items WHERE purchase_order_id = ?`, orderID); err != nil {
+		return err
+	}
+
+	insert := `INSERT INTO purchase_order_items (id, purchase_order_id, description, quantity, unit,
+			  unit_price, total_price, position)`
+	return insertBatch(ctx, tx, insert, "(?, ?, ?, ?, ?, ?, ?, ?)", len(items), func(i int) []interface{} {
+		item := items[i]
+		return []interface{}{item.ID, orderID, item.Description, item.Quantity, item.Unit,
+			item.UnitPrice, item.TotalPrice, item.Position}
+	})
+}
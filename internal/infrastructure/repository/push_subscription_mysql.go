@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type pushSubscriptionMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewPushSubscriptionMySQLRepository creates a new MySQL implementation of PushSubscriptionRepository
+func NewPushSubscriptionMySQLRepository(db *sqlx.DB) repository.PushSubscriptionRepository {
+	return &pushSubscriptionMySQLRepository{db: db}
+}
+
+// endpointHash keys subscriptions by endpoint, which is too long for a unique index
+func endpointHash(endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *pushSubscriptionMySQLRepository) Save(ctx context.Context, sub *entity.PushSubscription) error {
+	// A browser that subscribes again, possibly logged in as someone else, moves its endpoint
+	query := `INSERT INTO push_subscriptions (id, user_id, endpoint, endpoint_hash, p256dh, auth, user_agent, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			  ON DUPLICATE KEY UPDATE user_id = VALUES(user_id), p256dh = VALUES(p256dh), auth = VALUES(auth),
+			  user_agent = VALUES(user_agent)`
+	_, err := r.db.ExecContext(ctx, query, sub.ID, sub.UserID, sub.Endpoint, endpointHash(sub.Endpoint),
+		sub.P256dh, sub.Auth, sub.UserAgent, sub.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return r.db.GetContext(ctx, &sub.ID, `SELECT id FROM push_subscriptions WHERE endpoint_hash = ?`, endpointHash(sub.Endpoint))
+}
+
+func (r *pushSubscriptionMySQLRepository) FindByUserID(ctx context.Context, userID string) ([]entity.PushSubscription, error) {
+	var subs []entity.PushSubscription
+	query := `SELECT id, user_id, endpoint, p256dh, auth, user_agent, created_at
+			  FROM push_subscriptions WHERE user_id = ? ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &subs, query, userID)
+	return subs, err
+}
+
+func (r *pushSubscriptionMySQLRepository) Delete(ctx context.Context, userID, id string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM push_subscriptions WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *pushSubscriptionMySQLRepository) DeleteByEndpoint(ctx context.Context, endpoint string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM push_subscriptions WHERE endpoint_hash = ?`, endpointHash(endpoint))
+	return err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type refreshTokenMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewRefreshTokenMySQLRepository creates a new MySQL implementation of RefreshTokenRepository
+func NewRefreshTokenMySQLRepository(db *sqlx.DB) repository.RefreshTokenRepository {
+	return &refreshTokenMySQLRepository{db: db}
+}
+
+func (r *refreshTokenMySQLRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO refresh_tokens
+			  (id, user_id, family_id, token_hash, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)`,
+		token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+func (r *refreshTokenMySQLRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.db.GetContext(ctx, &token, `SELECT id, user_id, family_id, token_hash, expires_at, created_at,
+			  revoked_at, replaced_by
+			  FROM refresh_tokens WHERE token_hash = ?`, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenMySQLRepository) Revoke(ctx context.Context, id string, replacedBy *string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by = ?
+			  WHERE id = ? AND revoked_at IS NULL`, replacedBy, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *refreshTokenMySQLRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = NOW()
+			  WHERE family_id = ? AND revoked_at IS NULL`, familyID)
+	return err
+}
+
+func (r *refreshTokenMySQLRepository) RevokeByUser(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE refresh_tokens SET revoked_at = NOW()
+			  WHERE user_id = ? AND revoked_at IS NULL`, userID)
+	return err
+}
+
+func (r *refreshTokenMySQLRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// versionedTables maps the tables that can back ETags to the expression giving the last
+// modification of a row
+var versionedTables = map[string]string{
+	"audits":        "COALESCE(updated_at, created_at)",
+	"audit_items":   "created_at",
+	"tasks":         "COALESCE(updated_at, created_at)",
+	"inspections":   "COALESCE(updated_at, created_at)",
+	"payments":      "COALESCE(updated_at, created_at)",
+	"enrollments":   "COALESCE(updated_at, created_at)",
+	"notifications": "GREATEST(created_at, COALESCE(read_at, created_at))",
+}
+
+type resourceVersionMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewResourceVersionMySQLRepository creates a new MySQL implementation of ResourceVersionRepository
+func NewResourceVersionMySQLRepository(db *sqlx.DB) repository.ResourceVersionRepository {
+	return &resourceVersionMySQLRepository{db: db}
+}
+
+func (r *resourceVersionMySQLRepository) TableVersion(ctx context.Context, table string) (*entity.ResourceVersion, error) {
+	modified, ok := versionedTables[table]
+	if !ok {
+		return nil, fmt.Errorf("table %s is not versioned", table)
+	}
+
+	query := fmt.Sprintf(`SELECT COUNT(*) AS row_count, MAX(%s) AS last_modified, NOW() AS checked_at FROM %s`,
+		modified, table)
+	var version entity.ResourceVersion
+	if err := r.db.GetContext(ctx, &version, query); err != nil {
+		return nil, err
+	}
+	version.Table = table
+	return &version, nil
+}
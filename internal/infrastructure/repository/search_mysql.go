@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// searchTarget describes how a search type is queried. Columns must match the FULLTEXT
+// index of the table (migration 055) in the same order.
+type searchTarget struct {
+	table    string
+	title    string
+	subtitle string
+	columns  []string
+	where    string
+}
+
+var searchTargets = map[string]searchTarget{
+	entity.SearchTypeContrato: {table: "contratos", title: "nome", subtitle: "cidade", columns: []string{"nome", "descricao", "cidade"}, where: "deleted_at IS NULL"},
+	entity.SearchTypeSupplier: {table: "suppliers", title: "name", subtitle: "category", columns: []string{"name", "category", "notes"}, where: "is_active = 1"},
+	entity.SearchTypeTask:     {table: "tasks", title: "title", subtitle: "status", columns: []string{"title", "description"}, where: "1=1"},
+	entity.SearchTypeGestor:   {table: "gestores", title: "nome", subtitle: "email", columns: []string{"nome", "email"}, where: "deleted_at IS NULL"},
+}
+
+// minFullTextTerm is InnoDB's default innodb_ft_min_token_size: shorter words are not
+// indexed, so searches with them fall back to LIKE
+const minFullTextTerm = 3
+
+// errNoFullTextIndex is MySQL's ER_FT_MATCHING_KEY_NOT_FOUND
+const errNoFullTextIndex = 1191
+
+type searchMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewSearchMySQLRepository creates a new MySQL implementation of SearchRepository
+func NewSearchMySQLRepository(db *sqlx.DB) repository.SearchRepository {
+	return &searchMySQLRepository{db: db}
+}
+
+func (r *searchMySQLRepository) Search(ctx context.Context, searchType string, terms []string, limit int) ([]entity.SearchResult, error) {
+	target, ok := searchTargets[searchType]
+	if !ok {
+		return nil, fmt.Errorf("unknown search type %q", searchType)
+	}
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	if query, ok := fullTextQuery(terms); ok {
+		results, err := r.searchFullText(ctx, searchType, target, query, limit)
+		var mysqlErr *mysql.MySQLError
+		if !errors.As(err, &mysqlErr) || mysqlErr.Number != errNoFullTextIndex {
+			return results, err
+		}
+	}
+	return r.searchLike(ctx, searchType, target, terms, limit)
+}
+
+func (r *searchMySQLRepository) searchFullText(ctx context.Context, searchType string, t searchTarget, query string, limit int) ([]entity.SearchResult, error) {
+	match := "MATCH(" + strings.Join(t.columns, ", ") + ") AGAINST (? IN BOOLEAN MODE)"
+	var results []entity.SearchResult
+	err := r.db.SelectContext(ctx, &results,
+		`SELECT ? AS type, id, `+t.title+` AS title, `+t.subtitle+` AS subtitle, `+match+` AS score
+		 FROM `+t.table+`
+		 WHERE `+t.where+` AND `+match+`
+		 ORDER BY score DESC, title
+		 LIMIT ?`,
+		searchType, query, query, limit)
+	return results, err
+}
+
+// searchLike matches every term against any of the columns, ranking titles equal to the
+// search above titles starting with it
+func (r *searchMySQLRepository) searchLike(ctx context.Context, searchType string, t searchTarget, terms []string, limit int) ([]entity.SearchResult, error) {
+	escape := strings.NewReplacer("%", `\%`, "_", `\_`)
+	phrase := escape.Replace(strings.Join(terms, " "))
+	args := []interface{}{searchType, strings.Join(terms, " "), phrase + "%"}
+
+	conditions := []string{t.where}
+	for _, term := range terms {
+		pattern := "%" + escape.Replace(term) + "%"
+		match := make([]string, len(t.columns))
+		for i, col := range t.columns {
+			match[i] = col + " LIKE ?"
+			args = append(args, pattern)
+		}
+		conditions = append(conditions, "("+strings.Join(match, " OR ")+")")
+	}
+
+	var results []entity.SearchResult
+	err := r.db.SelectContext(ctx, &results,
+		`SELECT ? AS type, id, `+t.title+` AS title, `+t.subtitle+` AS subtitle,
+		 CASE WHEN `+t.title+` = ? THEN 3 WHEN `+t.title+` LIKE ? THEN 2 ELSE 1 END AS score
+		 FROM `+t.table+`
+		 WHERE `+strings.Join(conditions, " AND ")+`
+		 ORDER BY score DESC, title
+		 LIMIT ?`,
+		append(args, limit)...)
+	return results, err
+}
+
+// fullTextQuery builds a boolean mode query requiring every term as a word prefix. It
+// reports false when a term is too short to be in the FULLTEXT index.
+func fullTextQuery(terms []string) (string, bool) {
+	operators := strings.NewReplacer("+", "", "-", "", "<", "", ">", "", "(", "", ")", "", "~", "", "*", "", `"`, "", "@", "")
+	words := make([]string, 0, len(terms))
+	for _, term := range terms {
+		term = operators.Replace(term)
+		if len([]rune(term)) < minFullTextTerm {
+			return "", false
+		}
+		words = append(words, "+"+term+"*")
+	}
+	return strings.Join(words, " "), true
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -197,3 +198,179 @@ func (r *SettingMySQLRepository) GetCategories(ctx context.Context) ([]string, e
 
 	return categories, nil
 }
+
+// ApplyChanges updates the settings or their overrides and records each change in
+// setting_changes within one transaction, reading the previous value under a row lock
+func (r *SettingMySQLRepository) ApplyChanges(ctx context.Context, changes []*entity.SettingChange) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, ch := range changes {
+		if ch.Scope == "" {
+			ch.Scope = entity.SettingScopeGlobal
+		}
+		if ch.Scope == entity.SettingScopeGlobal {
+			err = applyGlobalChange(ctx, tx, ch)
+		} else {
+			err = applyOverrideChange(ctx, tx, ch)
+		}
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO setting_changes (id, setting_key, scope, scope_id, old_value, new_value, source, changed_by,
+			                             rollback_of, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())
+		`
+		if _, err := tx.ExecContext(ctx, query,
+			ch.ID, ch.SettingKey, ch.Scope, ch.ScopeID, ch.OldValue, ch.NewValue, ch.Source, ch.ChangedBy,
+			ch.RollbackOf); err != nil {
+			return fmt.Errorf("failed to record change of setting %s: %w", ch.SettingKey, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func applyGlobalChange(ctx context.Context, tx *sqlx.Tx, ch *entity.SettingChange) error {
+	var old sql.NullString
+	err := tx.GetContext(ctx, &old, `SELECT setting_value FROM settings WHERE setting_key = ? FOR UPDATE`, ch.SettingKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("setting not found: %s", ch.SettingKey)
+		}
+		return fmt.Errorf("failed to lock setting %s: %w", ch.SettingKey, err)
+	}
+	ch.OldValue = nil
+	if old.Valid {
+		ch.OldValue = &old.String
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE settings SET setting_value = ?, updated_at = NOW() WHERE setting_key = ?`,
+		ch.NewValue, ch.SettingKey); err != nil {
+		return fmt.Errorf("failed to update setting %s: %w", ch.SettingKey, err)
+	}
+	return nil
+}
+
+func applyOverrideChange(ctx context.Context, tx *sqlx.Tx, ch *entity.SettingChange) error {
+	if ch.ScopeID == nil {
+		return fmt.Errorf("setting override of %s has no scope id", ch.SettingKey)
+	}
+
+	var old sql.NullString
+	err := tx.GetContext(ctx, &old, `
+		SELECT setting_value FROM setting_overrides
+		WHERE setting_key = ? AND scope = ? AND scope_id = ?
+		FOR UPDATE
+	`, ch.SettingKey, ch.Scope, *ch.ScopeID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to lock override of setting %s: %w", ch.SettingKey, err)
+	}
+	ch.OldValue = nil
+	if old.Valid {
+		ch.OldValue = &old.String
+	}
+
+	if ch.NewValue == nil {
+		_, err = tx.ExecContext(ctx,
+			`DELETE FROM setting_overrides WHERE setting_key = ? AND scope = ? AND scope_id = ?`,
+			ch.SettingKey, ch.Scope, *ch.ScopeID)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO setting_overrides (id, setting_key, scope, scope_id, setting_value, created_at)
+			VALUES (?, ?, ?, ?, ?, NOW())
+			ON DUPLICATE KEY UPDATE setting_value = VALUES(setting_value), updated_at = NOW()
+		`, uuid.New().String(), ch.SettingKey, ch.Scope, *ch.ScopeID, *ch.NewValue)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update override of setting %s: %w", ch.SettingKey, err)
+	}
+	return nil
+}
+
+// FindChanges returns the recorded changes of a setting, newest first
+func (r *SettingMySQLRepository) FindChanges(ctx context.Context, key string, limit int) ([]entity.SettingChange, error) {
+	query := `
+		SELECT id, setting_key, scope, scope_id, old_value, new_value, source, changed_by, rollback_of, created_at
+		FROM setting_changes
+		WHERE setting_key = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	var changes []entity.SettingChange
+	err := r.db.SelectContext(ctx, &changes, query, key, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// FindChangeByID returns a recorded setting change by its ID
+func (r *SettingMySQLRepository) FindChangeByID(ctx context.Context, id string) (*entity.SettingChange, error) {
+	query := `
+		SELECT id, setting_key, scope, scope_id, old_value, new_value, source, changed_by, rollback_of, created_at
+		FROM setting_changes
+		WHERE id = ?
+	`
+
+	var change entity.SettingChange
+	err := r.db.GetContext(ctx, &change, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get setting change: %w", err)
+	}
+
+	return &change, nil
+}
+
+// FindOverrides returns the overrides of a setting, organizations first
+func (r *SettingMySQLRepository) FindOverrides(ctx context.Context, key string) ([]entity.SettingOverride, error) {
+	query := `
+		SELECT id, setting_key, scope, scope_id, setting_value, created_at, updated_at
+		FROM setting_overrides
+		WHERE setting_key = ?
+		ORDER BY scope DESC, scope_id
+	`
+
+	var overrides []entity.SettingOverride
+	err := r.db.SelectContext(ctx, &overrides, query, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting overrides: %w", err)
+	}
+
+	return overrides, nil
+}
+
+// FindOverride returns the override of a setting for a scope
+func (r *SettingMySQLRepository) FindOverride(ctx context.Context, key string, scope entity.SettingScope, scopeID string) (*entity.SettingOverride, error) {
+	query := `
+		SELECT id, setting_key, scope, scope_id, setting_value, created_at, updated_at
+		FROM setting_overrides
+		WHERE setting_key = ? AND scope = ? AND scope_id = ?
+	`
+
+	var override entity.SettingOverride
+	err := r.db.GetContext(ctx, &override, query, key, scope, scopeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get setting override: %w", err)
+	}
+
+	return &override, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type splitAdjustmentMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewSplitAdjustmentMySQLRepository creates a new MySQL implementation of SplitAdjustmentRepository
+func NewSplitAdjustmentMySQLRepository(db *sqlx.DB) repository.SplitAdjustmentRepository {
+	return &splitAdjustmentMySQLRepository{db: db}
+}
+
+const splitAdjustmentSelect = `SELECT id, split_id, dispute_id, status, reason, previous_instructor_amount,
+			  instructor_amount, requested_by, decided_by, decision_notes, decided_at, created_at
+			  FROM revenue_split_adjustments`
+
+func (r *splitAdjustmentMySQLRepository) FindAll(ctx context.Context, filters entity.SplitAdjustmentFilters) ([]entity.SplitAdjustment, error) {
+	query := splitAdjustmentSelect + ` WHERE 1=1`
+	args := []interface{}{}
+
+	if filters.SplitID != "" {
+		query += " AND split_id = ?"
+		args = append(args, filters.SplitID)
+	}
+	if filters.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filters.Status)
+	}
+	query += " ORDER BY created_at DESC"
+
+	var adjustments []entity.SplitAdjustment
+	if err := r.db.SelectContext(ctx, &adjustments, query, args...); err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+func (r *splitAdjustmentMySQLRepository) FindByID(ctx context.Context, id string) (*entity.SplitAdjustment, error) {
+	var adjustment entity.SplitAdjustment
+	err := r.db.GetContext(ctx, &adjustment, splitAdjustmentSelect+` WHERE id = ?`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+func (r *splitAdjustmentMySQLRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.SplitAdjustment, error) {
+	var adjustment entity.SplitAdjustment
+	err := tx.GetContext(ctx, &adjustment, splitAdjustmentSelect+` WHERE id = ? FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+func (r *splitAdjustmentMySQLRepository) FindPendingBySplitWithTx(ctx context.Context, tx *sqlx.Tx, splitID string) (*entity.SplitAdjustment, error) {
+	var adjustment entity.SplitAdjustment
+	query := splitAdjustmentSelect + ` WHERE split_id = ? AND status = 'pending' LIMIT 1`
+	err := tx.GetContext(ctx, &adjustment, query, splitID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+func (r *splitAdjustmentMySQLRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, a *entity.SplitAdjustment) error {
+	query := `INSERT INTO revenue_split_adjustments (id, split_id, dispute_id, status, reason,
+			  previous_instructor_amount, instructor_amount, requested_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := tx.ExecContext(ctx, query,
+		a.ID, a.SplitID, a.DisputeID, a.Status, a.Reason,
+		a.PreviousInstructorAmount, a.InstructorAmount, a.RequestedBy)
+	return err
+}
+
+func (r *splitAdjustmentMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, a *entity.SplitAdjustment) error {
+	query := `UPDATE revenue_split_adjustments SET status = ?, decided_by = ?, decision_notes = ?, decided_at = ?
+			  WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query, a.Status, a.DecidedBy, a.DecisionNotes, a.DecidedAt, a.ID)
+	return err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type splitDisputeMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewSplitDisputeMySQLRepository creates a new MySQL implementation of SplitDisputeRepository
+func NewSplitDisputeMySQLRepository(db *sqlx.DB) repository.SplitDisputeRepository {
+	return &splitDisputeMySQLRepository{db: db}
+}
+
+const splitDisputeSelect = `SELECT id, split_id, instructor_id, status, reason, expected_amount,
+			  resolution_notes, resolved_by, resolved_at, adjustment_id, created_at, updated_at
+			  FROM revenue_split_disputes`
+
+func (r *splitDisputeMySQLRepository) FindAll(ctx context.Context, filters entity.SplitDisputeFilters) ([]entity.SplitDispute, error) {
+	query := splitDisputeSelect + ` WHERE 1=1`
+	args := []interface{}{}
+
+	if filters.SplitID != "" {
+		query += " AND split_id = ?"
+		args = append(args, filters.SplitID)
+	}
+	if filters.InstructorID != "" {
+		query += " AND instructor_id = ?"
+		args = append(args, filters.InstructorID)
+	}
+	if filters.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filters.Status)
+	}
+	query += " ORDER BY created_at DESC"
+
+	var disputes []entity.SplitDispute
+	if err := r.db.SelectContext(ctx, &disputes, query, args...); err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
+
+func (r *splitDisputeMySQLRepository) FindByID(ctx context.Context, id string) (*entity.SplitDispute, error) {
+	var dispute entity.SplitDispute
+	err := r.db.GetContext(ctx, &dispute, splitDisputeSelect+` WHERE id = ?`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *splitDisputeMySQLRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.SplitDispute, error) {
+	var dispute entity.SplitDispute
+	err := tx.GetContext(ctx, &dispute, splitDisputeSelect+` WHERE id = ? FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *splitDisputeMySQLRepository) FindActiveBySplit(ctx context.Context, splitID string) (*entity.SplitDispute, error) {
+	var dispute entity.SplitDispute
+	query := splitDisputeSelect + ` WHERE split_id = ? AND status IN ('open', 'under_review') LIMIT 1`
+	err := r.db.GetContext(ctx, &dispute, query, splitID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *splitDisputeMySQLRepository) Create(ctx context.Context, d *entity.SplitDispute) error {
+	query := `INSERT INTO revenue_split_disputes (id, split_id, instructor_id, status, reason, expected_amount, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query, d.ID, d.SplitID, d.InstructorID, d.Status, d.Reason, d.ExpectedAmount)
+	return err
+}
+
+func (r *splitDisputeMySQLRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, d *entity.SplitDispute) error {
+	query := `UPDATE revenue_split_disputes SET status = ?, resolution_notes = ?, resolved_by = ?, resolved_at = ?,
+			  adjustment_id = ?, updated_at = NOW()
+			  WHERE id = ?`
+	_, err := tx.ExecContext(ctx, query, d.Status, d.ResolutionNotes, d.ResolvedBy, d.ResolvedAt, d.AdjustmentID, d.ID)
+	return err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type splitRuleMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewSplitRuleMySQLRepository creates a new MySQL implementation of SplitRuleRepository
+func NewSplitRuleMySQLRepository(db *sqlx.DB) repository.SplitRuleRepository {
+	return &splitRuleMySQLRepository{db: db}
+}
+
+const splitRuleSelect = `SELECT id, scope, scope_id, position, party_type, party_id, label, percent,
+			  fixed_amount, created_at
+			  FROM revenue_split_rules`
+
+func (r *splitRuleMySQLRepository) FindByScope(ctx context.Context, scope, scopeID string) ([]entity.SplitRule, error) {
+	var rules []entity.SplitRule
+	query := splitRuleSelect + ` WHERE scope = ? AND scope_id = ? ORDER BY position`
+	err := r.db.SelectContext(ctx, &rules, query, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *splitRuleMySQLRepository) FindOverrides(ctx context.Context) ([]entity.SplitRule, error) {
+	var rules []entity.SplitRule
+	query := splitRuleSelect + ` WHERE scope <> 'global' ORDER BY scope, scope_id, position`
+	err := r.db.SelectContext(ctx, &rules, query)
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *splitRuleMySQLRepository) ReplaceScopeWithTx(ctx context.Context, tx *sqlx.Tx, scope, scopeID string, rules []entity.SplitRule) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM revenue_split_rules WHERE scope = ? AND scope_id = ?`, scope, scopeID); err != nil {
+		return err
+	}
+
+	insert := `INSERT INTO revenue_split_rules (id, scope, scope_id, position, party_type, party_id, label,
+			  percent, fixed_amount, created_at)`
+	return insertBatch(ctx, tx, insert, "(?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())", len(rules), func(i int) []interface{} {
+		rule := rules[i]
+		return []interface{}{rule.ID, scope, scopeID, rule.Position, rule.PartyType, rule.PartyID, rule.Label,
+			rule.Percent, rule.FixedAmount}
+	})
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type supplierContractMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewSupplierContractMySQLRepository creates a new MySQL implementation of SupplierContractRepository. reader serves
+// the report queries and may be a read replica; writes go to db.
+func NewSupplierContractMySQLRepository(db, reader *sqlx.DB) repository.SupplierContractRepository {
+	return &supplierContractMySQLRepository{db: db, reader: reader}
+}
+
+const supplierContractSelect = `SELECT sc.id, sc.supplier_id, sc.contrato_id, sc.service_category, sc.monthly_budget,
+			  sc.is_active, sc.notes, sc.created_at, sc.updated_at,
+			  s.name AS supplier_name, c.nome AS contrato_nome
+			  FROM supplier_contracts sc
+			  INNER JOIN suppliers s ON s.id = sc.supplier_id
+			  INNER JOIN contratos c ON c.id = sc.contrato_id`
+
+func (r *supplierContractMySQLRepository) FindByID(ctx context.Context, id string) (*entity.SupplierContract, error) {
+	var link entity.SupplierContract
+	query := supplierContractSelect + ` WHERE sc.id = ?`
+	err := r.db.GetContext(ctx, &link, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *supplierContractMySQLRepository) FindBySupplierID(ctx context.Context, supplierID string) ([]entity.SupplierContract, error) {
+	var links []entity.SupplierContract
+	query := supplierContractSelect + ` WHERE sc.supplier_id = ? ORDER BY c.nome, sc.service_category`
+	err := r.db.SelectContext(ctx, &links, query, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *supplierContractMySQLRepository) FindByContratoID(ctx context.Context, contratoID string) ([]entity.SupplierContract, error) {
+	var links []entity.SupplierContract
+	query := supplierContractSelect + ` WHERE sc.contrato_id = ? ORDER BY sc.service_category, s.name`
+	err := r.db.SelectContext(ctx, &links, query, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *supplierContractMySQLRepository) FindLink(ctx context.Context, supplierID, contratoID, serviceCategory string) (*entity.SupplierContract, error) {
+	var link entity.SupplierContract
+	query := supplierContractSelect + ` WHERE sc.supplier_id = ? AND sc.contrato_id = ? AND sc.service_category = ?`
+	err := r.db.GetContext(ctx, &link, query, supplierID, contratoID, serviceCategory)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *supplierContractMySQLRepository) Create(ctx context.Context, link *entity.SupplierContract) error {
+	query := `INSERT INTO supplier_contracts (id, supplier_id, contrato_id, service_category, monthly_budget,
+			  is_active, notes, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		link.ID, link.SupplierID, link.ContratoID, link.ServiceCategory, link.MonthlyBudget,
+		link.IsActive, link.Notes)
+	return err
+}
+
+func (r *supplierContractMySQLRepository) Update(ctx context.Context, link *entity.SupplierContract) error {
+	query := `UPDATE supplier_contracts
+			  SET service_category = ?, monthly_budget = ?, is_active = ?, notes = ?, updated_at = NOW()
+			  WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		link.ServiceCategory, link.MonthlyBudget, link.IsActive, link.Notes, link.ID)
+	return err
+}
+
+func (r *supplierContractMySQLRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM supplier_contracts WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *supplierContractMySQLRepository) FindSpendByLinkID(ctx context.Context, linkID string) ([]entity.SupplierSpend, error) {
+	var entries []entity.SupplierSpend
+	query := `SELECT id, supplier_contract_id, supplier_id, contrato_id, period, amount, description,
+			  invoice_number, created_by, created_at
+			  FROM supplier_spend
+			  WHERE supplier_contract_id = ?
+			  ORDER BY period DESC, created_at DESC`
+	err := r.db.SelectContext(ctx, &entries, query, linkID)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *supplierContractMySQLRepository) CreateSpend(ctx context.Context, spend *entity.SupplierSpend) error {
+	query := `INSERT INTO supplier_spend (id, supplier_contract_id, supplier_id, contrato_id, period, amount,
+			  description, invoice_number, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		spend.ID, spend.SupplierContractID, spend.SupplierID, spend.ContratoID, spend.Period, spend.Amount,
+		spend.Description, spend.InvoiceNumber, spend.CreatedBy)
+	return err
+}
+
+func (r *supplierContractMySQLRepository) SumSpend(ctx context.Context, groupBy string, filters entity.SpendReportFilters) ([]entity.SpendAggregate, error) {
+	var query string
+	if groupBy == entity.SpendGroupBySupplier {
+		query = `SELECT sp.supplier_id AS group_id, s.name AS group_name, sp.period,
+			  SUM(sp.amount) AS total_amount, COUNT(*) AS entry_count
+			  FROM supplier_spend sp
+			  INNER JOIN suppliers s ON s.id = sp.supplier_id
+			  WHERE 1=1`
+	} else {
+		query = `SELECT sp.contrato_id AS group_id, c.nome AS group_name, sp.period,
+			  SUM(sp.amount) AS total_amount, COUNT(*) AS entry_count
+			  FROM supplier_spend sp
+			  INNER JOIN contratos c ON c.id = sp.contrato_id
+			  WHERE 1=1`
+	}
+	args := []interface{}{}
+
+	if filters.From != "" {
+		query += " AND sp.period >= ?"
+		args = append(args, filters.From)
+	}
+	if filters.To != "" {
+		query += " AND sp.period <= ?"
+		args = append(args, filters.To)
+	}
+	if filters.SupplierID != "" {
+		query += " AND sp.supplier_id = ?"
+		args = append(args, filters.SupplierID)
+	}
+	if filters.ContratoID != "" {
+		query += " AND sp.contrato_id = ?"
+		args = append(args, filters.ContratoID)
+	}
+
+	query += " GROUP BY group_id, group_name, sp.period ORDER BY group_name, sp.period"
+
+	var rows []entity.SpendAggregate
+	if err := r.reader.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *supplierContractMySQLRepository) SumMonthlyBudget(ctx context.Context, groupBy string, filters entity.SpendReportFilters) (map[string]float64, error) {
+	column := "contrato_id"
+	if groupBy == entity.SpendGroupBySupplier {
+		column = "supplier_id"
+	}
+
+	query := `SELECT ` + column + ` AS group_id, SUM(monthly_budget) AS total_amount
+			  FROM supplier_contracts
+			  WHERE is_active = 1 AND monthly_budget IS NOT NULL`
+	args := []interface{}{}
+
+	if filters.SupplierID != "" {
+		query += " AND supplier_id = ?"
+		args = append(args, filters.SupplierID)
+	}
+	if filters.ContratoID != "" {
+		query += " AND contrato_id = ?"
+		args = append(args, filters.ContratoID)
+	}
+	query += " GROUP BY " + column
+
+	var rows []struct {
+		GroupID     string  `db:"group_id"`
+		TotalAmount float64 `db:"total_amount"`
+	}
+	if err := r.reader.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		result[row.GroupID] = row.TotalAmount
+	}
+	return result, nil
+}
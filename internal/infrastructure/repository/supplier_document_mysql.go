@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type supplierDocumentMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewSupplierDocumentMySQLRepository creates a new MySQL implementation of SupplierDocumentRepository
+func NewSupplierDocumentMySQLRepository(db *sqlx.DB) repository.SupplierDocumentRepository {
+	return &supplierDocumentMySQLRepository{db: db}
+}
+
+const supplierDocumentColumns = `id, supplier_id, document_type, title, number, issued_at, expires_at, notes,
+			  is_active, created_by, created_at, updated_at`
+
+func (r *supplierDocumentMySQLRepository) FindByID(ctx context.Context, id string) (*entity.SupplierDocument, error) {
+	var doc entity.SupplierDocument
+	query := `SELECT ` + supplierDocumentColumns + ` FROM supplier_documents WHERE id = ?`
+	err := r.db.GetContext(ctx, &doc, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *supplierDocumentMySQLRepository) FindBySupplierID(ctx context.Context, supplierID string, includeInactive bool) ([]entity.SupplierDocument, error) {
+	query := `SELECT ` + supplierDocumentColumns + ` FROM supplier_documents WHERE supplier_id = ?`
+	if !includeInactive {
+		query += ` AND is_active = 1`
+	}
+	query += ` ORDER BY expires_at IS NULL, expires_at, title`
+
+	var docs []entity.SupplierDocument
+	if err := r.db.SelectContext(ctx, &docs, query, supplierID); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (r *supplierDocumentMySQLRepository) Create(ctx context.Context, doc *entity.SupplierDocument) error {
+	query := `INSERT INTO supplier_documents (id, supplier_id, document_type, title, number, issued_at, expires_at,
+			  notes, is_active, created_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		doc.ID, doc.SupplierID, doc.DocumentType, doc.Title, doc.Number, doc.IssuedAt, doc.ExpiresAt,
+		doc.Notes, doc.IsActive, doc.CreatedBy, doc.CreatedAt)
+	return err
+}
+
+func (r *supplierDocumentMySQLRepository) Update(ctx context.Context, doc *entity.SupplierDocument) error {
+	query := `UPDATE supplier_documents SET title = ?, number = ?, issued_at = ?, expires_at = ?, notes = ?,
+			  is_active = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query,
+		doc.Title, doc.Number, doc.IssuedAt, doc.ExpiresAt, doc.Notes, doc.IsActive, doc.UpdatedAt, doc.ID)
+	return err
+}
+
+func (r *supplierDocumentMySQLRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM supplier_documents WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type supplierEvaluationMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewSupplierEvaluationMySQLRepository creates a new MySQL implementation of SupplierEvaluationRepository
+func NewSupplierEvaluationMySQLRepository(db *sqlx.DB) repository.SupplierEvaluationRepository {
+	return &supplierEvaluationMySQLRepository{db: db}
+}
+
+const supplierScoreAggregates = `COUNT(*) AS evaluation_count,
+			  ROUND(AVG(quality_score), 2) AS quality_score,
+			  ROUND(AVG(punctuality_score), 2) AS punctuality_score,
+			  ROUND(AVG(price_score), 2) AS price_score,
+			  ROUND(AVG(overall_score), 2) AS overall_score`
+
+func (r *supplierEvaluationMySQLRepository) FindBySupplierID(ctx context.Context, supplierID string) ([]entity.SupplierEvaluation, error) {
+	var evaluations []entity.SupplierEvaluation
+	query := `SELECT id, supplier_id, contrato_id, period, quality_score, punctuality_score, price_score,
+			  overall_score, comments, evaluated_by, created_at, updated_at
+			  FROM supplier_evaluations
+			  WHERE supplier_id = ?
+			  ORDER BY period DESC, created_at DESC`
+	err := r.db.SelectContext(ctx, &evaluations, query, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	return evaluations, nil
+}
+
+func (r *supplierEvaluationMySQLRepository) FindByPeriod(ctx context.Context, supplierID, contratoID, period string) (*entity.SupplierEvaluation, error) {
+	var evaluation entity.SupplierEvaluation
+	query := `SELECT id, supplier_id, contrato_id, period, quality_score, punctuality_score, price_score,
+			  overall_score, comments, evaluated_by, created_at, updated_at
+			  FROM supplier_evaluations
+			  WHERE supplier_id = ? AND contrato_id = ? AND period = ?`
+	err := r.db.GetContext(ctx, &evaluation, query, supplierID, contratoID, period)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &evaluation, nil
+}
+
+func (r *supplierEvaluationMySQLRepository) GetScores(ctx context.Context, supplierID string) (*entity.SupplierScores, error) {
+	var scores entity.SupplierScores
+	query := `SELECT ` + supplierScoreAggregates + `
+			  FROM supplier_evaluations
+			  WHERE supplier_id = ?`
+	if err := r.db.GetContext(ctx, &scores, query, supplierID); err != nil {
+		return nil, err
+	}
+	if scores.EvaluationCount == 0 {
+		return nil, nil
+	}
+	return &scores, nil
+}
+
+func (r *supplierEvaluationMySQLRepository) GetScoresBySupplierIDs(ctx context.Context, supplierIDs []string) (map[string]entity.SupplierScores, error) {
+	result := make(map[string]entity.SupplierScores)
+	if len(supplierIDs) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT supplier_id, `+supplierScoreAggregates+`
+			  FROM supplier_evaluations
+			  WHERE supplier_id IN (?)
+			  GROUP BY supplier_id`, supplierIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		SupplierID string `db:"supplier_id"`
+		entity.SupplierScores
+	}
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.SupplierID] = row.SupplierScores
+	}
+	return result, nil
+}
+
+func (r *supplierEvaluationMySQLRepository) GetRanking(ctx context.Context, filters entity.SupplierRankingFilters) ([]entity.SupplierRanking, error) {
+	query := `SELECT s.id AS supplier_id, s.name AS supplier_name, s.category,
+			  COUNT(*) AS evaluation_count,
+			  ROUND(AVG(e.quality_score), 2) AS quality_score,
+			  ROUND(AVG(e.punctuality_score), 2) AS punctuality_score,
+			  ROUND(AVG(e.price_score), 2) AS price_score,
+			  ROUND(AVG(e.overall_score), 2) AS overall_score
+			  FROM supplier_evaluations e
+			  INNER JOIN suppliers s ON s.id = e.supplier_id
+			  WHERE s.is_active = 1`
+	args := []interface{}{}
+
+	if filters.Category != "" {
+		query += " AND s.category = ?"
+		args = append(args, filters.Category)
+	}
+	if filters.ContratoID != "" {
+		query += " AND e.contrato_id = ?"
+		args = append(args, filters.ContratoID)
+	}
+	if filters.From != "" {
+		query += " AND e.period >= ?"
+		args = append(args, filters.From)
+	}
+	if filters.To != "" {
+		query += " AND e.period <= ?"
+		args = append(args, filters.To)
+	}
+
+	query += " GROUP BY s.id, s.name, s.category"
+	if filters.MinEvaluations > 0 {
+		query += " HAVING COUNT(*) >= ?"
+		args = append(args, filters.MinEvaluations)
+	}
+	query += " ORDER BY overall_score DESC, evaluation_count DESC, s.name"
+	if filters.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filters.Limit)
+	}
+
+	var ranking []entity.SupplierRanking
+	if err := r.db.SelectContext(ctx, &ranking, query, args...); err != nil {
+		return nil, err
+	}
+	return ranking, nil
+}
+
+func (r *supplierEvaluationMySQLRepository) Create(ctx context.Context, e *entity.SupplierEvaluation) error {
+	query := `INSERT INTO supplier_evaluations (id, supplier_id, contrato_id, period, quality_score,
+			  punctuality_score, price_score, overall_score, comments, evaluated_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query,
+		e.ID, e.SupplierID, e.ContratoID, e.Period, e.QualityScore,
+		e.PunctualityScore, e.PriceScore, e.OverallScore, e.Comments, e.EvaluatedBy)
+	return err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type systemImageMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewSystemImageMySQLRepository creates a new MySQL implementation of SystemImageRepository
+func NewSystemImageMySQLRepository(db *sqlx.DB) repository.SystemImageRepository {
+	return &systemImageMySQLRepository{db: db}
+}
+
+const systemImageSelect = `SELECT id, filename, description, created_at, updated_at FROM system_images`
+
+func (r *systemImageMySQLRepository) FindAll(ctx context.Context) ([]entity.SystemImage, error) {
+	var images []entity.SystemImage
+	if err := r.db.SelectContext(ctx, &images, systemImageSelect+` ORDER BY id`); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *systemImageMySQLRepository) FindByID(ctx context.Context, id string) (*entity.SystemImage, error) {
+	return r.findOne(ctx, systemImageSelect+` WHERE id = ?`, id)
+}
+
+func (r *systemImageMySQLRepository) FindByFilename(ctx context.Context, filename string) (*entity.SystemImage, error) {
+	return r.findOne(ctx, systemImageSelect+` WHERE filename = ?`, filename)
+}
+
+func (r *systemImageMySQLRepository) findOne(ctx context.Context, query string, arg string) (*entity.SystemImage, error) {
+	var image entity.SystemImage
+	if err := r.db.GetContext(ctx, &image, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *systemImageMySQLRepository) Create(ctx context.Context, image *entity.SystemImage) error {
+	query := `INSERT INTO system_images (id, filename, description, created_at) VALUES (?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, image.ID, image.Filename, image.Description, image.CreatedAt)
+	return err
+}
+
+func (r *systemImageMySQLRepository) Update(ctx context.Context, image *entity.SystemImage) error {
+	query := `UPDATE system_images SET filename = ?, description = ?, updated_at = NOW() WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, image.Filename, image.Description, image.ID)
+	return err
+}
+
+func (r *systemImageMySQLRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM system_images WHERE id = ?`, id)
+	return err
+}
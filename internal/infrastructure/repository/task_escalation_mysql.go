@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type taskEscalationMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaskEscalationMySQLRepository creates a new MySQL implementation of TaskEscalationRepository
+func NewTaskEscalationMySQLRepository(db *sqlx.DB) repository.TaskEscalationRepository {
+	return &taskEscalationMySQLRepository{db: db}
+}
+
+func (r *taskEscalationMySQLRepository) FindOverdue(ctx context.Context, now time.Time) ([]entity.OverdueTask, error) {
+	var tasks []entity.OverdueTask
+	query := `SELECT t.id AS task_id, t.title, t.priority, t.due_date, t.contract_id,
+			  c.nome AS contract_name, c.gestor_id, t.escalation_level
+			  FROM tasks t
+			  LEFT JOIN contratos c ON c.id = t.contract_id AND c.deleted_at IS NULL
+			  WHERE t.due_date < ?
+			  AND t.status NOT IN ('completed', 'cancelled')
+			  AND t.escalation_level < ?
+			  ORDER BY t.due_date ASC`
+	if err := r.db.SelectContext(ctx, &tasks, query, now, entity.TaskEscalationAdmin); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskEscalationMySQLRepository) MarkEscalated(ctx context.Context, taskID string, level int, at time.Time) (bool, error) {
+	// updated_at is left alone: escalating is not an edit of the task
+	query := `UPDATE tasks SET escalation_level = ?, escalated_at = ? WHERE id = ? AND escalation_level < ?`
+	result, err := r.db.ExecContext(ctx, query, level, at, taskID, level)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *taskEscalationMySQLRepository) Create(ctx context.Context, escalation *entity.TaskEscalation) error {
+	query := `INSERT INTO task_escalations (id, task_id, contract_id, level, overdue_hours, recipients, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		escalation.ID, escalation.TaskID, escalation.ContractID, escalation.Level,
+		escalation.OverdueHours, escalation.Recipients, escalation.CreatedAt)
+	return err
+}
+
+func (r *taskEscalationMySQLRepository) Report(ctx context.Context, filters entity.TaskEscalationReportFilters) ([]entity.TaskEscalationReport, error) {
+	var conditions []string
+	var args []interface{}
+	if filters.ContractID != "" {
+		conditions = append(conditions, "e.contract_id = ?")
+		args = append(args, filters.ContractID)
+	}
+	if filters.From != nil {
+		conditions = append(conditions, "e.created_at >= ?")
+		args = append(args, *filters.From)
+	}
+	if filters.To != nil {
+		conditions = append(conditions, "e.created_at < ?")
+		args = append(args, *filters.To)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var report []entity.TaskEscalationReport
+	query := `SELECT e.contract_id, MAX(c.nome) AS contract_name,
+			  COUNT(DISTINCT e.task_id) AS tasks,
+			  SUM(CASE WHEN e.level = 1 THEN 1 ELSE 0 END) AS gestor_escalations,
+			  SUM(CASE WHEN e.level = 2 THEN 1 ELSE 0 END) AS admin_escalations,
+			  COUNT(DISTINCT CASE WHEN t.status NOT IN ('completed', 'cancelled') THEN e.task_id END) AS still_open,
+			  MAX(e.created_at) AS last_escalated_at
+			  FROM task_escalations e
+			  LEFT JOIN contratos c ON c.id = e.contract_id
+			  LEFT JOIN tasks t ON t.id = e.task_id
+			  ` + where + `
+			  GROUP BY e.contract_id
+			  ORDER BY admin_escalations DESC, gestor_escalations DESC`
+	if err := r.db.SelectContext(ctx, &report, query, args...); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/jmoiron/sqlx"
 )
@@ -21,6 +22,24 @@ func NewTaskMySQLRepository(db *sqlx.DB) repository.TaskRepository {
 	return &taskMySQLRepository{db: db}
 }
 
+// taskListSchema lists the fields accepted in task filter[...] and sort parameters
+var taskListSchema = listSchema{
+	"status":       {expr: "t.status", kind: kindString, sortable: true},
+	"priority":     {expr: "t.priority", kind: kindString, sortable: true, sortExpr: taskPriorityRank},
+	"title":        {expr: "t.title", kind: kindString, sortable: true},
+	"contract_id":  {expr: "t.contract_id", kind: kindString},
+	"assigned_to":  {expr: "t.assigned_to", kind: kindString},
+	"created_by":   {expr: "t.created_by", kind: kindString},
+	"due_date":     {expr: "t.due_date", kind: kindTime, sortable: true},
+	"completed_at": {expr: "t.completed_at", kind: kindTime, sortable: true},
+	"created_at":   {expr: "t.created_at", kind: kindTime, sortable: true},
+	"updated_at":   {expr: "t.updated_at", kind: kindTime, sortable: true},
+}
+
+// taskPriorityRank sorts priorities by rank rather than alphabetically, so -priority puts
+// urgent tasks first
+const taskPriorityRank = "CASE t.priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'urgent' THEN 4 END"
+
 func (r *taskMySQLRepository) FindAll(ctx context.Context, filter *entity.TaskFilter) ([]entity.Task, error) {
 	var tasks []entity.Task
 
@@ -28,7 +47,7 @@ func (r *taskMySQLRepository) FindAll(ctx context.Context, filter *entity.TaskFi
 			  t.contract_id, c.nome as contract_name,
 			  t.assigned_to, ga.nome as assigned_to_name,
 			  t.created_by, gc.nome as created_by_name,
-			  t.completed_at, t.created_at, t.updated_at
+			  t.completed_at, t.escalation_level, t.escalated_at, t.created_at, t.updated_at
 			  FROM tasks t
 			  LEFT JOIN contratos c ON c.id = t.contract_id
 			  LEFT JOIN gestores ga ON ga.id = t.assigned_to
@@ -60,13 +79,31 @@ func (r *taskMySQLRepository) FindAll(ctx context.Context, filter *entity.TaskFi
 		}
 	}
 
+	var lq listquery.Query
+	if filter != nil {
+		lq = filter.Query
+	}
+	queryConditions, queryArgs, err := taskListSchema.where(lq)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append(conditions, queryConditions...)
+	args = append(args, queryArgs...)
+	orderBy, err := taskListSchema.orderBy(lq)
+	if err != nil {
+		return nil, err
+	}
+	if orderBy == "" {
+		orderBy = "CASE t.priority WHEN 'urgent' THEN 1 WHEN 'high' THEN 2 WHEN 'medium' THEN 3 WHEN 'low' THEN 4 END, t.due_date ASC, t.created_at DESC"
+	}
+
 	if len(conditions) > 0 {
 		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	query += " ORDER BY CASE t.priority WHEN 'urgent' THEN 1 WHEN 'high' THEN 2 WHEN 'medium' THEN 3 WHEN 'low' THEN 4 END, t.due_date ASC, t.created_at DESC"
+	query += " ORDER BY " + orderBy
 
-	err := r.db.SelectContext(ctx, &tasks, query, args...)
+	err = r.db.SelectContext(ctx, &tasks, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +116,7 @@ func (r *taskMySQLRepository) FindByID(ctx context.Context, id string) (*entity.
 			  t.contract_id, c.nome as contract_name,
 			  t.assigned_to, ga.nome as assigned_to_name,
 			  t.created_by, gc.nome as created_by_name,
-			  t.completed_at, t.created_at, t.updated_at
+			  t.completed_at, t.escalation_level, t.escalated_at, t.created_at, t.updated_at
 			  FROM tasks t
 			  LEFT JOIN contratos c ON c.id = t.contract_id
 			  LEFT JOIN gestores ga ON ga.id = t.assigned_to
@@ -101,7 +138,7 @@ func (r *taskMySQLRepository) FindByContract(ctx context.Context, contractID str
 			  t.contract_id, c.nome as contract_name,
 			  t.assigned_to, ga.nome as assigned_to_name,
 			  t.created_by, gc.nome as created_by_name,
-			  t.completed_at, t.created_at, t.updated_at
+			  t.completed_at, t.escalation_level, t.escalated_at, t.created_at, t.updated_at
 			  FROM tasks t
 			  LEFT JOIN contratos c ON c.id = t.contract_id
 			  LEFT JOIN gestores ga ON ga.id = t.assigned_to
@@ -121,7 +158,7 @@ func (r *taskMySQLRepository) FindByAssignee(ctx context.Context, assigneeID str
 			  t.contract_id, c.nome as contract_name,
 			  t.assigned_to, ga.nome as assigned_to_name,
 			  t.created_by, gc.nome as created_by_name,
-			  t.completed_at, t.created_at, t.updated_at
+			  t.completed_at, t.escalation_level, t.escalated_at, t.created_at, t.updated_at
 			  FROM tasks t
 			  LEFT JOIN contratos c ON c.id = t.contract_id
 			  LEFT JOIN gestores ga ON ga.id = t.assigned_to
@@ -141,7 +178,7 @@ func (r *taskMySQLRepository) FindByCreator(ctx context.Context, creatorID strin
 			  t.contract_id, c.nome as contract_name,
 			  t.assigned_to, ga.nome as assigned_to_name,
 			  t.created_by, gc.nome as created_by_name,
-			  t.completed_at, t.created_at, t.updated_at
+			  t.completed_at, t.escalation_level, t.escalated_at, t.created_at, t.updated_at
 			  FROM tasks t
 			  LEFT JOIN contratos c ON c.id = t.contract_id
 			  LEFT JOIN gestores ga ON ga.id = t.assigned_to
@@ -161,7 +198,7 @@ func (r *taskMySQLRepository) FindOverdue(ctx context.Context) ([]entity.Task, e
 			  t.contract_id, c.nome as contract_name,
 			  t.assigned_to, ga.nome as assigned_to_name,
 			  t.created_by, gc.nome as created_by_name,
-			  t.completed_at, t.created_at, t.updated_at
+			  t.completed_at, t.escalation_level, t.escalated_at, t.created_at, t.updated_at
 			  FROM tasks t
 			  LEFT JOIN contratos c ON c.id = t.contract_id
 			  LEFT JOIN gestores ga ON ga.id = t.assigned_to
@@ -186,14 +223,38 @@ func (r *taskMySQLRepository) Create(ctx context.Context, task *entity.Task) err
 	return err
 }
 
+func (r *taskMySQLRepository) CreateBatch(ctx context.Context, tasks []*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insert := `INSERT INTO tasks (id, title, description, status, priority, due_date,
+			  contract_id, assigned_to, created_by, completed_at, created_at)`
+	err = insertBatch(ctx, tx, insert, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())", len(tasks), func(i int) []interface{} {
+		task := tasks[i]
+		return []interface{}{task.ID, task.Title, task.Description, task.Status, task.Priority, task.DueDate,
+			task.ContractID, task.AssignedTo, task.CreatedBy, task.CompletedAt}
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (r *taskMySQLRepository) Update(ctx context.Context, task *entity.Task) error {
 	query := `UPDATE tasks
 			  SET title = ?, description = ?, status = ?, priority = ?, due_date = ?,
-			  contract_id = ?, assigned_to = ?, completed_at = ?, updated_at = NOW()
+			  contract_id = ?, assigned_to = ?, completed_at = ?, escalation_level = ?, escalated_at = ?,
+			  updated_at = NOW()
 			  WHERE id = ?`
 	_, err := r.db.ExecContext(ctx, query,
 		task.Title, task.Description, task.Status, task.Priority, task.DueDate,
-		task.ContractID, task.AssignedTo, task.CompletedAt, task.ID)
+		task.ContractID, task.AssignedTo, task.CompletedAt, task.EscalationLevel, task.EscalatedAt, task.ID)
 	return err
 }
 
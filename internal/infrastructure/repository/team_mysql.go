@@ -287,3 +287,65 @@ func (r *teamMySQLRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
+
+func (r *teamMySQLRepository) FindWorkload(ctx context.Context, filter *entity.TeamWorkloadFilter) ([]entity.TeamWorkload, error) {
+	var workloads []entity.TeamWorkload
+
+	query := `
+		SELECT
+			g.id as user_id,
+			g.nome as user_name,
+			(SELECT COUNT(DISTINCT a.contract_id) FROM team_members a
+			 WHERE a.user_id = g.id AND a.is_active = 1) as active_contracts,
+			(SELECT COUNT(*) FROM tasks t
+			 WHERE t.assigned_to = g.id AND t.status IN ('pending', 'in_progress')
+			 AND (t.due_date IS NULL OR t.due_date < ?)) as open_tasks,
+			(SELECT COUNT(*) FROM tasks t
+			 WHERE t.assigned_to = g.id AND t.status IN ('pending', 'in_progress')
+			 AND t.due_date < ?) as overdue_tasks,
+			(SELECT COUNT(*) FROM tasks t
+			 WHERE t.assigned_to = g.id AND t.status IN ('pending', 'in_progress')
+			 AND (t.due_date IS NULL OR t.due_date < ?) AND t.priority IN ('high', 'urgent')) as high_priority_tasks,
+			(SELECT COUNT(*) FROM inspections i
+			 WHERE i.inspector_id = g.id AND i.status IN ('scheduled', 'in_progress')
+			 AND i.inspection_date >= ? AND i.inspection_date < ?) as scheduled_inspections,
+			(SELECT COUNT(*) FROM agenda e
+			 WHERE e.user_id = g.id AND e.start_datetime < ? AND e.end_datetime > ?) as agenda_events,
+			(SELECT COALESCE(SUM(TIMESTAMPDIFF(MINUTE, GREATEST(e.start_datetime, ?), LEAST(e.end_datetime, ?))), 0)
+			 FROM agenda e
+			 WHERE e.user_id = g.id AND e.start_datetime < ? AND e.end_datetime > ?) as agenda_minutes
+		FROM gestores g
+		WHERE g.deleted_at IS NULL AND EXISTS (
+			SELECT 1 FROM team_members tm
+			WHERE tm.user_id = g.id AND tm.is_active = 1
+			AND (tm.start_date IS NULL OR tm.start_date < ?)
+			AND (tm.end_date IS NULL OR tm.end_date >= ?)
+	`
+
+	args := []interface{}{
+		filter.To,
+		filter.Now,
+		filter.To,
+		filter.From, filter.To,
+		filter.To, filter.From,
+		filter.From, filter.To, filter.To, filter.From,
+		filter.To, filter.From,
+	}
+
+	if filter.ContractID != nil && *filter.ContractID != "" {
+		query += " AND tm.contract_id = ?"
+		args = append(args, *filter.ContractID)
+	}
+
+	query += `
+		)
+		ORDER BY g.nome
+	`
+
+	err := r.db.SelectContext(ctx, &workloads, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return workloads, nil
+}
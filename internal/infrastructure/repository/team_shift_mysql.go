@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type teamShiftMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewTeamShiftMySQLRepository creates a new MySQL implementation of TeamShiftRepository
+func NewTeamShiftMySQLRepository(db *sqlx.DB) repository.TeamShiftRepository {
+	return &teamShiftMySQLRepository{db: db}
+}
+
+const teamShiftSelect = `
+		SELECT
+			s.id,
+			s.team_member_id,
+			s.user_id,
+			COALESCE(g.nome, '') as user_name,
+			s.contract_id,
+			COALESCE(c.nome, '') as contract_name,
+			s.weekday,
+			TIME_FORMAT(s.start_time, '%H:%i') as start_time,
+			TIME_FORMAT(s.end_time, '%H:%i') as end_time,
+			s.effective_from,
+			s.effective_until,
+			s.notes,
+			s.is_active,
+			s.created_at,
+			s.updated_at
+		FROM team_shifts s
+		LEFT JOIN gestores g ON s.user_id = g.id
+		LEFT JOIN contratos c ON s.contract_id = c.id
+	`
+
+func (r *teamShiftMySQLRepository) FindAll(ctx context.Context, filter *entity.TeamShiftFilter) ([]entity.TeamShift, error) {
+	var shifts []entity.TeamShift
+
+	query := teamShiftSelect + " WHERE 1=1"
+
+	var args []interface{}
+	var conditions []string
+
+	if filter != nil {
+		if filter.ContractID != nil && *filter.ContractID != "" {
+			conditions = append(conditions, "s.contract_id = ?")
+			args = append(args, *filter.ContractID)
+		}
+		if filter.UserID != nil && *filter.UserID != "" {
+			conditions = append(conditions, "s.user_id = ?")
+			args = append(args, *filter.UserID)
+		}
+		if filter.IsActive != nil {
+			conditions = append(conditions, "s.is_active = ?")
+			args = append(args, *filter.IsActive)
+		}
+	}
+
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY s.weekday, s.start_time, user_name"
+
+	err := r.db.SelectContext(ctx, &shifts, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return shifts, nil
+}
+
+func (r *teamShiftMySQLRepository) FindByID(ctx context.Context, id string) (*entity.TeamShift, error) {
+	var shift entity.TeamShift
+	query := teamShiftSelect + " WHERE s.id = ?"
+	err := r.db.GetContext(ctx, &shift, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &shift, nil
+}
+
+func (r *teamShiftMySQLRepository) Create(ctx context.Context, shift *entity.TeamShiftDB) error {
+	query := `INSERT INTO team_shifts (id, team_member_id, user_id, contract_id, weekday, start_time, end_time,
+			  effective_from, effective_until, notes, is_active, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`
+	_, err := r.db.ExecContext(ctx, query, shift.ID, shift.TeamMemberID, shift.UserID, shift.ContractID,
+		shift.Weekday, shift.StartTime, shift.EndTime, shift.EffectiveFrom, shift.EffectiveUntil, shift.Notes, shift.IsActive)
+	return err
+}
+
+func (r *teamShiftMySQLRepository) Update(ctx context.Context, shift *entity.TeamShiftDB) error {
+	query := `UPDATE team_shifts
+			  SET weekday = ?, start_time = ?, end_time = ?, effective_from = ?, effective_until = ?,
+			  notes = ?, is_active = ?, updated_at = NOW()
+			  WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, shift.Weekday, shift.StartTime, shift.EndTime,
+		shift.EffectiveFrom, shift.EffectiveUntil, shift.Notes, shift.IsActive, shift.ID)
+	return err
+}
+
+func (r *teamShiftMySQLRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM team_shifts WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -23,7 +23,7 @@ func NewUserMySQLRepository(db *sqlx.DB) repository.UserRepository {
 
 func (r *userMySQLRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
 	var user entity.User
-	query := `SELECT id, email, password_hash, name, role, is_active, phone, cpf, avatar_url, last_login, created_at, updated_at
+	query := `SELECT id, email, password_hash, name, role, is_active, phone, cpf, avatar_url, bio, last_login, created_at, updated_at
 			  FROM users
 			  WHERE id = ?`
 	err := r.db.GetContext(ctx, &user, query, id)
@@ -38,7 +38,7 @@ func (r *userMySQLRepository) FindByID(ctx context.Context, id string) (*entity.
 
 func (r *userMySQLRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	query := `SELECT id, email, password_hash, name, role, is_active, phone, cpf, avatar_url, last_login, created_at, updated_at
+	query := `SELECT id, email, password_hash, name, role, is_active, phone, cpf, avatar_url, bio, last_login, created_at, updated_at
 			  FROM users
 			  WHERE email = ?`
 	err := r.db.GetContext(ctx, &user, query, email)
@@ -53,7 +53,7 @@ func (r *userMySQLRepository) FindByEmail(ctx context.Context, email string) (*e
 
 func (r *userMySQLRepository) FindAll(ctx context.Context) ([]entity.User, error) {
 	var users []entity.User
-	query := `SELECT id, email, password_hash, name, role, is_active, phone, cpf, avatar_url, last_login, created_at, updated_at
+	query := `SELECT id, email, password_hash, name, role, is_active, phone, cpf, avatar_url, bio, last_login, created_at, updated_at
 			  FROM users
 			  WHERE is_active = 1
 			  ORDER BY name`
@@ -69,7 +69,7 @@ func (r *userMySQLRepository) FindAllWithFilters(ctx context.Context, filters re
 	var conditions []string
 	var args []interface{}
 
-	baseQuery := `SELECT id, email, password_hash, name, role, is_active, phone, cpf, avatar_url, last_login, created_at, updated_at
+	baseQuery := `SELECT id, email, password_hash, name, role, is_active, phone, cpf, avatar_url, bio, last_login, created_at, updated_at
 			      FROM users`
 
 	// Apply filters
@@ -121,9 +121,9 @@ func (r *userMySQLRepository) Create(ctx context.Context, user *entity.User) err
 
 func (r *userMySQLRepository) Update(ctx context.Context, user *entity.User) error {
 	query := `UPDATE users
-			  SET email = ?, name = ?, role = ?, is_active = ?, phone = ?, cpf = ?, avatar_url = ?, updated_at = NOW()
+			  SET email = ?, name = ?, role = ?, is_active = ?, phone = ?, cpf = ?, avatar_url = ?, bio = ?, updated_at = NOW()
 			  WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, user.Email, user.Nome, user.Role, user.IsActive, user.Phone, user.CPF, user.AvatarURL, user.ID)
+	_, err := r.db.ExecContext(ctx, query, user.Email, user.Nome, user.Role, user.IsActive, user.Phone, user.CPF, user.AvatarURL, user.Bio, user.ID)
 	return err
 }
 
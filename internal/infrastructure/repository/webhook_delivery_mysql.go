@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type webhookDeliveryMySQLRepository struct {
+	db     *sqlx.DB
+	reader *sqlx.DB
+}
+
+// NewWebhookDeliveryMySQLRepository creates a new MySQL implementation of WebhookDeliveryRepository.
+// reader serves the dashboard queries and may be a read replica; writes go to db.
+func NewWebhookDeliveryMySQLRepository(db, reader *sqlx.DB) repository.WebhookDeliveryRepository {
+	return &webhookDeliveryMySQLRepository{db: db, reader: reader}
+}
+
+func (r *webhookDeliveryMySQLRepository) Create(ctx context.Context, d *entity.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (id, gateway, event_type, gateway_event_id, gateway_payment_id,
+			  outcome, status, latency_ms, error, received_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query,
+		d.ID, d.Gateway, d.EventType, d.GatewayEventID, d.GatewayPaymentID,
+		d.Outcome, d.Status, d.LatencyMs, d.Error, d.ReceivedAt)
+	return err
+}
+
+func (r *webhookDeliveryMySQLRepository) FindAll(ctx context.Context, filters entity.WebhookDeliveryFilters) ([]entity.WebhookDelivery, int, error) {
+	where := []string{"1=1"}
+	args := []interface{}{}
+
+	if filters.Gateway != "" {
+		where = append(where, "gateway = ?")
+		args = append(args, filters.Gateway)
+	}
+	if filters.Outcome != "" {
+		where = append(where, "outcome = ?")
+		args = append(args, filters.Outcome)
+	}
+	if filters.Since != nil {
+		where = append(where, "received_at >= ?")
+		args = append(args, *filters.Since)
+	}
+	whereClause := strings.Join(where, " AND ")
+
+	var total int
+	if err := r.reader.GetContext(ctx, &total, `SELECT COUNT(*) FROM webhook_deliveries WHERE `+whereClause, args...); err != nil {
+		return nil, 0, err
+	}
+
+	page := filters.Page
+	if page < 1 {
+		page = 1
+	}
+	perPage := filters.PerPage
+	if perPage < 1 {
+		perPage = 50
+	}
+
+	query := fmt.Sprintf(`SELECT id, gateway, event_type, gateway_event_id, gateway_payment_id, outcome, status,
+			  latency_ms, error, received_at
+			  FROM webhook_deliveries WHERE %s ORDER BY received_at DESC LIMIT ? OFFSET ?`, whereClause)
+	var deliveries []entity.WebhookDelivery
+	if err := r.reader.SelectContext(ctx, &deliveries, query, append(args, perPage, (page-1)*perPage)...); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *webhookDeliveryMySQLRepository) Stats(ctx context.Context, since time.Time) ([]entity.WebhookGatewayStats, error) {
+	query := `SELECT gateway,
+			  COUNT(*) AS total,
+			  COALESCE(SUM(outcome = 'processed'), 0) AS processed,
+			  COALESCE(SUM(outcome = 'ignored'), 0) AS ignored,
+			  COALESCE(SUM(outcome = 'rejected'), 0) AS rejected,
+			  COALESCE(SUM(outcome = 'invalid'), 0) AS invalid,
+			  COALESCE(SUM(outcome = 'failed'), 0) AS failed,
+			  COALESCE(AVG(latency_ms), 0) AS avg_latency_ms,
+			  COALESCE(MAX(latency_ms), 0) AS max_latency_ms,
+			  MAX(received_at) AS last_received_at,
+			  MAX(CASE WHEN outcome IN ('processed', 'ignored') THEN received_at END) AS last_processed_at,
+			  MAX(CASE WHEN outcome IN ('rejected', 'invalid', 'failed') THEN received_at END) AS last_failure_at
+			  FROM webhook_deliveries
+			  WHERE received_at >= ?
+			  GROUP BY gateway
+			  ORDER BY gateway`
+	var stats []entity.WebhookGatewayStats
+	if err := r.reader.SelectContext(ctx, &stats, query, since); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type webhookEventMySQLRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookEventMySQLRepository creates a new MySQL implementation of WebhookEventRepository
+func NewWebhookEventMySQLRepository(db *sqlx.DB) repository.WebhookEventRepository {
+	return &webhookEventMySQLRepository{db: db}
+}
+
+func (r *webhookEventMySQLRepository) ClaimWithTx(ctx context.Context, tx *sqlx.Tx, event *entity.ProcessedWebhookEvent) (bool, error) {
+	// A concurrent delivery of the same event blocks on the unique key until the first
+	// transaction ends, then is ignored if it committed
+	result, err := tx.ExecContext(ctx, `INSERT IGNORE INTO webhook_events
+			  (gateway, gateway_event_id, event_type, gateway_payment_id, processed_at)
+			  VALUES (?, ?, ?, ?, ?)`,
+		event.Gateway, event.GatewayEventID, event.EventType, event.GatewayPaymentID, event.ProcessedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
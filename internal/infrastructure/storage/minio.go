@@ -247,64 +247,29 @@ func getExtensionFromContentType(contentType string) string {
 	return ""
 }
 
+// contentTypes maps the known file extensions to their content type
+var contentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".svg":  "image/svg+xml",
+	".pdf":  "application/pdf",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xls":  "application/vnd.ms-excel",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
 // GetContentTypeFromExtension returns content type based on file extension
 func GetContentTypeFromExtension(filename string) string {
-	ext := strings.ToLower(filepath.Ext(filename))
-	contentTypes := map[string]string{
-		".jpg":  "image/jpeg",
-		".jpeg": "image/jpeg",
-		".png":  "image/png",
-		".gif":  "image/gif",
-		".webp": "image/webp",
-		".svg":  "image/svg+xml",
-		".pdf":  "application/pdf",
-		".mp4":  "video/mp4",
-		".webm": "video/webm",
-		".mp3":  "audio/mpeg",
-		".wav":  "audio/wav",
-		".doc":  "application/msword",
-		".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
-	}
-
-	if ct, ok := contentTypes[ext]; ok {
+	if ct, ok := contentTypes[strings.ToLower(filepath.Ext(filename))]; ok {
 		return ct
 	}
 	return "application/octet-stream"
 }
-
-// IsAllowedImageType checks if the content type is an allowed image type
-func IsAllowedImageType(contentType string) bool {
-	allowed := []string{
-		"image/jpeg",
-		"image/png",
-		"image/gif",
-		"image/webp",
-		"image/svg+xml",
-	}
-
-	for _, a := range allowed {
-		if contentType == a {
-			return true
-		}
-	}
-	return false
-}
-
-// IsAllowedEvidenceType checks if the content type is allowed for evidence files
-func IsAllowedEvidenceType(contentType string) bool {
-	allowed := []string{
-		"image/jpeg",
-		"image/png",
-		"image/gif",
-		"application/pdf",
-		"application/msword",
-		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
-	}
-
-	for _, a := range allowed {
-		if contentType == a {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Upload contexts, each with its own policy of accepted content types and maximum size
+const (
+	UploadEvidence         = "evidence"          // audit evidence sent through the portal
+	UploadInspectionPhoto  = "inspection"        // evidence attached to an inspection
+	UploadTaskAttachment   = "task"              // evidence attached to a task
+	UploadPortalImage      = "portal"            // images of the public portal
+	UploadImage            = "image"             // images of the image library
+	UploadContractDocument = "contract_document" // contract document versions
+	UploadPayoutReceipt    = "payout_receipt"    // bank receipts of paid payout batches
+	UploadPaymentProof     = "payment_proof"     // proofs of payments confirmed by hand
+)
+
+// UploadContexts lists the upload contexts in the order they are documented
+var UploadContexts = []string{
+	UploadEvidence, UploadInspectionPhoto, UploadTaskAttachment, UploadPortalImage,
+	UploadImage, UploadContractDocument, UploadPayoutReceipt, UploadPaymentProof,
+}
+
+// UploadPolicySettingKey returns the setting holding the policy of an upload context
+func UploadPolicySettingKey(context string) string {
+	return "upload_policy_" + context
+}
+
+var (
+	evidenceTypes = []string{
+		"image/jpeg",
+		"image/png",
+		"image/gif",
+		"application/pdf",
+		"application/msword",
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	}
+	documentTypes = []string{
+		"application/pdf",
+		"application/msword",
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		"application/vnd.ms-excel",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"image/jpeg",
+		"image/png",
+	}
+)
+
+// UploadPolicy restricts the files accepted in an upload context. AllowedTypes are MIME
+// types; "image/*" accepts any subtype.
+type UploadPolicy struct {
+	MaxSize      int64    `json:"max_size"`
+	AllowedTypes []string `json:"allowed_types"`
+}
+
+// Allows reports whether the content type is accepted
+func (p UploadPolicy) Allows(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, t := range p.AllowedTypes {
+		if t == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedExtensions lists the accepted types as file extensions (jpg, png, ...), for error
+// messages. Wildcard types are listed as they are.
+func (p UploadPolicy) AllowedExtensions() string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, t := range p.AllowedTypes {
+		exts := extensionsOf(t)
+		if len(exts) == 0 {
+			exts = []string{t}
+		}
+		for _, ext := range exts {
+			if !seen[ext] {
+				seen[ext] = true
+				names = append(names, ext)
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// MaxSizeLabel formats the maximum size for error messages, in MB when it is a whole number
+func (p UploadPolicy) MaxSizeLabel() string {
+	const mb = 1024 * 1024
+	if p.MaxSize >= mb && p.MaxSize%mb == 0 {
+		return fmt.Sprintf("%dMB", p.MaxSize/mb)
+	}
+	return fmt.Sprintf("%d bytes", p.MaxSize)
+}
+
+// extensionsOf returns the known extensions of a MIME type, without the dot
+func extensionsOf(mimeType string) []string {
+	var exts []string
+	for ext, ct := range contentTypes {
+		if ct == mimeType {
+			exts = append(exts, strings.TrimPrefix(ext, "."))
+		}
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// DefaultUploadPolicies returns the policies used while no setting overrides them.
+// maxUploadSize (MAX_UPLOAD_SIZE) bounds every context but the portal ones, kept at 10MB.
+func DefaultUploadPolicies(maxUploadSize int64) map[string]UploadPolicy {
+	const portalMax = 10 * 1024 * 1024
+	return map[string]UploadPolicy{
+		UploadEvidence:         {MaxSize: portalMax, AllowedTypes: evidenceTypes},
+		UploadInspectionPhoto:  {MaxSize: portalMax, AllowedTypes: evidenceTypes},
+		UploadTaskAttachment:   {MaxSize: portalMax, AllowedTypes: evidenceTypes},
+		UploadPortalImage:      {MaxSize: portalMax, AllowedTypes: []string{"image/*"}},
+		UploadImage:            {MaxSize: maxUploadSize, AllowedTypes: []string{"image/jpeg", "image/png", "image/gif", "image/webp"}},
+		UploadContractDocument: {MaxSize: maxUploadSize, AllowedTypes: documentTypes},
+		UploadPayoutReceipt:    {MaxSize: maxUploadSize, AllowedTypes: evidenceTypes},
+		UploadPaymentProof:     {MaxSize: maxUploadSize, AllowedTypes: evidenceTypes},
+	}
+}
+
+// ParseUploadPolicy parses the JSON policy of a setting, e.g.
+// {"max_size": 5242880, "allowed_types": ["image/jpeg", "application/pdf"]}. Fields left out
+// keep the value of base; an empty value returns base.
+func ParseUploadPolicy(value string, base UploadPolicy) (UploadPolicy, error) {
+	if strings.TrimSpace(value) == "" {
+		return base, nil
+	}
+
+	var raw struct {
+		MaxSize      *int64    `json:"max_size"`
+		AllowedTypes *[]string `json:"allowed_types"`
+	}
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return UploadPolicy{}, fmt.Errorf("invalid upload policy: %w", err)
+	}
+
+	policy := base
+	if raw.MaxSize != nil {
+		if *raw.MaxSize <= 0 {
+			return UploadPolicy{}, fmt.Errorf("max_size must be positive")
+		}
+		policy.MaxSize = *raw.MaxSize
+	}
+	if raw.AllowedTypes != nil {
+		if len(*raw.AllowedTypes) == 0 {
+			return UploadPolicy{}, fmt.Errorf("allowed_types must not be empty")
+		}
+		types := make([]string, 0, len(*raw.AllowedTypes))
+		for _, t := range *raw.AllowedTypes {
+			t = strings.ToLower(strings.TrimSpace(t))
+			major, minor, ok := strings.Cut(t, "/")
+			if !ok || major == "" || minor == "" || major == "*" || strings.ContainsAny(t, " ;,") {
+				return UploadPolicy{}, fmt.Errorf("%q is not a MIME type", t)
+			}
+			types = append(types, t)
+		}
+		policy.AllowedTypes = types
+	}
+	return policy, nil
+}
+
+// UploadPolicies holds the policy of every upload context. A context's policy can be
+// replaced at runtime, e.g. when its setting changes.
+type UploadPolicies struct {
+	defaults map[string]UploadPolicy
+
+	mu       sync.RWMutex
+	policies map[string]UploadPolicy
+}
+
+// NewUploadPolicies creates the policies with their defaults
+func NewUploadPolicies(maxUploadSize int64) *UploadPolicies {
+	defaults := DefaultUploadPolicies(maxUploadSize)
+	policies := make(map[string]UploadPolicy, len(defaults))
+	for k, v := range defaults {
+		policies[k] = v
+	}
+	return &UploadPolicies{defaults: defaults, policies: policies}
+}
+
+// Get returns the policy of an upload context
+func (p *UploadPolicies) Get(context string) UploadPolicy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.policies[context]
+}
+
+// Validator returns the setting validator of an upload context
+func (p *UploadPolicies) Validator(context string) func(value string) error {
+	return func(value string) error {
+		_, err := ParseUploadPolicy(value, p.defaults[context])
+		return err
+	}
+}
+
+// Setter returns the setting subscriber of an upload context. Fields left out of the value
+// keep their defaults; an invalid value is logged and the current policy is kept.
+func (p *UploadPolicies) Setter(context string) func(value string) {
+	return func(value string) {
+		policy, err := ParseUploadPolicy(value, p.defaults[context])
+		if err != nil {
+			log.Printf("[UPLOADS] Ignoring invalid %s upload policy: %v", context, err)
+			return
+		}
+		p.mu.Lock()
+		p.policies[context] = policy
+		p.mu.Unlock()
+	}
+}
@@ -0,0 +1,70 @@
+package storage
+
+import "testing"
+
+func TestUploadPolicyAllows(t *testing.T) {
+	p := UploadPolicy{AllowedTypes: []string{"application/pdf", "image/*"}}
+	for ct, want := range map[string]bool{
+		"application/pdf": true,
+		"image/webp":      true,
+		"IMAGE/PNG":       true,
+		"application/zip": false,
+		"imagex/png":      false,
+	} {
+		if got := p.Allows(ct); got != want {
+			t.Errorf("Allows(%q) = %v, want %v", ct, got, want)
+		}
+	}
+	if got := p.AllowedExtensions(); got != "pdf, image/*" {
+		t.Errorf("AllowedExtensions() = %q", got)
+	}
+}
+
+func TestParseUploadPolicy(t *testing.T) {
+	base := DefaultUploadPolicies(50 * 1024 * 1024)[UploadEvidence]
+
+	p, err := ParseUploadPolicy(`{"max_size": 2097152}`, base)
+	if err != nil || p.MaxSize != 2*1024*1024 || len(p.AllowedTypes) != len(base.AllowedTypes) {
+		t.Errorf("max_size only: %+v, %v", p, err)
+	}
+	if p.MaxSizeLabel() != "2MB" {
+		t.Errorf("MaxSizeLabel() = %q", p.MaxSizeLabel())
+	}
+
+	p, err = ParseUploadPolicy(`{"allowed_types": [" Image/JPEG "]}`, base)
+	if err != nil || p.MaxSize != base.MaxSize || len(p.AllowedTypes) != 1 || !p.Allows("image/jpeg") {
+		t.Errorf("allowed_types only: %+v, %v", p, err)
+	}
+
+	for _, bad := range []string{`[]`, `{"max_size": 0}`, `{"allowed_types": []}`, `{"allowed_types": ["pdf"]}`, `{"allowed_types": ["*/*"]}`, `{"max_bytes": 10}`} {
+		if _, err := ParseUploadPolicy(bad, base); err == nil {
+			t.Errorf("ParseUploadPolicy(%s): expected an error", bad)
+		}
+	}
+}
+
+func TestUploadPoliciesSetter(t *testing.T) {
+	policies := NewUploadPolicies(50 * 1024 * 1024)
+	set := policies.Setter(UploadTaskAttachment)
+
+	set(`{"allowed_types": ["application/pdf"]}`)
+	if p := policies.Get(UploadTaskAttachment); p.Allows("image/png") || !p.Allows("application/pdf") {
+		t.Errorf("task policy = %+v", p)
+	}
+	if !policies.Get(UploadEvidence).Allows("image/png") {
+		t.Error("other contexts must keep their policy")
+	}
+
+	set(`not json`)
+	if p := policies.Get(UploadTaskAttachment); !p.Allows("application/pdf") || len(p.AllowedTypes) != 1 {
+		t.Errorf("invalid value replaced the policy: %+v", p)
+	}
+	if err := policies.Validator(UploadTaskAttachment)(`not json`); err == nil {
+		t.Error("validator accepted an invalid value")
+	}
+
+	set("")
+	if p := policies.Get(UploadTaskAttachment); !p.Allows("image/png") || p.MaxSize != 10*1024*1024 {
+		t.Errorf("empty value did not restore the defaults: %+v", p)
+	}
+}
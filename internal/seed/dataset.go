@@ -0,0 +1,355 @@
+// Package seed generates a demo dataset (gestores, contratos, audits with items, courses,
+// enrollments and payments) and stores it through the repositories, for staging and local
+// development. The dataset depends only on the options, so every run with the same seed
+// produces the same records.
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/google/uuid"
+)
+
+// EmailDomain is the domain of every demo email, so demo records are easy to spot
+const EmailDomain = "demo.condotrack.com.br"
+
+// auditTolerance matches the approval tolerance of the audit usecase
+const auditTolerance = 5.0
+
+// Options sizes the dataset
+type Options struct {
+	Seed               int64
+	Gestores           int
+	ContratosPerGestor int
+	AuditsPerContrato  int
+	Courses            int
+	Enrollments        int
+}
+
+// DefaultOptions is a dataset small enough to load in seconds that still fills every list
+// and chart of the frontend
+var DefaultOptions = Options{
+	Seed:               1,
+	Gestores:           3,
+	ContratosPerGestor: 3,
+	AuditsPerContrato:  6,
+	Courses:            4,
+	Enrollments:        40,
+}
+
+// Dataset is the set of records to store
+type Dataset struct {
+	Instructors []entity.User
+	Students    []entity.User
+	Gestores    []entity.Gestor
+	Contratos   []entity.Contrato
+	Categories  []entity.AuditCategory
+	Audits      []entity.Audit
+	AuditItems  []entity.AuditItem
+	Courses     []entity.Course
+	Matriculas  []entity.Matricula
+	Payments    []entity.Payment
+}
+
+var (
+	firstNames = []string{"Ana", "Bruno", "Carla", "Diego", "Eduarda", "Felipe", "Gabriela", "Henrique", "Isabela", "João", "Larissa", "Marcos", "Natália", "Otávio", "Paula", "Rafael", "Sofia", "Thiago", "Vanessa", "William"}
+	lastNames  = []string{"Almeida", "Barbosa", "Cardoso", "Duarte", "Ferreira", "Gomes", "Lima", "Martins", "Nogueira", "Oliveira", "Pereira", "Ribeiro", "Santos", "Teixeira", "Vieira"}
+	buildings  = []string{"Residencial Jardim das Flores", "Condomínio Parque Verde", "Edifício Solar do Atlântico", "Residencial Vila Nova", "Condomínio Bosque Imperial", "Edifício Torre Azul", "Residencial Monte Belo", "Condomínio Recanto do Sol", "Edifício Mirante", "Residencial Águas Claras", "Condomínio Alto da Serra", "Edifício Central Park"}
+	streets    = []string{"Rua das Acácias", "Avenida Paulista", "Rua Sete de Setembro", "Avenida Brasil", "Rua XV de Novembro", "Rua das Palmeiras", "Avenida Atlântica", "Rua Dom Pedro II"}
+	cities     = []struct{ Cidade, Estado, CEP string }{
+		{"São Paulo", "SP", "01310-100"},
+		{"Rio de Janeiro", "RJ", "22021-001"},
+		{"Belo Horizonte", "MG", "30130-010"},
+		{"Curitiba", "PR", "80020-000"},
+		{"Porto Alegre", "RS", "90010-150"},
+	}
+	auditCategories = []struct {
+		Name   string
+		Weight float64
+		Items  []string
+	}{
+		{"Limpeza", 1.0, []string{"Hall de entrada", "Escadas e corredores", "Garagem", "Área da piscina"}},
+		{"Segurança", 1.5, []string{"Portaria", "Câmeras de monitoramento", "Extintores", "Iluminação de emergência"}},
+		{"Manutenção", 1.2, []string{"Elevadores", "Bombas d'água", "Portões automáticos", "Telhado e calhas"}},
+		{"Jardinagem", 0.8, []string{"Poda e gramado", "Irrigação", "Canteiros"}},
+	}
+	courses = []struct {
+		Name     string
+		Hours    int
+		Price    float64
+		Discount float64
+	}{
+		{"Gestão Condominial Essencial", 40, 497, 397},
+		{"Síndico Profissional", 60, 897, 0},
+		{"Manutenção Predial Preventiva", 24, 297, 247},
+		{"Legislação Condominial", 16, 197, 0},
+		{"Mediação de Conflitos em Condomínios", 12, 147, 0},
+		{"Finanças e Prestação de Contas", 20, 347, 297},
+	}
+	paymentMethods = []string{entity.MethodPIX, entity.MethodPIX, entity.MethodBoleto, entity.MethodCreditCard}
+)
+
+// Generate builds the dataset of the options. Dates are relative to now: audits spread over
+// the previous months and enrollments over the previous weeks.
+func Generate(opts Options, now time.Time) *Dataset {
+	g := &generator{rng: rand.New(rand.NewSource(opts.Seed)), now: now}
+	ds := &Dataset{}
+
+	for _, c := range auditCategories {
+		ds.Categories = append(ds.Categories, entity.AuditCategory{ID: g.id(), Name: c.Name, Weight: c.Weight, Order: len(ds.Categories) + 1})
+	}
+
+	for i := 0; i < opts.Gestores; i++ {
+		nome := g.name()
+		gestor := entity.Gestor{
+			ID:       g.id(),
+			Nome:     nome,
+			Email:    email("gestor", i+1),
+			Telefone: g.phone(),
+			Ativo:    true,
+		}
+		ds.Gestores = append(ds.Gestores, gestor)
+
+		for j := 0; j < opts.ContratosPerGestor; j++ {
+			contrato := g.contrato(gestor.ID, len(ds.Contratos))
+			ds.Contratos = append(ds.Contratos, contrato)
+			g.audits(ds, contrato, opts.AuditsPerContrato)
+		}
+	}
+
+	for i := 0; i < opts.Courses; i++ {
+		instructor := g.user(entity.RoleInstructor, email("instrutor", i+1))
+		ds.Instructors = append(ds.Instructors, instructor)
+
+		c := courses[i%len(courses)]
+		name := c.Name
+		if i >= len(courses) {
+			name = fmt.Sprintf("%s %d", c.Name, i/len(courses)+1)
+		}
+		course := entity.Course{
+			ID:             g.id(),
+			Name:           name,
+			Description:    strPtr("Curso de demonstração: " + name),
+			InstructorID:   strPtr(instructor.ID),
+			InstructorName: strPtr(instructor.Nome),
+			DurationHours:  c.Hours,
+			Price:          c.Price,
+			IsActive:       true,
+		}
+		if c.Discount > 0 {
+			course.DiscountPrice = floatPtr(c.Discount)
+		}
+		ds.Courses = append(ds.Courses, course)
+	}
+
+	if len(ds.Courses) > 0 {
+		for i := 0; i < opts.Enrollments; i++ {
+			student := g.user(entity.RoleStudent, email("aluno", i+1))
+			ds.Students = append(ds.Students, student)
+			g.enrollment(ds, student, ds.Courses[g.rng.Intn(len(ds.Courses))])
+		}
+	}
+	return ds
+}
+
+type generator struct {
+	rng *rand.Rand
+	now time.Time
+}
+
+func (g *generator) id() string {
+	return uuid.Must(uuid.NewRandomFromReader(g.rng)).String()
+}
+
+func (g *generator) name() string {
+	return firstNames[g.rng.Intn(len(firstNames))] + " " + lastNames[g.rng.Intn(len(lastNames))]
+}
+
+func (g *generator) phone() *string {
+	return strPtr(fmt.Sprintf("(11) 9%04d-%04d", g.rng.Intn(10000), g.rng.Intn(10000)))
+}
+
+func (g *generator) user(role entity.UserRole, email string) entity.User {
+	return entity.User{
+		ID:       g.id(),
+		Email:    email,
+		Nome:     g.name(),
+		Role:     role,
+		IsActive: true,
+		Phone:    g.phone(),
+	}
+}
+
+func (g *generator) contrato(gestorID string, n int) entity.Contrato {
+	city := cities[g.rng.Intn(len(cities))]
+	nome := buildings[n%len(buildings)]
+	if n >= len(buildings) {
+		nome = fmt.Sprintf("%s %d", nome, n/len(buildings)+1)
+	}
+	inicio := g.now.AddDate(-1-g.rng.Intn(3), -g.rng.Intn(12), 0)
+	fim := inicio.AddDate(3, 0, 0)
+	return entity.Contrato{
+		ID:            g.id(),
+		GestorID:      gestorID,
+		Nome:          nome,
+		Endereco:      strPtr(fmt.Sprintf("%s, %d", streets[g.rng.Intn(len(streets))], 10+g.rng.Intn(1990))),
+		Cidade:        &city.Cidade,
+		Estado:        &city.Estado,
+		CEP:           &city.CEP,
+		TotalUnidades: 20 + g.rng.Intn(300),
+		MetaScore:     []float64{75, 80, 85, 90}[g.rng.Intn(4)],
+		DataInicio:    &inicio,
+		DataFim:       &fim,
+		Ativo:         fim.After(g.now),
+	}
+}
+
+// audits creates monthly audits of a contract, oldest first, with a score that trends up
+// so the evolution charts have something to show
+func (g *generator) audits(ds *Dataset, contrato entity.Contrato, count int) {
+	quality := 0.6 + g.rng.Float64()*0.25
+	var previous *float64
+	for k := count; k > 0; k-- {
+		audit := entity.Audit{
+			ID:            g.id(),
+			ContractID:    contrato.ID,
+			AuditorName:   g.name(),
+			AuditDate:     g.now.AddDate(0, -k, -g.rng.Intn(20)),
+			TargetScore:   contrato.MetaScore,
+			PreviousScore: previous,
+		}
+
+		// The score is the weighted average of the item percentages, as in the audit form
+		var weighted, weights float64
+		for c, category := range auditCategories {
+			var score, max float64
+			for _, name := range category.Items {
+				item := entity.AuditItem{
+					ID:         g.id(),
+					AuditID:    audit.ID,
+					CategoryID: ds.Categories[c].ID,
+					ItemName:   name,
+					MaxScore:   10,
+					Score:      math.Min(10, math.Round(10*(quality+g.rng.Float64()*0.3))),
+				}
+				if item.Score < 6 {
+					item.Observation = strPtr("Necessita correção até a próxima visita")
+				}
+				ds.AuditItems = append(ds.AuditItems, item)
+				score += item.Score
+				max += item.MaxScore
+			}
+			weighted += score / max * 100 * category.Weight
+			weights += category.Weight
+		}
+		audit.Score = math.Round(weighted/weights*10) / 10
+		audit.Status = entity.CalculateStatus(audit.Score, audit.TargetScore, auditTolerance)
+		if audit.Status == entity.AuditStatusRejected {
+			audit.Observations = strPtr("Pontuação abaixo da meta; plano de ação enviado ao gestor")
+		}
+		ds.Audits = append(ds.Audits, audit)
+
+		score := audit.Score
+		previous = &score
+		quality = math.Min(0.9, quality+0.02)
+	}
+}
+
+// enrollment creates an enrollment with the payment its status implies: active and
+// completed enrollments were paid, pending ones await payment and cancelled ones were
+// refunded
+func (g *generator) enrollment(ds *Dataset, student entity.User, course entity.Course) {
+	enrolled := g.now.AddDate(0, 0, -g.rng.Intn(120))
+	amount := course.Price
+	discount := 0.0
+	if course.DiscountPrice != nil {
+		discount = course.Price - *course.DiscountPrice
+	}
+	final := amount - discount
+	method := paymentMethods[g.rng.Intn(len(paymentMethods))]
+	expiration := enrolled.AddDate(1, 0, 0)
+
+	m := entity.Matricula{
+		ID:             g.id(),
+		StudentID:      student.ID,
+		StudentName:    student.Nome,
+		StudentEmail:   student.Email,
+		StudentPhone:   student.Phone,
+		CourseID:       course.ID,
+		CourseName:     course.Name,
+		InstructorID:   course.InstructorID,
+		InstructorName: course.InstructorName,
+		Amount:         amount,
+		DiscountAmount: discount,
+		FinalAmount:    final,
+		PaymentMethod:  &method,
+		EnrollmentDate: enrolled,
+		ExpirationDate: &expiration,
+	}
+
+	p := entity.Payment{
+		ID:               g.id(),
+		EnrollmentID:     m.ID,
+		PayerUserID:      &student.ID,
+		PayerName:        student.Nome,
+		PayerEmail:       student.Email,
+		GrossAmount:      money.FromFloat(amount),
+		DiscountAmount:   money.FromFloat(discount),
+		NetAmount:        money.FromFloat(final),
+		PaymentMethod:    method,
+		Gateway:          entity.GatewayManual,
+		InstallmentCount: 1,
+	}
+	due := enrolled.AddDate(0, 0, 3)
+	p.DueDate = &due
+
+	switch r := g.rng.Float64(); {
+	case r < 0.55:
+		m.Status = entity.EnrollmentStatusActive
+		m.PaymentStatus = entity.PaymentStatusConfirmed
+		m.Progress = math.Round(g.rng.Float64() * 90)
+		p.Status = entity.FinPaymentStatusConfirmed
+		p.PaidAt = timePtr(enrolled.Add(time.Duration(1+g.rng.Intn(48)) * time.Hour))
+	case r < 0.75:
+		m.Status = entity.EnrollmentStatusCompleted
+		m.PaymentStatus = entity.PaymentStatusConfirmed
+		m.Progress = 100
+		m.CompletionDate = timePtr(enrolled.AddDate(0, 0, 20+g.rng.Intn(60)))
+		p.Status = entity.FinPaymentStatusConfirmed
+		p.PaidAt = timePtr(enrolled.Add(time.Duration(1+g.rng.Intn(48)) * time.Hour))
+	case r < 0.9:
+		m.Status = entity.EnrollmentStatusPending
+		m.PaymentStatus = entity.PaymentStatusPending
+		p.Status = entity.FinPaymentStatusAwaitingPayment
+		if due.Before(g.now) {
+			m.PaymentStatus = entity.PaymentStatusOverdue
+			p.Status = entity.FinPaymentStatusOverdue
+		}
+	default:
+		m.Status = entity.EnrollmentStatusCancelled
+		m.PaymentStatus = entity.PaymentStatusRefunded
+		p.Status = entity.FinPaymentStatusRefunded
+		p.PaidAt = timePtr(enrolled.Add(time.Duration(1+g.rng.Intn(48)) * time.Hour))
+		p.RefundedAt = timePtr(p.PaidAt.AddDate(0, 0, 1+g.rng.Intn(6)))
+		p.RefundedAmount = p.NetAmount
+	}
+
+	m.PaymentID = &p.ID
+	ds.Matriculas = append(ds.Matriculas, m)
+	ds.Payments = append(ds.Payments, p)
+}
+
+// email returns the demo email of the n-th record of a kind, e.g. gestor1@<EmailDomain>
+func email(kind string, n int) string {
+	return fmt.Sprintf("%s%d@%s", strings.ToLower(kind), n, EmailDomain)
+}
+
+func strPtr(s string) *string        { return &s }
+func floatPtr(f float64) *float64    { return &f }
+func timePtr(t time.Time) *time.Time { return &t }
@@ -0,0 +1,146 @@
+package seed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/money"
+)
+
+var testNow = time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+func TestGenerate_Sizes(t *testing.T) {
+	ds := Generate(DefaultOptions, testNow)
+	opts := DefaultOptions
+
+	if len(ds.Gestores) != opts.Gestores {
+		t.Errorf("expected %d gestores, got %d", opts.Gestores, len(ds.Gestores))
+	}
+	if len(ds.Contratos) != opts.Gestores*opts.ContratosPerGestor {
+		t.Errorf("expected %d contratos, got %d", opts.Gestores*opts.ContratosPerGestor, len(ds.Contratos))
+	}
+	if len(ds.Audits) != len(ds.Contratos)*opts.AuditsPerContrato {
+		t.Errorf("expected %d audits, got %d", len(ds.Contratos)*opts.AuditsPerContrato, len(ds.Audits))
+	}
+	if len(ds.Courses) != opts.Courses || len(ds.Instructors) != opts.Courses {
+		t.Errorf("expected %d courses and instructors, got %d and %d", opts.Courses, len(ds.Courses), len(ds.Instructors))
+	}
+	if len(ds.Matriculas) != opts.Enrollments || len(ds.Payments) != opts.Enrollments {
+		t.Errorf("expected %d enrollments and payments, got %d and %d", opts.Enrollments, len(ds.Matriculas), len(ds.Payments))
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	a := Generate(DefaultOptions, testNow)
+	b := Generate(DefaultOptions, testNow)
+	if !reflect.DeepEqual(a, b) {
+		t.Error("expected the same dataset for the same seed")
+	}
+
+	opts := DefaultOptions
+	opts.Seed = 2
+	if c := Generate(opts, testNow); c.Gestores[0].ID == a.Gestores[0].ID {
+		t.Error("expected different IDs for another seed")
+	}
+}
+
+func TestGenerate_References(t *testing.T) {
+	ds := Generate(DefaultOptions, testNow)
+
+	contratos := map[string]entity.Contrato{}
+	for _, c := range ds.Contratos {
+		contratos[c.ID] = c
+	}
+	categories := map[string]bool{}
+	for _, c := range ds.Categories {
+		categories[c.ID] = true
+	}
+	audits := map[string]bool{}
+	for _, a := range ds.Audits {
+		contrato, ok := contratos[a.ContractID]
+		if !ok {
+			t.Fatalf("audit %s points at an unknown contrato", a.ID)
+		}
+		if a.TargetScore != contrato.MetaScore {
+			t.Errorf("expected target %v of the contrato, got %v", contrato.MetaScore, a.TargetScore)
+		}
+		if want := entity.CalculateStatus(a.Score, a.TargetScore, auditTolerance); a.Status != want {
+			t.Errorf("expected status %s for score %v, got %s", want, a.Score, a.Status)
+		}
+		if !a.AuditDate.Before(testNow) {
+			t.Errorf("expected audit dates in the past, got %v", a.AuditDate)
+		}
+		audits[a.ID] = true
+	}
+	for _, item := range ds.AuditItems {
+		if !audits[item.AuditID] || !categories[item.CategoryID] {
+			t.Fatalf("audit item %s points at an unknown audit or category", item.ID)
+		}
+		if item.Score < 0 || item.Score > item.MaxScore {
+			t.Errorf("expected item score within 0..%v, got %v", item.MaxScore, item.Score)
+		}
+	}
+}
+
+func TestGenerate_PaymentsMatchEnrollments(t *testing.T) {
+	ds := Generate(DefaultOptions, testNow)
+
+	payments := map[string]entity.Payment{}
+	for _, p := range ds.Payments {
+		payments[p.ID] = p
+	}
+	for _, m := range ds.Matriculas {
+		if m.PaymentID == nil {
+			t.Fatalf("enrollment %s has no payment", m.ID)
+		}
+		p := payments[*m.PaymentID]
+		if p.EnrollmentID != m.ID {
+			t.Fatalf("payment %s points at another enrollment", p.ID)
+		}
+		if m.FinalAmount != m.Amount-m.DiscountAmount || p.NetAmount != money.FromFloat(m.FinalAmount) {
+			t.Errorf("expected net amount %v, got enrollment %v and payment %v", m.Amount-m.DiscountAmount, m.FinalAmount, p.NetAmount)
+		}
+
+		switch m.Status {
+		case entity.EnrollmentStatusActive, entity.EnrollmentStatusCompleted:
+			if p.Status != entity.FinPaymentStatusConfirmed || p.PaidAt == nil {
+				t.Errorf("expected a confirmed payment for a %s enrollment, got %s", m.Status, p.Status)
+			}
+		case entity.EnrollmentStatusCancelled:
+			if p.Status != entity.FinPaymentStatusRefunded || p.RefundedAmount != p.NetAmount {
+				t.Errorf("expected a full refund for a cancelled enrollment, got %s of %v", p.Status, p.RefundedAmount)
+			}
+		case entity.EnrollmentStatusPending:
+			if p.PaidAt != nil {
+				t.Error("expected an unpaid payment for a pending enrollment")
+			}
+		default:
+			t.Errorf("unexpected enrollment status %s", m.Status)
+		}
+	}
+}
+
+func TestGenerate_UniqueEmails(t *testing.T) {
+	opts := DefaultOptions
+	opts.Courses = 8 // more courses than names, which get a number
+	ds := Generate(opts, testNow)
+
+	seen := map[string]bool{}
+	for _, users := range [][]entity.User{ds.Instructors, ds.Students} {
+		for _, u := range users {
+			if seen[u.Email] {
+				t.Errorf("duplicate email %s", u.Email)
+			}
+			seen[u.Email] = true
+		}
+	}
+	names := map[string]bool{}
+	for _, c := range ds.Courses {
+		if names[c.Name] {
+			t.Errorf("duplicate course %s", c.Name)
+		}
+		names[c.Name] = true
+	}
+}
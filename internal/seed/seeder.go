@@ -0,0 +1,192 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+// ErrAlreadySeeded is returned when the database already has the demo dataset
+var ErrAlreadySeeded = errors.New("demo dataset already loaded")
+
+// Seeder stores a dataset through the repositories, so the records go through the same
+// queries as the API
+type Seeder struct {
+	users      repository.UserRepository
+	gestores   repository.GestorRepository
+	contratos  repository.ContratoRepository
+	categories repository.AuditCategoryRepository
+	audits     repository.AuditRepository
+	auditItems repository.AuditItemRepository
+	courses    repository.CourseRepository
+	matriculas repository.MatriculaRepository
+	payments   repository.PaymentRepository
+	// hashPassword hashes the password shared by the demo users
+	hashPassword func(string) (string, error)
+}
+
+// NewSeeder creates a new seeder
+func NewSeeder(
+	users repository.UserRepository,
+	gestores repository.GestorRepository,
+	contratos repository.ContratoRepository,
+	categories repository.AuditCategoryRepository,
+	audits repository.AuditRepository,
+	auditItems repository.AuditItemRepository,
+	courses repository.CourseRepository,
+	matriculas repository.MatriculaRepository,
+	payments repository.PaymentRepository,
+	hashPassword func(string) (string, error),
+) *Seeder {
+	return &Seeder{
+		users:        users,
+		gestores:     gestores,
+		contratos:    contratos,
+		categories:   categories,
+		audits:       audits,
+		auditItems:   auditItems,
+		courses:      courses,
+		matriculas:   matriculas,
+		payments:     payments,
+		hashPassword: hashPassword,
+	}
+}
+
+// Run stores the dataset, with password as the password of every demo user. It returns
+// ErrAlreadySeeded when the first demo gestor exists, so running it twice does not
+// duplicate records.
+func (s *Seeder) Run(ctx context.Context, ds *Dataset, password string) error {
+	if len(ds.Gestores) > 0 {
+		existing, err := s.gestores.FindByEmail(ctx, ds.Gestores[0].Email)
+		if err != nil {
+			return fmt.Errorf("check existing dataset: %w", err)
+		}
+		if existing != nil {
+			return ErrAlreadySeeded
+		}
+	}
+
+	hash, err := s.hashPassword(password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	if err := s.storeUsers(ctx, ds, hash); err != nil {
+		return err
+	}
+	for i := range ds.Gestores {
+		if err := s.gestores.Create(ctx, &ds.Gestores[i]); err != nil {
+			return fmt.Errorf("create gestor %s: %w", ds.Gestores[i].Email, err)
+		}
+	}
+	for i := range ds.Contratos {
+		if err := s.contratos.Create(ctx, &ds.Contratos[i]); err != nil {
+			return fmt.Errorf("create contrato %s: %w", ds.Contratos[i].Nome, err)
+		}
+	}
+
+	if err := s.resolveCategories(ctx, ds); err != nil {
+		return err
+	}
+	for i := range ds.Audits {
+		if err := s.audits.Create(ctx, &ds.Audits[i]); err != nil {
+			return fmt.Errorf("create audit: %w", err)
+		}
+	}
+	if len(ds.AuditItems) > 0 {
+		if err := s.auditItems.CreateBatch(ctx, ds.AuditItems); err != nil {
+			return fmt.Errorf("create audit items: %w", err)
+		}
+	}
+
+	for i := range ds.Courses {
+		if err := s.courses.Create(ctx, &ds.Courses[i]); err != nil {
+			return fmt.Errorf("create course %s: %w", ds.Courses[i].Name, err)
+		}
+	}
+	for i := range ds.Matriculas {
+		if err := s.matriculas.Create(ctx, &ds.Matriculas[i]); err != nil {
+			return fmt.Errorf("create enrollment of %s: %w", ds.Matriculas[i].StudentEmail, err)
+		}
+	}
+	for i := range ds.Payments {
+		if err := s.payments.Create(ctx, &ds.Payments[i]); err != nil {
+			return fmt.Errorf("create payment: %w", err)
+		}
+	}
+	return nil
+}
+
+// storeUsers creates the demo users. Users whose email is already registered, e.g. by an
+// interrupted run, are reused and the records of the dataset point at their IDs.
+func (s *Seeder) storeUsers(ctx context.Context, ds *Dataset, hash string) error {
+	ids := make(map[string]string)
+	for _, users := range [][]entity.User{ds.Instructors, ds.Students} {
+		for i := range users {
+			u := &users[i]
+			existing, err := s.users.FindByEmail(ctx, u.Email)
+			if err != nil {
+				return fmt.Errorf("check user %s: %w", u.Email, err)
+			}
+			if existing != nil {
+				ids[u.ID] = existing.ID
+				u.ID = existing.ID
+				continue
+			}
+			u.PasswordHash = hash
+			if err := s.users.Create(ctx, u); err != nil {
+				return fmt.Errorf("create user %s: %w", u.Email, err)
+			}
+		}
+	}
+
+	remap := func(id *string) {
+		if id != nil {
+			if existing, ok := ids[*id]; ok {
+				*id = existing
+			}
+		}
+	}
+	for i := range ds.Courses {
+		remap(ds.Courses[i].InstructorID)
+	}
+	for i := range ds.Matriculas {
+		remap(&ds.Matriculas[i].StudentID)
+		remap(ds.Matriculas[i].InstructorID)
+	}
+	for i := range ds.Payments {
+		remap(ds.Payments[i].PayerUserID)
+	}
+	return nil
+}
+
+// resolveCategories reuses the audit categories that already exist by name and creates the
+// missing ones, pointing the audit items at the stored IDs
+func (s *Seeder) resolveCategories(ctx context.Context, ds *Dataset) error {
+	ids := make(map[string]string, len(ds.Categories))
+	for i := range ds.Categories {
+		category := &ds.Categories[i]
+		existing, err := s.categories.FindByName(ctx, category.Name)
+		if err != nil {
+			return fmt.Errorf("check audit category %s: %w", category.Name, err)
+		}
+		if existing != nil {
+			ids[category.ID] = existing.ID
+			continue
+		}
+		if err := s.categories.Create(ctx, category); err != nil {
+			return fmt.Errorf("create audit category %s: %w", category.Name, err)
+		}
+		ids[category.ID] = category.ID
+	}
+
+	for i := range ds.AuditItems {
+		if id, ok := ids[ds.AuditItems[i].CategoryID]; ok {
+			ds.AuditItems[i].CategoryID = id
+		}
+	}
+	return nil
+}
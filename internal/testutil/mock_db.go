@@ -0,0 +1,40 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewMockDB returns a database whose transactions begin, commit and roll back
+// without a server, for use cases whose repositories are all mocks. Any
+// statement sent to it fails.
+func NewMockDB() *database.MySQL {
+	return &database.MySQL{DB: sqlx.NewDb(sql.OpenDB(mockConnector{}), "mysql")}
+}
+
+var errMockDBStatement = errors.New("mock database: statements are not supported")
+
+type mockConnector struct{}
+
+func (mockConnector) Connect(ctx context.Context) (driver.Conn, error) { return mockConn{}, nil }
+func (mockConnector) Driver() driver.Driver                            { return mockDriver{} }
+
+type mockDriver struct{}
+
+func (mockDriver) Open(name string) (driver.Conn, error) { return mockConn{}, nil }
+
+type mockConn struct{}
+
+func (mockConn) Prepare(query string) (driver.Stmt, error) { return nil, errMockDBStatement }
+func (mockConn) Close() error                              { return nil }
+func (mockConn) Begin() (driver.Tx, error)                 { return mockTx{}, nil }
+
+type mockTx struct{}
+
+func (mockTx) Commit() error   { return nil }
+func (mockTx) Rollback() error { return nil }
@@ -132,3 +132,35 @@ func (m *MockGateway) GetFees() gateway.GatewayFees {
 		CardFixed:   0.49,
 	}
 }
+
+// MockTransferGateway is a mock implementation of gateway.TransferGateway for testing.
+// Requests are kept in Transfers.
+type MockTransferGateway struct {
+	CreateTransferFunc func(ctx context.Context, req gateway.TransferRequest) (*gateway.TransferResponse, error)
+	Transfers          []gateway.TransferRequest
+}
+
+func (m *MockTransferGateway) Name() string {
+	return "mock"
+}
+
+func (m *MockTransferGateway) CreateTransfer(ctx context.Context, req gateway.TransferRequest) (*gateway.TransferResponse, error) {
+	m.Transfers = append(m.Transfers, req)
+	if m.CreateTransferFunc != nil {
+		return m.CreateTransferFunc(ctx, req)
+	}
+	return &gateway.TransferResponse{
+		GatewayTransferID: "tra_mock_123",
+		Status:            gateway.TransferStatusPending,
+		Amount:            req.Amount,
+		ExternalReference: req.ExternalReference,
+	}, nil
+}
+
+func (m *MockTransferGateway) GetTransfer(ctx context.Context, gatewayTransferID string) (*gateway.TransferResponse, error) {
+	return nil, nil
+}
+
+func (m *MockTransferGateway) ParseTransferEvent(ctx context.Context, body []byte) (*gateway.TransferEvent, error) {
+	return nil, nil
+}
@@ -2,8 +2,12 @@ package testutil
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/jmoiron/sqlx"
 )
@@ -41,13 +45,24 @@ func (m *MockPaymentRepository) FindByEnrollmentID(ctx context.Context, enrollme
 	}
 	var result []entity.Payment
 	for _, p := range m.Payments {
-		if p.EnrollmentID == enrollmentID {
+		if p.EnrollmentID == enrollmentID && p.InstallmentOf == nil {
 			result = append(result, *p)
 		}
 	}
 	return result, nil
 }
 
+func (m *MockPaymentRepository) FindInstallments(ctx context.Context, parentID string) ([]entity.Payment, error) {
+	var result []entity.Payment
+	for _, p := range m.Payments {
+		if p.InstallmentOf != nil && *p.InstallmentOf == parentID {
+			result = append(result, *p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return *result[i].InstallmentNumber < *result[j].InstallmentNumber })
+	return result, nil
+}
+
 func (m *MockPaymentRepository) FindByGatewayPaymentID(ctx context.Context, gw, gatewayPaymentID string) (*entity.Payment, error) {
 	if m.FindByGatewayPaymentIDFunc != nil {
 		return m.FindByGatewayPaymentIDFunc(ctx, gw, gatewayPaymentID)
@@ -60,22 +75,52 @@ func (m *MockPaymentRepository) FindByGatewayPaymentID(ctx context.Context, gw,
 	return nil, nil
 }
 
+func (m *MockPaymentRepository) FindByGatewayInstallmentID(ctx context.Context, gw, installmentID string) (*entity.Payment, error) {
+	for _, p := range m.Payments {
+		if p.Gateway == gw && p.GatewayInstallmentID != nil && *p.GatewayInstallmentID == installmentID {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *MockPaymentRepository) FindAll(ctx context.Context, filters repository.PaymentFilters) ([]entity.Payment, int, error) {
 	if m.FindAllFunc != nil {
 		return m.FindAllFunc(ctx, filters)
 	}
 	var result []entity.Payment
 	for _, p := range m.Payments {
+		if filters.PayerUserID != "" && (p.PayerUserID == nil || *p.PayerUserID != filters.PayerUserID) {
+			continue
+		}
 		result = append(result, *p)
 	}
 	return result, len(result), nil
 }
 
+func (m *MockPaymentRepository) FindExpiredPix(ctx context.Context, now time.Time, limit int) ([]entity.Payment, error) {
+	var result []entity.Payment
+	for _, p := range m.Payments {
+		if p.PixExpired(now) {
+			result = append(result, *p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
 func (m *MockPaymentRepository) Create(ctx context.Context, p *entity.Payment) error {
 	m.Payments[p.ID] = p
 	return nil
 }
 
+func (m *MockPaymentRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.Payment, error) {
+	return m.FindByID(ctx, id)
+}
+
 func (m *MockPaymentRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, p *entity.Payment) error {
 	m.Payments[p.ID] = p
 	return nil
@@ -220,3 +265,1579 @@ func (m *MockPaymentTransactionRepository) Create(ctx context.Context, txLog *en
 func (m *MockPaymentTransactionRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, txLog *entity.PaymentTransaction) error {
 	return m.Create(ctx, txLog)
 }
+
+// MockFeatureFlagRepository is a mock implementation of repository.FeatureFlagRepository.
+type MockFeatureFlagRepository struct {
+	Flags     map[string]*entity.FeatureFlag // keyed by flag key
+	FindCalls int                            // number of FindAll calls
+}
+
+func NewMockFeatureFlagRepository() *MockFeatureFlagRepository {
+	return &MockFeatureFlagRepository{Flags: make(map[string]*entity.FeatureFlag)}
+}
+
+func (m *MockFeatureFlagRepository) FindAll(ctx context.Context) ([]entity.FeatureFlag, error) {
+	m.FindCalls++
+	var result []entity.FeatureFlag
+	for _, f := range m.Flags {
+		result = append(result, *f)
+	}
+	return result, nil
+}
+
+func (m *MockFeatureFlagRepository) FindByKey(ctx context.Context, key string) (*entity.FeatureFlag, error) {
+	f, ok := m.Flags[key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *f
+	return &copied, nil
+}
+
+func (m *MockFeatureFlagRepository) Create(ctx context.Context, f *entity.FeatureFlag) error {
+	m.Flags[f.Key] = f
+	return nil
+}
+
+func (m *MockFeatureFlagRepository) Update(ctx context.Context, f *entity.FeatureFlag) error {
+	m.Flags[f.Key] = f
+	return nil
+}
+
+func (m *MockFeatureFlagRepository) Delete(ctx context.Context, key string) error {
+	delete(m.Flags, key)
+	return nil
+}
+
+// MockSettingRepository is a mock implementation of repository.SettingRepository.
+type MockSettingRepository struct {
+	Settings  map[string]*entity.Setting // keyed by setting key
+	Changes   []*entity.SettingChange            // in the order they were applied
+	Overrides map[string]*entity.SettingOverride // keyed by setting key, scope and scope ID
+}
+
+func NewMockSettingRepository(settings ...*entity.Setting) *MockSettingRepository {
+	m := &MockSettingRepository{
+		Settings:  make(map[string]*entity.Setting),
+		Overrides: make(map[string]*entity.SettingOverride),
+	}
+	for _, s := range settings {
+		m.Settings[s.Key] = s
+	}
+	return m
+}
+
+func (m *MockSettingRepository) GetAll(ctx context.Context) ([]*entity.Setting, error) {
+	var result []*entity.Setting
+	for _, s := range m.Settings {
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (m *MockSettingRepository) GetByCategory(ctx context.Context, category string) ([]*entity.Setting, error) {
+	var result []*entity.Setting
+	for _, s := range m.Settings {
+		if string(s.Category) == category {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockSettingRepository) GetByKey(ctx context.Context, key string) (*entity.Setting, error) {
+	return m.Settings[key], nil
+}
+
+func (m *MockSettingRepository) GetByID(ctx context.Context, id string) (*entity.Setting, error) {
+	for _, s := range m.Settings {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockSettingRepository) Update(ctx context.Context, key string, value string) error {
+	if s, ok := m.Settings[key]; ok {
+		s.Value = &value
+	}
+	return nil
+}
+
+func (m *MockSettingRepository) UpdateByID(ctx context.Context, id string, value string) error {
+	s, _ := m.GetByID(ctx, id)
+	if s != nil {
+		s.Value = &value
+	}
+	return nil
+}
+
+func (m *MockSettingRepository) BulkUpdate(ctx context.Context, settings map[string]string) error {
+	for key, value := range settings {
+		_ = m.Update(ctx, key, value)
+	}
+	return nil
+}
+
+func (m *MockSettingRepository) GetValue(ctx context.Context, key string) (string, error) {
+	if s, ok := m.Settings[key]; ok && s.Value != nil {
+		return *s.Value, nil
+	}
+	return "", nil
+}
+
+func (m *MockSettingRepository) GetCategories(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockSettingRepository) ApplyChanges(ctx context.Context, changes []*entity.SettingChange) error {
+	for _, ch := range changes {
+		s, ok := m.Settings[ch.SettingKey]
+		if !ok {
+			return errors.New("setting not found: " + ch.SettingKey)
+		}
+		if ch.Scope == "" || ch.Scope == entity.SettingScopeGlobal {
+			ch.OldValue = s.Value
+			value := *ch.NewValue
+			s.Value = &value
+		} else {
+			k := overrideKey(ch.SettingKey, ch.Scope, *ch.ScopeID)
+			ch.OldValue = nil
+			if o, ok := m.Overrides[k]; ok {
+				old := o.Value
+				ch.OldValue = &old
+			}
+			if ch.NewValue == nil {
+				delete(m.Overrides, k)
+			} else {
+				m.Overrides[k] = &entity.SettingOverride{
+					ID: ch.ID, SettingKey: ch.SettingKey, Scope: ch.Scope, ScopeID: *ch.ScopeID, Value: *ch.NewValue,
+				}
+			}
+		}
+		m.Changes = append(m.Changes, ch)
+	}
+	return nil
+}
+
+func (m *MockSettingRepository) FindOverrides(ctx context.Context, key string) ([]entity.SettingOverride, error) {
+	var result []entity.SettingOverride
+	for _, o := range m.Overrides {
+		if o.SettingKey == key {
+			result = append(result, *o)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockSettingRepository) FindOverride(ctx context.Context, key string, scope entity.SettingScope, scopeID string) (*entity.SettingOverride, error) {
+	if o, ok := m.Overrides[overrideKey(key, scope, scopeID)]; ok {
+		c := *o
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func overrideKey(key string, scope entity.SettingScope, scopeID string) string {
+	return key + "|" + string(scope) + "|" + scopeID
+}
+
+func (m *MockSettingRepository) FindChanges(ctx context.Context, key string, limit int) ([]entity.SettingChange, error) {
+	var result []entity.SettingChange
+	for i := len(m.Changes) - 1; i >= 0 && len(result) < limit; i-- {
+		if m.Changes[i].SettingKey == key {
+			result = append(result, *m.Changes[i])
+		}
+	}
+	return result, nil
+}
+
+func (m *MockSettingRepository) FindChangeByID(ctx context.Context, id string) (*entity.SettingChange, error) {
+	for _, ch := range m.Changes {
+		if ch.ID == id {
+			c := *ch
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// MockContratoRepository is a mock implementation of repository.ContratoRepository.
+type MockContratoRepository struct {
+	Contratos map[string]*entity.Contrato // keyed by ID
+}
+
+func NewMockContratoRepository(contratos ...*entity.Contrato) *MockContratoRepository {
+	m := &MockContratoRepository{Contratos: make(map[string]*entity.Contrato)}
+	for _, c := range contratos {
+		m.Contratos[c.ID] = c
+	}
+	return m
+}
+
+func (m *MockContratoRepository) FindAll(ctx context.Context) ([]entity.Contrato, error) {
+	var result []entity.Contrato
+	for _, c := range m.Contratos {
+		if c.DeletedAt == nil {
+			result = append(result, *c)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockContratoRepository) FindByID(ctx context.Context, id string) (*entity.Contrato, error) {
+	if c, ok := m.Contratos[id]; ok && c.DeletedAt == nil {
+		return c, nil
+	}
+	return nil, nil
+}
+
+func (m *MockContratoRepository) FindByGestorID(ctx context.Context, gestorID string) ([]entity.Contrato, error) {
+	var result []entity.Contrato
+	for _, c := range m.Contratos {
+		if c.GestorID == gestorID && c.DeletedAt == nil {
+			result = append(result, *c)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockContratoRepository) FindAllWithGestor(ctx context.Context) ([]entity.ContratoWithGestor, error) {
+	var result []entity.ContratoWithGestor
+	for _, c := range m.Contratos {
+		if c.DeletedAt == nil {
+			result = append(result, entity.ContratoWithGestor{Contrato: *c})
+		}
+	}
+	return result, nil
+}
+
+func (m *MockContratoRepository) Create(ctx context.Context, contrato *entity.Contrato) error {
+	m.Contratos[contrato.ID] = contrato
+	return nil
+}
+
+func (m *MockContratoRepository) Update(ctx context.Context, contrato *entity.Contrato) error {
+	m.Contratos[contrato.ID] = contrato
+	return nil
+}
+
+func (m *MockContratoRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, contrato *entity.Contrato) error {
+	return m.Update(ctx, contrato)
+}
+
+func (m *MockContratoRepository) Delete(ctx context.Context, id string) error {
+	if c, ok := m.Contratos[id]; ok && c.DeletedAt == nil {
+		now := time.Now()
+		c.DeletedAt = &now
+	}
+	return nil
+}
+
+func (m *MockContratoRepository) FindDeleted(ctx context.Context) ([]entity.Contrato, error) {
+	var result []entity.Contrato
+	for _, c := range m.Contratos {
+		if c.DeletedAt != nil {
+			result = append(result, *c)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockContratoRepository) Restore(ctx context.Context, id string) (bool, error) {
+	c, ok := m.Contratos[id]
+	if !ok || c.DeletedAt == nil {
+		return false, nil
+	}
+	c.DeletedAt = nil
+	return true, nil
+}
+
+func (m *MockContratoRepository) CountByGestorID(ctx context.Context, gestorID string) (int, error) {
+	list, _ := m.FindByGestorID(ctx, gestorID)
+	return len(list), nil
+}
+
+// MockAIUsageRepository is a mock implementation of repository.AIUsageRepository.
+type MockAIUsageRepository struct {
+	Records []*entity.AIUsage
+}
+
+func NewMockAIUsageRepository() *MockAIUsageRepository {
+	return &MockAIUsageRepository{}
+}
+
+func (m *MockAIUsageRepository) Create(ctx context.Context, u *entity.AIUsage) error {
+	m.Records = append(m.Records, u)
+	return nil
+}
+
+func (m *MockAIUsageRepository) Summarize(ctx context.Context, filters entity.AIUsageFilters) ([]entity.AIUsageSummary, error) {
+	var result []entity.AIUsageSummary
+	index := map[string]int{}
+	for _, u := range m.Records {
+		if filters.Feature != "" && u.Feature != filters.Feature {
+			continue
+		}
+		key := u.Provider + "/" + u.Model
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			result = append(result, entity.AIUsageSummary{Provider: u.Provider, Model: u.Model})
+		}
+		result[i].Calls++
+		if !u.Success {
+			result[i].Failures++
+		}
+		result[i].InputTokens += int64(u.InputTokens)
+		result[i].OutputTokens += int64(u.OutputTokens)
+	}
+	return result, nil
+}
+
+// MockSystemImageRepository is a mock implementation of repository.SystemImageRepository.
+type MockSystemImageRepository struct {
+	Images    map[string]*entity.SystemImage // keyed by slot ID
+	FindCalls int                            // number of FindAll calls
+}
+
+func NewMockSystemImageRepository(images ...*entity.SystemImage) *MockSystemImageRepository {
+	m := &MockSystemImageRepository{Images: make(map[string]*entity.SystemImage)}
+	for _, img := range images {
+		m.Images[img.ID] = img
+	}
+	return m
+}
+
+func (m *MockSystemImageRepository) FindAll(ctx context.Context) ([]entity.SystemImage, error) {
+	m.FindCalls++
+	var result []entity.SystemImage
+	for _, img := range m.Images {
+		result = append(result, *img)
+	}
+	return result, nil
+}
+
+func (m *MockSystemImageRepository) FindByID(ctx context.Context, id string) (*entity.SystemImage, error) {
+	img, ok := m.Images[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *img
+	return &copied, nil
+}
+
+func (m *MockSystemImageRepository) FindByFilename(ctx context.Context, filename string) (*entity.SystemImage, error) {
+	for _, img := range m.Images {
+		if img.Filename == filename {
+			copied := *img
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockSystemImageRepository) Create(ctx context.Context, img *entity.SystemImage) error {
+	m.Images[img.ID] = img
+	return nil
+}
+
+func (m *MockSystemImageRepository) Update(ctx context.Context, img *entity.SystemImage) error {
+	m.Images[img.ID] = img
+	return nil
+}
+
+func (m *MockSystemImageRepository) Delete(ctx context.Context, id string) error {
+	delete(m.Images, id)
+	return nil
+}
+
+// MockNotificacaoRepository is a mock implementation of repository.NotificacaoRepository.
+type MockNotificacaoRepository struct {
+	Notifications map[string]*entity.Notificacao // keyed by ID
+	Preferences   map[string]map[string]bool     // keyed by user ID, then type
+	CountCalls    int                            // number of CountUnread calls
+}
+
+func NewMockNotificacaoRepository() *MockNotificacaoRepository {
+	return &MockNotificacaoRepository{
+		Notifications: make(map[string]*entity.Notificacao),
+		Preferences:   make(map[string]map[string]bool),
+	}
+}
+
+func (m *MockNotificacaoRepository) FindByID(ctx context.Context, id string) (*entity.Notificacao, error) {
+	n, ok := m.Notifications[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *n
+	return &copied, nil
+}
+
+func (m *MockNotificacaoRepository) FindByUserID(ctx context.Context, userID string) ([]entity.Notificacao, error) {
+	var result []entity.Notificacao
+	for _, n := range m.Notifications {
+		if n.UserID == userID {
+			result = append(result, *n)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockNotificacaoRepository) FindUnreadByUserID(ctx context.Context, userID string) ([]entity.Notificacao, error) {
+	var result []entity.Notificacao
+	for _, n := range m.Notifications {
+		if n.UserID == userID && !n.Read {
+			result = append(result, *n)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockNotificacaoRepository) Create(ctx context.Context, notif *entity.Notificacao) error {
+	m.Notifications[notif.ID] = notif
+	return nil
+}
+
+func (m *MockNotificacaoRepository) MarkAsRead(ctx context.Context, id string) error {
+	if n, ok := m.Notifications[id]; ok {
+		n.Read = true
+	}
+	return nil
+}
+
+func (m *MockNotificacaoRepository) MarkAllAsRead(ctx context.Context, userID string) error {
+	for _, n := range m.Notifications {
+		if n.UserID == userID {
+			n.Read = true
+		}
+	}
+	return nil
+}
+
+func (m *MockNotificacaoRepository) Delete(ctx context.Context, id string) error {
+	delete(m.Notifications, id)
+	return nil
+}
+
+func (m *MockNotificacaoRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	m.CountCalls++
+	count := 0
+	for _, n := range m.Notifications {
+		if n.UserID == userID && !n.Read {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockNotificacaoRepository) FindPreferences(ctx context.Context, userID string) ([]entity.NotificationPreference, error) {
+	var result []entity.NotificationPreference
+	for t, enabled := range m.Preferences[userID] {
+		result = append(result, entity.NotificationPreference{UserID: userID, Type: t, Enabled: enabled})
+	}
+	return result, nil
+}
+
+func (m *MockNotificacaoRepository) SavePreferences(ctx context.Context, userID string, prefs []entity.NotificationPreference) error {
+	if m.Preferences[userID] == nil {
+		m.Preferences[userID] = make(map[string]bool)
+	}
+	for _, p := range prefs {
+		m.Preferences[userID][p.Type] = p.Enabled
+	}
+	return nil
+}
+
+// MockMatriculaRepository is a mock implementation of repository.MatriculaRepository.
+type MockMatriculaRepository struct {
+	Enrollments map[string]*entity.Matricula // keyed by ID
+}
+
+func NewMockMatriculaRepository() *MockMatriculaRepository {
+	return &MockMatriculaRepository{Enrollments: make(map[string]*entity.Matricula)}
+}
+
+func (m *MockMatriculaRepository) FindAll(ctx context.Context, page, perPage int, query listquery.Query) ([]entity.Matricula, int, error) {
+	var result []entity.Matricula
+	for _, e := range m.Enrollments {
+		result = append(result, *e)
+	}
+	return result, len(result), nil
+}
+
+func (m *MockMatriculaRepository) Search(ctx context.Context, filter *entity.EnrollmentSearchFilter) ([]entity.Matricula, int, error) {
+	return m.FindAll(ctx, filter.Page, filter.PerPage, listquery.Query{})
+}
+
+func (m *MockMatriculaRepository) FindByID(ctx context.Context, id string) (*entity.Matricula, error) {
+	e, ok := m.Enrollments[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (m *MockMatriculaRepository) FindByIDForUpdateWithTx(ctx context.Context, tx *sqlx.Tx, id string) (*entity.Matricula, error) {
+	return m.FindByID(ctx, id)
+}
+
+func (m *MockMatriculaRepository) FindByStudentID(ctx context.Context, studentID string) ([]entity.Matricula, error) {
+	var result []entity.Matricula
+	for _, e := range m.Enrollments {
+		if e.StudentID == studentID {
+			result = append(result, *e)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockMatriculaRepository) FindByCourseID(ctx context.Context, courseID string) ([]entity.Matricula, error) {
+	var result []entity.Matricula
+	for _, e := range m.Enrollments {
+		if e.CourseID == courseID {
+			result = append(result, *e)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockMatriculaRepository) FindByAsaasPaymentID(ctx context.Context, asaasPaymentID string) (*entity.Matricula, error) {
+	for _, e := range m.Enrollments {
+		if e.AsaasPaymentID != nil && *e.AsaasPaymentID == asaasPaymentID {
+			copied := *e
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockMatriculaRepository) Create(ctx context.Context, e *entity.Matricula) error {
+	m.Enrollments[e.ID] = e
+	return nil
+}
+
+func (m *MockMatriculaRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, e *entity.Matricula) error {
+	return m.Create(ctx, e)
+}
+
+func (m *MockMatriculaRepository) Update(ctx context.Context, e *entity.Matricula) error {
+	m.Enrollments[e.ID] = e
+	return nil
+}
+
+func (m *MockMatriculaRepository) UpdateWithTx(ctx context.Context, tx *sqlx.Tx, e *entity.Matricula) error {
+	return m.Update(ctx, e)
+}
+
+func (m *MockMatriculaRepository) UpdatePaymentStatus(ctx context.Context, id, paymentStatus string) error {
+	if e, ok := m.Enrollments[id]; ok {
+		e.PaymentStatus = paymentStatus
+	}
+	return nil
+}
+
+func (m *MockMatriculaRepository) UpdatePaymentStatusWithTx(ctx context.Context, tx *sqlx.Tx, id, paymentStatus string) error {
+	return m.UpdatePaymentStatus(ctx, id, paymentStatus)
+}
+
+func (m *MockMatriculaRepository) ClaimCancellationWithTx(ctx context.Context, tx *sqlx.Tx, id string) (bool, error) {
+	e, ok := m.Enrollments[id]
+	if !ok || e.Status == entity.EnrollmentStatusCancelled || e.Status == entity.EnrollmentStatusCancelling {
+		return false, nil
+	}
+	e.Status = entity.EnrollmentStatusCancelling
+	return true, nil
+}
+
+func (m *MockMatriculaRepository) ClaimRetry(ctx context.Context, id string) (bool, error) {
+	e, ok := m.Enrollments[id]
+	if !ok || (e.Status != entity.EnrollmentStatusPending && e.Status != entity.EnrollmentStatusExpired) {
+		return false, nil
+	}
+	switch e.PaymentStatus {
+	case entity.PaymentStatusPending, entity.PaymentStatusOverdue, entity.PaymentStatusFailed:
+	default:
+		return false, nil
+	}
+	e.Status = entity.EnrollmentStatusRetrying
+	return true, nil
+}
+
+func (m *MockMatriculaRepository) ReleaseRetry(ctx context.Context, id, status string) error {
+	if e, ok := m.Enrollments[id]; ok && e.Status == entity.EnrollmentStatusRetrying {
+		e.Status = status
+	}
+	return nil
+}
+
+func (m *MockMatriculaRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	if e, ok := m.Enrollments[id]; ok {
+		e.Status = status
+	}
+	return nil
+}
+
+func (m *MockMatriculaRepository) UpdateProgress(ctx context.Context, id string, progress float64) error {
+	if e, ok := m.Enrollments[id]; ok {
+		e.Progress = progress
+	}
+	return nil
+}
+
+func (m *MockMatriculaRepository) Delete(ctx context.Context, id string) error {
+	delete(m.Enrollments, id)
+	return nil
+}
+
+func (m *MockMatriculaRepository) CountByStudentID(ctx context.Context, studentID string) (int, error) {
+	result, _ := m.FindByStudentID(ctx, studentID)
+	return len(result), nil
+}
+
+func (m *MockMatriculaRepository) CountByCourseID(ctx context.Context, courseID string) (int, error) {
+	result, _ := m.FindByCourseID(ctx, courseID)
+	return len(result), nil
+}
+
+// MockEnrollmentRenewalRepository is a mock implementation of repository.EnrollmentRenewalRepository.
+type MockEnrollmentRenewalRepository struct {
+	Renewals map[string]*entity.EnrollmentRenewal // keyed by ID
+}
+
+func NewMockEnrollmentRenewalRepository() *MockEnrollmentRenewalRepository {
+	return &MockEnrollmentRenewalRepository{Renewals: make(map[string]*entity.EnrollmentRenewal)}
+}
+
+func (m *MockEnrollmentRenewalRepository) FindByPaymentID(ctx context.Context, paymentID string) (*entity.EnrollmentRenewal, error) {
+	for _, r := range m.Renewals {
+		if r.PaymentID == paymentID {
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockEnrollmentRenewalRepository) FindByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.EnrollmentRenewal, error) {
+	var result []entity.EnrollmentRenewal
+	for _, r := range m.Renewals {
+		if r.EnrollmentID == enrollmentID {
+			result = append(result, *r)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockEnrollmentRenewalRepository) FindPendingByEnrollmentID(ctx context.Context, enrollmentID string) (*entity.EnrollmentRenewal, error) {
+	for _, r := range m.Renewals {
+		if r.EnrollmentID == enrollmentID && r.Status == entity.RenewalStatusPending {
+			copied := *r
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockEnrollmentRenewalRepository) FindPendingByEnrollmentIDWithTx(ctx context.Context, tx *sqlx.Tx, enrollmentID string) (*entity.EnrollmentRenewal, error) {
+	return m.FindPendingByEnrollmentID(ctx, enrollmentID)
+}
+
+func (m *MockEnrollmentRenewalRepository) CreateWithTx(ctx context.Context, tx *sqlx.Tx, renewal *entity.EnrollmentRenewal) error {
+	m.Renewals[renewal.ID] = renewal
+	return nil
+}
+
+func (m *MockEnrollmentRenewalRepository) ConfirmWithTx(ctx context.Context, tx *sqlx.Tx, renewal *entity.EnrollmentRenewal) error {
+	m.Renewals[renewal.ID] = renewal
+	return nil
+}
+
+func (m *MockEnrollmentRenewalRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	if r, ok := m.Renewals[id]; ok {
+		r.Status = status
+	}
+	return nil
+}
+
+func (m *MockEnrollmentRenewalRepository) UpdatePaymentID(ctx context.Context, id, paymentID string) error {
+	if r, ok := m.Renewals[id]; ok {
+		r.PaymentID = paymentID
+	}
+	return nil
+}
+
+func (m *MockEnrollmentRenewalRepository) UpdatePaymentIDWithTx(ctx context.Context, tx *sqlx.Tx, id, paymentID string) error {
+	return m.UpdatePaymentID(ctx, id, paymentID)
+}
+
+// MockContractBillingRepository is a mock implementation of repository.ContractBillingRepository.
+type MockContractBillingRepository struct {
+	Plans   map[string]*entity.ContractBillingPlan // keyed by contract ID
+	Charges map[string]*entity.ContractCharge      // keyed by ID
+}
+
+func NewMockContractBillingRepository(plans ...*entity.ContractBillingPlan) *MockContractBillingRepository {
+	m := &MockContractBillingRepository{
+		Plans:   make(map[string]*entity.ContractBillingPlan),
+		Charges: make(map[string]*entity.ContractCharge),
+	}
+	for _, p := range plans {
+		m.Plans[p.ContratoID] = p
+	}
+	return m
+}
+
+func (m *MockContractBillingRepository) FindPlanByContratoID(ctx context.Context, contratoID string) (*entity.ContractBillingPlan, error) {
+	if p, ok := m.Plans[contratoID]; ok {
+		copied := *p
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockContractBillingRepository) FindActivePlans(ctx context.Context) ([]entity.ContractBillingPlan, error) {
+	var result []entity.ContractBillingPlan
+	for _, p := range m.Plans {
+		if p.Active {
+			result = append(result, *p)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockContractBillingRepository) SavePlan(ctx context.Context, plan *entity.ContractBillingPlan) error {
+	copied := *plan
+	m.Plans[plan.ContratoID] = &copied
+	return nil
+}
+
+func (m *MockContractBillingRepository) UpdatePlanCustomer(ctx context.Context, id, gateway, customerID string) error {
+	for _, p := range m.Plans {
+		if p.ID == id {
+			p.Gateway = &gateway
+			p.GatewayCustomerID = &customerID
+		}
+	}
+	return nil
+}
+
+func (m *MockContractBillingRepository) FindCharges(ctx context.Context, filters entity.ContractChargeFilters) ([]entity.ContractCharge, error) {
+	var result []entity.ContractCharge
+	for _, c := range m.Charges {
+		if filters.ContratoID != "" && c.ContratoID != filters.ContratoID {
+			continue
+		}
+		if filters.Status != "" && c.Status != filters.Status {
+			continue
+		}
+		result = append(result, *c)
+	}
+	return result, nil
+}
+
+func (m *MockContractBillingRepository) FindChargeByID(ctx context.Context, id string) (*entity.ContractCharge, error) {
+	if c, ok := m.Charges[id]; ok {
+		copied := *c
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockContractBillingRepository) FindChargeByPeriod(ctx context.Context, contratoID, period string) (*entity.ContractCharge, error) {
+	for _, c := range m.Charges {
+		if c.ContratoID == contratoID && c.Period == period {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockContractBillingRepository) FindChargeByGatewayPaymentID(ctx context.Context, gateway, gatewayPaymentID string) (*entity.ContractCharge, error) {
+	for _, c := range m.Charges {
+		if c.Gateway != nil && *c.Gateway == gateway && c.GatewayPaymentID != nil && *c.GatewayPaymentID == gatewayPaymentID {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockContractBillingRepository) CreateCharge(ctx context.Context, charge *entity.ContractCharge) (bool, error) {
+	if existing, _ := m.FindChargeByPeriod(ctx, charge.ContratoID, charge.Period); existing != nil {
+		return false, nil
+	}
+	copied := *charge
+	m.Charges[charge.ID] = &copied
+	return true, nil
+}
+
+func (m *MockContractBillingRepository) ClaimFailedCharge(ctx context.Context, id string) (bool, error) {
+	c, ok := m.Charges[id]
+	if !ok || c.Status != entity.ContractChargeStatusFailed {
+		return false, nil
+	}
+	c.Status = entity.ContractChargeStatusPending
+	return true, nil
+}
+
+func (m *MockContractBillingRepository) UpdateCharge(ctx context.Context, charge *entity.ContractCharge) error {
+	copied := *charge
+	m.Charges[charge.ID] = &copied
+	return nil
+}
+
+func (m *MockContractBillingRepository) MarkOverdue(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	day := before.Format("2006-01-02")
+	for _, c := range m.Charges {
+		if c.Status == entity.ContractChargeStatusOpen && c.DueDate.Format("2006-01-02") < day {
+			c.Status = entity.ContractChargeStatusOverdue
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *MockContractBillingRepository) FindReceivables(ctx context.Context, now time.Time) ([]entity.ContractReceivable, error) {
+	return nil, nil
+}
+
+// MockUserRepository is a mock implementation of repository.UserRepository.
+type MockUserRepository struct {
+	Users map[string]*entity.User // keyed by ID
+}
+
+func NewMockUserRepository(users ...*entity.User) *MockUserRepository {
+	m := &MockUserRepository{Users: make(map[string]*entity.User)}
+	for _, u := range users {
+		m.Users[u.ID] = u
+	}
+	return m
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*entity.User, error) {
+	u, ok := m.Users[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *u
+	return &copied, nil
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, u := range m.Users {
+		if u.Email == email {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockUserRepository) FindAll(ctx context.Context) ([]entity.User, error) {
+	var result []entity.User
+	for _, u := range m.Users {
+		if u.IsActive {
+			result = append(result, *u)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockUserRepository) FindAllWithFilters(ctx context.Context, filters repository.UserFilters) ([]entity.User, error) {
+	var result []entity.User
+	for _, u := range m.Users {
+		if filters.Role != nil && u.Role != *filters.Role {
+			continue
+		}
+		if filters.IsActive != nil && u.IsActive != *filters.IsActive {
+			continue
+		}
+		result = append(result, *u)
+	}
+	return result, nil
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *entity.User) error {
+	m.Users[user.ID] = user
+	return nil
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *entity.User) error {
+	m.Users[user.ID] = user
+	return nil
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
+	if u, ok := m.Users[id]; ok {
+		u.IsActive = false
+	}
+	return nil
+}
+
+func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, id string) error {
+	if u, ok := m.Users[id]; ok {
+		now := time.Now()
+		u.LastLoginAt = &now
+	}
+	return nil
+}
+
+func (m *MockUserRepository) UpdatePassword(ctx context.Context, id string, passwordHash string) error {
+	if u, ok := m.Users[id]; ok {
+		u.PasswordHash = passwordHash
+	}
+	return nil
+}
+
+func (m *MockUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	u, _ := m.FindByEmail(ctx, email)
+	return u != nil, nil
+}
+
+func (m *MockUserRepository) Count(ctx context.Context) (int64, error) {
+	users, _ := m.FindAll(ctx)
+	return int64(len(users)), nil
+}
+
+// MockRefreshTokenRepository is a mock implementation of repository.RefreshTokenRepository.
+type MockRefreshTokenRepository struct {
+	Tokens map[string]*entity.RefreshToken // keyed by ID
+}
+
+func NewMockRefreshTokenRepository() *MockRefreshTokenRepository {
+	return &MockRefreshTokenRepository{Tokens: make(map[string]*entity.RefreshToken)}
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *entity.RefreshToken) error {
+	copied := *token
+	m.Tokens[token.ID] = &copied
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	for _, t := range m.Tokens {
+		if t.TokenHash == tokenHash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id string, replacedBy *string) (bool, error) {
+	t, ok := m.Tokens[id]
+	if !ok || t.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.RevokedAt = &now
+	t.ReplacedBy = replacedBy
+	return true, nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	now := time.Now()
+	for _, t := range m.Tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUser(ctx context.Context, userID string) error {
+	now := time.Now()
+	for _, t := range m.Tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockRefreshTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	var n int64
+	for id, t := range m.Tokens {
+		if t.ExpiresAt.Before(time.Now()) {
+			delete(m.Tokens, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// MockNotificationDeliveryRepository is a mock implementation of repository.NotificationDeliveryRepository.
+type MockNotificationDeliveryRepository struct {
+	Deliveries   map[string]*entity.NotificationDelivery // keyed by ID
+	ChannelPrefs map[string]map[string]bool              // keyed by user ID, then channel
+}
+
+func NewMockNotificationDeliveryRepository() *MockNotificationDeliveryRepository {
+	return &MockNotificationDeliveryRepository{
+		Deliveries:   make(map[string]*entity.NotificationDelivery),
+		ChannelPrefs: make(map[string]map[string]bool),
+	}
+}
+
+func (m *MockNotificationDeliveryRepository) CreateDeliveries(ctx context.Context, deliveries []entity.NotificationDelivery) error {
+	for _, d := range deliveries {
+		d := d
+		m.Deliveries[d.ID] = &d
+	}
+	return nil
+}
+
+func (m *MockNotificationDeliveryRepository) ClaimDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]entity.NotificationDelivery, error) {
+	var result []entity.NotificationDelivery
+	for _, d := range m.Deliveries {
+		if len(result) == limit {
+			break
+		}
+		due := d.Status == entity.NotificationDeliveryPending && !d.NextAttemptAt.After(now)
+		stale := d.Status == entity.NotificationDeliverySending && d.UpdatedAt != nil && d.UpdatedAt.Before(staleBefore)
+		if due || stale {
+			d.Status = entity.NotificationDeliverySending
+			d.UpdatedAt = &now
+			result = append(result, *d)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockNotificationDeliveryRepository) UpdateDelivery(ctx context.Context, d *entity.NotificationDelivery) error {
+	copied := *d
+	m.Deliveries[d.ID] = &copied
+	return nil
+}
+
+func (m *MockNotificationDeliveryRepository) FindByNotificationID(ctx context.Context, notificationID string) ([]entity.NotificationDelivery, error) {
+	var result []entity.NotificationDelivery
+	for _, d := range m.Deliveries {
+		if d.NotificationID == notificationID {
+			result = append(result, *d)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockNotificationDeliveryRepository) MarkDelivered(ctx context.Context, notificationID, channel string, at time.Time) error {
+	for _, d := range m.Deliveries {
+		if d.NotificationID == notificationID && d.Channel == channel && d.DeliveredAt == nil {
+			d.DeliveredAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *MockNotificationDeliveryRepository) MarkOpened(ctx context.Context, notificationID, channel string, at time.Time) error {
+	for _, d := range m.Deliveries {
+		if d.NotificationID == notificationID && d.Channel == channel {
+			if d.OpenedAt == nil {
+				d.OpenedAt = &at
+			}
+			if d.DeliveredAt == nil {
+				d.DeliveredAt = &at
+			}
+		}
+	}
+	return nil
+}
+
+func (m *MockNotificationDeliveryRepository) FindChannelPreferences(ctx context.Context, userID string) ([]entity.NotificationChannelPreference, error) {
+	var result []entity.NotificationChannelPreference
+	for ch, enabled := range m.ChannelPrefs[userID] {
+		result = append(result, entity.NotificationChannelPreference{UserID: userID, Channel: ch, Enabled: enabled})
+	}
+	return result, nil
+}
+
+func (m *MockNotificationDeliveryRepository) SaveChannelPreferences(ctx context.Context, userID string, prefs []entity.NotificationChannelPreference) error {
+	if m.ChannelPrefs[userID] == nil {
+		m.ChannelPrefs[userID] = make(map[string]bool)
+	}
+	for _, p := range prefs {
+		m.ChannelPrefs[userID][p.Channel] = p.Enabled
+	}
+	return nil
+}
+
+// MockPushSubscriptionRepository is a mock implementation of repository.PushSubscriptionRepository.
+type MockPushSubscriptionRepository struct {
+	Subscriptions map[string]*entity.PushSubscription // keyed by ID
+}
+
+func NewMockPushSubscriptionRepository() *MockPushSubscriptionRepository {
+	return &MockPushSubscriptionRepository{Subscriptions: make(map[string]*entity.PushSubscription)}
+}
+
+func (m *MockPushSubscriptionRepository) Save(ctx context.Context, sub *entity.PushSubscription) error {
+	for _, s := range m.Subscriptions {
+		if s.Endpoint == sub.Endpoint {
+			sub.ID = s.ID
+		}
+	}
+	copied := *sub
+	m.Subscriptions[sub.ID] = &copied
+	return nil
+}
+
+func (m *MockPushSubscriptionRepository) FindByUserID(ctx context.Context, userID string) ([]entity.PushSubscription, error) {
+	var result []entity.PushSubscription
+	for _, s := range m.Subscriptions {
+		if s.UserID == userID {
+			result = append(result, *s)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockPushSubscriptionRepository) Delete(ctx context.Context, userID, id string) (bool, error) {
+	s, ok := m.Subscriptions[id]
+	if !ok || s.UserID != userID {
+		return false, nil
+	}
+	delete(m.Subscriptions, id)
+	return true, nil
+}
+
+func (m *MockPushSubscriptionRepository) DeleteByEndpoint(ctx context.Context, endpoint string) error {
+	for id, s := range m.Subscriptions {
+		if s.Endpoint == endpoint {
+			delete(m.Subscriptions, id)
+		}
+	}
+	return nil
+}
+
+// MockGatewayCustomerRepository is a mock implementation of repository.GatewayCustomerRepository.
+type MockGatewayCustomerRepository struct {
+	Customers map[string]*entity.GatewayCustomer // keyed by gateway + "/" + document
+}
+
+func NewMockGatewayCustomerRepository() *MockGatewayCustomerRepository {
+	return &MockGatewayCustomerRepository{Customers: make(map[string]*entity.GatewayCustomer)}
+}
+
+func (m *MockGatewayCustomerRepository) FindByDocument(ctx context.Context, gateway, document string) (*entity.GatewayCustomer, error) {
+	if c, ok := m.Customers[gateway+"/"+document]; ok {
+		copied := *c
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockGatewayCustomerRepository) Create(ctx context.Context, customer *entity.GatewayCustomer) error {
+	key := customer.Gateway + "/" + customer.Document
+	if _, ok := m.Customers[key]; !ok {
+		copied := *customer
+		m.Customers[key] = &copied
+	}
+	return nil
+}
+
+func (m *MockGatewayCustomerRepository) BackfillFromPayments(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// MockCourseRepository is a mock implementation of repository.CourseRepository.
+type MockCourseRepository struct {
+	Courses map[string]*entity.Course // keyed by ID
+}
+
+func NewMockCourseRepository(courses ...*entity.Course) *MockCourseRepository {
+	m := &MockCourseRepository{Courses: make(map[string]*entity.Course)}
+	for _, c := range courses {
+		m.Courses[c.ID] = c
+	}
+	return m
+}
+
+func (m *MockCourseRepository) FindAll(ctx context.Context, page, perPage int) ([]entity.Course, int, error) {
+	var result []entity.Course
+	for _, c := range m.Courses {
+		result = append(result, *c)
+	}
+	return result, len(result), nil
+}
+
+func (m *MockCourseRepository) FindByID(ctx context.Context, id string) (*entity.Course, error) {
+	if c, ok := m.Courses[id]; ok {
+		copied := *c
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *MockCourseRepository) FindByInstructor(ctx context.Context, instructorID string) ([]entity.Course, error) {
+	var result []entity.Course
+	for _, c := range m.Courses {
+		if c.InstructorID != nil && *c.InstructorID == instructorID {
+			result = append(result, *c)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockCourseRepository) FindActive(ctx context.Context, page, perPage int) ([]entity.Course, int, error) {
+	var result []entity.Course
+	for _, c := range m.Courses {
+		if c.IsActive {
+			result = append(result, *c)
+		}
+	}
+	return result, len(result), nil
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *entity.Course) error {
+	copied := *course
+	m.Courses[course.ID] = &copied
+	return nil
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *entity.Course) error {
+	return m.Create(ctx, course)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id string) error {
+	delete(m.Courses, id)
+	return nil
+}
+
+func (m *MockCourseRepository) CountByInstructor(ctx context.Context, instructorID string) (int, error) {
+	result, _ := m.FindByInstructor(ctx, instructorID)
+	return len(result), nil
+}
+
+// MockCertificadoRepository is a mock implementation of repository.CertificadoRepository.
+type MockCertificadoRepository struct {
+	Certificates map[string]*entity.Certificado // keyed by ID
+}
+
+func NewMockCertificadoRepository() *MockCertificadoRepository {
+	return &MockCertificadoRepository{Certificates: make(map[string]*entity.Certificado)}
+}
+
+func (m *MockCertificadoRepository) find(match func(*entity.Certificado) bool) *entity.Certificado {
+	for _, c := range m.Certificates {
+		if match(c) {
+			copied := *c
+			return &copied
+		}
+	}
+	return nil
+}
+
+func (m *MockCertificadoRepository) FindByID(ctx context.Context, id string) (*entity.Certificado, error) {
+	return m.find(func(c *entity.Certificado) bool { return c.ID == id }), nil
+}
+
+func (m *MockCertificadoRepository) FindByValidationCode(ctx context.Context, code string) (*entity.Certificado, error) {
+	return m.find(func(c *entity.Certificado) bool { return c.ValidationCode == code }), nil
+}
+
+func (m *MockCertificadoRepository) FindByStudentID(ctx context.Context, studentID string) ([]entity.Certificado, error) {
+	var result []entity.Certificado
+	for _, c := range m.Certificates {
+		if c.StudentID == studentID {
+			result = append(result, *c)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockCertificadoRepository) FindByEnrollmentID(ctx context.Context, enrollmentID string) (*entity.Certificado, error) {
+	return m.find(func(c *entity.Certificado) bool { return c.EnrollmentID == enrollmentID }), nil
+}
+
+func (m *MockCertificadoRepository) Create(ctx context.Context, cert *entity.Certificado) error {
+	copied := *cert
+	m.Certificates[cert.ID] = &copied
+	return nil
+}
+
+func (m *MockCertificadoRepository) Update(ctx context.Context, cert *entity.Certificado) error {
+	return m.Create(ctx, cert)
+}
+
+func (m *MockCertificadoRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	if c, ok := m.Certificates[id]; ok {
+		c.Status = status
+	}
+	return nil
+}
+
+func (m *MockCertificadoRepository) Delete(ctx context.Context, id string) error {
+	delete(m.Certificates, id)
+	return nil
+}
+
+func (m *MockCertificadoRepository) Exists(ctx context.Context, enrollmentID string) (bool, error) {
+	c, _ := m.FindByEnrollmentID(ctx, enrollmentID)
+	return c != nil, nil
+}
+
+// MockCourseRatingRepository is a mock implementation of repository.CourseRatingRepository.
+type MockCourseRatingRepository struct {
+	Ratings map[string]*entity.CourseRating // keyed by course ID and student ID
+}
+
+func NewMockCourseRatingRepository(ratings ...*entity.CourseRating) *MockCourseRatingRepository {
+	m := &MockCourseRatingRepository{Ratings: make(map[string]*entity.CourseRating)}
+	for _, r := range ratings {
+		m.Ratings[r.CourseID+"/"+r.StudentID] = r
+	}
+	return m
+}
+
+func (m *MockCourseRatingRepository) Save(ctx context.Context, rating *entity.CourseRating) error {
+	copied := *rating
+	m.Ratings[rating.CourseID+"/"+rating.StudentID] = &copied
+	return nil
+}
+
+func (m *MockCourseRatingRepository) FindByStudent(ctx context.Context, courseID, studentID string) (*entity.CourseRating, error) {
+	r, ok := m.Ratings[courseID+"/"+studentID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (m *MockCourseRatingRepository) Summaries(ctx context.Context) ([]entity.CourseRatingSummary, error) {
+	byCourse := make(map[string]*entity.CourseRatingSummary)
+	var order []string
+	for _, r := range m.Ratings {
+		s, ok := byCourse[r.CourseID]
+		if !ok {
+			s = &entity.CourseRatingSummary{CourseID: r.CourseID}
+			byCourse[r.CourseID] = s
+			order = append(order, r.CourseID)
+		}
+		s.Average = (s.Average*float64(s.Count) + float64(r.Rating)) / float64(s.Count+1)
+		s.Count++
+	}
+	result := make([]entity.CourseRatingSummary, 0, len(order))
+	for _, id := range order {
+		result = append(result, *byCourse[id])
+	}
+	return result, nil
+}
+
+// MockCheckoutSessionRepository is a mock implementation of repository.CheckoutSessionRepository.
+type MockCheckoutSessionRepository struct {
+	Sessions map[string]*entity.CheckoutSession
+}
+
+func NewMockCheckoutSessionRepository(sessions ...*entity.CheckoutSession) *MockCheckoutSessionRepository {
+	m := &MockCheckoutSessionRepository{Sessions: make(map[string]*entity.CheckoutSession)}
+	for _, s := range sessions {
+		m.Sessions[s.ID] = s
+	}
+	return m
+}
+
+func (m *MockCheckoutSessionRepository) Create(ctx context.Context, session *entity.CheckoutSession) error {
+	copied := *session
+	m.Sessions[session.ID] = &copied
+	return nil
+}
+
+func (m *MockCheckoutSessionRepository) FindByID(ctx context.Context, id string) (*entity.CheckoutSession, error) {
+	s, ok := m.Sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *s
+	return &copied, nil
+}
+
+func (m *MockCheckoutSessionRepository) Claim(ctx context.Context, id string, now time.Time) (bool, error) {
+	s, ok := m.Sessions[id]
+	if !ok || s.Status != entity.CheckoutSessionStatusOpen || !now.Before(s.ExpiresAt) {
+		return false, nil
+	}
+	s.Status = entity.CheckoutSessionStatusProcessing
+	return true, nil
+}
+
+func (m *MockCheckoutSessionRepository) Release(ctx context.Context, id string) error {
+	if s, ok := m.Sessions[id]; ok && s.Status == entity.CheckoutSessionStatusProcessing {
+		s.Status = entity.CheckoutSessionStatusOpen
+	}
+	return nil
+}
+
+func (m *MockCheckoutSessionRepository) Complete(ctx context.Context, id, enrollmentID string) error {
+	if s, ok := m.Sessions[id]; ok && s.Status == entity.CheckoutSessionStatusProcessing {
+		now := time.Now()
+		s.Status = entity.CheckoutSessionStatusCompleted
+		s.EnrollmentID = &enrollmentID
+		s.CompletedAt = &now
+	}
+	return nil
+}
+
+func (m *MockCheckoutSessionRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	for id, s := range m.Sessions {
+		if s.Status != entity.CheckoutSessionStatusCompleted && s.ExpiresAt.Before(before) {
+			delete(m.Sessions, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// MockInstructorPayoutAccountRepository is a mock implementation of repository.InstructorPayoutAccountRepository.
+type MockInstructorPayoutAccountRepository struct {
+	Accounts map[string]*entity.InstructorPayoutAccount // keyed by instructor ID
+}
+
+func NewMockInstructorPayoutAccountRepository(accounts ...*entity.InstructorPayoutAccount) *MockInstructorPayoutAccountRepository {
+	m := &MockInstructorPayoutAccountRepository{Accounts: make(map[string]*entity.InstructorPayoutAccount)}
+	for _, a := range accounts {
+		m.Accounts[a.InstructorID] = a
+	}
+	return m
+}
+
+func (m *MockInstructorPayoutAccountRepository) FindByInstructorID(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error) {
+	a, ok := m.Accounts[instructorID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *a
+	return &copied, nil
+}
+
+func (m *MockInstructorPayoutAccountRepository) Save(ctx context.Context, account *entity.InstructorPayoutAccount) error {
+	copied := *account
+	m.Accounts[account.InstructorID] = &copied
+	return nil
+}
+
+func (m *MockInstructorPayoutAccountRepository) UpdateVerification(ctx context.Context, account *entity.InstructorPayoutAccount, attempts int) (bool, error) {
+	a, ok := m.Accounts[account.InstructorID]
+	if !ok || a.VerificationAttempts != attempts {
+		return false, nil
+	}
+	copied := *account
+	m.Accounts[account.InstructorID] = &copied
+	return true, nil
+}
+
+// MockGestorRepository is a mock implementation of repository.GestorRepository.
+// Reassign records its calls and returns ReassignResult.
+type MockGestorRepository struct {
+	Gestores       map[string]*entity.Gestor // keyed by ID
+	Reassigned     [][2]string               // from, to
+	ReassignResult *entity.GestorReassignment
+}
+
+func NewMockGestorRepository(gestores ...*entity.Gestor) *MockGestorRepository {
+	m := &MockGestorRepository{Gestores: make(map[string]*entity.Gestor)}
+	for _, g := range gestores {
+		m.Gestores[g.ID] = g
+	}
+	return m
+}
+
+func (m *MockGestorRepository) FindAll(ctx context.Context) ([]entity.Gestor, error) {
+	var result []entity.Gestor
+	for _, g := range m.Gestores {
+		if g.DeletedAt == nil && g.Ativo {
+			result = append(result, *g)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockGestorRepository) FindByID(ctx context.Context, id string) (*entity.Gestor, error) {
+	g, ok := m.Gestores[id]
+	if !ok || g.DeletedAt != nil {
+		return nil, nil
+	}
+	copied := *g
+	return &copied, nil
+}
+
+func (m *MockGestorRepository) FindByEmail(ctx context.Context, email string) (*entity.Gestor, error) {
+	for _, g := range m.Gestores {
+		if g.Email == email {
+			copied := *g
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockGestorRepository) FindByUserID(ctx context.Context, userID string) (*entity.Gestor, error) {
+	for _, g := range m.Gestores {
+		if g.UserID != nil && *g.UserID == userID {
+			copied := *g
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockGestorRepository) FindAllWithContracts(ctx context.Context) ([]entity.GestorWithContracts, error) {
+	return nil, nil
+}
+
+func (m *MockGestorRepository) Create(ctx context.Context, gestor *entity.Gestor) error {
+	m.Gestores[gestor.ID] = gestor
+	return nil
+}
+
+func (m *MockGestorRepository) Update(ctx context.Context, gestor *entity.Gestor) error {
+	m.Gestores[gestor.ID] = gestor
+	return nil
+}
+
+func (m *MockGestorRepository) Delete(ctx context.Context, id string) error {
+	if g, ok := m.Gestores[id]; ok && g.DeletedAt == nil {
+		now := time.Now()
+		g.DeletedAt = &now
+	}
+	return nil
+}
+
+func (m *MockGestorRepository) FindDeleted(ctx context.Context) ([]entity.Gestor, error) {
+	var result []entity.Gestor
+	for _, g := range m.Gestores {
+		if g.DeletedAt != nil {
+			result = append(result, *g)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockGestorRepository) Restore(ctx context.Context, id string) (bool, error) {
+	g, ok := m.Gestores[id]
+	if !ok || g.DeletedAt == nil {
+		return false, nil
+	}
+	g.DeletedAt = nil
+	return true, nil
+}
+
+func (m *MockGestorRepository) FindContractMetrics(ctx context.Context, gestorID string, from, to, now time.Time) ([]entity.GestorContractMetrics, error) {
+	return nil, nil
+}
+
+func (m *MockGestorRepository) Reassign(ctx context.Context, fromID, toID string) (*entity.GestorReassignment, error) {
+	m.Reassigned = append(m.Reassigned, [2]string{fromID, toID})
+	if m.ReassignResult != nil {
+		return m.ReassignResult, nil
+	}
+	return &entity.GestorReassignment{FromGestorID: fromID, ToGestorID: toID}, nil
+}
@@ -0,0 +1,298 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxExportRange limits on-demand exports to roughly one year
+	maxExportRange = 366 * 24 * time.Hour
+
+	// maxSyncEntries caps a single sync run; the next run picks up the rest
+	maxSyncEntries = 5000
+
+	// syncLookback re-examines entries before the cursor, since payout batches can be marked
+	// paid with an earlier date and gateways report payment dates without time
+	syncLookback = 31 * 24 * time.Hour
+
+	// maxRuns is the size of the sync history
+	maxRuns = 100
+)
+
+// UseCase defines the accounting export use case interface
+type UseCase interface {
+	// ListEntries returns the accounting entries of a period (YYYY-MM-DD, both inclusive)
+	ListEntries(ctx context.Context, from, to string) ([]entity.AccountingEntry, error)
+
+	// Export renders the entries of a period in an accounting system layout
+	Export(ctx context.Context, layout, from, to string) (*entity.AccountingExport, error)
+
+	// Sync records the entries not yet exported to a layout as a new run; returns nil when there is nothing new
+	Sync(ctx context.Context, layout, userID string) (*entity.AccountingSyncRun, error)
+
+	// ListRuns returns the sync history, optionally of a single layout
+	ListRuns(ctx context.Context, layout string) ([]entity.AccountingSyncRun, error)
+
+	// DownloadRun renders the entries of a sync run again
+	DownloadRun(ctx context.Context, id string) (*entity.AccountingExport, error)
+
+	// ListCursors returns the sync cursor of each layout
+	ListCursors(ctx context.Context) ([]entity.AccountingSyncCursor, error)
+
+	// RequestClose queues the double-entry export of a closed month (YYYY-MM) in csv or sped
+	RequestClose(ctx context.Context, period, format, userID string) (*entity.AccountingCloseJob, error)
+
+	// ListCloseJobs returns the period close exports, newest first
+	ListCloseJobs(ctx context.Context) ([]entity.AccountingCloseJob, error)
+
+	// GetCloseJob returns a period close export, with a presigned download URL once it is done
+	GetCloseJob(ctx context.Context, id string) (*entity.AccountingCloseJob, error)
+
+	// StartCloseWorker generates the queued period close exports every interval until shutdown
+	StartCloseWorker(lc *lifecycle.Manager, interval time.Duration)
+}
+
+type accountingUseCase struct {
+	repo       repository.AccountingRepository
+	storage    *storage.StorageService
+	db         *database.MySQL
+	bucket     string
+	taxPercent float64
+}
+
+// NewUseCase creates a new accounting export use case
+func NewUseCase(
+	repo repository.AccountingRepository,
+	storageService *storage.StorageService,
+	db *database.MySQL,
+	cfg *config.Config,
+) UseCase {
+	return &accountingUseCase{
+		repo:       repo,
+		storage:    storageService,
+		db:         db,
+		bucket:     cfg.MinioBucketAccounting,
+		taxPercent: cfg.AccountingTaxPercent,
+	}
+}
+
+// ListEntries returns the accounting entries of a period
+func (uc *accountingUseCase) ListEntries(ctx context.Context, from, to string) ([]entity.AccountingEntry, error) {
+	start, end, err := parsePeriod(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.FindEntries(ctx, start, end)
+}
+
+// Export renders the entries of a period without touching the sync cursor, so it can be
+// used to rebuild closed months in the accounting system.
+func (uc *accountingUseCase) Export(ctx context.Context, layout, from, to string) (*entity.AccountingExport, error) {
+	if !entity.IsValidAccountingLayout(layout) {
+		return nil, errors.New("invalid layout: use contaazul or omie")
+	}
+
+	start, end, err := parsePeriod(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.repo.FindEntries(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderLayout(layout, entries)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.AccountingExport{
+		FileName:   fmt.Sprintf("%s_%s_%s.csv", layout, start.Format("20060102"), end.AddDate(0, 0, -1).Format("20060102")),
+		Content:    content,
+		EntryCount: len(entries),
+	}, nil
+}
+
+// Sync exports every entry that was never exported to the layout. Entries are recorded
+// per layout, so running it again (or concurrently) never exports an entry twice; the
+// cursor only narrows the search window.
+func (uc *accountingUseCase) Sync(ctx context.Context, layout, userID string) (*entity.AccountingSyncRun, error) {
+	if !entity.IsValidAccountingLayout(layout) {
+		return nil, errors.New("invalid layout: use contaazul or omie")
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor, err := uc.repo.LockCursorWithTx(ctx, tx, layout)
+	if err != nil {
+		return nil, err
+	}
+	if cursor == nil {
+		return nil, errors.New("accounting sync cursor not found")
+	}
+
+	entries, err := uc.repo.FindUnexportedEntries(ctx, layout, syncWindowStart(cursor), maxSyncEntries)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	run := &entity.AccountingSyncRun{
+		ID:          uuid.New().String(),
+		Layout:      layout,
+		EntryCount:  len(entries),
+		TotalAmount: netAmount(entries),
+		CreatedAt:   time.Now(),
+	}
+	if userID != "" {
+		run.CreatedBy = &userID
+	}
+
+	if err := uc.repo.CreateRunWithTx(ctx, tx, run, entryKeys(entries)); err != nil {
+		return nil, err
+	}
+
+	cursor.LastOccurredAt = latestOccurrence(cursor.LastOccurredAt, entries)
+	cursor.LastRunID = &run.ID
+	if err := uc.repo.UpdateCursorWithTx(ctx, tx, cursor); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// ListRuns returns the sync history, newest first
+func (uc *accountingUseCase) ListRuns(ctx context.Context, layout string) ([]entity.AccountingSyncRun, error) {
+	if layout != "" && !entity.IsValidAccountingLayout(layout) {
+		return nil, errors.New("invalid layout: use contaazul or omie")
+	}
+	return uc.repo.FindRuns(ctx, layout, maxRuns)
+}
+
+// DownloadRun renders the entries of a sync run in its layout. Amounts reflect the sources
+// as they are now, but the set of entries is exactly the one the run exported.
+func (uc *accountingUseCase) DownloadRun(ctx context.Context, id string) (*entity.AccountingExport, error) {
+	run, err := uc.repo.FindRunByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, errors.New("accounting sync run not found")
+	}
+
+	entries, err := uc.repo.FindRunEntries(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderLayout(run.Layout, entries)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.AccountingExport{
+		FileName:   fmt.Sprintf("%s_sync_%s.csv", run.Layout, run.CreatedAt.Format("20060102_150405")),
+		Content:    content,
+		EntryCount: len(entries),
+	}, nil
+}
+
+// ListCursors returns the sync cursor of each layout
+func (uc *accountingUseCase) ListCursors(ctx context.Context) ([]entity.AccountingSyncCursor, error) {
+	return uc.repo.FindCursors(ctx)
+}
+
+// parsePeriod turns inclusive YYYY-MM-DD dates into a [start, end) range.
+// Missing dates default to the current month.
+func parsePeriod(from, to string, now time.Time) (time.Time, time.Time, error) {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from: use YYYY-MM-DD")
+		}
+		start = t
+	}
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to: use YYYY-MM-DD")
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, errors.New("invalid period: to must not be before from")
+	}
+	if end.Sub(start) > maxExportRange {
+		return time.Time{}, time.Time{}, errors.New("invalid period: at most one year")
+	}
+	return start, end, nil
+}
+
+// syncWindowStart is where the search for unexported entries begins
+func syncWindowStart(cursor *entity.AccountingSyncCursor) time.Time {
+	if cursor.LastOccurredAt == nil {
+		return time.Time{}
+	}
+	return cursor.LastOccurredAt.Add(-syncLookback)
+}
+
+// latestOccurrence advances the cursor to the newest exported entry; it never moves back
+func latestOccurrence(current *time.Time, entries []entity.AccountingEntry) *time.Time {
+	latest := current
+	for i := range entries {
+		if latest == nil || entries[i].OccurredAt.After(*latest) {
+			t := entries[i].OccurredAt
+			latest = &t
+		}
+	}
+	return latest
+}
+
+// netAmount is the cash effect of the entries: revenue minus fees, refunds and payouts
+func netAmount(entries []entity.AccountingEntry) float64 {
+	var total float64
+	for i := range entries {
+		if entries[i].IsExpense() {
+			total -= entries[i].Amount
+		} else {
+			total += entries[i].Amount
+		}
+	}
+	return roundCents(total)
+}
+
+func entryKeys(entries []entity.AccountingEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for i := range entries {
+		keys = append(keys, entries[i].Key)
+	}
+	return keys
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,186 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// Chart of accounts of the period close journal. Every movement goes through the gateway
+// bank account, where payments settle and payouts leave.
+var (
+	accountBank       = entity.LedgerAccount{Code: "1.1.1.02", Name: "Bancos conta movimento - gateways", Nature: "01"}
+	accountTaxPayable = entity.LedgerAccount{Code: "2.1.3.01", Name: "Impostos a recolher", Nature: "02"}
+	accountRevenue    = entity.LedgerAccount{Code: "3.1.1.01", Name: "Receita de cursos", Nature: "04"}
+	accountSalesTax   = entity.LedgerAccount{Code: "3.2.1.01", Name: "Impostos sobre vendas", Nature: "04"}
+	accountReturns    = entity.LedgerAccount{Code: "3.2.2.01", Name: "Devoluções e estornos de vendas", Nature: "04"}
+	accountPayouts    = entity.LedgerAccount{Code: "4.1.1.01", Name: "Repasses a instrutores", Nature: "04"}
+	accountFees       = entity.LedgerAccount{Code: "4.1.2.01", Name: "Tarifas de gateway", Nature: "04"}
+)
+
+var journalHeader = []string{
+	"Data", "Lançamento", "Tipo", "Conta débito", "Descrição débito", "Conta crédito",
+	"Descrição crédito", "Valor", "Histórico", "Participante", "CPF/CNPJ",
+}
+
+// buildJournal turns the entries into debit/credit postings. With a tax rate, revenue also
+// provisions its taxes and refunds reverse the provision of the amount returned.
+func buildJournal(entries []entity.AccountingEntry, taxPercent float64) []entity.JournalLine {
+	lines := make([]entity.JournalLine, 0, len(entries))
+	for i := range entries {
+		e := &entries[i]
+		line := entity.JournalLine{
+			Key:          e.Key,
+			Type:         e.Type,
+			Date:         e.OccurredAt,
+			Amount:       roundCents(e.Amount),
+			History:      e.Description,
+			Counterparty: deref(e.Counterparty),
+			Document:     deref(e.CounterpartyDocument),
+		}
+		var tax *entity.JournalLine
+		switch e.Type {
+		case entity.AccountingEntryRevenue:
+			line.Debit, line.Credit = accountBank, accountRevenue
+			tax = taxLine(line, "tax:"+e.SourceID, accountSalesTax, accountTaxPayable, "Impostos - ", taxPercent)
+		case entity.AccountingEntryRefund:
+			line.Debit, line.Credit = accountReturns, accountBank
+			tax = taxLine(line, "tax-refund:"+e.SourceID, accountTaxPayable, accountSalesTax, "Reversão de impostos - ", taxPercent)
+		case entity.AccountingEntryGatewayFee:
+			line.Debit, line.Credit = accountFees, accountBank
+		case entity.AccountingEntryInstructorPayout:
+			line.Debit, line.Credit = accountPayouts, accountBank
+		default:
+			continue
+		}
+		lines = append(lines, line)
+		if tax != nil {
+			lines = append(lines, *tax)
+		}
+	}
+	return lines
+}
+
+// taxLine is the tax provision (or its reversal) of a posting; nil without a tax rate
+func taxLine(base entity.JournalLine, key string, debit, credit entity.LedgerAccount, history string, taxPercent float64) *entity.JournalLine {
+	amount := roundCents(base.Amount * taxPercent / 100)
+	if amount <= 0 {
+		return nil
+	}
+	base.Key = key
+	base.Type = entity.AccountingEntryTax
+	base.Debit, base.Credit = debit, credit
+	base.Amount = amount
+	base.History = history + base.History
+	return &base
+}
+
+// journalTotal is the sum of the postings, the same on the debit and credit sides
+func journalTotal(lines []entity.JournalLine) float64 {
+	var total float64
+	for i := range lines {
+		total += lines[i].Amount
+	}
+	return roundCents(total)
+}
+
+// renderJournalCSV writes the journal as a semicolon separated file, one posting per line
+func renderJournalCSV(lines []entity.JournalLine) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(utf8BOM)
+
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+	if err := w.Write(journalHeader); err != nil {
+		return nil, err
+	}
+	for i := range lines {
+		l := &lines[i]
+		record := []string{
+			formatDate(l.Date), l.Key, journalCategory(l.Type), l.Debit.Code, l.Debit.Name,
+			l.Credit.Code, l.Credit.Name, formatAmount(l.Amount), l.History, l.Counterparty, l.Document,
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderSPED writes the chart of accounts used (I050) and one entry (I200) with its debit and
+// credit (I250) per posting, in ISO-8859-1 with CRLF line endings as the ECD requires. It is a
+// fragment to import into the bookkeeping software, which adds the company blocks.
+func renderSPED(lines []entity.JournalLine, periodStart time.Time) []byte {
+	var buf bytes.Buffer
+	write := func(fields ...string) {
+		buf.WriteString("|")
+		for _, f := range fields {
+			buf.WriteString(latin1(strings.ReplaceAll(f, "|", " ")))
+			buf.WriteString("|")
+		}
+		buf.WriteString("\r\n")
+	}
+
+	for _, account := range usedAccounts(lines) {
+		parent := account.Code[:strings.LastIndex(account.Code, ".")]
+		write("I050", spedDate(periodStart), account.Nature, "A", "4", account.Code, parent, account.Name)
+	}
+
+	for i := range lines {
+		l := &lines[i]
+		amount := formatAmount(l.Amount)
+		write("I200", l.Key, spedDate(l.Date), amount, "N")
+		write("I250", l.Debit.Code, "", amount, "D", "", "", l.History, "")
+		write("I250", l.Credit.Code, "", amount, "C", "", "", l.History, "")
+	}
+	return buf.Bytes()
+}
+
+// usedAccounts lists the accounts of the postings in chart order
+func usedAccounts(lines []entity.JournalLine) []entity.LedgerAccount {
+	chart := []entity.LedgerAccount{
+		accountBank, accountTaxPayable, accountRevenue, accountSalesTax, accountReturns, accountPayouts, accountFees,
+	}
+	used := make(map[string]bool)
+	for i := range lines {
+		used[lines[i].Debit.Code] = true
+		used[lines[i].Credit.Code] = true
+	}
+	var accounts []entity.LedgerAccount
+	for _, account := range chart {
+		if used[account.Code] {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts
+}
+
+func journalCategory(entryType string) string {
+	if entryType == entity.AccountingEntryTax {
+		return "Impostos sobre vendas"
+	}
+	return entryCategories[entryType]
+}
+
+func spedDate(t time.Time) string {
+	return t.Format("02012006")
+}
+
+// latin1 encodes the text in ISO-8859-1, replacing characters outside it
+func latin1(s string) string {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xFF {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return string(out)
+}
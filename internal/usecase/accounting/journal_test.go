@@ -0,0 +1,116 @@
+package accounting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func closeEntries() []entity.AccountingEntry {
+	entries := testEntries()
+	entries[0].SourceID = "p1"
+	refundAt := time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC)
+	return append(entries,
+		entity.AccountingEntry{Key: "refund:p2", Type: entity.AccountingEntryRefund, SourceID: "p2", OccurredAt: refundAt,
+			Amount: 200, Description: "Estorno matrícula m2"},
+		entity.AccountingEntry{Key: "payout:b1", Type: entity.AccountingEntryInstructorPayout, SourceID: "b1", OccurredAt: refundAt,
+			Amount: 800, Description: "Repasse lote b1"},
+	)
+}
+
+func TestBuildJournal(t *testing.T) {
+	lines := buildJournal(closeEntries(), 10)
+	if len(lines) != 6 {
+		t.Fatalf("expected 6 postings, got %d", len(lines))
+	}
+
+	revenue, tax := lines[0], lines[1]
+	if revenue.Debit != accountBank || revenue.Credit != accountRevenue || revenue.Amount != 1500.5 {
+		t.Errorf("revenue posting = %+v", revenue)
+	}
+	if tax.Key != "tax:p1" || tax.Debit != accountSalesTax || tax.Credit != accountTaxPayable || tax.Amount != 150.05 {
+		t.Errorf("tax posting = %+v", tax)
+	}
+	if lines[2].Debit != accountFees || lines[2].Credit != accountBank {
+		t.Errorf("fee posting = %+v", lines[2])
+	}
+	reversal := lines[4]
+	if reversal.Key != "tax-refund:p2" || reversal.Debit != accountTaxPayable || reversal.Amount != 20 {
+		t.Errorf("tax reversal = %+v", reversal)
+	}
+	if lines[5].Debit != accountPayouts {
+		t.Errorf("payout posting = %+v", lines[5])
+	}
+	if got := journalTotal(lines); got != 2672.54 {
+		t.Errorf("journalTotal = %v, want 2672.54", got)
+	}
+
+	if got := buildJournal(closeEntries(), 0); len(got) != 4 {
+		t.Errorf("expected no tax postings without a rate, got %d postings", len(got))
+	}
+}
+
+func TestRenderJournalCSV(t *testing.T) {
+	content, err := renderJournalCSV(buildJournal(closeEntries(), 10))
+	if err != nil {
+		t.Fatalf("renderJournalCSV: unexpected error %v", err)
+	}
+
+	lines := strings.Split(strings.TrimPrefix(string(content), utf8BOM), "\n")
+	if !strings.HasPrefix(lines[0], "Data;Lançamento;Tipo;Conta débito;") {
+		t.Errorf("header = %q", lines[0])
+	}
+	want := "05/03/2024;payment:p1;Vendas de cursos;1.1.1.02;Bancos conta movimento - gateways;3.1.1.01;Receita de cursos;1500,50;Matrícula m1;Maria Souza;12345678900"
+	if lines[1] != want {
+		t.Errorf("revenue line = %q, want %q", lines[1], want)
+	}
+	if !strings.Contains(lines[2], ";Impostos sobre vendas;3.2.1.01;") || !strings.Contains(lines[2], ";150,05;Impostos - Matrícula m1;") {
+		t.Errorf("tax line = %q", lines[2])
+	}
+}
+
+func TestRenderSPED(t *testing.T) {
+	entries := closeEntries()[:1]
+	entries[0].Description = "Matrícula | m1"
+	content := string(renderSPED(buildJournal(entries, 0), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+	records := strings.Split(strings.TrimSuffix(content, "\r\n"), "\r\n")
+	want := []string{
+		"|I050|01032024|01|A|4|1.1.1.02|1.1.1|Bancos conta movimento - gateways|",
+		"|I050|01032024|04|A|4|3.1.1.01|3.1.1|Receita de cursos|",
+		"|I200|payment:p1|05032024|1500,50|N|",
+		"|I250|1.1.1.02||1500,50|D|||" + latin1("Matrícula   m1") + "||",
+		"|I250|3.1.1.01||1500,50|C|||" + latin1("Matrícula   m1") + "||",
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %q", len(want), len(records), records)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+	if strings.Contains(content, "í") {
+		t.Error("expected the file in ISO-8859-1, found UTF-8 text")
+	}
+}
+
+func TestParseClosePeriod(t *testing.T) {
+	now := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+
+	start, end, err := parseClosePeriod("2024-03", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !start.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("period = [%v, %v)", start, end)
+	}
+
+	for _, period := range []string{"2024-04", "2024-13", "03/2024", ""} {
+		if _, _, err := parseClosePeriod(period, now); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("parseClosePeriod(%q): expected an invalid period error, got %v", period, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// utf8BOM makes spreadsheet tools open the accented headers correctly
+const utf8BOM = "\xEF\xBB\xBF"
+
+// Categories used by the finance team's chart of accounts
+var entryCategories = map[string]string{
+	entity.AccountingEntryRevenue:          "Vendas de cursos",
+	entity.AccountingEntryGatewayFee:       "Tarifas de gateway",
+	entity.AccountingEntryRefund:           "Estornos",
+	entity.AccountingEntryInstructorPayout: "Repasse a instrutores",
+}
+
+var contaAzulHeader = []string{
+	"Data de competência", "Data de vencimento", "Data de pagamento", "Valor", "Categoria",
+	"Descrição", "Cliente/Fornecedor", "CNPJ/CPF Cliente/Fornecedor", "Observações",
+}
+
+var omieHeader = []string{
+	"Código de Integração", "Tipo", "Data de Emissão", "Data de Vencimento", "Data de Pagamento",
+	"Valor", "Categoria", "Cliente/Fornecedor", "CPF/CNPJ", "Observação",
+}
+
+// renderLayout writes the entries as a semicolon separated file in the import layout of the
+// accounting system. Conta Azul takes expenses as negative values; Omie takes positive values
+// with a receivable/payable type and uses the entry key as integration code, so re-importing
+// the same file updates instead of duplicating.
+func renderLayout(layout string, entries []entity.AccountingEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(utf8BOM)
+
+	w := csv.NewWriter(&buf)
+	w.Comma = ';'
+
+	switch layout {
+	case entity.AccountingLayoutContaAzul:
+		if err := w.Write(contaAzulHeader); err != nil {
+			return nil, err
+		}
+		for i := range entries {
+			e := &entries[i]
+			amount := e.Amount
+			if e.IsExpense() {
+				amount = -amount
+			}
+			date := formatDate(e.OccurredAt)
+			record := []string{
+				date, date, date, formatAmount(amount), entryCategories[e.Type],
+				e.Description, deref(e.Counterparty), deref(e.CounterpartyDocument), e.Key,
+			}
+			if err := w.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	case entity.AccountingLayoutOmie:
+		if err := w.Write(omieHeader); err != nil {
+			return nil, err
+		}
+		for i := range entries {
+			e := &entries[i]
+			kind := "Receber"
+			if e.IsExpense() {
+				kind = "Pagar"
+			}
+			date := formatDate(e.OccurredAt)
+			record := []string{
+				e.Key, kind, date, date, date, formatAmount(e.Amount), entryCategories[e.Type],
+				deref(e.Counterparty), deref(e.CounterpartyDocument), e.Description,
+			}
+			if err := w.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid layout: %s", layout)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatAmount uses the Brazilian decimal comma without thousands separators
+func formatAmount(v float64) string {
+	return strings.Replace(fmt.Sprintf("%.2f", v), ".", ",", 1)
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("02/01/2006")
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
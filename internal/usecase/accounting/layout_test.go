@@ -0,0 +1,106 @@
+package accounting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func testEntries() []entity.AccountingEntry {
+	payer := "Maria Souza"
+	cpf := "12345678900"
+	paidAt := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	return []entity.AccountingEntry{
+		{Key: "payment:p1", Type: entity.AccountingEntryRevenue, OccurredAt: paidAt, Amount: 1500.5,
+			Description: "Matrícula m1", Counterparty: &payer, CounterpartyDocument: &cpf},
+		{Key: "fee:p1", Type: entity.AccountingEntryGatewayFee, OccurredAt: paidAt, Amount: 1.99,
+			Description: "Tarifa asaas - matrícula m1"},
+	}
+}
+
+func TestRenderContaAzul(t *testing.T) {
+	content, err := renderLayout(entity.AccountingLayoutContaAzul, testEntries())
+	if err != nil {
+		t.Fatalf("renderLayout: unexpected error %v", err)
+	}
+
+	lines := strings.Split(strings.TrimPrefix(string(content), utf8BOM), "\n")
+	if !strings.HasPrefix(lines[0], "Data de competência;") {
+		t.Errorf("header = %q", lines[0])
+	}
+	want := "05/03/2024;05/03/2024;05/03/2024;1500,50;Vendas de cursos;Matrícula m1;Maria Souza;12345678900;payment:p1"
+	if lines[1] != want {
+		t.Errorf("revenue line = %q, want %q", lines[1], want)
+	}
+	if !strings.Contains(lines[2], ";-1,99;Tarifas de gateway;") {
+		t.Errorf("fee line = %q, want a negative amount", lines[2])
+	}
+}
+
+func TestRenderOmie(t *testing.T) {
+	content, err := renderLayout(entity.AccountingLayoutOmie, testEntries())
+	if err != nil {
+		t.Fatalf("renderLayout: unexpected error %v", err)
+	}
+
+	lines := strings.Split(strings.TrimPrefix(string(content), utf8BOM), "\n")
+	if !strings.HasPrefix(lines[1], "payment:p1;Receber;05/03/2024;") {
+		t.Errorf("revenue line = %q", lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "fee:p1;Pagar;") || !strings.Contains(lines[2], ";1,99;") {
+		t.Errorf("fee line = %q, want a positive payable", lines[2])
+	}
+
+	if _, err := renderLayout("quickbooks", nil); err == nil {
+		t.Error("renderLayout with an unknown layout: expected error")
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2024, 2, 20, 10, 0, 0, 0, time.UTC)
+
+	start, end, err := parsePeriod("", "", now)
+	if err != nil || !start.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("default period = %v - %v, %v; want February 2024", start, end, err)
+	}
+
+	start, end, err = parsePeriod("2024-01-10", "2024-01-10", now)
+	if err != nil || end.Sub(start) != 24*time.Hour {
+		t.Errorf("single day = %v - %v, %v; want one day", start, end, err)
+	}
+
+	for _, tt := range [][2]string{{"2024-13-01", ""}, {"2024-02-10", "2024-02-01"}, {"2022-01-01", "2024-01-01"}} {
+		if _, _, err := parsePeriod(tt[0], tt[1], now); err == nil {
+			t.Errorf("parsePeriod(%q, %q): expected error", tt[0], tt[1])
+		}
+	}
+}
+
+func TestCursorHelpers(t *testing.T) {
+	entries := testEntries()
+	later := entries[0]
+	later.Key = "refund:p1"
+	later.Type = entity.AccountingEntryRefund
+	later.Amount = 500
+	later.OccurredAt = later.OccurredAt.Add(48 * time.Hour)
+	entries = append(entries, later)
+
+	if got := netAmount(entries); got != 998.51 {
+		t.Errorf("netAmount = %v, want 998.51", got)
+	}
+
+	cursor := latestOccurrence(nil, entries)
+	if cursor == nil || !cursor.Equal(later.OccurredAt) {
+		t.Fatalf("latestOccurrence = %v, want %v", cursor, later.OccurredAt)
+	}
+	newer := later.OccurredAt.Add(time.Hour)
+	if got := latestOccurrence(&newer, entries); !got.Equal(newer) {
+		t.Errorf("latestOccurrence moved the cursor back to %v", got)
+	}
+
+	if start := syncWindowStart(&entity.AccountingSyncCursor{}); !start.IsZero() {
+		t.Errorf("syncWindowStart without cursor = %v, want zero time", start)
+	}
+}
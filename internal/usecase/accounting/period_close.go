@@ -0,0 +1,168 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/google/uuid"
+)
+
+const (
+	// closeJobTimeout is how long a running export may take before another worker claims it again
+	closeJobTimeout = 30 * time.Minute
+
+	// closeDownloadTTL is how long the download link of a finished export is valid
+	closeDownloadTTL = 15 * time.Minute
+
+	// maxCloseError is the size of the error column of a failed export
+	maxCloseError = 500
+)
+
+// RequestClose queues the period close export of a past month (YYYY-MM)
+func (uc *accountingUseCase) RequestClose(ctx context.Context, period, format, userID string) (*entity.AccountingCloseJob, error) {
+	if _, _, err := parseClosePeriod(period, time.Now()); err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = entity.AccountingCloseFormatCSV
+	}
+	if !entity.IsValidAccountingCloseFormat(format) {
+		return nil, errors.New("invalid format: use csv or sped")
+	}
+	if uc.storage == nil {
+		return nil, errors.New("storage service is not available")
+	}
+
+	job := &entity.AccountingCloseJob{
+		ID:        uuid.New().String(),
+		Period:    period,
+		Format:    format,
+		Status:    entity.AccountingCloseStatusPending,
+		CreatedAt: time.Now(),
+	}
+	if userID != "" {
+		job.CreatedBy = &userID
+	}
+	if err := uc.repo.CreateCloseJob(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// ListCloseJobs returns the period close exports, newest first
+func (uc *accountingUseCase) ListCloseJobs(ctx context.Context) ([]entity.AccountingCloseJob, error) {
+	return uc.repo.FindCloseJobs(ctx, maxRuns)
+}
+
+// GetCloseJob returns a period close export; once done it carries a short-lived download URL
+func (uc *accountingUseCase) GetCloseJob(ctx context.Context, id string) (*entity.AccountingCloseJob, error) {
+	job, err := uc.repo.FindCloseJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, errors.New("accounting close job not found")
+	}
+
+	if job.Status == entity.AccountingCloseStatusDone && job.FileKey != nil && uc.storage != nil {
+		url, err := uc.storage.GetPresignedURL(ctx, uc.bucket, *job.FileKey, closeDownloadTTL)
+		if err != nil {
+			return nil, err
+		}
+		job.DownloadURL = url
+	}
+	return job, nil
+}
+
+// StartCloseWorker generates the queued exports every interval until shutdown
+func (uc *accountingUseCase) StartCloseWorker(lc *lifecycle.Manager, interval time.Duration) {
+	lc.Every("accounting period close", interval, true, func(ctx context.Context) {
+		for ctx.Err() == nil {
+			job, err := uc.repo.ClaimCloseJob(ctx, time.Now().Add(-closeJobTimeout))
+			if err != nil {
+				log.Printf("[ACCOUNTING] Failed to claim period close job: %v", err)
+				return
+			}
+			if job == nil {
+				return
+			}
+			uc.runCloseJob(ctx, job)
+		}
+	})
+}
+
+// runCloseJob generates and stores the file of a claimed export, recording the outcome on the job
+func (uc *accountingUseCase) runCloseJob(ctx context.Context, job *entity.AccountingCloseJob) {
+	err := uc.generateClose(ctx, job)
+	now := time.Now()
+	job.FinishedAt = &now
+	if err != nil {
+		log.Printf("[ACCOUNTING] Period close %s (%s) failed: %v", job.Period, job.ID, err)
+		msg := err.Error()
+		if len(msg) > maxCloseError {
+			msg = msg[:maxCloseError]
+		}
+		job.Status = entity.AccountingCloseStatusFailed
+		job.Error = &msg
+	} else {
+		job.Status = entity.AccountingCloseStatusDone
+		job.Error = nil
+	}
+	if err := uc.repo.FinishCloseJob(ctx, job); err != nil {
+		log.Printf("[ACCOUNTING] Failed to save period close job %s: %v", job.ID, err)
+	}
+}
+
+func (uc *accountingUseCase) generateClose(ctx context.Context, job *entity.AccountingCloseJob) error {
+	if uc.storage == nil {
+		return errors.New("storage service is not available")
+	}
+	start, end, err := parseClosePeriod(job.Period, time.Now())
+	if err != nil {
+		return err
+	}
+
+	entries, err := uc.repo.FindEntries(ctx, start, end)
+	if err != nil {
+		return err
+	}
+	lines := buildJournal(entries, uc.taxPercent)
+
+	var content []byte
+	ext, contentType := "csv", "text/csv; charset=utf-8"
+	if job.Format == entity.AccountingCloseFormatSPED {
+		content = renderSPED(lines, start)
+		ext, contentType = "txt", "text/plain; charset=iso-8859-1"
+	} else if content, err = renderJournalCSV(lines); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("closes/%s/%s.%s", job.Period, job.ID, ext)
+	if _, err := uc.storage.UploadFile(ctx, uc.bucket, key, bytes.NewReader(content), int64(len(content)), contentType); err != nil {
+		return err
+	}
+	job.FileKey = &key
+	job.LineCount = len(lines)
+	job.TotalAmount = journalTotal(lines)
+	return nil
+}
+
+// parseClosePeriod turns a YYYY-MM period into its [start, end) range. Only months that
+// already ended can be closed.
+func parseClosePeriod(period string, now time.Time) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid period: use YYYY-MM")
+	}
+	end := start.AddDate(0, 1, 0)
+	if end.After(now) {
+		return time.Time{}, time.Time{}, errors.New("invalid period: the month is not closed yet")
+	}
+	return start, end, nil
+}
@@ -0,0 +1,153 @@
+package activitylog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// apiPrefix is the prefix of the routes whose changes are recorded
+const apiPrefix = "/api/v1/"
+
+// untrackedRoutes are mutating routes that change no data, or whose changes are not made by
+// the caller (sessions, previews, AI prompts)
+var untrackedRoutes = map[string]bool{
+	"/api/v1/auth/login":                   true,
+	"/api/v1/auth/refresh":                 true,
+	"/api/v1/auth/logout":                  true,
+	"/api/v1/coupons/validate":             true,
+	"/api/v1/revenue-splits/rules/preview": true,
+	"/api/v1/portal/ai":                    true,
+	"/api/v1/portal/ai/stream":             true,
+}
+
+var actions = map[string]string{
+	http.MethodPost:   entity.ActivityActionCreate,
+	http.MethodPut:    entity.ActivityActionUpdate,
+	http.MethodPatch:  entity.ActivityActionUpdate,
+	http.MethodDelete: entity.ActivityActionDelete,
+}
+
+// Mutation describes a request that may have changed data, as the ActivityLog middleware
+// saw it
+type Mutation struct {
+	Method    string
+	Route     string // route template, "/api/v1/contratos/:id"
+	Path      string // request path, "/api/v1/contratos/42"
+	Body      string // JSON body with credentials, card data and documents redacted
+	CreatedID string // ID of the record in the response
+	UserID    string
+	UserRole  string
+	IP        string
+	RequestID string
+	Status    int
+}
+
+// UseCase defines the activity log use case interface
+type UseCase interface {
+	Record(ctx context.Context, m Mutation) error
+	List(ctx context.Context, filters entity.ActivityLogFilters) ([]entity.ActivityLog, int, error)
+}
+
+type activityLogUseCase struct {
+	repo repository.ActivityLogRepository
+}
+
+// NewUseCase creates a new activity log use case
+func NewUseCase(repo repository.ActivityLogRepository) UseCase {
+	return &activityLogUseCase{repo: repo}
+}
+
+// Record stores the activity log entry of a mutation. Only changes are recorded: requests
+// that failed, were not authenticated or hit a route that changes nothing are skipped.
+func (uc *activityLogUseCase) Record(ctx context.Context, m Mutation) error {
+	entry, ok := newEntry(m)
+	if !ok {
+		return nil
+	}
+	return uc.repo.Create(ctx, entry)
+}
+
+// List returns the entries matching the filters, newest first, and the total count
+func (uc *activityLogUseCase) List(ctx context.Context, filters entity.ActivityLogFilters) ([]entity.ActivityLog, int, error) {
+	switch filters.Action {
+	case "", entity.ActivityActionCreate, entity.ActivityActionUpdate, entity.ActivityActionDelete:
+	default:
+		return nil, 0, fmt.Errorf("invalid action %q, expected create, update or delete", filters.Action)
+	}
+	logs, total, err := uc.repo.FindAll(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	if logs == nil {
+		logs = []entity.ActivityLog{}
+	}
+	return logs, total, nil
+}
+
+func newEntry(m Mutation) (*entity.ActivityLog, bool) {
+	action, ok := actions[m.Method]
+	if !ok || m.Status >= http.StatusBadRequest || m.UserID == "" || untrackedRoutes[m.Route] {
+		return nil, false
+	}
+	resource, ok := strings.CutPrefix(m.Route, apiPrefix)
+	if !ok || resource == "" {
+		return nil, false
+	}
+
+	entry := &entity.ActivityLog{
+		ID:        uuid.New().String(),
+		UserID:    &m.UserID,
+		Action:    action,
+		Entity:    strings.SplitN(resource, "/", 2)[0],
+		Route:     m.Route,
+		IPAddress: m.IP,
+		CreatedAt: time.Now(),
+	}
+	if id := routeID(m.Route, m.Path); id != "" {
+		entry.EntityID = &id
+	} else if m.CreatedID != "" && action == entity.ActivityActionCreate {
+		entry.EntityID = &m.CreatedID
+	}
+	if m.UserRole != "" {
+		entry.UserRole = &m.UserRole
+	}
+	if m.RequestID != "" {
+		entry.RequestID = &m.RequestID
+	}
+	// Bodies too large or not JSON reach here as a note, which is not a diff
+	if m.Body != "" && json.Valid([]byte(m.Body)) {
+		entry.Diff = json.RawMessage(m.Body)
+	}
+	return entry, true
+}
+
+// routeID returns the value of the :id parameter of the route, or of its first parameter
+// when it has no :id ("/settings/:key")
+func routeID(route, path string) string {
+	routeParts := strings.Split(route, "/")
+	pathParts := strings.Split(path, "/")
+	if len(routeParts) != len(pathParts) {
+		return ""
+	}
+	first := ""
+	for i, part := range routeParts {
+		if !strings.HasPrefix(part, ":") {
+			continue
+		}
+		if part == ":id" {
+			return pathParts[i]
+		}
+		if first == "" {
+			first = pathParts[i]
+		}
+	}
+	return first
+}
@@ -0,0 +1,126 @@
+package activitylog
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+type fakeActivityRepo struct {
+	created []*entity.ActivityLog
+}
+
+func (r *fakeActivityRepo) Create(ctx context.Context, l *entity.ActivityLog) error {
+	r.created = append(r.created, l)
+	return nil
+}
+
+func (r *fakeActivityRepo) FindAll(ctx context.Context, filters entity.ActivityLogFilters) ([]entity.ActivityLog, int, error) {
+	return nil, 0, nil
+}
+
+func TestRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Mutation
+		action   string
+		entity   string
+		entityID string
+		diff     string
+	}{
+		{
+			name:     "update",
+			m:        Mutation{Method: "PUT", Route: "/api/v1/contratos/:id", Path: "/api/v1/contratos/c-1", Body: `{"nome":"Novo"}`, Status: 200},
+			action:   entity.ActivityActionUpdate,
+			entity:   "contratos",
+			entityID: "c-1",
+			diff:     `{"nome":"Novo"}`,
+		},
+		{
+			name:     "sub-resource belongs to the parent",
+			m:        Mutation{Method: "DELETE", Route: "/api/v1/contratos/:id/documents/:docId", Path: "/api/v1/contratos/c-1/documents/d-1", Status: 200},
+			action:   entity.ActivityActionDelete,
+			entity:   "contratos",
+			entityID: "c-1",
+		},
+		{
+			name:     "create takes the ID of the response",
+			m:        Mutation{Method: "POST", Route: "/api/v1/suppliers", Path: "/api/v1/suppliers", Body: `{"name":"Acme"}`, CreatedID: "s-1", Status: 201},
+			action:   entity.ActivityActionCreate,
+			entity:   "suppliers",
+			entityID: "s-1",
+			diff:     `{"name":"Acme"}`,
+		},
+		{
+			name:     "first parameter without :id",
+			m:        Mutation{Method: "PATCH", Route: "/api/v1/settings/:key/overrides/:scope/:scope_id", Path: "/api/v1/settings/fee/overrides/course/x", Status: 200},
+			action:   entity.ActivityActionUpdate,
+			entity:   "settings",
+			entityID: "fee",
+		},
+		{
+			name:   "body that is not JSON",
+			m:      Mutation{Method: "POST", Route: "/api/v1/images", Path: "/api/v1/images", Body: "[multipart body omitted]", Status: 201},
+			action: entity.ActivityActionCreate,
+			entity: "images",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeActivityRepo{}
+			tt.m.UserID = "u-1"
+			tt.m.IP = "10.0.0.1"
+			if err := NewUseCase(repo).Record(context.Background(), tt.m); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(repo.created) != 1 {
+				t.Fatalf("created %d entries, want 1", len(repo.created))
+			}
+			got := repo.created[0]
+			if got.Action != tt.action || got.Entity != tt.entity || *got.UserID != "u-1" || got.IPAddress != "10.0.0.1" {
+				t.Errorf("entry = %+v", got)
+			}
+			entityID := ""
+			if got.EntityID != nil {
+				entityID = *got.EntityID
+			}
+			if entityID != tt.entityID {
+				t.Errorf("entity_id = %q, want %q", entityID, tt.entityID)
+			}
+			if string(got.Diff) != tt.diff {
+				t.Errorf("diff = %s, want %s", got.Diff, tt.diff)
+			}
+		})
+	}
+}
+
+func TestRecord_Skipped(t *testing.T) {
+	tests := map[string]Mutation{
+		"failed":          {Method: "PUT", Route: "/api/v1/contratos/:id", Path: "/api/v1/contratos/c-1", UserID: "u-1", Status: 400},
+		"unauthenticated": {Method: "POST", Route: "/api/v1/checkout", Path: "/api/v1/checkout", Status: 201},
+		"untracked route": {Method: "POST", Route: "/api/v1/auth/logout", Path: "/api/v1/auth/logout", UserID: "u-1", Status: 200},
+		"read":            {Method: "GET", Route: "/api/v1/contratos", Path: "/api/v1/contratos", UserID: "u-1", Status: 200},
+		"outside the api": {Method: "POST", Route: "/backend_integration/api_router.php", Path: "/backend_integration/api_router.php", UserID: "u-1", Status: 200},
+		"unknown route":   {Method: "POST", Path: "/api/v1/missing", UserID: "u-1", Status: 200},
+	}
+	for name, m := range tests {
+		t.Run(name, func(t *testing.T) {
+			repo := &fakeActivityRepo{}
+			if err := NewUseCase(repo).Record(context.Background(), m); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(repo.created) != 0 {
+				t.Errorf("recorded %+v", repo.created[0])
+			}
+		})
+	}
+}
+
+func TestList_InvalidAction(t *testing.T) {
+	_, _, err := NewUseCase(&fakeActivityRepo{}).List(context.Background(), entity.ActivityLogFilters{Action: "read"})
+	if err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+		t.Errorf("err = %v, want invalid action", err)
+	}
+}
@@ -0,0 +1,194 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/ai"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// ErrUnavailable is returned when no AI provider is configured
+var ErrUnavailable = errors.New("AI service not available")
+
+// Call identifies what an AI request is for, for metering
+type Call struct {
+	Feature string
+	UserID  string
+}
+
+// UseCase defines the AI assistant use case interface
+type UseCase interface {
+	// Generate returns the answer of the first provider that succeeds
+	Generate(ctx context.Context, call Call, req ai.Request) (*ai.Response, error)
+
+	// Stream relays the answer of the first provider that succeeds through onChunk
+	Stream(ctx context.Context, call Call, req ai.Request, onChunk func(text string) error) (*ai.Response, error)
+
+	// SetOrder replaces the provider order, a comma separated list of provider names
+	SetOrder(order string)
+
+	// Usage summarizes the metered provider calls
+	Usage(ctx context.Context, filters entity.AIUsageFilters) ([]entity.AIUsageSummary, error)
+}
+
+type assistantUseCase struct {
+	usageRepo repository.AIUsageRepository
+	providers map[string]ai.AIProvider
+	defaults  []string
+
+	mu    sync.RWMutex
+	order []string
+}
+
+// NewUseCase creates a new AI assistant use case. Providers are tried in the given order;
+// an empty order falls back to the order the providers are passed in.
+func NewUseCase(usageRepo repository.AIUsageRepository, order string, providers ...ai.AIProvider) UseCase {
+	uc := &assistantUseCase{
+		usageRepo: usageRepo,
+		providers: make(map[string]ai.AIProvider, len(providers)),
+	}
+	for _, p := range providers {
+		uc.providers[p.Name()] = p
+		uc.defaults = append(uc.defaults, p.Name())
+	}
+	uc.SetOrder(order)
+	return uc
+}
+
+// SetOrder replaces the provider order. Unknown names are ignored; an order naming no
+// known provider restores the default.
+func (uc *assistantUseCase) SetOrder(order string) {
+	var names []string
+	for _, name := range strings.Split(order, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := uc.providers[name]; !ok {
+			continue
+		}
+		duplicate := false
+		for _, n := range names {
+			duplicate = duplicate || n == name
+		}
+		if !duplicate {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		names = uc.defaults
+	}
+
+	uc.mu.Lock()
+	uc.order = names
+	uc.mu.Unlock()
+}
+
+// available returns the configured providers in order
+func (uc *assistantUseCase) available() []ai.AIProvider {
+	uc.mu.RLock()
+	order := uc.order
+	uc.mu.RUnlock()
+
+	var list []ai.AIProvider
+	for _, name := range order {
+		if p := uc.providers[name]; p.Available() {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// Generate tries each available provider in order, falling back to the next one on error
+func (uc *assistantUseCase) Generate(ctx context.Context, call Call, req ai.Request) (*ai.Response, error) {
+	return uc.attempt(ctx, call, func(p ai.AIProvider) (*ai.Response, bool, error) {
+		resp, err := p.Generate(ctx, req)
+		return resp, false, err
+	})
+}
+
+// Stream tries each available provider in order. Once a provider has sent part of the answer
+// its errors are returned as is: falling back would repeat the answer from the start.
+func (uc *assistantUseCase) Stream(ctx context.Context, call Call, req ai.Request, onChunk func(text string) error) (*ai.Response, error) {
+	return uc.attempt(ctx, call, func(p ai.AIProvider) (*ai.Response, bool, error) {
+		sent := false
+		resp, err := p.Stream(ctx, req, func(text string) error {
+			sent = true
+			return onChunk(text)
+		})
+		return resp, sent, err
+	})
+}
+
+// attempt runs fn against the providers until one succeeds, metering every attempt. fn
+// reports whether the attempt already reached the caller, which stops the fallback.
+func (uc *assistantUseCase) attempt(ctx context.Context, call Call, fn func(p ai.AIProvider) (*ai.Response, bool, error)) (*ai.Response, error) {
+	providers := uc.available()
+	if len(providers) == 0 {
+		return nil, ErrUnavailable
+	}
+
+	var lastErr error
+	for _, p := range providers {
+		started := time.Now()
+		resp, committed, err := fn(p)
+		uc.record(ctx, call, p, resp, time.Since(started), err)
+		if err == nil {
+			return resp, nil
+		}
+		if committed || ctx.Err() != nil {
+			return resp, err
+		}
+		log.Printf("[AI] Provider %s failed, trying the next one: %v", p.Name(), err)
+		lastErr = err
+	}
+	return nil, fmt.Errorf("all AI providers failed: %w", lastErr)
+}
+
+// record meters a provider call. It outlives the request context so calls cancelled by the
+// client are still counted.
+func (uc *assistantUseCase) record(ctx context.Context, call Call, p ai.AIProvider, resp *ai.Response, latency time.Duration, err error) {
+	usage := &entity.AIUsage{
+		ID:        uuid.New().String(),
+		Provider:  p.Name(),
+		Model:     p.Model(),
+		Feature:   call.Feature,
+		LatencyMs: latency.Milliseconds(),
+		Success:   err == nil,
+	}
+	if call.UserID != "" {
+		usage.UserID = &call.UserID
+	}
+	if resp != nil {
+		usage.InputTokens = resp.Usage.InputTokens
+		usage.OutputTokens = resp.Usage.OutputTokens
+	}
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		usage.Error = &msg
+	}
+
+	if err := uc.usageRepo.Create(context.WithoutCancel(ctx), usage); err != nil {
+		log.Printf("[AI] Failed to record usage of %s: %v", p.Name(), err)
+	}
+}
+
+// Usage summarizes the metered provider calls
+func (uc *assistantUseCase) Usage(ctx context.Context, filters entity.AIUsageFilters) ([]entity.AIUsageSummary, error) {
+	summary, err := uc.usageRepo.Summarize(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil {
+		summary = []entity.AIUsageSummary{}
+	}
+	return summary, nil
+}
@@ -0,0 +1,111 @@
+package assistant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/ai"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+type fakeProvider struct {
+	name   string
+	key    string
+	chunks []string
+	err    error
+	calls  int
+}
+
+func (p *fakeProvider) Name() string    { return p.name }
+func (p *fakeProvider) Model() string   { return p.name + "-model" }
+func (p *fakeProvider) Available() bool { return p.key != "" }
+
+func (p *fakeProvider) Generate(ctx context.Context, req ai.Request) (*ai.Response, error) {
+	return p.Stream(ctx, req, func(string) error { return nil })
+}
+
+func (p *fakeProvider) Stream(ctx context.Context, req ai.Request, onChunk func(string) error) (*ai.Response, error) {
+	p.calls++
+	resp := &ai.Response{Provider: p.name, Model: p.Model(), Usage: ai.Usage{InputTokens: 10}}
+	for _, c := range p.chunks {
+		resp.Text += c
+		resp.Usage.OutputTokens++
+		if err := onChunk(c); err != nil {
+			return resp, err
+		}
+	}
+	if p.err != nil {
+		return resp, p.err
+	}
+	return resp, nil
+}
+
+func TestGenerate_FallsBackOnError(t *testing.T) {
+	usage := testutil.NewMockAIUsageRepository()
+	gemini := &fakeProvider{name: "gemini", key: "k", err: errors.New("quota exceeded")}
+	openai := &fakeProvider{name: "openai", key: "k", chunks: []string{"olá"}}
+	uc := NewUseCase(usage, "", gemini, openai)
+
+	resp, err := uc.Generate(context.Background(), Call{Feature: "portal", UserID: "u1"}, ai.Request{Prompt: "oi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Provider != "openai" || resp.Text != "olá" {
+		t.Errorf("expected the openai answer, got %+v", resp)
+	}
+	if len(usage.Records) != 2 || usage.Records[0].Success || !usage.Records[1].Success {
+		t.Fatalf("expected a failed and a successful attempt metered, got %+v", usage.Records)
+	}
+	if usage.Records[1].UserID == nil || *usage.Records[1].UserID != "u1" || usage.Records[1].InputTokens != 10 {
+		t.Errorf("unexpected usage record: %+v", usage.Records[1])
+	}
+}
+
+func TestSetOrder_SkipsUnavailable(t *testing.T) {
+	usage := testutil.NewMockAIUsageRepository()
+	gemini := &fakeProvider{name: "gemini", key: "k", chunks: []string{"g"}}
+	openai := &fakeProvider{name: "openai", chunks: []string{"o"}}
+	anthropic := &fakeProvider{name: "anthropic", key: "k", chunks: []string{"a"}}
+	uc := NewUseCase(usage, "openai, anthropic, unknown", gemini, openai, anthropic)
+
+	resp, err := uc.Generate(context.Background(), Call{Feature: "portal"}, ai.Request{Prompt: "oi"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Provider != "anthropic" || gemini.calls != 0 || openai.calls != 0 {
+		t.Errorf("expected only anthropic to be called, got %s (gemini %d, openai %d)", resp.Provider, gemini.calls, openai.calls)
+	}
+
+	uc.SetOrder("")
+	resp, _ = uc.Generate(context.Background(), Call{Feature: "portal"}, ai.Request{Prompt: "oi"})
+	if resp.Provider != "gemini" {
+		t.Errorf("expected the default order to start with gemini, got %s", resp.Provider)
+	}
+}
+
+func TestStream_NoFallbackAfterChunks(t *testing.T) {
+	usage := testutil.NewMockAIUsageRepository()
+	gemini := &fakeProvider{name: "gemini", key: "k", chunks: []string{"par"}, err: errors.New("connection reset")}
+	openai := &fakeProvider{name: "openai", key: "k", chunks: []string{"ok"}}
+	uc := NewUseCase(usage, "", gemini, openai)
+
+	var got string
+	_, err := uc.Stream(context.Background(), Call{Feature: "portal"}, ai.Request{Prompt: "oi"}, func(text string) error {
+		got += text
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected the stream error")
+	}
+	if got != "par" || openai.calls != 0 {
+		t.Errorf("expected no fallback once chunks were sent, got %q (openai %d calls)", got, openai.calls)
+	}
+}
+
+func TestGenerate_Unavailable(t *testing.T) {
+	uc := NewUseCase(testutil.NewMockAIUsageRepository(), "", &fakeProvider{name: "gemini"})
+	if _, err := uc.Generate(context.Background(), Call{}, ai.Request{Prompt: "oi"}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/usecase/assistant"
 	"github.com/google/uuid"
 )
 
@@ -18,20 +19,22 @@ const (
 
 // UseCase defines the audit use case interface
 type UseCase interface {
-	ListAudits(ctx context.Context) ([]entity.Audit, error)
-	ListAuditsWithContract(ctx context.Context) ([]entity.AuditWithContract, error)
-	ListAuditsByContract(ctx context.Context, contractID string) ([]entity.Audit, error)
+	ListAudits(ctx context.Context, filters repository.AuditFilters) ([]entity.AuditWithContract, int, error)
 	GetAuditByID(ctx context.Context, id string) (*entity.Audit, error)
 	GetAuditMeta(ctx context.Context, contractID string) (*entity.AuditMeta, error)
 	CreateAudit(ctx context.Context, req *entity.CreateAuditRequest) (*entity.Audit, error)
 	UpdateAudit(ctx context.Context, id string, req *entity.UpdateAuditRequest) (*entity.Audit, error)
 	DeleteAudit(ctx context.Context, id string) error
+	SummarizeAudit(ctx context.Context, id, userID string, refresh bool) (*entity.AuditAISummary, error)
+	ListDataTemplates() []entity.AuditDataTemplate
+	MigrateDataJSON(ctx context.Context, req *entity.MigrateAuditDataRequest) (*entity.AuditDataMigrationResult, error)
 }
 
 type auditUseCase struct {
 	repo         repository.AuditRepository
 	itemRepo     repository.AuditItemRepository
 	contratoRepo repository.ContratoRepository
+	aiUC         assistant.UseCase
 	db           *database.MySQL
 }
 
@@ -40,29 +43,21 @@ func NewUseCase(
 	repo repository.AuditRepository,
 	itemRepo repository.AuditItemRepository,
 	contratoRepo repository.ContratoRepository,
+	aiUC assistant.UseCase,
 	db *database.MySQL,
 ) UseCase {
 	return &auditUseCase{
 		repo:         repo,
 		itemRepo:     itemRepo,
 		contratoRepo: contratoRepo,
+		aiUC:         aiUC,
 		db:           db,
 	}
 }
 
-// ListAudits returns all audits
-func (uc *auditUseCase) ListAudits(ctx context.Context) ([]entity.Audit, error) {
-	return uc.repo.FindAll(ctx)
-}
-
-// ListAuditsWithContract returns all audits with contract information
-func (uc *auditUseCase) ListAuditsWithContract(ctx context.Context) ([]entity.AuditWithContract, error) {
-	return uc.repo.FindAllWithContract(ctx)
-}
-
-// ListAuditsByContract returns all audits for a specific contract
-func (uc *auditUseCase) ListAuditsByContract(ctx context.Context, contractID string) ([]entity.Audit, error) {
-	return uc.repo.FindByContractID(ctx, contractID)
+// ListAudits returns a page of the audits matching the filters and the total number of matches
+func (uc *auditUseCase) ListAudits(ctx context.Context, filters repository.AuditFilters) ([]entity.AuditWithContract, int, error) {
+	return uc.repo.FindAll(ctx, filters)
 }
 
 // GetAuditByID returns a specific audit by ID
@@ -105,6 +100,11 @@ func (uc *auditUseCase) CreateAudit(ctx context.Context, req *entity.CreateAudit
 		return nil, errors.New("contract not found")
 	}
 
+	dataJSON, _, err := normalizeDataJSON(req.DataJSON)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get previous score if exists
 	var previousScore *float64
 	lastAudit, err := uc.repo.FindLastByContractID(ctx, req.ContractID)
@@ -141,7 +141,7 @@ func (uc *auditUseCase) CreateAudit(ctx context.Context, req *entity.CreateAudit
 		PreviousScore: previousScore,
 		Status:        status,
 		Observations:  req.Observations,
-		DataJSON:      req.DataJSON,
+		DataJSON:      dataJSON,
 		CreatedAt:     time.Now(),
 	}
 
@@ -219,7 +219,11 @@ func (uc *auditUseCase) UpdateAudit(ctx context.Context, id string, req *entity.
 		audit.Observations = req.Observations
 	}
 	if req.DataJSON != nil {
-		audit.DataJSON = req.DataJSON
+		dataJSON, _, err := normalizeDataJSON(req.DataJSON)
+		if err != nil {
+			return nil, err
+		}
+		audit.DataJSON = dataJSON
 	}
 
 	// Recalculate status if score or target changed
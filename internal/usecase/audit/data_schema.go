@@ -0,0 +1,360 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/jsonschema"
+)
+
+// Templates of the audit data_json. A payload names its template and schema version
+// ({"template": "checklist", "schema_version": 2, ...}); one without them is a checklist
+// in the format of the legacy portal, version 1. Payloads in an older version are still
+// accepted and are upgraded to the current version before they are stored.
+const (
+	DataTemplateChecklist  = "checklist"
+	DataTemplateOccurrence = "occurrence"
+)
+
+// legacyChecklistCategory is the section the items of a version 1 checklist are moved to
+const legacyChecklistCategory = "Geral"
+
+const checklistSchemaV1 = `{
+	"type": "object",
+	"description": "Checklist of the legacy portal",
+	"required": ["items"],
+	"additionalProperties": false,
+	"properties": {
+		"template": {"type": "string", "enum": ["checklist"]},
+		"schema_version": {"type": "integer", "enum": [1]},
+		"items": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"required": ["name"],
+				"additionalProperties": false,
+				"properties": {
+					"name": {"type": "string", "minLength": 1, "maxLength": 255},
+					"ok": {"type": "boolean"},
+					"note": {"type": "string", "maxLength": 2000}
+				}
+			}
+		}
+	}
+}`
+
+const checklistSchemaV2 = `{
+	"type": "object",
+	"description": "Checklist of scored items grouped by category",
+	"required": ["template", "schema_version", "sections"],
+	"additionalProperties": false,
+	"properties": {
+		"template": {"type": "string", "enum": ["checklist"]},
+		"schema_version": {"type": "integer", "enum": [2]},
+		"sections": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"required": ["category", "items"],
+				"additionalProperties": false,
+				"properties": {
+					"category": {"type": "string", "minLength": 1, "maxLength": 100},
+					"items": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"required": ["name", "score", "max_score"],
+							"additionalProperties": false,
+							"properties": {
+								"name": {"type": "string", "minLength": 1, "maxLength": 255},
+								"score": {"type": "number", "minimum": 0},
+								"max_score": {"type": "number", "minimum": 0},
+								"observation": {"type": "string", "maxLength": 2000}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+const occurrenceSchemaV1 = `{
+	"type": "object",
+	"description": "Occurrences found during the audit",
+	"required": ["template", "schema_version", "occurrences"],
+	"additionalProperties": false,
+	"properties": {
+		"template": {"type": "string", "enum": ["occurrence"]},
+		"schema_version": {"type": "integer", "enum": [1]},
+		"occurrences": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"required": ["description", "severity"],
+				"additionalProperties": false,
+				"properties": {
+					"description": {"type": "string", "minLength": 1, "maxLength": 2000},
+					"severity": {"type": "string", "enum": ["low", "medium", "high"]},
+					"location": {"type": "string", "maxLength": 255}
+				}
+			}
+		}
+	}
+}`
+
+// dataUpgrade turns a payload of one schema version into the next one
+type dataUpgrade func(doc map[string]interface{}) map[string]interface{}
+
+type dataTemplate struct {
+	name     string
+	schemas  []string      // JSON schema of every version, version 1 first
+	upgrades []dataUpgrade // upgrades[i] turns version i+1 into version i+2
+	parsed   []*jsonschema.Schema
+}
+
+func (t *dataTemplate) current() int {
+	return len(t.schemas)
+}
+
+// dataTemplates are the known templates by name. The schemas are constants, so one that
+// does not parse is a bug and stops the program at startup.
+var dataTemplates = loadDataTemplates(
+	&dataTemplate{
+		name:     DataTemplateChecklist,
+		schemas:  []string{checklistSchemaV1, checklistSchemaV2},
+		upgrades: []dataUpgrade{upgradeChecklistV1},
+	},
+	&dataTemplate{
+		name:    DataTemplateOccurrence,
+		schemas: []string{occurrenceSchemaV1},
+	},
+)
+
+func loadDataTemplates(templates ...*dataTemplate) map[string]*dataTemplate {
+	byName := make(map[string]*dataTemplate, len(templates))
+	for _, t := range templates {
+		if len(t.upgrades) != len(t.schemas)-1 {
+			panic(fmt.Sprintf("audit: template %s needs one upgrade per schema version", t.name))
+		}
+		for i, s := range t.schemas {
+			schema, err := jsonschema.Parse([]byte(s))
+			if err != nil {
+				panic(fmt.Sprintf("audit: schema %s v%d: %v", t.name, i+1, err))
+			}
+			t.parsed = append(t.parsed, schema)
+		}
+		byName[t.name] = t
+	}
+	return byName
+}
+
+// upgradeChecklistV1 moves the items of a legacy checklist to a single section, scoring
+// each item 1 of 1 when it was ok and 0 otherwise
+func upgradeChecklistV1(doc map[string]interface{}) map[string]interface{} {
+	legacy, _ := doc["items"].([]interface{})
+	items := make([]interface{}, 0, len(legacy))
+	for _, raw := range legacy {
+		old, _ := raw.(map[string]interface{})
+		item := map[string]interface{}{
+			"name":      old["name"],
+			"score":     0,
+			"max_score": 1,
+		}
+		if ok, _ := old["ok"].(bool); ok {
+			item["score"] = 1
+		}
+		if note, _ := old["note"].(string); note != "" {
+			item["observation"] = note
+		}
+		items = append(items, item)
+	}
+	return map[string]interface{}{
+		"template":       DataTemplateChecklist,
+		"schema_version": 2,
+		"sections": []interface{}{
+			map[string]interface{}{"category": legacyChecklistCategory, "items": items},
+		},
+	}
+}
+
+// DataJSONError reports a data_json that does not match the schema of its template
+type DataJSONError struct {
+	Errors []jsonschema.Error
+}
+
+func (e *DataJSONError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "invalid data_json: " + strings.Join(msgs, "; ")
+}
+
+func invalidDataJSON(path, format string, args ...interface{}) *DataJSONError {
+	return &DataJSONError{Errors: []jsonschema.Error{{Path: path, Message: fmt.Sprintf(format, args...)}}}
+}
+
+// isEmptyDataJSON reports whether an audit carries no data_json
+func isEmptyDataJSON(data json.RawMessage) bool {
+	switch string(bytes.TrimSpace(data)) {
+	case "", "null", "{}":
+		return true
+	}
+	return false
+}
+
+// normalizeDataJSON validates a data_json against the schema of its template and version
+// and returns it in the current version of the template; upgraded reports whether it was
+// in an older one. Empty payloads are returned as they are.
+func normalizeDataJSON(data json.RawMessage) (out json.RawMessage, upgraded bool, err error) {
+	if isEmptyDataJSON(data) {
+		return data, false, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false, invalidDataJSON("", "must be a JSON object")
+	}
+
+	name := DataTemplateChecklist
+	if v, ok := doc["template"]; ok {
+		s, isString := v.(string)
+		if !isString {
+			return nil, false, invalidDataJSON("template", "must be a string")
+		}
+		name = s
+	}
+	tmpl, ok := dataTemplates[name]
+	if !ok {
+		return nil, false, invalidDataJSON("template", "must be one of %s", strings.Join(dataTemplateNames(), ", "))
+	}
+
+	version := 1
+	if v, ok := doc["schema_version"]; ok {
+		n, isNumber := v.(json.Number)
+		i, convErr := n.Int64()
+		if !isNumber || convErr != nil {
+			return nil, false, invalidDataJSON("schema_version", "must be an integer")
+		}
+		if i < 1 || i > int64(tmpl.current()) {
+			return nil, false, invalidDataJSON("schema_version", "must be between 1 and %d for template %s", tmpl.current(), tmpl.name)
+		}
+		version = int(i)
+	}
+
+	if errs := tmpl.parsed[version-1].Validate(data); len(errs) > 0 {
+		return nil, false, &DataJSONError{Errors: errs}
+	}
+	if version == tmpl.current() {
+		return data, false, nil
+	}
+
+	for v := version; v < tmpl.current(); v++ {
+		doc = tmpl.upgrades[v-1](doc)
+	}
+	out, err = json.Marshal(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	if errs := tmpl.parsed[tmpl.current()-1].Validate(out); len(errs) > 0 {
+		return nil, false, &DataJSONError{Errors: errs}
+	}
+	return out, true, nil
+}
+
+func dataTemplateNames() []string {
+	names := make([]string, 0, len(dataTemplates))
+	for name := range dataTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListDataTemplates returns the data_json templates with the schema of their current version
+func (uc *auditUseCase) ListDataTemplates() []entity.AuditDataTemplate {
+	templates := make([]entity.AuditDataTemplate, 0, len(dataTemplates))
+	for _, name := range dataTemplateNames() {
+		t := dataTemplates[name]
+		versions := make([]int, t.current())
+		for i := range versions {
+			versions[i] = i + 1
+		}
+		templates = append(templates, entity.AuditDataTemplate{
+			Name:           t.name,
+			CurrentVersion: t.current(),
+			Versions:       versions,
+			Schema:         json.RawMessage(t.schemas[t.current()-1]),
+		})
+	}
+	return templates
+}
+
+// dataMigrationPageSize is the number of audits read per query while migrating data_json
+const dataMigrationPageSize = 200
+
+// MigrateDataJSON upgrades the stored data_json of the audits to the current version of
+// their template. Payloads that do not match any version are reported and left unchanged;
+// a dry run only reports what would be upgraded.
+func (uc *auditUseCase) MigrateDataJSON(ctx context.Context, req *entity.MigrateAuditDataRequest) (*entity.AuditDataMigrationResult, error) {
+	result := &entity.AuditDataMigrationResult{DryRun: req.DryRun, Failed: []entity.AuditDataMigrationFailure{}}
+
+	afterID := ""
+	for req.Limit == 0 || result.Scanned < req.Limit {
+		size := dataMigrationPageSize
+		if req.Limit > 0 {
+			size = min(size, req.Limit-result.Scanned)
+		}
+		records, err := uc.repo.FindDataJSON(ctx, afterID, size)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			result.Scanned++
+			afterID = record.AuditID
+			if isEmptyDataJSON(record.DataJSON) {
+				result.Current++
+				continue
+			}
+
+			data, upgraded, err := normalizeDataJSON(record.DataJSON)
+			if err != nil {
+				var invalid *DataJSONError
+				if !errors.As(err, &invalid) {
+					return nil, err
+				}
+				failure := entity.AuditDataMigrationFailure{AuditID: record.AuditID}
+				for _, e := range invalid.Errors {
+					failure.Errors = append(failure.Errors, e.Error())
+				}
+				result.Failed = append(result.Failed, failure)
+				continue
+			}
+			if !upgraded {
+				result.Current++
+				continue
+			}
+			if !req.DryRun {
+				if err := uc.repo.UpdateDataJSON(ctx, record.AuditID, data); err != nil {
+					return nil, err
+				}
+			}
+			result.Upgraded++
+		}
+		if len(records) < size {
+			break
+		}
+	}
+	return result, nil
+}
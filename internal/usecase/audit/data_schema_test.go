@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+const legacyChecklist = `{"items": [{"name": "Extintores", "ok": true}, {"name": "Piscina", "ok": false, "note": "Água turva"}]}`
+
+func TestNormalizeDataJSONUpgradesLegacyChecklist(t *testing.T) {
+	out, upgraded, err := normalizeDataJSON(json.RawMessage(legacyChecklist))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !upgraded {
+		t.Error("legacy checklist: expected an upgrade")
+	}
+	want := `{"schema_version":2,"sections":[{"category":"Geral","items":[{"max_score":1,"name":"Extintores","score":1},{"max_score":1,"name":"Piscina","observation":"Água turva","score":0}]}],"template":"checklist"}`
+	if string(out) != want {
+		t.Errorf("upgraded payload =\n%s\nwant\n%s", out, want)
+	}
+
+	again, upgraded, err := normalizeDataJSON(out)
+	if err != nil || upgraded || string(again) != string(out) {
+		t.Errorf("current payload: got %s, upgraded %v, err %v", again, upgraded, err)
+	}
+}
+
+func TestNormalizeDataJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		path string
+	}{
+		{"not an object", `[1, 2]`, ""},
+		{"unknown template", `{"template": "inventory", "schema_version": 1}`, "template"},
+		{"unknown version", `{"template": "checklist", "schema_version": 3}`, "schema_version"},
+		{"fractional version", `{"template": "checklist", "schema_version": 1.5}`, "schema_version"},
+		{"negative score", `{"template": "checklist", "schema_version": 2, "sections": [{"category": "Áreas comuns", "items": [{"name": "Hall", "score": -1, "max_score": 10}]}]}`, "sections[0].items[0].score"},
+		{"legacy item without name", `{"items": [{"ok": true}]}`, "items[0].name"},
+		{"unknown severity", `{"template": "occurrence", "schema_version": 1, "occurrences": [{"description": "Vazamento", "severity": "urgent"}]}`, "occurrences[0].severity"},
+	}
+	for _, tt := range tests {
+		_, _, err := normalizeDataJSON(json.RawMessage(tt.data))
+		var invalid *DataJSONError
+		if !errors.As(err, &invalid) {
+			t.Errorf("%s: err = %v, want a DataJSONError", tt.name, err)
+			continue
+		}
+		if invalid.Errors[0].Path != tt.path {
+			t.Errorf("%s: errors = %v, want one at %q", tt.name, invalid.Errors, tt.path)
+		}
+	}
+
+	for _, empty := range []string{"", "null", "{}"} {
+		if _, _, err := normalizeDataJSON(json.RawMessage(empty)); err != nil {
+			t.Errorf("empty payload %q: unexpected error %v", empty, err)
+		}
+	}
+}
+
+func TestListDataTemplates(t *testing.T) {
+	uc := &auditUseCase{}
+	templates := uc.ListDataTemplates()
+	if len(templates) != 2 || templates[0].Name != DataTemplateChecklist || templates[0].CurrentVersion != 2 {
+		t.Fatalf("templates = %+v", templates)
+	}
+	if len(templates[0].Versions) != 2 || !json.Valid(templates[0].Schema) {
+		t.Errorf("checklist template = %+v", templates[0])
+	}
+}
+
+type fakeAuditRepo struct {
+	repository.AuditRepository
+	records []entity.AuditDataRecord // ordered by ID
+	updated map[string]string
+}
+
+func (r *fakeAuditRepo) FindDataJSON(ctx context.Context, afterID string, limit int) ([]entity.AuditDataRecord, error) {
+	var page []entity.AuditDataRecord
+	for _, rec := range r.records {
+		if rec.AuditID > afterID && len(page) < limit {
+			page = append(page, rec)
+		}
+	}
+	return page, nil
+}
+
+func (r *fakeAuditRepo) UpdateDataJSON(ctx context.Context, id string, data json.RawMessage) error {
+	r.updated[id] = string(data)
+	return nil
+}
+
+func TestMigrateDataJSON(t *testing.T) {
+	current, _, _ := normalizeDataJSON(json.RawMessage(legacyChecklist))
+	repo := &fakeAuditRepo{
+		records: []entity.AuditDataRecord{
+			{AuditID: "a1", DataJSON: json.RawMessage(legacyChecklist)},
+			{AuditID: "a2", DataJSON: current},
+			{AuditID: "a3", DataJSON: json.RawMessage(`{"checklist": "free text"}`)},
+			{AuditID: "a4", DataJSON: json.RawMessage(`{}`)},
+			{AuditID: "a5", DataJSON: json.RawMessage(`{"items": []}`)},
+		},
+		updated: map[string]string{},
+	}
+	uc := &auditUseCase{repo: repo}
+
+	dry, err := uc.MigrateDataJSON(context.Background(), &entity.MigrateAuditDataRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.Scanned != 5 || dry.Upgraded != 2 || dry.Current != 2 || len(dry.Failed) != 1 || len(repo.updated) != 0 {
+		t.Errorf("dry run = %+v, updated %v", dry, repo.updated)
+	}
+	if dry.Failed[0].AuditID != "a3" {
+		t.Errorf("failed = %+v", dry.Failed)
+	}
+
+	limited, err := uc.MigrateDataJSON(context.Background(), &entity.MigrateAuditDataRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if limited.Scanned != 2 || limited.Upgraded != 1 || len(repo.updated) != 1 || repo.updated["a1"] != string(current) {
+		t.Errorf("limited run = %+v, updated %v", limited, repo.updated)
+	}
+}
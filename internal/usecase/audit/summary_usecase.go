@@ -0,0 +1,131 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/ai"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/usecase/assistant"
+)
+
+const summarySystemPrompt = `Você é um consultor sênior de auditorias de facilities e ISO 9001 em condomínios. Responda SEMPRE em PORTUGUÊS DO BRASIL.
+Analise a auditoria recebida e responda APENAS com um objeto JSON, sem texto adicional, no formato:
+{"summary": "resumo executivo em até 3 parágrafos", "corrective_actions": [{"title": "ação curta", "description": "o que fazer e por quê", "priority": "low|medium|high|urgent", "category": "categoria do item"}]}
+Priorize os itens com as piores notas e as observações do auditor. Sugira no máximo 10 ações.`
+
+// maxSummaryDataJSON bounds the raw audit data sent for audits recorded without items
+const maxSummaryDataJSON = 8000
+
+// SummarizeAudit returns the AI executive summary of an audit with suggested corrective
+// actions. The cached summary is returned while the audit data is unchanged, unless refresh
+// is set.
+func (uc *auditUseCase) SummarizeAudit(ctx context.Context, id, userID string, refresh bool) (*entity.AuditAISummary, error) {
+	audit, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if audit == nil {
+		return nil, errors.New("audit not found")
+	}
+
+	items, err := uc.itemRepo.FindByAuditIDWithCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	contractName := audit.ContractID
+	if contrato, err := uc.contratoRepo.FindByID(ctx, audit.ContractID); err == nil && contrato != nil {
+		contractName = contrato.Nome
+	}
+
+	prompt := summaryPrompt(audit, contractName, items)
+	sum := sha256.Sum256([]byte(summarySystemPrompt + prompt))
+	hash := hex.EncodeToString(sum[:])
+
+	if !refresh {
+		cached, err := uc.repo.FindAISummary(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil && cached.InputHash == hash {
+			cached.Cached = true
+			return cached, nil
+		}
+	}
+
+	if uc.aiUC == nil {
+		return nil, assistant.ErrUnavailable
+	}
+	resp, err := uc.aiUC.Generate(ctx, assistant.Call{Feature: "audit_summary", UserID: userID}, ai.Request{
+		System:      summarySystemPrompt,
+		Prompt:      prompt,
+		Temperature: 0.3,
+		MaxTokens:   2048,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &entity.AuditAISummary{}
+	if err := ai.DecodeJSON(resp.Text, summary); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(summary.Summary) == "" {
+		return nil, errors.New("invalid AI response: empty summary")
+	}
+	if summary.CorrectiveActions == nil {
+		summary.CorrectiveActions = []entity.AuditCorrectiveAction{}
+	}
+	for i := range summary.CorrectiveActions {
+		summary.CorrectiveActions[i].Priority = entity.NormalizeTaskPriority(summary.CorrectiveActions[i].Priority)
+	}
+	summary.Provider = resp.Provider
+	summary.Model = resp.Model
+	summary.GeneratedAt = time.Now()
+	summary.InputHash = hash
+
+	if err := uc.repo.SaveAISummary(ctx, id, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// summaryPrompt describes the audit, its scores and the auditor observations
+func summaryPrompt(audit *entity.Audit, contractName string, items []entity.AuditItemWithCategory) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Contrato: %s\n", contractName)
+	fmt.Fprintf(&b, "Auditor: %s\n", audit.AuditorName)
+	fmt.Fprintf(&b, "Data: %s\n", audit.AuditDate.Format("02/01/2006"))
+	fmt.Fprintf(&b, "Nota: %.1f (meta %.1f", audit.Score, audit.TargetScore)
+	if audit.PreviousScore != nil {
+		fmt.Fprintf(&b, ", auditoria anterior %.1f", *audit.PreviousScore)
+	}
+	fmt.Fprintf(&b, ")\nStatus: %s\n", audit.Status)
+	if audit.Observations != nil && *audit.Observations != "" {
+		fmt.Fprintf(&b, "Observações gerais: %s\n", *audit.Observations)
+	}
+
+	if len(items) > 0 {
+		b.WriteString("\nItens avaliados:\n")
+		for _, item := range items {
+			fmt.Fprintf(&b, "- [%s] %s: %.1f/%.1f (%.0f%%)", item.CategoryName, item.ItemName,
+				item.Score, item.MaxScore, item.CalculateItemPercentage())
+			if item.Observation != nil && *item.Observation != "" {
+				fmt.Fprintf(&b, " - %s", *item.Observation)
+			}
+			b.WriteString("\n")
+		}
+	} else if data := string(audit.DataJSON); data != "" && data != "{}" && data != "null" {
+		// Audits recorded by the legacy portal keep their checklist in data_json only
+		if len(data) > maxSummaryDataJSON {
+			data = data[:maxSummaryDataJSON]
+		}
+		fmt.Fprintf(&b, "\nDados da auditoria (JSON): %s\n", data)
+	}
+	return b.String()
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/condotrack/api/internal/infrastructure/auth"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/phone"
 	"github.com/google/uuid"
 )
 
@@ -24,13 +27,23 @@ var (
 	ErrInvalidOldPassword = errors.New("invalid old password")
 	// ErrSamePassword is returned when new password is same as old
 	ErrSamePassword = errors.New("new password must be different from old password")
+	// ErrInvalidRefreshToken is returned when the refresh token is unknown, expired or revoked
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrInvalidPhone is returned when the phone number is not valid
+	ErrInvalidPhone = apperror.New(apperror.CodeInvalidPhone, "invalid phone: include the area code, e.g. (11) 98765-4321")
 )
 
 // UseCase defines the interface for authentication use cases
 type UseCase interface {
-	// Login authenticates a user and returns a token
+	// Login authenticates a user and returns an access token and a refresh token
 	Login(ctx context.Context, email, password string) (*entity.LoginResponse, error)
 
+	// Refresh exchanges a refresh token for a new access token and a new refresh token
+	Refresh(ctx context.Context, refreshToken string) (*entity.LoginResponse, error)
+
+	// RevokeRefreshToken revokes a refresh token and every token rotated from the same login
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+
 	// Register creates a new user account
 	Register(ctx context.Context, req entity.RegisterRequest) (*entity.User, error)
 
@@ -57,15 +70,25 @@ type UseCase interface {
 }
 
 type authUseCase struct {
-	userRepo   repository.UserRepository
-	jwtManager *auth.JWTManager
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	jwtManager       *auth.JWTManager
+	refreshTTL       time.Duration
 }
 
-// NewUseCase creates a new authentication use case
-func NewUseCase(userRepo repository.UserRepository, jwtManager *auth.JWTManager) UseCase {
+// NewUseCase creates a new authentication use case. Refresh tokens expire refreshTTL after
+// they are issued.
+func NewUseCase(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	jwtManager *auth.JWTManager,
+	refreshTTL time.Duration,
+) UseCase {
 	return &authUseCase{
-		userRepo:   userRepo,
-		jwtManager: jwtManager,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		jwtManager:       jwtManager,
+		refreshTTL:       refreshTTL,
 	}
 }
 
@@ -90,26 +113,130 @@ func (uc *authUseCase) Login(ctx context.Context, email, password string) (*enti
 		return nil, ErrInvalidCredentials
 	}
 
-	// Generate token
-	token, err := uc.jwtManager.GenerateToken(user.ID, user.Email, string(user.Role))
+	// Each login starts a new family of refresh tokens
+	refreshToken, stored, err := uc.newRefreshToken(user.ID, uuid.New().String())
 	if err != nil {
 		return nil, err
 	}
+	if err := uc.refreshTokenRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
 
 	// Update last login
 	if err := uc.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
 		log.Printf("[WARN] Failed to update last login for user %s (email: %s): %v", user.ID, user.Email, err)
 	}
 
+	return uc.loginResponse(user, refreshToken)
+}
+
+// Refresh rotates a refresh token: the presented token is revoked and replaced by a new one
+// of the same family. A rotated token is only presented again when it leaked or was stolen,
+// so its reuse revokes the whole family and the user has to log in again.
+func (uc *authUseCase) Refresh(ctx context.Context, refreshToken string) (*entity.LoginResponse, error) {
+	current, err := uc.refreshTokenRepo.FindByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.Rotated() {
+		log.Printf("[WARN] Reuse of rotated refresh token %s of user %s; revoking its family", current.ID, current.UserID)
+		if err := uc.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if !current.Active(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := uc.userRepo.FindByID(ctx, current.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		if err := uc.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	next, stored, err := uc.newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	// The successor is stored before the current token is revoked, so a failure in between
+	// never leaves the session without a valid token
+	if err := uc.refreshTokenRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+	// Two requests racing with the same token: only the first one rotates it, and the
+	// successor of the other one, never handed out, is revoked
+	revoked, err := uc.refreshTokenRepo.Revoke(ctx, current.ID, &stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		if _, err := uc.refreshTokenRepo.Revoke(ctx, stored.ID, nil); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return uc.loginResponse(user, next)
+}
+
+// RevokeRefreshToken revokes the family of a refresh token; unknown tokens are ignored
+func (uc *authUseCase) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	current, err := uc.refreshTokenRepo.FindByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil || current == nil {
+		return err
+	}
+	return uc.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID)
+}
+
+// newRefreshToken generates a refresh token of a family and the record it is stored as
+func (uc *authUseCase) newRefreshToken(userID, familyID string) (string, *entity.RefreshToken, error) {
+	token, hash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+	now := time.Now()
+	return token, &entity.RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(uc.refreshTTL),
+		CreatedAt: now,
+	}, nil
+}
+
+// loginResponse issues an access token for the user alongside its refresh token
+func (uc *authUseCase) loginResponse(user *entity.User, refreshToken string) (*entity.LoginResponse, error) {
+	token, err := uc.jwtManager.GenerateToken(user.ID, user.Email, string(user.Role))
+	if err != nil {
+		return nil, err
+	}
+
 	return &entity.LoginResponse{
-		Token:     token,
-		ExpiresIn: int64(uc.jwtManager.GetTokenDuration().Seconds()),
-		User:      user.ToPublic(),
+		Token:            token,
+		ExpiresIn:        int64(uc.jwtManager.GetTokenDuration().Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int64(uc.refreshTTL.Seconds()),
+		User:             user.ToPublic(),
 	}, nil
 }
 
 // Register creates a new user account
 func (uc *authUseCase) Register(ctx context.Context, req entity.RegisterRequest) (*entity.User, error) {
+	phoneNumber, err := phone.NormalizeOptional(req.Phone)
+	if err != nil {
+		return nil, ErrInvalidPhone
+	}
+
 	// Check if email already exists
 	exists, err := uc.userRepo.ExistsByEmail(ctx, req.Email)
 	if err != nil {
@@ -137,7 +264,7 @@ func (uc *authUseCase) Register(ctx context.Context, req entity.RegisterRequest)
 		Nome:         req.Nome,
 		Role:         role,
 		IsActive:     true,
-		Phone:        req.Phone,
+		Phone:        phoneNumber,
 	}
 
 	if err := uc.userRepo.Create(ctx, user); err != nil {
@@ -188,7 +315,12 @@ func (uc *authUseCase) ChangePassword(ctx context.Context, userID, oldPassword,
 	}
 
 	// Update password
-	return uc.userRepo.UpdatePassword(ctx, userID, newHash)
+	if err := uc.userRepo.UpdatePassword(ctx, userID, newHash); err != nil {
+		return err
+	}
+
+	// Sessions opened with the old password must not outlive it
+	return uc.refreshTokenRepo.RevokeByUser(ctx, userID)
 }
 
 // GetUserByID returns a user by ID
@@ -226,11 +358,16 @@ func (uc *authUseCase) UpdateUser(ctx context.Context, userID string, req entity
 		user.Nome = *req.Nome
 	}
 	if req.Phone != nil {
-		user.Phone = req.Phone
+		if user.Phone, err = phone.NormalizeOptional(req.Phone); err != nil {
+			return nil, ErrInvalidPhone
+		}
 	}
 	if req.AvatarURL != nil {
 		user.AvatarURL = req.AvatarURL
 	}
+	if req.Bio != nil {
+		user.Bio = req.Bio
+	}
 
 	if err := uc.userRepo.Update(ctx, user); err != nil {
 		return nil, err
@@ -273,11 +410,16 @@ func (uc *authUseCase) AdminUpdateUser(ctx context.Context, userID string, req e
 		user.IsActive = *req.IsActive
 	}
 	if req.Phone != nil {
-		user.Phone = req.Phone
+		if user.Phone, err = phone.NormalizeOptional(req.Phone); err != nil {
+			return nil, ErrInvalidPhone
+		}
 	}
 	if req.AvatarURL != nil {
 		user.AvatarURL = req.AvatarURL
 	}
+	if req.Bio != nil {
+		user.Bio = req.Bio
+	}
 
 	if err := uc.userRepo.Update(ctx, user); err != nil {
 		return nil, err
@@ -296,5 +438,8 @@ func (uc *authUseCase) DeleteUser(ctx context.Context, userID string) error {
 		return ErrUserNotFound
 	}
 
-	return uc.userRepo.Delete(ctx, userID)
+	if err := uc.userRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+	return uc.refreshTokenRepo.RevokeByUser(ctx, userID)
 }
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/infrastructure/auth"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func newTestUseCase(t *testing.T) (UseCase, *testutil.MockUserRepository, *testutil.MockRefreshTokenRepository) {
+	t.Helper()
+	hash, err := auth.HashPassword("secret123")
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	users := testutil.NewMockUserRepository(&entity.User{
+		ID:           "user-1",
+		Email:        "aluno@example.com",
+		PasswordHash: hash,
+		Role:         entity.RoleStudent,
+		IsActive:     true,
+	})
+	tokens := testutil.NewMockRefreshTokenRepository()
+	uc := NewUseCase(users, tokens, auth.NewJWTManager("test-secret", 1), 30*24*time.Hour)
+	return uc, users, tokens
+}
+
+func TestRefresh_RotatesToken(t *testing.T) {
+	ctx := context.Background()
+	uc, _, tokens := newTestUseCase(t)
+
+	login, err := uc.Login(ctx, "aluno@example.com", "secret123")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if login.RefreshToken == "" || login.RefreshExpiresIn != 30*24*3600 {
+		t.Fatalf("expected a refresh token valid for 30 days, got %+v", login)
+	}
+
+	refreshed, err := uc.Refresh(ctx, login.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if refreshed.Token == "" || refreshed.RefreshToken == "" || refreshed.RefreshToken == login.RefreshToken {
+		t.Fatalf("expected a new access token and a rotated refresh token, got %+v", refreshed)
+	}
+	if len(tokens.Tokens) != 2 {
+		t.Errorf("expected the rotated token to be kept for reuse detection, got %d tokens", len(tokens.Tokens))
+	}
+
+	// The rotated token is refused and its reuse revokes the token issued in its place
+	if _, err := uc.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected the rotated token to be refused, got %v", err)
+	}
+	if _, err := uc.Refresh(ctx, refreshed.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected the family to be revoked after a reuse, got %v", err)
+	}
+}
+
+// racedTokenRepo rotates the token in another request right after it is read
+type racedTokenRepo struct {
+	*testutil.MockRefreshTokenRepository
+}
+
+func (r racedTokenRepo) FindByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	token, err := r.MockRefreshTokenRepository.FindByHash(ctx, tokenHash)
+	if token != nil {
+		winner := "token-of-the-other-request"
+		r.Revoke(ctx, token.ID, &winner)
+	}
+	return token, err
+}
+
+func TestRefresh_LosesRace(t *testing.T) {
+	ctx := context.Background()
+	uc, users, tokens := newTestUseCase(t)
+	login, err := uc.Login(ctx, "aluno@example.com", "secret123")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	raced := NewUseCase(users, racedTokenRepo{tokens}, auth.NewJWTManager("test-secret", 1), 30*24*time.Hour)
+
+	if _, err := raced.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected the request that lost the rotation to be refused, got %v", err)
+	}
+	active := 0
+	for _, token := range tokens.Tokens {
+		if token.RevokedAt == nil {
+			active++
+		}
+	}
+	if active != 0 {
+		t.Errorf("expected the successor of the refused request to be revoked, got %d active tokens", active)
+	}
+}
+
+func TestRefresh_Refused(t *testing.T) {
+	ctx := context.Background()
+	uc, users, tokens := newTestUseCase(t)
+
+	if _, err := uc.Refresh(ctx, "unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected an unknown token to be refused, got %v", err)
+	}
+
+	login, _ := uc.Login(ctx, "aluno@example.com", "secret123")
+	for _, token := range tokens.Tokens {
+		token.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+	if _, err := uc.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected an expired token to be refused, got %v", err)
+	}
+
+	login, _ = uc.Login(ctx, "aluno@example.com", "secret123")
+	users.Users["user-1"].IsActive = false
+	if _, err := uc.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected the token of an inactive user to be refused, got %v", err)
+	}
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	ctx := context.Background()
+	uc, _, _ := newTestUseCase(t)
+
+	login, _ := uc.Login(ctx, "aluno@example.com", "secret123")
+	other, _ := uc.Login(ctx, "aluno@example.com", "secret123")
+	if err := uc.RevokeRefreshToken(ctx, login.RefreshToken); err != nil {
+		t.Fatalf("RevokeRefreshToken: %v", err)
+	}
+	if _, err := uc.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected a revoked token to be refused, got %v", err)
+	}
+	other, err := uc.Refresh(ctx, other.RefreshToken)
+	if err != nil {
+		t.Fatalf("expected the other session to keep working, got %v", err)
+	}
+
+	if err := uc.ChangePassword(ctx, "user-1", "secret123", "newsecret"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := uc.Refresh(ctx, other.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Error("expected a password change to revoke every session")
+	}
+}
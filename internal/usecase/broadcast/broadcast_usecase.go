@@ -0,0 +1,158 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/google/uuid"
+)
+
+// recentLimit is how many broadcasts List returns
+const recentLimit = 50
+
+// ErrBroadcastNotFound is returned for broadcasts that do not exist
+var ErrBroadcastNotFound = errors.New("broadcast not found")
+
+// UseCase defines the notification broadcast use case interface
+type UseCase interface {
+	// Send notifies every active user, or the active users of a role, and records the broadcast
+	Send(ctx context.Context, createdBy string, req *entity.CreateNotificationBroadcastRequest) (*entity.NotificationBroadcast, error)
+
+	// List returns the latest broadcasts, newest first
+	List(ctx context.Context) ([]entity.NotificationBroadcast, error)
+
+	// GetStats returns the engagement of a broadcast on each channel
+	GetStats(ctx context.Context, id string) (*entity.BroadcastStats, error)
+}
+
+type broadcastUseCase struct {
+	repo     repository.NotificationBroadcastRepository
+	userRepo repository.UserRepository
+	notifier notification.UseCase
+	now      func() time.Time
+}
+
+// NewUseCase creates a new notification broadcast use case
+func NewUseCase(
+	repo repository.NotificationBroadcastRepository,
+	userRepo repository.UserRepository,
+	notifier notification.UseCase,
+) UseCase {
+	return &broadcastUseCase{repo: repo, userRepo: userRepo, notifier: notifier, now: time.Now}
+}
+
+// Send records the broadcast first, so its notifications are never orphaned, then creates one
+// notification per recipient, queued on the channels of its user. Recipients counts the users
+// notified, including those who turned the type off, whose notification is dropped silently.
+func (uc *broadcastUseCase) Send(ctx context.Context, createdBy string, req *entity.CreateNotificationBroadcastRequest) (*entity.NotificationBroadcast, error) {
+	notifType := req.Type
+	if notifType == "" {
+		notifType = entity.NotificationTypeSystem
+	}
+	if notifType != entity.NotificationTypeSystem && !entity.IsOptionalNotificationType(notifType) {
+		return nil, fmt.Errorf("invalid notification type: %s", notifType)
+	}
+
+	active := true
+	filters := repository.UserFilters{IsActive: &active}
+	if req.Role != nil && *req.Role != "" {
+		role := entity.UserRole(*req.Role)
+		if !role.IsValid() {
+			return nil, fmt.Errorf("invalid role: %s", *req.Role)
+		}
+		filters.Role = &role
+	}
+	users, err := uc.userRepo.FindAllWithFilters(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &entity.NotificationBroadcast{
+		ID:        uuid.New().String(),
+		Type:      notifType,
+		Title:     req.Title,
+		Message:   req.Message,
+		CreatedAt: uc.now(),
+	}
+	if filters.Role != nil {
+		role := string(*filters.Role)
+		b.TargetRole = &role
+	}
+	if createdBy != "" {
+		b.CreatedBy = &createdBy
+	}
+	if err := uc.repo.Create(ctx, b); err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		notif := &entity.Notificacao{
+			ID:          uuid.New().String(),
+			UserID:      users[i].ID,
+			Type:        b.Type,
+			Title:       b.Title,
+			Message:     b.Message,
+			BroadcastID: &b.ID,
+		}
+		if err := uc.notifier.Create(ctx, notif); err != nil {
+			log.Printf("[BROADCAST] Failed to notify user %s of broadcast %s: %v", users[i].ID, b.ID, err)
+			continue
+		}
+		b.Recipients++
+	}
+	if err := uc.repo.SetRecipients(ctx, b.ID, b.Recipients); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// List returns the latest broadcasts
+func (uc *broadcastUseCase) List(ctx context.Context) ([]entity.NotificationBroadcast, error) {
+	return uc.repo.FindRecent(ctx, recentLimit)
+}
+
+// GetStats returns the receipts of a broadcast per channel, in-app first, with the shares
+// of its notifications sent, delivered and read
+func (uc *broadcastUseCase) GetStats(ctx context.Context, id string) (*entity.BroadcastStats, error) {
+	b, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, ErrBroadcastNotFound
+	}
+
+	stats, err := uc.repo.ChannelStats(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	byChannel := make(map[string]entity.BroadcastChannelStats, len(stats))
+	for _, s := range stats {
+		byChannel[s.Channel] = s
+	}
+
+	report := &entity.BroadcastStats{Broadcast: *b, Channels: []entity.BroadcastChannelStats{}}
+	channels := []string{entity.NotificationChannelInApp}
+	for _, def := range entity.NotificationChannels {
+		channels = append(channels, def.Channel)
+	}
+	for _, ch := range channels {
+		s, ok := byChannel[ch]
+		if !ok {
+			continue
+		}
+		if s.Total > 0 {
+			s.SentRate = float64(s.Sent) / float64(s.Total)
+			s.DeliveredRate = float64(s.Delivered) / float64(s.Total)
+			s.ReadRate = float64(s.Read) / float64(s.Total)
+		}
+		report.Channels = append(report.Channels, s)
+	}
+	return report, nil
+}
@@ -0,0 +1,150 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/internal/usecase/notification"
+)
+
+type fakeBroadcastRepo struct {
+	broadcasts map[string]*entity.NotificationBroadcast
+	stats      []entity.BroadcastChannelStats
+}
+
+func newFakeBroadcastRepo() *fakeBroadcastRepo {
+	return &fakeBroadcastRepo{broadcasts: make(map[string]*entity.NotificationBroadcast)}
+}
+
+func (r *fakeBroadcastRepo) Create(ctx context.Context, b *entity.NotificationBroadcast) error {
+	copied := *b
+	r.broadcasts[b.ID] = &copied
+	return nil
+}
+
+func (r *fakeBroadcastRepo) SetRecipients(ctx context.Context, id string, recipients int) error {
+	r.broadcasts[id].Recipients = recipients
+	return nil
+}
+
+func (r *fakeBroadcastRepo) FindByID(ctx context.Context, id string) (*entity.NotificationBroadcast, error) {
+	b, ok := r.broadcasts[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *b
+	return &copied, nil
+}
+
+func (r *fakeBroadcastRepo) FindRecent(ctx context.Context, limit int) ([]entity.NotificationBroadcast, error) {
+	return nil, nil
+}
+
+func (r *fakeBroadcastRepo) ChannelStats(ctx context.Context, id string) ([]entity.BroadcastChannelStats, error) {
+	return r.stats, nil
+}
+
+type fakeNotifier struct {
+	notification.UseCase
+	sent []*entity.Notificacao
+	fail map[string]bool // user IDs
+}
+
+func (n *fakeNotifier) Create(ctx context.Context, notif *entity.Notificacao) error {
+	if n.fail[notif.UserID] {
+		return errors.New("db down")
+	}
+	n.sent = append(n.sent, notif)
+	return nil
+}
+
+func TestSend(t *testing.T) {
+	users := testutil.NewMockUserRepository(
+		&entity.User{ID: "student-1", Role: entity.RoleStudent, IsActive: true},
+		&entity.User{ID: "student-2", Role: entity.RoleStudent, IsActive: true},
+		&entity.User{ID: "student-3", Role: entity.RoleStudent, IsActive: false},
+		&entity.User{ID: "admin-1", Role: entity.RoleAdmin, IsActive: true},
+	)
+	repo := newFakeBroadcastRepo()
+	notifier := &fakeNotifier{fail: map[string]bool{"student-2": true}}
+	uc := NewUseCase(repo, users, notifier)
+	ctx := context.Background()
+
+	role := string(entity.RoleStudent)
+	b, err := uc.Send(ctx, "admin-1", &entity.CreateNotificationBroadcastRequest{Title: "Aviso", Message: "Aulas suspensas", Role: &role})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Type != entity.NotificationTypeSystem || b.Recipients != 1 || b.TargetRole == nil || *b.TargetRole != role {
+		t.Errorf("unexpected broadcast %+v", b)
+	}
+	if stored := repo.broadcasts[b.ID]; stored == nil || stored.Recipients != 1 || stored.CreatedBy == nil || *stored.CreatedBy != "admin-1" {
+		t.Errorf("expected the broadcast to be stored with its recipients, got %+v", stored)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].UserID != "student-1" ||
+		notifier.sent[0].BroadcastID == nil || *notifier.sent[0].BroadcastID != b.ID {
+		t.Fatalf("expected one notification of the broadcast to student-1, got %+v", notifier.sent)
+	}
+
+	notifier.sent = nil
+	if _, err := uc.Send(ctx, "admin-1", &entity.CreateNotificationBroadcastRequest{Type: entity.NotificationTypeContract, Title: "Aviso", Message: "Reajuste"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, n := range notifier.sent {
+		ids = append(ids, n.UserID)
+	}
+	sort.Strings(ids)
+	if len(ids) != 2 || ids[0] != "admin-1" || ids[1] != "student-1" {
+		t.Errorf("expected every active user but the failing one, got %v", ids)
+	}
+
+	for _, req := range []entity.CreateNotificationBroadcastRequest{
+		{Type: "promo", Title: "Aviso", Message: "x"},
+		{Title: "Aviso", Message: "x", Role: func() *string { r := "root"; return &r }()},
+	} {
+		if _, err := uc.Send(ctx, "admin-1", &req); err == nil {
+			t.Errorf("expected an error for %+v", req)
+		}
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	repo := newFakeBroadcastRepo()
+	repo.broadcasts["b1"] = &entity.NotificationBroadcast{ID: "b1", Title: "Aviso", Recipients: 4}
+	repo.stats = []entity.BroadcastChannelStats{
+		{Channel: entity.NotificationChannelPush, Total: 2, Sent: 2, Delivered: 1},
+		{Channel: entity.NotificationChannelEmail, Total: 4, Sent: 3, Skipped: 1, Delivered: 2, Read: 2},
+		{Channel: entity.NotificationChannelInApp, Total: 4, Sent: 4, Delivered: 4, Read: 1},
+	}
+	uc := NewUseCase(repo, testutil.NewMockUserRepository(), &fakeNotifier{})
+
+	stats, err := uc.GetStats(context.Background(), "b1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.Channels) != 3 {
+		t.Fatalf("expected three channels, got %+v", stats.Channels)
+	}
+	inApp, email, push := stats.Channels[0], stats.Channels[1], stats.Channels[2]
+	if inApp.Channel != entity.NotificationChannelInApp || email.Channel != entity.NotificationChannelEmail || push.Channel != entity.NotificationChannelPush {
+		t.Fatalf("expected in-app, e-mail and push in order, got %+v", stats.Channels)
+	}
+	if inApp.ReadRate != 0.25 || inApp.SentRate != 1 {
+		t.Errorf("unexpected in-app rates %+v", inApp)
+	}
+	if email.SentRate != 0.75 || email.DeliveredRate != 0.5 || email.ReadRate != 0.5 {
+		t.Errorf("unexpected e-mail rates %+v", email)
+	}
+	if push.DeliveredRate != 0.5 || push.ReadRate != 0 {
+		t.Errorf("unexpected push rates %+v", push)
+	}
+
+	if _, err := uc.GetStats(context.Background(), "missing"); !errors.Is(err, ErrBroadcastNotFound) {
+		t.Errorf("expected ErrBroadcastNotFound, got %v", err)
+	}
+}
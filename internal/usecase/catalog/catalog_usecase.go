@@ -0,0 +1,227 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/checkout"
+	"github.com/condotrack/api/pkg/money"
+)
+
+// maxCourses caps how many published courses the catalog lists
+const maxCourses = 500
+
+// ErrCourseNotFound is returned for courses that do not exist or are not published
+var ErrCourseNotFound = errors.New("course not found")
+
+// UseCase defines the public course catalog of the marketing site. It is served without
+// authentication, so only the fields of the views below ever leave the API.
+type UseCase interface {
+	// ListCourses returns the published courses, newest first
+	ListCourses(ctx context.Context) ([]Course, error)
+
+	// GetCourse returns a published course
+	GetCourse(ctx context.Context, id string) (*Course, error)
+}
+
+// Pricer prices the payment methods of a course; checkout.UseCase implements it
+type Pricer interface {
+	GetPaymentMethods(ctx context.Context, courseID, discountCode string) (*checkout.PaymentMethods, error)
+}
+
+// Course is the public view of a published course: its pricing plans, instructor and rating
+type Course struct {
+	ID            string                     `json:"id"`
+	Name          string                     `json:"name"`
+	Description   *string                    `json:"description,omitempty"`
+	DurationHours int                        `json:"duration_hours"`
+	ThumbnailURL  *string                    `json:"thumbnail_url,omitempty"`
+	Price         money.Cents                `json:"price"`
+	FinalPrice    money.Cents                `json:"final_price"`
+	Instructor    *Instructor                `json:"instructor,omitempty"`
+	Rating        entity.CourseRatingSummary `json:"rating"`
+	Plans         []Plan                     `json:"plans"`
+}
+
+// Instructor is the public profile of the instructor of a course
+type Instructor struct {
+	Name      string  `json:"name"`
+	Bio       *string `json:"bio,omitempty"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+}
+
+// Plan is how a course can be paid with a payment method: in full, and in the card or carnê
+// installments offered
+type Plan struct {
+	Method       string                       `json:"method"`
+	Total        money.Cents                  `json:"total"`
+	Installments []checkout.InstallmentOption `json:"installments,omitempty"`
+}
+
+type catalogUseCase struct {
+	courseRepo repository.CourseRepository
+	userRepo   repository.UserRepository
+	ratingRepo repository.CourseRatingRepository
+	pricer     Pricer
+	ttl        time.Duration
+
+	// load serializes reloads so an expired catalog is loaded once, not by every request
+	load     sync.Mutex
+	mu       sync.RWMutex
+	courses  []Course
+	byID     map[string]*Course
+	loadedAt time.Time
+}
+
+// NewUseCase creates a new catalog use case. The catalog is loaded at most once per ttl, so
+// course, price and rating changes take up to ttl to show.
+func NewUseCase(
+	courseRepo repository.CourseRepository,
+	userRepo repository.UserRepository,
+	ratingRepo repository.CourseRatingRepository,
+	pricer Pricer,
+	ttl time.Duration,
+) UseCase {
+	return &catalogUseCase{
+		courseRepo: courseRepo,
+		userRepo:   userRepo,
+		ratingRepo: ratingRepo,
+		pricer:     pricer,
+		ttl:        ttl,
+	}
+}
+
+// ListCourses returns the published courses from the cache
+func (uc *catalogUseCase) ListCourses(ctx context.Context) ([]Course, error) {
+	courses, _, err := uc.cached(ctx)
+	return courses, err
+}
+
+// GetCourse returns a published course from the cache
+func (uc *catalogUseCase) GetCourse(ctx context.Context, id string) (*Course, error) {
+	_, byID, err := uc.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	course, ok := byID[id]
+	if !ok {
+		return nil, ErrCourseNotFound
+	}
+	return course, nil
+}
+
+// cached returns the catalog, reloading it once it is older than the TTL
+func (uc *catalogUseCase) cached(ctx context.Context) ([]Course, map[string]*Course, error) {
+	if courses, byID, ok := uc.fresh(); ok {
+		return courses, byID, nil
+	}
+
+	uc.load.Lock()
+	defer uc.load.Unlock()
+	// Another request may have reloaded it while this one waited
+	if courses, byID, ok := uc.fresh(); ok {
+		return courses, byID, nil
+	}
+
+	courses, err := uc.build(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]*Course, len(courses))
+	for i := range courses {
+		byID[courses[i].ID] = &courses[i]
+	}
+
+	uc.mu.Lock()
+	uc.courses, uc.byID, uc.loadedAt = courses, byID, time.Now()
+	uc.mu.Unlock()
+	return courses, byID, nil
+}
+
+func (uc *catalogUseCase) fresh() ([]Course, map[string]*Course, bool) {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.courses, uc.byID, uc.byID != nil && time.Since(uc.loadedAt) < uc.ttl
+}
+
+// build loads the published courses with their instructors, ratings and pricing plans
+func (uc *catalogUseCase) build(ctx context.Context) ([]Course, error) {
+	published, _, err := uc.courseRepo.FindActive(ctx, 1, maxCourses)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(published, func(i, j int) bool { return published[i].CreatedAt.After(published[j].CreatedAt) })
+
+	summaries, err := uc.ratingRepo.Summaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ratings := make(map[string]entity.CourseRatingSummary, len(summaries))
+	for _, s := range summaries {
+		ratings[s.CourseID] = s
+	}
+
+	instructors := make(map[string]*Instructor)
+	courses := make([]Course, 0, len(published))
+	for i := range published {
+		c := &published[i]
+		course := Course{
+			ID:            c.ID,
+			Name:          c.Name,
+			Description:   c.Description,
+			DurationHours: c.DurationHours,
+			ThumbnailURL:  c.ThumbnailURL,
+			Price:         money.FromFloat(c.Price),
+			FinalPrice:    money.FromFloat(c.EffectivePrice()),
+			Rating:        ratings[c.ID],
+			Plans:         uc.plans(ctx, c.ID),
+		}
+		if c.InstructorID != nil {
+			instructor, ok := instructors[*c.InstructorID]
+			if !ok {
+				if instructor, err = uc.instructor(ctx, *c.InstructorID); err != nil {
+					return nil, err
+				}
+				instructors[*c.InstructorID] = instructor
+			}
+			course.Instructor = instructor
+		}
+		courses = append(courses, course)
+	}
+	return courses, nil
+}
+
+// instructor returns the public profile of an instructor, nil when the account is gone or
+// deactivated
+func (uc *catalogUseCase) instructor(ctx context.Context, id string) (*Instructor, error) {
+	user, err := uc.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive {
+		return nil, nil
+	}
+	return &Instructor{Name: user.Nome, Bio: user.Bio, AvatarURL: user.AvatarURL}, nil
+}
+
+// plans returns the pricing plans of a course from the checkout, keeping what the buyer pays
+// and leaving out the gateway and its fees. A course whose plans cannot be priced is listed
+// without them.
+func (uc *catalogUseCase) plans(ctx context.Context, courseID string) []Plan {
+	methods, err := uc.pricer.GetPaymentMethods(ctx, courseID, "")
+	if err != nil {
+		log.Printf("[CATALOG] Failed to price course %s: %v", courseID, err)
+		return []Plan{}
+	}
+	plans := make([]Plan, 0, len(methods.Methods))
+	for _, m := range methods.Methods {
+		plans = append(plans, Plan{Method: m.Method, Total: m.Total, Installments: m.Installments})
+	}
+	return plans
+}
@@ -0,0 +1,126 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/internal/usecase/checkout"
+	"github.com/condotrack/api/pkg/money"
+)
+
+type fakePricer struct {
+	calls int
+}
+
+func (p *fakePricer) GetPaymentMethods(ctx context.Context, courseID, discountCode string) (*checkout.PaymentMethods, error) {
+	p.calls++
+	return &checkout.PaymentMethods{
+		Gateway:  "asaas",
+		CourseID: courseID,
+		Methods: []checkout.PaymentMethodOption{
+			{Method: "pix", Fee: 199, Total: 19990, NetAmount: 19791},
+			{Method: "card", Fee: 999, Total: 19990, NetAmount: 18991, Installments: []checkout.InstallmentOption{
+				{Count: 1, Amount: 19990, Total: 19990},
+				{Count: 2, Amount: 9995, Total: 19990},
+			}},
+		},
+	}, nil
+}
+
+func newTestCatalog(ttl time.Duration) (UseCase, *testutil.MockCourseRepository, *fakePricer) {
+	instructorID, bio := "inst-1", "Engenheira civil, 15 anos de manutenção predial"
+	discount := 199.9
+	courses := testutil.NewMockCourseRepository(
+		&entity.Course{ID: "c1", Name: "NR-35", InstructorID: &instructorID, DurationHours: 8, Price: 249.9, DiscountPrice: &discount, IsActive: true, CreatedAt: time.Now().Add(-time.Hour)},
+		&entity.Course{ID: "c2", Name: "Síndico Profissional", DurationHours: 40, Price: 990, IsActive: true, CreatedAt: time.Now()},
+		&entity.Course{ID: "c3", Name: "Rascunho", DurationHours: 1, Price: 10, IsActive: false},
+	)
+	users := testutil.NewMockUserRepository(&entity.User{ID: instructorID, Nome: "Ana Souza", Email: "ana@example.com", Bio: &bio, Role: entity.RoleInstructor, IsActive: true})
+	ratings := testutil.NewMockCourseRatingRepository(
+		&entity.CourseRating{CourseID: "c1", StudentID: "s1", Rating: 5},
+		&entity.CourseRating{CourseID: "c1", StudentID: "s2", Rating: 4},
+	)
+	pricer := &fakePricer{}
+	return NewUseCase(courses, users, ratings, pricer, ttl), courses, pricer
+}
+
+func TestListCourses(t *testing.T) {
+	uc, _, _ := newTestCatalog(time.Minute)
+
+	courses, err := uc.ListCourses(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(courses) != 2 || courses[0].ID != "c2" || courses[1].ID != "c1" {
+		t.Fatalf("expected the published courses newest first, got %+v", courses)
+	}
+
+	c := courses[1]
+	if c.Price != 24990 || c.FinalPrice != money.FromFloat(199.9) {
+		t.Errorf("unexpected prices: %s %s", c.Price, c.FinalPrice)
+	}
+	if c.Instructor == nil || c.Instructor.Name != "Ana Souza" || c.Instructor.Bio == nil {
+		t.Errorf("expected the instructor profile, got %+v", c.Instructor)
+	}
+	if c.Rating.Count != 2 || c.Rating.Average != 4.5 {
+		t.Errorf("expected an average of 4.5 from 2 ratings, got %+v", c.Rating)
+	}
+	if len(c.Plans) != 2 || c.Plans[1].Method != "card" || len(c.Plans[1].Installments) != 2 {
+		t.Errorf("expected the pix and card plans, got %+v", c.Plans)
+	}
+	if courses[0].Instructor != nil || courses[0].Rating.Count != 0 {
+		t.Errorf("expected a course without instructor or ratings, got %+v", courses[0])
+	}
+
+	body, _ := json.Marshal(courses)
+	for _, field := range []string{"instructor_id", "ana@example.com", "net_amount", "fee", "gateway", "is_active"} {
+		if strings.Contains(string(body), field) {
+			t.Errorf("expected %q not to be exposed: %s", field, body)
+		}
+	}
+}
+
+func TestGetCourse(t *testing.T) {
+	uc, _, _ := newTestCatalog(time.Minute)
+	ctx := context.Background()
+
+	if c, err := uc.GetCourse(ctx, "c1"); err != nil || c.Name != "NR-35" {
+		t.Fatalf("expected the course, got %+v, %v", c, err)
+	}
+	if _, err := uc.GetCourse(ctx, "c3"); !errors.Is(err, ErrCourseNotFound) {
+		t.Errorf("expected an unpublished course to be hidden, got %v", err)
+	}
+	if _, err := uc.GetCourse(ctx, "missing"); !errors.Is(err, ErrCourseNotFound) {
+		t.Errorf("expected course not found, got %v", err)
+	}
+}
+
+func TestListCourses_Cached(t *testing.T) {
+	uc, courses, pricer := newTestCatalog(time.Minute)
+	ctx := context.Background()
+
+	if _, err := uc.ListCourses(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	courses.Courses["c2"].Name = "Renamed"
+	list, _ := uc.ListCourses(ctx)
+	if _, err := uc.GetCourse(ctx, "c1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list[0].Name != "Síndico Profissional" || pricer.calls != 2 {
+		t.Errorf("expected the catalog to be served from the cache, got %q after %d pricings", list[0].Name, pricer.calls)
+	}
+
+	uc, courses, _ = newTestCatalog(0)
+	uc.ListCourses(ctx)
+	courses.Courses["c2"].Name = "Renamed"
+	if list, _ := uc.ListCourses(ctx); list[0].Name != "Renamed" {
+		t.Errorf("expected an expired catalog to be reloaded, got %q", list[0].Name)
+	}
+}
@@ -1,19 +1,32 @@
 package certificado
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
+	"github.com/condotrack/api/internal/config"
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/pkg/document"
 	"github.com/google/uuid"
 )
 
+const (
+	// defaultCourseHours is used when the course has no duration
+	defaultCourseHours = 40
+
+	// pdfDownloadTTL is how long the download link of a certificate PDF is valid
+	pdfDownloadTTL = 15 * time.Minute
+)
+
 // UseCase defines the certificado use case interface
 type UseCase interface {
 	GetCertificatesByStudent(ctx context.Context, studentID string) ([]entity.Certificado, error)
@@ -25,13 +38,28 @@ type UseCase interface {
 type certificadoUseCase struct {
 	certRepo      repository.CertificadoRepository
 	matriculaRepo repository.MatriculaRepository
+	courseRepo    repository.CourseRepository
+	storage       *storage.StorageService
+	bucket        string
+	validationURL string
 }
 
-// NewUseCase creates a new certificado use case
-func NewUseCase(certRepo repository.CertificadoRepository, matriculaRepo repository.MatriculaRepository) UseCase {
+// NewUseCase creates a new certificado use case. Without storage, certificates are issued
+// without a PDF.
+func NewUseCase(
+	certRepo repository.CertificadoRepository,
+	matriculaRepo repository.MatriculaRepository,
+	courseRepo repository.CourseRepository,
+	storageService *storage.StorageService,
+	cfg *config.Config,
+) UseCase {
 	return &certificadoUseCase{
 		certRepo:      certRepo,
 		matriculaRepo: matriculaRepo,
+		courseRepo:    courseRepo,
+		storage:       storageService,
+		bucket:        cfg.MinioBucketCerts,
+		validationURL: strings.TrimRight(cfg.CertificateValidationURL, "/"),
 	}
 }
 
@@ -40,9 +68,25 @@ func (uc *certificadoUseCase) GetCertificatesByStudent(ctx context.Context, stud
 	return uc.certRepo.FindByStudentID(ctx, studentID)
 }
 
-// GetCertificateByID returns a specific certificate by ID
+// GetCertificateByID returns a specific certificate by ID with a short-lived download URL of
+// its PDF. Certificates issued before PDFs were stored get theirs rendered on first request.
 func (uc *certificadoUseCase) GetCertificateByID(ctx context.Context, id string) (*entity.Certificado, error) {
-	return uc.certRepo.FindByID(ctx, id)
+	cert, err := uc.certRepo.FindByID(ctx, id)
+	if err != nil || cert == nil || uc.storage == nil {
+		return cert, err
+	}
+
+	if cert.PDFKey == nil {
+		if err := uc.storePDF(ctx, cert); err != nil {
+			return nil, err
+		}
+	}
+	url, err := uc.storage.GetPresignedURL(ctx, uc.bucket, *cert.PDFKey, pdfDownloadTTL)
+	if err != nil {
+		return nil, err
+	}
+	cert.DownloadURL = &url
+	return cert, nil
 }
 
 // ValidateCertificate validates a certificate by its code
@@ -108,6 +152,16 @@ func (uc *certificadoUseCase) GenerateCertificate(ctx context.Context, enrollmen
 		return nil, errors.New("payment is not confirmed")
 	}
 
+	// The CPF is printed on the certificate, so one that fails its check digits is fixed first
+	var studentCPF *string
+	if enrollment.StudentCPF != nil && *enrollment.StudentCPF != "" {
+		cpf, err := document.NormalizeCPF(*enrollment.StudentCPF)
+		if err != nil {
+			return nil, errors.New("invalid student CPF on the enrollment")
+		}
+		studentCPF = &cpf
+	}
+
 	// Generate validation code
 	validationCode := generateValidationCode()
 
@@ -117,16 +171,22 @@ func (uc *certificadoUseCase) GenerateCertificate(ctx context.Context, enrollmen
 		completionDate = *enrollment.CompletionDate
 	}
 
+	// Course hours come from the course
+	course, err := uc.courseRepo.FindByID(ctx, enrollment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create certificate
 	cert := &entity.Certificado{
 		ID:             uuid.New().String(),
 		EnrollmentID:   enrollmentID,
 		StudentID:      enrollment.StudentID,
 		StudentName:    enrollment.StudentName,
-		StudentCPF:     enrollment.StudentCPF,
+		StudentCPF:     studentCPF,
 		CourseID:       enrollment.CourseID,
 		CourseName:     enrollment.CourseName,
-		CourseHours:    40, // Default course hours, could be from course entity
+		CourseHours:    defaultCourseHours,
 		InstructorName: enrollment.InstructorName,
 		CompletionDate: completionDate,
 		IssueDate:      time.Now(),
@@ -135,10 +195,21 @@ func (uc *certificadoUseCase) GenerateCertificate(ctx context.Context, enrollmen
 		CreatedAt:      time.Now(),
 	}
 
+	if course != nil && course.DurationHours > 0 {
+		cert.CourseHours = course.DurationHours
+	}
+
 	if err := uc.certRepo.Create(ctx, cert); err != nil {
 		return nil, err
 	}
 
+	// The PDF is rendered again on the first detail request if storing it fails now
+	if uc.storage != nil {
+		if err := uc.storePDF(ctx, cert); err != nil {
+			log.Printf("[WARN] failed to store PDF of certificate %s: %v", cert.ID, err)
+		}
+	}
+
 	// Update enrollment with certificate ID
 	enrollment.CertificateID = &cert.ID
 	if err := uc.matriculaRepo.Update(ctx, enrollment); err != nil {
@@ -149,6 +220,17 @@ func (uc *certificadoUseCase) GenerateCertificate(ctx context.Context, enrollmen
 	return cert, nil
 }
 
+// storePDF renders the PDF of a certificate, uploads it and saves its key
+func (uc *certificadoUseCase) storePDF(ctx context.Context, cert *entity.Certificado) error {
+	content := renderPDF(cert, uc.validationURL+"/"+cert.ValidationCode)
+	key := fmt.Sprintf("%s/%s.pdf", cert.StudentID, cert.ID)
+	if _, err := uc.storage.UploadFile(ctx, uc.bucket, key, bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		return err
+	}
+	cert.PDFKey = &key
+	return uc.certRepo.Update(ctx, cert)
+}
+
 // generateValidationCode generates a unique validation code
 func generateValidationCode() string {
 	bytes := make([]byte, 8)
@@ -0,0 +1,105 @@
+package certificado
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func TestGenerateCertificate_UsesCourseHours(t *testing.T) {
+	ctx := context.Background()
+	enrollments := testutil.NewMockMatriculaRepository()
+	enrollments.Enrollments["enr-1"] = &entity.Matricula{
+		ID:            "enr-1",
+		StudentID:     "student-1",
+		StudentName:   "Maria Souza",
+		CourseID:      "course-1",
+		CourseName:    "Síndico Profissional",
+		Status:        entity.EnrollmentStatusCompleted,
+		PaymentStatus: entity.PaymentStatusConfirmed,
+	}
+	courses := testutil.NewMockCourseRepository(&entity.Course{ID: "course-1", DurationHours: 24})
+	certs := testutil.NewMockCertificadoRepository()
+	uc := NewUseCase(certs, enrollments, courses, nil, &config.Config{})
+
+	cert, err := uc.GenerateCertificate(ctx, "enr-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cert.CourseHours != 24 {
+		t.Errorf("expected the 24 hours of the course, got %d", cert.CourseHours)
+	}
+	if cert.PDFKey != nil {
+		t.Errorf("expected no PDF without storage, got %s", *cert.PDFKey)
+	}
+
+	again, err := uc.GenerateCertificate(ctx, "enr-1")
+	if err != nil || again.ID != cert.ID {
+		t.Errorf("expected the existing certificate back, got %+v, %v", again, err)
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	instructor := "João Lima"
+	cert := &entity.Certificado{
+		StudentName:    "Maria Souza",
+		CourseName:     "Gestão de Condomínios",
+		CourseHours:    24,
+		InstructorName: &instructor,
+		CompletionDate: time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+		IssueDate:      time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC),
+		ValidationCode: "A1B2C3D4E5F6A7B8",
+	}
+
+	out := renderPDF(cert, "https://condotrack.com/validate/A1B2C3D4E5F6A7B8")
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatal("expected PDF output")
+	}
+	if !bytes.Contains(out, []byte("(Maria Souza) Tj")) {
+		t.Error("expected student name in document")
+	}
+	if !bytes.Contains(out, []byte("(A1B2C3D4E5F6A7B8) Tj")) {
+		t.Error("expected validation code in document")
+	}
+	if !bytes.Contains(out, []byte("24 horas, em 10/05/2024.")) {
+		t.Error("expected course hours and completion date in document")
+	}
+	if !bytes.Contains(out, []byte(" re f Q")) {
+		t.Error("expected the QR code modules in document")
+	}
+}
+
+func TestGenerateCertificate_StudentCPF(t *testing.T) {
+	ctx := context.Background()
+	cpf, invalid := "529.982.247-25", "529.982.247-26"
+	enrollments := testutil.NewMockMatriculaRepository()
+	for id, doc := range map[string]*string{"enr-1": &cpf, "enr-2": &invalid} {
+		enrollments.Enrollments[id] = &entity.Matricula{
+			ID:            id,
+			StudentID:     "student-1",
+			StudentName:   "Maria Souza",
+			StudentCPF:    doc,
+			CourseID:      "course-1",
+			Status:        entity.EnrollmentStatusCompleted,
+			PaymentStatus: entity.PaymentStatusConfirmed,
+		}
+	}
+	uc := NewUseCase(testutil.NewMockCertificadoRepository(), enrollments, testutil.NewMockCourseRepository(&entity.Course{ID: "course-1"}), nil, &config.Config{})
+
+	cert, err := uc.GenerateCertificate(ctx, "enr-1")
+	if err != nil || cert.StudentCPF == nil || *cert.StudentCPF != "52998224725" {
+		t.Fatalf("expected the CPF digits on the certificate, got %+v, %v", cert, err)
+	}
+	if out := renderPDF(cert, "https://condotrack.com/validate/x"); !bytes.Contains(out, []byte("(CPF: 529.982.247-25) Tj")) {
+		t.Error("expected the formatted CPF in document")
+	}
+
+	if _, err := uc.GenerateCertificate(ctx, "enr-2"); err == nil || err.Error() != "invalid student CPF on the enrollment" {
+		t.Errorf("expected an invalid CPF error, got %v", err)
+	}
+}
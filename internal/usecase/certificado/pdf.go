@@ -0,0 +1,91 @@
+package certificado
+
+import (
+	"fmt"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/document"
+	"github.com/condotrack/api/pkg/pdf"
+	"github.com/condotrack/api/pkg/qrcode"
+)
+
+// Layout (points, landscape A4)
+const (
+	pageWidth   = pdf.A4Height
+	pageHeight  = pdf.A4Width
+	centerX     = pageWidth / 2
+	textWidth   = pageWidth - 200
+	qrSide      = 96.0
+	qrLeft      = pageWidth - 80 - qrSide
+	qrTop       = 400.0
+	signatureY  = 470.0
+	signatureX1 = 100.0
+	signatureX2 = 340.0
+)
+
+// renderPDF lays out a certificate on a landscape A4 page. The QR code points to
+// validationURL, the public validation link of the certificate.
+func renderPDF(cert *entity.Certificado, validationURL string) []byte {
+	doc := pdf.NewLandscape()
+	doc.SetTitle("Certificado " + cert.ValidationCode)
+	doc.AddPage()
+
+	doc.Rect(20, 20, pageWidth-40, pageHeight-40, 2)
+	doc.Rect(28, 28, pageWidth-56, pageHeight-56, 0.5)
+
+	doc.Text(centerX, 110, 36, pdf.Bold, pdf.AlignCenter, "CERTIFICADO")
+	doc.Text(centerX, 135, 14, pdf.Regular, pdf.AlignCenter, "DE CONCLUSÃO")
+
+	doc.Text(centerX, 195, 14, pdf.Regular, pdf.AlignCenter, "Certificamos que")
+	doc.Text(centerX, 235, 26, pdf.Bold, pdf.AlignCenter, cert.StudentName)
+	y := 235.0
+	if cert.StudentCPF != nil && *cert.StudentCPF != "" {
+		y += 22
+		doc.Text(centerX, y, 11, pdf.Regular, pdf.AlignCenter, "CPF: "+document.Format(*cert.StudentCPF))
+	}
+
+	body := fmt.Sprintf("concluiu o curso %s, com carga horária de %d horas, em %s.",
+		cert.CourseName, cert.CourseHours, cert.CompletionDate.Format("02/01/2006"))
+	y += 40
+	for _, line := range pdf.WrapText(body, 14, pdf.Regular, textWidth) {
+		doc.Text(centerX, y, 14, pdf.Regular, pdf.AlignCenter, line)
+		y += 20
+	}
+
+	if cert.InstructorName != nil && *cert.InstructorName != "" {
+		doc.Line(signatureX1, signatureY, signatureX2, signatureY, 0.75)
+		mid := (signatureX1 + signatureX2) / 2
+		doc.Text(mid, signatureY+16, 11, pdf.Bold, pdf.AlignCenter, *cert.InstructorName)
+		doc.Text(mid, signatureY+30, 9, pdf.Regular, pdf.AlignCenter, "Instrutor")
+	}
+	doc.Text(signatureX1, 540, 9, pdf.Regular, pdf.AlignLeft, "Emitido em "+cert.IssueDate.Format("02/01/2006"))
+
+	if code, err := qrcode.Encode(validationURL); err == nil {
+		drawQR(doc, code, qrLeft, qrTop, qrSide)
+	}
+	labelX := qrLeft + qrSide/2
+	doc.Text(labelX, qrTop+qrSide+14, 8, pdf.Regular, pdf.AlignCenter, "Código de validação")
+	doc.Text(labelX, qrTop+qrSide+27, 10, pdf.Bold, pdf.AlignCenter, cert.ValidationCode)
+
+	return doc.Bytes()
+}
+
+// drawQR draws a QR code in a side x side square, one filled rectangle per run of dark
+// modules on a row. The page is white, so light modules and the quiet zone are not drawn.
+func drawQR(doc *pdf.Document, code *qrcode.Code, x, y, side float64) {
+	n := code.Size()
+	module := side / float64(n)
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; {
+			if !code.Dark(col, row) {
+				col++
+				continue
+			}
+			start := col
+			for col < n && code.Dark(col, row) {
+				col++
+			}
+			doc.FillRect(x+float64(start)*module, y+float64(row)*module, float64(col-start)*module, module, 0)
+		}
+	}
+}
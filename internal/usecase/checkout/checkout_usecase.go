@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"log"
+	"math"
+	"sync/atomic"
 	"time"
 
 	"github.com/condotrack/api/internal/config"
@@ -11,7 +13,13 @@ import (
 	"github.com/condotrack/api/internal/domain/gateway"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/document"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/condotrack/api/pkg/signedurl"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
 // CheckoutRequest represents the request to create a checkout
@@ -30,11 +38,16 @@ type CheckoutRequest struct {
 	InstructorName string `json:"instructor_name,omitempty"`
 
 	// Payment info
-	Amount        float64 `json:"amount" binding:"required,gt=0"`
-	DiscountCode  string  `json:"discount_code,omitempty"`
-	PaymentMethod string  `json:"payment_method" binding:"required"` // pix, boleto, card
+	Amount        money.Cents `json:"amount" binding:"required,gt=0"`
+	DiscountCode  string      `json:"discount_code,omitempty"`
+	PaymentMethod string      `json:"payment_method" binding:"required"` // pix, boleto, card
 
 	// Card info (required if payment_method is card)
+	CardInfo
+}
+
+// CardInfo holds the credit card fields shared by checkout-style requests
+type CardInfo struct {
 	CardNumber   string `json:"card_number,omitempty"`
 	CardExpMonth string `json:"card_exp_month,omitempty"`
 	CardExpYear  string `json:"card_exp_year,omitempty"`
@@ -47,6 +60,45 @@ type CheckoutRequest struct {
 	Installments int    `json:"installments,omitempty"`
 }
 
+// RenewalRequest represents the request to renew an enrollment
+type RenewalRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required"` // pix, boleto, card
+
+	// Card info (required if payment_method is card)
+	CardInfo
+}
+
+// RenewalResponse represents the renewal checkout response
+type RenewalResponse struct {
+	EnrollmentID    string      `json:"enrollment_id"`
+	RenewalID       string      `json:"renewal_id"`
+	PaymentID       string      `json:"payment_id"`
+	Status          string      `json:"status"`
+	BaseAmount      money.Cents `json:"base_amount"`
+	DiscountPercent float64     `json:"discount_percent"`
+	DiscountAmount  money.Cents `json:"discount_amount"`
+	FinalAmount     money.Cents `json:"final_amount"`
+	ExtensionDays   int         `json:"extension_days"`
+
+	// Fee passed through to the buyer, included in TotalAmount
+	FeeSurcharge money.Cents             `json:"fee_surcharge"`
+	TotalAmount  money.Cents             `json:"total_amount"`
+	LineItems    entity.PaymentLineItems `json:"line_items"`
+
+	// PIX specific
+	PixQRCode         string `json:"pix_qr_code,omitempty"`
+	PixCopyPaste      string `json:"pix_copy_paste,omitempty"`
+	PixExpirationDate string `json:"pix_expiration_date,omitempty"`
+
+	// Boleto specific
+	BoletoURL     string `json:"boleto_url,omitempty"`
+	BoletoBarCode string `json:"boleto_bar_code,omitempty"`
+	BoletoDueDate string `json:"boleto_due_date,omitempty"`
+
+	// Card/invoice
+	InvoiceURL string `json:"invoice_url,omitempty"`
+}
+
 // CheckoutResponse represents the checkout response
 type CheckoutResponse struct {
 	EnrollmentID string `json:"enrollment_id"`
@@ -63,13 +115,21 @@ type CheckoutResponse struct {
 	BoletoBarCode string `json:"boleto_bar_code,omitempty"`
 	BoletoDueDate string `json:"boleto_due_date,omitempty"`
 
+	// Boletos of a carnê, the first one being the boleto above
+	Installments []BoletoInstallment `json:"installments,omitempty"`
+
 	// Revenue split info
-	GrossAmount      float64 `json:"gross_amount"`
-	DiscountAmount   float64 `json:"discount_amount"`
-	PaymentFee       float64 `json:"payment_fee"`
-	NetAmount        float64 `json:"net_amount"`
-	InstructorAmount float64 `json:"instructor_amount"`
-	PlatformAmount   float64 `json:"platform_amount"`
+	GrossAmount      money.Cents `json:"gross_amount"`
+	DiscountAmount   money.Cents `json:"discount_amount"`
+	PaymentFee       money.Cents `json:"payment_fee"`
+	NetAmount        money.Cents `json:"net_amount"`
+	InstructorAmount money.Cents `json:"instructor_amount"`
+	PlatformAmount   money.Cents `json:"platform_amount"`
+
+	// What the buyer pays: the amount after discounts plus the fee passed through
+	FeeSurcharge money.Cents             `json:"fee_surcharge"`
+	TotalAmount  money.Cents             `json:"total_amount"`
+	LineItems    entity.PaymentLineItems `json:"line_items,omitempty"`
 
 	// Coupon info
 	CouponCode string `json:"coupon_code,omitempty"`
@@ -79,6 +139,33 @@ type CheckoutResponse struct {
 type UseCase interface {
 	CreateCheckout(ctx context.Context, req *CheckoutRequest) (*CheckoutResponse, error)
 	GetCheckoutStatus(ctx context.Context, enrollmentID string) (*CheckoutResponse, error)
+	RenewEnrollment(ctx context.Context, enrollmentID string, req *RenewalRequest, userID, role string) (*RenewalResponse, error)
+
+	// RetryCheckout charges again a pending or expired enrollment whose payment failed or
+	// expired; only the enrolled student or an admin may retry
+	RetryCheckout(ctx context.Context, enrollmentID string, req *RetryRequest, userID, role string) (*CheckoutResponse, error)
+	CancelEnrollment(ctx context.Context, enrollmentID string, req *entity.CancelEnrollmentRequest, cancelledBy string) (*entity.CancelEnrollmentResponse, error)
+
+	// GetPaymentMethods returns the payment methods, fees and installments offered for a course
+	GetPaymentMethods(ctx context.Context, courseID, discountCode string) (*PaymentMethods, error)
+
+	// StartCustomerBackfill maps the payers of past payments to their gateway customers
+	StartCustomerBackfill(lc *lifecycle.Manager)
+
+	// SetFeePassThrough switches fee pass-through pricing on or off ("true" or "false")
+	SetFeePassThrough(value string)
+
+	// CreateSession opens a checkout session for a course and returns its signed token
+	CreateSession(ctx context.Context, req *entity.CreateCheckoutSessionRequest) (*Session, error)
+
+	// CheckoutSession pays for the course of the session of a token
+	CheckoutSession(ctx context.Context, token string, req *SessionCheckoutRequest) (*CheckoutResponse, error)
+
+	// GetSessionStatus returns the status of the session of a token
+	GetSessionStatus(ctx context.Context, token string) (*SessionStatus, error)
+
+	// StartSessionCleanup deletes the checkout sessions that expired unpaid
+	StartSessionCleanup(lc *lifecycle.Manager)
 }
 
 type checkoutUseCase struct {
@@ -87,77 +174,105 @@ type checkoutUseCase struct {
 	paymentRepo       repository.PaymentRepository
 	couponRepo        repository.CouponRepository
 	paymentTxnRepo    repository.PaymentTransactionRepository
+	courseRepo        repository.CourseRepository
+	renewalRepo       repository.EnrollmentRenewalRepository
+	cancellationRepo  repository.EnrollmentCancellationRepository
+	revenueSplitRepo  repository.RevenueSplitRepository
+	ledgerRepo        repository.InstructorLedgerRepository
+	customerRepo      repository.GatewayCustomerRepository
+	sessionRepo       repository.CheckoutSessionRepository
 	db                *database.MySQL
 	instructorPercent float64
 	platformPercent   float64
+	renewalDiscount   float64
+	renewalDays       int
+	withdrawalDays    int
+	methods           []string
+	maxInstallments   int
+	minInstallment    money.Cents
+	sessionSigner     *signedurl.Signer
+	sessionTTL        time.Duration
+
+	feePassThrough        atomic.Bool
+	feePassThroughDefault bool
 }
 
-// NewUseCase creates a new checkout use case
+// NewUseCase creates a new checkout use case. Checkout session tokens are signed with
+// CheckoutSessionSecret and valid for CheckoutSessionTTL minutes.
 func NewUseCase(
 	gw gateway.PaymentGateway,
 	matriculaRepo repository.MatriculaRepository,
 	paymentRepo repository.PaymentRepository,
 	couponRepo repository.CouponRepository,
 	paymentTxnRepo repository.PaymentTransactionRepository,
+	courseRepo repository.CourseRepository,
+	renewalRepo repository.EnrollmentRenewalRepository,
+	cancellationRepo repository.EnrollmentCancellationRepository,
+	revenueSplitRepo repository.RevenueSplitRepository,
+	ledgerRepo repository.InstructorLedgerRepository,
+	customerRepo repository.GatewayCustomerRepository,
+	sessionRepo repository.CheckoutSessionRepository,
 	db *database.MySQL,
 	cfg *config.Config,
 ) UseCase {
-	return &checkoutUseCase{
+	uc := &checkoutUseCase{
 		gw:                gw,
 		matriculaRepo:     matriculaRepo,
 		paymentRepo:       paymentRepo,
 		couponRepo:        couponRepo,
 		paymentTxnRepo:    paymentTxnRepo,
+		courseRepo:        courseRepo,
+		renewalRepo:       renewalRepo,
+		cancellationRepo:  cancellationRepo,
+		revenueSplitRepo:  revenueSplitRepo,
+		ledgerRepo:        ledgerRepo,
+		customerRepo:      customerRepo,
+		sessionRepo:       sessionRepo,
 		db:                db,
 		instructorPercent: cfg.RevenueInstructorPercent,
 		platformPercent:   cfg.RevenuePlatformPercent,
+		renewalDiscount:   cfg.RenewalDiscountPercent,
+		renewalDays:       cfg.RenewalExtensionDays,
+		withdrawalDays:    cfg.RefundWithdrawalDays,
+		methods:           paymentMethods(cfg.CheckoutPaymentMethods),
+		maxInstallments:   cfg.CheckoutMaxInstallments,
+		minInstallment:    money.FromFloat(cfg.CheckoutMinInstallment),
+		sessionSigner:     signedurl.New(cfg.CheckoutSessionSecret),
+		sessionTTL:        time.Duration(cfg.CheckoutSessionTTL) * time.Minute,
+
+		feePassThroughDefault: cfg.CheckoutFeePassThrough,
 	}
+	uc.feePassThrough.Store(cfg.CheckoutFeePassThrough)
+	return uc
 }
 
 // CreateCheckout creates a complete checkout with enrollment, payment record, and gateway charge
 func (uc *checkoutUseCase) CreateCheckout(ctx context.Context, req *CheckoutRequest) (*CheckoutResponse, error) {
-	// Validate payment method
-	if req.PaymentMethod != "pix" && req.PaymentMethod != "boleto" && req.PaymentMethod != "card" {
-		return nil, errors.New("invalid payment method. Use: pix, boleto, or card")
+	if err := validatePaymentMethod(req.PaymentMethod, &req.CardInfo); err != nil {
+		return nil, err
 	}
-
-	// Validate card info if payment method is card
-	if req.PaymentMethod == "card" && (req.CardNumber == "" || req.CardCVV == "") {
-		return nil, errors.New("credit card information is required for card payment")
+	// Documents are stored and sent to the gateway as digits only
+	cpf, err := document.NormalizeCPF(req.StudentCPF)
+	if err != nil {
+		return nil, invalidDocument("student_cpf", "CPF")
+	}
+	req.StudentCPF = cpf
+	if req.StudentPhone, err = normalizePhone("student_phone", req.StudentPhone); err != nil {
+		return nil, err
 	}
 
-	// --- Coupon validation ---
-	var coupon *entity.Coupon
-	var discountAmount float64
-	finalAmount := req.Amount
-
-	if req.DiscountCode != "" {
-		var err error
-		coupon, err = uc.couponRepo.FindByCode(ctx, req.DiscountCode)
-		if err != nil {
-			return nil, err
-		}
-		if coupon == nil {
-			return nil, errors.New("invalid coupon code")
-		}
-
-		// Check per-user usage limit
-		if coupon.MaxUsesPerUser != nil && req.StudentID != "" {
-			usageCount, err := uc.couponRepo.CountUsageByUser(ctx, coupon.ID, req.StudentID)
-			if err != nil {
-				return nil, err
-			}
-			if usageCount >= *coupon.MaxUsesPerUser {
-				return nil, errors.New("coupon usage limit exceeded for this user")
-			}
-		}
-
-		discountAmount = coupon.CalculateDiscount(req.Amount)
-		if discountAmount <= 0 {
-			return nil, errors.New("coupon is not applicable to this order")
-		}
-		finalAmount = req.Amount - discountAmount
+	coupon, discountAmount, err := uc.applyCoupon(ctx, req.DiscountCode, req.StudentID, req.Amount)
+	if err != nil {
+		return nil, err
 	}
+	finalAmount := req.Amount - discountAmount
+	if err := uc.checkOffered(req.PaymentMethod, finalAmount, req.Installments); err != nil {
+		return nil, err
+	}
+	// Boleto installments are a carnê, one boleto per installment, each with its own fee
+	charges := chargeCount(req.PaymentMethod, req.Installments)
+	surcharge := uc.feeSurcharge(finalAmount, req.PaymentMethod) * money.Cents(charges)
+	chargeAmount := finalAmount + surcharge
 
 	// Start transaction
 	tx, err := uc.db.BeginTx(ctx)
@@ -166,13 +281,14 @@ func (uc *checkoutUseCase) CreateCheckout(ctx context.Context, req *CheckoutRequ
 	}
 	defer tx.Rollback()
 
-	// Create or get gateway customer
-	customer, err := uc.gw.CreateCustomer(ctx, gateway.CreateCustomerRequest{
+	// Reuse the gateway customer of the CPF, creating it on the first purchase
+	customer := gateway.CreateCustomerRequest{
 		Name:     req.StudentName,
 		Email:    req.StudentEmail,
 		Document: req.StudentCPF,
 		Phone:    req.StudentPhone,
-	})
+	}
+	customerGatewayID, customerGateway, err := uc.gatewayCustomer(ctx, req.StudentID, customer)
 	if err != nil {
 		return nil, err
 	}
@@ -191,14 +307,14 @@ func (uc *checkoutUseCase) CreateCheckout(ctx context.Context, req *CheckoutRequ
 		InstructorID:    &req.InstructorID,
 		InstructorName:  &req.InstructorName,
 		PaymentStatus:   entity.PaymentStatusPending,
-		Amount:          req.Amount,
-		DiscountAmount:  discountAmount,
-		FinalAmount:     finalAmount,
+		Amount:          req.Amount.Float(),
+		DiscountAmount:  discountAmount.Float(),
+		FinalAmount:     finalAmount.Float(),
 		PaymentMethod:   &req.PaymentMethod,
 		EnrollmentDate:  time.Now(),
 		Status:          entity.EnrollmentStatusPending,
 		Progress:        0,
-		AsaasCustomerID: &customer.GatewayID,
+		AsaasCustomerID: &customerGatewayID,
 		CreatedAt:       time.Now(),
 	}
 
@@ -211,95 +327,101 @@ func (uc *checkoutUseCase) CreateCheckout(ctx context.Context, req *CheckoutRequ
 	dueDate := time.Now().AddDate(0, 0, 3) // 3 days from now
 	description := "Matrícula: " + req.CourseName
 
-	switch req.PaymentMethod {
-	case "pix":
-		gatewayResp, err = uc.gw.CreatePixPayment(ctx, gateway.CreatePaymentRequest{
-			CustomerGatewayID: customer.GatewayID,
-			Amount:            finalAmount,
-			Description:       description,
-			DueDate:           dueDate,
-			ExternalReference: enrollmentID,
-		})
-	case "boleto":
-		gatewayResp, err = uc.gw.CreateBoletoPayment(ctx, gateway.CreatePaymentRequest{
-			CustomerGatewayID: customer.GatewayID,
-			Amount:            finalAmount,
-			Description:       description,
-			DueDate:           dueDate,
-			ExternalReference: enrollmentID,
-		})
-	case "card":
-		gatewayResp, err = uc.gw.CreateCardPayment(ctx, gateway.CreateCardPaymentRequest{
-			CreatePaymentRequest: gateway.CreatePaymentRequest{
-				CustomerGatewayID: customer.GatewayID,
-				Amount:            finalAmount,
-				Description:       description,
-				DueDate:           dueDate,
-				ExternalReference: enrollmentID,
-			},
-			CardNumber:   req.CardNumber,
-			CardExpMonth: req.CardExpMonth,
-			CardExpYear:  req.CardExpYear,
-			CardCVV:      req.CardCVV,
-			HolderName:   req.HolderName,
-			HolderEmail:  req.HolderEmail,
-			HolderDoc:    req.HolderDoc,
-			HolderZip:    req.HolderZip,
-			HolderPhone:  req.HolderPhone,
-			Installments: req.Installments,
-		})
+	base := gateway.CreatePaymentRequest{
+		CustomerGatewayID: customerGatewayID,
+		Amount:            chargeAmount.Float(),
+		Description:       description,
+		DueDate:           dueDate,
+		ExternalReference: enrollmentID,
+		CustomerGateway:   customerGateway,
+		Customer:          &customer,
+	}
+	var carnet []*gateway.PaymentResponse
+	if charges > 1 {
+		carnet, err = uc.createCarnet(ctx, base, chargeAmount, charges)
+		if err == nil {
+			gatewayResp = carnet[0]
+		}
+	} else {
+		gatewayResp, err = uc.createGatewayCharge(ctx, req.PaymentMethod, base, req.CardInfo)
 	}
-
 	if err != nil {
-		return nil, err
+		return nil, gateway.Failure(err)
 	}
 
-	// Update enrollment with gateway payment info
+	// A charge moved to the fallback gateway has a customer of its own there
+	chargedOn := uc.chargedOn(gatewayResp)
+	if gatewayResp.CustomerGatewayID != "" {
+		customerGatewayID = gatewayResp.CustomerGatewayID
+		uc.saveCustomer(ctx, req.StudentID, chargedOn, req.StudentCPF, customerGatewayID)
+	}
+
+	// Update enrollment with gateway payment info; the first boleto stands for a carnê
 	enrollment.AsaasPaymentID = &gatewayResp.GatewayPaymentID
+	enrollment.AsaasCustomerID = &customerGatewayID
 	if err := uc.matriculaRepo.UpdateWithTx(ctx, tx, enrollment); err != nil {
 		return nil, err
 	}
 
-	// Calculate fees using the gateway's fee config
-	fees := uc.gw.GetFees()
-	gatewayFee := calculateGatewayFee(finalAmount, req.PaymentMethod, fees)
+	// Calculate fees using the fee config of the gateway that created the charge
+	fees := gateway.ForPayment(uc.gw, chargedOn).GetFees()
+	chargeFee := calculateGatewayFee(chargeAmount, req.PaymentMethod, fees)
+	gatewayFee := chargeFee * money.Cents(charges)
 
 	// Create payment record in payments table
 	paymentID := uuid.New().String()
 	var couponID *string
+	discountLabel := "Desconto"
 	if coupon != nil {
 		couponID = &coupon.ID
+		discountLabel = "Cupom " + coupon.Code
 	}
 	gwPaymentID := gatewayResp.GatewayPaymentID
 	invoiceURL := gatewayResp.InvoiceURL
 	dueDatePtr := dueDate
 
 	paymentRecord := &entity.Payment{
-		ID:                paymentID,
-		EnrollmentID:      enrollmentID,
-		PayerUserID:       &req.StudentID,
-		PayerName:         req.StudentName,
-		PayerEmail:        req.StudentEmail,
-		PayerCPF:          &req.StudentCPF,
-		GrossAmount:       req.Amount,
-		DiscountAmount:    discountAmount,
-		NetAmount:         finalAmount,
-		GatewayFee:        gatewayFee,
-		PaymentMethod:     req.PaymentMethod,
-		Gateway:           uc.gw.Name(),
-		GatewayPaymentID:  &gwPaymentID,
-		GatewayCustomerID: &customer.GatewayID,
-		GatewayInvoiceURL: nilIfEmpty(invoiceURL),
-		InstallmentCount:  maxInt(req.Installments, 1),
-		Status:            entity.FinPaymentStatusPending,
-		CouponID:          couponID,
-		DueDate:           &dueDatePtr,
-		CreatedAt:         time.Now(),
+		ID:                   paymentID,
+		EnrollmentID:         enrollmentID,
+		PayerUserID:          &req.StudentID,
+		PayerName:            req.StudentName,
+		PayerEmail:           req.StudentEmail,
+		PayerCPF:             &req.StudentCPF,
+		GrossAmount:          req.Amount + surcharge,
+		DiscountAmount:       discountAmount,
+		NetAmount:            chargeAmount,
+		GatewayFee:           gatewayFee,
+		FeeSurcharge:         surcharge,
+		PaymentMethod:        req.PaymentMethod,
+		Gateway:              chargedOn,
+		GatewayPaymentID:     &gwPaymentID,
+		GatewayCustomerID:    &customerGatewayID,
+		GatewayInvoiceURL:    nilIfEmpty(invoiceURL),
+		GatewayInstallmentID: nilIfEmpty(gatewayResp.InstallmentID),
+		InstallmentCount:     maxInt(req.Installments, 1),
+		Status:               entity.FinPaymentStatusPending,
+		CouponID:             couponID,
+		DueDate:              &dueDatePtr,
+		CreatedAt:            time.Now(),
+		LineItems:            lineItems(description, req.Amount, discountAmount, discountLabel, surcharge, req.PaymentMethod),
+	}
+
+	// The boletos of a carnê are payments of their own under this one, which keeps the totals
+	var installments []*entity.Payment
+	if carnet != nil {
+		paymentRecord.GatewayPaymentID = nil
+		paymentRecord.GatewayInvoiceURL = nil
+		installments = carnetPayments(paymentRecord, carnet, chargeFee)
 	}
 
 	if err := uc.paymentRepo.CreateWithTx(ctx, tx, paymentRecord); err != nil {
 		return nil, err
 	}
+	for _, installment := range installments {
+		if err := uc.paymentRepo.CreateWithTx(ctx, tx, installment); err != nil {
+			return nil, err
+		}
+	}
 
 	// Record coupon usage
 	if coupon != nil {
@@ -312,9 +434,9 @@ func (uc *checkoutUseCase) CreateCheckout(ctx context.Context, req *CheckoutRequ
 			CourseID:        &req.CourseID,
 			DiscountType:    coupon.DiscountType,
 			DiscountValue:   coupon.DiscountValue,
-			DiscountApplied: discountAmount,
-			OriginalAmount:  req.Amount,
-			FinalAmount:     finalAmount,
+			DiscountApplied: discountAmount.Float(),
+			OriginalAmount:  req.Amount.Float(),
+			FinalAmount:     finalAmount.Float(),
 		}
 		if err := uc.couponRepo.CreateUsageWithTx(ctx, tx, usage); err != nil {
 			log.Printf("Failed to create coupon usage: %v", err)
@@ -333,9 +455,9 @@ func (uc *checkoutUseCase) CreateCheckout(ctx context.Context, req *CheckoutRequ
 	uc.logPaymentCreated(ctx, paymentRecord, gatewayResp)
 
 	// Calculate revenue split for response
-	netAfterFee := finalAmount - gatewayFee
-	instructorAmount := netAfterFee * (uc.instructorPercent / 100)
-	platformAmount := netAfterFee * (uc.platformPercent / 100)
+	netAfterFee := chargeAmount - gatewayFee
+	instructorAmount := netAfterFee.Percent(uc.instructorPercent)
+	platformAmount := netAfterFee.Percent(uc.platformPercent)
 
 	// Build response
 	response := &CheckoutResponse{
@@ -348,6 +470,9 @@ func (uc *checkoutUseCase) CreateCheckout(ctx context.Context, req *CheckoutRequ
 		NetAmount:        netAfterFee,
 		InstructorAmount: instructorAmount,
 		PlatformAmount:   platformAmount,
+		FeeSurcharge:     surcharge,
+		TotalAmount:      chargeAmount,
+		LineItems:        paymentRecord.LineItems,
 	}
 
 	if coupon != nil {
@@ -366,6 +491,9 @@ func (uc *checkoutUseCase) CreateCheckout(ctx context.Context, req *CheckoutRequ
 		response.BoletoBarCode = gatewayResp.BoletoBarCode
 		response.BoletoDueDate = gatewayResp.DueDate
 	}
+	if carnet != nil {
+		response.Installments = carnetInstallments(installments, carnet)
+	}
 
 	return response, nil
 }
@@ -384,6 +512,7 @@ func (uc *checkoutUseCase) GetCheckoutStatus(ctx context.Context, enrollmentID s
 		EnrollmentID: enrollmentID,
 		Status:       enrollment.PaymentStatus,
 	}
+	chargeAmount := money.FromFloat(enrollment.FinalAmount)
 
 	// Try to get payment info from payments table
 	payments, err := uc.paymentRepo.FindByEnrollmentID(ctx, enrollmentID)
@@ -393,10 +522,13 @@ func (uc *checkoutUseCase) GetCheckoutStatus(ctx context.Context, enrollmentID s
 		response.Status = p.Status
 		response.GrossAmount = p.GrossAmount
 		response.DiscountAmount = p.DiscountAmount
+		response.FeeSurcharge = p.FeeSurcharge
+		response.LineItems = p.LineItems
+		chargeAmount = p.NetAmount
 
 		// Get live status from gateway if we have a gateway payment ID
 		if p.GatewayPaymentID != nil && *p.GatewayPaymentID != "" {
-			gwPayment, err := uc.gw.GetPayment(ctx, *p.GatewayPaymentID)
+			gwPayment, err := gateway.ForPayment(uc.gw, p.Gateway).GetPayment(ctx, *p.GatewayPaymentID)
 			if err == nil {
 				response.Status = gwPayment.Status
 				if gwPayment.PixQRCodeBase64 != "" {
@@ -411,7 +543,7 @@ func (uc *checkoutUseCase) GetCheckoutStatus(ctx context.Context, enrollmentID s
 	} else {
 		// Fallback: get info from gateway via enrollment's asaas payment ID
 		if enrollment.AsaasPaymentID != nil && *enrollment.AsaasPaymentID != "" {
-			gwPayment, err := uc.gw.GetPayment(ctx, *enrollment.AsaasPaymentID)
+			gwPayment, err := gateway.ForPayment(uc.gw, "asaas").GetPayment(ctx, *enrollment.AsaasPaymentID)
 			if err == nil {
 				response.PaymentID = gwPayment.GatewayPaymentID
 				response.Status = gwPayment.Status
@@ -433,17 +565,618 @@ func (uc *checkoutUseCase) GetCheckoutStatus(ctx context.Context, enrollmentID s
 	}
 
 	fees := uc.gw.GetFees()
-	gatewayFee := calculateGatewayFee(enrollment.FinalAmount, paymentMethod, fees)
-	netAfterFee := enrollment.FinalAmount - gatewayFee
+	gatewayFee := calculateGatewayFee(chargeAmount, paymentMethod, fees)
+	netAfterFee := chargeAmount - gatewayFee
 
+	response.TotalAmount = chargeAmount
 	response.PaymentFee = gatewayFee
 	response.NetAmount = netAfterFee
-	response.InstructorAmount = netAfterFee * (uc.instructorPercent / 100)
-	response.PlatformAmount = netAfterFee * (uc.platformPercent / 100)
+	response.InstructorAmount = netAfterFee.Percent(uc.instructorPercent)
+	response.PlatformAmount = netAfterFee.Percent(uc.platformPercent)
 
 	return response, nil
 }
 
+// ErrRenewalForbidden is returned when someone other than the enrolled student or an admin renews
+var ErrRenewalForbidden = apperror.New(apperror.CodeForbidden, "only the enrolled student or an admin can renew this enrollment")
+
+// RenewEnrollment creates a discounted renewal charge for an existing enrollment.
+// The new payment is linked to the original enrollment and an enrollment_renewals
+// row; the expiration date is extended by the webhook once the payment is confirmed.
+// Only the enrolled student and admins may renew, and an enrollment holds at most
+// one pending renewal.
+func (uc *checkoutUseCase) RenewEnrollment(ctx context.Context, enrollmentID string, req *RenewalRequest, userID, role string) (*RenewalResponse, error) {
+	if err := validatePaymentMethod(req.PaymentMethod, &req.CardInfo); err != nil {
+		return nil, err
+	}
+
+	enrollment, err := uc.matriculaRepo.FindByID(ctx, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, errors.New("enrollment not found")
+	}
+	if role != string(entity.RoleAdmin) && (userID == "" || enrollment.StudentID != userID) {
+		return nil, ErrRenewalForbidden
+	}
+	if enrollment.Status != entity.EnrollmentStatusActive && enrollment.Status != entity.EnrollmentStatusExpired &&
+		enrollment.Status != entity.EnrollmentStatusCompleted {
+		return nil, errors.New("only active, completed or expired enrollments can be renewed")
+	}
+	if enrollment.PaymentStatus != entity.PaymentStatusConfirmed && enrollment.PaymentStatus != entity.PaymentStatusComped {
+		return nil, errors.New("enrollment payment is not settled")
+	}
+
+	// The enrollment row stays locked until the renewal is recorded, so a concurrent
+	// renewal waits here and then finds this one pending instead of charging again
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	locked, err := uc.matriculaRepo.FindByIDForUpdateWithTx(ctx, tx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if locked == nil {
+		return nil, errors.New("enrollment not found")
+	}
+	pending, err := uc.renewalRepo.FindPendingByEnrollmentIDWithTx(ctx, tx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, errors.New("enrollment already has a pending renewal")
+	}
+
+	// Renewal is priced from the current course price, falling back to the original amount
+	baseAmount := money.FromFloat(enrollment.Amount)
+	course, err := uc.courseRepo.FindByID(ctx, enrollment.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if course != nil {
+		baseAmount = money.FromFloat(course.EffectivePrice())
+	}
+	if baseAmount <= 0 {
+		return nil, errors.New("course has no price to renew")
+	}
+
+	discountPercent := uc.renewalDiscount
+	if discountPercent < 0 || discountPercent >= 100 {
+		discountPercent = 0
+	}
+	discountAmount := baseAmount.Percent(discountPercent)
+	finalAmount := baseAmount - discountAmount
+	if err := uc.checkOffered(req.PaymentMethod, finalAmount, req.Installments); err != nil {
+		return nil, err
+	}
+	if chargeCount(req.PaymentMethod, req.Installments) > 1 {
+		return nil, apperror.New(apperror.CodeValidationFailed, "renewals are charged in a single boleto")
+	}
+	surcharge := uc.feeSurcharge(finalAmount, req.PaymentMethod)
+	chargeAmount := finalAmount + surcharge
+
+	// Reuse the gateway customer from the original checkout when available
+	customerGatewayID, customerGateway := "", ""
+	if enrollment.AsaasCustomerID != nil {
+		customerGatewayID = *enrollment.AsaasCustomerID
+	}
+	var customer *gateway.CreateCustomerRequest
+	if enrollment.StudentCPF != nil && *enrollment.StudentCPF != "" {
+		customer = &gateway.CreateCustomerRequest{
+			Name:     enrollment.StudentName,
+			Email:    enrollment.StudentEmail,
+			Document: *enrollment.StudentCPF,
+			Phone:    derefString(enrollment.StudentPhone),
+		}
+	}
+	if customerGatewayID == "" {
+		if customer == nil {
+			return nil, errors.New("student CPF is required to create a renewal charge")
+		}
+		customerGatewayID, customerGateway, err = uc.gatewayCustomer(ctx, enrollment.StudentID, *customer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	renewalID := uuid.New().String()
+	dueDate := time.Now().AddDate(0, 0, 3)
+	description := "Renovação de matrícula: " + enrollment.CourseName
+	gatewayResp, err := uc.createGatewayCharge(ctx, req.PaymentMethod, gateway.CreatePaymentRequest{
+		CustomerGatewayID: customerGatewayID,
+		Amount:            chargeAmount.Float(),
+		Description:       description,
+		DueDate:           dueDate,
+		ExternalReference: enrollment.ID,
+		CustomerGateway:   customerGateway,
+		Customer:          customer,
+	}, req.CardInfo)
+	if err != nil {
+		return nil, gateway.Failure(err)
+	}
+	chargedOn := uc.chargedOn(gatewayResp)
+	if gatewayResp.CustomerGatewayID != "" {
+		customerGatewayID = gatewayResp.CustomerGatewayID
+		uc.saveCustomer(ctx, enrollment.StudentID, chargedOn, customer.Document, customerGatewayID)
+	}
+
+	fees := gateway.ForPayment(uc.gw, chargedOn).GetFees()
+	gwPaymentID := gatewayResp.GatewayPaymentID
+	paymentRecord := &entity.Payment{
+		ID:                   uuid.New().String(),
+		EnrollmentID:         enrollment.ID,
+		PayerUserID:          &enrollment.StudentID,
+		PayerName:            enrollment.StudentName,
+		PayerEmail:           enrollment.StudentEmail,
+		PayerCPF:             enrollment.StudentCPF,
+		GrossAmount:          baseAmount + surcharge,
+		DiscountAmount:       discountAmount,
+		NetAmount:            chargeAmount,
+		GatewayFee:           calculateGatewayFee(chargeAmount, req.PaymentMethod, fees),
+		FeeSurcharge:         surcharge,
+		PaymentMethod:        req.PaymentMethod,
+		Gateway:              chargedOn,
+		GatewayPaymentID:     &gwPaymentID,
+		GatewayCustomerID:    &customerGatewayID,
+		GatewayInvoiceURL:    nilIfEmpty(gatewayResp.InvoiceURL),
+		GatewayInstallmentID: nilIfEmpty(gatewayResp.InstallmentID),
+		InstallmentCount:     maxInt(req.Installments, 1),
+		Status:               entity.FinPaymentStatusPending,
+		DueDate:              &dueDate,
+		CreatedAt:            time.Now(),
+		LineItems:            lineItems(description, baseAmount, discountAmount, "Desconto de renovação", surcharge, req.PaymentMethod),
+	}
+	if err := uc.paymentRepo.CreateWithTx(ctx, tx, paymentRecord); err != nil {
+		return nil, err
+	}
+
+	renewal := &entity.EnrollmentRenewal{
+		ID:                 renewalID,
+		EnrollmentID:       enrollment.ID,
+		PaymentID:          paymentRecord.ID,
+		BaseAmount:         baseAmount.Float(),
+		DiscountPercent:    discountPercent,
+		FinalAmount:        finalAmount.Float(),
+		ExtensionDays:      uc.renewalDays,
+		PreviousExpiration: enrollment.ExpirationDate,
+		Status:             entity.RenewalStatusPending,
+		CreatedAt:          time.Now(),
+	}
+	if err := uc.renewalRepo.CreateWithTx(ctx, tx, renewal); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	uc.logPaymentCreated(ctx, paymentRecord, gatewayResp)
+
+	result := &RenewalResponse{
+		EnrollmentID:    enrollment.ID,
+		RenewalID:       renewal.ID,
+		PaymentID:       paymentRecord.ID,
+		Status:          gatewayResp.Status,
+		BaseAmount:      baseAmount,
+		DiscountPercent: discountPercent,
+		DiscountAmount:  discountAmount,
+		FinalAmount:     finalAmount,
+		ExtensionDays:   renewal.ExtensionDays,
+		FeeSurcharge:    surcharge,
+		TotalAmount:     chargeAmount,
+		LineItems:       paymentRecord.LineItems,
+		InvoiceURL:      gatewayResp.InvoiceURL,
+	}
+	if req.PaymentMethod == "pix" {
+		result.PixQRCode = gatewayResp.PixQRCodeBase64
+		result.PixCopyPaste = gatewayResp.PixCopyPaste
+		result.PixExpirationDate = gatewayResp.PixExpiration
+	}
+	if req.PaymentMethod == "boleto" {
+		result.BoletoURL = gatewayResp.BoletoURL
+		result.BoletoBarCode = gatewayResp.BoletoBarCode
+		result.BoletoDueDate = gatewayResp.DueDate
+	}
+
+	return result, nil
+}
+
+// CancelEnrollment cancels an enrollment, refunds the payment according to the
+// refund policy (full within the withdrawal window, prorated after it) and
+// reverses the instructor share of the revenue split. The enrollment is claimed
+// as cancelling before the refund is sent, so it is refunded at most once.
+func (uc *checkoutUseCase) CancelEnrollment(ctx context.Context, enrollmentID string, req *entity.CancelEnrollmentRequest, cancelledBy string) (*entity.CancelEnrollmentResponse, error) {
+	enrollment, err := uc.matriculaRepo.FindByID(ctx, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, errors.New("enrollment not found")
+	}
+	if enrollment.Status == entity.EnrollmentStatusCancelled || enrollment.Status == entity.EnrollmentStatusCancelling {
+		return nil, errors.New("enrollment already cancelled or being cancelled")
+	}
+
+	// Most recent settled payment; comped and unpaid enrollments are cancelled without refund
+	var payment *entity.Payment
+	payments, err := uc.paymentRepo.FindByEnrollmentID(ctx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range payments {
+		if payments[i].Status == entity.FinPaymentStatusConfirmed || payments[i].Status == entity.FinPaymentStatusReceived {
+			payment = &payments[i]
+			break
+		}
+	}
+
+	now := time.Now()
+	purchasedAt := enrollment.EnrollmentDate
+	var paid money.Cents
+	var gatewayPaymentID string
+	gatewayName := "asaas" // enrollments predating payment records were charged on Asaas
+	if payment != nil {
+		gatewayName = payment.Gateway
+		paid = payment.GrossAmount - payment.DiscountAmount - payment.RefundedAmount
+		if payment.PaidAt != nil {
+			purchasedAt = *payment.PaidAt
+		}
+		if payment.GatewayPaymentID != nil {
+			gatewayPaymentID = *payment.GatewayPaymentID
+		}
+	} else if enrollment.PaymentStatus == entity.PaymentStatusConfirmed && enrollment.AsaasPaymentID != nil {
+		paid = money.FromFloat(enrollment.FinalAmount)
+		gatewayPaymentID = *enrollment.AsaasPaymentID
+	}
+
+	decision := entity.DecideRefund(paid.Float(), purchasedAt, enrollment.ExpirationDate, enrollment.Progress, now, uc.withdrawalDays)
+
+	cancellation := &entity.EnrollmentCancellation{
+		ID:                uuid.New().String(),
+		EnrollmentID:      enrollment.ID,
+		Policy:            decision.Policy,
+		Basis:             decision.Basis,
+		DaysSincePurchase: decision.DaysSincePurchase,
+		PaidAmount:        paid.Float(),
+		RefundPercent:     decision.RefundPercent,
+		RefundAmount:      decision.RefundAmount,
+		RefundStatus:      entity.CancellationRefundNotApplicable,
+		Reason:            req.Reason,
+		CreatedAt:         now,
+	}
+	if cancelledBy != "" {
+		cancellation.CancelledBy = &cancelledBy
+	}
+	if payment != nil {
+		cancellation.PaymentID = &payment.ID
+	}
+
+	if decision.RefundAmount > 0 {
+		if gatewayPaymentID == "" {
+			return nil, errors.New("payment has no gateway reference to refund")
+		}
+		cancellation.RefundStatus = entity.CancellationRefundRequested
+	}
+
+	// Claim the enrollment and record the cancellation before anything reaches the gateway: a
+	// concurrent or repeated cancel stops at the claim instead of refunding the payment again
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	claimed, err := uc.matriculaRepo.ClaimCancellationWithTx(ctx, tx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.New("enrollment already cancelled or being cancelled")
+	}
+	if err := uc.cancellationRepo.CreateWithTx(ctx, tx, cancellation); err != nil {
+		return nil, err
+	}
+
+	if decision.RefundAmount <= 0 {
+		if _, err := uc.completeCancellation(ctx, tx, enrollment, payment, decision, cancellation, now); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return &entity.CancelEnrollmentResponse{Enrollment: enrollment, Cancellation: cancellation}, nil
+	}
+
+	previousStatus := enrollment.Status
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	refundResp, err := gateway.ForPayment(uc.gw, gatewayName).RefundPayment(ctx, gatewayPaymentID, decision.RefundAmount)
+	if err != nil {
+		uc.releaseCancellation(ctx, enrollment, previousStatus, cancellation)
+		return nil, err
+	}
+	cancellation.GatewayRefundStatus = nilIfEmpty(refundResp.Status)
+
+	// The refund is issued: from here on the enrollment stays claimed, and if the local
+	// records cannot be saved the refund webhook finishes the cancellation
+	tx, err = uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	prevPaymentStatus := ""
+	if payment != nil {
+		prevPaymentStatus = payment.Status
+	}
+	splits, err := uc.completeCancellation(ctx, tx, enrollment, payment, decision, cancellation, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.cancellationRepo.UpdateRefundWithTx(ctx, tx, cancellation); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if payment != nil {
+		uc.logRefundRequested(ctx, payment, prevPaymentStatus, money.FromFloat(decision.RefundAmount), cancelledBy)
+	}
+
+	result := &entity.CancelEnrollmentResponse{
+		Enrollment:    enrollment,
+		Cancellation:  cancellation,
+		RevenueSplits: splits,
+	}
+	if len(splits) > 0 {
+		result.RevenueSplit = &splits[0]
+	}
+	return result, nil
+}
+
+// completeCancellation records a claimed cancellation: the refund on the payment, the
+// cancelled enrollment and the instructor share given back
+func (uc *checkoutUseCase) completeCancellation(ctx context.Context, tx *sqlx.Tx, enrollment *entity.Matricula, payment *entity.Payment, decision entity.RefundDecision, cancellation *entity.EnrollmentCancellation, now time.Time) ([]entity.RevenueSplit, error) {
+	if payment != nil && decision.RefundAmount > 0 {
+		payment.RefundedAmount += money.FromFloat(decision.RefundAmount)
+		payment.RefundedAt = &now
+		payment.Status = entity.FinPaymentStatusPartiallyRefunded
+		if decision.Policy == entity.RefundPolicyFull {
+			payment.Status = entity.FinPaymentStatusRefunded
+		}
+		if err := uc.paymentRepo.UpdateWithTx(ctx, tx, payment); err != nil {
+			return nil, err
+		}
+	}
+
+	enrollment.Status = entity.EnrollmentStatusCancelled
+	if decision.RefundAmount > 0 {
+		enrollment.PaymentStatus = entity.PaymentStatusRefunded
+	}
+	if err := uc.matriculaRepo.UpdateWithTx(ctx, tx, enrollment); err != nil {
+		return nil, err
+	}
+
+	if decision.RefundAmount <= 0 {
+		return nil, nil
+	}
+	return uc.reverseSplits(ctx, tx, enrollment.ID, payment, money.FromFloat(decision.RefundAmount), cancellation)
+}
+
+// releaseCancellation gives a claimed enrollment back its status after the gateway refused
+// the refund, so the cancellation can be retried; the attempt stays recorded as failed
+func (uc *checkoutUseCase) releaseCancellation(ctx context.Context, enrollment *entity.Matricula, status string, cancellation *entity.EnrollmentCancellation) {
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		log.Printf("Failed to release cancellation of enrollment %s: %v", enrollment.ID, err)
+		return
+	}
+	defer tx.Rollback()
+
+	enrollment.Status = status
+	cancellation.RefundStatus = entity.CancellationRefundFailed
+	if err := uc.matriculaRepo.UpdateWithTx(ctx, tx, enrollment); err != nil {
+		log.Printf("Failed to release cancellation of enrollment %s: %v", enrollment.ID, err)
+		return
+	}
+	if err := uc.cancellationRepo.UpdateRefundWithTx(ctx, tx, cancellation); err != nil {
+		log.Printf("Failed to release cancellation of enrollment %s: %v", enrollment.ID, err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to release cancellation of enrollment %s: %v", enrollment.ID, err)
+	}
+}
+
+// reverseSplits takes the refunded share out of the revenue splits of the payment, one per
+// paid installment, prorating the refund by what each split received. The first split is
+// the one recorded on the cancellation.
+func (uc *checkoutUseCase) reverseSplits(ctx context.Context, tx *sqlx.Tx, enrollmentID string, payment *entity.Payment, refund money.Cents, cancellation *entity.EnrollmentCancellation) ([]entity.RevenueSplit, error) {
+	var splits []entity.RevenueSplit
+	if payment != nil {
+		splitPaymentID := payment.ID
+		if payment.InstallmentOf != nil {
+			splitPaymentID = *payment.InstallmentOf
+		}
+		var err error
+		if splits, err = uc.revenueSplitRepo.FindAllByPaymentID(ctx, splitPaymentID); err != nil {
+			return nil, err
+		}
+	} else {
+		split, err := uc.revenueSplitRepo.FindByEnrollmentID(ctx, enrollmentID)
+		if err != nil {
+			return nil, err
+		}
+		if split != nil {
+			splits = append(splits, *split)
+		}
+	}
+	if len(splits) == 0 {
+		return nil, nil
+	}
+
+	cancellation.RevenueSplitID = &splits[0].ID
+	for i, share := range entity.ProrateRefund(splits, refund) {
+		if err := uc.reverseSplit(ctx, tx, &splits[i], share, cancellation); err != nil {
+			return nil, err
+		}
+	}
+	return splits, nil
+}
+
+// reverseSplit takes the refunded share out of one revenue split. Pending splits are
+// reduced in place (or marked reversed on a full refund); splits already paid out
+// are left untouched and the instructor share to recover is added to the cancellation.
+// Either way the instructor share given back is debited from the instructor ledger.
+func (uc *checkoutUseCase) reverseSplit(ctx context.Context, tx *sqlx.Tx, split *entity.RevenueSplit, refund money.Cents, cancellation *entity.EnrollmentCancellation) error {
+	if split.GrossAmount <= 0 || refund <= 0 {
+		return nil
+	}
+	refundShare := math.Min(1, float64(refund)/float64(split.GrossAmount))
+
+	if split.Status == entity.RevenueSplitStatusProcessed {
+		clawback := split.InstructorAmount.Mul(refundShare)
+		cancellation.InstructorClawback = (money.FromFloat(cancellation.InstructorClawback) + clawback).Float()
+		return uc.debitRefund(ctx, tx, split, cancellation, clawback)
+	}
+
+	if refundShare >= 1 {
+		if err := uc.revenueSplitRepo.UpdateStatusWithTx(ctx, tx, split.ID, entity.RevenueSplitStatusReversed); err != nil {
+			return err
+		}
+		split.Status = entity.RevenueSplitStatusReversed
+		return uc.debitRefund(ctx, tx, split, cancellation, split.InstructorAmount)
+	}
+
+	parties, err := uc.revenueSplitRepo.FindParties(ctx, split.ID)
+	if err != nil {
+		return err
+	}
+
+	keep := 1 - refundShare
+	previousInstructor := split.InstructorAmount
+	split.GrossAmount -= refund
+	split.NetAmount = split.NetAmount.Mul(keep)
+	split.InstructorAmount = split.InstructorAmount.Mul(keep)
+	split.PlatformAmount = split.PlatformAmount.Mul(keep)
+	split.PlatformFee = split.PlatformAmount
+	if len(parties) > 0 {
+		entity.ScaleParties(parties, keep, split.NetAmount)
+		split.ApplyParties(parties)
+		if err := uc.revenueSplitRepo.ReplacePartiesWithTx(ctx, tx, split); err != nil {
+			return err
+		}
+	}
+	if err := uc.revenueSplitRepo.UpdateAmountsWithTx(ctx, tx, split); err != nil {
+		return err
+	}
+	return uc.debitRefund(ctx, tx, split, cancellation, previousInstructor-split.InstructorAmount)
+}
+
+// debitRefund records the instructor share of one split given back by a cancellation
+func (uc *checkoutUseCase) debitRefund(ctx context.Context, tx *sqlx.Tx, split *entity.RevenueSplit, cancellation *entity.EnrollmentCancellation, amount money.Cents) error {
+	entry := entity.NewSplitLedgerEntry(split, entity.LedgerEntryRefund, split.EventID(cancellation.ID), -amount,
+		"Estorno - matrícula "+split.EnrollmentID, time.Now())
+	if entry == nil {
+		return nil
+	}
+	entry.ID = uuid.New().String()
+	return uc.ledgerRepo.CreateWithTx(ctx, tx, entry)
+}
+
+// applyCoupon validates a discount code for the amount and returns the coupon with its
+// discount; an empty code applies no discount. The per-user limit is checked when the
+// student is known.
+func (uc *checkoutUseCase) applyCoupon(ctx context.Context, code, studentID string, amount money.Cents) (*entity.Coupon, money.Cents, error) {
+	if code == "" {
+		return nil, 0, nil
+	}
+	coupon, err := uc.couponRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, 0, err
+	}
+	if coupon == nil {
+		return nil, 0, entity.ErrCouponNotFound
+	}
+
+	// Check per-user usage limit
+	if coupon.MaxUsesPerUser != nil && studentID != "" {
+		usageCount, err := uc.couponRepo.CountUsageByUser(ctx, coupon.ID, studentID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if usageCount >= *coupon.MaxUsesPerUser {
+			return nil, 0, entity.ErrCouponUserLimit
+		}
+	}
+
+	if err := coupon.CheckEligibility(amount.Float(), time.Now()); err != nil {
+		return nil, 0, err
+	}
+	discount := money.FromFloat(coupon.CalculateDiscount(amount.Float()))
+	if discount <= 0 {
+		return nil, 0, entity.ErrCouponNotApplicable
+	}
+	return coupon, discount, nil
+}
+
+// validatePaymentMethod checks the method is supported and card data is present for card
+// payments, and normalizes the document and phone of the card holder
+func validatePaymentMethod(method string, card *CardInfo) error {
+	if method != "pix" && method != "boleto" && method != "card" {
+		return apperror.New(apperror.CodeInvalidPaymentMethod, "invalid payment method. Use: pix, boleto, or card")
+	}
+	if method == "card" && (card.CardNumber == "" || card.CardCVV == "") {
+		return apperror.New(apperror.CodeCardRequired, "credit card information is required for card payment")
+	}
+	if card.HolderDoc != "" {
+		doc, err := document.Normalize(card.HolderDoc)
+		if err != nil {
+			return invalidDocument("holder_doc", "CPF or CNPJ")
+		}
+		card.HolderDoc = doc
+	}
+	holderPhone, err := normalizePhone("holder_phone", card.HolderPhone)
+	if err != nil {
+		return err
+	}
+	card.HolderPhone = holderPhone
+	return nil
+}
+
+// createGatewayCharge creates the charge on the gateway for the given payment method
+func (uc *checkoutUseCase) createGatewayCharge(ctx context.Context, method string, base gateway.CreatePaymentRequest, card CardInfo) (*gateway.PaymentResponse, error) {
+	switch method {
+	case "pix":
+		return uc.gw.CreatePixPayment(ctx, base)
+	case "boleto":
+		return uc.gw.CreateBoletoPayment(ctx, base)
+	case "card":
+		return uc.gw.CreateCardPayment(ctx, gateway.CreateCardPaymentRequest{
+			CreatePaymentRequest: base,
+			CardNumber:           card.CardNumber,
+			CardExpMonth:         card.CardExpMonth,
+			CardExpYear:          card.CardExpYear,
+			CardCVV:              card.CardCVV,
+			HolderName:           card.HolderName,
+			HolderEmail:          card.HolderEmail,
+			HolderDoc:            card.HolderDoc,
+			HolderZip:            card.HolderZip,
+			HolderPhone:          card.HolderPhone,
+			Installments:         card.Installments,
+		})
+	default:
+		return nil, apperror.New(apperror.CodeInvalidPaymentMethod, "invalid payment method. Use: pix, boleto, or card")
+	}
+}
+
 // logPaymentCreated creates a transaction log for payment creation (non-critical)
 func (uc *checkoutUseCase) logPaymentCreated(ctx context.Context, payment *entity.Payment, gwResp *gateway.PaymentResponse) {
 	txLog := &entity.PaymentTransaction{
@@ -464,15 +1197,35 @@ func (uc *checkoutUseCase) logPaymentCreated(ctx context.Context, payment *entit
 	}
 }
 
+// logRefundRequested records the refund request in the payment audit trail
+func (uc *checkoutUseCase) logRefundRequested(ctx context.Context, payment *entity.Payment, prevStatus string, amount money.Cents, triggeredBy string) {
+	description := "enrollment cancellation"
+	txLog := &entity.PaymentTransaction{
+		ID:             uuid.New().String(),
+		PaymentID:      payment.ID,
+		PreviousStatus: &prevStatus,
+		NewStatus:      payment.Status,
+		EventSource:    entity.EventSourceAPI,
+		EventType:      entity.TxEventRefundRequested,
+		Amount:         &amount,
+		Description:    &description,
+		TriggeredBy:    nilIfEmpty(triggeredBy),
+	}
+
+	if err := uc.paymentTxnRepo.Create(ctx, txLog); err != nil {
+		log.Printf("Failed to log payment transaction: %v", err)
+	}
+}
+
 // calculateGatewayFee calculates fee based on payment method and gateway fees
-func calculateGatewayFee(amount float64, method string, fees gateway.GatewayFees) float64 {
+func calculateGatewayFee(amount money.Cents, method string, fees gateway.GatewayFees) money.Cents {
 	switch method {
 	case "pix", "PIX":
-		return amount * fees.PixPercent
+		return amount.Mul(fees.PixPercent)
 	case "boleto", "BOLETO":
-		return fees.BoletoFixed
+		return money.FromFloat(fees.BoletoFixed)
 	case "card", "credit_card", "CREDIT_CARD":
-		return (amount * fees.CardPercent) + fees.CardFixed
+		return amount.Mul(fees.CardPercent) + money.FromFloat(fees.CardFixed)
 	default:
 		return 0
 	}
@@ -485,6 +1238,13 @@ func nilIfEmpty(s string) *string {
 	return &s
 }
 
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 func maxInt(a, b int) int {
 	if a > b {
 		return a
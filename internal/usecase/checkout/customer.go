@@ -0,0 +1,119 @@
+package checkout
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/document"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/condotrack/api/pkg/phone"
+	"github.com/google/uuid"
+)
+
+// gatewayCustomer returns the gateway customer of the payer and the gateway holding it,
+// creating it on the gateway only the first time the document is seen there. The customer
+// keeps the name and e-mail of that first purchase.
+func (uc *checkoutUseCase) gatewayCustomer(ctx context.Context, userID string, req gateway.CreateCustomerRequest) (string, string, error) {
+	gwName := uc.gw.Name()
+	doc := ""
+	if req.Document != "" {
+		var err error
+		if doc, err = document.Normalize(req.Document); err != nil {
+			return "", "", invalidDocument("document", "CPF or CNPJ")
+		}
+		req.Document = doc
+	}
+	if doc != "" {
+		known, err := uc.customerRepo.FindByDocument(ctx, gwName, doc)
+		if err != nil {
+			return "", "", err
+		}
+		if known != nil {
+			return known.GatewayCustomerID, gwName, nil
+		}
+	}
+
+	customer, err := uc.gw.CreateCustomer(ctx, req)
+	if err != nil {
+		return "", "", gateway.Failure(err)
+	}
+	// A fallback gateway may have created it on another provider
+	if customer.Gateway != "" {
+		gwName = customer.Gateway
+	}
+	if doc != "" {
+		uc.saveCustomer(ctx, userID, gwName, doc, customer.GatewayID)
+	}
+	return customer.GatewayID, gwName, nil
+}
+
+// saveCustomer maps a document to its customer on a gateway. The customer exists on the
+// gateway either way, so a failure is only logged and the checkout goes on.
+func (uc *checkoutUseCase) saveCustomer(ctx context.Context, userID, gwName, doc, customerID string) {
+	doc, err := document.Normalize(doc)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	mapping := &entity.GatewayCustomer{
+		ID:                uuid.New().String(),
+		Gateway:           gwName,
+		Document:          doc,
+		UserID:            nilIfEmpty(userID),
+		GatewayCustomerID: customerID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := uc.customerRepo.Create(ctx, mapping); err != nil {
+		log.Printf("Failed to save gateway customer of %s on %s: %v", userID, gwName, err)
+	}
+}
+
+// chargedOn returns the gateway that created a charge: the fallback gateway reports it when
+// the charge moved, otherwise it is the default one
+func (uc *checkoutUseCase) chargedOn(resp *gateway.PaymentResponse) string {
+	if resp.Gateway != "" {
+		return resp.Gateway
+	}
+	return uc.gw.Name()
+}
+
+// StartCustomerBackfill maps the payers of past payments to their gateway customers once, in
+// the background
+func (uc *checkoutUseCase) StartCustomerBackfill(lc *lifecycle.Manager) {
+	lc.Go("gateway customer backfill", func(ctx context.Context) {
+		n, err := uc.customerRepo.BackfillFromPayments(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Gateway customer backfill failed: %v", err)
+			}
+			return
+		}
+		if n > 0 {
+			log.Printf("Gateway customers backfilled: %d", n)
+		}
+	})
+}
+
+// invalidDocument reports a CPF or CNPJ rejected before it reaches the gateway
+func invalidDocument(field, kind string) error {
+	return apperror.New(apperror.CodeInvalidDocument, "invalid "+field+": enter a valid "+kind)
+}
+
+// normalizePhone returns an optional phone in E.164, or an error naming the field; an empty
+// phone is left empty
+func normalizePhone(field, value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	n, err := phone.Normalize(value)
+	if err != nil {
+		return "", apperror.New(apperror.CodeInvalidPhone, "invalid "+field+": include the area code")
+	}
+	return n, nil
+}
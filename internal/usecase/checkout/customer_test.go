@@ -0,0 +1,79 @@
+package checkout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/apperror"
+)
+
+func TestGatewayCustomerReusedByDocument(t *testing.T) {
+	ctx := context.Background()
+	customers := testutil.NewMockGatewayCustomerRepository()
+	created := 0
+	gwName := "asaas"
+	gw := &testutil.MockGateway{
+		NameFunc: func() string { return gwName },
+		CreateCustomerFunc: func(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+			created++
+			return &gateway.CustomerResponse{GatewayID: gwName + "-cust"}, nil
+		},
+	}
+	uc := &checkoutUseCase{gw: gw, customerRepo: customers}
+
+	id, _, err := uc.gatewayCustomer(ctx, "student-1", gateway.CreateCustomerRequest{Name: "Ana", Document: "123.456.789-09"})
+	if err != nil || id != "asaas-cust" {
+		t.Fatalf("expected the customer to be created, got %q, %v", id, err)
+	}
+	mapping := customers.Customers["asaas/12345678909"]
+	if mapping == nil || mapping.UserID == nil || *mapping.UserID != "student-1" {
+		t.Fatalf("expected the customer to be mapped by the digits of the CPF, got %+v", customers.Customers)
+	}
+
+	if id, _, _ := uc.gatewayCustomer(ctx, "student-1", gateway.CreateCustomerRequest{Document: "12345678909"}); id != "asaas-cust" || created != 1 {
+		t.Errorf("expected the customer to be reused, got %q after %d creations", id, created)
+	}
+
+	// Each gateway has its own customers
+	gwName = "mercadopago"
+	if id, _, _ := uc.gatewayCustomer(ctx, "student-1", gateway.CreateCustomerRequest{Document: "12345678909"}); id != "mercadopago-cust" || created != 2 {
+		t.Errorf("expected a customer on the other gateway, got %q after %d creations", id, created)
+	}
+}
+
+func TestGatewayCustomerRejectsInvalidDocument(t *testing.T) {
+	var sent []string
+	gw := &testutil.MockGateway{
+		NameFunc: func() string { return "asaas" },
+		CreateCustomerFunc: func(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+			sent = append(sent, req.Document)
+			return &gateway.CustomerResponse{GatewayID: "cust"}, nil
+		},
+	}
+	uc := &checkoutUseCase{gw: gw, customerRepo: testutil.NewMockGatewayCustomerRepository()}
+
+	_, _, err := uc.gatewayCustomer(context.Background(), "student-1", gateway.CreateCustomerRequest{Document: "123.456.789-00"})
+	if appErr, ok := apperror.As(err); !ok || appErr.Code != apperror.CodeInvalidDocument || len(sent) != 0 {
+		t.Fatalf("expected INVALID_DOCUMENT before the gateway call, got %v with %d calls", err, len(sent))
+	}
+
+	if _, _, err := uc.gatewayCustomer(context.Background(), "student-1", gateway.CreateCustomerRequest{Document: "11.222.333/0001-81"}); err != nil {
+		t.Fatalf("unexpected error for a valid CNPJ: %v", err)
+	}
+	if len(sent) != 1 || sent[0] != "11222333000181" {
+		t.Errorf("expected the gateway to get the digits only, got %v", sent)
+	}
+}
+
+func TestValidatePaymentMethodNormalizesHolderDoc(t *testing.T) {
+	card := CardInfo{CardNumber: "4111111111111111", CardCVV: "123", HolderDoc: "529.982.247-25"}
+	if err := validatePaymentMethod("card", &card); err != nil || card.HolderDoc != "52998224725" {
+		t.Errorf("holder_doc = %q, err %v", card.HolderDoc, err)
+	}
+	card.HolderDoc = "529.982.247-26"
+	if appErr, ok := apperror.As(validatePaymentMethod("card", &card)); !ok || appErr.Code != apperror.CodeInvalidDocument {
+		t.Errorf("invalid holder_doc: err = %v", appErr)
+	}
+}
@@ -0,0 +1,74 @@
+package checkout
+
+import (
+	"math"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/money"
+)
+
+// SetFeePassThrough switches fee pass-through pricing on ("true") or off ("false"); an empty
+// value restores the environment default. Subscribed to the checkout_fee_pass_through setting.
+func (uc *checkoutUseCase) SetFeePassThrough(value string) {
+	switch value {
+	case "true":
+		uc.feePassThrough.Store(true)
+	case "false":
+		uc.feePassThrough.Store(false)
+	case "":
+		uc.feePassThrough.Store(uc.feePassThroughDefault)
+	}
+}
+
+// feeSurcharge is what the buyer pays on top of amount for the method when fees are passed
+// through, zero otherwise
+func (uc *checkoutUseCase) feeSurcharge(amount money.Cents, method string) money.Cents {
+	if !uc.feePassThrough.Load() {
+		return 0
+	}
+	return passThroughSurcharge(amount, method, uc.gw.GetFees())
+}
+
+// passThroughSurcharge returns the surcharge that leaves amount once the gateway takes its fee
+// from amount plus the surcharge. The total is rounded up to the cent, so the fee never eats
+// into amount.
+func passThroughSurcharge(amount money.Cents, method string, fees gateway.GatewayFees) money.Cents {
+	cents := float64(amount)
+	var total float64
+	switch method {
+	case "pix":
+		if fees.PixPercent >= 1 {
+			return 0
+		}
+		total = cents / (1 - fees.PixPercent)
+	case "boleto":
+		total = cents + float64(money.FromFloat(fees.BoletoFixed))
+	case "card":
+		if fees.CardPercent >= 1 {
+			return 0
+		}
+		total = (cents + float64(money.FromFloat(fees.CardFixed))) / (1 - fees.CardPercent)
+	default:
+		return 0
+	}
+	return max(money.Cents(math.Ceil(math.Round(total*1e4)/1e4))-amount, 0)
+}
+
+// lineItems breaks a charge down into the price, the discount and the fee surcharge
+func lineItems(description string, price, discount money.Cents, discountLabel string, surcharge money.Cents, method string) entity.PaymentLineItems {
+	items := entity.PaymentLineItems{{Kind: entity.LineItemPrice, Description: description, Amount: price}}
+	if discount > 0 {
+		items = append(items, entity.PaymentLineItem{Kind: entity.LineItemDiscount, Description: discountLabel, Amount: -discount})
+	}
+	if surcharge > 0 {
+		items = append(items, entity.PaymentLineItem{Kind: entity.LineItemFee, Description: "Taxa de processamento (" + methodLabels[method] + ")", Amount: surcharge})
+	}
+	return items
+}
+
+var methodLabels = map[string]string{
+	"pix":    "PIX",
+	"boleto": "boleto",
+	"card":   "cartão",
+}
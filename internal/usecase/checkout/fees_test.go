@@ -0,0 +1,115 @@
+package checkout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/money"
+)
+
+func TestPassThroughSurcharge(t *testing.T) {
+	fees := gateway.GatewayFees{PixPercent: 0.01, BoletoFixed: 2.99, CardPercent: 0.03, CardFixed: 0.49}
+	tests := []struct {
+		amount    money.Cents
+		method    string
+		surcharge money.Cents
+	}{
+		{3600, "pix", 37},
+		{3600, "boleto", 299},
+		{3600, "card", 162},
+		{9900, "pix", 100},
+		{19990, "card", 669},
+		{3600, "cash", 0},
+	}
+	for _, tt := range tests {
+		got := passThroughSurcharge(tt.amount, tt.method, fees)
+		if got != tt.surcharge {
+			t.Errorf("%s %v: expected surcharge %v, got %v", tt.method, tt.amount, tt.surcharge, got)
+		}
+		if tt.surcharge == 0 {
+			continue
+		}
+		// The fee on the total leaves the amount, and one cent less would not
+		total := tt.amount + got
+		if net := total - calculateGatewayFee(total, tt.method, fees); net < tt.amount {
+			t.Errorf("%s %v: expected at least the amount left, got %v", tt.method, tt.amount, net)
+		}
+		if tt.method != "boleto" {
+			// Unrounded, as the fee rounding could otherwise hide a cent
+			less := total - 1
+			if net := less.Float() - unroundedFee(less, tt.method, fees); net >= tt.amount.Float() {
+				t.Errorf("%s %v: expected the smallest surcharge, %v leaves %.4f", tt.method, tt.amount, less, net)
+			}
+		}
+	}
+}
+
+func TestGetPaymentMethods_FeePassThrough(t *testing.T) {
+	ctx := context.Background()
+	uc := newMethodsUseCase("pix,boleto,card")
+	uc.SetFeePassThrough("true")
+
+	result, err := uc.GetPaymentMethods(ctx, "c1", "DEZ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.FeePassThrough || result.Amount != 3600 {
+		t.Fatalf("expected fee pass-through on R$ 36, got %+v", result)
+	}
+	pix, boleto, card := result.Methods[0], result.Methods[1], result.Methods[2]
+	if pix.Surcharge != 37 || pix.Total != 3637 || pix.NetAmount < 3600 {
+		t.Errorf("unexpected pix option: %+v", pix)
+	}
+	if boleto.Surcharge != 299 || boleto.Total != 3899 || boleto.NetAmount != 3600 {
+		t.Errorf("unexpected boleto option: %+v", boleto)
+	}
+	// Every boleto of a carnê carries the surcharge
+	if boleto.Installments[1].Count != 3 || boleto.Installments[1].Total != 4497 {
+		t.Errorf("unexpected carnê option: %+v", boleto.Installments[1])
+	}
+	if card.Total != 3762 || card.Installments[0].Total != 3762 || card.Total <= pix.Total {
+		t.Errorf("expected card to cost more than pix, got %+v", card)
+	}
+
+	uc.SetFeePassThrough("")
+	result, _ = uc.GetPaymentMethods(ctx, "c1", "DEZ")
+	if result.FeePassThrough || result.Methods[0].Total != 3600 {
+		t.Errorf("expected an empty setting to restore the default, got %+v", result)
+	}
+}
+
+func TestLineItems(t *testing.T) {
+	items := lineItems("Matrícula: Curso", 5000, 1400, "Cupom DEZ", 37, "pix")
+	if len(items) != 3 {
+		t.Fatalf("expected price, discount and fee, got %+v", items)
+	}
+	if items[1].Kind != entity.LineItemDiscount || items[1].Amount != -1400 {
+		t.Errorf("expected a negative discount line, got %+v", items[1])
+	}
+	if items[2].Kind != entity.LineItemFee || items[2].Description != "Taxa de processamento (PIX)" {
+		t.Errorf("unexpected fee line: %+v", items[2])
+	}
+	var total money.Cents
+	for _, item := range items {
+		total += item.Amount
+	}
+	if total != 3637 {
+		t.Errorf("expected the lines to add up to 36.37, got %v", total)
+	}
+
+	if items := lineItems("Matrícula: Curso", 5000, 0, "Desconto", 0, "pix"); len(items) != 1 {
+		t.Errorf("expected only the price line, got %+v", items)
+	}
+}
+
+func unroundedFee(amount money.Cents, method string, fees gateway.GatewayFees) float64 {
+	switch method {
+	case "pix":
+		return amount.Float() * fees.PixPercent
+	case "card":
+		return amount.Float()*fees.CardPercent + fees.CardFixed
+	}
+	return 0
+}
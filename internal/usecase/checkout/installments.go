@@ -0,0 +1,126 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/google/uuid"
+)
+
+// BoletoInstallment is one boleto of a carnê
+type BoletoInstallment struct {
+	Number        int         `json:"number"`
+	PaymentID     string      `json:"payment_id"`
+	Amount        money.Cents `json:"amount"`
+	DueDate       string      `json:"due_date"`
+	BoletoURL     string      `json:"boleto_url,omitempty"`
+	BoletoBarCode string      `json:"boleto_bar_code,omitempty"`
+}
+
+// chargeCount returns how many gateway charges a checkout creates: one boleto per
+// installment for a carnê, a single charge otherwise. Card installments are one charge the
+// gateway splits.
+func chargeCount(method string, installments int) int {
+	if method == "boleto" && installments > 1 {
+		return installments
+	}
+	return 1
+}
+
+// carnetDueDate returns the due date of installment n of a carnê, a month after the previous one
+func carnetDueDate(first time.Time, n int) time.Time {
+	return first.AddDate(0, n-1, 0)
+}
+
+// createCarnet creates the boletos of a carnê, sharing amount between them. When one fails
+// the boletos already created are cancelled, so the buyer is never left with part of a plan.
+func (uc *checkoutUseCase) createCarnet(ctx context.Context, base gateway.CreatePaymentRequest, amount money.Cents, count int) ([]*gateway.PaymentResponse, error) {
+	charges := make([]*gateway.PaymentResponse, 0, count)
+	for n := 1; n <= count; n++ {
+		req := base
+		req.Amount = entity.InstallmentShare(amount, count, n).Float()
+		req.DueDate = carnetDueDate(base.DueDate, n)
+		req.Description = fmt.Sprintf("%s (parcela %d/%d)", base.Description, n, count)
+		charge, err := uc.gw.CreateBoletoPayment(ctx, req)
+		if err != nil {
+			for _, created := range charges {
+				gw := gateway.ForPayment(uc.gw, uc.chargedOn(created))
+				if cancelErr := gw.CancelPayment(ctx, created.GatewayPaymentID); cancelErr != nil {
+					log.Printf("Failed to cancel boleto %s of an incomplete carnê: %v", created.GatewayPaymentID, cancelErr)
+				}
+			}
+			return nil, err
+		}
+		charges = append(charges, charge)
+
+		// The other boletos go to the gateway of the first one, which may be the fallback
+		if n == 1 {
+			base.CustomerGateway = uc.chargedOn(charge)
+			if charge.CustomerGatewayID != "" {
+				base.CustomerGatewayID = charge.CustomerGatewayID
+			}
+			base.Customer = nil
+		}
+	}
+	return charges, nil
+}
+
+// carnetPayments returns the payments of the boletos of a carnê, linked to the parent
+// payment. Each one takes its share of the parent amounts and carries the fee of its boleto.
+func carnetPayments(parent *entity.Payment, charges []*gateway.PaymentResponse, boletoFee money.Cents) []*entity.Payment {
+	count := len(charges)
+	payments := make([]*entity.Payment, count)
+	for i, charge := range charges {
+		number := i + 1
+		gross := entity.InstallmentShare(parent.GrossAmount, count, number)
+		net := entity.InstallmentShare(parent.NetAmount, count, number)
+		dueDate := carnetDueDate(*parent.DueDate, number)
+		gwPaymentID := charge.GatewayPaymentID
+		payments[i] = &entity.Payment{
+			ID:                uuid.New().String(),
+			EnrollmentID:      parent.EnrollmentID,
+			PayerUserID:       parent.PayerUserID,
+			PayerName:         parent.PayerName,
+			PayerEmail:        parent.PayerEmail,
+			PayerCPF:          parent.PayerCPF,
+			GrossAmount:       gross,
+			DiscountAmount:    gross - net,
+			NetAmount:         net,
+			GatewayFee:        boletoFee,
+			FeeSurcharge:      entity.InstallmentShare(parent.FeeSurcharge, count, number),
+			PaymentMethod:     parent.PaymentMethod,
+			Gateway:           parent.Gateway,
+			GatewayPaymentID:  &gwPaymentID,
+			GatewayCustomerID: parent.GatewayCustomerID,
+			GatewayInvoiceURL: nilIfEmpty(charge.InvoiceURL),
+			InstallmentCount:  count,
+			InstallmentOf:     &parent.ID,
+			InstallmentNumber: &number,
+			Status:            entity.FinPaymentStatusPending,
+			DueDate:           &dueDate,
+			CreatedAt:         parent.CreatedAt,
+		}
+	}
+	return payments
+}
+
+// carnetInstallments lists the boletos of a carnê for the checkout response
+func carnetInstallments(payments []*entity.Payment, charges []*gateway.PaymentResponse) []BoletoInstallment {
+	installments := make([]BoletoInstallment, len(payments))
+	for i, p := range payments {
+		installments[i] = BoletoInstallment{
+			Number:        *p.InstallmentNumber,
+			PaymentID:     p.ID,
+			Amount:        p.NetAmount,
+			DueDate:       p.DueDate.Format("2006-01-02"),
+			BoletoURL:     charges[i].BoletoURL,
+			BoletoBarCode: charges[i].BoletoBarCode,
+		}
+	}
+	return installments
+}
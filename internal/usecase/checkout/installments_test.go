@@ -0,0 +1,107 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func TestCreateCarnet(t *testing.T) {
+	var requests []gateway.CreatePaymentRequest
+	uc := &checkoutUseCase{gw: &testutil.MockGateway{
+		CreateBoletoPaymentFunc: func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+			requests = append(requests, req)
+			return &gateway.PaymentResponse{GatewayPaymentID: fmt.Sprintf("bol_%d", len(requests))}, nil
+		},
+	}}
+	first := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	charges, err := uc.createCarnet(context.Background(), gateway.CreatePaymentRequest{Description: "Matrícula: NR-10", DueDate: first}, 10000, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(charges) != 3 || charges[2].GatewayPaymentID != "bol_3" {
+		t.Fatalf("unexpected charges: %+v", charges)
+	}
+	if requests[0].Amount != 33.33 || requests[1].Amount != 33.33 || requests[2].Amount != 33.34 {
+		t.Errorf("expected the amount shared between the boletos, got %v, %v, %v", requests[0].Amount, requests[1].Amount, requests[2].Amount)
+	}
+	if requests[1].Description != "Matrícula: NR-10 (parcela 2/3)" || !requests[2].DueDate.Equal(first.AddDate(0, 2, 0)) {
+		t.Errorf("unexpected second and third boletos: %+v, %+v", requests[1], requests[2])
+	}
+}
+
+func TestCreateCarnet_FailureCancelsCreatedBoletos(t *testing.T) {
+	var cancelled []string
+	created := 0
+	uc := &checkoutUseCase{gw: &testutil.MockGateway{
+		CreateBoletoPaymentFunc: func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+			created++
+			if created == 3 {
+				return nil, errors.New("gateway unavailable")
+			}
+			return &gateway.PaymentResponse{GatewayPaymentID: fmt.Sprintf("bol_%d", created)}, nil
+		},
+		CancelPaymentFunc: func(ctx context.Context, gatewayPaymentID string) error {
+			cancelled = append(cancelled, gatewayPaymentID)
+			return nil
+		},
+	}}
+
+	if _, err := uc.createCarnet(context.Background(), gateway.CreatePaymentRequest{DueDate: time.Now()}, 10000, 4); err == nil {
+		t.Fatal("expected the failed boleto to fail the carnê")
+	}
+	if created != 3 || len(cancelled) != 2 || cancelled[0] != "bol_1" || cancelled[1] != "bol_2" {
+		t.Errorf("expected the two boletos created to be cancelled, created %d, cancelled %v", created, cancelled)
+	}
+}
+
+func TestCarnetPayments(t *testing.T) {
+	due := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	parent := &entity.Payment{
+		ID:             "parent",
+		EnrollmentID:   "e1",
+		GrossAmount:    10598, // R$ 100 plus 2 boleto surcharges of R$ 2.99
+		DiscountAmount: 1000,
+		NetAmount:      9598,
+		FeeSurcharge:   598,
+		PaymentMethod:  "boleto",
+		DueDate:        &due,
+	}
+	charges := []*gateway.PaymentResponse{{GatewayPaymentID: "bol_1", InvoiceURL: "https://pay/1"}, {GatewayPaymentID: "bol_2"}}
+
+	payments := carnetPayments(parent, charges, 299)
+	if len(payments) != 2 {
+		t.Fatalf("expected one payment per boleto, got %d", len(payments))
+	}
+	first, second := payments[0], payments[1]
+	if *first.InstallmentOf != "parent" || *first.InstallmentNumber != 1 || *second.InstallmentNumber != 2 {
+		t.Errorf("expected the payments linked to the parent by number, got %+v, %+v", first, second)
+	}
+	if first.NetAmount+second.NetAmount != parent.NetAmount || first.GrossAmount+second.GrossAmount != parent.GrossAmount {
+		t.Errorf("expected the amounts to add up to the parent ones, got %+v, %+v", first, second)
+	}
+	if first.GrossAmount-first.DiscountAmount != first.NetAmount || first.GatewayFee != 299 || second.GatewayFee != 299 {
+		t.Errorf("unexpected amounts of the first boleto: %+v", first)
+	}
+	if *second.GatewayPaymentID != "bol_2" || !second.DueDate.Equal(due.AddDate(0, 1, 0)) || *first.GatewayInvoiceURL != "https://pay/1" {
+		t.Errorf("unexpected gateway data: %+v, %+v", first, second)
+	}
+
+	installments := carnetInstallments(payments, charges)
+	if installments[1].DueDate != "2026-04-10" || installments[1].Amount != second.NetAmount {
+		t.Errorf("unexpected installments: %+v", installments)
+	}
+}
+
+func TestChargeCount(t *testing.T) {
+	if chargeCount("boleto", 3) != 3 || chargeCount("boleto", 0) != 1 || chargeCount("card", 6) != 1 {
+		t.Error("expected one charge per boleto of a carnê and one charge otherwise")
+	}
+}
@@ -0,0 +1,157 @@
+package checkout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/money"
+)
+
+// PaymentMethods lists what a course can be paid with on the active gateway, priced with the
+// course discount and an optional coupon
+type PaymentMethods struct {
+	Gateway        string                `json:"gateway"`
+	CourseID       string                `json:"course_id"`
+	Price          money.Cents           `json:"price"`
+	DiscountAmount money.Cents           `json:"discount_amount"`
+	CouponCode     string                `json:"coupon_code,omitempty"`
+	CouponDiscount money.Cents           `json:"coupon_discount,omitempty"`
+	Amount         money.Cents           `json:"amount"`
+	FeePassThrough bool                  `json:"fee_pass_through"`
+	Methods        []PaymentMethodOption `json:"methods"`
+}
+
+// PaymentMethodOption is one payment method with the gateway fee charged on it. With fee
+// pass-through the fee is added to what the buyer pays as Surcharge.
+type PaymentMethodOption struct {
+	Method       string              `json:"method"`
+	Fee          money.Cents         `json:"fee"`
+	Surcharge    money.Cents         `json:"surcharge"`
+	Total        money.Cents         `json:"total"`
+	NetAmount    money.Cents         `json:"net_amount"`
+	Installments []InstallmentOption `json:"installments,omitempty"`
+}
+
+// InstallmentOption is a card installment count, or the number of boletos of a carnê, and
+// the amount of each installment
+type InstallmentOption struct {
+	Count  int         `json:"count"`
+	Amount money.Cents `json:"amount"`
+	Total  money.Cents `json:"total"`
+}
+
+// GetPaymentMethods returns the payment methods offered for a course
+func (uc *checkoutUseCase) GetPaymentMethods(ctx context.Context, courseID, discountCode string) (*PaymentMethods, error) {
+	if courseID == "" {
+		return nil, apperror.New(apperror.CodeBadRequest, "course_id is required")
+	}
+	course, err := uc.courseRepo.FindByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil || !course.IsActive {
+		return nil, apperror.New(apperror.CodeNotFound, "course not found")
+	}
+
+	price := money.FromFloat(course.EffectivePrice())
+	result := &PaymentMethods{
+		Gateway:        uc.gw.Name(),
+		CourseID:       course.ID,
+		Price:          money.FromFloat(course.Price),
+		DiscountAmount: money.FromFloat(course.Price) - price,
+		Amount:         price,
+		FeePassThrough: uc.feePassThrough.Load(),
+		Methods:        []PaymentMethodOption{},
+	}
+	if discountCode != "" {
+		coupon, discount, err := uc.applyCoupon(ctx, discountCode, "", price)
+		if err != nil {
+			return nil, err
+		}
+		result.CouponCode = coupon.Code
+		result.CouponDiscount = discount
+		result.Amount = price - discount
+	}
+
+	fees := uc.gw.GetFees()
+	for _, method := range uc.methods {
+		surcharge := uc.feeSurcharge(result.Amount, method)
+		total := result.Amount + surcharge
+		fee := calculateGatewayFee(total, method, fees)
+		option := PaymentMethodOption{
+			Method:    method,
+			Fee:       fee,
+			Surcharge: surcharge,
+			Total:     total,
+			NetAmount: total - fee,
+		}
+		switch method {
+		case "card":
+			for n := 1; n <= uc.maxInstallmentsFor(result.Amount); n++ {
+				option.Installments = append(option.Installments, InstallmentOption{
+					Count:  n,
+					Amount: total.Mul(1 / float64(n)),
+					Total:  total,
+				})
+			}
+		case "boleto":
+			// A carnê is one boleto per installment, each carrying the boleto surcharge
+			for n := 2; n <= uc.maxInstallmentsFor(result.Amount); n++ {
+				carnetTotal := result.Amount + surcharge*money.Cents(n)
+				option.Installments = append(option.Installments, InstallmentOption{
+					Count:  n,
+					Amount: carnetTotal.Mul(1 / float64(n)),
+					Total:  carnetTotal,
+				})
+			}
+		}
+		result.Methods = append(result.Methods, option)
+	}
+	return result, nil
+}
+
+// checkOffered rejects a payment method that is not offered, or more card or boleto
+// installments than the amount allows
+func (uc *checkoutUseCase) checkOffered(method string, amount money.Cents, installments int) error {
+	if !contains(uc.methods, method) {
+		return apperror.New(apperror.CodeInvalidPaymentMethod, fmt.Sprintf("payment method %s is not available", method))
+	}
+	if (method == "card" || method == "boleto") && installments > uc.maxInstallmentsFor(amount) {
+		return apperror.New(apperror.CodeValidationFailed,
+			fmt.Sprintf("at most %d installments are available for this amount", uc.maxInstallmentsFor(amount)))
+	}
+	return nil
+}
+
+// maxInstallmentsFor returns how many installments an amount can be split in, keeping
+// each installment at least the minimum
+func (uc *checkoutUseCase) maxInstallmentsFor(amount money.Cents) int {
+	n := uc.maxInstallments
+	if uc.minInstallment > 0 {
+		n = min(n, int(amount/uc.minInstallment))
+	}
+	return max(n, 1)
+}
+
+// paymentMethods parses the comma separated methods offered, keeping the known ones
+func paymentMethods(list string) []string {
+	var methods []string
+	for _, m := range strings.Split(list, ",") {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if (m == "pix" || m == "boleto" || m == "card") && !contains(methods, m) {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
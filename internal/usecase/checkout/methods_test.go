@@ -0,0 +1,93 @@
+package checkout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/apperror"
+)
+
+func newMethodsUseCase(methods string) *checkoutUseCase {
+	discount := 40.0
+	coupons := testutil.NewMockCouponRepository()
+	coupons.Coupons["cp-1"] = &entity.Coupon{ID: "cp-1", Code: "DEZ", DiscountType: entity.DiscountTypePercentage, DiscountValue: 10, IsActive: true}
+	coupons.CouponCodes["DEZ"] = "cp-1"
+	return &checkoutUseCase{
+		gw: &testutil.MockGateway{
+			GetFeesFunc: func() gateway.GatewayFees {
+				return gateway.GatewayFees{PixPercent: 0.01, BoletoFixed: 2.99, CardPercent: 0.03, CardFixed: 0.49}
+			},
+		},
+		courseRepo: testutil.NewMockCourseRepository(
+			&entity.Course{ID: "c1", Price: 50, DiscountPrice: &discount, IsActive: true},
+			&entity.Course{ID: "c2", Price: 50, IsActive: false},
+		),
+		couponRepo:      coupons,
+		methods:         paymentMethods(methods),
+		maxInstallments: 12,
+		minInstallment:  500,
+	}
+}
+
+func TestGetPaymentMethods(t *testing.T) {
+	ctx := context.Background()
+	uc := newMethodsUseCase("pix, boleto,card")
+
+	result, err := uc.GetPaymentMethods(ctx, "c1", "DEZ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Price != 5000 || result.DiscountAmount != 1000 || result.CouponDiscount != 400 || result.Amount != 3600 {
+		t.Fatalf("expected the course and coupon discounts, got %+v", result)
+	}
+	if len(result.Methods) != 3 {
+		t.Fatalf("expected three methods, got %+v", result.Methods)
+	}
+	pix, boleto, card := result.Methods[0], result.Methods[1], result.Methods[2]
+	if pix.Method != "pix" || pix.Fee != 36 || pix.NetAmount != 3564 {
+		t.Errorf("unexpected pix option: %+v", pix)
+	}
+	// A carnê of 2 to 7 boletos
+	if boleto.Fee != 299 || len(boleto.Installments) != 6 || boleto.Installments[0].Count != 2 || boleto.Installments[0].Amount != 1800 {
+		t.Errorf("unexpected boleto option: %+v", boleto)
+	}
+	// R$ 36 allows 7 installments of at least R$ 5
+	if card.Fee != 157 || len(card.Installments) != 7 || card.Installments[6].Amount != 514 {
+		t.Errorf("unexpected card option: %+v", card)
+	}
+
+	if _, err := uc.GetPaymentMethods(ctx, "c2", ""); !hasCode(err, apperror.CodeNotFound) {
+		t.Errorf("expected an inactive course to be not found, got %v", err)
+	}
+	if _, err := uc.GetPaymentMethods(ctx, "c1", "NOPE"); !hasCode(err, apperror.CodeCouponNotFound) {
+		t.Errorf("expected an unknown coupon to be rejected, got %v", err)
+	}
+}
+
+func TestCheckOffered(t *testing.T) {
+	uc := newMethodsUseCase("pix,card,cash")
+
+	if err := uc.checkOffered("boleto", 10000, 0); !hasCode(err, apperror.CodeInvalidPaymentMethod) {
+		t.Errorf("expected boleto to be unavailable, got %v", err)
+	}
+	if err := uc.checkOffered("card", 10000, 12); err != nil {
+		t.Errorf("expected 12 installments of R$ 8.33, got %v", err)
+	}
+	if err := uc.checkOffered("card", 2000, 5); !hasCode(err, apperror.CodeValidationFailed) {
+		t.Errorf("expected 5 installments of R$ 4 to be rejected, got %v", err)
+	}
+	if err := newMethodsUseCase("boleto").checkOffered("boleto", 2000, 5); !hasCode(err, apperror.CodeValidationFailed) {
+		t.Errorf("expected a carnê of 5 boletos of R$ 4 to be rejected, got %v", err)
+	}
+	if len(uc.methods) != 2 {
+		t.Errorf("expected unknown methods to be ignored, got %v", uc.methods)
+	}
+}
+
+func hasCode(err error, code apperror.Code) bool {
+	appErr, ok := apperror.As(err)
+	return ok && appErr.Code == code
+}
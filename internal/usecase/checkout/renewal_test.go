@@ -0,0 +1,44 @@
+package checkout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func TestRenewEnrollment_OnlyStudentOrAdmin(t *testing.T) {
+	uc := newMethodsUseCase("pix,boleto,card")
+	enrollments := testutil.NewMockMatriculaRepository()
+	// Cancelled, so the callers allowed in stop at the status check before any charge
+	enrollments.Enrollments["e1"] = &entity.Matricula{ID: "e1", StudentID: "stu-1", CourseID: "c1",
+		Status: entity.EnrollmentStatusCancelled, PaymentStatus: entity.PaymentStatusConfirmed}
+	uc.matriculaRepo = enrollments
+	req := &RenewalRequest{PaymentMethod: "pix"}
+
+	tests := []struct {
+		name    string
+		userID  string
+		role    string
+		allowed bool
+	}{
+		{"enrolled student", "stu-1", "student", true},
+		{"admin", "adm-1", "admin", true},
+		{"another student", "stu-2", "student", false},
+		{"instructor", "inst-1", "instructor", false},
+		{"gestor", "gst-1", "gestor", false},
+		{"no user", "", "student", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.RenewEnrollment(context.Background(), "e1", req, tt.userID, tt.role)
+			if tt.allowed && err == ErrRenewalForbidden {
+				t.Fatalf("expected %s to be allowed to renew", tt.name)
+			}
+			if !tt.allowed && err != ErrRenewalForbidden {
+				t.Fatalf("expected %s to be refused, got %v", tt.name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package checkout
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/google/uuid"
+)
+
+// Errors returned when a checkout cannot be retried
+var (
+	ErrRetryNotAllowed = apperror.New(apperror.CodeConflict, "only pending or expired enrollments can be retried")
+	ErrRetryPaid       = apperror.New(apperror.CodeConflict, "enrollment payment was already received")
+	ErrRetryInProgress = apperror.New(apperror.CodeConflict, "enrollment checkout is already being retried")
+	ErrRetryForbidden  = apperror.New(apperror.CodeForbidden, "only the enrolled student or an admin can retry this checkout")
+)
+
+// RetryRequest represents the request to pay again for an enrollment whose charge failed or
+// expired; the price and coupon come from the enrollment
+type RetryRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required"` // pix, boleto, card
+
+	// Card info (required if payment_method is card)
+	CardInfo
+}
+
+// RetryCheckout creates a new charge for a pending or expired enrollment that was never paid,
+// so the student pays again without a new enrollment. The charges still open on the gateway
+// are cancelled first, so only the new one can be paid. The enrollment keeps its price and
+// coupon discount, and the payment method may change. Only the enrolled student and admins
+// may retry, and the enrollment is claimed as retrying until the new charge is recorded, so
+// concurrent retries cannot charge twice.
+func (uc *checkoutUseCase) RetryCheckout(ctx context.Context, enrollmentID string, req *RetryRequest, userID, role string) (result *CheckoutResponse, err error) {
+	if err := validatePaymentMethod(req.PaymentMethod, &req.CardInfo); err != nil {
+		return nil, err
+	}
+
+	enrollment, err := uc.matriculaRepo.FindByID(ctx, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "enrollment not found")
+	}
+	if role != string(entity.RoleAdmin) && (userID == "" || enrollment.StudentID != userID) {
+		return nil, ErrRetryForbidden
+	}
+	if enrollment.Status == entity.EnrollmentStatusRetrying {
+		return nil, ErrRetryInProgress
+	}
+	if enrollment.Status != entity.EnrollmentStatusPending && enrollment.Status != entity.EnrollmentStatusExpired {
+		return nil, ErrRetryNotAllowed
+	}
+	switch enrollment.PaymentStatus {
+	case entity.PaymentStatusPending, entity.PaymentStatusOverdue, entity.PaymentStatusFailed:
+	default:
+		return nil, ErrRetryNotAllowed
+	}
+
+	amount := money.FromFloat(enrollment.Amount)
+	discountAmount := money.FromFloat(enrollment.DiscountAmount)
+	finalAmount := amount - discountAmount
+	if err := uc.checkOffered(req.PaymentMethod, finalAmount, req.Installments); err != nil {
+		return nil, err
+	}
+
+	claimed, err := uc.matriculaRepo.ClaimRetry(ctx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrRetryInProgress
+	}
+	// Until the new charge is recorded, a failure gives the enrollment its status back
+	previousStatus := enrollment.Status
+	defer func() {
+		if err == nil {
+			return
+		}
+		if releaseErr := uc.matriculaRepo.ReleaseRetry(context.WithoutCancel(ctx), enrollment.ID, previousStatus); releaseErr != nil {
+			log.Printf("Failed to release checkout retry of enrollment %s: %v", enrollment.ID, releaseErr)
+		}
+	}()
+
+	payments, err := uc.paymentRepo.FindByEnrollmentID(ctx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+	coupon, err := uc.enrollmentCoupon(ctx, payments)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.releaseCharges(ctx, payments); err != nil {
+		return nil, err
+	}
+
+	// Reuse the gateway customer of the CPF, falling back to the one of the first charge
+	var customer *gateway.CreateCustomerRequest
+	customerGatewayID, customerGateway := derefString(enrollment.AsaasCustomerID), ""
+	if cpf := derefString(enrollment.StudentCPF); cpf != "" {
+		customer = &gateway.CreateCustomerRequest{
+			Name:     enrollment.StudentName,
+			Email:    enrollment.StudentEmail,
+			Document: cpf,
+			Phone:    derefString(enrollment.StudentPhone),
+		}
+		customerGatewayID, customerGateway, err = uc.gatewayCustomer(ctx, enrollment.StudentID, *customer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if customerGatewayID == "" {
+		return nil, apperror.New(apperror.CodeValidationFailed, "student CPF is required to retry the checkout")
+	}
+
+	charges := chargeCount(req.PaymentMethod, req.Installments)
+	surcharge := uc.feeSurcharge(finalAmount, req.PaymentMethod) * money.Cents(charges)
+	chargeAmount := finalAmount + surcharge
+
+	dueDate := time.Now().AddDate(0, 0, 3)
+	description := "Matrícula: " + enrollment.CourseName
+	base := gateway.CreatePaymentRequest{
+		CustomerGatewayID: customerGatewayID,
+		Amount:            chargeAmount.Float(),
+		Description:       description,
+		DueDate:           dueDate,
+		ExternalReference: enrollment.ID,
+		CustomerGateway:   customerGateway,
+		Customer:          customer,
+	}
+	var gatewayResp *gateway.PaymentResponse
+	var carnet []*gateway.PaymentResponse
+	if charges > 1 {
+		carnet, err = uc.createCarnet(ctx, base, chargeAmount, charges)
+		if err == nil {
+			gatewayResp = carnet[0]
+		}
+	} else {
+		gatewayResp, err = uc.createGatewayCharge(ctx, req.PaymentMethod, base, req.CardInfo)
+	}
+	if err != nil {
+		return nil, gateway.Failure(err)
+	}
+	chargedOn := uc.chargedOn(gatewayResp)
+	if gatewayResp.CustomerGatewayID != "" {
+		customerGatewayID = gatewayResp.CustomerGatewayID
+		if customer != nil {
+			uc.saveCustomer(ctx, enrollment.StudentID, chargedOn, customer.Document, customerGatewayID)
+		}
+	}
+
+	fees := gateway.ForPayment(uc.gw, chargedOn).GetFees()
+	chargeFee := calculateGatewayFee(chargeAmount, req.PaymentMethod, fees)
+	gatewayFee := chargeFee * money.Cents(charges)
+
+	var couponID *string
+	discountLabel := "Desconto"
+	if coupon != nil {
+		couponID = &coupon.ID
+		discountLabel = "Cupom " + coupon.Code
+	}
+	gwPaymentID := gatewayResp.GatewayPaymentID
+	paymentRecord := &entity.Payment{
+		ID:                   uuid.New().String(),
+		EnrollmentID:         enrollment.ID,
+		PayerUserID:          &enrollment.StudentID,
+		PayerName:            enrollment.StudentName,
+		PayerEmail:           enrollment.StudentEmail,
+		PayerCPF:             enrollment.StudentCPF,
+		GrossAmount:          amount + surcharge,
+		DiscountAmount:       discountAmount,
+		NetAmount:            chargeAmount,
+		GatewayFee:           gatewayFee,
+		FeeSurcharge:         surcharge,
+		PaymentMethod:        req.PaymentMethod,
+		Gateway:              chargedOn,
+		GatewayPaymentID:     &gwPaymentID,
+		GatewayCustomerID:    &customerGatewayID,
+		GatewayInvoiceURL:    nilIfEmpty(gatewayResp.InvoiceURL),
+		GatewayInstallmentID: nilIfEmpty(gatewayResp.InstallmentID),
+		InstallmentCount:     maxInt(req.Installments, 1),
+		Status:               entity.FinPaymentStatusPending,
+		CouponID:             couponID,
+		DueDate:              &dueDate,
+		CreatedAt:            time.Now(),
+		LineItems:            lineItems(description, amount, discountAmount, discountLabel, surcharge, req.PaymentMethod),
+	}
+	var installments []*entity.Payment
+	if carnet != nil {
+		paymentRecord.GatewayPaymentID = nil
+		paymentRecord.GatewayInvoiceURL = nil
+		installments = carnetPayments(paymentRecord, carnet, chargeFee)
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := uc.paymentRepo.CreateWithTx(ctx, tx, paymentRecord); err != nil {
+		return nil, err
+	}
+	for _, installment := range installments {
+		if err := uc.paymentRepo.CreateWithTx(ctx, tx, installment); err != nil {
+			return nil, err
+		}
+	}
+
+	// The enrollment waits for the new charge again
+	enrollment.AsaasPaymentID = &gatewayResp.GatewayPaymentID
+	enrollment.AsaasCustomerID = &customerGatewayID
+	enrollment.PaymentMethod = &req.PaymentMethod
+	enrollment.Status = entity.EnrollmentStatusPending
+	enrollment.PaymentStatus = entity.PaymentStatusPending
+	if err := uc.matriculaRepo.UpdateWithTx(ctx, tx, enrollment); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	uc.logPaymentCreated(ctx, paymentRecord, gatewayResp)
+
+	netAfterFee := chargeAmount - gatewayFee
+	response := &CheckoutResponse{
+		EnrollmentID:     enrollment.ID,
+		PaymentID:        paymentRecord.ID,
+		Status:           gatewayResp.Status,
+		GrossAmount:      amount,
+		DiscountAmount:   discountAmount,
+		PaymentFee:       gatewayFee,
+		NetAmount:        netAfterFee,
+		InstructorAmount: netAfterFee.Percent(uc.instructorPercent),
+		PlatformAmount:   netAfterFee.Percent(uc.platformPercent),
+		FeeSurcharge:     surcharge,
+		TotalAmount:      chargeAmount,
+		LineItems:        paymentRecord.LineItems,
+	}
+	if coupon != nil {
+		response.CouponCode = coupon.Code
+	}
+	if req.PaymentMethod == "pix" {
+		response.PixQRCode = gatewayResp.PixQRCodeBase64
+		response.PixCopyPaste = gatewayResp.PixCopyPaste
+		response.PixExpirationDate = gatewayResp.PixExpiration
+	}
+	if req.PaymentMethod == "boleto" {
+		response.BoletoURL = gatewayResp.BoletoURL
+		response.BoletoBarCode = gatewayResp.BoletoBarCode
+		response.BoletoDueDate = gatewayResp.DueDate
+	}
+	if carnet != nil {
+		response.Installments = carnetInstallments(installments, carnet)
+	}
+
+	return response, nil
+}
+
+// enrollmentCoupon returns the coupon applied to the first charge of an enrollment. Its usage
+// was counted by that checkout, so it is not checked or counted again.
+func (uc *checkoutUseCase) enrollmentCoupon(ctx context.Context, payments []entity.Payment) (*entity.Coupon, error) {
+	for _, p := range payments {
+		if p.CouponID != nil {
+			return uc.couponRepo.FindByID(ctx, *p.CouponID)
+		}
+	}
+	return nil, nil
+}
+
+// releaseCharges cancels the charges of an enrollment that can still be paid, on the gateway
+// and then locally, and returns ErrRetryPaid when one of them was paid in the meantime (the
+// webhook settles it). The boletos of a carnê are cancelled one by one.
+func (uc *checkoutUseCase) releaseCharges(ctx context.Context, payments []entity.Payment) error {
+	for i := range payments {
+		payment := &payments[i]
+		if payment.IsPaid() {
+			return ErrRetryPaid
+		}
+		if !retryReleasable(payment.Status) {
+			continue
+		}
+
+		charges := []*entity.Payment{payment}
+		if payment.GatewayPaymentID == nil && payment.InstallmentCount > 1 {
+			installments, err := uc.paymentRepo.FindInstallments(ctx, payment.ID)
+			if err != nil {
+				return err
+			}
+			for j := range installments {
+				if installments[j].IsPaid() {
+					return ErrRetryPaid
+				}
+				if retryReleasable(installments[j].Status) {
+					charges = append(charges, &installments[j])
+				}
+			}
+		}
+
+		for _, charge := range charges {
+			if err := uc.cancelCharge(ctx, charge); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cancelCharge cancels the gateway charge of a payment, unless the gateway reports it paid
+// or already cancelled, and then the payment
+func (uc *checkoutUseCase) cancelCharge(ctx context.Context, payment *entity.Payment) error {
+	if payment.GatewayPaymentID != nil && *payment.GatewayPaymentID != "" {
+		gw := gateway.ForPayment(uc.gw, payment.Gateway)
+		charge, err := gw.GetPayment(ctx, *payment.GatewayPaymentID)
+		if err != nil {
+			return gateway.Failure(err)
+		}
+		switch charge.Status {
+		case gateway.StatusConfirmed, gateway.StatusReceived:
+			return ErrRetryPaid
+		case gateway.StatusCancelled:
+		default:
+			if err := gw.CancelPayment(ctx, *payment.GatewayPaymentID); err != nil {
+				return gateway.Failure(err)
+			}
+		}
+	}
+
+	prevStatus := payment.Status
+	now := time.Now()
+	payment.Status = entity.FinPaymentStatusCancelled
+	payment.CancelledAt = &now
+	if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+		return err
+	}
+
+	event := "checkout_retry"
+	description := "charge replaced by a checkout retry"
+	txLog := &entity.PaymentTransaction{
+		ID:             uuid.New().String(),
+		PaymentID:      payment.ID,
+		PreviousStatus: &prevStatus,
+		NewStatus:      payment.Status,
+		EventSource:    entity.EventSourceAPI,
+		EventType:      entity.TxEventStatusChanged,
+		GatewayEventID: &event,
+		Amount:         &payment.NetAmount,
+		Description:    &description,
+	}
+	if err := uc.paymentTxnRepo.Create(ctx, txLog); err != nil {
+		log.Printf("Failed to log payment transaction: %v", err)
+	}
+	return nil
+}
+
+// retryReleasable reports whether a payment in the status may still be paid on the gateway
+func retryReleasable(status string) bool {
+	switch status {
+	case entity.FinPaymentStatusPending, entity.FinPaymentStatusAwaitingPayment, entity.FinPaymentStatusOverdue:
+		return true
+	}
+	return false
+}
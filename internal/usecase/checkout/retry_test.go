@@ -0,0 +1,127 @@
+package checkout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/apperror"
+)
+
+func newRetryUseCase() (*checkoutUseCase, *testutil.MockPaymentRepository, *testutil.MockMatriculaRepository, *[]string) {
+	uc := newMethodsUseCase("pix,boleto,card")
+	payments := testutil.NewMockPaymentRepository()
+	enrollments := testutil.NewMockMatriculaRepository()
+	var cancelled []string
+	uc.gw.(*testutil.MockGateway).CancelPaymentFunc = func(ctx context.Context, id string) error {
+		cancelled = append(cancelled, id)
+		return nil
+	}
+	uc.paymentRepo = payments
+	uc.matriculaRepo = enrollments
+	uc.paymentTxnRepo = testutil.NewMockPaymentTransactionRepository()
+	return uc, payments, enrollments, &cancelled
+}
+
+func TestRetryCheckout_Rejects(t *testing.T) {
+	uc, payments, enrollments, cancelled := newRetryUseCase()
+	ctx := context.Background()
+	req := &RetryRequest{PaymentMethod: "pix"}
+
+	if _, err := uc.RetryCheckout(ctx, "missing", req, "s1", "student"); !hasCode(err, apperror.CodeNotFound) {
+		t.Errorf("expected an unknown enrollment to be not found, got %v", err)
+	}
+
+	enrollments.Enrollments["e1"] = &entity.Matricula{ID: "e1", StudentID: "s1", Status: entity.EnrollmentStatusActive, PaymentStatus: entity.PaymentStatusConfirmed, Amount: 40}
+	if _, err := uc.RetryCheckout(ctx, "e1", req, "s1", "student"); err != ErrRetryNotAllowed {
+		t.Errorf("expected an active enrollment to be refused, got %v", err)
+	}
+	// Expired after its access ended, not for lack of payment
+	enrollments.Enrollments["e1"].Status = entity.EnrollmentStatusExpired
+	if _, err := uc.RetryCheckout(ctx, "e1", req, "s1", "student"); err != ErrRetryNotAllowed {
+		t.Errorf("expected a paid enrollment to be refused, got %v", err)
+	}
+
+	// Paid on the gateway before the webhook arrived: the charge is kept
+	enrollments.Enrollments["e1"].Status = entity.EnrollmentStatusPending
+	enrollments.Enrollments["e1"].PaymentStatus = entity.PaymentStatusPending
+	charge := "pay_1"
+	payments.Payments["p1"] = &entity.Payment{ID: "p1", EnrollmentID: "e1", PaymentMethod: "pix", Status: entity.FinPaymentStatusPending, GatewayPaymentID: &charge}
+	uc.gw.(*testutil.MockGateway).GetPaymentFunc = func(ctx context.Context, id string) (*gateway.PaymentResponse, error) {
+		return &gateway.PaymentResponse{GatewayPaymentID: id, Status: gateway.StatusReceived}, nil
+	}
+	if _, err := uc.RetryCheckout(ctx, "e1", req, "s1", "student"); err != ErrRetryPaid {
+		t.Errorf("expected a paid charge to be refused, got %v", err)
+	}
+	if len(*cancelled) != 0 || payments.Payments["p1"].Status != entity.FinPaymentStatusPending {
+		t.Errorf("expected the paid charge to be kept, got %v", *cancelled)
+	}
+	if status := enrollments.Enrollments["e1"].Status; status != entity.EnrollmentStatusPending {
+		t.Errorf("expected the claim of the failed retry to be released, got %q", status)
+	}
+}
+
+func TestRetryCheckout_OwnerAndClaim(t *testing.T) {
+	uc, _, enrollments, _ := newRetryUseCase()
+	ctx := context.Background()
+	req := &RetryRequest{PaymentMethod: "pix"}
+	enrollments.Enrollments["e1"] = &entity.Matricula{ID: "e1", StudentID: "s1", Status: entity.EnrollmentStatusPending, PaymentStatus: entity.PaymentStatusFailed, Amount: 40}
+
+	if _, err := uc.RetryCheckout(ctx, "e1", req, "s2", "student"); err != ErrRetryForbidden {
+		t.Errorf("expected another student to be refused, got %v", err)
+	}
+	if _, err := uc.RetryCheckout(ctx, "e1", req, "", ""); err != ErrRetryForbidden {
+		t.Errorf("expected an anonymous retry to be refused, got %v", err)
+	}
+
+	// Another retry holds the claim while it creates its charge
+	enrollments.Enrollments["e1"].Status = entity.EnrollmentStatusRetrying
+	if _, err := uc.RetryCheckout(ctx, "e1", req, "admin-1", "admin"); err != ErrRetryInProgress {
+		t.Errorf("expected a concurrent retry to be refused, got %v", err)
+	}
+	if status := enrollments.Enrollments["e1"].Status; status != entity.EnrollmentStatusRetrying {
+		t.Errorf("expected the claim of the other retry to be kept, got %q", status)
+	}
+}
+
+func TestReleaseCharges(t *testing.T) {
+	uc, payments, _, cancelled := newRetryUseCase()
+	ctx := context.Background()
+
+	one, two, expired := 1, 2, "pay_expired"
+	b1, b2 := "bol_1", "bol_2"
+	payments.Payments["p1"] = &entity.Payment{ID: "p1", EnrollmentID: "e1", Status: entity.FinPaymentStatusOverdue, GatewayPaymentID: &expired}
+	payments.Payments["p2"] = &entity.Payment{ID: "p2", EnrollmentID: "e1", Status: entity.FinPaymentStatusFailed}
+	payments.Payments["p3"] = &entity.Payment{ID: "p3", EnrollmentID: "e1", Status: entity.FinPaymentStatusPending, InstallmentCount: 2}
+	payments.Payments["p3-1"] = &entity.Payment{ID: "p3-1", EnrollmentID: "e1", Status: entity.FinPaymentStatusPending, GatewayPaymentID: &b1, InstallmentOf: strPtr("p3"), InstallmentNumber: &one}
+	payments.Payments["p3-2"] = &entity.Payment{ID: "p3-2", EnrollmentID: "e1", Status: entity.FinPaymentStatusPending, GatewayPaymentID: &b2, InstallmentOf: strPtr("p3"), InstallmentNumber: &two}
+
+	// The gateway already cancelled the first boleto
+	uc.gw.(*testutil.MockGateway).GetPaymentFunc = func(ctx context.Context, id string) (*gateway.PaymentResponse, error) {
+		status := gateway.StatusPending
+		if id == b1 {
+			status = gateway.StatusCancelled
+		}
+		return &gateway.PaymentResponse{GatewayPaymentID: id, Status: status}, nil
+	}
+
+	list, _ := payments.FindByEnrollmentID(ctx, "e1")
+	if err := uc.releaseCharges(ctx, list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*cancelled) != 2 {
+		t.Errorf("expected the open gateway charges to be cancelled, got %v", *cancelled)
+	}
+	for _, id := range []string{"p1", "p3", "p3-1", "p3-2"} {
+		if p := payments.Payments[id]; p.Status != entity.FinPaymentStatusCancelled || p.CancelledAt == nil {
+			t.Errorf("expected %s to be cancelled, got %q", id, p.Status)
+		}
+	}
+	if payments.Payments["p2"].Status != entity.FinPaymentStatusFailed {
+		t.Errorf("expected the failed payment to be kept, got %q", payments.Payments["p2"].Status)
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,236 @@
+package checkout
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/condotrack/api/pkg/signedurl"
+	"github.com/google/uuid"
+)
+
+// sessionRetention is how long expired, unpaid sessions are kept before they are deleted
+const sessionRetention = 24 * time.Hour
+
+// Errors returned for checkout session tokens
+var (
+	ErrSessionInvalid = apperror.New(apperror.CodeCheckoutSessionInvalid, "invalid checkout session token")
+	ErrSessionExpired = apperror.New(apperror.CodeCheckoutSessionExpired, "checkout session expired")
+	ErrSessionUsed    = apperror.New(apperror.CodeCheckoutSessionUsed, "checkout session was already paid")
+)
+
+// Session is an opened checkout session: the token a landing page hands to the embedded
+// checkout, and the price and payment methods of the course in it
+type Session struct {
+	SessionID      string          `json:"session_id"`
+	Token          string          `json:"token"`
+	Status         string          `json:"status"`
+	ExpiresAt      time.Time       `json:"expires_at"`
+	CourseID       string          `json:"course_id"`
+	CourseName     string          `json:"course_name"`
+	PaymentMethods *PaymentMethods `json:"payment_methods"`
+}
+
+// SessionCheckoutRequest represents the request to pay for the course of a checkout session;
+// the course, price and coupon come from the session
+type SessionCheckoutRequest struct {
+	// Student info
+	StudentID    string `json:"student_id" binding:"required"`
+	StudentName  string `json:"student_name" binding:"required"`
+	StudentEmail string `json:"student_email" binding:"required,email"`
+	StudentCPF   string `json:"student_cpf" binding:"required"`
+	StudentPhone string `json:"student_phone,omitempty"`
+
+	PaymentMethod string `json:"payment_method" binding:"required"` // pix, boleto, card
+
+	// Card info (required if payment_method is card)
+	CardInfo
+}
+
+// SessionStatus is what the embedded checkout polls: the status of the session and, once it
+// is paid, the enrollment and the status of its payment
+type SessionStatus struct {
+	SessionID     string    `json:"session_id"`
+	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	EnrollmentID  string    `json:"enrollment_id,omitempty"`
+	PaymentStatus string    `json:"payment_status,omitempty"`
+}
+
+// CreateSession opens a checkout session for a course. The price, with the course discount,
+// is fixed for the session, and the coupon is checked now and again when it is paid.
+func (uc *checkoutUseCase) CreateSession(ctx context.Context, req *entity.CreateCheckoutSessionRequest) (*Session, error) {
+	methods, err := uc.GetPaymentMethods(ctx, req.CourseID, req.DiscountCode)
+	if err != nil {
+		return nil, err
+	}
+	course, err := uc.courseRepo.FindByID(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, apperror.New(apperror.CodeNotFound, "course not found")
+	}
+
+	// Tokens carry the expiry in seconds, so the session keeps the same precision
+	now := time.Now()
+	session := &entity.CheckoutSession{
+		ID:           uuid.New().String(),
+		CourseID:     course.ID,
+		Amount:       methods.Price - methods.DiscountAmount,
+		DiscountCode: nilIfEmpty(methods.CouponCode),
+		Status:       entity.CheckoutSessionStatusOpen,
+		ExpiresAt:    now.Add(uc.sessionTTL).Truncate(time.Second),
+		CreatedAt:    now,
+	}
+	if err := uc.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		SessionID:      session.ID,
+		Token:          uc.sessionToken(session),
+		Status:         session.Status,
+		ExpiresAt:      session.ExpiresAt,
+		CourseID:       course.ID,
+		CourseName:     course.Name,
+		PaymentMethods: methods,
+	}, nil
+}
+
+// CheckoutSession pays for the course of a checkout session. A session is paid once; a
+// checkout that fails reopens it so the buyer can try again while it has not expired.
+func (uc *checkoutUseCase) CheckoutSession(ctx context.Context, token string, req *SessionCheckoutRequest) (*CheckoutResponse, error) {
+	session, err := uc.findSession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	switch session.CurrentStatus(time.Now()) {
+	case entity.CheckoutSessionStatusOpen:
+	case entity.CheckoutSessionStatusExpired:
+		return nil, ErrSessionExpired
+	default:
+		return nil, ErrSessionUsed
+	}
+
+	course, err := uc.courseRepo.FindByID(ctx, session.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil || !course.IsActive {
+		return nil, apperror.New(apperror.CodeNotFound, "course not found")
+	}
+
+	claimed, err := uc.sessionRepo.Claim(ctx, session.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrSessionUsed
+	}
+
+	checkoutReq := &CheckoutRequest{
+		StudentID:      req.StudentID,
+		StudentName:    req.StudentName,
+		StudentEmail:   req.StudentEmail,
+		StudentCPF:     req.StudentCPF,
+		StudentPhone:   req.StudentPhone,
+		CourseID:       course.ID,
+		CourseName:     course.Name,
+		InstructorID:   derefString(course.InstructorID),
+		InstructorName: derefString(course.InstructorName),
+		Amount:         session.Amount,
+		DiscountCode:   derefString(session.DiscountCode),
+		PaymentMethod:  req.PaymentMethod,
+		CardInfo:       req.CardInfo,
+	}
+	result, err := uc.CreateCheckout(ctx, checkoutReq)
+	if err != nil {
+		if releaseErr := uc.sessionRepo.Release(ctx, session.ID); releaseErr != nil {
+			log.Printf("Failed to reopen checkout session %s: %v", session.ID, releaseErr)
+		}
+		return nil, err
+	}
+	if err := uc.sessionRepo.Complete(ctx, session.ID, result.EnrollmentID); err != nil {
+		log.Printf("Failed to complete checkout session %s: %v", session.ID, err)
+	}
+	return result, nil
+}
+
+// GetSessionStatus returns the status of a checkout session. Tokens past their expiry can
+// still poll, so a checkout paid just before the end sees its payment confirmed.
+func (uc *checkoutUseCase) GetSessionStatus(ctx context.Context, token string) (*SessionStatus, error) {
+	session, err := uc.findSession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SessionStatus{
+		SessionID: session.ID,
+		Status:    session.CurrentStatus(time.Now()),
+		ExpiresAt: session.ExpiresAt,
+	}
+	if session.EnrollmentID != nil {
+		status.EnrollmentID = *session.EnrollmentID
+		enrollment, err := uc.matriculaRepo.FindByID(ctx, *session.EnrollmentID)
+		if err != nil {
+			return nil, err
+		}
+		if enrollment != nil {
+			status.PaymentStatus = enrollment.PaymentStatus
+		}
+	}
+	return status, nil
+}
+
+// StartSessionCleanup deletes the checkout sessions that expired unpaid, every hour
+func (uc *checkoutUseCase) StartSessionCleanup(lc *lifecycle.Manager) {
+	lc.Every("checkout session cleanup", time.Hour, false, func(ctx context.Context) {
+		n, err := uc.sessionRepo.DeleteExpired(ctx, time.Now().Add(-sessionRetention))
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to delete expired checkout sessions: %v", err)
+			}
+			return
+		}
+		if n > 0 {
+			log.Printf("Expired checkout sessions deleted: %d", n)
+		}
+	})
+}
+
+// sessionToken signs the ID and expiry of a session as "<id>.<expires>.<signature>"
+func (uc *checkoutUseCase) sessionToken(session *entity.CheckoutSession) string {
+	sig := uc.sessionSigner.Sign(sessionPath(session.ID), session.ExpiresAt)
+	return session.ID + "." + sig.Get("expires") + "." + sig.Get("signature")
+}
+
+// findSession returns the session of a token with a valid signature, expired or not
+func (uc *checkoutUseCase) findSession(ctx context.Context, token string) (*entity.CheckoutSession, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrSessionInvalid
+	}
+	// An expired token has a valid signature; the session tells whether it can still be paid
+	if _, err := uc.sessionSigner.Verify(sessionPath(parts[0]), parts[1], parts[2], time.Now()); err != nil && !errors.Is(err, signedurl.ErrExpired) {
+		return nil, ErrSessionInvalid
+	}
+
+	session, err := uc.sessionRepo.FindByID(ctx, parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrSessionInvalid
+	}
+	return session, nil
+}
+
+func sessionPath(id string) string {
+	return "checkout-session/" + id
+}
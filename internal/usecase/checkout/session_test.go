@@ -0,0 +1,106 @@
+package checkout
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/signedurl"
+)
+
+func newSessionUseCase() (*checkoutUseCase, *testutil.MockCheckoutSessionRepository, *testutil.MockMatriculaRepository) {
+	uc := newMethodsUseCase("pix,card")
+	sessions := testutil.NewMockCheckoutSessionRepository()
+	enrollments := testutil.NewMockMatriculaRepository()
+	uc.sessionRepo = sessions
+	uc.matriculaRepo = enrollments
+	uc.sessionSigner = signedurl.New("secret")
+	uc.sessionTTL = 30 * time.Minute
+	return uc, sessions, enrollments
+}
+
+func TestCreateSession(t *testing.T) {
+	uc, sessions, _ := newSessionUseCase()
+	ctx := context.Background()
+
+	session, err := uc.CreateSession(ctx, &entity.CreateCheckoutSessionRequest{CourseID: "c1", DiscountCode: "DEZ"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != entity.CheckoutSessionStatusOpen || session.PaymentMethods.Amount != 3600 || len(session.PaymentMethods.Methods) != 2 {
+		t.Fatalf("expected an open session priced with the coupon, got %+v", session)
+	}
+	stored := sessions.Sessions[session.SessionID]
+	if stored == nil || stored.Amount != 4000 || stored.DiscountCode == nil || *stored.DiscountCode != "DEZ" {
+		t.Fatalf("expected the discounted price and the coupon to be kept, got %+v", stored)
+	}
+	if !strings.HasPrefix(session.Token, session.SessionID+".") {
+		t.Errorf("expected the token to carry the session ID, got %q", session.Token)
+	}
+
+	if _, err := uc.CreateSession(ctx, &entity.CreateCheckoutSessionRequest{CourseID: "c2"}); !hasCode(err, apperror.CodeNotFound) {
+		t.Errorf("expected an inactive course to be not found, got %v", err)
+	}
+	if _, err := uc.CreateSession(ctx, &entity.CreateCheckoutSessionRequest{CourseID: "c1", DiscountCode: "NOPE"}); !hasCode(err, apperror.CodeCouponNotFound) {
+		t.Errorf("expected an unknown coupon to be rejected, got %v", err)
+	}
+}
+
+func TestGetSessionStatus(t *testing.T) {
+	uc, sessions, enrollments := newSessionUseCase()
+	ctx := context.Background()
+
+	session, _ := uc.CreateSession(ctx, &entity.CreateCheckoutSessionRequest{CourseID: "c1"})
+	status, err := uc.GetSessionStatus(ctx, session.Token)
+	if err != nil || status.Status != entity.CheckoutSessionStatusOpen || status.EnrollmentID != "" {
+		t.Fatalf("expected an open session, got %+v, %v", status, err)
+	}
+
+	enrollments.Enrollments["enr-1"] = &entity.Matricula{ID: "enr-1", PaymentStatus: entity.PaymentStatusPending}
+	sessions.Claim(ctx, session.SessionID, time.Now())
+	sessions.Complete(ctx, session.SessionID, "enr-1")
+	status, _ = uc.GetSessionStatus(ctx, session.Token)
+	if status.Status != entity.CheckoutSessionStatusCompleted || status.EnrollmentID != "enr-1" || status.PaymentStatus != entity.PaymentStatusPending {
+		t.Errorf("expected the enrollment of the paid session, got %+v", status)
+	}
+
+	tampered := session.Token[:len(session.Token)-1] + "0"
+	if tampered == session.Token {
+		tampered = session.Token[:len(session.Token)-1] + "1"
+	}
+	for _, token := range []string{"", "garbage", tampered, "missing." + strings.SplitN(session.Token, ".", 2)[1]} {
+		if _, err := uc.GetSessionStatus(ctx, token); !hasCode(err, apperror.CodeCheckoutSessionInvalid) {
+			t.Errorf("expected %q to be rejected, got %v", token, err)
+		}
+	}
+}
+
+func TestCheckoutSession_RejectsExpiredAndUsedSessions(t *testing.T) {
+	uc, sessions, _ := newSessionUseCase()
+	ctx := context.Background()
+	req := &SessionCheckoutRequest{StudentID: "s1", PaymentMethod: "pix"}
+
+	// Expired tokens can still poll, but not pay
+	uc.sessionTTL = -time.Minute
+	expired, _ := uc.CreateSession(ctx, &entity.CreateCheckoutSessionRequest{CourseID: "c1"})
+	if _, err := uc.CheckoutSession(ctx, expired.Token, req); !hasCode(err, apperror.CodeCheckoutSessionExpired) {
+		t.Errorf("expected an expired session, got %v", err)
+	}
+	if status, err := uc.GetSessionStatus(ctx, expired.Token); err != nil || status.Status != entity.CheckoutSessionStatusExpired {
+		t.Errorf("expected the expired status, got %+v, %v", status, err)
+	}
+
+	uc.sessionTTL = 30 * time.Minute
+	session, _ := uc.CreateSession(ctx, &entity.CreateCheckoutSessionRequest{CourseID: "c1"})
+	sessions.Claim(ctx, session.SessionID, time.Now())
+	if _, err := uc.CheckoutSession(ctx, session.Token, req); !hasCode(err, apperror.CodeCheckoutSessionUsed) {
+		t.Errorf("expected a session being paid to be rejected, got %v", err)
+	}
+	if sessions.Sessions[session.SessionID].Status != entity.CheckoutSessionStatusProcessing {
+		t.Errorf("expected the claim of the other checkout to be kept, got %q", sessions.Sessions[session.SessionID].Status)
+	}
+}
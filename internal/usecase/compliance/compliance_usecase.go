@@ -0,0 +1,87 @@
+package compliance
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+// DefaultDays is the window of the dashboard when none is given
+const DefaultDays = 60
+
+// UseCase defines the compliance dashboard use case
+type UseCase interface {
+	// GetExpirations returns the documents expired or expiring within days, grouped by contract
+	GetExpirations(ctx context.Context, days int) (*entity.ComplianceExpirations, error)
+}
+
+type complianceUseCase struct {
+	repo repository.ComplianceRepository
+}
+
+// NewUseCase creates a new compliance use case
+func NewUseCase(repo repository.ComplianceRepository) UseCase {
+	return &complianceUseCase{repo: repo}
+}
+
+// GetExpirations returns the expirations up to days from today
+func (uc *complianceUseCase) GetExpirations(ctx context.Context, days int) (*entity.ComplianceExpirations, error) {
+	if days <= 0 {
+		days = DefaultDays
+	}
+
+	now := time.Now()
+	until := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+	items, err := uc.repo.FindExpirations(ctx, until)
+	if err != nil {
+		return nil, err
+	}
+	return groupExpirations(days, until, items, now), nil
+}
+
+// groupExpirations groups the expirations (ordered by expiry date) by contract, keeping the
+// order within each contract and putting the contracts with the soonest expiry first
+func groupExpirations(days int, until time.Time, items []entity.ComplianceExpiration, now time.Time) *entity.ComplianceExpirations {
+	result := &entity.ComplianceExpirations{
+		Days:      days,
+		Until:     until,
+		Contracts: []entity.ContractComplianceExpirations{},
+	}
+
+	index := make(map[string]int)
+	for _, item := range items {
+		item.DaysLeft = entity.DaysUntil(item.ExpiresAt, now)
+		item.Expired = item.DaysLeft < 0
+
+		i, ok := index[item.ContratoID]
+		if !ok {
+			i = len(result.Contracts)
+			index[item.ContratoID] = i
+			result.Contracts = append(result.Contracts, entity.ContractComplianceExpirations{
+				ContratoID:     item.ContratoID,
+				ContratoNome:   item.ContratoNome,
+				NextExpiration: item.ExpiresAt,
+			})
+		}
+		group := &result.Contracts[i]
+		if item.ExpiresAt.Before(group.NextExpiration) {
+			group.NextExpiration = item.ExpiresAt
+		}
+		if item.Expired {
+			group.Expired++
+			result.Expired++
+		} else {
+			group.Expiring++
+			result.Expiring++
+		}
+		group.Items = append(group.Items, item)
+	}
+
+	sort.SliceStable(result.Contracts, func(i, j int) bool {
+		return result.Contracts[i].NextExpiration.Before(result.Contracts[j].NextExpiration)
+	})
+	return result
+}
@@ -0,0 +1,51 @@
+package compliance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestGroupExpirations(t *testing.T) {
+	now := time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.UTC) }
+	supplier := "s1"
+	items := []entity.ComplianceExpiration{
+		{Source: entity.ComplianceSourceSupplierDocument, ID: "d1", ContratoID: "c2", ContratoNome: "Residencial B", SupplierID: &supplier, ExpiresAt: day(5)},
+		{Source: entity.ComplianceSourceContractDocument, ID: "d2", ContratoID: "c1", ContratoNome: "Residencial A", ExpiresAt: day(10)},
+		{Source: entity.ComplianceSourceCertification, ID: "d3", ContratoID: "c2", ContratoNome: "Residencial B", SupplierID: &supplier, ExpiresAt: day(20)},
+	}
+
+	result := groupExpirations(30, day(9), items, now)
+
+	if result.Expired != 1 || result.Expiring != 2 {
+		t.Errorf("expected 1 expired and 2 expiring, got %d and %d", result.Expired, result.Expiring)
+	}
+	if len(result.Contracts) != 2 {
+		t.Fatalf("expected 2 contracts, got %d", len(result.Contracts))
+	}
+
+	b := result.Contracts[0]
+	if b.ContratoID != "c2" || len(b.Items) != 2 || b.Expired != 1 || b.Expiring != 1 || !b.NextExpiration.Equal(day(5)) {
+		t.Errorf("expected the contract with the expired document first, got %+v", b)
+	}
+	if first := b.Items[0]; !first.Expired || first.DaysLeft != -5 {
+		t.Errorf("expected the first document expired 5 days ago, got %+v", first)
+	}
+	if second := b.Items[1]; second.Expired || second.DaysLeft != 10 {
+		t.Errorf("expected the certification to expire in 10 days, got %+v", second)
+	}
+
+	a := result.Contracts[1]
+	if a.ContratoID != "c1" || a.Items[0].DaysLeft != 0 || a.Items[0].Expired {
+		t.Errorf("expected a document expiring today not to be expired, got %+v", a)
+	}
+}
+
+func TestGroupExpirations_Empty(t *testing.T) {
+	result := groupExpirations(60, time.Now(), nil, time.Now())
+	if result.Contracts == nil || len(result.Contracts) != 0 {
+		t.Error("expected empty, non-nil contracts")
+	}
+}
@@ -0,0 +1,632 @@
+package contractbilling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/document"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/condotrack/api/pkg/phone"
+	"github.com/google/uuid"
+)
+
+// maxChargeError is the size of the error column of a failed charge
+const maxChargeError = 500
+
+// ErrAlreadyCharged is returned when the contract already has a charge for the period
+var ErrAlreadyCharged = apperror.New(apperror.CodeConflict, "contract is already charged for this period")
+
+// UseCase defines the contract billing use case interface
+type UseCase interface {
+	// GetPlan returns the monthly fee plan of a contract
+	GetPlan(ctx context.Context, contratoID string) (*entity.ContractBillingPlan, error)
+
+	// SavePlan sets up or changes the monthly fee plan of a contract; charges already issued keep their terms
+	SavePlan(ctx context.Context, contratoID string, req *entity.SaveContractBillingPlanRequest, userID string) (*entity.ContractBillingPlan, error)
+
+	// ListCharges returns contract charges matching the filters, newest period first
+	ListCharges(ctx context.Context, filters entity.ContractChargeFilters) ([]entity.ContractCharge, error)
+
+	// CreateCharge bills a contract for a period out of schedule, or retries a failed charge
+	CreateCharge(ctx context.Context, contratoID, period string) (*entity.ContractCharge, error)
+
+	// CancelCharge cancels an unpaid charge at the gateway
+	CancelCharge(ctx context.Context, contratoID, id string) (*entity.ContractCharge, error)
+
+	// GetReceivables returns the open balance and delinquency of each contract
+	GetReceivables(ctx context.Context) (*entity.ContractReceivablesReport, error)
+
+	// RunBilling issues the charges due by now and flags the late ones as overdue
+	RunBilling(ctx context.Context, now time.Time) (*entity.ContractBillingRunResult, error)
+
+	// StartBillingScheduler runs the billing every interval until shutdown
+	StartBillingScheduler(lc *lifecycle.Manager, interval time.Duration)
+
+	// HandleChargeEvent applies a payment webhook to the contract charge it belongs to; it
+	// reports false when the payment is not a contract charge
+	HandleChargeEvent(ctx context.Context, event *gateway.WebhookEvent) (bool, error)
+}
+
+type contractBillingUseCase struct {
+	repo         repository.ContractBillingRepository
+	contratoRepo repository.ContratoRepository
+	gw           gateway.PaymentGateway
+	leadDays     int
+}
+
+// NewUseCase creates a new contract billing use case
+func NewUseCase(
+	repo repository.ContractBillingRepository,
+	contratoRepo repository.ContratoRepository,
+	gw gateway.PaymentGateway,
+	cfg *config.Config,
+) UseCase {
+	return &contractBillingUseCase{
+		repo:         repo,
+		contratoRepo: contratoRepo,
+		gw:           gw,
+		leadDays:     cfg.ContractBillingLeadDays,
+	}
+}
+
+// GetPlan returns the monthly fee plan of a contract
+func (uc *contractBillingUseCase) GetPlan(ctx context.Context, contratoID string) (*entity.ContractBillingPlan, error) {
+	if _, err := uc.findContrato(ctx, contratoID); err != nil {
+		return nil, err
+	}
+	plan, err := uc.repo.FindPlanByContratoID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, errors.New("billing plan not found")
+	}
+	return plan, nil
+}
+
+// SavePlan sets up or replaces the monthly fee plan of a contract. Changing the payer
+// document drops the gateway customer so the next charge is billed to the new payer.
+func (uc *contractBillingUseCase) SavePlan(ctx context.Context, contratoID string, req *entity.SaveContractBillingPlanRequest, userID string) (*entity.ContractBillingPlan, error) {
+	contrato, err := uc.findContrato(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := uc.repo.FindPlanByContratoID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		plan = &entity.ContractBillingPlan{
+			ID:         uuid.New().String(),
+			ContratoID: contratoID,
+			Active:     true,
+			CreatedAt:  time.Now(),
+		}
+		if userID != "" {
+			plan.CreatedBy = &userID
+		}
+	}
+
+	payerDocument, err := document.Normalize(req.PayerDocument)
+	if err != nil {
+		return nil, errors.New("invalid payer_document: use a valid CPF or CNPJ")
+	}
+	payerPhone, err := phone.NormalizeOptional(req.PayerPhone)
+	if err != nil {
+		return nil, errors.New("invalid payer_phone: include the area code")
+	}
+	if payerDocument != plan.PayerDocument {
+		plan.Gateway = nil
+		plan.GatewayCustomerID = nil
+	}
+
+	plan.Description = strings.TrimSpace(req.Description)
+	if plan.Description == "" {
+		plan.Description = "Taxa de administração - " + contrato.Nome
+	}
+	plan.MonthlyAmount = roundCents(req.MonthlyAmount)
+	plan.DueDay = req.DueDay
+	plan.BillingType = req.BillingType
+	if plan.BillingType == "" {
+		plan.BillingType = entity.MethodBoleto
+	}
+	plan.PayerName = strings.TrimSpace(req.PayerName)
+	plan.PayerDocument = payerDocument
+	plan.PayerEmail = strings.TrimSpace(req.PayerEmail)
+	plan.PayerPhone = payerPhone
+	plan.StartPeriod = req.StartPeriod
+	plan.EndPeriod = req.EndPeriod
+	if plan.EndPeriod != nil && *plan.EndPeriod == "" {
+		plan.EndPeriod = nil
+	}
+	if req.Active != nil {
+		plan.Active = *req.Active
+	}
+	if err := validatePlan(plan); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.SavePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	plan.ContratoNome = contrato.Nome
+	return plan, nil
+}
+
+// ListCharges returns contract charges, with the days late of the unpaid ones
+func (uc *contractBillingUseCase) ListCharges(ctx context.Context, filters entity.ContractChargeFilters) ([]entity.ContractCharge, error) {
+	if filters.ContratoID != "" {
+		if _, err := uc.findContrato(ctx, filters.ContratoID); err != nil {
+			return nil, err
+		}
+	}
+	if (filters.From != "" && !entity.IsValidEvaluationPeriod(filters.From)) ||
+		(filters.To != "" && !entity.IsValidEvaluationPeriod(filters.To)) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+
+	charges, err := uc.repo.FindCharges(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range charges {
+		charges[i].DaysOverdue = charges[i].OverdueDays(now)
+	}
+	return charges, nil
+}
+
+// CreateCharge bills a contract for a period without waiting for the schedule
+func (uc *contractBillingUseCase) CreateCharge(ctx context.Context, contratoID, period string) (*entity.ContractCharge, error) {
+	if _, err := uc.findContrato(ctx, contratoID); err != nil {
+		return nil, err
+	}
+	if !entity.IsValidEvaluationPeriod(period) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+	plan, err := uc.repo.FindPlanByContratoID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil || !plan.Active {
+		return nil, errors.New("invalid contract: no active billing plan")
+	}
+	if !plan.BillsPeriod(period) {
+		return nil, errors.New("invalid period: outside the billing plan")
+	}
+
+	charge, created, err := uc.bill(ctx, plan, period, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyCharged
+	}
+	if charge.Status == entity.ContractChargeStatusFailed {
+		return nil, gateway.Failure(errors.New(deref(charge.Error)))
+	}
+	charge.ContratoNome = plan.ContratoNome
+	return charge, nil
+}
+
+// CancelCharge cancels an unpaid charge. The gateway charge is cancelled first so the payer
+// can no longer pay a receivable that was written off.
+func (uc *contractBillingUseCase) CancelCharge(ctx context.Context, contratoID, id string) (*entity.ContractCharge, error) {
+	charge, err := uc.repo.FindChargeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if charge == nil || charge.ContratoID != contratoID {
+		return nil, errors.New("charge not found")
+	}
+	if !charge.IsReceivable() && charge.Status != entity.ContractChargeStatusFailed {
+		return nil, apperror.New(apperror.CodeConflict, "only unpaid charges can be cancelled")
+	}
+
+	if charge.GatewayPaymentID != nil {
+		gw := gateway.ForPayment(uc.gw, deref(charge.Gateway))
+		if err := gw.CancelPayment(ctx, *charge.GatewayPaymentID); err != nil {
+			return nil, gateway.Failure(err)
+		}
+	}
+	charge.Status = entity.ContractChargeStatusCancelled
+	if err := uc.repo.UpdateCharge(ctx, charge); err != nil {
+		return nil, err
+	}
+	return charge, nil
+}
+
+// GetReceivables returns the open balance of each contract with the late part by age
+func (uc *contractBillingUseCase) GetReceivables(ctx context.Context) (*entity.ContractReceivablesReport, error) {
+	rows, err := uc.repo.FindReceivables(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return buildReceivablesReport(rows), nil
+}
+
+// RunBilling flags late charges as overdue and issues the charges of the current and next
+// months that are within the lead days of their due date. Failed charges are tried again.
+func (uc *contractBillingUseCase) RunBilling(ctx context.Context, now time.Time) (*entity.ContractBillingRunResult, error) {
+	result := &entity.ContractBillingRunResult{}
+
+	overdue, err := uc.repo.MarkOverdue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	result.Overdue = int(overdue)
+
+	plans, err := uc.repo.FindActivePlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range plans {
+		plan := &plans[i]
+		for _, period := range duePeriods(plan, now, uc.leadDays) {
+			charge, created, err := uc.bill(ctx, plan, period, now)
+			if err != nil {
+				log.Printf("[CONTRACT BILLING] Failed to bill contract %s for %s: %v", plan.ContratoID, period, err)
+				result.Failed++
+				continue
+			}
+			if !created {
+				continue
+			}
+			if charge.Status == entity.ContractChargeStatusFailed {
+				result.Failed++
+			} else {
+				result.Created++
+			}
+		}
+	}
+	return result, nil
+}
+
+// StartBillingScheduler runs the billing every interval, starting at once
+func (uc *contractBillingUseCase) StartBillingScheduler(lc *lifecycle.Manager, interval time.Duration) {
+	lc.Every("contract billing", interval, true, func(ctx context.Context) {
+		result, err := uc.RunBilling(ctx, time.Now())
+		if err != nil {
+			log.Printf("[CONTRACT BILLING] Billing run failed: %v", err)
+			return
+		}
+		if result.Created > 0 || result.Failed > 0 || result.Overdue > 0 {
+			log.Printf("[CONTRACT BILLING] Issued %d charges, %d failed, %d went overdue",
+				result.Created, result.Failed, result.Overdue)
+		}
+	})
+}
+
+// HandleChargeEvent updates a contract charge from a payment webhook. Paid, refunded and
+// cancelled charges are final, so late or redelivered events do not reopen them.
+func (uc *contractBillingUseCase) HandleChargeEvent(ctx context.Context, event *gateway.WebhookEvent) (bool, error) {
+	charge, err := uc.repo.FindChargeByGatewayPaymentID(ctx, event.GatewayName, event.PaymentID)
+	if err != nil {
+		return false, err
+	}
+	if charge == nil {
+		return false, nil
+	}
+
+	if !applyEvent(charge, event, uc.feesOf(charge)) {
+		return true, nil
+	}
+	if err := uc.repo.UpdateCharge(ctx, charge); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// bill issues the charge of a plan for a period. It reports created=false when the period
+// was already charged; failed charges are claimed and issued again.
+func (uc *contractBillingUseCase) bill(ctx context.Context, plan *entity.ContractBillingPlan, period string, now time.Time) (*entity.ContractCharge, bool, error) {
+	charge, err := uc.repo.FindChargeByPeriod(ctx, plan.ContratoID, period)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if charge != nil {
+		if charge.Status != entity.ContractChargeStatusFailed {
+			return charge, false, nil
+		}
+		claimed, err := uc.repo.ClaimFailedCharge(ctx, charge.ID)
+		if err != nil || !claimed {
+			return charge, false, err
+		}
+		// A retry bills the current terms of the plan
+		retry, err := newCharge(plan, period, now)
+		if err != nil {
+			return nil, false, err
+		}
+		retry.ID, retry.CreatedAt = charge.ID, charge.CreatedAt
+		charge = retry
+	} else {
+		if charge, err = newCharge(plan, period, now); err != nil {
+			return nil, false, err
+		}
+		created, err := uc.repo.CreateCharge(ctx, charge)
+		if err != nil || !created {
+			return charge, false, err
+		}
+	}
+
+	uc.issue(ctx, plan, charge)
+	if err := uc.repo.UpdateCharge(ctx, charge); err != nil {
+		uc.discard(ctx, charge)
+		return nil, false, err
+	}
+	return charge, true, nil
+}
+
+// issue creates the gateway charge. A refusal leaves the charge failed with the reason, so
+// the next run tries again; the charge is then saved by the caller either way.
+func (uc *contractBillingUseCase) issue(ctx context.Context, plan *entity.ContractBillingPlan, charge *entity.ContractCharge) {
+	// Resolve the active gateway once so the customer and the charge live in the same one
+	gw := gateway.ForPayment(uc.gw, uc.gw.Name())
+
+	resp, err := uc.createPayment(ctx, gw, plan, charge)
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > maxChargeError {
+			msg = msg[:maxChargeError]
+		}
+		charge.Status = entity.ContractChargeStatusFailed
+		charge.Error = &msg
+		return
+	}
+
+	name := gw.Name()
+	charge.Status = entity.ContractChargeStatusOpen
+	charge.Error = nil
+	charge.Gateway = &name
+	charge.GatewayPaymentID = &resp.GatewayPaymentID
+	charge.InvoiceURL = nilIfEmpty(resp.InvoiceURL)
+	charge.BoletoURL = nilIfEmpty(resp.BoletoURL)
+	charge.PixCopyPaste = nilIfEmpty(resp.PixCopyPaste)
+}
+
+func (uc *contractBillingUseCase) createPayment(ctx context.Context, gw gateway.PaymentGateway, plan *entity.ContractBillingPlan, charge *entity.ContractCharge) (*gateway.PaymentResponse, error) {
+	customerID, err := uc.customer(ctx, gw, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	req := gateway.CreatePaymentRequest{
+		CustomerGatewayID: customerID,
+		Amount:            charge.Amount,
+		Description:       charge.Description,
+		DueDate:           charge.DueDate,
+		ExternalReference: charge.ID,
+	}
+	if charge.BillingType == entity.MethodPIX {
+		return gw.CreatePixPayment(ctx, req)
+	}
+	return gw.CreateBoletoPayment(ctx, req)
+}
+
+// customer returns the gateway customer of the payer, creating it in gw the first time
+func (uc *contractBillingUseCase) customer(ctx context.Context, gw gateway.PaymentGateway, plan *entity.ContractBillingPlan) (string, error) {
+	if plan.GatewayCustomerID != nil && deref(plan.Gateway) == gw.Name() {
+		return *plan.GatewayCustomerID, nil
+	}
+
+	customer, err := gw.CreateCustomer(ctx, gateway.CreateCustomerRequest{
+		Name:     plan.PayerName,
+		Email:    plan.PayerEmail,
+		Document: plan.PayerDocument,
+		Phone:    deref(plan.PayerPhone),
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := uc.repo.UpdatePlanCustomer(ctx, plan.ID, gw.Name(), customer.GatewayID); err != nil {
+		return "", err
+	}
+	name := gw.Name()
+	plan.Gateway = &name
+	plan.GatewayCustomerID = &customer.GatewayID
+	return customer.GatewayID, nil
+}
+
+// discard cancels a gateway charge that could not be recorded, so the payer is not billed
+// for a receivable nobody tracks
+func (uc *contractBillingUseCase) discard(ctx context.Context, charge *entity.ContractCharge) {
+	if charge.GatewayPaymentID == nil {
+		return
+	}
+	gw := gateway.ForPayment(uc.gw, deref(charge.Gateway))
+	if err := gw.CancelPayment(ctx, *charge.GatewayPaymentID); err != nil {
+		log.Printf("[CONTRACT BILLING] Failed to cancel unrecorded charge %s: %v", *charge.GatewayPaymentID, err)
+	}
+}
+
+func (uc *contractBillingUseCase) feesOf(charge *entity.ContractCharge) gateway.GatewayFees {
+	return gateway.ForPayment(uc.gw, deref(charge.Gateway)).GetFees()
+}
+
+func (uc *contractBillingUseCase) findContrato(ctx context.Context, contratoID string) (*entity.Contrato, error) {
+	contrato, err := uc.contratoRepo.FindByID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+	return contrato, nil
+}
+
+// newCharge is the pending charge of a plan for a period. Gateways reject due dates in the
+// past, so a charge issued late is due today.
+func newCharge(plan *entity.ContractBillingPlan, period string, now time.Time) (*entity.ContractCharge, error) {
+	due, err := plan.DueDate(period)
+	if err != nil {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if due.Before(today) {
+		due = today
+	}
+
+	month, _ := time.Parse("2006-01", period)
+	return &entity.ContractCharge{
+		ID:          uuid.New().String(),
+		PlanID:      plan.ID,
+		ContratoID:  plan.ContratoID,
+		Period:      period,
+		Description: fmt.Sprintf("%s - %s", plan.Description, month.Format("01/2006")),
+		Amount:      plan.MonthlyAmount,
+		DueDate:     due,
+		BillingType: plan.BillingType,
+		Status:      entity.ContractChargeStatusPending,
+		CreatedAt:   now,
+	}, nil
+}
+
+// duePeriods lists the periods of the current and next month the plan must be billed for by
+// now: those whose due date is at most leadDays away
+func duePeriods(plan *entity.ContractBillingPlan, now time.Time, leadDays int) []string {
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	issueBy := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, leadDays)
+
+	var periods []string
+	for _, month := range []time.Time{current, current.AddDate(0, 1, 0)} {
+		period := month.Format("2006-01")
+		if !plan.BillsPeriod(period) {
+			continue
+		}
+		due, err := plan.DueDate(period)
+		if err != nil || due.After(issueBy) {
+			continue
+		}
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// applyEvent moves the charge to the status of the webhook; it reports false when the
+// event changes nothing
+func applyEvent(charge *entity.ContractCharge, event *gateway.WebhookEvent, fees gateway.GatewayFees) bool {
+	if charge.Status == entity.ContractChargeStatusRefunded || charge.Status == entity.ContractChargeStatusCancelled {
+		return false
+	}
+
+	switch event.EventType {
+	case gateway.EventPaymentConfirmed, gateway.EventPaymentReceived:
+		if charge.Status == entity.ContractChargeStatusPaid {
+			return false
+		}
+		paid := charge.Amount
+		if event.Amount > 0 {
+			paid = roundCents(event.Amount)
+		}
+		paidAt := time.Now()
+		if event.PaidAt != nil {
+			paidAt = *event.PaidAt
+		}
+		charge.Status = entity.ContractChargeStatusPaid
+		charge.PaidAmount = &paid
+		charge.PaidAt = &paidAt
+		charge.GatewayFee = chargeFee(paid, event.NetAmount, charge.BillingType, fees)
+	case gateway.EventPaymentOverdue:
+		if charge.Status != entity.ContractChargeStatusOpen {
+			return false
+		}
+		charge.Status = entity.ContractChargeStatusOverdue
+	case gateway.EventPaymentRefunded, gateway.EventPaymentChargeback:
+		if charge.Status != entity.ContractChargeStatusPaid {
+			return false
+		}
+		charge.Status = entity.ContractChargeStatusRefunded
+	case gateway.EventPaymentDeleted:
+		if charge.Status == entity.ContractChargeStatusPaid {
+			return false
+		}
+		charge.Status = entity.ContractChargeStatusCancelled
+	default:
+		return false
+	}
+	return true
+}
+
+// chargeFee is the gateway fee of a paid charge: what the gateway kept when it reports the
+// net amount, the configured fee otherwise
+func chargeFee(paid, net float64, billingType string, fees gateway.GatewayFees) float64 {
+	if net > 0 && net <= paid {
+		return roundCents(paid - net)
+	}
+	if billingType == entity.MethodPIX {
+		return roundCents(paid * fees.PixPercent)
+	}
+	return roundCents(fees.BoletoFixed)
+}
+
+func buildReceivablesReport(rows []entity.ContractReceivable) *entity.ContractReceivablesReport {
+	report := &entity.ContractReceivablesReport{Contracts: rows}
+	if report.Contracts == nil {
+		report.Contracts = []entity.ContractReceivable{}
+	}
+	for i := range rows {
+		report.OpenAmount += rows[i].OpenAmount
+		report.OverdueAmount += rows[i].OverdueAmount
+	}
+	report.OpenAmount = roundCents(report.OpenAmount)
+	report.OverdueAmount = roundCents(report.OverdueAmount)
+	if report.OpenAmount > 0 {
+		pct := roundCents(report.OverdueAmount / report.OpenAmount * 100)
+		report.DelinquencyPct = &pct
+	}
+	return report
+}
+
+func validatePlan(plan *entity.ContractBillingPlan) error {
+	if plan.MonthlyAmount <= 0 {
+		return errors.New("invalid monthly_amount: must be greater than zero")
+	}
+	if plan.DueDay < 1 || plan.DueDay > 28 {
+		return errors.New("invalid due_day: use a day between 1 and 28")
+	}
+	if plan.BillingType != entity.MethodBoleto && plan.BillingType != entity.MethodPIX {
+		return errors.New("invalid billing_type: use boleto or pix")
+	}
+	if !entity.IsValidEvaluationPeriod(plan.StartPeriod) {
+		return errors.New("invalid start_period: use YYYY-MM")
+	}
+	if plan.EndPeriod != nil {
+		if !entity.IsValidEvaluationPeriod(*plan.EndPeriod) {
+			return errors.New("invalid end_period: use YYYY-MM")
+		}
+		if *plan.EndPeriod < plan.StartPeriod {
+			return errors.New("invalid end_period: must not be before start_period")
+		}
+	}
+	return nil
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,239 @@
+package contractbilling
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func testPlan() *entity.ContractBillingPlan {
+	return &entity.ContractBillingPlan{
+		ID:            "plan-1",
+		ContratoID:    "ct-1",
+		Description:   "Taxa de administração",
+		MonthlyAmount: 1500,
+		DueDay:        10,
+		BillingType:   entity.MethodBoleto,
+		PayerName:     "Condomínio Sol",
+		PayerDocument: "12345678000190",
+		PayerEmail:    "sindico@sol.com",
+		StartPeriod:   "2024-01",
+		Active:        true,
+	}
+}
+
+func newTestUseCase(repo *testutil.MockContractBillingRepository, gw *testutil.MockGateway) *contractBillingUseCase {
+	return &contractBillingUseCase{
+		repo:         repo,
+		contratoRepo: testutil.NewMockContratoRepository(&entity.Contrato{ID: "ct-1", Nome: "Residencial Sol"}),
+		gw:           gw,
+		leadDays:     10,
+	}
+}
+
+func TestRunBilling(t *testing.T) {
+	ctx := context.Background()
+	repo := testutil.NewMockContractBillingRepository(testPlan())
+	customers := 0
+	gw := &testutil.MockGateway{
+		CreateCustomerFunc: func(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+			customers++
+			return &gateway.CustomerResponse{GatewayID: "cust-1"}, nil
+		},
+	}
+	uc := newTestUseCase(repo, gw)
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+
+	result, err := uc.RunBilling(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Created != 1 || result.Failed != 0 {
+		t.Fatalf("expected one charge, got %+v", result)
+	}
+	charge, _ := repo.FindChargeByPeriod(ctx, "ct-1", "2024-05")
+	if charge == nil || charge.Status != entity.ContractChargeStatusOpen {
+		t.Fatalf("expected an open may charge, got %+v", charge)
+	}
+	if deref(charge.GatewayPaymentID) != "pay_boleto_mock_123" || deref(charge.Gateway) != "mock" {
+		t.Errorf("expected the gateway charge to be recorded, got %+v", charge)
+	}
+	if charge.Description != "Taxa de administração - 05/2024" || charge.Amount != 1500 {
+		t.Errorf("unexpected charge terms: %+v", charge)
+	}
+	if june, _ := repo.FindChargeByPeriod(ctx, "ct-1", "2024-06"); june != nil {
+		t.Errorf("june is outside the lead days, got %+v", june)
+	}
+
+	result, err = uc.RunBilling(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Created != 0 || len(repo.Charges) != 1 {
+		t.Errorf("expected a second run to bill nothing, got %+v", result)
+	}
+	if customers != 1 {
+		t.Errorf("expected the customer to be created once, got %d", customers)
+	}
+}
+
+func TestRunBillingRetriesFailedCharge(t *testing.T) {
+	ctx := context.Background()
+	repo := testutil.NewMockContractBillingRepository(testPlan())
+	refuse := true
+	gw := &testutil.MockGateway{
+		CreateBoletoPaymentFunc: func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+			if refuse {
+				return nil, errors.New("invalid cnpj")
+			}
+			return &gateway.PaymentResponse{GatewayPaymentID: "pay-1"}, nil
+		},
+	}
+	uc := newTestUseCase(repo, gw)
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+
+	result, _ := uc.RunBilling(ctx, now)
+	if result.Failed != 1 {
+		t.Fatalf("expected a failed charge, got %+v", result)
+	}
+	charge, _ := repo.FindChargeByPeriod(ctx, "ct-1", "2024-05")
+	if charge.Status != entity.ContractChargeStatusFailed || deref(charge.Error) != "invalid cnpj" {
+		t.Fatalf("expected the refusal to be recorded, got %+v", charge)
+	}
+	failedID := charge.ID
+
+	refuse = false
+	result, _ = uc.RunBilling(ctx, now)
+	if result.Created != 1 {
+		t.Fatalf("expected the failed charge to be retried, got %+v", result)
+	}
+	charge, _ = repo.FindChargeByPeriod(ctx, "ct-1", "2024-05")
+	if charge.ID != failedID || charge.Status != entity.ContractChargeStatusOpen || charge.Error != nil {
+		t.Errorf("expected the same charge to be issued, got %+v", charge)
+	}
+}
+
+func TestHandleChargeEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := testutil.NewMockContractBillingRepository(testPlan())
+	gwName, paymentID := "mock", "pay-1"
+	repo.Charges["ch-1"] = &entity.ContractCharge{
+		ID:               "ch-1",
+		ContratoID:       "ct-1",
+		Period:           "2024-05",
+		Amount:           1500,
+		BillingType:      entity.MethodBoleto,
+		Status:           entity.ContractChargeStatusOpen,
+		Gateway:          &gwName,
+		GatewayPaymentID: &paymentID,
+	}
+	uc := newTestUseCase(repo, &testutil.MockGateway{})
+	paidAt := time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC)
+
+	event := &gateway.WebhookEvent{
+		EventType:   gateway.EventPaymentReceived,
+		GatewayName: "mock",
+		PaymentID:   "pay-1",
+		Amount:      1500,
+		NetAmount:   1496.01,
+		PaidAt:      &paidAt,
+	}
+	handled, err := uc.HandleChargeEvent(ctx, event)
+	if err != nil || !handled {
+		t.Fatalf("expected the event to be handled, got %v, %v", handled, err)
+	}
+	charge := repo.Charges["ch-1"]
+	if charge.Status != entity.ContractChargeStatusPaid || charge.GatewayFee != 3.99 {
+		t.Errorf("expected a paid charge with the gateway fee, got %+v", charge)
+	}
+
+	// A late overdue event must not reopen a paid charge
+	event.EventType = gateway.EventPaymentOverdue
+	if _, err := uc.HandleChargeEvent(ctx, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.Charges["ch-1"].Status != entity.ContractChargeStatusPaid {
+		t.Errorf("expected the charge to stay paid, got %s", repo.Charges["ch-1"].Status)
+	}
+
+	handled, err = uc.HandleChargeEvent(ctx, &gateway.WebhookEvent{GatewayName: "mock", PaymentID: "course-pay"})
+	if err != nil || handled {
+		t.Errorf("expected a course payment not to be handled, got %v, %v", handled, err)
+	}
+}
+
+func TestDuePeriods(t *testing.T) {
+	plan := testPlan()
+	plan.DueDay = 5
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{"before the lead days", time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC), []string{"2024-05"}},
+		{"next month within the lead days", time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC), []string{"2024-05", "2024-06"}},
+		{"across the year end", time.Date(2024, 12, 28, 0, 0, 0, 0, time.UTC), []string{"2024-12", "2025-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := duePeriods(plan, tt.now, 10); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	end := "2024-05"
+	plan.EndPeriod = &end
+	if got := duePeriods(plan, time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC), 10); !reflect.DeepEqual(got, []string{"2024-05"}) {
+		t.Errorf("expected the plan to end in may, got %v", got)
+	}
+}
+
+func TestSavePlanValidation(t *testing.T) {
+	ctx := context.Background()
+	uc := newTestUseCase(testutil.NewMockContractBillingRepository(), &testutil.MockGateway{})
+	req := &entity.SaveContractBillingPlanRequest{
+		MonthlyAmount: 1500,
+		DueDay:        10,
+		PayerName:     "Condomínio Sol",
+		PayerDocument: "11.222.333/0001-81",
+		PayerEmail:    "sindico@sol.com",
+		StartPeriod:   "2024-01",
+	}
+
+	plan, err := uc.SavePlan(ctx, "ct-1", req, "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.PayerDocument != "11222333000181" || plan.BillingType != entity.MethodBoleto {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+	if plan.Description != "Taxa de administração - Residencial Sol" {
+		t.Errorf("unexpected default description: %q", plan.Description)
+	}
+
+	for _, invalid := range []string{"123", "11.222.333/0001-80"} {
+		req.PayerDocument = invalid
+		if _, err := uc.SavePlan(ctx, "ct-1", req, "user-1"); err == nil {
+			t.Errorf("expected the payer document %s to be rejected", invalid)
+		}
+	}
+
+	req.PayerDocument = "11222333000181"
+	end := "2023-12"
+	req.EndPeriod = &end
+	if _, err := uc.SavePlan(ctx, "ct-1", req, "user-1"); err == nil {
+		t.Error("expected an end period before the start to be rejected")
+	}
+
+	if _, err := uc.SavePlan(ctx, "ct-missing", req, "user-1"); err == nil || err.Error() != "contract not found" {
+		t.Errorf("expected contract not found, got %v", err)
+	}
+}
@@ -0,0 +1,360 @@
+package contractdocument
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/google/uuid"
+)
+
+// ErrAccessDenied is returned when the user is not part of the contract team
+var ErrAccessDenied = errors.New("access restricted to the contract team")
+
+// UseCase defines the contract document use case interface
+type UseCase interface {
+	ListDocuments(ctx context.Context, contratoID string, filters entity.ContractDocumentFilters, userID, role string) ([]entity.ContractDocument, error)
+	GetDocument(ctx context.Context, contratoID, documentID, userID, role string) (*entity.ContractDocument, error)
+	CreateDocument(ctx context.Context, contratoID string, req *entity.CreateContractDocumentRequest, file io.Reader, userID, role string) (*entity.ContractDocument, error)
+	AddVersion(ctx context.Context, contratoID, documentID string, upload *entity.ContractDocumentUpload, file io.Reader, userID, role string) (*entity.ContractDocumentVersion, error)
+	OpenVersion(ctx context.Context, contratoID, documentID string, version int, on *time.Time, userID, role string) (*entity.ContractDocumentVersion, io.ReadCloser, error)
+	ArchiveDocument(ctx context.Context, contratoID, documentID, userID, role string) error
+}
+
+type contractDocumentUseCase struct {
+	repo         repository.ContractDocumentRepository
+	contratoRepo repository.ContratoRepository
+	teamRepo     repository.TeamRepository
+	storage      *storage.StorageService
+	db           *database.MySQL
+	bucket       string
+}
+
+// NewUseCase creates a new contract document use case
+func NewUseCase(
+	repo repository.ContractDocumentRepository,
+	contratoRepo repository.ContratoRepository,
+	teamRepo repository.TeamRepository,
+	storageService *storage.StorageService,
+	db *database.MySQL,
+	cfg *config.Config,
+) UseCase {
+	return &contractDocumentUseCase{
+		repo:         repo,
+		contratoRepo: contratoRepo,
+		teamRepo:     teamRepo,
+		storage:      storageService,
+		db:           db,
+		bucket:       cfg.MinioBucketContracts,
+	}
+}
+
+// ListDocuments returns the documents of a contract
+func (uc *contractDocumentUseCase) ListDocuments(ctx context.Context, contratoID string, filters entity.ContractDocumentFilters, userID, role string) ([]entity.ContractDocument, error) {
+	if err := uc.authorize(ctx, contratoID, userID, role); err != nil {
+		return nil, err
+	}
+	return uc.repo.FindByContratoID(ctx, contratoID, filters)
+}
+
+// GetDocument returns a document with its version history
+func (uc *contractDocumentUseCase) GetDocument(ctx context.Context, contratoID, documentID, userID, role string) (*entity.ContractDocument, error) {
+	if err := uc.authorize(ctx, contratoID, userID, role); err != nil {
+		return nil, err
+	}
+
+	doc, err := uc.findDocument(ctx, contratoID, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Versions, err = uc.repo.FindVersions(ctx, doc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// CreateDocument attaches a new document to a contract and stores its first version
+func (uc *contractDocumentUseCase) CreateDocument(ctx context.Context, contratoID string, req *entity.CreateContractDocumentRequest, file io.Reader, userID, role string) (*entity.ContractDocument, error) {
+	if uc.storage == nil {
+		return nil, errors.New("storage service is not available")
+	}
+	if !entity.IsValidContractDocumentType(req.DocumentType) {
+		return nil, errors.New("invalid document_type: use contract, addendum, price_table or other")
+	}
+
+	contrato, err := uc.contratoRepo.FindByID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+	if err := uc.authorize(ctx, contratoID, userID, role); err != nil {
+		return nil, err
+	}
+
+	version, err := newVersion(&req.Upload, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &entity.ContractDocument{
+		ID:             uuid.New().String(),
+		ContratoID:     contrato.ID,
+		DocumentType:   req.DocumentType,
+		Title:          strings.TrimSpace(req.Title),
+		Description:    req.Description,
+		CurrentVersion: 1,
+		IsActive:       true,
+		CreatedAt:      time.Now(),
+	}
+	if userID != "" {
+		doc.CreatedBy = &userID
+	}
+	version.DocumentID = doc.ID
+	version.Version = 1
+	version.ObjectKey = objectKey(doc, 1, req.Upload.FileName)
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := uc.repo.CreateWithTx(ctx, tx, doc); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.CreateVersionWithTx(ctx, tx, version); err != nil {
+		return nil, err
+	}
+
+	if err := uc.store(ctx, version, file); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		uc.discard(ctx, version)
+		return nil, err
+	}
+
+	doc.Versions = []entity.ContractDocumentVersion{*version}
+	return doc, nil
+}
+
+// AddVersion uploads a new revision of a document. Earlier open-ended versions
+// stop being effective the day before the new one takes effect.
+func (uc *contractDocumentUseCase) AddVersion(ctx context.Context, contratoID, documentID string, upload *entity.ContractDocumentUpload, file io.Reader, userID, role string) (*entity.ContractDocumentVersion, error) {
+	if uc.storage == nil {
+		return nil, errors.New("storage service is not available")
+	}
+	if err := uc.authorize(ctx, contratoID, userID, role); err != nil {
+		return nil, err
+	}
+
+	version, err := newVersion(upload, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	doc, err := uc.repo.FindByIDForUpdateWithTx(ctx, tx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.ContratoID != contratoID {
+		return nil, errors.New("document not found")
+	}
+	if !doc.IsActive {
+		return nil, errors.New("document is archived")
+	}
+
+	version.DocumentID = doc.ID
+	version.Version = doc.CurrentVersion + 1
+	version.ObjectKey = objectKey(doc, version.Version, upload.FileName)
+
+	if err := uc.repo.CreateVersionWithTx(ctx, tx, version); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.CloseOpenVersionsWithTx(ctx, tx, doc.ID, version.Version, version.EffectiveFrom.AddDate(0, 0, -1)); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.UpdateCurrentVersionWithTx(ctx, tx, doc.ID, version.Version); err != nil {
+		return nil, err
+	}
+
+	if err := uc.store(ctx, version, file); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		uc.discard(ctx, version)
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// OpenVersion returns a version and its file contents. A zero version selects
+// the version in effect on the given date (today when nil).
+func (uc *contractDocumentUseCase) OpenVersion(ctx context.Context, contratoID, documentID string, version int, on *time.Time, userID, role string) (*entity.ContractDocumentVersion, io.ReadCloser, error) {
+	if uc.storage == nil {
+		return nil, nil, errors.New("storage service is not available")
+	}
+	if err := uc.authorize(ctx, contratoID, userID, role); err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := uc.findDocument(ctx, contratoID, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var selected *entity.ContractDocumentVersion
+	if version > 0 {
+		selected, err = uc.repo.FindVersion(ctx, doc.ID, version)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		versions, err := uc.repo.FindVersions(ctx, doc.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		date := time.Now()
+		if on != nil {
+			date = *on
+		}
+		selected = entity.EffectiveContractDocumentVersion(versions, date)
+	}
+	if selected == nil {
+		return nil, nil, errors.New("document version not found")
+	}
+
+	reader, _, err := uc.storage.GetFile(ctx, uc.bucket, selected.ObjectKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return selected, reader, nil
+}
+
+// ArchiveDocument hides a document from listings while keeping its history
+func (uc *contractDocumentUseCase) ArchiveDocument(ctx context.Context, contratoID, documentID, userID, role string) error {
+	if err := uc.authorize(ctx, contratoID, userID, role); err != nil {
+		return err
+	}
+
+	doc, err := uc.findDocument(ctx, contratoID, documentID)
+	if err != nil {
+		return err
+	}
+
+	return uc.repo.Deactivate(ctx, doc.ID)
+}
+
+// authorize allows admins and active team members of the contract
+func (uc *contractDocumentUseCase) authorize(ctx context.Context, contratoID, userID, role string) error {
+	if role == string(entity.RoleAdmin) {
+		return nil
+	}
+	if userID == "" {
+		return ErrAccessDenied
+	}
+
+	member, err := uc.teamRepo.FindByUserAndContract(ctx, userID, contratoID)
+	if err != nil {
+		return err
+	}
+	if !isActiveMember(member, time.Now()) {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+func (uc *contractDocumentUseCase) findDocument(ctx context.Context, contratoID, documentID string) (*entity.ContractDocument, error) {
+	doc, err := uc.repo.FindByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.ContratoID != contratoID {
+		return nil, errors.New("document not found")
+	}
+	return doc, nil
+}
+
+func (uc *contractDocumentUseCase) store(ctx context.Context, version *entity.ContractDocumentVersion, file io.Reader) error {
+	result, err := uc.storage.UploadFile(ctx, uc.bucket, version.ObjectKey, file, version.Size, version.ContentType)
+	if err != nil {
+		return err
+	}
+	version.Size = result.Size
+	return nil
+}
+
+// discard removes an uploaded object whose database record was not committed
+func (uc *contractDocumentUseCase) discard(ctx context.Context, version *entity.ContractDocumentVersion) {
+	if err := uc.storage.DeleteFile(ctx, uc.bucket, version.ObjectKey); err != nil {
+		log.Printf("Failed to remove orphaned contract document %s: %v", version.ObjectKey, err)
+	}
+}
+
+func isActiveMember(member *entity.TeamMember, now time.Time) bool {
+	return member != nil && member.IsActiveOn(now)
+}
+
+func newVersion(upload *entity.ContractDocumentUpload, userID string) (*entity.ContractDocumentVersion, error) {
+	from := time.Now()
+	if upload.EffectiveFrom != nil && *upload.EffectiveFrom != "" {
+		t, err := time.Parse("2006-01-02", *upload.EffectiveFrom)
+		if err != nil {
+			return nil, errors.New("invalid effective_from: use YYYY-MM-DD")
+		}
+		from = t
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+
+	version := &entity.ContractDocumentVersion{
+		ID:            uuid.New().String(),
+		FileName:      upload.FileName,
+		ContentType:   upload.ContentType,
+		Size:          upload.Size,
+		EffectiveFrom: from,
+		Notes:         upload.Notes,
+		CreatedAt:     time.Now(),
+	}
+	if upload.EffectiveUntil != nil && *upload.EffectiveUntil != "" {
+		until, err := time.Parse("2006-01-02", *upload.EffectiveUntil)
+		if err != nil {
+			return nil, errors.New("invalid effective_until: use YYYY-MM-DD")
+		}
+		if until.Before(from) {
+			return nil, errors.New("invalid effective_until: must not be before effective_from")
+		}
+		version.EffectiveUntil = &until
+	}
+	if userID != "" {
+		version.UploadedBy = &userID
+	}
+	return version, nil
+}
+
+// objectKey builds the storage path of a document version: <contrato>/<document>/v<n><ext>
+func objectKey(doc *entity.ContractDocument, version int, fileName string) string {
+	return fmt.Sprintf("%s/%s/v%d%s", doc.ContratoID, doc.ID, version, strings.ToLower(filepath.Ext(fileName)))
+}
@@ -0,0 +1,64 @@
+package contractdocument
+
+import (
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestIsActiveMember(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, -1, 0)
+	future := now.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name   string
+		member *entity.TeamMember
+		want   bool
+	}{
+		{"nil", nil, false},
+		{"inactive", &entity.TeamMember{IsActive: false}, false},
+		{"active open", &entity.TeamMember{IsActive: true}, true},
+		{"not started", &entity.TeamMember{IsActive: true, StartDate: &future}, false},
+		{"ended", &entity.TeamMember{IsActive: true, EndDate: &past}, false},
+		{"within period", &entity.TeamMember{IsActive: true, StartDate: &past, EndDate: &future}, true},
+	}
+
+	for _, tt := range tests {
+		if got := isActiveMember(tt.member, now); got != tt.want {
+			t.Errorf("%s: isActiveMember = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewVersion_EffectiveDates(t *testing.T) {
+	from := "2024-03-01"
+	until := "2024-02-01"
+	if _, err := newVersion(&entity.ContractDocumentUpload{EffectiveFrom: &from, EffectiveUntil: &until}, ""); err == nil {
+		t.Error("expected error when effective_until is before effective_from")
+	}
+
+	bad := "01/03/2024"
+	if _, err := newVersion(&entity.ContractDocumentUpload{EffectiveFrom: &bad}, ""); err == nil {
+		t.Error("expected error for malformed effective_from")
+	}
+
+	v, err := newVersion(&entity.ContractDocumentUpload{FileName: "contrato.pdf", EffectiveFrom: &from}, "user-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.EffectiveFrom.Format("2006-01-02") != from || v.EffectiveUntil != nil {
+		t.Errorf("unexpected effective range: %v - %v", v.EffectiveFrom, v.EffectiveUntil)
+	}
+	if v.UploadedBy == nil || *v.UploadedBy != "user-1" {
+		t.Error("expected uploaded_by to be set")
+	}
+}
+
+func TestObjectKey(t *testing.T) {
+	doc := &entity.ContractDocument{ID: "doc-1", ContratoID: "ct-1"}
+	if got := objectKey(doc, 3, "Tabela de Preços.XLSX"); got != "ct-1/doc-1/v3.xlsx" {
+		t.Errorf("objectKey = %q", got)
+	}
+}
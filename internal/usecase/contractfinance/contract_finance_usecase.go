@@ -0,0 +1,467 @@
+package contractfinance
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// maxReportMonths bounds the range of a financial report
+const maxReportMonths = 36
+
+// UseCase defines the contract budget and cost use case interface
+type UseCase interface {
+	ListBudgetLines(ctx context.Context, contratoID string) ([]entity.ContractBudgetLine, error)
+	CreateBudgetLine(ctx context.Context, contratoID string, req *entity.CreateContractBudgetLineRequest) (*entity.ContractBudgetLine, error)
+	UpdateBudgetLine(ctx context.Context, contratoID, id string, req *entity.UpdateContractBudgetLineRequest) (*entity.ContractBudgetLine, error)
+	DeleteBudgetLine(ctx context.Context, contratoID, id string) error
+	ListCosts(ctx context.Context, contratoID string, filters entity.ContractCostFilters) ([]entity.ContractCost, error)
+	RecordCost(ctx context.Context, contratoID string, req *entity.CreateContractCostRequest, createdBy string) (*entity.ContractCost, error)
+	DeleteCost(ctx context.Context, contratoID, id string) error
+	GetFinancialReport(ctx context.Context, contratoID, from, to string) (*entity.ContractFinancialReport, error)
+}
+
+type contractFinanceUseCase struct {
+	repo         repository.ContractFinancialRepository
+	contratoRepo repository.ContratoRepository
+	taskRepo     repository.TaskRepository
+}
+
+// NewUseCase creates a new contract finance use case
+func NewUseCase(
+	repo repository.ContractFinancialRepository,
+	contratoRepo repository.ContratoRepository,
+	taskRepo repository.TaskRepository,
+) UseCase {
+	return &contractFinanceUseCase{
+		repo:         repo,
+		contratoRepo: contratoRepo,
+		taskRepo:     taskRepo,
+	}
+}
+
+// ListBudgetLines returns the budget lines of a contract
+func (uc *contractFinanceUseCase) ListBudgetLines(ctx context.Context, contratoID string) ([]entity.ContractBudgetLine, error) {
+	if _, err := uc.findContrato(ctx, contratoID); err != nil {
+		return nil, err
+	}
+	return uc.repo.FindBudgetLines(ctx, contratoID)
+}
+
+// CreateBudgetLine adds a planned monthly revenue or cost to a contract
+func (uc *contractFinanceUseCase) CreateBudgetLine(ctx context.Context, contratoID string, req *entity.CreateContractBudgetLineRequest) (*entity.ContractBudgetLine, error) {
+	if _, err := uc.findContrato(ctx, contratoID); err != nil {
+		return nil, err
+	}
+
+	line := &entity.ContractBudgetLine{
+		ID:            uuid.New().String(),
+		ContratoID:    contratoID,
+		Kind:          req.Kind,
+		Category:      strings.TrimSpace(req.Category),
+		MonthlyAmount: roundCents(req.MonthlyAmount),
+		StartPeriod:   req.StartPeriod,
+		EndPeriod:     req.EndPeriod,
+		Notes:         req.Notes,
+		CreatedAt:     time.Now(),
+	}
+	if err := validateRange(line); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.CreateBudgetLine(ctx, line); err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// UpdateBudgetLine updates a budget line of a contract
+func (uc *contractFinanceUseCase) UpdateBudgetLine(ctx context.Context, contratoID, id string, req *entity.UpdateContractBudgetLineRequest) (*entity.ContractBudgetLine, error) {
+	line, err := uc.findBudgetLine(ctx, contratoID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Category != nil {
+		line.Category = strings.TrimSpace(*req.Category)
+	}
+	if req.MonthlyAmount != nil {
+		line.MonthlyAmount = roundCents(*req.MonthlyAmount)
+	}
+	if req.StartPeriod != nil {
+		line.StartPeriod = *req.StartPeriod
+	}
+	if req.EndPeriod != nil {
+		if *req.EndPeriod == "" {
+			line.EndPeriod = nil
+		} else {
+			line.EndPeriod = req.EndPeriod
+		}
+	}
+	if req.Notes != nil {
+		line.Notes = req.Notes
+	}
+	if err := validateRange(line); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.UpdateBudgetLine(ctx, line); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	line.UpdatedAt = &now
+	return line, nil
+}
+
+// DeleteBudgetLine removes a budget line of a contract
+func (uc *contractFinanceUseCase) DeleteBudgetLine(ctx context.Context, contratoID, id string) error {
+	if _, err := uc.findBudgetLine(ctx, contratoID, id); err != nil {
+		return err
+	}
+	return uc.repo.DeleteBudgetLine(ctx, id)
+}
+
+// ListCosts returns the recorded costs of a contract
+func (uc *contractFinanceUseCase) ListCosts(ctx context.Context, contratoID string, filters entity.ContractCostFilters) ([]entity.ContractCost, error) {
+	if _, err := uc.findContrato(ctx, contratoID); err != nil {
+		return nil, err
+	}
+	return uc.repo.FindCosts(ctx, contratoID, filters)
+}
+
+// RecordCost records an actual cost. Task time is valued as hours × hourly rate
+// and must reference a task of the same contract.
+func (uc *contractFinanceUseCase) RecordCost(ctx context.Context, contratoID string, req *entity.CreateContractCostRequest, createdBy string) (*entity.ContractCost, error) {
+	if _, err := uc.findContrato(ctx, contratoID); err != nil {
+		return nil, err
+	}
+	if !entity.IsValidEvaluationPeriod(req.Period) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+
+	cost := &entity.ContractCost{
+		ID:          uuid.New().String(),
+		ContratoID:  contratoID,
+		Source:      entity.ContractCostSourceManual,
+		Category:    strings.TrimSpace(req.Category),
+		Period:      req.Period,
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+	}
+	if createdBy != "" {
+		cost.CreatedBy = &createdBy
+	}
+
+	if req.TaskID != nil && *req.TaskID != "" {
+		if req.Hours == nil || req.HourlyRate == nil {
+			return nil, errors.New("invalid task time: hours and hourly_rate are required")
+		}
+		task, err := uc.taskRepo.FindByID(ctx, *req.TaskID)
+		if err != nil {
+			return nil, err
+		}
+		if task == nil {
+			return nil, errors.New("task not found")
+		}
+		if task.ContractID == nil || *task.ContractID != contratoID {
+			return nil, errors.New("invalid task: task does not belong to this contract")
+		}
+
+		cost.Source = entity.ContractCostSourceTaskTime
+		cost.TaskID = &task.ID
+		cost.Hours = req.Hours
+		cost.HourlyRate = req.HourlyRate
+		cost.Amount = roundCents(*req.Hours * *req.HourlyRate)
+		if cost.Category == "" {
+			cost.Category = "mao_de_obra"
+		}
+		if cost.Description == nil {
+			cost.Description = &task.Title
+		}
+	} else {
+		if req.Amount == nil {
+			return nil, errors.New("invalid cost: amount is required")
+		}
+		if cost.Category == "" {
+			return nil, errors.New("invalid cost: category is required")
+		}
+		cost.Amount = roundCents(*req.Amount)
+	}
+
+	if err := uc.repo.CreateCost(ctx, cost); err != nil {
+		return nil, err
+	}
+	return cost, nil
+}
+
+// DeleteCost removes a recorded cost of a contract
+func (uc *contractFinanceUseCase) DeleteCost(ctx context.Context, contratoID, id string) error {
+	cost, err := uc.repo.FindCostByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if cost == nil || cost.ContratoID != contratoID {
+		return errors.New("cost not found")
+	}
+	return uc.repo.DeleteCost(ctx, id)
+}
+
+// GetFinancialReport returns the monthly budget vs actual report of a contract.
+// The range defaults to the last 12 months, including the current one.
+func (uc *contractFinanceUseCase) GetFinancialReport(ctx context.Context, contratoID, from, to string) (*entity.ContractFinancialReport, error) {
+	contrato, err := uc.findContrato(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if to == "" {
+		to = now.Format("2006-01")
+	}
+	if from == "" {
+		if t, err := time.Parse("2006-01", to); err == nil {
+			from = t.AddDate(0, -11, 0).Format("2006-01")
+		}
+	}
+	if !entity.IsValidEvaluationPeriod(from) || !entity.IsValidEvaluationPeriod(to) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+	periods := entity.PeriodsBetween(from, to)
+	if len(periods) == 0 {
+		return nil, errors.New("invalid period: from must not be after to")
+	}
+	if len(periods) > maxReportMonths {
+		return nil, errors.New("invalid period: range is limited to 36 months")
+	}
+
+	lines, err := uc.repo.FindBudgetLines(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	costs, err := uc.repo.SumCosts(ctx, contratoID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	spend, err := uc.repo.SumSupplierSpend(ctx, contratoID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	charges, err := uc.repo.SumCharges(ctx, contratoID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildFinancialReport(periods, lines, append(costs, spend...))
+	applyCharges(report, charges)
+	report.ContratoID = contrato.ID
+	report.ContratoNome = contrato.Nome
+	report.From = from
+	report.To = to
+	return report, nil
+}
+
+func (uc *contractFinanceUseCase) findContrato(ctx context.Context, contratoID string) (*entity.Contrato, error) {
+	contrato, err := uc.contratoRepo.FindByID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+	return contrato, nil
+}
+
+func (uc *contractFinanceUseCase) findBudgetLine(ctx context.Context, contratoID, id string) (*entity.ContractBudgetLine, error) {
+	line, err := uc.repo.FindBudgetLineByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if line == nil || line.ContratoID != contratoID {
+		return nil, errors.New("budget line not found")
+	}
+	return line, nil
+}
+
+func validateRange(line *entity.ContractBudgetLine) error {
+	if line.Category == "" {
+		return errors.New("invalid budget line: category is required")
+	}
+	if !entity.IsValidEvaluationPeriod(line.StartPeriod) {
+		return errors.New("invalid start_period: use YYYY-MM")
+	}
+	if line.EndPeriod != nil {
+		if !entity.IsValidEvaluationPeriod(*line.EndPeriod) {
+			return errors.New("invalid end_period: use YYYY-MM")
+		}
+		if *line.EndPeriod < line.StartPeriod {
+			return errors.New("invalid end_period: must not be before start_period")
+		}
+	}
+	return nil
+}
+
+// buildFinancialReport compares, for each period, the budgeted revenue and cost
+// lines with the actual costs (recorded costs and supplier spend). Cost variance
+// is budget minus actual, so a negative value means the contract went over budget.
+func buildFinancialReport(periods []string, lines []entity.ContractBudgetLine, actuals []entity.ContractCostAggregate) *entity.ContractFinancialReport {
+	type key struct{ period, category string }
+	actualByCategory := make(map[key]float64)
+	actualBySource := make(map[string]map[string]float64)
+	for _, a := range actuals {
+		actualByCategory[key{a.Period, a.Category}] += a.Total
+		if actualBySource[a.Period] == nil {
+			actualBySource[a.Period] = make(map[string]float64)
+		}
+		actualBySource[a.Period][a.Source] += a.Total
+	}
+
+	report := &entity.ContractFinancialReport{
+		Months: make([]entity.ContractFinancialMonth, 0, len(periods)),
+	}
+	totals := &report.Totals
+	totals.Categories = []entity.ContractFinancialCategory{}
+	totalCategories := make(map[string]*entity.ContractFinancialCategory)
+
+	for _, period := range periods {
+		month := entity.ContractFinancialMonth{Period: period}
+		budgetByCategory := make(map[string]float64)
+
+		for i := range lines {
+			line := &lines[i]
+			if !line.AppliesTo(period) {
+				continue
+			}
+			if line.Kind == entity.BudgetKindRevenue {
+				month.Revenue += line.MonthlyAmount
+			} else {
+				month.BudgetCost += line.MonthlyAmount
+				budgetByCategory[line.Category] += line.MonthlyAmount
+			}
+		}
+
+		sources := actualBySource[period]
+		month.SupplierCost = roundCents(sources[entity.ContractCostSourceSupplier])
+		month.TaskTimeCost = roundCents(sources[entity.ContractCostSourceTaskTime])
+		month.OtherCost = roundCents(sources[entity.ContractCostSourceManual])
+		month.ActualCost = roundCents(month.SupplierCost + month.TaskTimeCost + month.OtherCost)
+
+		categories := make(map[string]bool)
+		for c := range budgetByCategory {
+			categories[c] = true
+		}
+		for k := range actualByCategory {
+			if k.period == period {
+				categories[k.category] = true
+			}
+		}
+		names := make([]string, 0, len(categories))
+		for c := range categories {
+			names = append(names, c)
+		}
+		sort.Strings(names)
+
+		month.Categories = make([]entity.ContractFinancialCategory, 0, len(names))
+		for _, c := range names {
+			row := entity.ContractFinancialCategory{
+				Category: c,
+				Budget:   roundCents(budgetByCategory[c]),
+				Actual:   roundCents(actualByCategory[key{period, c}]),
+			}
+			row.Variance = roundCents(row.Budget - row.Actual)
+			month.Categories = append(month.Categories, row)
+
+			total, ok := totalCategories[c]
+			if !ok {
+				total = &entity.ContractFinancialCategory{Category: c}
+				totalCategories[c] = total
+			}
+			total.Budget += row.Budget
+			total.Actual += row.Actual
+		}
+
+		finalizeMonth(&month)
+		report.Months = append(report.Months, month)
+
+		totals.Revenue += month.Revenue
+		totals.BudgetCost += month.BudgetCost
+		totals.SupplierCost += month.SupplierCost
+		totals.TaskTimeCost += month.TaskTimeCost
+		totals.OtherCost += month.OtherCost
+		totals.ActualCost += month.ActualCost
+	}
+
+	names := make([]string, 0, len(totalCategories))
+	for c := range totalCategories {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+	for _, c := range names {
+		t := totalCategories[c]
+		t.Budget = roundCents(t.Budget)
+		t.Actual = roundCents(t.Actual)
+		t.Variance = roundCents(t.Budget - t.Actual)
+		totals.Categories = append(totals.Categories, *t)
+	}
+
+	totals.SupplierCost = roundCents(totals.SupplierCost)
+	totals.TaskTimeCost = roundCents(totals.TaskTimeCost)
+	totals.OtherCost = roundCents(totals.OtherCost)
+	totals.ActualCost = roundCents(totals.ActualCost)
+	finalizeMonth(totals)
+
+	return report
+}
+
+// applyCharges adds what the contract billed and received through its monthly fee
+func applyCharges(report *entity.ContractFinancialReport, charges []entity.ContractChargeAggregate) {
+	byPeriod := make(map[string]entity.ContractChargeAggregate, len(charges))
+	for _, c := range charges {
+		byPeriod[c.Period] = c
+	}
+
+	totals := &report.Totals
+	totals.Billed, totals.Received = 0, 0
+	for i := range report.Months {
+		m := &report.Months[i]
+		c := byPeriod[m.Period]
+		m.Billed = roundCents(c.Billed)
+		m.Received = roundCents(c.Received)
+		m.Outstanding = roundCents(m.Billed - m.Received)
+		totals.Billed += m.Billed
+		totals.Received += m.Received
+	}
+	totals.Billed = roundCents(totals.Billed)
+	totals.Received = roundCents(totals.Received)
+	totals.Outstanding = roundCents(totals.Billed - totals.Received)
+}
+
+// finalizeMonth fills in variance and margin figures from the month totals
+func finalizeMonth(m *entity.ContractFinancialMonth) {
+	m.Revenue = roundCents(m.Revenue)
+	m.BudgetCost = roundCents(m.BudgetCost)
+	m.CostVariance = roundCents(m.BudgetCost - m.ActualCost)
+	m.BudgetMargin = roundCents(m.Revenue - m.BudgetCost)
+	m.ActualMargin = roundCents(m.Revenue - m.ActualCost)
+
+	if m.BudgetCost > 0 {
+		pct := roundCents(m.CostVariance / m.BudgetCost * 100)
+		m.CostVariancePct = &pct
+	}
+	if m.Revenue > 0 {
+		pct := roundCents(m.ActualMargin / m.Revenue * 100)
+		m.MarginPct = &pct
+	}
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
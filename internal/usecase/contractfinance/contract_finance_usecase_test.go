@@ -0,0 +1,93 @@
+package contractfinance
+
+import (
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestBuildFinancialReport(t *testing.T) {
+	end := "2024-02"
+	lines := []entity.ContractBudgetLine{
+		{Kind: entity.BudgetKindRevenue, Category: "taxa_administracao", MonthlyAmount: 10000, StartPeriod: "2024-01"},
+		{Kind: entity.BudgetKindCost, Category: "limpeza", MonthlyAmount: 3000, StartPeriod: "2024-01"},
+		{Kind: entity.BudgetKindCost, Category: "mao_de_obra", MonthlyAmount: 2000, StartPeriod: "2024-01", EndPeriod: &end},
+	}
+	actuals := []entity.ContractCostAggregate{
+		{Period: "2024-01", Source: entity.ContractCostSourceSupplier, Category: "limpeza", Total: 3200},
+		{Period: "2024-01", Source: entity.ContractCostSourceTaskTime, Category: "mao_de_obra", Total: 1500},
+		{Period: "2024-03", Source: entity.ContractCostSourceManual, Category: "manutencao", Total: 800.5},
+	}
+
+	report := buildFinancialReport([]string{"2024-01", "2024-02", "2024-03"}, lines, actuals)
+	if len(report.Months) != 3 {
+		t.Fatalf("expected 3 months, got %d", len(report.Months))
+	}
+
+	jan := report.Months[0]
+	if jan.Revenue != 10000 || jan.BudgetCost != 5000 || jan.ActualCost != 4700 {
+		t.Errorf("unexpected january totals: %+v", jan)
+	}
+	if jan.SupplierCost != 3200 || jan.TaskTimeCost != 1500 || jan.OtherCost != 0 {
+		t.Errorf("unexpected january cost split: %+v", jan)
+	}
+	if jan.CostVariance != 300 || jan.ActualMargin != 5300 || jan.BudgetMargin != 5000 {
+		t.Errorf("unexpected january variance/margin: %+v", jan)
+	}
+	if jan.MarginPct == nil || *jan.MarginPct != 53 {
+		t.Errorf("expected 53%% margin, got %v", jan.MarginPct)
+	}
+	if len(jan.Categories) != 2 || jan.Categories[0].Category != "limpeza" || jan.Categories[0].Variance != -200 {
+		t.Errorf("unexpected january categories: %+v", jan.Categories)
+	}
+
+	mar := report.Months[2]
+	if mar.BudgetCost != 3000 {
+		t.Errorf("expected mao_de_obra line to end in february, got budget %.2f", mar.BudgetCost)
+	}
+	if mar.OtherCost != 800.5 || len(mar.Categories) != 2 {
+		t.Errorf("expected unbudgeted category to be reported: %+v", mar)
+	}
+
+	totals := report.Totals
+	if totals.Revenue != 30000 || totals.BudgetCost != 13000 || totals.ActualCost != 5500.5 {
+		t.Errorf("unexpected totals: %+v", totals)
+	}
+	if totals.CostVariance != 7499.5 {
+		t.Errorf("expected total variance 7499.50, got %.2f", totals.CostVariance)
+	}
+	if len(totals.Categories) != 3 {
+		t.Errorf("expected 3 categories in totals, got %d", len(totals.Categories))
+	}
+}
+
+func TestValidateRange(t *testing.T) {
+	before := "2023-12"
+	line := &entity.ContractBudgetLine{Category: "limpeza", StartPeriod: "2024-01", EndPeriod: &before}
+	if err := validateRange(line); err == nil {
+		t.Error("expected error when end_period is before start_period")
+	}
+
+	line.EndPeriod = nil
+	line.StartPeriod = "2024-13"
+	if err := validateRange(line); err == nil {
+		t.Error("expected error for invalid start_period")
+	}
+}
+
+func TestApplyCharges(t *testing.T) {
+	report := buildFinancialReport([]string{"2024-01", "2024-02"}, nil, nil)
+	applyCharges(report, []entity.ContractChargeAggregate{
+		{Period: "2024-02", Billed: 5000, Received: 3000.1},
+	})
+
+	if jan := report.Months[0]; jan.Billed != 0 || jan.Outstanding != 0 {
+		t.Errorf("expected nothing billed in January, got %+v", jan)
+	}
+	if feb := report.Months[1]; feb.Billed != 5000 || feb.Received != 3000.1 || feb.Outstanding != 1999.9 {
+		t.Errorf("unexpected February figures: %+v", feb)
+	}
+	if report.Totals.Billed != 5000 || report.Totals.Outstanding != 1999.9 {
+		t.Errorf("unexpected totals: %+v", report.Totals)
+	}
+}
@@ -0,0 +1,135 @@
+package contractkpi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/google/uuid"
+)
+
+// defaultHistoryDays is the range of the history when no from date is given
+const defaultHistoryDays = 90
+
+// UseCase defines the contract KPI snapshot use case interface
+type UseCase interface {
+	RunSnapshot(ctx context.Context, now time.Time) (*entity.ContractKPISnapshotResult, error)
+	GetHistory(ctx context.Context, contratoID, from, to string) (*entity.ContractKPIHistory, error)
+	StartSnapshotScheduler(lc *lifecycle.Manager, interval time.Duration)
+}
+
+type contractKPIUseCase struct {
+	repo         repository.ContractKPIRepository
+	contratoRepo repository.ContratoRepository
+}
+
+// NewUseCase creates a new contract KPI snapshot use case
+func NewUseCase(repo repository.ContractKPIRepository, contratoRepo repository.ContratoRepository) UseCase {
+	return &contractKPIUseCase{
+		repo:         repo,
+		contratoRepo: contratoRepo,
+	}
+}
+
+// RunSnapshot measures the KPIs of every active contract and stores them as the snapshot of
+// the day. Running it again the same day refreshes that day's snapshot only.
+func (uc *contractKPIUseCase) RunSnapshot(ctx context.Context, now time.Time) (*entity.ContractKPISnapshotResult, error) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	snapshots, err := uc.repo.Measure(ctx, day.AddDate(0, 0, 1-entity.ContractKPIWindowDays), now)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range snapshots {
+		snapshots[i].ID = uuid.New().String()
+		snapshots[i].SnapshotDate = day
+		snapshots[i].InspectionComplianceRate = percentage(snapshots[i].InspectionsCompleted, snapshots[i].InspectionsDue)
+	}
+	if err := uc.repo.Save(ctx, snapshots); err != nil {
+		return nil, err
+	}
+
+	return &entity.ContractKPISnapshotResult{
+		SnapshotDate: day.Format("2006-01-02"),
+		Contracts:    len(snapshots),
+	}, nil
+}
+
+// GetHistory returns the snapshots of a contract between from and to (YYYY-MM-DD, inclusive).
+// The range defaults to the last 90 days.
+func (uc *contractKPIUseCase) GetHistory(ctx context.Context, contratoID, from, to string) (*entity.ContractKPIHistory, error) {
+	now := time.Now()
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if to != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			return nil, errors.New("invalid to date, expected YYYY-MM-DD")
+		}
+		end = parsed
+	}
+
+	start := end.AddDate(0, 0, 1-defaultHistoryDays)
+	if from != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			return nil, errors.New("invalid from date, expected YYYY-MM-DD")
+		}
+		start = parsed
+	}
+	if start.After(end) {
+		return nil, errors.New("invalid date range: from is after to")
+	}
+
+	contrato, err := uc.contratoRepo.FindByID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contrato not found")
+	}
+
+	snapshots, err := uc.repo.FindByContratoID(ctx, contratoID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if snapshots == nil {
+		snapshots = []entity.ContractKPISnapshot{}
+	}
+
+	return &entity.ContractKPIHistory{
+		ContratoID: contratoID,
+		From:       start.Format("2006-01-02"),
+		To:         end.Format("2006-01-02"),
+		Snapshots:  snapshots,
+	}, nil
+}
+
+// StartSnapshotScheduler takes the snapshot once at startup and then at every interval, as a
+// lifecycle worker
+func (uc *contractKPIUseCase) StartSnapshotScheduler(lc *lifecycle.Manager, interval time.Duration) {
+	lc.Every("contract KPI snapshots", interval, true, func(ctx context.Context) {
+		result, err := uc.RunSnapshot(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Contract KPI snapshot failed: %v", err)
+			}
+			return
+		}
+		log.Printf("Contract KPI snapshot %s taken for %d contracts", result.SnapshotDate, result.Contracts)
+	})
+}
+
+// percentage returns part/total as a percentage, or nil when there is nothing to measure
+func percentage(part, total int) *float64 {
+	if total == 0 {
+		return nil
+	}
+	pct := math.Round(float64(part)/float64(total)*10000) / 100
+	return &pct
+}
@@ -0,0 +1,98 @@
+package contractkpi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+type fakeKPIRepo struct {
+	measured   []entity.ContractKPISnapshot
+	from, now  time.Time
+	saved      []entity.ContractKPISnapshot
+	historyArg [2]time.Time
+}
+
+func (f *fakeKPIRepo) Measure(ctx context.Context, from, now time.Time) ([]entity.ContractKPISnapshot, error) {
+	f.from, f.now = from, now
+	return f.measured, nil
+}
+
+func (f *fakeKPIRepo) Save(ctx context.Context, snapshots []entity.ContractKPISnapshot) error {
+	f.saved = snapshots
+	return nil
+}
+
+func (f *fakeKPIRepo) FindByContratoID(ctx context.Context, contratoID string, from, to time.Time) ([]entity.ContractKPISnapshot, error) {
+	f.historyArg = [2]time.Time{from, to}
+	return nil, nil
+}
+
+func TestRunSnapshot(t *testing.T) {
+	repo := &fakeKPIRepo{measured: []entity.ContractKPISnapshot{
+		{ContratoID: "ct-1", AuditCount: 2, OpenTasks: 3, InspectionsDue: 3, InspectionsCompleted: 2},
+		{ContratoID: "ct-2"},
+	}}
+	uc := NewUseCase(repo, testutil.NewMockContratoRepository())
+
+	now := time.Date(2024, 5, 31, 15, 30, 0, 0, time.Local)
+	result, err := uc.RunSnapshot(context.Background(), now)
+	if err != nil {
+		t.Fatalf("RunSnapshot: unexpected error %v", err)
+	}
+	if result.SnapshotDate != "2024-05-31" || result.Contracts != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	if want := time.Date(2024, 5, 2, 0, 0, 0, 0, time.Local); !repo.from.Equal(want) || !repo.now.Equal(now) {
+		t.Errorf("measured from %v to %v, want a 30 day window ending now", repo.from, repo.now)
+	}
+
+	if len(repo.saved) != 2 {
+		t.Fatalf("saved %d snapshots, want 2", len(repo.saved))
+	}
+	first := repo.saved[0]
+	if first.ID == "" || first.SnapshotDate.Format("2006-01-02") != "2024-05-31" || first.SnapshotDate.Hour() != 0 {
+		t.Errorf("snapshot not stamped with the day: %+v", first)
+	}
+	if first.InspectionComplianceRate == nil || *first.InspectionComplianceRate != 66.67 {
+		t.Errorf("compliance rate = %v, want 66.67", first.InspectionComplianceRate)
+	}
+	if repo.saved[1].InspectionComplianceRate != nil {
+		t.Error("contract without due inspections should have no compliance rate")
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	repo := &fakeKPIRepo{}
+	uc := NewUseCase(repo, testutil.NewMockContratoRepository(&entity.Contrato{ID: "ct-1"}))
+	ctx := context.Background()
+
+	history, err := uc.GetHistory(ctx, "ct-1", "2024-01-01", "2024-03-31")
+	if err != nil {
+		t.Fatalf("GetHistory: unexpected error %v", err)
+	}
+	if history.From != "2024-01-01" || history.To != "2024-03-31" || history.Snapshots == nil {
+		t.Errorf("history = %+v", history)
+	}
+	if repo.historyArg[0].Format("2006-01-02") != "2024-01-01" || repo.historyArg[1].Format("2006-01-02") != "2024-03-31" {
+		t.Errorf("queried %v", repo.historyArg)
+	}
+
+	history, _ = uc.GetHistory(ctx, "ct-1", "", "2024-03-31")
+	if history.From != "2024-01-02" {
+		t.Errorf("default from = %s, want 90 days back", history.From)
+	}
+
+	if _, err := uc.GetHistory(ctx, "ct-1", "2024-04-01", "2024-03-31"); err == nil {
+		t.Error("from after to: expected error")
+	}
+	if _, err := uc.GetHistory(ctx, "ct-1", "01/04/2024", ""); err == nil {
+		t.Error("invalid from: expected error")
+	}
+	if _, err := uc.GetHistory(ctx, "missing", "", ""); err == nil || err.Error() != "contrato not found" {
+		t.Errorf("unknown contrato: err = %v", err)
+	}
+}
@@ -0,0 +1,413 @@
+package contractrenewal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// UseCase defines the contract renewal use case interface
+type UseCase interface {
+	ListRenewals(ctx context.Context, filters entity.ContractRenewalFilters) ([]entity.ContractRenewal, error)
+	ListUpcoming(ctx context.Context, days int) ([]entity.UpcomingContractRenewal, error)
+	ListContractRenewals(ctx context.Context, contratoID string) ([]entity.ContractRenewal, error)
+	CreateRenewal(ctx context.Context, contratoID string, req *entity.CreateContractRenewalRequest, userID string) (*entity.ContractRenewal, error)
+	UpdateRenewal(ctx context.Context, contratoID, id string, req *entity.UpdateContractRenewalRequest, userID string) (*entity.ContractRenewal, error)
+	RunAlerts(ctx context.Context, now time.Time) (*entity.ContractRenewalAlertResult, error)
+	StartAlertScheduler(lc *lifecycle.Manager, interval time.Duration)
+}
+
+type contractRenewalUseCase struct {
+	repo         repository.ContractRenewalRepository
+	contratoRepo repository.ContratoRepository
+	teamRepo     repository.TeamRepository
+	userRepo     repository.UserRepository
+	notifier     notification.UseCase
+	db           *database.MySQL
+	thresholds   []int
+}
+
+// NewUseCase creates a new contract renewal use case
+func NewUseCase(
+	repo repository.ContractRenewalRepository,
+	contratoRepo repository.ContratoRepository,
+	teamRepo repository.TeamRepository,
+	userRepo repository.UserRepository,
+	notifier notification.UseCase,
+	db *database.MySQL,
+	cfg *config.Config,
+) UseCase {
+	return &contractRenewalUseCase{
+		repo:         repo,
+		contratoRepo: contratoRepo,
+		teamRepo:     teamRepo,
+		userRepo:     userRepo,
+		notifier:     notifier,
+		db:           db,
+		thresholds:   cfg.ContractRenewalAlertDays,
+	}
+}
+
+// ListRenewals returns renewals across all contracts
+func (uc *contractRenewalUseCase) ListRenewals(ctx context.Context, filters entity.ContractRenewalFilters) ([]entity.ContractRenewal, error) {
+	return uc.repo.FindAll(ctx, filters)
+}
+
+// ListUpcoming returns active contracts ending within the next days
+func (uc *contractRenewalUseCase) ListUpcoming(ctx context.Context, days int) ([]entity.UpcomingContractRenewal, error) {
+	if days <= 0 {
+		days = uc.maxThreshold()
+	}
+
+	now := time.Now()
+	upcoming, err := uc.repo.FindUpcoming(ctx, now, now.AddDate(0, 0, days))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range upcoming {
+		upcoming[i].DaysLeft = entity.DaysUntil(upcoming[i].DataFim, now)
+	}
+	return upcoming, nil
+}
+
+// ListContractRenewals returns the renewal history of a contract
+func (uc *contractRenewalUseCase) ListContractRenewals(ctx context.Context, contratoID string) ([]entity.ContractRenewal, error) {
+	contrato, err := uc.contratoRepo.FindByID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+	return uc.repo.FindByContratoID(ctx, contratoID)
+}
+
+// CreateRenewal opens a renewal for a contract; only one may be open at a time
+func (uc *contractRenewalUseCase) CreateRenewal(ctx context.Context, contratoID string, req *entity.CreateContractRenewalRequest, userID string) (*entity.ContractRenewal, error) {
+	contrato, err := uc.contratoRepo.FindByID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+
+	open, err := uc.repo.FindOpenByContratoID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, errors.New("contract already has an open renewal")
+	}
+
+	renewal := newRenewal(contrato, userID)
+	renewal.NewMonthlyValue = req.NewMonthlyValue
+	renewal.AdjustmentPercent = req.AdjustmentPercent
+	renewal.Terms = req.Terms
+	renewal.Notes = req.Notes
+	if renewal.NewStartDate, err = parseDate("new_start_date", req.NewStartDate); err != nil {
+		return nil, err
+	}
+	if renewal.NewEndDate, err = parseDate("new_end_date", req.NewEndDate); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, renewal); err != nil {
+		return nil, err
+	}
+	renewal.ContratoNome = contrato.Nome
+	return renewal, nil
+}
+
+// UpdateRenewal updates the negotiated terms and moves the renewal through its workflow.
+// A renewal marked as renewed extends the contract end date to the new end date.
+func (uc *contractRenewalUseCase) UpdateRenewal(ctx context.Context, contratoID, id string, req *entity.UpdateContractRenewalRequest, userID string) (*entity.ContractRenewal, error) {
+	renewal, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if renewal == nil || renewal.ContratoID != contratoID {
+		return nil, errors.New("renewal not found")
+	}
+	if !renewal.IsOpen() {
+		return nil, errors.New("renewal is already " + renewal.Status)
+	}
+
+	if req.NewStartDate != nil {
+		if renewal.NewStartDate, err = parseDate("new_start_date", req.NewStartDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.NewEndDate != nil {
+		if renewal.NewEndDate, err = parseDate("new_end_date", req.NewEndDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.NewMonthlyValue != nil {
+		renewal.NewMonthlyValue = req.NewMonthlyValue
+	}
+	if req.AdjustmentPercent != nil {
+		renewal.AdjustmentPercent = req.AdjustmentPercent
+	}
+	if req.Terms != nil {
+		renewal.Terms = req.Terms
+	}
+	if req.Notes != nil {
+		renewal.Notes = req.Notes
+	}
+
+	if req.Status != nil && *req.Status != renewal.Status {
+		if !entity.CanTransitionContractRenewal(renewal.Status, *req.Status) {
+			return nil, errors.New("invalid status transition from " + renewal.Status + " to " + *req.Status)
+		}
+		renewal.Status = *req.Status
+		if !renewal.IsOpen() {
+			now := time.Now()
+			renewal.DecidedAt = &now
+			if userID != "" {
+				renewal.DecidedBy = &userID
+			}
+		}
+	}
+
+	// The contract is extended together with the renewal, or not at all
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if renewal.Status == entity.ContractRenewalStatusRenewed {
+		if err := uc.extendContract(ctx, tx, renewal); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.repo.UpdateWithTx(ctx, tx, renewal); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	renewal.UpdatedAt = &now
+	return renewal, nil
+}
+
+// extendContract applies the renewed end date to the contract within the transaction
+func (uc *contractRenewalUseCase) extendContract(ctx context.Context, tx *sqlx.Tx, renewal *entity.ContractRenewal) error {
+	if renewal.NewEndDate == nil {
+		return errors.New("invalid renewal: new_end_date is required to mark as renewed")
+	}
+
+	contrato, err := uc.contratoRepo.FindByID(ctx, renewal.ContratoID)
+	if err != nil {
+		return err
+	}
+	if contrato == nil {
+		return errors.New("contract not found")
+	}
+	if contrato.DataFim != nil && !renewal.NewEndDate.After(*contrato.DataFim) {
+		return errors.New("invalid renewal: new_end_date must be after the current end date")
+	}
+
+	contrato.DataFim = renewal.NewEndDate
+	contrato.Ativo = true
+	return uc.contratoRepo.UpdateWithTx(ctx, tx, contrato)
+}
+
+// RunAlerts notifies the team of contracts reaching an alert threshold and opens
+// a pending renewal for contracts that have none yet
+func (uc *contractRenewalUseCase) RunAlerts(ctx context.Context, now time.Time) (*entity.ContractRenewalAlertResult, error) {
+	result := &entity.ContractRenewalAlertResult{}
+	if len(uc.thresholds) == 0 {
+		return result, nil
+	}
+
+	upcoming, err := uc.repo.FindUpcoming(ctx, now, now.AddDate(0, 0, uc.maxThreshold()))
+	if err != nil {
+		return nil, err
+	}
+
+	var admins []entity.User
+	if len(upcoming) > 0 {
+		admins, err = uc.activeAdmins(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item := range upcoming {
+		result.Checked++
+
+		daysLeft := entity.DaysUntil(item.DataFim, now)
+		sent, err := uc.repo.FindSentThresholds(ctx, item.ContratoID, item.DataFim)
+		if err != nil {
+			return nil, err
+		}
+		threshold, due := entity.DueRenewalAlertThreshold(daysLeft, uc.thresholds, sent)
+		if !due {
+			continue
+		}
+
+		renewalID := item.RenewalID
+		if renewalID == nil {
+			contrato, err := uc.contratoRepo.FindByID(ctx, item.ContratoID)
+			if err != nil {
+				return nil, err
+			}
+			if contrato != nil {
+				renewal := newRenewal(contrato, "")
+				if err := uc.repo.Create(ctx, renewal); err != nil {
+					return nil, err
+				}
+				renewalID = &renewal.ID
+				result.RenewalsOpened++
+			}
+		}
+
+		recipients, err := uc.recipients(ctx, item.ContratoID, admins)
+		if err != nil {
+			return nil, err
+		}
+		for _, userID := range recipients {
+			if err := uc.notifier.Create(ctx, alertNotification(userID, &item, daysLeft, renewalID)); err != nil {
+				log.Printf("Failed to notify user %s about contract %s renewal: %v", userID, item.ContratoID, err)
+				continue
+			}
+			result.Notifications++
+		}
+
+		alert := &entity.ContractRenewalAlert{
+			ID:            uuid.New().String(),
+			ContratoID:    item.ContratoID,
+			EndDate:       item.DataFim,
+			ThresholdDays: threshold,
+			Recipients:    len(recipients),
+		}
+		if err := uc.repo.CreateAlert(ctx, alert); err != nil {
+			return nil, err
+		}
+		result.AlertsSent++
+	}
+
+	return result, nil
+}
+
+// StartAlertScheduler runs the alert sweep once at startup and then at every interval, as
+// a lifecycle worker. Shutdown cancels a sweep in progress; thresholds already recorded are
+// not alerted again by the next one.
+func (uc *contractRenewalUseCase) StartAlertScheduler(lc *lifecycle.Manager, interval time.Duration) {
+	lc.Every("contract renewal alerts", interval, true, func(ctx context.Context) {
+		result, err := uc.RunAlerts(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Contract renewal alert sweep failed: %v", err)
+			}
+		} else if result.AlertsSent > 0 {
+			log.Printf("Contract renewal alerts sent: %d (%d notifications)", result.AlertsSent, result.Notifications)
+		}
+	})
+}
+
+// recipients returns the active team members of the contract plus active admins, without duplicates
+func (uc *contractRenewalUseCase) recipients(ctx context.Context, contratoID string, admins []entity.User) ([]string, error) {
+	members, err := uc.teamRepo.FindActiveByContract(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, m := range members {
+		if !seen[m.UserID] {
+			seen[m.UserID] = true
+			ids = append(ids, m.UserID)
+		}
+	}
+	for _, a := range admins {
+		if !seen[a.ID] {
+			seen[a.ID] = true
+			ids = append(ids, a.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (uc *contractRenewalUseCase) activeAdmins(ctx context.Context) ([]entity.User, error) {
+	role := entity.RoleAdmin
+	active := true
+	return uc.userRepo.FindAllWithFilters(ctx, repository.UserFilters{Role: &role, IsActive: &active})
+}
+
+func (uc *contractRenewalUseCase) maxThreshold() int {
+	max := 0
+	for _, t := range uc.thresholds {
+		if t > max {
+			max = t
+		}
+	}
+	return max
+}
+
+func newRenewal(contrato *entity.Contrato, userID string) *entity.ContractRenewal {
+	renewal := &entity.ContractRenewal{
+		ID:             uuid.New().String(),
+		ContratoID:     contrato.ID,
+		Status:         entity.ContractRenewalStatusPending,
+		CurrentEndDate: contrato.DataFim,
+		CreatedAt:      time.Now(),
+		ContratoNome:   contrato.Nome,
+	}
+	if userID != "" {
+		renewal.CreatedBy = &userID
+	}
+	return renewal
+}
+
+func alertNotification(userID string, item *entity.UpcomingContractRenewal, daysLeft int, renewalID *string) *entity.Notificacao {
+	payload := map[string]interface{}{
+		"contrato_id": item.ContratoID,
+		"data_fim":    item.DataFim.Format("2006-01-02"),
+		"days_left":   daysLeft,
+	}
+	if renewalID != nil {
+		payload["renewal_id"] = *renewalID
+	}
+	raw, _ := json.Marshal(payload)
+	data := string(raw)
+
+	return &entity.Notificacao{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Type:      entity.NotificationTypeContract,
+		Title:     "Renovação de contrato",
+		Message:   fmt.Sprintf("O contrato %s vence em %d dias (%s).", item.ContratoNome, daysLeft, item.DataFim.Format("02/01/2006")),
+		Data:      &data,
+		CreatedAt: time.Now(),
+	}
+}
+
+func parseDate(field string, s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: use YYYY-MM-DD", field)
+	}
+	return &t, nil
+}
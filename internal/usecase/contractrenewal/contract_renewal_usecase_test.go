@@ -0,0 +1,42 @@
+package contractrenewal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestAlertNotification(t *testing.T) {
+	renewalID := "ren-1"
+	item := &entity.UpcomingContractRenewal{
+		ContratoID:   "ct-1",
+		ContratoNome: "Residencial Sol",
+		DataFim:      time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	n := alertNotification("user-1", item, 60, &renewalID)
+	if n.UserID != "user-1" || n.Type != entity.NotificationTypeContract {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+	if !strings.Contains(n.Message, "Residencial Sol") || !strings.Contains(n.Message, "60 dias") {
+		t.Errorf("unexpected message: %q", n.Message)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(*n.Data), &data); err != nil {
+		t.Fatalf("invalid data payload: %v", err)
+	}
+	if data["renewal_id"] != "ren-1" || data["data_fim"] != "2024-09-30" {
+		t.Errorf("unexpected data payload: %v", data)
+	}
+}
+
+func TestMaxThreshold(t *testing.T) {
+	uc := &contractRenewalUseCase{thresholds: []int{30, 90, 60}}
+	if got := uc.maxThreshold(); got != 90 {
+		t.Errorf("expected 90, got %d", got)
+	}
+}
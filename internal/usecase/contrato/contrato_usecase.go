@@ -19,6 +19,8 @@ type UseCase interface {
 	CreateContrato(ctx context.Context, req *entity.CreateContratoRequest) (*entity.Contrato, error)
 	UpdateContrato(ctx context.Context, id string, req *entity.UpdateContratoRequest) (*entity.Contrato, error)
 	DeleteContrato(ctx context.Context, id string) error
+	ListDeletedContratos(ctx context.Context) ([]entity.Contrato, error)
+	RestoreContrato(ctx context.Context, id string) (*entity.Contrato, error)
 }
 
 type contratoUseCase struct {
@@ -165,7 +167,7 @@ func (uc *contratoUseCase) UpdateContrato(ctx context.Context, id string, req *e
 	return contrato, nil
 }
 
-// DeleteContrato deletes a contrato by ID
+// DeleteContrato soft deletes a contrato by ID; audits and tasks keep referencing it
 func (uc *contratoUseCase) DeleteContrato(ctx context.Context, id string) error {
 	// Verify contrato exists
 	contrato, err := uc.repo.FindByID(ctx, id)
@@ -178,3 +180,21 @@ func (uc *contratoUseCase) DeleteContrato(ctx context.Context, id string) error
 
 	return uc.repo.Delete(ctx, id)
 }
+
+// ListDeletedContratos returns the contratos in the trash
+func (uc *contratoUseCase) ListDeletedContratos(ctx context.Context) ([]entity.Contrato, error) {
+	return uc.repo.FindDeleted(ctx)
+}
+
+// RestoreContrato brings a contrato back from the trash
+func (uc *contratoUseCase) RestoreContrato(ctx context.Context, id string) (*entity.Contrato, error) {
+	restored, err := uc.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, errors.New("contrato not found")
+	}
+
+	return uc.repo.FindByID(ctx, id)
+}
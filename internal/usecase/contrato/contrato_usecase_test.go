@@ -0,0 +1,43 @@
+package contrato
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func TestDeleteAndRestoreContrato(t *testing.T) {
+	ctx := context.Background()
+	repo := testutil.NewMockContratoRepository(&entity.Contrato{ID: "ct-1", GestorID: "g1", Nome: "Residencial Sol", Ativo: true})
+	uc := NewUseCase(repo, nil)
+
+	if err := uc.DeleteContrato(ctx, "ct-1"); err != nil {
+		t.Fatalf("DeleteContrato: unexpected error %v", err)
+	}
+	if c, _ := uc.GetContratoByID(ctx, "ct-1"); c != nil {
+		t.Error("deleted contrato still returned by ID")
+	}
+	if list, _ := uc.ListContratos(ctx); len(list) != 0 {
+		t.Errorf("deleted contrato still listed: %+v", list)
+	}
+	trash, _ := uc.ListDeletedContratos(ctx)
+	if len(trash) != 1 || trash[0].DeletedAt == nil {
+		t.Fatalf("trash = %+v, want the deleted contrato", trash)
+	}
+
+	restored, err := uc.RestoreContrato(ctx, "ct-1")
+	if err != nil {
+		t.Fatalf("RestoreContrato: unexpected error %v", err)
+	}
+	if restored == nil || restored.DeletedAt != nil || !restored.Ativo {
+		t.Errorf("restored = %+v, want the contrato back", restored)
+	}
+	if _, err := uc.RestoreContrato(ctx, "ct-1"); err == nil || err.Error() != "contrato not found" {
+		t.Errorf("restoring a contrato not in the trash: err = %v", err)
+	}
+	if err := uc.DeleteContrato(ctx, "missing"); err == nil {
+		t.Error("deleting an unknown contrato: expected error")
+	}
+}
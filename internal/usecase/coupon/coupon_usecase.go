@@ -8,6 +8,7 @@ import (
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/google/uuid"
 )
 
@@ -64,9 +65,11 @@ type ValidateCouponRequest struct {
 	UserID   string  `json:"user_id,omitempty"`
 }
 
-// ValidateCouponResponse represents the coupon validation result
+// ValidateCouponResponse represents the coupon validation result. Reason is the error
+// code explaining why an invalid coupon was refused.
 type ValidateCouponResponse struct {
 	Valid           bool    `json:"valid"`
+	Reason          apperror.Code `json:"reason,omitempty"`
 	CouponID        string  `json:"coupon_id,omitempty"`
 	Code            string  `json:"code"`
 	DiscountType    string  `json:"discount_type,omitempty"`
@@ -243,6 +246,18 @@ func (uc *couponUseCase) Delete(ctx context.Context, id string) error {
 	return uc.repo.Delete(ctx, id)
 }
 
+// invalidCouponMessages are the messages shown to the user for each refusal reason
+var invalidCouponMessages = map[apperror.Code]string{
+	apperror.CodeCouponNotFound:      "Cupom não encontrado",
+	apperror.CodeCouponInactive:      "Cupom inativo",
+	apperror.CodeCouponNotStarted:    "Cupom ainda não está válido",
+	apperror.CodeCouponExpired:       "Cupom expirado",
+	apperror.CodeCouponUsageLimit:    "Cupom esgotado",
+	apperror.CodeCouponUserLimit:     "Limite de uso deste cupom excedido",
+	apperror.CodeCouponMinimumAmount: "Valor do pedido abaixo do mínimo do cupom",
+	apperror.CodeCouponNotApplicable: "Cupom não aplicável a este pedido",
+}
+
 func (uc *couponUseCase) ValidateCoupon(ctx context.Context, req *ValidateCouponRequest) (*ValidateCouponResponse, error) {
 	coupon, err := uc.repo.FindByCode(ctx, strings.ToUpper(req.Code))
 	if err != nil {
@@ -250,12 +265,7 @@ func (uc *couponUseCase) ValidateCoupon(ctx context.Context, req *ValidateCoupon
 	}
 
 	if coupon == nil {
-		return &ValidateCouponResponse{
-			Valid:       false,
-			Code:        req.Code,
-			FinalAmount: req.Amount,
-			Message:     "Cupom não encontrado",
-		}, nil
+		return invalidCoupon(req, entity.ErrCouponNotFound), nil
 	}
 
 	// Check per-user usage
@@ -265,23 +275,17 @@ func (uc *couponUseCase) ValidateCoupon(ctx context.Context, req *ValidateCoupon
 			return nil, err
 		}
 		if usageCount >= *coupon.MaxUsesPerUser {
-			return &ValidateCouponResponse{
-				Valid:       false,
-				Code:        req.Code,
-				FinalAmount: req.Amount,
-				Message:     "Limite de uso deste cupom excedido",
-			}, nil
+			return invalidCoupon(req, entity.ErrCouponUserLimit), nil
 		}
 	}
 
+	if err := coupon.CheckEligibility(req.Amount, time.Now()); err != nil {
+		return invalidCoupon(req, err), nil
+	}
+
 	discount := coupon.CalculateDiscount(req.Amount)
 	if discount <= 0 {
-		return &ValidateCouponResponse{
-			Valid:       false,
-			Code:        req.Code,
-			FinalAmount: req.Amount,
-			Message:     "Cupom não aplicável a este pedido",
-		}, nil
+		return invalidCoupon(req, entity.ErrCouponNotApplicable), nil
 	}
 
 	return &ValidateCouponResponse{
@@ -295,3 +299,14 @@ func (uc *couponUseCase) ValidateCoupon(ctx context.Context, req *ValidateCoupon
 		Message:        "Cupom válido",
 	}, nil
 }
+
+func invalidCoupon(req *ValidateCouponRequest, reason error) *ValidateCouponResponse {
+	code := apperror.CodeOf(reason)
+	return &ValidateCouponResponse{
+		Valid:       false,
+		Reason:      code,
+		Code:        req.Code,
+		FinalAmount: req.Amount,
+		Message:     invalidCouponMessages[code],
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/apperror"
 )
 
 func newTestUseCase() (UseCase, *testutil.MockCouponRepository) {
@@ -210,6 +211,9 @@ func TestValidateCoupon_NotFound(t *testing.T) {
 	if result.Valid {
 		t.Error("expected coupon to be invalid")
 	}
+	if result.Reason != apperror.CodeCouponNotFound {
+		t.Errorf("expected reason %s, got %q", apperror.CodeCouponNotFound, result.Reason)
+	}
 }
 
 func TestValidateCoupon_Expired(t *testing.T) {
@@ -236,6 +240,9 @@ func TestValidateCoupon_Expired(t *testing.T) {
 	if result.Valid {
 		t.Error("expected expired coupon to be invalid")
 	}
+	if result.Reason != apperror.CodeCouponExpired {
+		t.Errorf("expected reason %s, got %q", apperror.CodeCouponExpired, result.Reason)
+	}
 }
 
 func TestValidateCoupon_PerUserLimit(t *testing.T) {
@@ -266,6 +273,9 @@ func TestValidateCoupon_PerUserLimit(t *testing.T) {
 	if result.Valid {
 		t.Error("expected coupon to be invalid when per-user limit exceeded")
 	}
+	if result.Reason != apperror.CodeCouponUserLimit {
+		t.Errorf("expected reason %s, got %q", apperror.CodeCouponUserLimit, result.Reason)
+	}
 }
 
 func TestValidateCoupon_PerUserLimit_OtherUser(t *testing.T) {
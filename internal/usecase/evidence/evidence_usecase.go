@@ -0,0 +1,212 @@
+package evidence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/google/uuid"
+)
+
+// UseCase defines the evidence file use case interface
+type UseCase interface {
+	// Upload stores an evidence file with its metadata
+	Upload(ctx context.Context, req *entity.UploadEvidenceRequest, file io.Reader, userID string) (*entity.EvidenceFile, error)
+
+	// List returns a page of evidence files matching the filter
+	List(ctx context.Context, filter *entity.EvidenceFilter) (*entity.EvidenceListResponse, error)
+
+	// Delete removes an evidence file and its metadata
+	Delete(ctx context.Context, filename string) error
+}
+
+type evidenceUseCase struct {
+	repo           repository.EvidenceRepository
+	auditRepo      repository.AuditRepository
+	inspectionRepo repository.InspectionRepository
+	taskRepo       repository.TaskRepository
+	storage        *storage.StorageService
+	bucket         string
+}
+
+// NewUseCase creates a new evidence use case
+func NewUseCase(
+	repo repository.EvidenceRepository,
+	auditRepo repository.AuditRepository,
+	inspectionRepo repository.InspectionRepository,
+	taskRepo repository.TaskRepository,
+	storageService *storage.StorageService,
+	cfg *config.Config,
+) UseCase {
+	return &evidenceUseCase{
+		repo:           repo,
+		auditRepo:      auditRepo,
+		inspectionRepo: inspectionRepo,
+		taskRepo:       taskRepo,
+		storage:        storageService,
+		bucket:         cfg.MinioBucketEvidence,
+	}
+}
+
+// Upload validates the metadata and the linked entity before storing the file, so a rejected
+// request leaves nothing in the bucket
+func (uc *evidenceUseCase) Upload(ctx context.Context, req *entity.UploadEvidenceRequest, file io.Reader, userID string) (*entity.EvidenceFile, error) {
+	if uc.storage == nil {
+		return nil, errors.New("storage service is not available")
+	}
+
+	ev, err := newEvidenceFile(req, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if ev.EntityType != nil {
+		if err := uc.checkEntity(ctx, *ev.EntityType, *ev.EntityID); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := uc.storage.UploadFile(ctx, uc.bucket, ev.Filename, file, req.Size, req.ContentType); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Create(ctx, ev); err != nil {
+		if delErr := uc.storage.DeleteFile(ctx, uc.bucket, ev.Filename); delErr != nil {
+			log.Printf("[EVIDENCE] Failed to remove %s after a metadata error: %v", ev.Filename, delErr)
+		}
+		return nil, err
+	}
+
+	ev.URL = uc.storage.GetPublicURL(uc.bucket, ev.Filename)
+	return ev, nil
+}
+
+// List returns a page of evidence files, most recently captured first by default
+func (uc *evidenceUseCase) List(ctx context.Context, filter *entity.EvidenceFilter) (*entity.EvidenceListResponse, error) {
+	if filter.EntityType != nil && !entity.ValidEvidenceEntityType(*filter.EntityType) {
+		return nil, errors.New("invalid entity_type: use audit, inspection or task")
+	}
+	tags, ok := entity.NormalizeEvidenceTags(filter.Tags)
+	if !ok {
+		return nil, errors.New("invalid tag filter")
+	}
+	filter.Tags = tags
+
+	files, total, err := uc.repo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if files == nil {
+		files = []entity.EvidenceFile{}
+	}
+	if uc.storage != nil {
+		for i := range files {
+			files[i].URL = uc.storage.GetPublicURL(uc.bucket, files[i].Filename)
+		}
+	}
+
+	return &entity.EvidenceListResponse{
+		Evidence: files,
+		Total:    total,
+		Page:     filter.Page,
+		PerPage:  filter.PerPage,
+	}, nil
+}
+
+// Delete removes the object and its metadata. Files uploaded before metadata was recorded have
+// no row and are removed from the bucket only.
+func (uc *evidenceUseCase) Delete(ctx context.Context, filename string) error {
+	if uc.storage == nil {
+		return errors.New("storage service is not available")
+	}
+	if err := uc.storage.DeleteFile(ctx, uc.bucket, filename); err != nil {
+		return err
+	}
+	return uc.repo.DeleteByFilename(ctx, filename)
+}
+
+// checkEntity verifies that the audit, inspection or task the evidence documents exists
+func (uc *evidenceUseCase) checkEntity(ctx context.Context, entityType, entityID string) error {
+	var found bool
+	switch entityType {
+	case entity.EvidenceEntityAudit:
+		a, err := uc.auditRepo.FindByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		found = a != nil
+	case entity.EvidenceEntityInspection:
+		i, err := uc.inspectionRepo.FindByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		found = i != nil
+	case entity.EvidenceEntityTask:
+		t, err := uc.taskRepo.FindByID(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		found = t != nil
+	}
+	if !found {
+		return fmt.Errorf("%s not found", entityType)
+	}
+	return nil
+}
+
+// newEvidenceFile validates the upload metadata and builds the evidence record
+func newEvidenceFile(req *entity.UploadEvidenceRequest, userID string, now time.Time) (*entity.EvidenceFile, error) {
+	tags, ok := entity.NormalizeEvidenceTags(req.Tags)
+	if !ok {
+		return nil, fmt.Errorf("invalid tags: at most %d tags of up to %d characters", entity.MaxEvidenceTags, entity.MaxEvidenceTagLength)
+	}
+
+	ev := &entity.EvidenceFile{
+		ID:           uuid.New().String(),
+		Filename:     fmt.Sprintf("ev_%d_%s%s", now.Unix(), uuid.New().String()[:8], strings.ToLower(filepath.Ext(req.FileName))),
+		OriginalName: req.FileName,
+		ContentType:  req.ContentType,
+		Size:         req.Size,
+		Tags:         tags,
+		CreatedAt:    now,
+	}
+	if req.Description != nil {
+		if d := strings.TrimSpace(*req.Description); d != "" {
+			ev.Description = &d
+		}
+	}
+	if userID != "" {
+		ev.UploadedBy = &userID
+	}
+
+	if req.CapturedAt != nil {
+		captured, ok := entity.ParseCaptureDate(*req.CapturedAt)
+		if !ok {
+			return nil, errors.New("invalid captured_at: use YYYY-MM-DD or RFC 3339")
+		}
+		if captured.After(now.Add(24 * time.Hour)) {
+			return nil, errors.New("invalid captured_at: date is in the future")
+		}
+		ev.CapturedAt = &captured
+	}
+
+	switch {
+	case req.EntityType == nil && req.EntityID == nil:
+	case req.EntityType == nil || req.EntityID == nil:
+		return nil, errors.New("invalid link: entity_type and entity_id must be given together")
+	case !entity.ValidEvidenceEntityType(*req.EntityType):
+		return nil, errors.New("invalid entity_type: use audit, inspection or task")
+	default:
+		ev.EntityType = req.EntityType
+		ev.EntityID = req.EntityID
+	}
+
+	return ev, nil
+}
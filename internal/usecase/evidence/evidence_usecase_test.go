@@ -0,0 +1,83 @@
+package evidence
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestNewEvidenceFile(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	req := &entity.UploadEvidenceRequest{
+		FileName:    "Fachada Bloco A.JPG",
+		ContentType: "image/jpeg",
+		Size:        2048,
+		Description: strPtr("  Infiltração na fachada  "),
+		Tags:        []string{"Fachada,infiltração", "fachada"},
+		CapturedAt:  strPtr("2024-05-30"),
+		EntityType:  strPtr(entity.EvidenceEntityInspection),
+		EntityID:    strPtr("insp-1"),
+	}
+
+	ev, err := newEvidenceFile(req, "user-1", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(ev.Filename, "ev_") || !strings.HasSuffix(ev.Filename, ".jpg") {
+		t.Errorf("filename = %q", ev.Filename)
+	}
+	if ev.OriginalName != req.FileName || ev.Size != 2048 {
+		t.Errorf("unexpected file fields: %+v", ev)
+	}
+	if ev.Description == nil || *ev.Description != "Infiltração na fachada" {
+		t.Errorf("description = %v", ev.Description)
+	}
+	if len(ev.Tags) != 2 || ev.Tags[0] != "fachada" || ev.Tags[1] != "infiltração" {
+		t.Errorf("tags = %v", ev.Tags)
+	}
+	if ev.CapturedAt == nil || ev.CapturedAt.Format("2006-01-02") != "2024-05-30" {
+		t.Errorf("captured_at = %v", ev.CapturedAt)
+	}
+	if ev.EntityType == nil || *ev.EntityType != "inspection" || *ev.EntityID != "insp-1" {
+		t.Errorf("unexpected link: %v %v", ev.EntityType, ev.EntityID)
+	}
+	if ev.UploadedBy == nil || *ev.UploadedBy != "user-1" {
+		t.Error("expected uploaded_by to be set")
+	}
+}
+
+func TestNewEvidenceFile_Invalid(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		req  entity.UploadEvidenceRequest
+	}{
+		{"future capture date", entity.UploadEvidenceRequest{CapturedAt: strPtr("2024-06-10")}},
+		{"malformed capture date", entity.UploadEvidenceRequest{CapturedAt: strPtr("30/05/2024")}},
+		{"entity type without id", entity.UploadEvidenceRequest{EntityType: strPtr("audit")}},
+		{"entity id without type", entity.UploadEvidenceRequest{EntityID: strPtr("a-1")}},
+		{"unknown entity type", entity.UploadEvidenceRequest{EntityType: strPtr("contract"), EntityID: strPtr("c-1")}},
+		{"tag too long", entity.UploadEvidenceRequest{Tags: []string{strings.Repeat("x", entity.MaxEvidenceTagLength+1)}}},
+	}
+	for _, tt := range tests {
+		req := tt.req
+		req.FileName = "a.png"
+		if _, err := newEvidenceFile(&req, "", now); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("%s: expected an invalid error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestNewEvidenceFile_NoMetadata(t *testing.T) {
+	ev, err := newEvidenceFile(&entity.UploadEvidenceRequest{FileName: "laudo.pdf"}, "", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ev.Tags == nil || ev.Description != nil || ev.CapturedAt != nil || ev.EntityType != nil || ev.UploadedBy != nil {
+		t.Errorf("expected empty metadata, got %+v", ev)
+	}
+}
@@ -0,0 +1,249 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// cacheTTL bounds how long a flag change made on another instance takes to be seen
+const cacheTTL = 30 * time.Second
+
+// UseCase defines the feature flag use case interface
+type UseCase interface {
+	// ListFlags returns all feature flags
+	ListFlags(ctx context.Context) ([]entity.FeatureFlag, error)
+
+	// GetFlag returns a feature flag by key
+	GetFlag(ctx context.Context, key string) (*entity.FeatureFlag, error)
+
+	// CreateFlag creates a feature flag
+	CreateFlag(ctx context.Context, req *entity.CreateFeatureFlagRequest) (*entity.FeatureFlag, error)
+
+	// UpdateFlag changes the state or targeting of a feature flag
+	UpdateFlag(ctx context.Context, key string, req *entity.UpdateFeatureFlagRequest) (*entity.FeatureFlag, error)
+
+	// DeleteFlag removes a feature flag
+	DeleteFlag(ctx context.Context, key string) error
+
+	// IsEnabled evaluates a flag for a user; unknown flags are off
+	IsEnabled(ctx context.Context, key string, fc entity.FlagContext) bool
+
+	// Evaluate returns the state of every flag for a user
+	Evaluate(ctx context.Context, fc entity.FlagContext) (map[string]bool, error)
+}
+
+type featureFlagUseCase struct {
+	repo repository.FeatureFlagRepository
+
+	mu       sync.RWMutex
+	flags    map[string]entity.FeatureFlag
+	loadedAt time.Time
+}
+
+// NewUseCase creates a new feature flag use case
+func NewUseCase(repo repository.FeatureFlagRepository) UseCase {
+	return &featureFlagUseCase{repo: repo}
+}
+
+// ListFlags returns all feature flags ordered by key
+func (uc *featureFlagUseCase) ListFlags(ctx context.Context) ([]entity.FeatureFlag, error) {
+	flags, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if flags == nil {
+		flags = []entity.FeatureFlag{}
+	}
+	return flags, nil
+}
+
+// GetFlag returns a feature flag by key
+func (uc *featureFlagUseCase) GetFlag(ctx context.Context, key string) (*entity.FeatureFlag, error) {
+	flag, err := uc.repo.FindByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if flag == nil {
+		return nil, errors.New("feature flag not found")
+	}
+	return flag, nil
+}
+
+// CreateFlag creates a feature flag. New flags are usually created disabled and enabled once
+// the targeting is in place.
+func (uc *featureFlagUseCase) CreateFlag(ctx context.Context, req *entity.CreateFeatureFlagRequest) (*entity.FeatureFlag, error) {
+	key := strings.ToLower(strings.TrimSpace(req.Key))
+	if !entity.ValidFeatureFlagKey(key) {
+		return nil, errors.New("invalid key: use lowercase letters, digits, '.', '_' or '-'")
+	}
+
+	existing, err := uc.repo.FindByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("invalid key: feature flag already exists")
+	}
+
+	roles, err := normalizeRoles(req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	flag := &entity.FeatureFlag{
+		ID:             uuid.New().String(),
+		Key:            key,
+		Description:    req.Description,
+		Enabled:        req.Enabled,
+		RolloutPercent: req.RolloutPercent,
+		UserIDs:        normalizeIDs(req.UserIDs),
+		Roles:          roles,
+		OrgIDs:         normalizeIDs(req.OrgIDs),
+	}
+	if err := uc.repo.Create(ctx, flag); err != nil {
+		return nil, err
+	}
+	uc.invalidate()
+
+	return uc.repo.FindByKey(ctx, key)
+}
+
+// UpdateFlag applies the provided fields to a feature flag
+func (uc *featureFlagUseCase) UpdateFlag(ctx context.Context, key string, req *entity.UpdateFeatureFlagRequest) (*entity.FeatureFlag, error) {
+	flag, err := uc.GetFlag(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		flag.Description = req.Description
+	}
+	if req.Enabled != nil {
+		flag.Enabled = *req.Enabled
+	}
+	if req.RolloutPercent != nil {
+		flag.RolloutPercent = *req.RolloutPercent
+	}
+	if req.UserIDs != nil {
+		flag.UserIDs = normalizeIDs(*req.UserIDs)
+	}
+	if req.Roles != nil {
+		roles, err := normalizeRoles(*req.Roles)
+		if err != nil {
+			return nil, err
+		}
+		flag.Roles = roles
+	}
+	if req.OrgIDs != nil {
+		flag.OrgIDs = normalizeIDs(*req.OrgIDs)
+	}
+
+	if err := uc.repo.Update(ctx, flag); err != nil {
+		return nil, err
+	}
+	uc.invalidate()
+
+	return uc.repo.FindByKey(ctx, flag.Key)
+}
+
+// DeleteFlag removes a feature flag; code still checking it sees it as off
+func (uc *featureFlagUseCase) DeleteFlag(ctx context.Context, key string) error {
+	if _, err := uc.GetFlag(ctx, key); err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(ctx, key); err != nil {
+		return err
+	}
+	uc.invalidate()
+	return nil
+}
+
+// IsEnabled evaluates a flag from the cache. It fails closed: when the flags cannot be
+// loaded, every flag is off.
+func (uc *featureFlagUseCase) IsEnabled(ctx context.Context, key string, fc entity.FlagContext) bool {
+	flags, err := uc.cached(ctx)
+	if err != nil {
+		log.Printf("[FEATURE_FLAGS] Failed to load flags, %q is off: %v", key, err)
+		return false
+	}
+	flag, ok := flags[key]
+	if !ok {
+		return false
+	}
+	return flag.IsEnabledFor(fc)
+}
+
+// Evaluate returns the state of every flag for a user
+func (uc *featureFlagUseCase) Evaluate(ctx context.Context, fc entity.FlagContext) (map[string]bool, error) {
+	flags, err := uc.cached(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool, len(flags))
+	for key, flag := range flags {
+		result[key] = flag.IsEnabledFor(fc)
+	}
+	return result, nil
+}
+
+// cached returns the flags by key, reloading them once the cache is older than cacheTTL
+func (uc *featureFlagUseCase) cached(ctx context.Context) (map[string]entity.FeatureFlag, error) {
+	uc.mu.RLock()
+	flags, loadedAt := uc.flags, uc.loadedAt
+	uc.mu.RUnlock()
+	if flags != nil && time.Since(loadedAt) < cacheTTL {
+		return flags, nil
+	}
+
+	list, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	flags = make(map[string]entity.FeatureFlag, len(list))
+	for _, f := range list {
+		flags[f.Key] = f
+	}
+
+	uc.mu.Lock()
+	uc.flags = flags
+	uc.loadedAt = time.Now()
+	uc.mu.Unlock()
+	return flags, nil
+}
+
+// invalidate drops the cache so changes made through this instance apply immediately
+func (uc *featureFlagUseCase) invalidate() {
+	uc.mu.Lock()
+	uc.flags = nil
+	uc.mu.Unlock()
+}
+
+// normalizeIDs trims the IDs and drops blanks and duplicates
+func normalizeIDs(ids []string) entity.StringList {
+	list := entity.StringList{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !list.Contains(id) {
+			list = append(list, id)
+		}
+	}
+	return list
+}
+
+func normalizeRoles(roles []string) (entity.StringList, error) {
+	list := normalizeIDs(roles)
+	for _, role := range list {
+		if !entity.UserRole(role).IsValid() {
+			return nil, errors.New("invalid role: " + role)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,91 @@
+package featureflag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func newTestUseCase() (UseCase, *testutil.MockFeatureFlagRepository) {
+	mockRepo := testutil.NewMockFeatureFlagRepository()
+	return NewUseCase(mockRepo), mockRepo
+}
+
+func TestCreateFlag_NormalizesTargeting(t *testing.T) {
+	uc, _ := newTestUseCase()
+	flag, err := uc.CreateFlag(context.Background(), &entity.CreateFeatureFlagRequest{
+		Key:            " Gateway.MercadoPago ",
+		Enabled:        true,
+		RolloutPercent: 10,
+		UserIDs:        []string{"u1", " u1", ""},
+		Roles:          []string{"instructor"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flag.Key != "gateway.mercadopago" {
+		t.Errorf("expected lowercase key, got %q", flag.Key)
+	}
+	if len(flag.UserIDs) != 1 || flag.OrgIDs == nil {
+		t.Errorf("targeting = users %v, orgs %v", flag.UserIDs, flag.OrgIDs)
+	}
+}
+
+func TestCreateFlag_Invalid(t *testing.T) {
+	uc, _ := newTestUseCase()
+	ctx := context.Background()
+
+	if _, err := uc.CreateFlag(ctx, &entity.CreateFeatureFlagRequest{Key: "bad key"}); err == nil {
+		t.Error("invalid key: expected error")
+	}
+	if _, err := uc.CreateFlag(ctx, &entity.CreateFeatureFlagRequest{Key: "flag-a", Roles: []string{"owner"}}); err == nil {
+		t.Error("unknown role: expected error")
+	}
+	if _, err := uc.CreateFlag(ctx, &entity.CreateFeatureFlagRequest{Key: "flag-a"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.CreateFlag(ctx, &entity.CreateFeatureFlagRequest{Key: "flag-a"}); err == nil {
+		t.Error("duplicate key: expected error")
+	}
+}
+
+func TestIsEnabled_CachesAndInvalidatesOnUpdate(t *testing.T) {
+	uc, repo := newTestUseCase()
+	ctx := context.Background()
+	fc := entity.FlagContext{UserID: "u1", Role: "student"}
+
+	if _, err := uc.CreateFlag(ctx, &entity.CreateFeatureFlagRequest{Key: "new-portal", RolloutPercent: 100}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uc.IsEnabled(ctx, "new-portal", fc) {
+		t.Error("disabled flag: expected off")
+	}
+	if uc.IsEnabled(ctx, "unknown", fc) {
+		t.Error("unknown flag: expected off")
+	}
+	if repo.FindCalls != 1 {
+		t.Errorf("expected flags loaded once, got %d loads", repo.FindCalls)
+	}
+
+	enabled := true
+	if _, err := uc.UpdateFlag(ctx, "new-portal", &entity.UpdateFeatureFlagRequest{Enabled: &enabled}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !uc.IsEnabled(ctx, "new-portal", fc) {
+		t.Error("enabled flag: expected on right after the update")
+	}
+
+	states, err := uc.Evaluate(ctx, fc)
+	if err != nil || !states["new-portal"] {
+		t.Errorf("Evaluate = %v, %v", states, err)
+	}
+}
+
+func TestUpdateFlag_NotFound(t *testing.T) {
+	uc, _ := newTestUseCase()
+	if _, err := uc.UpdateFlag(context.Background(), "missing", &entity.UpdateFeatureFlagRequest{}); err == nil {
+		t.Error("expected not found error")
+	}
+}
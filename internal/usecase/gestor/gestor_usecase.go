@@ -3,15 +3,19 @@ package gestor
 import (
 	"context"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/phone"
 	"github.com/google/uuid"
 )
 
 // CreateGestorRequest represents the request to create a gestor
 type CreateGestorRequest struct {
+	UserID   *string `json:"user_id"` // account that logs in as this gestor
 	Nome     string  `json:"nome" binding:"required"`
 	Email    string  `json:"email" binding:"required,email"`
 	Telefone *string `json:"telefone"`
@@ -20,6 +24,7 @@ type CreateGestorRequest struct {
 
 // UpdateGestorRequest represents the request to update a gestor
 type UpdateGestorRequest struct {
+	UserID   *string `json:"user_id"` // empty unlinks the account
 	Nome     *string `json:"nome"`
 	Email    *string `json:"email"`
 	Telefone *string `json:"telefone"`
@@ -27,6 +32,11 @@ type UpdateGestorRequest struct {
 	Ativo    *bool   `json:"ativo"`
 }
 
+// ReassignGestorRequest represents the request to move what a gestor manages to another gestor
+type ReassignGestorRequest struct {
+	ToGestorID string `json:"to_gestor_id" binding:"required"`
+}
+
 // UseCase defines the gestor use case interface
 type UseCase interface {
 	ListGestores(ctx context.Context) ([]entity.Gestor, error)
@@ -35,6 +45,10 @@ type UseCase interface {
 	CreateGestor(ctx context.Context, req *CreateGestorRequest) (*entity.Gestor, error)
 	UpdateGestor(ctx context.Context, id string, req *UpdateGestorRequest) (*entity.Gestor, error)
 	DeleteGestor(ctx context.Context, id string) error
+	ListDeletedGestores(ctx context.Context) ([]entity.Gestor, error)
+	RestoreGestor(ctx context.Context, id string) (*entity.Gestor, error)
+	GetGestorMetrics(ctx context.Context, id, from, to string) (*entity.GestorMetrics, error)
+	ReassignGestor(ctx context.Context, id string, req *ReassignGestorRequest) (*entity.GestorReassignment, error)
 }
 
 type gestorUseCase struct {
@@ -63,6 +77,11 @@ func (uc *gestorUseCase) GetGestorByID(ctx context.Context, id string) (*entity.
 
 // CreateGestor creates a new gestor
 func (uc *gestorUseCase) CreateGestor(ctx context.Context, req *CreateGestorRequest) (*entity.Gestor, error) {
+	telefone, err := normalizePhone("telefone", req.Telefone)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if email already exists
 	existing, err := uc.repo.FindByEmail(ctx, req.Email)
 	if err != nil {
@@ -72,12 +91,19 @@ func (uc *gestorUseCase) CreateGestor(ctx context.Context, req *CreateGestorRequ
 		return nil, errors.New("gestor with this email already exists")
 	}
 
+	id := uuid.New().String()
+	userID, err := uc.linkUser(ctx, id, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create gestor entity
 	gestor := &entity.Gestor{
-		ID:        uuid.New().String(),
+		ID:        id,
+		UserID:    userID,
 		Nome:      req.Nome,
 		Email:     req.Email,
-		Telefone:  req.Telefone,
+		Telefone:  telefone,
 		CPF:       req.CPF,
 		Ativo:     true,
 		CreatedAt: time.Now(),
@@ -114,11 +140,18 @@ func (uc *gestorUseCase) UpdateGestor(ctx context.Context, id string, req *Updat
 	}
 
 	// Update fields if provided
+	if req.UserID != nil {
+		if gestor.UserID, err = uc.linkUser(ctx, id, req.UserID); err != nil {
+			return nil, err
+		}
+	}
 	if req.Nome != nil {
 		gestor.Nome = *req.Nome
 	}
 	if req.Telefone != nil {
-		gestor.Telefone = req.Telefone
+		if gestor.Telefone, err = normalizePhone("telefone", req.Telefone); err != nil {
+			return nil, err
+		}
 	}
 	if req.CPF != nil {
 		gestor.CPF = req.CPF
@@ -138,7 +171,22 @@ func (uc *gestorUseCase) UpdateGestor(ctx context.Context, id string, req *Updat
 	return gestor, nil
 }
 
-// DeleteGestor soft deletes a gestor by setting ativo to false
+// linkUser checks the user account is not linked to another gestor; an empty ID unlinks it
+func (uc *gestorUseCase) linkUser(ctx context.Context, gestorID string, userID *string) (*string, error) {
+	if userID == nil || *userID == "" {
+		return nil, nil
+	}
+	linked, err := uc.repo.FindByUserID(ctx, *userID)
+	if err != nil {
+		return nil, err
+	}
+	if linked != nil && linked.ID != gestorID {
+		return nil, errors.New("gestor with this user already exists")
+	}
+	return userID, nil
+}
+
+// DeleteGestor soft deletes a gestor, moving it to the trash
 func (uc *gestorUseCase) DeleteGestor(ctx context.Context, id string) error {
 	// Find existing gestor
 	gestor, err := uc.repo.FindByID(ctx, id)
@@ -152,3 +200,181 @@ func (uc *gestorUseCase) DeleteGestor(ctx context.Context, id string) error {
 	// Soft delete by calling repository Delete method
 	return uc.repo.Delete(ctx, id)
 }
+
+// ListDeletedGestores returns the gestores in the trash
+func (uc *gestorUseCase) ListDeletedGestores(ctx context.Context) ([]entity.Gestor, error) {
+	return uc.repo.FindDeleted(ctx)
+}
+
+// RestoreGestor brings a gestor back from the trash
+func (uc *gestorUseCase) RestoreGestor(ctx context.Context, id string) (*entity.Gestor, error) {
+	restored, err := uc.repo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, errors.New("gestor not found")
+	}
+
+	return uc.repo.FindByID(ctx, id)
+}
+
+// ReassignGestor moves the contracts, open tasks and scheduled inspections of a gestor to an
+// active gestor, all or nothing. The gestor leaving may already be in the trash.
+func (uc *gestorUseCase) ReassignGestor(ctx context.Context, id string, req *ReassignGestorRequest) (*entity.GestorReassignment, error) {
+	if req.ToGestorID == id {
+		return nil, errors.New("invalid to_gestor_id: must be another gestor")
+	}
+
+	from, err := uc.findIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, errors.New("gestor not found")
+	}
+
+	to, err := uc.repo.FindByID(ctx, req.ToGestorID)
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		return nil, errors.New("invalid to_gestor_id: gestor not found")
+	}
+	if !to.Ativo {
+		return nil, errors.New("invalid to_gestor_id: gestor is inactive")
+	}
+
+	return uc.repo.Reassign(ctx, from.ID, to.ID)
+}
+
+// findIncludingDeleted returns a gestor by ID, looking in the trash too
+func (uc *gestorUseCase) findIncludingDeleted(ctx context.Context, id string) (*entity.Gestor, error) {
+	gestor, err := uc.repo.FindByID(ctx, id)
+	if err != nil || gestor != nil {
+		return gestor, err
+	}
+
+	deleted, err := uc.repo.FindDeleted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range deleted {
+		if deleted[i].ID == id {
+			return &deleted[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetGestorMetrics aggregates the performance of a gestor's contracts between from and to
+// (YYYY-MM-DD, inclusive). The range defaults to the last 12 months.
+func (uc *gestorUseCase) GetGestorMetrics(ctx context.Context, id, from, to string) (*entity.GestorMetrics, error) {
+	now := time.Now()
+
+	end := now
+	if to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, errors.New("invalid to date, expected YYYY-MM-DD")
+		}
+		end = parsed
+	}
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.Local)
+
+	start := end.AddDate(-1, 0, 1)
+	if from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, errors.New("invalid from date, expected YYYY-MM-DD")
+		}
+		start = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if start.After(end) {
+		return nil, errors.New("invalid date range: from is after to")
+	}
+
+	gestor, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if gestor == nil {
+		return nil, errors.New("gestor not found")
+	}
+
+	contracts, err := uc.repo.FindContractMetrics(ctx, id, start, end.AddDate(0, 0, 1), now)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := buildGestorMetrics(contracts)
+	metrics.GestorID = gestor.ID
+	metrics.GestorNome = gestor.Nome
+	metrics.From = start.Format("2006-01-02")
+	metrics.To = end.Format("2006-01-02")
+	return metrics, nil
+}
+
+// buildGestorMetrics computes per-contract and overall rates from the raw counts.
+// Tasks still open and not yet due are left out of the on-time rate.
+func buildGestorMetrics(contracts []entity.GestorContractMetrics) *entity.GestorMetrics {
+	metrics := &entity.GestorMetrics{
+		ManagedContracts: len(contracts),
+		Contracts:        make([]entity.GestorContractMetrics, 0, len(contracts)),
+	}
+
+	var scoreSum float64
+	for _, c := range contracts {
+		measured := c.TasksCompletedOnTime + c.TasksCompletedLate + c.TasksOverdue
+		if c.AuditCount > 0 {
+			avg := round2(c.AuditScoreSum / float64(c.AuditCount))
+			c.AverageAuditScore = &avg
+		}
+		c.TaskOnTimeRate = percentage(c.TasksCompletedOnTime, measured)
+		c.InspectionComplianceRate = percentage(c.InspectionsCompleted, c.InspectionsDue)
+
+		if c.Ativo {
+			metrics.ActiveContracts++
+		}
+		metrics.AuditCount += c.AuditCount
+		metrics.AuditsOnTarget += c.AuditsOnTarget
+		scoreSum += c.AuditScoreSum
+		metrics.TasksMeasured += measured
+		metrics.TasksCompletedOnTime += c.TasksCompletedOnTime
+		metrics.InspectionsDue += c.InspectionsDue
+		metrics.InspectionsCompleted += c.InspectionsCompleted
+
+		metrics.Contracts = append(metrics.Contracts, c)
+	}
+
+	if metrics.AuditCount > 0 {
+		avg := round2(scoreSum / float64(metrics.AuditCount))
+		metrics.AverageAuditScore = &avg
+	}
+	metrics.TaskOnTimeRate = percentage(metrics.TasksCompletedOnTime, metrics.TasksMeasured)
+	metrics.InspectionComplianceRate = percentage(metrics.InspectionsCompleted, metrics.InspectionsDue)
+	return metrics
+}
+
+// percentage returns part/total as a percentage, or nil when there is nothing to measure
+func percentage(part, total int) *float64 {
+	if total == 0 {
+		return nil
+	}
+	pct := round2(float64(part) / float64(total) * 100)
+	return &pct
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// normalizePhone returns an optional phone number in E.164, the format the WhatsApp and SMS
+// channels send to
+func normalizePhone(field string, p *string) (*string, error) {
+	n, err := phone.NormalizeOptional(p)
+	if err != nil {
+		return nil, apperror.New(apperror.CodeInvalidPhone, "invalid "+field+": include the area code, e.g. (11) 98765-4321")
+	}
+	return n, nil
+}
@@ -0,0 +1,120 @@
+package gestor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func TestBuildGestorMetrics(t *testing.T) {
+	contracts := []entity.GestorContractMetrics{
+		{
+			ContratoID: "c1", Ativo: true,
+			AuditCount: 2, AuditScoreSum: 170, AuditsOnTarget: 1,
+			TasksCompletedOnTime: 6, TasksCompletedLate: 1, TasksOverdue: 1,
+			InspectionsDue: 4, InspectionsCompleted: 3,
+		},
+		{
+			ContratoID: "c2", Ativo: false,
+			AuditCount: 1, AuditScoreSum: 95, AuditsOnTarget: 1,
+		},
+	}
+
+	m := buildGestorMetrics(contracts)
+	if m.ManagedContracts != 2 || m.ActiveContracts != 1 {
+		t.Errorf("unexpected contract counts: managed=%d active=%d", m.ManagedContracts, m.ActiveContracts)
+	}
+	if m.AverageAuditScore == nil || *m.AverageAuditScore != 88.33 {
+		t.Errorf("expected average audit score 88.33, got %v", m.AverageAuditScore)
+	}
+	if m.TasksMeasured != 8 || m.TaskOnTimeRate == nil || *m.TaskOnTimeRate != 75 {
+		t.Errorf("expected 75%% on-time over 8 tasks, got %d / %v", m.TasksMeasured, m.TaskOnTimeRate)
+	}
+	if m.InspectionComplianceRate == nil || *m.InspectionComplianceRate != 75 {
+		t.Errorf("expected 75%% inspection compliance, got %v", m.InspectionComplianceRate)
+	}
+
+	c2 := m.Contracts[1]
+	if c2.AverageAuditScore == nil || *c2.AverageAuditScore != 95 {
+		t.Errorf("expected contract average 95, got %v", c2.AverageAuditScore)
+	}
+	if c2.TaskOnTimeRate != nil || c2.InspectionComplianceRate != nil {
+		t.Error("expected nil rates for a contract with nothing to measure")
+	}
+}
+
+func TestBuildGestorMetricsEmpty(t *testing.T) {
+	m := buildGestorMetrics(nil)
+	if m.ManagedContracts != 0 || m.AverageAuditScore != nil || m.TaskOnTimeRate != nil {
+		t.Errorf("unexpected metrics for gestor without contracts: %+v", m)
+	}
+	if m.Contracts == nil {
+		t.Error("expected empty contracts slice, got nil")
+	}
+}
+
+func TestReassignGestor(t *testing.T) {
+	deletedAt := time.Now()
+	repo := testutil.NewMockGestorRepository(
+		&entity.Gestor{ID: "g1", Ativo: true, DeletedAt: &deletedAt},
+		&entity.Gestor{ID: "g2", Ativo: true},
+		&entity.Gestor{ID: "g3", Ativo: false},
+	)
+	uc := NewUseCase(repo)
+	ctx := context.Background()
+
+	if _, err := uc.ReassignGestor(ctx, "g1", &ReassignGestorRequest{ToGestorID: "g2"}); err != nil {
+		t.Fatalf("expected a gestor in the trash to be reassigned, got %v", err)
+	}
+	if len(repo.Reassigned) != 1 || repo.Reassigned[0] != [2]string{"g1", "g2"} {
+		t.Errorf("expected g1 to move to g2, got %v", repo.Reassigned)
+	}
+
+	tests := []struct {
+		from, to, want string
+	}{
+		{"g2", "g2", "invalid to_gestor_id: must be another gestor"},
+		{"missing", "g2", "gestor not found"},
+		{"g2", "missing", "invalid to_gestor_id: gestor not found"},
+		{"g2", "g3", "invalid to_gestor_id: gestor is inactive"},
+		{"g2", "g1", "invalid to_gestor_id: gestor not found"},
+	}
+	for _, tt := range tests {
+		_, err := uc.ReassignGestor(ctx, tt.from, &ReassignGestorRequest{ToGestorID: tt.to})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s -> %s: expected %q, got %v", tt.from, tt.to, tt.want, err)
+		}
+	}
+	if len(repo.Reassigned) != 1 {
+		t.Errorf("expected rejected requests to move nothing, got %v", repo.Reassigned)
+	}
+}
+
+func TestGestorUserLink(t *testing.T) {
+	ana := "user-ana"
+	repo := testutil.NewMockGestorRepository(&entity.Gestor{ID: "gst-1", UserID: &ana, Email: "ana@x.com", Ativo: true})
+	uc := NewUseCase(repo)
+	ctx := context.Background()
+
+	if _, err := uc.CreateGestor(ctx, &CreateGestorRequest{UserID: &ana, Nome: "Bia", Email: "bia@x.com"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected a user linked to another gestor to be refused, got %v", err)
+	}
+	bia := "user-bia"
+	created, err := uc.CreateGestor(ctx, &CreateGestorRequest{UserID: &bia, Nome: "Bia", Email: "bia@x.com"})
+	if err != nil || created.UserID == nil || *created.UserID != bia {
+		t.Fatalf("expected the gestor to be linked to the user, got %+v, %v", created, err)
+	}
+
+	// Saving the same link again is fine, an empty one unlinks the account
+	if _, err := uc.UpdateGestor(ctx, "gst-1", &UpdateGestorRequest{UserID: &ana}); err != nil {
+		t.Errorf("expected the gestor to keep its user, got %v", err)
+	}
+	empty := ""
+	if updated, err := uc.UpdateGestor(ctx, "gst-1", &UpdateGestorRequest{UserID: &empty}); err != nil || updated.UserID != nil {
+		t.Errorf("expected the user to be unlinked, got %+v, %v", updated, err)
+	}
+}
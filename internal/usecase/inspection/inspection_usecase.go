@@ -43,7 +43,7 @@ func NewUseCase(
 
 // ListInspections returns inspections based on filters
 func (uc *inspectionUseCase) ListInspections(ctx context.Context, filter *entity.InspectionFilter) ([]entity.Inspection, error) {
-	if filter == nil || (filter.ContractID == "" && filter.InspectorID == "" && filter.Status == "" && filter.StartDate == nil && filter.EndDate == nil) {
+	if filter == nil || (filter.ContractID == "" && filter.InspectorID == "" && filter.Status == "" && filter.StartDate == nil && filter.EndDate == nil && filter.Query.IsEmpty()) {
 		return uc.repo.FindAll(ctx)
 	}
 	return uc.repo.FindAllWithFilters(ctx, filter)
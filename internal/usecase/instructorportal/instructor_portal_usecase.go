@@ -0,0 +1,155 @@
+package instructorportal
+
+import (
+	"context"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/payout"
+	"github.com/condotrack/api/internal/usecase/revenue"
+)
+
+// UseCase defines the self-service use case of the logged-in instructor. Every method is
+// scoped to the user ID of the token, which is the instructor ID of courses and splits.
+type UseCase interface {
+	// ListCourses returns the courses the instructor teaches
+	ListCourses(ctx context.Context, instructorID string) ([]entity.Course, error)
+
+	// ListEnrollments returns the enrollments in the courses of the instructor matching the
+	// list query, with pagination
+	ListEnrollments(ctx context.Context, instructorID string, page, perPage int, query listquery.Query) ([]entity.Matricula, int, error)
+
+	// GetEarnings returns the ledger totals of the instructor with their revenue splits
+	GetEarnings(ctx context.Context, instructorID string) (*Earnings, error)
+
+	// ListPayouts returns the payout history of the instructor
+	ListPayouts(ctx context.Context, instructorID, status string) ([]entity.PayoutBatch, error)
+
+	// GetPayoutAccount returns the account the instructor is paid out to
+	GetPayoutAccount(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error)
+
+	// SavePayoutAccount registers or replaces the account the instructor is paid out to
+	SavePayoutAccount(ctx context.Context, instructorID string, req *entity.SavePayoutAccountRequest) (*entity.InstructorPayoutAccount, error)
+
+	// VerifyPayoutAccount starts a new verification of the account the instructor is paid out to
+	VerifyPayoutAccount(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error)
+
+	// ConfirmPayoutAccount confirms the micro-deposit the instructor received on their payout account
+	ConfirmPayoutAccount(ctx context.Context, instructorID string, req *entity.ConfirmMicroDepositRequest) (*entity.InstructorPayoutAccount, error)
+}
+
+// Earnings is what the instructor earned so far and the splits the amounts come from
+type Earnings struct {
+	Summary *revenue.InstructorTotalResponse `json:"summary"`
+	Splits  []entity.RevenueSplit            `json:"splits"`
+}
+
+type instructorPortalUseCase struct {
+	courseRepo    repository.CourseRepository
+	matriculaRepo repository.MatriculaRepository
+	revenue       revenue.UseCase
+	payouts       payout.UseCase
+	transfers     payout.TransferUseCase
+}
+
+// NewUseCase creates a new instructor portal use case
+func NewUseCase(
+	courseRepo repository.CourseRepository,
+	matriculaRepo repository.MatriculaRepository,
+	revenueUC revenue.UseCase,
+	payouts payout.UseCase,
+	transfers payout.TransferUseCase,
+) UseCase {
+	return &instructorPortalUseCase{
+		courseRepo:    courseRepo,
+		matriculaRepo: matriculaRepo,
+		revenue:       revenueUC,
+		payouts:       payouts,
+		transfers:     transfers,
+	}
+}
+
+// role is the role the payout use cases authorize the instructor with
+const role = string(entity.RoleInstructor)
+
+// ListCourses returns the courses of the instructor
+func (uc *instructorPortalUseCase) ListCourses(ctx context.Context, instructorID string) ([]entity.Course, error) {
+	courses, err := uc.courseRepo.FindByInstructor(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	if courses == nil {
+		courses = []entity.Course{}
+	}
+	return courses, nil
+}
+
+// ListEnrollments returns the enrollments of the courses of the instructor. The instructor
+// filter is added to the query, so a filter on another instructor matches nothing.
+func (uc *instructorPortalUseCase) ListEnrollments(ctx context.Context, instructorID string, page, perPage int, query listquery.Query) ([]entity.Matricula, int, error) {
+	query.Filters = append(query.Filters, listquery.Filter{Field: "instructor_id", Op: listquery.OpEq, Value: instructorID})
+
+	enrollments, total, err := uc.matriculaRepo.FindAll(ctx, page, perPage, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range enrollments {
+		// The instructor follows the progress of the students but has no use for their
+		// documents or phone numbers
+		enrollments[i].StudentCPF = nil
+		enrollments[i].StudentPhone = nil
+	}
+	if enrollments == nil {
+		enrollments = []entity.Matricula{}
+	}
+	return enrollments, total, nil
+}
+
+// GetEarnings returns the ledger totals and the revenue splits of the instructor
+func (uc *instructorPortalUseCase) GetEarnings(ctx context.Context, instructorID string) (*Earnings, error) {
+	summary, err := uc.revenue.GetInstructorTotalEarnings(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	splits, err := uc.revenue.GetInstructorEarnings(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	if splits == nil {
+		splits = []entity.RevenueSplit{}
+	}
+	return &Earnings{Summary: summary, Splits: splits}, nil
+}
+
+// ListPayouts returns the payout batches of the instructor
+func (uc *instructorPortalUseCase) ListPayouts(ctx context.Context, instructorID, status string) ([]entity.PayoutBatch, error) {
+	batches, err := uc.payouts.ListInstructorBatches(ctx, instructorID, status, instructorID, role)
+	if err != nil {
+		return nil, err
+	}
+	if batches == nil {
+		batches = []entity.PayoutBatch{}
+	}
+	return batches, nil
+}
+
+// GetPayoutAccount returns the payout account of the instructor
+func (uc *instructorPortalUseCase) GetPayoutAccount(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error) {
+	return uc.transfers.GetAccount(ctx, instructorID, instructorID, role)
+}
+
+// SavePayoutAccount registers the payout account of the instructor
+func (uc *instructorPortalUseCase) SavePayoutAccount(ctx context.Context, instructorID string, req *entity.SavePayoutAccountRequest) (*entity.InstructorPayoutAccount, error) {
+	return uc.transfers.SaveAccount(ctx, instructorID, req)
+}
+
+// VerifyPayoutAccount starts a new verification of the payout account of the instructor
+func (uc *instructorPortalUseCase) VerifyPayoutAccount(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error) {
+	return uc.transfers.StartVerification(ctx, instructorID)
+}
+
+// ConfirmPayoutAccount confirms the micro-deposit received on the payout account of the instructor
+func (uc *instructorPortalUseCase) ConfirmPayoutAccount(ctx context.Context, instructorID string, req *entity.ConfirmMicroDepositRequest) (*entity.InstructorPayoutAccount, error) {
+	return uc.transfers.ConfirmMicroDeposit(ctx, instructorID, req.Amount)
+}
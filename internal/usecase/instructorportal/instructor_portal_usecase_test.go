@@ -0,0 +1,45 @@
+package instructorportal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+// queryRecorder keeps the list query the use case sent to the repository
+type queryRecorder struct {
+	*testutil.MockMatriculaRepository
+	query listquery.Query
+}
+
+func (r *queryRecorder) FindAll(ctx context.Context, page, perPage int, query listquery.Query) ([]entity.Matricula, int, error) {
+	r.query = query
+	return r.MockMatriculaRepository.FindAll(ctx, page, perPage, query)
+}
+
+func TestListEnrollments_ScopedToInstructor(t *testing.T) {
+	cpf, phone := "12345678900", "11999990000"
+	repo := &queryRecorder{MockMatriculaRepository: testutil.NewMockMatriculaRepository()}
+	repo.Enrollments["e1"] = &entity.Matricula{ID: "e1", StudentName: "Aluno", StudentCPF: &cpf, StudentPhone: &phone}
+	uc := NewUseCase(nil, repo, nil, nil, nil)
+
+	query := listquery.Query{Filters: []listquery.Filter{{Field: "status", Op: listquery.OpEq, Value: "active"}}}
+	enrollments, total, err := uc.ListEnrollments(context.Background(), "instructor-1", 1, 20, query)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := listquery.Filter{Field: "instructor_id", Op: listquery.OpEq, Value: "instructor-1"}
+	if len(repo.query.Filters) != 2 || repo.query.Filters[1] != want {
+		t.Errorf("expected the instructor filter to be added, got %+v", repo.query.Filters)
+	}
+	if total != 1 || len(enrollments) != 1 {
+		t.Fatalf("expected one enrollment, got %d", total)
+	}
+	if enrollments[0].StudentCPF != nil || enrollments[0].StudentPhone != nil {
+		t.Errorf("expected the student CPF and phone to be hidden, got %+v", enrollments[0])
+	}
+}
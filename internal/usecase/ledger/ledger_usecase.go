@@ -0,0 +1,167 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+// ErrAccessDenied is returned when an instructor requests the ledger of someone else
+var ErrAccessDenied = errors.New("access restricted to your own ledger")
+
+// UseCase defines the instructor earnings ledger use case interface
+type UseCase interface {
+	// GetLedger returns the ledger entries of an instructor, optionally limited to a period
+	GetLedger(ctx context.Context, instructorID, from, to, userID, role string) (*entity.InstructorStatement, error)
+
+	// GetStatement returns the statement of an instructor for one month (YYYY-MM)
+	GetStatement(ctx context.Context, instructorID, month, userID, role string) (*entity.InstructorStatement, error)
+
+	// ExportStatementPDF renders the monthly statement of an instructor as a PDF document
+	ExportStatementPDF(ctx context.Context, instructorID, month, userID, role string) (*entity.InstructorStatement, []byte, error)
+}
+
+type ledgerUseCase struct {
+	repo     repository.InstructorLedgerRepository
+	userRepo repository.UserRepository
+}
+
+// NewUseCase creates a new instructor ledger use case
+func NewUseCase(repo repository.InstructorLedgerRepository, userRepo repository.UserRepository) UseCase {
+	return &ledgerUseCase{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// GetLedger returns the entries of [from, to] (whole history when both are empty); the opening
+// balance carries every entry before from
+func (uc *ledgerUseCase) GetLedger(ctx context.Context, instructorID, from, to, userID, role string) (*entity.InstructorStatement, error) {
+	start, end, err := parseRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return uc.statement(ctx, instructorID, start, end, userID, role)
+}
+
+// GetStatement returns the statement of a calendar month, the current one when month is empty
+func (uc *ledgerUseCase) GetStatement(ctx context.Context, instructorID, month, userID, role string) (*entity.InstructorStatement, error) {
+	start, err := parseMonth(month, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	end := start.AddDate(0, 1, 0)
+	return uc.statement(ctx, instructorID, &start, &end, userID, role)
+}
+
+// ExportStatementPDF renders the monthly statement as a PDF document
+func (uc *ledgerUseCase) ExportStatementPDF(ctx context.Context, instructorID, month, userID, role string) (*entity.InstructorStatement, []byte, error) {
+	statement, err := uc.GetStatement(ctx, instructorID, month, userID, role)
+	if err != nil {
+		return nil, nil, err
+	}
+	return statement, renderStatementPDF(statement, time.Now()), nil
+}
+
+// statement builds the ledger of an instructor over [start, end)
+func (uc *ledgerUseCase) statement(ctx context.Context, instructorID string, start, end *time.Time, userID, role string) (*entity.InstructorStatement, error) {
+	if err := authorize(instructorID, userID, role); err != nil {
+		return nil, err
+	}
+
+	instructor, err := uc.userRepo.FindByID(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	if instructor == nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	var opening float64
+	if start != nil {
+		opening, err = uc.repo.GetBalance(ctx, instructorID, *start)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := uc.repo.FindByInstructor(ctx, instructorID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []entity.InstructorLedgerEntry{}
+	}
+
+	totals, closing := entity.SummarizeLedger(opening, entries)
+	statement := &entity.InstructorStatement{
+		InstructorID:   instructorID,
+		InstructorName: instructor.Nome,
+		PeriodStart:    start,
+		OpeningBalance: roundCents(opening),
+		Totals:         totals,
+		ClosingBalance: closing,
+		Entries:        entries,
+	}
+	// The period end is shown inclusive
+	if end != nil {
+		last := end.AddDate(0, 0, -1)
+		statement.PeriodEnd = &last
+	}
+	return statement, nil
+}
+
+// parseRange parses an optional [from, to] date range; to is inclusive
+func parseRange(from, to string) (*time.Time, *time.Time, error) {
+	var start, end *time.Time
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, nil, errors.New("invalid from: use YYYY-MM-DD")
+		}
+		start = &t
+	}
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, nil, errors.New("invalid to: use YYYY-MM-DD")
+		}
+		t = t.AddDate(0, 0, 1)
+		end = &t
+	}
+	if start != nil && end != nil && !end.After(*start) {
+		return nil, nil, errors.New("invalid period: to must not be before from")
+	}
+	return start, end, nil
+}
+
+// parseMonth returns the first day of a YYYY-MM month, the month of now when empty
+func parseMonth(month string, now time.Time) (time.Time, error) {
+	if month == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, errors.New("invalid month: use YYYY-MM")
+	}
+	return t, nil
+}
+
+// authorize allows admins and the instructor the ledger belongs to
+func authorize(instructorID, userID, role string) error {
+	if role == string(entity.RoleAdmin) {
+		return nil
+	}
+	if userID == "" || userID != instructorID {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
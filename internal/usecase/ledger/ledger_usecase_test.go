@@ -0,0 +1,97 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestParseMonth(t *testing.T) {
+	now := time.Date(2026, 3, 17, 15, 0, 0, 0, time.UTC)
+
+	start, err := parseMonth("", now)
+	if err != nil || !start.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseMonth(\"\") = %v, %v; want 2026-03-01", start, err)
+	}
+
+	start, err = parseMonth("2025-12", now)
+	if err != nil || !start.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parseMonth(2025-12) = %v, %v; want 2025-12-01", start, err)
+	}
+
+	if _, err := parseMonth("12/2025", now); err == nil {
+		t.Error("parseMonth(12/2025): expected error")
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	start, end, err := parseRange("", "")
+	if err != nil || start != nil || end != nil {
+		t.Errorf("empty range = %v/%v, %v; want whole history", start, end, err)
+	}
+
+	start, end, err = parseRange("2026-03-01", "2026-03-31")
+	if err != nil {
+		t.Fatalf("parseRange: unexpected error %v", err)
+	}
+	if !end.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) || !start.Before(*end) {
+		t.Errorf("range = %v/%v; want to be inclusive", start, end)
+	}
+
+	if _, _, err := parseRange("2026-03-10", "2026-03-01"); err == nil {
+		t.Error("to before from: expected error")
+	}
+	if _, _, err := parseRange("10/03/2026", ""); err == nil {
+		t.Error("invalid from: expected error")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	if err := authorize("inst-1", "admin-1", string(entity.RoleAdmin)); err != nil {
+		t.Errorf("admin: authorize = %v, want nil", err)
+	}
+	if err := authorize("inst-1", "inst-1", "instructor"); err != nil {
+		t.Errorf("own ledger: authorize = %v, want nil", err)
+	}
+	if err := authorize("inst-1", "inst-2", "instructor"); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("other instructor: authorize = %v, want ErrAccessDenied", err)
+	}
+}
+
+func TestRenderStatementPDF(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	statement := &entity.InstructorStatement{
+		InstructorName: "Maria Souza",
+		PeriodStart:    &start,
+		PeriodEnd:      &end,
+		OpeningBalance: 100,
+	}
+	for i := 0; i < 60; i++ {
+		statement.Entries = append(statement.Entries, entity.InstructorLedgerEntry{
+			EntryType:   entity.LedgerEntryCredit,
+			Amount:      10,
+			Description: fmt.Sprintf("Receita - matrícula %d", i),
+			OccurredAt:  start.AddDate(0, 0, i%28),
+		})
+	}
+	statement.Totals, statement.ClosingBalance = entity.SummarizeLedger(statement.OpeningBalance, statement.Entries)
+
+	out := renderStatementPDF(statement, end)
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatal("expected PDF output")
+	}
+	if !bytes.Contains(out, []byte("(01/03/2026 - 31/03/2026) Tj")) {
+		t.Error("expected statement period in document")
+	}
+	if !bytes.Contains(out, []byte("(R$ 700,00) Tj")) {
+		t.Error("expected closing balance in document")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Error("expected entries to continue on a second page")
+	}
+}
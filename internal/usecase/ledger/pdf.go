@@ -0,0 +1,149 @@
+package ledger
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/pdf"
+)
+
+var entryTypeLabels = map[string]string{
+	entity.LedgerEntryCredit:     "Crédito",
+	entity.LedgerEntryRefund:     "Estorno",
+	entity.LedgerEntryChargeback: "Chargeback",
+	entity.LedgerEntryPayout:     "Repasse",
+	entity.LedgerEntryAdjustment: "Ajuste",
+}
+
+// Layout (points)
+const (
+	marginLeft   = 40.0
+	marginRight  = pdf.A4Width - 40.0
+	marginBottom = pdf.A4Height - 60.0
+	colType      = 330.0
+	colAmount    = 460.0
+)
+
+// renderStatementPDF lays out an instructor statement on A4 pages, with the running balance
+// after each entry
+func renderStatementPDF(s *entity.InstructorStatement, generatedAt time.Time) []byte {
+	doc := pdf.New()
+	doc.SetTitle("Extrato " + s.InstructorName)
+	doc.AddPage()
+
+	y := 60.0
+	doc.Text(marginLeft, y, 18, pdf.Bold, pdf.AlignLeft, "EXTRATO DO INSTRUTOR")
+	doc.Text(marginRight, y, 12, pdf.Bold, pdf.AlignRight, periodLabel(s))
+	y += 18
+	doc.Text(marginLeft, y, 10, pdf.Regular, pdf.AlignLeft, s.InstructorName)
+	doc.Text(marginRight, y, 10, pdf.Regular, pdf.AlignRight, "Emitido em "+generatedAt.Format("02/01/2006 15:04"))
+	y += 10
+	doc.Line(marginLeft, y, marginRight, y, 1)
+
+	// Summary
+	y += 22
+	summary := []struct {
+		label  string
+		amount float64
+	}{
+		{"Saldo anterior", s.OpeningBalance},
+		{"Créditos", s.Totals.Credits},
+		{"Estornos", s.Totals.Refunds},
+		{"Chargebacks", s.Totals.Chargebacks},
+		{"Ajustes", s.Totals.Adjustments},
+		{"Repasses", s.Totals.Payouts},
+	}
+	for _, line := range summary {
+		doc.Text(marginLeft, y, 10, pdf.Regular, pdf.AlignLeft, line.label)
+		doc.Text(colAmount, y, 10, pdf.Regular, pdf.AlignRight, formatBRL(line.amount))
+		y += 14
+	}
+	doc.Line(marginLeft, y-8, colAmount, y-8, 0.5)
+	y += 4
+	doc.Text(marginLeft, y, 11, pdf.Bold, pdf.AlignLeft, "Saldo final")
+	doc.Text(colAmount, y, 11, pdf.Bold, pdf.AlignRight, formatBRL(s.ClosingBalance))
+
+	// Entries table
+	y += 30
+	header := func() {
+		doc.FillRect(marginLeft, y-12, marginRight-marginLeft, 18, 0.9)
+		doc.Text(marginLeft+4, y, 10, pdf.Bold, pdf.AlignLeft, "Data")
+		doc.Text(marginLeft+70, y, 10, pdf.Bold, pdf.AlignLeft, "Descrição")
+		doc.Text(colType, y, 10, pdf.Bold, pdf.AlignLeft, "Tipo")
+		doc.Text(colAmount, y, 10, pdf.Bold, pdf.AlignRight, "Valor")
+		doc.Text(marginRight-4, y, 10, pdf.Bold, pdf.AlignRight, "Saldo")
+		y += 20
+	}
+	header()
+
+	if len(s.Entries) == 0 {
+		doc.Text(marginLeft+4, y, 10, pdf.Regular, pdf.AlignLeft, "Nenhuma movimentação no período")
+	}
+
+	balance := s.OpeningBalance
+	for _, e := range s.Entries {
+		lines := pdf.WrapText(e.Description, 10, pdf.Regular, colType-marginLeft-80)
+		if y+float64(len(lines))*13 > marginBottom {
+			doc.AddPage()
+			y = 60
+			header()
+		}
+
+		balance = roundCents(balance + e.Amount)
+		doc.Text(marginLeft+4, y, 10, pdf.Regular, pdf.AlignLeft, e.OccurredAt.Format("02/01/2006"))
+		doc.Text(colType, y, 10, pdf.Regular, pdf.AlignLeft, entryTypeLabel(e.EntryType))
+		doc.Text(colAmount, y, 10, pdf.Regular, pdf.AlignRight, formatBRL(e.Amount))
+		doc.Text(marginRight-4, y, 10, pdf.Regular, pdf.AlignRight, formatBRL(balance))
+		for _, line := range lines {
+			doc.Text(marginLeft+70, y, 10, pdf.Regular, pdf.AlignLeft, line)
+			y += 13
+		}
+		y += 4
+	}
+
+	return doc.Bytes()
+}
+
+// periodLabel prints the statement period as dd/mm/yyyy - dd/mm/yyyy
+func periodLabel(s *entity.InstructorStatement) string {
+	switch {
+	case s.PeriodStart != nil && s.PeriodEnd != nil:
+		return s.PeriodStart.Format("02/01/2006") + " - " + s.PeriodEnd.Format("02/01/2006")
+	case s.PeriodStart != nil:
+		return "A partir de " + s.PeriodStart.Format("02/01/2006")
+	case s.PeriodEnd != nil:
+		return "Até " + s.PeriodEnd.Format("02/01/2006")
+	default:
+		return "Histórico completo"
+	}
+}
+
+func entryTypeLabel(entryType string) string {
+	if label, ok := entryTypeLabels[entryType]; ok {
+		return label
+	}
+	return entryType
+}
+
+// formatBRL formats an amount as Brazilian currency (R$ 1.234,56)
+func formatBRL(v float64) string {
+	neg := v < 0
+	cents := int64(math.Round(math.Abs(v) * 100))
+	intPart := fmt.Sprintf("%d", cents/100)
+
+	var grouped []string
+	for len(intPart) > 3 {
+		grouped = append([]string{intPart[len(intPart)-3:]}, grouped...)
+		intPart = intPart[:len(intPart)-3]
+	}
+	grouped = append([]string{intPart}, grouped...)
+
+	s := fmt.Sprintf("R$ %s,%02d", strings.Join(grouped, "."), cents%100)
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
@@ -2,34 +2,72 @@ package matricula
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/listquery"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/usecase/revenue"
+	"github.com/condotrack/api/pkg/document"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/condotrack/api/pkg/phone"
 	"github.com/google/uuid"
 )
 
 // UseCase defines the matricula use case interface
 type UseCase interface {
-	ListEnrollments(ctx context.Context, page, perPage int) (*entity.MatriculaListResponse, error)
+	ListEnrollments(ctx context.Context, page, perPage int, query listquery.Query) (*entity.MatriculaListResponse, error)
 	ListEnrollmentsByStudent(ctx context.Context, studentID string) ([]entity.Matricula, error)
+	SearchEnrollments(ctx context.Context, filter *entity.EnrollmentSearchFilter) (*entity.MatriculaListResponse, error)
 	GetEnrollmentByID(ctx context.Context, id string) (*entity.Matricula, error)
 	CreateEnrollment(ctx context.Context, req *entity.CreateMatriculaRequest) (*entity.Matricula, error)
 	UpdatePaymentStatus(ctx context.Context, id, status string) error
 	UpdateProgress(ctx context.Context, id string, progress float64) error
+	BulkCreateEnrollments(ctx context.Context, req *entity.BulkEnrollmentRequest) (*entity.BulkEnrollmentResponse, error)
+	TransferEnrollment(ctx context.Context, id string, req *entity.TransferEnrollmentRequest, transferredBy string) (*entity.TransferEnrollmentResponse, error)
+	ListTransfers(ctx context.Context, id string) ([]entity.EnrollmentTransfer, error)
 }
 
 type matriculaUseCase struct {
-	repo repository.MatriculaRepository
+	repo             repository.MatriculaRepository
+	courseRepo       repository.CourseRepository
+	revenueSplitRepo repository.RevenueSplitRepository
+	splitRules       revenue.SplitRuleUseCase
+	transferRepo     repository.EnrollmentTransferRepository
+	ledgerRepo       repository.InstructorLedgerRepository
+	db               *database.MySQL
 }
 
 // NewUseCase creates a new matricula use case
-func NewUseCase(repo repository.MatriculaRepository) UseCase {
-	return &matriculaUseCase{repo: repo}
+func NewUseCase(
+	repo repository.MatriculaRepository,
+	courseRepo repository.CourseRepository,
+	revenueSplitRepo repository.RevenueSplitRepository,
+	splitRules revenue.SplitRuleUseCase,
+	transferRepo repository.EnrollmentTransferRepository,
+	ledgerRepo repository.InstructorLedgerRepository,
+	db *database.MySQL,
+) UseCase {
+	return &matriculaUseCase{
+		repo:             repo,
+		courseRepo:       courseRepo,
+		revenueSplitRepo: revenueSplitRepo,
+		splitRules:       splitRules,
+		transferRepo:     transferRepo,
+		ledgerRepo:       ledgerRepo,
+		db:               db,
+	}
 }
 
-// ListEnrollments returns all enrollments with pagination
-func (uc *matriculaUseCase) ListEnrollments(ctx context.Context, page, perPage int) (*entity.MatriculaListResponse, error) {
+// ListEnrollments returns the enrollments matching the list query, with pagination
+func (uc *matriculaUseCase) ListEnrollments(ctx context.Context, page, perPage int, query listquery.Query) (*entity.MatriculaListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -40,7 +78,7 @@ func (uc *matriculaUseCase) ListEnrollments(ctx context.Context, page, perPage i
 		perPage = 100
 	}
 
-	enrollments, total, err := uc.repo.FindAll(ctx, page, perPage)
+	enrollments, total, err := uc.repo.FindAll(ctx, page, perPage, query)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +96,65 @@ func (uc *matriculaUseCase) ListEnrollmentsByStudent(ctx context.Context, studen
 	return uc.repo.FindByStudentID(ctx, studentID)
 }
 
+var (
+	searchStatuses = map[string]bool{
+		entity.EnrollmentStatusPending: true, entity.EnrollmentStatusActive: true, entity.EnrollmentStatusCompleted: true,
+		entity.EnrollmentStatusCancelled: true, entity.EnrollmentStatusCancelling: true, entity.EnrollmentStatusExpired: true,
+		entity.EnrollmentStatusRetrying: true,
+	}
+	searchPaymentStatuses = map[string]bool{
+		entity.PaymentStatusPending: true, entity.PaymentStatusConfirmed: true, entity.PaymentStatusFailed: true,
+		entity.PaymentStatusRefunded: true, entity.PaymentStatusOverdue: true, entity.PaymentStatusChargeback: true,
+		entity.PaymentStatusComped: true,
+	}
+)
+
+// SearchEnrollments finds enrollments by student name, email or CPF and course name. A search
+// needs terms or at least one filter, so it never lists every enrollment.
+func (uc *matriculaUseCase) SearchEnrollments(ctx context.Context, filter *entity.EnrollmentSearchFilter) (*entity.MatriculaListResponse, error) {
+	filter.Terms = strings.TrimSpace(filter.Terms)
+	if filter.Terms != "" && len([]rune(filter.Terms)) < entity.MinEnrollmentSearchLength {
+		return nil, fmt.Errorf("invalid search: q must have at least %d characters", entity.MinEnrollmentSearchLength)
+	}
+	if filter.Terms == "" && len(filter.Statuses) == 0 && len(filter.PaymentStatuses) == 0 && filter.CourseID == nil {
+		return nil, errors.New("invalid search: q or a filter is required")
+	}
+	for _, status := range filter.Statuses {
+		if !searchStatuses[status] {
+			return nil, fmt.Errorf("invalid status %q", status)
+		}
+	}
+	for _, status := range filter.PaymentStatuses {
+		if !searchPaymentStatuses[status] {
+			return nil, fmt.Errorf("invalid payment_status %q", status)
+		}
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PerPage < 1 {
+		filter.PerPage = 10
+	}
+	if filter.PerPage > 100 {
+		filter.PerPage = 100
+	}
+
+	enrollments, total, err := uc.repo.Search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if enrollments == nil {
+		enrollments = []entity.Matricula{}
+	}
+
+	return &entity.MatriculaListResponse{
+		Enrollments: enrollments,
+		Total:       total,
+		Page:        filter.Page,
+		PerPage:     filter.PerPage,
+	}, nil
+}
+
 // GetEnrollmentByID returns a specific enrollment by ID
 func (uc *matriculaUseCase) GetEnrollmentByID(ctx context.Context, id string) (*entity.Matricula, error) {
 	return uc.repo.FindByID(ctx, id)
@@ -65,6 +162,15 @@ func (uc *matriculaUseCase) GetEnrollmentByID(ctx context.Context, id string) (*
 
 // CreateEnrollment creates a new enrollment
 func (uc *matriculaUseCase) CreateEnrollment(ctx context.Context, req *entity.CreateMatriculaRequest) (*entity.Matricula, error) {
+	cpf, err := normalizeStudentCPF("student_cpf", req.StudentCPF)
+	if err != nil {
+		return nil, err
+	}
+	req.StudentCPF = cpf
+	if req.StudentPhone, err = normalizeStudentPhone("student_phone", req.StudentPhone); err != nil {
+		return nil, err
+	}
+
 	// Calculate final amount
 	finalAmount := req.Amount - req.DiscountAmount
 	if finalAmount < 0 {
@@ -110,3 +216,479 @@ func (uc *matriculaUseCase) UpdatePaymentStatus(ctx context.Context, id, status
 func (uc *matriculaUseCase) UpdateProgress(ctx context.Context, id string, progress float64) error {
 	return uc.repo.UpdateProgress(ctx, id, progress)
 }
+
+// BulkCreateEnrollments enrolls a list of students in a course without charging them.
+// Enrollments are created as active with payment status "comped"; students already
+// enrolled in the course (or repeated in the request) are skipped. All inserts run in
+// a single transaction so a failed batch leaves no partial seats behind.
+func (uc *matriculaUseCase) BulkCreateEnrollments(ctx context.Context, req *entity.BulkEnrollmentRequest) (*entity.BulkEnrollmentResponse, error) {
+	if len(req.Students) == 0 {
+		return nil, errors.New("no students provided")
+	}
+	if len(req.Students) > entity.MaxBulkEnrollmentStudents {
+		return nil, fmt.Errorf("too many students: maximum is %d per request", entity.MaxBulkEnrollmentStudents)
+	}
+
+	for i := range req.Students {
+		cpf, err := normalizeStudentCPF("student_cpf", req.Students[i].StudentCPF)
+		if err != nil {
+			return nil, fmt.Errorf("%w (student %s)", err, req.Students[i].StudentID)
+		}
+		req.Students[i].StudentCPF = cpf
+		if req.Students[i].StudentPhone, err = normalizeStudentPhone("student_phone", req.Students[i].StudentPhone); err != nil {
+			return nil, fmt.Errorf("%w (student %s)", err, req.Students[i].StudentID)
+		}
+	}
+
+	course, err := uc.courseRepo.FindByID(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	if course == nil {
+		return nil, errors.New("course not found")
+	}
+
+	existing, err := uc.repo.FindByCourseID(ctx, course.ID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		if m.Status != entity.EnrollmentStatusCancelled {
+			enrolled[m.StudentID] = true
+		}
+	}
+
+	result := &entity.BulkEnrollmentResponse{
+		CourseID:    course.ID,
+		Enrollments: []entity.Matricula{},
+		Skipped:     []entity.BulkEnrollmentSkipped{},
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, student := range req.Students {
+		if enrolled[student.StudentID] {
+			result.Skipped = append(result.Skipped, entity.BulkEnrollmentSkipped{
+				StudentID:    student.StudentID,
+				StudentEmail: student.StudentEmail,
+				Reason:       "already enrolled",
+			})
+			continue
+		}
+
+		enrollment := entity.Matricula{
+			ID:             uuid.New().String(),
+			StudentID:      student.StudentID,
+			StudentName:    student.StudentName,
+			StudentEmail:   student.StudentEmail,
+			StudentCPF:     student.StudentCPF,
+			StudentPhone:   student.StudentPhone,
+			CourseID:       course.ID,
+			CourseName:     course.Name,
+			InstructorID:   course.InstructorID,
+			InstructorName: course.InstructorName,
+			PaymentStatus:  entity.PaymentStatusComped,
+			Amount:         course.Price,
+			DiscountAmount: course.Price,
+			FinalAmount:    0,
+			EnrollmentDate: now,
+			Status:         entity.EnrollmentStatusActive,
+			Progress:       0,
+			CreatedAt:      now,
+		}
+
+		if err := uc.repo.CreateWithTx(ctx, tx, &enrollment); err != nil {
+			return nil, err
+		}
+
+		enrolled[student.StudentID] = true
+		result.Enrollments = append(result.Enrollments, enrollment)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	result.TotalCreated = len(result.Enrollments)
+	result.TotalSkipped = len(result.Skipped)
+	return result, nil
+}
+
+// TransferEnrollment moves an enrollment to another course and/or another student.
+// When the course changes, paid seats keep the original discount and may move to a
+// course with the same or a lower final price. The difference to a cheaper course is
+// credited to the student (the negative PriceDifference shown in the student statement)
+// and taken out of the revenue splits; charging the difference to a pricier course is not
+// supported and needs a manual adjustment. Every pending revenue split (one per paid
+// installment) is re-allocated with the split rules of the target course so the new
+// instructor receives the share. Processed splits have already been paid out and cannot
+// be adjusted automatically. Every transfer is recorded in the enrollment transfer history.
+func (uc *matriculaUseCase) TransferEnrollment(ctx context.Context, id string, req *entity.TransferEnrollmentRequest, transferredBy string) (*entity.TransferEnrollmentResponse, error) {
+	enrollment, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, errors.New("enrollment not found")
+	}
+	if enrollment.Status == entity.EnrollmentStatusCancelled || enrollment.Status == entity.EnrollmentStatusCancelling ||
+		enrollment.Status == entity.EnrollmentStatusCompleted || enrollment.Status == entity.EnrollmentStatusRetrying {
+		return nil, errors.New("enrollment cannot be transferred in its current status")
+	}
+
+	changeCourse := req.ToCourseID != nil && *req.ToCourseID != "" && *req.ToCourseID != enrollment.CourseID
+	changeStudent := req.ToStudentID != nil && *req.ToStudentID != "" && *req.ToStudentID != enrollment.StudentID
+	if !changeCourse && !changeStudent {
+		return nil, errors.New("transfer requires a different course or student")
+	}
+	if changeStudent && (req.ToStudentName == nil || *req.ToStudentName == "" || req.ToStudentEmail == nil || *req.ToStudentEmail == "") {
+		return nil, errors.New("to_student_name and to_student_email are required when transferring to another student")
+	}
+	if changeStudent {
+		cpf, err := normalizeStudentCPF("to_student_cpf", req.ToStudentCPF)
+		if err != nil {
+			return nil, err
+		}
+		req.ToStudentCPF = cpf
+		if req.ToStudentPhone, err = normalizeStudentPhone("to_student_phone", req.ToStudentPhone); err != nil {
+			return nil, err
+		}
+	}
+
+	transfer := &entity.EnrollmentTransfer{
+		ID:              uuid.New().String(),
+		EnrollmentID:    enrollment.ID,
+		FromStudentID:   enrollment.StudentID,
+		FromStudentName: enrollment.StudentName,
+		ToStudentID:     enrollment.StudentID,
+		ToStudentName:   enrollment.StudentName,
+		FromCourseID:    enrollment.CourseID,
+		FromCourseName:  enrollment.CourseName,
+		ToCourseID:      enrollment.CourseID,
+		ToCourseName:    enrollment.CourseName,
+		FromAmount:      enrollment.FinalAmount,
+		ToAmount:        enrollment.FinalAmount,
+		Reason:          req.Reason,
+		CreatedAt:       time.Now(),
+	}
+	if transferredBy != "" {
+		transfer.TransferredBy = &transferredBy
+	}
+
+	switch {
+	case changeCourse && changeStudent:
+		transfer.TransferType = entity.TransferTypeBoth
+	case changeCourse:
+		transfer.TransferType = entity.TransferTypeCourse
+	default:
+		transfer.TransferType = entity.TransferTypeStudent
+	}
+
+	if changeStudent {
+		enrollment.StudentID = *req.ToStudentID
+		enrollment.StudentName = *req.ToStudentName
+		enrollment.StudentEmail = *req.ToStudentEmail
+		enrollment.StudentCPF = req.ToStudentCPF
+		enrollment.StudentPhone = req.ToStudentPhone
+		transfer.ToStudentID = enrollment.StudentID
+		transfer.ToStudentName = enrollment.StudentName
+	}
+
+	// Avoid enrolling the (possibly new) student twice in the (possibly new) course
+	targetCourseID := enrollment.CourseID
+	if changeCourse {
+		targetCourseID = *req.ToCourseID
+	}
+	existing, err := uc.repo.FindByCourseID(ctx, targetCourseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range existing {
+		if m.StudentID == enrollment.StudentID && m.ID != enrollment.ID && m.Status != entity.EnrollmentStatusCancelled {
+			return nil, errors.New("student is already enrolled in the target course")
+		}
+	}
+
+	var splits []entity.RevenueSplit
+	var ledgerEntries []*entity.InstructorLedgerEntry
+	if changeCourse {
+		course, err := uc.courseRepo.FindByID(ctx, *req.ToCourseID)
+		if err != nil {
+			return nil, err
+		}
+		if course == nil {
+			return nil, errors.New("course not found")
+		}
+		if !course.IsActive {
+			return nil, errors.New("target course is not active")
+		}
+
+		enrollment.CourseID = course.ID
+		enrollment.CourseName = course.Name
+		enrollment.InstructorID = course.InstructorID
+		enrollment.InstructorName = course.InstructorName
+
+		// Comped seats stay free; paid seats are re-priced from the target course, which may
+		// cost less (the difference becomes a credit) but not more
+		var credit money.Cents
+		if enrollment.PaymentStatus != entity.PaymentStatusComped {
+			amount := course.EffectivePrice()
+			finalAmount := roundCents(math.Max(amount-enrollment.DiscountAmount, 0))
+			if finalAmount > enrollment.FinalAmount {
+				return nil, errors.New("target course price differs from the enrollment amount; charging the difference requires a manual adjustment")
+			}
+			if finalAmount < enrollment.FinalAmount && enrollment.PaymentStatus != entity.PaymentStatusConfirmed {
+				return nil, errors.New("only paid enrollments can be transferred to a cheaper course")
+			}
+			credit = money.FromFloat(enrollment.FinalAmount - finalAmount)
+			enrollment.Amount = amount
+			enrollment.FinalAmount = finalAmount
+		}
+
+		transfer.ToCourseID = course.ID
+		transfer.ToCourseName = course.Name
+		transfer.ToAmount = enrollment.FinalAmount
+		transfer.PriceDifference = roundCents(transfer.ToAmount - transfer.FromAmount)
+
+		// Every split moves: one per paid installment, and those of renewals
+		splits, err = uc.revenueSplitRepo.FindAllByEnrollmentID(ctx, enrollment.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range splits {
+			if splits[i].Status == entity.RevenueSplitStatusProcessed {
+				return nil, errors.New("revenue split already processed; transfer requires a manual adjustment")
+			}
+		}
+
+		// The share moves in the ledger too: taken from the original instructor, credited
+		// again to the instructor of the target course
+		now := time.Now()
+		description := "Transferência de curso - matrícula " + enrollment.ID
+		var fromInstructor, toInstructor money.Cents
+		credits := entity.ProrateRefund(splits, credit)
+		for i := range splits {
+			split := &splits[i]
+			split.Parties, err = uc.revenueSplitRepo.FindParties(ctx, split.ID)
+			if err != nil {
+				return nil, err
+			}
+			previous := split.InstructorAmount
+			// The credit is no longer revenue: it comes out of what the split received
+			split.GrossAmount -= credits[i]
+			split.NetAmount = max(split.NetAmount-credits[i], 0)
+			if split.Status == entity.RevenueSplitStatusPending {
+				ledgerEntries = append(ledgerEntries, entity.NewSplitLedgerEntry(split, entity.LedgerEntryAdjustment,
+					split.EventID(transfer.ID+":from"), -previous, description, now))
+			}
+			if err := uc.recalculateSplit(ctx, split, enrollment); err != nil {
+				return nil, err
+			}
+			if split.Status == entity.RevenueSplitStatusPending {
+				ledgerEntries = append(ledgerEntries, entity.NewSplitLedgerEntry(split, entity.LedgerEntryAdjustment,
+					split.EventID(transfer.ID+":to"), split.InstructorAmount, description, now))
+			}
+			fromInstructor += previous
+			toInstructor += split.InstructorAmount
+		}
+		if len(splits) > 0 {
+			transfer.RevenueSplitID = &splits[0].ID
+			fromAmount, toAmount := fromInstructor.Float(), toInstructor.Float()
+			transfer.FromInstructorAmount = &fromAmount
+			transfer.ToInstructorAmount = &toAmount
+		}
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := uc.repo.UpdateWithTx(ctx, tx, enrollment); err != nil {
+		return nil, err
+	}
+	for i := range splits {
+		if err := uc.revenueSplitRepo.UpdateAmountsWithTx(ctx, tx, &splits[i]); err != nil {
+			return nil, err
+		}
+		if err := uc.revenueSplitRepo.ReplacePartiesWithTx(ctx, tx, &splits[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := uc.transferRepo.CreateWithTx(ctx, tx, transfer); err != nil {
+		return nil, err
+	}
+	for _, entry := range ledgerEntries {
+		if entry == nil {
+			continue
+		}
+		entry.ID = uuid.New().String()
+		if err := uc.ledgerRepo.CreateWithTx(ctx, tx, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.repo.FindByID(ctx, enrollment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.TransferEnrollmentResponse{
+		Enrollment:    updated,
+		Transfer:      transfer,
+		RevenueSplits: splits,
+	}
+	if len(splits) > 0 {
+		result.RevenueSplit = &splits[0]
+	}
+	return result, nil
+}
+
+// ListTransfers returns the transfer history of an enrollment
+func (uc *matriculaUseCase) ListTransfers(ctx context.Context, id string) ([]entity.EnrollmentTransfer, error) {
+	enrollment, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, errors.New("enrollment not found")
+	}
+	return uc.transferRepo.FindByEnrollmentID(ctx, id)
+}
+
+// recalculateSplit re-allocates a pending revenue split after a course change. The split
+// keeps the amounts that were paid, fee included; the new instructor and the split rules
+// of the target course decide the shares, as for a split created by the payment webhook.
+func (uc *matriculaUseCase) recalculateSplit(ctx context.Context, split *entity.RevenueSplit, enrollment *entity.Matricula) error {
+	split.InstructorID = enrollment.InstructorID
+	return uc.splitRules.Allocate(ctx, split, enrollment.CourseID)
+}
+
+// normalizeStudentCPF returns the digits of an optional student CPF, or an error naming the
+// field when its check digits do not match. An empty CPF is left out.
+func normalizeStudentCPF(field string, cpf *string) (*string, error) {
+	if cpf == nil || strings.TrimSpace(*cpf) == "" {
+		return nil, nil
+	}
+	digits, err := document.NormalizeCPF(*cpf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %q is not a valid CPF", field, *cpf)
+	}
+	return &digits, nil
+}
+
+// normalizeStudentPhone returns an optional student phone in E.164, or an error naming the field
+// when it is not a phone number. An empty phone is left out.
+func normalizeStudentPhone(field string, p *string) (*string, error) {
+	if p == nil || strings.TrimSpace(*p) == "" {
+		return nil, nil
+	}
+	n, err := phone.Normalize(*p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %q is not a phone number with area code", field, *p)
+	}
+	return &n, nil
+}
+
+// roundCents rounds a monetary value to 2 decimal places
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// ParseBulkEnrollmentCSV reads students from a CSV file with a header row.
+// Required columns: student_id, student_name, student_email. Optional columns:
+// student_cpf, student_phone. Column order does not matter.
+func ParseBulkEnrollmentCSV(r io.Reader) ([]entity.BulkEnrollmentStudent, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv file is empty")
+		}
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"student_id", "student_name", "student_email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv is missing required column %q", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		idx, ok := columns[name]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+	optional := func(record []string, name string) *string {
+		if v := field(record, name); v != "" {
+			return &v
+		}
+		return nil
+	}
+
+	var students []entity.BulkEnrollmentStudent
+	line := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv at line %d: %w", line, err)
+		}
+
+		student := entity.BulkEnrollmentStudent{
+			StudentID:    field(record, "student_id"),
+			StudentName:  field(record, "student_name"),
+			StudentEmail: field(record, "student_email"),
+			StudentCPF:   optional(record, "student_cpf"),
+			StudentPhone: optional(record, "student_phone"),
+		}
+		if student.StudentID == "" && student.StudentName == "" && student.StudentEmail == "" {
+			continue // blank line
+		}
+		if student.StudentID == "" || student.StudentName == "" || student.StudentEmail == "" {
+			return nil, fmt.Errorf("csv line %d: student_id, student_name and student_email are required", line)
+		}
+		if !strings.Contains(student.StudentEmail, "@") {
+			return nil, fmt.Errorf("csv line %d: invalid student_email", line)
+		}
+		if student.StudentCPF, err = normalizeStudentCPF("student_cpf", student.StudentCPF); err != nil {
+			return nil, fmt.Errorf("csv line %d: %w", line, err)
+		}
+		if student.StudentPhone, err = normalizeStudentPhone("student_phone", student.StudentPhone); err != nil {
+			return nil, fmt.Errorf("csv line %d: %w", line, err)
+		}
+
+		students = append(students, student)
+		if len(students) > entity.MaxBulkEnrollmentStudents {
+			return nil, fmt.Errorf("too many students: maximum is %d per request", entity.MaxBulkEnrollmentStudents)
+		}
+	}
+
+	if len(students) == 0 {
+		return nil, errors.New("no students provided")
+	}
+	return students, nil
+}
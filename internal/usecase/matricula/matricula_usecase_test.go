@@ -0,0 +1,205 @@
+package matricula
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/internal/usecase/revenue"
+)
+
+func TestParseBulkEnrollmentCSV_Success(t *testing.T) {
+	input := "student_email,student_name,student_id,student_phone\n" +
+		"ana@example.com,Ana Souza,stu-1,11999990000\n" +
+		"\n" +
+		"bruno@example.com, Bruno Lima ,stu-2,\n"
+
+	students, err := ParseBulkEnrollmentCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(students) != 2 {
+		t.Fatalf("expected 2 students, got %d", len(students))
+	}
+	if students[0].StudentID != "stu-1" || students[0].StudentEmail != "ana@example.com" {
+		t.Errorf("unexpected first student: %+v", students[0])
+	}
+	if students[0].StudentPhone == nil || *students[0].StudentPhone != "+5511999990000" {
+		t.Error("expected phone to be parsed for first student")
+	}
+	if students[1].StudentName != "Bruno Lima" {
+		t.Errorf("expected trimmed name 'Bruno Lima', got %q", students[1].StudentName)
+	}
+	if students[1].StudentPhone != nil {
+		t.Error("expected empty phone to be nil")
+	}
+}
+
+func TestParseBulkEnrollmentCSV_MissingColumn(t *testing.T) {
+	_, err := ParseBulkEnrollmentCSV(strings.NewReader("student_id,student_name\nstu-1,Ana\n"))
+	if err == nil {
+		t.Fatal("expected error for missing student_email column")
+	}
+}
+
+func TestParseBulkEnrollmentCSV_MissingField(t *testing.T) {
+	input := "student_id,student_name,student_email\nstu-1,,ana@example.com\n"
+	_, err := ParseBulkEnrollmentCSV(strings.NewReader(input))
+	if err == nil {
+		t.Fatal("expected error for row without student_name")
+	}
+}
+
+func TestParseBulkEnrollmentCSV_StudentCPF(t *testing.T) {
+	input := "student_id,student_name,student_email,student_cpf\nstu-1,Ana,ana@example.com,529.982.247-25\n"
+	students, err := ParseBulkEnrollmentCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if students[0].StudentCPF == nil || *students[0].StudentCPF != "52998224725" {
+		t.Errorf("expected the CPF digits, got %v", students[0].StudentCPF)
+	}
+
+	input = "student_id,student_name,student_email,student_cpf\nstu-1,Ana,ana@example.com,529.982.247-26\n"
+	if _, err := ParseBulkEnrollmentCSV(strings.NewReader(input)); err == nil || !strings.HasPrefix(err.Error(), "csv line 2: invalid student_cpf") {
+		t.Errorf("expected an invalid CPF error on line 2, got %v", err)
+	}
+}
+
+func TestParseBulkEnrollmentCSV_StudentPhone(t *testing.T) {
+	input := "student_id,student_name,student_email,student_phone\nstu-1,Ana,ana@example.com,(11) 8765-4321\n"
+	if _, err := ParseBulkEnrollmentCSV(strings.NewReader(input)); err == nil || !strings.HasPrefix(err.Error(), "csv line 2: invalid student_phone") {
+		t.Errorf("expected an invalid phone error on line 2, got %v", err)
+	}
+}
+
+func TestParseBulkEnrollmentCSV_Empty(t *testing.T) {
+	if _, err := ParseBulkEnrollmentCSV(strings.NewReader("")); err == nil {
+		t.Fatal("expected error for empty file")
+	}
+	if _, err := ParseBulkEnrollmentCSV(strings.NewReader("student_id,student_name,student_email\n")); err == nil {
+		t.Fatal("expected error for header-only file")
+	}
+}
+
+func TestSearchEnrollments_Validation(t *testing.T) {
+	uc := &matriculaUseCase{}
+	courseID := "course-1"
+
+	cases := []struct {
+		name   string
+		filter entity.EnrollmentSearchFilter
+		want   string
+	}{
+		{"too short", entity.EnrollmentSearchFilter{Terms: " a "}, "at least"},
+		{"nothing to search", entity.EnrollmentSearchFilter{Terms: "  "}, "q or a filter"},
+		{"unknown status", entity.EnrollmentSearchFilter{Terms: "ana", Statuses: []string{"archived"}}, "invalid status"},
+		{"unknown payment status", entity.EnrollmentSearchFilter{CourseID: &courseID, PaymentStatuses: []string{"paid"}}, "invalid payment_status"},
+	}
+	for _, tc := range cases {
+		_, err := uc.SearchEnrollments(context.Background(), &tc.filter)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestTransferEnrollment_RejectsPriceChange(t *testing.T) {
+	enrollments := testutil.NewMockMatriculaRepository()
+	enrollments.Enrollments["e1"] = &entity.Matricula{ID: "e1", CourseID: "c1", StudentID: "s1",
+		Status: entity.EnrollmentStatusActive, PaymentStatus: entity.PaymentStatusConfirmed,
+		Amount: 500, DiscountAmount: 50, FinalAmount: 450}
+	courses := testutil.NewMockCourseRepository(&entity.Course{ID: "c2", Price: 800, IsActive: true})
+	uc := &matriculaUseCase{repo: enrollments, courseRepo: courses}
+
+	to := "c2"
+	_, err := uc.TransferEnrollment(context.Background(), "e1", &entity.TransferEnrollmentRequest{ToCourseID: &to}, "admin-1")
+	if err == nil || !strings.Contains(err.Error(), "price differs") {
+		t.Fatalf("expected a transfer to a course with another price to be refused, got %v", err)
+	}
+	if e := enrollments.Enrollments["e1"]; e.CourseID != "c1" || e.FinalAmount != 450 {
+		t.Errorf("expected the enrollment to be kept, got %+v", e)
+	}
+}
+
+func TestTransferEnrollment_CheaperCourseRequiresPayment(t *testing.T) {
+	enrollments := testutil.NewMockMatriculaRepository()
+	enrollments.Enrollments["e1"] = &entity.Matricula{ID: "e1", CourseID: "c1", StudentID: "s1",
+		Status: entity.EnrollmentStatusPending, PaymentStatus: entity.PaymentStatusPending,
+		Amount: 500, DiscountAmount: 50, FinalAmount: 450}
+	courses := testutil.NewMockCourseRepository(&entity.Course{ID: "c2", Price: 300, IsActive: true})
+	uc := &matriculaUseCase{repo: enrollments, courseRepo: courses}
+
+	to := "c2"
+	_, err := uc.TransferEnrollment(context.Background(), "e1", &entity.TransferEnrollmentRequest{ToCourseID: &to}, "admin-1")
+	if err == nil || !strings.Contains(err.Error(), "only paid enrollments") {
+		t.Fatalf("expected a cheaper course to need a paid enrollment to credit, got %v", err)
+	}
+}
+
+func TestTransferEnrollment_RejectsDuplicateStudent(t *testing.T) {
+	enrollments := testutil.NewMockMatriculaRepository()
+	enrollments.Enrollments["e1"] = &entity.Matricula{ID: "e1", CourseID: "c1", StudentID: "s1",
+		Status: entity.EnrollmentStatusActive, PaymentStatus: entity.PaymentStatusConfirmed, FinalAmount: 450}
+	enrollments.Enrollments["e2"] = &entity.Matricula{ID: "e2", CourseID: "c1", StudentID: "s2",
+		Status: entity.EnrollmentStatusActive, PaymentStatus: entity.PaymentStatusConfirmed, FinalAmount: 450}
+	uc := &matriculaUseCase{repo: enrollments}
+
+	to, name, email := "s2", "Bia", "bia@example.com"
+	_, err := uc.TransferEnrollment(context.Background(), "e1", &entity.TransferEnrollmentRequest{
+		ToStudentID: &to, ToStudentName: &name, ToStudentEmail: &email}, "admin-1")
+	if err == nil || !strings.Contains(err.Error(), "already enrolled") {
+		t.Fatalf("expected a seat swap to a student already in the course to be refused, got %v", err)
+	}
+	if e := enrollments.Enrollments["e1"]; e.StudentID != "s1" {
+		t.Errorf("expected the enrollment to be kept, got %+v", e)
+	}
+}
+
+// stubSplitRules allocates every split with the rules given, as the rule resolver of the course would
+type stubSplitRules struct {
+	revenue.SplitRuleUseCase
+	rules    []entity.SplitRule
+	courseID string
+}
+
+func (s *stubSplitRules) Allocate(ctx context.Context, split *entity.RevenueSplit, courseID string) error {
+	s.courseID = courseID
+	parties, err := entity.AllocateSplit(split.NetAmount, s.rules, split.InstructorID)
+	if err != nil {
+		return err
+	}
+	split.ApplyParties(parties)
+	return nil
+}
+
+func TestRecalculateSplit_KeepsPaidAmounts(t *testing.T) {
+	rules := &stubSplitRules{rules: []entity.SplitRule{
+		{PartyType: entity.SplitPartyInstructor, Percent: 60},
+		{PartyType: entity.SplitPartyPlatform, Percent: 40},
+	}}
+	uc := &matriculaUseCase{splitRules: rules}
+
+	oldInstructor, newInstructor := "inst-1", "inst-2"
+	split := &entity.RevenueSplit{ID: "rs1", GrossAmount: 45000, PaymentFee: 1000, NetAmount: 44000,
+		InstructorAmount: 30800, PlatformAmount: 13200, InstructorID: &oldInstructor}
+	enrollment := &entity.Matricula{ID: "e1", CourseID: "c2", InstructorID: &newInstructor, FinalAmount: 450}
+
+	if err := uc.recalculateSplit(context.Background(), split, enrollment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules.courseID != "c2" {
+		t.Errorf("expected the rules of the target course, got %q", rules.courseID)
+	}
+	if split.GrossAmount != 45000 || split.PaymentFee != 1000 || split.NetAmount != 44000 {
+		t.Errorf("expected the paid amounts to be kept, got gross %d fee %d net %d", split.GrossAmount, split.PaymentFee, split.NetAmount)
+	}
+	if split.InstructorAmount != 26400 || split.PlatformAmount != 17600 {
+		t.Errorf("expected the shares of the target course, got %d / %d", split.InstructorAmount, split.PlatformAmount)
+	}
+	if split.InstructorID == nil || *split.InstructorID != newInstructor || len(split.Parties) != 2 || *split.Parties[0].PartyID != newInstructor {
+		t.Errorf("expected the instructor of the target course to receive the share, got %+v", split.Parties)
+	}
+}
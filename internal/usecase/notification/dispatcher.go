@@ -0,0 +1,365 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/google/uuid"
+)
+
+const (
+	// sendTimeout bounds one delivery attempt
+	sendTimeout = 30 * time.Second
+	// claimTimeout is how long a claimed delivery stays with its dispatcher before another
+	// instance takes it over, e.g. after a crash
+	claimTimeout = 5 * time.Minute
+	// maxDeliveryError is the longest error stored on a delivery
+	maxDeliveryError = 500
+)
+
+// ErrPushSubscriptionNotFound is returned for push subscriptions that do not exist or belong
+// to another user
+var ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+
+// DispatcherConfig holds the retry policy of outbound deliveries
+type DispatcherConfig struct {
+	MaxAttempts int           // attempts before a delivery fails
+	BaseDelay   time.Duration // wait after the first failure, doubled after each one
+	MaxDelay    time.Duration
+	BatchSize   int // deliveries claimed at a time
+}
+
+// DispatchResult counts the outcomes of a dispatch run
+type DispatchResult struct {
+	Sent    int `json:"sent"`
+	Retried int `json:"retried"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// PushKeyer is implemented by push channels, whose public key browsers need to subscribe
+type PushKeyer interface {
+	PublicKey() string
+}
+
+// Dispatcher delivers notifications on the outbound channels the user turned on. Deliveries
+// are queued in the database when a notification is created and sent by a background worker,
+// so a slow or failing provider never delays the request that created the notification.
+type Dispatcher struct {
+	deliveries    repository.NotificationDeliveryRepository
+	pushSubs      repository.PushSubscriptionRepository
+	notifications repository.NotificacaoRepository
+	users         repository.UserRepository
+	channels      map[string]notify.Channel
+	cfg           DispatcherConfig
+	now           func() time.Time
+}
+
+// NewDispatcher creates a dispatcher delivering on the given channels; channels that are not
+// configured on this server are left out and reported unavailable to users
+func NewDispatcher(
+	deliveries repository.NotificationDeliveryRepository,
+	pushSubs repository.PushSubscriptionRepository,
+	notifications repository.NotificacaoRepository,
+	users repository.UserRepository,
+	cfg DispatcherConfig,
+	channels ...notify.Channel,
+) *Dispatcher {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 50
+	}
+	byName := make(map[string]notify.Channel, len(channels))
+	for _, ch := range channels {
+		byName[ch.Name()] = ch
+	}
+	return &Dispatcher{
+		deliveries:    deliveries,
+		pushSubs:      pushSubs,
+		notifications: notifications,
+		users:         users,
+		channels:      byName,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+// Enqueue queues the deliveries of a stored notification on the channels of its user
+func (d *Dispatcher) Enqueue(ctx context.Context, n *entity.Notificacao) error {
+	prefs, err := d.ChannelPreferences(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+
+	now := d.now()
+	var deliveries []entity.NotificationDelivery
+	for _, p := range prefs {
+		if !p.Enabled || !p.Available {
+			continue
+		}
+		deliveries = append(deliveries, entity.NotificationDelivery{
+			ID:             uuid.New().String(),
+			NotificationID: n.ID,
+			UserID:         n.UserID,
+			Channel:        p.Channel,
+			Status:         entity.NotificationDeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return d.deliveries.CreateDeliveries(ctx, deliveries)
+}
+
+// ChannelPreferences returns every channel with whether the user receives notifications on
+// it and whether it is configured on this server
+func (d *Dispatcher) ChannelPreferences(ctx context.Context, userID string) ([]entity.NotificationChannelPreference, error) {
+	stored, err := d.deliveries.FindChannelPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byChannel := make(map[string]entity.NotificationChannelPreference, len(stored))
+	for _, p := range stored {
+		byChannel[p.Channel] = p
+	}
+
+	prefs := make([]entity.NotificationChannelPreference, 0, len(entity.NotificationChannels))
+	for _, def := range entity.NotificationChannels {
+		p, ok := byChannel[def.Channel]
+		if !ok {
+			p = def
+			p.UserID = userID
+		}
+		_, p.Available = d.channels[p.Channel]
+		prefs = append(prefs, p)
+	}
+	return prefs, nil
+}
+
+// UpdateChannelPreferences turns channels on or off; channels left out keep their setting
+func (d *Dispatcher) UpdateChannelPreferences(ctx context.Context, userID string, req *entity.UpdateNotificationChannelsRequest) ([]entity.NotificationChannelPreference, error) {
+	prefs := make([]entity.NotificationChannelPreference, 0, len(req.Channels))
+	for ch, enabled := range req.Channels {
+		if !entity.IsNotificationChannel(ch) {
+			return nil, fmt.Errorf("invalid notification channel: %s", ch)
+		}
+		prefs = append(prefs, entity.NotificationChannelPreference{UserID: userID, Channel: ch, Enabled: enabled})
+	}
+	if err := d.deliveries.SaveChannelPreferences(ctx, userID, prefs); err != nil {
+		return nil, err
+	}
+	return d.ChannelPreferences(ctx, userID)
+}
+
+// SubscribePush registers a browser for the web push notifications of the user
+func (d *Dispatcher) SubscribePush(ctx context.Context, userID string, req *entity.CreatePushSubscriptionRequest, userAgent string) (*entity.PushSubscription, error) {
+	if req.Keys.P256dh == "" || req.Keys.Auth == "" {
+		return nil, errors.New("invalid push subscription: keys.p256dh and keys.auth are required")
+	}
+	sub := &entity.PushSubscription{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+		CreatedAt: d.now(),
+	}
+	if userAgent != "" {
+		if len(userAgent) > 255 {
+			userAgent = userAgent[:255]
+		}
+		sub.UserAgent = &userAgent
+	}
+	if err := d.pushSubs.Save(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// UnsubscribePush removes a push subscription of the user
+func (d *Dispatcher) UnsubscribePush(ctx context.Context, userID, id string) error {
+	deleted, err := d.pushSubs.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPushSubscriptionNotFound
+	}
+	return nil
+}
+
+// PushPublicKey returns the VAPID public key browsers subscribe with, empty when web push is
+// not configured
+func (d *Dispatcher) PushPublicKey() string {
+	if keyer, ok := d.channels[entity.NotificationChannelPush].(PushKeyer); ok {
+		return keyer.PublicKey()
+	}
+	return ""
+}
+
+// Receipts returns the state of the outbound deliveries of a notification, in channel order
+func (d *Dispatcher) Receipts(ctx context.Context, notificationID string) ([]entity.NotificationReceipt, error) {
+	deliveries, err := d.deliveries.FindByNotificationID(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	byChannel := make(map[string]entity.NotificationDelivery, len(deliveries))
+	for _, del := range deliveries {
+		byChannel[del.Channel] = del
+	}
+
+	var receipts []entity.NotificationReceipt
+	for _, def := range entity.NotificationChannels {
+		del, ok := byChannel[def.Channel]
+		if !ok {
+			continue
+		}
+		receipts = append(receipts, entity.NotificationReceipt{
+			Channel:     del.Channel,
+			Status:      del.Status,
+			SentAt:      del.SentAt,
+			DeliveredAt: del.DeliveredAt,
+			ReadAt:      del.OpenedAt,
+		})
+	}
+	return receipts, nil
+}
+
+// RecordOpen records that the e-mail of a notification was opened
+func (d *Dispatcher) RecordOpen(ctx context.Context, notificationID string) error {
+	return d.deliveries.MarkOpened(ctx, notificationID, entity.NotificationChannelEmail, d.now())
+}
+
+// ConfirmPush records that a browser of the user received the push of a notification
+func (d *Dispatcher) ConfirmPush(ctx context.Context, notificationID string) error {
+	return d.deliveries.MarkDelivered(ctx, notificationID, entity.NotificationChannelPush, d.now())
+}
+
+// Start dispatches the due deliveries every interval until shutdown
+func (d *Dispatcher) Start(lc *lifecycle.Manager, interval time.Duration) {
+	if len(d.channels) == 0 {
+		return
+	}
+	lc.Every("notification dispatch", interval, true, func(ctx context.Context) {
+		result, err := d.DispatchDue(ctx)
+		if err != nil {
+			log.Printf("[NOTIFICATION] Dispatch failed: %v", err)
+		}
+		if result.Sent > 0 || result.Retried > 0 || result.Failed > 0 {
+			log.Printf("[NOTIFICATION] Sent %d deliveries, %d to retry, %d failed, %d skipped",
+				result.Sent, result.Retried, result.Failed, result.Skipped)
+		}
+	})
+}
+
+// DispatchDue sends the deliveries whose attempt is due, batch after batch until none is left
+func (d *Dispatcher) DispatchDue(ctx context.Context) (*DispatchResult, error) {
+	result := &DispatchResult{}
+	for ctx.Err() == nil {
+		now := d.now()
+		batch, err := d.deliveries.ClaimDue(ctx, now, now.Add(-claimTimeout), d.cfg.BatchSize)
+		if err != nil {
+			return result, err
+		}
+		users := make(map[string]*entity.User)
+		for i := range batch {
+			d.attempt(ctx, &batch[i], users, result)
+		}
+		if len(batch) < d.cfg.BatchSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// attempt sends one claimed delivery and records the outcome; users caches the recipients
+// of the batch
+func (d *Dispatcher) attempt(ctx context.Context, delivery *entity.NotificationDelivery, users map[string]*entity.User, result *DispatchResult) {
+	err := d.send(ctx, delivery, users)
+	now := d.now()
+	delivery.Attempts++
+	delivery.LastError = nil
+
+	switch {
+	case err == nil:
+		delivery.Status = entity.NotificationDeliverySent
+		delivery.SentAt = &now
+		result.Sent++
+	case errors.Is(err, notify.ErrNoAddress):
+		delivery.Status = entity.NotificationDeliverySkipped
+		delivery.LastError = deliveryError(err)
+		result.Skipped++
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		delivery.Status = entity.NotificationDeliveryFailed
+		delivery.LastError = deliveryError(err)
+		result.Failed++
+		log.Printf("[NOTIFICATION] Gave up %s delivery %s after %d attempts: %v",
+			delivery.Channel, delivery.ID, delivery.Attempts, err)
+	default:
+		delivery.Status = entity.NotificationDeliveryPending
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+		delivery.LastError = deliveryError(err)
+		result.Retried++
+	}
+
+	if err := d.deliveries.UpdateDelivery(ctx, delivery); err != nil {
+		log.Printf("[NOTIFICATION] Failed to save delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *entity.NotificationDelivery, users map[string]*entity.User) error {
+	channel, ok := d.channels[delivery.Channel]
+	if !ok {
+		return fmt.Errorf("%w: channel %s is not configured", notify.ErrNoAddress, delivery.Channel)
+	}
+
+	n, err := d.notifications.FindByID(ctx, delivery.NotificationID)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		return fmt.Errorf("%w: the notification was deleted", notify.ErrNoAddress)
+	}
+
+	user, cached := users[delivery.UserID]
+	if !cached {
+		if user, err = d.users.FindByID(ctx, delivery.UserID); err != nil {
+			return err
+		}
+		users[delivery.UserID] = user
+	}
+	if user == nil || !user.IsActive {
+		return fmt.Errorf("%w: the user is missing or inactive", notify.ErrNoAddress)
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	return channel.Send(sendCtx, n, user)
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BaseDelay << (attempts - 1)
+	if delay <= 0 || (d.cfg.MaxDelay > 0 && delay > d.cfg.MaxDelay) {
+		delay = d.cfg.MaxDelay
+	}
+	return delay
+}
+
+func deliveryError(err error) *string {
+	msg := err.Error()
+	if len(msg) > maxDeliveryError {
+		msg = msg[:maxDeliveryError]
+	}
+	return &msg
+}
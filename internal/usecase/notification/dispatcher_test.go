@@ -0,0 +1,246 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/notify"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/realtime"
+)
+
+// fakeChannel records what it sends and fails with the queued errors first
+type fakeChannel struct {
+	name string
+	errs []error
+	sent []string // notification IDs
+}
+
+func (f *fakeChannel) Name() string { return f.name }
+
+func (f *fakeChannel) Send(ctx context.Context, n *entity.Notificacao, to *entity.User) error {
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	f.sent = append(f.sent, n.ID)
+	return nil
+}
+
+type dispatchFixture struct {
+	notifs     *testutil.MockNotificacaoRepository
+	deliveries *testutil.MockNotificationDeliveryRepository
+	email      *fakeChannel
+	dispatcher *Dispatcher
+	uc         UseCase
+	now        time.Time
+}
+
+func newDispatchFixture() *dispatchFixture {
+	f := &dispatchFixture{
+		notifs:     testutil.NewMockNotificacaoRepository(),
+		deliveries: testutil.NewMockNotificationDeliveryRepository(),
+		email:      &fakeChannel{name: entity.NotificationChannelEmail},
+		now:        time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC),
+	}
+	users := testutil.NewMockUserRepository(&entity.User{ID: "user-1", Email: "ana@exemplo.com", IsActive: true})
+	f.dispatcher = NewDispatcher(f.deliveries, testutil.NewMockPushSubscriptionRepository(), f.notifs, users,
+		DispatcherConfig{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour, BatchSize: 10}, f.email)
+	f.dispatcher.now = func() time.Time { return f.now }
+	f.uc = NewUseCase(f.notifs, realtime.NewHub(), f.dispatcher)
+	return f
+}
+
+func (f *dispatchFixture) onlyDelivery(t *testing.T) *entity.NotificationDelivery {
+	t.Helper()
+	if len(f.deliveries.Deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(f.deliveries.Deliveries))
+	}
+	for _, d := range f.deliveries.Deliveries {
+		return d
+	}
+	return nil
+}
+
+func TestCreateQueuesEnabledChannels(t *testing.T) {
+	f := newDispatchFixture()
+	ctx := context.Background()
+
+	if err := f.uc.Create(ctx, newNotification("n1", "user-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// WhatsApp is off by default and push is not configured
+	d := f.onlyDelivery(t)
+	if d.Channel != entity.NotificationChannelEmail || d.Status != entity.NotificationDeliveryPending {
+		t.Errorf("expected a pending e-mail delivery, got %+v", d)
+	}
+
+	f.deliveries.ChannelPrefs["user-1"] = map[string]bool{entity.NotificationChannelEmail: false}
+	f.uc.Create(ctx, newNotification("n2", "user-1"))
+	if len(f.deliveries.Deliveries) != 1 {
+		t.Errorf("expected no delivery once e-mail is turned off, got %d", len(f.deliveries.Deliveries))
+	}
+}
+
+func TestDispatchDueRetriesWithBackoff(t *testing.T) {
+	f := newDispatchFixture()
+	ctx := context.Background()
+	f.email.errs = []error{errors.New("smtp: 421 busy"), errors.New("smtp: 421 busy")}
+	f.uc.Create(ctx, newNotification("n1", "user-1"))
+
+	result, err := f.dispatcher.DispatchDue(ctx)
+	if err != nil || result.Retried != 1 {
+		t.Fatalf("expected a retry, got %+v, %v", result, err)
+	}
+	d := f.onlyDelivery(t)
+	if d.Status != entity.NotificationDeliveryPending || !d.NextAttemptAt.Equal(f.now.Add(time.Minute)) {
+		t.Fatalf("expected the next attempt in a minute, got %+v", d)
+	}
+
+	if result, _ := f.dispatcher.DispatchDue(ctx); result.Retried != 0 || result.Sent != 0 {
+		t.Errorf("expected nothing due before the backoff, got %+v", result)
+	}
+
+	f.now = f.now.Add(time.Minute)
+	f.dispatcher.DispatchDue(ctx)
+	if d := f.onlyDelivery(t); !d.NextAttemptAt.Equal(f.now.Add(2 * time.Minute)) {
+		t.Fatalf("expected the backoff to double, got %+v", d)
+	}
+
+	f.now = f.now.Add(2 * time.Minute)
+	result, _ = f.dispatcher.DispatchDue(ctx)
+	d = f.onlyDelivery(t)
+	if result.Sent != 1 || d.Status != entity.NotificationDeliverySent || d.Attempts != 3 || d.LastError != nil {
+		t.Errorf("expected the third attempt to be sent, got %+v", d)
+	}
+	if len(f.email.sent) != 1 || f.email.sent[0] != "n1" {
+		t.Errorf("expected n1 to be sent once, got %v", f.email.sent)
+	}
+}
+
+func TestDispatchDueGivesUp(t *testing.T) {
+	f := newDispatchFixture()
+	ctx := context.Background()
+	f.email.errs = []error{errors.New("down"), errors.New("down"), errors.New("down")}
+	f.uc.Create(ctx, newNotification("n1", "user-1"))
+
+	for i := 0; i < 3; i++ {
+		f.dispatcher.DispatchDue(ctx)
+		f.now = f.now.Add(time.Hour)
+	}
+	d := f.onlyDelivery(t)
+	if d.Status != entity.NotificationDeliveryFailed || d.Attempts != 3 || d.LastError == nil || *d.LastError != "down" {
+		t.Errorf("expected the delivery to fail after 3 attempts, got %+v", d)
+	}
+}
+
+func TestDispatchDueSkipsUnreachable(t *testing.T) {
+	f := newDispatchFixture()
+	ctx := context.Background()
+	f.email.errs = []error{notify.ErrNoAddress}
+	f.uc.Create(ctx, newNotification("n1", "user-1"))
+
+	result, _ := f.dispatcher.DispatchDue(ctx)
+	if d := f.onlyDelivery(t); result.Skipped != 1 || d.Status != entity.NotificationDeliverySkipped {
+		t.Errorf("expected the delivery to be skipped, got %+v", d)
+	}
+}
+
+func TestChannelPreferences(t *testing.T) {
+	f := newDispatchFixture()
+	ctx := context.Background()
+
+	prefs, err := f.uc.UpdateChannelPreferences(ctx, "user-1", &entity.UpdateNotificationChannelsRequest{
+		Channels: map[string]bool{entity.NotificationChannelWhatsApp: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][2]bool{ // enabled, available
+		entity.NotificationChannelEmail:    {true, true},
+		entity.NotificationChannelWhatsApp: {true, false},
+		entity.NotificationChannelPush:     {true, false},
+	}
+	if len(prefs) != len(want) {
+		t.Fatalf("expected every channel, got %+v", prefs)
+	}
+	for _, p := range prefs {
+		if got := [2]bool{p.Enabled, p.Available}; got != want[p.Channel] {
+			t.Errorf("%s: expected enabled/available %v, got %v", p.Channel, want[p.Channel], got)
+		}
+	}
+
+	_, err = f.uc.UpdateChannelPreferences(ctx, "user-1", &entity.UpdateNotificationChannelsRequest{
+		Channels: map[string]bool{"pager": true},
+	})
+	if err == nil {
+		t.Error("expected an unknown channel to be rejected")
+	}
+}
+
+func TestReceipts(t *testing.T) {
+	f := newDispatchFixture()
+	ctx := context.Background()
+	f.uc.Create(ctx, newNotification("n1", "user-1"))
+	f.dispatcher.DispatchDue(ctx)
+
+	f.now = f.now.Add(time.Hour)
+	if err := f.uc.RecordOpen(ctx, "n1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.now = f.now.Add(time.Hour)
+	f.uc.RecordOpen(ctx, "n1")
+	readAt := f.now
+	f.notifs.Notifications["n1"].Read = true
+	f.notifs.Notifications["n1"].ReadAt = &readAt
+
+	receipts, err := f.uc.GetReceipts(ctx, "user-1", "n1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(receipts) != 2 || receipts[0].Channel != entity.NotificationChannelInApp || receipts[1].Channel != entity.NotificationChannelEmail {
+		t.Fatalf("expected in-app and e-mail receipts, got %+v", receipts)
+	}
+	if receipts[0].ReadAt == nil || !receipts[0].ReadAt.Equal(readAt) {
+		t.Errorf("expected the in-app read time, got %+v", receipts[0])
+	}
+	opened := f.now.Add(-time.Hour)
+	email := receipts[1]
+	if email.Status != entity.NotificationDeliverySent || email.ReadAt == nil || !email.ReadAt.Equal(opened) ||
+		email.DeliveredAt == nil || !email.DeliveredAt.Equal(opened) {
+		t.Errorf("expected the first open to be kept as read and delivered, got %+v", email)
+	}
+
+	if _, err := f.uc.GetReceipts(ctx, "user-2", "n1"); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected ErrNotificationNotFound for another user, got %v", err)
+	}
+}
+
+func TestConfirmDelivery(t *testing.T) {
+	f := newDispatchFixture()
+	ctx := context.Background()
+	f.uc.Create(ctx, newNotification("n1", "user-1"))
+	f.deliveries.CreateDeliveries(ctx, []entity.NotificationDelivery{{
+		ID: "push-1", NotificationID: "n1", UserID: "user-1", Channel: entity.NotificationChannelPush,
+		Status: entity.NotificationDeliverySent,
+	}})
+
+	if err := f.uc.ConfirmDelivery(ctx, "user-2", "n1"); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected ErrNotificationNotFound for another user, got %v", err)
+	}
+	if err := f.uc.ConfirmDelivery(ctx, "user-1", "n1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d := f.deliveries.Deliveries["push-1"]; d.DeliveredAt == nil || !d.DeliveredAt.Equal(f.now) || d.OpenedAt != nil {
+		t.Errorf("expected the push to be delivered, got %+v", d)
+	}
+	for _, d := range f.deliveries.Deliveries {
+		if d.Channel == entity.NotificationChannelEmail && d.DeliveredAt != nil {
+			t.Errorf("expected the e-mail delivery to be left alone, got %+v", d)
+		}
+	}
+}
@@ -0,0 +1,396 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/realtime"
+)
+
+// countTTL bounds how long a count changed on another instance takes to be seen
+const countTTL = 30 * time.Second
+
+// Events pushed to the subscribers of a user
+const (
+	// EventUnreadCount carries the unread count whenever it changes
+	EventUnreadCount = "unread_count"
+	// EventNotification carries each new notification of the user, such as a confirmed
+	// payment or an assigned task
+	EventNotification = "notification"
+)
+
+// ErrNotificationNotFound is returned for notifications that do not exist or belong to
+// another user
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// ErrChannelsUnavailable is returned by the channel methods when there is no dispatcher
+var ErrChannelsUnavailable = errors.New("notification channels are not available")
+
+// UseCase defines the notification use case interface
+type UseCase interface {
+	// List returns the notifications of a user, newest first
+	List(ctx context.Context, userID string) ([]entity.Notificacao, error)
+
+	// ListUnread returns the unread notifications of a user, newest first
+	ListUnread(ctx context.Context, userID string) ([]entity.Notificacao, error)
+
+	// Create stores a notification and pushes it, with the new unread count, to its user
+	Create(ctx context.Context, notif *entity.Notificacao) error
+
+	// MarkAsRead marks a notification of the user as read
+	MarkAsRead(ctx context.Context, userID, id string) error
+
+	// MarkAllAsRead marks every notification of the user as read
+	MarkAllAsRead(ctx context.Context, userID string) error
+
+	// Delete removes a notification of the user
+	Delete(ctx context.Context, userID, id string) error
+
+	// GetReceipts returns the delivery and read state of a notification of the user on each
+	// channel, in-app first
+	GetReceipts(ctx context.Context, userID, id string) ([]entity.NotificationReceipt, error)
+
+	// RecordOpen records the open of the e-mail of a notification, reported by its tracking
+	// pixel
+	RecordOpen(ctx context.Context, id string) error
+
+	// ConfirmDelivery records that the push of a notification of the user reached a browser
+	ConfirmDelivery(ctx context.Context, userID, id string) error
+
+	// CountUnread returns the unread count of a user, cached for countTTL
+	CountUnread(ctx context.Context, userID string) (int, error)
+
+	// Subscribe streams the new notifications and unread count changes of a user until the
+	// subscription is closed
+	Subscribe(userID string) *realtime.Subscription
+
+	// GetPreferences returns whether the user receives each optional notification type
+	GetPreferences(ctx context.Context, userID string) ([]entity.NotificationPreference, error)
+
+	// UpdatePreferences turns notification types on or off for the user
+	UpdatePreferences(ctx context.Context, userID string, req *entity.UpdateNotificationPreferencesRequest) ([]entity.NotificationPreference, error)
+
+	// GetChannelPreferences returns whether the user receives notifications on each channel
+	GetChannelPreferences(ctx context.Context, userID string) ([]entity.NotificationChannelPreference, error)
+
+	// UpdateChannelPreferences turns channels on or off for the user
+	UpdateChannelPreferences(ctx context.Context, userID string, req *entity.UpdateNotificationChannelsRequest) ([]entity.NotificationChannelPreference, error)
+
+	// SubscribePush registers a browser for the web push notifications of the user
+	SubscribePush(ctx context.Context, userID string, req *entity.CreatePushSubscriptionRequest, userAgent string) (*entity.PushSubscription, error)
+
+	// UnsubscribePush removes a push subscription of the user
+	UnsubscribePush(ctx context.Context, userID, id string) error
+
+	// PushPublicKey returns the VAPID public key browsers subscribe with, empty when web push
+	// is not configured
+	PushPublicKey() string
+}
+
+type cachedCount struct {
+	count int
+	at    time.Time
+}
+
+type notificationUseCase struct {
+	repo       repository.NotificacaoRepository
+	hub        *realtime.Hub
+	dispatcher *Dispatcher
+
+	mu     sync.Mutex
+	counts map[string]cachedCount
+}
+
+// NewUseCase creates a new notification use case. Count changes are published on hub, which
+// only reaches the clients connected to this instance. New notifications are queued on
+// dispatcher for e-mail, WhatsApp and push; a nil dispatcher only stores them.
+func NewUseCase(repo repository.NotificacaoRepository, hub *realtime.Hub, dispatcher *Dispatcher) UseCase {
+	return &notificationUseCase{repo: repo, hub: hub, dispatcher: dispatcher, counts: make(map[string]cachedCount)}
+}
+
+// List returns the notifications of a user
+func (uc *notificationUseCase) List(ctx context.Context, userID string) ([]entity.Notificacao, error) {
+	return uc.repo.FindByUserID(ctx, userID)
+}
+
+// ListUnread returns the unread notifications of a user
+func (uc *notificationUseCase) ListUnread(ctx context.Context, userID string) ([]entity.Notificacao, error) {
+	return uc.repo.FindUnreadByUserID(ctx, userID)
+}
+
+// Create stores a notification, pushes it to the connected clients of its user, updates
+// their badge and queues its outbound deliveries. Notifications of a type the user turned
+// off are dropped.
+func (uc *notificationUseCase) Create(ctx context.Context, notif *entity.Notificacao) error {
+	if !uc.wants(ctx, notif.UserID, notif.Type) {
+		return nil
+	}
+	if err := uc.repo.Create(ctx, notif); err != nil {
+		return err
+	}
+	uc.hub.Publish(notif.UserID, NotificationEvent(notif))
+	uc.changed(ctx, notif.UserID)
+
+	// The notification is stored, so a failure to queue its deliveries is only logged
+	if uc.dispatcher != nil {
+		if err := uc.dispatcher.Enqueue(ctx, notif); err != nil {
+			log.Printf("[NOTIFICATION] Failed to queue deliveries of notification %s: %v", notif.ID, err)
+		}
+	}
+	return nil
+}
+
+// MarkAsRead marks a notification of the user as read
+func (uc *notificationUseCase) MarkAsRead(ctx context.Context, userID, id string) error {
+	if err := uc.checkOwner(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := uc.repo.MarkAsRead(ctx, id); err != nil {
+		return err
+	}
+	uc.changed(ctx, userID)
+	return nil
+}
+
+// MarkAllAsRead marks every notification of the user as read
+func (uc *notificationUseCase) MarkAllAsRead(ctx context.Context, userID string) error {
+	if err := uc.repo.MarkAllAsRead(ctx, userID); err != nil {
+		return err
+	}
+	uc.changed(ctx, userID)
+	return nil
+}
+
+// Delete removes a notification of the user
+func (uc *notificationUseCase) Delete(ctx context.Context, userID, id string) error {
+	if err := uc.checkOwner(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	uc.changed(ctx, userID)
+	return nil
+}
+
+// GetReceipts returns the in-app state of a notification of the user followed by its
+// outbound deliveries
+func (uc *notificationUseCase) GetReceipts(ctx context.Context, userID, id string) ([]entity.NotificationReceipt, error) {
+	notif, err := uc.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	receipts := []entity.NotificationReceipt{{
+		Channel:     entity.NotificationChannelInApp,
+		Status:      entity.NotificationDeliverySent,
+		SentAt:      &notif.CreatedAt,
+		DeliveredAt: &notif.CreatedAt,
+		ReadAt:      notif.ReadAt,
+	}}
+	if uc.dispatcher == nil {
+		return receipts, nil
+	}
+	outbound, err := uc.dispatcher.Receipts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return append(receipts, outbound...), nil
+}
+
+// RecordOpen records the open of the e-mail of a notification
+func (uc *notificationUseCase) RecordOpen(ctx context.Context, id string) error {
+	if uc.dispatcher == nil {
+		return ErrChannelsUnavailable
+	}
+	return uc.dispatcher.RecordOpen(ctx, id)
+}
+
+// ConfirmDelivery records that the push of a notification of the user reached a browser
+func (uc *notificationUseCase) ConfirmDelivery(ctx context.Context, userID, id string) error {
+	if err := uc.checkOwner(ctx, userID, id); err != nil {
+		return err
+	}
+	if uc.dispatcher == nil {
+		return ErrChannelsUnavailable
+	}
+	return uc.dispatcher.ConfirmPush(ctx, id)
+}
+
+// CountUnread returns the unread count of a user from the cache, loading it when missing or
+// older than countTTL
+func (uc *notificationUseCase) CountUnread(ctx context.Context, userID string) (int, error) {
+	uc.mu.Lock()
+	cached, ok := uc.counts[userID]
+	uc.mu.Unlock()
+	if ok && time.Since(cached.at) < countTTL {
+		return cached.count, nil
+	}
+	return uc.loadCount(ctx, userID)
+}
+
+// Subscribe streams the new notifications and unread count changes of a user
+func (uc *notificationUseCase) Subscribe(userID string) *realtime.Subscription {
+	return uc.hub.Subscribe(userID)
+}
+
+// GetPreferences returns every optional notification type with whether the user receives it
+func (uc *notificationUseCase) GetPreferences(ctx context.Context, userID string) ([]entity.NotificationPreference, error) {
+	stored, err := uc.repo.FindPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mergePreferences(userID, stored), nil
+}
+
+// UpdatePreferences turns notification types on or off; types left out keep their setting
+func (uc *notificationUseCase) UpdatePreferences(ctx context.Context, userID string, req *entity.UpdateNotificationPreferencesRequest) ([]entity.NotificationPreference, error) {
+	prefs := make([]entity.NotificationPreference, 0, len(req.Preferences))
+	for t, enabled := range req.Preferences {
+		if !entity.IsOptionalNotificationType(t) {
+			return nil, fmt.Errorf("invalid notification type: %s", t)
+		}
+		prefs = append(prefs, entity.NotificationPreference{UserID: userID, Type: t, Enabled: enabled})
+	}
+	if err := uc.repo.SavePreferences(ctx, userID, prefs); err != nil {
+		return nil, err
+	}
+	return uc.GetPreferences(ctx, userID)
+}
+
+// GetChannelPreferences returns every channel with whether the user receives it
+func (uc *notificationUseCase) GetChannelPreferences(ctx context.Context, userID string) ([]entity.NotificationChannelPreference, error) {
+	if uc.dispatcher == nil {
+		return nil, ErrChannelsUnavailable
+	}
+	return uc.dispatcher.ChannelPreferences(ctx, userID)
+}
+
+// UpdateChannelPreferences turns channels on or off; channels left out keep their setting
+func (uc *notificationUseCase) UpdateChannelPreferences(ctx context.Context, userID string, req *entity.UpdateNotificationChannelsRequest) ([]entity.NotificationChannelPreference, error) {
+	if uc.dispatcher == nil {
+		return nil, ErrChannelsUnavailable
+	}
+	return uc.dispatcher.UpdateChannelPreferences(ctx, userID, req)
+}
+
+// SubscribePush registers a browser for the web push notifications of the user
+func (uc *notificationUseCase) SubscribePush(ctx context.Context, userID string, req *entity.CreatePushSubscriptionRequest, userAgent string) (*entity.PushSubscription, error) {
+	if uc.dispatcher == nil {
+		return nil, ErrChannelsUnavailable
+	}
+	return uc.dispatcher.SubscribePush(ctx, userID, req, userAgent)
+}
+
+// UnsubscribePush removes a push subscription of the user
+func (uc *notificationUseCase) UnsubscribePush(ctx context.Context, userID, id string) error {
+	if uc.dispatcher == nil {
+		return ErrChannelsUnavailable
+	}
+	return uc.dispatcher.UnsubscribePush(ctx, userID, id)
+}
+
+// PushPublicKey returns the VAPID public key of the push channel
+func (uc *notificationUseCase) PushPublicKey() string {
+	if uc.dispatcher == nil {
+		return ""
+	}
+	return uc.dispatcher.PushPublicKey()
+}
+
+// wants reports whether the user receives notifications of a type. When the preferences
+// cannot be read the notification is delivered.
+func (uc *notificationUseCase) wants(ctx context.Context, userID, notifType string) bool {
+	if !entity.IsOptionalNotificationType(notifType) {
+		return true
+	}
+	stored, err := uc.repo.FindPreferences(ctx, userID)
+	if err != nil {
+		log.Printf("[NOTIFICATION] Failed to load preferences of user %s: %v", userID, err)
+		return true
+	}
+	for _, p := range stored {
+		if p.Type == notifType {
+			return p.Enabled
+		}
+	}
+	return true
+}
+
+func (uc *notificationUseCase) checkOwner(ctx context.Context, userID, id string) error {
+	_, err := uc.owned(ctx, userID, id)
+	return err
+}
+
+// owned returns a notification of the user, ErrNotificationNotFound when it belongs to another
+func (uc *notificationUseCase) owned(ctx context.Context, userID, id string) (*entity.Notificacao, error) {
+	notif, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if notif == nil || notif.UserID != userID {
+		return nil, ErrNotificationNotFound
+	}
+	return notif, nil
+}
+
+func (uc *notificationUseCase) loadCount(ctx context.Context, userID string) (int, error) {
+	count, err := uc.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	uc.mu.Lock()
+	uc.counts[userID] = cachedCount{count: count, at: time.Now()}
+	uc.mu.Unlock()
+	return count, nil
+}
+
+// changed drops the cached count of a user and, when the user is connected, pushes the new
+// one. The write already succeeded, so a failure here is only logged.
+func (uc *notificationUseCase) changed(ctx context.Context, userID string) {
+	uc.mu.Lock()
+	delete(uc.counts, userID)
+	uc.mu.Unlock()
+
+	if uc.hub.Subscribers(userID) == 0 {
+		return
+	}
+	count, err := uc.loadCount(ctx, userID)
+	if err != nil {
+		log.Printf("[NOTIFICATION] Failed to refresh unread count of user %s: %v", userID, err)
+		return
+	}
+	uc.hub.Publish(userID, UnreadCountEvent(count))
+}
+
+// mergePreferences lists the optional types in order, enabled unless the user turned them off
+func mergePreferences(userID string, stored []entity.NotificationPreference) []entity.NotificationPreference {
+	byType := make(map[string]entity.NotificationPreference, len(stored))
+	for _, p := range stored {
+		byType[p.Type] = p
+	}
+	prefs := make([]entity.NotificationPreference, 0, len(entity.OptionalNotificationTypes))
+	for _, t := range entity.OptionalNotificationTypes {
+		p, ok := byType[t]
+		if !ok {
+			p = entity.NotificationPreference{UserID: userID, Type: t, Enabled: true}
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs
+}
+
+// UnreadCountEvent is the event carrying a user's unread count
+func UnreadCountEvent(count int) realtime.Event {
+	return realtime.Event{Type: EventUnreadCount, Data: map[string]int{"unread_count": count}}
+}
+
+// NotificationEvent is the event carrying a new notification
+func NotificationEvent(notif *entity.Notificacao) realtime.Event {
+	return realtime.Event{Type: EventNotification, Data: notif}
+}
@@ -0,0 +1,179 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/realtime"
+)
+
+func newNotification(id, userID string) *entity.Notificacao {
+	return &entity.Notificacao{ID: id, UserID: userID, Type: entity.NotificationTypeSystem, Title: "Aviso"}
+}
+
+// nextCount returns the unread count of the next event on the subscription
+func nextCount(t *testing.T, sub *realtime.Subscription) int {
+	t.Helper()
+	select {
+	case e := <-sub.C:
+		if e.Type != EventUnreadCount {
+			t.Fatalf("unexpected event %s", e.Type)
+		}
+		return e.Data.(map[string]int)["unread_count"]
+	default:
+		t.Fatal("expected an unread count event")
+		return 0
+	}
+}
+
+// nextNotification returns the notification of the next event on the subscription
+func nextNotification(t *testing.T, sub *realtime.Subscription) *entity.Notificacao {
+	t.Helper()
+	select {
+	case e := <-sub.C:
+		if e.Type != EventNotification {
+			t.Fatalf("unexpected event %s", e.Type)
+		}
+		return e.Data.(*entity.Notificacao)
+	default:
+		t.Fatal("expected a notification event")
+		return nil
+	}
+}
+
+func TestCountUnread_Cached(t *testing.T) {
+	repo := testutil.NewMockNotificacaoRepository()
+	uc := NewUseCase(repo, realtime.NewHub(), nil)
+	ctx := context.Background()
+	repo.Notifications["n1"] = newNotification("n1", "user-1")
+
+	for i := 0; i < 3; i++ {
+		count, err := uc.CountUnread(ctx, "user-1")
+		if err != nil || count != 1 {
+			t.Fatalf("expected 1 unread, got %d, %v", count, err)
+		}
+	}
+	if repo.CountCalls != 1 {
+		t.Errorf("expected one count query, got %d", repo.CountCalls)
+	}
+
+	if err := uc.Create(ctx, newNotification("n2", "user-1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count, _ := uc.CountUnread(ctx, "user-1"); count != 2 {
+		t.Errorf("expected the cache to be dropped on create, got %d", count)
+	}
+}
+
+func TestChangesArePushed(t *testing.T) {
+	repo := testutil.NewMockNotificacaoRepository()
+	uc := NewUseCase(repo, realtime.NewHub(), nil)
+	ctx := context.Background()
+
+	sub := uc.Subscribe("user-1")
+	defer sub.Close()
+
+	_ = uc.Create(ctx, newNotification("n1", "user-1"))
+	_ = uc.Create(ctx, newNotification("n2", "user-1"))
+	if got := nextNotification(t, sub); got.ID != "n1" {
+		t.Errorf("expected the first notification, got %s", got.ID)
+	}
+	if got := nextCount(t, sub); got != 1 {
+		t.Errorf("expected 1 after the first notification, got %d", got)
+	}
+	if got := nextNotification(t, sub); got.ID != "n2" {
+		t.Errorf("expected the second notification, got %s", got.ID)
+	}
+	if got := nextCount(t, sub); got != 2 {
+		t.Errorf("expected 2 after the second notification, got %d", got)
+	}
+
+	if err := uc.MarkAsRead(ctx, "user-1", "n1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := nextCount(t, sub); got != 1 {
+		t.Errorf("expected 1 after reading one, got %d", got)
+	}
+	if err := uc.MarkAllAsRead(ctx, "user-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := nextCount(t, sub); got != 0 {
+		t.Errorf("expected 0 after reading all, got %d", got)
+	}
+
+	other := uc.Subscribe("user-2")
+	defer other.Close()
+	_ = uc.Create(ctx, newNotification("n3", "user-1"))
+	if len(other.C) != 0 {
+		t.Error("expected no event for another user")
+	}
+}
+
+func TestOwnership(t *testing.T) {
+	repo := testutil.NewMockNotificacaoRepository()
+	uc := NewUseCase(repo, realtime.NewHub(), nil)
+	ctx := context.Background()
+	repo.Notifications["n1"] = newNotification("n1", "user-1")
+
+	if err := uc.MarkAsRead(ctx, "user-2", "n1"); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected not found for another user's notification, got %v", err)
+	}
+	if err := uc.Delete(ctx, "user-2", "n1"); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected not found for another user's notification, got %v", err)
+	}
+	if err := uc.Delete(ctx, "user-1", "missing"); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("expected not found for a missing notification, got %v", err)
+	}
+	if repo.Notifications["n1"].Read {
+		t.Error("expected the notification to be left untouched")
+	}
+}
+
+func TestPreferences(t *testing.T) {
+	repo := testutil.NewMockNotificacaoRepository()
+	uc := NewUseCase(repo, realtime.NewHub(), nil)
+	ctx := context.Background()
+
+	prefs, err := uc.UpdatePreferences(ctx, "user-1", &entity.UpdateNotificationPreferencesRequest{
+		Preferences: map[string]bool{entity.NotificationTypePayment: false},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prefs) != len(entity.OptionalNotificationTypes) {
+		t.Fatalf("expected every optional type, got %+v", prefs)
+	}
+	for _, p := range prefs {
+		if p.Enabled != (p.Type != entity.NotificationTypePayment) {
+			t.Errorf("unexpected preference %+v", p)
+		}
+	}
+
+	muted := &entity.Notificacao{ID: "n1", UserID: "user-1", Type: entity.NotificationTypePayment}
+	system := &entity.Notificacao{ID: "n2", UserID: "user-1", Type: entity.NotificationTypeSystem}
+	other := &entity.Notificacao{ID: "n3", UserID: "user-2", Type: entity.NotificationTypePayment}
+	for _, n := range []*entity.Notificacao{muted, system, other} {
+		if err := uc.Create(ctx, n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, ok := repo.Notifications["n1"]; ok {
+		t.Error("expected the muted payment notification to be dropped")
+	}
+	if _, ok := repo.Notifications["n2"]; !ok {
+		t.Error("expected system notifications to be delivered")
+	}
+	if _, ok := repo.Notifications["n3"]; !ok {
+		t.Error("expected other users to keep receiving payment notifications")
+	}
+
+	_, err = uc.UpdatePreferences(ctx, "user-1", &entity.UpdateNotificationPreferencesRequest{
+		Preferences: map[string]bool{entity.NotificationTypeSystem: false},
+	})
+	if err == nil {
+		t.Error("expected system notifications not to be optional")
+	}
+}
@@ -0,0 +1,40 @@
+package notification
+
+import (
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/pkg/signedurl"
+)
+
+// openPixelTTL is how long the tracking pixel of an e-mail records opens
+const openPixelTTL = 90 * 24 * time.Hour
+
+// OpenTracker signs the links of the tracking pixel e-mails carry, so the pixel can report
+// the open of a notification without the user's token
+type OpenTracker struct {
+	signer  *signedurl.Signer
+	baseURL string
+	now     func() time.Time
+}
+
+// NewOpenTracker creates a tracker for pixels served by the API at baseURL
+func NewOpenTracker(secret, baseURL string) *OpenTracker {
+	return &OpenTracker{signer: signedurl.New(secret), baseURL: strings.TrimRight(baseURL, "/"), now: time.Now}
+}
+
+// URL returns the signed link of the tracking pixel of a notification
+func (t *OpenTracker) URL(notificationID string) string {
+	return t.baseURL + t.signer.URL(openPixelPath(notificationID), t.now().Add(openPixelTTL))
+}
+
+// Verify reports whether expires and signature grant the pixel of a notification
+func (t *OpenTracker) Verify(notificationID, expires, signature string) bool {
+	_, err := t.signer.Verify(openPixelPath(notificationID), expires, signature, t.now())
+	return err == nil
+}
+
+// openPixelPath is the route of the tracking pixel of a notification
+func openPixelPath(notificationID string) string {
+	return "/api/v1/notifications/" + notificationID + "/open.gif"
+}
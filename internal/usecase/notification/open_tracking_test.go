@@ -0,0 +1,33 @@
+package notification
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenTracker(t *testing.T) {
+	tracker := NewOpenTracker("secret", "https://api.condotrack.com/")
+	tracker.now = func() time.Time { return time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC) }
+
+	link := tracker.URL("n1")
+	if !strings.HasPrefix(link, "https://api.condotrack.com/api/v1/notifications/n1/open.gif?") {
+		t.Fatalf("unexpected pixel link %s", link)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	q := u.Query()
+	if !tracker.Verify("n1", q.Get("expires"), q.Get("signature")) {
+		t.Error("expected the link to verify")
+	}
+	if tracker.Verify("n2", q.Get("expires"), q.Get("signature")) {
+		t.Error("expected the link of n1 to be rejected for n2")
+	}
+	tracker.now = func() time.Time { return time.Date(2024, 8, 1, 9, 0, 0, 0, time.UTC) }
+	if tracker.Verify("n1", q.Get("expires"), q.Get("signature")) {
+		t.Error("expected the link to expire after 90 days")
+	}
+}
@@ -0,0 +1,167 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/google/uuid"
+)
+
+// ErrNoPaymentProof is returned for payments that were not confirmed by hand
+var ErrNoPaymentProof = errors.New("payment has no manual confirmation")
+
+// MarkPaidResponse is a payment confirmed by hand and its confirmation
+type MarkPaidResponse struct {
+	Payment      *entity.Payment             `json:"payment"`
+	Confirmation *entity.PaymentConfirmation `json:"confirmation"`
+}
+
+// MarkPaid confirms an open payment paid outside the gateway, e.g. by bank transfer. The
+// proof is stored and the confirmation goes through the webhook pipeline (see EventApplier)
+// like a gateway confirmation: the payment and enrollment are confirmed, the revenue split
+// and instructor ledger credit are created and the student is notified. The split is not
+// transferred automatically, the funds are not in the gateway balance.
+func (uc *paymentUseCase) MarkPaid(ctx context.Context, paymentID string, proof *entity.PaymentProof, file io.Reader, userID, ip string) (*MarkPaidResponse, error) {
+	reason := strings.TrimSpace(proof.Reason)
+	if reason == "" {
+		return nil, errors.New("invalid request: reason is required")
+	}
+	paidAt := time.Now()
+	if proof.PaidAt != nil && *proof.PaidAt != "" {
+		t, err := parseDateString(*proof.PaidAt)
+		if err != nil {
+			return nil, errors.New("invalid paid_at: use YYYY-MM-DD")
+		}
+		if t.After(paidAt) {
+			return nil, errors.New("invalid paid_at: cannot be in the future")
+		}
+		paidAt = t
+	}
+
+	payment, err := uc.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if !isReconcilable(payment) {
+		if payment.GatewayPaymentID == nil || *payment.GatewayPaymentID == "" {
+			return nil, errors.New("invalid payment: it has no gateway charge, the boletos of a carnê are marked paid one by one")
+		}
+		return nil, fmt.Errorf("invalid payment: cannot mark a %s payment as paid", payment.Status)
+	}
+	if uc.applier == nil {
+		return nil, errors.New("payment confirmation is not configured")
+	}
+	if uc.storage == nil {
+		return nil, errors.New("storage service is not available")
+	}
+	existing, err := uc.confirmationRepo.FindByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("invalid payment: it was already marked paid")
+	}
+
+	confirmation := &entity.PaymentConfirmation{
+		ID:               uuid.New().String(),
+		PaymentID:        payment.ID,
+		Reason:           reason,
+		PaymentReference: proof.PaymentReference,
+		ProofFileName:    proof.FileName,
+		ProofContentType: proof.ContentType,
+		PaidAt:           paidAt,
+		CreatedAt:        time.Now(),
+	}
+	if userID != "" {
+		confirmation.ConfirmedBy = &userID
+	}
+	confirmation.ProofKey = fmt.Sprintf("%s/%s%s", payment.ID, confirmation.ID, strings.ToLower(filepath.Ext(proof.FileName)))
+	result, err := uc.storage.UploadFile(ctx, uc.proofBucket, confirmation.ProofKey, file, proof.Size, proof.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	confirmation.ProofSize = result.Size
+
+	// Recorded first: a payment is confirmed by hand once
+	if err := uc.confirmationRepo.Create(ctx, confirmation); err != nil {
+		uc.discardProof(ctx, confirmation.ProofKey)
+		return nil, err
+	}
+	if err := uc.applier.ApplyEvent(ctx, &gateway.WebhookEvent{
+		EventID:      "manual:" + payment.ID,
+		EventType:    gateway.EventPaymentConfirmed,
+		GatewayEvent: gateway.GatewayEventManual,
+		GatewayName:  payment.Gateway,
+		PaymentID:    *payment.GatewayPaymentID,
+		Amount:       payment.GrossAmount.Float(),
+		Status:       gateway.StatusReceived,
+		BillingType:  payment.PaymentMethod,
+		PaidAt:       &paidAt,
+	}); err != nil {
+		if delErr := uc.confirmationRepo.Delete(ctx, confirmation.ID); delErr != nil {
+			log.Printf("Failed to remove manual confirmation %s of payment %s: %v", confirmation.ID, payment.ID, delErr)
+		}
+		uc.discardProof(ctx, confirmation.ProofKey)
+		return nil, err
+	}
+
+	prevStatus := payment.Status
+	amount := payment.GrossAmount
+	txLog := &entity.PaymentTransaction{
+		ID:             uuid.New().String(),
+		PaymentID:      payment.ID,
+		PreviousStatus: &prevStatus,
+		NewStatus:      entity.FinPaymentStatusConfirmed,
+		EventSource:    entity.EventSourceManual,
+		EventType:      entity.TxEventManualConfirmation,
+		Amount:         &amount,
+		Description:    &reason,
+		IPAddress:      nilIfEmpty(ip),
+		TriggeredBy:    nilIfEmpty(userID),
+	}
+	if err := uc.paymentTxnRepo.Create(ctx, txLog); err != nil {
+		log.Printf("Failed to log payment transaction: %v", err)
+	}
+
+	confirmed, err := uc.paymentRepo.FindByID(ctx, payment.ID)
+	if err != nil || confirmed == nil {
+		confirmed = payment
+	}
+	return &MarkPaidResponse{Payment: confirmed, Confirmation: confirmation}, nil
+}
+
+// OpenProof returns the manual confirmation of a payment and the contents of its proof
+func (uc *paymentUseCase) OpenProof(ctx context.Context, paymentID string) (*entity.PaymentConfirmation, io.ReadCloser, error) {
+	if uc.storage == nil {
+		return nil, nil, errors.New("storage service is not available")
+	}
+	confirmation, err := uc.confirmationRepo.FindByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if confirmation == nil {
+		return nil, nil, ErrNoPaymentProof
+	}
+	reader, _, err := uc.storage.GetFile(ctx, uc.proofBucket, confirmation.ProofKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return confirmation, reader, nil
+}
+
+func (uc *paymentUseCase) discardProof(ctx context.Context, key string) {
+	if err := uc.storage.DeleteFile(ctx, uc.proofBucket, key); err != nil {
+		log.Printf("Failed to remove orphaned payment proof %s: %v", key, err)
+	}
+}
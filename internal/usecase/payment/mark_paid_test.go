@@ -0,0 +1,53 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestMarkPaid_Validation(t *testing.T) {
+	uc, payments, applier := newReconcileFixture(nil)
+	addPayment(payments, "open", entity.FinPaymentStatusAwaitingPayment, "ch1")
+	addPayment(payments, "paid", entity.FinPaymentStatusConfirmed, "ch2")
+	addPayment(payments, "carnet", entity.FinPaymentStatusPending, "")
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	badDate := "14/10/2026"
+	tests := []struct {
+		name      string
+		paymentID string
+		proof     entity.PaymentProof
+		want      string
+	}{
+		{"reason required", "open", entity.PaymentProof{Reason: "  "}, "invalid request: reason is required"},
+		{"paid_at format", "open", entity.PaymentProof{Reason: "TED", PaidAt: &badDate}, "invalid paid_at: use YYYY-MM-DD"},
+		{"paid_at in the future", "open", entity.PaymentProof{Reason: "TED", PaidAt: &tomorrow}, "invalid paid_at: cannot be in the future"},
+		{"already paid", "paid", entity.PaymentProof{Reason: "TED"}, "invalid payment: cannot mark a confirmed payment as paid"},
+		{"no gateway charge", "carnet", entity.PaymentProof{Reason: "TED"}, "invalid payment: it has no gateway charge"},
+		{"no storage", "open", entity.PaymentProof{Reason: "TED"}, "storage service is not available"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.MarkPaid(context.Background(), tt.paymentID, &tt.proof, strings.NewReader("proof"), "admin-1", "10.0.0.1")
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if len(applier.events) != 0 {
+		t.Errorf("applied %d events", len(applier.events))
+	}
+}
+
+func TestMarkPaid_NotFound(t *testing.T) {
+	uc, _, _ := newReconcileFixture(nil)
+	_, err := uc.MarkPaid(context.Background(), "missing", &entity.PaymentProof{Reason: "TED"}, strings.NewReader("proof"), "admin-1", "")
+	if !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("err = %v, want ErrPaymentNotFound", err)
+	}
+}
@@ -3,12 +3,22 @@ package payment
 import (
 	"context"
 	"errors"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/condotrack/api/internal/config"
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/gateway"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/document"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/condotrack/api/pkg/phone"
 )
 
 // UseCase defines the payment use case interface
@@ -21,8 +31,24 @@ type UseCase interface {
 	SimulateRevenueSplit(req *entity.CalculateSplitRequest) *entity.CalculateSplitResponse
 	ListPayments(ctx context.Context, filters repository.PaymentFilters) ([]entity.Payment, int, error)
 	GetPaymentsByEnrollment(ctx context.Context, enrollmentID string) ([]entity.Payment, error)
+	GetPaymentTimeline(ctx context.Context, paymentID string) (*entity.PaymentTimeline, error)
+	GetInstallments(ctx context.Context, paymentID, userID, role string) (*entity.PaymentInstallmentPlan, error)
+	ReissueBoleto(ctx context.Context, paymentID string, req *ReissueBoletoRequest, triggeredBy string) (*ReissueBoletoResponse, error)
+	RegeneratePix(ctx context.Context, paymentID, userID, role string) (*RegeneratePixResponse, error)
+	Reconcile(ctx context.Context, req *ReconcileRequest) (*ReconcileReport, error)
+	MarkPaid(ctx context.Context, paymentID string, proof *entity.PaymentProof, file io.Reader, userID, ip string) (*MarkPaidResponse, error)
+	OpenProof(ctx context.Context, paymentID string) (*entity.PaymentConfirmation, io.ReadCloser, error)
+	SetEventApplier(applier EventApplier)
+	ExpirePixPayments(ctx context.Context, now time.Time) (*PixExpirationResult, error)
+	StartPixExpirationWorker(lc *lifecycle.Manager, interval time.Duration)
 }
 
+// ErrPaymentNotFound is returned when a payment does not exist locally
+var ErrPaymentNotFound = errors.New("payment not found")
+
+// ErrNoInstallments is returned for payments not charged in installments of their own
+var ErrNoInstallments = errors.New("payment has no installment plan")
+
 // CreateCustomerRequest is the handler-level customer request (gateway-agnostic)
 type CreateCustomerRequest struct {
 	Name     string `json:"name" binding:"required"`
@@ -64,29 +90,59 @@ type CreateCardPaymentRequest struct {
 
 // PaymentStatusResponse combines local DB data with gateway data
 type PaymentStatusResponse struct {
-	ID                string  `json:"id"`
-	Status            string  `json:"status"`
-	GatewayStatus     string  `json:"gateway_status,omitempty"`
-	Amount            float64 `json:"amount"`
-	NetAmount         float64 `json:"net_amount"`
-	BillingType       string  `json:"billing_type,omitempty"`
-	DueDate           string  `json:"due_date,omitempty"`
-	Gateway           string  `json:"gateway"`
-	GatewayPaymentID  string  `json:"gateway_payment_id,omitempty"`
+	ID               string      `json:"id"`
+	Status           string      `json:"status"`
+	GatewayStatus    string      `json:"gateway_status,omitempty"`
+	Amount           money.Cents `json:"amount"`
+	NetAmount        money.Cents `json:"net_amount"`
+	BillingType      string      `json:"billing_type,omitempty"`
+	DueDate          string      `json:"due_date,omitempty"`
+	Gateway          string      `json:"gateway"`
+	GatewayPaymentID string      `json:"gateway_payment_id,omitempty"`
 }
 
 type paymentUseCase struct {
 	gw                gateway.PaymentGateway
 	paymentRepo       repository.PaymentRepository
+	paymentTxnRepo    repository.PaymentTransactionRepository
+	matriculaRepo     repository.MatriculaRepository
+	renewalRepo       repository.EnrollmentRenewalRepository
+	notifier          notification.UseCase
+	applier           EventApplier
+	confirmationRepo  repository.PaymentConfirmationRepository
+	storage           *storage.StorageService
+	db                *database.MySQL
+	proofBucket       string
+	retryURL          string
 	instructorPercent float64
 	platformPercent   float64
 }
 
 // NewUseCase creates a new payment use case
-func NewUseCase(gw gateway.PaymentGateway, paymentRepo repository.PaymentRepository, cfg *config.Config) UseCase {
+func NewUseCase(
+	gw gateway.PaymentGateway,
+	paymentRepo repository.PaymentRepository,
+	paymentTxnRepo repository.PaymentTransactionRepository,
+	matriculaRepo repository.MatriculaRepository,
+	renewalRepo repository.EnrollmentRenewalRepository,
+	notifier notification.UseCase,
+	confirmationRepo repository.PaymentConfirmationRepository,
+	storageService *storage.StorageService,
+	db *database.MySQL,
+	cfg *config.Config,
+) UseCase {
 	return &paymentUseCase{
 		gw:                gw,
 		paymentRepo:       paymentRepo,
+		paymentTxnRepo:    paymentTxnRepo,
+		matriculaRepo:     matriculaRepo,
+		renewalRepo:       renewalRepo,
+		notifier:          notifier,
+		confirmationRepo:  confirmationRepo,
+		storage:           storageService,
+		db:                db,
+		proofBucket:       cfg.MinioBucketPaymentProofs,
+		retryURL:          strings.TrimRight(cfg.CheckoutRetryURL, "/"),
 		instructorPercent: cfg.RevenueInstructorPercent,
 		platformPercent:   cfg.RevenuePlatformPercent,
 	}
@@ -100,12 +156,20 @@ func (uc *paymentUseCase) CreateCustomer(ctx context.Context, req *CreateCustome
 	if req.Document == "" {
 		return nil, errors.New("customer CPF/CNPJ is required")
 	}
+	doc, err := normalizeDocument("cpf_cnpj", req.Document)
+	if err != nil {
+		return nil, err
+	}
+	phoneNumber, err := normalizePhone("phone", req.Phone)
+	if err != nil {
+		return nil, err
+	}
 
 	return uc.gw.CreateCustomer(ctx, gateway.CreateCustomerRequest{
 		Name:     req.Name,
 		Email:    req.Email,
-		Document: req.Document,
-		Phone:    req.Phone,
+		Document: doc,
+		Phone:    phoneNumber,
 	})
 }
 
@@ -156,9 +220,39 @@ func (uc *paymentUseCase) CreateCardPayment(ctx context.Context, req *CreateCard
 	}
 
 	gwReq := req.toGatewayRequest()
+	doc, err := normalizeDocument("holder_cpf", req.HolderCPF)
+	if err != nil {
+		return nil, err
+	}
+	gwReq.HolderDoc = doc
+	if gwReq.HolderPhone, err = normalizePhone("holder_phone", req.HolderPhone); err != nil {
+		return nil, err
+	}
 	return uc.gw.CreateCardPayment(ctx, gwReq)
 }
 
+// normalizeDocument returns the digits of a valid CPF or CNPJ, so invalid documents are
+// rejected before they reach the gateway
+func normalizeDocument(field, value string) (string, error) {
+	digits, err := document.Normalize(value)
+	if err != nil {
+		return "", apperror.New(apperror.CodeInvalidDocument, "invalid "+field+": enter a valid CPF or CNPJ")
+	}
+	return digits, nil
+}
+
+// normalizePhone returns an optional phone in E.164; an empty phone is left empty
+func normalizePhone(field, value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	n, err := phone.Normalize(value)
+	if err != nil {
+		return "", apperror.New(apperror.CodeInvalidPhone, "invalid "+field+": include the area code")
+	}
+	return n, nil
+}
+
 // GetPaymentStatus retrieves the status of a payment (local DB + gateway)
 func (uc *paymentUseCase) GetPaymentStatus(ctx context.Context, paymentID string) (*PaymentStatusResponse, error) {
 	if paymentID == "" {
@@ -183,7 +277,7 @@ func (uc *paymentUseCase) GetPaymentStatus(ctx context.Context, paymentID string
 			resp.GatewayPaymentID = *payment.GatewayPaymentID
 
 			// Get live status from gateway
-			gwPayment, err := uc.gw.GetPayment(ctx, *payment.GatewayPaymentID)
+			gwPayment, err := gateway.ForPayment(uc.gw, payment.Gateway).GetPayment(ctx, *payment.GatewayPaymentID)
 			if err == nil {
 				resp.GatewayStatus = gwPayment.Status
 				resp.BillingType = gwPayment.BillingType
@@ -203,8 +297,8 @@ func (uc *paymentUseCase) GetPaymentStatus(ctx context.Context, paymentID string
 		ID:               gwPayment.GatewayPaymentID,
 		Status:           gwPayment.Status,
 		GatewayStatus:    gwPayment.GatewayRawStatus,
-		Amount:           gwPayment.Amount,
-		NetAmount:        gwPayment.NetAmount,
+		Amount:           money.FromFloat(gwPayment.Amount),
+		NetAmount:        money.FromFloat(gwPayment.NetAmount),
 		BillingType:      gwPayment.BillingType,
 		DueDate:          gwPayment.DueDate,
 		Gateway:          uc.gw.Name(),
@@ -244,6 +338,66 @@ func (uc *paymentUseCase) GetPaymentsByEnrollment(ctx context.Context, enrollmen
 	return uc.paymentRepo.FindByEnrollmentID(ctx, enrollmentID)
 }
 
+// GetInstallments returns the installment plan of a payment, such as the boletos of a carnê,
+// given the ID of the payment or of one of its installments. Only the payer and admins see it.
+func (uc *paymentUseCase) GetInstallments(ctx context.Context, paymentID, userID, role string) (*entity.PaymentInstallmentPlan, error) {
+	payment, err := uc.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment != nil && payment.InstallmentOf != nil {
+		payment, err = uc.paymentRepo.FindByID(ctx, *payment.InstallmentOf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if role != string(entity.RoleAdmin) && (payment.PayerUserID == nil || *payment.PayerUserID != userID) {
+		return nil, ErrAccessDenied
+	}
+
+	installments, err := uc.paymentRepo.FindInstallments(ctx, payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(installments) == 0 {
+		return nil, ErrNoInstallments
+	}
+	return entity.NewPaymentInstallmentPlan(payment, installments), nil
+}
+
+// GetPaymentTimeline returns the transactions of a payment, oldest first, with readable labels
+func (uc *paymentUseCase) GetPaymentTimeline(ctx context.Context, paymentID string) (*entity.PaymentTimeline, error) {
+	if paymentID == "" {
+		return nil, errors.New("payment ID is required")
+	}
+
+	payment, err := uc.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	txns, err := uc.paymentTxnRepo.FindByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &entity.PaymentTimeline{
+		PaymentID: payment.ID,
+		Status:    payment.Status,
+		Events:    make([]entity.PaymentTimelineEvent, 0, len(txns)),
+	}
+	for _, tx := range txns {
+		timeline.Events = append(timeline.Events, entity.NewPaymentTimelineEvent(tx))
+	}
+	return timeline, nil
+}
+
 // toGatewayRequest converts handler-level request to gateway request
 func (req *CreatePaymentRequest) toGatewayRequest() (*gateway.CreatePaymentRequest, error) {
 	gwReq := &gateway.CreatePaymentRequest{
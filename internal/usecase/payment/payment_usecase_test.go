@@ -2,6 +2,8 @@ package payment
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/condotrack/api/internal/config"
@@ -9,6 +11,7 @@ import (
 	"github.com/condotrack/api/internal/domain/gateway"
 	"github.com/condotrack/api/internal/domain/repository"
 	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/apperror"
 )
 
 func newTestUseCase() (UseCase, *testutil.MockGateway, *testutil.MockPaymentRepository) {
@@ -18,13 +21,15 @@ func newTestUseCase() (UseCase, *testutil.MockGateway, *testutil.MockPaymentRepo
 		RevenueInstructorPercent: 70,
 		RevenuePlatformPercent:   30,
 	}
-	uc := NewUseCase(mockGw, mockRepo, cfg)
+	uc := NewUseCase(mockGw, mockRepo, testutil.NewMockPaymentTransactionRepository(), testutil.NewMockMatriculaRepository(), testutil.NewMockEnrollmentRenewalRepository(), nil, nil, nil, nil, cfg)
 	return uc, mockGw, mockRepo
 }
 
 func TestCreateCustomer_Success(t *testing.T) {
 	uc, mockGw, _ := newTestUseCase()
+	var sentDocument string
 	mockGw.CreateCustomerFunc = func(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+		sentDocument = req.Document
 		return &gateway.CustomerResponse{
 			GatewayID: "cust_123",
 			Name:      req.Name,
@@ -35,7 +40,7 @@ func TestCreateCustomer_Success(t *testing.T) {
 	resp, err := uc.CreateCustomer(context.Background(), &CreateCustomerRequest{
 		Name:     "Test User",
 		Email:    "test@test.com",
-		Document: "12345678900",
+		Document: "529.982.247-25",
 	})
 
 	if err != nil {
@@ -44,6 +49,51 @@ func TestCreateCustomer_Success(t *testing.T) {
 	if resp.GatewayID != "cust_123" {
 		t.Errorf("expected GatewayID cust_123, got %s", resp.GatewayID)
 	}
+	if sentDocument != "52998224725" {
+		t.Errorf("expected the gateway to get the digits of the CPF, got %q", sentDocument)
+	}
+}
+
+func TestCreateCustomer_InvalidDocument(t *testing.T) {
+	uc, mockGw, _ := newTestUseCase()
+	called := false
+	mockGw.CreateCustomerFunc = func(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+		called = true
+		return &gateway.CustomerResponse{}, nil
+	}
+
+	_, err := uc.CreateCustomer(context.Background(), &CreateCustomerRequest{
+		Name:     "Test User",
+		Document: "12345678900",
+	})
+	if appErr, ok := apperror.As(err); !ok || appErr.Code != apperror.CodeInvalidDocument {
+		t.Errorf("expected INVALID_DOCUMENT, got %v", err)
+	}
+	if called {
+		t.Error("expected the gateway not to be called")
+	}
+}
+
+func TestCreateCustomer_Phone(t *testing.T) {
+	uc, mockGw, _ := newTestUseCase()
+	var sentPhone string
+	mockGw.CreateCustomerFunc = func(ctx context.Context, req gateway.CreateCustomerRequest) (*gateway.CustomerResponse, error) {
+		sentPhone = req.Phone
+		return &gateway.CustomerResponse{GatewayID: "cust_123"}, nil
+	}
+
+	req := &CreateCustomerRequest{Name: "Test User", Document: "529.982.247-25", Phone: "(11) 98765-4321"}
+	if _, err := uc.CreateCustomer(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sentPhone != "+5511987654321" {
+		t.Errorf("expected the gateway to get the phone in E.164, got %q", sentPhone)
+	}
+
+	req.Phone = "98765-4321"
+	if _, err := uc.CreateCustomer(context.Background(), req); apperror.CodeOf(err) != apperror.CodeInvalidPhone {
+		t.Errorf("expected INVALID_PHONE, got %v", err)
+	}
 }
 
 func TestCreateCustomer_MissingName(t *testing.T) {
@@ -122,8 +172,8 @@ func TestGetPaymentStatus_FromLocalDB(t *testing.T) {
 	mockRepo.Payments["p1"] = &entity.Payment{
 		ID:               "p1",
 		EnrollmentID:     "e1",
-		GrossAmount:      100,
-		NetAmount:        90,
+		GrossAmount:      10000,
+		NetAmount:        9000,
 		Status:           entity.FinPaymentStatusConfirmed,
 		Gateway:          entity.GatewayAsaas,
 		GatewayPaymentID: &gwPaymentID,
@@ -242,3 +292,75 @@ func TestGetPaymentsByEnrollment(t *testing.T) {
 		t.Errorf("expected 2 payments for e1, got %d", len(payments))
 	}
 }
+
+func TestGetPaymentTimeline(t *testing.T) {
+	paymentRepo := testutil.NewMockPaymentRepository()
+	txnRepo := testutil.NewMockPaymentTransactionRepository()
+	uc := NewUseCase(&testutil.MockGateway{}, paymentRepo, txnRepo, testutil.NewMockMatriculaRepository(), testutil.NewMockEnrollmentRenewalRepository(), nil, nil, nil, nil, &config.Config{})
+
+	paymentRepo.Payments["p1"] = &entity.Payment{ID: "p1", Status: entity.FinPaymentStatusConfirmed}
+	pending := entity.FinPaymentStatusPending
+	event := "PAYMENT_CONFIRMED"
+	txnRepo.Transactions = []*entity.PaymentTransaction{
+		{ID: "t1", PaymentID: "p1", EventType: entity.TxEventCreated, NewStatus: pending},
+		{ID: "t2", PaymentID: "p1", EventType: entity.TxEventWebhookReceived, GatewayEventID: &event, NewStatus: "CONFIRMED"},
+		{ID: "t3", PaymentID: "p1", EventType: entity.TxEventStatusChanged, PreviousStatus: &pending, NewStatus: entity.FinPaymentStatusConfirmed},
+		{ID: "t4", PaymentID: "p2", EventType: entity.TxEventCreated, NewStatus: pending},
+	}
+
+	timeline, err := uc.GetPaymentTimeline(context.Background(), "p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timeline.Status != entity.FinPaymentStatusConfirmed || len(timeline.Events) != 3 {
+		t.Fatalf("expected 3 events of a confirmed payment, got %+v", timeline)
+	}
+	if timeline.Events[2].Label != "Status alterado de Pendente para Confirmado" {
+		t.Errorf("unexpected label: %q", timeline.Events[2].Label)
+	}
+}
+
+func TestGetPaymentTimeline_NotFound(t *testing.T) {
+	uc, _, _ := newTestUseCase()
+	if _, err := uc.GetPaymentTimeline(context.Background(), "missing"); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("expected ErrPaymentNotFound, got %v", err)
+	}
+}
+
+func TestGetInstallments(t *testing.T) {
+	uc, _, repo := newTestUseCase()
+	student := "student-1"
+	parentID := "p1"
+	repo.Payments["p1"] = &entity.Payment{ID: "p1", PayerUserID: &student, PaymentMethod: entity.MethodBoleto, InstallmentCount: 3, NetAmount: 30000, Status: entity.FinPaymentStatusConfirmed}
+	for n, status := range []string{entity.FinPaymentStatusReceived, entity.FinPaymentStatusOverdue, entity.FinPaymentStatusPending} {
+		number := n + 1
+		id := fmt.Sprintf("p1-%d", number)
+		repo.Payments[id] = &entity.Payment{ID: id, PayerUserID: &student, InstallmentOf: &parentID, InstallmentNumber: &number, NetAmount: 10000, Status: status}
+	}
+	repo.Payments["single"] = &entity.Payment{ID: "single", PayerUserID: &student}
+
+	// An installment resolves to the plan of its parent
+	plan, err := uc.GetInstallments(context.Background(), "p1-2", student, "student")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.PaymentID != "p1" || len(plan.Installments) != 3 || plan.Installments[1].PaymentID != "p1-2" {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if plan.PaidCount != 1 || plan.OverdueCount != 1 || plan.PaidAmount != 10000 || plan.TotalAmount != 30000 {
+		t.Errorf("unexpected totals: %+v", plan)
+	}
+
+	if _, err := uc.GetInstallments(context.Background(), "p1", "someone-else", "student"); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("expected ErrAccessDenied, got %v", err)
+	}
+	if _, err := uc.GetInstallments(context.Background(), "p1", "admin-1", string(entity.RoleAdmin)); err != nil {
+		t.Errorf("expected admins to see the plan, got %v", err)
+	}
+	if _, err := uc.GetInstallments(context.Background(), "single", student, "student"); !errors.Is(err, ErrNoInstallments) {
+		t.Errorf("expected ErrNoInstallments, got %v", err)
+	}
+	if _, err := uc.GetInstallments(context.Background(), "missing", student, "student"); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("expected ErrPaymentNotFound, got %v", err)
+	}
+}
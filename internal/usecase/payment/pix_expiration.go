@@ -0,0 +1,177 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/google/uuid"
+)
+
+// pixExpirationBatch is how many expired PIX payments a run cancels; the rest wait for the
+// next run, as do payments whose gateway charge could not be cancelled
+const pixExpirationBatch = 100
+
+// PixExpirationResult summarizes a run of the PIX expiration worker
+type PixExpirationResult struct {
+	Cancelled int `json:"cancelled"`
+	Expired   int `json:"expired"` // enrollments marked expired
+	Skipped   int `json:"skipped"` // paid on the gateway, settled by its webhook
+	Failed    int `json:"failed"`
+}
+
+// ExpirePixPayments cancels the unpaid PIX payments whose QR code expired: the gateway charge
+// is cancelled first, so a payment is only cancelled locally once nobody can pay it. The
+// enrollment waiting for that payment is marked expired and the student is told how to retry.
+func (uc *paymentUseCase) ExpirePixPayments(ctx context.Context, now time.Time) (*PixExpirationResult, error) {
+	payments, err := uc.paymentRepo.FindExpiredPix(ctx, now, pixExpirationBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PixExpirationResult{}
+	for i := range payments {
+		if ctx.Err() != nil {
+			break
+		}
+		payment := &payments[i]
+		cancelled, err := uc.expirePix(ctx, payment, now)
+		switch {
+		case err != nil:
+			log.Printf("[PAYMENT] Failed to cancel expired PIX payment %s: %v", payment.ID, err)
+			result.Failed++
+			continue
+		case !cancelled:
+			result.Skipped++
+			continue
+		}
+		result.Cancelled++
+
+		expired, err := uc.expireEnrollment(ctx, payment)
+		if err != nil {
+			log.Printf("[PAYMENT] Failed to expire enrollment %s of PIX payment %s: %v", payment.EnrollmentID, payment.ID, err)
+			continue
+		}
+		if expired != nil {
+			result.Expired++
+			uc.notifyPixExpired(ctx, payment, expired)
+		}
+	}
+	return result, nil
+}
+
+// StartPixExpirationWorker cancels expired PIX payments every interval
+func (uc *paymentUseCase) StartPixExpirationWorker(lc *lifecycle.Manager, interval time.Duration) {
+	lc.Every("pix expiration", interval, false, func(ctx context.Context) {
+		result, err := uc.ExpirePixPayments(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[PAYMENT] PIX expiration run failed: %v", err)
+			}
+			return
+		}
+		if result.Cancelled > 0 || result.Failed > 0 {
+			log.Printf("[PAYMENT] Expired PIX payments cancelled: %d, enrollments expired: %d, failed: %d",
+				result.Cancelled, result.Expired, result.Failed)
+		}
+	})
+}
+
+// expirePix cancels the gateway charge and then the payment. A charge the gateway reports
+// as paid is left alone (false): the student paid just before the expiration and the
+// webhook settles it.
+func (uc *paymentUseCase) expirePix(ctx context.Context, payment *entity.Payment, now time.Time) (bool, error) {
+	if payment.GatewayPaymentID != nil {
+		gw := gateway.ForPayment(uc.gw, payment.Gateway)
+		charge, err := gw.GetPayment(ctx, *payment.GatewayPaymentID)
+		if err != nil {
+			return false, gateway.Failure(err)
+		}
+		switch charge.Status {
+		case gateway.StatusConfirmed, gateway.StatusReceived:
+			return false, nil
+		case gateway.StatusCancelled:
+		default:
+			if err := gw.CancelPayment(ctx, *payment.GatewayPaymentID); err != nil {
+				return false, gateway.Failure(err)
+			}
+		}
+	}
+
+	prevStatus := payment.Status
+	payment.Status = entity.FinPaymentStatusCancelled
+	payment.CancelledAt = &now
+	if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+		return false, err
+	}
+	uc.logTransaction(ctx, payment.ID, prevStatus, payment.Status, "pix_expired", payment.NetAmount,
+		"PIX QR code expired, charge cancelled", "")
+	return true, nil
+}
+
+// expireEnrollment marks expired the enrollment that was waiting for the payment. Active
+// enrollments (e.g. a renewal charge) and enrollments already linked to another charge are
+// left as they are, and nil is returned.
+func (uc *paymentUseCase) expireEnrollment(ctx context.Context, payment *entity.Payment) (*entity.Matricula, error) {
+	enrollment, err := uc.matriculaRepo.FindByID(ctx, payment.EnrollmentID)
+	if err != nil || enrollment == nil {
+		return nil, err
+	}
+	if enrollment.Status != entity.EnrollmentStatusPending || derefString(enrollment.AsaasPaymentID) != derefString(payment.GatewayPaymentID) {
+		return nil, nil
+	}
+
+	enrollment.Status = entity.EnrollmentStatusExpired
+	enrollment.PaymentStatus = entity.PaymentStatusOverdue
+	if err := uc.matriculaRepo.Update(ctx, enrollment); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// notifyPixExpired sends the student the link to retry the checkout (non-critical)
+func (uc *paymentUseCase) notifyPixExpired(ctx context.Context, payment *entity.Payment, enrollment *entity.Matricula) {
+	userID := enrollment.StudentID
+	if payment.PayerUserID != nil && *payment.PayerUserID != "" {
+		userID = *payment.PayerUserID
+	}
+	if userID == "" {
+		return
+	}
+
+	fields := map[string]string{
+		"payment_id":    payment.ID,
+		"enrollment_id": enrollment.ID,
+	}
+	message := fmt.Sprintf("O PIX da sua matrícula em %s expirou e a cobrança foi cancelada. Gere um novo pagamento para garantir sua vaga.", enrollment.CourseName)
+	if uc.retryURL != "" {
+		fields["retry_url"] = uc.retryURL + "/" + enrollment.ID
+		message += " Acesse: " + fields["retry_url"]
+	}
+	raw, _ := json.Marshal(fields)
+	data := string(raw)
+	notif := &entity.Notificacao{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Type:      entity.NotificationTypePayment,
+		Title:     "PIX expirado",
+		Message:   message,
+		Data:      &data,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.notifier.Create(ctx, notif); err != nil {
+		log.Printf("[PAYMENT] Failed to notify student of expired PIX %s: %v", payment.ID, err)
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
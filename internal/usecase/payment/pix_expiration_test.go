@@ -0,0 +1,97 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/condotrack/api/pkg/realtime"
+)
+
+func TestExpirePixPayments(t *testing.T) {
+	f := newReissueFixture()
+	notifier := notification.NewUseCase(f.notifs, realtime.NewHub(), nil)
+	f.uc = NewUseCase(f.gw, f.payments, f.txns, f.enrollments, f.renewals, notifier, nil, nil, nil,
+		&config.Config{CheckoutRetryURL: "https://app.condotrack.com.br/checkout/"})
+	expirePix(f)
+	f.enrollments.Enrollments["e1"].Status = entity.EnrollmentStatusPending
+
+	// A renewal charge of an active enrollment, and a charge still payable
+	now := time.Now()
+	later, renewalCharge, otherCharge := now.Add(time.Hour), "pay_renewal", "pay_live"
+	f.payments.Payments["p2"] = &entity.Payment{
+		ID: "p2", EnrollmentID: "e2", PaymentMethod: entity.MethodPIX, Status: entity.FinPaymentStatusOverdue,
+		GatewayPaymentID: &renewalCharge, CreatedAt: now,
+	}
+	f.enrollments.Enrollments["e2"] = &entity.Matricula{ID: "e2", StudentID: "stu-2", Status: entity.EnrollmentStatusActive}
+	f.payments.Payments["p3"] = &entity.Payment{
+		ID: "p3", EnrollmentID: "e3", PaymentMethod: entity.MethodPIX, Status: entity.FinPaymentStatusPending,
+		GatewayPaymentID: &otherCharge, ExpiresAt: &later,
+	}
+
+	result, err := f.uc.ExpirePixPayments(context.Background(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Cancelled != 2 || result.Expired != 1 || result.Failed != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(f.cancelled) != 2 {
+		t.Errorf("expected both gateway charges to be cancelled, got %v", f.cancelled)
+	}
+	if p := f.payments.Payments["p1"]; p.Status != entity.FinPaymentStatusCancelled || p.CancelledAt == nil {
+		t.Errorf("expected the expired payment to be cancelled: %+v", p)
+	}
+	if p := f.payments.Payments["p3"]; p.Status != entity.FinPaymentStatusPending {
+		t.Errorf("expected the payable charge to be kept, got %q", p.Status)
+	}
+
+	if e := f.enrollments.Enrollments["e1"]; e.Status != entity.EnrollmentStatusExpired || e.PaymentStatus != entity.PaymentStatusOverdue {
+		t.Errorf("expected the enrollment to expire, got %q/%q", e.Status, e.PaymentStatus)
+	}
+	if e := f.enrollments.Enrollments["e2"]; e.Status != entity.EnrollmentStatusActive {
+		t.Errorf("expected the active enrollment to be kept, got %q", e.Status)
+	}
+
+	if len(f.notifs.Notifications) != 1 {
+		t.Fatalf("expected one notification, got %d", len(f.notifs.Notifications))
+	}
+	for _, n := range f.notifs.Notifications {
+		if n.UserID != "stu-1" || n.Data == nil || !strings.Contains(*n.Data, `"retry_url":"https://app.condotrack.com.br/checkout/e1"`) {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+	}
+}
+
+func TestExpirePixPayments_GatewayState(t *testing.T) {
+	f := newReissueFixture()
+	expirePix(f)
+	f.enrollments.Enrollments["e1"].Status = entity.EnrollmentStatusPending
+
+	// Paid just before the expiration: the webhook settles it
+	f.gw.GetPaymentFunc = func(ctx context.Context, id string) (*gateway.PaymentResponse, error) {
+		return &gateway.PaymentResponse{GatewayPaymentID: id, Status: gateway.StatusReceived}, nil
+	}
+	result, _ := f.uc.ExpirePixPayments(context.Background(), time.Now())
+	if result.Skipped != 1 || len(f.cancelled) != 0 || f.payments.Payments["p1"].Status != entity.FinPaymentStatusPending {
+		t.Errorf("expected a paid charge to be left alone, got %+v", result)
+	}
+
+	// The charge could not be cancelled: nothing changes until the next run
+	f.gw.GetPaymentFunc = nil
+	f.gw.CancelPaymentFunc = func(ctx context.Context, id string) error { return errors.New("gateway down") }
+	result, _ = f.uc.ExpirePixPayments(context.Background(), time.Now())
+	if result.Failed != 1 || f.payments.Payments["p1"].Status != entity.FinPaymentStatusPending ||
+		f.enrollments.Enrollments["e1"].Status != entity.EnrollmentStatusPending {
+		t.Errorf("expected the payment to wait for the next run, got %+v", result)
+	}
+	if len(f.notifs.Notifications) != 0 {
+		t.Errorf("expected no notification, got %d", len(f.notifs.Notifications))
+	}
+}
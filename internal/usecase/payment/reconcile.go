@@ -0,0 +1,221 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/domain/listquery"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+// Reconciliation limits
+const (
+	defaultReconcileLimit = 100
+	maxReconcileLimit     = 500
+)
+
+// Reconciliation actions
+const (
+	ReconcileActionConfirm    = "confirm"
+	ReconcileActionOverdue    = "mark_overdue"
+	ReconcileActionRefund     = "refund"
+	ReconcileActionCancel     = "cancel"
+	ReconcileActionChargeback = "chargeback"
+	ReconcileActionFail       = "mark_failed"
+)
+
+// reconcileStatuses are the local statuses still waiting for a gateway event
+var reconcileStatuses = []string{
+	entity.FinPaymentStatusPending,
+	entity.FinPaymentStatusAwaitingPayment,
+	entity.FinPaymentStatusOverdue,
+}
+
+// EventApplier applies a canonical gateway event the way its webhook would. The webhook
+// handler implements it, so a reconciled payment goes through the same split, ledger and
+// enrollment updates as one confirmed by the gateway.
+type EventApplier interface {
+	ApplyEvent(ctx context.Context, event *gateway.WebhookEvent) error
+}
+
+// ReconcileRequest is the request to reconcile open payments against their gateway
+type ReconcileRequest struct {
+	DryRun  bool   `json:"dry_run"`
+	Limit   int    `json:"limit,omitempty" binding:"omitempty,min=1"`
+	Gateway string `json:"gateway,omitempty"`
+}
+
+// ReconcileDiff is a payment whose local status differs from the gateway's
+type ReconcileDiff struct {
+	PaymentID        string `json:"payment_id"`
+	Gateway          string `json:"gateway"`
+	GatewayPaymentID string `json:"gateway_payment_id"`
+	LocalStatus      string `json:"local_status"`
+	GatewayStatus    string `json:"gateway_status"`
+	Action           string `json:"action,omitempty"`
+	Applied          bool   `json:"applied"`
+	Error            string `json:"error,omitempty"`
+}
+
+// ReconcileReport is the outcome of a reconciliation run
+type ReconcileReport struct {
+	DryRun   bool            `json:"dry_run"`
+	Checked  int             `json:"checked"`
+	Drifted  int             `json:"drifted"`
+	Applied  int             `json:"applied"`
+	Failed   int             `json:"failed"`
+	Payments []ReconcileDiff `json:"payments"`
+}
+
+// SetEventApplier sets where reconciled events are applied. The webhook handler is built
+// after the use case, so it is wired afterwards.
+func (uc *paymentUseCase) SetEventApplier(applier EventApplier) {
+	uc.applier = applier
+}
+
+// Reconcile queries the gateway for the oldest open payments and fixes the local status of
+// the ones whose webhook was missed. A dry run only reports the differences.
+func (uc *paymentUseCase) Reconcile(ctx context.Context, req *ReconcileRequest) (*ReconcileReport, error) {
+	if !req.DryRun && uc.applier == nil {
+		return nil, errors.New("payment reconciliation is not configured")
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultReconcileLimit
+	}
+	limit = min(limit, maxReconcileLimit)
+
+	payments, _, err := uc.paymentRepo.FindAll(ctx, repository.PaymentFilters{
+		Gateway: req.Gateway,
+		PerPage: limit,
+		Query: listquery.Query{
+			Filters: []listquery.Filter{{Field: "status", Op: listquery.OpIn, Value: strings.Join(reconcileStatuses, ",")}},
+			Sort:    []listquery.Sort{{Field: "created_at"}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconcileReport{DryRun: req.DryRun, Payments: []ReconcileDiff{}}
+	for i := range payments {
+		p := &payments[i]
+		if !isReconcilable(p) {
+			continue
+		}
+		report.Checked++
+
+		diff := ReconcileDiff{
+			PaymentID:        p.ID,
+			Gateway:          p.Gateway,
+			GatewayPaymentID: *p.GatewayPaymentID,
+			LocalStatus:      p.Status,
+		}
+		remote, err := gateway.ForPayment(uc.gw, p.Gateway).GetPayment(ctx, *p.GatewayPaymentID)
+		if err != nil {
+			diff.Error = err.Error()
+			report.Failed++
+			report.Payments = append(report.Payments, diff)
+			continue
+		}
+		diff.GatewayStatus = remote.Status
+		diff.Action = reconcileAction(p.Status, remote.Status)
+		if diff.Action == "" {
+			continue
+		}
+		report.Drifted++
+
+		if !req.DryRun {
+			if err := uc.applyReconcile(ctx, p, remote, diff.Action); err != nil {
+				log.Printf("Failed to reconcile payment %s: %v", p.ID, err)
+				diff.Error = err.Error()
+				report.Failed++
+			} else {
+				diff.Applied = true
+				report.Applied++
+			}
+		}
+		report.Payments = append(report.Payments, diff)
+	}
+
+	log.Printf("Payment reconciliation: dry_run=%t checked=%d drifted=%d applied=%d failed=%d",
+		req.DryRun, report.Checked, report.Drifted, report.Applied, report.Failed)
+	return report, nil
+}
+
+// applyReconcile replays the missed webhook of a payment. Failed charges have no webhook
+// handler, so their status is set directly.
+func (uc *paymentUseCase) applyReconcile(ctx context.Context, p *entity.Payment, remote *gateway.PaymentResponse, action string) error {
+	if action == ReconcileActionFail {
+		return uc.paymentRepo.UpdateStatus(ctx, p.ID, entity.FinPaymentStatusFailed)
+	}
+
+	paidAt := remote.PaidAt
+	if paidAt == nil {
+		paidAt = remote.ConfirmedAt
+	}
+	return uc.applier.ApplyEvent(ctx, &gateway.WebhookEvent{
+		// Claimed like a gateway event ID, so concurrent runs confirm a payment once
+		EventID:          "reconcile:" + *p.GatewayPaymentID + ":" + remote.Status,
+		EventType:        reconcileEvents[action],
+		GatewayEvent:     "RECONCILE",
+		GatewayName:      p.Gateway,
+		PaymentID:        *p.GatewayPaymentID,
+		Amount:           remote.Amount,
+		NetAmount:        remote.NetAmount,
+		Status:           remote.Status,
+		GatewayRawStatus: remote.GatewayRawStatus,
+		BillingType:      remote.BillingType,
+		PaidAt:           paidAt,
+		Settled:          remote.Status == gateway.StatusReceived,
+	})
+}
+
+// reconcileEvents maps the actions replayed through the webhook handler to their event
+var reconcileEvents = map[string]string{
+	ReconcileActionConfirm:    gateway.EventPaymentConfirmed,
+	ReconcileActionOverdue:    gateway.EventPaymentOverdue,
+	ReconcileActionRefund:     gateway.EventPaymentRefunded,
+	ReconcileActionCancel:     gateway.EventPaymentDeleted,
+	ReconcileActionChargeback: gateway.EventPaymentChargeback,
+}
+
+// reconcileAction returns the action that brings an open local status in line with the
+// gateway status, or "" when they agree. A pending charge never reverts an overdue payment,
+// the gateway keeps some overdue charges payable.
+func reconcileAction(local, remote string) string {
+	switch remote {
+	case gateway.StatusConfirmed, gateway.StatusReceived:
+		return ReconcileActionConfirm
+	case gateway.StatusOverdue:
+		if local != entity.FinPaymentStatusOverdue {
+			return ReconcileActionOverdue
+		}
+	case gateway.StatusRefunded:
+		return ReconcileActionRefund
+	case gateway.StatusCancelled:
+		return ReconcileActionCancel
+	case gateway.StatusChargeback:
+		return ReconcileActionChargeback
+	case gateway.StatusFailed:
+		return ReconcileActionFail
+	}
+	return ""
+}
+
+// isReconcilable reports whether a payment is open and has a gateway charge to query
+func isReconcilable(p *entity.Payment) bool {
+	if p.GatewayPaymentID == nil || *p.GatewayPaymentID == "" {
+		return false
+	}
+	for _, s := range reconcileStatuses {
+		if p.Status == s {
+			return true
+		}
+	}
+	return false
+}
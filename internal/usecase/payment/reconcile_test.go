@@ -0,0 +1,167 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+type recordingApplier struct {
+	events []*gateway.WebhookEvent
+	err    error
+}
+
+func (a *recordingApplier) ApplyEvent(ctx context.Context, event *gateway.WebhookEvent) error {
+	a.events = append(a.events, event)
+	return a.err
+}
+
+func newReconcileFixture(remote map[string]string) (UseCase, *testutil.MockPaymentRepository, *recordingApplier) {
+	payments := testutil.NewMockPaymentRepository()
+	gw := &testutil.MockGateway{
+		GetPaymentFunc: func(ctx context.Context, id string) (*gateway.PaymentResponse, error) {
+			status, ok := remote[id]
+			if !ok {
+				return nil, errors.New("charge not found")
+			}
+			return &gateway.PaymentResponse{GatewayPaymentID: id, Status: status, Amount: 100, NetAmount: 99}, nil
+		},
+	}
+	uc := NewUseCase(gw, payments, testutil.NewMockPaymentTransactionRepository(), testutil.NewMockMatriculaRepository(),
+		testutil.NewMockEnrollmentRenewalRepository(), nil, nil, nil, nil, &config.Config{})
+	applier := &recordingApplier{}
+	uc.SetEventApplier(applier)
+	return uc, payments, applier
+}
+
+func addPayment(repo *testutil.MockPaymentRepository, id, status, charge string) {
+	p := &entity.Payment{ID: id, Gateway: "mock", Status: status}
+	if charge != "" {
+		p.GatewayPaymentID = &charge
+	}
+	repo.Payments[id] = p
+}
+
+func TestReconcile_DryRunReportsDrifts(t *testing.T) {
+	uc, payments, applier := newReconcileFixture(map[string]string{
+		"ch1": gateway.StatusReceived,
+		"ch2": gateway.StatusPending,
+	})
+	addPayment(payments, "p1", entity.FinPaymentStatusPending, "ch1")
+	addPayment(payments, "p2", entity.FinPaymentStatusAwaitingPayment, "ch2")
+
+	report, err := uc.Reconcile(context.Background(), &ReconcileRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Checked != 2 || report.Drifted != 1 || report.Applied != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(report.Payments) != 1 {
+		t.Fatalf("expected 1 diff, got %+v", report.Payments)
+	}
+	diff := report.Payments[0]
+	if diff.PaymentID != "p1" || diff.Action != ReconcileActionConfirm || diff.GatewayStatus != gateway.StatusReceived || diff.Applied {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if len(applier.events) != 0 {
+		t.Errorf("dry run applied %d events", len(applier.events))
+	}
+}
+
+func TestReconcile_AppliesMissedEvents(t *testing.T) {
+	uc, payments, applier := newReconcileFixture(map[string]string{
+		"ch1": gateway.StatusReceived,
+		"ch2": gateway.StatusFailed,
+	})
+	addPayment(payments, "p1", entity.FinPaymentStatusPending, "ch1")
+	addPayment(payments, "p2", entity.FinPaymentStatusPending, "ch2")
+
+	report, err := uc.Reconcile(context.Background(), &ReconcileRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Drifted != 2 || report.Applied != 2 || report.Failed != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if len(applier.events) != 1 {
+		t.Fatalf("expected 1 replayed event, got %d", len(applier.events))
+	}
+	ev := applier.events[0]
+	if ev.EventType != gateway.EventPaymentConfirmed || ev.PaymentID != "ch1" || ev.GatewayName != "mock" || !ev.Settled || ev.NetAmount != 99 {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+	if ev.EventID == "" {
+		t.Error("expected the replayed event to carry an event ID")
+	}
+	if got := payments.Payments["p2"].Status; got != entity.FinPaymentStatusFailed {
+		t.Errorf("expected failed charge to be marked failed, got %q", got)
+	}
+}
+
+func TestReconcile_SkipsClosedAndChargelessPayments(t *testing.T) {
+	uc, payments, _ := newReconcileFixture(map[string]string{"ch1": gateway.StatusRefunded})
+	addPayment(payments, "p1", entity.FinPaymentStatusConfirmed, "ch1")
+	addPayment(payments, "p2", entity.FinPaymentStatusPending, "")
+
+	report, err := uc.Reconcile(context.Background(), &ReconcileRequest{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Checked != 0 || len(report.Payments) != 0 {
+		t.Errorf("expected nothing to check, got %+v", report)
+	}
+}
+
+func TestReconcile_ReportsGatewayErrors(t *testing.T) {
+	uc, payments, _ := newReconcileFixture(map[string]string{})
+	addPayment(payments, "p1", entity.FinPaymentStatusOverdue, "ch_missing")
+
+	report, err := uc.Reconcile(context.Background(), &ReconcileRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Failed != 1 || len(report.Payments) != 1 || report.Payments[0].Error == "" {
+		t.Errorf("expected the gateway error in the report, got %+v", report)
+	}
+}
+
+func TestReconcile_FailedApplyIsReported(t *testing.T) {
+	uc, payments, applier := newReconcileFixture(map[string]string{"ch1": gateway.StatusCancelled})
+	applier.err = errors.New("db down")
+	addPayment(payments, "p1", entity.FinPaymentStatusPending, "ch1")
+
+	report, err := uc.Reconcile(context.Background(), &ReconcileRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Applied != 0 || report.Failed != 1 || report.Payments[0].Applied || report.Payments[0].Error != "db down" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if applier.events[0].EventType != gateway.EventPaymentDeleted {
+		t.Errorf("expected a deletion event, got %s", applier.events[0].EventType)
+	}
+}
+
+func TestReconcileAction(t *testing.T) {
+	tests := []struct {
+		local, remote, want string
+	}{
+		{entity.FinPaymentStatusPending, gateway.StatusConfirmed, ReconcileActionConfirm},
+		{entity.FinPaymentStatusPending, gateway.StatusOverdue, ReconcileActionOverdue},
+		{entity.FinPaymentStatusOverdue, gateway.StatusOverdue, ""},
+		{entity.FinPaymentStatusOverdue, gateway.StatusPending, ""},
+		{entity.FinPaymentStatusPending, gateway.StatusRefunded, ReconcileActionRefund},
+		{entity.FinPaymentStatusPending, gateway.StatusChargeback, ReconcileActionChargeback},
+	}
+	for _, tt := range tests {
+		if got := reconcileAction(tt.local, tt.remote); got != tt.want {
+			t.Errorf("reconcileAction(%q, %q) = %q, want %q", tt.local, tt.remote, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/google/uuid"
+)
+
+// reissueDays is how long a replacement charge stays payable when no due date is given, the
+// same term checkout gives the first one
+const reissueDays = 3
+
+// pixExpirationLayout is the layout of gateway.PaymentResponse.PixExpiration
+const pixExpirationLayout = "2006-01-02 15:04:05"
+
+var (
+	// ErrBoletoNotReissuable is returned for payments that are not overdue boletos
+	ErrBoletoNotReissuable = apperror.New(apperror.CodeConflict, "only overdue boleto payments can be reissued")
+
+	// ErrPixNotRegenerable is returned for payments that are not expired, unpaid PIX charges
+	ErrPixNotRegenerable = apperror.New(apperror.CodeConflict, "only expired PIX payments can be regenerated")
+
+	// ErrAccessDenied is returned when a user acts on a payment of someone else
+	ErrAccessDenied = errors.New("access restricted to your own payments")
+)
+
+// ReissueBoletoRequest is the request to issue a second copy of an overdue boleto
+type ReissueBoletoRequest struct {
+	DueDate string `json:"due_date,omitempty"` // YYYY-MM-DD, defaults to 3 days from now
+}
+
+// ReissueBoletoResponse is the new boleto of a payment
+type ReissueBoletoResponse struct {
+	PaymentID        string `json:"payment_id"`
+	GatewayPaymentID string `json:"gateway_payment_id"`
+	Status           string `json:"status"`
+	DueDate          string `json:"due_date"`
+	BoletoURL        string `json:"boleto_url,omitempty"`
+	InvoiceURL       string `json:"invoice_url,omitempty"`
+	BarCode          string `json:"bar_code,omitempty"`
+}
+
+// ReissueBoleto replaces the charge of an overdue boleto with a new one due on the requested
+// date. The payment keeps its ID and points to the new charge.
+func (uc *paymentUseCase) ReissueBoleto(ctx context.Context, paymentID string, req *ReissueBoletoRequest, triggeredBy string) (*ReissueBoletoResponse, error) {
+	dueDate, err := reissueDueDate(req.DueDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := uc.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if payment.PaymentMethod != entity.MethodBoleto || payment.Status != entity.FinPaymentStatusOverdue {
+		return nil, ErrBoletoNotReissuable
+	}
+	if payment.GatewayPaymentID == nil || payment.GatewayCustomerID == nil {
+		return nil, errors.New("payment has no gateway charge to reissue")
+	}
+
+	enrollment, err := uc.matriculaRepo.FindByID(ctx, payment.EnrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldChargeID := *payment.GatewayPaymentID
+	charge, err := replaceCharge(ctx, gateway.ForPayment(uc.gw, payment.Gateway), oldChargeID, func(gw gateway.PaymentGateway) (*gateway.PaymentResponse, error) {
+		return gw.CreateBoletoPayment(ctx, gateway.CreatePaymentRequest{
+			CustomerGatewayID: *payment.GatewayCustomerID,
+			Amount:            payment.NetAmount.Float(),
+			Description:       chargeDescription(enrollment, "segunda via"),
+			DueDate:           dueDate,
+			ExternalReference: payment.EnrollmentID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prevStatus := payment.Status
+	boletoURL := charge.BoletoURL
+	if boletoURL == "" {
+		boletoURL = charge.InvoiceURL
+	}
+	payment.GatewayPaymentID = &charge.GatewayPaymentID
+	payment.GatewayInvoiceURL = nilIfEmpty(boletoURL)
+	payment.DueDate = &dueDate
+	payment.Status = entity.FinPaymentStatusPending
+	if err := uc.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	if relinkEnrollment(enrollment, oldChargeID, charge.GatewayPaymentID) {
+		if err := uc.matriculaRepo.Update(ctx, enrollment); err != nil {
+			return nil, err
+		}
+	}
+
+	uc.logTransaction(ctx, payment.ID, prevStatus, payment.Status, "boleto_reissued", payment.NetAmount,
+		fmt.Sprintf("boleto reissued, replacing %s, due %s", oldChargeID, dueDate.Format("2006-01-02")), triggeredBy)
+	uc.notifyBoletoReissued(ctx, payment, boletoURL)
+
+	return &ReissueBoletoResponse{
+		PaymentID:        payment.ID,
+		GatewayPaymentID: charge.GatewayPaymentID,
+		Status:           payment.Status,
+		DueDate:          dueDate.Format("2006-01-02"),
+		BoletoURL:        charge.BoletoURL,
+		InvoiceURL:       charge.InvoiceURL,
+		BarCode:          charge.BoletoBarCode,
+	}, nil
+}
+
+// reissueDueDate parses the requested due date, which must be after today
+func reissueDueDate(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return now.AddDate(0, 0, reissueDays), nil
+	}
+	dueDate, err := parseDateString(value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid due_date format (use YYYY-MM-DD)")
+	}
+	if !dueDate.After(now) {
+		return time.Time{}, errors.New("invalid due_date: must be after today")
+	}
+	return dueDate, nil
+}
+
+// notifyBoletoReissued sends the new boleto link to the student (non-critical)
+func (uc *paymentUseCase) notifyBoletoReissued(ctx context.Context, payment *entity.Payment, boletoURL string) {
+	if payment.PayerUserID == nil || *payment.PayerUserID == "" {
+		return
+	}
+	raw, _ := json.Marshal(map[string]string{
+		"payment_id": payment.ID,
+		"boleto_url": boletoURL,
+		"due_date":   payment.DueDate.Format("2006-01-02"),
+	})
+	data := string(raw)
+	notif := &entity.Notificacao{
+		ID:        uuid.New().String(),
+		UserID:    *payment.PayerUserID,
+		Type:      entity.NotificationTypePayment,
+		Title:     "Segunda via do boleto",
+		Message:   fmt.Sprintf("A segunda via do seu boleto está disponível, com vencimento em %s.", payment.DueDate.Format("02/01/2006")),
+		Data:      &data,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.notifier.Create(ctx, notif); err != nil {
+		log.Printf("[PAYMENT] Failed to notify student of reissued boleto %s: %v", payment.ID, err)
+	}
+}
+
+// RegeneratePixResponse is the new PIX charge of an expired payment
+type RegeneratePixResponse struct {
+	PaymentID         string      `json:"payment_id"`
+	ReplacedPaymentID string      `json:"replaced_payment_id"`
+	EnrollmentID      string      `json:"enrollment_id"`
+	Status            string      `json:"status"`
+	Amount            money.Cents `json:"amount"`
+	PixQRCode         string      `json:"pix_qr_code,omitempty"`
+	PixCopyPaste      string      `json:"pix_copy_paste,omitempty"`
+	PixExpiration     string      `json:"pix_expiration,omitempty"`
+}
+
+// RegeneratePix replaces an expired PIX charge with a new one. Admins can regenerate any
+// payment and other users only the ones they pay. The expired payment is cancelled and a new
+// payment of the same enrollment holds the new charge, so each QR code keeps its own record.
+// The expired payment stays locked until the new one, the enrollment and the renewal point
+// to each other, so a concurrent regeneration waits and then finds it cancelled.
+func (uc *paymentUseCase) RegeneratePix(ctx context.Context, paymentID, userID, role string) (*RegeneratePixResponse, error) {
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	old, err := uc.paymentRepo.FindByIDForUpdateWithTx(ctx, tx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if old == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if role != string(entity.RoleAdmin) && (old.PayerUserID == nil || *old.PayerUserID != userID) {
+		return nil, ErrAccessDenied
+	}
+	now := time.Now()
+	if !old.PixExpired(now) {
+		return nil, ErrPixNotRegenerable
+	}
+	if old.GatewayPaymentID == nil || old.GatewayCustomerID == nil {
+		return nil, errors.New("payment has no gateway charge to regenerate")
+	}
+
+	enrollment, err := uc.matriculaRepo.FindByID(ctx, old.EnrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate := now.AddDate(0, 0, reissueDays)
+	oldChargeID := *old.GatewayPaymentID
+	charge, err := replaceCharge(ctx, gateway.ForPayment(uc.gw, old.Gateway), oldChargeID, func(gw gateway.PaymentGateway) (*gateway.PaymentResponse, error) {
+		return gw.CreatePixPayment(ctx, gateway.CreatePaymentRequest{
+			CustomerGatewayID: *old.GatewayCustomerID,
+			Amount:            old.NetAmount.Float(),
+			Description:       chargeDescription(enrollment, "novo PIX"),
+			DueDate:           dueDate,
+			ExternalReference: old.EnrollmentID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := *old
+	fresh.ID = uuid.New().String()
+	fresh.GatewayPaymentID = &charge.GatewayPaymentID
+	fresh.GatewayInvoiceURL = nilIfEmpty(charge.InvoiceURL)
+	fresh.GatewayMetadata = nil
+	fresh.Status = entity.FinPaymentStatusPending
+	fresh.DueDate = &dueDate
+	fresh.ExpiresAt = nil
+	if t, err := time.ParseInLocation(pixExpirationLayout, charge.PixExpiration, time.Local); err == nil {
+		fresh.ExpiresAt = &t
+	}
+	fresh.PaidAt, fresh.RefundedAt, fresh.CancelledAt, fresh.UpdatedAt = nil, nil, nil, nil
+	fresh.CreatedAt = now
+	if err := uc.paymentRepo.CreateWithTx(ctx, tx, &fresh); err != nil {
+		return nil, err
+	}
+
+	prevStatus := old.Status
+	old.Status = entity.FinPaymentStatusCancelled
+	old.CancelledAt = &now
+	if err := uc.paymentRepo.UpdateWithTx(ctx, tx, old); err != nil {
+		return nil, err
+	}
+
+	if relinkEnrollment(enrollment, oldChargeID, charge.GatewayPaymentID) {
+		if err := uc.matriculaRepo.UpdateWithTx(ctx, tx, enrollment); err != nil {
+			return nil, err
+		}
+	}
+	// Renewal charges are found through enrollment_renewals by the payment ID
+	renewal, err := uc.renewalRepo.FindByPaymentID(ctx, old.ID)
+	if err != nil {
+		return nil, err
+	}
+	if renewal != nil {
+		if err := uc.renewalRepo.UpdatePaymentIDWithTx(ctx, tx, renewal.ID, fresh.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	uc.logTransaction(ctx, old.ID, prevStatus, old.Status, "pix_regenerated", old.NetAmount,
+		"expired PIX replaced by payment "+fresh.ID, userID)
+	uc.logTransaction(ctx, fresh.ID, "", fresh.Status, "pix_regenerated", fresh.NetAmount,
+		"replaces expired PIX payment "+old.ID, userID)
+
+	return &RegeneratePixResponse{
+		PaymentID:         fresh.ID,
+		ReplacedPaymentID: old.ID,
+		EnrollmentID:      fresh.EnrollmentID,
+		Status:            fresh.Status,
+		Amount:            money.FromFloat(charge.Amount),
+		PixQRCode:         charge.PixQRCodeBase64,
+		PixCopyPaste:      charge.PixCopyPaste,
+		PixExpiration:     charge.PixExpiration,
+	}, nil
+}
+
+// replaceCharge creates the replacement of a gateway charge and then cancels the old one. If
+// the cancellation fails the new charge is cancelled instead, so the student never holds two
+// payable charges for the same payment.
+func replaceCharge(ctx context.Context, gw gateway.PaymentGateway, oldChargeID string, create func(gateway.PaymentGateway) (*gateway.PaymentResponse, error)) (*gateway.PaymentResponse, error) {
+	charge, err := create(gw)
+	if err != nil {
+		return nil, gateway.Failure(err)
+	}
+	if err := gw.CancelPayment(ctx, oldChargeID); err != nil {
+		if cancelErr := gw.CancelPayment(ctx, charge.GatewayPaymentID); cancelErr != nil {
+			log.Printf("[PAYMENT] Failed to cancel replacement charge %s of %s: %v", charge.GatewayPaymentID, oldChargeID, cancelErr)
+		}
+		return nil, gateway.Failure(err)
+	}
+	return charge, nil
+}
+
+// relinkEnrollment points the enrollment to the replacement of its first charge; webhooks
+// find the enrollment by that gateway payment ID. It reports whether the enrollment changed
+// and must be saved.
+func relinkEnrollment(enrollment *entity.Matricula, oldChargeID, newChargeID string) bool {
+	if enrollment == nil || enrollment.AsaasPaymentID == nil || *enrollment.AsaasPaymentID != oldChargeID {
+		return false
+	}
+	enrollment.AsaasPaymentID = &newChargeID
+	enrollment.PaymentStatus = entity.PaymentStatusPending
+	return true
+}
+
+// logTransaction records a status change in the payment audit trail (non-critical)
+func (uc *paymentUseCase) logTransaction(ctx context.Context, paymentID, prevStatus, newStatus, event string, amount money.Cents, description, triggeredBy string) {
+	txLog := &entity.PaymentTransaction{
+		ID:             uuid.New().String(),
+		PaymentID:      paymentID,
+		PreviousStatus: nilIfEmpty(prevStatus),
+		NewStatus:      newStatus,
+		EventSource:    entity.EventSourceAPI,
+		EventType:      entity.TxEventStatusChanged,
+		GatewayEventID: &event,
+		Amount:         &amount,
+		Description:    &description,
+		TriggeredBy:    nilIfEmpty(triggeredBy),
+	}
+	if err := uc.paymentTxnRepo.Create(ctx, txLog); err != nil {
+		log.Printf("Failed to log payment transaction: %v", err)
+	}
+}
+
+// chargeDescription describes a replacement charge on the gateway
+func chargeDescription(enrollment *entity.Matricula, kind string) string {
+	if enrollment == nil {
+		return "Pagamento (" + kind + ")"
+	}
+	return "Matrícula: " + enrollment.CourseName + " (" + kind + ")"
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
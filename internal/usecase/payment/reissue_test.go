@@ -0,0 +1,234 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/condotrack/api/pkg/realtime"
+)
+
+type reissueFixture struct {
+	uc          UseCase
+	gw          *testutil.MockGateway
+	payments    *testutil.MockPaymentRepository
+	txns        *testutil.MockPaymentTransactionRepository
+	enrollments *testutil.MockMatriculaRepository
+	renewals    *testutil.MockEnrollmentRenewalRepository
+	notifs      *testutil.MockNotificacaoRepository
+	cancelled   []string
+}
+
+func newReissueFixture() *reissueFixture {
+	f := &reissueFixture{
+		gw:          &testutil.MockGateway{},
+		payments:    testutil.NewMockPaymentRepository(),
+		txns:        testutil.NewMockPaymentTransactionRepository(),
+		enrollments: testutil.NewMockMatriculaRepository(),
+		renewals:    testutil.NewMockEnrollmentRenewalRepository(),
+		notifs:      testutil.NewMockNotificacaoRepository(),
+	}
+	f.gw.CancelPaymentFunc = func(ctx context.Context, id string) error {
+		f.cancelled = append(f.cancelled, id)
+		return nil
+	}
+	notifier := notification.NewUseCase(f.notifs, realtime.NewHub(), nil)
+	f.uc = NewUseCase(f.gw, f.payments, f.txns, f.enrollments, f.renewals, notifier, nil, nil, testutil.NewMockDB(), &config.Config{})
+
+	oldCharge, customer, student := "pay_old", "cus_1", "stu-1"
+	f.payments.Payments["p1"] = &entity.Payment{
+		ID: "p1", EnrollmentID: "e1", PayerUserID: &student, NetAmount: 15000,
+		PaymentMethod: entity.MethodBoleto, Status: entity.FinPaymentStatusOverdue,
+		GatewayPaymentID: &oldCharge, GatewayCustomerID: &customer,
+	}
+	f.enrollments.Enrollments["e1"] = &entity.Matricula{
+		ID: "e1", StudentID: student, CourseName: "NR-10",
+		AsaasPaymentID: &oldCharge, PaymentStatus: entity.PaymentStatusOverdue,
+	}
+	return f
+}
+
+func TestReissueBoleto_Success(t *testing.T) {
+	f := newReissueFixture()
+	var charged gateway.CreatePaymentRequest
+	f.gw.CreateBoletoPaymentFunc = func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		charged = req
+		return &gateway.PaymentResponse{GatewayPaymentID: "pay_new", BoletoURL: "https://boleto/new", Status: gateway.StatusPending}, nil
+	}
+	due := time.Now().AddDate(0, 0, 5).Format("2006-01-02")
+
+	resp, err := f.uc.ReissueBoleto(context.Background(), "p1", &ReissueBoletoRequest{DueDate: due}, "admin-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GatewayPaymentID != "pay_new" || resp.DueDate != due {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if charged.Amount != 150 || charged.CustomerGatewayID != "cus_1" || charged.DueDate.Format("2006-01-02") != due {
+		t.Errorf("unexpected charge: %+v", charged)
+	}
+	if len(f.cancelled) != 1 || f.cancelled[0] != "pay_old" {
+		t.Errorf("expected the old charge to be cancelled, got %v", f.cancelled)
+	}
+
+	p := f.payments.Payments["p1"]
+	if *p.GatewayPaymentID != "pay_new" || p.Status != entity.FinPaymentStatusPending || *p.GatewayInvoiceURL != "https://boleto/new" {
+		t.Errorf("payment not updated: %+v", p)
+	}
+	if e := f.enrollments.Enrollments["e1"]; *e.AsaasPaymentID != "pay_new" || e.PaymentStatus != entity.PaymentStatusPending {
+		t.Errorf("enrollment not pointed to the new charge: %+v", e)
+	}
+	if len(f.txns.Transactions) != 1 || *f.txns.Transactions[0].TriggeredBy != "admin-1" {
+		t.Errorf("expected the reissue to be logged, got %+v", f.txns.Transactions)
+	}
+	if len(f.notifs.Notifications) != 1 {
+		t.Fatalf("expected the student to be notified, got %d notifications", len(f.notifs.Notifications))
+	}
+	for _, n := range f.notifs.Notifications {
+		if n.UserID != "stu-1" || n.Type != entity.NotificationTypePayment || n.Data == nil {
+			t.Errorf("unexpected notification: %+v", n)
+		}
+	}
+}
+
+func TestReissueBoleto_NotOverdue(t *testing.T) {
+	f := newReissueFixture()
+	f.payments.Payments["p1"].Status = entity.FinPaymentStatusPending
+
+	_, err := f.uc.ReissueBoleto(context.Background(), "p1", &ReissueBoletoRequest{}, "")
+	if !errors.Is(err, ErrBoletoNotReissuable) {
+		t.Errorf("expected ErrBoletoNotReissuable, got %v", err)
+	}
+}
+
+func TestReissueBoleto_PastDueDate(t *testing.T) {
+	f := newReissueFixture()
+	_, err := f.uc.ReissueBoleto(context.Background(), "p1", &ReissueBoletoRequest{DueDate: "2020-01-01"}, "")
+	if err == nil || err.Error() != "invalid due_date: must be after today" {
+		t.Errorf("expected a due date error, got %v", err)
+	}
+}
+
+func TestReissueBoleto_CancelFailureKeepsOldCharge(t *testing.T) {
+	f := newReissueFixture()
+	f.gw.CancelPaymentFunc = func(ctx context.Context, id string) error {
+		f.cancelled = append(f.cancelled, id)
+		if id == "pay_old" {
+			return errors.New("already paid")
+		}
+		return nil
+	}
+
+	if _, err := f.uc.ReissueBoleto(context.Background(), "p1", &ReissueBoletoRequest{}, ""); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(f.cancelled) != 2 || f.cancelled[1] != "pay_boleto_mock_123" {
+		t.Errorf("expected the new charge to be cancelled, got %v", f.cancelled)
+	}
+	if *f.payments.Payments["p1"].GatewayPaymentID != "pay_old" {
+		t.Error("expected the payment to keep the old charge")
+	}
+}
+
+func expirePix(f *reissueFixture) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	p := f.payments.Payments["p1"]
+	p.PaymentMethod = entity.MethodPIX
+	p.Status = entity.FinPaymentStatusPending
+	p.DueDate = &yesterday
+}
+
+func TestRegeneratePix_Success(t *testing.T) {
+	f := newReissueFixture()
+	expirePix(f)
+	f.gw.CreatePixPaymentFunc = func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		return &gateway.PaymentResponse{
+			GatewayPaymentID: "pay_new", Amount: req.Amount, Status: gateway.StatusPending,
+			PixCopyPaste: "000201", PixExpiration: "2030-01-01 23:59:59",
+		}, nil
+	}
+
+	resp, err := f.uc.RegeneratePix(context.Background(), "p1", "stu-1", string(entity.RoleStudent))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ReplacedPaymentID != "p1" || resp.PaymentID == "p1" || resp.PixCopyPaste != "000201" || resp.Amount != 15000 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	old := f.payments.Payments["p1"]
+	if old.Status != entity.FinPaymentStatusCancelled || old.CancelledAt == nil {
+		t.Errorf("expected the expired payment to be cancelled: %+v", old)
+	}
+	fresh := f.payments.Payments[resp.PaymentID]
+	if fresh == nil || fresh.EnrollmentID != "e1" || *fresh.GatewayPaymentID != "pay_new" || fresh.Status != entity.FinPaymentStatusPending {
+		t.Fatalf("unexpected new payment: %+v", fresh)
+	}
+	if fresh.ExpiresAt == nil || fresh.ExpiresAt.Year() != 2030 {
+		t.Errorf("expected the QR code expiration to be stored, got %v", fresh.ExpiresAt)
+	}
+	if len(f.cancelled) != 1 || f.cancelled[0] != "pay_old" {
+		t.Errorf("expected the old charge to be cancelled, got %v", f.cancelled)
+	}
+	if e := f.enrollments.Enrollments["e1"]; *e.AsaasPaymentID != "pay_new" {
+		t.Errorf("expected the enrollment to point to the new charge, got %s", *e.AsaasPaymentID)
+	}
+}
+
+func TestRegeneratePix_RelinksRenewal(t *testing.T) {
+	f := newReissueFixture()
+	expirePix(f)
+	other := "pay_first"
+	f.enrollments.Enrollments["e1"].AsaasPaymentID = &other
+	f.renewals.Renewals["r1"] = &entity.EnrollmentRenewal{ID: "r1", EnrollmentID: "e1", PaymentID: "p1", Status: entity.RenewalStatusPending}
+
+	resp, err := f.uc.RegeneratePix(context.Background(), "p1", "admin-1", string(entity.RoleAdmin))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.renewals.Renewals["r1"].PaymentID != resp.PaymentID {
+		t.Errorf("expected the renewal to point to the new payment, got %s", f.renewals.Renewals["r1"].PaymentID)
+	}
+	if *f.enrollments.Enrollments["e1"].AsaasPaymentID != "pay_first" {
+		t.Error("expected the first charge of the enrollment to be kept")
+	}
+}
+
+func TestRegeneratePix_Rejections(t *testing.T) {
+	f := newReissueFixture()
+	expirePix(f)
+	if _, err := f.uc.RegeneratePix(context.Background(), "p1", "stu-2", string(entity.RoleStudent)); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("expected ErrAccessDenied for another student, got %v", err)
+	}
+
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	f.payments.Payments["p1"].DueDate = &tomorrow
+	if _, err := f.uc.RegeneratePix(context.Background(), "p1", "stu-1", string(entity.RoleStudent)); !errors.Is(err, ErrPixNotRegenerable) {
+		t.Errorf("expected ErrPixNotRegenerable for a payable charge, got %v", err)
+	}
+}
+
+func TestRegeneratePix_OnlyOnce(t *testing.T) {
+	f := newReissueFixture()
+	expirePix(f)
+	f.gw.CreatePixPaymentFunc = func(ctx context.Context, req gateway.CreatePaymentRequest) (*gateway.PaymentResponse, error) {
+		return &gateway.PaymentResponse{GatewayPaymentID: "pay_new", Amount: req.Amount, Status: gateway.StatusPending}, nil
+	}
+
+	if _, err := f.uc.RegeneratePix(context.Background(), "p1", "stu-1", string(entity.RoleStudent)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A repeated request finds the expired payment already cancelled by the first one
+	if _, err := f.uc.RegeneratePix(context.Background(), "p1", "stu-1", string(entity.RoleStudent)); !errors.Is(err, ErrPixNotRegenerable) {
+		t.Errorf("expected ErrPixNotRegenerable for a replaced payment, got %v", err)
+	}
+	if len(f.cancelled) != 1 {
+		t.Errorf("expected a single gateway replacement, got %v", f.cancelled)
+	}
+}
@@ -0,0 +1,236 @@
+package payout
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/pkg/money"
+)
+
+const (
+	// maxMicroDepositAttempts is how many wrong amounts fail a micro-deposit verification
+	maxMicroDepositAttempts = 3
+
+	// maxMicroDepositCents bounds the random micro-deposit amount (R$ 0,01 to R$ 0,99)
+	maxMicroDepositCents = 99
+
+	// microDepositReference prefixes the external reference of micro-deposits, followed by the instructor ID
+	microDepositReference = "payout-account:"
+)
+
+// errVerificationChanged is returned when two requests update the verification of an account at once
+var errVerificationChanged = errors.New("invalid payout account: verification changed, try again")
+
+// StartVerification verifies the payout account of an instructor again: after a failure, when
+// the gateway could not be reached, or when the micro-deposit did not arrive. Verified
+// accounts are returned as they are.
+func (uc *transferUseCase) StartVerification(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error) {
+	account, err := uc.accountRepo.FindByInstructorID(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, errors.New("payout account not found")
+	}
+	if account.IsVerified() {
+		return account, nil
+	}
+
+	if err := uc.startVerification(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// ConfirmMicroDeposit verifies the payout account when the amount matches the micro-deposit.
+// After maxMicroDepositAttempts wrong amounts the verification fails and a new micro-deposit
+// has to be requested.
+func (uc *transferUseCase) ConfirmMicroDeposit(ctx context.Context, instructorID string, amount money.Cents) (*entity.InstructorPayoutAccount, error) {
+	account, err := uc.accountRepo.FindByInstructorID(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, errors.New("payout account not found")
+	}
+	if account.VerificationStatus != entity.PayoutAccountPending || account.MicroDepositAmount == nil {
+		return nil, errors.New("invalid payout account: no micro-deposit awaiting confirmation")
+	}
+
+	attempts := account.VerificationAttempts
+	account.VerificationAttempts++
+	matched := amount == *account.MicroDepositAmount
+	switch {
+	case matched:
+		now := time.Now()
+		account.VerificationStatus = entity.PayoutAccountVerified
+		account.VerificationError = nil
+		account.VerifiedAt = &now
+	case account.VerificationAttempts >= maxMicroDepositAttempts:
+		account.VerificationStatus = entity.PayoutAccountFailed
+		setVerificationError(account, "micro-deposit amount did not match")
+	}
+
+	updated, err := uc.accountRepo.UpdateVerification(ctx, account, attempts)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errVerificationChanged
+	}
+
+	if !matched {
+		if account.VerificationStatus == entity.PayoutAccountFailed {
+			return nil, errors.New("invalid amount: does not match the micro-deposit, request a new verification")
+		}
+		return nil, fmt.Errorf("invalid amount: does not match the micro-deposit, %d attempts left",
+			maxMicroDepositAttempts-account.VerificationAttempts)
+	}
+	return account, nil
+}
+
+// startVerification checks the destination of the account with the gateway when it supports
+// it, otherwise sends a micro-deposit for the instructor to confirm. When the gateway cannot
+// be reached the account stays unverified with the error, so verification can be started again.
+func (uc *transferUseCase) startVerification(ctx context.Context, account *entity.InstructorPayoutAccount) error {
+	attempts := account.VerificationAttempts
+	copyVerification(account, &entity.InstructorPayoutAccount{VerificationStatus: entity.PayoutAccountUnverified})
+
+	if uc.gw == nil {
+		setVerificationError(account, "transfer gateway is not available")
+	} else if verifier, ok := uc.gw.(gateway.AccountVerifier); ok {
+		verifyWithGateway(ctx, verifier, account)
+	} else {
+		uc.sendMicroDeposit(ctx, account)
+	}
+
+	updated, err := uc.accountRepo.UpdateVerification(ctx, account, attempts)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return errVerificationChanged
+	}
+	return nil
+}
+
+// verifyWithGateway settles the verification with the answer of the gateway
+func verifyWithGateway(ctx context.Context, verifier gateway.AccountVerifier, account *entity.InstructorPayoutAccount) {
+	method := entity.PayoutVerificationGateway
+	account.VerificationMethod = &method
+
+	result, err := verifier.VerifyAccount(ctx, transferDestination(account))
+	switch {
+	case err != nil:
+		log.Printf("Verification of payout account of instructor %s failed: %v", account.InstructorID, err)
+		setVerificationError(account, err.Error())
+	case result.Valid:
+		now := time.Now()
+		account.VerificationStatus = entity.PayoutAccountVerified
+		account.VerifiedAt = &now
+	default:
+		account.VerificationStatus = entity.PayoutAccountFailed
+		setVerificationError(account, result.Reason)
+	}
+}
+
+// sendMicroDeposit transfers a random amount of cents to the destination of the account.
+// A transfer the gateway rejects outright fails the verification.
+func (uc *transferUseCase) sendMicroDeposit(ctx context.Context, account *entity.InstructorPayoutAccount) {
+	method := entity.PayoutVerificationMicroDeposit
+	account.VerificationMethod = &method
+
+	amount, err := microDepositAmount()
+	if err != nil {
+		setVerificationError(account, err.Error())
+		return
+	}
+
+	req := transferDestination(account)
+	req.Amount = amount.Float()
+	req.Description = "Verificação da conta de repasse"
+	req.ExternalReference = microDepositReference + account.InstructorID
+	resp, err := uc.gw.CreateTransfer(ctx, req)
+	if err != nil {
+		log.Printf("Micro-deposit to payout account of instructor %s failed: %v", account.InstructorID, err)
+		setVerificationError(account, err.Error())
+		return
+	}
+
+	account.MicroDepositAmount = &amount
+	account.MicroDepositTransferID = &resp.GatewayTransferID
+	switch resp.Status {
+	case gateway.TransferStatusFailed, gateway.TransferStatusCancelled:
+		account.VerificationStatus = entity.PayoutAccountFailed
+		setVerificationError(account, resp.FailReason)
+	default:
+		account.VerificationStatus = entity.PayoutAccountPending
+	}
+}
+
+// applyMicroDepositEvent fails a pending verification whose micro-deposit did not go through.
+// Events of earlier micro-deposits, replaced by a new verification, are ignored.
+func (uc *transferUseCase) applyMicroDepositEvent(ctx context.Context, event *gateway.TransferEvent) error {
+	if event.Transfer.Status != gateway.TransferStatusFailed && event.Transfer.Status != gateway.TransferStatusCancelled {
+		return nil
+	}
+
+	instructorID := strings.TrimPrefix(event.Transfer.ExternalReference, microDepositReference)
+	account, err := uc.accountRepo.FindByInstructorID(ctx, instructorID)
+	if err != nil {
+		return err
+	}
+	if account == nil || account.VerificationStatus != entity.PayoutAccountPending ||
+		account.MicroDepositTransferID == nil || *account.MicroDepositTransferID != event.Transfer.GatewayTransferID {
+		return nil
+	}
+
+	account.VerificationStatus = entity.PayoutAccountFailed
+	reason := event.Transfer.FailReason
+	if reason == "" {
+		reason = "micro-deposit was not delivered"
+	}
+	setVerificationError(account, reason)
+	if _, err := uc.accountRepo.UpdateVerification(ctx, account, account.VerificationAttempts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// copyVerification sets the verification of account to the one of from
+func copyVerification(account, from *entity.InstructorPayoutAccount) {
+	account.VerificationStatus = from.VerificationStatus
+	account.VerificationMethod = from.VerificationMethod
+	account.MicroDepositAmount = from.MicroDepositAmount
+	account.MicroDepositTransferID = from.MicroDepositTransferID
+	account.VerificationAttempts = from.VerificationAttempts
+	account.VerificationError = from.VerificationError
+	account.VerifiedAt = from.VerifiedAt
+}
+
+func setVerificationError(account *entity.InstructorPayoutAccount, reason string) {
+	if reason == "" {
+		account.VerificationError = nil
+		return
+	}
+	reason = truncateError(reason)
+	account.VerificationError = &reason
+}
+
+// microDepositAmount picks the micro-deposit amount; it is what proves the instructor has
+// access to the account, so it comes from crypto/rand
+func microDepositAmount() (money.Cents, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(maxMicroDepositCents))
+	if err != nil {
+		return 0, err
+	}
+	return money.Cents(n.Int64() + 1), nil
+}
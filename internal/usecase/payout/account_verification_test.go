@@ -0,0 +1,141 @@
+package payout
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/money"
+)
+
+// verifyingGateway checks destinations instead of relying on micro-deposits
+type verifyingGateway struct {
+	testutil.MockTransferGateway
+	result *gateway.AccountVerification
+	err    error
+}
+
+func (g *verifyingGateway) VerifyAccount(ctx context.Context, req gateway.TransferRequest) (*gateway.AccountVerification, error) {
+	return g.result, g.err
+}
+
+func newVerificationUseCase(gw gateway.TransferGateway) (*transferUseCase, *testutil.MockInstructorPayoutAccountRepository) {
+	accounts := testutil.NewMockInstructorPayoutAccountRepository()
+	return &transferUseCase{
+		accountRepo: accounts,
+		userRepo:    testutil.NewMockUserRepository(&entity.User{ID: "inst-1", Role: "instructor"}),
+		gw:          gw,
+	}, accounts
+}
+
+func pixRequest(key string) *entity.SavePayoutAccountRequest {
+	return &entity.SavePayoutAccountRequest{Method: entity.PayoutMethodPix, PixKey: strPtr(key), PixKeyType: strPtr("EMAIL")}
+}
+
+func TestSaveAccount_MicroDeposit(t *testing.T) {
+	gw := &testutil.MockTransferGateway{}
+	uc, accounts := newVerificationUseCase(gw)
+	ctx := context.Background()
+
+	account, err := uc.SaveAccount(ctx, "inst-1", pixRequest("a@b.com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.VerificationStatus != entity.PayoutAccountPending || len(gw.Transfers) != 1 {
+		t.Fatalf("expected a micro-deposit to be sent, got %q after %d transfers", account.VerificationStatus, len(gw.Transfers))
+	}
+	deposit := gw.Transfers[0]
+	if deposit.PixKey != "a@b.com" || deposit.Amount < 0.01 || deposit.Amount > 0.99 || deposit.ExternalReference != "payout-account:inst-1" {
+		t.Errorf("micro-deposit request = %+v", deposit)
+	}
+	amount := *accounts.Accounts["inst-1"].MicroDepositAmount
+	if money.FromFloat(deposit.Amount) != amount {
+		t.Errorf("expected the amount sent to be stored, got %v and %v", deposit.Amount, amount)
+	}
+
+	wrong := amount%maxMicroDepositCents + 1
+	if _, err := uc.ConfirmMicroDeposit(ctx, "inst-1", wrong); err == nil || !strings.Contains(err.Error(), "2 attempts left") {
+		t.Errorf("expected a wrong amount to be rejected, got %v", err)
+	}
+	if _, err := uc.ConfirmMicroDeposit(ctx, "inst-1", wrong); err == nil {
+		t.Error("expected a second wrong amount to be rejected")
+	}
+	if _, err := uc.ConfirmMicroDeposit(ctx, "inst-1", wrong); err == nil || accounts.Accounts["inst-1"].VerificationStatus != entity.PayoutAccountFailed {
+		t.Errorf("expected a third attempt to fail the verification, got %v", err)
+	}
+	if _, err := uc.ConfirmMicroDeposit(ctx, "inst-1", amount); err == nil {
+		t.Error("expected a failed verification to need a new micro-deposit")
+	}
+
+	if account, err = uc.StartVerification(ctx, "inst-1"); err != nil || account.VerificationStatus != entity.PayoutAccountPending || account.VerificationAttempts != 0 {
+		t.Fatalf("expected a new micro-deposit, got %+v, %v", account, err)
+	}
+	amount = *accounts.Accounts["inst-1"].MicroDepositAmount
+	if account, err = uc.ConfirmMicroDeposit(ctx, "inst-1", amount); err != nil || !account.IsVerified() || account.VerifiedAt == nil {
+		t.Fatalf("expected the account to be verified, got %+v, %v", account, err)
+	}
+
+	// Saving the same destination keeps the verification, a new one starts over
+	if account, _ = uc.SaveAccount(ctx, "inst-1", pixRequest("a@b.com")); !account.IsVerified() || len(gw.Transfers) != 2 {
+		t.Errorf("expected the verification to be kept, got %q after %d transfers", account.VerificationStatus, len(gw.Transfers))
+	}
+	if account, _ = uc.SaveAccount(ctx, "inst-1", pixRequest("c@d.com")); account.VerificationStatus != entity.PayoutAccountPending || len(gw.Transfers) != 3 {
+		t.Errorf("expected a new destination to be verified again, got %q after %d transfers", account.VerificationStatus, len(gw.Transfers))
+	}
+}
+
+func TestSaveAccount_MicroDepositFailures(t *testing.T) {
+	gw := &testutil.MockTransferGateway{CreateTransferFunc: func(ctx context.Context, req gateway.TransferRequest) (*gateway.TransferResponse, error) {
+		return nil, errors.New("gateway timeout")
+	}}
+	uc, accounts := newVerificationUseCase(gw)
+	ctx := context.Background()
+
+	account, err := uc.SaveAccount(ctx, "inst-1", pixRequest("a@b.com"))
+	if err != nil {
+		t.Fatalf("expected the account to be saved, got %v", err)
+	}
+	if account.VerificationStatus != entity.PayoutAccountUnverified || deref(account.VerificationError) != "gateway timeout" {
+		t.Errorf("expected the account to stay unverified with the error, got %+v", account)
+	}
+
+	// A micro-deposit the bank returns fails the verification
+	gw.CreateTransferFunc = nil
+	uc.StartVerification(ctx, "inst-1")
+	event := &gateway.TransferEvent{Transfer: gateway.TransferResponse{
+		GatewayTransferID: "tra_mock_123", Status: gateway.TransferStatusFailed, ExternalReference: "payout-account:inst-1",
+	}}
+	if err := uc.HandleTransferEvent(ctx, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored := accounts.Accounts["inst-1"]; stored.VerificationStatus != entity.PayoutAccountFailed || deref(stored.VerificationError) == "" {
+		t.Errorf("expected the returned micro-deposit to fail the verification, got %+v", stored)
+	}
+}
+
+func TestSaveAccount_GatewayVerification(t *testing.T) {
+	gw := &verifyingGateway{result: &gateway.AccountVerification{Valid: true}}
+	uc, _ := newVerificationUseCase(gw)
+	ctx := context.Background()
+
+	account, err := uc.SaveAccount(ctx, "inst-1", pixRequest("a@b.com"))
+	if err != nil || !account.IsVerified() || deref(account.VerificationMethod) != entity.PayoutVerificationGateway {
+		t.Fatalf("expected the gateway to verify the account, got %+v, %v", account, err)
+	}
+	if len(gw.Transfers) != 0 {
+		t.Errorf("expected no micro-deposit, got %d transfers", len(gw.Transfers))
+	}
+
+	gw.result = &gateway.AccountVerification{Reason: "pix key not found"}
+	account, _ = uc.SaveAccount(ctx, "inst-1", pixRequest("c@d.com"))
+	if account.VerificationStatus != entity.PayoutAccountFailed || deref(account.VerificationError) != "pix key not found" {
+		t.Errorf("expected the rejected destination to fail, got %+v", account)
+	}
+	if _, err := uc.ConfirmMicroDeposit(ctx, "inst-1", 10); err == nil {
+		t.Error("expected no micro-deposit to confirm")
+	}
+}
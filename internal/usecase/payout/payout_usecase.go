@@ -0,0 +1,451 @@
+package payout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/internal/infrastructure/storage"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/google/uuid"
+)
+
+// ErrAccessDenied is returned when an instructor requests payouts of someone else
+var ErrAccessDenied = errors.New("access restricted to your own payouts")
+
+// UseCase defines the payout batch use case interface
+type UseCase interface {
+	// ListBatches returns payout batches with optional filters
+	ListBatches(ctx context.Context, filters entity.PayoutBatchFilters) ([]entity.PayoutBatch, error)
+
+	// ListInstructorBatches returns the payout history of an instructor
+	ListInstructorBatches(ctx context.Context, instructorID, status, userID, role string) ([]entity.PayoutBatch, error)
+
+	// GetBatch returns a batch with its revenue splits
+	GetBatch(ctx context.Context, id, userID, role string) (*entity.PayoutBatch, error)
+
+	// ListPayableBalances returns the pending, unbatched totals per instructor
+	ListPayableBalances(ctx context.Context) ([]entity.InstructorPayableBalance, error)
+
+	// CreateBatch groups pending splits of an instructor into a scheduled batch
+	CreateBatch(ctx context.Context, req *entity.CreatePayoutBatchRequest, userID string) (*entity.PayoutBatch, error)
+
+	// MarkPaid stores the bank receipt and settles the splits of a scheduled batch
+	MarkPaid(ctx context.Context, id string, receipt *entity.PayoutReceipt, file io.Reader, userID string) (*entity.PayoutBatch, error)
+
+	// CancelBatch cancels a scheduled batch and releases its splits
+	CancelBatch(ctx context.Context, id string) (*entity.PayoutBatch, error)
+
+	// OpenReceipt returns a paid batch and its bank receipt contents
+	OpenReceipt(ctx context.Context, id, userID, role string) (*entity.PayoutBatch, io.ReadCloser, error)
+}
+
+type payoutUseCase struct {
+	repo        repository.PayoutBatchRepository
+	userRepo    repository.UserRepository
+	accountRepo repository.InstructorPayoutAccountRepository
+	ledgerRepo  repository.InstructorLedgerRepository
+	storage     *storage.StorageService
+	db          *database.MySQL
+	bucket      string
+}
+
+// NewUseCase creates a new payout batch use case
+func NewUseCase(
+	repo repository.PayoutBatchRepository,
+	userRepo repository.UserRepository,
+	accountRepo repository.InstructorPayoutAccountRepository,
+	ledgerRepo repository.InstructorLedgerRepository,
+	storageService *storage.StorageService,
+	db *database.MySQL,
+	cfg *config.Config,
+) UseCase {
+	return &payoutUseCase{
+		repo:        repo,
+		userRepo:    userRepo,
+		accountRepo: accountRepo,
+		ledgerRepo:  ledgerRepo,
+		storage:     storageService,
+		db:          db,
+		bucket:      cfg.MinioBucketPayouts,
+	}
+}
+
+// ListBatches returns payout batches with optional filters
+func (uc *payoutUseCase) ListBatches(ctx context.Context, filters entity.PayoutBatchFilters) ([]entity.PayoutBatch, error) {
+	return uc.repo.FindAll(ctx, filters)
+}
+
+// ListInstructorBatches returns the payout history of an instructor, newest payout date first
+func (uc *payoutUseCase) ListInstructorBatches(ctx context.Context, instructorID, status, userID, role string) ([]entity.PayoutBatch, error) {
+	if err := authorize(instructorID, userID, role); err != nil {
+		return nil, err
+	}
+	return uc.repo.FindAll(ctx, entity.PayoutBatchFilters{InstructorID: instructorID, Status: status})
+}
+
+// GetBatch returns a batch with its revenue splits
+func (uc *payoutUseCase) GetBatch(ctx context.Context, id, userID, role string) (*entity.PayoutBatch, error) {
+	batch, err := uc.findBatch(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Splits, err = uc.repo.FindSplits(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// ListPayableBalances returns the pending, unbatched totals per instructor
+func (uc *payoutUseCase) ListPayableBalances(ctx context.Context) ([]entity.InstructorPayableBalance, error) {
+	return uc.repo.FindPayableBalances(ctx)
+}
+
+// CreateBatch groups pending splits of an instructor into a batch scheduled for the payout date.
+// Splits already in another batch or no longer pending are rejected when listed explicitly,
+// and instructors are only paid out to a verified payout account.
+func (uc *payoutUseCase) CreateBatch(ctx context.Context, req *entity.CreatePayoutBatchRequest, userID string) (*entity.PayoutBatch, error) {
+	payoutDate, err := time.Parse("2006-01-02", req.PayoutDate)
+	if err != nil {
+		return nil, errors.New("invalid payout_date: use YYYY-MM-DD")
+	}
+
+	var createdBefore *time.Time
+	if req.CreatedUntil != nil && *req.CreatedUntil != "" {
+		until, err := time.Parse("2006-01-02", *req.CreatedUntil)
+		if err != nil {
+			return nil, errors.New("invalid created_until: use YYYY-MM-DD")
+		}
+		next := until.AddDate(0, 0, 1)
+		createdBefore = &next
+	}
+
+	instructor, err := uc.userRepo.FindByID(ctx, req.InstructorID)
+	if err != nil {
+		return nil, err
+	}
+	if instructor == nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	account, err := uc.accountRepo.FindByInstructorID(ctx, req.InstructorID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil || !account.IsVerified() {
+		return nil, errors.New("invalid batch: instructor has no verified payout account")
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	payable, err := uc.repo.FindPayableSplitsForUpdateWithTx(ctx, tx, req.InstructorID, createdBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	splits, err := selectSplits(payable, req.SplitIDs)
+	if err != nil {
+		return nil, err
+	}
+	total := sumInstructorAmount(splits)
+	if len(splits) == 0 || total <= 0 {
+		return nil, errors.New("invalid batch: no pending splits to pay out")
+	}
+
+	batch := &entity.PayoutBatch{
+		ID:             uuid.New().String(),
+		InstructorID:   req.InstructorID,
+		InstructorName: &instructor.Nome,
+		Status:         entity.PayoutBatchStatusScheduled,
+		PayoutDate:     payoutDate,
+		TotalAmount:    total,
+		SplitCount:     len(splits),
+		Notes:          req.Notes,
+		CreatedAt:      time.Now(),
+		Splits:         splits,
+	}
+	if userID != "" {
+		batch.CreatedBy = &userID
+	}
+
+	if err := uc.repo.CreateWithTx(ctx, tx, batch); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.AssignSplitsWithTx(ctx, tx, batch.ID, splitIDs(splits)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	for i := range batch.Splits {
+		batch.Splits[i].PayoutBatchID = &batch.ID
+	}
+	return batch, nil
+}
+
+// MarkPaid uploads the bank receipt and settles the batch. Splits that stopped being pending
+// since the batch was scheduled (e.g. refunded enrollments) are released and the total is
+// recomputed, so the batch always reflects what was actually paid.
+func (uc *payoutUseCase) MarkPaid(ctx context.Context, id string, receipt *entity.PayoutReceipt, file io.Reader, userID string) (*entity.PayoutBatch, error) {
+	if uc.storage == nil {
+		return nil, errors.New("storage service is not available")
+	}
+
+	paidAt := time.Now()
+	if receipt.PaidAt != nil && *receipt.PaidAt != "" {
+		t, err := time.Parse("2006-01-02", *receipt.PaidAt)
+		if err != nil {
+			return nil, errors.New("invalid paid_at: use YYYY-MM-DD")
+		}
+		paidAt = t
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	batch, err := uc.repo.FindByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, errors.New("payout batch not found")
+	}
+	if batch.Status != entity.PayoutBatchStatusScheduled {
+		return nil, fmt.Errorf("invalid batch: cannot pay a %s batch", batch.Status)
+	}
+
+	splits, err := uc.repo.FindSplitsForUpdateWithTx(ctx, tx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	payable, released := partitionPending(splits)
+	if len(payable) == 0 {
+		return nil, errors.New("invalid batch: none of its splits is still pending")
+	}
+	if err := uc.repo.ReleaseSplitsWithTx(ctx, tx, batch.ID, splitIDs(released)); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.MarkSplitsProcessedWithTx(ctx, tx, batch.ID, paidAt); err != nil {
+		return nil, err
+	}
+
+	key := receiptKey(batch, receipt.FileName)
+	result, err := uc.storage.UploadFile(ctx, uc.bucket, key, file, receipt.Size, receipt.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Status = entity.PayoutBatchStatusPaid
+	batch.TotalAmount = sumInstructorAmount(payable)
+	batch.SplitCount = len(payable)
+	batch.PaymentReference = receipt.PaymentReference
+	batch.ReceiptKey = &key
+	batch.ReceiptFileName = &receipt.FileName
+	batch.ReceiptContentType = &receipt.ContentType
+	batch.ReceiptSize = &result.Size
+	batch.PaidAt = &paidAt
+	if userID != "" {
+		batch.PaidBy = &userID
+	}
+
+	if err := uc.repo.UpdateWithTx(ctx, tx, batch); err != nil {
+		uc.discard(ctx, key)
+		return nil, err
+	}
+	if err := uc.ledgerRepo.CreateWithTx(ctx, tx, &entity.InstructorLedgerEntry{
+		ID:            uuid.New().String(),
+		InstructorID:  batch.InstructorID,
+		EntryKey:      entity.LedgerEntryPayout + ":" + batch.ID,
+		EntryType:     entity.LedgerEntryPayout,
+		Amount:        -batch.TotalAmount.Float(),
+		PayoutBatchID: &batch.ID,
+		Description:   "Repasse - lote " + batch.ID,
+		OccurredAt:    paidAt,
+	}); err != nil {
+		uc.discard(ctx, key)
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		uc.discard(ctx, key)
+		return nil, err
+	}
+
+	for i := range payable {
+		payable[i].Status = entity.RevenueSplitStatusProcessed
+		payable[i].ProcessedAt = &paidAt
+	}
+	batch.Splits = payable
+	return batch, nil
+}
+
+// CancelBatch cancels a scheduled batch, making its splits available for a new batch
+func (uc *payoutUseCase) CancelBatch(ctx context.Context, id string) (*entity.PayoutBatch, error) {
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	batch, err := uc.repo.FindByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, errors.New("payout batch not found")
+	}
+	if batch.Status != entity.PayoutBatchStatusScheduled {
+		return nil, fmt.Errorf("invalid batch: cannot cancel a %s batch", batch.Status)
+	}
+
+	splits, err := uc.repo.FindSplitsForUpdateWithTx(ctx, tx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.ReleaseSplitsWithTx(ctx, tx, batch.ID, splitIDs(splits)); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	batch.Status = entity.PayoutBatchStatusCancelled
+	batch.CancelledAt = &now
+	if err := uc.repo.UpdateWithTx(ctx, tx, batch); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// OpenReceipt returns a batch and the contents of its bank receipt
+func (uc *payoutUseCase) OpenReceipt(ctx context.Context, id, userID, role string) (*entity.PayoutBatch, io.ReadCloser, error) {
+	if uc.storage == nil {
+		return nil, nil, errors.New("storage service is not available")
+	}
+
+	batch, err := uc.findBatch(ctx, id, userID, role)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !batch.HasReceipt() {
+		return nil, nil, errors.New("payout receipt not found")
+	}
+
+	reader, _, err := uc.storage.GetFile(ctx, uc.bucket, *batch.ReceiptKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return batch, reader, nil
+}
+
+func (uc *payoutUseCase) findBatch(ctx context.Context, id, userID, role string) (*entity.PayoutBatch, error) {
+	batch, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, errors.New("payout batch not found")
+	}
+	if err := authorize(batch.InstructorID, userID, role); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// discard removes an uploaded receipt whose batch update was not committed
+func (uc *payoutUseCase) discard(ctx context.Context, key string) {
+	if err := uc.storage.DeleteFile(ctx, uc.bucket, key); err != nil {
+		log.Printf("Failed to remove orphaned payout receipt %s: %v", key, err)
+	}
+}
+
+// authorize allows admins and the instructor the payouts belong to
+func authorize(instructorID, userID, role string) error {
+	if role == string(entity.RoleAdmin) {
+		return nil
+	}
+	if userID == "" || userID != instructorID {
+		return ErrAccessDenied
+	}
+	return nil
+}
+
+// selectSplits picks the requested splits out of the payable ones, or all of them when no
+// IDs are given
+func selectSplits(payable []entity.RevenueSplit, ids []string) ([]entity.RevenueSplit, error) {
+	if len(ids) == 0 {
+		return payable, nil
+	}
+
+	byID := make(map[string]entity.RevenueSplit, len(payable))
+	for _, s := range payable {
+		byID[s.ID] = s
+	}
+
+	selected := make([]entity.RevenueSplit, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		s, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("invalid split_ids: %s is not a pending, unbatched split of this instructor", id)
+		}
+		selected = append(selected, s)
+	}
+	return selected, nil
+}
+
+// partitionPending separates splits that can still be paid from those to release
+func partitionPending(splits []entity.RevenueSplit) (pending, released []entity.RevenueSplit) {
+	for _, s := range splits {
+		if s.Status == entity.RevenueSplitStatusPending {
+			pending = append(pending, s)
+		} else {
+			released = append(released, s)
+		}
+	}
+	return pending, released
+}
+
+func sumInstructorAmount(splits []entity.RevenueSplit) money.Cents {
+	var total money.Cents
+	for _, s := range splits {
+		total += s.InstructorAmount
+	}
+	return total
+}
+
+func splitIDs(splits []entity.RevenueSplit) []string {
+	ids := make([]string, len(splits))
+	for i, s := range splits {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+// receiptKey builds the storage path of a batch receipt: <instructor>/<batch><ext>
+func receiptKey(batch *entity.PayoutBatch, fileName string) string {
+	return fmt.Sprintf("%s/%s%s", batch.InstructorID, batch.ID, strings.ToLower(filepath.Ext(fileName)))
+}
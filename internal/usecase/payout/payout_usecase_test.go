@@ -0,0 +1,84 @@
+package payout
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name         string
+		instructorID string
+		userID       string
+		role         string
+		wantErr      bool
+	}{
+		{"admin", "inst-1", "admin-1", "admin", false},
+		{"own payouts", "inst-1", "inst-1", "instructor", false},
+		{"other instructor", "inst-1", "inst-2", "instructor", true},
+		{"no user", "inst-1", "", "instructor", true},
+	}
+
+	for _, tt := range tests {
+		err := authorize(tt.instructorID, tt.userID, tt.role)
+		if tt.wantErr && !errors.Is(err, ErrAccessDenied) {
+			t.Errorf("%s: authorize = %v, want ErrAccessDenied", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: authorize = %v, want nil", tt.name, err)
+		}
+	}
+}
+
+func TestSelectSplits(t *testing.T) {
+	payable := []entity.RevenueSplit{
+		{ID: "s1", InstructorAmount: 1000},
+		{ID: "s2", InstructorAmount: 2000},
+		{ID: "s3", InstructorAmount: 3000},
+	}
+
+	all, err := selectSplits(payable, nil)
+	if err != nil || len(all) != 3 {
+		t.Fatalf("selectSplits(nil) = %d splits, %v; want all 3", len(all), err)
+	}
+
+	picked, err := selectSplits(payable, []string{"s3", "s1", "s3"})
+	if err != nil {
+		t.Fatalf("selectSplits: unexpected error %v", err)
+	}
+	if len(picked) != 2 || picked[0].ID != "s3" || picked[1].ID != "s1" {
+		t.Errorf("selectSplits = %+v, want s3 and s1 once each", picked)
+	}
+
+	if _, err := selectSplits(payable, []string{"s1", "other"}); err == nil {
+		t.Error("selectSplits with an unknown split: expected error")
+	}
+}
+
+func TestPartitionPendingAndTotal(t *testing.T) {
+	splits := []entity.RevenueSplit{
+		{ID: "s1", Status: entity.RevenueSplitStatusPending, InstructorAmount: 1011},
+		{ID: "s2", Status: entity.RevenueSplitStatusReversed, InstructorAmount: 5000},
+		{ID: "s3", Status: entity.RevenueSplitStatusPending, InstructorAmount: 2020},
+	}
+
+	pending, released := partitionPending(splits)
+	if len(pending) != 2 || len(released) != 1 || released[0].ID != "s2" {
+		t.Fatalf("partitionPending = %d pending, %+v released", len(pending), released)
+	}
+	if got := sumInstructorAmount(pending); got != 3031 {
+		t.Errorf("sumInstructorAmount = %v, want 30.31", got)
+	}
+	if ids := splitIDs(pending); len(ids) != 2 || ids[0] != "s1" || ids[1] != "s3" {
+		t.Errorf("splitIDs = %v, want [s1 s3]", ids)
+	}
+}
+
+func TestReceiptKey(t *testing.T) {
+	batch := &entity.PayoutBatch{ID: "batch-1", InstructorID: "inst-1"}
+	if got := receiptKey(batch, "Comprovante.PDF"); got != "inst-1/batch-1.pdf" {
+		t.Errorf("receiptKey = %q, want inst-1/batch-1.pdf", got)
+	}
+}
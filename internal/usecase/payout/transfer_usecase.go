@@ -0,0 +1,372 @@
+package payout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/google/uuid"
+)
+
+// maxTransferErrorLength matches the transfer_error and verification_error columns
+const maxTransferErrorLength = 255
+
+// TransferUseCase defines the automatic instructor transfer use case interface
+type TransferUseCase interface {
+	// GetAccount returns the payout account of an instructor
+	GetAccount(ctx context.Context, instructorID, userID, role string) (*entity.InstructorPayoutAccount, error)
+
+	// SaveAccount registers or replaces the payout account of an instructor
+	SaveAccount(ctx context.Context, instructorID string, req *entity.SavePayoutAccountRequest) (*entity.InstructorPayoutAccount, error)
+
+	// StartVerification verifies the payout account of an instructor again, or sends a new micro-deposit
+	StartVerification(ctx context.Context, instructorID string) (*entity.InstructorPayoutAccount, error)
+
+	// ConfirmMicroDeposit verifies the payout account of an instructor with the micro-deposit amount received
+	ConfirmMicroDeposit(ctx context.Context, instructorID string, amount money.Cents) (*entity.InstructorPayoutAccount, error)
+
+	// TransferSplit transfers the instructor amount of a split right away (manual trigger or retry)
+	TransferSplit(ctx context.Context, splitID string) (*entity.RevenueSplit, error)
+
+	// AutoTransfer transfers a settled split when automatic transfers are enabled for its instructor
+	AutoTransfer(ctx context.Context, splitID string) error
+
+	// HandleTransferEvent applies a transfer status webhook to its split
+	HandleTransferEvent(ctx context.Context, event *gateway.TransferEvent) error
+}
+
+type transferUseCase struct {
+	splitRepo   repository.RevenueSplitRepository
+	accountRepo repository.InstructorPayoutAccountRepository
+	userRepo    repository.UserRepository
+	ledgerRepo  repository.InstructorLedgerRepository
+	gw          gateway.TransferGateway
+	enabled     bool
+}
+
+// NewTransferUseCase creates a new instructor transfer use case
+func NewTransferUseCase(
+	splitRepo repository.RevenueSplitRepository,
+	accountRepo repository.InstructorPayoutAccountRepository,
+	userRepo repository.UserRepository,
+	ledgerRepo repository.InstructorLedgerRepository,
+	gw gateway.TransferGateway,
+	cfg *config.Config,
+) TransferUseCase {
+	return &transferUseCase{
+		splitRepo:   splitRepo,
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		ledgerRepo:  ledgerRepo,
+		gw:          gw,
+		enabled:     cfg.InstructorAutoTransfer,
+	}
+}
+
+// GetAccount returns the payout account of an instructor
+func (uc *transferUseCase) GetAccount(ctx context.Context, instructorID, userID, role string) (*entity.InstructorPayoutAccount, error) {
+	if err := authorize(instructorID, userID, role); err != nil {
+		return nil, err
+	}
+
+	account, err := uc.accountRepo.FindByInstructorID(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, errors.New("payout account not found")
+	}
+	return account, nil
+}
+
+// SaveAccount registers or replaces the payout account of an instructor.
+// Automatic transfers are on unless the request turns them off. A new destination starts
+// a verification; saving the same destination again keeps the one it has.
+func (uc *transferUseCase) SaveAccount(ctx context.Context, instructorID string, req *entity.SavePayoutAccountRequest) (*entity.InstructorPayoutAccount, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	instructor, err := uc.userRepo.FindByID(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+	if instructor == nil {
+		return nil, errors.New("instructor not found")
+	}
+
+	existing, err := uc.accountRepo.FindByInstructorID(ctx, instructorID)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &entity.InstructorPayoutAccount{
+		InstructorID:       instructorID,
+		Method:             req.Method,
+		AutoTransfer:       req.AutoTransfer == nil || *req.AutoTransfer,
+		VerificationStatus: entity.PayoutAccountUnverified,
+		CreatedAt:          time.Now(),
+	}
+	switch req.Method {
+	case entity.PayoutMethodWallet:
+		account.WalletID = req.WalletID
+	case entity.PayoutMethodPix:
+		account.PixKey = req.PixKey
+		account.PixKeyType = req.PixKeyType
+	case entity.PayoutMethodBankAccount:
+		account.BankCode = req.BankCode
+		account.BankAgency = req.BankAgency
+		account.BankAccount = req.BankAccount
+		account.BankAccountDigit = req.BankAccountDigit
+		account.BankAccountType = req.BankAccountType
+		account.OwnerName = req.OwnerName
+		account.OwnerDocument = req.OwnerDocument
+	}
+
+	keep := existing != nil && existing.VerificationStatus != entity.PayoutAccountUnverified && existing.SameDestination(account)
+	if keep {
+		copyVerification(account, existing)
+	}
+	if err := uc.accountRepo.Save(ctx, account); err != nil {
+		return nil, err
+	}
+	if keep {
+		return account, nil
+	}
+
+	if err := uc.startVerification(ctx, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// TransferSplit transfers the instructor amount of a pending split that is not part of a
+// payout batch. Gateway rejections are recorded on the split rather than returned, so a
+// failed transfer can be retried or paid through a batch instead.
+func (uc *transferUseCase) TransferSplit(ctx context.Context, splitID string) (*entity.RevenueSplit, error) {
+	if uc.gw == nil {
+		return nil, errors.New("transfer gateway is not available")
+	}
+
+	split, err := uc.splitRepo.FindByID(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, errors.New("revenue split not found")
+	}
+	if split.InstructorID == nil || split.InstructorAmount <= 0 {
+		return nil, errors.New("invalid split: no instructor amount to transfer")
+	}
+
+	account, err := uc.accountRepo.FindByInstructorID(ctx, *split.InstructorID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, errors.New("invalid split: instructor has no payout account")
+	}
+	if !account.IsVerified() {
+		return nil, errors.New("invalid split: instructor payout account is not verified")
+	}
+
+	claimed, err := uc.splitRepo.ClaimForTransfer(ctx, split.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, errors.New("invalid split: already paid, batched or being transferred")
+	}
+
+	if err := uc.transfer(ctx, split, account); err != nil {
+		return nil, err
+	}
+	return split, nil
+}
+
+// AutoTransfer is called once a payment settles. Splits of instructors without a verified
+// payout account, with automatic transfers turned off, or already paid otherwise are left alone.
+func (uc *transferUseCase) AutoTransfer(ctx context.Context, splitID string) error {
+	if !uc.enabled || uc.gw == nil {
+		return nil
+	}
+
+	split, err := uc.splitRepo.FindByID(ctx, splitID)
+	if err != nil {
+		return err
+	}
+	if split == nil || split.InstructorID == nil || split.InstructorAmount <= 0 {
+		return nil
+	}
+
+	account, err := uc.accountRepo.FindByInstructorID(ctx, *split.InstructorID)
+	if err != nil {
+		return err
+	}
+	if account == nil || !account.AutoTransfer || !account.IsVerified() {
+		return nil
+	}
+
+	claimed, err := uc.splitRepo.ClaimForTransfer(ctx, split.ID)
+	if err != nil || !claimed {
+		return err
+	}
+
+	return uc.transfer(ctx, split, account)
+}
+
+// HandleTransferEvent updates the split of a transfer from a gateway webhook.
+// The split is located by transfer ID, falling back to the external reference (split ID)
+// for events that arrive before the transfer ID was stored. Completed transfers are final.
+// Events of micro-deposits go to the verification of their payout account instead.
+func (uc *transferUseCase) HandleTransferEvent(ctx context.Context, event *gateway.TransferEvent) error {
+	if strings.HasPrefix(event.Transfer.ExternalReference, microDepositReference) {
+		return uc.applyMicroDepositEvent(ctx, event)
+	}
+
+	split, err := uc.splitRepo.FindByTransferID(ctx, event.Transfer.GatewayTransferID)
+	if err != nil {
+		return err
+	}
+	if split == nil && event.Transfer.ExternalReference != "" {
+		split, err = uc.splitRepo.FindByID(ctx, event.Transfer.ExternalReference)
+		if err != nil {
+			return err
+		}
+		// A retried split points to its latest transfer; events of earlier attempts are stale
+		if split != nil && split.TransferID != nil && *split.TransferID != event.Transfer.GatewayTransferID {
+			split = nil
+		}
+	}
+	if split == nil {
+		log.Printf("No revenue split found for transfer %s", event.Transfer.GatewayTransferID)
+		return nil
+	}
+	// Redelivered events still record the payout in case the first attempt failed to
+	if split.TransferStatus != nil && *split.TransferStatus == entity.SplitTransferStatusDone {
+		return uc.recordPayout(ctx, split)
+	}
+
+	if split.TransferID == nil {
+		split.TransferID = &event.Transfer.GatewayTransferID
+	}
+	applyTransferStatus(split, &event.Transfer, time.Now())
+	if err := uc.splitRepo.UpdateTransfer(ctx, split); err != nil {
+		return err
+	}
+	return uc.recordPayout(ctx, split)
+}
+
+// transfer sends a claimed split to the gateway and records the outcome
+func (uc *transferUseCase) transfer(ctx context.Context, split *entity.RevenueSplit, account *entity.InstructorPayoutAccount) error {
+	resp, err := uc.gw.CreateTransfer(ctx, buildTransferRequest(split, account))
+	if err != nil {
+		log.Printf("Transfer of revenue split %s failed: %v", split.ID, err)
+		resp = &gateway.TransferResponse{Status: gateway.TransferStatusFailed, FailReason: err.Error()}
+	} else {
+		split.TransferID = &resp.GatewayTransferID
+	}
+
+	applyTransferStatus(split, resp, time.Now())
+	if err := uc.splitRepo.UpdateTransfer(ctx, split); err != nil {
+		return fmt.Errorf("failed to record transfer of revenue split %s: %w", split.ID, err)
+	}
+	if err := uc.recordPayout(ctx, split); err != nil {
+		log.Printf("Failed to record ledger payout of revenue split %s: %v", split.ID, err)
+	}
+	return nil
+}
+
+// recordPayout debits a completed transfer from the instructor ledger
+func (uc *transferUseCase) recordPayout(ctx context.Context, split *entity.RevenueSplit) error {
+	if split.TransferStatus == nil || *split.TransferStatus != entity.SplitTransferStatusDone || split.TransferredAt == nil {
+		return nil
+	}
+	entry := entity.NewSplitLedgerEntry(split, entity.LedgerEntryPayout, split.ID, -split.InstructorAmount,
+		"Repasse - matrícula "+split.EnrollmentID, *split.TransferredAt)
+	if entry == nil {
+		return nil
+	}
+	entry.ID = uuid.New().String()
+	return uc.ledgerRepo.Create(ctx, entry)
+}
+
+// buildTransferRequest sends the instructor amount to the destination of the payout account
+func buildTransferRequest(split *entity.RevenueSplit, account *entity.InstructorPayoutAccount) gateway.TransferRequest {
+	req := transferDestination(account)
+	req.Amount = split.InstructorAmount.Float()
+	req.Description = fmt.Sprintf("Repasse instrutor - matrícula %s", split.EnrollmentID)
+	req.ExternalReference = split.ID
+	return req
+}
+
+// transferDestination returns a transfer request to the destination of the payout account
+func transferDestination(account *entity.InstructorPayoutAccount) gateway.TransferRequest {
+	var req gateway.TransferRequest
+	switch account.Method {
+	case entity.PayoutMethodWallet:
+		req.WalletID = deref(account.WalletID)
+	case entity.PayoutMethodPix:
+		req.PixKey = deref(account.PixKey)
+		req.PixKeyType = deref(account.PixKeyType)
+	case entity.PayoutMethodBankAccount:
+		req.BankAccount = &gateway.TransferBankAccount{
+			BankCode:      deref(account.BankCode),
+			Agency:        deref(account.BankAgency),
+			Account:       deref(account.BankAccount),
+			AccountDigit:  deref(account.BankAccountDigit),
+			AccountType:   deref(account.BankAccountType),
+			OwnerName:     deref(account.OwnerName),
+			OwnerDocument: deref(account.OwnerDocument),
+		}
+	}
+	return req
+}
+
+// applyTransferStatus maps a gateway transfer status onto the split. A completed transfer
+// settles the split; failed or cancelled transfers leave it pending so it can be retried
+// or included in a payout batch.
+func applyTransferStatus(split *entity.RevenueSplit, resp *gateway.TransferResponse, now time.Time) {
+	status := resp.Status
+	split.TransferStatus = &status
+	split.TransferError = nil
+
+	switch status {
+	case gateway.TransferStatusDone:
+		if split.TransferredAt == nil {
+			split.TransferredAt = &now
+		}
+		if split.Status == entity.RevenueSplitStatusPending {
+			split.Status = entity.RevenueSplitStatusProcessed
+			split.ProcessedAt = split.TransferredAt
+		}
+	case gateway.TransferStatusFailed, gateway.TransferStatusCancelled:
+		if resp.FailReason != "" {
+			reason := truncateError(resp.FailReason)
+			split.TransferError = &reason
+		}
+	}
+}
+
+// truncateError cuts a gateway error to maxTransferErrorLength characters
+func truncateError(reason string) string {
+	if r := []rune(reason); len(r) > maxTransferErrorLength {
+		return string(r[:maxTransferErrorLength])
+	}
+	return reason
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,83 @@
+package payout
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestBuildTransferRequest(t *testing.T) {
+	split := &entity.RevenueSplit{ID: "split-1", EnrollmentID: "enr-1", InstructorAmount: 7000}
+
+	wallet := buildTransferRequest(split, &entity.InstructorPayoutAccount{
+		Method:   entity.PayoutMethodWallet,
+		WalletID: strPtr("wal-1"),
+	})
+	if wallet.WalletID != "wal-1" || wallet.PixKey != "" || wallet.BankAccount != nil {
+		t.Errorf("wallet request = %+v", wallet)
+	}
+	if wallet.Amount != 70 || wallet.ExternalReference != "split-1" {
+		t.Errorf("wallet request amount/reference = %v/%q", wallet.Amount, wallet.ExternalReference)
+	}
+
+	pix := buildTransferRequest(split, &entity.InstructorPayoutAccount{
+		Method:     entity.PayoutMethodPix,
+		PixKey:     strPtr("12345678900"),
+		PixKeyType: strPtr("CPF"),
+		WalletID:   strPtr("ignored"),
+	})
+	if pix.PixKey != "12345678900" || pix.PixKeyType != "CPF" || pix.WalletID != "" {
+		t.Errorf("pix request = %+v", pix)
+	}
+
+	bank := buildTransferRequest(split, &entity.InstructorPayoutAccount{
+		Method:           entity.PayoutMethodBankAccount,
+		BankCode:         strPtr("341"),
+		BankAgency:       strPtr("0001"),
+		BankAccount:      strPtr("12345"),
+		BankAccountDigit: strPtr("6"),
+		OwnerName:        strPtr("Maria"),
+		OwnerDocument:    strPtr("12345678900"),
+	})
+	if bank.BankAccount == nil || bank.BankAccount.BankCode != "341" || bank.BankAccount.AccountDigit != "6" {
+		t.Errorf("bank request = %+v", bank.BankAccount)
+	}
+}
+
+func TestApplyTransferStatus(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	split := &entity.RevenueSplit{Status: entity.RevenueSplitStatusPending}
+	applyTransferStatus(split, &gateway.TransferResponse{Status: gateway.TransferStatusProcessing}, now)
+	if *split.TransferStatus != gateway.TransferStatusProcessing || split.Status != entity.RevenueSplitStatusPending {
+		t.Errorf("processing: transfer=%s status=%s", *split.TransferStatus, split.Status)
+	}
+
+	applyTransferStatus(split, &gateway.TransferResponse{Status: gateway.TransferStatusDone}, now)
+	if split.Status != entity.RevenueSplitStatusProcessed || split.ProcessedAt == nil || !split.TransferredAt.Equal(now) {
+		t.Errorf("done: status=%s processed_at=%v transferred_at=%v", split.Status, split.ProcessedAt, split.TransferredAt)
+	}
+
+	failed := &entity.RevenueSplit{Status: entity.RevenueSplitStatusPending}
+	applyTransferStatus(failed, &gateway.TransferResponse{
+		Status:     gateway.TransferStatusFailed,
+		FailReason: strings.Repeat("ã", maxTransferErrorLength+10),
+	}, now)
+	if failed.Status != entity.RevenueSplitStatusPending || failed.ProcessedAt != nil {
+		t.Errorf("failed: split should stay pending, got %s", failed.Status)
+	}
+	if failed.TransferError == nil || len([]rune(*failed.TransferError)) != maxTransferErrorLength {
+		t.Errorf("failed: transfer error should be truncated to %d characters", maxTransferErrorLength)
+	}
+
+	// A retry that is accepted clears the previous failure
+	applyTransferStatus(failed, &gateway.TransferResponse{Status: gateway.TransferStatusPending}, now)
+	if failed.TransferError != nil {
+		t.Errorf("pending after retry: transfer error = %q, want nil", *failed.TransferError)
+	}
+}
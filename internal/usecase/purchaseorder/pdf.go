@@ -0,0 +1,200 @@
+package purchaseorder
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/pkg/pdf"
+)
+
+var statusLabels = map[string]string{
+	entity.PurchaseOrderStatusDraft:     "Rascunho",
+	entity.PurchaseOrderStatusApproved:  "Aprovado",
+	entity.PurchaseOrderStatusDelivered: "Entregue",
+	entity.PurchaseOrderStatusInvoiced:  "Faturado",
+	entity.PurchaseOrderStatusCancelled: "Cancelado",
+}
+
+// Layout (points)
+const (
+	marginLeft   = 40.0
+	marginRight  = pdf.A4Width - 40.0
+	marginBottom = pdf.A4Height - 60.0
+	colQty       = 360.0
+	colUnitPrice = 460.0
+)
+
+// renderPDF lays out a purchase order on A4 pages
+func renderPDF(order *entity.PurchaseOrder, supplier *entity.Supplier, contrato *entity.Contrato) []byte {
+	doc := pdf.New()
+	doc.SetTitle("Pedido de Compra " + order.Number)
+	doc.AddPage()
+
+	y := 60.0
+	doc.Text(marginLeft, y, 18, pdf.Bold, pdf.AlignLeft, "PEDIDO DE COMPRA")
+	doc.Text(marginRight, y, 12, pdf.Bold, pdf.AlignRight, order.Number)
+	y += 18
+	doc.Text(marginRight, y, 10, pdf.Regular, pdf.AlignRight, "Status: "+statusLabel(order.Status))
+	doc.Text(marginLeft, y, 10, pdf.Regular, pdf.AlignLeft, "Emitido em "+order.CreatedAt.Format("02/01/2006"))
+	y += 10
+	doc.Line(marginLeft, y, marginRight, y, 1)
+
+	// Supplier and contract blocks
+	y += 22
+	doc.Text(marginLeft, y, 11, pdf.Bold, pdf.AlignLeft, "Fornecedor")
+	doc.Text(300, y, 11, pdf.Bold, pdf.AlignLeft, "Contrato")
+	y += 15
+	left := []string{order.SupplierName}
+	right := []string{order.ContratoNome}
+	if supplier != nil {
+		left = []string{supplier.Name}
+		if supplier.CNPJ != nil {
+			left = append(left, "CNPJ: "+*supplier.CNPJ)
+		}
+		if supplier.Email != nil {
+			left = append(left, *supplier.Email)
+		}
+		if supplier.Phone != nil {
+			left = append(left, *supplier.Phone)
+		}
+	}
+	if contrato != nil {
+		right = []string{contrato.Nome}
+		if contrato.Endereco != nil {
+			right = append(right, *contrato.Endereco)
+		}
+		var city []string
+		if contrato.Cidade != nil {
+			city = append(city, *contrato.Cidade)
+		}
+		if contrato.Estado != nil {
+			city = append(city, *contrato.Estado)
+		}
+		if len(city) > 0 {
+			right = append(right, strings.Join(city, " - "))
+		}
+	}
+	rows := len(left)
+	if len(right) > rows {
+		rows = len(right)
+	}
+	for i := 0; i < rows; i++ {
+		if i < len(left) {
+			doc.Text(marginLeft, y, 10, pdf.Regular, pdf.AlignLeft, left[i])
+		}
+		if i < len(right) {
+			doc.Text(300, y, 10, pdf.Regular, pdf.AlignLeft, right[i])
+		}
+		y += 13
+	}
+
+	if order.ExpectedDeliveryDate != nil {
+		y += 4
+		doc.Text(marginLeft, y, 10, pdf.Regular, pdf.AlignLeft,
+			"Entrega prevista: "+order.ExpectedDeliveryDate.Format("02/01/2006"))
+		y += 13
+	}
+	if order.Description != nil && *order.Description != "" {
+		y += 4
+		for _, line := range pdf.WrapText(*order.Description, 10, pdf.Regular, marginRight-marginLeft) {
+			doc.Text(marginLeft, y, 10, pdf.Regular, pdf.AlignLeft, line)
+			y += 13
+		}
+	}
+
+	// Items table
+	y += 12
+	header := func() {
+		doc.FillRect(marginLeft, y-12, marginRight-marginLeft, 18, 0.9)
+		doc.Text(marginLeft+4, y, 10, pdf.Bold, pdf.AlignLeft, "Item")
+		doc.Text(colQty, y, 10, pdf.Bold, pdf.AlignRight, "Qtd.")
+		doc.Text(colUnitPrice, y, 10, pdf.Bold, pdf.AlignRight, "Preço unit.")
+		doc.Text(marginRight-4, y, 10, pdf.Bold, pdf.AlignRight, "Total")
+		y += 20
+	}
+	header()
+
+	for _, item := range order.Items {
+		lines := pdf.WrapText(item.Description, 10, pdf.Regular, colQty-marginLeft-60)
+		if y+float64(len(lines))*13 > marginBottom {
+			doc.AddPage()
+			y = 60
+			header()
+		}
+
+		qty := formatQuantity(item.Quantity)
+		if item.Unit != nil && *item.Unit != "" {
+			qty += " " + *item.Unit
+		}
+		doc.Text(colQty, y, 10, pdf.Regular, pdf.AlignRight, qty)
+		doc.Text(colUnitPrice, y, 10, pdf.Regular, pdf.AlignRight, formatBRL(item.UnitPrice))
+		doc.Text(marginRight-4, y, 10, pdf.Regular, pdf.AlignRight, formatBRL(item.TotalPrice))
+		for _, line := range lines {
+			doc.Text(marginLeft+4, y, 10, pdf.Regular, pdf.AlignLeft, line)
+			y += 13
+		}
+		y += 4
+	}
+
+	doc.Line(marginLeft, y-6, marginRight, y-6, 0.5)
+	y += 10
+	doc.Text(colUnitPrice, y, 11, pdf.Bold, pdf.AlignRight, "Total do pedido")
+	doc.Text(marginRight-4, y, 11, pdf.Bold, pdf.AlignRight, formatBRL(order.TotalAmount))
+
+	// Approval and invoice trail
+	y += 30
+	if y > marginBottom {
+		doc.AddPage()
+		y = 60
+	}
+	if order.ApprovedAt != nil {
+		doc.Text(marginLeft, y, 9, pdf.Regular, pdf.AlignLeft, "Aprovado em "+order.ApprovedAt.Format("02/01/2006 15:04"))
+		y += 12
+	}
+	if order.DeliveredAt != nil {
+		doc.Text(marginLeft, y, 9, pdf.Regular, pdf.AlignLeft, "Entregue em "+order.DeliveredAt.Format("02/01/2006 15:04"))
+		y += 12
+	}
+	if order.InvoiceNumber != nil {
+		doc.Text(marginLeft, y, 9, pdf.Regular, pdf.AlignLeft, "Nota fiscal: "+*order.InvoiceNumber)
+	}
+
+	return doc.Bytes()
+}
+
+func statusLabel(status string) string {
+	if label, ok := statusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// formatBRL formats an amount as Brazilian currency (R$ 1.234,56)
+func formatBRL(v float64) string {
+	neg := v < 0
+	cents := int64(math.Round(math.Abs(v) * 100))
+	intPart := fmt.Sprintf("%d", cents/100)
+
+	var grouped []string
+	for len(intPart) > 3 {
+		grouped = append([]string{intPart[len(intPart)-3:]}, grouped...)
+		intPart = intPart[:len(intPart)-3]
+	}
+	grouped = append([]string{intPart}, grouped...)
+
+	s := fmt.Sprintf("R$ %s,%02d", strings.Join(grouped, "."), cents%100)
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// formatQuantity prints whole quantities without decimals
+func formatQuantity(q float64) string {
+	if q == math.Trunc(q) {
+		return fmt.Sprintf("%.0f", q)
+	}
+	return strings.Replace(fmt.Sprintf("%.2f", q), ".", ",", 1)
+}
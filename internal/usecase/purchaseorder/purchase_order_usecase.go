@@ -0,0 +1,342 @@
+package purchaseorder
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// ErrPurchaseOrderChanged is returned when another request changed the order's status
+// between loading and saving it, e.g. an edit racing an approval
+var ErrPurchaseOrderChanged = apperror.New(apperror.CodeConflict, "purchase order was changed by another request; reload it and try again")
+
+// UseCase defines the purchase order use case interface
+type UseCase interface {
+	ListPurchaseOrders(ctx context.Context, filters entity.PurchaseOrderFilters) ([]entity.PurchaseOrder, error)
+	GetPurchaseOrder(ctx context.Context, id string) (*entity.PurchaseOrder, error)
+	CreatePurchaseOrder(ctx context.Context, req *entity.CreatePurchaseOrderRequest, createdBy string) (*entity.PurchaseOrder, error)
+	UpdatePurchaseOrder(ctx context.Context, id string, req *entity.UpdatePurchaseOrderRequest) (*entity.PurchaseOrder, error)
+	ApprovePurchaseOrder(ctx context.Context, id, userID, role string) (*entity.PurchaseOrder, error)
+	DeliverPurchaseOrder(ctx context.Context, id string) (*entity.PurchaseOrder, error)
+	InvoicePurchaseOrder(ctx context.Context, id string, req *entity.InvoicePurchaseOrderRequest) (*entity.PurchaseOrder, error)
+	CancelPurchaseOrder(ctx context.Context, id string, req *entity.CancelPurchaseOrderRequest) (*entity.PurchaseOrder, error)
+	ExportPDF(ctx context.Context, id string) (*entity.PurchaseOrder, []byte, error)
+}
+
+type purchaseOrderUseCase struct {
+	repo           repository.PurchaseOrderRepository
+	supplierRepo   repository.SupplierRepository
+	contratoRepo   repository.ContratoRepository
+	db             *database.MySQL
+	approvalLimits map[string]float64
+}
+
+// NewUseCase creates a new purchase order use case
+func NewUseCase(
+	repo repository.PurchaseOrderRepository,
+	supplierRepo repository.SupplierRepository,
+	contratoRepo repository.ContratoRepository,
+	db *database.MySQL,
+	cfg *config.Config,
+) UseCase {
+	return &purchaseOrderUseCase{
+		repo:         repo,
+		supplierRepo: supplierRepo,
+		contratoRepo: contratoRepo,
+		db:           db,
+		approvalLimits: map[string]float64{
+			"supervisor": cfg.PurchaseOrderLimitSupervisor,
+			"gestor":     cfg.PurchaseOrderLimitGestor,
+		},
+	}
+}
+
+// ListPurchaseOrders returns purchase orders matching the filters
+func (uc *purchaseOrderUseCase) ListPurchaseOrders(ctx context.Context, filters entity.PurchaseOrderFilters) ([]entity.PurchaseOrder, error) {
+	return uc.repo.FindAll(ctx, filters)
+}
+
+// GetPurchaseOrder returns a purchase order with its items
+func (uc *purchaseOrderUseCase) GetPurchaseOrder(ctx context.Context, id string) (*entity.PurchaseOrder, error) {
+	order, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errors.New("purchase order not found")
+	}
+
+	items, err := uc.repo.FindItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+
+	return order, nil
+}
+
+// CreatePurchaseOrder creates a draft purchase order
+func (uc *purchaseOrderUseCase) CreatePurchaseOrder(ctx context.Context, req *entity.CreatePurchaseOrderRequest, createdBy string) (*entity.PurchaseOrder, error) {
+	supplier, err := uc.supplierRepo.FindByID(ctx, req.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+	if !supplier.IsActive {
+		return nil, errors.New("supplier is inactive")
+	}
+
+	contrato, err := uc.contratoRepo.FindByID(ctx, req.ContratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+
+	deliveryDate, err := parseDate(req.ExpectedDeliveryDate)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &entity.PurchaseOrder{
+		ID:                   uuid.New().String(),
+		SupplierID:           supplier.ID,
+		ContratoID:           contrato.ID,
+		Status:               entity.PurchaseOrderStatusDraft,
+		Description:          req.Description,
+		ExpectedDeliveryDate: deliveryDate,
+		CreatedAt:            time.Now(),
+		SupplierName:         supplier.Name,
+		ContratoNome:         contrato.Nome,
+	}
+	if createdBy != "" {
+		order.CreatedBy = &createdBy
+	}
+	order.Items, order.TotalAmount = entity.BuildPurchaseOrderItems(order.ID, req.Items, newID)
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	order.Number, err = uc.repo.NextNumberWithTx(ctx, tx, order.CreatedAt.Year())
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.CreateWithTx(ctx, tx, order); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.ReplaceItemsWithTx(ctx, tx, order.ID, order.Items); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// UpdatePurchaseOrder updates description, delivery date or items of a draft order
+func (uc *purchaseOrderUseCase) UpdatePurchaseOrder(ctx context.Context, id string, req *entity.UpdatePurchaseOrderRequest) (*entity.PurchaseOrder, error) {
+	order, err := uc.GetPurchaseOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != entity.PurchaseOrderStatusDraft {
+		return nil, errors.New("only draft purchase orders can be edited")
+	}
+
+	if req.Description != nil {
+		order.Description = req.Description
+	}
+	if req.ExpectedDeliveryDate != nil {
+		order.ExpectedDeliveryDate, err = parseDate(req.ExpectedDeliveryDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(req.Items) > 0 {
+		order.Items, order.TotalAmount = entity.BuildPurchaseOrderItems(order.ID, req.Items, newID)
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ok, err := uc.repo.UpdateWithTx(ctx, tx, order)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPurchaseOrderChanged
+	}
+	if len(req.Items) > 0 {
+		if err := uc.repo.ReplaceItemsWithTx(ctx, tx, order.ID, order.Items); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order.UpdatedAt = &now
+	return order, nil
+}
+
+// ApprovePurchaseOrder approves a draft order if the total is within the role's approval limit
+func (uc *purchaseOrderUseCase) ApprovePurchaseOrder(ctx context.Context, id, userID, role string) (*entity.PurchaseOrder, error) {
+	order, err := uc.transition(ctx, id, entity.PurchaseOrderStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	from := order.Status
+
+	if !uc.canApprove(role, order.TotalAmount) {
+		return nil, errors.New("order total exceeds your approval limit")
+	}
+
+	now := time.Now()
+	order.Status = entity.PurchaseOrderStatusApproved
+	order.ApprovedAt = &now
+	if userID != "" {
+		order.ApprovedBy = &userID
+	}
+
+	return uc.save(ctx, order, from)
+}
+
+// DeliverPurchaseOrder marks an approved order as delivered
+func (uc *purchaseOrderUseCase) DeliverPurchaseOrder(ctx context.Context, id string) (*entity.PurchaseOrder, error) {
+	order, err := uc.transition(ctx, id, entity.PurchaseOrderStatusDelivered)
+	if err != nil {
+		return nil, err
+	}
+	from := order.Status
+
+	now := time.Now()
+	order.Status = entity.PurchaseOrderStatusDelivered
+	order.DeliveredAt = &now
+
+	return uc.save(ctx, order, from)
+}
+
+// InvoicePurchaseOrder registers the supplier invoice on a delivered order
+func (uc *purchaseOrderUseCase) InvoicePurchaseOrder(ctx context.Context, id string, req *entity.InvoicePurchaseOrderRequest) (*entity.PurchaseOrder, error) {
+	order, err := uc.transition(ctx, id, entity.PurchaseOrderStatusInvoiced)
+	if err != nil {
+		return nil, err
+	}
+	from := order.Status
+
+	now := time.Now()
+	order.Status = entity.PurchaseOrderStatusInvoiced
+	order.InvoiceNumber = &req.InvoiceNumber
+	order.InvoicedAt = &now
+
+	return uc.save(ctx, order, from)
+}
+
+// CancelPurchaseOrder cancels a draft or approved order
+func (uc *purchaseOrderUseCase) CancelPurchaseOrder(ctx context.Context, id string, req *entity.CancelPurchaseOrderRequest) (*entity.PurchaseOrder, error) {
+	order, err := uc.transition(ctx, id, entity.PurchaseOrderStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	from := order.Status
+
+	now := time.Now()
+	order.Status = entity.PurchaseOrderStatusCancelled
+	order.CancelledAt = &now
+	order.CancelReason = req.Reason
+
+	return uc.save(ctx, order, from)
+}
+
+// ExportPDF renders the purchase order as a PDF document
+func (uc *purchaseOrderUseCase) ExportPDF(ctx context.Context, id string) (*entity.PurchaseOrder, []byte, error) {
+	order, err := uc.GetPurchaseOrder(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	supplier, err := uc.supplierRepo.FindByID(ctx, order.SupplierID)
+	if err != nil {
+		return nil, nil, err
+	}
+	contrato, err := uc.contratoRepo.FindByID(ctx, order.ContratoID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return order, renderPDF(order, supplier, contrato), nil
+}
+
+// transition loads an order and checks the requested status change is allowed
+func (uc *purchaseOrderUseCase) transition(ctx context.Context, id, to string) (*entity.PurchaseOrder, error) {
+	order, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errors.New("purchase order not found")
+	}
+	if !entity.CanTransitionPurchaseOrder(order.Status, to) {
+		return nil, errors.New("invalid status transition from " + order.Status + " to " + to)
+	}
+	return order, nil
+}
+
+// save stores a transitioned order only if it is still in the from status with the total that was
+// checked, so concurrent transitions or a racing edit cannot both apply
+func (uc *purchaseOrderUseCase) save(ctx context.Context, order *entity.PurchaseOrder, from string) (*entity.PurchaseOrder, error) {
+	ok, err := uc.repo.UpdateStatus(ctx, order, from)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPurchaseOrderChanged
+	}
+	now := time.Now()
+	order.UpdatedAt = &now
+	return order, nil
+}
+
+// canApprove reports whether role may approve an order of the given total
+func (uc *purchaseOrderUseCase) canApprove(role string, total float64) bool {
+	if role == string(entity.RoleAdmin) {
+		return true
+	}
+	limit, ok := uc.approvalLimits[role]
+	return ok && total <= limit
+}
+
+func parseDate(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return nil, errors.New("invalid expected_delivery_date: use YYYY-MM-DD")
+	}
+	return &t, nil
+}
+
+func newID() string {
+	return uuid.New().String()
+}
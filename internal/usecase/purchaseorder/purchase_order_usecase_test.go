@@ -0,0 +1,106 @@
+package purchaseorder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+// racedOrderRepo serves a draft order whose status another request changes before it is saved
+type racedOrderRepo struct {
+	repository.PurchaseOrderRepository
+	order *entity.PurchaseOrder
+}
+
+func (r *racedOrderRepo) FindByID(ctx context.Context, id string) (*entity.PurchaseOrder, error) {
+	order := *r.order
+	return &order, nil
+}
+
+func (r *racedOrderRepo) UpdateStatus(ctx context.Context, order *entity.PurchaseOrder, from string) (bool, error) {
+	return false, nil
+}
+
+func TestCanApprove_ByRoleLimit(t *testing.T) {
+	uc := &purchaseOrderUseCase{approvalLimits: map[string]float64{
+		"supervisor": 2000,
+		"gestor":     10000,
+	}}
+
+	tests := []struct {
+		role  string
+		total float64
+		want  bool
+	}{
+		{"admin", 1000000, true},
+		{"supervisor", 2000, true},
+		{"supervisor", 2000.01, false},
+		{"gestor", 9999.99, true},
+		{"gestor", 15000, false},
+		{"zelador", 10, false},
+	}
+
+	for _, tt := range tests {
+		if got := uc.canApprove(tt.role, tt.total); got != tt.want {
+			t.Errorf("canApprove(%s, %.2f) = %v, want %v", tt.role, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBRL(t *testing.T) {
+	tests := map[float64]string{
+		0:          "R$ 0,00",
+		5.5:        "R$ 5,50",
+		1234.56:    "R$ 1.234,56",
+		1000000:    "R$ 1.000.000,00",
+		-42.1:      "-R$ 42,10",
+		999.999:    "R$ 1.000,00",
+		123456.789: "R$ 123.456,79",
+	}
+	for in, want := range tests {
+		if got := formatBRL(in); got != want {
+			t.Errorf("formatBRL(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	unit := "un"
+	order := &entity.PurchaseOrder{
+		Number:       "PO-2024-00001",
+		Status:       entity.PurchaseOrderStatusApproved,
+		TotalAmount:  150,
+		CreatedAt:    time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+		SupplierName: "Limpeza Total",
+		ContratoNome: "Residencial Sol",
+		Items: []entity.PurchaseOrderItem{
+			{Description: "Detergente", Quantity: 10, Unit: &unit, UnitPrice: 15, TotalPrice: 150},
+		},
+	}
+
+	out := renderPDF(order, nil, nil)
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Fatal("expected PDF output")
+	}
+	if !bytes.Contains(out, []byte("(PO-2024-00001) Tj")) {
+		t.Error("expected order number in document")
+	}
+	if !bytes.Contains(out, []byte("(R$ 150,00) Tj")) {
+		t.Error("expected formatted total in document")
+	}
+}
+
+func TestApprovePurchaseOrder_ChangedConcurrently(t *testing.T) {
+	repo := &racedOrderRepo{order: &entity.PurchaseOrder{ID: "po-1", Status: entity.PurchaseOrderStatusDraft, TotalAmount: 100}}
+	uc := &purchaseOrderUseCase{repo: repo}
+
+	_, err := uc.ApprovePurchaseOrder(context.Background(), "po-1", "u1", "admin")
+	if !errors.Is(err, ErrPurchaseOrderChanged) {
+		t.Fatalf("expected ErrPurchaseOrderChanged, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package revenue
 import (
 	"context"
 	"errors"
+	"math"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
@@ -22,11 +23,14 @@ type UseCase interface {
 	// GetInstructorEarnings returns all revenue splits for an instructor
 	GetInstructorEarnings(ctx context.Context, instructorID string) ([]entity.RevenueSplit, error)
 
-	// GetInstructorTotalEarnings returns total earnings for an instructor
+	// GetInstructorTotalEarnings returns the ledger totals of an instructor
 	GetInstructorTotalEarnings(ctx context.Context, instructorID string) (*InstructorTotalResponse, error)
 
 	// UpdateStatus updates the status of a revenue split
 	UpdateStatus(ctx context.Context, id, status string) error
+
+	// GetPartyLedger returns the shares of a split party (instructor, affiliate or platform) with totals
+	GetPartyLedger(ctx context.Context, partyType, partyID string) (*entity.SplitPartyLedger, error)
 }
 
 // RevenueSplitFilters holds the filter parameters for listing revenue splits
@@ -36,21 +40,28 @@ type RevenueSplitFilters struct {
 	Status       string
 }
 
-// InstructorTotalResponse represents the total earnings response for an instructor
+// InstructorTotalResponse represents the total earnings response for an instructor.
+// Total earnings are the credits net of refunds, chargebacks and adjustments; the balance
+// is what is still owed after payouts.
 type InstructorTotalResponse struct {
-	InstructorID  string  `json:"instructor_id"`
-	TotalEarnings float64 `json:"total_earnings"`
-	Currency      string  `json:"currency"`
+	InstructorID  string                        `json:"instructor_id"`
+	TotalEarnings float64                       `json:"total_earnings"`
+	PaidOut       float64                       `json:"paid_out"`
+	Balance       float64                       `json:"balance"`
+	Totals        entity.InstructorLedgerTotals `json:"totals"`
+	Currency      string                        `json:"currency"`
 }
 
 type revenueUseCase struct {
 	revenueSplitRepo repository.RevenueSplitRepository
+	ledgerRepo       repository.InstructorLedgerRepository
 }
 
 // NewUseCase creates a new revenue use case
-func NewUseCase(revenueSplitRepo repository.RevenueSplitRepository) UseCase {
+func NewUseCase(revenueSplitRepo repository.RevenueSplitRepository, ledgerRepo repository.InstructorLedgerRepository) UseCase {
 	return &revenueUseCase{
 		revenueSplitRepo: revenueSplitRepo,
+		ledgerRepo:       ledgerRepo,
 	}
 }
 
@@ -95,12 +106,22 @@ func (uc *revenueUseCase) ListRevenueSplits(ctx context.Context, filters Revenue
 	return uc.revenueSplitRepo.FindAll(ctx, filters.Status)
 }
 
-// GetRevenueSplitByID returns a revenue split by ID
+// GetRevenueSplitByID returns a revenue split by ID with its party shares
 func (uc *revenueUseCase) GetRevenueSplitByID(ctx context.Context, id string) (*entity.RevenueSplit, error) {
 	if id == "" {
 		return nil, errors.New("id is required")
 	}
-	return uc.revenueSplitRepo.FindByID(ctx, id)
+
+	split, err := uc.revenueSplitRepo.FindByID(ctx, id)
+	if err != nil || split == nil {
+		return split, err
+	}
+
+	split.Parties, err = uc.revenueSplitRepo.FindParties(ctx, split.ID)
+	if err != nil {
+		return nil, err
+	}
+	return split, nil
 }
 
 // GetRevenueSplitByEnrollment returns revenue split by enrollment ID
@@ -119,20 +140,24 @@ func (uc *revenueUseCase) GetInstructorEarnings(ctx context.Context, instructorI
 	return uc.revenueSplitRepo.FindByInstructorID(ctx, instructorID)
 }
 
-// GetInstructorTotalEarnings returns total earnings for an instructor
+// GetInstructorTotalEarnings sums the instructor ledger
 func (uc *revenueUseCase) GetInstructorTotalEarnings(ctx context.Context, instructorID string) (*InstructorTotalResponse, error) {
 	if instructorID == "" {
 		return nil, errors.New("instructor_id is required")
 	}
 
-	total, err := uc.revenueSplitRepo.GetTotalByInstructor(ctx, instructorID)
+	totals, err := uc.ledgerRepo.GetTotals(ctx, instructorID)
 	if err != nil {
 		return nil, err
 	}
 
+	earned := totals.Earned()
 	return &InstructorTotalResponse{
 		InstructorID:  instructorID,
-		TotalEarnings: total,
+		TotalEarnings: earned,
+		PaidOut:       roundCents(-totals.Payouts),
+		Balance:       roundCents(earned + totals.Payouts),
+		Totals:        *totals,
 		Currency:      "BRL",
 	}, nil
 }
@@ -165,3 +190,48 @@ func (uc *revenueUseCase) UpdateStatus(ctx context.Context, id, status string) e
 
 	return uc.revenueSplitRepo.UpdateStatus(ctx, id, status)
 }
+
+// GetPartyLedger returns every share of a party, newest first, with totals per split status;
+// the total covers pending and processed shares.
+// The platform has no party ID; instructors and affiliates are identified by their user ID.
+func (uc *revenueUseCase) GetPartyLedger(ctx context.Context, partyType, partyID string) (*entity.SplitPartyLedger, error) {
+	switch partyType {
+	case entity.SplitPartyPlatform:
+		partyID = ""
+	case entity.SplitPartyInstructor, entity.SplitPartyAffiliate:
+		if partyID == "" {
+			return nil, errors.New("invalid party: party_id is required")
+		}
+	default:
+		return nil, errors.New("invalid party type: must be 'instructor', 'affiliate', or 'platform'")
+	}
+
+	entries, err := uc.revenueSplitRepo.FindPartyEntries(ctx, partyType, partyID)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := &entity.SplitPartyLedger{PartyType: partyType, Entries: entries}
+	if partyID != "" {
+		ledger.PartyID = &partyID
+	}
+	if ledger.Entries == nil {
+		ledger.Entries = []entity.RevenueSplitParty{}
+	}
+	for _, e := range entries {
+		switch e.Status {
+		case entity.RevenueSplitStatusPending:
+			ledger.PendingAmount += e.Amount
+		case entity.RevenueSplitStatusProcessed:
+			ledger.ProcessedAmount += e.Amount
+		case entity.RevenueSplitStatusReversed:
+			ledger.ReversedAmount += e.Amount
+		}
+	}
+	ledger.TotalAmount = ledger.PendingAmount + ledger.ProcessedAmount
+	return ledger, nil
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,339 @@
+package revenue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/google/uuid"
+)
+
+// SplitRuleUseCase defines the multi-party split configuration use case interface
+type SplitRuleUseCase interface {
+	// GetRules returns the global split configuration in effect
+	GetRules(ctx context.Context) ([]entity.SplitRule, error)
+
+	// SaveRules validates and replaces the global split configuration
+	SaveRules(ctx context.Context, req *entity.SaveSplitRulesRequest) ([]entity.SplitRule, error)
+
+	// ListOverrides returns every course and instructor override
+	ListOverrides(ctx context.Context) ([]entity.SplitRuleSet, error)
+
+	// GetOverride returns the override of a course or instructor
+	GetOverride(ctx context.Context, scope, scopeID string) (*entity.SplitRuleSet, error)
+
+	// SaveOverride validates and replaces the override of a course or instructor
+	SaveOverride(ctx context.Context, scope, scopeID string, req *entity.SaveSplitRulesRequest) (*entity.SplitRuleSet, error)
+
+	// DeleteOverride removes the override of a course or instructor
+	DeleteOverride(ctx context.Context, scope, scopeID string) error
+
+	// Resolve returns the configuration that applies to a course and instructor
+	Resolve(ctx context.Context, courseID, instructorID string) (*entity.SplitRuleSet, error)
+
+	// Preview allocates a net amount with the given rules, or the configuration that applies when empty
+	Preview(ctx context.Context, req *PreviewSplitRequest) ([]entity.RevenueSplitParty, error)
+
+	// Simulate applies the configuration of the course/instructor to a simulated split
+	Simulate(ctx context.Context, result *entity.CalculateSplitResponse, courseID, instructorID string) error
+
+	// Allocate fills the party shares and instructor/platform totals of a new split of a course
+	Allocate(ctx context.Context, split *entity.RevenueSplit, courseID string) error
+}
+
+// PreviewSplitRequest represents the request to preview how a net amount is shared
+type PreviewSplitRequest struct {
+	NetAmount    money.Cents             `json:"net_amount" binding:"required,gt=0"`
+	Rules        []entity.SplitRuleInput `json:"rules" binding:"omitempty,dive"`
+	CourseID     string                  `json:"course_id"`
+	InstructorID string                  `json:"instructor_id"`
+}
+
+type splitRuleUseCase struct {
+	repo              repository.SplitRuleRepository
+	courseRepo        repository.CourseRepository
+	userRepo          repository.UserRepository
+	db                *database.MySQL
+	instructorPercent float64
+	platformPercent   float64
+}
+
+// NewSplitRuleUseCase creates a new split configuration use case
+func NewSplitRuleUseCase(
+	repo repository.SplitRuleRepository,
+	courseRepo repository.CourseRepository,
+	userRepo repository.UserRepository,
+	db *database.MySQL,
+	cfg *config.Config,
+) SplitRuleUseCase {
+	return &splitRuleUseCase{
+		repo:              repo,
+		courseRepo:        courseRepo,
+		userRepo:          userRepo,
+		db:                db,
+		instructorPercent: cfg.RevenueInstructorPercent,
+		platformPercent:   cfg.RevenuePlatformPercent,
+	}
+}
+
+// GetRules returns the configured rules, falling back to the global instructor/platform percentages
+func (uc *splitRuleUseCase) GetRules(ctx context.Context) ([]entity.SplitRule, error) {
+	rules, err := uc.repo.FindByScope(ctx, entity.SplitScopeGlobal, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return entity.DefaultSplitRules(uc.instructorPercent, uc.platformPercent), nil
+	}
+	return rules, nil
+}
+
+// SaveRules replaces the global split configuration; it only affects splits created afterwards
+func (uc *splitRuleUseCase) SaveRules(ctx context.Context, req *entity.SaveSplitRulesRequest) ([]entity.SplitRule, error) {
+	return uc.replace(ctx, entity.SplitScopeGlobal, "", req)
+}
+
+// ListOverrides returns every course and instructor override
+func (uc *splitRuleUseCase) ListOverrides(ctx context.Context) ([]entity.SplitRuleSet, error) {
+	rules, err := uc.repo.FindOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return entity.GroupSplitRules(rules), nil
+}
+
+// GetOverride returns the override of a course or instructor
+func (uc *splitRuleUseCase) GetOverride(ctx context.Context, scope, scopeID string) (*entity.SplitRuleSet, error) {
+	if err := validateOverrideScope(scope); err != nil {
+		return nil, err
+	}
+
+	rules, err := uc.repo.FindByScope(ctx, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, errors.New("split override not found")
+	}
+	return &entity.SplitRuleSet{Scope: scope, ScopeID: scopeID, Rules: rules}, nil
+}
+
+// SaveOverride replaces the override of an existing course or instructor
+func (uc *splitRuleUseCase) SaveOverride(ctx context.Context, scope, scopeID string, req *entity.SaveSplitRulesRequest) (*entity.SplitRuleSet, error) {
+	if err := validateOverrideScope(scope); err != nil {
+		return nil, err
+	}
+
+	switch scope {
+	case entity.SplitScopeCourse:
+		course, err := uc.courseRepo.FindByID(ctx, scopeID)
+		if err != nil {
+			return nil, err
+		}
+		if course == nil {
+			return nil, errors.New("course not found")
+		}
+	case entity.SplitScopeInstructor:
+		instructor, err := uc.userRepo.FindByID(ctx, scopeID)
+		if err != nil {
+			return nil, err
+		}
+		if instructor == nil {
+			return nil, errors.New("instructor not found")
+		}
+	}
+
+	rules, err := uc.replace(ctx, scope, scopeID, req)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.SplitRuleSet{Scope: scope, ScopeID: scopeID, Rules: rules}, nil
+}
+
+// DeleteOverride removes the override; the course or instructor falls back to the next scope
+func (uc *splitRuleUseCase) DeleteOverride(ctx context.Context, scope, scopeID string) error {
+	if _, err := uc.GetOverride(ctx, scope, scopeID); err != nil {
+		return err
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := uc.repo.ReplaceScopeWithTx(ctx, tx, scope, scopeID, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Resolve picks the course override, then the instructor override, then the global configuration.
+// The instructor of the course is used when no instructor is given.
+func (uc *splitRuleUseCase) Resolve(ctx context.Context, courseID, instructorID string) (*entity.SplitRuleSet, error) {
+	if courseID != "" {
+		rules, err := uc.repo.FindByScope(ctx, entity.SplitScopeCourse, courseID)
+		if err != nil {
+			return nil, err
+		}
+		if len(rules) > 0 {
+			return &entity.SplitRuleSet{Scope: entity.SplitScopeCourse, ScopeID: courseID, Rules: rules}, nil
+		}
+
+		if instructorID == "" {
+			course, err := uc.courseRepo.FindByID(ctx, courseID)
+			if err != nil {
+				return nil, err
+			}
+			if course != nil && course.InstructorID != nil {
+				instructorID = *course.InstructorID
+			}
+		}
+	}
+
+	if instructorID != "" {
+		rules, err := uc.repo.FindByScope(ctx, entity.SplitScopeInstructor, instructorID)
+		if err != nil {
+			return nil, err
+		}
+		if len(rules) > 0 {
+			return &entity.SplitRuleSet{Scope: entity.SplitScopeInstructor, ScopeID: instructorID, Rules: rules}, nil
+		}
+	}
+
+	rules, err := uc.GetRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.SplitRuleSet{Scope: entity.SplitScopeGlobal, Rules: rules}, nil
+}
+
+// Preview shows the shares of each party without creating anything
+func (uc *splitRuleUseCase) Preview(ctx context.Context, req *PreviewSplitRequest) ([]entity.RevenueSplitParty, error) {
+	if len(req.Rules) > 0 {
+		rules := rulesFromInput(req.Rules)
+		if err := entity.ValidateSplitRules(rules); err != nil {
+			return nil, err
+		}
+		return entity.AllocateSplit(req.NetAmount, rules, nil)
+	}
+
+	set, err := uc.Resolve(ctx, req.CourseID, req.InstructorID)
+	if err != nil {
+		return nil, err
+	}
+	return entity.AllocateSplit(req.NetAmount, set.Rules, nil)
+}
+
+// Simulate replaces the instructor/platform amounts of a simulation with the shares of the
+// configuration that applies to the course/instructor
+func (uc *splitRuleUseCase) Simulate(ctx context.Context, result *entity.CalculateSplitResponse, courseID, instructorID string) error {
+	set, err := uc.Resolve(ctx, courseID, instructorID)
+	if err != nil {
+		return err
+	}
+
+	parties, err := entity.AllocateSplit(money.FromFloat(result.NetAmount), set.Rules, nil)
+	if err != nil {
+		return err
+	}
+
+	result.InstructorAmount = 0
+	result.PlatformAmount = 0
+	result.InstructorPercent = 0
+	result.PlatformPercent = 0
+	for _, p := range parties {
+		switch p.PartyType {
+		case entity.SplitPartyInstructor:
+			result.InstructorAmount += p.Amount.Float()
+			result.InstructorPercent += p.Percent
+		case entity.SplitPartyPlatform:
+			result.PlatformAmount += p.Amount.Float()
+			result.PlatformPercent += p.Percent
+		}
+	}
+	result.RuleScope = set.Scope
+	result.Parties = parties
+	return nil
+}
+
+// Allocate shares the net amount of the split according to the configuration of its course
+func (uc *splitRuleUseCase) Allocate(ctx context.Context, split *entity.RevenueSplit, courseID string) error {
+	instructorID := ""
+	if split.InstructorID != nil {
+		instructorID = *split.InstructorID
+	}
+
+	set, err := uc.Resolve(ctx, courseID, instructorID)
+	if err != nil {
+		return err
+	}
+
+	parties, err := entity.AllocateSplit(split.NetAmount, set.Rules, split.InstructorID)
+	if err != nil {
+		return err
+	}
+	for i := range parties {
+		parties[i].ID = uuid.New().String()
+	}
+	split.ApplyParties(parties)
+	return nil
+}
+
+// replace validates and stores the configuration of a scope
+func (uc *splitRuleUseCase) replace(ctx context.Context, scope, scopeID string, req *entity.SaveSplitRulesRequest) ([]entity.SplitRule, error) {
+	rules := rulesFromInput(req.Rules)
+	if err := entity.ValidateSplitRules(rules); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range rules {
+		rules[i].ID = uuid.New().String()
+		rules[i].Scope = scope
+		rules[i].ScopeID = scopeID
+		rules[i].CreatedAt = now
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := uc.repo.ReplaceScopeWithTx(ctx, tx, scope, scopeID, rules); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func validateOverrideScope(scope string) error {
+	if scope != entity.SplitScopeCourse && scope != entity.SplitScopeInstructor {
+		return errors.New("invalid scope: must be 'course' or 'instructor'")
+	}
+	return nil
+}
+
+// rulesFromInput numbers the parties in request order
+func rulesFromInput(inputs []entity.SplitRuleInput) []entity.SplitRule {
+	rules := make([]entity.SplitRule, len(inputs))
+	for i, in := range inputs {
+		rules[i] = entity.SplitRule{
+			Position:    i + 1,
+			PartyType:   in.PartyType,
+			PartyID:     in.PartyID,
+			Label:       in.Label,
+			Percent:     in.Percent,
+			FixedAmount: in.FixedAmount,
+		}
+	}
+	return rules
+}
@@ -0,0 +1,72 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+// UseCase defines the global search use case interface
+type UseCase interface {
+	Search(ctx context.Context, filter *entity.SearchFilter) (*entity.SearchResponse, error)
+}
+
+type searchUseCase struct {
+	repo repository.SearchRepository
+}
+
+// NewUseCase creates a new global search use case
+func NewUseCase(repo repository.SearchRepository) UseCase {
+	return &searchUseCase{repo: repo}
+}
+
+// Search finds contratos, suppliers, tasks and gestores matching every term and returns them
+// grouped by type in the order of entity.SearchTypes, each group best ranked first. Types
+// without results are left out.
+func (uc *searchUseCase) Search(ctx context.Context, filter *entity.SearchFilter) (*entity.SearchResponse, error) {
+	terms := strings.Fields(filter.Terms)
+	query := strings.Join(terms, " ")
+	if len([]rune(query)) < entity.MinSearchLength {
+		return nil, fmt.Errorf("invalid search: q must have at least %d characters", entity.MinSearchLength)
+	}
+
+	types := filter.Types
+	if len(types) == 0 {
+		types = entity.SearchTypes
+	}
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		if !entity.IsValidSearchType(t) {
+			return nil, fmt.Errorf("invalid type %q: use %s", t, strings.Join(entity.SearchTypes, ", "))
+		}
+		wanted[t] = true
+	}
+
+	limit := filter.Limit
+	if limit < 1 {
+		limit = entity.DefaultSearchLimit
+	}
+	if limit > entity.MaxSearchLimit {
+		limit = entity.MaxSearchLimit
+	}
+
+	resp := &entity.SearchResponse{Query: query, Groups: []entity.SearchGroup{}}
+	for _, t := range entity.SearchTypes {
+		if !wanted[t] {
+			continue
+		}
+		results, err := uc.repo.Search(ctx, t, terms, limit)
+		if err != nil {
+			return nil, fmt.Errorf("search %s: %w", t, err)
+		}
+		if len(results) == 0 {
+			continue
+		}
+		resp.Groups = append(resp.Groups, entity.SearchGroup{Type: t, Results: results})
+		resp.Total += len(results)
+	}
+	return resp, nil
+}
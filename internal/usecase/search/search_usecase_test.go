@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+type fakeSearchRepo struct {
+	results map[string][]entity.SearchResult
+	calls   []string
+	limit   int
+}
+
+func (r *fakeSearchRepo) Search(ctx context.Context, searchType string, terms []string, limit int) ([]entity.SearchResult, error) {
+	r.calls = append(r.calls, searchType+":"+strings.Join(terms, "+"))
+	r.limit = limit
+	return r.results[searchType], nil
+}
+
+func TestSearchGroupsByType(t *testing.T) {
+	repo := &fakeSearchRepo{results: map[string][]entity.SearchResult{
+		entity.SearchTypeTask:     {{Type: entity.SearchTypeTask, ID: "t1", Title: "Revisar bombas", Score: 2.5}},
+		entity.SearchTypeContrato: {{Type: entity.SearchTypeContrato, ID: "c1", Title: "Residencial Bombas", Score: 1}},
+	}}
+	uc := NewUseCase(repo)
+
+	resp, err := uc.Search(context.Background(), &entity.SearchFilter{Terms: "  bombas   agua "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Query != "bombas agua" || resp.Total != 2 || len(resp.Groups) != 2 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if resp.Groups[0].Type != entity.SearchTypeContrato || resp.Groups[1].Type != entity.SearchTypeTask {
+		t.Errorf("groups out of order: %+v", resp.Groups)
+	}
+	if len(repo.calls) != len(entity.SearchTypes) || repo.calls[0] != "contrato:bombas+agua" {
+		t.Errorf("calls = %v", repo.calls)
+	}
+	if repo.limit != entity.DefaultSearchLimit {
+		t.Errorf("limit = %d", repo.limit)
+	}
+}
+
+func TestSearchTypesAndLimit(t *testing.T) {
+	repo := &fakeSearchRepo{}
+	uc := NewUseCase(repo)
+
+	resp, err := uc.Search(context.Background(), &entity.SearchFilter{Terms: "bombas", Types: []string{entity.SearchTypeGestor}, Limit: 500})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.calls) != 1 || repo.calls[0] != "gestor:bombas" || repo.limit != entity.MaxSearchLimit {
+		t.Errorf("calls = %v, limit %d", repo.calls, repo.limit)
+	}
+	if resp.Groups == nil || resp.Total != 0 {
+		t.Errorf("expected empty groups, got %+v", resp)
+	}
+}
+
+func TestSearchValidation(t *testing.T) {
+	uc := NewUseCase(&fakeSearchRepo{})
+	for _, filter := range []entity.SearchFilter{
+		{Terms: ""},
+		{Terms: " a "},
+		{Terms: "bombas", Types: []string{"enrollment"}},
+	} {
+		if _, err := uc.Search(context.Background(), &filter); err == nil || !strings.HasPrefix(err.Error(), "invalid") {
+			t.Errorf("filter %+v: expected an invalid search error, got %v", filter, err)
+		}
+	}
+}
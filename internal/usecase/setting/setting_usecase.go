@@ -2,28 +2,189 @@ package setting
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"regexp"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/cache"
+	"github.com/condotrack/api/pkg/secretbox"
+	"github.com/google/uuid"
 )
 
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// cachePrefix starts the cache keys of settings, so every write drops all of them
+const cachePrefix = "settings:"
+
+// ErrNoMasterKey is returned when a secret setting is written or read without SETTINGS_MASTER_KEY
+var ErrNoMasterKey = errors.New("secret settings require SETTINGS_MASTER_KEY to be configured")
+
 // UseCase handles setting business logic
 type UseCase struct {
-	settingRepo repository.SettingRepository
+	settingRepo  repository.SettingRepository
+	contratoRepo repository.ContratoRepository
+	secrets      *secretbox.Box // nil when no master key is configured
+	cache        cache.Cache
+	cacheTTL     time.Duration
+
+	mu          sync.RWMutex
+	subscribers map[string][]func(value string)
+	validators  map[string]func(value string) error
 }
 
-// NewUseCase creates a new setting use case
-func NewUseCase(settingRepo repository.SettingRepository) *UseCase {
+// NewUseCase creates a new setting use case. Secret settings are encrypted with secrets;
+// without it they can be masked and listed but not written. Contracts are looked up to
+// resolve scoped settings. Reads are served from c for cacheTTL (secrets stay encrypted in
+// it) and every write drops the cached settings; a nil c reads the repository every time.
+func NewUseCase(settingRepo repository.SettingRepository, contratoRepo repository.ContratoRepository, secrets *secretbox.Box, c cache.Cache, cacheTTL time.Duration) *UseCase {
 	return &UseCase{
-		settingRepo: settingRepo,
+		settingRepo:  settingRepo,
+		contratoRepo: contratoRepo,
+		secrets:      secrets,
+		cache:        c,
+		cacheTTL:     cacheTTL,
+		subscribers:  make(map[string][]func(value string)),
+		validators:   make(map[string]func(value string) error),
+	}
+}
+
+// Subscribe registers a function called with the new (decrypted) value whenever the setting
+// changes, so services holding credentials pick them up without a restart
+func (uc *UseCase) Subscribe(key string, fn func(value string)) {
+	uc.mu.Lock()
+	uc.subscribers[key] = append(uc.subscribers[key], fn)
+	uc.mu.Unlock()
+}
+
+// AddValidator registers a check run on every new value of the setting, for formats a
+// validation regex cannot express. Values it rejects are not stored.
+func (uc *UseCase) AddValidator(key string, fn func(value string) error) {
+	uc.mu.Lock()
+	uc.validators[key] = fn
+	uc.mu.Unlock()
+}
+
+// ApplyStoredValues hands the stored value of every subscribed setting to its subscribers.
+// Called at startup; empty settings leave the environment configuration in place.
+func (uc *UseCase) ApplyStoredValues(ctx context.Context) {
+	uc.mu.RLock()
+	keys := make([]string, 0, len(uc.subscribers))
+	for key := range uc.subscribers {
+		keys = append(keys, key)
+	}
+	uc.mu.RUnlock()
+
+	for _, key := range keys {
+		value, err := uc.GetSettingValue(ctx, key)
+		if err != nil {
+			log.Printf("[SETTINGS] Failed to load %s: %v", key, err)
+			continue
+		}
+		if value != "" {
+			uc.notify(key, value)
+		}
+	}
+}
+
+// EncryptStoredSecrets encrypts secret settings still stored in plaintext and returns how
+// many were rewritten
+func (uc *UseCase) EncryptStoredSecrets(ctx context.Context) (int, error) {
+	if uc.secrets == nil {
+		return 0, ErrNoMasterKey
+	}
+	settings, err := uc.settingRepo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get all settings: %w", err)
+	}
+	defer uc.invalidate(ctx)
+
+	count := 0
+	for _, s := range settings {
+		if !s.IsSecret || s.Value == nil || *s.Value == "" || secretbox.IsEncrypted(*s.Value) {
+			continue
+		}
+		sealed, err := uc.secrets.Encrypt(*s.Value)
+		if err != nil {
+			return count, err
+		}
+		if err := uc.settingRepo.UpdateByID(ctx, s.ID, sealed); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (uc *UseCase) notify(key, value string) {
+	uc.mu.RLock()
+	fns := uc.subscribers[key]
+	uc.mu.RUnlock()
+	for _, fn := range fns {
+		fn(value)
+	}
+}
+
+// storedValue returns the value to persist for a setting: secrets are encrypted. keep is
+// true when a secret is sent back masked, meaning the current value stays unchanged.
+func (uc *UseCase) storedValue(setting *entity.Setting, value string) (stored string, keep bool, err error) {
+	if !setting.IsSecret || value == "" {
+		return value, false, nil
+	}
+	if value == entity.SecretMask {
+		return "", true, nil
+	}
+	if uc.secrets == nil {
+		return "", false, ErrNoMasterKey
+	}
+	stored, err = uc.secrets.Encrypt(value)
+	return stored, false, err
+}
+
+// validate checks a new value against the setting rules and its registered validator
+func (uc *UseCase) validate(setting *entity.Setting, value string) error {
+	if err := validateValue(setting, value); err != nil {
+		return err
+	}
+	uc.mu.RLock()
+	fn := uc.validators[setting.Key]
+	uc.mu.RUnlock()
+	if fn != nil && value != "" {
+		if err := fn(value); err != nil {
+			return fmt.Errorf("invalid value for setting %s: %w", setting.Key, err)
+		}
 	}
+	return nil
+}
+
+// validateValue checks the required flag and the validation regex of a setting
+func validateValue(setting *entity.Setting, value string) error {
+	if setting.IsRequired && value == "" {
+		return fmt.Errorf("setting %s is required", setting.Key)
+	}
+	if setting.ValidationRegex != nil && *setting.ValidationRegex != "" && value != "" {
+		matched, err := regexp.MatchString(*setting.ValidationRegex, value)
+		if err != nil {
+			return fmt.Errorf("invalid validation regex for setting %s: %w", setting.Key, err)
+		}
+		if !matched {
+			return fmt.Errorf("value for setting %s does not match the required format", setting.Key)
+		}
+	}
+	return nil
 }
 
 // GetAllSettings returns all settings (with secret values masked)
 func (uc *UseCase) GetAllSettings(ctx context.Context) ([]*entity.SettingPublic, error) {
-	settings, err := uc.settingRepo.GetAll(ctx)
+	settings, err := uc.all(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all settings: %w", err)
 	}
@@ -38,7 +199,7 @@ func (uc *UseCase) GetAllSettings(ctx context.Context) ([]*entity.SettingPublic,
 
 // GetSettingsByCategory returns settings grouped by category
 func (uc *UseCase) GetSettingsByCategory(ctx context.Context) ([]*entity.SettingsByCategory, error) {
-	settings, err := uc.settingRepo.GetAll(ctx)
+	settings, err := uc.all(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all settings: %w", err)
 	}
@@ -75,7 +236,7 @@ func (uc *UseCase) GetSettingsByCategory(ctx context.Context) ([]*entity.Setting
 
 // GetSettingByKey returns a setting by its key (with secret value masked)
 func (uc *UseCase) GetSettingByKey(ctx context.Context, key string) (*entity.SettingPublic, error) {
-	setting, err := uc.settingRepo.GetByKey(ctx, key)
+	setting, err := uc.byKey(ctx, key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get setting: %w", err)
 	}
@@ -86,13 +247,60 @@ func (uc *UseCase) GetSettingByKey(ctx context.Context, key string) (*entity.Set
 	return setting.ToPublic(), nil
 }
 
-// GetSettingValue returns the raw value of a setting (for internal use)
+// GetSettingValue returns the value of a setting, decrypted (for internal use)
 func (uc *UseCase) GetSettingValue(ctx context.Context, key string) (string, error) {
-	return uc.settingRepo.GetValue(ctx, key)
+	value, err := uc.value(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return uc.reveal(value)
+}
+
+// reveal decrypts a stored value; plaintext values are returned as is
+func (uc *UseCase) reveal(stored string) (string, error) {
+	if !secretbox.IsEncrypted(stored) {
+		return stored, nil
+	}
+	if uc.secrets == nil {
+		return "", ErrNoMasterKey
+	}
+	return uc.secrets.Decrypt(stored)
+}
+
+// newChange validates a new value for a setting and returns the change that stores it, or
+// nil when the value is a kept secret or the setting already has it
+func (uc *UseCase) newChange(setting *entity.Setting, value, source, actorID string) (*entity.SettingChange, error) {
+	stored, keep, err := uc.storedValue(setting, value)
+	if err != nil || keep {
+		return nil, err
+	}
+	if err := uc.validate(setting, value); err != nil {
+		return nil, err
+	}
+
+	current := ""
+	if setting.Value != nil {
+		current = *setting.Value
+	}
+	if plain, err := uc.reveal(current); err == nil && plain == value {
+		return nil, nil
+	}
+
+	change := &entity.SettingChange{
+		ID:         uuid.New().String(),
+		SettingKey: setting.Key,
+		Scope:      entity.SettingScopeGlobal,
+		NewValue:   &stored,
+		Source:     source,
+	}
+	if actorID != "" {
+		change.ChangedBy = &actorID
+	}
+	return change, nil
 }
 
 // UpdateSetting updates a single setting value
-func (uc *UseCase) UpdateSetting(ctx context.Context, key string, value string) error {
+func (uc *UseCase) UpdateSetting(ctx context.Context, key string, value string, actorID string) error {
 	// Verify setting exists
 	setting, err := uc.settingRepo.GetByKey(ctx, key)
 	if err != nil {
@@ -102,27 +310,11 @@ func (uc *UseCase) UpdateSetting(ctx context.Context, key string, value string)
 		return fmt.Errorf("setting not found: %s", key)
 	}
 
-	// Validate required fields
-	if setting.IsRequired && value == "" {
-		return fmt.Errorf("setting %s is required", key)
-	}
-
-	// Validate value against regex pattern if defined
-	if setting.ValidationRegex != nil && *setting.ValidationRegex != "" && value != "" {
-		matched, err := regexp.MatchString(*setting.ValidationRegex, value)
-		if err != nil {
-			return fmt.Errorf("invalid validation regex for setting %s: %w", key, err)
-		}
-		if !matched {
-			return fmt.Errorf("value for setting %s does not match the required format", key)
-		}
-	}
-
-	return uc.settingRepo.Update(ctx, key, value)
+	return uc.apply(ctx, setting, value, entity.SettingChangeSourceUpdate, actorID)
 }
 
 // UpdateSettingByID updates a setting by its ID
-func (uc *UseCase) UpdateSettingByID(ctx context.Context, id string, value string) error {
+func (uc *UseCase) UpdateSettingByID(ctx context.Context, id string, value string, actorID string) error {
 	// Verify setting exists
 	setting, err := uc.settingRepo.GetByID(ctx, id)
 	if err != nil {
@@ -132,18 +324,34 @@ func (uc *UseCase) UpdateSettingByID(ctx context.Context, id string, value strin
 		return fmt.Errorf("setting not found: %s", id)
 	}
 
-	// Validate required fields
-	if setting.IsRequired && value == "" {
-		return fmt.Errorf("setting %s is required", setting.Key)
+	return uc.apply(ctx, setting, value, entity.SettingChangeSourceUpdate, actorID)
+}
+
+func (uc *UseCase) apply(ctx context.Context, setting *entity.Setting, value, source, actorID string) error {
+	change, err := uc.newChange(setting, value, source, actorID)
+	if err != nil || change == nil {
+		return err
 	}
 
-	return uc.settingRepo.UpdateByID(ctx, id, value)
+	if err := uc.applyChanges(ctx, []*entity.SettingChange{change}); err != nil {
+		return err
+	}
+	uc.notify(setting.Key, value)
+	return nil
 }
 
-// BulkUpdateSettings updates multiple settings at once
-func (uc *UseCase) BulkUpdateSettings(ctx context.Context, settings map[string]string) error {
+// BulkUpdateSettings updates multiple settings at once. Every changed value is recorded in
+// the history; masked secrets and unchanged values are left out.
+func (uc *UseCase) BulkUpdateSettings(ctx context.Context, settings map[string]string, actorID string) error {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	// Validate all settings exist and required values are provided
-	for key, value := range settings {
+	changes := make([]*entity.SettingChange, 0, len(keys))
+	for _, key := range keys {
 		setting, err := uc.settingRepo.GetByKey(ctx, key)
 		if err != nil {
 			return fmt.Errorf("failed to validate setting %s: %w", key, err)
@@ -151,12 +359,319 @@ func (uc *UseCase) BulkUpdateSettings(ctx context.Context, settings map[string]s
 		if setting == nil {
 			return fmt.Errorf("setting not found: %s", key)
 		}
-		if setting.IsRequired && value == "" {
-			return fmt.Errorf("setting %s is required", key)
+		change, err := uc.newChange(setting, settings[key], entity.SettingChangeSourceBulk, actorID)
+		if err != nil {
+			return err
+		}
+		if change != nil {
+			changes = append(changes, change)
 		}
 	}
+	if len(changes) == 0 {
+		return nil
+	}
 
-	return uc.settingRepo.BulkUpdate(ctx, settings)
+	if err := uc.applyChanges(ctx, changes); err != nil {
+		return err
+	}
+	for _, change := range changes {
+		uc.notify(change.SettingKey, settings[change.SettingKey])
+	}
+	return nil
+}
+
+// GetSettingHistory returns the recorded changes of a setting, newest first. Values of secret
+// settings are masked.
+func (uc *UseCase) GetSettingHistory(ctx context.Context, key string, limit int) ([]entity.SettingChange, error) {
+	setting, err := uc.settingRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	if setting == nil {
+		return nil, fmt.Errorf("setting not found: %s", key)
+	}
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	changes, err := uc.settingRepo.FindChanges(ctx, key, limit)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []entity.SettingChange{}
+	}
+	if setting.IsSecret {
+		for i := range changes {
+			changes[i] = changes[i].Masked()
+		}
+	}
+	return changes, nil
+}
+
+// RollbackSetting restores the value a setting had before the given change, for the scope
+// the change was made in; rolling back the creation of an override removes it. The rollback
+// is itself recorded, so it can be undone the same way.
+func (uc *UseCase) RollbackSetting(ctx context.Context, key, changeID, actorID string) (*entity.SettingPublic, error) {
+	setting, err := uc.settingRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	if setting == nil {
+		return nil, fmt.Errorf("setting not found: %s", key)
+	}
+
+	target, err := uc.settingRepo.FindChangeByID(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil || target.SettingKey != key {
+		return nil, fmt.Errorf("setting change not found: %s", changeID)
+	}
+
+	var change *entity.SettingChange
+	value := ""
+	if target.Scope != "" && target.Scope != entity.SettingScopeGlobal && target.ScopeID != nil {
+		change, err = uc.newOverrideChange(ctx, setting, target.Scope, *target.ScopeID, target.OldValue, entity.SettingChangeSourceRollback, actorID)
+	} else {
+		previous := ""
+		if target.OldValue != nil {
+			previous = *target.OldValue
+		}
+		if value, err = uc.reveal(previous); err != nil {
+			return nil, err
+		}
+		change, err = uc.newChange(setting, value, entity.SettingChangeSourceRollback, actorID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if change != nil {
+		change.RollbackOf = &target.ID
+		if err := uc.applyChanges(ctx, []*entity.SettingChange{change}); err != nil {
+			return nil, err
+		}
+		if change.Scope == entity.SettingScopeGlobal {
+			uc.notify(key, value)
+		}
+	}
+
+	return uc.GetSettingByKey(ctx, key)
+}
+
+// ListOverrides returns the organization and contract overrides of a setting
+func (uc *UseCase) ListOverrides(ctx context.Context, key string) ([]entity.SettingOverride, error) {
+	setting, err := uc.settingRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	if setting == nil {
+		return nil, fmt.Errorf("setting not found: %s", key)
+	}
+
+	overrides, err := uc.settingRepo.FindOverrides(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if overrides == nil {
+		overrides = []entity.SettingOverride{}
+	}
+	return overrides, nil
+}
+
+// SetOverride sets the value of a setting for an organization or contract
+func (uc *UseCase) SetOverride(ctx context.Context, key string, scope entity.SettingScope, scopeID, value, actorID string) (*entity.SettingOverride, error) {
+	return uc.changeOverride(ctx, key, scope, scopeID, &value, actorID)
+}
+
+// DeleteOverride removes the value of a setting for an organization or contract, so it
+// resolves from the next scope again
+func (uc *UseCase) DeleteOverride(ctx context.Context, key string, scope entity.SettingScope, scopeID, actorID string) error {
+	_, err := uc.changeOverride(ctx, key, scope, scopeID, nil, actorID)
+	return err
+}
+
+func (uc *UseCase) changeOverride(ctx context.Context, key string, scope entity.SettingScope, scopeID string, value *string, actorID string) (*entity.SettingOverride, error) {
+	setting, err := uc.settingRepo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	if setting == nil {
+		return nil, fmt.Errorf("setting not found: %s", key)
+	}
+	if err := uc.checkScope(ctx, scope, scopeID); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		existing, err := uc.settingRepo.FindOverride(ctx, key, scope, scopeID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("setting override not found: %s", key)
+		}
+	}
+
+	change, err := uc.newOverrideChange(ctx, setting, scope, scopeID, value, entity.SettingChangeSourceUpdate, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if change != nil {
+		if err := uc.applyChanges(ctx, []*entity.SettingChange{change}); err != nil {
+			return nil, err
+		}
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return uc.settingRepo.FindOverride(ctx, key, scope, scopeID)
+}
+
+// newOverrideChange validates a new override value and returns the change that stores it, or
+// nil when the override already has it. A nil value removes the override.
+func (uc *UseCase) newOverrideChange(ctx context.Context, setting *entity.Setting, scope entity.SettingScope, scopeID string, value *string, source, actorID string) (*entity.SettingChange, error) {
+	if setting.IsSecret {
+		return nil, fmt.Errorf("invalid scope: secret setting %s can only be set globally", setting.Key)
+	}
+	if value != nil {
+		if err := uc.validate(setting, *value); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := uc.settingRepo.FindOverride(ctx, setting.Key, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	if (existing == nil && value == nil) || (existing != nil && value != nil && existing.Value == *value) {
+		return nil, nil
+	}
+
+	change := &entity.SettingChange{
+		ID:         uuid.New().String(),
+		SettingKey: setting.Key,
+		Scope:      scope,
+		ScopeID:    &scopeID,
+		NewValue:   value,
+		Source:     source,
+	}
+	if actorID != "" {
+		change.ChangedBy = &actorID
+	}
+	return change, nil
+}
+
+// checkScope verifies an override scope: contracts must exist and organizations must manage
+// at least one contract
+func (uc *UseCase) checkScope(ctx context.Context, scope entity.SettingScope, scopeID string) error {
+	if scopeID == "" {
+		return errors.New("invalid scope: scope id is required")
+	}
+	switch scope {
+	case entity.SettingScopeContract:
+		contrato, err := uc.contratoRepo.FindByID(ctx, scopeID)
+		if err != nil {
+			return err
+		}
+		if contrato == nil {
+			return fmt.Errorf("contract not found: %s", scopeID)
+		}
+	case entity.SettingScopeOrganization:
+		count, err := uc.contratoRepo.CountByGestorID(ctx, scopeID)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("organization not found: %s", scopeID)
+		}
+	default:
+		return fmt.Errorf("invalid scope: %s (use organization or contract)", scope)
+	}
+	return nil
+}
+
+// ContractTarget returns the target a setting is resolved for on a contract: the contract and
+// the organization of its gestor
+func (uc *UseCase) ContractTarget(ctx context.Context, contractID string) (entity.SettingTarget, error) {
+	contrato, err := uc.contratoRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return entity.SettingTarget{}, err
+	}
+	if contrato == nil {
+		return entity.SettingTarget{}, fmt.Errorf("contract not found: %s", contractID)
+	}
+	return entity.SettingTarget{OrganizationID: contrato.GestorID, ContractID: contrato.ID}, nil
+}
+
+// ResolveSettingValue returns the value of a setting for a target, taking the contract
+// override, then the organization override, then the global value (for internal use)
+func (uc *UseCase) ResolveSettingValue(ctx context.Context, key string, target entity.SettingTarget) (*entity.ResolvedSetting, error) {
+	setting, err := uc.byKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	if setting == nil {
+		return nil, fmt.Errorf("setting not found: %s", key)
+	}
+
+	scopes := []struct {
+		scope entity.SettingScope
+		id    string
+	}{
+		{entity.SettingScopeContract, target.ContractID},
+		{entity.SettingScopeOrganization, target.OrganizationID},
+	}
+	for _, sc := range scopes {
+		if sc.id == "" || setting.IsSecret {
+			continue
+		}
+		override, err := uc.override(ctx, key, sc.scope, sc.id)
+		if err != nil {
+			return nil, err
+		}
+		if override != nil {
+			return &entity.ResolvedSetting{Key: key, Value: override.Value, Scope: sc.scope, ScopeID: sc.id}, nil
+		}
+	}
+
+	stored := ""
+	if setting.Value != nil {
+		stored = *setting.Value
+	}
+	value, err := uc.reveal(stored)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.ResolvedSetting{Key: key, Value: value, Scope: entity.SettingScopeGlobal}, nil
+}
+
+// ResolveSetting resolves a setting for a contract or organization for display: secret values
+// are masked. With a contract, the organization is the gestor managing it.
+func (uc *UseCase) ResolveSetting(ctx context.Context, key, contractID, organizationID string) (*entity.ResolvedSetting, error) {
+	target := entity.SettingTarget{OrganizationID: organizationID}
+	if contractID != "" {
+		var err error
+		if target, err = uc.ContractTarget(ctx, contractID); err != nil {
+			return nil, err
+		}
+	}
+
+	resolved, err := uc.ResolveSettingValue(ctx, key, target)
+	if err != nil {
+		return nil, err
+	}
+	setting, err := uc.byKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	if setting != nil && setting.IsSecret && resolved.Value != "" {
+		resolved.Value = entity.SecretMask
+	}
+	return resolved, nil
 }
 
 // GetCategories returns all available categories
@@ -166,23 +681,70 @@ func (uc *UseCase) GetCategories(ctx context.Context) ([]string, error) {
 
 // GetAsaasConfig returns Asaas configuration for internal use
 func (uc *UseCase) GetAsaasConfig(ctx context.Context) (apiKey, apiURL, webhookToken, env string, err error) {
-	apiKey, _ = uc.settingRepo.GetValue(ctx, "asaas_api_key")
-	apiURL, _ = uc.settingRepo.GetValue(ctx, "asaas_api_url")
-	webhookToken, _ = uc.settingRepo.GetValue(ctx, "asaas_webhook_token")
-	env, _ = uc.settingRepo.GetValue(ctx, "asaas_env")
+	apiKey, _ = uc.GetSettingValue(ctx, "asaas_api_key")
+	apiURL, _ = uc.GetSettingValue(ctx, "asaas_api_url")
+	webhookToken, _ = uc.GetSettingValue(ctx, "asaas_webhook_token")
+	env, _ = uc.GetSettingValue(ctx, "asaas_env")
 	return
 }
 
 // GetGeminiAPIKey returns the Gemini API key for internal use
 func (uc *UseCase) GetGeminiAPIKey(ctx context.Context) (string, error) {
-	return uc.settingRepo.GetValue(ctx, "gemini_api_key")
+	return uc.GetSettingValue(ctx, "gemini_api_key")
 }
 
 // IsAIEnabled returns whether AI features are enabled
 func (uc *UseCase) IsAIEnabled(ctx context.Context) (bool, error) {
-	value, err := uc.settingRepo.GetValue(ctx, "ai_enabled")
+	value, err := uc.value(ctx, "ai_enabled")
 	if err != nil {
 		return false, err
 	}
 	return value == "true" || value == "1", nil
 }
+
+// applyChanges writes the changes and drops the cached settings, here and, with a shared
+// cache, on every instance
+func (uc *UseCase) applyChanges(ctx context.Context, changes []*entity.SettingChange) error {
+	if err := uc.settingRepo.ApplyChanges(ctx, changes); err != nil {
+		return err
+	}
+	uc.invalidate(ctx)
+	return nil
+}
+
+// invalidate drops the cached settings. A failure is logged: the stale values expire with
+// the TTL.
+func (uc *UseCase) invalidate(ctx context.Context) {
+	if uc.cache == nil {
+		return
+	}
+	if err := uc.cache.DeletePrefix(ctx, cachePrefix); err != nil {
+		log.Printf("[SETTINGS] Failed to invalidate the cache: %v", err)
+	}
+}
+
+// all returns every setting from the cache
+func (uc *UseCase) all(ctx context.Context) ([]*entity.Setting, error) {
+	return cache.Fetch(ctx, uc.cache, cachePrefix+"all", uc.cacheTTL, uc.settingRepo.GetAll)
+}
+
+// byKey returns a setting from the cache, nil when it does not exist
+func (uc *UseCase) byKey(ctx context.Context, key string) (*entity.Setting, error) {
+	return cache.Fetch(ctx, uc.cache, cachePrefix+"key:"+key, uc.cacheTTL, func(ctx context.Context) (*entity.Setting, error) {
+		return uc.settingRepo.GetByKey(ctx, key)
+	})
+}
+
+// value returns the stored value of a setting from the cache
+func (uc *UseCase) value(ctx context.Context, key string) (string, error) {
+	return cache.Fetch(ctx, uc.cache, cachePrefix+"value:"+key, uc.cacheTTL, func(ctx context.Context) (string, error) {
+		return uc.settingRepo.GetValue(ctx, key)
+	})
+}
+
+// override returns the override of a setting for a scope from the cache, nil when there is none
+func (uc *UseCase) override(ctx context.Context, key string, scope entity.SettingScope, scopeID string) (*entity.SettingOverride, error) {
+	return cache.Fetch(ctx, uc.cache, cachePrefix+"override:"+key+":"+string(scope)+":"+scopeID, uc.cacheTTL, func(ctx context.Context) (*entity.SettingOverride, error) {
+		return uc.settingRepo.FindOverride(ctx, key, scope, scopeID)
+	})
+}
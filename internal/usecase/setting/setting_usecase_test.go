@@ -0,0 +1,314 @@
+package setting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/cache"
+	"github.com/condotrack/api/pkg/secretbox"
+)
+
+const testMasterKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func strPtr(s string) *string { return &s }
+
+func newTestUseCase(t *testing.T, withKey bool) (*UseCase, *testutil.MockSettingRepository) {
+	t.Helper()
+	repo := testutil.NewMockSettingRepository(
+		&entity.Setting{ID: "1", Key: "asaas_api_key", Type: entity.SettingTypeSecret, IsSecret: true},
+		&entity.Setting{ID: "2", Key: "ai_enabled", Type: entity.SettingTypeBoolean, Value: strPtr("false")},
+		&entity.Setting{ID: "3", Key: "audit_target_score", Type: entity.SettingTypeNumber, Value: strPtr("80")},
+	)
+	var box *secretbox.Box
+	if withKey {
+		var err error
+		if box, err = secretbox.New(testMasterKey); err != nil {
+			t.Fatalf("secretbox.New: %v", err)
+		}
+	}
+	contratos := testutil.NewMockContratoRepository(
+		&entity.Contrato{ID: "c-1", GestorID: "g-1"},
+		&entity.Contrato{ID: "c-2", GestorID: "g-1"},
+	)
+	return NewUseCase(repo, contratos, box, cache.NewMemory(), time.Minute), repo
+}
+
+func TestUpdateSetting_EncryptsSecrets(t *testing.T) {
+	uc, repo := newTestUseCase(t, true)
+	ctx := context.Background()
+
+	var notified string
+	uc.Subscribe("asaas_api_key", func(v string) { notified = v })
+
+	if err := uc.UpdateSetting(ctx, "asaas_api_key", "$aact_123", "admin-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored := *repo.Settings["asaas_api_key"].Value
+	if !secretbox.IsEncrypted(stored) {
+		t.Errorf("expected encrypted value at rest, got %q", stored)
+	}
+	if notified != "$aact_123" {
+		t.Errorf("subscriber got %q, want the plaintext value", notified)
+	}
+
+	value, err := uc.GetSettingValue(ctx, "asaas_api_key")
+	if err != nil || value != "$aact_123" {
+		t.Errorf("GetSettingValue = %q, %v", value, err)
+	}
+
+	public, _ := uc.GetSettingByKey(ctx, "asaas_api_key")
+	if public.Value != entity.SecretMask {
+		t.Errorf("read response leaked the secret: %q", public.Value)
+	}
+}
+
+func TestUpdateSetting_MaskKeepsSecret(t *testing.T) {
+	uc, repo := newTestUseCase(t, true)
+	ctx := context.Background()
+
+	_ = uc.UpdateSetting(ctx, "asaas_api_key", "$aact_123", "admin-1")
+	before := *repo.Settings["asaas_api_key"].Value
+
+	err := uc.BulkUpdateSettings(ctx, map[string]string{"asaas_api_key": entity.SecretMask, "ai_enabled": "true"}, "admin-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *repo.Settings["asaas_api_key"].Value != before {
+		t.Error("masked value overwrote the stored secret")
+	}
+	if *repo.Settings["ai_enabled"].Value != "true" {
+		t.Error("expected plain setting updated")
+	}
+}
+
+func TestUpdateSetting_SecretWithoutMasterKey(t *testing.T) {
+	uc, repo := newTestUseCase(t, false)
+
+	err := uc.UpdateSetting(context.Background(), "asaas_api_key", "$aact_123", "admin-1")
+	if !errors.Is(err, ErrNoMasterKey) {
+		t.Errorf("expected ErrNoMasterKey, got %v", err)
+	}
+	if repo.Settings["asaas_api_key"].Value != nil {
+		t.Error("secret stored in plaintext without a master key")
+	}
+	if err := uc.UpdateSetting(context.Background(), "ai_enabled", "true", "admin-1"); err != nil {
+		t.Errorf("plain settings should not need a master key: %v", err)
+	}
+}
+
+func TestEncryptStoredSecretsAndApply(t *testing.T) {
+	uc, repo := newTestUseCase(t, true)
+	ctx := context.Background()
+	repo.Settings["asaas_api_key"].Value = strPtr("legacy-plain")
+
+	n, err := uc.EncryptStoredSecrets(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("EncryptStoredSecrets = %d, %v; want 1", n, err)
+	}
+	if !secretbox.IsEncrypted(*repo.Settings["asaas_api_key"].Value) {
+		t.Error("expected plaintext secret to be encrypted")
+	}
+
+	var applied string
+	uc.Subscribe("asaas_api_key", func(v string) { applied = v })
+	uc.ApplyStoredValues(ctx)
+	if applied != "legacy-plain" {
+		t.Errorf("ApplyStoredValues handed %q, want the decrypted value", applied)
+	}
+}
+
+func TestUpdateSetting_RecordsHistory(t *testing.T) {
+	uc, repo := newTestUseCase(t, true)
+	ctx := context.Background()
+
+	if err := uc.UpdateSetting(ctx, "ai_enabled", "true", "admin-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Unchanged values are not recorded
+	_ = uc.BulkUpdateSettings(ctx, map[string]string{"ai_enabled": "true"}, "admin-2")
+
+	if len(repo.Changes) != 1 {
+		t.Fatalf("expected 1 recorded change, got %d", len(repo.Changes))
+	}
+	ch := repo.Changes[0]
+	if ch.OldValue == nil || *ch.OldValue != "false" || *ch.NewValue != "true" {
+		t.Errorf("unexpected change values: %v -> %v", ch.OldValue, ch.NewValue)
+	}
+	if ch.ChangedBy == nil || *ch.ChangedBy != "admin-1" || ch.Source != entity.SettingChangeSourceUpdate {
+		t.Errorf("unexpected actor or source: %v, %q", ch.ChangedBy, ch.Source)
+	}
+
+	_ = uc.UpdateSetting(ctx, "asaas_api_key", "$aact_123", "admin-1")
+	history, err := uc.GetSettingHistory(ctx, "asaas_api_key", 0)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("GetSettingHistory = %d entries, %v", len(history), err)
+	}
+	if *history[0].NewValue != entity.SecretMask {
+		t.Errorf("history leaked the secret: %q", *history[0].NewValue)
+	}
+}
+
+func TestRollbackSetting(t *testing.T) {
+	uc, repo := newTestUseCase(t, true)
+	ctx := context.Background()
+
+	var notified string
+	uc.Subscribe("asaas_api_key", func(v string) { notified = v })
+
+	_ = uc.UpdateSetting(ctx, "asaas_api_key", "first", "admin-1")
+	_ = uc.UpdateSetting(ctx, "asaas_api_key", "second", "admin-1")
+	second := repo.Changes[1]
+
+	if _, err := uc.RollbackSetting(ctx, "asaas_api_key", second.ID, "admin-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, _ := uc.GetSettingValue(ctx, "asaas_api_key"); value != "first" {
+		t.Errorf("expected value before the change restored, got %q", value)
+	}
+	if notified != "first" {
+		t.Errorf("subscriber got %q after rollback", notified)
+	}
+
+	rollback := repo.Changes[len(repo.Changes)-1]
+	if rollback.Source != entity.SettingChangeSourceRollback || rollback.RollbackOf == nil || *rollback.RollbackOf != second.ID {
+		t.Errorf("rollback not recorded: %+v", rollback)
+	}
+
+	if _, err := uc.RollbackSetting(ctx, "ai_enabled", second.ID, "admin-2"); err == nil {
+		t.Error("expected an error rolling back a change of another setting")
+	}
+}
+
+func TestResolveSettingValue_ScopeOrder(t *testing.T) {
+	uc, _ := newTestUseCase(t, true)
+	ctx := context.Background()
+
+	if _, err := uc.SetOverride(ctx, "audit_target_score", entity.SettingScopeOrganization, "g-1", "85", "admin-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.SetOverride(ctx, "audit_target_score", entity.SettingScopeContract, "c-1", "90", "admin-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		contract  string
+		wantValue string
+		wantScope entity.SettingScope
+	}{
+		{"c-1", "90", entity.SettingScopeContract},
+		{"c-2", "85", entity.SettingScopeOrganization},
+	}
+	for _, tt := range tests {
+		target, err := uc.ContractTarget(ctx, tt.contract)
+		if err != nil {
+			t.Fatalf("ContractTarget(%s): %v", tt.contract, err)
+		}
+		resolved, err := uc.ResolveSettingValue(ctx, "audit_target_score", target)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resolved.Value != tt.wantValue || resolved.Scope != tt.wantScope {
+			t.Errorf("contract %s resolved %q from %s, want %q from %s",
+				tt.contract, resolved.Value, resolved.Scope, tt.wantValue, tt.wantScope)
+		}
+	}
+
+	resolved, _ := uc.ResolveSettingValue(ctx, "audit_target_score", entity.SettingTarget{})
+	if resolved.Value != "80" || resolved.Scope != entity.SettingScopeGlobal {
+		t.Errorf("expected the global value, got %q from %s", resolved.Value, resolved.Scope)
+	}
+
+	if err := uc.DeleteOverride(ctx, "audit_target_score", entity.SettingScopeContract, "c-1", "admin-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	target, _ := uc.ContractTarget(ctx, "c-1")
+	if resolved, _ := uc.ResolveSettingValue(ctx, "audit_target_score", target); resolved.Value != "85" {
+		t.Errorf("expected the organization value after removing the contract override, got %q", resolved.Value)
+	}
+}
+
+func TestSetOverride_Invalid(t *testing.T) {
+	uc, _ := newTestUseCase(t, true)
+	ctx := context.Background()
+
+	if _, err := uc.SetOverride(ctx, "asaas_api_key", entity.SettingScopeContract, "c-1", "key", "admin-1"); err == nil {
+		t.Error("expected secret settings to be global only")
+	}
+	if _, err := uc.SetOverride(ctx, "audit_target_score", entity.SettingScopeContract, "missing", "90", "admin-1"); err == nil {
+		t.Error("expected an error for an unknown contract")
+	}
+	if _, err := uc.SetOverride(ctx, "audit_target_score", entity.SettingScopeGlobal, "c-1", "90", "admin-1"); err == nil {
+		t.Error("expected an error for the global scope")
+	}
+}
+
+func TestRollbackSetting_Override(t *testing.T) {
+	uc, repo := newTestUseCase(t, true)
+	ctx := context.Background()
+
+	_, _ = uc.SetOverride(ctx, "audit_target_score", entity.SettingScopeContract, "c-1", "90", "admin-1")
+	created := repo.Changes[len(repo.Changes)-1]
+
+	if _, err := uc.RollbackSetting(ctx, "audit_target_score", created.ID, "admin-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if o, _ := repo.FindOverride(ctx, "audit_target_score", entity.SettingScopeContract, "c-1"); o != nil {
+		t.Errorf("expected rolling back the creation to remove the override, got %q", o.Value)
+	}
+	if *repo.Settings["audit_target_score"].Value != "80" {
+		t.Error("rolling back an override changed the global value")
+	}
+}
+
+func TestUpdateSetting_Validator(t *testing.T) {
+	uc, repo := newTestUseCase(t, false)
+	ctx := context.Background()
+
+	uc.AddValidator("audit_target_score", func(v string) error {
+		if v == "101" {
+			return errors.New("score above 100")
+		}
+		return nil
+	})
+
+	if err := uc.UpdateSetting(ctx, "audit_target_score", "101", "admin-1"); err == nil {
+		t.Fatal("expected the validator to reject the value")
+	}
+	if *repo.Settings["audit_target_score"].Value != "80" {
+		t.Error("rejected value was stored")
+	}
+	if err := uc.UpdateSetting(ctx, "audit_target_score", "90", "admin-1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSettings_CachedUntilWrite(t *testing.T) {
+	uc, repo := newTestUseCase(t, true)
+	ctx := context.Background()
+
+	if enabled, _ := uc.IsAIEnabled(ctx); enabled {
+		t.Fatal("expected AI to be disabled")
+	}
+	uc.GetSettingByKey(ctx, "audit_target_score")
+
+	// Changes made behind the use case are only seen once the cache expires
+	repo.Settings["ai_enabled"].Value = strPtr("true")
+	repo.Settings["audit_target_score"].Value = strPtr("70")
+	if enabled, _ := uc.IsAIEnabled(ctx); enabled {
+		t.Error("expected the cached value")
+	}
+
+	if err := uc.UpdateSetting(ctx, "audit_target_score", "75", "admin-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if enabled, _ := uc.IsAIEnabled(ctx); !enabled {
+		t.Error("expected a write to drop the cached settings")
+	}
+	if public, _ := uc.GetSettingByKey(ctx, "audit_target_score"); public.Value != "75" {
+		t.Errorf("expected the new value, got %q", public.Value)
+	}
+}
@@ -0,0 +1,476 @@
+package splitadjustment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/infrastructure/database"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrAccessDenied is returned when an instructor acts on a split or dispute of someone else
+var ErrAccessDenied = errors.New("access restricted to your own revenue splits")
+
+// UseCase defines the revenue split adjustment and dispute use case interface
+type UseCase interface {
+	// ListAdjustments returns adjustments matching the filters
+	ListAdjustments(ctx context.Context, filters entity.SplitAdjustmentFilters) ([]entity.SplitAdjustment, error)
+
+	// CreateAdjustment requests a change of the instructor share of a split
+	CreateAdjustment(ctx context.Context, splitID string, req *entity.CreateSplitAdjustmentRequest, userID string) (*entity.SplitAdjustment, error)
+
+	// ApproveAdjustment applies a pending adjustment to its split and the instructor ledger
+	ApproveAdjustment(ctx context.Context, id string, req *entity.DecideSplitAdjustmentRequest, userID string) (*entity.SplitAdjustment, error)
+
+	// RejectAdjustment discards a pending adjustment
+	RejectAdjustment(ctx context.Context, id string, req *entity.DecideSplitAdjustmentRequest, userID string) (*entity.SplitAdjustment, error)
+
+	// ListDisputes returns disputes matching the filters; instructors only see their own
+	ListDisputes(ctx context.Context, filters entity.SplitDisputeFilters, userID, role string) ([]entity.SplitDispute, error)
+
+	// GetDispute returns a dispute by ID
+	GetDispute(ctx context.Context, id, userID, role string) (*entity.SplitDispute, error)
+
+	// OpenDispute lets the instructor of a split contest it
+	OpenDispute(ctx context.Context, splitID string, req *entity.OpenSplitDisputeRequest, userID string) (*entity.SplitDispute, error)
+
+	// ReviewDispute marks an open dispute as under review
+	ReviewDispute(ctx context.Context, id string) (*entity.SplitDispute, error)
+
+	// ResolveDispute closes a dispute, optionally requesting an adjustment of the split
+	ResolveDispute(ctx context.Context, id string, req *entity.ResolveSplitDisputeRequest, userID string) (*entity.SplitDispute, error)
+}
+
+type splitAdjustmentUseCase struct {
+	repo             repository.SplitAdjustmentRepository
+	disputeRepo      repository.SplitDisputeRepository
+	revenueSplitRepo repository.RevenueSplitRepository
+	ledgerRepo       repository.InstructorLedgerRepository
+	db               *database.MySQL
+}
+
+// NewUseCase creates a new revenue split adjustment use case
+func NewUseCase(
+	repo repository.SplitAdjustmentRepository,
+	disputeRepo repository.SplitDisputeRepository,
+	revenueSplitRepo repository.RevenueSplitRepository,
+	ledgerRepo repository.InstructorLedgerRepository,
+	db *database.MySQL,
+) UseCase {
+	return &splitAdjustmentUseCase{
+		repo:             repo,
+		disputeRepo:      disputeRepo,
+		revenueSplitRepo: revenueSplitRepo,
+		ledgerRepo:       ledgerRepo,
+		db:               db,
+	}
+}
+
+// ListAdjustments returns adjustments matching the filters
+func (uc *splitAdjustmentUseCase) ListAdjustments(ctx context.Context, filters entity.SplitAdjustmentFilters) ([]entity.SplitAdjustment, error) {
+	adjustments, err := uc.repo.FindAll(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if adjustments == nil {
+		adjustments = []entity.SplitAdjustment{}
+	}
+	return adjustments, nil
+}
+
+// CreateAdjustment validates the new instructor amount against the current split and records
+// the request; the split itself only changes on approval
+func (uc *splitAdjustmentUseCase) CreateAdjustment(ctx context.Context, splitID string, req *entity.CreateSplitAdjustmentRequest, userID string) (*entity.SplitAdjustment, error) {
+	split, err := uc.adjustableSplit(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAdjustment(split, *req.InstructorAmount); err != nil {
+		return nil, err
+	}
+
+	if req.DisputeID != nil && *req.DisputeID != "" {
+		dispute, err := uc.disputeRepo.FindByID(ctx, *req.DisputeID)
+		if err != nil {
+			return nil, err
+		}
+		if dispute == nil || dispute.SplitID != split.ID {
+			return nil, errors.New("split dispute not found")
+		}
+		if !dispute.IsActive() {
+			return nil, errors.New("invalid dispute: dispute is already closed")
+		}
+	} else {
+		req.DisputeID = nil
+	}
+
+	adjustment := newAdjustment(split, *req.InstructorAmount, req.Reason, req.DisputeID, userID)
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := uc.createAdjustmentWithTx(ctx, tx, adjustment); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.FindByID(ctx, adjustment.ID)
+}
+
+// ApproveAdjustment applies the adjustment. Pending splits are re-priced so the next payout
+// carries the new share; processed splits were already paid out, so the difference is only
+// recorded in the instructor ledger and settled with the next payouts.
+func (uc *splitAdjustmentUseCase) ApproveAdjustment(ctx context.Context, id string, req *entity.DecideSplitAdjustmentRequest, userID string) (*entity.SplitAdjustment, error) {
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	adjustment, err := uc.repo.FindByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment == nil {
+		return nil, errors.New("split adjustment not found")
+	}
+	if adjustment.Status != entity.SplitAdjustmentStatusPending {
+		return nil, errors.New("invalid status: adjustment was already " + adjustment.Status)
+	}
+	if adjustment.RequestedBy == userID {
+		return nil, errors.New("invalid approval: an adjustment must be approved by another admin")
+	}
+
+	split, err := uc.revenueSplitRepo.FindByIDForUpdateWithTx(ctx, tx, adjustment.SplitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, errors.New("revenue split not found")
+	}
+	if split.InstructorAmount != adjustment.PreviousInstructorAmount {
+		return nil, errors.New("invalid adjustment: split changed since the adjustment was requested")
+	}
+
+	switch split.Status {
+	case entity.RevenueSplitStatusPending:
+		if split.PayoutBatchID != nil {
+			return nil, errors.New("invalid adjustment: split is in a payout batch; cancel the batch first")
+		}
+		if split.TransferStatus != nil && *split.TransferStatus == entity.SplitTransferStatusProcessing {
+			return nil, errors.New("invalid adjustment: split is being transferred")
+		}
+		split.Parties, err = uc.revenueSplitRepo.FindParties(ctx, split.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := entity.AdjustInstructorShare(split, adjustment.InstructorAmount); err != nil {
+			return nil, err
+		}
+		if err := uc.revenueSplitRepo.UpdateAmountsWithTx(ctx, tx, split); err != nil {
+			return nil, err
+		}
+		if len(split.Parties) > 0 {
+			if err := uc.revenueSplitRepo.ReplacePartiesWithTx(ctx, tx, split); err != nil {
+				return nil, err
+			}
+		}
+	case entity.RevenueSplitStatusProcessed:
+		// Already paid out: only the ledger records the difference
+	default:
+		return nil, errors.New("invalid adjustment: split is " + split.Status)
+	}
+
+	now := time.Now()
+	entry := entity.NewSplitLedgerEntry(split, entity.LedgerEntryAdjustment, adjustment.ID, adjustment.Delta(),
+		"Ajuste - matrícula "+split.EnrollmentID, now)
+	if entry != nil {
+		entry.ID = uuid.New().String()
+		if err := uc.ledgerRepo.CreateWithTx(ctx, tx, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	decide(adjustment, entity.SplitAdjustmentStatusApproved, req, userID, now)
+	if err := uc.repo.UpdateWithTx(ctx, tx, adjustment); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.FindByID(ctx, adjustment.ID)
+}
+
+// RejectAdjustment discards a pending adjustment; the split is left untouched
+func (uc *splitAdjustmentUseCase) RejectAdjustment(ctx context.Context, id string, req *entity.DecideSplitAdjustmentRequest, userID string) (*entity.SplitAdjustment, error) {
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	adjustment, err := uc.repo.FindByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment == nil {
+		return nil, errors.New("split adjustment not found")
+	}
+	if adjustment.Status != entity.SplitAdjustmentStatusPending {
+		return nil, errors.New("invalid status: adjustment was already " + adjustment.Status)
+	}
+
+	decide(adjustment, entity.SplitAdjustmentStatusRejected, req, userID, time.Now())
+	if err := uc.repo.UpdateWithTx(ctx, tx, adjustment); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.FindByID(ctx, adjustment.ID)
+}
+
+// ListDisputes returns disputes matching the filters
+func (uc *splitAdjustmentUseCase) ListDisputes(ctx context.Context, filters entity.SplitDisputeFilters, userID, role string) ([]entity.SplitDispute, error) {
+	if role != string(entity.RoleAdmin) {
+		if userID == "" {
+			return nil, ErrAccessDenied
+		}
+		filters.InstructorID = userID
+	}
+
+	disputes, err := uc.disputeRepo.FindAll(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	if disputes == nil {
+		disputes = []entity.SplitDispute{}
+	}
+	return disputes, nil
+}
+
+// GetDispute returns a dispute by ID
+func (uc *splitAdjustmentUseCase) GetDispute(ctx context.Context, id, userID, role string) (*entity.SplitDispute, error) {
+	dispute, err := uc.disputeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil {
+		return nil, errors.New("split dispute not found")
+	}
+	if err := authorize(dispute.InstructorID, userID, role); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// OpenDispute records a contestation by the instructor of the split. A split has at most one
+// dispute awaiting a resolution at a time.
+func (uc *splitAdjustmentUseCase) OpenDispute(ctx context.Context, splitID string, req *entity.OpenSplitDisputeRequest, userID string) (*entity.SplitDispute, error) {
+	split, err := uc.revenueSplitRepo.FindByID(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, errors.New("revenue split not found")
+	}
+	if split.InstructorID == nil || *split.InstructorID != userID {
+		return nil, ErrAccessDenied
+	}
+
+	active, err := uc.disputeRepo.FindActiveBySplit(ctx, split.ID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, errors.New("invalid dispute: split already has an open dispute")
+	}
+
+	dispute := &entity.SplitDispute{
+		ID:             uuid.New().String(),
+		SplitID:        split.ID,
+		InstructorID:   userID,
+		Status:         entity.SplitDisputeStatusOpen,
+		Reason:         req.Reason,
+		ExpectedAmount: req.ExpectedAmount,
+	}
+	if err := uc.disputeRepo.Create(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	return uc.disputeRepo.FindByID(ctx, dispute.ID)
+}
+
+// ReviewDispute marks an open dispute as under review
+func (uc *splitAdjustmentUseCase) ReviewDispute(ctx context.Context, id string) (*entity.SplitDispute, error) {
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	dispute, err := uc.lockDispute(ctx, tx, id, entity.SplitDisputeStatusUnderReview)
+	if err != nil {
+		return nil, err
+	}
+	dispute.Status = entity.SplitDisputeStatusUnderReview
+	if err := uc.disputeRepo.UpdateWithTx(ctx, tx, dispute); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return uc.disputeRepo.FindByID(ctx, dispute.ID)
+}
+
+// ResolveDispute closes a dispute with resolution notes. Resolving it with an instructor
+// amount requests the matching adjustment, which still goes through approval.
+func (uc *splitAdjustmentUseCase) ResolveDispute(ctx context.Context, id string, req *entity.ResolveSplitDisputeRequest, userID string) (*entity.SplitDispute, error) {
+	if req.InstructorAmount != nil && req.Status != entity.SplitDisputeStatusResolved {
+		return nil, errors.New("invalid resolution: an instructor amount requires status resolved")
+	}
+
+	tx, err := uc.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	dispute, err := uc.lockDispute(ctx, tx, id, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.InstructorAmount != nil {
+		split, err := uc.adjustableSplit(ctx, dispute.SplitID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkAdjustment(split, *req.InstructorAmount); err != nil {
+			return nil, err
+		}
+		adjustment := newAdjustment(split, *req.InstructorAmount, req.ResolutionNotes, &dispute.ID, userID)
+		if err := uc.createAdjustmentWithTx(ctx, tx, adjustment); err != nil {
+			return nil, err
+		}
+		dispute.AdjustmentID = &adjustment.ID
+	}
+
+	now := time.Now()
+	dispute.Status = req.Status
+	dispute.ResolutionNotes = &req.ResolutionNotes
+	dispute.ResolvedBy = &userID
+	dispute.ResolvedAt = &now
+	if err := uc.disputeRepo.UpdateWithTx(ctx, tx, dispute); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return uc.disputeRepo.FindByID(ctx, dispute.ID)
+}
+
+// lockDispute locks a dispute and checks it may move to the given status
+func (uc *splitAdjustmentUseCase) lockDispute(ctx context.Context, tx *sqlx.Tx, id, status string) (*entity.SplitDispute, error) {
+	dispute, err := uc.disputeRepo.FindByIDForUpdateWithTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil {
+		return nil, errors.New("split dispute not found")
+	}
+	if !entity.CanTransitionSplitDispute(dispute.Status, status) {
+		return nil, errors.New("invalid status transition: " + dispute.Status + " -> " + status)
+	}
+	return dispute, nil
+}
+
+// adjustableSplit returns a split whose instructor share may still be adjusted
+func (uc *splitAdjustmentUseCase) adjustableSplit(ctx context.Context, splitID string) (*entity.RevenueSplit, error) {
+	split, err := uc.revenueSplitRepo.FindByID(ctx, splitID)
+	if err != nil {
+		return nil, err
+	}
+	if split == nil {
+		return nil, errors.New("revenue split not found")
+	}
+	if split.InstructorID == nil || *split.InstructorID == "" {
+		return nil, errors.New("invalid adjustment: split has no instructor")
+	}
+	if split.Status != entity.RevenueSplitStatusPending && split.Status != entity.RevenueSplitStatusProcessed {
+		return nil, errors.New("invalid adjustment: split is " + split.Status)
+	}
+	return split, nil
+}
+
+// createAdjustmentWithTx records an adjustment unless its split already has one awaiting approval
+func (uc *splitAdjustmentUseCase) createAdjustmentWithTx(ctx context.Context, tx *sqlx.Tx, adjustment *entity.SplitAdjustment) error {
+	pending, err := uc.repo.FindPendingBySplitWithTx(ctx, tx, adjustment.SplitID)
+	if err != nil {
+		return err
+	}
+	if pending != nil {
+		return errors.New("invalid adjustment: split already has an adjustment awaiting approval")
+	}
+	return uc.repo.CreateWithTx(ctx, tx, adjustment)
+}
+
+// checkAdjustment applies the new amount to a copy of the split to validate it
+func checkAdjustment(split *entity.RevenueSplit, amount money.Cents) error {
+	probe := *split
+	probe.Parties = nil
+	if err := entity.AdjustInstructorShare(&probe, amount); err != nil {
+		return err
+	}
+	if probe.InstructorAmount == split.InstructorAmount {
+		return errors.New("invalid adjustment: instructor amount is unchanged")
+	}
+	return nil
+}
+
+func newAdjustment(split *entity.RevenueSplit, amount money.Cents, reason string, disputeID *string, userID string) *entity.SplitAdjustment {
+	return &entity.SplitAdjustment{
+		ID:                       uuid.New().String(),
+		SplitID:                  split.ID,
+		DisputeID:                disputeID,
+		Status:                   entity.SplitAdjustmentStatusPending,
+		Reason:                   reason,
+		PreviousInstructorAmount: split.InstructorAmount,
+		InstructorAmount:         amount,
+		RequestedBy:              userID,
+	}
+}
+
+func decide(adjustment *entity.SplitAdjustment, status string, req *entity.DecideSplitAdjustmentRequest, userID string, at time.Time) {
+	adjustment.Status = status
+	adjustment.DecidedBy = &userID
+	adjustment.DecidedAt = &at
+	if req != nil && req.Notes != nil && *req.Notes != "" {
+		adjustment.DecisionNotes = req.Notes
+	}
+}
+
+// authorize allows admins and the instructor the dispute belongs to
+func authorize(instructorID, userID, role string) error {
+	if role == string(entity.RoleAdmin) {
+		return nil
+	}
+	if userID == "" || userID != instructorID {
+		return ErrAccessDenied
+	}
+	return nil
+}
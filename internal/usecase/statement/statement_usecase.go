@@ -0,0 +1,276 @@
+package statement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/money"
+)
+
+// ErrStudentNotFound is returned for a student without enrollments
+var ErrStudentNotFound = errors.New("student not found")
+
+// ErrAccessDenied is returned when a student asks for the statement of someone else
+var ErrAccessDenied = errors.New("access restricted to your own statement")
+
+// UseCase defines the student statement use case
+type UseCase interface {
+	// GetStatement returns the statement of a student across enrollments. Admins see any
+	// student, students only themselves.
+	GetStatement(ctx context.Context, studentID, userID, role string) (*entity.StudentStatement, error)
+}
+
+type statementUseCase struct {
+	matriculaRepo repository.MatriculaRepository
+	paymentRepo   repository.PaymentRepository
+	transferRepo  repository.EnrollmentTransferRepository
+	now           func() time.Time
+}
+
+// NewUseCase creates a new student statement use case
+func NewUseCase(
+	matriculaRepo repository.MatriculaRepository,
+	paymentRepo repository.PaymentRepository,
+	transferRepo repository.EnrollmentTransferRepository,
+) UseCase {
+	return &statementUseCase{
+		matriculaRepo: matriculaRepo,
+		paymentRepo:   paymentRepo,
+		transferRepo:  transferRepo,
+		now:           time.Now,
+	}
+}
+
+// GetStatement returns the statement of a student
+func (uc *statementUseCase) GetStatement(ctx context.Context, studentID, userID, role string) (*entity.StudentStatement, error) {
+	if role != string(entity.RoleAdmin) && studentID != userID {
+		return nil, ErrAccessDenied
+	}
+
+	enrollments, err := uc.matriculaRepo.FindByStudentID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(enrollments) == 0 {
+		return nil, ErrStudentNotFound
+	}
+
+	statement := &entity.StudentStatement{
+		StudentID:    studentID,
+		StudentName:  enrollments[0].StudentName,
+		StudentEmail: enrollments[0].StudentEmail,
+		Enrollments:  make([]entity.StatementEnrollment, 0, len(enrollments)),
+		Entries:      []entity.StatementEntry{},
+		OpenCharges:  []entity.StatementCharge{},
+	}
+	today := startOfDay(uc.now())
+
+	for _, e := range enrollments {
+		statement.Enrollments = append(statement.Enrollments, entity.StatementEnrollment{
+			ID:            e.ID,
+			CourseID:      e.CourseID,
+			CourseName:    e.CourseName,
+			Status:        e.Status,
+			PaymentStatus: e.PaymentStatus,
+			CreatedAt:     e.CreatedAt,
+		})
+
+		payments, err := uc.charges(ctx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range payments {
+			addPayment(statement, &e, &payments[i], today)
+		}
+
+		transfers, err := uc.transferRepo.FindByEnrollmentID(ctx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range transfers {
+			addCredit(statement, &t, studentID)
+		}
+	}
+
+	sort.SliceStable(statement.Entries, func(i, j int) bool {
+		return statement.Entries[i].Date.Before(statement.Entries[j].Date)
+	})
+	sort.SliceStable(statement.OpenCharges, func(i, j int) bool {
+		return dueBefore(statement.OpenCharges[i].DueDate, statement.OpenCharges[j].DueDate)
+	})
+	statement.Totals.Balance = statement.Totals.Open - statement.Totals.Credits
+	return statement, nil
+}
+
+// charges returns the payments of an enrollment as the student is charged them: the
+// boletos of a carnê instead of its parent payment
+func (uc *statementUseCase) charges(ctx context.Context, enrollmentID string) ([]entity.Payment, error) {
+	payments, err := uc.paymentRepo.FindByEnrollmentID(ctx, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	charges := make([]entity.Payment, 0, len(payments))
+	for _, p := range payments {
+		if p.InstallmentCount > 1 {
+			installments, err := uc.paymentRepo.FindInstallments(ctx, p.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(installments) > 0 {
+				charges = append(charges, installments...)
+				continue
+			}
+		}
+		charges = append(charges, p)
+	}
+	return charges, nil
+}
+
+// addPayment adds the movements of a payment to the statement
+func addPayment(s *entity.StudentStatement, e *entity.Matricula, p *entity.Payment, today time.Time) {
+	if p.Status == entity.FinPaymentStatusCancelled || p.Status == entity.FinPaymentStatusFailed {
+		return
+	}
+	paymentID := p.ID
+	status := p.Status
+	description := e.CourseName
+	if p.InstallmentNumber != nil && p.InstallmentCount > 1 {
+		description = fmt.Sprintf("%s (parcela %d/%d)", e.CourseName, *p.InstallmentNumber, p.InstallmentCount)
+	}
+
+	s.Entries = append(s.Entries, entity.StatementEntry{
+		Date:         p.CreatedAt,
+		Type:         entity.StatementEntryCharge,
+		EnrollmentID: e.ID,
+		PaymentID:    &paymentID,
+		Description:  description,
+		Amount:       p.NetAmount,
+		Status:       &status,
+	})
+	s.Totals.Charged += p.NetAmount
+
+	if paid(p.Status) {
+		date := p.CreatedAt
+		if p.PaidAt != nil {
+			date = *p.PaidAt
+		}
+		s.Entries = append(s.Entries, entity.StatementEntry{
+			Date:         date,
+			Type:         entity.StatementEntryPayment,
+			EnrollmentID: e.ID,
+			PaymentID:    &paymentID,
+			Description:  "Pagamento " + description,
+			Amount:       p.NetAmount,
+		})
+		s.Totals.Paid += p.NetAmount
+	}
+
+	if p.RefundedAmount > 0 {
+		date := p.CreatedAt
+		if p.RefundedAt != nil {
+			date = *p.RefundedAt
+		} else if p.UpdatedAt != nil {
+			date = *p.UpdatedAt
+		}
+		s.Entries = append(s.Entries, entity.StatementEntry{
+			Date:         date,
+			Type:         entity.StatementEntryRefund,
+			EnrollmentID: e.ID,
+			PaymentID:    &paymentID,
+			Description:  "Estorno " + description,
+			Amount:       p.RefundedAmount,
+		})
+		s.Totals.Refunded += p.RefundedAmount
+	}
+
+	if p.Status == entity.FinPaymentStatusChargeback {
+		date := p.CreatedAt
+		if p.UpdatedAt != nil {
+			date = *p.UpdatedAt
+		}
+		s.Entries = append(s.Entries, entity.StatementEntry{
+			Date:         date,
+			Type:         entity.StatementEntryChargeback,
+			EnrollmentID: e.ID,
+			PaymentID:    &paymentID,
+			Description:  "Chargeback " + description,
+			Amount:       p.NetAmount,
+		})
+	}
+
+	if open(p.Status) {
+		overdue := p.Status == entity.FinPaymentStatusOverdue || (p.DueDate != nil && p.DueDate.Before(today))
+		s.OpenCharges = append(s.OpenCharges, entity.StatementCharge{
+			PaymentID:         p.ID,
+			EnrollmentID:      e.ID,
+			CourseName:        e.CourseName,
+			PaymentMethod:     p.PaymentMethod,
+			InstallmentNumber: p.InstallmentNumber,
+			InstallmentCount:  p.InstallmentCount,
+			Amount:            p.NetAmount,
+			Status:            p.Status,
+			DueDate:           p.DueDate,
+			Overdue:           overdue,
+			InvoiceURL:        p.GatewayInvoiceURL,
+		})
+		s.Totals.Open += p.NetAmount
+		if overdue {
+			s.Totals.Overdue += p.NetAmount
+		}
+	}
+}
+
+// addCredit adds the price difference of a transfer of the student to a cheaper course
+func addCredit(s *entity.StudentStatement, t *entity.EnrollmentTransfer, studentID string) {
+	if t.ToStudentID != studentID || t.PriceDifference >= 0 {
+		return
+	}
+	credit := money.FromFloat(-t.PriceDifference)
+	s.Entries = append(s.Entries, entity.StatementEntry{
+		Date:         t.CreatedAt,
+		Type:         entity.StatementEntryCredit,
+		EnrollmentID: t.EnrollmentID,
+		Description:  "Crédito da transferência de " + t.FromCourseName + " para " + t.ToCourseName,
+		Amount:       credit,
+	})
+	s.Totals.Credits += credit
+}
+
+// paid reports whether the student paid a payment in the given status, even when the money
+// was later returned
+func paid(status string) bool {
+	switch status {
+	case entity.FinPaymentStatusConfirmed, entity.FinPaymentStatusReceived,
+		entity.FinPaymentStatusRefundRequested, entity.FinPaymentStatusRefunded,
+		entity.FinPaymentStatusPartiallyRefunded, entity.FinPaymentStatusChargeback:
+		return true
+	}
+	return false
+}
+
+// open reports whether a payment in the given status is still awaiting payment
+func open(status string) bool {
+	switch status {
+	case entity.FinPaymentStatusPending, entity.FinPaymentStatusAwaitingPayment, entity.FinPaymentStatusOverdue:
+		return true
+	}
+	return false
+}
+
+// dueBefore orders open charges by due date, the ones without a due date last
+func dueBefore(a, b *time.Time) bool {
+	if a == nil {
+		return false
+	}
+	return b == nil || a.Before(*b)
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,138 @@
+package statement
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/money"
+	"github.com/jmoiron/sqlx"
+)
+
+type stubTransferRepo struct {
+	transfers []entity.EnrollmentTransfer
+}
+
+func (r *stubTransferRepo) FindByEnrollmentID(ctx context.Context, enrollmentID string) ([]entity.EnrollmentTransfer, error) {
+	var result []entity.EnrollmentTransfer
+	for _, t := range r.transfers {
+		if t.EnrollmentID == enrollmentID {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (r *stubTransferRepo) CreateWithTx(ctx context.Context, tx *sqlx.Tx, transfer *entity.EnrollmentTransfer) error {
+	return nil
+}
+
+func date(day int) time.Time {
+	return time.Date(2024, 5, day, 10, 0, 0, 0, time.UTC)
+}
+
+func newTestStatementUseCase(transfers ...entity.EnrollmentTransfer) (*statementUseCase, *testutil.MockMatriculaRepository, *testutil.MockPaymentRepository) {
+	enrollments := testutil.NewMockMatriculaRepository()
+	enrollments.Enrollments["enr-1"] = &entity.Matricula{ID: "enr-1", StudentID: "student-1", StudentName: "Ana", CourseID: "c1", CourseName: "Síndico Profissional", CreatedAt: date(1)}
+	enrollments.Enrollments["enr-2"] = &entity.Matricula{ID: "enr-2", StudentID: "student-1", StudentName: "Ana", CourseID: "c2", CourseName: "Zeladoria", CreatedAt: date(2)}
+	payments := testutil.NewMockPaymentRepository()
+	uc := NewUseCase(enrollments, payments, &stubTransferRepo{transfers: transfers}).(*statementUseCase)
+	uc.now = func() time.Time { return date(15) }
+	return uc, enrollments, payments
+}
+
+func TestGetStatement(t *testing.T) {
+	uc, _, payments := newTestStatementUseCase(entity.EnrollmentTransfer{
+		ID: "t1", EnrollmentID: "enr-2", ToStudentID: "student-1", FromCourseName: "Gestão", ToCourseName: "Zeladoria",
+		PriceDifference: -50, CreatedAt: date(3),
+	})
+	paidAt := date(4)
+	refundedAt := date(6)
+	payments.Payments["p1"] = &entity.Payment{ID: "p1", EnrollmentID: "enr-1", NetAmount: 30000, RefundedAmount: 10000,
+		Status: entity.FinPaymentStatusPartiallyRefunded, PaidAt: &paidAt, RefundedAt: &refundedAt, CreatedAt: date(1)}
+	payments.Payments["p2"] = &entity.Payment{ID: "p2", EnrollmentID: "enr-1", NetAmount: 99999, Status: entity.FinPaymentStatusCancelled, CreatedAt: date(1)}
+
+	// A carnê of two boletos: the first overdue, the second still to come
+	payments.Payments["carne"] = &entity.Payment{ID: "carne", EnrollmentID: "enr-2", NetAmount: 20000, InstallmentCount: 2,
+		PaymentMethod: entity.MethodBoleto, Status: entity.FinPaymentStatusPending, CreatedAt: date(2)}
+	parent := "carne"
+	one, two := 1, 2
+	due1, due2 := date(10), time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	payments.Payments["b1"] = &entity.Payment{ID: "b1", EnrollmentID: "enr-2", NetAmount: 10000, InstallmentCount: 2, InstallmentOf: &parent,
+		InstallmentNumber: &one, PaymentMethod: entity.MethodBoleto, Status: entity.FinPaymentStatusPending, DueDate: &due1, CreatedAt: date(2)}
+	payments.Payments["b2"] = &entity.Payment{ID: "b2", EnrollmentID: "enr-2", NetAmount: 10000, InstallmentCount: 2, InstallmentOf: &parent,
+		InstallmentNumber: &two, PaymentMethod: entity.MethodBoleto, Status: entity.FinPaymentStatusPending, DueDate: &due2, CreatedAt: date(2)}
+
+	s, err := uc.GetStatement(context.Background(), "student-1", "admin-1", string(entity.RoleAdmin))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.Enrollments) != 2 || s.StudentName != "Ana" {
+		t.Errorf("unexpected student: %+v", s)
+	}
+
+	want := entity.StatementTotals{Charged: 50000, Paid: 30000, Refunded: 10000, Open: 20000, Overdue: 10000, Credits: money.FromFloat(50), Balance: 15000}
+	if s.Totals != want {
+		t.Errorf("expected totals %+v, got %+v", want, s.Totals)
+	}
+
+	// 3 charges, 1 payment, 1 refund and 1 credit, oldest first
+	if len(s.Entries) != 6 {
+		t.Fatalf("expected 6 entries, got %d: %+v", len(s.Entries), s.Entries)
+	}
+	for i := 1; i < len(s.Entries); i++ {
+		if s.Entries[i].Date.Before(s.Entries[i-1].Date) {
+			t.Errorf("entries out of order at %d", i)
+		}
+	}
+	if last := s.Entries[5]; last.Type != entity.StatementEntryRefund || last.Amount != 10000 {
+		t.Errorf("expected the refund last, got %+v", last)
+	}
+
+	if len(s.OpenCharges) != 2 {
+		t.Fatalf("expected the 2 boletos open, got %+v", s.OpenCharges)
+	}
+	if c := s.OpenCharges[0]; c.PaymentID != "b1" || !c.Overdue {
+		t.Errorf("expected the first boleto overdue, got %+v", c)
+	}
+	if c := s.OpenCharges[1]; c.PaymentID != "b2" || c.Overdue {
+		t.Errorf("expected the second boleto not overdue, got %+v", c)
+	}
+}
+
+func TestGetStatement_Chargeback(t *testing.T) {
+	uc, _, payments := newTestStatementUseCase()
+	payments.Payments["p1"] = &entity.Payment{ID: "p1", EnrollmentID: "enr-1", NetAmount: 30000, Status: entity.FinPaymentStatusChargeback, CreatedAt: date(1)}
+
+	s, err := uc.GetStatement(context.Background(), "student-1", "student-1", string(entity.RoleStudent))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var chargebacks int
+	for _, e := range s.Entries {
+		if e.Type == entity.StatementEntryChargeback {
+			chargebacks++
+		}
+	}
+	if chargebacks != 1 || s.Totals.Paid != 30000 || s.Totals.Open != 0 {
+		t.Errorf("expected a paid payment with a chargeback, got %+v", s)
+	}
+}
+
+func TestGetStatement_Access(t *testing.T) {
+	uc, _, _ := newTestStatementUseCase()
+	ctx := context.Background()
+
+	if _, err := uc.GetStatement(ctx, "student-1", "student-2", string(entity.RoleStudent)); !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("expected access denied for another student, got %v", err)
+	}
+	if _, err := uc.GetStatement(ctx, "student-1", "student-1", string(entity.RoleStudent)); err != nil {
+		t.Errorf("expected the student to see their own statement, got %v", err)
+	}
+	if _, err := uc.GetStatement(ctx, "missing", "admin-1", string(entity.RoleAdmin)); !errors.Is(err, ErrStudentNotFound) {
+		t.Errorf("expected student not found, got %v", err)
+	}
+}
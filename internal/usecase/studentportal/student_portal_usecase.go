@@ -0,0 +1,211 @@
+package studentportal
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// ErrPaymentNotFound is returned for payments that do not exist or are paid by someone else,
+// so a student cannot probe for the payments of others
+var ErrPaymentNotFound = errors.New("payment not found")
+
+// ErrNoOpenCharge is returned when the payment is no longer awaiting payment
+var ErrNoOpenCharge = apperror.New(apperror.CodeConflict, "payment has no open charge")
+
+// ErrEnrollmentNotFound is returned for enrollments that do not exist or belong to someone else
+var ErrEnrollmentNotFound = errors.New("enrollment not found")
+
+// ErrCannotRate is returned when the enrollment is pending, cancelled or expired
+var ErrCannotRate = apperror.New(apperror.CodeConflict, "only active or completed enrollments can rate their course")
+
+// UseCase defines the self-service use case of the logged-in student. Every method is scoped
+// to the user ID of the token.
+type UseCase interface {
+	// ListEnrollments returns the enrollments of the student with their progress, newest first
+	ListEnrollments(ctx context.Context, userID string) ([]entity.Matricula, error)
+
+	// ListPayments returns the payments the student is the payer of, newest first
+	ListPayments(ctx context.Context, userID string, page, perPage int) ([]entity.Payment, int, error)
+
+	// GetPaymentCharge returns the boleto or PIX of an open payment of the student
+	GetPaymentCharge(ctx context.Context, userID, paymentID string) (*PaymentCharge, error)
+
+	// ListCertificates returns the certificates issued to the student
+	ListCertificates(ctx context.Context, userID string) ([]entity.Certificado, error)
+
+	// RateCourse rates the course of an enrollment of the student, replacing an earlier rating
+	RateCourse(ctx context.Context, userID, enrollmentID string, req *entity.RateCourseRequest) (*entity.CourseRating, error)
+}
+
+// PaymentCharge is what the student needs to pay an open payment: the boleto link and bar
+// code, or the PIX QR code
+type PaymentCharge struct {
+	PaymentID     string  `json:"payment_id"`
+	EnrollmentID  string  `json:"enrollment_id"`
+	Status        string  `json:"status"`
+	PaymentMethod string  `json:"payment_method"`
+	Amount        float64 `json:"amount"`
+	DueDate       string  `json:"due_date,omitempty"`
+	InvoiceURL    string  `json:"invoice_url,omitempty"`
+	BoletoURL     string  `json:"boleto_url,omitempty"`
+	BoletoBarCode string  `json:"boleto_barcode,omitempty"`
+	PixQRCode     string  `json:"pix_qr_code,omitempty"`
+	PixCopyPaste  string  `json:"pix_copy_paste,omitempty"`
+	PixExpiration string  `json:"pix_expiration,omitempty"`
+}
+
+type studentPortalUseCase struct {
+	matriculaRepo   repository.MatriculaRepository
+	paymentRepo     repository.PaymentRepository
+	certificadoRepo repository.CertificadoRepository
+	ratingRepo      repository.CourseRatingRepository
+	gw              gateway.PaymentGateway
+}
+
+// NewUseCase creates a new student portal use case
+func NewUseCase(
+	matriculaRepo repository.MatriculaRepository,
+	paymentRepo repository.PaymentRepository,
+	certificadoRepo repository.CertificadoRepository,
+	ratingRepo repository.CourseRatingRepository,
+	gw gateway.PaymentGateway,
+) UseCase {
+	return &studentPortalUseCase{
+		matriculaRepo:   matriculaRepo,
+		paymentRepo:     paymentRepo,
+		certificadoRepo: certificadoRepo,
+		ratingRepo:      ratingRepo,
+		gw:              gw,
+	}
+}
+
+// ListEnrollments returns the enrollments of the student
+func (uc *studentPortalUseCase) ListEnrollments(ctx context.Context, userID string) ([]entity.Matricula, error) {
+	enrollments, err := uc.matriculaRepo.FindByStudentID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollments == nil {
+		enrollments = []entity.Matricula{}
+	}
+	return enrollments, nil
+}
+
+// ListPayments returns the payments of the student, one page at a time
+func (uc *studentPortalUseCase) ListPayments(ctx context.Context, userID string, page, perPage int) ([]entity.Payment, int, error) {
+	payments, total, err := uc.paymentRepo.FindAll(ctx, repository.PaymentFilters{
+		PayerUserID: userID,
+		Page:        page,
+		PerPage:     perPage,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if payments == nil {
+		payments = []entity.Payment{}
+	}
+	return payments, total, nil
+}
+
+// GetPaymentCharge fetches the charge of an open payment from its gateway, so the student
+// always gets the current boleto or PIX code
+func (uc *studentPortalUseCase) GetPaymentCharge(ctx context.Context, userID, paymentID string) (*PaymentCharge, error) {
+	payment, err := uc.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil || payment.PayerUserID == nil || *payment.PayerUserID != userID {
+		return nil, ErrPaymentNotFound
+	}
+	if !isOpen(payment.Status) || payment.GatewayPaymentID == nil {
+		return nil, ErrNoOpenCharge
+	}
+
+	charge, err := gateway.ForPayment(uc.gw, payment.Gateway).GetPayment(ctx, *payment.GatewayPaymentID)
+	if err != nil {
+		return nil, gateway.Failure(err)
+	}
+
+	result := &PaymentCharge{
+		PaymentID:     payment.ID,
+		EnrollmentID:  payment.EnrollmentID,
+		Status:        payment.Status,
+		PaymentMethod: payment.PaymentMethod,
+		Amount:        payment.NetAmount.Float(),
+		DueDate:       charge.DueDate,
+		InvoiceURL:    charge.InvoiceURL,
+		BoletoURL:     charge.BoletoURL,
+		BoletoBarCode: charge.BoletoBarCode,
+		PixQRCode:     charge.PixQRCodeBase64,
+		PixCopyPaste:  charge.PixCopyPaste,
+		PixExpiration: charge.PixExpiration,
+	}
+	if result.InvoiceURL == "" && payment.GatewayInvoiceURL != nil {
+		result.InvoiceURL = *payment.GatewayInvoiceURL
+	}
+	if result.DueDate == "" && payment.DueDate != nil {
+		result.DueDate = payment.DueDate.Format("2006-01-02")
+	}
+	return result, nil
+}
+
+// ListCertificates returns the certificates of the student
+func (uc *studentPortalUseCase) ListCertificates(ctx context.Context, userID string) ([]entity.Certificado, error) {
+	certs, err := uc.certificadoRepo.FindByStudentID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if certs == nil {
+		certs = []entity.Certificado{}
+	}
+	return certs, nil
+}
+
+// RateCourse saves the rating of the student for the course of the enrollment. The rating
+// keeps its ID when the student rates the course again, from this or another enrollment.
+func (uc *studentPortalUseCase) RateCourse(ctx context.Context, userID, enrollmentID string, req *entity.RateCourseRequest) (*entity.CourseRating, error) {
+	enrollment, err := uc.matriculaRepo.FindByID(ctx, enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil || enrollment.StudentID != userID {
+		return nil, ErrEnrollmentNotFound
+	}
+	if !entity.CanRateEnrollment(enrollment.Status) {
+		return nil, ErrCannotRate
+	}
+
+	rating, err := uc.ratingRepo.FindByStudent(ctx, enrollment.CourseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if rating == nil {
+		rating = &entity.CourseRating{ID: uuid.New().String(), CourseID: enrollment.CourseID, StudentID: userID, CreatedAt: now}
+	} else {
+		rating.UpdatedAt = &now
+	}
+	rating.EnrollmentID = enrollment.ID
+	rating.Rating = req.Rating
+	rating.Comment = req.Comment
+	if err := uc.ratingRepo.Save(ctx, rating); err != nil {
+		return nil, err
+	}
+	return rating, nil
+}
+
+// isOpen reports whether a payment is still awaiting payment
+func isOpen(status string) bool {
+	switch status {
+	case entity.FinPaymentStatusPending, entity.FinPaymentStatusAwaitingPayment, entity.FinPaymentStatusOverdue:
+		return true
+	}
+	return false
+}
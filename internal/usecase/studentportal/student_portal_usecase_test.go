@@ -0,0 +1,103 @@
+package studentportal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/gateway"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/pkg/apperror"
+)
+
+func newTestPayment(id, payer, status string) *entity.Payment {
+	gwID := "gw-" + id
+	return &entity.Payment{
+		ID:               id,
+		EnrollmentID:     "enr-1",
+		PayerUserID:      &payer,
+		NetAmount:        19990,
+		PaymentMethod:    entity.MethodPIX,
+		Gateway:          "mock",
+		GatewayPaymentID: &gwID,
+		Status:           status,
+	}
+}
+
+func TestGetPaymentCharge(t *testing.T) {
+	ctx := context.Background()
+	payments := testutil.NewMockPaymentRepository()
+	payments.Payments["p1"] = newTestPayment("p1", "student-1", entity.FinPaymentStatusPending)
+	payments.Payments["p2"] = newTestPayment("p2", "student-1", entity.FinPaymentStatusConfirmed)
+	gw := &testutil.MockGateway{
+		GetPaymentFunc: func(ctx context.Context, id string) (*gateway.PaymentResponse, error) {
+			return &gateway.PaymentResponse{GatewayPaymentID: id, DueDate: "2024-05-10", PixCopyPaste: "000201pix"}, nil
+		},
+	}
+	uc := NewUseCase(testutil.NewMockMatriculaRepository(), payments, nil, nil, gw)
+
+	charge, err := uc.GetPaymentCharge(ctx, "student-1", "p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if charge.PixCopyPaste != "000201pix" || charge.DueDate != "2024-05-10" || charge.Amount != 199.9 {
+		t.Errorf("unexpected charge: %+v", charge)
+	}
+
+	if _, err := uc.GetPaymentCharge(ctx, "student-2", "p1"); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("expected the payment of another student to be hidden, got %v", err)
+	}
+	if _, err := uc.GetPaymentCharge(ctx, "student-1", "missing"); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("expected payment not found, got %v", err)
+	}
+	_, err = uc.GetPaymentCharge(ctx, "student-1", "p2")
+	if appErr, ok := apperror.As(err); !ok || appErr.Code != apperror.CodeConflict {
+		t.Errorf("expected a conflict for a confirmed payment, got %v", err)
+	}
+}
+
+func TestListPayments_ScopedToPayer(t *testing.T) {
+	ctx := context.Background()
+	payments := testutil.NewMockPaymentRepository()
+	payments.Payments["p1"] = newTestPayment("p1", "student-1", entity.FinPaymentStatusPending)
+	payments.Payments["p2"] = newTestPayment("p2", "student-2", entity.FinPaymentStatusPending)
+	uc := NewUseCase(testutil.NewMockMatriculaRepository(), payments, nil, nil, &testutil.MockGateway{})
+
+	list, total, err := uc.ListPayments(ctx, "student-1", 1, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 1 || len(list) != 1 || list[0].ID != "p1" {
+		t.Errorf("expected only the payments of student-1, got %+v", list)
+	}
+}
+
+func TestRateCourse(t *testing.T) {
+	ctx := context.Background()
+	enrollments := testutil.NewMockMatriculaRepository()
+	enrollments.Enrollments["enr-1"] = &entity.Matricula{ID: "enr-1", StudentID: "student-1", CourseID: "c1", Status: entity.EnrollmentStatusActive}
+	enrollments.Enrollments["enr-2"] = &entity.Matricula{ID: "enr-2", StudentID: "student-1", CourseID: "c2", Status: entity.EnrollmentStatusPending}
+	ratings := testutil.NewMockCourseRatingRepository()
+	uc := NewUseCase(enrollments, testutil.NewMockPaymentRepository(), nil, ratings, &testutil.MockGateway{})
+
+	first, err := uc.RateCourse(ctx, "student-1", "enr-1", &entity.RateCourseRequest{Rating: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	comment := "Ótimo curso"
+	second, err := uc.RateCourse(ctx, "student-1", "enr-1", &entity.RateCourseRequest{Rating: 5, Comment: &comment})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.ID != first.ID || second.Rating != 5 || second.UpdatedAt == nil || len(ratings.Ratings) != 1 {
+		t.Errorf("expected rating again to replace the rating, got %+v", second)
+	}
+
+	if _, err := uc.RateCourse(ctx, "student-2", "enr-1", &entity.RateCourseRequest{Rating: 1}); !errors.Is(err, ErrEnrollmentNotFound) {
+		t.Errorf("expected the enrollment of another student to be hidden, got %v", err)
+	}
+	if _, err := uc.RateCourse(ctx, "student-1", "enr-2", &entity.RateCourseRequest{Rating: 1}); !errors.Is(err, ErrCannotRate) {
+		t.Errorf("expected a pending enrollment not to rate, got %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package supplier
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// LinkContract links a supplier to a contract for a service category
+func (uc *supplierUseCase) LinkContract(ctx context.Context, supplierID string, req *entity.CreateSupplierContractRequest) (*entity.SupplierContract, error) {
+	category := strings.TrimSpace(req.ServiceCategory)
+	if category == "" {
+		return nil, errors.New("service_category is required")
+	}
+
+	supplier, err := uc.repo.FindByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+
+	contrato, err := uc.contratoRepo.FindByID(ctx, req.ContratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+
+	existing, err := uc.contractRepo.FindLink(ctx, supplierID, req.ContratoID, category)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("supplier is already linked to this contract for this service category")
+	}
+
+	link := &entity.SupplierContract{
+		ID:              uuid.New().String(),
+		SupplierID:      supplierID,
+		ContratoID:      req.ContratoID,
+		ServiceCategory: category,
+		MonthlyBudget:   req.MonthlyBudget,
+		IsActive:        true,
+		Notes:           req.Notes,
+		CreatedAt:       time.Now(),
+		SupplierName:    supplier.Name,
+		ContratoNome:    contrato.Nome,
+	}
+
+	if err := uc.contractRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// ListSupplierContracts returns the contracts a supplier is linked to
+func (uc *supplierUseCase) ListSupplierContracts(ctx context.Context, supplierID string) ([]entity.SupplierContract, error) {
+	supplier, err := uc.repo.FindByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+
+	return uc.contractRepo.FindBySupplierID(ctx, supplierID)
+}
+
+// ListContractSuppliers returns the suppliers linked to a contract
+func (uc *supplierUseCase) ListContractSuppliers(ctx context.Context, contratoID string) ([]entity.SupplierContract, error) {
+	contrato, err := uc.contratoRepo.FindByID(ctx, contratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+
+	return uc.contractRepo.FindByContratoID(ctx, contratoID)
+}
+
+// UpdateContractLink updates the category, budget or status of a supplier-contract link
+func (uc *supplierUseCase) UpdateContractLink(ctx context.Context, supplierID, linkID string, req *entity.UpdateSupplierContractRequest) (*entity.SupplierContract, error) {
+	link, err := uc.findLink(ctx, supplierID, linkID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ServiceCategory != nil {
+		category := strings.TrimSpace(*req.ServiceCategory)
+		if category == "" {
+			return nil, errors.New("service_category is required")
+		}
+		if category != link.ServiceCategory {
+			existing, err := uc.contractRepo.FindLink(ctx, supplierID, link.ContratoID, category)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				return nil, errors.New("supplier is already linked to this contract for this service category")
+			}
+		}
+		link.ServiceCategory = category
+	}
+	if req.MonthlyBudget != nil {
+		link.MonthlyBudget = req.MonthlyBudget
+	}
+	if req.IsActive != nil {
+		link.IsActive = *req.IsActive
+	}
+	if req.Notes != nil {
+		link.Notes = req.Notes
+	}
+
+	now := time.Now()
+	link.UpdatedAt = &now
+
+	if err := uc.contractRepo.Update(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+// UnlinkContract removes a supplier-contract link together with its spend entries
+func (uc *supplierUseCase) UnlinkContract(ctx context.Context, supplierID, linkID string) error {
+	if _, err := uc.findLink(ctx, supplierID, linkID); err != nil {
+		return err
+	}
+	return uc.contractRepo.Delete(ctx, linkID)
+}
+
+// RecordSpend records a monthly spend entry on a supplier-contract link
+func (uc *supplierUseCase) RecordSpend(ctx context.Context, supplierID, linkID string, req *entity.CreateSupplierSpendRequest, createdBy string) (*entity.SupplierSpend, error) {
+	if !entity.IsValidEvaluationPeriod(req.Period) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+
+	link, err := uc.findLink(ctx, supplierID, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if !link.IsActive {
+		return nil, errors.New("supplier-contract link is inactive")
+	}
+
+	spend := &entity.SupplierSpend{
+		ID:                 uuid.New().String(),
+		SupplierContractID: link.ID,
+		SupplierID:         link.SupplierID,
+		ContratoID:         link.ContratoID,
+		Period:             req.Period,
+		Amount:             roundCents(req.Amount),
+		Description:        req.Description,
+		InvoiceNumber:      req.InvoiceNumber,
+		CreatedAt:          time.Now(),
+	}
+	if createdBy != "" {
+		spend.CreatedBy = &createdBy
+	}
+
+	if err := uc.contractRepo.CreateSpend(ctx, spend); err != nil {
+		return nil, err
+	}
+
+	return spend, nil
+}
+
+// ListSpend returns the spend entries of a supplier-contract link
+func (uc *supplierUseCase) ListSpend(ctx context.Context, supplierID, linkID string) ([]entity.SupplierSpend, error) {
+	if _, err := uc.findLink(ctx, supplierID, linkID); err != nil {
+		return nil, err
+	}
+	return uc.contractRepo.FindSpendByLinkID(ctx, linkID)
+}
+
+// GetSpendReport returns spend per contract or per supplier, month by month against budget
+func (uc *supplierUseCase) GetSpendReport(ctx context.Context, groupBy string, filters entity.SpendReportFilters) (*entity.SpendReport, error) {
+	if groupBy != entity.SpendGroupByContract && groupBy != entity.SpendGroupBySupplier {
+		return nil, errors.New("invalid group_by: use contract or supplier")
+	}
+	if filters.From != "" && !entity.IsValidEvaluationPeriod(filters.From) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+	if filters.To != "" && !entity.IsValidEvaluationPeriod(filters.To) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+
+	rows, err := uc.contractRepo.SumSpend(ctx, groupBy, filters)
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := uc.contractRepo.SumMonthlyBudget(ctx, groupBy, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildSpendReport(groupBy, filters, rows, budgets), nil
+}
+
+// findLink loads a link and checks it belongs to the supplier
+func (uc *supplierUseCase) findLink(ctx context.Context, supplierID, linkID string) (*entity.SupplierContract, error) {
+	link, err := uc.contractRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.SupplierID != supplierID {
+		return nil, errors.New("supplier-contract link not found")
+	}
+	return link, nil
+}
+
+// buildSpendReport groups the per-period aggregates (ordered by group and period)
+// into report items and compares each month with the group's monthly budget.
+func buildSpendReport(groupBy string, filters entity.SpendReportFilters, rows []entity.SpendAggregate, budgets map[string]float64) *entity.SpendReport {
+	report := &entity.SpendReport{
+		GroupBy: groupBy,
+		From:    filters.From,
+		To:      filters.To,
+		Items:   []entity.SpendReportItem{},
+	}
+
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.GroupID]
+		if !ok {
+			item := entity.SpendReportItem{
+				ID:     row.GroupID,
+				Name:   row.GroupName,
+				Months: []entity.SpendReportMonth{},
+			}
+			if budget, ok := budgets[row.GroupID]; ok {
+				b := budget
+				item.MonthlyBudget = &b
+			}
+			report.Items = append(report.Items, item)
+			i = len(report.Items) - 1
+			index[row.GroupID] = i
+		}
+
+		item := &report.Items[i]
+		amount := roundCents(row.TotalAmount)
+		month := entity.SpendReportMonth{Period: row.Period, Amount: amount}
+		if item.MonthlyBudget != nil {
+			budget := *item.MonthlyBudget
+			variance := roundCents(budget - amount)
+			month.Budget = &budget
+			month.Variance = &variance
+		}
+		item.Months = append(item.Months, month)
+		item.TotalAmount = roundCents(item.TotalAmount + amount)
+		item.EntryCount += row.EntryCount
+		report.TotalAmount = roundCents(report.TotalAmount + amount)
+	}
+
+	return report
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,51 @@
+package supplier
+
+import (
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestBuildSpendReport_GroupsMonthsWithBudget(t *testing.T) {
+	rows := []entity.SpendAggregate{
+		{GroupID: "c1", GroupName: "Residencial A", Period: "2024-01", TotalAmount: 1200, EntryCount: 2},
+		{GroupID: "c1", GroupName: "Residencial A", Period: "2024-02", TotalAmount: 800.5, EntryCount: 1},
+		{GroupID: "c2", GroupName: "Residencial B", Period: "2024-01", TotalAmount: 300, EntryCount: 1},
+	}
+	budgets := map[string]float64{"c1": 1000}
+
+	report := buildSpendReport(entity.SpendGroupByContract, entity.SpendReportFilters{From: "2024-01"}, rows, budgets)
+
+	if len(report.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(report.Items))
+	}
+	if report.TotalAmount != 2300.5 {
+		t.Errorf("expected total 2300.50, got %.2f", report.TotalAmount)
+	}
+
+	a := report.Items[0]
+	if a.TotalAmount != 2000.5 || a.EntryCount != 3 || len(a.Months) != 2 {
+		t.Errorf("unexpected first item: %+v", a)
+	}
+	if a.Months[0].Variance == nil || *a.Months[0].Variance != -200 {
+		t.Errorf("expected January variance -200, got %v", a.Months[0].Variance)
+	}
+	if a.Months[1].Variance == nil || *a.Months[1].Variance != 199.5 {
+		t.Errorf("expected February variance 199.50, got %v", a.Months[1].Variance)
+	}
+
+	b := report.Items[1]
+	if b.MonthlyBudget != nil || b.Months[0].Budget != nil {
+		t.Error("expected no budget for contract without budgeted links")
+	}
+}
+
+func TestBuildSpendReport_Empty(t *testing.T) {
+	report := buildSpendReport(entity.SpendGroupBySupplier, entity.SpendReportFilters{}, nil, nil)
+	if report.Items == nil || len(report.Items) != 0 {
+		t.Error("expected empty, non-nil items")
+	}
+	if report.GroupBy != entity.SpendGroupBySupplier {
+		t.Errorf("expected group_by supplier, got %s", report.GroupBy)
+	}
+}
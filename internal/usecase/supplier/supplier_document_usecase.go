@@ -0,0 +1,158 @@
+package supplier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// AddDocument registers a compliance document of a supplier
+func (uc *supplierUseCase) AddDocument(ctx context.Context, supplierID string, req *entity.CreateSupplierDocumentRequest, createdBy string) (*entity.SupplierDocument, error) {
+	if !entity.IsValidSupplierDocumentType(req.DocumentType) {
+		return nil, errors.New("invalid document_type: use license, insurance, tax_clearance, certification or other")
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, errors.New("title is required")
+	}
+	issuedAt, err := parseDocumentDate("issued_at", req.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := parseDocumentDate("expires_at", req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDocumentDates(issuedAt, expiresAt); err != nil {
+		return nil, err
+	}
+
+	supplier, err := uc.repo.FindByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+
+	doc := &entity.SupplierDocument{
+		ID:           uuid.New().String(),
+		SupplierID:   supplierID,
+		DocumentType: req.DocumentType,
+		Title:        title,
+		Number:       req.Number,
+		IssuedAt:     issuedAt,
+		ExpiresAt:    expiresAt,
+		Notes:        req.Notes,
+		IsActive:     true,
+		CreatedAt:    time.Now(),
+	}
+	if createdBy != "" {
+		doc.CreatedBy = &createdBy
+	}
+
+	if err := uc.documentRepo.Create(ctx, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// ListDocuments returns the documents of a supplier
+func (uc *supplierUseCase) ListDocuments(ctx context.Context, supplierID string, includeInactive bool) ([]entity.SupplierDocument, error) {
+	supplier, err := uc.repo.FindByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+	return uc.documentRepo.FindBySupplierID(ctx, supplierID, includeInactive)
+}
+
+// UpdateDocument updates a supplier document, typically with the dates of its renewal
+func (uc *supplierUseCase) UpdateDocument(ctx context.Context, supplierID, documentID string, req *entity.UpdateSupplierDocumentRequest) (*entity.SupplierDocument, error) {
+	doc, err := uc.findDocument(ctx, supplierID, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, errors.New("title is required")
+		}
+		doc.Title = title
+	}
+	if req.Number != nil {
+		doc.Number = req.Number
+	}
+	if req.IssuedAt != nil {
+		if doc.IssuedAt, err = parseDocumentDate("issued_at", req.IssuedAt); err != nil {
+			return nil, err
+		}
+	}
+	if req.ExpiresAt != nil {
+		if doc.ExpiresAt, err = parseDocumentDate("expires_at", req.ExpiresAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkDocumentDates(doc.IssuedAt, doc.ExpiresAt); err != nil {
+		return nil, err
+	}
+	if req.Notes != nil {
+		doc.Notes = req.Notes
+	}
+	if req.IsActive != nil {
+		doc.IsActive = *req.IsActive
+	}
+
+	now := time.Now()
+	doc.UpdatedAt = &now
+
+	if err := uc.documentRepo.Update(ctx, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// DeleteDocument removes a supplier document
+func (uc *supplierUseCase) DeleteDocument(ctx context.Context, supplierID, documentID string) error {
+	if _, err := uc.findDocument(ctx, supplierID, documentID); err != nil {
+		return err
+	}
+	return uc.documentRepo.Delete(ctx, documentID)
+}
+
+func (uc *supplierUseCase) findDocument(ctx context.Context, supplierID, documentID string) (*entity.SupplierDocument, error) {
+	doc, err := uc.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil || doc.SupplierID != supplierID {
+		return nil, errors.New("supplier document not found")
+	}
+	return doc, nil
+}
+
+func parseDocumentDate(field string, s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: use YYYY-MM-DD", field)
+	}
+	return &t, nil
+}
+
+func checkDocumentDates(issuedAt, expiresAt *time.Time) error {
+	if issuedAt != nil && expiresAt != nil && expiresAt.Before(*issuedAt) {
+		return errors.New("invalid expires_at: must not be before issued_at")
+	}
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/pkg/apperror"
+	"github.com/condotrack/api/pkg/phone"
 	"github.com/google/uuid"
 )
 
@@ -18,34 +20,113 @@ type UseCase interface {
 	CreateSupplier(ctx context.Context, req *entity.CreateSupplierRequest) (*entity.Supplier, error)
 	UpdateSupplier(ctx context.Context, id string, req *entity.UpdateSupplierRequest) (*entity.Supplier, error)
 	DeleteSupplier(ctx context.Context, id string) error
+	EvaluateSupplier(ctx context.Context, supplierID string, req *entity.CreateSupplierEvaluationRequest, evaluatedBy string) (*entity.SupplierEvaluation, error)
+	ListEvaluations(ctx context.Context, supplierID string) ([]entity.SupplierEvaluation, error)
+	GetRanking(ctx context.Context, filters entity.SupplierRankingFilters) ([]entity.SupplierRanking, error)
+	LinkContract(ctx context.Context, supplierID string, req *entity.CreateSupplierContractRequest) (*entity.SupplierContract, error)
+	ListSupplierContracts(ctx context.Context, supplierID string) ([]entity.SupplierContract, error)
+	ListContractSuppliers(ctx context.Context, contratoID string) ([]entity.SupplierContract, error)
+	UpdateContractLink(ctx context.Context, supplierID, linkID string, req *entity.UpdateSupplierContractRequest) (*entity.SupplierContract, error)
+	UnlinkContract(ctx context.Context, supplierID, linkID string) error
+	RecordSpend(ctx context.Context, supplierID, linkID string, req *entity.CreateSupplierSpendRequest, createdBy string) (*entity.SupplierSpend, error)
+	ListSpend(ctx context.Context, supplierID, linkID string) ([]entity.SupplierSpend, error)
+	GetSpendReport(ctx context.Context, groupBy string, filters entity.SpendReportFilters) (*entity.SpendReport, error)
+	AddDocument(ctx context.Context, supplierID string, req *entity.CreateSupplierDocumentRequest, createdBy string) (*entity.SupplierDocument, error)
+	ListDocuments(ctx context.Context, supplierID string, includeInactive bool) ([]entity.SupplierDocument, error)
+	UpdateDocument(ctx context.Context, supplierID, documentID string, req *entity.UpdateSupplierDocumentRequest) (*entity.SupplierDocument, error)
+	DeleteDocument(ctx context.Context, supplierID, documentID string) error
 }
 
 type supplierUseCase struct {
-	repo repository.SupplierRepository
+	repo           repository.SupplierRepository
+	evaluationRepo repository.SupplierEvaluationRepository
+	contractRepo   repository.SupplierContractRepository
+	contratoRepo   repository.ContratoRepository
+	documentRepo   repository.SupplierDocumentRepository
 }
 
 // NewUseCase creates a new supplier use case
-func NewUseCase(repo repository.SupplierRepository) UseCase {
-	return &supplierUseCase{repo: repo}
+func NewUseCase(
+	repo repository.SupplierRepository,
+	evaluationRepo repository.SupplierEvaluationRepository,
+	contractRepo repository.SupplierContractRepository,
+	contratoRepo repository.ContratoRepository,
+	documentRepo repository.SupplierDocumentRepository,
+) UseCase {
+	return &supplierUseCase{
+		repo:           repo,
+		evaluationRepo: evaluationRepo,
+		contractRepo:   contractRepo,
+		contratoRepo:   contratoRepo,
+		documentRepo:   documentRepo,
+	}
 }
 
 // ListSuppliers returns all suppliers with optional category and isActive filters
 func (uc *supplierUseCase) ListSuppliers(ctx context.Context, category *string, isActive *bool) ([]entity.Supplier, error) {
-	return uc.repo.FindAll(ctx, category, isActive)
+	suppliers, err := uc.repo.FindAll(ctx, category, isActive)
+	if err != nil {
+		return nil, err
+	}
+	return uc.attachScores(ctx, suppliers)
 }
 
 // ListActiveSuppliers returns all active suppliers
 func (uc *supplierUseCase) ListActiveSuppliers(ctx context.Context) ([]entity.Supplier, error) {
-	return uc.repo.FindActive(ctx)
+	suppliers, err := uc.repo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return uc.attachScores(ctx, suppliers)
 }
 
 // GetSupplierByID returns a specific supplier by ID
 func (uc *supplierUseCase) GetSupplierByID(ctx context.Context, id string) (*entity.Supplier, error) {
-	return uc.repo.FindByID(ctx, id)
+	supplier, err := uc.repo.FindByID(ctx, id)
+	if err != nil || supplier == nil {
+		return supplier, err
+	}
+
+	scores, err := uc.evaluationRepo.GetScores(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	supplier.Scores = scores
+
+	return supplier, nil
+}
+
+// attachScores fills the averaged evaluation scores of each supplier
+func (uc *supplierUseCase) attachScores(ctx context.Context, suppliers []entity.Supplier) ([]entity.Supplier, error) {
+	if len(suppliers) == 0 {
+		return suppliers, nil
+	}
+
+	ids := make([]string, len(suppliers))
+	for i := range suppliers {
+		ids[i] = suppliers[i].ID
+	}
+
+	scores, err := uc.evaluationRepo.GetScoresBySupplierIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range suppliers {
+		if s, ok := scores[suppliers[i].ID]; ok {
+			suppliers[i].Scores = &s
+		}
+	}
+
+	return suppliers, nil
 }
 
 // CreateSupplier creates a new supplier
 func (uc *supplierUseCase) CreateSupplier(ctx context.Context, req *entity.CreateSupplierRequest) (*entity.Supplier, error) {
+	phoneNumber, err := normalizePhone("phone", req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if CNPJ already exists (if provided)
 	if req.CNPJ != nil && *req.CNPJ != "" {
 		existing, err := uc.repo.FindByCNPJ(ctx, *req.CNPJ)
@@ -63,7 +144,7 @@ func (uc *supplierUseCase) CreateSupplier(ctx context.Context, req *entity.Creat
 		Name:      req.Name,
 		CNPJ:      req.CNPJ,
 		Email:     req.Email,
-		Phone:     req.Phone,
+		Phone:     phoneNumber,
 		Address:   req.Address,
 		Category:  req.Category,
 		IsActive:  true,
@@ -111,7 +192,9 @@ func (uc *supplierUseCase) UpdateSupplier(ctx context.Context, id string, req *e
 		supplier.Email = req.Email
 	}
 	if req.Phone != nil {
-		supplier.Phone = req.Phone
+		if supplier.Phone, err = normalizePhone("phone", req.Phone); err != nil {
+			return nil, err
+		}
 	}
 	if req.Address != nil {
 		supplier.Address = req.Address
@@ -151,3 +234,100 @@ func (uc *supplierUseCase) DeleteSupplier(ctx context.Context, id string) error
 	// Soft delete by calling repository Delete method
 	return uc.repo.Delete(ctx, id)
 }
+
+// EvaluateSupplier records a periodic evaluation of a supplier on a contract
+func (uc *supplierUseCase) EvaluateSupplier(ctx context.Context, supplierID string, req *entity.CreateSupplierEvaluationRequest, evaluatedBy string) (*entity.SupplierEvaluation, error) {
+	if !entity.IsValidEvaluationPeriod(req.Period) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+
+	supplier, err := uc.repo.FindByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+
+	contrato, err := uc.contratoRepo.FindByID(ctx, req.ContratoID)
+	if err != nil {
+		return nil, err
+	}
+	if contrato == nil {
+		return nil, errors.New("contract not found")
+	}
+
+	// One evaluation per supplier, contract and period
+	existing, err := uc.evaluationRepo.FindByPeriod(ctx, supplierID, req.ContratoID, req.Period)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("supplier already evaluated for this contract and period")
+	}
+
+	evaluation := &entity.SupplierEvaluation{
+		ID:               uuid.New().String(),
+		SupplierID:       supplierID,
+		ContratoID:       req.ContratoID,
+		Period:           req.Period,
+		QualityScore:     req.QualityScore,
+		PunctualityScore: req.PunctualityScore,
+		PriceScore:       req.PriceScore,
+		OverallScore:     entity.CalculateOverallScore(req.QualityScore, req.PunctualityScore, req.PriceScore),
+		Comments:         req.Comments,
+		CreatedAt:        time.Now(),
+	}
+	if evaluatedBy != "" {
+		evaluation.EvaluatedBy = &evaluatedBy
+	}
+
+	if err := uc.evaluationRepo.Create(ctx, evaluation); err != nil {
+		return nil, err
+	}
+
+	return evaluation, nil
+}
+
+// ListEvaluations returns the evaluation history of a supplier
+func (uc *supplierUseCase) ListEvaluations(ctx context.Context, supplierID string) ([]entity.SupplierEvaluation, error) {
+	supplier, err := uc.repo.FindByID(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.New("supplier not found")
+	}
+
+	return uc.evaluationRepo.FindBySupplierID(ctx, supplierID)
+}
+
+// GetRanking returns suppliers ranked by their averaged overall score
+func (uc *supplierUseCase) GetRanking(ctx context.Context, filters entity.SupplierRankingFilters) ([]entity.SupplierRanking, error) {
+	if filters.From != "" && !entity.IsValidEvaluationPeriod(filters.From) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+	if filters.To != "" && !entity.IsValidEvaluationPeriod(filters.To) {
+		return nil, errors.New("invalid period: use YYYY-MM")
+	}
+
+	ranking, err := uc.evaluationRepo.GetRanking(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ranking {
+		ranking[i].Position = i + 1
+	}
+
+	return ranking, nil
+}
+
+// normalizePhone returns an optional phone number in E.164, the format the WhatsApp and SMS
+// channels send to
+func normalizePhone(field string, p *string) (*string, error) {
+	n, err := phone.NormalizeOptional(p)
+	if err != nil {
+		return nil, apperror.New(apperror.CodeInvalidPhone, "invalid "+field+": include the area code, e.g. (11) 98765-4321")
+	}
+	return n, nil
+}
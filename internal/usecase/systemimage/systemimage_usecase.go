@@ -0,0 +1,192 @@
+package systemimage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+// cacheTTL bounds how long a slot change made on another instance takes to be seen by the
+// portal image endpoints
+const cacheTTL = time.Minute
+
+// UseCase defines the system image use case interface
+type UseCase interface {
+	// ListImages returns all system image slots
+	ListImages(ctx context.Context) ([]entity.SystemImage, error)
+
+	// GetImage returns a slot by ID
+	GetImage(ctx context.Context, id string) (*entity.SystemImage, error)
+
+	// CreateImage creates a slot
+	CreateImage(ctx context.Context, req *entity.CreateSystemImageRequest) (*entity.SystemImage, error)
+
+	// UpdateImage changes the file or description of a slot
+	UpdateImage(ctx context.Context, id string, req *entity.UpdateSystemImageRequest) (*entity.SystemImage, error)
+
+	// DeleteImage removes a slot; its file stays in the bucket
+	DeleteImage(ctx context.Context, id string) error
+
+	// Filenames returns the file of every slot by slot ID, from a cache
+	Filenames(ctx context.Context) (map[string]string, error)
+}
+
+type systemImageUseCase struct {
+	repo repository.SystemImageRepository
+
+	mu        sync.RWMutex
+	filenames map[string]string
+	loadedAt  time.Time
+}
+
+// NewUseCase creates a new system image use case
+func NewUseCase(repo repository.SystemImageRepository) UseCase {
+	return &systemImageUseCase{repo: repo}
+}
+
+// ListImages returns all system image slots ordered by ID
+func (uc *systemImageUseCase) ListImages(ctx context.Context) ([]entity.SystemImage, error) {
+	images, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if images == nil {
+		images = []entity.SystemImage{}
+	}
+	return images, nil
+}
+
+// GetImage returns a slot by ID
+func (uc *systemImageUseCase) GetImage(ctx context.Context, id string) (*entity.SystemImage, error) {
+	image, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if image == nil {
+		return nil, errors.New("system image not found")
+	}
+	return image, nil
+}
+
+// CreateImage creates a slot. The file is uploaded afterwards through the portal image
+// endpoint with the slot ID.
+func (uc *systemImageUseCase) CreateImage(ctx context.Context, req *entity.CreateSystemImageRequest) (*entity.SystemImage, error) {
+	id := strings.ToLower(strings.TrimSpace(req.ID))
+	if !entity.ValidSystemImageID(id) {
+		return nil, errors.New("invalid id: use lowercase letters, digits, '_' or '-'")
+	}
+	existing, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("invalid id: system image already exists")
+	}
+
+	image := &entity.SystemImage{
+		ID:          id,
+		Filename:    strings.TrimSpace(req.Filename),
+		Description: req.Description,
+		CreatedAt:   time.Now(),
+	}
+	if err := uc.checkFilename(ctx, image); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Create(ctx, image); err != nil {
+		return nil, err
+	}
+	uc.invalidate()
+
+	return uc.GetImage(ctx, id)
+}
+
+// UpdateImage applies the provided fields to a slot. Pointing a slot to another file does not
+// move the current file: upload the new image with the slot ID.
+func (uc *systemImageUseCase) UpdateImage(ctx context.Context, id string, req *entity.UpdateSystemImageRequest) (*entity.SystemImage, error) {
+	image, err := uc.GetImage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Filename != nil {
+		image.Filename = strings.TrimSpace(*req.Filename)
+		if err := uc.checkFilename(ctx, image); err != nil {
+			return nil, err
+		}
+	}
+	if req.Description != nil {
+		image.Description = req.Description
+	}
+
+	if err := uc.repo.Update(ctx, image); err != nil {
+		return nil, err
+	}
+	uc.invalidate()
+
+	return uc.GetImage(ctx, id)
+}
+
+// DeleteImage removes a slot
+func (uc *systemImageUseCase) DeleteImage(ctx context.Context, id string) error {
+	if _, err := uc.GetImage(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	uc.invalidate()
+	return nil
+}
+
+// Filenames returns the file of every slot, reloading them once the cache is older than
+// cacheTTL
+func (uc *systemImageUseCase) Filenames(ctx context.Context) (map[string]string, error) {
+	uc.mu.RLock()
+	filenames, loadedAt := uc.filenames, uc.loadedAt
+	uc.mu.RUnlock()
+	if filenames != nil && time.Since(loadedAt) < cacheTTL {
+		return filenames, nil
+	}
+
+	images, err := uc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filenames = make(map[string]string, len(images))
+	for _, img := range images {
+		filenames[img.ID] = img.Filename
+	}
+
+	uc.mu.Lock()
+	uc.filenames = filenames
+	uc.loadedAt = time.Now()
+	uc.mu.Unlock()
+	return filenames, nil
+}
+
+// checkFilename validates the file of a slot; two slots cannot share a file
+func (uc *systemImageUseCase) checkFilename(ctx context.Context, image *entity.SystemImage) error {
+	if !entity.ValidSystemImageFilename(image.Filename) {
+		return errors.New("invalid filename: use a png, jpg, gif, webp or svg name without directories or the upload_ prefix")
+	}
+	other, err := uc.repo.FindByFilename(ctx, image.Filename)
+	if err != nil {
+		return err
+	}
+	if other != nil && other.ID != image.ID {
+		return errors.New("invalid filename: already used by system image " + other.ID)
+	}
+	return nil
+}
+
+// invalidate drops the cache so changes made through this instance apply immediately
+func (uc *systemImageUseCase) invalidate() {
+	uc.mu.Lock()
+	uc.filenames = nil
+	uc.mu.Unlock()
+}
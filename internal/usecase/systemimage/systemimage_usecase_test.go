@@ -0,0 +1,101 @@
+package systemimage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+)
+
+func newTestUseCase() (UseCase, *testutil.MockSystemImageRepository) {
+	repo := testutil.NewMockSystemImageRepository(&entity.SystemImage{ID: "sys_logo", Filename: "logo-condotrack.png"})
+	return NewUseCase(repo), repo
+}
+
+func TestCreateImage(t *testing.T) {
+	uc, _ := newTestUseCase()
+	image, err := uc.CreateImage(context.Background(), &entity.CreateSystemImageRequest{
+		ID:       " Banner_Natal ",
+		Filename: "banner-natal-2024.png",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image.ID != "banner_natal" {
+		t.Errorf("expected lowercase id, got %q", image.ID)
+	}
+}
+
+func TestCreateImage_Invalid(t *testing.T) {
+	uc, _ := newTestUseCase()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  entity.CreateSystemImageRequest
+	}{
+		{"bad id", entity.CreateSystemImageRequest{ID: "logo principal", Filename: "a.png"}},
+		{"duplicate id", entity.CreateSystemImageRequest{ID: "sys_logo", Filename: "a.png"}},
+		{"bad filename", entity.CreateSystemImageRequest{ID: "banner", Filename: "../banner.png"}},
+		{"upload namespace", entity.CreateSystemImageRequest{ID: "banner", Filename: "upload_1_abc.png"}},
+		{"filename of another slot", entity.CreateSystemImageRequest{ID: "banner", Filename: "logo-condotrack.png"}},
+	}
+	for _, tt := range tests {
+		if _, err := uc.CreateImage(ctx, &tt.req); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestUpdateImage(t *testing.T) {
+	uc, _ := newTestUseCase()
+	ctx := context.Background()
+
+	filename := "logo-2025.png"
+	image, err := uc.UpdateImage(ctx, "sys_logo", &entity.UpdateSystemImageRequest{Filename: &filename})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image.Filename != filename {
+		t.Errorf("filename = %q, want %q", image.Filename, filename)
+	}
+
+	if _, err := uc.UpdateImage(ctx, "missing", &entity.UpdateSystemImageRequest{}); err == nil {
+		t.Error("expected not found error")
+	}
+}
+
+func TestFilenames_CachesAndInvalidates(t *testing.T) {
+	uc, repo := newTestUseCase()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		names, err := uc.Filenames(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if names["sys_logo"] != "logo-condotrack.png" {
+			t.Fatalf("unexpected filenames %v", names)
+		}
+	}
+	if repo.FindCalls != 1 {
+		t.Errorf("expected the slots to be loaded once, got %d loads", repo.FindCalls)
+	}
+
+	if _, err := uc.CreateImage(ctx, &entity.CreateSystemImageRequest{ID: "pos_1", Filename: "step1.png"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names, _ := uc.Filenames(ctx)
+	if names["pos_1"] != "step1.png" {
+		t.Errorf("expected the new slot after invalidation, got %v", names)
+	}
+
+	if err := uc.DeleteImage(ctx, "pos_1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names, _ = uc.Filenames(ctx)
+	if _, ok := names["pos_1"]; ok {
+		t.Error("expected the deleted slot to be gone")
+	}
+}
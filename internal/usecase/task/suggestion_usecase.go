@@ -0,0 +1,150 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/condotrack/api/internal/domain/ai"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/assistant"
+)
+
+const suggestionSystemPrompt = `Você é um gestor de facilities experiente em condomínios. Responda SEMPRE em PORTUGUÊS DO BRASIL.
+A partir dos problemas encontrados, proponha tarefas objetivas para corrigi-los e responda APENAS com um objeto JSON, sem texto adicional, no formato:
+{"tasks": [{"title": "tarefa curta e acionável", "description": "o que fazer e qual problema resolve", "priority": "low|medium|high|urgent", "assignee_role": "leader|auditor|inspector", "due_in_days": 7}]}
+assignee_role é o papel da equipe do contrato que deve executar a tarefa: leader (líder), auditor ou inspector (inspetor). Proponha no máximo 10 tarefas, sem repetir problemas.`
+
+// SuggestionUseCase defines the AI task suggestion use case interface
+type SuggestionUseCase interface {
+	SuggestFromAudit(ctx context.Context, auditID, userID string) (*entity.TaskSuggestions, error)
+	SuggestFromInspection(ctx context.Context, inspectionID, userID string) (*entity.TaskSuggestions, error)
+}
+
+type suggestionUseCase struct {
+	auditRepo      repository.AuditRepository
+	auditItemRepo  repository.AuditItemRepository
+	inspectionRepo repository.InspectionRepository
+	aiUC           assistant.UseCase
+}
+
+// NewSuggestionUseCase creates a new AI task suggestion use case
+func NewSuggestionUseCase(
+	auditRepo repository.AuditRepository,
+	auditItemRepo repository.AuditItemRepository,
+	inspectionRepo repository.InspectionRepository,
+	aiUC assistant.UseCase,
+) SuggestionUseCase {
+	return &suggestionUseCase{
+		auditRepo:      auditRepo,
+		auditItemRepo:  auditItemRepo,
+		inspectionRepo: inspectionRepo,
+		aiUC:           aiUC,
+	}
+}
+
+// SuggestFromAudit proposes tasks for the non-conformities of an audit: the items scoring
+// below entity.NonConformityPercent and the auditor observations
+func (uc *suggestionUseCase) SuggestFromAudit(ctx context.Context, auditID, userID string) (*entity.TaskSuggestions, error) {
+	audit, err := uc.auditRepo.FindByID(ctx, auditID)
+	if err != nil {
+		return nil, err
+	}
+	if audit == nil {
+		return nil, errors.New("audit not found")
+	}
+
+	items, err := uc.auditItemRepo.FindByAuditIDWithCategory(ctx, auditID)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Auditoria de %s com nota %.1f (meta %.1f).\n", audit.AuditDate.Format("02/01/2006"), audit.Score, audit.TargetScore)
+	found := false
+	for _, item := range items {
+		if !item.IsNonConformity() {
+			continue
+		}
+		if !found {
+			b.WriteString("\nNão conformidades:\n")
+			found = true
+		}
+		fmt.Fprintf(&b, "- [%s] %s: %.0f%% da nota", item.CategoryName, item.ItemName, item.CalculateItemPercentage())
+		if item.Observation != nil && *item.Observation != "" {
+			fmt.Fprintf(&b, " - %s", *item.Observation)
+		}
+		b.WriteString("\n")
+	}
+	if audit.Observations != nil && strings.TrimSpace(*audit.Observations) != "" {
+		fmt.Fprintf(&b, "\nObservações do auditor: %s\n", *audit.Observations)
+		found = true
+	}
+	if !found {
+		return nil, errors.New("invalid source: audit has no non-conformities")
+	}
+
+	return uc.suggest(ctx, entity.TaskSourceAudit, auditID, audit.ContractID, userID, b.String())
+}
+
+// SuggestFromInspection proposes tasks for the findings and recommendations of an inspection
+func (uc *suggestionUseCase) SuggestFromInspection(ctx context.Context, inspectionID, userID string) (*entity.TaskSuggestions, error) {
+	inspection, err := uc.inspectionRepo.FindByID(ctx, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if inspection == nil {
+		return nil, errors.New("inspection not found")
+	}
+	if inspection.Findings == nil || strings.TrimSpace(*inspection.Findings) == "" {
+		return nil, errors.New("invalid source: inspection has no findings")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Inspeção %s de %s em %s.\n", inspection.InspectionType, inspection.InspectionDate.Format("02/01/2006"), inspection.ContractName)
+	fmt.Fprintf(&b, "\nConstatações: %s\n", *inspection.Findings)
+	if inspection.Recommendations != nil && *inspection.Recommendations != "" {
+		fmt.Fprintf(&b, "\nRecomendações do inspetor: %s\n", *inspection.Recommendations)
+	}
+
+	return uc.suggest(ctx, entity.TaskSourceInspection, inspectionID, inspection.ContractID, userID, b.String())
+}
+
+func (uc *suggestionUseCase) suggest(ctx context.Context, source, sourceID, contractID, userID, prompt string) (*entity.TaskSuggestions, error) {
+	if uc.aiUC == nil {
+		return nil, assistant.ErrUnavailable
+	}
+	resp, err := uc.aiUC.Generate(ctx, assistant.Call{Feature: "task_suggestions", UserID: userID}, ai.Request{
+		System:      suggestionSystemPrompt,
+		Prompt:      prompt,
+		Temperature: 0.3,
+		MaxTokens:   2048,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Tasks []entity.TaskSuggestion `json:"tasks"`
+	}
+	if err := ai.DecodeJSON(resp.Text, &out); err != nil {
+		return nil, err
+	}
+
+	suggestions := &entity.TaskSuggestions{
+		Source:     source,
+		SourceID:   sourceID,
+		ContractID: contractID,
+		Tasks:      []entity.TaskSuggestion{},
+		Provider:   resp.Provider,
+		Model:      resp.Model,
+	}
+	for _, t := range out.Tasks {
+		if t.Normalize() {
+			suggestions.Tasks = append(suggestions.Tasks, t)
+		}
+	}
+	return suggestions, nil
+}
@@ -2,11 +2,15 @@ package task
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/condotrack/api/internal/domain/entity"
 	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/notification"
 	"github.com/google/uuid"
 )
 
@@ -15,6 +19,7 @@ type UseCase interface {
 	ListTasks(ctx context.Context, filter *entity.TaskFilter) ([]entity.Task, error)
 	GetTaskByID(ctx context.Context, id string) (*entity.Task, error)
 	CreateTask(ctx context.Context, req *entity.CreateTaskRequest) (*entity.Task, error)
+	CreateTasks(ctx context.Context, req *entity.BulkCreateTaskRequest) ([]entity.Task, error)
 	UpdateTask(ctx context.Context, id string, req *entity.UpdateTaskRequest) (*entity.Task, error)
 	UpdateTaskStatus(ctx context.Context, id string, req *entity.UpdateTaskStatusRequest) (*entity.Task, error)
 	DeleteTask(ctx context.Context, id string) error
@@ -27,18 +32,22 @@ type taskUseCase struct {
 	repo         repository.TaskRepository
 	contratoRepo repository.ContratoRepository
 	gestorRepo   repository.GestorRepository
+	notifier     notification.UseCase
 }
 
-// NewUseCase creates a new task use case
+// NewUseCase creates a new task use case. Assignees are notified of the tasks assigned to
+// them through notifier.
 func NewUseCase(
 	repo repository.TaskRepository,
 	contratoRepo repository.ContratoRepository,
 	gestorRepo repository.GestorRepository,
+	notifier notification.UseCase,
 ) UseCase {
 	return &taskUseCase{
 		repo:         repo,
 		contratoRepo: contratoRepo,
 		gestorRepo:   gestorRepo,
+		notifier:     notifier,
 	}
 }
 
@@ -121,7 +130,91 @@ func (uc *taskUseCase) CreateTask(ctx context.Context, req *entity.CreateTaskReq
 	}
 
 	// Fetch the full task with joined names
-	return uc.repo.FindByID(ctx, task.ID)
+	created, err := uc.repo.FindByID(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if created != nil {
+		uc.notifyAssigned(ctx, created, req.CreatedBy)
+	}
+	return created, nil
+}
+
+// CreateTasks creates several tasks of a contract at once. Every task is validated before any
+// is created and they are stored in a single transaction, so either all or none are created.
+func (uc *taskUseCase) CreateTasks(ctx context.Context, req *entity.BulkCreateTaskRequest) ([]entity.Task, error) {
+	if req.ContractID != nil && *req.ContractID != "" {
+		contrato, err := uc.contratoRepo.FindByID(ctx, *req.ContractID)
+		if err != nil {
+			return nil, err
+		}
+		if contrato == nil {
+			return nil, errors.New("contract not found")
+		}
+	}
+
+	creator, err := uc.gestorRepo.FindByID(ctx, req.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	if creator == nil {
+		return nil, errors.New("creator not found")
+	}
+
+	assignees := map[string]bool{}
+	tasks := make([]*entity.Task, 0, len(req.Tasks))
+	now := time.Now()
+	for i, in := range req.Tasks {
+		if in.AssignedTo != nil && *in.AssignedTo != "" && !assignees[*in.AssignedTo] {
+			assignee, err := uc.gestorRepo.FindByID(ctx, *in.AssignedTo)
+			if err != nil {
+				return nil, err
+			}
+			if assignee == nil {
+				return nil, fmt.Errorf("assignee not found (task %d)", i+1)
+			}
+			assignees[*in.AssignedTo] = true
+		}
+
+		priority := in.Priority
+		if priority == "" {
+			priority = entity.TaskPriorityMedium
+		}
+		if !entity.ValidTaskPriority(priority) {
+			return nil, fmt.Errorf("invalid priority (task %d)", i+1)
+		}
+
+		tasks = append(tasks, &entity.Task{
+			ID:          uuid.New().String(),
+			Title:       in.Title,
+			Description: in.Description,
+			Status:      entity.TaskStatusPending,
+			Priority:    priority,
+			DueDate:     in.DueDate,
+			ContractID:  req.ContractID,
+			AssignedTo:  in.AssignedTo,
+			CreatedBy:   req.CreatedBy,
+			CreatedAt:   now,
+		})
+	}
+
+	if err := uc.repo.CreateBatch(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	// Fetch the full tasks with joined names
+	created := make([]entity.Task, 0, len(tasks))
+	for _, t := range tasks {
+		task, err := uc.repo.FindByID(ctx, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			uc.notifyAssigned(ctx, task, req.CreatedBy)
+			created = append(created, *task)
+		}
+	}
+	return created, nil
 }
 
 // UpdateTask updates an existing task
@@ -150,6 +243,7 @@ func (uc *taskUseCase) UpdateTask(ctx context.Context, id string, req *entity.Up
 	}
 
 	// Validate assignee if being updated
+	reassigned := false
 	if req.AssignedTo != nil {
 		if *req.AssignedTo != "" {
 			assignee, err := uc.gestorRepo.FindByID(ctx, *req.AssignedTo)
@@ -159,6 +253,7 @@ func (uc *taskUseCase) UpdateTask(ctx context.Context, id string, req *entity.Up
 			if assignee == nil {
 				return nil, errors.New("assignee not found")
 			}
+			reassigned = task.AssignedTo == nil || *task.AssignedTo != *req.AssignedTo
 		}
 		task.AssignedTo = req.AssignedTo
 	}
@@ -191,6 +286,11 @@ func (uc *taskUseCase) UpdateTask(ctx context.Context, id string, req *entity.Up
 		task.Priority = *req.Priority
 	}
 	if req.DueDate != nil {
+		// A new deadline starts the escalation over
+		if task.DueDate == nil || !req.DueDate.Equal(*task.DueDate) {
+			task.EscalationLevel = entity.TaskEscalationNone
+			task.EscalatedAt = nil
+		}
 		task.DueDate = req.DueDate
 	}
 
@@ -203,7 +303,14 @@ func (uc *taskUseCase) UpdateTask(ctx context.Context, id string, req *entity.Up
 	}
 
 	// Fetch the full task with joined names
-	return uc.repo.FindByID(ctx, task.ID)
+	updated, err := uc.repo.FindByID(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if updated != nil && reassigned {
+		uc.notifyAssigned(ctx, updated, "")
+	}
+	return updated, nil
 }
 
 // UpdateTaskStatus updates only the status of a task
@@ -276,3 +383,37 @@ func (uc *taskUseCase) GetTasksByAssignee(ctx context.Context, assigneeID string
 func (uc *taskUseCase) GetOverdueTasks(ctx context.Context) ([]entity.Task, error) {
 	return uc.repo.FindOverdue(ctx)
 }
+
+// notifyAssigned notifies the assignee of a task assigned to them, unless they assigned it
+// to themselves (non-critical)
+func (uc *taskUseCase) notifyAssigned(ctx context.Context, task *entity.Task, assignedBy string) {
+	if uc.notifier == nil || task.AssignedTo == nil || *task.AssignedTo == "" || *task.AssignedTo == assignedBy {
+		return
+	}
+	payload := map[string]interface{}{"task_id": task.ID, "priority": task.Priority}
+	if task.ContractID != nil {
+		payload["contract_id"] = *task.ContractID
+	}
+	if task.DueDate != nil {
+		payload["due_date"] = task.DueDate.Format(time.RFC3339)
+	}
+	raw, _ := json.Marshal(payload)
+	data := string(raw)
+
+	message := fmt.Sprintf("A tarefa \"%s\" foi atribuída a você.", task.Title)
+	if task.ContractName != nil {
+		message = fmt.Sprintf("A tarefa \"%s\" do contrato %s foi atribuída a você.", task.Title, *task.ContractName)
+	}
+	notif := &entity.Notificacao{
+		ID:        uuid.New().String(),
+		UserID:    *task.AssignedTo,
+		Type:      entity.NotificationTypeTask,
+		Title:     "Nova tarefa atribuída",
+		Message:   message,
+		Data:      &data,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.notifier.Create(ctx, notif); err != nil {
+		log.Printf("Failed to notify user %s about assigned task %s: %v", *task.AssignedTo, task.ID, err)
+	}
+}
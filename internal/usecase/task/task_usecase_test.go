@@ -0,0 +1,88 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/notification"
+)
+
+type fakeTaskRepo struct {
+	repository.TaskRepository
+	tasks map[string]*entity.Task
+}
+
+func (r *fakeTaskRepo) FindByID(ctx context.Context, id string) (*entity.Task, error) {
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (r *fakeTaskRepo) Create(ctx context.Context, task *entity.Task) error {
+	r.tasks[task.ID] = task
+	return nil
+}
+
+func (r *fakeTaskRepo) Update(ctx context.Context, task *entity.Task) error {
+	r.tasks[task.ID] = task
+	return nil
+}
+
+type fakeGestorRepo struct {
+	repository.GestorRepository
+}
+
+func (r *fakeGestorRepo) FindByID(ctx context.Context, id string) (*entity.Gestor, error) {
+	return &entity.Gestor{ID: id}, nil
+}
+
+type fakeNotifier struct {
+	notification.UseCase
+	sent []*entity.Notificacao
+}
+
+func (n *fakeNotifier) Create(ctx context.Context, notif *entity.Notificacao) error {
+	n.sent = append(n.sent, notif)
+	return nil
+}
+
+func TestAssigneeIsNotified(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeTaskRepo{tasks: map[string]*entity.Task{}}
+	notifier := &fakeNotifier{}
+	uc := NewUseCase(repo, nil, &fakeGestorRepo{}, notifier)
+
+	assignee, creator := "gestor-2", "gestor-1"
+	task, err := uc.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Revisar bombas", CreatedBy: creator, AssignedTo: &assignee})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].UserID != assignee || notifier.sent[0].Type != entity.NotificationTypeTask {
+		t.Fatalf("sent = %+v", notifier.sent)
+	}
+
+	// Tasks assigned to their creator and updates keeping the assignee notify nobody
+	if _, err := uc.CreateTask(ctx, &entity.CreateTaskRequest{Title: "Podar jardim", CreatedBy: creator, AssignedTo: &creator}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	title := "Revisar bombas d'água"
+	if _, err := uc.UpdateTask(ctx, task.ID, &entity.UpdateTaskRequest{Title: &title, AssignedTo: &assignee}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected no new notification, sent %d", len(notifier.sent))
+	}
+
+	other := "gestor-3"
+	if _, err := uc.UpdateTask(ctx, task.ID, &entity.UpdateTaskRequest{AssignedTo: &other}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.sent) != 2 || notifier.sent[1].UserID != other {
+		t.Errorf("reassignment: sent = %+v", notifier.sent)
+	}
+}
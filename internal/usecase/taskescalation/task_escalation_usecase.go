@@ -0,0 +1,210 @@
+package taskescalation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/condotrack/api/internal/config"
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+	"github.com/condotrack/api/internal/usecase/notification"
+	"github.com/condotrack/api/pkg/lifecycle"
+	"github.com/google/uuid"
+)
+
+// UseCase defines the overdue task escalation use case interface
+type UseCase interface {
+	RunEscalations(ctx context.Context, now time.Time) (*entity.TaskEscalationResult, error)
+	Report(ctx context.Context, filters entity.TaskEscalationReportFilters) ([]entity.TaskEscalationReport, error)
+	StartEscalationScheduler(lc *lifecycle.Manager, interval time.Duration)
+}
+
+type taskEscalationUseCase struct {
+	repo     repository.TaskEscalationRepository
+	userRepo repository.UserRepository
+	notifier notification.UseCase
+	policy   entity.TaskEscalationPolicy
+}
+
+// NewUseCase creates a new overdue task escalation use case
+func NewUseCase(
+	repo repository.TaskEscalationRepository,
+	userRepo repository.UserRepository,
+	notifier notification.UseCase,
+	cfg *config.Config,
+) UseCase {
+	return &taskEscalationUseCase{
+		repo:     repo,
+		userRepo: userRepo,
+		notifier: notifier,
+		policy: entity.TaskEscalationPolicy{
+			GestorAfter: time.Duration(cfg.TaskEscalationGestorHours) * time.Hour,
+			AdminAfter:  time.Duration(cfg.TaskEscalationAdminHours) * time.Hour,
+		},
+	}
+}
+
+// RunEscalations notifies the contract gestor of the tasks overdue past the first threshold
+// and the admins of those overdue past the second. A task reaching the admin level without
+// having been escalated to its gestor notifies the gestor as well.
+func (uc *taskEscalationUseCase) RunEscalations(ctx context.Context, now time.Time) (*entity.TaskEscalationResult, error) {
+	result := &entity.TaskEscalationResult{}
+	if !uc.policy.Enabled() {
+		return result, nil
+	}
+
+	tasks, err := uc.repo.FindOverdue(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var admins []entity.User
+	adminsLoaded := false
+	for i := range tasks {
+		task := &tasks[i]
+		result.Checked++
+
+		overdue := now.Sub(task.DueDate)
+		level := uc.policy.DueLevel(overdue, task.EscalationLevel)
+		if level == entity.TaskEscalationNone {
+			continue
+		}
+
+		// Claim the level first, so a concurrent sweep does not notify it again
+		claimed, err := uc.repo.MarkEscalated(ctx, task.TaskID, level, now)
+		if err != nil {
+			return nil, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if level == entity.TaskEscalationAdmin && !adminsLoaded {
+			if admins, err = uc.activeAdmins(ctx); err != nil {
+				return nil, err
+			}
+			adminsLoaded = true
+		}
+		recipients := escalationRecipients(task, level, admins)
+
+		hours := int(overdue.Hours())
+		sent := 0
+		for _, userID := range recipients {
+			if err := uc.notifier.Create(ctx, escalationNotification(userID, task, level, hours)); err != nil {
+				log.Printf("Failed to notify user %s about overdue task %s: %v", userID, task.TaskID, err)
+				continue
+			}
+			sent++
+		}
+
+		escalation := &entity.TaskEscalation{
+			ID:           uuid.New().String(),
+			TaskID:       task.TaskID,
+			ContractID:   task.ContractID,
+			Level:        level,
+			OverdueHours: hours,
+			Recipients:   sent,
+			CreatedAt:    now,
+		}
+		if err := uc.repo.Create(ctx, escalation); err != nil {
+			return nil, err
+		}
+		result.Escalated++
+		result.Notifications += sent
+	}
+
+	return result, nil
+}
+
+// Report returns the escalations per contract
+func (uc *taskEscalationUseCase) Report(ctx context.Context, filters entity.TaskEscalationReportFilters) ([]entity.TaskEscalationReport, error) {
+	return uc.repo.Report(ctx, filters)
+}
+
+// StartEscalationScheduler runs the escalation sweep once at startup and then at every
+// interval, as a lifecycle worker. Levels already recorded on a task are not notified again.
+func (uc *taskEscalationUseCase) StartEscalationScheduler(lc *lifecycle.Manager, interval time.Duration) {
+	lc.Every("task escalations", interval, true, func(ctx context.Context) {
+		result, err := uc.RunEscalations(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Task escalation sweep failed: %v", err)
+			}
+		} else if result.Escalated > 0 {
+			log.Printf("Overdue tasks escalated: %d (%d notifications)", result.Escalated, result.Notifications)
+		}
+	})
+}
+
+// escalationRecipients returns the users to notify of a task reaching a level, without
+// duplicates: the gestor until notified once, and the admins at the admin level
+func escalationRecipients(task *entity.OverdueTask, level int, admins []entity.User) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	if task.GestorID != nil && *task.GestorID != "" && task.EscalationLevel < entity.TaskEscalationGestor {
+		seen[*task.GestorID] = true
+		ids = append(ids, *task.GestorID)
+	}
+	if level == entity.TaskEscalationAdmin {
+		for _, a := range admins {
+			if !seen[a.ID] {
+				seen[a.ID] = true
+				ids = append(ids, a.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func (uc *taskEscalationUseCase) activeAdmins(ctx context.Context) ([]entity.User, error) {
+	role := entity.RoleAdmin
+	active := true
+	return uc.userRepo.FindAllWithFilters(ctx, repository.UserFilters{Role: &role, IsActive: &active})
+}
+
+func escalationNotification(userID string, task *entity.OverdueTask, level, overdueHours int) *entity.Notificacao {
+	payload := map[string]interface{}{
+		"task_id":          task.TaskID,
+		"escalation_level": level,
+		"overdue_hours":    overdueHours,
+		"due_date":         task.DueDate.Format(time.RFC3339),
+	}
+	if task.ContractID != nil {
+		payload["contract_id"] = *task.ContractID
+	}
+	raw, _ := json.Marshal(payload)
+	data := string(raw)
+
+	message := fmt.Sprintf("A tarefa \"%s\" está atrasada há %s.", task.Title, overdueText(overdueHours))
+	if task.ContractName != nil {
+		message = fmt.Sprintf("A tarefa \"%s\" do contrato %s está atrasada há %s.", task.Title, *task.ContractName, overdueText(overdueHours))
+	}
+	title := "Tarefa atrasada"
+	if level == entity.TaskEscalationAdmin {
+		title = "Tarefa atrasada escalada para a administração"
+	}
+
+	return &entity.Notificacao{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Type:      entity.NotificationTypeTask,
+		Title:     title,
+		Message:   message,
+		Data:      &data,
+		CreatedAt: time.Now(),
+	}
+}
+
+// overdueText formats an overdue time in hours, or in days from two days on
+func overdueText(hours int) string {
+	switch {
+	case hours >= 48:
+		return fmt.Sprintf("%d dias", hours/24)
+	case hours == 1:
+		return "1 hora"
+	}
+	return fmt.Sprintf("%d horas", hours)
+}
@@ -0,0 +1,133 @@
+package taskescalation
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/testutil"
+	"github.com/condotrack/api/internal/usecase/notification"
+)
+
+type fakeEscalationRepo struct {
+	tasks       []entity.OverdueTask
+	escalations []entity.TaskEscalation
+}
+
+func (r *fakeEscalationRepo) FindOverdue(ctx context.Context, now time.Time) ([]entity.OverdueTask, error) {
+	var overdue []entity.OverdueTask
+	for _, t := range r.tasks {
+		if t.DueDate.Before(now) && t.EscalationLevel < entity.TaskEscalationAdmin {
+			overdue = append(overdue, t)
+		}
+	}
+	return overdue, nil
+}
+
+func (r *fakeEscalationRepo) MarkEscalated(ctx context.Context, taskID string, level int, at time.Time) (bool, error) {
+	for i := range r.tasks {
+		if r.tasks[i].TaskID == taskID && r.tasks[i].EscalationLevel < level {
+			r.tasks[i].EscalationLevel = level
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeEscalationRepo) Create(ctx context.Context, escalation *entity.TaskEscalation) error {
+	r.escalations = append(r.escalations, *escalation)
+	return nil
+}
+
+func (r *fakeEscalationRepo) Report(ctx context.Context, filters entity.TaskEscalationReportFilters) ([]entity.TaskEscalationReport, error) {
+	return nil, nil
+}
+
+type fakeNotifier struct {
+	notification.UseCase
+	sent []*entity.Notificacao
+}
+
+func (n *fakeNotifier) Create(ctx context.Context, notif *entity.Notificacao) error {
+	n.sent = append(n.sent, notif)
+	return nil
+}
+
+func (n *fakeNotifier) recipients() []string {
+	var ids []string
+	for _, notif := range n.sent {
+		ids = append(ids, notif.UserID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestRunEscalations(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	gestor, contract, contractName := "gestor-1", "ct-1", "Residencial Sol"
+	repo := &fakeEscalationRepo{tasks: []entity.OverdueTask{
+		{TaskID: "recent", Title: "Trocar lâmpadas", DueDate: now.Add(-2 * time.Hour), GestorID: &gestor},
+		{TaskID: "day", Title: "Limpar caixa d'água", DueDate: now.Add(-30 * time.Hour), ContractID: &contract, ContractName: &contractName, GestorID: &gestor},
+		{TaskID: "week", Title: "Revisar extintores", DueDate: now.Add(-7 * 24 * time.Hour), ContractID: &contract, GestorID: &gestor},
+	}}
+	users := testutil.NewMockUserRepository(
+		&entity.User{ID: "admin-1", Role: entity.RoleAdmin, IsActive: true},
+		&entity.User{ID: "admin-2", Role: entity.RoleAdmin, IsActive: false},
+	)
+	notifier := &fakeNotifier{}
+	uc := &taskEscalationUseCase{
+		repo:     repo,
+		userRepo: users,
+		notifier: notifier,
+		policy:   entity.TaskEscalationPolicy{GestorAfter: 24 * time.Hour, AdminAfter: 72 * time.Hour},
+	}
+
+	result, err := uc.RunEscalations(context.Background(), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Checked != 3 || result.Escalated != 2 || result.Notifications != 3 {
+		t.Errorf("result = %+v", result)
+	}
+	// "day" notifies its gestor; "week" skipped the gestor level, so both gestor and admin
+	if got := strings.Join(notifier.recipients(), ","); got != "admin-1,gestor-1,gestor-1" {
+		t.Errorf("recipients = %s", got)
+	}
+	if len(repo.escalations) != 2 || repo.escalations[0].Level != entity.TaskEscalationGestor || repo.escalations[1].Level != entity.TaskEscalationAdmin {
+		t.Errorf("escalations = %+v", repo.escalations)
+	}
+	if repo.escalations[0].OverdueHours != 30 || !strings.Contains(notifier.sent[0].Message, contractName) {
+		t.Errorf("escalation = %+v, message %q", repo.escalations[0], notifier.sent[0].Message)
+	}
+
+	// Levels already reached are not notified again; "day" reaches the admin level later on
+	notifier.sent = nil
+	again, _ := uc.RunEscalations(context.Background(), now)
+	if again.Escalated != 0 || len(notifier.sent) != 0 {
+		t.Errorf("second run = %+v, sent %d", again, len(notifier.sent))
+	}
+	later, _ := uc.RunEscalations(context.Background(), now.Add(48*time.Hour))
+	if later.Escalated != 2 || strings.Join(notifier.recipients(), ",") != "admin-1,gestor-1" {
+		t.Errorf("later run = %+v, recipients %v", later, notifier.recipients())
+	}
+}
+
+func TestRunEscalationsDisabled(t *testing.T) {
+	repo := &fakeEscalationRepo{tasks: []entity.OverdueTask{{TaskID: "t", DueDate: time.Now().Add(-1000 * time.Hour)}}}
+	uc := &taskEscalationUseCase{repo: repo, notifier: &fakeNotifier{}}
+	result, err := uc.RunEscalations(context.Background(), time.Now())
+	if err != nil || result.Checked != 0 || len(repo.escalations) != 0 {
+		t.Errorf("disabled policy: result %+v, err %v", result, err)
+	}
+}
+
+func TestOverdueText(t *testing.T) {
+	for hours, want := range map[int]string{1: "1 hora", 30: "30 horas", 72: "3 dias"} {
+		if got := overdueText(hours); got != want {
+			t.Errorf("overdueText(%d) = %q, want %q", hours, got, want)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	// conflictLookaheadDays is how far ahead a new or updated shift is checked against the agenda
+	conflictLookaheadDays = 28
+	// maxShiftReportDays caps the date range of conflict and coverage reports
+	maxShiftReportDays = 62
+)
+
+// ListShifts returns all shifts with optional filters
+func (uc *teamUseCase) ListShifts(ctx context.Context, filter *entity.TeamShiftFilter) ([]entity.TeamShift, error) {
+	return uc.shiftRepo.FindAll(ctx, filter)
+}
+
+// GetShiftByID returns a specific shift by ID
+func (uc *teamUseCase) GetShiftByID(ctx context.Context, id string) (*entity.TeamShift, error) {
+	return uc.shiftRepo.FindByID(ctx, id)
+}
+
+// CreateShift schedules a weekly shift for a team member.
+// Overlapping shifts of the same member are rejected; agenda clashes are returned as conflicts.
+func (uc *teamUseCase) CreateShift(ctx context.Context, req *entity.CreateTeamShiftRequest) (*entity.TeamShift, error) {
+	member, err := uc.teamRepo.FindByID(ctx, req.TeamMemberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.New("team member not found")
+	}
+	if !member.IsActive {
+		return nil, errors.New("invalid team member: assignment is not active")
+	}
+
+	shift := &entity.TeamShift{
+		ID:             uuid.New().String(),
+		TeamMemberID:   member.ID,
+		UserID:         member.UserID,
+		ContractID:     member.ContractID,
+		Weekday:        *req.Weekday,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		EffectiveFrom:  req.EffectiveFrom,
+		EffectiveUntil: req.EffectiveUntil,
+		Notes:          req.Notes,
+		IsActive:       true,
+	}
+	if err := uc.validateShift(ctx, shift); err != nil {
+		return nil, err
+	}
+
+	shiftDB := &entity.TeamShiftDB{
+		ID:             shift.ID,
+		TeamMemberID:   shift.TeamMemberID,
+		UserID:         shift.UserID,
+		ContractID:     shift.ContractID,
+		Weekday:        shift.Weekday,
+		StartTime:      shift.StartTime,
+		EndTime:        shift.EndTime,
+		EffectiveFrom:  shift.EffectiveFrom,
+		EffectiveUntil: shift.EffectiveUntil,
+		Notes:          shift.Notes,
+		IsActive:       shift.IsActive,
+		CreatedAt:      time.Now(),
+	}
+	if err := uc.shiftRepo.Create(ctx, shiftDB); err != nil {
+		return nil, err
+	}
+
+	return uc.shiftWithConflicts(ctx, shift.ID)
+}
+
+// UpdateShift updates an existing shift
+func (uc *teamUseCase) UpdateShift(ctx context.Context, id string, req *entity.UpdateTeamShiftRequest) (*entity.TeamShift, error) {
+	shift, err := uc.shiftRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if shift == nil {
+		return nil, errors.New("shift not found")
+	}
+
+	if req.Weekday != nil {
+		shift.Weekday = *req.Weekday
+	}
+	if req.StartTime != nil {
+		shift.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		shift.EndTime = *req.EndTime
+	}
+	if req.EffectiveFrom != nil {
+		shift.EffectiveFrom = req.EffectiveFrom
+	}
+	if req.EffectiveUntil != nil {
+		shift.EffectiveUntil = req.EffectiveUntil
+	}
+	if req.Notes != nil {
+		shift.Notes = req.Notes
+	}
+	if req.IsActive != nil {
+		shift.IsActive = *req.IsActive
+	}
+
+	if shift.IsActive {
+		if err := uc.validateShift(ctx, shift); err != nil {
+			return nil, err
+		}
+	}
+
+	shiftDB := &entity.TeamShiftDB{
+		ID:             shift.ID,
+		Weekday:        shift.Weekday,
+		StartTime:      shift.StartTime,
+		EndTime:        shift.EndTime,
+		EffectiveFrom:  shift.EffectiveFrom,
+		EffectiveUntil: shift.EffectiveUntil,
+		Notes:          shift.Notes,
+		IsActive:       shift.IsActive,
+	}
+	if err := uc.shiftRepo.Update(ctx, shiftDB); err != nil {
+		return nil, err
+	}
+
+	return uc.shiftWithConflicts(ctx, id)
+}
+
+// DeleteShift removes a shift
+func (uc *teamUseCase) DeleteShift(ctx context.Context, id string) error {
+	shift, err := uc.shiftRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if shift == nil {
+		return errors.New("shift not found")
+	}
+
+	return uc.shiftRepo.Delete(ctx, id)
+}
+
+// GetShiftConflicts returns agenda events that clash with the active shifts of a contract
+func (uc *teamUseCase) GetShiftConflicts(ctx context.Context, contractID, from, to string) ([]entity.TeamShiftConflict, error) {
+	start, end, err := parseShiftRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := uc.contratoRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract == nil {
+		return nil, errors.New("contract not found")
+	}
+
+	shifts, err := uc.shiftRepo.FindAll(ctx, &entity.TeamShiftFilter{ContractID: &contractID, IsActive: boolPtr(true)})
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := uc.memberEvents(ctx, shifts, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make([]entity.TeamShiftConflict, 0)
+	for i := range shifts {
+		conflicts = append(conflicts, entity.ShiftEventConflicts(&shifts[i], events[shifts[i].UserID], start, end)...)
+	}
+	sortConflicts(conflicts)
+	return conflicts, nil
+}
+
+// GetCoverageReport returns the daily shift coverage of a contract between from and to
+// (YYYY-MM-DD, inclusive). The range defaults to the next 7 days.
+func (uc *teamUseCase) GetCoverageReport(ctx context.Context, contractID, from, to string) (*entity.TeamCoverageReport, error) {
+	start, end, err := parseShiftRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := uc.contratoRepo.FindByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract == nil {
+		return nil, errors.New("contract not found")
+	}
+
+	shifts, err := uc.shiftRepo.FindAll(ctx, &entity.TeamShiftFilter{ContractID: &contractID, IsActive: boolPtr(true)})
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := uc.memberEvents(ctx, shifts, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := buildCoverageReport(shifts, events, start, end)
+	report.ContractID = contract.ID
+	report.ContractName = contract.Nome
+	return report, nil
+}
+
+// validateShift checks the shift hours and effective dates and rejects overlaps with
+// other active shifts of the same member, on any contract
+func (uc *teamUseCase) validateShift(ctx context.Context, shift *entity.TeamShift) error {
+	start, err := entity.ParseShiftClock(shift.StartTime)
+	if err != nil {
+		return errors.New("invalid start_time, expected HH:MM")
+	}
+	end, err := entity.ParseShiftClock(shift.EndTime)
+	if err != nil {
+		return errors.New("invalid end_time, expected HH:MM")
+	}
+	if end <= start {
+		return errors.New("invalid shift: end_time must be after start_time")
+	}
+	shift.StartTime = entity.FormatShiftClock(start)
+	shift.EndTime = entity.FormatShiftClock(end)
+
+	if shift.EffectiveFrom != nil && shift.EffectiveUntil != nil && shift.EffectiveUntil.Before(*shift.EffectiveFrom) {
+		return errors.New("invalid shift: effective_until is before effective_from")
+	}
+
+	existing, err := uc.shiftRepo.FindAll(ctx, &entity.TeamShiftFilter{UserID: &shift.UserID, IsActive: boolPtr(true)})
+	if err != nil {
+		return err
+	}
+	for i := range existing {
+		if existing[i].ID != shift.ID && shift.Overlaps(&existing[i]) {
+			return errors.New("shift overlaps another shift of this member")
+		}
+	}
+	return nil
+}
+
+// shiftWithConflicts loads a shift and attaches the agenda conflicts of its upcoming occurrences
+func (uc *teamUseCase) shiftWithConflicts(ctx context.Context, id string) (*entity.TeamShift, error) {
+	shift, err := uc.shiftRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if shift == nil || !shift.IsActive {
+		return shift, nil
+	}
+
+	start := today()
+	end := start.AddDate(0, 0, conflictLookaheadDays)
+	events, err := uc.memberEvents(ctx, []entity.TeamShift{*shift}, start, end)
+	if err != nil {
+		return nil, err
+	}
+	shift.Conflicts = entity.ShiftEventConflicts(shift, events[shift.UserID], start, end)
+	return shift, nil
+}
+
+// memberEvents loads the agenda events of every member with a shift, keyed by user ID
+func (uc *teamUseCase) memberEvents(ctx context.Context, shifts []entity.TeamShift, start, end time.Time) (map[string][]entity.AgendaEvent, error) {
+	events := make(map[string][]entity.AgendaEvent)
+	for _, s := range shifts {
+		if _, ok := events[s.UserID]; ok {
+			continue
+		}
+		userID := s.UserID
+		userEvents, err := uc.agendaRepo.FindWithFilters(ctx, &entity.AgendaFilter{
+			StartDate: &start,
+			EndDate:   &end,
+			UserID:    &userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events[userID] = userEvents
+	}
+	return events, nil
+}
+
+// buildCoverageReport lays the shifts out day by day between start (inclusive) and end (exclusive)
+func buildCoverageReport(shifts []entity.TeamShift, events map[string][]entity.AgendaEvent, start, end time.Time) *entity.TeamCoverageReport {
+	report := &entity.TeamCoverageReport{
+		From:          start.Format("2006-01-02"),
+		To:            end.AddDate(0, 0, -1).Format("2006-01-02"),
+		UncoveredDays: make([]string, 0),
+		Days:          make([]entity.TeamCoverageDay, 0),
+		Members:       make([]entity.TeamCoverageMember, 0),
+		Conflicts:     make([]entity.TeamShiftConflict, 0),
+	}
+
+	members := make(map[string]*entity.TeamCoverageMember)
+	var memberOrder []string
+	totalMinutes := 0
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		coverage := entity.TeamCoverageDay{
+			Date:    day.Format("2006-01-02"),
+			Weekday: int(day.Weekday()),
+			Shifts:  make([]entity.TeamCoverageShift, 0),
+		}
+
+		var intervals [][2]int
+		for i := range shifts {
+			s := &shifts[i]
+			if !s.OccursOn(day) {
+				continue
+			}
+			from, _ := entity.ParseShiftClock(s.StartTime)
+			to, _ := entity.ParseShiftClock(s.EndTime)
+			intervals = append(intervals, [2]int{from, to})
+
+			conflicts := entity.ShiftEventConflicts(s, events[s.UserID], day, day.AddDate(0, 0, 1))
+			report.Conflicts = append(report.Conflicts, conflicts...)
+
+			coverage.Shifts = append(coverage.Shifts, entity.TeamCoverageShift{
+				ShiftID:   s.ID,
+				UserID:    s.UserID,
+				UserName:  s.UserName,
+				StartTime: s.StartTime,
+				EndTime:   s.EndTime,
+				Conflicts: len(conflicts),
+			})
+
+			m, ok := members[s.UserID]
+			if !ok {
+				m = &entity.TeamCoverageMember{UserID: s.UserID, UserName: s.UserName}
+				members[s.UserID] = m
+				memberOrder = append(memberOrder, s.UserID)
+			}
+			m.ScheduledMinutes += to - from
+			m.Conflicts += len(conflicts)
+		}
+
+		if len(intervals) == 0 {
+			report.UncoveredDays = append(report.UncoveredDays, coverage.Date)
+		} else {
+			merged := mergeIntervals(intervals)
+			for _, iv := range merged {
+				coverage.CoveredMinutes += iv[1] - iv[0]
+			}
+			first := entity.FormatShiftClock(merged[0][0])
+			last := entity.FormatShiftClock(merged[len(merged)-1][1])
+			coverage.FirstStart = &first
+			coverage.LastEnd = &last
+			sort.Slice(coverage.Shifts, func(i, j int) bool {
+				return coverage.Shifts[i].StartTime < coverage.Shifts[j].StartTime
+			})
+		}
+		totalMinutes += coverage.CoveredMinutes
+		report.Days = append(report.Days, coverage)
+	}
+
+	for _, userID := range memberOrder {
+		m := members[userID]
+		m.ScheduledHours = minutesToHours(m.ScheduledMinutes)
+		report.Members = append(report.Members, *m)
+	}
+	report.CoveredHours = minutesToHours(totalMinutes)
+	sortConflicts(report.Conflicts)
+	return report
+}
+
+// mergeIntervals returns the union of [start, end) minute intervals, sorted by start
+func mergeIntervals(intervals [][2]int) [][2]int {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i][0] < intervals[j][0] })
+	merged := [][2]int{intervals[0]}
+	for _, iv := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if iv[0] <= last[1] {
+			if iv[1] > last[1] {
+				last[1] = iv[1]
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+	return merged
+}
+
+// parseShiftRange parses an inclusive YYYY-MM-DD range into [start, end).
+// Dates are kept in UTC, matching how agenda datetimes are read from the database.
+func parseShiftRange(from, to string) (time.Time, time.Time, error) {
+	start := today()
+	if from != "" {
+		parsed, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from date, expected YYYY-MM-DD")
+		}
+		start = parsed
+	}
+
+	last := start.AddDate(0, 0, 6)
+	if to != "" {
+		parsed, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to date, expected YYYY-MM-DD")
+		}
+		last = parsed
+	}
+
+	if last.Before(start) {
+		return time.Time{}, time.Time{}, errors.New("invalid date range: to is before from")
+	}
+	end := last.AddDate(0, 0, 1)
+	if end.Sub(start) > maxShiftReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("invalid date range: at most 62 days")
+	}
+	return start, end, nil
+}
+
+func sortConflicts(conflicts []entity.TeamShiftConflict) {
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		if conflicts[i].Date != conflicts[j].Date {
+			return conflicts[i].Date < conflicts[j].Date
+		}
+		return conflicts[i].ShiftStart < conflicts[j].ShiftStart
+	})
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func minutesToHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,68 @@
+package team
+
+import (
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestBuildCoverageReport(t *testing.T) {
+	until := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	shifts := []entity.TeamShift{
+		{ID: "s1", UserID: "u1", UserName: "Ana", ContractID: "c1", Weekday: int(time.Monday), StartTime: "08:00", EndTime: "12:00", IsActive: true},
+		{ID: "s2", UserID: "u2", UserName: "Bruno", ContractID: "c1", Weekday: int(time.Monday), StartTime: "10:00", EndTime: "14:00", IsActive: true},
+		{ID: "s3", UserID: "u2", UserName: "Bruno", ContractID: "c1", Weekday: int(time.Tuesday), StartTime: "14:00", EndTime: "18:00", IsActive: true, EffectiveUntil: &until},
+	}
+
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := map[string][]entity.AgendaEvent{
+		"u1": {{ID: "e1", StartDatetime: monday.Add(9 * time.Hour), EndDatetime: monday.Add(10 * time.Hour)}},
+	}
+
+	report := buildCoverageReport(shifts, events, monday, monday.AddDate(0, 0, 3))
+	if report.From != "2024-01-01" || report.To != "2024-01-03" || len(report.Days) != 3 {
+		t.Fatalf("unexpected report range: %s..%s (%d days)", report.From, report.To, len(report.Days))
+	}
+
+	mon := report.Days[0]
+	if mon.CoveredMinutes != 360 || *mon.FirstStart != "08:00" || *mon.LastEnd != "14:00" {
+		t.Errorf("unexpected monday coverage: %+v", mon)
+	}
+	if len(mon.Shifts) != 2 || mon.Shifts[0].Conflicts != 1 {
+		t.Errorf("unexpected monday shifts: %+v", mon.Shifts)
+	}
+
+	if len(report.UncoveredDays) != 2 || report.UncoveredDays[0] != "2024-01-02" {
+		t.Errorf("expected tuesday (shift ended) and wednesday uncovered, got %v", report.UncoveredDays)
+	}
+	if report.CoveredHours != 6 {
+		t.Errorf("expected 6 covered hours, got %.2f", report.CoveredHours)
+	}
+	if len(report.Members) != 2 || report.Members[1].ScheduledHours != 4 || report.Members[0].Conflicts != 1 {
+		t.Errorf("unexpected members: %+v", report.Members)
+	}
+	if len(report.Conflicts) != 1 || report.Conflicts[0].EventID != "e1" {
+		t.Errorf("unexpected conflicts: %+v", report.Conflicts)
+	}
+}
+
+func TestParseShiftRange(t *testing.T) {
+	start, end, err := parseShiftRange("2024-01-01", "2024-01-07")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if end.Sub(start) != 7*24*time.Hour {
+		t.Errorf("expected 7 day range, got %v", end.Sub(start))
+	}
+
+	if _, _, err := parseShiftRange("2024-01-07", "2024-01-01"); err == nil {
+		t.Error("expected error when to is before from")
+	}
+	if _, _, err := parseShiftRange("2024-01-01", "2024-06-01"); err == nil {
+		t.Error("expected error for range longer than the limit")
+	}
+	if _, _, err := parseShiftRange("01/01/2024", ""); err == nil {
+		t.Error("expected error for malformed date")
+	}
+}
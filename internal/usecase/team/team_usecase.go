@@ -32,12 +32,38 @@ type UseCase interface {
 
 	// GetContractsByUser returns all contracts a user is assigned to
 	GetContractsByUser(ctx context.Context, userID string) ([]entity.TeamMember, error)
+
+	// ListShifts returns all shifts with optional filters
+	ListShifts(ctx context.Context, filter *entity.TeamShiftFilter) ([]entity.TeamShift, error)
+
+	// GetShiftByID returns a specific shift by ID
+	GetShiftByID(ctx context.Context, id string) (*entity.TeamShift, error)
+
+	// CreateShift schedules a weekly shift for a team member
+	CreateShift(ctx context.Context, req *entity.CreateTeamShiftRequest) (*entity.TeamShift, error)
+
+	// UpdateShift updates an existing shift
+	UpdateShift(ctx context.Context, id string, req *entity.UpdateTeamShiftRequest) (*entity.TeamShift, error)
+
+	// DeleteShift removes a shift
+	DeleteShift(ctx context.Context, id string) error
+
+	// GetShiftConflicts returns agenda events that clash with the shifts of a contract
+	GetShiftConflicts(ctx context.Context, contractID, from, to string) ([]entity.TeamShiftConflict, error)
+
+	// GetCoverageReport returns the daily shift coverage of a contract
+	GetCoverageReport(ctx context.Context, contractID, from, to string) (*entity.TeamCoverageReport, error)
+
+	// GetWorkload returns the open work and commitments of each team member
+	GetWorkload(ctx context.Context, contractID, from, to string) ([]entity.TeamWorkload, error)
 }
 
 type teamUseCase struct {
 	teamRepo     repository.TeamRepository
 	gestorRepo   repository.GestorRepository
 	contratoRepo repository.ContratoRepository
+	shiftRepo    repository.TeamShiftRepository
+	agendaRepo   repository.AgendaRepository
 }
 
 // NewUseCase creates a new team use case
@@ -45,11 +71,15 @@ func NewUseCase(
 	teamRepo repository.TeamRepository,
 	gestorRepo repository.GestorRepository,
 	contratoRepo repository.ContratoRepository,
+	shiftRepo repository.TeamShiftRepository,
+	agendaRepo repository.AgendaRepository,
 ) UseCase {
 	return &teamUseCase{
 		teamRepo:     teamRepo,
 		gestorRepo:   gestorRepo,
 		contratoRepo: contratoRepo,
+		shiftRepo:    shiftRepo,
+		agendaRepo:   agendaRepo,
 	}
 }
 
@@ -0,0 +1,81 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+// GetWorkload returns the open work and commitments of each team member between from and to
+// (YYYY-MM-DD, inclusive; defaults to the next 7 days), least loaded first.
+// Members are scoped to the contract when given, but their workload spans all contracts.
+func (uc *teamUseCase) GetWorkload(ctx context.Context, contractID, from, to string) ([]entity.TeamWorkload, error) {
+	start, end, err := parseShiftRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &entity.TeamWorkloadFilter{From: start, To: end, Now: time.Now()}
+	if contractID != "" {
+		contract, err := uc.contratoRepo.FindByID(ctx, contractID)
+		if err != nil {
+			return nil, err
+		}
+		if contract == nil {
+			return nil, errors.New("contract not found")
+		}
+		filter.ContractID = &contractID
+	}
+
+	workloads, err := uc.teamRepo.FindWorkload(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts, err := uc.shiftRepo.FindAll(ctx, &entity.TeamShiftFilter{IsActive: boolPtr(true)})
+	if err != nil {
+		return nil, err
+	}
+
+	return buildWorkload(workloads, shifts, start, end), nil
+}
+
+// buildWorkload adds shift capacity and totals to the workload rows and sorts them so the
+// least loaded members come first
+func buildWorkload(workloads []entity.TeamWorkload, shifts []entity.TeamShift, start, end time.Time) []entity.TeamWorkload {
+	shiftMinutes := make(map[string]int)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		for i := range shifts {
+			s := &shifts[i]
+			if !s.OccursOn(day) {
+				continue
+			}
+			from, _ := entity.ParseShiftClock(s.StartTime)
+			to, _ := entity.ParseShiftClock(s.EndTime)
+			shiftMinutes[s.UserID] += to - from
+		}
+	}
+
+	result := make([]entity.TeamWorkload, 0, len(workloads))
+	for _, w := range workloads {
+		w.ShiftMinutes = shiftMinutes[w.UserID]
+		w.TotalItems = w.OpenTasks + w.ScheduledInspections + w.AgendaEvents
+		if w.ShiftMinutes > 0 {
+			pct := math.Round(float64(w.AgendaMinutes)/float64(w.ShiftMinutes)*10000) / 100
+			w.AgendaUtilization = &pct
+		}
+		result = append(result, w)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].TotalItems != result[j].TotalItems {
+			return result[i].TotalItems < result[j].TotalItems
+		}
+		return result[i].AgendaMinutes < result[j].AgendaMinutes
+	})
+	return result
+}
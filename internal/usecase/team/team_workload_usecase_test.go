@@ -0,0 +1,41 @@
+package team
+
+import (
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+func TestBuildWorkload(t *testing.T) {
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	shifts := []entity.TeamShift{
+		{ID: "s1", UserID: "u1", Weekday: int(time.Monday), StartTime: "08:00", EndTime: "12:00", IsActive: true},
+		{ID: "s2", UserID: "u1", Weekday: int(time.Tuesday), StartTime: "08:00", EndTime: "12:00", IsActive: true},
+		{ID: "s3", UserID: "u2", Weekday: int(time.Monday), StartTime: "13:00", EndTime: "17:00", IsActive: true},
+	}
+	workloads := []entity.TeamWorkload{
+		{UserID: "u1", UserName: "Ana", OpenTasks: 4, ScheduledInspections: 1, AgendaEvents: 2, AgendaMinutes: 120},
+		{UserID: "u2", UserName: "Bruno", OpenTasks: 1, AgendaEvents: 1, AgendaMinutes: 60},
+		{UserID: "u3", UserName: "Carla"},
+	}
+
+	result := buildWorkload(workloads, shifts, monday, monday.AddDate(0, 0, 7))
+	if len(result) != 3 {
+		t.Fatalf("expected 3 members, got %d", len(result))
+	}
+	if result[0].UserID != "u3" || result[1].UserID != "u2" || result[2].UserID != "u1" {
+		t.Errorf("expected least loaded first, got %s, %s, %s", result[0].UserID, result[1].UserID, result[2].UserID)
+	}
+
+	ana := result[2]
+	if ana.ShiftMinutes != 480 || ana.TotalItems != 7 {
+		t.Errorf("unexpected workload for u1: %+v", ana)
+	}
+	if ana.AgendaUtilization == nil || *ana.AgendaUtilization != 25 {
+		t.Errorf("expected 25%% agenda utilization, got %v", ana.AgendaUtilization)
+	}
+	if result[0].AgendaUtilization != nil {
+		t.Error("expected no utilization for a member without shifts")
+	}
+}
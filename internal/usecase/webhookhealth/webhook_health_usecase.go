@@ -0,0 +1,91 @@
+package webhookhealth
+
+import (
+	"context"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+	"github.com/condotrack/api/internal/domain/repository"
+)
+
+// degradedFailureRate is the share of failed deliveries from which a gateway is degraded
+const degradedFailureRate = 0.1
+
+// UseCase defines the webhook health use case interface
+type UseCase interface {
+	Health(ctx context.Context, since time.Time) (*entity.WebhookHealthReport, error)
+	ListDeliveries(ctx context.Context, filters entity.WebhookDeliveryFilters) ([]entity.WebhookDelivery, int, error)
+}
+
+type webhookHealthUseCase struct {
+	repo     repository.WebhookDeliveryRepository
+	gateways func() []string
+}
+
+// NewUseCase creates a new webhook health use case. gateways lists the registered gateways,
+// which are reported even when none of their webhooks arrived.
+func NewUseCase(repo repository.WebhookDeliveryRepository, gateways func() []string) UseCase {
+	return &webhookHealthUseCase{repo: repo, gateways: gateways}
+}
+
+// Health reports the deliveries of each gateway received since the given time: counts per
+// outcome, latency, the last delivery received, processed and failed, and a status. A gateway
+// is silent without deliveries, failing when nothing was processed since its last failure
+// and degraded when at least 10% of its deliveries failed.
+func (uc *webhookHealthUseCase) Health(ctx context.Context, since time.Time) (*entity.WebhookHealthReport, error) {
+	stats, err := uc.repo.Stats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	byGateway := make(map[string]entity.WebhookGatewayStats, len(stats))
+	for _, s := range stats {
+		byGateway[s.Gateway] = s
+	}
+
+	report := &entity.WebhookHealthReport{Since: since, Gateways: []entity.WebhookHealth{}}
+	seen := make(map[string]bool)
+	for _, name := range uc.gateways() {
+		seen[name] = true
+		s, ok := byGateway[name]
+		if !ok {
+			s = entity.WebhookGatewayStats{Gateway: name}
+		}
+		report.Gateways = append(report.Gateways, health(s))
+	}
+	// Gateways no longer registered still show the deliveries they sent
+	for _, s := range stats {
+		if !seen[s.Gateway] {
+			report.Gateways = append(report.Gateways, health(s))
+		}
+	}
+	return report, nil
+}
+
+// ListDeliveries returns the recent deliveries matching the filters
+func (uc *webhookHealthUseCase) ListDeliveries(ctx context.Context, filters entity.WebhookDeliveryFilters) ([]entity.WebhookDelivery, int, error) {
+	deliveries, total, err := uc.repo.FindAll(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	if deliveries == nil {
+		deliveries = []entity.WebhookDelivery{}
+	}
+	return deliveries, total, nil
+}
+
+func health(s entity.WebhookGatewayStats) entity.WebhookHealth {
+	h := entity.WebhookHealth{WebhookGatewayStats: s, Status: entity.WebhookHealthHealthy}
+	if s.Total == 0 {
+		h.Status = entity.WebhookHealthSilent
+		return h
+	}
+	h.FailureRate = float64(s.Failures()) / float64(s.Total)
+	switch {
+	case s.LastFailureAt != nil && (s.LastProcessedAt == nil || s.LastFailureAt.After(*s.LastProcessedAt)):
+		h.Status = entity.WebhookHealthFailing
+	case h.FailureRate >= degradedFailureRate:
+		h.Status = entity.WebhookHealthDegraded
+	}
+	return h
+}
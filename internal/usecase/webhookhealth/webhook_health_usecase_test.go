@@ -0,0 +1,69 @@
+package webhookhealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/condotrack/api/internal/domain/entity"
+)
+
+type fakeDeliveryRepo struct {
+	stats []entity.WebhookGatewayStats
+	since time.Time
+}
+
+func (r *fakeDeliveryRepo) Create(ctx context.Context, d *entity.WebhookDelivery) error {
+	return nil
+}
+
+func (r *fakeDeliveryRepo) FindAll(ctx context.Context, filters entity.WebhookDeliveryFilters) ([]entity.WebhookDelivery, int, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeDeliveryRepo) Stats(ctx context.Context, since time.Time) ([]entity.WebhookGatewayStats, error) {
+	r.since = since
+	return r.stats, nil
+}
+
+func TestHealth(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	earlier, later := now.Add(-2*time.Hour), now.Add(-time.Hour)
+	repo := &fakeDeliveryRepo{stats: []entity.WebhookGatewayStats{
+		{Gateway: "asaas", Total: 20, Processed: 19, Failed: 1, LastProcessedAt: &later, LastFailureAt: &earlier},
+		{Gateway: "legacy", Total: 3, Processed: 3, LastProcessedAt: &later},
+		{Gateway: "mercadopago", Total: 5, Processed: 2, Rejected: 3, LastProcessedAt: &earlier, LastFailureAt: &later},
+		{Gateway: "mock", Total: 10, Processed: 8, Invalid: 2, LastProcessedAt: &later, LastFailureAt: &earlier},
+	}}
+	uc := NewUseCase(repo, func() []string { return []string{"asaas", "mercadopago", "mock", "pagarme"} })
+
+	since := now.Add(-24 * time.Hour)
+	report, err := uc.Health(context.Background(), since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.since.Equal(since) || !report.Since.Equal(since) {
+		t.Errorf("since = %v, report %v", repo.since, report.Since)
+	}
+
+	want := []struct {
+		gateway, status string
+	}{
+		{"asaas", entity.WebhookHealthHealthy},
+		{"mercadopago", entity.WebhookHealthFailing},
+		{"mock", entity.WebhookHealthDegraded},
+		{"pagarme", entity.WebhookHealthSilent},
+		{"legacy", entity.WebhookHealthHealthy},
+	}
+	if len(report.Gateways) != len(want) {
+		t.Fatalf("gateways = %+v", report.Gateways)
+	}
+	for i, w := range want {
+		if g := report.Gateways[i]; g.Gateway != w.gateway || g.Status != w.status {
+			t.Errorf("gateway %d = %s %s, want %s %s", i, g.Gateway, g.Status, w.gateway, w.status)
+		}
+	}
+	if rate := report.Gateways[2].FailureRate; rate != 0.2 {
+		t.Errorf("mock failure rate = %v", rate)
+	}
+}
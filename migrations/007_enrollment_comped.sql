@@ -0,0 +1,4 @@
+-- Comped (free) seats for corporate/B2B bulk enrollments
+ALTER TABLE enrollments
+    MODIFY COLUMN payment_status ENUM('pending', 'confirmed', 'failed', 'refunded', 'overdue', 'chargeback', 'comped')
+    NOT NULL DEFAULT 'pending';
@@ -0,0 +1,25 @@
+-- Enrollment transfer history (course swaps and corporate seat reassignment)
+CREATE TABLE IF NOT EXISTS enrollment_transfers (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    enrollment_id VARCHAR(36) NOT NULL,
+    transfer_type ENUM('course', 'student', 'course_and_student') NOT NULL,
+    from_student_id VARCHAR(36) NOT NULL,
+    from_student_name VARCHAR(255) NOT NULL,
+    to_student_id VARCHAR(36) NOT NULL,
+    to_student_name VARCHAR(255) NOT NULL,
+    from_course_id VARCHAR(36) NOT NULL,
+    from_course_name VARCHAR(255) NOT NULL,
+    to_course_id VARCHAR(36) NOT NULL,
+    to_course_name VARCHAR(255) NOT NULL,
+    from_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    to_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    price_difference DECIMAL(10,2) NOT NULL DEFAULT 0,
+    revenue_split_id VARCHAR(36) NULL,
+    from_instructor_amount DECIMAL(10,2) NULL,
+    to_instructor_amount DECIMAL(10,2) NULL,
+    reason TEXT NULL,
+    transferred_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_enrollment_transfers_enrollment (enrollment_id),
+    CONSTRAINT fk_enrollment_transfers_enrollment FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,20 @@
+-- Enrollment renewals: discounted re-purchase that extends expiration_date on confirmation
+CREATE TABLE IF NOT EXISTS enrollment_renewals (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    enrollment_id VARCHAR(36) NOT NULL,
+    payment_id VARCHAR(36) NOT NULL,
+    base_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    final_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    extension_days INT NOT NULL DEFAULT 365,
+    previous_expiration DATETIME NULL,
+    new_expiration DATETIME NULL,
+    status ENUM('pending', 'confirmed', 'cancelled') NOT NULL DEFAULT 'pending',
+    confirmed_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_enrollment_renewals_payment (payment_id),
+    INDEX idx_enrollment_renewals_enrollment (enrollment_id, status),
+    CONSTRAINT fk_enrollment_renewals_enrollment FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_enrollment_renewals_payment FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
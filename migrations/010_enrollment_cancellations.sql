@@ -0,0 +1,25 @@
+-- Enrollment cancellations with the refund policy decision (CDC 7-day window, prorated after)
+CREATE TABLE IF NOT EXISTS enrollment_cancellations (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    enrollment_id VARCHAR(36) NOT NULL,
+    payment_id VARCHAR(36) NULL,
+    policy ENUM('full', 'prorated', 'none') NOT NULL,
+    basis VARCHAR(32) NOT NULL,
+    days_since_purchase INT NOT NULL DEFAULT 0,
+    paid_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    refund_percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    refund_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    refund_status ENUM('not_applicable', 'requested') NOT NULL DEFAULT 'not_applicable',
+    gateway_refund_status VARCHAR(50) NULL,
+    revenue_split_id VARCHAR(36) NULL,
+    instructor_clawback DECIMAL(10,2) NOT NULL DEFAULT 0,
+    reason TEXT NULL,
+    cancelled_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_enrollment_cancellations_enrollment (enrollment_id),
+    CONSTRAINT fk_enrollment_cancellations_enrollment FOREIGN KEY (enrollment_id) REFERENCES enrollments(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Revenue splits fully refunded before payout are kept for history as 'reversed'
+ALTER TABLE revenue_splits
+    MODIFY COLUMN status ENUM('pending', 'processed', 'failed', 'reversed') NOT NULL DEFAULT 'pending';
@@ -0,0 +1,22 @@
+-- Periodic supplier evaluations per contract (scores 1-5)
+CREATE TABLE IF NOT EXISTS supplier_evaluations (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    supplier_id VARCHAR(36) NOT NULL,
+    contrato_id VARCHAR(36) NOT NULL,
+    period CHAR(7) NOT NULL,
+    quality_score TINYINT NOT NULL,
+    punctuality_score TINYINT NOT NULL,
+    price_score TINYINT NOT NULL,
+    overall_score DECIMAL(4,2) NOT NULL,
+    comments TEXT NULL,
+    evaluated_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_supplier_evaluations_period (supplier_id, contrato_id, period),
+    INDEX idx_supplier_evaluations_contrato (contrato_id),
+    CONSTRAINT fk_supplier_evaluations_supplier FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE CASCADE,
+    CONSTRAINT fk_supplier_evaluations_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE,
+    CONSTRAINT chk_supplier_evaluations_scores CHECK (
+        quality_score BETWEEN 1 AND 5 AND punctuality_score BETWEEN 1 AND 5 AND price_score BETWEEN 1 AND 5
+    )
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,33 @@
+-- Supplier-contract links per service category and monthly spend entries
+CREATE TABLE IF NOT EXISTS supplier_contracts (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    supplier_id VARCHAR(36) NOT NULL,
+    contrato_id VARCHAR(36) NOT NULL,
+    service_category VARCHAR(100) NOT NULL,
+    monthly_budget DECIMAL(12,2) NULL,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    notes TEXT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_supplier_contracts_link (supplier_id, contrato_id, service_category),
+    INDEX idx_supplier_contracts_contrato (contrato_id),
+    CONSTRAINT fk_supplier_contracts_supplier FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE CASCADE,
+    CONSTRAINT fk_supplier_contracts_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS supplier_spend (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    supplier_contract_id VARCHAR(36) NOT NULL,
+    supplier_id VARCHAR(36) NOT NULL,
+    contrato_id VARCHAR(36) NOT NULL,
+    period CHAR(7) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    description VARCHAR(255) NULL,
+    invoice_number VARCHAR(100) NULL,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_supplier_spend_link (supplier_contract_id, period),
+    INDEX idx_supplier_spend_contrato (contrato_id, period),
+    INDEX idx_supplier_spend_supplier (supplier_id, period),
+    CONSTRAINT fk_supplier_spend_link FOREIGN KEY (supplier_contract_id) REFERENCES supplier_contracts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
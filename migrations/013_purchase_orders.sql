@@ -0,0 +1,40 @@
+-- Supplier purchase orders (draft -> approved -> delivered -> invoiced)
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    number VARCHAR(20) NOT NULL,
+    supplier_id VARCHAR(36) NOT NULL,
+    contrato_id VARCHAR(36) NOT NULL,
+    status ENUM('draft', 'approved', 'delivered', 'invoiced', 'cancelled') NOT NULL DEFAULT 'draft',
+    description TEXT NULL,
+    total_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    expected_delivery_date DATE NULL,
+    created_by VARCHAR(36) NULL,
+    approved_by VARCHAR(36) NULL,
+    approved_at DATETIME NULL,
+    delivered_at DATETIME NULL,
+    invoice_number VARCHAR(100) NULL,
+    invoiced_at DATETIME NULL,
+    cancelled_at DATETIME NULL,
+    cancel_reason TEXT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_purchase_orders_number (number),
+    INDEX idx_purchase_orders_supplier (supplier_id),
+    INDEX idx_purchase_orders_contrato (contrato_id),
+    INDEX idx_purchase_orders_status (status),
+    CONSTRAINT fk_purchase_orders_supplier FOREIGN KEY (supplier_id) REFERENCES suppliers(id),
+    CONSTRAINT fk_purchase_orders_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS purchase_order_items (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    purchase_order_id VARCHAR(36) NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    quantity DECIMAL(12,3) NOT NULL,
+    unit VARCHAR(20) NULL,
+    unit_price DECIMAL(12,2) NOT NULL DEFAULT 0,
+    total_price DECIMAL(12,2) NOT NULL DEFAULT 0,
+    position INT NOT NULL DEFAULT 0,
+    INDEX idx_purchase_order_items_order (purchase_order_id, position),
+    CONSTRAINT fk_purchase_order_items_order FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
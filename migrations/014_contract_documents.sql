@@ -0,0 +1,32 @@
+-- Contract documents (signed contract, addenda, price tables) with version history
+CREATE TABLE IF NOT EXISTS contract_documents (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    contrato_id VARCHAR(36) NOT NULL,
+    document_type ENUM('contract', 'addendum', 'price_table', 'other') NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    current_version INT NOT NULL DEFAULT 1,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_contract_documents_contrato (contrato_id, document_type),
+    CONSTRAINT fk_contract_documents_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS contract_document_versions (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    document_id VARCHAR(36) NOT NULL,
+    version INT NOT NULL,
+    object_key VARCHAR(500) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    effective_from DATE NOT NULL,
+    effective_until DATE NULL,
+    notes TEXT NULL,
+    uploaded_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_contract_document_versions (document_id, version),
+    CONSTRAINT fk_contract_document_versions_document FOREIGN KEY (document_id) REFERENCES contract_documents(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,35 @@
+-- Contract renewal pipeline: renewal records with new terms and expiration alerts
+CREATE TABLE IF NOT EXISTS contract_renewals (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    contrato_id VARCHAR(36) NOT NULL,
+    status ENUM('pending', 'renegotiating', 'renewed', 'lost') NOT NULL DEFAULT 'pending',
+    current_end_date DATE NULL,
+    new_start_date DATE NULL,
+    new_end_date DATE NULL,
+    new_monthly_value DECIMAL(12,2) NULL,
+    adjustment_percent DECIMAL(6,2) NULL,
+    terms TEXT NULL,
+    notes TEXT NULL,
+    created_by VARCHAR(36) NULL,
+    decided_by VARCHAR(36) NULL,
+    decided_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_contract_renewals_contrato (contrato_id, status),
+    INDEX idx_contract_renewals_status (status),
+    CONSTRAINT fk_contract_renewals_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- One row per alert threshold sent for a given contract end date
+CREATE TABLE IF NOT EXISTS contract_renewal_alerts (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    contrato_id VARCHAR(36) NOT NULL,
+    end_date DATE NOT NULL,
+    threshold_days INT NOT NULL,
+    recipients INT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_contract_renewal_alerts (contrato_id, end_date, threshold_days),
+    CONSTRAINT fk_contract_renewal_alerts_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE INDEX idx_contratos_data_fim ON contratos (ativo, data_fim);
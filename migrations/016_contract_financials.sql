@@ -0,0 +1,34 @@
+-- Contract budget lines (planned monthly revenue/cost) and actual costs
+CREATE TABLE IF NOT EXISTS contract_budget_lines (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    contrato_id VARCHAR(36) NOT NULL,
+    kind ENUM('revenue', 'cost') NOT NULL,
+    category VARCHAR(100) NOT NULL,
+    monthly_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    start_period CHAR(7) NOT NULL,
+    end_period CHAR(7) NULL,
+    notes TEXT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_contract_budget_lines_contrato (contrato_id, kind),
+    CONSTRAINT fk_contract_budget_lines_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Supplier spend is read from supplier_spend; this table holds manual costs and task time
+CREATE TABLE IF NOT EXISTS contract_costs (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    contrato_id VARCHAR(36) NOT NULL,
+    source ENUM('manual', 'task_time') NOT NULL DEFAULT 'manual',
+    category VARCHAR(100) NOT NULL,
+    period CHAR(7) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    description VARCHAR(255) NULL,
+    task_id VARCHAR(36) NULL,
+    hours DECIMAL(8,2) NULL,
+    hourly_rate DECIMAL(10,2) NULL,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_contract_costs_contrato (contrato_id, period),
+    INDEX idx_contract_costs_task (task_id),
+    CONSTRAINT fk_contract_costs_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
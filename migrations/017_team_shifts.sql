@@ -0,0 +1,20 @@
+-- Weekly shift schedules of team members per contract
+CREATE TABLE IF NOT EXISTS team_shifts (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    team_member_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    contract_id VARCHAR(36) NOT NULL,
+    weekday TINYINT NOT NULL COMMENT '0 = Sunday ... 6 = Saturday',
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    effective_from DATE NULL,
+    effective_until DATE NULL,
+    notes TEXT NULL,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_team_shifts_contract (contract_id, is_active, weekday),
+    INDEX idx_team_shifts_user (user_id, is_active),
+    CONSTRAINT fk_team_shifts_member FOREIGN KEY (team_member_id) REFERENCES team_members(id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_shifts_contract FOREIGN KEY (contract_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
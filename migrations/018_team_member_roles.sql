@@ -0,0 +1,10 @@
+-- Per-contract team roles. Assignments created before roles had meaning carried free-form
+-- values; they become read-only viewers until a leader or admin sets a real role.
+UPDATE team_members
+SET role = 'viewer'
+WHERE role IS NULL OR role NOT IN ('leader', 'auditor', 'inspector', 'viewer');
+
+ALTER TABLE team_members
+    MODIFY role ENUM('leader', 'auditor', 'inspector', 'viewer') NOT NULL DEFAULT 'viewer';
+
+CREATE INDEX idx_team_members_user_contract ON team_members (user_id, contract_id);
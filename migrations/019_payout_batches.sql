@@ -0,0 +1,28 @@
+-- Instructor payout batches: pending revenue splits grouped into one bank payout
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    instructor_id VARCHAR(36) NOT NULL,
+    status ENUM('scheduled', 'paid', 'cancelled') NOT NULL DEFAULT 'scheduled',
+    payout_date DATE NOT NULL,
+    total_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    split_count INT NOT NULL DEFAULT 0,
+    notes TEXT NULL,
+    payment_reference VARCHAR(255) NULL,
+    receipt_key VARCHAR(500) NULL,
+    receipt_file_name VARCHAR(255) NULL,
+    receipt_content_type VARCHAR(100) NULL,
+    receipt_size BIGINT NULL,
+    created_by VARCHAR(36) NULL,
+    paid_by VARCHAR(36) NULL,
+    paid_at DATETIME NULL,
+    cancelled_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_payout_batches_instructor (instructor_id, payout_date),
+    INDEX idx_payout_batches_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE revenue_splits
+    ADD COLUMN payout_batch_id VARCHAR(36) NULL AFTER status,
+    ADD INDEX idx_revenue_splits_payout_batch (payout_batch_id),
+    ADD INDEX idx_revenue_splits_payable (instructor_id, status, payout_batch_id);
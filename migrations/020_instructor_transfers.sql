@@ -0,0 +1,26 @@
+-- Automatic instructor payouts through gateway transfers
+
+CREATE TABLE IF NOT EXISTS instructor_payout_accounts (
+    instructor_id VARCHAR(36) NOT NULL PRIMARY KEY,
+    method ENUM('wallet', 'pix', 'bank_account') NOT NULL,
+    wallet_id VARCHAR(100) NULL,
+    pix_key VARCHAR(140) NULL,
+    pix_key_type ENUM('CPF', 'CNPJ', 'EMAIL', 'PHONE', 'EVP') NULL,
+    bank_code VARCHAR(10) NULL,
+    bank_agency VARCHAR(10) NULL,
+    bank_account VARCHAR(20) NULL,
+    bank_account_digit VARCHAR(2) NULL,
+    bank_account_type ENUM('CONTA_CORRENTE', 'CONTA_POUPANCA') NULL,
+    owner_name VARCHAR(255) NULL,
+    owner_document VARCHAR(20) NULL,
+    auto_transfer TINYINT(1) NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE revenue_splits
+    ADD COLUMN transfer_id VARCHAR(100) NULL AFTER payout_batch_id,
+    ADD COLUMN transfer_status ENUM('pending', 'processing', 'done', 'failed', 'cancelled') NULL AFTER transfer_id,
+    ADD COLUMN transfer_error VARCHAR(255) NULL AFTER transfer_status,
+    ADD COLUMN transferred_at DATETIME NULL AFTER transfer_error,
+    ADD UNIQUE INDEX idx_revenue_splits_transfer (transfer_id);
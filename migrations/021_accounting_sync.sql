@@ -0,0 +1,28 @@
+-- Accounting system export (Conta Azul / Omie): incremental sync state per layout
+
+CREATE TABLE IF NOT EXISTS accounting_sync_cursors (
+    layout VARCHAR(20) NOT NULL PRIMARY KEY,
+    last_occurred_at DATETIME NULL,
+    last_run_id VARCHAR(36) NULL,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS accounting_sync_runs (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    layout VARCHAR(20) NOT NULL,
+    entry_count INT NOT NULL DEFAULT 0,
+    total_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_accounting_sync_runs_layout (layout, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- One row per entry and layout: an entry is exported at most once to each accounting system
+CREATE TABLE IF NOT EXISTS accounting_exported_entries (
+    layout VARCHAR(20) NOT NULL,
+    entry_key VARCHAR(80) NOT NULL,
+    run_id VARCHAR(36) NOT NULL,
+    exported_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (layout, entry_key),
+    INDEX idx_accounting_exported_entries_run (run_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
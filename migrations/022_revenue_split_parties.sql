@@ -0,0 +1,44 @@
+-- Multi-party revenue splits: ordered split configuration and the share of each party per split
+
+-- Empty table means the REVENUE_INSTRUCTOR_PERCENT / REVENUE_PLATFORM_PERCENT defaults apply
+CREATE TABLE IF NOT EXISTS revenue_split_rules (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    position INT NOT NULL,
+    party_type ENUM('instructor', 'affiliate', 'platform') NOT NULL,
+    party_id VARCHAR(36) NULL,
+    label VARCHAR(100) NULL,
+    percent DECIMAL(7,4) NOT NULL DEFAULT 0,
+    fixed_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_revenue_split_rules_position (position)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS revenue_split_parties (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    split_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL,
+    party_type ENUM('instructor', 'affiliate', 'platform') NOT NULL,
+    party_id VARCHAR(36) NULL,
+    label VARCHAR(100) NULL,
+    percent DECIMAL(7,4) NOT NULL DEFAULT 0,
+    fixed_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_revenue_split_parties_split (split_id, position),
+    INDEX idx_revenue_split_parties_party (party_type, party_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Existing splits become two-party splits so the ledgers cover their history
+INSERT INTO revenue_split_parties (id, split_id, position, party_type, party_id, percent, amount, created_at)
+SELECT UUID(), s.id, 1, 'instructor', s.instructor_id,
+       CASE WHEN s.net_amount > 0 THEN ROUND(s.instructor_amount / s.net_amount * 100, 4) ELSE 0 END,
+       s.instructor_amount, s.created_at
+FROM revenue_splits s
+WHERE NOT EXISTS (SELECT 1 FROM revenue_split_parties p WHERE p.split_id = s.id);
+
+INSERT INTO revenue_split_parties (id, split_id, position, party_type, party_id, percent, amount, created_at)
+SELECT UUID(), s.id, 2, 'platform', NULL,
+       CASE WHEN s.net_amount > 0 THEN ROUND(s.platform_amount / s.net_amount * 100, 4) ELSE 0 END,
+       s.platform_amount, s.created_at
+FROM revenue_splits s
+WHERE NOT EXISTS (SELECT 1 FROM revenue_split_parties p WHERE p.split_id = s.id AND p.party_type = 'platform');
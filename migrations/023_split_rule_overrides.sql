@@ -0,0 +1,7 @@
+-- Per-course and per-instructor revenue split overrides.
+-- A course override wins over an instructor override, which wins over the global configuration.
+ALTER TABLE revenue_split_rules
+    ADD COLUMN scope ENUM('global', 'course', 'instructor') NOT NULL DEFAULT 'global' AFTER id,
+    ADD COLUMN scope_id VARCHAR(36) NOT NULL DEFAULT '' AFTER scope,
+    DROP INDEX idx_revenue_split_rules_position,
+    ADD UNIQUE INDEX idx_revenue_split_rules_scope (scope, scope_id, position);
@@ -0,0 +1,63 @@
+-- Instructor earnings ledger: every movement of the balance owed to an instructor.
+-- Amounts are signed: credits are positive, refunds, chargebacks and payouts negative.
+
+CREATE TABLE IF NOT EXISTS instructor_ledger_entries (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    instructor_id VARCHAR(36) NOT NULL,
+    entry_key VARCHAR(120) NOT NULL,
+    entry_type ENUM('credit', 'refund', 'chargeback', 'payout', 'adjustment') NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    revenue_split_id VARCHAR(36) NULL,
+    payout_batch_id VARCHAR(36) NULL,
+    enrollment_id VARCHAR(36) NULL,
+    description VARCHAR(255) NOT NULL,
+    occurred_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_instructor_ledger_entries_key (entry_key),
+    INDEX idx_instructor_ledger_entries_instructor (instructor_id, occurred_at),
+    INDEX idx_instructor_ledger_entries_split (revenue_split_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Backfill from existing data. Pending splits reduced by a partial refund before this
+-- migration only keep their reduced amount, so they are credited with that amount.
+INSERT IGNORE INTO instructor_ledger_entries (id, instructor_id, entry_key, entry_type, amount,
+    revenue_split_id, enrollment_id, description, occurred_at)
+SELECT UUID(), s.instructor_id, CONCAT('credit:', s.id), 'credit', s.instructor_amount,
+       s.id, s.enrollment_id, CONCAT('Receita - matrícula ', s.enrollment_id), s.created_at
+FROM revenue_splits s
+WHERE s.instructor_id IS NOT NULL AND s.status <> 'failed' AND s.instructor_amount > 0;
+
+-- Cancellations: reversed pending splits give back the whole share, paid out splits the clawback
+INSERT IGNORE INTO instructor_ledger_entries (id, instructor_id, entry_key, entry_type, amount,
+    revenue_split_id, enrollment_id, description, occurred_at)
+SELECT UUID(), s.instructor_id, CONCAT('refund:', c.id), 'refund',
+       -CASE WHEN s.status = 'reversed' THEN s.instructor_amount ELSE c.instructor_clawback END,
+       s.id, s.enrollment_id, CONCAT('Estorno - matrícula ', s.enrollment_id), c.created_at
+FROM enrollment_cancellations c
+INNER JOIN revenue_splits s ON s.id = c.revenue_split_id
+WHERE s.instructor_id IS NOT NULL
+  AND ((s.status = 'reversed' AND s.instructor_amount > 0) OR c.instructor_clawback > 0);
+
+INSERT IGNORE INTO instructor_ledger_entries (id, instructor_id, entry_key, entry_type, amount,
+    revenue_split_id, enrollment_id, description, occurred_at)
+SELECT UUID(), s.instructor_id, CONCAT('chargeback:', p.id), 'chargeback', -s.instructor_amount,
+       s.id, s.enrollment_id, CONCAT('Chargeback - matrícula ', s.enrollment_id), COALESCE(p.updated_at, p.created_at)
+FROM payments p
+INNER JOIN revenue_splits s ON s.payment_id = p.id
+WHERE p.status = 'chargeback' AND s.instructor_id IS NOT NULL AND s.status <> 'failed'
+  AND s.status <> 'reversed' AND s.instructor_amount > 0;
+
+INSERT IGNORE INTO instructor_ledger_entries (id, instructor_id, entry_key, entry_type, amount,
+    payout_batch_id, description, occurred_at)
+SELECT UUID(), b.instructor_id, CONCAT('payout:', b.id), 'payout', -b.total_amount,
+       b.id, CONCAT('Repasse - lote ', b.id), b.paid_at
+FROM payout_batches b
+WHERE b.status = 'paid' AND b.paid_at IS NOT NULL AND b.total_amount > 0;
+
+INSERT IGNORE INTO instructor_ledger_entries (id, instructor_id, entry_key, entry_type, amount,
+    revenue_split_id, enrollment_id, description, occurred_at)
+SELECT UUID(), s.instructor_id, CONCAT('payout:', s.id), 'payout', -s.instructor_amount,
+       s.id, s.enrollment_id, CONCAT('Repasse - matrícula ', s.enrollment_id), s.transferred_at
+FROM revenue_splits s
+WHERE s.transfer_status = 'done' AND s.transferred_at IS NOT NULL AND s.payout_batch_id IS NULL
+  AND s.instructor_id IS NOT NULL AND s.instructor_amount > 0;
@@ -0,0 +1,35 @@
+-- Revenue split adjustments (approved by a second admin) and instructor disputes
+
+CREATE TABLE IF NOT EXISTS revenue_split_disputes (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    split_id VARCHAR(36) NOT NULL,
+    instructor_id VARCHAR(36) NOT NULL,
+    status ENUM('open', 'under_review', 'resolved', 'rejected') NOT NULL DEFAULT 'open',
+    reason TEXT NOT NULL,
+    expected_amount DECIMAL(12,2) NULL,
+    resolution_notes TEXT NULL,
+    resolved_by VARCHAR(36) NULL,
+    resolved_at DATETIME NULL,
+    adjustment_id VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_revenue_split_disputes_split (split_id, status),
+    INDEX idx_revenue_split_disputes_instructor (instructor_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS revenue_split_adjustments (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    split_id VARCHAR(36) NOT NULL,
+    dispute_id VARCHAR(36) NULL,
+    status ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+    reason VARCHAR(500) NOT NULL,
+    previous_instructor_amount DECIMAL(12,2) NOT NULL,
+    instructor_amount DECIMAL(12,2) NOT NULL,
+    requested_by VARCHAR(36) NOT NULL,
+    decided_by VARCHAR(36) NULL,
+    decision_notes VARCHAR(500) NULL,
+    decided_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_revenue_split_adjustments_split (split_id, status),
+    INDEX idx_revenue_split_adjustments_status (status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
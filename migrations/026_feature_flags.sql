@@ -0,0 +1,17 @@
+-- Feature flags: kill switch, audience restrictions (users, roles, orgs) and gradual rollout.
+-- Orgs are contracts (condominiums); the targeting lists are JSON arrays of IDs.
+
+CREATE TABLE IF NOT EXISTS feature_flags (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    flag_key VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NULL,
+    enabled TINYINT(1) NOT NULL DEFAULT 0,
+    rollout_percent TINYINT UNSIGNED NOT NULL DEFAULT 0,
+    user_ids JSON NOT NULL,
+    roles JSON NOT NULL,
+    org_ids JSON NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_feature_flags_key (flag_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
@@ -0,0 +1,12 @@
+-- Secret settings are stored encrypted ("enc:v1:" + base64 AES-GCM), longer than the plaintext
+ALTER TABLE settings MODIFY setting_value TEXT NULL;
+
+UPDATE settings SET is_secret = 1, setting_type = 'secret'
+WHERE setting_key IN ('asaas_api_key', 'asaas_webhook_token', 'gemini_api_key');
+
+-- Mercado Pago access token, editable through /settings like the Asaas ones
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, display_order, created_at)
+SELECT UUID(), 'mercadopago_access_token', NULL, 'secret', 'payment', 'Mercado Pago Access Token',
+       'Token de acesso da API do Mercado Pago', 1, 0, 20, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'mercadopago_access_token');
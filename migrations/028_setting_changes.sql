@@ -0,0 +1,15 @@
+-- Settings change history: every write through the settings API records the previous and
+-- new value and who made it, so a key can be rolled back. Secret values are stored as kept in
+-- settings (encrypted).
+
+CREATE TABLE IF NOT EXISTS setting_changes (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    setting_key VARCHAR(100) NOT NULL,
+    old_value TEXT NULL,
+    new_value TEXT NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    changed_by VARCHAR(36) NULL,
+    rollback_of VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_setting_changes_key (setting_key, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
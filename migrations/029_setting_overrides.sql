@@ -0,0 +1,21 @@
+-- Scoped settings: a setting can be overridden for an organization (the gestor managing the
+-- contracts) or a single contract. Values resolve contract, then organization, then global.
+-- Changes of overrides are recorded in setting_changes with their scope; removing an override
+-- records a NULL new value.
+
+CREATE TABLE IF NOT EXISTS setting_overrides (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    setting_key VARCHAR(100) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    scope_id VARCHAR(36) NOT NULL,
+    setting_value TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_setting_overrides_scope (setting_key, scope, scope_id),
+    INDEX idx_setting_overrides_scope_id (scope, scope_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE setting_changes
+    ADD COLUMN scope VARCHAR(20) NOT NULL DEFAULT 'global' AFTER setting_key,
+    ADD COLUMN scope_id VARCHAR(36) NULL AFTER scope,
+    MODIFY new_value TEXT NULL;
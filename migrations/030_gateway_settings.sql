@@ -0,0 +1,33 @@
+-- Payment gateway settings, reloaded at runtime: the default gateway, whether each gateway
+-- takes new payments and the remaining Mercado Pago credentials. Empty values fall back to
+-- the environment (DEFAULT_PAYMENT_GATEWAY, MERCADOPAGO_*).
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'payment_default_gateway', NULL, 'string', 'payment', 'Gateway padrão',
+       'Gateway usado em novos pagamentos (asaas ou mercadopago)', 0, 0, '^(asaas|mercadopago)$', 1, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'payment_default_gateway');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'asaas_enabled', NULL, 'boolean', 'payment', 'Asaas ativo',
+       'Aceita novos pagamentos pelo Asaas', 0, 0, '^(true|false)$', 2, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'asaas_enabled');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'mercadopago_enabled', NULL, 'boolean', 'payment', 'Mercado Pago ativo',
+       'Aceita novos pagamentos pelo Mercado Pago', 0, 0, '^(true|false)$', 19, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'mercadopago_enabled');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'mercadopago_env', NULL, 'string', 'payment', 'Ambiente Mercado Pago',
+       'sandbox ou production', 0, 0, '^(sandbox|production)$', 21, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'mercadopago_env');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'mercadopago_webhook_secret', NULL, 'secret', 'payment', 'Mercado Pago Webhook Secret',
+       'Segredo usado para validar a assinatura dos webhooks do Mercado Pago', 1, 0, NULL, 22, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'mercadopago_webhook_secret');
@@ -0,0 +1,37 @@
+-- AI provider metering: one row per provider call, including failed attempts that fell back
+-- to the next provider. Also seeds the OpenAI and Anthropic keys and the provider order.
+
+CREATE TABLE IF NOT EXISTS ai_usage (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    provider VARCHAR(30) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    feature VARCHAR(50) NOT NULL,
+    user_id VARCHAR(36) NULL,
+    input_tokens INT NOT NULL DEFAULT 0,
+    output_tokens INT NOT NULL DEFAULT 0,
+    latency_ms INT NOT NULL DEFAULT 0,
+    success TINYINT(1) NOT NULL DEFAULT 1,
+    error_message VARCHAR(500) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_ai_usage_created (created_at),
+    INDEX idx_ai_usage_provider (provider, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'openai_api_key', NULL, 'secret', 'ai', 'OpenAI API Key',
+       'Chave da API da OpenAI', 1, 0, NULL, 3, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'openai_api_key');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'anthropic_api_key', NULL, 'secret', 'ai', 'Anthropic API Key',
+       'Chave da API da Anthropic', 1, 0, NULL, 4, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'anthropic_api_key');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'ai_providers', NULL, 'string', 'ai', 'Ordem dos provedores de IA',
+       'Provedores tentados em ordem, separados por vírgula (gemini, openai, anthropic)', 0, 0,
+       '^(gemini|openai|anthropic)(,(gemini|openai|anthropic))*$', 5, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'ai_providers');
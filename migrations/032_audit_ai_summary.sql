@@ -0,0 +1,5 @@
+-- AI executive summary cached on the audit. ai_summary_hash identifies the audit data the
+-- summary was generated from; a different hash means the summary is stale.
+ALTER TABLE audits
+    ADD COLUMN ai_summary JSON NULL AFTER data_json,
+    ADD COLUMN ai_summary_hash VARCHAR(64) NULL AFTER ai_summary;
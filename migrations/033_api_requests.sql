@@ -0,0 +1,20 @@
+-- Audit log of mutating API requests (POST, PUT, PATCH, DELETE) for troubleshooting and
+-- compliance. Bodies are stored with credentials and card data redacted.
+
+CREATE TABLE IF NOT EXISTS api_requests (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(500) NOT NULL,
+    route VARCHAR(255) NULL,
+    actor_id VARCHAR(36) NULL,
+    actor_role VARCHAR(20) NULL,
+    status SMALLINT NOT NULL,
+    latency_ms INT NOT NULL DEFAULT 0,
+    client_ip VARCHAR(45) NOT NULL,
+    request_id VARCHAR(100) NULL,
+    body MEDIUMTEXT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_api_requests_created (created_at),
+    INDEX idx_api_requests_actor (actor_id, created_at),
+    INDEX idx_api_requests_path (path(100), created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+-- IP allowlists (comma separated CIDR ranges or addresses) for the user administration and
+-- settings routes. Empty values allow every client.
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'ip_allowlist_users', NULL, 'string', 'security', 'IPs permitidos - usuários',
+       'Faixas CIDR (ou IPs) separadas por vírgula com acesso a /api/v1/auth/users; vazio libera todos', 0, 0,
+       NULL, 1, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'ip_allowlist_users');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'ip_allowlist_settings', NULL, 'string', 'security', 'IPs permitidos - configurações',
+       'Faixas CIDR (ou IPs) separadas por vírgula com acesso a /api/v1/settings; vazio libera todos', 0, 0,
+       NULL, 2, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'ip_allowlist_settings');
@@ -0,0 +1,17 @@
+-- Responses of requests sent with an Idempotency-Key header, replayed when a client retries
+-- the same request. Keys are scoped by route and user and expire after 24 hours.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id CHAR(64) NOT NULL PRIMARY KEY,
+    idempotency_key VARCHAR(255) NOT NULL,
+    user_id VARCHAR(36) NULL,
+    route VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code SMALLINT NULL,
+    content_type VARCHAR(100) NULL,
+    response_body MEDIUMTEXT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at DATETIME NULL,
+    expires_at DATETIME NOT NULL,
+    INDEX idx_idempotency_keys_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,21 @@
+-- Metadata of the files in the evidence bucket: tags, description, capture date and the
+-- audit, inspection or task they document. The object itself stays in MinIO under filename.
+
+CREATE TABLE IF NOT EXISTS evidence_files (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL,
+    original_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    description TEXT NULL,
+    tags JSON NOT NULL,
+    captured_at DATETIME NULL,
+    entity_type ENUM('audit', 'inspection', 'task') NULL,
+    entity_id VARCHAR(36) NULL,
+    uploaded_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_evidence_files_filename (filename),
+    INDEX idx_evidence_files_entity (entity_type, entity_id),
+    INDEX idx_evidence_files_captured (captured_at),
+    INDEX idx_evidence_files_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,27 @@
+-- Slots of the branded portal images. Uploads with a slot ID overwrite its file in the portal
+-- bucket, and system images cannot be deleted through the portal image endpoints.
+
+CREATE TABLE IF NOT EXISTS system_images (
+    id VARCHAR(50) NOT NULL PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL,
+    description VARCHAR(255) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_system_images_filename (filename)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Slots previously hard-coded in the portal handler
+INSERT IGNORE INTO system_images (id, filename, description) VALUES
+    ('sys_logo', 'logo-condotrack.png', 'Logo CondoTrack'),
+    ('sys_prof', 'professor-sergio.png', 'Foto do professor'),
+    ('sys_prof_mini', 'prof-sergio.png', 'Foto do professor (miniatura)'),
+    ('pos_1', 'step1.png', 'Pós-venda: passo 1'),
+    ('pos_2', 'step2.png', 'Pós-venda: passo 2'),
+    ('pos_3', 'step3.png', 'Pós-venda: passo 3'),
+    ('pos_4', 'step4.png', 'Pós-venda: passo 4'),
+    ('pos_5', 'step5.png', 'Pós-venda: passo 5'),
+    ('pos_6', 'step6.png', 'Pós-venda: passo 6'),
+    ('pos_7', 'step7.png', 'Pós-venda: passo 7'),
+    ('padlet_bg', 'padlet_bg.png', 'Padlet: fundo'),
+    ('padlet_cover', 'padlet-cover-v2.png', 'Padlet: capa'),
+    ('padlet_comm', 'padlet-comunidade.png', 'Padlet: comunidade');
@@ -0,0 +1,13 @@
+-- Daily request counts of the deprecated /backend_integration/api_router.php endpoints, used
+-- to plan the shutdown of the legacy router.
+
+CREATE TABLE IF NOT EXISTS legacy_usage (
+    day DATE NOT NULL,
+    endpoint VARCHAR(50) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    requests INT NOT NULL DEFAULT 0,
+    errors INT NOT NULL DEFAULT 0,
+    last_user_id VARCHAR(36) NULL,
+    last_seen_at DATETIME NOT NULL,
+    PRIMARY KEY (day, endpoint, method)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,18 @@
+-- Accounting period close: journal exports generated in the background and kept in storage
+
+CREATE TABLE IF NOT EXISTS accounting_close_jobs (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    period CHAR(7) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    status ENUM('pending', 'running', 'done', 'failed') NOT NULL DEFAULT 'pending',
+    file_key VARCHAR(255) NULL,
+    line_count INT NOT NULL DEFAULT 0,
+    total_amount DECIMAL(14,2) NOT NULL DEFAULT 0,
+    error VARCHAR(500) NULL,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at DATETIME NULL,
+    finished_at DATETIME NULL,
+    INDEX idx_accounting_close_jobs_status (status, created_at),
+    INDEX idx_accounting_close_jobs_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,54 @@
+-- Contract billing: monthly fee plan of each contract and the receivables it generates
+
+CREATE TABLE IF NOT EXISTS contract_billing_plans (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    contrato_id VARCHAR(36) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    monthly_amount DECIMAL(12,2) NOT NULL,
+    due_day TINYINT NOT NULL,
+    billing_type ENUM('boleto', 'pix') NOT NULL DEFAULT 'boleto',
+    payer_name VARCHAR(255) NOT NULL,
+    payer_document VARCHAR(20) NOT NULL,
+    payer_email VARCHAR(255) NOT NULL,
+    payer_phone VARCHAR(20) NULL,
+    gateway VARCHAR(50) NULL,
+    gateway_customer_id VARCHAR(100) NULL,
+    start_period CHAR(7) NOT NULL,
+    end_period CHAR(7) NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_contract_billing_plans_contrato (contrato_id),
+    CONSTRAINT fk_contract_billing_plans_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- One charge per contract and month; the unique key keeps concurrent runs from billing twice
+CREATE TABLE IF NOT EXISTS contract_charges (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    plan_id VARCHAR(36) NOT NULL,
+    contrato_id VARCHAR(36) NOT NULL,
+    period CHAR(7) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    due_date DATE NOT NULL,
+    billing_type ENUM('boleto', 'pix') NOT NULL,
+    status ENUM('pending', 'open', 'overdue', 'paid', 'cancelled', 'refunded', 'failed') NOT NULL DEFAULT 'pending',
+    gateway VARCHAR(50) NULL,
+    gateway_payment_id VARCHAR(100) NULL,
+    invoice_url VARCHAR(500) NULL,
+    boleto_url VARCHAR(500) NULL,
+    pix_copy_paste TEXT NULL,
+    paid_amount DECIMAL(12,2) NULL,
+    gateway_fee DECIMAL(12,2) NOT NULL DEFAULT 0,
+    paid_at DATETIME NULL,
+    error VARCHAR(500) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_contract_charges_period (contrato_id, period),
+    INDEX idx_contract_charges_status (status, due_date),
+    INDEX idx_contract_charges_gateway (gateway, gateway_payment_id),
+    INDEX idx_contract_charges_paid (paid_at),
+    CONSTRAINT fk_contract_charges_plan FOREIGN KEY (plan_id) REFERENCES contract_billing_plans(id) ON DELETE CASCADE,
+    CONSTRAINT fk_contract_charges_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+-- Gateway webhook events already processed. Gateways redeliver events they consider
+-- unacknowledged, so a confirmation is recorded in the same transaction as its revenue split
+-- and a second delivery of the event is skipped.
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    gateway VARCHAR(50) NOT NULL,
+    gateway_event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    gateway_payment_id VARCHAR(255) NOT NULL,
+    processed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (gateway, gateway_event_id),
+    INDEX idx_webhook_events_payment (gateway, gateway_payment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,19 @@
+-- Refresh tokens exchanged at POST /api/v1/auth/refresh for a new access token. Only the
+-- SHA-256 hash of a token is stored. Each exchange revokes the token and issues a new one of
+-- the same family; presenting a rotated token again revokes the whole family. Revoked tokens
+-- are kept until they expire so a reuse is still recognised.
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    family_id VARCHAR(36) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME NULL,
+    replaced_by VARCHAR(36) NULL,
+    UNIQUE KEY uk_refresh_tokens_hash (token_hash),
+    INDEX idx_refresh_tokens_family (family_id),
+    INDEX idx_refresh_tokens_user (user_id),
+    INDEX idx_refresh_tokens_expires (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+-- Gateway customers by payer document (CPF or CNPJ, digits only), one per gateway. Checkout
+-- looks the payer up here before creating a customer, so repeat purchases reuse it. Existing
+-- payments are backfilled at startup.
+
+CREATE TABLE IF NOT EXISTS gateway_customers (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    gateway VARCHAR(50) NOT NULL,
+    document VARCHAR(14) NOT NULL,
+    user_id VARCHAR(36) NULL,
+    gateway_customer_id VARCHAR(100) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_gateway_customers_document (gateway, document),
+    INDEX idx_gateway_customers_user (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
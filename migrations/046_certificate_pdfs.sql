@@ -0,0 +1,4 @@
+-- Rendered certificate PDFs. The PDF is generated when the certificate is issued, or on the
+-- first detail request for older certificates, and stored in the certificates bucket.
+ALTER TABLE certificates
+    ADD COLUMN pdf_key VARCHAR(255) NULL AFTER download_url;
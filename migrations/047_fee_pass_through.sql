@@ -0,0 +1,12 @@
+-- Fee pass-through pricing: when enabled, checkout adds the gateway fee of the payment method
+-- to what the buyer pays. The surcharge and the line items of the charge are kept on the
+-- payment. Empty setting values fall back to CHECKOUT_FEE_PASS_THROUGH.
+ALTER TABLE payments
+    ADD COLUMN fee_surcharge DECIMAL(10,2) NOT NULL DEFAULT 0.00 AFTER gateway_fee,
+    ADD COLUMN line_items JSON NULL AFTER gateway_metadata;
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'checkout_fee_pass_through', NULL, 'boolean', 'payment', 'Repassar taxas ao comprador',
+       'Soma a taxa do gateway de cada forma de pagamento ao valor cobrado', 0, 0, '^(true|false)$', 30, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'checkout_fee_pass_through');
@@ -0,0 +1,11 @@
+-- Card installment settlements: the gateway confirms and settles each installment of a card
+-- charge as its own payment, grouped under an installment plan ID. The plan ID is kept on the
+-- payment so later installments find it, and each settled installment gets its own revenue
+-- split, numbered from 1.
+ALTER TABLE payments
+    ADD COLUMN gateway_installment_id VARCHAR(100) NULL AFTER gateway_customer_id,
+    ADD INDEX idx_payments_gateway_installment (gateway, gateway_installment_id);
+
+ALTER TABLE revenue_splits
+    ADD COLUMN installment_number INT NULL AFTER payment_id,
+    ADD UNIQUE INDEX idx_revenue_splits_installment (payment_id, installment_number);
@@ -0,0 +1,10 @@
+-- Soft delete for contratos and gestores: audits, tasks and inspections keep referencing a
+-- deleted row, so DELETE only stamps deleted_at. Deleted rows are left out of the listings
+-- and lookups and can be restored from the trash by an admin.
+ALTER TABLE contratos
+    ADD COLUMN deleted_at DATETIME NULL AFTER updated_at,
+    ADD INDEX idx_contratos_deleted_at (deleted_at);
+
+ALTER TABLE gestores
+    ADD COLUMN deleted_at DATETIME NULL AFTER updated_at,
+    ADD INDEX idx_gestores_deleted_at (deleted_at);
@@ -0,0 +1,21 @@
+-- Daily KPI snapshots per contract: the audit score, open tasks and inspection compliance as
+-- measured on each day. Trend queries read the history instead of recomputing it, and later
+-- edits to audits or tasks leave past snapshots untouched; only the current day is refreshed.
+CREATE TABLE IF NOT EXISTS contract_kpi_snapshots (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    contrato_id VARCHAR(36) NOT NULL,
+    snapshot_date DATE NOT NULL,
+    audit_count INT NOT NULL DEFAULT 0,
+    average_audit_score DECIMAL(5,2) NULL,
+    last_audit_score DECIMAL(5,2) NULL,
+    open_tasks INT NOT NULL DEFAULT 0,
+    overdue_tasks INT NOT NULL DEFAULT 0,
+    inspections_due INT NOT NULL DEFAULT 0,
+    inspections_completed INT NOT NULL DEFAULT 0,
+    inspection_compliance_rate DECIMAL(5,2) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_contract_kpi_snapshots_day (contrato_id, snapshot_date),
+    INDEX idx_contract_kpi_snapshots_date (snapshot_date),
+    CONSTRAINT fk_contract_kpi_snapshots_contrato FOREIGN KEY (contrato_id) REFERENCES contratos(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
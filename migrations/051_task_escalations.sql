@@ -0,0 +1,19 @@
+-- Escalation of overdue tasks: once a task is overdue past the first threshold the contract
+-- gestor is notified, past the second the admins. The level reached is kept on the task so a
+-- level is never notified twice, and every escalation is logged for the per-contract report,
+-- which outlives the task. Moving the due date resets the level.
+ALTER TABLE tasks
+    ADD COLUMN escalation_level TINYINT NOT NULL DEFAULT 0 AFTER completed_at,
+    ADD COLUMN escalated_at DATETIME NULL AFTER escalation_level;
+
+CREATE TABLE IF NOT EXISTS task_escalations (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    task_id VARCHAR(36) NOT NULL,
+    contract_id VARCHAR(36) NULL,
+    level TINYINT NOT NULL,
+    overdue_hours INT NOT NULL,
+    recipients INT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_task_escalations_task (task_id),
+    INDEX idx_task_escalations_contract (contract_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
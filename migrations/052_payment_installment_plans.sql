@@ -0,0 +1,5 @@
+-- Boleto installment plans (carnê): checkout charges each installment as a boleto of its own,
+-- stored as a child payment (installment_of = parent payment ID, installment_number from 1).
+-- The parent payment keeps the totals of the plan; the children are read by the parent ID.
+ALTER TABLE payments
+    ADD INDEX idx_payments_installment_of (installment_of, installment_number);
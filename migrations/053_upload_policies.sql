@@ -0,0 +1,51 @@
+-- Accepted content types and maximum size per upload context, as JSON
+-- ({"max_size": <bytes>, "allowed_types": ["image/jpeg", ...]}). Empty values keep the defaults.
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'upload_policy_evidence', NULL, 'json', 'storage', 'Uploads - evidências',
+       'Tipos MIME e tamanho máximo (bytes) das evidências de auditoria; vazio usa o padrão', 0, 0,
+       NULL, 1, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'upload_policy_evidence');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'upload_policy_inspection', NULL, 'json', 'storage', 'Uploads - fotos de vistoria',
+       'Tipos MIME e tamanho máximo (bytes) das evidências de vistorias; vazio usa o padrão', 0, 0,
+       NULL, 2, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'upload_policy_inspection');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'upload_policy_task', NULL, 'json', 'storage', 'Uploads - anexos de tarefas',
+       'Tipos MIME e tamanho máximo (bytes) dos anexos de tarefas; vazio usa o padrão', 0, 0,
+       NULL, 3, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'upload_policy_task');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'upload_policy_portal', NULL, 'json', 'storage', 'Uploads - imagens do portal',
+       'Tipos MIME e tamanho máximo (bytes) das imagens do portal; vazio usa o padrão', 0, 0,
+       NULL, 4, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'upload_policy_portal');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'upload_policy_image', NULL, 'json', 'storage', 'Uploads - biblioteca de imagens',
+       'Tipos MIME e tamanho máximo (bytes) da biblioteca de imagens; vazio usa o padrão', 0, 0,
+       NULL, 5, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'upload_policy_image');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'upload_policy_contract_document', NULL, 'json', 'storage', 'Uploads - documentos de contrato',
+       'Tipos MIME e tamanho máximo (bytes) dos documentos de contrato; vazio usa o padrão', 0, 0,
+       NULL, 6, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'upload_policy_contract_document');
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'upload_policy_payout_receipt', NULL, 'json', 'storage', 'Uploads - comprovantes de repasse',
+       'Tipos MIME e tamanho máximo (bytes) dos comprovantes de repasse; vazio usa o padrão', 0, 0,
+       NULL, 7, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'upload_policy_payout_receipt');
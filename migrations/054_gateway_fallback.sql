@@ -0,0 +1,8 @@
+-- Gateway that new checkout charges move to when the default gateway fails them, reloaded at
+-- runtime. Empty falls back to FALLBACK_PAYMENT_GATEWAY; "none" turns the fallback off.
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'payment_fallback_gateway', NULL, 'string', 'payment', 'Gateway de contingência',
+       'Gateway que recebe as cobranças recusadas pelo gateway padrão (asaas, mercadopago ou none)', 0, 0, '^(asaas|mercadopago|none)$', 3, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'payment_fallback_gateway');
@@ -0,0 +1,6 @@
+-- FULLTEXT indexes of the global search (GET /api/v1/search). The column lists must match the
+-- ones searched in search_mysql.go; while an index is missing its type is searched with LIKE.
+ALTER TABLE contratos ADD FULLTEXT INDEX ft_contratos_search (nome, descricao, cidade);
+ALTER TABLE suppliers ADD FULLTEXT INDEX ft_suppliers_search (name, category, notes);
+ALTER TABLE tasks ADD FULLTEXT INDEX ft_tasks_search (title, description);
+ALTER TABLE gestores ADD FULLTEXT INDEX ft_gestores_search (nome, email);
@@ -0,0 +1,18 @@
+-- Every webhook delivery received from a payment gateway, whatever its outcome, for the
+-- webhook health dashboard: a gateway whose deliveries stop arriving or start failing
+-- (e.g. a rotated token) shows up before payments pile up unconfirmed. webhook_events
+-- only keeps the events that changed our data.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    gateway VARCHAR(30) NOT NULL,
+    event_type VARCHAR(60) NULL,
+    gateway_event_id VARCHAR(100) NULL,
+    gateway_payment_id VARCHAR(100) NULL,
+    outcome VARCHAR(20) NOT NULL,
+    status SMALLINT NOT NULL,
+    latency_ms INT NOT NULL DEFAULT 0,
+    error VARCHAR(500) NULL,
+    received_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_webhook_deliveries_gateway (gateway, received_at),
+    INDEX idx_webhook_deliveries_outcome (outcome, received_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,20 @@
+-- Changes made through the API, one row per successful authenticated POST/PUT/PATCH/DELETE:
+-- who (user, role and IP) changed which entity and the fields submitted. api_requests keeps
+-- every mutating request, failed ones included, for troubleshooting; activity_logs answers
+-- "who changed this contrato" by entity.
+CREATE TABLE IF NOT EXISTS activity_logs (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    user_id VARCHAR(36) NULL,
+    user_role VARCHAR(20) NULL,
+    action VARCHAR(10) NOT NULL,
+    entity VARCHAR(60) NOT NULL,
+    entity_id VARCHAR(100) NULL,
+    route VARCHAR(255) NOT NULL,
+    diff JSON NULL,
+    ip_address VARCHAR(45) NOT NULL,
+    request_id VARCHAR(100) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_activity_logs_user (user_id, created_at),
+    INDEX idx_activity_logs_entity (entity, entity_id, created_at),
+    INDEX idx_activity_logs_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
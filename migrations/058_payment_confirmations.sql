@@ -0,0 +1,17 @@
+-- Payments an admin confirmed by hand, e.g. a bank transfer made outside the gateway: the
+-- reason, the proof of payment and who confirmed it. The confirmation itself goes through
+-- the webhook pipeline (payment, enrollment, revenue split, payment_transactions).
+CREATE TABLE IF NOT EXISTS payment_confirmations (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    payment_id VARCHAR(36) NOT NULL,
+    reason TEXT NOT NULL,
+    payment_reference VARCHAR(255) NULL,
+    proof_key VARCHAR(500) NOT NULL,
+    proof_file_name VARCHAR(255) NOT NULL,
+    proof_content_type VARCHAR(100) NOT NULL,
+    proof_size BIGINT NOT NULL,
+    paid_at DATETIME NOT NULL,
+    confirmed_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_payment_confirmations_payment (payment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,21 @@
+-- Compliance documents of suppliers (licenses, insurance, tax clearances, certifications) with their expiry
+CREATE TABLE IF NOT EXISTS supplier_documents (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    supplier_id VARCHAR(36) NOT NULL,
+    document_type ENUM('license', 'insurance', 'tax_clearance', 'certification', 'other') NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    number VARCHAR(100) NULL,
+    issued_at DATE NULL,
+    expires_at DATE NULL,
+    notes TEXT NULL,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_supplier_documents_supplier (supplier_id, document_type),
+    INDEX idx_supplier_documents_expires (is_active, expires_at),
+    CONSTRAINT fk_supplier_documents_supplier FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Contract documents expiring soon are found by the end of their current version
+CREATE INDEX idx_contract_document_versions_until ON contract_document_versions (effective_until);
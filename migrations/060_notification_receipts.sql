@@ -0,0 +1,23 @@
+-- Read receipts per channel: in-app reads stay on notifications.read_at, e-mail opens (a
+-- signed tracking pixel) and push deliveries confirmed by the service worker are kept on the
+-- delivery of the channel. Broadcasts send one notification to many users; their
+-- notifications carry the broadcast ID so the engagement is aggregated per broadcast.
+ALTER TABLE notification_deliveries
+    ADD COLUMN delivered_at DATETIME NULL AFTER sent_at,
+    ADD COLUMN opened_at DATETIME NULL AFTER delivered_at;
+
+ALTER TABLE notifications
+    ADD COLUMN broadcast_id VARCHAR(36) NULL AFTER data,
+    ADD INDEX idx_notifications_broadcast (broadcast_id);
+
+CREATE TABLE IF NOT EXISTS notification_broadcasts (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    target_role VARCHAR(20) NULL,
+    recipients INT NOT NULL DEFAULT 0,
+    created_by VARCHAR(36) NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notification_broadcasts_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
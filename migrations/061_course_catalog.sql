@@ -0,0 +1,17 @@
+-- Instructor bios shown on the public course catalog
+ALTER TABLE users ADD COLUMN bio TEXT NULL AFTER avatar_url;
+
+-- Ratings students give the courses they are enrolled in, one per student and course
+CREATE TABLE IF NOT EXISTS course_ratings (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    course_id VARCHAR(36) NOT NULL,
+    student_id VARCHAR(36) NOT NULL,
+    enrollment_id VARCHAR(36) NOT NULL,
+    rating TINYINT NOT NULL,
+    comment TEXT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NULL ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_course_ratings_student (course_id, student_id),
+    INDEX idx_course_ratings_enrollment (enrollment_id),
+    CONSTRAINT chk_course_ratings_rating CHECK (rating BETWEEN 1 AND 5)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+-- Checkout sessions of embedded checkouts: the course and price a landing page opened a
+-- checkout for, and the enrollment it was paid with
+CREATE TABLE IF NOT EXISTS checkout_sessions (
+    id VARCHAR(36) NOT NULL PRIMARY KEY,
+    course_id VARCHAR(36) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    discount_code VARCHAR(50) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    enrollment_id VARCHAR(36) NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at DATETIME NULL,
+    INDEX idx_checkout_sessions_status_expires (status, expires_at),
+    INDEX idx_checkout_sessions_enrollment (enrollment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
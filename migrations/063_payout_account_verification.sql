@@ -0,0 +1,14 @@
+-- Verification of instructor payout accounts, by the gateway or a micro-deposit the
+-- instructor confirms; payouts only go to verified accounts
+ALTER TABLE instructor_payout_accounts
+    ADD COLUMN verification_status ENUM('unverified', 'pending', 'verified', 'failed') NOT NULL DEFAULT 'unverified' AFTER auto_transfer,
+    ADD COLUMN verification_method ENUM('gateway', 'micro_deposit') NULL AFTER verification_status,
+    ADD COLUMN micro_deposit_amount DECIMAL(10,2) NULL AFTER verification_method,
+    ADD COLUMN micro_deposit_transfer_id VARCHAR(100) NULL AFTER micro_deposit_amount,
+    ADD COLUMN verification_attempts INT NOT NULL DEFAULT 0 AFTER micro_deposit_transfer_id,
+    ADD COLUMN verification_error VARCHAR(255) NULL AFTER verification_attempts,
+    ADD COLUMN verified_at DATETIME NULL AFTER verification_error;
+
+-- Accounts registered before verification existed were already paid out to
+UPDATE instructor_payout_accounts
+SET verification_status = 'verified', verified_at = COALESCE(updated_at, created_at);
@@ -0,0 +1,3 @@
+-- Unpaid PIX payments are cancelled once their QR code expires; the sweep looks them up
+-- by method, status and expiration
+CREATE INDEX idx_payments_pix_expiration ON payments (payment_method, status, expires_at);
@@ -0,0 +1,11 @@
+-- Links each gestor record to the user account that logs in as that gestor, so ownership
+-- checks compare gestores.user_id, not gestores.id, with the user of the token
+ALTER TABLE gestores
+    ADD COLUMN user_id VARCHAR(36) NULL AFTER id,
+    ADD UNIQUE INDEX uq_gestores_user_id (user_id);
+
+-- Gestores were registered with the e-mail of their account
+UPDATE gestores g
+JOIN users u ON u.email = g.email AND u.role = 'gestor'
+SET g.user_id = u.id
+WHERE g.user_id IS NULL;
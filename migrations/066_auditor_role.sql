@@ -0,0 +1,4 @@
+-- Auditors run audits on contracts without managing them (see entity.RolePermission).
+-- The ENUM keeps every existing role.
+ALTER TABLE users
+    MODIFY role ENUM('admin', 'gestor', 'supervisor', 'zelador', 'manutencao', 'asg', 'student', 'instructor', 'auditor') NOT NULL DEFAULT 'student';
@@ -0,0 +1,8 @@
+-- A cancellation claims its enrollment as 'cancelling' before the refund goes to the gateway,
+-- so a concurrent or repeated cancel cannot refund the same payment twice
+ALTER TABLE enrollments
+    MODIFY COLUMN status ENUM('pending', 'active', 'completed', 'cancelled', 'cancelling', 'expired') NOT NULL DEFAULT 'pending';
+
+-- Refunds refused by the gateway release the enrollment and stay recorded as 'failed'
+ALTER TABLE enrollment_cancellations
+    MODIFY COLUMN refund_status ENUM('not_applicable', 'requested', 'failed') NOT NULL DEFAULT 'not_applicable';
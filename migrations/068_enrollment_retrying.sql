@@ -0,0 +1,4 @@
+-- A checkout retry claims its enrollment as 'retrying' while the new charge is created on the
+-- gateway, so two concurrent retries cannot both charge the student
+ALTER TABLE enrollments
+    MODIFY COLUMN status ENUM('pending', 'active', 'completed', 'cancelled', 'cancelling', 'expired', 'retrying') NOT NULL DEFAULT 'pending';
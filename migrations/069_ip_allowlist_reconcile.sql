@@ -0,0 +1,9 @@
+-- IP allowlist (comma separated CIDR ranges or addresses) for the payment reconciliation route.
+-- Empty values allow every client.
+
+INSERT INTO settings (id, setting_key, setting_value, setting_type, category, label, description,
+    is_secret, is_required, validation_regex, display_order, created_at)
+SELECT UUID(), 'ip_allowlist_reconcile', NULL, 'string', 'security', 'IPs permitidos - conciliação',
+       'Faixas CIDR (ou IPs) separadas por vírgula com acesso a /api/v1/payments/reconcile; vazio libera todos', 0, 0,
+       NULL, 3, NOW()
+FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM settings WHERE setting_key = 'ip_allowlist_reconcile');
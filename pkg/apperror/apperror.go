@@ -0,0 +1,251 @@
+// Package apperror defines the machine-readable error codes returned by the API. Clients
+// branch on the code; the message is for people and may change or be translated.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+)
+
+// Code identifies an error condition, e.g. COUPON_EXPIRED
+type Code string
+
+// Generic codes, used when a response has no more specific code
+const (
+	CodeBadRequest       Code = "BAD_REQUEST"
+	CodeUnauthorized     Code = "UNAUTHORIZED"
+	CodeForbidden        Code = "FORBIDDEN"
+	CodeNotFound         Code = "NOT_FOUND"
+	CodeConflict         Code = "CONFLICT"
+	CodePayloadTooLarge  Code = "PAYLOAD_TOO_LARGE"
+	CodeValidationFailed Code = "VALIDATION_FAILED"
+	CodeRateLimited      Code = "RATE_LIMITED"
+	CodeInternal         Code = "INTERNAL_ERROR"
+	CodeUnavailable      Code = "SERVICE_UNAVAILABLE"
+)
+
+// Authentication and access codes
+const (
+	CodeTokenMissing        Code = "TOKEN_MISSING"
+	CodeTokenInvalid        Code = "TOKEN_INVALID"
+	CodeTokenExpired        Code = "TOKEN_EXPIRED"
+	CodeTokenRevoked        Code = "TOKEN_REVOKED"
+	CodeInvalidCredentials  Code = "INVALID_CREDENTIALS"
+	CodeRefreshTokenInvalid Code = "REFRESH_TOKEN_INVALID"
+	CodeEmailTaken          Code = "EMAIL_ALREADY_REGISTERED"
+	CodeIPNotAllowed        Code = "IP_NOT_ALLOWED"
+)
+
+// Idempotency codes
+const (
+	CodeIdempotencyKeyInvalid Code = "IDEMPOTENCY_KEY_INVALID"
+	CodeIdempotencyKeyReused  Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyInProgress Code = "IDEMPOTENCY_IN_PROGRESS"
+)
+
+// Coupon codes
+const (
+	CodeCouponNotFound      Code = "COUPON_NOT_FOUND"
+	CodeCouponInactive      Code = "COUPON_INACTIVE"
+	CodeCouponNotStarted    Code = "COUPON_NOT_STARTED"
+	CodeCouponExpired       Code = "COUPON_EXPIRED"
+	CodeCouponUsageLimit    Code = "COUPON_USAGE_LIMIT"
+	CodeCouponUserLimit     Code = "COUPON_USER_LIMIT"
+	CodeCouponMinimumAmount Code = "COUPON_MINIMUM_AMOUNT"
+	CodeCouponNotApplicable Code = "COUPON_NOT_APPLICABLE"
+)
+
+// Payment and integration codes
+const (
+	CodeInvalidPaymentMethod Code = "INVALID_PAYMENT_METHOD"
+	CodeCardRequired         Code = "CARD_DATA_REQUIRED"
+	CodeInvalidDocument      Code = "INVALID_DOCUMENT"
+	CodeInvalidPhone         Code = "INVALID_PHONE"
+	CodeGatewayTimeout       Code = "GATEWAY_TIMEOUT"
+	CodeGatewayError         Code = "GATEWAY_ERROR"
+	CodeAIUnavailable        Code = "AI_UNAVAILABLE"
+)
+
+// Checkout session codes
+const (
+	CodeCheckoutSessionInvalid Code = "CHECKOUT_SESSION_INVALID"
+	CodeCheckoutSessionExpired Code = "CHECKOUT_SESSION_EXPIRED"
+	CodeCheckoutSessionUsed    Code = "CHECKOUT_SESSION_USED"
+)
+
+// Entry documents an error code
+type Entry struct {
+	Code        Code   `json:"code"`
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+}
+
+var catalog = map[Code]Entry{}
+
+func register(code Code, status int, description string) {
+	catalog[code] = Entry{Code: code, Status: status, Description: description}
+}
+
+func init() {
+	register(CodeBadRequest, http.StatusBadRequest, "The request is malformed or breaks a business rule")
+	register(CodeUnauthorized, http.StatusUnauthorized, "Authentication is required")
+	register(CodeForbidden, http.StatusForbidden, "The user may not perform this action")
+	register(CodeNotFound, http.StatusNotFound, "The resource does not exist or is not visible to the user")
+	register(CodeConflict, http.StatusConflict, "The request conflicts with the current state of the resource")
+	register(CodePayloadTooLarge, http.StatusRequestEntityTooLarge, "The request body exceeds the allowed size")
+	register(CodeValidationFailed, http.StatusUnprocessableEntity, "One or more fields are invalid; see field_errors")
+	register(CodeRateLimited, http.StatusTooManyRequests, "Too many requests; retry later")
+	register(CodeInternal, http.StatusInternalServerError, "Unexpected server error")
+	register(CodeUnavailable, http.StatusServiceUnavailable, "The service is temporarily unavailable")
+
+	register(CodeTokenMissing, http.StatusUnauthorized, "The Authorization header or bearer token is missing")
+	register(CodeTokenInvalid, http.StatusUnauthorized, "The token is malformed or its signature is invalid")
+	register(CodeTokenExpired, http.StatusUnauthorized, "The token has expired; log in again")
+	register(CodeTokenRevoked, http.StatusUnauthorized, "The token was revoked by a logout")
+	register(CodeInvalidCredentials, http.StatusUnauthorized, "Wrong email or password, or the account is inactive")
+	register(CodeRefreshTokenInvalid, http.StatusUnauthorized, "The refresh token is unknown, expired or revoked; log in again")
+	register(CodeEmailTaken, http.StatusBadRequest, "The email is already registered")
+	register(CodeIPNotAllowed, http.StatusForbidden, "The client IP is not in the allowlist of the route")
+
+	register(CodeIdempotencyKeyInvalid, http.StatusBadRequest, "The Idempotency-Key header is longer than 255 characters")
+	register(CodeIdempotencyKeyReused, http.StatusUnprocessableEntity, "The Idempotency-Key was already used with a different request body")
+	register(CodeIdempotencyInProgress, http.StatusConflict, "A request with the same Idempotency-Key is still being processed; retry later")
+
+	register(CodeCouponNotFound, http.StatusBadRequest, "No coupon has the given code")
+	register(CodeCouponInactive, http.StatusBadRequest, "The coupon is disabled")
+	register(CodeCouponNotStarted, http.StatusBadRequest, "The coupon validity period has not started")
+	register(CodeCouponExpired, http.StatusBadRequest, "The coupon has expired")
+	register(CodeCouponUsageLimit, http.StatusBadRequest, "The coupon reached its maximum number of uses")
+	register(CodeCouponUserLimit, http.StatusBadRequest, "The user reached the coupon limit of uses per user")
+	register(CodeCouponMinimumAmount, http.StatusBadRequest, "The order amount is below the coupon minimum; details.minimum_order_amount")
+	register(CodeCouponNotApplicable, http.StatusBadRequest, "The coupon gives no discount on this order")
+
+	register(CodeInvalidPaymentMethod, http.StatusBadRequest, "The payment method is not pix, boleto or card, or is not offered")
+	register(CodeCardRequired, http.StatusBadRequest, "Card payments require the card number and CVV")
+	register(CodeInvalidDocument, http.StatusBadRequest, "The CPF or CNPJ has a wrong length or check digits")
+	register(CodeInvalidPhone, http.StatusBadRequest, "The phone number is not valid; Brazilian numbers need the area code")
+	register(CodeGatewayTimeout, http.StatusGatewayTimeout, "The payment gateway did not answer in time; the charge may still be created")
+	register(CodeGatewayError, http.StatusBadGateway, "The payment gateway rejected or failed the request")
+	register(CodeAIUnavailable, http.StatusInternalServerError, "No AI provider is configured or reachable")
+
+	register(CodeCheckoutSessionInvalid, http.StatusUnauthorized, "The checkout session token is malformed, its signature is invalid or the session does not exist")
+	register(CodeCheckoutSessionExpired, http.StatusGone, "The checkout session expired; open a new one")
+	register(CodeCheckoutSessionUsed, http.StatusConflict, "The checkout session was already paid or is being paid")
+}
+
+// Catalog returns every documented error code, sorted by code
+func Catalog() []Entry {
+	entries := make([]Entry, 0, len(catalog))
+	for _, e := range catalog {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
+
+// Status returns the HTTP status of a code, 500 for unknown codes
+func Status(code Code) int {
+	if e, ok := catalog[code]; ok {
+		return e.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// ForStatus returns the generic code of an HTTP status
+func ForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeValidationFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// Error is an error carrying a code. Message is safe to show to the client; Err is the
+// underlying cause and is only logged.
+type Error struct {
+	Code    Code
+	Message string
+	Details map[string]interface{}
+	Err     error
+}
+
+// New creates an error with a code and a client-facing message
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap creates an error with a code that keeps err as its cause
+func Wrap(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// WithDetails returns a copy of the error with a detail added
+func (e *Error) WithDetails(key string, value interface{}) *Error {
+	cp := *e
+	cp.Details = make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		cp.Details[k] = v
+	}
+	cp.Details[key] = value
+	return &cp
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors with the same code, so sentinel errors can be compared with errors.Is
+// even after WithDetails
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Status returns the HTTP status of the error code
+func (e *Error) Status() int {
+	return Status(e.Code)
+}
+
+// As returns the first *Error in the chain of err
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// CodeOf returns the code of err, or an empty code when it has none
+func CodeOf(err error) Code {
+	if appErr, ok := As(err); ok {
+		return appErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCatalog_Documented(t *testing.T) {
+	entries := Catalog()
+	if len(entries) == 0 {
+		t.Fatal("catalog is empty")
+	}
+	for i, e := range entries {
+		if e.Description == "" {
+			t.Errorf("%s has no description", e.Code)
+		}
+		if e.Status < 400 {
+			t.Errorf("%s has status %d, want an error status", e.Code, e.Status)
+		}
+		if i > 0 && entries[i-1].Code >= e.Code {
+			t.Errorf("catalog not sorted at %s", e.Code)
+		}
+	}
+}
+
+func TestForStatus_Registered(t *testing.T) {
+	for _, status := range []int{400, 401, 403, 404, 409, 413, 422, 429, 500, 502, 503} {
+		code := ForStatus(status)
+		if _, ok := catalog[code]; !ok {
+			t.Errorf("ForStatus(%d) = %s, which is not in the catalog", status, code)
+		}
+	}
+}
+
+func TestAs_Wrapped(t *testing.T) {
+	cause := errors.New("i/o timeout")
+	err := fmt.Errorf("create charge: %w", Wrap(CodeGatewayTimeout, "Payment gateway timed out", cause))
+
+	appErr, ok := As(err)
+	if !ok {
+		t.Fatal("As should find the coded error")
+	}
+	if appErr.Status() != http.StatusGatewayTimeout {
+		t.Errorf("Status() = %d, want %d", appErr.Status(), http.StatusGatewayTimeout)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("the cause should stay reachable with errors.Is")
+	}
+	if CodeOf(errors.New("plain")) != "" {
+		t.Error("CodeOf of a plain error should be empty")
+	}
+}
+
+func TestIs_SameCode(t *testing.T) {
+	sentinel := New(CodeCouponExpired, "coupon has expired")
+	err := sentinel.WithDetails("expires_at", "2026-01-01")
+
+	if !errors.Is(err, sentinel) {
+		t.Error("errors with the same code should match")
+	}
+	if errors.Is(err, New(CodeCouponInactive, "coupon is inactive")) {
+		t.Error("errors with different codes should not match")
+	}
+	if len(sentinel.Details) != 0 {
+		t.Error("WithDetails must not modify the original error")
+	}
+}
@@ -0,0 +1,138 @@
+// Package cache keeps JSON encoded values for a TTL, in the memory of the instance or in a
+// Redis shared by the replicas. Values are read through Fetch, which loads and stores them on
+// a miss; writers drop the keys their changes affect with Delete or DeletePrefix.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired entries are dropped from memory
+const sweepInterval = time.Minute
+
+// Cache stores values by key for a TTL
+type Cache interface {
+	// Get decodes the value of key into dest and reports whether it was found
+	Get(ctx context.Context, key string, dest interface{}) (bool, error)
+
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+
+	// Delete drops the keys
+	Delete(ctx context.Context, keys ...string) error
+
+	// DeletePrefix drops every key starting with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Fetch returns the cached value of key, calling load and caching its result for ttl on a
+// miss. A nil cache or a ttl of zero disables caching. Cache failures are logged and the value
+// is loaded, so an unavailable Redis slows requests down but does not fail them.
+func Fetch[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) {
+	if c == nil || ttl <= 0 {
+		return load(ctx)
+	}
+
+	var value T
+	found, err := c.Get(ctx, key, &value)
+	if err != nil {
+		log.Printf("[CACHE] Failed to read %s: %v", key, err)
+	} else if found {
+		return value, nil
+	}
+
+	value, err = load(ctx)
+	if err != nil {
+		return value, err
+	}
+	if err := c.Set(ctx, key, value, ttl); err != nil {
+		log.Printf("[CACHE] Failed to store %s: %v", key, err)
+	}
+	return value, nil
+}
+
+type entry struct {
+	data    []byte
+	expires time.Time
+}
+
+// Memory keeps the values in the memory of the instance. Values are stored encoded, so
+// callers never share them with the cache.
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[string]entry
+	now     func() time.Time
+}
+
+// NewMemory creates a memory cache; expired entries are dropped in the background
+func NewMemory() *Memory {
+	m := &Memory{entries: make(map[string]entry), now: time.Now}
+	go func() {
+		for {
+			time.Sleep(sweepInterval)
+			m.sweep()
+		}
+	}()
+	return m
+}
+
+// Get decodes the value of key into dest
+func (m *Memory) Get(_ context.Context, key string, dest interface{}) (bool, error) {
+	m.mu.RLock()
+	e, ok := m.entries[key]
+	m.mu.RUnlock()
+	if !ok || !m.now().Before(e.expires) {
+		return false, nil
+	}
+	return true, json.Unmarshal(e.data, dest)
+}
+
+// Set stores value under key for ttl
+func (m *Memory) Set(_ context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.entries[key] = entry{data: data, expires: m.now().Add(ttl)}
+	m.mu.Unlock()
+	return nil
+}
+
+// Delete drops the keys
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// DeletePrefix drops every key starting with prefix
+func (m *Memory) DeletePrefix(_ context.Context, prefix string) error {
+	m.mu.Lock()
+	for key := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.entries, key)
+		}
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) sweep() {
+	now := m.now()
+	m.mu.Lock()
+	for key, e := range m.entries {
+		if !now.Before(e.expires) {
+			delete(m.entries, key)
+		}
+	}
+	m.mu.Unlock()
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type stats struct {
+	Total  int     `json:"total"`
+	Amount float64 `json:"amount"`
+}
+
+// testCache runs the same checks against every implementation
+func testCache(t *testing.T, c Cache, expire func(time.Duration)) {
+	ctx := context.Background()
+	loads := 0
+	load := func(context.Context) (*stats, error) {
+		loads++
+		return &stats{Total: loads, Amount: 99.9}, nil
+	}
+
+	first, err := Fetch(ctx, c, "stats:overview", time.Minute, load)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := Fetch(ctx, c, "stats:overview", time.Minute, load)
+	if loads != 1 || second.Total != 1 || second.Amount != 99.9 || second == first {
+		t.Errorf("expected a copy of the cached value, got %+v after %d loads", second, loads)
+	}
+
+	Fetch(ctx, c, "stats:audits", time.Minute, load)
+	Fetch(ctx, c, "settings:all", time.Minute, load)
+	if err := c.DeletePrefix(ctx, "stats:"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var s stats
+	if found, _ := c.Get(ctx, "stats:audits", &s); found {
+		t.Error("expected the stats keys to be dropped")
+	}
+	if found, _ := c.Get(ctx, "settings:all", &s); !found {
+		t.Error("expected keys of another prefix to be kept")
+	}
+	if err := c.Delete(ctx, "settings:all"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found, _ := c.Get(ctx, "settings:all", &s); found {
+		t.Error("expected the key to be dropped")
+	}
+
+	loads = 0
+	Fetch(ctx, c, "stats:payments", time.Minute, load)
+	expire(2 * time.Minute)
+	if v, _ := Fetch(ctx, c, "stats:payments", time.Minute, load); loads != 2 || v.Total != 2 {
+		t.Errorf("expected an expired value to be loaded again, got %+v", v)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	m := &Memory{entries: make(map[string]entry)}
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	testCache(t, m, func(d time.Duration) { now = now.Add(d) })
+
+	now = now.Add(2 * time.Minute)
+	m.sweep()
+	if len(m.entries) != 0 {
+		t.Errorf("expected expired entries to be swept, got %d", len(m.entries))
+	}
+}
+
+func TestRedis(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer client.Close()
+
+	testCache(t, NewRedis(client, "cache:"), srv.FastForward)
+
+	if keys := srv.Keys(); len(keys) != 1 || keys[0] != "cache:stats:payments" {
+		t.Errorf("expected the keys to carry the prefix, got %v", keys)
+	}
+}
+
+func TestFetch_LoadsWhenCacheFails(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr(), MaxRetries: -1})
+	defer client.Close()
+	srv.Close()
+
+	ctx := context.Background()
+	v, err := Fetch(ctx, NewRedis(client, "cache:"), "stats:overview", time.Minute, func(context.Context) (int, error) { return 42, nil })
+	if err != nil || v != 42 {
+		t.Errorf("expected the value to be loaded, got %d, %v", v, err)
+	}
+
+	errLoad := errors.New("db down")
+	if _, err := Fetch(ctx, NewMemory(), "stats:overview", time.Minute, func(context.Context) (int, error) { return 0, errLoad }); !errors.Is(err, errLoad) {
+		t.Errorf("expected the load error, got %v", err)
+	}
+	if v, _ := Fetch(ctx, nil, "stats:overview", time.Minute, func(context.Context) (int, error) { return 7, nil }); v != 7 {
+		t.Errorf("expected a nil cache to load, got %d", v)
+	}
+}
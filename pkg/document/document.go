@@ -0,0 +1,127 @@
+// Package document validates and normalizes Brazilian taxpayer documents: the CPF of people
+// (11 digits) and the CNPJ of companies (14 digits). Documents are stored and sent to the
+// payment gateways as digits only; punctuation ("123.456.789-09") is accepted on input.
+package document
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidCPF is returned for a CPF with a wrong length or check digits
+	ErrInvalidCPF = errors.New("document: invalid CPF")
+	// ErrInvalidCNPJ is returned for a CNPJ with a wrong length or check digits
+	ErrInvalidCNPJ = errors.New("document: invalid CNPJ")
+	// ErrInvalid is returned for a value that is neither a CPF nor a CNPJ
+	ErrInvalid = errors.New("document: not a CPF or CNPJ")
+)
+
+// Digits returns the digits of s, dropping punctuation and spaces
+func Digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Normalize returns the digits of a valid CPF or CNPJ, telling them apart by length
+func Normalize(s string) (string, error) {
+	d := Digits(s)
+	check := checkCNPJ
+	switch len(d) {
+	case 11:
+		check = checkCPF
+	case 14:
+	default:
+		return "", ErrInvalid
+	}
+	if err := check(d); err != nil {
+		return "", err
+	}
+	return d, nil
+}
+
+// NormalizeCPF returns the digits of a valid CPF
+func NormalizeCPF(s string) (string, error) {
+	d := Digits(s)
+	if checkCPF(d) != nil {
+		return "", ErrInvalidCPF
+	}
+	return d, nil
+}
+
+// Valid reports whether s is a valid CPF or CNPJ, with or without punctuation
+func Valid(s string) bool {
+	_, err := Normalize(s)
+	return err == nil
+}
+
+// Format punctuates a CPF (000.000.000-00) or CNPJ (00.000.000/0000-00). Other values are
+// returned as they are.
+func Format(s string) string {
+	d := Digits(s)
+	switch len(d) {
+	case 11:
+		return d[:3] + "." + d[3:6] + "." + d[6:9] + "-" + d[9:]
+	case 14:
+		return d[:2] + "." + d[2:5] + "." + d[5:8] + "/" + d[8:12] + "-" + d[12:]
+	}
+	return s
+}
+
+func checkCPF(d string) error {
+	if len(d) != 11 || repeated(d) {
+		return ErrInvalidCPF
+	}
+	if checkDigit(d[:9], 10) != d[9] || checkDigit(d[:10], 11) != d[10] {
+		return ErrInvalidCPF
+	}
+	return nil
+}
+
+func checkCNPJ(d string) error {
+	if len(d) != 14 || repeated(d) {
+		return ErrInvalidCNPJ
+	}
+	if cnpjCheckDigit(d[:12]) != d[12] || cnpjCheckDigit(d[:13]) != d[13] {
+		return ErrInvalidCNPJ
+	}
+	return nil
+}
+
+// checkDigit computes a CPF check digit, weighting the digits from weight down to 2
+func checkDigit(d string, weight int) byte {
+	sum := 0
+	for i := 0; i < len(d); i++ {
+		sum += int(d[i]-'0') * (weight - i)
+	}
+	return mod11(sum)
+}
+
+// cnpjCheckDigit computes a CNPJ check digit, weighting the digits from the right with 2 to 9
+// and starting over
+func cnpjCheckDigit(d string) byte {
+	sum := 0
+	for i := 0; i < len(d); i++ {
+		sum += int(d[len(d)-1-i]-'0') * (2 + i%8)
+	}
+	return mod11(sum)
+}
+
+func mod11(sum int) byte {
+	r := sum % 11
+	if r < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - r)
+}
+
+// repeated reports whether every digit is the same; such numbers pass the check digits but
+// are never issued
+func repeated(d string) bool {
+	return strings.Count(d, d[:1]) == len(d)
+}
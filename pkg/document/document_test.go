@@ -0,0 +1,57 @@
+package document
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{"529.982.247-25", "52998224725", nil},
+		{"52998224725", "52998224725", nil},
+		{" 111.444.777-35 ", "11144477735", nil},
+		{"11.222.333/0001-81", "11222333000181", nil},
+		{"529.982.247-24", "", ErrInvalidCPF},
+		{"111.111.111-11", "", ErrInvalidCPF},
+		{"11.222.333/0001-80", "", ErrInvalidCNPJ},
+		{"00.000.000/0000-00", "", ErrInvalidCNPJ},
+		{"1234", "", ErrInvalid},
+		{"", "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Normalize(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeCPF(t *testing.T) {
+	if got, err := NormalizeCPF("529.982.247-25"); err != nil || got != "52998224725" {
+		t.Errorf("NormalizeCPF = %q, %v", got, err)
+	}
+	if _, err := NormalizeCPF("11.222.333/0001-81"); !errors.Is(err, ErrInvalidCPF) {
+		t.Errorf("NormalizeCPF(CNPJ) error = %v", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	for in, want := range map[string]string{
+		"52998224725":    "529.982.247-25",
+		"11222333000181": "11.222.333/0001-81",
+		"529.982.247-25": "529.982.247-25",
+		"123":            "123",
+	} {
+		if got := Format(in); got != want {
+			t.Errorf("Format(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
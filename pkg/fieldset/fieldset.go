@@ -0,0 +1,117 @@
+// Package fieldset implements sparse fieldsets: responses trimmed to the JSON fields a client
+// asks for, as in ?fields=id,student_name,status.
+package fieldset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Parse splits a comma separated fields parameter. An empty parameter returns nil, meaning
+// every field.
+func Parse(param string) []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, f := range strings.Split(param, ",") {
+		f = strings.TrimSpace(f)
+		if f != "" && !seen[f] {
+			seen[f] = true
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Names returns the JSON field names of a struct type, or of the element type of a slice or
+// pointer; fields of embedded structs are included
+func Names(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	names := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			for n := range Names(f.Type) {
+				names[n] = true
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// Select returns v, a struct or a slice of structs, as generic JSON keeping only the given
+// fields of each object; nested values are kept whole. Fields the type does not have are an
+// error. With no fields, v is returned unchanged.
+func Select(v interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+
+	known := Names(reflect.TypeOf(v))
+	var unknown []string
+	for _, f := range fields {
+		if !known[f] {
+			unknown = append(unknown, f)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("invalid fields: %s", strings.Join(unknown, ", "))
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+	switch val := generic.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if obj, ok := item.(map[string]interface{}); ok {
+				trim(obj, keep)
+			}
+		}
+	case map[string]interface{}:
+		trim(val, keep)
+	}
+	return generic, nil
+}
+
+func trim(obj map[string]interface{}, keep map[string]bool) {
+	for k := range obj {
+		if !keep[k] {
+			delete(obj, k)
+		}
+	}
+}
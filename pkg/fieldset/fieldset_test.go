@@ -0,0 +1,69 @@
+package fieldset
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type item struct {
+	base
+	Name   string  `json:"name"`
+	Status string  `json:"status"`
+	Note   *string `json:"note,omitempty"`
+	Secret string  `json:"-"`
+}
+
+func TestParse(t *testing.T) {
+	got := Parse(" id, name,,id ,status")
+	want := []string{"id", "name", "status"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse = %v, want %v", got, want)
+	}
+	if Parse("") != nil {
+		t.Error("empty parameter should select every field")
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names(reflect.TypeOf([]*item{}))
+	for _, n := range []string{"id", "name", "status", "note"} {
+		if !names[n] {
+			t.Errorf("expected field %q", n)
+		}
+	}
+	if names["Secret"] || names["-"] {
+		t.Error("ignored fields should not be selectable")
+	}
+}
+
+func TestSelect_Slice(t *testing.T) {
+	items := []item{{base: base{ID: "1"}, Name: "a", Status: "active"}, {base: base{ID: "2"}, Name: "b", Status: "done"}}
+
+	got, err := Select(items, []string{"id", "status"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := json.Marshal(got)
+	if string(data) != `[{"id":"1","status":"active"},{"id":"2","status":"done"}]` {
+		t.Errorf("unexpected selection: %s", data)
+	}
+}
+
+func TestSelect_UnknownField(t *testing.T) {
+	if _, err := Select(item{}, []string{"id", "password"}); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestSelect_NoFields(t *testing.T) {
+	in := item{Name: "a"}
+	got, err := Select(in, nil)
+	if err != nil || !reflect.DeepEqual(got, in) {
+		t.Errorf("Select without fields = %v, %v", got, err)
+	}
+}
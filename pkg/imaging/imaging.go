@@ -0,0 +1,167 @@
+// Package imaging decodes uploaded images and produces downscaled variants using only the
+// standard library decoders (JPEG, PNG and GIF).
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// MaxPixels bounds the images that are decoded, so a small file declaring huge dimensions
+// cannot exhaust memory
+const MaxPixels = 40_000_000
+
+// jpegQuality is the quality of the JPEG variants
+const jpegQuality = 82
+
+// ErrUnsupported is returned for formats that cannot be decoded, e.g. WebP
+var ErrUnsupported = errors.New("imaging: unsupported image format")
+
+// Variant is a downscaled version of an image fitting a square of Size pixels
+type Variant struct {
+	Name string
+	Size int
+}
+
+// Variants are the sizes generated for every uploaded image
+var Variants = []Variant{
+	{Name: "thumb", Size: 320},
+	{Name: "medium", Size: 1280},
+}
+
+// Resized is an encoded variant
+type Resized struct {
+	Variant     Variant
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Supported reports whether variants can be generated for a content type
+func Supported(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// GenerateVariants decodes the image and returns the variants smaller than the original,
+// encoded in the original format. Images already smaller than a variant yield no entry for
+// it, since the original serves that size.
+func GenerateVariants(data []byte) ([]Resized, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("imaging: image of %dx%d exceeds %d pixels", cfg.Width, cfg.Height, MaxPixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imaging: decode %s: %w", format, err)
+	}
+
+	var out []Resized
+	for _, v := range Variants {
+		w, h := fit(cfg.Width, cfg.Height, v.Size)
+		if w >= cfg.Width && h >= cfg.Height {
+			continue
+		}
+		resized := Resize(src, w, h)
+
+		var buf bytes.Buffer
+		contentType, err := encode(&buf, resized, format)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Resized{Variant: v, Data: buf.Bytes(), ContentType: contentType, Width: w, Height: h})
+	}
+	return out, nil
+}
+
+func encode(buf *bytes.Buffer, img image.Image, format string) (string, error) {
+	switch format {
+	case "jpeg":
+		return "image/jpeg", jpeg.Encode(buf, img, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		return "image/png", png.Encode(buf, img)
+	case "gif":
+		// Variants keep only the first frame
+		return "image/gif", gif.Encode(buf, img, nil)
+	}
+	return "", ErrUnsupported
+}
+
+// fit scales width x height down to fit in a size x size square, keeping the aspect ratio
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		h := height * size / width
+		if h < 1 {
+			h = 1
+		}
+		return size, h
+	}
+	w := width * size / height
+	if w < 1 {
+		w = 1
+	}
+	return w, size
+}
+
+// Resize downscales src to width x height by averaging the source pixels each destination
+// pixel covers (box filter), which avoids the aliasing of nearest-neighbour sampling
+func Resize(src image.Image, width, height int) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	sw, sh := b.Dx(), b.Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := b.Min.Y + y*sh/height
+		y1 := b.Min.Y + (y+1)*sh/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := b.Min.X + x*sw/width
+			x1 := b.Min.X + (x+1)*sw/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(src.At(sx, sy)).(color.NRGBA64)
+					// Weight by alpha so transparent pixels do not darken the edges
+					r += uint64(c.R) * uint64(c.A)
+					g += uint64(c.G) * uint64(c.A)
+					bl += uint64(c.B) * uint64(c.A)
+					a += uint64(c.A)
+					n++
+				}
+			}
+
+			var px color.NRGBA
+			if a > 0 {
+				px.R = uint8(r / a >> 8)
+				px.G = uint8(g / a >> 8)
+				px.B = uint8(bl / a >> 8)
+				px.A = uint8(a / n >> 8)
+			}
+			dst.SetNRGBA(x, y, px)
+		}
+	}
+	return dst
+}
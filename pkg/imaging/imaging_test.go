@@ -0,0 +1,111 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func solid(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestGenerateVariants_Sizes(t *testing.T) {
+	data := encodePNG(t, solid(2000, 1000, color.NRGBA{R: 200, A: 255}))
+
+	variants, err := GenerateVariants(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(variants) != 2 {
+		t.Fatalf("expected 2 variants, got %d", len(variants))
+	}
+
+	want := map[string][2]int{"thumb": {320, 160}, "medium": {1280, 640}}
+	for _, v := range variants {
+		size := want[v.Variant.Name]
+		if v.Width != size[0] || v.Height != size[1] {
+			t.Errorf("%s = %dx%d, want %dx%d", v.Variant.Name, v.Width, v.Height, size[0], size[1])
+		}
+		if v.ContentType != "image/png" {
+			t.Errorf("%s content type = %s, want image/png", v.Variant.Name, v.ContentType)
+		}
+		cfg, err := png.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil || cfg.Width != size[0] {
+			t.Errorf("%s does not decode to the variant size: %v", v.Variant.Name, err)
+		}
+	}
+}
+
+func TestGenerateVariants_SmallImage(t *testing.T) {
+	data := encodePNG(t, solid(500, 300, color.White))
+
+	variants, err := GenerateVariants(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(variants) != 1 || variants[0].Variant.Name != "thumb" {
+		t.Errorf("expected only the thumb variant, got %+v", variants)
+	}
+}
+
+func TestGenerateVariants_KeepsJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, solid(800, 800, color.Black), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	variants, err := GenerateVariants(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(variants) != 1 || variants[0].ContentType != "image/jpeg" {
+		t.Errorf("expected a JPEG thumb, got %+v", variants)
+	}
+}
+
+func TestGenerateVariants_Unsupported(t *testing.T) {
+	if _, err := GenerateVariants([]byte("RIFF....WEBPVP8 ")); err != ErrUnsupported {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestResize_AveragesColors(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 255})
+	src.SetNRGBA(1, 0, color.NRGBA{B: 255, A: 255})
+
+	px := Resize(src, 1, 1).NRGBAAt(0, 0)
+	if px.R < 120 || px.R > 135 || px.B < 120 || px.B > 135 || px.A != 255 {
+		t.Errorf("expected an even mix of red and blue, got %+v", px)
+	}
+}
+
+func TestResize_TransparentEdges(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	src.SetNRGBA(1, 0, color.NRGBA{})
+
+	px := Resize(src, 1, 1).NRGBAAt(0, 0)
+	if px.R != 255 || px.A < 120 || px.A > 135 {
+		t.Errorf("expected half-transparent white, got %+v", px)
+	}
+}
@@ -0,0 +1,84 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+)
+
+const itemSchema = `{
+	"type": "object",
+	"required": ["name", "score"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "maxLength": 10},
+		"score": {"type": "number", "minimum": 0, "maximum": 10},
+		"count": {"type": "integer"},
+		"severity": {"type": "string", "enum": ["low", "high"]},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}}
+	}
+}`
+
+func mustParse(t *testing.T, s string) *Schema {
+	t.Helper()
+	schema, err := Parse([]byte(s))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return schema
+}
+
+func TestValidateValid(t *testing.T) {
+	s := mustParse(t, itemSchema)
+	if errs := s.Validate([]byte(`{"name": "Piscina", "score": 7.5, "count": 3, "severity": "low", "tags": ["a"]}`)); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	s := mustParse(t, itemSchema)
+	errs := s.Validate([]byte(`{"name": "", "score": 11, "count": 1.5, "severity": "medium", "tags": ["a", 2, "c"], "extra": true}`))
+
+	want := map[string]string{
+		"score":    "must be at most 10",
+		"name":     "must not be empty",
+		"count":    "must be an integer",
+		"severity": "must be one of low, high",
+		"tags":     "must have at most 2 items",
+		"tags[1]":  "must be a string",
+		"extra":    "is not allowed",
+	}
+	got := map[string]string{}
+	for _, e := range errs {
+		got[e.Path] = e.Message
+	}
+	for path, msg := range want {
+		if got[path] != msg {
+			t.Errorf("%s: got %q, want %q", path, got[path], msg)
+		}
+	}
+	if len(errs) != len(want) {
+		t.Errorf("got %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+}
+
+func TestValidateRequiredAndNesting(t *testing.T) {
+	s := mustParse(t, `{"type": "object", "required": ["items"], "properties": {
+		"items": {"type": "array", "minItems": 1, "items": `+itemSchema+`}}}`)
+
+	errs := s.Validate([]byte(`{}`))
+	if len(errs) != 1 || errs[0].Path != "items" || errs[0].Message != "is required" {
+		t.Errorf("missing items: %v", errs)
+	}
+
+	errs = s.Validate([]byte(`{"items": [{"name": "ok", "score": 1}, {"score": "high"}]}`))
+	if len(errs) != 2 || errs[0].Error() != "items[1].name is required" || errs[1].Error() != "items[1].score must be a number" {
+		t.Errorf("nested errors: %v", errs)
+	}
+
+	if errs := s.Validate([]byte(`[]`)); len(errs) != 1 || errs[0].Error() != "must be an object" {
+		t.Errorf("root type: %v", errs)
+	}
+	if errs := s.Validate([]byte(`{`)); len(errs) != 1 || !strings.Contains(errs[0].Message, "JSON") {
+		t.Errorf("invalid JSON: %v", errs)
+	}
+}
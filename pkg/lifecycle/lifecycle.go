@@ -0,0 +1,210 @@
+// Package lifecycle coordinates the background work of the process so shutdown can drain it:
+// workers are stopped through their context, in-flight operations are waited for and
+// shutdown hooks checkpoint in-memory state before exit.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrDraining is returned by Track once shutdown has started
+var ErrDraining = errors.New("lifecycle: shutting down")
+
+// Manager tracks the workers, in-flight operations and shutdown hooks of the process.
+// A nil Manager runs work untracked, which keeps tests and tools free of it.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	workers sync.WaitGroup
+
+	mu       sync.Mutex
+	draining bool
+	inflight map[string]int
+	idle     *sync.Cond
+	hooks    []hook
+}
+
+type hook struct {
+	name string
+	fn   func(context.Context) error
+}
+
+// New creates a manager
+func New() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{ctx: ctx, cancel: cancel, inflight: make(map[string]int)}
+	m.idle = sync.NewCond(&m.mu)
+	return m
+}
+
+// Go runs a worker until its context is cancelled at shutdown. Shutdown waits for fn to
+// return, so fn must watch ctx.
+func (m *Manager) Go(name string, fn func(ctx context.Context)) {
+	if m == nil {
+		go fn(context.Background())
+		return
+	}
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[LIFECYCLE] Worker %s panicked: %v", name, r)
+			}
+		}()
+		fn(m.ctx)
+	}()
+}
+
+// Every runs fn at every interval until shutdown; runNow also runs it at once. A run in
+// progress at shutdown gets a cancelled context and is waited for.
+func (m *Manager) Every(name string, interval time.Duration, runNow bool, fn func(ctx context.Context)) {
+	if interval <= 0 {
+		return
+	}
+	m.Go(name, func(ctx context.Context) {
+		if runNow {
+			fn(ctx)
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fn(ctx)
+			}
+		}
+	})
+}
+
+// Track registers an in-flight operation of a kind (e.g. "webhook") and returns the function
+// that ends it. Shutdown waits for every tracked operation. Once shutdown has started no
+// operation can begin and ErrDraining is returned, with a done function that does nothing.
+func (m *Manager) Track(kind string) (done func(), err error) {
+	if m == nil {
+		return func() {}, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.draining {
+		return func() {}, ErrDraining
+	}
+	m.inflight[kind]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			m.inflight[kind]--
+			if m.inflight[kind] == 0 {
+				delete(m.inflight, kind)
+			}
+			if len(m.inflight) == 0 {
+				m.idle.Broadcast()
+			}
+			m.mu.Unlock()
+		})
+	}, nil
+}
+
+// OnShutdown registers a hook run after workers and in-flight operations are drained, e.g.
+// to flush buffered counters. Hooks run in reverse order of registration.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+	m.mu.Unlock()
+}
+
+// Draining reports whether shutdown has started
+func (m *Manager) Draining() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.draining
+}
+
+// Shutdown stops the workers, waits for them and for the in-flight operations, then runs the
+// hooks. When ctx ends first it still runs the hooks, with a short grace period, and
+// returns an error naming what was left behind.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	m.draining = true
+	hooks := append([]hook(nil), m.hooks...)
+	m.mu.Unlock()
+	m.cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		m.mu.Lock()
+		for len(m.inflight) > 0 {
+			m.idle.Wait()
+		}
+		m.mu.Unlock()
+		close(drained)
+	}()
+
+	var drainErr error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		drainErr = fmt.Errorf("lifecycle: drain interrupted with %s in flight", m.pending())
+	}
+
+	// Hooks checkpoint state even when draining timed out
+	hookCtx := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		hookCtx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+	}
+	var errs []error
+	if drainErr != nil {
+		errs = append(errs, drainErr)
+	}
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].fn(hookCtx); err != nil {
+			errs = append(errs, fmt.Errorf("lifecycle: %s: %w", hooks[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pending describes the operations still in flight, e.g. "2 webhook, 1 request_audit"
+func (m *Manager) pending() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.inflight) == 0 {
+		return "workers"
+	}
+	kinds := make([]string, 0, len(m.inflight))
+	for kind := range m.inflight {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	out := ""
+	for i, kind := range kinds {
+		if i > 0 {
+			out += ", "
+		}
+		out += fmt.Sprintf("%d %s", m.inflight[kind], kind)
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdown_StopsWorkers(t *testing.T) {
+	m := New()
+	var stopped atomic.Bool
+	m.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		stopped.Store(true)
+	})
+
+	var runs atomic.Int32
+	m.Every("ticker", time.Millisecond, true, func(context.Context) { runs.Add(1) })
+
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stopped.Load() {
+		t.Error("expected Shutdown to wait for the worker")
+	}
+	if runs.Load() == 0 {
+		t.Error("expected runNow to run the job at once")
+	}
+}
+
+func TestShutdown_WaitsForTracked(t *testing.T) {
+	m := New()
+	done, err := m.Track("webhook")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var finished atomic.Bool
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		finished.Store(true)
+		done()
+		done() // a second call is a no-op
+	}()
+
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !finished.Load() {
+		t.Error("expected Shutdown to wait for the tracked operation")
+	}
+	if !m.Draining() {
+		t.Error("expected the manager to be draining")
+	}
+	if _, err := m.Track("webhook"); !errors.Is(err, ErrDraining) {
+		t.Errorf("expected ErrDraining after shutdown, got %v", err)
+	}
+}
+
+func TestShutdown_HooksInReverseOrder(t *testing.T) {
+	m := New()
+	var order []string
+	m.OnShutdown("first", func(context.Context) error {
+		order = append(order, "first")
+		return nil
+	})
+	m.OnShutdown("second", func(context.Context) error {
+		order = append(order, "second")
+		return errors.New("flush failed")
+	})
+
+	err := m.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "second: flush failed") {
+		t.Errorf("expected the hook error, got %v", err)
+	}
+	if strings.Join(order, ",") != "second,first" {
+		t.Errorf("expected hooks in reverse order, got %v", order)
+	}
+}
+
+func TestShutdown_Timeout(t *testing.T) {
+	m := New()
+	if _, err := m.Track("webhook"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var hookRan bool
+	m.OnShutdown("flush", func(ctx context.Context) error {
+		hookRan = ctx.Err() == nil
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := m.Shutdown(ctx)
+	if err == nil || !strings.Contains(err.Error(), "1 webhook") {
+		t.Errorf("expected an error naming the pending webhook, got %v", err)
+	}
+	if !hookRan {
+		t.Error("expected hooks to run with a live context after the timeout")
+	}
+}
+
+func TestNilManager(t *testing.T) {
+	var m *Manager
+	ran := make(chan struct{})
+	m.Go("worker", func(context.Context) { close(ran) })
+	<-ran
+
+	done, err := m.Track("webhook")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done()
+	m.OnShutdown("hook", func(context.Context) error { return nil })
+	if m.Draining() || m.Shutdown(context.Background()) != nil {
+		t.Error("expected a nil manager to do nothing")
+	}
+}
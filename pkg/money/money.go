@@ -0,0 +1,154 @@
+// Package money keeps amounts in integer cents, so payment totals, fees and revenue split
+// shares add up exactly instead of drifting with float rounding. Amounts still cross the API,
+// the database and the payment gateways as decimal numbers: Cents marshals to and from JSON
+// numbers and DECIMAL columns, and FromFloat / Float convert at the gateway calls.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Cents is an amount in cents (centavos)
+type Cents int64
+
+// ErrInvalid is returned for text that is not a decimal amount
+var ErrInvalid = errors.New("money: invalid amount")
+
+// FromFloat converts a decimal amount to cents, rounding half away from zero. The value is
+// first rounded to a millionth, so amounts like 0.285 that floats store as 0.28499... round up.
+func FromFloat(v float64) Cents {
+	return Cents(round(v * 100))
+}
+
+// Float returns the amount as a decimal number, for the gateways and float based code
+func (c Cents) Float() float64 {
+	return float64(c) / 100
+}
+
+// String formats the amount with two decimal places, e.g. "-12.30"
+func (c Cents) String() string {
+	sign := ""
+	v := int64(c)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// Mul multiplies the amount by a rate (0.0299 for 2.99%), rounding to the cent
+func (c Cents) Mul(rate float64) Cents {
+	return Cents(round(float64(c) * rate))
+}
+
+// Percent returns p percent of the amount, rounding to the cent
+func (c Cents) Percent(p float64) Cents {
+	return Cents(round(float64(c) * p / 100))
+}
+
+// Split divides the amount into n parts that add up to it: every part gets the amount / n
+// rounded toward zero and the last one the remainder
+func (c Cents) Split(n int) []Cents {
+	if n < 1 {
+		return nil
+	}
+	parts := make([]Cents, n)
+	share := c / Cents(n)
+	for i := range parts {
+		parts[i] = share
+	}
+	parts[n-1] = c - share*Cents(n-1)
+	return parts
+}
+
+// Parse reads a decimal amount such as "10", "10.5", "-0.05" or "1e2". Up to two decimal
+// places are read exactly; longer fractions are rounded half away from zero.
+func Parse(s string) (Cents, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if hasFrac && len(frac) <= 2 && isDigits(frac) {
+		neg := strings.HasPrefix(whole, "-")
+		if units, err := strconv.ParseInt(whole, 10, 64); err == nil {
+			cents, _ := strconv.ParseInt(frac+strings.Repeat("0", 2-len(frac)), 10, 64)
+			if neg {
+				cents = -cents
+			}
+			return Cents(units*100 + cents), nil
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	return FromFloat(v), nil
+}
+
+// MarshalJSON writes the amount as a JSON number, e.g. 12.3
+func (c Cents) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(c.Float(), 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON reads a JSON number or a numeric string; null leaves the amount unchanged
+func (c *Cents) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	v, err := Parse(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
+// Value stores the amount in a DECIMAL column
+func (c Cents) Value() (driver.Value, error) {
+	return c.String(), nil
+}
+
+// Scan reads a DECIMAL column; NULL is zero
+func (c *Cents) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*c = 0
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*c = parsed
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*c = parsed
+	case float64:
+		*c = FromFloat(v)
+	case int64:
+		*c = Cents(v * 100)
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+	return nil
+}
+
+// round rounds half away from zero after rounding to a millionth
+func round(v float64) float64 {
+	return math.Round(math.Round(v*1e6) / 1e6)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
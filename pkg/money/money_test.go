@@ -0,0 +1,130 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want Cents
+	}{
+		{0, 0},
+		{10, 1000},
+		{0.285, 29},
+		{1.005, 101},
+		{19.99, 1999},
+		{-0.015, -2},
+		{0.1 + 0.2, 30},
+	}
+	for _, tt := range tests {
+		if got := FromFloat(tt.in); got != tt.want {
+			t.Errorf("FromFloat(%v) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[Cents]string{0: "0.00", 5: "0.05", 1230: "12.30", -5: "-0.05", -123456: "-1234.56"}
+	for in, want := range tests {
+		if got := in.String(); got != want {
+			t.Errorf("Cents(%d).String() = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Cents
+	}{
+		{"10", 1000},
+		{"10.5", 1050},
+		{"10.50", 1050},
+		{"-0.05", -5},
+		{"0.285", 29},
+		{"1e2", 10000},
+		{".5", 50},
+		{" 99.99 ", 9999},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"", "abc", "1.2.3", "NaN", "Inf"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) expected an error", in)
+		}
+	}
+}
+
+func TestMulAndPercent(t *testing.T) {
+	if got := Cents(10000).Mul(0.0299); got != 299 {
+		t.Errorf("Mul = %d, want 299", got)
+	}
+	if got := Cents(1050).Percent(50); got != 525 {
+		t.Errorf("Percent = %d, want 525", got)
+	}
+	if got := Cents(15).Percent(10); got != 2 {
+		t.Errorf("Percent half cent = %d, want 2", got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	parts := Cents(1000).Split(3)
+	if len(parts) != 3 || parts[0] != 333 || parts[1] != 333 || parts[2] != 334 {
+		t.Errorf("unexpected parts: %v", parts)
+	}
+	if Cents(1000).Split(0) != nil {
+		t.Error("expected no parts for n < 1")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		Amount Cents `json:"amount"`
+		Fee    Cents `json:"fee"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 199.9, "fee": "2.99"}`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if v.Amount != 19990 || v.Fee != 299 {
+		t.Errorf("unexpected amounts: %+v", v)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(out) != `{"amount":199.9,"fee":2.99}` {
+		t.Errorf("unexpected JSON: %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"amount": true}`), &v); err == nil {
+		t.Error("expected an error for a non numeric amount")
+	}
+}
+
+func TestScanAndValue(t *testing.T) {
+	var c Cents
+	for _, src := range []interface{}{[]byte("150.25"), "150.25", 150.25} {
+		if err := c.Scan(src); err != nil || c != 15025 {
+			t.Errorf("Scan(%v) = %d, %v", src, c, err)
+		}
+	}
+	if err := c.Scan(int64(3)); err != nil || c != 300 {
+		t.Errorf("Scan(int64) = %d, %v", c, err)
+	}
+	if err := c.Scan(nil); err != nil || c != 0 {
+		t.Errorf("Scan(nil) = %d, %v", c, err)
+	}
+	v, err := Cents(-1205).Value()
+	if err != nil || v != "-12.05" {
+		t.Errorf("Value = %v, %v", v, err)
+	}
+}
@@ -0,0 +1,262 @@
+// Package pdf is a minimal PDF writer for simple text documents (purchase
+// orders, certificates, statements). It supports A4 pages, the standard
+// Helvetica fonts with WinAnsi encoding, text, lines and filled rectangles.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Font selects one of the built-in Helvetica faces
+type Font int
+
+// Built-in fonts
+const (
+	Regular Font = iota
+	Bold
+)
+
+// Align controls horizontal text alignment relative to x
+type Align int
+
+// Text alignments
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Document is an in-memory PDF being built page by page.
+// Coordinates are in points with the origin at the top-left corner.
+type Document struct {
+	width  float64
+	height float64
+	pages  []*bytes.Buffer
+	title  string
+}
+
+// New creates an empty A4 portrait document
+func New() *Document {
+	return &Document{width: A4Width, height: A4Height}
+}
+
+// NewLandscape creates an empty A4 landscape document
+func NewLandscape() *Document {
+	return &Document{width: A4Height, height: A4Width}
+}
+
+// SetTitle sets the document title metadata
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// Width returns the page width in points
+func (d *Document) Width() float64 { return d.width }
+
+// Height returns the page height in points
+func (d *Document) Height() float64 { return d.height }
+
+// PageCount returns the number of pages added so far
+func (d *Document) PageCount() int { return len(d.pages) }
+
+// AddPage starts a new page; subsequent drawing goes to it
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws a single line of text with its baseline at y
+func (d *Document) Text(x, y, size float64, font Font, align Align, s string) {
+	switch align {
+	case AlignRight:
+		x -= TextWidth(s, size, font)
+	case AlignCenter:
+		x -= TextWidth(s, size, font) / 2
+	}
+	fmt.Fprintf(d.current(), "BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		int(font)+1, size, x, d.height-y, escape(encode(s)))
+}
+
+// Line draws a straight line
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+		width, x1, d.height-y1, x2, d.height-y2)
+}
+
+// Rect draws a rectangle outline with its top-left corner at (x, y)
+func (d *Document) Rect(x, y, w, h, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f %.2f %.2f re S\n",
+		width, x, d.height-y-h, w, h)
+}
+
+// FillRect fills a rectangle with a gray level between 0 (black) and 1 (white)
+func (d *Document) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.current(), "q %.3f g %.2f %.2f %.2f %.2f re f Q\n",
+		gray, x, d.height-y-h, w, h)
+}
+
+// WrapText splits s into lines that fit maxWidth at the given size
+func WrapText(s string, size float64, font Font, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, w := range words[1:] {
+			candidate := line + " " + w
+			if TextWidth(candidate, size, font) > maxWidth {
+				lines = append(lines, line)
+				line = w
+				continue
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Bytes renders the document
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo renders the document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Object layout: 1 catalog, 2 page tree, 3-4 fonts, 5 info, then page/content pairs
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+i*2)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj(fmt.Sprintf("<< /Title (%s) /Producer (condotrack) >>", escape(encode(d.title))))
+
+	for i, content := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			d.width, d.height, firstPage+i*2+1))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// encode converts UTF-8 text to WinAnsi (Latin-1 subset); other runes become '?'
+func encode(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '€':
+			b = append(b, 0x80)
+		case r == '–' || r == '—':
+			b = append(b, '-')
+		case r == '“' || r == '”':
+			b = append(b, '"')
+		case r == '‘' || r == '’':
+			b = append(b, '\'')
+		case r == '•':
+			b = append(b, 0x95)
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			b = append(b, byte(r))
+		default:
+			b = append(b, '?')
+		}
+	}
+	return string(b)
+}
+
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`, "\r", "", "\n", " ")
+	return r.Replace(s)
+}
+
+// TextWidth approximates the rendered width of s in points
+func TextWidth(s string, size float64, font Font) float64 {
+	var units int
+	for _, r := range s {
+		units += glyphWidth(r, font)
+	}
+	return float64(units) * size / 1000
+}
+
+// glyphWidth returns approximate Helvetica advance widths (1/1000 em)
+func glyphWidth(r rune, font Font) int {
+	switch {
+	case r == ' ':
+		return 278
+	case r >= '0' && r <= '9':
+		return 556
+	case r == '.' || r == ',' || r == ':' || r == ';':
+		return 278
+	case r == 'i' || r == 'j' || r == 'l' || r == '|' || r == '\'':
+		if font == Bold {
+			return 278
+		}
+		return 222
+	case r == 'f' || r == 't' || r == 'I' || r == '/' || r == '-' || r == '(' || r == ')':
+		return 333
+	case r == 'm' || r == 'M' || r == 'W':
+		return 833
+	case r == 'w':
+		return 722
+	case r >= 'A' && r <= 'Z':
+		return 667
+	case r == 'r':
+		if font == Bold {
+			return 389
+		}
+		return 333
+	default:
+		if font == Bold {
+			return 611
+		}
+		return 556
+	}
+}
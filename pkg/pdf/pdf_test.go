@@ -0,0 +1,81 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestDocument_Structure(t *testing.T) {
+	doc := New()
+	doc.SetTitle("Pedido de compra")
+	doc.AddPage()
+	doc.Text(40, 60, 14, Bold, AlignLeft, "Pedido PO-0001")
+	doc.Line(40, 70, 555, 70, 1)
+	doc.AddPage()
+	doc.Text(555, 60, 10, Regular, AlignRight, "Total: R$ 1.234,56")
+
+	out := doc.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) {
+		t.Fatal("expected PDF header")
+	}
+	if !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("expected EOF marker")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Error("expected two pages in page tree")
+	}
+	if !bytes.Contains(out, []byte("(Pedido PO-0001) Tj")) {
+		t.Error("expected text operator in content stream")
+	}
+}
+
+func TestDocument_XrefOffsets(t *testing.T) {
+	doc := New()
+	doc.Text(10, 10, 12, Regular, AlignLeft, "ok")
+	out := doc.Bytes()
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if m == nil {
+		t.Fatal("startxref not found")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref")) {
+		t.Fatalf("startxref %d does not point to xref table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out, -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		want := fmt.Sprintf("%d 0 obj", i+1)
+		if !bytes.HasPrefix(out[off:], []byte(want)) {
+			t.Errorf("xref entry %d points to %q", i+1, out[off:off+10])
+		}
+	}
+}
+
+func TestEncode_LatinAndEscapes(t *testing.T) {
+	got := escape(encode("Manutenção (área) \\ 50€"))
+	want := "Manuten\xe7\xe3o \\(\xe1rea\\) \\\\ 50\x80"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if encode("日本") != "??" {
+		t.Error("expected unsupported runes to be replaced")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := WrapText("um dois tres quatro cinco seis", 10, Regular, 60)
+	if len(lines) < 2 {
+		t.Fatalf("expected text to wrap, got %v", lines)
+	}
+	for _, l := range lines {
+		if TextWidth(l, 10, Regular) > 60 && len(bytes.Fields([]byte(l))) > 1 {
+			t.Errorf("line %q exceeds max width", l)
+		}
+	}
+}
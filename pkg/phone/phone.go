@@ -0,0 +1,100 @@
+// Package phone validates and normalizes phone numbers to E.164 ("+5511987654321"), the
+// format WhatsApp, SMS and the payment gateways expect. Numbers without a country code are
+// taken as Brazilian; punctuation ("(11) 98765-4321") is accepted on input.
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned for a value that is not a phone number
+var ErrInvalid = errors.New("phone: invalid phone number")
+
+// brazil is the country code of Brazilian numbers
+const brazil = "55"
+
+// Normalize returns a phone number in E.164. Brazilian numbers need the area code and either
+// nine digits starting with 9 (mobile) or eight starting with 2 to 5 (landline); numbers of
+// other countries must start with "+" and are only checked for length.
+func Normalize(s string) (string, error) {
+	international := strings.HasPrefix(strings.TrimSpace(s), "+")
+	// A leading 0 is the trunk prefix of long-distance calls ("011 98765-4321")
+	d := strings.TrimLeft(digits(s), "0")
+	if !international {
+		if len(d) == 10 || len(d) == 11 {
+			d = brazil + d
+		} else if !strings.HasPrefix(d, brazil) {
+			return "", ErrInvalid
+		}
+	}
+	if len(d) < 8 || len(d) > 15 {
+		return "", ErrInvalid
+	}
+	if national, ok := strings.CutPrefix(d, brazil); ok && !validBrazilian(national) {
+		return "", ErrInvalid
+	}
+	return "+" + d, nil
+}
+
+// NormalizeOptional normalizes an optional phone number; nil is returned as it is and a blank
+// value clears the number
+func NormalizeOptional(p *string) (*string, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if strings.TrimSpace(*p) == "" {
+		empty := ""
+		return &empty, nil
+	}
+	n, err := Normalize(*p)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Valid reports whether s is a phone number, with or without punctuation
+func Valid(s string) bool {
+	_, err := Normalize(s)
+	return err == nil
+}
+
+// AreaCode splits a Brazilian number into its area code (DDD) and the local number. Numbers
+// of other countries, or not numbers at all, return an empty area code and their digits.
+func AreaCode(s string) (area, number string) {
+	n, err := Normalize(s)
+	if err != nil {
+		return "", digits(s)
+	}
+	national, ok := strings.CutPrefix(n[1:], brazil)
+	if !ok {
+		return "", n[1:]
+	}
+	return national[:2], national[2:]
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// validBrazilian checks the national part of a Brazilian number: an area code without zeros
+// followed by a mobile or landline number
+func validBrazilian(n string) bool {
+	if len(n) != 10 && len(n) != 11 {
+		return false
+	}
+	if n[0] == '0' || n[1] == '0' {
+		return false
+	}
+	if len(n) == 11 {
+		return n[2] == '9'
+	}
+	return n[2] >= '2' && n[2] <= '5'
+}
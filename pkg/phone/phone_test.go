@@ -0,0 +1,73 @@
+package phone
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"(11) 98765-4321", "+5511987654321"},
+		{"11 3333-4444", "+551133334444"},
+		{"011 98765-4321", "+5511987654321"},
+		{"5511987654321", "+5511987654321"},
+		{"+55 (21) 99876-5432", "+5521998765432"},
+		{"+1 415 555 0100", "+14155550100"},
+		{"+351 912 345 678", "+351912345678"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{
+		"",
+		"123",
+		"98765-4321",       // no area code
+		"(11) 8765-4321",   // 8-digit mobile
+		"(11) 6765-4321",   // landlines start with 2 to 5
+		"(01) 98765-4321",  // area codes have no zero
+		"+55 11 8876-5432", // landline starting with 8
+		"1234567890123456",
+	} {
+		if got, err := Normalize(in); err != ErrInvalid {
+			t.Errorf("Normalize(%q) = %q, %v; want ErrInvalid", in, got, err)
+		}
+	}
+}
+
+func TestNormalizeOptional(t *testing.T) {
+	if got, err := NormalizeOptional(nil); got != nil || err != nil {
+		t.Errorf("nil: got %v, %v", got, err)
+	}
+	blank := " "
+	if got, err := NormalizeOptional(&blank); err != nil || *got != "" {
+		t.Errorf("blank: got %q, %v", *got, err)
+	}
+	formatted := "(11) 98765-4321"
+	if got, err := NormalizeOptional(&formatted); err != nil || *got != "+5511987654321" {
+		t.Errorf("formatted: got %v, %v", got, err)
+	}
+	invalid := "123"
+	if _, err := NormalizeOptional(&invalid); err != ErrInvalid {
+		t.Errorf("invalid: err = %v", err)
+	}
+}
+
+func TestAreaCode(t *testing.T) {
+	tests := []struct {
+		in, area, number string
+	}{
+		{"+5511987654321", "11", "987654321"},
+		{"(21) 3333-4444", "21", "33334444"},
+		{"+1 415 555 0100", "", "14155550100"},
+		{"98765-4321", "", "987654321"},
+	}
+	for _, tt := range tests {
+		if area, number := AreaCode(tt.in); area != tt.area || number != tt.number {
+			t.Errorf("AreaCode(%q) = %q, %q; want %q, %q", tt.in, area, number, tt.area, tt.number)
+		}
+	}
+}
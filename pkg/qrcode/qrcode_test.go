@@ -0,0 +1,211 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+// decode reads a symbol back: format, unmasking, codeword order, Reed-Solomon check and the
+// byte mode payload
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	version := (c.size - 17) / 4
+
+	var format int
+	for i := 0; i <= 5; i++ {
+		format |= b2i(c.modules[i][8]) << i
+	}
+	format |= b2i(c.modules[7][8])<<6 | b2i(c.modules[8][8])<<7 | b2i(c.modules[8][7])<<8
+	for i := 9; i < 15; i++ {
+		format |= b2i(c.modules[8][14-i]) << i
+	}
+	var second int
+	for i := 0; i < 8; i++ {
+		second |= b2i(c.modules[8][c.size-1-i]) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= b2i(c.modules[c.size-15+i][8]) << i
+	}
+	if format != second {
+		t.Fatalf("format copies differ: %015b %015b", format, second)
+	}
+	format ^= 0x5412
+	if format>>13 != 0 {
+		t.Fatalf("expected level M, got format %015b", format)
+	}
+	mask := format >> 10 & 7
+
+	c.applyMask(mask)
+	defer c.applyMask(mask)
+	var bits bitBuffer
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.size; vert++ {
+			y := vert
+			if (right+1)&2 == 0 {
+				y = c.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				if !c.function[y][right-j] {
+					bits = append(bits, c.modules[y][right-j])
+				}
+			}
+		}
+	}
+	codewords := bits.bytes()
+
+	layout := layouts[version]
+	var blocks [][]byte
+	for _, g := range layout.groups {
+		for i := 0; i < g[0]; i++ {
+			blocks = append(blocks, make([]byte, 0, g[1]+layout.ecPerBlock))
+		}
+	}
+	pos := 0
+	for i := 0; ; i++ {
+		added := false
+		for k, g := range expandGroups(layout) {
+			if i < g {
+				blocks[k] = append(blocks[k], codewords[pos])
+				pos++
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for k := range blocks {
+			blocks[k] = append(blocks[k], codewords[pos])
+			pos++
+		}
+	}
+
+	var data []byte
+	for k, block := range blocks {
+		// A valid codeword polynomial vanishes at the generator roots a^0 .. a^(ec-1)
+		root := byte(1)
+		for i := 0; i < layout.ecPerBlock; i++ {
+			var s byte
+			for _, cw := range block {
+				s = gfMul(s, root) ^ cw
+			}
+			if s != 0 {
+				t.Fatalf("block %d: syndrome %d is %d", k, i, s)
+			}
+			root = gfMul(root, 2)
+		}
+		data = append(data, block[:len(block)-layout.ecPerBlock]...)
+	}
+
+	if data[0]>>4 != 0x4 {
+		t.Fatalf("expected byte mode, got %x", data[0]>>4)
+	}
+	var stream bitBuffer
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	n := readBits(stream[4:], countBits)
+	var out []byte
+	for i := 0; i < n; i++ {
+		out = append(out, byte(readBits(stream[4+countBits+8*i:], 8)))
+	}
+	return string(out)
+}
+
+func expandGroups(l blockLayout) []int {
+	var sizes []int
+	for _, g := range l.groups {
+		for i := 0; i < g[0]; i++ {
+			sizes = append(sizes, g[1])
+		}
+	}
+	return sizes
+}
+
+func readBits(b bitBuffer, n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		v = v<<1 | b2i(b[i])
+	}
+	return v
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		text    string
+		version int
+	}{
+		{"A1B2C3D4E5F6", 1},
+		{"https://condotrack.com/api/v1/certificados/validate/0123456789ABCDEF", 5},
+		{strings.Repeat("x", 150), 8},
+		{strings.Repeat("y", 213), 10},
+	}
+	for _, tt := range tests {
+		c, err := Encode(tt.text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := 17 + 4*tt.version; c.Size() != want {
+			t.Errorf("%d bytes: expected size %d, got %d", len(tt.text), want, c.Size())
+		}
+		if got := decode(t, c); got != tt.text {
+			t.Errorf("expected %q back, got %q", tt.text, got)
+		}
+	}
+}
+
+func TestEncodeFinderAndTiming(t *testing.T) {
+	c, err := Encode("hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	row := ""
+	for x := 0; x < c.Size(); x++ {
+		if c.Dark(x, 0) {
+			row += "#"
+		} else {
+			row += "."
+		}
+	}
+	if !strings.HasPrefix(row, "#######.") || !strings.HasSuffix(row, ".#######") {
+		t.Errorf("expected finder patterns on the top row, got %s", row)
+	}
+	for x := 8; x < c.Size()-8; x++ {
+		if c.Dark(x, 6) != (x%2 == 0) {
+			t.Fatalf("expected the timing pattern on row 6, column %d", x)
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("z", 214)); err != ErrTooLong {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	// Version 7 information from the standard, annex D
+	c := newCode(7)
+	var bits int
+	for i := 0; i < 18; i++ {
+		bits |= b2i(c.modules[i/3][c.size-11+i%3]) << i
+	}
+	if bits != 0x07C94 {
+		t.Errorf("expected version information 0x07C94, got %#x", bits)
+	}
+}
@@ -0,0 +1,120 @@
+// Package ratelimit keeps the token buckets of the API rate limiters. A bucket holds limit
+// tokens and refills over its window; each request takes one. The memory store is local to
+// the instance, so every replica has a budget of its own; the Redis store shares the buckets
+// across replicas.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are dropped from memory
+const sweepInterval = time.Minute
+
+// Quota is the state of a bucket after a request
+type Quota struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration // until the bucket is full again
+	Retry     time.Duration // until the next token, when the request was refused
+}
+
+// Store keeps the token bucket of each key (a limiter and an IP address or user)
+type Store interface {
+	// Allow takes a token of the bucket of key, holding limit tokens refilled over window;
+	// the request is refused when the bucket is empty
+	Allow(ctx context.Context, key string, limit int, window time.Duration) Quota
+}
+
+// bucket holds the tokens of a key
+type bucket struct {
+	mu       sync.Mutex
+	tokens   int
+	window   time.Duration
+	lastSeen time.Time
+}
+
+// MemoryStore keeps the buckets in the memory of the instance
+type MemoryStore struct {
+	buckets sync.Map
+	now     func() time.Time
+}
+
+// NewMemoryStore creates a memory store; buckets idle for twice their window are dropped in
+// the background
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{now: time.Now}
+	go func() {
+		for {
+			time.Sleep(sweepInterval)
+			s.sweep()
+		}
+	}()
+	return s
+}
+
+// Allow takes a token of the bucket of key
+func (s *MemoryStore) Allow(_ context.Context, key string, limit int, window time.Duration) Quota {
+	now := s.now()
+	val, _ := s.buckets.LoadOrStore(key, &bucket{tokens: limit, window: window, lastSeen: now})
+	b := val.(*bucket)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Replenish tokens based on elapsed time
+	elapsed := now.Sub(b.lastSeen)
+	if elapsed > window {
+		// Full window has passed, reset tokens
+		b.tokens = limit
+	} else {
+		// Proportional replenishment
+		replenish := int(float64(limit) * (float64(elapsed) / float64(window)))
+		b.tokens += replenish
+		if b.tokens > limit {
+			b.tokens = limit
+		}
+	}
+	b.window = window
+	b.lastSeen = now
+
+	perToken := tokenInterval(limit, window)
+	q := Quota{Limit: limit}
+	if b.tokens <= 0 {
+		q.Reset = time.Duration(limit) * perToken
+		q.Retry = perToken
+		return q
+	}
+
+	// Consume a token
+	b.tokens--
+	q.Allowed = true
+	q.Remaining = b.tokens
+	q.Reset = time.Duration(limit-b.tokens) * perToken
+	return q
+}
+
+func (s *MemoryStore) sweep() {
+	now := s.now()
+	s.buckets.Range(func(key, value interface{}) bool {
+		b := value.(*bucket)
+		b.mu.Lock()
+		idle := now.Sub(b.lastSeen) > b.window*2
+		b.mu.Unlock()
+		if idle {
+			s.buckets.Delete(key)
+		}
+		return true
+	})
+}
+
+// tokenInterval is how long a token takes to come back
+func tokenInterval(limit int, window time.Duration) time.Duration {
+	if limit <= 0 {
+		return window
+	}
+	return window / time.Duration(limit)
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := &MemoryStore{}
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if q := s.Allow(ctx, "login:ip:1.2.3.4", 3, time.Minute); !q.Allowed || q.Remaining != 2-i {
+			t.Fatalf("request %d: expected it to be allowed, got %+v", i, q)
+		}
+	}
+	q := s.Allow(ctx, "login:ip:1.2.3.4", 3, time.Minute)
+	if q.Allowed || q.Retry != 20*time.Second || q.Reset != time.Minute {
+		t.Fatalf("expected the fourth request to wait for a token, got %+v", q)
+	}
+	if q := s.Allow(ctx, "register:ip:1.2.3.4", 3, time.Minute); !q.Allowed {
+		t.Errorf("expected another key to have its own bucket, got %+v", q)
+	}
+
+	now = now.Add(20 * time.Second)
+	if q := s.Allow(ctx, "login:ip:1.2.3.4", 3, time.Minute); !q.Allowed || q.Remaining != 0 {
+		t.Errorf("expected a token back after window/limit, got %+v", q)
+	}
+
+	now = now.Add(3 * time.Minute)
+	s.sweep()
+	if _, ok := s.buckets.Load("login:ip:1.2.3.4"); ok {
+		t.Error("expected the idle bucket to be dropped")
+	}
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// errorLogInterval bounds how often Redis failures are logged, as every request would
+const errorLogInterval = time.Minute
+
+// takeToken refills and takes a token of the bucket of KEYS[1] atomically, with the clock of
+// Redis so replicas with skewed clocks agree. ARGV holds the limit and the window in ms; the
+// tokens are returned in thousandths, as Lua numbers are truncated to integers on return.
+var takeToken = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = limit
+	ts = now
+end
+tokens = math.min(limit, tokens + limit * math.max(0, now - ts) / window)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], window * 2)
+return {allowed, math.floor(tokens * 1000)}
+`)
+
+// RedisStore keeps the buckets in Redis, shared by every instance. While Redis is
+// unreachable, requests are limited with buckets in the memory of the instance instead.
+type RedisStore struct {
+	client   redis.UniversalClient
+	prefix   string
+	fallback *MemoryStore
+	loggedAt atomic.Int64 // unix time of the last logged failure
+}
+
+// NewRedisStore creates a Redis store whose keys start with prefix ("ratelimit:")
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix, fallback: NewMemoryStore()}
+}
+
+// Allow takes a token of the bucket of key
+func (s *RedisStore) Allow(ctx context.Context, key string, limit int, window time.Duration) Quota {
+	res, err := takeToken.Run(ctx, s.client, []string{s.prefix + key}, limit, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		s.logFailure(err)
+		return s.fallback.Allow(ctx, key, limit, window)
+	}
+
+	perToken := tokenInterval(limit, window)
+	tokens := time.Duration(res[1]) // thousandths
+	q := Quota{Allowed: res[0] == 1, Limit: limit, Remaining: int(res[1] / 1000)}
+	q.Reset = (time.Duration(limit)*1000 - tokens) * perToken / 1000
+	if !q.Allowed {
+		q.Retry = (1000 - tokens) * perToken / 1000
+	}
+	return q
+}
+
+func (s *RedisStore) logFailure(err error) {
+	now := time.Now().Unix()
+	last := s.loggedAt.Load()
+	if now-last < int64(errorLogInterval.Seconds()) || !s.loggedAt.CompareAndSwap(last, now) {
+		return
+	}
+	log.Printf("[RATELIMIT] Redis unavailable, limiting per instance: %v", err)
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisStore(t *testing.T) {
+	srv := miniredis.RunT(t)
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	srv.SetTime(now)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer client.Close()
+	ctx := context.Background()
+
+	// Two instances sharing the Redis share the budget
+	a, b := NewRedisStore(client, "ratelimit:"), NewRedisStore(client, "ratelimit:")
+	if q := a.Allow(ctx, "login:ip:1.2.3.4", 2, time.Minute); !q.Allowed || q.Remaining != 1 || q.Reset != 30*time.Second {
+		t.Fatalf("expected the first request to be allowed, got %+v", q)
+	}
+	if q := b.Allow(ctx, "login:ip:1.2.3.4", 2, time.Minute); !q.Allowed || q.Remaining != 0 {
+		t.Fatalf("expected the second request to be allowed, got %+v", q)
+	}
+	q := a.Allow(ctx, "login:ip:1.2.3.4", 2, time.Minute)
+	if q.Allowed || q.Retry != 30*time.Second || q.Reset != time.Minute {
+		t.Fatalf("expected the third request to be refused, got %+v", q)
+	}
+	if ttl := srv.TTL("ratelimit:login:ip:1.2.3.4"); ttl != 2*time.Minute {
+		t.Errorf("expected the bucket to expire after two windows, got %s", ttl)
+	}
+
+	srv.SetTime(now.Add(45 * time.Second))
+	if q := b.Allow(ctx, "login:ip:1.2.3.4", 2, time.Minute); !q.Allowed || q.Remaining != 0 {
+		t.Errorf("expected a token back after window/limit, got %+v", q)
+	}
+}
+
+func TestRedisStore_FallsBackToMemory(t *testing.T) {
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr(), MaxRetries: -1})
+	defer client.Close()
+	srv.Close()
+
+	s := NewRedisStore(client, "ratelimit:")
+	ctx := context.Background()
+	if q := s.Allow(ctx, "ai:ip:1.2.3.4", 1, time.Minute); !q.Allowed {
+		t.Fatalf("expected the request to be allowed by the local bucket, got %+v", q)
+	}
+	if q := s.Allow(ctx, "ai:ip:1.2.3.4", 1, time.Minute); q.Allowed {
+		t.Errorf("expected the local bucket to limit while Redis is down, got %+v", q)
+	}
+}
@@ -0,0 +1,105 @@
+// Package realtime is an in-process publish/subscribe hub that pushes events to the clients
+// connected to this instance, e.g. over WebSocket.
+package realtime
+
+import (
+	"sync"
+)
+
+// subscriberBuffer is how many events a slow subscriber may fall behind before new events
+// are dropped for it
+const subscriberBuffer = 16
+
+// Event is a message pushed to the subscribers of a topic
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// Hub delivers events to the subscribers of a topic, such as a user ID
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[string]map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription receives the events of a topic on C until it is closed
+type Subscription struct {
+	C <-chan Event
+
+	hub   *Hub
+	topic string
+	ch    chan Event
+	once  sync.Once
+}
+
+// NewHub creates a hub
+func NewHub() *Hub {
+	return &Hub{subs: make(map[string]map[*Subscription]struct{})}
+}
+
+// Subscribe starts receiving the events of topic. The caller must Close the subscription;
+// C is closed when either side ends it.
+func (h *Hub) Subscribe(topic string) *Subscription {
+	ch := make(chan Event, subscriberBuffer)
+	s := &Subscription{C: ch, hub: h, topic: topic, ch: ch}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(ch)
+		return s
+	}
+	if h.subs[topic] == nil {
+		h.subs[topic] = make(map[*Subscription]struct{})
+	}
+	h.subs[topic][s] = struct{}{}
+	return s
+}
+
+// Publish sends an event to every subscriber of topic without blocking; subscribers whose
+// buffer is full miss it.
+func (h *Hub) Publish(topic string, event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for s := range h.subs[topic] {
+		select {
+		case s.ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribers returns the number of subscriptions to topic
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[topic])
+}
+
+// Close ends every subscription, e.g. at shutdown so connected clients are let go. Later
+// subscriptions are closed at once.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for topic, subs := range h.subs {
+		for s := range subs {
+			s.once.Do(func() { close(s.ch) })
+		}
+		delete(h.subs, topic)
+	}
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if subs := s.hub.subs[s.topic]; subs != nil {
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(s.hub.subs, s.topic)
+		}
+	}
+	s.once.Do(func() { close(s.ch) })
+}
@@ -0,0 +1,66 @@
+package realtime
+
+import (
+	"testing"
+)
+
+func TestHub_PublishToTopic(t *testing.T) {
+	h := NewHub()
+	a := h.Subscribe("user-1")
+	b := h.Subscribe("user-2")
+	defer a.Close()
+	defer b.Close()
+
+	h.Publish("user-1", Event{Type: "badge", Data: 3})
+
+	select {
+	case e := <-a.C:
+		if e.Type != "badge" || e.Data != 3 {
+			t.Errorf("unexpected event: %+v", e)
+		}
+	default:
+		t.Fatal("expected the subscriber of the topic to get the event")
+	}
+	select {
+	case e := <-b.C:
+		t.Errorf("expected no event on another topic, got %+v", e)
+	default:
+	}
+}
+
+func TestHub_SlowSubscriberDoesNotBlock(t *testing.T) {
+	h := NewHub()
+	s := h.Subscribe("user-1")
+	defer s.Close()
+
+	for i := 0; i < subscriberBuffer*2; i++ {
+		h.Publish("user-1", Event{Type: "badge", Data: i})
+	}
+	if len(s.C) != subscriberBuffer {
+		t.Errorf("expected %d buffered events, got %d", subscriberBuffer, len(s.C))
+	}
+}
+
+func TestHub_Close(t *testing.T) {
+	h := NewHub()
+	s := h.Subscribe("user-1")
+	if h.Subscribers("user-1") != 1 {
+		t.Fatalf("expected one subscriber, got %d", h.Subscribers("user-1"))
+	}
+
+	s.Close()
+	s.Close() // closing twice is safe
+	if h.Subscribers("user-1") != 0 {
+		t.Errorf("expected the subscription to be removed, got %d", h.Subscribers("user-1"))
+	}
+
+	open := h.Subscribe("user-2")
+	h.Close()
+	if _, ok := <-open.C; ok {
+		t.Error("expected Close to end open subscriptions")
+	}
+	open.Close()
+	if _, ok := <-h.Subscribe("user-3").C; ok {
+		t.Error("expected subscriptions after Close to be closed")
+	}
+}
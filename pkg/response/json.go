@@ -4,15 +4,27 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/gin-gonic/gin"
 )
 
-// Response represents a standard API response
+// Response represents a standard API response. Error responses carry a code from the
+// apperror catalog besides the human-readable error message.
 type Response struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Message string      `json:"message,omitempty"`
+	Success     bool          `json:"success"`
+	Data        interface{}   `json:"data,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	Code        apperror.Code `json:"code,omitempty"`
+	Details     interface{}   `json:"details,omitempty"`
+	FieldErrors []FieldError  `json:"field_errors,omitempty"`
+	Message     string        `json:"message,omitempty"`
+}
+
+// FieldError describes an invalid field of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
 }
 
 // Success sends a successful response with data
@@ -40,14 +52,52 @@ func Created(c *gin.Context, data interface{}) {
 	})
 }
 
-// Error sends an error response
+// Error sends an error response with the generic code of the status
 func Error(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, Response{
 		Success: false,
 		Error:   message,
+		Code:    apperror.ForStatus(statusCode),
+	})
+}
+
+// ErrorCode sends an error response with a specific code and the status the catalog
+// assigns to it
+func ErrorCode(c *gin.Context, code apperror.Code, message string) {
+	c.JSON(apperror.Status(code), Response{
+		Success: false,
+		Error:   message,
+		Code:    code,
 	})
 }
 
+// AppError sends the response of a coded error, using the status of its code
+func AppError(c *gin.Context, err *apperror.Error) {
+	resp := Response{
+		Success: false,
+		Error:   err.Message,
+		Code:    err.Code,
+	}
+	if len(err.Details) > 0 {
+		resp.Details = err.Details
+	}
+	status := err.Status()
+	if status >= 500 && err.Err != nil {
+		log.Printf("[ERROR] %s: %v", err.Code, err.Err)
+	}
+	c.JSON(status, resp)
+}
+
+// FromError sends the response of a coded error anywhere in the chain of err and falls
+// back to SafeInternalError for any other error
+func FromError(c *gin.Context, context string, err error) {
+	if appErr, ok := apperror.As(err); ok {
+		AppError(c, appErr)
+		return
+	}
+	SafeInternalError(c, context, err)
+}
+
 // BadRequest sends a 400 Bad Request response
 func BadRequest(c *gin.Context, message string) {
 	Error(c, http.StatusBadRequest, message)
@@ -67,7 +117,7 @@ func InternalError(c *gin.Context, message string) {
 // message to the client, preventing internal details from leaking.
 func SafeInternalError(c *gin.Context, context string, err error) {
 	log.Printf("[ERROR] %s: %v", context, err)
-	Error(c, http.StatusInternalServerError, "Internal server error")
+	ErrorCode(c, apperror.CodeInternal, "Internal server error")
 }
 
 // Unauthorized sends a 401 Unauthorized response
@@ -85,6 +135,16 @@ func ValidationError(c *gin.Context, message string) {
 	Error(c, http.StatusUnprocessableEntity, message)
 }
 
+// FieldValidationError sends a 422 Unprocessable Entity response listing the invalid fields
+func FieldValidationError(c *gin.Context, message string, fields []FieldError) {
+	c.JSON(http.StatusUnprocessableEntity, Response{
+		Success:     false,
+		Error:       message,
+		Code:        apperror.CodeValidationFailed,
+		FieldErrors: fields,
+	})
+}
+
 // Custom sends a custom JSON response
 func Custom(c *gin.Context, statusCode int, data interface{}) {
 	c.JSON(statusCode, data)
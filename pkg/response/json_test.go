@@ -3,10 +3,12 @@ package response
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/condotrack/api/pkg/apperror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -186,3 +188,77 @@ func TestSuccessWithMessage(t *testing.T) {
 		t.Errorf("Message = %q, want %q", resp.Message, "operation completed")
 	}
 }
+
+func TestError_DefaultCode(t *testing.T) {
+	c, w := newTestContext()
+	NotFound(c, "audit not found")
+
+	resp := parseResponse(t, w)
+	if resp.Code != apperror.CodeNotFound {
+		t.Errorf("Code = %q, want %q", resp.Code, apperror.CodeNotFound)
+	}
+}
+
+func TestFromError_AppError(t *testing.T) {
+	c, w := newTestContext()
+	err := apperror.New(apperror.CodeCouponMinimumAmount, "order amount is below the coupon minimum").
+		WithDetails("minimum_order_amount", 100.0)
+	FromError(c, "Failed to create checkout", fmt.Errorf("checkout: %w", err))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	resp := parseResponse(t, w)
+	if resp.Code != apperror.CodeCouponMinimumAmount {
+		t.Errorf("Code = %q, want %q", resp.Code, apperror.CodeCouponMinimumAmount)
+	}
+	if resp.Error != "order amount is below the coupon minimum" {
+		t.Errorf("Error = %q", resp.Error)
+	}
+	details, _ := resp.Details.(map[string]interface{})
+	if details["minimum_order_amount"] != 100.0 {
+		t.Errorf("Details = %v, want minimum_order_amount", resp.Details)
+	}
+}
+
+func TestFromError_HidesCause(t *testing.T) {
+	c, w := newTestContext()
+	FromError(c, "Failed to create checkout", apperror.Wrap(apperror.CodeGatewayTimeout, "Payment gateway timed out", errors.New("dial tcp: i/o timeout")))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusGatewayTimeout)
+	}
+	resp := parseResponse(t, w)
+	if resp.Error != "Payment gateway timed out" {
+		t.Errorf("Error = %q, the cause must not be exposed", resp.Error)
+	}
+}
+
+func TestFromError_PlainError(t *testing.T) {
+	c, w := newTestContext()
+	FromError(c, "Failed to create checkout", errors.New("connection refused"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	resp := parseResponse(t, w)
+	if resp.Code != apperror.CodeInternal || resp.Error != "Internal server error" {
+		t.Errorf("got code %q error %q, want the generic internal error", resp.Code, resp.Error)
+	}
+}
+
+func TestFieldValidationError(t *testing.T) {
+	c, w := newTestContext()
+	FieldValidationError(c, "Invalid request", []FieldError{{Field: "email", Rule: "required", Message: "email is required"}})
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	resp := parseResponse(t, w)
+	if resp.Code != apperror.CodeValidationFailed {
+		t.Errorf("Code = %q, want %q", resp.Code, apperror.CodeValidationFailed)
+	}
+	if len(resp.FieldErrors) != 1 || resp.FieldErrors[0].Field != "email" {
+		t.Errorf("FieldErrors = %+v", resp.FieldErrors)
+	}
+}
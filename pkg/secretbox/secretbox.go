@@ -0,0 +1,90 @@
+// Package secretbox encrypts short secrets (API keys, tokens) for storage with AES-256-GCM.
+// Encrypted values are text, prefixed with a version marker so plaintext values written
+// before encryption was enabled can still be told apart and read.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Prefix marks encrypted values: "enc:v1:" + base64(nonce || ciphertext || tag)
+const Prefix = "enc:v1:"
+
+// ErrInvalidKey is returned when the master key is not 32 bytes
+var ErrInvalidKey = errors.New("secretbox: master key must be 32 bytes, base64 or hex encoded")
+
+// Box encrypts and decrypts values with a master key
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New creates a Box from a base64 or hex encoded 32-byte master key
+func New(masterKey string) (*Box, error) {
+	key, err := decodeKey(strings.TrimSpace(masterKey))
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Encrypt seals a value with a random nonce
+func (b *Box) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens an encrypted value. Values without the prefix are returned unchanged.
+func (b *Box) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return "", errors.New("secretbox: malformed value")
+	}
+	size := b.aead.NonceSize()
+	if len(sealed) < size+b.aead.Overhead() {
+		return "", errors.New("secretbox: malformed value")
+	}
+	plaintext, err := b.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", errors.New("secretbox: value cannot be decrypted with this master key")
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether a value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+func decodeKey(s string) ([]byte, error) {
+	if len(s) == 64 {
+		if key, err := hex.DecodeString(s); err == nil {
+			return key, nil
+		}
+	}
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, ErrInvalidKey
+}
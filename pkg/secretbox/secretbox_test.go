@@ -0,0 +1,62 @@
+package secretbox
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+const testKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestEncryptDecrypt(t *testing.T) {
+	box, err := New(testKey)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	sealed, err := box.Encrypt("$aact_secret")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !IsEncrypted(sealed) || strings.Contains(sealed, "aact") {
+		t.Errorf("sealed value %q does not look encrypted", sealed)
+	}
+
+	again, _ := box.Encrypt("$aact_secret")
+	if again == sealed {
+		t.Error("expected a fresh nonce per encryption")
+	}
+
+	plain, err := box.Decrypt(sealed)
+	if err != nil || plain != "$aact_secret" {
+		t.Errorf("Decrypt = %q, %v", plain, err)
+	}
+}
+
+func TestDecryptPlaintextAndTampering(t *testing.T) {
+	box, _ := New(testKey)
+
+	if plain, err := box.Decrypt("legacy-plain-value"); err != nil || plain != "legacy-plain-value" {
+		t.Errorf("plaintext Decrypt = %q, %v; want unchanged", plain, err)
+	}
+
+	sealed, _ := box.Encrypt("token")
+	raw, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, Prefix))
+	raw[len(raw)-1] ^= 0xff
+	if _, err := box.Decrypt(Prefix + base64.StdEncoding.EncodeToString(raw)); err == nil {
+		t.Error("tampered value: expected error")
+	}
+
+	other, _ := New(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	if _, err := other.Decrypt(sealed); err == nil {
+		t.Error("wrong master key: expected error")
+	}
+}
+
+func TestNewRejectsShortKeys(t *testing.T) {
+	for _, key := range []string{"", "short", base64.StdEncoding.EncodeToString(make([]byte, 16))} {
+		if _, err := New(key); err != ErrInvalidKey {
+			t.Errorf("New(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
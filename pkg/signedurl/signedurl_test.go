@@ -0,0 +1,83 @@
+package signedurl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	s := New("secret")
+	now := time.Unix(1_700_000_000, 0)
+	q := s.Sign("/api/v1/images/file/a.png", now.Add(time.Minute))
+
+	exp, err := s.Verify("/api/v1/images/file/a.png", q.Get("expires"), q.Get("signature"), now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exp.Equal(now.Add(time.Minute)) {
+		t.Errorf("expiry = %v, want %v", exp, now.Add(time.Minute))
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	s := New("secret")
+	now := time.Unix(1_700_000_000, 0)
+	q := s.Sign("/a.png", now.Add(time.Minute))
+
+	tests := []struct {
+		name                     string
+		path, expires, signature string
+		now                      time.Time
+		want                     error
+	}{
+		{"other path", "/b.png", q.Get("expires"), q.Get("signature"), now, ErrInvalid},
+		{"extended expiry", "/a.png", "1800000000", q.Get("signature"), now, ErrInvalid},
+		{"missing signature", "/a.png", q.Get("expires"), "", now, ErrInvalid},
+		{"malformed expiry", "/a.png", "soon", q.Get("signature"), now, ErrInvalid},
+		{"expired", "/a.png", q.Get("expires"), q.Get("signature"), now.Add(time.Minute), ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Verify(tt.path, tt.expires, tt.signature, tt.now); err != tt.want {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	other := New("other")
+	if _, err := other.Verify("/a.png", q.Get("expires"), q.Get("signature"), now); err != ErrInvalid {
+		t.Errorf("signature from another key: err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestURL(t *testing.T) {
+	s := New("secret")
+	raw := s.URL("/a.png", time.Unix(1_700_000_060, 0))
+	if !strings.HasPrefix(raw, "/a.png?") {
+		t.Fatalf("unexpected URL %q", raw)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("expires") != "1700000060" || u.Query().Get("signature") == "" {
+		t.Errorf("missing parameters in %q", raw)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	ttl := 15 * time.Minute
+	a := Expiry(time.Unix(900*1000+10, 0), ttl)
+	b := Expiry(time.Unix(900*1000+800, 0), ttl)
+	if !a.Equal(b) {
+		t.Errorf("expiries in the same window differ: %v, %v", a, b)
+	}
+
+	now := time.Unix(900*1000+899, 0)
+	exp := Expiry(now, ttl)
+	if exp.Sub(now) < ttl || exp.Sub(now) > 2*ttl {
+		t.Errorf("expiry %v is not between ttl and 2*ttl from now", exp.Sub(now))
+	}
+}
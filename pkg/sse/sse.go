@@ -0,0 +1,55 @@
+// Package sse reads Server-Sent Events streams, as returned by the streaming APIs of the AI
+// providers.
+package sse
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// maxLineSize bounds a single line of the stream
+const maxLineSize = 1024 * 1024
+
+// Event is a server-sent event; Name is empty for unnamed ("message") events
+type Event struct {
+	Name string
+	Data string
+}
+
+// Read parses an event stream and calls fn for every event carrying data. It stops at the
+// end of the stream, on a read error or at the first error returned by fn.
+func Read(r io.Reader, fn func(Event) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var name string
+	var data []string
+	dispatch := func() error {
+		defer func() { name, data = "", nil }()
+		if len(data) == 0 {
+			return nil
+		}
+		return fn(Event{Name: name, Data: strings.Join(data, "\n")})
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := dispatch(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+			// comment / keep-alive
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return dispatch()
+}
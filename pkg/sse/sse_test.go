@@ -0,0 +1,51 @@
+package sse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	stream := ": keep-alive\n\n" +
+		"data: {\"a\":1}\n\n" +
+		"event: message_delta\n" +
+		"data: line one\n" +
+		"data: line two\n\n" +
+		"data: [DONE]"
+
+	var events []Event
+	err := Read(strings.NewReader(stream), func(e Event) error {
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Event{
+		{Data: `{"a":1}`},
+		{Name: "message_delta", Data: "line one\nline two"},
+		{Data: "[DONE]"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
+func TestRead_StopsOnCallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	calls := 0
+	err := Read(strings.NewReader("data: 1\n\ndata: 2\n\n"), func(Event) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Read = %v after %d calls, want stop after 1", err, calls)
+	}
+}
@@ -0,0 +1,236 @@
+// Package validation turns request binding errors into per-field errors with localized
+// messages. Field paths follow the JSON names of the request, e.g. "items[0].title".
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/condotrack/api/pkg/response"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Supported locales; LocalePT is the default
+const (
+	LocalePT = "pt-BR"
+	LocaleEN = "en"
+)
+
+// Setup makes the binding validator report fields by their JSON name. It must run before
+// the first request is bound.
+func Setup() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(jsonName)
+}
+
+func jsonName(fld reflect.StructField) string {
+	name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return fld.Name
+	}
+	return name
+}
+
+// Locale picks the message locale from an Accept-Language header
+func Locale(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		switch {
+		case strings.HasPrefix(tag, "pt"):
+			return LocalePT
+		case strings.HasPrefix(tag, "en"):
+			return LocaleEN
+		}
+	}
+	return LocalePT
+}
+
+// Translate returns the field errors of a binding error. ok is false when err is not about
+// the fields, e.g. a malformed JSON body.
+func Translate(err error, locale string) (fields []response.FieldError, ok bool) {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		for _, fe := range verrs {
+			fields = append(fields, response.FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Message: message(fe, locale),
+			})
+		}
+		return fields, true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []response.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: typeMessage(typeErr.Type, locale),
+		}}, true
+	}
+	return nil, false
+}
+
+// fieldPath drops the request type name the namespace starts with
+func fieldPath(namespace string) string {
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+type messages struct {
+	required, email, oneof, invalid string
+	// min, max, len, gt, gte, lt, lte by the kind of value they bound
+	text, number, items map[string]string
+}
+
+var catalog = map[string]messages{
+	LocalePT: {
+		required: "é obrigatório",
+		email:    "deve ser um e-mail válido",
+		oneof:    "deve ser um dos valores: %s",
+		invalid:  "é inválido",
+		text: map[string]string{
+			"min": "deve ter pelo menos %s caracteres",
+			"max": "deve ter no máximo %s caracteres",
+			"len": "deve ter %s caracteres",
+		},
+		number: map[string]string{
+			"min": "deve ser no mínimo %s",
+			"max": "deve ser no máximo %s",
+			"len": "deve ser igual a %s",
+			"gt":  "deve ser maior que %s",
+			"gte": "deve ser maior ou igual a %s",
+			"lt":  "deve ser menor que %s",
+			"lte": "deve ser menor ou igual a %s",
+		},
+		items: map[string]string{
+			"min": "deve ter pelo menos %s itens",
+			"max": "deve ter no máximo %s itens",
+			"len": "deve ter %s itens",
+			"gt":  "deve ter mais de %s itens",
+			"gte": "deve ter pelo menos %s itens",
+			"lt":  "deve ter menos de %s itens",
+			"lte": "deve ter no máximo %s itens",
+		},
+	},
+	LocaleEN: {
+		required: "is required",
+		email:    "must be a valid email",
+		oneof:    "must be one of: %s",
+		invalid:  "is invalid",
+		text: map[string]string{
+			"min": "must be at least %s characters long",
+			"max": "must be at most %s characters long",
+			"len": "must be %s characters long",
+		},
+		number: map[string]string{
+			"min": "must be at least %s",
+			"max": "must be at most %s",
+			"len": "must be equal to %s",
+			"gt":  "must be greater than %s",
+			"gte": "must be greater than or equal to %s",
+			"lt":  "must be less than %s",
+			"lte": "must be less than or equal to %s",
+		},
+		items: map[string]string{
+			"min": "must have at least %s items",
+			"max": "must have at most %s items",
+			"len": "must have %s items",
+			"gt":  "must have more than %s items",
+			"gte": "must have at least %s items",
+			"lt":  "must have fewer than %s items",
+			"lte": "must have at most %s items",
+		},
+	},
+}
+
+func message(fe validator.FieldError, locale string) string {
+	m, ok := catalog[locale]
+	if !ok {
+		m = catalog[LocalePT]
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return m.required
+	case "email":
+		return m.email
+	case "oneof":
+		return fmt.Sprintf(m.oneof, strings.Join(strings.Fields(fe.Param()), ", "))
+	}
+
+	var bounds map[string]string
+	switch fe.Kind() {
+	case reflect.String:
+		bounds = m.text
+	case reflect.Slice, reflect.Array, reflect.Map:
+		bounds = m.items
+	default:
+		bounds = m.number
+	}
+	if format, ok := bounds[fe.Tag()]; ok {
+		return fmt.Sprintf(format, fe.Param())
+	}
+	return m.invalid
+}
+
+// typeMessages are the messages of a JSON value of the wrong type, by the expected kind
+var typeMessages = map[string]map[string]string{
+	LocalePT: {
+		"string":  "deve ser um texto",
+		"boolean": "deve ser verdadeiro ou falso",
+		"number":  "deve ser um número",
+		"list":    "deve ser uma lista",
+		"object":  "deve ser um objeto",
+		"":        "tem um tipo inválido",
+	},
+	LocaleEN: {
+		"string":  "must be a string",
+		"boolean": "must be a boolean",
+		"number":  "must be a number",
+		"list":    "must be a list",
+		"object":  "must be an object",
+		"":        "has an invalid type",
+	},
+}
+
+func typeMessage(t reflect.Type, locale string) string {
+	m, ok := typeMessages[locale]
+	if !ok {
+		m = typeMessages[LocalePT]
+	}
+	return m[jsonKind(t)]
+}
+
+func jsonKind(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return ""
+}
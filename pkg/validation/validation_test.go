@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type testItem struct {
+	Title string `json:"title" binding:"required,max=5"`
+}
+
+type testRequest struct {
+	Email    string     `json:"email" binding:"required,email"`
+	Status   string     `json:"status" binding:"omitempty,oneof=open closed"`
+	Amount   *float64   `json:"amount" binding:"required,gt=0"`
+	Items    []testItem `json:"items" binding:"required,min=1,dive"`
+	Internal string     `json:"-"`
+}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	Setup()
+}
+
+func bind(t *testing.T, body string) error {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	var req testRequest
+	return c.ShouldBindJSON(&req)
+}
+
+func byField(t *testing.T, err error, locale string) map[string]string {
+	t.Helper()
+	fields, ok := Translate(err, locale)
+	if !ok {
+		t.Fatalf("Translate(%v) should report field errors", err)
+	}
+	m := map[string]string{}
+	for _, f := range fields {
+		m[f.Field] = f.Rule + ": " + f.Message
+	}
+	return m
+}
+
+func TestTranslate_FieldPaths(t *testing.T) {
+	err := bind(t, `{"email":"nope","status":"pending","amount":0,"items":[{"title":"long title"}]}`)
+	got := byField(t, err, LocalePT)
+
+	want := map[string]string{
+		"email":          "email: deve ser um e-mail válido",
+		"status":         "oneof: deve ser um dos valores: open, closed",
+		"amount":         "gt: deve ser maior que 0",
+		"items[0].title": "max: deve ter no máximo 5 caracteres",
+	}
+	for field, msg := range want {
+		if got[field] != msg {
+			t.Errorf("%s = %q, want %q", field, got[field], msg)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d field errors, want %d: %v", len(got), len(want), got)
+	}
+}
+
+func TestTranslate_English(t *testing.T) {
+	err := bind(t, `{"items":[]}`)
+	got := byField(t, err, LocaleEN)
+
+	if got["email"] != "required: is required" {
+		t.Errorf("email = %q", got["email"])
+	}
+	if got["items"] != "min: must have at least 1 items" {
+		t.Errorf("items = %q", got["items"])
+	}
+}
+
+func TestTranslate_WrongType(t *testing.T) {
+	err := bind(t, `{"email":"a@b.com","amount":"ten","items":[{"title":"a"}]}`)
+	got := byField(t, err, LocalePT)
+
+	if got["amount"] != "type: deve ser um número" {
+		t.Errorf("amount = %q", got["amount"])
+	}
+}
+
+func TestTranslate_MalformedBody(t *testing.T) {
+	if _, ok := Translate(bind(t, `{"email":`), LocalePT); ok {
+		t.Error("a malformed body has no field errors")
+	}
+}
+
+func TestLocale(t *testing.T) {
+	tests := map[string]string{
+		"":                        LocalePT,
+		"en-US,en;q=0.9":          LocaleEN,
+		"pt-BR,pt;q=0.9,en;q=0.8": LocalePT,
+		"fr-FR, en;q=0.5":         LocaleEN,
+		"de":                      LocalePT,
+	}
+	for header, want := range tests {
+		if got := Locale(header); got != want {
+			t.Errorf("Locale(%q) = %q, want %q", header, got, want)
+		}
+	}
+}